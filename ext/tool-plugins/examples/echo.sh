@@ -0,0 +1,15 @@
+#!/bin/sh
+# Minimal tool plugin: copy to ext/tool-plugins/ and make it executable.
+read -r request
+case "$request" in
+  *'"method":"describe"'*)
+    echo '{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"echo","description":"Echo the request back","parameters_schema":{"type":"object","properties":{"text":{"type":"string"}}},"requires_approval":false}]}}'
+    ;;
+  *'"method":"execute"'*)
+    escaped=$(printf '%s' "$request" | sed 's/\\/\\\\/g; s/"/\\"/g')
+    printf '{"jsonrpc":"2.0","id":1,"result":{"output":"%s"}}\n' "$escaped"
+    ;;
+  *)
+    echo '{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}'
+    ;;
+esac
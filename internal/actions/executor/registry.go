@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/dwizi/agent-runtime/internal/store"
@@ -24,6 +25,9 @@ type Plugin interface {
 
 type Registry struct {
 	plugins map[string]Plugin
+	// dryRunLogger is set in dry-run mode: actions are resolved to their
+	// plugin as usual, then logged instead of executed.
+	dryRunLogger *slog.Logger
 }
 
 func NewRegistry(plugins ...Plugin) *Registry {
@@ -45,6 +49,19 @@ func NewRegistry(plugins ...Plugin) *Registry {
 	}
 }
 
+// SetDryRun makes Execute simulate every action: unknown action types still
+// fail, known ones are logged to logger and report success without running.
+func (r *Registry) SetDryRun(enabled bool, logger *slog.Logger) {
+	if !enabled {
+		r.dryRunLogger = nil
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	r.dryRunLogger = logger
+}
+
 func (r *Registry) Execute(ctx context.Context, approval store.ActionApproval) (Result, error) {
 	if r == nil {
 		return Result{}, fmt.Errorf("%w: no registry configured", ErrPluginNotFound)
@@ -57,6 +74,19 @@ func (r *Registry) Execute(ctx context.Context, approval store.ActionApproval) (
 	if !ok {
 		return Result{}, fmt.Errorf("%w: %s", ErrPluginNotFound, actionType)
 	}
+	if r.dryRunLogger != nil {
+		r.dryRunLogger.Info("dry run: action not executed",
+			"action_id", approval.ID,
+			"action_type", actionType,
+			"target", approval.ActionTarget,
+			"plugin", plugin.PluginKey(),
+			"workspace_id", approval.WorkspaceID,
+		)
+		return Result{
+			Plugin:  plugin.PluginKey(),
+			Message: fmt.Sprintf("dry run: %s to %s was simulated, not executed", actionType, firstNonEmpty(approval.ActionTarget, "(no target)")),
+		}, nil
+	}
 	result, err := plugin.Execute(ctx, approval)
 	if err != nil {
 		return Result{}, err
@@ -70,3 +100,12 @@ func (r *Registry) Execute(ctx context.Context, approval store.ActionApproval) (
 func normalizeActionType(value string) string {
 	return strings.ToLower(strings.TrimSpace(value))
 }
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
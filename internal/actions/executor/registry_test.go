@@ -58,3 +58,26 @@ func TestRegistryReturnsNotFound(t *testing.T) {
 		t.Fatalf("expected not found error, got %v", err)
 	}
 }
+
+func TestRegistryDryRunSimulatesActions(t *testing.T) {
+	registry := NewRegistry(&fakePlugin{
+		key:   "webhook",
+		types: []string{"webhook"},
+		err:   errors.New("must not run in dry-run mode"),
+	})
+	registry.SetDryRun(true, nil)
+	result, err := registry.Execute(context.Background(), store.ActionApproval{
+		ID:           "act-1",
+		ActionType:   "webhook",
+		ActionTarget: "https://hooks.example.com/deploy",
+	})
+	if err != nil {
+		t.Fatalf("expected simulated success, got %v", err)
+	}
+	if result.Plugin != "webhook" || result.Message != "dry run: webhook to https://hooks.example.com/deploy was simulated, not executed" {
+		t.Fatalf("unexpected dry-run result %+v", result)
+	}
+	if _, err := registry.Execute(context.Background(), store.ActionApproval{ActionType: "unknown"}); !errors.Is(err, ErrPluginNotFound) {
+		t.Fatalf("expected unknown actions to fail in dry-run mode, got %v", err)
+	}
+}
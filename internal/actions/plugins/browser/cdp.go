@@ -0,0 +1,146 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type cdpTarget struct {
+	ID                   string `json:"id"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+type cdpMessage struct {
+	ID     int             `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params map[string]any  `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// cdpSession drives a single page target over the DevTools websocket.
+// Commands are issued sequentially; events seen while waiting for a reply
+// are remembered so waitEvent can return immediately.
+type cdpSession struct {
+	conn   *websocket.Conn
+	nextID int
+	seen   map[string]bool
+}
+
+func (p *Plugin) openTarget(ctx context.Context) (cdpTarget, error) {
+	endpoint := p.endpoint("/json/new") + "?" + url.QueryEscape("about:blank")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, nil)
+	if err != nil {
+		return cdpTarget{}, err
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return cdpTarget{}, fmt.Errorf("open browser tab: %w", err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 64*1024))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return cdpTarget{}, fmt.Errorf("open browser tab: status %d", res.StatusCode)
+	}
+	var target cdpTarget
+	if err := json.Unmarshal(body, &target); err != nil {
+		return cdpTarget{}, fmt.Errorf("open browser tab: %w", err)
+	}
+	if strings.TrimSpace(target.WebSocketDebuggerURL) == "" {
+		return cdpTarget{}, fmt.Errorf("open browser tab: no debugger url")
+	}
+	return target, nil
+}
+
+func (p *Plugin) closeTarget(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint("/json/close/"+url.PathEscape(id)), nil)
+	if err != nil {
+		return
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return
+	}
+	_ = res.Body.Close()
+}
+
+func (p *Plugin) endpoint(path string) string {
+	return strings.TrimRight(p.cdpURL, "/") + path
+}
+
+func dialSession(ctx context.Context, wsURL string) (*cdpSession, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connect to browser tab: %w", err)
+	}
+	return &cdpSession{conn: conn, seen: map[string]bool{}}, nil
+}
+
+func (s *cdpSession) Close() error {
+	return s.conn.Close()
+}
+
+func (s *cdpSession) call(ctx context.Context, method string, params map[string]any, out any) error {
+	s.nextID++
+	id := s.nextID
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+	if err := s.conn.WriteJSON(cdpMessage{ID: id, Method: method, Params: params}); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	for {
+		message, err := s.read(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+		if message.ID != id {
+			continue
+		}
+		if message.Error != nil {
+			return fmt.Errorf("%s: %s", method, message.Error.Message)
+		}
+		if out == nil || len(message.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(message.Result, out)
+	}
+}
+
+func (s *cdpSession) waitEvent(ctx context.Context, method string) error {
+	for !s.seen[method] {
+		if _, err := s.read(ctx); err != nil {
+			return fmt.Errorf("wait for %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+func (s *cdpSession) read(ctx context.Context) (cdpMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return cdpMessage{}, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetReadDeadline(deadline)
+	}
+	var message cdpMessage
+	if err := s.conn.ReadJSON(&message); err != nil {
+		return cdpMessage{}, err
+	}
+	if message.Method != "" {
+		s.seen[message.Method] = true
+	}
+	return message, nil
+}
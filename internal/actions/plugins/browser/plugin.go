@@ -0,0 +1,268 @@
+// Package browser loads pages in a headless Chrome over the DevTools
+// protocol so JS-rendered sites can be read and captured.
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	ActionType      = "browser_page"
+	maxWaitMS       = 10000
+	defaultMaxChars = 20000
+)
+
+type Config struct {
+	CDPURL        string
+	WorkspaceRoot string
+	Timeout       time.Duration
+	MaxTextChars  int
+}
+
+type Plugin struct {
+	cdpURL        string
+	workspaceRoot string
+	timeout       time.Duration
+	maxTextChars  int
+	client        *http.Client
+	now           func() time.Time
+}
+
+func New(cfg Config) *Plugin {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 45 * time.Second
+	}
+	maxChars := cfg.MaxTextChars
+	if maxChars <= 0 {
+		maxChars = defaultMaxChars
+	}
+	return &Plugin{
+		cdpURL:        strings.TrimSpace(cfg.CDPURL),
+		workspaceRoot: strings.TrimSpace(cfg.WorkspaceRoot),
+		timeout:       timeout,
+		maxTextChars:  maxChars,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		now:           time.Now,
+	}
+}
+
+func (p *Plugin) PluginKey() string {
+	return "browser"
+}
+
+func (p *Plugin) ActionTypes() []string {
+	return []string{ActionType}
+}
+
+type pageRequest struct {
+	url        string
+	selector   string
+	text       bool
+	screenshot bool
+	wait       time.Duration
+}
+
+func (p *Plugin) Execute(ctx context.Context, approval store.ActionApproval) (executor.Result, error) {
+	req, err := parseRequest(approval)
+	if err != nil {
+		return executor.Result{}, err
+	}
+	if p.cdpURL == "" {
+		return executor.Result{}, fmt.Errorf("browser plugin is not configured")
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	target, err := p.openTarget(ctx)
+	if err != nil {
+		return executor.Result{}, err
+	}
+	defer p.closeTarget(target.ID)
+	session, err := dialSession(ctx, target.WebSocketDebuggerURL)
+	if err != nil {
+		return executor.Result{}, err
+	}
+	defer session.Close()
+
+	if err := session.call(ctx, "Page.enable", nil, nil); err != nil {
+		return executor.Result{}, err
+	}
+	if err := session.call(ctx, "Emulation.setDeviceMetricsOverride", map[string]any{
+		"width": 1280, "height": 800, "deviceScaleFactor": 1, "mobile": false,
+	}, nil); err != nil {
+		return executor.Result{}, err
+	}
+	var navigation struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := session.call(ctx, "Page.navigate", map[string]any{"url": req.url}, &navigation); err != nil {
+		return executor.Result{}, err
+	}
+	if navigation.ErrorText != "" {
+		return executor.Result{}, fmt.Errorf("load %s: %s", req.url, navigation.ErrorText)
+	}
+	if err := session.waitEvent(ctx, "Page.loadEventFired"); err != nil {
+		return executor.Result{}, err
+	}
+	if req.wait > 0 {
+		select {
+		case <-ctx.Done():
+			return executor.Result{}, ctx.Err()
+		case <-time.After(req.wait):
+		}
+	}
+
+	title, err := evaluateString(ctx, session, "document.title")
+	if err != nil {
+		return executor.Result{}, err
+	}
+	lines := []string{fmt.Sprintf("Loaded %s", req.url)}
+	if title = strings.TrimSpace(title); title != "" {
+		lines = append(lines, "Title: "+title)
+	}
+	if req.screenshot {
+		relPath, err := p.saveScreenshot(ctx, session, approval.WorkspaceID, req.url)
+		if err != nil {
+			return executor.Result{}, err
+		}
+		lines = append(lines, "Screenshot: "+relPath)
+	}
+	if req.text {
+		text, err := evaluateString(ctx, session, textExpression(req.selector))
+		if err != nil {
+			return executor.Result{}, err
+		}
+		text = strings.TrimSpace(text)
+		if runes := []rune(text); len(runes) > p.maxTextChars {
+			text = string(runes[:p.maxTextChars]) + "\n\n[Truncated]"
+		}
+		lines = append(lines, "", text)
+	}
+	return executor.Result{
+		Plugin:  p.PluginKey(),
+		Message: strings.Join(lines, "\n"),
+	}, nil
+}
+
+func parseRequest(approval store.ActionApproval) (pageRequest, error) {
+	rawURL := strings.TrimSpace(approval.ActionTarget)
+	if rawURL == "" {
+		rawURL = getString(approval.Payload, "url")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return pageRequest{}, fmt.Errorf("browser action requires an http or https url")
+	}
+	req := pageRequest{url: parsed.String(), selector: getString(approval.Payload, "selector")}
+	switch mode := strings.ToLower(getString(approval.Payload, "mode")); mode {
+	case "", "text":
+		req.text = true
+	case "screenshot":
+		req.screenshot = true
+	case "both":
+		req.text, req.screenshot = true, true
+	default:
+		return pageRequest{}, fmt.Errorf("unsupported browser mode %q", mode)
+	}
+	if waitMS := getInt(approval.Payload, "wait_ms"); waitMS > 0 {
+		if waitMS > maxWaitMS {
+			waitMS = maxWaitMS
+		}
+		req.wait = time.Duration(waitMS) * time.Millisecond
+	}
+	return req, nil
+}
+
+func (p *Plugin) saveScreenshot(ctx context.Context, session *cdpSession, workspaceID, pageURL string) (string, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if p.workspaceRoot == "" || workspaceID == "" || strings.ContainsAny(workspaceID, `/\`) {
+		return "", fmt.Errorf("screenshot requires a workspace")
+	}
+	var shot struct {
+		Data string `json:"data"`
+	}
+	if err := session.call(ctx, "Page.captureScreenshot", map[string]any{"format": "png"}, &shot); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(shot.Data)
+	if err != nil {
+		return "", fmt.Errorf("decode screenshot: %w", err)
+	}
+	host := "page"
+	if parsed, err := url.Parse(pageURL); err == nil && parsed.Hostname() != "" {
+		host = strings.ReplaceAll(parsed.Hostname(), ".", "-")
+	}
+	relPath := filepath.ToSlash(filepath.Join("browser", fmt.Sprintf("%s-%s.png", p.now().UTC().Format("20060102-150405"), host)))
+	path := filepath.Join(p.workspaceRoot, workspaceID, "scratch", filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	return relPath, nil
+}
+
+func textExpression(selector string) string {
+	if strings.TrimSpace(selector) == "" {
+		return "document.body ? document.body.innerText : ''"
+	}
+	quoted, _ := json.Marshal(strings.TrimSpace(selector))
+	return fmt.Sprintf("Array.from(document.querySelectorAll(%s)).map(function (el) { return el.innerText; }).join('\\n\\n')", quoted)
+}
+
+func evaluateString(ctx context.Context, session *cdpSession, expression string) (string, error) {
+	var evaluated struct {
+		Result struct {
+			Value any `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	if err := session.call(ctx, "Runtime.evaluate", map[string]any{"expression": expression, "returnByValue": true}, &evaluated); err != nil {
+		return "", err
+	}
+	if evaluated.ExceptionDetails != nil {
+		return "", fmt.Errorf("evaluate page script: %s", evaluated.ExceptionDetails.Text)
+	}
+	value, _ := evaluated.Result.Value.(string)
+	return value, nil
+}
+
+func getString(payload map[string]any, key string) string {
+	if payload == nil {
+		return ""
+	}
+	value, _ := payload[key].(string)
+	return strings.TrimSpace(value)
+}
+
+func getInt(payload map[string]any, key string) int {
+	if payload == nil {
+		return 0
+	}
+	switch value := payload[key].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	case json.Number:
+		parsed, _ := value.Int64()
+		return int(parsed)
+	}
+	return 0
+}
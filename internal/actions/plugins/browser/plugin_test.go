@@ -0,0 +1,132 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeDevTools struct {
+	mu      sync.Mutex
+	server  *httptest.Server
+	methods []string
+	closed  bool
+}
+
+func newFakeDevTools(t *testing.T) *fakeDevTools {
+	t.Helper()
+	fake := &fakeDevTools{}
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json/new", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		wsURL := "ws" + strings.TrimPrefix(fake.server.URL, "http") + "/devtools/page/T1"
+		_, _ = w.Write([]byte(`{"id":"T1","webSocketDebuggerUrl":"` + wsURL + `"}`))
+	})
+	mux.HandleFunc("/json/close/T1", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		fake.closed = true
+		fake.mu.Unlock()
+	})
+	mux.HandleFunc("/devtools/page/T1", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg cdpMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			fake.mu.Lock()
+			fake.methods = append(fake.methods, msg.Method)
+			fake.mu.Unlock()
+			result := map[string]any{}
+			switch msg.Method {
+			case "Page.navigate":
+				_ = conn.WriteJSON(map[string]any{"method": "Page.frameStartedLoading"})
+				_ = conn.WriteJSON(map[string]any{"id": msg.ID, "result": map[string]any{"frameId": "F1"}})
+				_ = conn.WriteJSON(map[string]any{"method": "Page.loadEventFired"})
+				continue
+			case "Runtime.evaluate":
+				expression, _ := msg.Params["expression"].(string)
+				value := "Status page"
+				if strings.Contains(expression, "querySelectorAll") {
+					value = "All systems operational"
+				}
+				result["result"] = map[string]any{"type": "string", "value": value}
+			case "Page.captureScreenshot":
+				result["data"] = base64.StdEncoding.EncodeToString([]byte("png-bytes"))
+			}
+			_ = conn.WriteJSON(map[string]any{"id": msg.ID, "result": result})
+		}
+	})
+	fake.server = httptest.NewServer(mux)
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func TestExecuteLoadsPageTextAndScreenshot(t *testing.T) {
+	fake := newFakeDevTools(t)
+	root := t.TempDir()
+	plugin := New(Config{CDPURL: fake.server.URL, WorkspaceRoot: root})
+	plugin.now = func() time.Time { return time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC) }
+
+	result, err := plugin.Execute(context.Background(), store.ActionApproval{
+		WorkspaceID:  "ws-1",
+		ActionType:   ActionType,
+		ActionTarget: "https://status.example.com/",
+		Payload:      map[string]any{"mode": "both", "selector": "#status", "wait_ms": float64(1)},
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	want := "Loaded https://status.example.com/\nTitle: Status page\nScreenshot: browser/20261017-093000-status-example-com.png\n\nAll systems operational"
+	if result.Message != want || result.Plugin != "browser" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "ws-1", "scratch", "browser", "20261017-093000-status-example-com.png"))
+	if err != nil || string(data) != "png-bytes" {
+		t.Fatalf("expected screenshot written, got %q %v", data, err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !fake.closed {
+		t.Fatal("expected browser tab to be closed")
+	}
+	if fake.methods[0] != "Page.enable" || fake.methods[2] != "Page.navigate" {
+		t.Fatalf("unexpected call order %v", fake.methods)
+	}
+}
+
+func TestExecuteRejectsInvalidRequests(t *testing.T) {
+	plugin := New(Config{CDPURL: "http://127.0.0.1:9"})
+	cases := []store.ActionApproval{
+		{ActionTarget: "file:///etc/passwd"},
+		{ActionTarget: "https://example.com", Payload: map[string]any{"mode": "pdf"}},
+	}
+	for _, approval := range cases {
+		if _, err := plugin.Execute(context.Background(), approval); err == nil {
+			t.Fatalf("expected error for %+v", approval)
+		}
+	}
+	unconfigured := New(Config{})
+	if _, err := unconfigured.Execute(context.Background(), store.ActionApproval{ActionTarget: "https://example.com"}); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Fatalf("expected not configured error, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/calendar"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// Plugin creates calendar events once a calendar_create_event action is
+// approved.
+type Plugin struct {
+	client calendar.Client
+}
+
+func New(client calendar.Client) *Plugin {
+	return &Plugin{client: client}
+}
+
+func (p *Plugin) PluginKey() string {
+	return "calendar"
+}
+
+func (p *Plugin) ActionTypes() []string {
+	return []string{"calendar_create_event"}
+}
+
+func (p *Plugin) Execute(ctx context.Context, approval store.ActionApproval) (executor.Result, error) {
+	if p == nil || p.client == nil {
+		return executor.Result{}, calendar.ErrNotConfigured
+	}
+	event, err := EventFromPayload(approval.Payload)
+	if err != nil {
+		return executor.Result{}, err
+	}
+	if event.Title == "" {
+		event.Title = strings.TrimSpace(approval.ActionSummary)
+	}
+	created, err := p.client.CreateEvent(ctx, event)
+	if err != nil {
+		return executor.Result{}, err
+	}
+	message := fmt.Sprintf("Created %s event %q starting %s", p.client.Provider(), created.Title, formatStart(created))
+	if created.URL != "" {
+		message += " (" + created.URL + ")"
+	}
+	return executor.Result{Plugin: p.PluginKey(), Message: message}, nil
+}
+
+// EventFromPayload reads the calendar_create_event payload: title, start,
+// end, description, location, and attendees.
+func EventFromPayload(payload map[string]any) (calendar.Event, error) {
+	event := calendar.Event{
+		Title:       getString(payload, "title"),
+		Description: getString(payload, "description"),
+		Location:    getString(payload, "location"),
+		Attendees:   getStringList(payload, "attendees"),
+	}
+	start := getString(payload, "start")
+	if start == "" {
+		return calendar.Event{}, fmt.Errorf("%w: start is required", calendar.ErrInvalidEvent)
+	}
+	parsed, allDay, err := calendar.ParseTime(start)
+	if err != nil {
+		return calendar.Event{}, err
+	}
+	event.Start, event.AllDay = parsed, allDay
+	if end := getString(payload, "end"); end != "" {
+		parsedEnd, _, err := calendar.ParseTime(end)
+		if err != nil {
+			return calendar.Event{}, err
+		}
+		event.End = parsedEnd
+	}
+	return event, nil
+}
+
+func formatStart(event calendar.Event) string {
+	if event.AllDay {
+		return event.Start.Format(time.DateOnly)
+	}
+	return event.Start.UTC().Format(time.RFC3339)
+}
+
+func getString(payload map[string]any, key string) string {
+	value, ok := payload[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
+
+func getStringList(payload map[string]any, key string) []string {
+	values := []string{}
+	switch typed := payload[key].(type) {
+	case []any:
+		for _, item := range typed {
+			if text := strings.TrimSpace(fmt.Sprint(item)); text != "" {
+				values = append(values, text)
+			}
+		}
+	case []string:
+		for _, item := range typed {
+			if text := strings.TrimSpace(item); text != "" {
+				values = append(values, text)
+			}
+		}
+	case string:
+		for _, item := range strings.Split(typed, ",") {
+			if text := strings.TrimSpace(item); text != "" {
+				values = append(values, text)
+			}
+		}
+	}
+	return values
+}
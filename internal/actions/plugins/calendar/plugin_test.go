@@ -0,0 +1,63 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/calendar"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeClient struct {
+	created []calendar.Event
+}
+
+func (f *fakeClient) Provider() string { return "caldav" }
+
+func (f *fakeClient) ListEvents(ctx context.Context, from, to time.Time, limit int) ([]calendar.Event, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) CreateEvent(ctx context.Context, event calendar.Event) (calendar.Event, error) {
+	f.created = append(f.created, event)
+	event.URL = "https://cal/e1"
+	return event, nil
+}
+
+func TestPluginCreatesEventFromPayload(t *testing.T) {
+	client := &fakeClient{}
+	result, err := New(client).Execute(context.Background(), store.ActionApproval{
+		ActionType:    "calendar_create_event",
+		ActionSummary: "Release reminder",
+		Payload: map[string]any{
+			"start":     "2026-10-22T09:00:00+02:00",
+			"end":       "2026-10-22T09:30:00+02:00",
+			"attendees": []any{"dev@example.com", "ops@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(client.created) != 1 {
+		t.Fatalf("expected one event, got %d", len(client.created))
+	}
+	event := client.created[0]
+	if event.Title != "Release reminder" || !event.Start.Equal(time.Date(2026, 10, 22, 7, 0, 0, 0, time.UTC)) || len(event.Attendees) != 2 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if result.Plugin != "calendar" || !strings.Contains(result.Message, "2026-10-22T07:00:00Z (https://cal/e1)") {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestPluginRejectsBadPayloadAndMissingClient(t *testing.T) {
+	if _, err := New(&fakeClient{}).Execute(context.Background(), store.ActionApproval{Payload: map[string]any{"title": "x", "start": "next week"}}); !errors.Is(err, calendar.ErrInvalidEvent) {
+		t.Fatalf("expected invalid event, got %v", err)
+	}
+	if _, err := New(nil).Execute(context.Background(), store.ActionApproval{}); !errors.Is(err, calendar.ErrNotConfigured) {
+		t.Fatalf("expected not configured, got %v", err)
+	}
+}
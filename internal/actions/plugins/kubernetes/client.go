@@ -0,0 +1,116 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var errConflict = errors.New("kubernetes object already exists")
+
+// apiClient is a minimal Kubernetes REST client covering the namespace,
+// quota, job, pod and log endpoints the plugin needs.
+type apiClient struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+func newAPIClient(server, token, caCertFile string, httpClient *http.Client) (*apiClient, error) {
+	if httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if strings.TrimSpace(caCertFile) != "" {
+			pem, err := os.ReadFile(caCertFile)
+			if err != nil {
+				return nil, fmt.Errorf("read kubernetes ca: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("kubernetes ca file %s has no certificates", caCertFile)
+			}
+			transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		}
+		httpClient = &http.Client{Transport: transport}
+	}
+	return &apiClient{
+		server: strings.TrimRight(strings.TrimSpace(server), "/"),
+		token:  strings.TrimSpace(token),
+		http:   httpClient,
+	}, nil
+}
+
+func (c *apiClient) request(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes %s %s: %w", method, path, err)
+	}
+	if res.StatusCode == http.StatusConflict {
+		res.Body.Close()
+		return nil, errConflict
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		var status struct {
+			Message string `json:"message"`
+		}
+		message := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &status) == nil && strings.TrimSpace(status.Message) != "" {
+			message = status.Message
+		}
+		return nil, fmt.Errorf("kubernetes %s %s failed: status=%d message=%s", method, path, res.StatusCode, message)
+	}
+	return res, nil
+}
+
+func (c *apiClient) do(ctx context.Context, method, path string, body, out any) error {
+	res, err := c.request(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if out == nil {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode kubernetes %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// createIfMissing treats an existing object as success.
+func (c *apiClient) createIfMissing(ctx context.Context, path string, body any) error {
+	err := c.do(ctx, http.MethodPost, path, body, nil)
+	if errors.Is(err, errConflict) {
+		return nil
+	}
+	return err
+}
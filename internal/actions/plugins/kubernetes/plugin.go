@@ -0,0 +1,433 @@
+// Package kubernetes runs approved command actions as Kubernetes Jobs, one
+// namespace per workspace with a resource quota, and returns the job logs.
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/actions/plugins/sandbox"
+	"github.com/dwizi/agent-runtime/internal/agenterr"
+	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	ActionType    = "kubernetes_job"
+	containerName = "run"
+	quotaName     = "agent-runtime"
+	managedByKey  = "app.kubernetes.io/managed-by"
+	managedBy     = "agent-runtime"
+	workspaceKey  = "agent-runtime/workspace"
+)
+
+var ErrNotConfigured = errors.New("kubernetes job executor is not configured")
+
+type Config struct {
+	APIServer       string
+	Token           string
+	CACertFile      string
+	Image           string
+	AllowedCommands []string
+	NamespacePrefix string
+	CPULimit        string
+	MemoryLimit     string
+	QuotaCPU        string
+	QuotaMemory     string
+	QuotaPods       int
+	Timeout         time.Duration
+	MaxLogBytes     int
+	HTTPClient      *http.Client
+}
+
+type Plugin struct {
+	client          *apiClient
+	image           string
+	allowed         map[string]struct{}
+	namespacePrefix string
+	cpuLimit        string
+	memoryLimit     string
+	quotaCPU        string
+	quotaMemory     string
+	quotaPods       int
+	timeout         time.Duration
+	maxLogBytes     int
+	pollInterval    time.Duration
+	readyNamespaces sync.Map
+	egress          sandbox.CurlGuard
+}
+
+// SetEgressGuard pins curl commands to addresses the egress policy allows,
+// as the sandbox plugin does.
+func (p *Plugin) SetEgressGuard(guard sandbox.CurlGuard) {
+	p.egress = guard
+}
+
+func New(cfg Config) (*Plugin, error) {
+	if strings.TrimSpace(cfg.APIServer) == "" || strings.TrimSpace(cfg.Image) == "" {
+		return nil, fmt.Errorf("%w: api server and image are required", ErrNotConfigured)
+	}
+	client, err := newAPIClient(cfg.APIServer, cfg.Token, cfg.CACertFile, cfg.HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+	allowed := map[string]struct{}{}
+	for _, item := range cfg.AllowedCommands {
+		key := strings.ToLower(strings.TrimSpace(item))
+		if key == "" {
+			continue
+		}
+		allowed[key] = struct{}{}
+	}
+	prefix := strings.TrimSpace(cfg.NamespacePrefix)
+	if prefix == "" {
+		prefix = "agent-ws-"
+	}
+	timeout := cfg.Timeout
+	if timeout < time.Second {
+		timeout = 5 * time.Minute
+	}
+	maxLogBytes := cfg.MaxLogBytes
+	if maxLogBytes < 256 {
+		maxLogBytes = 64 * 1024
+	}
+	return &Plugin{
+		client:          client,
+		image:           strings.TrimSpace(cfg.Image),
+		allowed:         allowed,
+		namespacePrefix: prefix,
+		cpuLimit:        strings.TrimSpace(cfg.CPULimit),
+		memoryLimit:     strings.TrimSpace(cfg.MemoryLimit),
+		quotaCPU:        strings.TrimSpace(cfg.QuotaCPU),
+		quotaMemory:     strings.TrimSpace(cfg.QuotaMemory),
+		quotaPods:       cfg.QuotaPods,
+		timeout:         timeout,
+		maxLogBytes:     maxLogBytes,
+		pollInterval:    time.Second,
+	}, nil
+}
+
+func (p *Plugin) PluginKey() string {
+	return "kubernetes_job"
+}
+
+// ActionTypes takes over the command action types, so registering this
+// plugin after the sandbox plugin moves command execution into the cluster.
+func (p *Plugin) ActionTypes() []string {
+	return []string{"run_command", "shell_command", "cli_command", ActionType}
+}
+
+func (p *Plugin) Execute(ctx context.Context, approval store.ActionApproval) (executor.Result, error) {
+	command, args, err := sandbox.ParseCommand(approval)
+	if err != nil {
+		return executor.Result{}, fmt.Errorf("%w: %v", agenterr.ErrToolInvalidArgs, err)
+	}
+	if _, ok := p.allowed[strings.ToLower(command)]; !ok {
+		return executor.Result{}, fmt.Errorf("%w: command %q", agenterr.ErrToolNotAllowed, command)
+	}
+	workspaceID := strings.TrimSpace(approval.WorkspaceID)
+	if workspaceID == "" {
+		return executor.Result{}, fmt.Errorf("%w: workspace id is required for kubernetes job", agenterr.ErrToolInvalidArgs)
+	}
+	if p.egress != nil && strings.EqualFold(command, "curl") {
+		if args, err = p.egress.PinCurl(ctx, workspaceID, args); err != nil {
+			return executor.Result{}, fmt.Errorf("%w: %v", agenterr.ErrToolNotAllowed, err)
+		}
+	}
+
+	// Leave room beyond the job deadline for scheduling and log collection.
+	runCtx, cancel := context.WithTimeout(ctx, p.timeout+30*time.Second)
+	defer cancel()
+	namespace := p.namespaceFor(workspaceID)
+	if err := p.ensureNamespace(runCtx, namespace, workspaceID); err != nil {
+		return executor.Result{}, fmt.Errorf("%w: %v", agenterr.ErrToolPreflight, err)
+	}
+	jobName := "agent-run-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:10]
+	if err := p.client.do(runCtx, http.MethodPost, "/apis/batch/v1/namespaces/"+namespace+"/jobs", p.jobManifest(jobName, workspaceID, command, args), nil); err != nil {
+		return executor.Result{}, fmt.Errorf("create kubernetes job: %w", err)
+	}
+
+	logs, truncated, err := p.collectLogs(runCtx, namespace, jobName)
+	if err != nil {
+		p.deleteJob(namespace, jobName)
+		return executor.Result{}, fmt.Errorf("kubernetes job %s/%s: %w", namespace, jobName, err)
+	}
+	succeeded, err := p.waitForCompletion(runCtx, namespace, jobName)
+	if err != nil {
+		p.deleteJob(namespace, jobName)
+		return executor.Result{}, fmt.Errorf("kubernetes job %s/%s: %w", namespace, jobName, err)
+	}
+	output := compactLogs(logs, truncated)
+	if !succeeded {
+		return executor.Result{}, fmt.Errorf("kubernetes job %s/%s failed; output=%s", namespace, jobName, output)
+	}
+	return executor.Result{
+		Plugin:  p.PluginKey(),
+		Message: fmt.Sprintf("Kubernetes job %s/%s succeeded. Output: %s", namespace, jobName, output),
+	}, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// namespaceFor maps a workspace id onto a DNS-1123 label, falling back to a
+// hash suffix when the id is too long or has no usable characters.
+func (p *Plugin) namespaceFor(workspaceID string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(workspaceID), "-"), "-")
+	if name == "" || len(p.namespacePrefix)+len(name) > 63 {
+		sum := sha1.Sum([]byte(workspaceID))
+		suffix := hex.EncodeToString(sum[:])[:10]
+		room := 63 - len(p.namespacePrefix) - len(suffix) - 1
+		if room > 0 && len(name) > room {
+			name = strings.Trim(name[:room], "-")
+		}
+		if name == "" || room <= 0 {
+			name = suffix
+		} else {
+			name = name + "-" + suffix
+		}
+	}
+	return strings.Trim(p.namespacePrefix+name, "-")
+}
+
+func (p *Plugin) ensureNamespace(ctx context.Context, namespace, workspaceID string) error {
+	if _, ok := p.readyNamespaces.Load(namespace); ok {
+		return nil
+	}
+	labels := map[string]string{
+		managedByKey: managedBy,
+		workspaceKey: labelValue(workspaceID),
+	}
+	if err := p.client.createIfMissing(ctx, "/api/v1/namespaces", map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]any{"name": namespace, "labels": labels},
+	}); err != nil {
+		return fmt.Errorf("create namespace: %w", err)
+	}
+	hard := map[string]string{}
+	if p.quotaCPU != "" {
+		hard["limits.cpu"] = p.quotaCPU
+	}
+	if p.quotaMemory != "" {
+		hard["limits.memory"] = p.quotaMemory
+	}
+	if p.quotaPods > 0 {
+		hard["pods"] = strconv.Itoa(p.quotaPods)
+	}
+	if len(hard) > 0 {
+		if err := p.client.createIfMissing(ctx, "/api/v1/namespaces/"+namespace+"/resourcequotas", map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ResourceQuota",
+			"metadata":   map[string]any{"name": quotaName, "labels": labels},
+			"spec":       map[string]any{"hard": hard},
+		}); err != nil {
+			return fmt.Errorf("create resource quota: %w", err)
+		}
+	}
+	p.readyNamespaces.Store(namespace, struct{}{})
+	return nil
+}
+
+func (p *Plugin) jobManifest(name, workspaceID, command string, args []string) map[string]any {
+	labels := map[string]string{
+		managedByKey: managedBy,
+		workspaceKey: labelValue(workspaceID),
+	}
+	limits := map[string]string{}
+	if p.cpuLimit != "" {
+		limits["cpu"] = p.cpuLimit
+	}
+	if p.memoryLimit != "" {
+		limits["memory"] = p.memoryLimit
+	}
+	container := map[string]any{
+		"name":    containerName,
+		"image":   p.image,
+		"command": []string{command},
+		"args":    append([]string{}, args...),
+		"env":     []map[string]string{{"name": "HOME", "value": "/tmp"}},
+		"securityContext": map[string]any{
+			"allowPrivilegeEscalation": false,
+			"readOnlyRootFilesystem":   true,
+			"capabilities":             map[string]any{"drop": []string{"ALL"}},
+		},
+		"volumeMounts": []map[string]string{{"name": "tmp", "mountPath": "/tmp"}},
+	}
+	if len(limits) > 0 {
+		// A namespace quota on limits.* rejects pods that declare none.
+		container["resources"] = map[string]any{"limits": limits, "requests": limits}
+	}
+	return map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   map[string]any{"name": name, "labels": labels},
+		"spec": map[string]any{
+			"backoffLimit":            0,
+			"activeDeadlineSeconds":   int(p.timeout / time.Second),
+			"ttlSecondsAfterFinished": 600,
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": labels},
+				"spec": map[string]any{
+					"restartPolicy":                "Never",
+					"automountServiceAccountToken": false,
+					"containers":                   []any{container},
+					"volumes":                      []any{map[string]any{"name": "tmp", "emptyDir": map[string]any{}}},
+				},
+			},
+		},
+	}
+}
+
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Status struct {
+			Phase             string `json:"phase"`
+			ContainerStatuses []struct {
+				State struct {
+					Waiting *struct {
+						Reason  string `json:"reason"`
+						Message string `json:"message"`
+					} `json:"waiting"`
+				} `json:"state"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// collectLogs waits for the job's pod to start, then follows its log stream
+// until the container exits.
+func (p *Plugin) collectLogs(ctx context.Context, namespace, jobName string) (string, bool, error) {
+	selector := url.QueryEscape("job-name=" + jobName)
+	var podName string
+	for podName == "" {
+		var pods podList
+		if err := p.client.do(ctx, http.MethodGet, "/api/v1/namespaces/"+namespace+"/pods?labelSelector="+selector, nil, &pods); err != nil {
+			return "", false, err
+		}
+		for _, pod := range pods.Items {
+			for _, status := range pod.Status.ContainerStatuses {
+				if waiting := status.State.Waiting; waiting != nil && isFatalWaitReason(waiting.Reason) {
+					return "", false, fmt.Errorf("pod cannot start: %s %s", waiting.Reason, strings.TrimSpace(waiting.Message))
+				}
+			}
+			if pod.Status.Phase != "" && pod.Status.Phase != "Pending" {
+				podName = pod.Metadata.Name
+				break
+			}
+		}
+		if podName != "" {
+			break
+		}
+		if err := p.sleep(ctx); err != nil {
+			return "", false, fmt.Errorf("timed out waiting for pod to start")
+		}
+	}
+
+	query := url.Values{}
+	query.Set("container", containerName)
+	query.Set("follow", "true")
+	res, err := p.client.request(ctx, http.MethodGet, "/api/v1/namespaces/"+namespace+"/pods/"+podName+"/log?"+query.Encode(), nil)
+	if err != nil {
+		return "", false, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(res.Body, int64(p.maxLogBytes)+1))
+	if err != nil && ctx.Err() != nil {
+		return "", false, fmt.Errorf("timed out streaming logs")
+	}
+	truncated := len(data) > p.maxLogBytes
+	if truncated {
+		data = data[:p.maxLogBytes]
+	}
+	return string(data), truncated, nil
+}
+
+func isFatalWaitReason(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError":
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Plugin) waitForCompletion(ctx context.Context, namespace, jobName string) (bool, error) {
+	for {
+		var job struct {
+			Status struct {
+				Succeeded int `json:"succeeded"`
+				Failed    int `json:"failed"`
+			} `json:"status"`
+		}
+		if err := p.client.do(ctx, http.MethodGet, "/apis/batch/v1/namespaces/"+namespace+"/jobs/"+jobName, nil, &job); err != nil {
+			return false, err
+		}
+		if job.Status.Succeeded > 0 {
+			return true, nil
+		}
+		if job.Status.Failed > 0 {
+			return false, nil
+		}
+		if err := p.sleep(ctx); err != nil {
+			return false, fmt.Errorf("timed out waiting for job to finish")
+		}
+	}
+}
+
+func (p *Plugin) sleep(ctx context.Context) error {
+	timer := time.NewTimer(p.pollInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// deleteJob removes an abandoned job and its pods; it runs on a fresh
+// context because the execution context has usually expired by then.
+func (p *Plugin) deleteJob(namespace, jobName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_ = p.client.do(ctx, http.MethodDelete, "/apis/batch/v1/namespaces/"+namespace+"/jobs/"+jobName+"?propagationPolicy=Background", nil, nil)
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func labelValue(value string) string {
+	value = invalidLabelChars.ReplaceAllString(value, "-")
+	if len(value) > 63 {
+		value = value[:63]
+	}
+	return strings.Trim(value, "-._")
+}
+
+func compactLogs(logs string, truncated bool) string {
+	trimmed := strings.TrimSpace(logs)
+	if trimmed == "" {
+		if truncated {
+			return "(output truncated)"
+		}
+		return "(no output)"
+	}
+	if truncated {
+		return trimmed + " ... [truncated]"
+	}
+	return trimmed
+}
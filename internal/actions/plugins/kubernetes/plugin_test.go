@@ -0,0 +1,193 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/agenterr"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeCluster struct {
+	mu         sync.Mutex
+	jobFailed  bool
+	namespaces []string
+	quota      map[string]any
+	job        map[string]any
+	jobName    string
+	podPolls   int
+	deleted    bool
+}
+
+func (f *fakeCluster) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing bearer token on %s", r.URL.Path)
+		}
+		path := r.URL.Path
+		switch {
+		case r.Method == http.MethodPost && path == "/api/v1/namespaces":
+			var body struct {
+				Metadata struct {
+					Name string `json:"name"`
+				} `json:"metadata"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			for _, existing := range f.namespaces {
+				if existing == body.Metadata.Name {
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+			}
+			f.namespaces = append(f.namespaces, body.Metadata.Name)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/resourcequotas"):
+			_ = json.NewDecoder(r.Body).Decode(&f.quota)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/jobs"):
+			_ = json.NewDecoder(r.Body).Decode(&f.job)
+			f.jobName = f.job["metadata"].(map[string]any)["name"].(string)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/pods"):
+			if r.URL.Query().Get("labelSelector") != "job-name="+f.jobName {
+				t.Errorf("unexpected selector %q", r.URL.Query().Get("labelSelector"))
+			}
+			f.podPolls++
+			phase := "Pending"
+			if f.podPolls > 1 {
+				phase = "Running"
+			}
+			_, _ = w.Write([]byte(`{"items":[{"metadata":{"name":"` + f.jobName + `-abc"},"status":{"phase":"` + phase + `"}}]}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/log"):
+			if r.URL.Query().Get("follow") != "true" {
+				t.Errorf("expected follow=true log stream")
+			}
+			_, _ = w.Write([]byte("line one\nline two\n"))
+		case r.Method == http.MethodGet && strings.Contains(path, "/jobs/"):
+			if f.jobFailed {
+				_, _ = w.Write([]byte(`{"status":{"failed":1}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":{"succeeded":1}}`))
+		case r.Method == http.MethodDelete:
+			f.deleted = true
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func newTestPlugin(t *testing.T, cluster *fakeCluster) *Plugin {
+	t.Helper()
+	server := httptest.NewServer(cluster.handler(t))
+	t.Cleanup(server.Close)
+	plugin, err := New(Config{
+		APIServer:       server.URL,
+		Token:           "token",
+		Image:           "alpine:3.20",
+		AllowedCommands: []string{"echo"},
+		CPULimit:        "500m",
+		MemoryLimit:     "256Mi",
+		QuotaCPU:        "2",
+		QuotaMemory:     "2Gi",
+		QuotaPods:       5,
+		Timeout:         time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("new plugin: %v", err)
+	}
+	plugin.pollInterval = time.Millisecond
+	return plugin
+}
+
+func TestExecuteRunsJobInWorkspaceNamespace(t *testing.T) {
+	cluster := &fakeCluster{}
+	plugin := newTestPlugin(t, cluster)
+	approval := store.ActionApproval{
+		WorkspaceID:  "WS_1",
+		ActionType:   "run_command",
+		ActionTarget: "echo",
+		Payload:      map[string]any{"args": []any{"hello"}},
+	}
+	result, err := plugin.Execute(context.Background(), approval)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if result.Plugin != "kubernetes_job" || !strings.Contains(result.Message, "agent-ws-ws-1/agent-run-") || !strings.Contains(result.Message, "line one\nline two") {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(cluster.namespaces) != 1 || cluster.namespaces[0] != "agent-ws-ws-1" {
+		t.Fatalf("unexpected namespaces %v", cluster.namespaces)
+	}
+	hard := cluster.quota["spec"].(map[string]any)["hard"].(map[string]any)
+	if hard["limits.cpu"] != "2" || hard["limits.memory"] != "2Gi" || hard["pods"] != "5" {
+		t.Fatalf("unexpected quota %v", hard)
+	}
+	spec := cluster.job["spec"].(map[string]any)
+	container := spec["template"].(map[string]any)["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)
+	if container["image"] != "alpine:3.20" || container["command"].([]any)[0] != "echo" || container["args"].([]any)[0] != "hello" {
+		t.Fatalf("unexpected container %v", container)
+	}
+	limits := container["resources"].(map[string]any)["limits"].(map[string]any)
+	if limits["cpu"] != "500m" || limits["memory"] != "256Mi" || spec["activeDeadlineSeconds"].(float64) != 60 {
+		t.Fatalf("unexpected limits %v / spec %v", limits, spec)
+	}
+
+	// The namespace is set up once per workspace.
+	if _, err := plugin.Execute(context.Background(), approval); err != nil {
+		t.Fatalf("second execute: %v", err)
+	}
+	if len(cluster.namespaces) != 1 {
+		t.Fatalf("expected namespace reuse, got %v", cluster.namespaces)
+	}
+}
+
+func TestExecuteReportsFailedJob(t *testing.T) {
+	cluster := &fakeCluster{jobFailed: true}
+	plugin := newTestPlugin(t, cluster)
+	_, err := plugin.Execute(context.Background(), store.ActionApproval{
+		WorkspaceID:  "ws-1",
+		ActionType:   "run_command",
+		ActionTarget: "echo",
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed; output=line one") {
+		t.Fatalf("expected job failure with logs, got %v", err)
+	}
+}
+
+func TestExecuteRejectsDisallowedCommand(t *testing.T) {
+	plugin := newTestPlugin(t, &fakeCluster{})
+	_, err := plugin.Execute(context.Background(), store.ActionApproval{
+		WorkspaceID:  "ws-1",
+		ActionType:   "run_command",
+		ActionTarget: "rm",
+	})
+	if !errors.Is(err, agenterr.ErrToolNotAllowed) {
+		t.Fatalf("expected not allowed, got %v", err)
+	}
+}
+
+func TestNamespaceForLongWorkspaceID(t *testing.T) {
+	plugin := &Plugin{namespacePrefix: "agent-ws-"}
+	name := plugin.namespaceFor(strings.Repeat("team-", 20))
+	if len(name) > 63 || !strings.HasPrefix(name, "agent-ws-team-") {
+		t.Fatalf("unexpected namespace %q (%d)", name, len(name))
+	}
+	if got := plugin.namespaceFor("???"); !strings.HasPrefix(got, "agent-ws-") || len(got) != len("agent-ws-")+10 {
+		t.Fatalf("expected hashed namespace, got %q", got)
+	}
+}
@@ -0,0 +1,107 @@
+package sandbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dockerWorkspaceMount = "/workspace"
+
+// DockerConfig runs every command in a fresh, disposable container instead
+// of on the host. Setting Runtime to "runsc" adds gVisor isolation.
+type DockerConfig struct {
+	Image     string
+	Binary    string
+	Runtime   string
+	Network   string
+	CPUs      string
+	Memory    string
+	PidsLimit int
+}
+
+func normalizeDockerConfig(cfg DockerConfig) DockerConfig {
+	cfg.Image = strings.TrimSpace(cfg.Image)
+	cfg.Binary = strings.TrimSpace(cfg.Binary)
+	if cfg.Binary == "" {
+		cfg.Binary = "docker"
+	}
+	cfg.Runtime = strings.TrimSpace(cfg.Runtime)
+	cfg.Network = strings.TrimSpace(cfg.Network)
+	if cfg.Network == "" {
+		cfg.Network = "none"
+	}
+	cfg.CPUs = strings.TrimSpace(cfg.CPUs)
+	cfg.Memory = strings.TrimSpace(cfg.Memory)
+	if cfg.PidsLimit < 1 {
+		cfg.PidsLimit = 128
+	}
+	return cfg
+}
+
+func (d DockerConfig) enabled() bool {
+	return d.Image != ""
+}
+
+// dockerRunArgs mounts only the approval's workspace, read-only unless the
+// approval granted write, and maps the resolved cwd into the container.
+func (p *Plugin) dockerRunArgs(container, command string, args []string, workdir string, writable bool) []string {
+	workspaceDir := workdir
+	containerDir := dockerWorkspaceMount
+	if rel, err := filepath.Rel(p.workspaceRoot, workdir); err == nil {
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		workspaceDir = filepath.Join(p.workspaceRoot, parts[0])
+		containerDir = path.Join(dockerWorkspaceMount, path.Join(parts[1:]...))
+	}
+	mode := "ro"
+	if writable {
+		mode = "rw"
+	}
+
+	runArgs := []string{
+		"run", "--rm",
+		"--name", container,
+		"--network", p.docker.Network,
+		"--pids-limit", strconv.Itoa(p.docker.PidsLimit),
+		"--read-only",
+		"--tmpfs", "/tmp",
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges",
+		"--env", "HOME=/tmp",
+	}
+	if p.docker.Runtime != "" {
+		runArgs = append(runArgs, "--runtime", p.docker.Runtime)
+	}
+	if p.docker.CPUs != "" {
+		runArgs = append(runArgs, "--cpus", p.docker.CPUs)
+	}
+	if p.docker.Memory != "" {
+		runArgs = append(runArgs, "--memory", p.docker.Memory, "--memory-swap", p.docker.Memory)
+	}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 && gid >= 0 {
+		runArgs = append(runArgs, "--user", strconv.Itoa(uid)+":"+strconv.Itoa(gid))
+	}
+	runArgs = append(runArgs,
+		"--volume", workspaceDir+":"+dockerWorkspaceMount+":"+mode,
+		"--workdir", containerDir,
+		p.docker.Image,
+		command,
+	)
+	return append(runArgs, args...)
+}
+
+// cleanupContainer force-removes a container whose run hit the timeout;
+// killing the docker client alone leaves the container running.
+func (p *Plugin) cleanupContainer(runCtx context.Context, container string) {
+	if container == "" || runCtx.Err() == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_ = exec.CommandContext(ctx, p.docker.Binary, "rm", "--force", container).Run()
+}
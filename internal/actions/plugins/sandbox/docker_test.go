@@ -0,0 +1,74 @@
+package sandbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestExecuteInDockerMountsWorkspaceReadOnly(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available in test environment")
+	}
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "ws-1", "notes"), 0o755); err != nil {
+		t.Fatalf("mkdir workspace: %v", err)
+	}
+	fakeDocker := filepath.Join(t.TempDir(), "docker")
+	if err := os.WriteFile(fakeDocker, []byte("#!/bin/sh\necho \"$@\"\n"), 0o755); err != nil {
+		t.Fatalf("write fake docker: %v", err)
+	}
+	plugin := New(Config{
+		Enabled:         true,
+		WorkspaceRoot:   root,
+		AllowedCommands: []string{"ls"},
+		Timeout:         10 * time.Second,
+		Docker: DockerConfig{
+			Image:   "alpine:3.20",
+			Binary:  fakeDocker,
+			Runtime: "runsc",
+			CPUs:    "0.5",
+			Memory:  "256m",
+		},
+	})
+
+	approval := store.ActionApproval{
+		WorkspaceID:  "ws-1",
+		ActionType:   "run_command",
+		ActionTarget: "ls",
+		Payload:      map[string]any{"args": []any{"-la"}, "cwd": "notes"},
+	}
+	result, err := plugin.Execute(context.Background(), approval)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	for _, want := range []string{
+		"run --rm --name agent-runtime-sandbox-",
+		"--network none",
+		"--read-only",
+		"--runtime runsc",
+		"--cpus 0.5",
+		"--memory 256m",
+		"--volume " + filepath.Join(root, "ws-1") + ":/workspace:ro",
+		"--workdir /workspace/notes alpine:3.20 ls -la",
+	} {
+		if !strings.Contains(result.Message, want) {
+			t.Fatalf("expected %q in docker invocation: %s", want, result.Message)
+		}
+	}
+
+	approval.Payload["write"] = true
+	result, err = plugin.Execute(context.Background(), approval)
+	if err != nil {
+		t.Fatalf("execute with write grant failed: %v", err)
+	}
+	if !strings.Contains(result.Message, ":/workspace:rw") {
+		t.Fatalf("expected read-write mount when write is granted: %s", result.Message)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/dwizi/agent-runtime/internal/actions/executor"
 	"github.com/dwizi/agent-runtime/internal/agenterr"
 	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/google/uuid"
 )
 
 type Config struct {
@@ -25,6 +26,7 @@ type Config struct {
 	RunnerArgs      []string
 	Timeout         time.Duration
 	MaxOutputBytes  int
+	Docker          DockerConfig
 }
 
 type Plugin struct {
@@ -35,6 +37,21 @@ type Plugin struct {
 	runnerArgs     []string
 	timeout        time.Duration
 	maxOutputBytes int
+	docker         DockerConfig
+	egress         CurlGuard
+}
+
+// CurlGuard applies the egress policy to curl commands when they run.
+type CurlGuard interface {
+	PinCurl(ctx context.Context, workspaceID string, args []string) ([]string, error)
+}
+
+// SetEgressGuard checks curl URLs again at execution time and pins each
+// host to the address that passed the check, with redirects turned off.
+// The check made before approval is not enough: DNS answers change and
+// redirects can lead anywhere.
+func (p *Plugin) SetEgressGuard(guard CurlGuard) {
+	p.egress = guard
 }
 
 func New(cfg Config) *Plugin {
@@ -62,6 +79,7 @@ func New(cfg Config) *Plugin {
 		runnerArgs:     append([]string{}, cfg.RunnerArgs...),
 		timeout:        timeout,
 		maxOutputBytes: maxOutputBytes,
+		docker:         normalizeDockerConfig(cfg.Docker),
 	}
 }
 
@@ -84,30 +102,40 @@ func (p *Plugin) Execute(ctx context.Context, approval store.ActionApproval) (ex
 	if !p.isAllowed(command) {
 		return executor.Result{}, fmt.Errorf("%w: command %q", agenterr.ErrToolNotAllowed, command)
 	}
+	if p.egress != nil && strings.EqualFold(command, "curl") {
+		// The pinned arguments also keep the wget fallback, which cannot
+		// pin hosts, from running.
+		pinned, err := p.egress.PinCurl(ctx, approval.WorkspaceID, args)
+		if err != nil {
+			return executor.Result{}, fmt.Errorf("%w: %v", agenterr.ErrToolNotAllowed, err)
+		}
+		args = pinned
+	}
 	execCommand, execArgs, fallbackUsed := p.resolveExecutionCommand(command, args)
 	workdir, err := p.resolveWorkingDir(approval)
 	if err != nil {
 		return executor.Result{}, fmt.Errorf("%w: %v", agenterr.ErrToolPreflight, err)
 	}
+	writable := getBool(approval.Payload, "write")
 	runCtx, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
-	execName, execSpecArgs := p.executionSpec(execCommand, execArgs)
-	cmd := exec.CommandContext(runCtx, execName, execSpecArgs...)
-	cmd.Dir = workdir
+	cmd, container := p.buildCommand(runCtx, execCommand, execArgs, workdir, writable)
 	combinedOutput := &limitedBuffer{MaxBytes: p.maxOutputBytes}
 	cmd.Stdout = combinedOutput
 	cmd.Stderr = combinedOutput
-	if err := cmd.Run(); err != nil {
+	err = cmd.Run()
+	p.cleanupContainer(runCtx, container)
+	if err != nil {
 		if retryArgs, retryFallback, ok := retryGitDiffNoIndex(execCommand, execArgs, err, combinedOutput.String()); ok {
 			runCtxRetry, cancelRetry := context.WithTimeout(ctx, p.timeout)
 			defer cancelRetry()
-			execNameRetry, execSpecArgsRetry := p.executionSpec(execCommand, retryArgs)
-			retryCmd := exec.CommandContext(runCtxRetry, execNameRetry, execSpecArgsRetry...)
-			retryCmd.Dir = workdir
+			retryCmd, retryContainer := p.buildCommand(runCtxRetry, execCommand, retryArgs, workdir, writable)
 			retryOutput := &limitedBuffer{MaxBytes: p.maxOutputBytes}
 			retryCmd.Stdout = retryOutput
 			retryCmd.Stderr = retryOutput
-			if retryErr := retryCmd.Run(); retryErr == nil || isExpectedNonZeroExit(execCommand, retryArgs, retryErr) {
+			retryErr := retryCmd.Run()
+			p.cleanupContainer(runCtxRetry, retryContainer)
+			if retryErr == nil || isExpectedNonZeroExit(execCommand, retryArgs, retryErr) {
 				message := summarizeCommandOutcome(command, args, retryOutput.String(), retryOutput.Truncated)
 				fallbackUsed = mergeFallbackHint(fallbackUsed, retryFallback)
 				if strings.TrimSpace(fallbackUsed) != "" {
@@ -146,7 +174,7 @@ func (p *Plugin) resolveExecutionCommand(command string, args []string) (string,
 	if command == "" {
 		return command, args, ""
 	}
-	if strings.TrimSpace(p.runnerCommand) != "" {
+	if strings.TrimSpace(p.runnerCommand) != "" || p.docker.enabled() {
 		return command, args, ""
 	}
 	if _, err := exec.LookPath(command); err == nil {
@@ -411,6 +439,12 @@ func (p *Plugin) resolveWorkingDir(approval store.ActionApproval) (string, error
 	return resolved, nil
 }
 
+// ParseCommand extracts the executable and arguments from a command
+// approval, so other command backends apply the same validation.
+func ParseCommand(approval store.ActionApproval) (string, []string, error) {
+	return parseCommand(approval)
+}
+
 func parseCommand(approval store.ActionApproval) (string, []string, error) {
 	command := strings.TrimSpace(approval.ActionTarget)
 	commandFromPayload := getString(approval.Payload, "command")
@@ -493,6 +527,21 @@ func getString(payload map[string]any, key string) string {
 	}
 }
 
+func getBool(payload map[string]any, key string) bool {
+	value, ok := getPayloadValue(payload, key)
+	if !ok || value == nil {
+		return false
+	}
+	switch casted := value.(type) {
+	case bool:
+		return casted
+	case string:
+		return strings.EqualFold(strings.TrimSpace(casted), "true")
+	default:
+		return false
+	}
+}
+
 func getPayloadValue(payload map[string]any, key string) (any, bool) {
 	if payload == nil {
 		return nil, false
@@ -569,6 +618,20 @@ func looksLikeRedirectResponse(output string) bool {
 		(strings.Contains(lower, "http-equiv=\"refresh\"") && strings.Contains(lower, "url="))
 }
 
+// buildCommand prepares the process for one execution. In Docker mode it
+// also returns the container name so a timed-out run can be removed.
+func (p *Plugin) buildCommand(ctx context.Context, command string, args []string, workdir string, writable bool) (*exec.Cmd, string) {
+	if p.docker.enabled() {
+		container := "agent-runtime-sandbox-" + uuid.NewString()
+		runArgs := p.dockerRunArgs(container, command, args, workdir, writable)
+		return exec.CommandContext(ctx, p.docker.Binary, runArgs...), container
+	}
+	execName, execArgs := p.executionSpec(command, args)
+	cmd := exec.CommandContext(ctx, execName, execArgs...)
+	cmd.Dir = workdir
+	return cmd, ""
+}
+
 func (p *Plugin) executionSpec(command string, args []string) (string, []string) {
 	if strings.TrimSpace(p.runnerCommand) == "" {
 		return command, args
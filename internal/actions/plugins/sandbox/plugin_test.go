@@ -2,6 +2,7 @@ package sandbox
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -454,3 +455,54 @@ func TestExecuteGitDiffTreatsExitCodeOneAsSuccess(t *testing.T) {
 		t.Fatalf("expected git diff output, got %s", result.Message)
 	}
 }
+
+type fakeCurlGuard struct {
+	workspaceID string
+}
+
+func (f *fakeCurlGuard) PinCurl(ctx context.Context, workspaceID string, args []string) ([]string, error) {
+	f.workspaceID = workspaceID
+	if strings.Contains(strings.Join(args, " "), "169.254.169.254") {
+		return nil, errors.New("egress policy denied http://169.254.169.254: address is private")
+	}
+	return append([]string{"--resolve", "example.com:443:93.184.216.34"}, "-sS", "https://example.com"), nil
+}
+
+func TestExecutePinsCurlThroughTheEgressGuard(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "ws-1"), 0o755); err != nil {
+		t.Fatalf("mkdir workspace: %v", err)
+	}
+	plugin := New(Config{
+		Enabled:         true,
+		WorkspaceRoot:   root,
+		AllowedCommands: []string{"curl"},
+		RunnerCommand:   "echo",
+		Timeout:         10 * time.Second,
+	})
+	guard := &fakeCurlGuard{}
+	plugin.SetEgressGuard(guard)
+
+	result, err := plugin.Execute(context.Background(), store.ActionApproval{
+		WorkspaceID:  "ws-1",
+		ActionType:   "run_command",
+		ActionTarget: "curl",
+		Payload:      map[string]any{"args": []any{"-sSL", "https://example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if guard.workspaceID != "ws-1" || !strings.Contains(result.Message, "curl --resolve example.com:443:93.184.216.34 -sS https://example.com") {
+		t.Fatalf("expected the pinned command to run, got %s", result.Message)
+	}
+
+	_, err = plugin.Execute(context.Background(), store.ActionApproval{
+		WorkspaceID:  "ws-1",
+		ActionType:   "run_command",
+		ActionTarget: "curl",
+		Payload:      map[string]any{"args": []any{"http://169.254.169.254/latest"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "egress policy denied") {
+		t.Fatalf("expected the egress policy to refuse at execution, got %v", err)
+	}
+}
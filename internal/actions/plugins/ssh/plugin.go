@@ -0,0 +1,325 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/actions/plugins/sandbox"
+	"github.com/dwizi/agent-runtime/internal/agenterr"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// anyWorkspace keys hosts that every workspace may reach.
+const anyWorkspace = "*"
+
+type Config struct {
+	Binary         string
+	KeyFile        string
+	KnownHostsFile string
+	User           string
+	Hosts          map[string][]Host
+	Timeout        time.Duration
+	MaxOutputBytes int
+}
+
+// Host is an allowlisted SSH destination. User and Port are optional.
+type Host struct {
+	User string
+	Name string
+	Port int
+}
+
+func (h Host) String() string {
+	value := h.Name
+	if h.User != "" {
+		value = h.User + "@" + value
+	}
+	if h.Port > 0 {
+		value += ":" + strconv.Itoa(h.Port)
+	}
+	return value
+}
+
+type Plugin struct {
+	binary         string
+	keyFile        string
+	knownHostsFile string
+	user           string
+	hosts          map[string][]Host
+	timeout        time.Duration
+	maxOutputBytes int
+}
+
+func New(cfg Config) *Plugin {
+	binary := strings.TrimSpace(cfg.Binary)
+	if binary == "" {
+		binary = "ssh"
+	}
+	timeout := cfg.Timeout
+	if timeout < time.Second {
+		timeout = 5 * time.Minute
+	}
+	maxOutputBytes := cfg.MaxOutputBytes
+	if maxOutputBytes < 256 {
+		maxOutputBytes = 64 * 1024
+	}
+	hosts := map[string][]Host{}
+	for workspaceID, entries := range cfg.Hosts {
+		key := strings.TrimSpace(workspaceID)
+		if key == "" || len(entries) == 0 {
+			continue
+		}
+		hosts[key] = append(hosts[key], entries...)
+	}
+	return &Plugin{
+		binary:         binary,
+		keyFile:        strings.TrimSpace(cfg.KeyFile),
+		knownHostsFile: strings.TrimSpace(cfg.KnownHostsFile),
+		user:           strings.TrimSpace(cfg.User),
+		hosts:          hosts,
+		timeout:        timeout,
+		maxOutputBytes: maxOutputBytes,
+	}
+}
+
+func (p *Plugin) PluginKey() string {
+	return "ssh_command"
+}
+
+func (p *Plugin) ActionTypes() []string {
+	return []string{"ssh_command", "remote_command"}
+}
+
+func (p *Plugin) Execute(ctx context.Context, approval store.ActionApproval) (executor.Result, error) {
+	if p == nil {
+		return executor.Result{}, fmt.Errorf("ssh command execution is not configured")
+	}
+	requested, err := ParseHost(payloadString(approval.Payload, "host"))
+	if err != nil {
+		return executor.Result{}, fmt.Errorf("%w: %v", agenterr.ErrToolInvalidArgs, err)
+	}
+	host, ok := p.resolveHost(approval.WorkspaceID, requested)
+	if !ok {
+		return executor.Result{}, fmt.Errorf("%w: host %q is not allowlisted for workspace %s", agenterr.ErrToolNotAllowed, requested.String(), approval.WorkspaceID)
+	}
+	command, args, err := sandbox.ParseCommand(approval)
+	if err != nil {
+		return executor.Result{}, fmt.Errorf("%w: %v", agenterr.ErrToolInvalidArgs, err)
+	}
+	remoteCommand := shellJoin(append([]string{command}, args...))
+
+	runCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(runCtx, p.binary, p.sshArgs(host, remoteCommand)...)
+	stdout := &limitedBuffer{max: p.maxOutputBytes}
+	stderr := &limitedBuffer{max: p.maxOutputBytes}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	runErr := cmd.Run()
+
+	exitCode := 0
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(runErr, &exitErr) {
+			if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+				return executor.Result{}, fmt.Errorf("ssh command timed out after %s", p.timeout)
+			}
+			return executor.Result{}, fmt.Errorf("%w: run ssh: %v", agenterr.ErrToolPreflight, runErr)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	report := formatReport(host, remoteCommand, exitCode, stdout, stderr)
+	if runErr != nil {
+		// ssh reserves 255 for its own connection and authentication errors.
+		if exitCode == 255 {
+			return executor.Result{}, fmt.Errorf("%w: ssh connection failed\n%s", agenterr.ErrToolPreflight, report)
+		}
+		return executor.Result{}, fmt.Errorf("remote command failed\n%s", report)
+	}
+	return executor.Result{
+		Plugin:  p.PluginKey(),
+		Message: report,
+	}, nil
+}
+
+// resolveHost matches the requested host against the workspace allowlist and
+// the shared "*" entries. An allowlist entry pins the user and port when it
+// sets them; otherwise the request or the plugin default applies.
+func (p *Plugin) resolveHost(workspaceID string, requested Host) (Host, bool) {
+	candidates := append([]Host{}, p.hosts[strings.TrimSpace(workspaceID)]...)
+	candidates = append(candidates, p.hosts[anyWorkspace]...)
+	for _, allowed := range candidates {
+		if !strings.EqualFold(allowed.Name, requested.Name) {
+			continue
+		}
+		if allowed.Port > 0 && requested.Port > 0 && allowed.Port != requested.Port {
+			continue
+		}
+		if allowed.User != "" && requested.User != "" && allowed.User != requested.User {
+			continue
+		}
+		resolved := allowed
+		if resolved.Port == 0 {
+			resolved.Port = requested.Port
+		}
+		if resolved.User == "" {
+			resolved.User = requested.User
+		}
+		if resolved.User == "" {
+			resolved.User = p.user
+		}
+		return resolved, true
+	}
+	return Host{}, false
+}
+
+func (p *Plugin) sshArgs(host Host, remoteCommand string) []string {
+	args := []string{
+		"-T",
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=yes",
+		"-o", "ConnectTimeout=15",
+	}
+	if p.keyFile != "" {
+		args = append(args, "-i", p.keyFile, "-o", "IdentitiesOnly=yes")
+	}
+	if p.knownHostsFile != "" {
+		args = append(args, "-o", "UserKnownHostsFile="+p.knownHostsFile)
+	}
+	if host.Port > 0 {
+		args = append(args, "-p", strconv.Itoa(host.Port))
+	}
+	if host.User != "" {
+		args = append(args, "-l", host.User)
+	}
+	return append(args, "--", host.Name, remoteCommand)
+}
+
+// ParseHost parses "[user@]host[:port]".
+func ParseHost(value string) (Host, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Host{}, fmt.Errorf("host is required")
+	}
+	host := Host{}
+	if at := strings.LastIndex(value, "@"); at >= 0 {
+		host.User = strings.TrimSpace(value[:at])
+		value = value[at+1:]
+	}
+	if colon := strings.LastIndex(value, ":"); colon >= 0 && !strings.Contains(value[:colon], ":") {
+		port, err := strconv.Atoi(value[colon+1:])
+		if err != nil || port < 1 || port > 65535 {
+			return Host{}, fmt.Errorf("invalid port in %q", value)
+		}
+		host.Port = port
+		value = value[:colon]
+	}
+	host.Name = strings.TrimSpace(value)
+	if host.Name == "" || strings.HasPrefix(host.Name, "-") || strings.ContainsAny(host.Name, " \t/") {
+		return Host{}, fmt.Errorf("invalid host %q", value)
+	}
+	if strings.HasPrefix(host.User, "-") || strings.ContainsAny(host.User, " \t") {
+		return Host{}, fmt.Errorf("invalid user %q", host.User)
+	}
+	return host, nil
+}
+
+// ParseHostAllowlist parses "workspace=host,host;other=host". The workspace
+// "*" applies to every workspace.
+func ParseHostAllowlist(value string) (map[string][]Host, error) {
+	result := map[string][]Host{}
+	for _, group := range strings.Split(value, ";") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+		workspaceID, list, ok := strings.Cut(group, "=")
+		workspaceID = strings.TrimSpace(workspaceID)
+		if !ok || workspaceID == "" {
+			return nil, fmt.Errorf("invalid ssh host allowlist entry %q: expected workspace=host[,host]", group)
+		}
+		for _, item := range strings.Split(list, ",") {
+			if strings.TrimSpace(item) == "" {
+				continue
+			}
+			host, err := ParseHost(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ssh host allowlist entry for %s: %w", workspaceID, err)
+			}
+			result[workspaceID] = append(result[workspaceID], host)
+		}
+	}
+	return result, nil
+}
+
+func formatReport(host Host, remoteCommand string, exitCode int, stdout, stderr *limitedBuffer) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "ssh %s: %s (exit %d)", host.String(), remoteCommand, exitCode)
+	for _, stream := range []struct {
+		name   string
+		buffer *limitedBuffer
+	}{{"stdout", stdout}, {"stderr", stderr}} {
+		output := strings.TrimRight(stream.buffer.String(), "\n")
+		if output == "" {
+			continue
+		}
+		fmt.Fprintf(&builder, "\n--- %s ---\n%s", stream.name, output)
+		if stream.buffer.truncated {
+			builder.WriteString("\n[truncated]")
+		}
+	}
+	return builder.String()
+}
+
+// shellJoin quotes each word for the remote POSIX shell, since ssh sends the
+// command as a single string.
+func shellJoin(words []string) string {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		if word != "" && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,+@%") == "" {
+			quoted = append(quoted, word)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(word, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+func payloadString(payload map[string]any, key string) string {
+	if payload == nil {
+		return ""
+	}
+	value, _ := payload[key].(string)
+	return strings.TrimSpace(value)
+}
+
+type limitedBuffer struct {
+	max       int
+	truncated bool
+	buffer    bytes.Buffer
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := l.max - l.buffer.Len()
+	if remaining <= 0 {
+		l.truncated = true
+		return len(p), nil
+	}
+	if len(p) > remaining {
+		l.buffer.Write(p[:remaining])
+		l.truncated = true
+		return len(p), nil
+	}
+	return l.buffer.Write(p)
+}
+
+func (l *limitedBuffer) String() string {
+	return l.buffer.String()
+}
@@ -0,0 +1,97 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/agenterr"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func writeFakeSSH(t *testing.T, script string) string {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available in test environment")
+	}
+	path := filepath.Join(t.TempDir(), "ssh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("write fake ssh: %v", err)
+	}
+	return path
+}
+
+func TestExecuteRunsOnAllowlistedHost(t *testing.T) {
+	fake := writeFakeSSH(t, "echo \"args: $*\"\necho 'disk warning' >&2\n")
+	hosts, err := ParseHostAllowlist("ws-1=deploy@web-1.internal:2222;*=bastion")
+	if err != nil {
+		t.Fatalf("parse allowlist: %v", err)
+	}
+	plugin := New(Config{Binary: fake, KeyFile: "/keys/id_ed25519", KnownHostsFile: "/keys/known_hosts", User: "agent", Hosts: hosts, Timeout: 10 * time.Second})
+
+	result, err := plugin.Execute(context.Background(), store.ActionApproval{
+		WorkspaceID:  "ws-1",
+		ActionType:   "ssh_command",
+		ActionTarget: "df",
+		Payload:      map[string]any{"host": "web-1.internal", "args": []any{"-h", "/var/lib/app data"}},
+	})
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	for _, want := range []string{
+		"ssh deploy@web-1.internal:2222: df -h '/var/lib/app data' (exit 0)",
+		"-i /keys/id_ed25519 -o IdentitiesOnly=yes -o UserKnownHostsFile=/keys/known_hosts -p 2222 -l deploy -- web-1.internal df -h '/var/lib/app data'",
+		"--- stderr ---\ndisk warning",
+	} {
+		if !strings.Contains(result.Message, want) {
+			t.Fatalf("expected %q in message, got %s", want, result.Message)
+		}
+	}
+
+	result, err = plugin.Execute(context.Background(), store.ActionApproval{
+		WorkspaceID:  "ws-2",
+		ActionTarget: "uptime",
+		Payload:      map[string]any{"host": "bastion"},
+	})
+	if err != nil || !strings.Contains(result.Message, "-l agent -- bastion uptime") {
+		t.Fatalf("expected shared host with default user, got %v %s", err, result.Message)
+	}
+}
+
+func TestExecuteRejectsHostOutsideWorkspaceAllowlist(t *testing.T) {
+	fake := writeFakeSSH(t, "echo should-not-run\n")
+	plugin := New(Config{Binary: fake, Hosts: map[string][]Host{"ws-1": {{Name: "web-1"}}}})
+	for _, host := range []string{"web-1", "db-1", "-oProxyCommand=sh"} {
+		_, err := plugin.Execute(context.Background(), store.ActionApproval{
+			WorkspaceID:  "ws-2",
+			ActionTarget: "uptime",
+			Payload:      map[string]any{"host": host},
+		})
+		if !errors.Is(err, agenterr.ErrToolNotAllowed) && !errors.Is(err, agenterr.ErrToolInvalidArgs) {
+			t.Fatalf("expected host %q to be rejected, got %v", host, err)
+		}
+	}
+}
+
+func TestExecuteRecordsOutputOnRemoteFailure(t *testing.T) {
+	fake := writeFakeSSH(t, "echo partial\necho 'permission denied' >&2\nexit 3\n")
+	plugin := New(Config{Binary: fake, Hosts: map[string][]Host{"ws-1": {{Name: "web-1"}}}})
+	_, err := plugin.Execute(context.Background(), store.ActionApproval{
+		WorkspaceID:  "ws-1",
+		ActionTarget: "systemctl",
+		Payload:      map[string]any{"host": "web-1", "args": []any{"restart", "app"}},
+	})
+	if err == nil {
+		t.Fatal("expected remote failure")
+	}
+	for _, want := range []string{"(exit 3)", "--- stdout ---\npartial", "--- stderr ---\npermission denied"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got %v", want, err)
+		}
+	}
+}
@@ -11,11 +11,13 @@ import (
 	"time"
 
 	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/egress"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
 type Plugin struct {
 	client *http.Client
+	guard  *egress.Guard
 }
 
 func New(timeout time.Duration) *Plugin {
@@ -27,6 +29,21 @@ func New(timeout time.Duration) *Plugin {
 	}
 }
 
+// SetEgressGuard checks every request and the connections behind it,
+// redirects included, against the egress policy of the action's workspace.
+func (p *Plugin) SetEgressGuard(guard *egress.Guard) {
+	timeout := 15 * time.Second
+	if p.client != nil && p.client.Timeout > 0 {
+		timeout = p.client.Timeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would hide the real destination from the dial check.
+	transport.Proxy = nil
+	transport.DialContext = guard.DialContext
+	p.client = &http.Client{Timeout: timeout, Transport: transport}
+	p.guard = guard
+}
+
 func (p *Plugin) PluginKey() string {
 	return "webhook"
 }
@@ -53,6 +70,12 @@ func (p *Plugin) Execute(ctx context.Context, approval store.ActionApproval) (ex
 	if !strings.HasPrefix(strings.ToLower(url), "http://") && !strings.HasPrefix(strings.ToLower(url), "https://") {
 		return executor.Result{}, fmt.Errorf("unsupported webhook url scheme")
 	}
+	if p.guard != nil {
+		ctx = egress.WithWorkspace(ctx, approval.WorkspaceID)
+		if err := p.guard.Check(ctx, approval.WorkspaceID, url); err != nil {
+			return executor.Result{}, err
+		}
+	}
 
 	bodyBytes, err := resolveBody(approval.Payload)
 	if err != nil {
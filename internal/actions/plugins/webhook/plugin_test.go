@@ -2,12 +2,14 @@ package webhook
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/egress"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -61,3 +63,26 @@ func TestPluginExecuteFailureStatus(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestPluginExecuteEgressGuard(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	approval := store.ActionApproval{WorkspaceID: "ws-1", ActionType: "webhook", ActionTarget: server.URL}
+
+	plugin := New(5 * time.Second)
+	plugin.SetEgressGuard(egress.New(egress.Config{}))
+	_, err := plugin.Execute(context.Background(), approval)
+	var violation *egress.Violation
+	if !errors.As(err, &violation) || !strings.Contains(violation.Reason, "is private") || hits != 0 {
+		t.Fatalf("expected loopback refused, got %v (hits=%d)", err, hits)
+	}
+
+	plugin.SetEgressGuard(egress.New(egress.Config{Global: egress.Rules{AllowCIDRs: []string{"127.0.0.1"}}}))
+	if _, err := plugin.Execute(context.Background(), approval); err != nil || hits != 1 {
+		t.Fatalf("expected an allowlisted range to pass, got %v (hits=%d)", err, hits)
+	}
+}
@@ -1,6 +1,7 @@
 package adminclient
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/tls"
@@ -8,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -17,6 +19,10 @@ import (
 	"github.com/dwizi/agent-runtime/internal/config"
 )
 
+// ErrRevisionConflict is returned when the server refuses a mutation because
+// the record changed after the caller loaded it.
+var ErrRevisionConflict = errors.New("revision conflict")
+
 type Client struct {
 	baseURL string
 	http    *http.Client
@@ -80,6 +86,10 @@ type Objective struct {
 	AvgRunDurationMs     int64  `json:"avg_run_duration_ms"`
 	LastSuccessUnix      *int64 `json:"last_success_unix"`
 	LastFailureUnix      *int64 `json:"last_failure_unix"`
+	AutoPausedReason     string `json:"auto_paused_reason"`
+	ReportChangesOnly    bool   `json:"report_changes_only"`
+	DigestPeriod         string `json:"digest_period"`
+	Revision             int    `json:"revision"`
 }
 
 type ListObjectivesResponse struct {
@@ -87,28 +97,486 @@ type ListObjectivesResponse struct {
 	Count int         `json:"count"`
 }
 
+// CreateObjectiveRequest creates a schedule objective (CronExpr, which also
+// takes descriptors such as "@every 30m") or an event objective (EventKey).
+type CreateObjectiveRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	ContextID   string `json:"context_id"`
+	Title       string `json:"title"`
+	Prompt      string `json:"prompt"`
+	TriggerType string `json:"trigger_type"`
+	EventKey    string `json:"event_key,omitempty"`
+	CronExpr    string `json:"cron_expr,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
+	Active      *bool  `json:"active,omitempty"`
+	// ReportChangesOnly compares each run with the previous one and
+	// reports only what changed.
+	ReportChangesOnly bool `json:"report_changes_only,omitempty"`
+	// DigestPeriod is "daily" or "weekly" to collect successful runs into
+	// one digest message per context.
+	DigestPeriod string `json:"digest_period,omitempty"`
+}
+
+// UpdateObjectiveRequest changes the non-nil fields of an objective. A
+// non-zero Revision makes the server refuse the edit if the objective
+// changed since it was read.
+type UpdateObjectiveRequest struct {
+	ID          string  `json:"id"`
+	Title       *string `json:"title,omitempty"`
+	Prompt      *string `json:"prompt,omitempty"`
+	TriggerType *string `json:"trigger_type,omitempty"`
+	EventKey    *string `json:"event_key,omitempty"`
+	CronExpr    *string `json:"cron_expr,omitempty"`
+	Timezone    *string `json:"timezone,omitempty"`
+	Active      *bool   `json:"active,omitempty"`
+	// ReportChangesOnly turns change detection on or off.
+	ReportChangesOnly *bool `json:"report_changes_only,omitempty"`
+	// DigestPeriod sets the digest period; "" posts every run again.
+	DigestPeriod *string `json:"digest_period,omitempty"`
+	Revision     int     `json:"revision,omitempty"`
+}
+
+type ObjectiveTemplateParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     string `json:"default"`
+	Required    bool   `json:"required"`
+}
+
+type ObjectiveTemplate struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	TriggerType string                   `json:"trigger_type"`
+	Params      []ObjectiveTemplateParam `json:"params"`
+}
+
+type ListObjectiveTemplatesResponse struct {
+	Items []ObjectiveTemplate `json:"items"`
+	Count int                 `json:"count"`
+}
+
 type Task struct {
+	ID               string `json:"id"`
+	WorkspaceID      string `json:"workspace_id"`
+	ContextID        string `json:"context_id"`
+	Kind             string `json:"kind"`
+	Title            string `json:"title"`
+	Prompt           string `json:"prompt"`
+	Status           string `json:"status"`
+	RouteClass       string `json:"route_class"`
+	Priority         string `json:"priority"`
+	DueAtUnix        int64  `json:"due_at_unix"`
+	AssignedLane     string `json:"assigned_lane"`
+	SourceConnector  string `json:"source_connector"`
+	SourceExternalID string `json:"source_external_id"`
+	SourceUserID     string `json:"source_user_id"`
+	SourceText       string `json:"source_text"`
+	Attempts         int    `json:"attempts"`
+	WorkerID         int    `json:"worker_id"`
+	StartedAtUnix    int64  `json:"started_at_unix"`
+	FinishedAtUnix   int64  `json:"finished_at_unix"`
+	ResultSummary    string `json:"result_summary"`
+	ResultPath       string `json:"result_path"`
+	ErrorMessage     string `json:"error_message"`
+	CreatedAtUnix    int64  `json:"created_at_unix"`
+	UpdatedAtUnix    int64  `json:"updated_at_unix"`
+	Revision         int    `json:"revision"`
+	// DependsOn lists the tasks that must succeed first and Blocks the
+	// tasks waiting on this one.
+	DependsOn []string `json:"depends_on"`
+	Blocks    []string `json:"blocks"`
+	// DeadLetteredAtUnix is set once the task failed every retry.
+	DeadLetteredAtUnix int64 `json:"dead_lettered_at_unix"`
+	// RunAtUnix is when a scheduled task may start.
+	RunAtUnix int64 `json:"run_at_unix"`
+	// Progress of a running task; ProgressPercent is -1 when unknown and
+	// ProgressUpdatedAtUnix zero when the task reported none.
+	ProgressStep          string `json:"progress_step"`
+	ProgressPercent       int    `json:"progress_percent"`
+	ProgressUpdatedAtUnix int64  `json:"progress_updated_at_unix"`
+}
+
+// TaskResult is the markdown result file a finished task wrote. ResultPath
+// is relative to the task's workspace.
+type TaskResult struct {
+	TaskID      string `json:"task_id"`
+	WorkspaceID string `json:"workspace_id"`
+	ResultPath  string `json:"result_path"`
+	Content     string `json:"content"`
+	Truncated   bool   `json:"truncated"`
+}
+
+// TaskArtifact is a file a task produced. Path is relative to the task's
+// workspace; DownloadURL is a signed link, set when links are enabled.
+type TaskArtifact struct {
+	ID            string `json:"id"`
+	TaskID        string `json:"task_id"`
+	WorkspaceID   string `json:"workspace_id"`
+	Path          string `json:"path"`
+	Kind          string `json:"kind"`
+	Title         string `json:"title"`
+	MediaType     string `json:"media_type"`
+	SizeBytes     int64  `json:"size_bytes"`
+	CreatedAtUnix int64  `json:"created_at_unix"`
+	DownloadURL   string `json:"download_url,omitempty"`
+}
+
+type ListTasksResponse struct {
+	Items []Task `json:"items"`
+	Count int    `json:"count"`
+}
+
+type TaskPlanStep struct {
+	Position      int    `json:"position"`
+	Description   string `json:"description"`
+	Status        string `json:"status"`
+	Result        string `json:"result"`
+	UpdatedAtUnix int64  `json:"updated_at_unix"`
+}
+
+type TaskPlanResponse struct {
+	TaskID string         `json:"task_id"`
+	Steps  []TaskPlanStep `json:"steps"`
+	Count  int            `json:"count"`
+}
+
+type QuotaUsage struct {
+	Resource string `json:"resource"`
+	Period   string `json:"period"`
+	Limit    int    `json:"limit"`
+	Used     int    `json:"used"`
+}
+
+type QuotasResponse struct {
+	WorkspaceID string       `json:"workspace_id"`
+	Quotas      []QuotaUsage `json:"quotas"`
+}
+
+// QuotaOverrides are per-workspace quota limits. Nil fields fall back to the
+// runtime defaults; zero means unlimited.
+type QuotaOverrides struct {
+	WorkspaceID    string `json:"workspace_id"`
+	TasksPerDay    *int   `json:"tasks_per_day,omitempty"`
+	Objectives     *int   `json:"objectives,omitempty"`
+	ActionsPerDay  *int   `json:"actions_per_day,omitempty"`
+	TokensPerMonth *int   `json:"tokens_per_month,omitempty"`
+}
+
+type TrashItem struct {
+	Kind          string `json:"kind"`
+	ID            string `json:"id"`
+	WorkspaceID   string `json:"workspace_id"`
+	Title         string `json:"title"`
+	Status        string `json:"status"`
+	DeletedAtUnix int64  `json:"deleted_at_unix"`
+	PurgeAtUnix   int64  `json:"purge_at_unix"`
+}
+
+type ListTrashResponse struct {
+	Items         []TrashItem `json:"items"`
+	Count         int         `json:"count"`
+	RetentionDays int         `json:"retention_days"`
+}
+
+type Case struct {
+	ID             string     `json:"id"`
+	WorkspaceID    string     `json:"workspace_id"`
+	ContextID      string     `json:"context_id"`
+	Kind           string     `json:"kind"`
+	Title          string     `json:"title"`
+	Summary        string     `json:"summary"`
+	Status         string     `json:"status"`
+	OwnerUserID    string     `json:"owner_user_id"`
+	CreatedAtUnix  int64      `json:"created_at_unix"`
+	UpdatedAtUnix  int64      `json:"updated_at_unix"`
+	ResolvedAtUnix int64      `json:"resolved_at_unix,omitempty"`
+	Items          []CaseItem `json:"items,omitempty"`
+}
+
+type CaseItem struct {
+	Kind          string `json:"kind"`
+	ItemID        string `json:"item_id"`
+	Excerpt       string `json:"excerpt"`
+	CreatedAtUnix int64  `json:"created_at_unix"`
+}
+
+type ListCasesResponse struct {
+	Items []Case `json:"items"`
+	Count int    `json:"count"`
+}
+
+type SearchResult struct {
+	Kind          string `json:"kind"`
+	ID            string `json:"id"`
+	WorkspaceID   string `json:"workspace_id"`
+	Title         string `json:"title"`
+	Snippet       string `json:"snippet"`
+	Status        string `json:"status"`
+	Risk          string `json:"risk,omitempty"`
+	UpdatedAtUnix int64  `json:"updated_at_unix"`
+}
+
+type SearchResponse struct {
+	Items []SearchResult `json:"items"`
+	Count int            `json:"count"`
+}
+
+type AuditEvent struct {
+	ID            string `json:"id"`
+	WorkspaceID   string `json:"workspace_id"`
+	ContextID     string `json:"context_id"`
+	Connector     string `json:"connector"`
+	ExternalID    string `json:"external_id"`
+	SourceUserID  string `json:"source_user_id"`
+	EventType     string `json:"event_type"`
+	Stage         string `json:"stage"`
+	ToolName      string `json:"tool_name"`
+	ToolClass     string `json:"tool_class"`
+	Blocked       bool   `json:"blocked"`
+	BlockReason   string `json:"block_reason"`
+	Message       string `json:"message"`
+	CreatedAtUnix int64  `json:"created_at_unix"`
+	Seq           int64  `json:"seq"`
+	PrevHash      string `json:"prev_hash"`
+	Hash          string `json:"hash"`
+}
+
+type AuditEventsResponse struct {
+	Items      []AuditEvent `json:"items"`
+	Count      int          `json:"count"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// AuditQuery filters audit events; empty fields match everything. Since and
+// Until are unix seconds or RFC 3339.
+type AuditQuery struct {
+	WorkspaceID string
+	ContextID   string
+	UserID      string
+	EventType   string
+	Tool        string
+	BlockedOnly bool
+	Since       string
+	Until       string
+}
+
+func (q AuditQuery) values() url.Values {
+	values := url.Values{}
+	for key, value := range map[string]string{
+		"workspace_id": q.WorkspaceID,
+		"context_id":   q.ContextID,
+		"user_id":      q.UserID,
+		"event_type":   q.EventType,
+		"tool":         q.Tool,
+		"since":        q.Since,
+		"until":        q.Until,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			values.Set(key, value)
+		}
+	}
+	if q.BlockedOnly {
+		values.Set("blocked", "true")
+	}
+	return values
+}
+
+// BotfileDrift is one workspace's result from a botfile reconcile check.
+type BotfileDrift struct {
+	WorkspaceID   string   `json:"workspace_id"`
+	Drift         []string `json:"drift"`
+	Fixed         bool     `json:"fixed"`
+	CheckedAtUnix int64    `json:"checked_at_unix"`
+}
+
+type ReconcileBotfilesResponse struct {
+	Items   []BotfileDrift `json:"items"`
+	Count   int            `json:"count"`
+	Drifted int            `json:"drifted"`
+}
+
+// Webhook is a per-workspace webhook subscription. Secret is only filled in
+// by the SaveWebhook call that set or generated it.
+type Webhook struct {
+	ID                  string   `json:"id"`
+	WorkspaceID         string   `json:"workspace_id"`
+	URL                 string   `json:"url"`
+	EventTypes          []string `json:"event_types"`
+	Enabled             bool     `json:"enabled"`
+	HasSecret           bool     `json:"has_secret"`
+	Secret              string   `json:"secret,omitempty"`
+	LastDeliveryAt      string   `json:"last_delivery_at,omitempty"`
+	LastStatus          string   `json:"last_status"`
+	LastError           string   `json:"last_error"`
+	ConsecutiveFailures int      `json:"consecutive_failures"`
+	CreatedAt           string   `json:"created_at"`
+	UpdatedAt           string   `json:"updated_at"`
+}
+
+type WebhooksResponse struct {
+	WorkspaceID string    `json:"workspace_id"`
+	Items       []Webhook `json:"items"`
+	EventTypes  []string  `json:"event_types"`
+}
+
+// Workspace is a workspace with its task and objective counts. Workspaces
+// only known from their tasks or objectives have no slug or name.
+type Workspace struct {
+	ID                   string `json:"id"`
+	Slug                 string `json:"slug"`
+	Name                 string `json:"name"`
+	Kind                 string `json:"kind"`
+	TaskCount            int    `json:"task_count"`
+	OpenTaskCount        int    `json:"open_task_count"`
+	FailedTaskCount      int    `json:"failed_task_count"`
+	ObjectiveCount       int    `json:"objective_count"`
+	ActiveObjectiveCount int    `json:"active_objective_count"`
+}
+
+type ListWorkspacesResponse struct {
+	Items []Workspace `json:"items"`
+	Count int         `json:"count"`
+}
+
+// Change reports that the records of one kind (task, approval or objective)
+// in a workspace changed. Version only grows.
+type Change struct {
+	Kind        string `json:"kind"`
+	WorkspaceID string `json:"workspace_id"`
+	Version     int64  `json:"version"`
+}
+
+// ChatLog is one live chat log of a workspace.
+type ChatLog struct {
+	Connector     string `json:"connector"`
+	ExternalID    string `json:"external_id"`
+	UpdatedAtUnix int64  `json:"updated_at_unix"`
+	SizeBytes     int64  `json:"size_bytes"`
+}
+
+type ListChatLogsResponse struct {
+	Items []ChatLog `json:"items"`
+	Count int       `json:"count"`
+}
+
+// ChatLogEntry is one chat log entry. Direction is inbound, outbound or
+// tool; tool entries are the agent's tool calls.
+type ChatLogEntry struct {
+	TimestampUnix int64  `json:"timestamp_unix"`
+	Direction     string `json:"direction"`
+	Actor         string `json:"actor"`
+	Text          string `json:"text"`
+}
+
+type ChatLogTailResponse struct {
+	Items []ChatLogEntry `json:"items"`
+	Count int            `json:"count"`
+}
+
+// SaveWebhookRequest creates a subscription when ID is empty. On update,
+// empty fields, nil EventTypes and a nil Enabled keep the stored values.
+type SaveWebhookRequest struct {
+	ID          string   `json:"id,omitempty"`
+	WorkspaceID string   `json:"workspace_id,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Secret      string   `json:"secret,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
+
+type ApprovalSignoff struct {
+	UserID         string `json:"user_id"`
+	ApprovedAtUnix int64  `json:"approved_at_unix"`
+}
+
+// Approval is an action waiting for, or decided by, an admin. The
+// Execution* fields hold what the action returned once it ran.
+type Approval struct {
+	ID                string            `json:"id"`
+	WorkspaceID       string            `json:"workspace_id"`
+	ContextID         string            `json:"context_id"`
+	Connector         string            `json:"connector"`
+	ExternalID        string            `json:"external_id"`
+	RequesterUserID   string            `json:"requester_user_id"`
+	ActionType        string            `json:"action_type"`
+	ActionTarget      string            `json:"action_target"`
+	ActionSummary     string            `json:"action_summary"`
+	Payload           map[string]any    `json:"payload"`
+	Status            string            `json:"status"`
+	ApproverUserID    string            `json:"approver_user_id"`
+	DeniedReason      string            `json:"denied_reason"`
+	RequiredApprovals int               `json:"required_approvals"`
+	Signoffs          []ApprovalSignoff `json:"signoffs"`
+	RiskLevel         string            `json:"risk_level"`
+	RiskReason        string            `json:"risk_reason"`
+	ExecutionStatus   string            `json:"execution_status"`
+	ExecutionMessage  string            `json:"execution_message"`
+	ExecutorPlugin    string            `json:"executor_plugin"`
+	ExecutedAtUnix    int64             `json:"executed_at_unix"`
+	ExpiresAtUnix     int64             `json:"expires_at_unix"`
+	CreatedAtUnix     int64             `json:"created_at_unix"`
+	UpdatedAtUnix     int64             `json:"updated_at_unix"`
+	// Message is set when an approval was recorded but more admins must
+	// still approve before the action runs.
+	Message string `json:"message,omitempty"`
+}
+
+type ListApprovalsResponse struct {
+	Items []Approval `json:"items"`
+	Count int        `json:"count"`
+}
+
+type RunObjectiveResponse struct {
+	ObjectiveID string `json:"objective_id"`
+	TaskID      string `json:"task_id"`
+	Status      string `json:"status"`
+}
+
+// ObjectiveRun is one execution of an objective. FinishedAtUnix is nil
+// while its task runs.
+type ObjectiveRun struct {
 	ID             string `json:"id"`
+	ObjectiveID    string `json:"objective_id"`
 	WorkspaceID    string `json:"workspace_id"`
-	ContextID      string `json:"context_id"`
-	Kind           string `json:"kind"`
-	Title          string `json:"title"`
-	Prompt         string `json:"prompt"`
+	TaskID         string `json:"task_id"`
+	Trigger        string `json:"trigger"`
 	Status         string `json:"status"`
-	Attempts       int    `json:"attempts"`
-	WorkerID       int    `json:"worker_id"`
 	StartedAtUnix  int64  `json:"started_at_unix"`
-	FinishedAtUnix int64  `json:"finished_at_unix"`
-	ResultSummary  string `json:"result_summary"`
-	ResultPath     string `json:"result_path"`
-	ErrorMessage   string `json:"error_message"`
-	CreatedAtUnix  int64  `json:"created_at_unix"`
-	UpdatedAtUnix  int64  `json:"updated_at_unix"`
+	FinishedAtUnix *int64 `json:"finished_at_unix"`
+	DurationMs     int64  `json:"duration_ms"`
+	Summary        string `json:"summary"`
+	Error          string `json:"error"`
 }
 
-type ListTasksResponse struct {
-	Items []Task `json:"items"`
-	Count int    `json:"count"`
+type ListObjectiveRunsResponse struct {
+	ObjectiveID string         `json:"objective_id"`
+	Items       []ObjectiveRun `json:"items"`
+	Count       int            `json:"count"`
+}
+
+// FireEventRequest names an event for the event-triggered objectives. An
+// empty WorkspaceID reaches every workspace.
+type FireEventRequest struct {
+	EventKey    string         `json:"event_key"`
+	WorkspaceID string         `json:"workspace_id,omitempty"`
+	DedupeKey   string         `json:"dedupe_key,omitempty"`
+	Data        map[string]any `json:"data,omitempty"`
+}
+
+// FiredObjective is what an event did to one matching objective.
+type FiredObjective struct {
+	ObjectiveID   string `json:"objective_id"`
+	WorkspaceID   string `json:"workspace_id"`
+	Title         string `json:"title"`
+	TaskID        string `json:"task_id"`
+	AlreadyQueued bool   `json:"already_queued"`
+	Error         string `json:"error,omitempty"`
+}
+
+type FireEventResponse struct {
+	EventKey string           `json:"event_key"`
+	Fired    []FiredObjective `json:"fired"`
+	Count    int              `json:"count"`
 }
 
 type RetryTaskResponse struct {
@@ -120,6 +588,12 @@ type RetryTaskResponse struct {
 	Status      string `json:"status"`
 }
 
+type CancelTaskResponse struct {
+	TaskID     string `json:"task_id"`
+	Status     string `json:"status"`
+	WasRunning bool   `json:"was_running"`
+}
+
 type ChatRequest struct {
 	Connector   string `json:"connector"`
 	ExternalID  string `json:"external_id"`
@@ -165,17 +639,34 @@ func New(cfg config.Config) (*Client, error) {
 		timeout = 120 * time.Second
 	}
 
+	var transport http.RoundTripper = &http.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+	if cfg.AdminActingUser != "" {
+		transport = actingUserTransport{userID: cfg.AdminActingUser, next: transport}
+	}
 	return &Client{
 		baseURL: strings.TrimRight(cfg.AdminAPIURL, "/"),
 		http: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: tlsConfig,
-			},
-			Timeout: timeout,
+			Transport: transport,
+			Timeout:   timeout,
 		},
 	}, nil
 }
 
+// actingUserTransport names the runtime user every request acts for, so the
+// admin API checks it against that user's role.
+type actingUserTransport struct {
+	userID string
+	next   http.RoundTripper
+}
+
+func (t actingUserTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Agent-Runtime-User", t.userID)
+	return t.next.RoundTrip(req)
+}
+
 func (c *Client) WithTimeout(timeout time.Duration) *Client {
 	if c == nil {
 		return nil
@@ -303,10 +794,116 @@ func (c *Client) ListObjectives(ctx context.Context, workspaceID string, activeO
 	return response.Items, nil
 }
 
-func (c *Client) SetObjectiveActive(ctx context.Context, objectiveID string, active bool) (Objective, error) {
+// ListObjectiveRuns returns the latest runs of an objective, newest first.
+func (c *Client) ListObjectiveRuns(ctx context.Context, objectiveID string, limit int) ([]ObjectiveRun, error) {
+	objectiveID = strings.TrimSpace(objectiveID)
+	if objectiveID == "" {
+		return nil, fmt.Errorf("objective id is required")
+	}
+	query := url.Values{}
+	query.Set("id", objectiveID)
+	if limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/objectives/runs?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ListObjectiveRunsResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (c *Client) ListObjectiveTemplates(ctx context.Context) ([]ObjectiveTemplate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/objectives/templates", nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ListObjectiveTemplatesResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// CreateObjective creates an objective from explicit fields.
+func (c *Client) CreateObjective(ctx context.Context, input CreateObjectiveRequest) (Objective, error) {
+	if strings.TrimSpace(input.WorkspaceID) == "" {
+		return Objective{}, fmt.Errorf("workspace id is required")
+	}
+	requestBody, err := json.Marshal(input)
+	if err != nil {
+		return Objective{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/objectives", bytes.NewReader(requestBody))
+	if err != nil {
+		return Objective{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response Objective
+	if err := c.doJSON(req, &response); err != nil {
+		return Objective{}, err
+	}
+	return response, nil
+}
+
+// UpdateObjective edits an objective. A stale Revision returns
+// ErrRevisionConflict.
+func (c *Client) UpdateObjective(ctx context.Context, input UpdateObjectiveRequest) (Objective, error) {
+	input.ID = strings.TrimSpace(input.ID)
+	if input.ID == "" {
+		return Objective{}, fmt.Errorf("objective id is required")
+	}
+	requestBody, err := json.Marshal(input)
+	if err != nil {
+		return Objective{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/objectives/update", bytes.NewReader(requestBody))
+	if err != nil {
+		return Objective{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response Objective
+	if err := c.doJSON(req, &response); err != nil {
+		return Objective{}, err
+	}
+	return response, nil
+}
+
+// CreateObjectiveFromTemplate creates an objective in the workspace context
+// from a built-in template and its parameter values.
+func (c *Client) CreateObjectiveFromTemplate(ctx context.Context, workspaceID, contextID, template string, params map[string]string) (Objective, error) {
+	payload := map[string]any{
+		"workspace_id": strings.TrimSpace(workspaceID),
+		"context_id":   strings.TrimSpace(contextID),
+		"template":     strings.TrimSpace(template),
+		"params":       params,
+	}
+	requestBody, err := json.Marshal(payload)
+	if err != nil {
+		return Objective{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/objectives", bytes.NewReader(requestBody))
+	if err != nil {
+		return Objective{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response Objective
+	if err := c.doJSON(req, &response); err != nil {
+		return Objective{}, err
+	}
+	return response, nil
+}
+
+// SetObjectiveActive pauses or resumes an objective. A non-zero revision
+// makes the server refuse the change if the objective was edited since.
+func (c *Client) SetObjectiveActive(ctx context.Context, objectiveID string, active bool, revision int) (Objective, error) {
 	payload := map[string]any{
-		"id":     strings.TrimSpace(objectiveID),
-		"active": active,
+		"id":       strings.TrimSpace(objectiveID),
+		"active":   active,
+		"revision": revision,
 	}
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
@@ -324,9 +921,54 @@ func (c *Client) SetObjectiveActive(ctx context.Context, objectiveID string, act
 	return response, nil
 }
 
-func (c *Client) DeleteObjective(ctx context.Context, objectiveID string) error {
+// RunObjective queues an immediate run of the objective outside its schedule.
+func (c *Client) RunObjective(ctx context.Context, objectiveID string) (RunObjectiveResponse, error) {
+	objectiveID = strings.TrimSpace(objectiveID)
+	if objectiveID == "" {
+		return RunObjectiveResponse{}, fmt.Errorf("objective id is required")
+	}
+	requestBody, err := json.Marshal(map[string]string{"id": objectiveID})
+	if err != nil {
+		return RunObjectiveResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/objectives/run", bytes.NewReader(requestBody))
+	if err != nil {
+		return RunObjectiveResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response RunObjectiveResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return RunObjectiveResponse{}, err
+	}
+	return response, nil
+}
+
+// FireEvent runs the active objectives listening for an event.
+func (c *Client) FireEvent(ctx context.Context, input FireEventRequest) (FireEventResponse, error) {
+	input.EventKey = strings.TrimSpace(input.EventKey)
+	if input.EventKey == "" {
+		return FireEventResponse{}, fmt.Errorf("event key is required")
+	}
+	requestBody, err := json.Marshal(input)
+	if err != nil {
+		return FireEventResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/events", bytes.NewReader(requestBody))
+	if err != nil {
+		return FireEventResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response FireEventResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return FireEventResponse{}, err
+	}
+	return response, nil
+}
+
+func (c *Client) DeleteObjective(ctx context.Context, objectiveID string, revision int) error {
 	payload := map[string]any{
-		"id": strings.TrimSpace(objectiveID),
+		"id":       strings.TrimSpace(objectiveID),
+		"revision": revision,
 	}
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
@@ -388,49 +1030,719 @@ func (c *Client) RetryTask(ctx context.Context, taskID string) (RetryTaskRespons
 	return response, nil
 }
 
-func (c *Client) Chat(ctx context.Context, input ChatRequest) (ChatResponse, error) {
-	input.Text = strings.TrimSpace(input.Text)
-	if input.Text == "" {
-		return ChatResponse{}, fmt.Errorf("text is required")
+// CancelTask cancels a queued or running task.
+func (c *Client) CancelTask(ctx context.Context, taskID string) (CancelTaskResponse, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return CancelTaskResponse{}, fmt.Errorf("task id is required")
 	}
-	requestBody, err := json.Marshal(input)
+	requestBody, err := json.Marshal(map[string]string{"task_id": taskID})
 	if err != nil {
-		return ChatResponse{}, err
+		return CancelTaskResponse{}, err
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/chat", bytes.NewReader(requestBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/tasks/cancel", bytes.NewReader(requestBody))
 	if err != nil {
-		return ChatResponse{}, err
+		return CancelTaskResponse{}, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	var response ChatResponse
+	var response CancelTaskResponse
 	if err := c.doJSON(req, &response); err != nil {
-		return ChatResponse{}, err
+		return CancelTaskResponse{}, err
 	}
 	return response, nil
 }
 
-func (c *Client) doJSON(req *http.Request, out any) error {
-	res, err := c.http.Do(req)
+// GetTaskResult returns the result markdown of a finished task.
+func (c *Client) GetTaskResult(ctx context.Context, taskID string) (TaskResult, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return TaskResult{}, fmt.Errorf("task id is required")
+	}
+	query := url.Values{}
+	query.Set("id", taskID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/tasks/result?"+query.Encode(), nil)
 	if err != nil {
-		return err
+		return TaskResult{}, err
 	}
-	defer res.Body.Close()
+	var response TaskResult
+	if err := c.doJSON(req, &response); err != nil {
+		return TaskResult{}, err
+	}
+	return response, nil
+}
 
-	if res.StatusCode >= http.StatusBadRequest {
-		var apiError struct {
-			Error string `json:"error"`
-		}
-		_ = json.NewDecoder(res.Body).Decode(&apiError)
-		if strings.TrimSpace(apiError.Error) == "" {
-			apiError.Error = res.Status
-		}
-		return errors.New(apiError.Error)
+// ListTaskArtifacts returns the files a task produced, key ones first.
+func (c *Client) ListTaskArtifacts(ctx context.Context, taskID string) ([]TaskArtifact, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, fmt.Errorf("task id is required")
 	}
-	if out == nil {
-		return nil
+	query := url.Values{}
+	query.Set("task_id", taskID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/tasks/artifacts?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
 	}
-	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+	var response struct {
+		Artifacts []TaskArtifact `json:"artifacts"`
 	}
-	return nil
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Artifacts, nil
+}
+
+// DownloadTaskArtifact copies the content of an artifact to out.
+func (c *Client) DownloadTaskArtifact(ctx context.Context, artifactID string, out io.Writer) error {
+	artifactID = strings.TrimSpace(artifactID)
+	if artifactID == "" {
+		return fmt.Errorf("artifact id is required")
+	}
+	query := url.Values{}
+	query.Set("id", artifactID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/tasks/artifacts/download?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		return responseError(res)
+	}
+	_, err = io.Copy(out, res.Body)
+	return err
+}
+
+// DeleteTask moves a finished task to the trash.
+func (c *Client) DeleteTask(ctx context.Context, taskID string, revision int) error {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return fmt.Errorf("task id is required")
+	}
+	requestBody, err := json.Marshal(map[string]any{"task_id": taskID, "revision": revision})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/tasks/delete", bytes.NewReader(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.doJSON(req, nil)
+}
+
+func (c *Client) GetTaskPlan(ctx context.Context, taskID string) (TaskPlanResponse, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return TaskPlanResponse{}, fmt.Errorf("task id is required")
+	}
+	query := url.Values{}
+	query.Set("id", taskID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/tasks/plan?"+query.Encode(), nil)
+	if err != nil {
+		return TaskPlanResponse{}, err
+	}
+	var response TaskPlanResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return TaskPlanResponse{}, err
+	}
+	return response, nil
+}
+
+// UpdateTaskPlan replaces the steps of a planned task that have not started.
+func (c *Client) UpdateTaskPlan(ctx context.Context, taskID string, steps []string) (TaskPlanResponse, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return TaskPlanResponse{}, fmt.Errorf("task id is required")
+	}
+	requestBody, err := json.Marshal(map[string]any{
+		"id":    taskID,
+		"steps": steps,
+	})
+	if err != nil {
+		return TaskPlanResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/tasks/plan", bytes.NewReader(requestBody))
+	if err != nil {
+		return TaskPlanResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response TaskPlanResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return TaskPlanResponse{}, err
+	}
+	return response, nil
+}
+
+func (c *Client) GetQuotas(ctx context.Context, workspaceID string) (QuotasResponse, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return QuotasResponse{}, fmt.Errorf("workspace id is required")
+	}
+	query := url.Values{}
+	query.Set("workspace_id", workspaceID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/quotas?"+query.Encode(), nil)
+	if err != nil {
+		return QuotasResponse{}, err
+	}
+	var response QuotasResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return QuotasResponse{}, err
+	}
+	return response, nil
+}
+
+// SetQuotas replaces the quota overrides of a workspace.
+func (c *Client) SetQuotas(ctx context.Context, input QuotaOverrides) (QuotasResponse, error) {
+	input.WorkspaceID = strings.TrimSpace(input.WorkspaceID)
+	if input.WorkspaceID == "" {
+		return QuotasResponse{}, fmt.Errorf("workspace id is required")
+	}
+	requestBody, err := json.Marshal(input)
+	if err != nil {
+		return QuotasResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/quotas", bytes.NewReader(requestBody))
+	if err != nil {
+		return QuotasResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response QuotasResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return QuotasResponse{}, err
+	}
+	return response, nil
+}
+
+func (c *Client) ListTrash(ctx context.Context, workspaceID string, limit int) ([]TrashItem, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, fmt.Errorf("workspace id is required")
+	}
+	query := url.Values{}
+	query.Set("workspace_id", workspaceID)
+	if limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/trash?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ListTrashResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// Search runs a full-text query over tasks, objectives, approvals and audit
+// events. An empty workspaceID searches every workspace; kinds narrows the
+// record kinds and may be empty.
+func (c *Client) Search(ctx context.Context, query, workspaceID string, kinds []string, limit int) ([]SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	values := url.Values{}
+	values.Set("q", query)
+	if workspaceID = strings.TrimSpace(workspaceID); workspaceID != "" {
+		values.Set("workspace_id", workspaceID)
+	}
+	if len(kinds) > 0 {
+		values.Set("kind", strings.Join(kinds, ","))
+	}
+	if limit > 0 {
+		values.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/search?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response SearchResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// ListAuditEvents returns one page of audit events, newest first. Pass the
+// response's NextCursor as cursor to read the next page.
+func (c *Client) ListAuditEvents(ctx context.Context, query AuditQuery, cursor string, limit int) (AuditEventsResponse, error) {
+	values := query.values()
+	if cursor = strings.TrimSpace(cursor); cursor != "" {
+		values.Set("cursor", cursor)
+	}
+	if limit > 0 {
+		values.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/audit?"+values.Encode(), nil)
+	if err != nil {
+		return AuditEventsResponse{}, err
+	}
+	var response AuditEventsResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return AuditEventsResponse{}, err
+	}
+	return response, nil
+}
+
+// ExportAuditEvents copies every audit event matching query to out as
+// ndjson or csv.
+func (c *Client) ExportAuditEvents(ctx context.Context, query AuditQuery, format string, out io.Writer) error {
+	values := query.values()
+	if format = strings.TrimSpace(format); format != "" {
+		values.Set("format", format)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/audit/export?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		return responseError(res)
+	}
+	_, err = io.Copy(out, res.Body)
+	return err
+}
+
+// ListCases returns a workspace's cases, open ones first. An empty status
+// lists every case.
+func (c *Client) ListCases(ctx context.Context, workspaceID, status string, limit int) ([]Case, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, fmt.Errorf("workspace id is required")
+	}
+	query := url.Values{}
+	query.Set("workspace_id", workspaceID)
+	if status = strings.TrimSpace(status); status != "" {
+		query.Set("status", status)
+	}
+	if limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/cases?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ListCasesResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// GetCase returns a case with its linked items.
+func (c *Client) GetCase(ctx context.Context, id string) (Case, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Case{}, fmt.Errorf("id is required")
+	}
+	query := url.Values{}
+	query.Set("id", id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/cases/detail?"+query.Encode(), nil)
+	if err != nil {
+		return Case{}, err
+	}
+	var response Case
+	if err := c.doJSON(req, &response); err != nil {
+		return Case{}, err
+	}
+	return response, nil
+}
+
+// UpdateCase sets a case's status ("open" or "resolved") or owner; empty
+// values are left unchanged.
+func (c *Client) UpdateCase(ctx context.Context, id, status, ownerUserID string) (Case, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Case{}, fmt.Errorf("id is required")
+	}
+	requestBody, err := json.Marshal(map[string]string{
+		"id":            id,
+		"status":        strings.TrimSpace(status),
+		"owner_user_id": strings.TrimSpace(ownerUserID),
+	})
+	if err != nil {
+		return Case{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/cases/update", bytes.NewReader(requestBody))
+	if err != nil {
+		return Case{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response Case
+	if err := c.doJSON(req, &response); err != nil {
+		return Case{}, err
+	}
+	return response, nil
+}
+
+// RestoreTrashItem restores a trashed task or objective; kind is "task" or
+// "objective".
+func (c *Client) RestoreTrashItem(ctx context.Context, kind, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("id is required")
+	}
+	requestBody, err := json.Marshal(map[string]string{
+		"kind": strings.TrimSpace(kind),
+		"id":   id,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/trash/restore", bytes.NewReader(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.doJSON(req, nil)
+}
+
+// ReconcileBotfiles reports drift between the applied botfiles and live
+// workspace state, and restores the declared state when fix is set. An
+// empty workspace id checks every workspace with a botfile.
+func (c *Client) ReconcileBotfiles(ctx context.Context, workspaceID string, fix bool) (ReconcileBotfilesResponse, error) {
+	requestBody, err := json.Marshal(map[string]any{
+		"workspace_id": strings.TrimSpace(workspaceID),
+		"fix":          fix,
+	})
+	if err != nil {
+		return ReconcileBotfilesResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/botfile/reconcile", bytes.NewReader(requestBody))
+	if err != nil {
+		return ReconcileBotfilesResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response ReconcileBotfilesResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return ReconcileBotfilesResponse{}, err
+	}
+	return response, nil
+}
+
+// ListApprovals lists action approvals. An empty status lists pending
+// ones; "approved", "denied" and "all" include execution results. An empty
+// workspace id lists every workspace.
+func (c *Client) ListApprovals(ctx context.Context, workspaceID, status string, limit int) ([]Approval, error) {
+	query := url.Values{}
+	if strings.TrimSpace(workspaceID) != "" {
+		query.Set("workspace_id", strings.TrimSpace(workspaceID))
+	}
+	if strings.TrimSpace(status) != "" {
+		query.Set("status", strings.TrimSpace(status))
+	}
+	if limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/approvals?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ListApprovalsResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// GetApproval fetches one approval with its payload and execution result.
+func (c *Client) GetApproval(ctx context.Context, id string) (Approval, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Approval{}, fmt.Errorf("id is required")
+	}
+	query := url.Values{}
+	query.Set("id", id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/approvals/detail?"+query.Encode(), nil)
+	if err != nil {
+		return Approval{}, err
+	}
+	var response Approval
+	if err := c.doJSON(req, &response); err != nil {
+		return Approval{}, err
+	}
+	return response, nil
+}
+
+// ApproveAction approves a pending action and returns it with the
+// execution result. Under the two-person rule the first approval returns
+// the still pending action with Message set.
+func (c *Client) ApproveAction(ctx context.Context, id, approverUserID string) (Approval, error) {
+	return c.decideApproval(ctx, "/api/v1/approvals/approve", id, approverUserID, "")
+}
+
+func (c *Client) DenyAction(ctx context.Context, id, approverUserID, reason string) (Approval, error) {
+	return c.decideApproval(ctx, "/api/v1/approvals/deny", id, approverUserID, reason)
+}
+
+func (c *Client) decideApproval(ctx context.Context, path, id, approverUserID, reason string) (Approval, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Approval{}, fmt.Errorf("id is required")
+	}
+	requestBody, err := json.Marshal(map[string]string{
+		"id":               id,
+		"approver_user_id": strings.TrimSpace(approverUserID),
+		"reason":           strings.TrimSpace(reason),
+	})
+	if err != nil {
+		return Approval{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(requestBody))
+	if err != nil {
+		return Approval{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response Approval
+	if err := c.doJSON(req, &response); err != nil {
+		return Approval{}, err
+	}
+	return response, nil
+}
+
+// ListWebhooks lists the webhook subscriptions of a workspace.
+func (c *Client) ListWebhooks(ctx context.Context, workspaceID string) (WebhooksResponse, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return WebhooksResponse{}, fmt.Errorf("workspace id is required")
+	}
+	query := url.Values{}
+	query.Set("workspace_id", workspaceID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/webhooks?"+query.Encode(), nil)
+	if err != nil {
+		return WebhooksResponse{}, err
+	}
+	var response WebhooksResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return WebhooksResponse{}, err
+	}
+	return response, nil
+}
+
+// SaveWebhook creates or updates a webhook subscription.
+func (c *Client) SaveWebhook(ctx context.Context, input SaveWebhookRequest) (Webhook, error) {
+	input.ID = strings.TrimSpace(input.ID)
+	if input.ID == "" && strings.TrimSpace(input.WorkspaceID) == "" {
+		return Webhook{}, fmt.Errorf("workspace id is required")
+	}
+	requestBody, err := json.Marshal(input)
+	if err != nil {
+		return Webhook{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/webhooks", bytes.NewReader(requestBody))
+	if err != nil {
+		return Webhook{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response Webhook
+	if err := c.doJSON(req, &response); err != nil {
+		return Webhook{}, err
+	}
+	return response, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("id is required")
+	}
+	requestBody, err := json.Marshal(map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/webhooks/delete", bytes.NewReader(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.doJSON(req, nil)
+}
+
+// ListWorkspaces lists the workspaces with their task and objective counts.
+func (c *Client) ListWorkspaces(ctx context.Context) ([]Workspace, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/workspaces", nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ListWorkspacesResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// ChangeStream reads the change events of an open change stream.
+type ChangeStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+}
+
+// OpenChangeStream subscribes to the changes of a workspace, or of every
+// workspace when workspaceID is empty. The stream is not bound by the
+// client timeout; it lasts until ctx is done, Close is called or the
+// server goes away.
+func (c *Client) OpenChangeStream(ctx context.Context, workspaceID string) (*ChangeStream, error) {
+	endpoint := c.baseURL + "/api/v1/changes/stream"
+	if workspaceID = strings.TrimSpace(workspaceID); workspaceID != "" {
+		endpoint += "?workspace_id=" + url.QueryEscape(workspaceID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	streaming := http.Client{}
+	if c.http != nil {
+		streaming = *c.http
+	}
+	streaming.Timeout = 0
+	res, err := streaming.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+		return nil, responseError(res)
+	}
+	return &ChangeStream{body: res.Body, scanner: bufio.NewScanner(res.Body)}, nil
+}
+
+// Next blocks until the next change. It returns io.EOF once the server
+// closes the stream.
+func (s *ChangeStream) Next() (Change, error) {
+	event := ""
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		switch {
+		case line == "":
+			event = ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:") && event == "change":
+			var change Change
+			if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &change); err != nil {
+				return Change{}, fmt.Errorf("decode change: %w", err)
+			}
+			return change, nil
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return Change{}, err
+	}
+	return Change{}, io.EOF
+}
+
+func (s *ChangeStream) Close() error {
+	return s.body.Close()
+}
+
+// ListChatLogs lists the live chat logs of a workspace, most recently
+// written first.
+func (c *Client) ListChatLogs(ctx context.Context, workspaceID string) ([]ChatLog, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, fmt.Errorf("workspace id is required")
+	}
+	query := url.Values{}
+	query.Set("workspace_id", workspaceID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/chatlogs?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ListChatLogsResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// TailChatLog returns the newest entries of a chat log, oldest first.
+func (c *Client) TailChatLog(ctx context.Context, workspaceID, connector, externalID string, limit int) ([]ChatLogEntry, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	connector = strings.TrimSpace(connector)
+	externalID = strings.TrimSpace(externalID)
+	if workspaceID == "" || connector == "" || externalID == "" {
+		return nil, fmt.Errorf("workspace id, connector and external id are required")
+	}
+	query := url.Values{}
+	query.Set("workspace_id", workspaceID)
+	query.Set("connector", connector)
+	query.Set("external_id", externalID)
+	if limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/chatlogs/tail?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ChatLogTailResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (c *Client) Chat(ctx context.Context, input ChatRequest) (ChatResponse, error) {
+	input.Text = strings.TrimSpace(input.Text)
+	if input.Text == "" {
+		return ChatResponse{}, fmt.Errorf("text is required")
+	}
+	requestBody, err := json.Marshal(input)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/chat", bytes.NewReader(requestBody))
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response ChatResponse
+	if err := c.doJSON(req, &response); err != nil {
+		return ChatResponse{}, err
+	}
+	return response, nil
+}
+
+func (c *Client) doJSON(req *http.Request, out any) error {
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return responseError(res)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// responseError turns an error response into an error carrying the API's
+// message.
+func responseError(res *http.Response) error {
+	var apiError struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(res.Body).Decode(&apiError)
+	if strings.TrimSpace(apiError.Error) == "" {
+		apiError.Error = res.Status
+	}
+	if res.StatusCode == http.StatusConflict && strings.HasPrefix(apiError.Error, ErrRevisionConflict.Error()) {
+		return fmt.Errorf("%w%s", ErrRevisionConflict, strings.TrimPrefix(apiError.Error, ErrRevisionConflict.Error()))
+	}
+	return errors.New(apiError.Error)
 }
@@ -3,6 +3,8 @@ package adminclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -96,3 +98,215 @@ func TestNewRespectsAdminHTTPTimeoutConfig(t *testing.T) {
 		t.Fatalf("expected timeout 42s, got %s", client.http.Timeout)
 	}
 }
+
+func TestNewSendsTheConfiguredActingUser(t *testing.T) {
+	t.Parallel()
+
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Agent-Runtime-User")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[],"count":0}`))
+	}))
+	defer server.Close()
+
+	client, err := New(config.Config{AdminAPIURL: server.URL, AdminActingUser: "user-7"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := client.LookupPairing(context.Background(), "ABCDEFGH"); err != nil && got == "" {
+		t.Fatalf("lookup pairing: %v", err)
+	}
+	if got != "user-7" {
+		t.Fatalf("expected the acting user header, got %q", got)
+	}
+}
+
+func TestClientDeleteObjectiveReportsRevisionConflict(t *testing.T) {
+	t.Parallel()
+
+	var got struct {
+		ID       string `json:"id"`
+		Revision int    `json:"revision"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":"revision conflict: record changed since it was read"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, http: server.Client()}
+	err := client.DeleteObjective(context.Background(), "obj-1", 3)
+	if !errors.Is(err, ErrRevisionConflict) {
+		t.Fatalf("expected revision conflict, got %v", err)
+	}
+	if err.Error() != "revision conflict: record changed since it was read" {
+		t.Fatalf("unexpected error text %q", err.Error())
+	}
+	if got.ID != "obj-1" || got.Revision != 3 {
+		t.Fatalf("unexpected request payload: %+v", got)
+	}
+}
+
+func TestClientSearchSendsFilters(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/search" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("q") != "webhooks" || query.Get("kind") != "task,objective" || query.Get("workspace_id") != "" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[{"kind":"task","id":"task-1","workspace_id":"ws-1","title":"Wire up webhooks","snippet":"Wire up [webhooks]","status":"queued"}],"count":1}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, http: server.Client()}
+	results, err := client.Search(context.Background(), " webhooks ", "", []string{"task", "objective"}, 0)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "task-1" || results[0].Snippet != "Wire up [webhooks]" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestClientReconcileBotfilesSendsFix(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/botfile/reconcile" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var got struct {
+			WorkspaceID string `json:"workspace_id"`
+			Fix         bool   `json:"fix"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if got.WorkspaceID != "ws-1" || !got.Fix {
+			t.Fatalf("unexpected request payload: %+v", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[{"workspace_id":"ws-1","drift":["objectives: missing nightly-digest"],"fixed":true,"checked_at_unix":1760000000}],"count":1,"drifted":0}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, http: server.Client()}
+	response, err := client.ReconcileBotfiles(context.Background(), " ws-1 ", true)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(response.Items) != 1 || !response.Items[0].Fixed || response.Drifted != 0 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+}
+
+func TestClientApproveActionReturnsExecutionResult(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/approvals/approve" || r.Method != http.MethodPost {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		if payload["id"] != "act-1" || payload["approver_user_id"] != "admin-1" {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"act-1","status":"approved","execution_status":"succeeded","execution_message":"status 200","executor_plugin":"webhook","signoffs":[{"user_id":"admin-1","approved_at_unix":1760693700}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, http: server.Client()}
+	approval, err := client.ApproveAction(context.Background(), " act-1 ", "admin-1")
+	if err != nil {
+		t.Fatalf("approve action: %v", err)
+	}
+	if approval.ExecutionStatus != "succeeded" || approval.ExecutorPlugin != "webhook" || len(approval.Signoffs) != 1 {
+		t.Fatalf("unexpected approval: %+v", approval)
+	}
+}
+
+func TestClientUpdateObjectiveSendsClearedFields(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/objectives/update" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"obj-1","trigger_type":"event","event_key":"deploy.finished","revision":4}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, http: server.Client()}
+	trigger, eventKey, cronExpr := "event", "deploy.finished", ""
+	item, err := client.UpdateObjective(context.Background(), UpdateObjectiveRequest{
+		ID:          "obj-1",
+		TriggerType: &trigger,
+		EventKey:    &eventKey,
+		CronExpr:    &cronExpr,
+		Revision:    3,
+	})
+	if err != nil {
+		t.Fatalf("update objective: %v", err)
+	}
+	if item.Revision != 4 || item.EventKey != "deploy.finished" {
+		t.Fatalf("unexpected objective %+v", item)
+	}
+	if value, ok := got["cron_expr"]; !ok || value != "" {
+		t.Fatalf("expected cron_expr sent empty to clear it, got %+v", got)
+	}
+	if _, ok := got["title"]; ok {
+		t.Fatalf("expected unset title omitted, got %+v", got)
+	}
+	if got["revision"] != float64(3) {
+		t.Fatalf("expected revision 3, got %+v", got)
+	}
+}
+
+func TestClientChangeStreamReadsChangeEvents(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/changes/stream" || r.URL.Query().Get("workspace_id") != "ws-1" {
+			t.Fatalf("unexpected request %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("retry: 3000\n\n: ping\n\nevent: change\ndata: {\"kind\":\"task\",\"workspace_id\":\"ws-1\",\"version\":7}\n\n"))
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, http: server.Client()}
+	stream, err := client.OpenChangeStream(context.Background(), " ws-1 ")
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer stream.Close()
+	change, err := stream.Next()
+	if err != nil {
+		t.Fatalf("next change: %v", err)
+	}
+	if change != (Change{Kind: "task", WorkspaceID: "ws-1", Version: 7}) {
+		t.Fatalf("unexpected change %+v", change)
+	}
+	if _, err := stream.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF once the stream closes, got %v", err)
+	}
+}
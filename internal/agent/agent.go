@@ -14,7 +14,9 @@ import (
 
 	"github.com/dwizi/agent-runtime/internal/agent/tools"
 	"github.com/dwizi/agent-runtime/internal/agenterr"
+	"github.com/dwizi/agent-runtime/internal/i18n"
 	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/secretscan"
 )
 
 // Agent coordinates the "Think-Act" loop.
@@ -27,6 +29,7 @@ type Agent struct {
 	policyResolver  PolicyResolver
 	groundFirstStep bool
 	groundEveryStep bool
+	secretScanMode  secretscan.Mode
 
 	quotaMu    sync.Mutex
 	taskEvents map[string][]time.Time
@@ -34,7 +37,11 @@ type Agent struct {
 
 type contextKey string
 
-const sensitiveToolApprovalKey contextKey = "agent_sensitive_tool_approval"
+const (
+	sensitiveToolApprovalKey contextKey = "agent_sensitive_tool_approval"
+	toolApprovalScopesKey    contextKey = "agent_tool_approval_scopes"
+	explainModeKey           contextKey = "agent_explain_mode"
+)
 
 // New creates a new Agent.
 func New(logger *slog.Logger, responder llm.Responder, registry *tools.Registry, systemPrompt string) *Agent {
@@ -85,6 +92,11 @@ type ToolCall struct {
 	Status     string
 	ToolOutput string
 	Error      string
+	Reason     string
+	// DataKind and Data carry the JSON payload of tools that return
+	// structured results; both are empty for plain-text tools.
+	DataKind string
+	Data     json.RawMessage
 }
 
 // WithSensitiveToolApproval marks the context as approved for sensitive tool execution.
@@ -95,11 +107,46 @@ func WithSensitiveToolApproval(ctx context.Context) context.Context {
 	return context.WithValue(ctx, sensitiveToolApprovalKey, true)
 }
 
+// WithToolApprovalScopes approves sensitive tools whose name or class is
+// one of scopes, leaving every other sensitive tool blocked.
+func WithToolApprovalScopes(ctx context.Context, scopes ...string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cleaned := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if scope = strings.ToLower(strings.TrimSpace(scope)); scope != "" {
+			cleaned = append(cleaned, scope)
+		}
+	}
+	if len(cleaned) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, toolApprovalScopesKey, cleaned)
+}
+
 // HasSensitiveToolApproval reports whether the context contains a sensitive-tool approval token.
 func HasSensitiveToolApproval(ctx context.Context) bool {
 	return hasSensitiveToolApproval(ctx)
 }
 
+// WithExplainMode marks the context so the agent plans tool calls without executing them.
+func WithExplainMode(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, explainModeKey, true)
+}
+
+// IsExplainMode reports whether the context requests a no-execute preview turn.
+func IsExplainMode(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, ok := ctx.Value(explainModeKey).(bool)
+	return ok && enabled
+}
+
 func (a *Agent) SetDefaultPolicy(policy Policy) {
 	a.defaultPolicy = mergePolicy(defaultPolicy(), policy)
 }
@@ -114,6 +161,12 @@ func (a *Agent) SetGroundingPolicy(firstStep, everyStep bool) {
 	a.groundEveryStep = everyStep
 }
 
+// SetSecretScanMode decides whether credentials in tool output are masked or
+// withheld before the model sees them. The default masks.
+func (a *Agent) SetSecretScanMode(mode secretscan.Mode) {
+	a.secretScanMode = mode
+}
+
 type loopToolStep struct {
 	ToolName   string
 	ToolArgs   string
@@ -126,6 +179,7 @@ type parsedDecision struct {
 	IsTool        bool
 	ToolName      string
 	ToolArgs      json.RawMessage
+	ToolReason    string
 	FinalReply    string
 	HasConfidence bool
 	Confidence    float64
@@ -145,7 +199,11 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 
 	policy := a.resolvePolicy(ctx, input)
 	result.Policy = policy
+	explainMode := IsExplainMode(ctx)
 	appendTrace("start", "agent turn started")
+	if explainMode {
+		appendTrace("explain.start", "explain mode enabled; tools will not be executed")
+	}
 
 	if policy.MaxTurnDuration > 0 {
 		timeoutCtx, cancel := context.WithTimeout(ctx, policy.MaxTurnDuration)
@@ -165,7 +223,7 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 	// 1. Construct Prompt with Tools
 	toolDesc := "No tools registered."
 	if a.registry != nil {
-		toolDesc = a.registry.DescribeAll()
+		toolDesc = a.registry.DescribeAllExcept(policy.DeniedTools)
 	}
 
 	// We assume a.prompt contains instructions and a placeholder for tools.
@@ -190,7 +248,45 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 	toolSteps := make([]loopToolStep, 0, maxSteps)
 	failedSignatures := map[string]int{}
 	queuedApprovalSignatures := map[string]string{}
-	for step := 1; step <= maxSteps; step++ {
+	simulatedSignatures := map[string]struct{}{}
+	startStep := 1
+	checkpointer := checkpointerFromContext(ctx)
+	if explainMode {
+		checkpointer = nil
+	}
+	if checkpointer != nil {
+		checkpoint, found, err := checkpointer.LoadCheckpoint(ctx)
+		if err != nil {
+			appendTrace("checkpoint.error", err.Error())
+		} else if found && checkpoint.Step > 0 {
+			result.ToolCalls = checkpoint.ToolCalls
+			toolCalls = checkpoint.ToolCallCount
+			toolSteps, failedSignatures = restoreToolSteps(checkpoint.ToolCalls)
+			a.fenceRestoredSteps(toolSteps)
+			result.ActionTaken = toolCalls > 0
+			startStep = checkpoint.Step + 1
+			appendTrace("checkpoint.resume", fmt.Sprintf("resuming after step %d with %d tool calls", checkpoint.Step, len(checkpoint.ToolCalls)))
+		}
+	}
+	for step := startStep; step <= maxSteps; step++ {
+		if err := ctx.Err(); err != nil {
+			// The task was cancelled or the turn ran out of time while a
+			// tool ran; stop before asking the model again.
+			appendTrace("turn.stopped", err.Error())
+			result.Error = fmt.Errorf("agent turn stopped: %w", err)
+			return result
+		}
+		if checkpointer != nil && step > startStep {
+			// Everything up to the previous step is final; save it before the
+			// next model call.
+			if err := checkpointer.SaveCheckpoint(ctx, Checkpoint{
+				Step:          step - 1,
+				ToolCallCount: toolCalls,
+				ToolCalls:     result.ToolCalls,
+			}); err != nil {
+				appendTrace("checkpoint.error", err.Error())
+			}
+		}
 		result.Steps = step
 		llmInput := input
 		llmInput.SystemPrompt = fullPrompt
@@ -204,6 +300,9 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 		}
 		llmInput.SkipGrounding = !shouldGround
 		llmInput.Text = buildLoopInput(input.Text, toolSteps, step, maxSteps)
+		if explainMode {
+			llmInput.Text += "\n\n" + explainModeInstruction
+		}
 
 		response, err := a.llm.Reply(ctx, llmInput)
 		if err != nil {
@@ -219,7 +318,7 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 				result.Confidence = decision.Confidence
 				appendTrace("decision.confidence", fmt.Sprintf("model confidence=%.2f", decision.Confidence))
 			}
-			if policy.MinFinalConfidence > 0 && decision.HasConfidence && decision.Confidence < policy.MinFinalConfidence {
+			if !explainMode && policy.MinFinalConfidence > 0 && decision.HasConfidence && decision.Confidence < policy.MinFinalConfidence {
 				result.Blocked = true
 				result.BlockReason = fmt.Sprintf("model confidence %.2f below threshold %.2f", decision.Confidence, policy.MinFinalConfidence)
 				result.Reply = "I need a human review before taking action on this."
@@ -246,6 +345,7 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 			ToolName: strings.TrimSpace(toolName),
 			ToolArgs: compactLoopText(string(toolArgs), 800),
 			Status:   "selected",
+			Reason:   compactLoopText(decision.ToolReason, 400),
 		})
 
 		if policy.MaxToolCallsPerTurn > 0 && toolCalls+1 > policy.MaxToolCallsPerTurn {
@@ -302,7 +402,37 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 			appendTrace("policy.blocked", result.BlockReason)
 			return result
 		}
-		if requiresApproval && !hasSensitiveToolApproval(ctx) {
+		if explainMode {
+			if _, seen := simulatedSignatures[toolSig]; seen {
+				reason := "tool call was already previewed with the same args; finish the explanation"
+				appendTrace("explain.repeat", reason)
+				result.ToolCalls[toolCallIndex].Status = "blocked"
+				result.ToolCalls[toolCallIndex].Error = reason
+				toolSteps = append(toolSteps, loopToolStep{
+					ToolName:   toolName,
+					ToolArgs:   compactLoopText(string(toolArgs), 500),
+					ToolStatus: "blocked",
+					ToolError:  reason,
+				})
+				continue
+			}
+			simulatedSignatures[toolSig] = struct{}{}
+			note := "not executed (explain mode); assume it succeeds and plan the next step"
+			if requiresApproval && !toolApproved(ctx, toolName, toolClass) {
+				note = "not executed (explain mode); this tool would require admin approval before running"
+			}
+			result.ToolCalls[toolCallIndex].Status = "simulated"
+			result.ToolCalls[toolCallIndex].ToolOutput = note
+			appendTrace("explain.tool", fmt.Sprintf("simulated tool %s class=%s approval_required=%t", toolName, toolClass, requiresApproval))
+			toolSteps = append(toolSteps, loopToolStep{
+				ToolName:   toolName,
+				ToolArgs:   compactLoopText(string(toolArgs), 500),
+				ToolStatus: "simulated",
+				ToolOutput: note,
+			})
+			continue
+		}
+		if requiresApproval && !toolApproved(ctx, toolName, toolClass) {
 			result.Blocked = true
 			result.BlockReason = fmt.Sprintf("tool %s requires approval", toolName)
 			result.Reply = "I need explicit approval before running that sensitive action."
@@ -357,7 +487,12 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 			continue
 		}
 
-		output, err := a.registry.ExecuteTool(ctx, toolName, toolArgs)
+		toolCtx := ctx
+		if !hasSensitiveToolApproval(ctx) && toolApproved(ctx, toolName, toolClass) {
+			toolCtx = WithSensitiveToolApproval(ctx)
+		}
+		structured, err := a.registry.ExecuteToolStructured(toolCtx, toolName, toolArgs)
+		output := structured.Summary
 		toolCalls++
 		result.ActionTaken = true
 		result.ToolName = toolName
@@ -386,20 +521,38 @@ func (a *Agent) Execute(ctx context.Context, input llm.MessageInput) Result {
 			continue
 		}
 
+		if scanned, kinds := a.scanSecrets(output); len(kinds) > 0 {
+			appendTrace("audit.secret_detected", fmt.Sprintf("tool=%s class=%s kinds=%s action=%s connector=%s workspace=%s context=%s external=%s user=%s", toolName, toolClass, strings.Join(kinds, ","), a.secretAction(), strings.TrimSpace(input.Connector), strings.TrimSpace(input.WorkspaceID), strings.TrimSpace(input.ContextID), strings.TrimSpace(input.ExternalID), strings.TrimSpace(input.FromUserID)))
+			output = scanned
+			// Structured data carries the raw values; the summary stands in.
+			structured.Kind, structured.Data = "", nil
+		}
+		loopOutput := compactLoopText(output, 1000)
+		if untrustedTool(toolDef) {
+			sanitized, patterns := sanitizeUntrusted(output)
+			if len(patterns) > 0 {
+				appendTrace("audit.prompt_injection_suspected", fmt.Sprintf("tool=%s class=%s patterns=%s connector=%s workspace=%s context=%s external=%s user=%s", toolName, toolClass, strings.Join(patterns, ","), strings.TrimSpace(input.Connector), strings.TrimSpace(input.WorkspaceID), strings.TrimSpace(input.ContextID), strings.TrimSpace(input.ExternalID), strings.TrimSpace(input.FromUserID)))
+			}
+			output = sanitized
+			loopOutput = fenceUntrusted(toolName, compactLoopText(sanitized, 1000))
+		}
+
 		result.ToolOutput = output
 		result.ToolCalls[toolCallIndex].Status = "succeeded"
 		result.ToolCalls[toolCallIndex].ToolOutput = compactLoopText(output, 1200)
+		result.ToolCalls[toolCallIndex].DataKind = structured.Kind
+		result.ToolCalls[toolCallIndex].Data = structured.Data
 		appendTrace("tool.ok", fmt.Sprintf("tool %s executed successfully", toolName))
 
 		toolSteps = append(toolSteps, loopToolStep{
 			ToolName:   toolName,
 			ToolArgs:   compactLoopText(string(toolArgs), 500),
 			ToolStatus: "succeeded",
-			ToolOutput: compactLoopText(output, 1000),
+			ToolOutput: loopOutput,
 		})
 		if queuedActionID, pendingApproval := extractPendingApprovalActionID(output); pendingApproval {
 			queuedApprovalSignatures[toolSig] = strings.TrimSpace(queuedActionID)
-			result.Reply = buildPendingApprovalReply(strings.TrimSpace(queuedActionID))
+			result.Reply = buildPendingApprovalReply(ctx, strings.TrimSpace(queuedActionID))
 			appendTrace("decision.reply", "tool queued pending approval; ending turn with approval guidance")
 			return result
 		}
@@ -445,6 +598,10 @@ func buildLoopInput(userText string, toolSteps []loopToolStep, step, maxSteps in
 	return builder.String()
 }
 
+const explainModeInstruction = "EXPLAIN MODE: tools are simulated and never executed. " +
+	"Include a short \"reason\" field with every tool call explaining why you chose it. " +
+	"When the plan is complete, return a final answer describing what you would do."
+
 func (a *Agent) parseDecision(response string) parsedDecision {
 	// 1. Try to find a JSON object in the response
 	jsonStr := findFirstJSON(response)
@@ -474,6 +631,7 @@ func (a *Agent) parseDecision(response string) parsedDecision {
 		if args, ok := envelope["args"]; ok && len(strings.TrimSpace(string(args))) > 0 {
 			decision.ToolArgs = args
 		}
+		decision.ToolReason = firstStringField(envelope, "reason", "why", "thought")
 		return decision
 	}
 
@@ -750,11 +908,13 @@ func extractActionID(input string) (string, bool) {
 	return strings.TrimSpace(lower[start:end]), true
 }
 
-func buildPendingApprovalReply(actionID string) string {
+// buildPendingApprovalReply answers in the reply language of the channel
+// the turn came from.
+func buildPendingApprovalReply(ctx context.Context, actionID string) string {
 	if strings.TrimSpace(actionID) == "" {
-		return "I queued the action and it now needs admin approval. Run `/pending-actions`, then `/approve-action <action-id>` to continue."
+		return i18n.T(ctx, i18n.ApprovalQueuedNoID)
 	}
-	return fmt.Sprintf("I queued this action and it now needs admin approval.\n\nNext:\n1) Run `/approve-action %s`\n2) Or run `/pending-actions` to review approvals first.", actionID)
+	return i18n.T(ctx, i18n.ApprovalQueued, actionID)
 }
 
 func (a *Agent) resolvePolicy(ctx context.Context, input llm.MessageInput) Policy {
@@ -767,6 +927,11 @@ func (a *Agent) resolvePolicy(ctx context.Context, input llm.MessageInput) Polic
 }
 
 func isToolAllowed(policy Policy, toolName string) bool {
+	for _, denied := range policy.DeniedTools {
+		if strings.EqualFold(strings.TrimSpace(denied), strings.TrimSpace(toolName)) {
+			return false
+		}
+	}
 	if len(policy.AllowedTools) == 0 {
 		return true
 	}
@@ -813,6 +978,26 @@ func hasSensitiveToolApproval(ctx context.Context) bool {
 	return ok && granted
 }
 
+// toolApproved reports whether the context approves this tool, either
+// outright or through a scope naming the tool or its class.
+func toolApproved(ctx context.Context, toolName, toolClass string) bool {
+	if hasSensitiveToolApproval(ctx) {
+		return true
+	}
+	if ctx == nil {
+		return false
+	}
+	scopes, _ := ctx.Value(toolApprovalScopesKey).([]string)
+	name := strings.ToLower(strings.TrimSpace(toolName))
+	class := strings.ToLower(strings.TrimSpace(toolClass))
+	for _, scope := range scopes {
+		if scope == name || scope == class {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *Agent) allowAutonomousTask(input llm.MessageInput, policy Policy, now time.Time) (bool, string) {
 	if policy.MaxAutonomousTasksPerHour <= 0 && policy.MaxAutonomousTasksPerDay <= 0 {
 		return true, ""
@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const checkpointerKey contextKey = "agent_checkpointer"
+
+// Checkpoint is the intermediate state of an agent turn: the tool calls made
+// so far and the last finished loop step. It is enough to rebuild the work
+// log the model sees, so a resumed turn continues instead of starting over.
+type Checkpoint struct {
+	Step          int        `json:"step"`
+	ToolCallCount int        `json:"tool_call_count"`
+	ToolCalls     []ToolCall `json:"tool_calls"`
+}
+
+// Checkpointer persists turn state between loop steps. Implementations are
+// scoped to one unit of work, e.g. a background task.
+type Checkpointer interface {
+	LoadCheckpoint(ctx context.Context) (Checkpoint, bool, error)
+	SaveCheckpoint(ctx context.Context, checkpoint Checkpoint) error
+}
+
+// WithCheckpointer attaches a checkpointer to the context. The agent saves a
+// checkpoint after every loop step and resumes from a stored one on start.
+// A tool that finished right before a crash may run again on resume.
+func WithCheckpointer(ctx context.Context, checkpointer Checkpointer) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, checkpointerKey, checkpointer)
+}
+
+func checkpointerFromContext(ctx context.Context) Checkpointer {
+	if ctx == nil {
+		return nil
+	}
+	checkpointer, _ := ctx.Value(checkpointerKey).(Checkpointer)
+	return checkpointer
+}
+
+// restoreToolSteps rebuilds the loop work log from checkpointed tool calls.
+func restoreToolSteps(calls []ToolCall) ([]loopToolStep, map[string]int) {
+	steps := make([]loopToolStep, 0, len(calls))
+	failed := map[string]int{}
+	for _, call := range calls {
+		step := loopToolStep{
+			ToolName:   call.ToolName,
+			ToolArgs:   compactLoopText(call.ToolArgs, 500),
+			ToolStatus: call.Status,
+			ToolOutput: compactLoopText(call.ToolOutput, 1000),
+			ToolError:  call.Error,
+		}
+		steps = append(steps, step)
+		if call.Status == "failed" {
+			failed[loopToolSignature(call.ToolName, json.RawMessage(call.ToolArgs))]++
+		}
+	}
+	return steps, failed
+}
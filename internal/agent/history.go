@@ -4,9 +4,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/memorylog"
 )
 
 // GetRecentHistory retrieves the last N lines from the chat log for context.
+// Once the chat has been compacted, the latest rolling summary section is
+// put ahead of them so older turns are not lost. For a thread, externalID is
+// its memorylog.ConversationID so the window only holds that thread.
 func GetRecentHistory(workspaceRoot, workspaceID, connector, externalID string, maxLines int) string {
 	if workspaceRoot == "" || workspaceID == "" || connector == "" || externalID == "" {
 		return ""
@@ -15,9 +20,10 @@ func GetRecentHistory(workspaceRoot, workspaceID, connector, externalID string,
 		maxLines = 12
 	}
 
+	summary := memorylog.ReadSummary(workspaceRoot, workspaceID, connector, externalID, 1)
 	path := filepath.Join(workspaceRoot, workspaceID, "logs", "chats", strings.ToLower(connector), externalID+".md")
 	data, err := os.ReadFile(path)
-	if err != nil {
+	if err != nil && summary == "" {
 		return ""
 	}
 
@@ -26,6 +32,10 @@ func GetRecentHistory(workspaceRoot, workspaceID, connector, externalID string,
 		lines = lines[len(lines)-maxLines:]
 	}
 	lines = fitConversationBytes(lines, 2400)
+	if summary != "" {
+		earlier := fitConversationBytes(strings.Split(summary, "\n"), 800)
+		lines = append(append([]string{"earlier conversation (summary):"}, earlier...), lines...)
+	}
 	return strings.Join(lines, "\n")
 }
 
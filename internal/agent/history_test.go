@@ -74,3 +74,28 @@ func TestGetRecentHistoryRespectsLineLimit(t *testing.T) {
 		t.Fatalf("expected last two lines, got %q", got)
 	}
 }
+
+func TestGetRecentHistoryPrependsCompactedSummary(t *testing.T) {
+	root := t.TempDir()
+	logPath := filepath.Join(root, "ws-1", "logs", "chats", "telegram", "42.md")
+	summaryPath := filepath.Join(root, "ws-1", "memory", "chats", "telegram", "42.md")
+	for _, path := range []string{logPath, summaryPath} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+	}
+	if err := os.WriteFile(logPath, []byte("# Chat Log\n## 2026-02-16T10:00:00Z `INBOUND`\n- direction: `inbound`\nwhat region was staging again?\n"), 0o644); err != nil {
+		t.Fatalf("write log failed: %v", err)
+	}
+	summary := "# Chat Memory\n\n- connector: `telegram`\n- external_id: `42`\n\n## 2026-02-01 to 2026-02-03 (12 entries)\n- user: staging moved to eu-west-2\n"
+	if err := os.WriteFile(summaryPath, []byte(summary), 0o644); err != nil {
+		t.Fatalf("write summary failed: %v", err)
+	}
+
+	got := GetRecentHistory(root, "ws-1", "telegram", "42", 10)
+	summaryAt := strings.Index(got, "staging moved to eu-west-2")
+	liveAt := strings.Index(got, "user: what region was staging again?")
+	if summaryAt < 0 || liveAt < 0 || summaryAt > liveAt {
+		t.Fatalf("expected compacted summary ahead of live turns, got %q", got)
+	}
+}
@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+)
+
+// ErrEmptyPlan is returned when the model does not produce any plan steps.
+var ErrEmptyPlan = errors.New("planner returned no steps")
+
+const planInstruction = "PLANNING PHASE: do not call tools and do not answer the request yet.\n" +
+	"Break the request into at most %d concrete steps that can each be completed in one autonomous turn with the available tools.\n" +
+	"Each step must be one imperative sentence; the last step should produce the final answer.\n" +
+	"Respond with JSON only: {\"steps\": [\"...\", \"...\"]}"
+
+// Plan asks the model for an ordered step plan for input without executing
+// any tools. Plans longer than maxSteps are truncated.
+func (a *Agent) Plan(ctx context.Context, input llm.MessageInput, maxSteps int) ([]string, error) {
+	if maxSteps < 1 {
+		maxSteps = 1
+	}
+	toolDesc := "No tools registered."
+	if a.registry != nil {
+		toolDesc = a.registry.DescribeAll()
+	}
+	prompt := fmt.Sprintf("CURRENT TIME (UTC): %s\n\n%s\n\nAVAILABLE TOOLS:\n%s",
+		time.Now().UTC().Format(time.RFC1123),
+		strings.ReplaceAll(a.prompt, "%s", ""),
+		toolDesc,
+	)
+
+	llmInput := input
+	llmInput.SystemPrompt = prompt
+	llmInput.Text = "USER REQUEST:\n" + strings.TrimSpace(input.Text) + "\n\n" + fmt.Sprintf(planInstruction, maxSteps)
+	response, err := a.llm.Reply(ctx, llmInput)
+	if err != nil {
+		return nil, fmt.Errorf("llm error: %w", err)
+	}
+	steps := parsePlanSteps(response)
+	if len(steps) == 0 {
+		return nil, ErrEmptyPlan
+	}
+	if len(steps) > maxSteps {
+		steps = steps[:maxSteps]
+	}
+	a.logger.Info("agent_trace", "stage", "plan.ready", "message", fmt.Sprintf("planned %d steps", len(steps)))
+	return steps, nil
+}
+
+// parsePlanSteps reads {"steps": [...]} and falls back to a numbered or
+// bulleted list for models that ignore the JSON instruction.
+func parsePlanSteps(response string) []string {
+	if jsonStr := findFirstJSON(response); jsonStr != "" {
+		var envelope struct {
+			Steps []json.RawMessage `json:"steps"`
+		}
+		if err := json.Unmarshal([]byte(jsonStr), &envelope); err == nil && len(envelope.Steps) > 0 {
+			steps := make([]string, 0, len(envelope.Steps))
+			for _, raw := range envelope.Steps {
+				var text string
+				if err := json.Unmarshal(raw, &text); err != nil {
+					var object map[string]json.RawMessage
+					if err := json.Unmarshal(raw, &object); err != nil {
+						continue
+					}
+					text = firstStringField(object, "description", "step", "title")
+				}
+				if text = strings.TrimSpace(text); text != "" {
+					steps = append(steps, text)
+				}
+			}
+			return steps
+		}
+	}
+	steps := []string{}
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		trimmed := strings.TrimLeft(line, "0123456789")
+		switch {
+		case trimmed != line && (strings.HasPrefix(trimmed, ".") || strings.HasPrefix(trimmed, ")")):
+			trimmed = trimmed[1:]
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			trimmed = line[2:]
+		default:
+			continue
+		}
+		if trimmed = strings.TrimSpace(trimmed); trimmed != "" {
+			steps = append(steps, trimmed)
+		}
+	}
+	return steps
+}
@@ -20,6 +20,9 @@ type Policy struct {
 	MaxToolCallsPerTurn int
 	// AllowedTools restricts which tools can be executed. Empty means all registered tools.
 	AllowedTools []string
+	// DeniedTools hides tools from this turn even when they are allowed, for
+	// example a tool that a canary rollout only enables for some contexts.
+	DeniedTools []string
 	// AllowedToolClasses restricts tool classes that can be executed. Empty means all classes.
 	AllowedToolClasses []string
 	// MaxAutonomousTasksPerHour limits create_task tool invocations per context key per hour.
@@ -63,6 +66,9 @@ func mergePolicy(base, override Policy) Policy {
 	if len(override.AllowedTools) > 0 {
 		policy.AllowedTools = cleanToolList(override.AllowedTools)
 	}
+	if len(override.DeniedTools) > 0 {
+		policy.DeniedTools = cleanToolList(append(append([]string{}, policy.DeniedTools...), override.DeniedTools...))
+	}
 	if len(override.AllowedToolClasses) > 0 {
 		policy.AllowedToolClasses = cleanToolList(override.AllowedToolClasses)
 	}
@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/secretscan"
+)
+
+const secretsWithheld = "[tool output withheld: it contained credentials (%s)]"
+
+// scanSecrets masks credentials in tool output, or replaces the whole output
+// in block mode, and returns the kinds it found.
+func (a *Agent) scanSecrets(output string) (string, []string) {
+	if a.secretScanMode == secretscan.ModeOff {
+		return output, nil
+	}
+	masked, kinds := secretscan.Mask(output)
+	if len(kinds) == 0 {
+		return output, nil
+	}
+	if a.secretScanMode == secretscan.ModeBlock {
+		return fmt.Sprintf(secretsWithheld, strings.Join(kinds, ", ")), kinds
+	}
+	return masked, kinds
+}
+
+func (a *Agent) secretAction() string {
+	if a.secretScanMode == secretscan.ModeBlock {
+		return "withheld"
+	}
+	return "masked"
+}
@@ -0,0 +1,213 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrToolTimeout is wrapped by errors returned when a tool call exceeds its
+// execution timeout.
+var ErrToolTimeout = errors.New("tool execution timed out")
+
+// ExecutionPolicy bounds one tool call. Timeout applies per attempt; failed
+// attempts are retried up to MaxRetries times when the error is transient,
+// waiting RetryBackoff, then twice that, and so on. Zero fields are unset.
+type ExecutionPolicy struct {
+	Timeout      time.Duration
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// PolicyProvider is an optional interface for tools that declare their own
+// execution policy, e.g. read-only network tools that are safe to retry.
+// Registry overrides still take precedence.
+type PolicyProvider interface {
+	ExecutionPolicy() ExecutionPolicy
+}
+
+// SetDefaultPolicy sets the policy applied to every tool call.
+func (r *Registry) SetDefaultPolicy(policy ExecutionPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultPolicy = policy
+}
+
+// SetToolPolicy overrides the policy of one tool by name. Set fields win over
+// the default and over the policy the tool declares itself.
+func (r *Registry) SetToolPolicy(name string, policy ExecutionPolicy) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.toolPolicies[name] = policy
+}
+
+// PolicyFor returns the effective execution policy of a tool.
+func (r *Registry) PolicyFor(name string) ExecutionPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	policy := r.defaultPolicy
+	if provider, ok := r.tools[name].(PolicyProvider); ok {
+		policy = mergePolicy(policy, provider.ExecutionPolicy())
+	}
+	if override, ok := r.toolPolicies[name]; ok {
+		policy = mergePolicy(policy, override)
+	}
+	return policy
+}
+
+func mergePolicy(base, override ExecutionPolicy) ExecutionPolicy {
+	if override.Timeout > 0 {
+		base.Timeout = override.Timeout
+	}
+	if override.MaxRetries > 0 {
+		base.MaxRetries = override.MaxRetries
+	}
+	if override.RetryBackoff > 0 {
+		base.RetryBackoff = override.RetryBackoff
+	}
+	return base
+}
+
+// ParsePolicyOverrides reads per-tool overrides from "name=value" CSV lists:
+// timeouts in seconds and retry counts, e.g. "curl=30,web_search=15" and
+// "fetch_url=3".
+func ParsePolicyOverrides(timeoutsCSV, retriesCSV string) (map[string]ExecutionPolicy, error) {
+	overrides := map[string]ExecutionPolicy{}
+	timeouts, err := parseToolIntList(timeoutsCSV)
+	if err != nil {
+		return nil, fmt.Errorf("parse tool timeouts: %w", err)
+	}
+	for name, seconds := range timeouts {
+		policy := overrides[name]
+		policy.Timeout = time.Duration(seconds) * time.Second
+		overrides[name] = policy
+	}
+	retries, err := parseToolIntList(retriesCSV)
+	if err != nil {
+		return nil, fmt.Errorf("parse tool retries: %w", err)
+	}
+	for name, count := range retries {
+		policy := overrides[name]
+		policy.MaxRetries = count
+		overrides[name] = policy
+	}
+	return overrides, nil
+}
+
+func parseToolIntList(csv string) (map[string]int, error) {
+	values := map[string]int{}
+	for _, entry := range strings.Split(csv, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid entry %q, expected name=value", entry)
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid value in %q", entry)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// runWithPolicy runs call with the tool's timeout and retries transient
+// failures. A tool that ignores context cancellation is abandoned when its
+// timeout fires so it cannot hold up the agent turn.
+func (r *Registry) runWithPolicy(ctx context.Context, name string, call func(context.Context) (StructuredResult, error)) (StructuredResult, error) {
+	policy := r.PolicyFor(name)
+	backoff := policy.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		result, err := runWithTimeout(ctx, name, policy.Timeout, call)
+		if err == nil || attempt >= policy.MaxRetries || ctx.Err() != nil || !IsTransient(err) {
+			if err != nil && attempt > 0 {
+				err = fmt.Errorf("%w (after %d attempts)", err, attempt+1)
+			}
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func runWithTimeout(ctx context.Context, name string, timeout time.Duration, call func(context.Context) (StructuredResult, error)) (StructuredResult, error) {
+	if timeout <= 0 {
+		return call(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	type outcome struct {
+		result StructuredResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := call(attemptCtx)
+		done <- outcome{result: result, err: err}
+	}()
+	select {
+	case finished := <-done:
+		if finished.err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			return finished.result, fmt.Errorf("tool %s timed out after %s: %w", name, timeout, ErrToolTimeout)
+		}
+		return finished.result, finished.err
+	case <-attemptCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return StructuredResult{}, err
+		}
+		return StructuredResult{}, fmt.Errorf("tool %s timed out after %s: %w", name, timeout, ErrToolTimeout)
+	}
+}
+
+// IsTransient reports whether a tool error is worth retrying: timeouts,
+// refused or reset connections, and failures the error text marks as
+// temporary, since sandboxed commands only report their output.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrToolTimeout) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	text := strings.ToLower(err.Error())
+	for _, marker := range []string{
+		"timed out",
+		"timeout",
+		"connection reset",
+		"connection refused",
+		"temporary failure",
+		"try again",
+		"status 502",
+		"status 503",
+		"status 504",
+		"too many requests",
+	} {
+		if strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
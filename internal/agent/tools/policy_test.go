@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type retryableMockTool struct {
+	MockTool
+}
+
+func (m *retryableMockTool) ExecutionPolicy() ExecutionPolicy {
+	return ExecutionPolicy{MaxRetries: 2}
+}
+
+func TestRegistry_TimeoutAbandonsHungTool(t *testing.T) {
+	reg := NewRegistry()
+	release := make(chan struct{})
+	defer close(release)
+	reg.Register(&MockTool{NameVal: "hung", ExecFunc: func(ctx context.Context, args json.RawMessage) (string, error) {
+		<-release
+		return "late", nil
+	}})
+	reg.SetDefaultPolicy(ExecutionPolicy{Timeout: 20 * time.Millisecond})
+
+	started := time.Now()
+	_, err := reg.ExecuteTool(context.Background(), "hung", nil)
+	if !errors.Is(err, ErrToolTimeout) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("expected hung tool abandoned at its timeout, took %s", elapsed)
+	}
+}
+
+func TestRegistry_RetriesTransientFailures(t *testing.T) {
+	reg := NewRegistry()
+	attempts := 0
+	reg.Register(&retryableMockTool{MockTool{NameVal: "fetch", ExecFunc: func(ctx context.Context, args json.RawMessage) (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", errors.New("curl: (7) Failed to connect: Connection refused")
+		}
+		return "page", nil
+	}}})
+	reg.SetDefaultPolicy(ExecutionPolicy{RetryBackoff: time.Millisecond})
+
+	output, err := reg.ExecuteTool(context.Background(), "fetch", nil)
+	if err != nil || output != "page" || attempts != 3 {
+		t.Fatalf("expected success on third attempt, got %q %v after %d attempts", output, err, attempts)
+	}
+
+	attempts = -10 // fail every attempt this time
+	_, err = reg.ExecuteTool(context.Background(), "fetch", nil)
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("expected retries exhausted, got %v", err)
+	}
+}
+
+func TestRegistry_DoesNotRetryPermanentFailures(t *testing.T) {
+	reg := NewRegistry()
+	attempts := 0
+	reg.Register(&MockTool{NameVal: "write", ExecFunc: func(ctx context.Context, args json.RawMessage) (string, error) {
+		attempts++
+		return "", errors.New("permission denied")
+	}})
+	reg.SetDefaultPolicy(ExecutionPolicy{MaxRetries: 3, RetryBackoff: time.Millisecond})
+	if _, err := reg.ExecuteTool(context.Background(), "write", nil); err == nil || attempts != 1 {
+		t.Fatalf("expected a single failed attempt, got %v after %d attempts", err, attempts)
+	}
+}
+
+func TestRegistry_PolicyPrecedence(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&retryableMockTool{MockTool{NameVal: "fetch"}})
+	reg.SetDefaultPolicy(ExecutionPolicy{Timeout: time.Minute, RetryBackoff: time.Second})
+	reg.SetToolPolicy("fetch", ExecutionPolicy{Timeout: 5 * time.Second})
+
+	policy := reg.PolicyFor("fetch")
+	if policy.Timeout != 5*time.Second || policy.MaxRetries != 2 || policy.RetryBackoff != time.Second {
+		t.Fatalf("unexpected merged policy %+v", policy)
+	}
+}
+
+func TestParsePolicyOverrides(t *testing.T) {
+	overrides, err := ParsePolicyOverrides("curl=30, web_search=15", "fetch_url=3")
+	if err != nil {
+		t.Fatalf("parse overrides: %v", err)
+	}
+	if overrides["curl"].Timeout != 30*time.Second || overrides["fetch_url"].MaxRetries != 3 || len(overrides) != 3 {
+		t.Fatalf("unexpected overrides %+v", overrides)
+	}
+	if _, err := ParsePolicyOverrides("curl", ""); err == nil {
+		t.Fatal("expected malformed entry rejected")
+	}
+	if _, err := ParsePolicyOverrides("", "fetch_url=-1"); err == nil {
+		t.Fatal("expected negative retries rejected")
+	}
+}
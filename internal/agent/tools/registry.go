@@ -15,6 +15,8 @@ type Registry struct {
 	tools          map[string]Tool
 	toolNamespaces map[string]string
 	namespaces     map[string]map[string]struct{}
+	defaultPolicy  ExecutionPolicy
+	toolPolicies   map[string]ExecutionPolicy
 }
 
 func NewRegistry() *Registry {
@@ -22,6 +24,7 @@ func NewRegistry() *Registry {
 		tools:          make(map[string]Tool),
 		toolNamespaces: make(map[string]string),
 		namespaces:     make(map[string]map[string]struct{}),
+		toolPolicies:   make(map[string]ExecutionPolicy),
 	}
 }
 
@@ -56,7 +59,8 @@ func (r *Registry) List() []Tool {
 	return list
 }
 
-// ExecuteTool finds a tool by name and executes it with the provided raw JSON arguments.
+// ExecuteTool finds a tool by name and executes it with the provided raw JSON
+// arguments under the tool's execution policy.
 func (r *Registry) ExecuteTool(ctx context.Context, name string, args json.RawMessage) (string, error) {
 	tool, exists := r.Get(name)
 	if !exists {
@@ -67,17 +71,33 @@ func (r *Registry) ExecuteTool(ctx context.Context, name string, args json.RawMe
 			return "", fmt.Errorf("invalid args for %s: %w", name, err)
 		}
 	}
-	return tool.Execute(ctx, args)
+	result, err := r.runWithPolicy(ctx, name, func(ctx context.Context) (StructuredResult, error) {
+		output, err := tool.Execute(ctx, args)
+		return StructuredResult{Summary: output}, err
+	})
+	return result.Summary, err
 }
 
 // DescribeAll returns a formatted string describing all available tools for the LLM system prompt.
 func (r *Registry) DescribeAll() string {
+	return r.DescribeAllExcept(nil)
+}
+
+// DescribeAllExcept describes every registered tool but the excluded ones.
+func (r *Registry) DescribeAllExcept(excluded []string) string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	skip := map[string]bool{}
+	for _, name := range excluded {
+		skip[strings.ToLower(strings.TrimSpace(name))] = true
+	}
 	// Sort for deterministic output
 	names := make([]string, 0, len(r.tools))
 	for name := range r.tools {
+		if skip[strings.ToLower(name)] {
+			continue
+		}
 		names = append(names, name)
 	}
 	sort.Strings(names)
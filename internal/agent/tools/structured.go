@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Structured result kinds understood by RenderMarkdown.
+const (
+	ResultKindTable = "table"
+	ResultKindLinks = "links"
+)
+
+// StructuredResult pairs the text the LLM sees with a JSON payload that
+// downstream code can render without re-parsing prose.
+type StructuredResult struct {
+	Summary string
+	Kind    string
+	Data    json.RawMessage
+}
+
+// StructuredExecutor is an optional interface for tools that can return a
+// machine-readable result. Execute should still return the Summary.
+type StructuredExecutor interface {
+	ExecuteStructured(ctx context.Context, input json.RawMessage) (StructuredResult, error)
+}
+
+// Table is the payload for ResultKindTable. An optional Links slice holds
+// one URL per row.
+type Table struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+	Links   []string   `json:"links,omitempty"`
+}
+
+// Link is one entry of a ResultKindLinks payload.
+type Link struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// NewStructuredResult marshals data into a result of the given kind.
+func NewStructuredResult(summary, kind string, data any) (StructuredResult, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return StructuredResult{}, fmt.Errorf("marshal %s result: %w", kind, err)
+	}
+	return StructuredResult{Summary: summary, Kind: kind, Data: payload}, nil
+}
+
+// ExecuteToolStructured runs a tool and returns its structured result, or a
+// summary-only result for tools that return plain strings.
+func (r *Registry) ExecuteToolStructured(ctx context.Context, name string, args json.RawMessage) (StructuredResult, error) {
+	tool, exists := r.Get(name)
+	if !exists {
+		return StructuredResult{}, fmt.Errorf("tool not found: %s", name)
+	}
+	structured, ok := tool.(StructuredExecutor)
+	if !ok {
+		output, err := r.ExecuteTool(ctx, name, args)
+		return StructuredResult{Summary: output}, err
+	}
+	if validator, ok := tool.(ArgumentValidator); ok {
+		if err := validator.ValidateArgs(args); err != nil {
+			return StructuredResult{}, fmt.Errorf("invalid args for %s: %w", name, err)
+		}
+	}
+	return r.runWithPolicy(ctx, name, func(ctx context.Context) (StructuredResult, error) {
+		return structured.ExecuteStructured(ctx, args)
+	})
+}
+
+// RenderMarkdown renders a structured result as markdown for channels and
+// artifacts. Unknown kinds and malformed payloads fall back to the summary.
+func RenderMarkdown(result StructuredResult) string {
+	if len(result.Data) == 0 {
+		return result.Summary
+	}
+	switch result.Kind {
+	case ResultKindTable:
+		var table Table
+		if err := json.Unmarshal(result.Data, &table); err != nil || len(table.Columns) == 0 {
+			return result.Summary
+		}
+		return renderTable(table)
+	case ResultKindLinks:
+		var links []Link
+		if err := json.Unmarshal(result.Data, &links); err != nil || len(links) == 0 {
+			return result.Summary
+		}
+		lines := make([]string, 0, len(links))
+		for _, link := range links {
+			lines = append(lines, fmt.Sprintf("- [%s](%s)", escapeMarkdownCell(link.Title), link.URL))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return result.Summary
+	}
+}
+
+func renderTable(table Table) string {
+	lines := []string{
+		"| " + strings.Join(escapeCells(table.Columns), " | ") + " |",
+		"|" + strings.Repeat(" --- |", len(table.Columns)),
+	}
+	for index, row := range table.Rows {
+		cells := make([]string, len(table.Columns))
+		copy(cells, escapeCells(row))
+		if index < len(table.Links) && table.Links[index] != "" && cells[0] != "" {
+			cells[0] = fmt.Sprintf("[%s](%s)", cells[0], table.Links[index])
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func escapeCells(values []string) []string {
+	escaped := make([]string, len(values))
+	for index, value := range values {
+		escaped[index] = escapeMarkdownCell(value)
+	}
+	return escaped
+}
+
+func escapeMarkdownCell(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	return strings.NewReplacer("|", `\|`, "[", `\[`, "]", `\]`).Replace(value)
+}
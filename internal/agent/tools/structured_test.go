@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type structuredMockTool struct {
+	MockTool
+	result StructuredResult
+}
+
+func (s *structuredMockTool) ExecuteStructured(ctx context.Context, input json.RawMessage) (StructuredResult, error) {
+	return s.result, nil
+}
+
+func TestRegistry_ExecuteToolStructured(t *testing.T) {
+	table, err := NewStructuredResult("2 issues", ResultKindTable, Table{
+		Columns: []string{"Issue", "Title"},
+		Rows:    [][]string{{"#1", "Fix | pipes"}, {"#2", "Docs"}},
+		Links:   []string{"https://example.com/1", ""},
+	})
+	if err != nil {
+		t.Fatalf("new result: %v", err)
+	}
+	reg := NewRegistry()
+	reg.Register(&structuredMockTool{MockTool: MockTool{NameVal: "issues"}, result: table})
+	reg.Register(&MockTool{
+		NameVal: "plain",
+		ExecFunc: func(ctx context.Context, input json.RawMessage) (string, error) {
+			return "plain output", nil
+		},
+	})
+
+	result, err := reg.ExecuteToolStructured(context.Background(), "issues", nil)
+	if err != nil {
+		t.Fatalf("execute structured: %v", err)
+	}
+	rendered := RenderMarkdown(result)
+	for _, want := range []string{"| Issue | Title |", "| [#1](https://example.com/1) | Fix \\| pipes |", "| #2 | Docs |"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in rendered table, got:\n%s", want, rendered)
+		}
+	}
+
+	plain, err := reg.ExecuteToolStructured(context.Background(), "plain", nil)
+	if err != nil || plain.Summary != "plain output" || plain.Kind != "" || RenderMarkdown(plain) != "plain output" {
+		t.Fatalf("expected summary-only result for plain tool, got %+v (%v)", plain, err)
+	}
+}
+
+func TestRenderMarkdownLinksAndFallback(t *testing.T) {
+	links, _ := NewStructuredResult("one link", ResultKindLinks, []Link{{Title: "Runbook [v2]", URL: "https://example.com/runbook"}})
+	if got := RenderMarkdown(links); got != "- [Runbook \\[v2\\]](https://example.com/runbook)" {
+		t.Fatalf("unexpected links rendering: %s", got)
+	}
+	broken := StructuredResult{Summary: "fallback", Kind: ResultKindTable, Data: json.RawMessage(`{"rows":`)}
+	if got := RenderMarkdown(broken); got != "fallback" {
+		t.Fatalf("expected summary fallback, got %s", got)
+	}
+}
@@ -43,3 +43,10 @@ type MetadataProvider interface {
 	ToolClass() ToolClass
 	RequiresApproval() bool
 }
+
+// UntrustedSource is an optional interface for tools whose output comes from
+// outside the runtime (web pages, search results, documents). The agent
+// sanitizes that output and fences it off as data before the model sees it.
+type UntrustedSource interface {
+	UntrustedOutput() bool
+}
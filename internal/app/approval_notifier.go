@@ -0,0 +1,207 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/outbox"
+	"github.com/dwizi/agent-runtime/internal/reply"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// approvalNotifier posts new action approvals to the workspace's admin
+// channels with Approve/Deny buttons, so admins do not have to type
+// /approve-action.
+type approvalNotifier struct {
+	workspaceRoot string
+	store         *store.Store
+	publishers    map[string]connectors.Publisher
+	logger        *slog.Logger
+}
+
+func newApprovalNotifier(
+	workspaceRoot string,
+	storeRef *store.Store,
+	publishers map[string]connectors.Publisher,
+	logger *slog.Logger,
+) *approvalNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clean := map[string]connectors.Publisher{}
+	for key, publisher := range publishers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || publisher == nil {
+			continue
+		}
+		clean[name] = publisher
+	}
+	return &approvalNotifier{
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		store:         storeRef,
+		publishers:    clean,
+		logger:        logger,
+	}
+}
+
+// NotifyActionApprovalCreated publishes in the background; approvals are
+// created inside agent turns and tool calls that must not wait on connectors.
+func (n *approvalNotifier) NotifyActionApprovalCreated(ctx context.Context, approval store.ActionApproval) {
+	if n == nil || n.store == nil || strings.TrimSpace(approval.WorkspaceID) == "" {
+		return
+	}
+	go n.publish(approval)
+}
+
+func (n *approvalNotifier) publish(approval store.ActionApproval) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	targets, err := n.store.ListWorkspaceAdminDeliveries(ctx, approval.WorkspaceID, 50)
+	if err != nil {
+		n.logger.Error("list workspace admin deliveries failed", "workspace_id", approval.WorkspaceID, "error", err)
+		return
+	}
+	message := buildActionApprovalNotice(approval)
+	for _, target := range targets {
+		connector := strings.ToLower(strings.TrimSpace(target.Connector))
+		publisher := n.publishers[connector]
+		if publisher == nil {
+			continue
+		}
+		publishCtx, publishCancel := context.WithTimeout(outbox.WithCollapseKey(ctx, approvalAlertKey(approval.ID)), 10*time.Second)
+		messageID, err := connectors.PublishTracked(publishCtx, publisher, target.ExternalID, message)
+		publishCancel()
+		if err != nil {
+			n.logger.Error("publish approval notice failed",
+				"action_id", approval.ID,
+				"connector", connector,
+				"external_id", target.ExternalID,
+				"error", err,
+			)
+			continue
+		}
+		appendOutboundChatLog(n.workspaceRoot, target.WorkspaceID, target.Connector, target.ExternalID, message.PlainText())
+		if messageID == "" {
+			continue
+		}
+		if err := n.store.RecordAdminNotice(ctx, store.RecordAdminNoticeInput{
+			AlertKey:   approvalAlertKey(approval.ID),
+			Connector:  connector,
+			ExternalID: target.ExternalID,
+			MessageID:  messageID,
+		}); err != nil {
+			n.logger.Error("record approval notice failed", "action_id", approval.ID, "connector", connector, "error", err)
+		}
+	}
+	// An admin may have acted before every copy went out; those copies
+	// still carry buttons and are resolved now.
+	current, err := n.store.LookupActionApproval(ctx, approval.ID)
+	if err == nil && current.Status != "pending" {
+		n.resolve(ctx, current)
+	}
+}
+
+// NotifyActionApprovalDecided marks every copy of the approval notice handled
+// and edits them to show the outcome, so the Approve/Deny buttons disappear
+// from the admin channels where nobody acted.
+func (n *approvalNotifier) NotifyActionApprovalDecided(ctx context.Context, approval store.ActionApproval) {
+	if n == nil || n.store == nil {
+		return
+	}
+	go func() {
+		resolveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		n.resolve(resolveCtx, approval)
+	}()
+}
+
+func (n *approvalNotifier) resolve(ctx context.Context, approval store.ActionApproval) {
+	notices, err := n.store.MarkAdminNoticesHandled(ctx, approvalAlertKey(approval.ID), approval.ApproverUserID)
+	if err != nil {
+		n.logger.Error("mark approval notices handled failed", "action_id", approval.ID, "error", err)
+	}
+	message := buildActionApprovalResolution(approval)
+	for _, notice := range notices {
+		publisher := n.publishers[notice.Connector]
+		if publisher == nil {
+			continue
+		}
+		editCtx, editCancel := context.WithTimeout(ctx, 10*time.Second)
+		err := connectors.EditRich(editCtx, publisher, notice.ExternalID, notice.MessageID, message)
+		editCancel()
+		if err != nil {
+			n.logger.Warn("edit approval notice failed",
+				"action_id", approval.ID,
+				"connector", notice.Connector,
+				"external_id", notice.ExternalID,
+				"error", err,
+			)
+		}
+	}
+}
+
+func approvalAlertKey(approvalID string) string {
+	return "approval:" + strings.TrimSpace(approvalID)
+}
+
+func buildActionApprovalNotice(approval store.ActionApproval) reply.Message {
+	lines := []string{
+		fmt.Sprintf("- action: `%s`", approval.ActionType),
+	}
+	if target := strings.TrimSpace(approval.ActionTarget); target != "" {
+		lines = append(lines, fmt.Sprintf("- target: `%s`", target))
+	}
+	if summary := strings.TrimSpace(approval.ActionSummary); summary != "" {
+		lines = append(lines, "- summary: "+summary)
+	}
+	lines = append(lines,
+		fmt.Sprintf("- requested by: `%s` in %s `%s`", approval.RequesterUserID, approval.Connector, approval.ExternalID),
+		fmt.Sprintf("- id: `%s`", approval.ID),
+	)
+	if approval.RiskLevel != "" {
+		lines = append(lines, fmt.Sprintf("- risk: %s (%s)", approval.RiskLevel, approval.RiskReason))
+	}
+	if approval.RequiredApprovals > 1 {
+		lines = append(lines, fmt.Sprintf("- needs %d distinct admin approvals", approval.RequiredApprovals))
+	}
+	return reply.Message{
+		Title: "Approval needed",
+		Text:  strings.Join(lines, "\n"),
+		Buttons: []reply.Button{
+			{Label: "Approve", Command: "/approve-action " + approval.ID, Style: reply.ButtonPrimary},
+			{Label: "Deny", Command: "/deny-action " + approval.ID, Style: reply.ButtonDanger},
+		},
+	}
+}
+
+// buildActionApprovalResolution replaces an approval notice once the action
+// is decided. It has no buttons left to press.
+func buildActionApprovalResolution(approval store.ActionApproval) reply.Message {
+	lines := []string{
+		fmt.Sprintf("- action: `%s`", approval.ActionType),
+	}
+	if target := strings.TrimSpace(approval.ActionTarget); target != "" {
+		lines = append(lines, fmt.Sprintf("- target: `%s`", target))
+	}
+	lines = append(lines, fmt.Sprintf("- id: `%s`", approval.ID))
+	title := "Approval handled"
+	switch {
+	case approval.Status == "approved":
+		title = "Approved"
+		lines = append(lines, fmt.Sprintf("- approved by `%s`", approval.ApproverUserID))
+	case approval.ApproverUserID == store.ExpiredApprovalUserID:
+		title = "Expired"
+		lines = append(lines, "- "+approval.DeniedReason)
+	case approval.Status == "denied":
+		title = "Denied"
+		lines = append(lines, fmt.Sprintf("- denied by `%s`", approval.ApproverUserID))
+		if reason := strings.TrimSpace(approval.DeniedReason); reason != "" {
+			lines = append(lines, "- reason: "+reason)
+		}
+	}
+	return reply.Message{Title: title, Text: strings.Join(lines, "\n")}
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/reply"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeRichPublisher struct {
+	fakePublisher
+	rich []reply.Message
+}
+
+func (f *fakeRichPublisher) PublishRich(ctx context.Context, externalID string, message reply.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rich = append(f.rich, message)
+	return nil
+}
+
+// fakeTrackedPublisher hands out message ids and records edits to them.
+type fakeTrackedPublisher struct {
+	fakeRichPublisher
+	edits map[string]reply.Message
+}
+
+func (f *fakeTrackedPublisher) PublishRichTracked(ctx context.Context, externalID string, message reply.Message) (string, error) {
+	if err := f.PublishRich(ctx, externalID, message); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fmt.Sprintf("%s-%d", externalID, len(f.rich)), nil
+}
+
+func (f *fakeTrackedPublisher) EditRich(ctx context.Context, externalID, messageID string, message reply.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.edits == nil {
+		f.edits = map[string]reply.Message{}
+	}
+	f.edits[messageID] = message
+	return nil
+}
+
+func TestApprovalNotifierPostsButtonsToAdminChannels(t *testing.T) {
+	sqlStore := openAppTestStore(t)
+	ctx := context.Background()
+	adminContext, err := sqlStore.SetContextAdminByExternal(ctx, "telegram", "200", true)
+	if err != nil {
+		t.Fatalf("set admin context: %v", err)
+	}
+	approval, err := sqlStore.CreateActionApproval(ctx, store.CreateActionApprovalInput{
+		WorkspaceID:     adminContext.WorkspaceID,
+		ContextID:       adminContext.ID,
+		Connector:       "telegram",
+		ExternalID:      "200",
+		RequesterUserID: "user-1",
+		ActionType:      "send_email",
+		ActionTarget:    "ops@example.com",
+		ActionSummary:   "Send the weekly digest",
+	})
+	if err != nil {
+		t.Fatalf("create action approval: %v", err)
+	}
+
+	telegram := &fakeRichPublisher{}
+	notifier := newApprovalNotifier("", sqlStore, map[string]connectors.Publisher{"telegram": telegram}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	notifier.publish(approval)
+
+	if len(telegram.rich) != 1 {
+		t.Fatalf("expected one rich notice in the admin channel, got %d", len(telegram.rich))
+	}
+	buttons := telegram.rich[0].ValidButtons()
+	if len(buttons) != 2 || buttons[0].Command != "/approve-action "+approval.ID || buttons[1].Command != "/deny-action "+approval.ID {
+		t.Fatalf("unexpected approval buttons %+v", buttons)
+	}
+	if len(telegram.messages) != 0 {
+		t.Fatalf("expected no plain text fallback, got %+v", telegram.messages)
+	}
+}
+
+func TestApprovalNotifierEditsEveryAdminCopyOnceDecided(t *testing.T) {
+	sqlStore := openAppTestStore(t)
+	ctx := context.Background()
+	telegramAdmin, err := sqlStore.SetContextAdminByExternal(ctx, "telegram", "200", true)
+	if err != nil {
+		t.Fatalf("set telegram admin context: %v", err)
+	}
+	approval, err := sqlStore.CreateActionApproval(ctx, store.CreateActionApprovalInput{
+		WorkspaceID:     telegramAdmin.WorkspaceID,
+		ContextID:       telegramAdmin.ID,
+		Connector:       "telegram",
+		ExternalID:      "200",
+		RequesterUserID: "user-1",
+		ActionType:      "send_email",
+		ActionTarget:    "ops@example.com",
+	})
+	if err != nil {
+		t.Fatalf("create action approval: %v", err)
+	}
+
+	telegram := &fakeTrackedPublisher{}
+	discord := &fakeTrackedPublisher{}
+	notifier := newApprovalNotifier("", sqlStore, map[string]connectors.Publisher{"telegram": telegram, "discord": discord}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	notifier.publish(approval)
+	if len(telegram.rich) != 1 {
+		t.Fatalf("expected the notice in the admin channel, got %d", len(telegram.rich))
+	}
+	// A mirrored copy in a second admin channel, as sent for another
+	// context of the workspace.
+	if err := sqlStore.RecordAdminNotice(ctx, store.RecordAdminNoticeInput{
+		AlertKey:   approvalAlertKey(approval.ID),
+		Connector:  "discord",
+		ExternalID: "chan-1",
+		MessageID:  "msg-1",
+	}); err != nil {
+		t.Fatalf("record discord notice: %v", err)
+	}
+
+	approved, err := sqlStore.ApproveActionApproval(ctx, store.ApproveActionApprovalInput{ID: approval.ID, ApproverUserID: "admin-1"})
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	notifier.resolve(ctx, approved)
+	notifier.resolve(ctx, approved)
+
+	for name, publisher := range map[string]*fakeTrackedPublisher{"telegram": telegram, "discord": discord} {
+		if len(publisher.edits) != 1 {
+			t.Fatalf("expected one edit in %s, got %+v", name, publisher.edits)
+		}
+		for _, edit := range publisher.edits {
+			if edit.Title != "Approved" || len(edit.ValidButtons()) != 0 || !strings.Contains(edit.Text, "admin-1") {
+				t.Fatalf("unexpected %s edit %+v", name, edit)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/outbox"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// backpressureNotifier tells admin channels when the task queue becomes busy
+// and when it has drained again.
+type backpressureNotifier struct {
+	store         *store.Store
+	publishers    map[string]connectors.Publisher
+	workspaceRoot string
+	enabled       bool
+	logger        *slog.Logger
+}
+
+func newBackpressureNotifier(
+	storeRef *store.Store,
+	publishers map[string]connectors.Publisher,
+	workspaceRoot string,
+	enabled bool,
+	logger *slog.Logger,
+) *backpressureNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clean := map[string]connectors.Publisher{}
+	for key, publisher := range publishers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || publisher == nil {
+			continue
+		}
+		clean[name] = publisher
+	}
+	return &backpressureNotifier{
+		store:         storeRef,
+		publishers:    clean,
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		enabled:       enabled,
+		logger:        logger,
+	}
+}
+
+// OnBackpressure publishes in the background; the engine calls it while
+// queueing or picking up tasks.
+func (n *backpressureNotifier) OnBackpressure(state orchestrator.BackpressureState) {
+	if n == nil || n.store == nil || !n.enabled {
+		return
+	}
+	go n.publish(state)
+}
+
+func (n *backpressureNotifier) publish(state orchestrator.BackpressureState) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	records, err := n.store.ListAdminDeliveries(ctx, 200)
+	if err != nil {
+		n.logger.Error("backpressure list admin deliveries failed", "error", err)
+		return
+	}
+	text := buildBackpressureNotice(state)
+	seen := map[string]struct{}{}
+	for _, target := range records {
+		connector := strings.ToLower(strings.TrimSpace(target.Connector))
+		externalID := strings.TrimSpace(target.ExternalID)
+		if connector == "" || externalID == "" {
+			continue
+		}
+		if _, ok := seen[connector+"::"+externalID]; ok {
+			continue
+		}
+		seen[connector+"::"+externalID] = struct{}{}
+		publisher := n.publishers[connector]
+		if publisher == nil {
+			continue
+		}
+		publishCtx, publishCancel := context.WithTimeout(outbox.WithCollapseKey(ctx, "backpressure"), 10*time.Second)
+		err := publisher.Publish(publishCtx, externalID, text)
+		publishCancel()
+		if err != nil {
+			n.logger.Error("publish backpressure notice failed",
+				"connector", connector,
+				"external_id", externalID,
+				"error", err,
+			)
+			continue
+		}
+		appendOutboundChatLog(n.workspaceRoot, target.WorkspaceID, target.Connector, externalID, text)
+	}
+}
+
+func buildBackpressureNotice(state orchestrator.BackpressureState) string {
+	if !state.Active {
+		return fmt.Sprintf(
+			"Task queue recovered\n- waiting: %d (threshold %d)\nHeld low-priority tasks are being queued again.",
+			state.Depth,
+			state.Threshold,
+		)
+	}
+	return fmt.Sprintf(
+		"Task queue is busy\n- waiting: %d (threshold %d)\nNew p3 auto-routed tasks are held until the queue drains and users are told to expect delays. Check `GET /api/v1/workers` and raise the worker pool if this keeps happening.",
+		state.Depth,
+		state.Threshold,
+	)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/dwizi/agent-runtime/internal/connectors"
 	"github.com/dwizi/agent-runtime/internal/heartbeat"
+	"github.com/dwizi/agent-runtime/internal/outbox"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -84,7 +85,7 @@ func (n *heartbeatNotifier) HandleTransition(ctx context.Context, transition hea
 		if publisher == nil {
 			continue
 		}
-		publishCtx, cancel := context.WithTimeout(ctx, 8*time.Second)
+		publishCtx, cancel := context.WithTimeout(outbox.WithCollapseKey(ctx, "heartbeat:"+transition.Component), 8*time.Second)
 		err := publisher.Publish(publishCtx, target.ExternalID, message)
 		cancel()
 		if err != nil {
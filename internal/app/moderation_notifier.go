@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/reply"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// moderationNotifier sends appeals to the workspace's admin channels with
+// the contested decision's evidence and history.
+type moderationNotifier struct {
+	workspaceRoot string
+	store         *store.Store
+	publishers    map[string]connectors.Publisher
+	logger        *slog.Logger
+}
+
+func newModerationNotifier(
+	workspaceRoot string,
+	storeRef *store.Store,
+	publishers map[string]connectors.Publisher,
+	logger *slog.Logger,
+) *moderationNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clean := map[string]connectors.Publisher{}
+	for key, publisher := range publishers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || publisher == nil {
+			continue
+		}
+		clean[name] = publisher
+	}
+	return &moderationNotifier{
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		store:         storeRef,
+		publishers:    clean,
+		logger:        logger,
+	}
+}
+
+func (n *moderationNotifier) NotifyModerationAppeal(ctx context.Context, decision, appeal store.ModerationCase) {
+	if n == nil || n.store == nil {
+		return
+	}
+	go n.publish(decision, appeal)
+}
+
+func (n *moderationNotifier) publish(decision, appeal store.ModerationCase) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	targets, err := n.store.ListWorkspaceAdminDeliveries(ctx, decision.WorkspaceID, 50)
+	if err != nil {
+		n.logger.Error("list workspace admin deliveries failed", "workspace_id", decision.WorkspaceID, "error", err)
+		return
+	}
+	history, err := n.store.ListModerationDecisionsForUser(ctx, decision.Connector, decision.SubjectUserID, 6)
+	if err != nil {
+		n.logger.Warn("list moderation history failed", "case_id", decision.ID, "error", err)
+	}
+	message := buildModerationAppealNotice(decision, appeal, history)
+	for _, target := range targets {
+		connector := strings.ToLower(strings.TrimSpace(target.Connector))
+		publisher := n.publishers[connector]
+		if publisher == nil {
+			continue
+		}
+		publishCtx, publishCancel := context.WithTimeout(ctx, 10*time.Second)
+		err := connectors.PublishRich(publishCtx, publisher, target.ExternalID, message)
+		publishCancel()
+		if err != nil {
+			n.logger.Error("publish moderation appeal failed",
+				"appeal_id", appeal.ID,
+				"connector", connector,
+				"external_id", target.ExternalID,
+				"error", err,
+			)
+			continue
+		}
+		appendOutboundChatLog(n.workspaceRoot, target.WorkspaceID, target.Connector, target.ExternalID, message.PlainText())
+	}
+}
+
+// buildModerationAppealNotice lays out the appeal, the original evidence and
+// the user's earlier decisions so admins can judge without digging.
+func buildModerationAppealNotice(decision, appeal store.ModerationCase, history []store.ModerationCase) reply.Message {
+	lines := []string{
+		fmt.Sprintf("- user: `%s user %s` in `%s`", decision.Connector, decision.SubjectUserID, decision.ExternalID),
+		fmt.Sprintf("- appeal: `%s`", appeal.ID),
+		"- says: " + truncateSingleLine(appeal.Reason, 400),
+		"",
+		"Decision trail:",
+		fmt.Sprintf("- %s: message %s (case `%s`)", decision.CreatedAt.UTC().Format(time.RFC3339), decision.Action, decision.ID),
+	}
+	if reason := strings.TrimSpace(decision.Reason); reason != "" {
+		lines = append(lines, "- reason: "+reason)
+	}
+	if task := strings.TrimSpace(decision.TaskID); task != "" {
+		lines = append(lines, fmt.Sprintf("- moderation task: `%s`", task))
+	}
+	lines = append(lines, fmt.Sprintf("- %s: appealed", appeal.CreatedAt.UTC().Format(time.RFC3339)))
+	earlier := 0
+	for _, item := range history {
+		if item.ID == decision.ID {
+			continue
+		}
+		earlier++
+		lines = append(lines, fmt.Sprintf("- earlier: %s on %s (%s)", item.Action, item.CreatedAt.UTC().Format("2006-01-02"), item.Status))
+	}
+	if earlier == 0 {
+		lines = append(lines, "- no earlier decisions about this user")
+	}
+	message := reply.Message{
+		Title: "Moderation appeal",
+		Text:  strings.Join(lines, "\n"),
+		Buttons: []reply.Button{
+			{Label: "Uphold", Command: "/uphold-appeal " + appeal.ID, Style: reply.ButtonDanger},
+			{Label: "Overturn", Command: "/overturn-appeal " + appeal.ID, Style: reply.ButtonPrimary},
+		},
+	}
+	if evidence := strings.TrimSpace(decision.Evidence); evidence != "" {
+		message.Code = []reply.CodeBlock{{Content: evidence}}
+	}
+	return message
+}
@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
 	"github.com/dwizi/agent-runtime/internal/connectors"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/outbox"
+	"github.com/dwizi/agent-runtime/internal/reply"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -25,6 +29,17 @@ type taskCompletionNotifier struct {
 	failurePolicy string
 	agentService  AgentService
 	logger        *slog.Logger
+	// artifactLinks signs the download links of completion notices; nil
+	// attaches small artifacts instead.
+	artifactLinks *artifactlink.Signer
+	// digests takes the results of digest objectives instead of notifying.
+	digests *objectiveDigests
+
+	// progressInterval spaces the progress notices sent to the origin of a
+	// running task; zero sends none.
+	progressInterval time.Duration
+	progressMu       sync.Mutex
+	progressSent     map[string]time.Time
 }
 
 func newTaskCompletionNotifier(
@@ -63,6 +78,20 @@ func newTaskCompletionNotifier(
 	}
 }
 
+// SetProgressNotices makes long-running tasks tell their origin channel how
+// far they have come, at most once per interval.
+func (n *taskCompletionNotifier) SetProgressNotices(interval time.Duration) {
+	n.progressMu.Lock()
+	defer n.progressMu.Unlock()
+	n.progressInterval = interval
+}
+
+// SetObjectiveDigests collects the successful runs of digest objectives
+// into their digests instead of reporting each one.
+func (n *taskCompletionNotifier) SetObjectiveDigests(digests *objectiveDigests) {
+	n.digests = digests
+}
+
 func (n *taskCompletionNotifier) NotifyCompleted(task orchestrator.Task, result orchestrator.TaskResult) {
 	n.notify(task, result, nil, n.successPolicy)
 }
@@ -77,6 +106,7 @@ func (n *taskCompletionNotifier) NotifyStarted(task orchestrator.Task) {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
 	defer cancel()
+	ctx = outbox.WithCollapseKey(ctx, taskCollapseKey(task.ID))
 
 	taskRecord, hasTaskRecord := n.lookupTaskRecord(ctx, task.ID)
 	if !hasTaskRecord {
@@ -115,18 +145,89 @@ func (n *taskCompletionNotifier) NotifyStarted(task orchestrator.Task) {
 	}
 }
 
-func (n *taskCompletionNotifier) notify(task orchestrator.Task, result orchestrator.TaskResult, taskErr error, policy string) {
+// NotifyProgress posts a progress notice to the origin of a running task.
+// The first report of a run only starts the clock, so tasks finishing
+// within one interval stay quiet.
+func (n *taskCompletionNotifier) NotifyProgress(task orchestrator.Task, progress orchestrator.TaskProgress) {
 	if n == nil || n.store == nil || len(n.publishers) == 0 {
 		return
 	}
+	message := buildTaskProgressMessage(task, progress)
+	if message == "" || !n.progressDue(task.ID, time.Now()) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	// A later notice or the completion replaces a progress notice still
+	// queued during a connector outage.
+	ctx = outbox.WithCollapseKey(ctx, taskCollapseKey(task.ID))
+	for _, target := range n.resolveTargets(ctx, task, "origin") {
+		publisher := n.publishers[strings.ToLower(strings.TrimSpace(target.Connector))]
+		if publisher == nil {
+			continue
+		}
+		if err := publisher.Publish(ctx, target.ExternalID, message); err != nil {
+			n.logger.Error("task progress notification publish failed",
+				"task_id", task.ID,
+				"connector", target.Connector,
+				"external_id", target.ExternalID,
+				"error", err,
+			)
+			continue
+		}
+		appendOutboundChatLog(n.workspaceRoot, target.WorkspaceID, target.Connector, target.ExternalID, message)
+	}
+}
+
+func (n *taskCompletionNotifier) progressDue(taskID string, now time.Time) bool {
+	n.progressMu.Lock()
+	defer n.progressMu.Unlock()
+	if n.progressInterval <= 0 {
+		return false
+	}
+	if n.progressSent == nil {
+		n.progressSent = map[string]time.Time{}
+	}
+	last, seen := n.progressSent[taskID]
+	if !seen {
+		n.progressSent[taskID] = now
+		return false
+	}
+	if now.Sub(last) < n.progressInterval {
+		return false
+	}
+	n.progressSent[taskID] = now
+	return true
+}
+
+func (n *taskCompletionNotifier) notify(task orchestrator.Task, result orchestrator.TaskResult, taskErr error, policy string) {
+	if n == nil {
+		return
+	}
+	n.progressMu.Lock()
+	delete(n.progressSent, task.ID)
+	n.progressMu.Unlock()
+	if n.store == nil || len(n.publishers) == 0 || (taskErr == nil && result.Unchanged) {
+		return
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
+	// A completion queued during a connector outage replaces the queued start
+	// notice of the same task.
+	ctx = outbox.WithCollapseKey(ctx, taskCollapseKey(task.ID))
+	if taskErr == nil && n.digests.buffer(ctx, task, result) {
+		return
+	}
 
 	taskRecord, hasTaskRecord := n.lookupTaskRecord(ctx, task.ID)
 	routedTask := hasTaskRecord && strings.TrimSpace(taskRecord.RouteClass) != ""
 	if routedTask && taskErr != nil {
 		policy = "admin"
 	}
+	var attachments []reply.Attachment
+	if taskErr == nil {
+		result.Artifacts, attachments = n.keyArtifacts(ctx, task)
+	}
 	targets := n.resolveTargets(ctx, task, policy)
 	for _, target := range targets {
 		if taskErr != nil && !target.IsAdmin {
@@ -141,7 +242,14 @@ func (n *taskCompletionNotifier) notify(task orchestrator.Task, result orchestra
 		if publisher == nil {
 			continue
 		}
-		if err := publisher.Publish(ctx, target.ExternalID, message); err != nil {
+		message = appendArtifactLines(message, result.Artifacts)
+		var err error
+		if len(attachments) > 0 {
+			err = connectors.PublishRich(ctx, publisher, target.ExternalID, reply.Message{Text: message, Attachments: attachments})
+		} else {
+			err = publisher.Publish(ctx, target.ExternalID, message)
+		}
+		if err != nil {
 			n.logger.Error("task notification publish failed",
 				"task_id", task.ID,
 				"connector", target.Connector,
@@ -332,6 +440,27 @@ func buildTaskStartedMessage(taskRecord store.TaskRecord) string {
 	return "I ran some tools and I'm still working on this."
 }
 
+func buildTaskProgressMessage(task orchestrator.Task, progress orchestrator.TaskProgress) string {
+	step := truncateSingleLine(strings.TrimSpace(progress.Step), 300)
+	if step == "" && progress.Percent < 0 {
+		return ""
+	}
+	title := strings.TrimSpace(task.Title)
+	if title == "" {
+		title = "your task"
+	} else {
+		title = fmt.Sprintf("%q", truncateSingleLine(title, 120))
+	}
+	message := "Still working on " + title
+	if step != "" {
+		message += ": " + step
+	}
+	if progress.Percent >= 0 {
+		message += fmt.Sprintf(" (%d%%)", progress.Percent)
+	}
+	return message + "."
+}
+
 func includeOriginTarget(policy string) bool {
 	return policy == "both" || policy == "origin"
 }
@@ -382,3 +511,7 @@ func truncateWithEllipsis(input string, maxLen int) string {
 	}
 	return strings.TrimSpace(trimmed[:maxLen]) + "..."
 }
+
+func taskCollapseKey(taskID string) string {
+	return "task:" + strings.TrimSpace(taskID)
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/reply"
+)
+
+const (
+	// taskNoticeArtifacts bounds the artifacts a completion notice names.
+	taskNoticeArtifacts = 3
+	// taskArtifactAttachMaxBytes is the largest artifact attached to a
+	// completion notice when no download links are configured.
+	taskArtifactAttachMaxBytes = 512 * 1024
+)
+
+// SetArtifactLinks makes completion notices link the key artifacts of a
+// task instead of attaching them.
+func (n *taskCompletionNotifier) SetArtifactLinks(signer *artifactlink.Signer) {
+	n.artifactLinks = signer
+}
+
+// keyArtifacts returns the artifacts a completion notice presents, the
+// files the task saved before its result file, with their download links
+// when links are enabled. Without links, the ones small enough are returned
+// as attachments too.
+func (n *taskCompletionNotifier) keyArtifacts(ctx context.Context, task orchestrator.Task) ([]orchestrator.TaskArtifact, []reply.Attachment) {
+	stored, err := n.store.ListTaskArtifacts(ctx, task.ID)
+	if err != nil {
+		n.logger.Warn("task artifacts lookup failed", "task_id", task.ID, "error", err)
+		return nil, nil
+	}
+	ordered := stored[:0:0]
+	for _, artifact := range stored {
+		if artifact.Kind != "result" {
+			ordered = append(ordered, artifact)
+		}
+	}
+	for _, artifact := range stored {
+		if artifact.Kind == "result" {
+			ordered = append(ordered, artifact)
+		}
+	}
+	if len(ordered) > taskNoticeArtifacts {
+		ordered = ordered[:taskNoticeArtifacts]
+	}
+	artifacts := []orchestrator.TaskArtifact{}
+	attachments := []reply.Attachment{}
+	for _, record := range ordered {
+		artifact := orchestrator.TaskArtifact{
+			Path:      record.Path,
+			Kind:      record.Kind,
+			Title:     record.Title,
+			MediaType: record.MediaType,
+			SizeBytes: record.SizeBytes,
+			URL:       n.artifactLinks.Link(record.ID),
+		}
+		artifacts = append(artifacts, artifact)
+		if artifact.URL != "" || record.SizeBytes > taskArtifactAttachMaxBytes || n.workspaceRoot == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(n.workspaceRoot, record.WorkspaceID, filepath.FromSlash(record.Path)))
+		if err != nil || len(data) > taskArtifactAttachMaxBytes {
+			continue
+		}
+		attachments = append(attachments, reply.Attachment{Name: path.Base(record.Path), MediaType: record.MediaType, Data: data})
+	}
+	return artifacts, attachments
+}
+
+// appendArtifactLines lists artifacts under a completion notice, by link
+// when they have one and by workspace path otherwise.
+func appendArtifactLines(message string, artifacts []orchestrator.TaskArtifact) string {
+	if len(artifacts) == 0 {
+		return message
+	}
+	lines := []string{strings.TrimSpace(message), "", "Files:"}
+	for _, artifact := range artifacts {
+		title := strings.TrimSpace(artifact.Title)
+		if title == "" {
+			title = path.Base(artifact.Path)
+		}
+		location := "`" + artifact.Path + "`"
+		if artifact.URL != "" {
+			location = artifact.URL
+		}
+		lines = append(lines, fmt.Sprintf("- %s (%s): %s", title, formatArtifactSize(artifact.SizeBytes), location))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatArtifactSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
 	"github.com/dwizi/agent-runtime/internal/connectors"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
 	"github.com/dwizi/agent-runtime/internal/store"
@@ -104,6 +106,93 @@ func TestTaskCompletionNotificationToTaskContext(t *testing.T) {
 	}
 }
 
+func TestUnchangedObjectiveRunSendsNoNotice(t *testing.T) {
+	sqlStore := openAppTestStore(t)
+	ctx := context.Background()
+	contextRecord, err := sqlStore.EnsureContextForExternalChannel(ctx, "telegram", "100", "community")
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+		ID: "task-watch", WorkspaceID: contextRecord.WorkspaceID, ContextID: contextRecord.ID,
+		Kind: "objective", Title: "Pricing watch", Prompt: "Check the pricing page", Status: "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	publisher := &fakePublisher{}
+	notifier := newTaskCompletionNotifier("", sqlStore, map[string]connectors.Publisher{"telegram": publisher}, "both", "", "", &mockAgentService{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	observer := newTaskObserver(sqlStore, notifier, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	task := orchestrator.Task{
+		ID: "task-watch", WorkspaceID: contextRecord.WorkspaceID, ContextID: contextRecord.ID,
+		Kind: orchestrator.TaskKindObjective, Title: "Pricing watch", Prompt: "Check the pricing page",
+	}
+	observer.OnTaskStarted(task, 1)
+	observer.OnTaskCompleted(task, 1, orchestrator.TaskResult{Summary: "No changes since 2026-01-01 08:00 UTC.", Unchanged: true})
+
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+	if len(publisher.messages) != 0 {
+		t.Fatalf("expected no notice for an unchanged run, got %+v", publisher.messages)
+	}
+	record, err := sqlStore.LookupTask(ctx, "task-watch")
+	if err != nil || record.ResultSummary != "No changes since 2026-01-01 08:00 UTC." {
+		t.Fatalf("expected the unchanged summary stored, got %+v (%v)", record, err)
+	}
+}
+
+func TestTaskProgressIsStoredAndPostedToOrigin(t *testing.T) {
+	sqlStore := openAppTestStore(t)
+	ctx := context.Background()
+	contextRecord, err := sqlStore.EnsureContextForExternalChannel(ctx, "telegram", "100", "community")
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+		ID:          "task-p1",
+		WorkspaceID: contextRecord.WorkspaceID,
+		ContextID:   contextRecord.ID,
+		Kind:        "general",
+		Title:       "Market report",
+		Prompt:      "Write the report",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	publisher := &fakePublisher{}
+	notifier := newTaskCompletionNotifier("", sqlStore, map[string]connectors.Publisher{"telegram": publisher}, "admin", "", "", &mockAgentService{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	notifier.SetProgressNotices(time.Millisecond)
+	observer := newTaskObserver(sqlStore, notifier, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	task := orchestrator.Task{
+		ID:          "task-p1",
+		WorkspaceID: contextRecord.WorkspaceID,
+		ContextID:   contextRecord.ID,
+		Kind:        orchestrator.TaskKindGeneral,
+		Title:       "Market report",
+		CreatedAt:   time.Now().UTC(),
+	}
+	observer.OnTaskStarted(task, 1)
+	observer.OnTaskProgress(task, 1, orchestrator.TaskProgress{Percent: 20, Step: "step 2/5: collect prices"})
+	time.Sleep(5 * time.Millisecond)
+	observer.OnTaskProgress(task, 1, orchestrator.TaskProgress{Percent: 40, Step: "step 3/5: compare vendors"})
+
+	record, err := sqlStore.LookupTask(ctx, "task-p1")
+	if err != nil {
+		t.Fatalf("lookup task: %v", err)
+	}
+	if record.ProgressPercent != 40 || record.ProgressStep != "step 3/5: compare vendors" {
+		t.Fatalf("expected the latest progress stored, got %d %q", record.ProgressPercent, record.ProgressStep)
+	}
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+	if len(publisher.messages) != 1 || publisher.messages[0].externalID != "100" {
+		t.Fatalf("expected one progress notice to the origin, got %+v", publisher.messages)
+	}
+	if want := `Still working on "Market report": step 3/5: compare vendors (40%).`; publisher.messages[0].text != want {
+		t.Fatalf("expected %q, got %q", want, publisher.messages[0].text)
+	}
+}
+
 func TestTaskCompletionNotificationAppendsOutboundChatLog(t *testing.T) {
 	workspaceRoot := t.TempDir()
 	sqlStore := openAppTestStore(t)
@@ -740,3 +829,80 @@ func openAppTestStore(t *testing.T) *store.Store {
 	}
 	return sqlStore
 }
+
+func TestTaskCompletionNotificationPresentsArtifacts(t *testing.T) {
+	sqlStore := openAppTestStore(t)
+	ctx := context.Background()
+	workspaceRoot := t.TempDir()
+	contextRecord, err := sqlStore.EnsureContextForExternalChannel(ctx, "telegram", "100", "community")
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	task := orchestrator.Task{
+		ID:          "task-a1",
+		WorkspaceID: contextRecord.WorkspaceID,
+		ContextID:   contextRecord.ID,
+		Kind:        orchestrator.TaskKindGeneral,
+		Title:       "Vendor research",
+		CreatedAt:   time.Now().UTC(),
+	}
+	workspaceDir := filepath.Join(workspaceRoot, contextRecord.WorkspaceID)
+	for relativePath, content := range map[string]string{
+		"tasks/2026/10/17/task-a1.md": "# Task Result\n\ncompared three vendors\n",
+		"scratch/vendors.csv":         "vendor,price\nacme,10\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(workspaceDir, relativePath)), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(workspaceDir, relativePath), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", relativePath, err)
+		}
+	}
+	artifacts := collectTaskArtifacts(workspaceRoot, task, "tasks/2026/10/17/task-a1.md", []agent.ToolCall{
+		{ToolName: "write_file", ToolArgs: `{"path":"vendors.csv","content":"..."}`, Status: "succeeded"},
+		{ToolName: "write_file", ToolArgs: `{"path":"draft.txt","content":"..."}`, Status: "blocked"},
+		{ToolName: "write_file", ToolArgs: `{"path":"../escape.txt"}`, Status: "succeeded"},
+	})
+	if len(artifacts) != 2 || artifacts[0].Kind != "result" || artifacts[1].Path != "scratch/vendors.csv" || artifacts[1].MediaType != "text/csv; charset=utf-8" || artifacts[1].SizeBytes != 21 {
+		t.Fatalf("unexpected artifacts %+v", artifacts)
+	}
+
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+		ID: task.ID, WorkspaceID: task.WorkspaceID, ContextID: task.ContextID, Kind: "general", Title: task.Title, Prompt: "compare vendors", Status: "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	newNotifier := func(publisher connectors.Publisher, signer *artifactlink.Signer) *taskCompletionNotifier {
+		notifier := newTaskCompletionNotifier(workspaceRoot, sqlStore, map[string]connectors.Publisher{"telegram": publisher}, "both", "", "", &mockAgentService{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		notifier.SetArtifactLinks(signer)
+		return notifier
+	}
+	result := orchestrator.TaskResult{Summary: "compared three vendors", ArtifactPath: artifacts[0].Path, Artifacts: artifacts}
+
+	publisher := &fakeRichPublisher{}
+	observer := newTaskObserver(sqlStore, newNotifier(publisher, nil), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	observer.OnTaskStarted(task, 1)
+	observer.OnTaskCompleted(task, 1, result)
+	if stored, _ := sqlStore.ListTaskArtifacts(ctx, task.ID); len(stored) != 2 {
+		t.Fatalf("expected the artifacts stored, got %+v", stored)
+	}
+	if len(publisher.rich) != 1 || len(publisher.rich[0].Attachments) != 2 {
+		t.Fatalf("expected one notice with both files attached, got %+v", publisher.rich)
+	}
+	if name := publisher.rich[0].Attachments[0].Name; name != "vendors.csv" {
+		t.Fatalf("expected the saved file first, got %q", name)
+	}
+	if !strings.Contains(publisher.rich[0].Text, "Files:\n- vendors.csv (21 B): `scratch/vendors.csv`\n- Vendor research (") {
+		t.Fatalf("unexpected notice %q", publisher.rich[0].Text)
+	}
+
+	// With signed links the notice links the files instead.
+	linked := &fakeRichPublisher{}
+	newNotifier(linked, artifactlink.New("https://bot.example.com", "secret", time.Hour)).NotifyCompleted(task, result)
+	if len(linked.rich) != 0 || len(linked.messages) != 1 {
+		t.Fatalf("expected one plain notice, got %+v %+v", linked.rich, linked.messages)
+	}
+	if !strings.Contains(linked.messages[0].text, "- vendors.csv (21 B): https://bot.example.com/artifacts/download?") {
+		t.Fatalf("unexpected notice %q", linked.messages[0].text)
+	}
+}
@@ -0,0 +1,289 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/outbox"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	// objectiveDigestInterval is how often pending digests are checked for
+	// a period that ended.
+	objectiveDigestInterval = 5 * time.Minute
+	// objectiveDigestDir holds the digest buffers, relative to a workspace.
+	objectiveDigestDir = "digests/pending"
+	// objectiveDigestEntries bounds the runs a digest message lists.
+	objectiveDigestEntries = 50
+)
+
+type objectiveDigestStore interface {
+	LookupObjectiveRunByTask(ctx context.Context, taskID string) (store.ObjectiveRun, error)
+	LookupObjective(ctx context.Context, id string) (store.Objective, error)
+	LookupContextDelivery(ctx context.Context, contextID string) (store.ContextDelivery, error)
+}
+
+type objectiveDigestEntry struct {
+	ObjectiveID string    `json:"objective_id"`
+	Title       string    `json:"title"`
+	TaskID      string    `json:"task_id"`
+	Summary     string    `json:"summary"`
+	At          time.Time `json:"at"`
+}
+
+// objectiveDigests collects the results of digest objectives in one buffer
+// file per context and period under the workspace, digests/pending/
+// <context-id>.<period>.jsonl, and posts each buffer to its context as one
+// message once its UTC day or week is over.
+type objectiveDigests struct {
+	workspaceRoot string
+	store         objectiveDigestStore
+	publishers    map[string]connectors.Publisher
+	logger        *slog.Logger
+	// mu keeps a flush from dropping entries appended while it posts.
+	mu sync.Mutex
+}
+
+func newObjectiveDigests(workspaceRoot string, storeRef objectiveDigestStore, publishers map[string]connectors.Publisher, logger *slog.Logger) *objectiveDigests {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clean := map[string]connectors.Publisher{}
+	for key, publisher := range publishers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || publisher == nil {
+			continue
+		}
+		clean[name] = publisher
+	}
+	return &objectiveDigests{
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		store:         storeRef,
+		publishers:    clean,
+		logger:        logger,
+	}
+}
+
+// buffer adds the result of an objective task to its context's digest. It
+// reports false when the task's objective reports every run, or the entry
+// could not be written, so the caller notifies as usual.
+func (d *objectiveDigests) buffer(ctx context.Context, task orchestrator.Task, result orchestrator.TaskResult) bool {
+	if d == nil || d.workspaceRoot == "" || task.Kind != orchestrator.TaskKindObjective {
+		return false
+	}
+	run, err := d.store.LookupObjectiveRunByTask(ctx, task.ID)
+	if err != nil {
+		return false
+	}
+	objective, err := d.store.LookupObjective(ctx, run.ObjectiveID)
+	if err != nil || objective.DigestPeriod == store.ObjectiveDigestNone {
+		return false
+	}
+	path := d.bufferPath(objective.WorkspaceID, objective.ContextID, objective.DigestPeriod)
+	if path == "" {
+		return false
+	}
+	line, err := json.Marshal(objectiveDigestEntry{
+		ObjectiveID: objective.ID,
+		Title:       objective.Title,
+		TaskID:      task.ID,
+		Summary:     strings.TrimSpace(result.Summary),
+		At:          time.Now().UTC(),
+	})
+	if err != nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := appendDigestLine(path, line); err != nil {
+		d.logger.Error("objective digest append failed", "task_id", task.ID, "objective_id", objective.ID, "error", err)
+		return false
+	}
+	return true
+}
+
+func (d *objectiveDigests) run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = objectiveDigestInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.flush(ctx, time.Now().UTC())
+		}
+	}
+}
+
+// flush posts every digest whose period is over and returns how many were
+// posted. Digests that could not be posted stay buffered for the next try.
+func (d *objectiveDigests) flush(ctx context.Context, now time.Time) int {
+	if d == nil || d.workspaceRoot == "" {
+		return 0
+	}
+	paths, err := filepath.Glob(filepath.Join(d.workspaceRoot, "*", filepath.FromSlash(objectiveDigestDir), "*.jsonl"))
+	if err != nil {
+		d.logger.Error("objective digest listing failed", "error", err)
+		return 0
+	}
+	sort.Strings(paths)
+	posted := 0
+	for _, path := range paths {
+		if d.flushBuffer(ctx, path, now) {
+			posted++
+		}
+	}
+	return posted
+}
+
+func (d *objectiveDigests) flushBuffer(ctx context.Context, path string, now time.Time) bool {
+	name := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	contextID, period, ok := strings.Cut(name, ".")
+	if !ok {
+		return false
+	}
+	workspaceID := filepath.Base(filepath.Dir(filepath.Dir(filepath.Dir(path))))
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entries, err := readDigestEntries(path)
+	if err != nil {
+		d.logger.Error("objective digest read failed", "path", path, "error", err)
+		return false
+	}
+	if len(entries) == 0 {
+		_ = os.Remove(path)
+		return false
+	}
+	if now.Before(digestPeriodEnd(store.ObjectiveDigestPeriod(period), entries[0].At)) {
+		return false
+	}
+	delivery, err := d.store.LookupContextDelivery(ctx, contextID)
+	if errors.Is(err, store.ErrContextNotFound) {
+		d.logger.Warn("objective digest context is gone, dropping digest", "context_id", contextID, "entries", len(entries))
+		_ = os.Remove(path)
+		return false
+	}
+	if err != nil {
+		d.logger.Error("objective digest context lookup failed", "context_id", contextID, "error", err)
+		return false
+	}
+	publisher := d.publishers[strings.ToLower(strings.TrimSpace(delivery.Connector))]
+	if publisher == nil {
+		d.logger.Warn("objective digest connector has no publisher, dropping digest", "context_id", contextID, "connector", delivery.Connector)
+		_ = os.Remove(path)
+		return false
+	}
+	text := buildObjectiveDigest(store.ObjectiveDigestPeriod(period), entries)
+	publishCtx := outbox.WithCollapseKey(ctx, "objective-digest:"+contextID+":"+period)
+	if err := publisher.Publish(publishCtx, delivery.ExternalID, text); err != nil {
+		d.logger.Error("objective digest publish failed", "context_id", contextID, "connector", delivery.Connector, "error", err)
+		return false
+	}
+	appendOutboundChatLog(d.workspaceRoot, workspaceID, delivery.Connector, delivery.ExternalID, text)
+	if err := os.Remove(path); err != nil {
+		d.logger.Error("objective digest cleanup failed", "path", path, "error", err)
+	}
+	return true
+}
+
+// bufferPath returns the digest buffer of a context, or "" for IDs that are
+// not safe in its path. Context IDs may not hold the dot that separates the
+// period in the file name.
+func (d *objectiveDigests) bufferPath(workspaceID, contextID string, period store.ObjectiveDigestPeriod) string {
+	workspaceID = strings.TrimSpace(workspaceID)
+	contextID = strings.TrimSpace(contextID)
+	if workspaceID == "" || workspaceID == "." || workspaceID == ".." || strings.ContainsAny(workspaceID, `/\`) {
+		return ""
+	}
+	if contextID == "" || strings.ContainsAny(contextID, `/\.`) {
+		return ""
+	}
+	return filepath.Join(d.workspaceRoot, workspaceID, filepath.FromSlash(objectiveDigestDir), contextID+"."+string(period)+".jsonl")
+}
+
+// digestPeriodEnd returns when a digest started at first is due: the next
+// UTC midnight for daily digests, the next Monday 00:00 UTC for weekly ones.
+func digestPeriodEnd(period store.ObjectiveDigestPeriod, first time.Time) time.Time {
+	first = first.UTC()
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	if period == store.ObjectiveDigestWeekly {
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, 7-daysSinceMonday)
+	}
+	return day.AddDate(0, 0, 1)
+}
+
+func buildObjectiveDigest(period store.ObjectiveDigestPeriod, entries []objectiveDigestEntry) string {
+	heading := "Daily digest"
+	if period == store.ObjectiveDigestWeekly {
+		heading = "Weekly digest"
+	}
+	lines := []string{fmt.Sprintf("%s: %d objective update(s)", heading, len(entries)), ""}
+	for i, entry := range entries {
+		if i == objectiveDigestEntries {
+			lines = append(lines, fmt.Sprintf("(+%d more)", len(entries)-i))
+			break
+		}
+		summary := truncateSingleLine(entry.Summary, 300)
+		if summary == "" {
+			summary = "Done."
+		}
+		title := strings.TrimSpace(entry.Title)
+		if title == "" {
+			title = "Objective"
+		}
+		lines = append(lines, fmt.Sprintf("- %s (%s): %s", title, entry.At.UTC().Format("Jan 2 15:04"), summary))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func appendDigestLine(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readDigestEntries reads a digest buffer, skipping lines that do not parse.
+func readDigestEntries(path string) ([]objectiveDigestEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	entries := []objectiveDigestEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry objectiveDigestEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.At.IsZero() {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestDigestObjectiveRunsArePostedOncePerPeriod(t *testing.T) {
+	sqlStore := openAppTestStore(t)
+	ctx := context.Background()
+	workspaceRoot := t.TempDir()
+	contextRecord, err := sqlStore.EnsureContextForExternalChannel(ctx, "telegram", "100", "community")
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	objective, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: contextRecord.WorkspaceID, ContextID: contextRecord.ID, Title: "Support queue",
+		Prompt: "Summarize new support tickets", TriggerType: store.ObjectiveTriggerSchedule, CronExpr: "0 * * * *",
+		DigestPeriod: store.ObjectiveDigestDaily,
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	publisher := &fakePublisher{}
+	publishers := map[string]connectors.Publisher{"telegram": publisher}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	digests := newObjectiveDigests(workspaceRoot, sqlStore, publishers, logger)
+	notifier := newTaskCompletionNotifier(workspaceRoot, sqlStore, publishers, "both", "", "", &mockAgentService{}, logger)
+	notifier.SetObjectiveDigests(digests)
+
+	for _, taskID := range []string{"task-d1", "task-d2"} {
+		if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+			ID: taskID, WorkspaceID: contextRecord.WorkspaceID, ContextID: contextRecord.ID,
+			Kind: "objective", Title: "Support queue", Prompt: "Summarize new support tickets", Status: "queued",
+		}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+		if _, err := sqlStore.CreateObjectiveRun(ctx, store.CreateObjectiveRunInput{
+			ObjectiveID: objective.ID, WorkspaceID: objective.WorkspaceID, TaskID: taskID, Trigger: "schedule",
+		}); err != nil {
+			t.Fatalf("create run: %v", err)
+		}
+		task := orchestrator.Task{ID: taskID, WorkspaceID: contextRecord.WorkspaceID, ContextID: contextRecord.ID, Kind: orchestrator.TaskKindObjective, Title: "Support queue"}
+		notifier.NotifyCompleted(task, orchestrator.TaskResult{Summary: "2 new tickets from " + taskID})
+	}
+	publisher.mu.Lock()
+	sent := len(publisher.messages)
+	publisher.mu.Unlock()
+	if sent != 0 {
+		t.Fatalf("expected digest runs buffered instead of posted, got %d messages", sent)
+	}
+	bufferPath := filepath.Join(workspaceRoot, contextRecord.WorkspaceID, "digests", "pending", contextRecord.ID+".daily.jsonl")
+	if _, err := os.Stat(bufferPath); err != nil {
+		t.Fatalf("expected a digest buffer in the workspace: %v", err)
+	}
+
+	now := time.Now().UTC()
+	if posted := digests.flush(ctx, now); posted != 0 {
+		t.Fatalf("expected no digest before the day is over, posted %d", posted)
+	}
+	if posted := digests.flush(ctx, now.Add(24*time.Hour)); posted != 1 {
+		t.Fatalf("expected one digest after the day, posted %d", posted)
+	}
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+	if len(publisher.messages) != 1 || publisher.messages[0].externalID != "100" {
+		t.Fatalf("unexpected digest delivery %+v", publisher.messages)
+	}
+	text := publisher.messages[0].text
+	if !strings.HasPrefix(text, "Daily digest: 2 objective update(s)") || !strings.Contains(text, "2 new tickets from task-d1") || !strings.Contains(text, "2 new tickets from task-d2") {
+		t.Fatalf("unexpected digest %q", text)
+	}
+	if _, err := os.Stat(bufferPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the buffer removed after posting, got %v", err)
+	}
+}
+
+func TestDigestPeriodEnd(t *testing.T) {
+	wednesday := time.Date(2026, 10, 14, 17, 30, 0, 0, time.UTC)
+	if got := digestPeriodEnd(store.ObjectiveDigestDaily, wednesday); !got.Equal(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected daily end %s", got)
+	}
+	if got := digestPeriodEnd(store.ObjectiveDigestWeekly, wednesday); !got.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected weekly end %s", got)
+	}
+	sunday := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+	if got := digestPeriodEnd(store.ObjectiveDigestWeekly, sunday); !got.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected weekly end from sunday %s", got)
+	}
+}
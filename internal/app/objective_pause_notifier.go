@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/outbox"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// objectivePauseNotifier tells a workspace's admin channels that repeated
+// failures paused one of its objectives.
+type objectivePauseNotifier struct {
+	workspaceRoot string
+	store         *store.Store
+	publishers    map[string]connectors.Publisher
+	logger        *slog.Logger
+}
+
+func newObjectivePauseNotifier(
+	workspaceRoot string,
+	storeRef *store.Store,
+	publishers map[string]connectors.Publisher,
+	logger *slog.Logger,
+) *objectivePauseNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clean := map[string]connectors.Publisher{}
+	for key, publisher := range publishers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || publisher == nil {
+			continue
+		}
+		clean[name] = publisher
+	}
+	return &objectivePauseNotifier{
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		store:         storeRef,
+		publishers:    clean,
+		logger:        logger,
+	}
+}
+
+// NotifyObjectiveAutoPaused publishes in the background so the scheduler and
+// task workers do not wait on connectors.
+func (n *objectivePauseNotifier) NotifyObjectiveAutoPaused(ctx context.Context, objective store.Objective, reason, lastError string) {
+	if n == nil || n.store == nil {
+		return
+	}
+	go n.publish(objective, buildObjectiveAutoPausedNotice(objective, reason, lastError))
+}
+
+func (n *objectivePauseNotifier) publish(objective store.Objective, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	targets, err := n.store.ListWorkspaceAdminDeliveries(ctx, objective.WorkspaceID, 50)
+	if err != nil {
+		n.logger.Error("list workspace admin deliveries failed", "workspace_id", objective.WorkspaceID, "error", err)
+		return
+	}
+	for _, target := range targets {
+		connector := strings.ToLower(strings.TrimSpace(target.Connector))
+		publisher := n.publishers[connector]
+		if publisher == nil {
+			continue
+		}
+		publishCtx, publishCancel := context.WithTimeout(outbox.WithCollapseKey(ctx, "objective-paused:"+objective.ID), 10*time.Second)
+		err := publisher.Publish(publishCtx, target.ExternalID, text)
+		publishCancel()
+		if err != nil {
+			n.logger.Error("publish objective pause notice failed",
+				"objective_id", objective.ID,
+				"connector", connector,
+				"external_id", target.ExternalID,
+				"error", err,
+			)
+			continue
+		}
+		appendOutboundChatLog(n.workspaceRoot, target.WorkspaceID, target.Connector, target.ExternalID, text)
+	}
+}
+
+func buildObjectiveAutoPausedNotice(objective store.Objective, reason, lastError string) string {
+	title := strings.TrimSpace(objective.Title)
+	if title == "" {
+		title = objective.ID
+	}
+	lines := []string{
+		"Objective paused",
+		fmt.Sprintf("- objective: %s (`%s`)", title, objective.ID),
+		"- reason: " + strings.TrimSpace(reason),
+	}
+	if lastError = strings.TrimSpace(lastError); lastError != "" {
+		lines = append(lines, "- last error: "+truncateSingleLine(lastError, 200))
+	}
+	lines = append(lines, "Check its run history (`agent-runtime admin objectives runs "+objective.ID+"`) and resume it once fixed.")
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/outbox"
+	"github.com/dwizi/agent-runtime/internal/quota"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// quotaNotifier tells a workspace's admin channels that it hit a quota.
+type quotaNotifier struct {
+	workspaceRoot string
+	store         *store.Store
+	publishers    map[string]connectors.Publisher
+	logger        *slog.Logger
+}
+
+func newQuotaNotifier(
+	workspaceRoot string,
+	storeRef *store.Store,
+	publishers map[string]connectors.Publisher,
+	logger *slog.Logger,
+) *quotaNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clean := map[string]connectors.Publisher{}
+	for key, publisher := range publishers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || publisher == nil {
+			continue
+		}
+		clean[name] = publisher
+	}
+	return &quotaNotifier{
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		store:         storeRef,
+		publishers:    clean,
+		logger:        logger,
+	}
+}
+
+// NotifyQuotaExceeded publishes in the background; quota checks run inside
+// task and action creation and must not wait on connectors.
+func (n *quotaNotifier) NotifyQuotaExceeded(ctx context.Context, exceeded *quota.ExceededError) {
+	if n == nil || n.store == nil || exceeded == nil {
+		return
+	}
+	n.logger.Warn("workspace quota exceeded",
+		"workspace_id", exceeded.WorkspaceID,
+		"resource", exceeded.Resource,
+		"limit", exceeded.Limit,
+		"used", exceeded.Used,
+	)
+	go n.publish(exceeded)
+}
+
+func (n *quotaNotifier) publish(exceeded *quota.ExceededError) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	targets, err := n.store.ListWorkspaceAdminDeliveries(ctx, exceeded.WorkspaceID, 50)
+	if err != nil {
+		n.logger.Error("list workspace admin deliveries failed", "workspace_id", exceeded.WorkspaceID, "error", err)
+		return
+	}
+	text := buildQuotaExceededNotice(exceeded)
+	for _, target := range targets {
+		connector := strings.ToLower(strings.TrimSpace(target.Connector))
+		publisher := n.publishers[connector]
+		if publisher == nil {
+			continue
+		}
+		publishCtx, publishCancel := context.WithTimeout(outbox.WithCollapseKey(ctx, "quota:"+exceeded.WorkspaceID+":"+string(exceeded.Resource)), 10*time.Second)
+		err := publisher.Publish(publishCtx, target.ExternalID, text)
+		publishCancel()
+		if err != nil {
+			n.logger.Error("publish quota notice failed",
+				"workspace_id", exceeded.WorkspaceID,
+				"connector", connector,
+				"external_id", target.ExternalID,
+				"error", err,
+			)
+			continue
+		}
+		appendOutboundChatLog(n.workspaceRoot, target.WorkspaceID, target.Connector, target.ExternalID, text)
+	}
+}
+
+func buildQuotaExceededNotice(exceeded *quota.ExceededError) string {
+	return fmt.Sprintf(
+		"Quota reached\n- workspace: `%s`\n- %s\nNew work of this kind is refused until the quota period resets or an admin raises the limit (`POST /api/v1/quotas`).",
+		exceeded.WorkspaceID,
+		exceeded.Error(),
+	)
+}
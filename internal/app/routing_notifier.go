@@ -9,6 +9,7 @@ import (
 
 	"github.com/dwizi/agent-runtime/internal/connectors"
 	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/outbox"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -55,15 +56,20 @@ func (n *routingNotifier) NotifyRoutingDecision(ctx context.Context, decision ga
 	if workspaceID == "" {
 		return
 	}
+	n.notifyWorkspaceAdmins(ctx, workspaceID, buildRoutingDecisionNotice(decision), "")
+}
+
+// notifyWorkspaceAdmins posts text to every admin channel of the workspace.
+// A non-empty collapseKey lets the outbox drop an older undelivered copy.
+func (n *routingNotifier) notifyWorkspaceAdmins(ctx context.Context, workspaceID, text, collapseKey string) {
 	targets, err := n.store.ListWorkspaceAdminDeliveries(ctx, workspaceID, 50)
 	if err != nil {
 		n.logger.Error("list workspace admin deliveries failed", "workspace_id", workspaceID, "error", err)
 		return
 	}
-	if len(targets) == 0 {
-		return
+	if collapseKey != "" {
+		ctx = outbox.WithCollapseKey(ctx, collapseKey)
 	}
-	decisionText := buildRoutingDecisionNotice(decision)
 	for _, target := range targets {
 		connector := strings.ToLower(strings.TrimSpace(target.Connector))
 		publisher := n.publishers[connector]
@@ -71,10 +77,10 @@ func (n *routingNotifier) NotifyRoutingDecision(ctx context.Context, decision ga
 			continue
 		}
 		publishCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-		err := publisher.Publish(publishCtx, target.ExternalID, decisionText)
+		err := publisher.Publish(publishCtx, target.ExternalID, text)
 		cancel()
 		if err != nil {
-			n.logger.Error("publish triage routing notice failed",
+			n.logger.Error("publish admin notice failed",
 				"workspace_id", workspaceID,
 				"connector", connector,
 				"external_id", target.ExternalID,
@@ -82,11 +88,14 @@ func (n *routingNotifier) NotifyRoutingDecision(ctx context.Context, decision ga
 			)
 			continue
 		}
-		appendOutboundChatLog(n.workspaceRoot, target.WorkspaceID, target.Connector, target.ExternalID, decisionText)
+		appendOutboundChatLog(n.workspaceRoot, target.WorkspaceID, target.Connector, target.ExternalID, text)
 	}
 }
 
 func buildRoutingDecisionNotice(decision gateway.RouteDecision) string {
+	if strings.TrimSpace(decision.TaskID) == "" {
+		return buildHeldMessageNotice(decision)
+	}
 	builder := strings.Builder{}
 	builder.WriteString("Routing decision")
 	builder.WriteString("\n- task: `")
@@ -110,6 +119,16 @@ func buildRoutingDecisionNotice(decision gateway.RouteDecision) string {
 		builder.WriteString("\n- preview: ")
 		builder.WriteString(snippet)
 	}
+	if caseID := strings.TrimSpace(decision.CaseID); caseID != "" {
+		builder.WriteString("\n- moderation case: `")
+		builder.WriteString(caseID)
+		builder.WriteString("`")
+	}
+	if caseID := strings.TrimSpace(decision.TrackingCaseID); caseID != "" {
+		builder.WriteString("\n- tracking case: `")
+		builder.WriteString(caseID)
+		builder.WriteString("`")
+	}
 	builder.WriteString("\n\nOverride examples:")
 	builder.WriteString(fmt.Sprintf("\n- `/route %s moderation p1 2h`", decision.TaskID))
 	builder.WriteString(fmt.Sprintf("\n- `/route %s issue p2 8h`", decision.TaskID))
@@ -117,3 +136,37 @@ func buildRoutingDecisionNotice(decision gateway.RouteDecision) string {
 	builder.WriteString(fmt.Sprintf("\n- `/route %s noise p3`", decision.TaskID))
 	return compactLineBreaks(builder.String(), 1600)
 }
+
+// buildHeldMessageNotice describes a message that was held for moderation
+// without creating a task, such as one flagged by the spam filter.
+func buildHeldMessageNotice(decision gateway.RouteDecision) string {
+	builder := strings.Builder{}
+	builder.WriteString("Message held for moderation")
+	builder.WriteString("\n- from: `")
+	builder.WriteString(strings.TrimSpace(decision.SourceConnector))
+	builder.WriteString(" user ")
+	builder.WriteString(strings.TrimSpace(decision.SourceUserID))
+	builder.WriteString("` in `")
+	builder.WriteString(strings.TrimSpace(decision.SourceExternalID))
+	builder.WriteString("`")
+	if reason := strings.TrimSpace(decision.Reason); reason != "" {
+		builder.WriteString("\n- reason: ")
+		builder.WriteString(reason)
+	}
+	if snippet := truncateSingleLine(decision.SourceText, 220); snippet != "" {
+		builder.WriteString("\n- preview: ")
+		builder.WriteString(snippet)
+	}
+	if caseID := strings.TrimSpace(decision.CaseID); caseID != "" {
+		builder.WriteString("\n- case: `")
+		builder.WriteString(caseID)
+		builder.WriteString("` (the user can `/appeal`)")
+	}
+	if caseID := strings.TrimSpace(decision.TrackingCaseID); caseID != "" {
+		builder.WriteString("\n- tracking case: `")
+		builder.WriteString(caseID)
+		builder.WriteString("`")
+	}
+	builder.WriteString("\n\nThe message was not answered or routed to the agent.")
+	return compactLineBreaks(builder.String(), 1600)
+}
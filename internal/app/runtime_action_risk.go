@@ -0,0 +1,254 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/actions/plugins/sandbox"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// destructiveCommands change or remove things outside what the action asked
+// to read; shellCommands run whatever script they are handed.
+var destructiveCommands = map[string]struct{}{
+	"rm": {}, "rmdir": {}, "dd": {}, "mkfs": {}, "shred": {}, "truncate": {},
+	"chmod": {}, "chown": {}, "kill": {}, "pkill": {}, "killall": {},
+	"shutdown": {}, "reboot": {}, "sudo": {}, "su": {},
+}
+
+var shellCommands = map[string]struct{}{
+	"sh": {}, "bash": {}, "zsh": {}, "dash": {}, "python": {}, "python3": {}, "node": {}, "perl": {}, "ruby": {},
+}
+
+var actionRiskLevelPattern = regexp.MustCompile(`(?i)\b(low|medium|high)\b`)
+
+// actionRiskClassifier rates new action approvals from their command,
+// target domains and payload size. With a responder it also asks a model and
+// keeps the higher of the two ratings, so the model can raise a rating but
+// never lower what the heuristics found.
+type actionRiskClassifier struct {
+	trustedDomains map[string]struct{}
+	largePayload   int
+	responder      llm.Responder
+	timeout        time.Duration
+	logger         *slog.Logger
+}
+
+func newActionRiskClassifier(trustedDomains string, largePayloadBytes int, responder llm.Responder, logger *slog.Logger) *actionRiskClassifier {
+	if largePayloadBytes < 1 {
+		largePayloadBytes = 64 * 1024
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	classifier := &actionRiskClassifier{
+		trustedDomains: map[string]struct{}{},
+		largePayload:   largePayloadBytes,
+		responder:      responder,
+		timeout:        15 * time.Second,
+		logger:         logger,
+	}
+	for _, domain := range parseCSVTrimList(trustedDomains) {
+		classifier.trustedDomains[strings.ToLower(strings.TrimPrefix(domain, "@"))] = struct{}{}
+	}
+	return classifier
+}
+
+func (c *actionRiskClassifier) ClassifyActionRisk(ctx context.Context, record store.ActionApproval) (string, string) {
+	level, reasons := c.heuristicRisk(record)
+	if c.responder != nil {
+		modelLevel, modelReason, err := c.modelRisk(ctx, record)
+		if err != nil {
+			c.logger.Warn("model risk classification failed", "action_type", record.ActionType, "error", err)
+		} else if store.ActionRiskRank(modelLevel) > store.ActionRiskRank(level) {
+			level = modelLevel
+			reasons = append(reasons, "model: "+modelReason)
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no risky command, target or payload found")
+	}
+	return level, strings.Join(reasons, "; ")
+}
+
+// heuristicRisk returns the highest level any rule assigns, with the reasons
+// of every rule that fired.
+func (c *actionRiskClassifier) heuristicRisk(record store.ActionApproval) (string, []string) {
+	level := store.ActionRiskLow
+	reasons := []string{}
+	raise := func(to, reason string) {
+		if store.ActionRiskRank(to) > store.ActionRiskRank(level) {
+			level = to
+		}
+		reasons = append(reasons, reason)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(record.ActionType)) {
+	case "run_command", "shell_command", "cli_command":
+		if command, args, err := sandbox.ParseCommand(record); err == nil {
+			command = strings.ToLower(command)
+			if _, ok := destructiveCommands[command]; ok {
+				raise(store.ActionRiskHigh, "destructive command "+command)
+			}
+			if _, ok := shellCommands[command]; ok && len(args) > 0 {
+				raise(store.ActionRiskHigh, command+" runs an inline script")
+			}
+		}
+		if commandUsesNetwork(record) {
+			raise(store.ActionRiskMedium, "command uses the network")
+		}
+		if write, ok := record.Payload["write"].(bool); ok && write {
+			raise(store.ActionRiskMedium, "command may write to the workspace")
+		}
+	case "send_email", "smtp_email", "email":
+		external := c.untrustedRecipients(record)
+		switch {
+		case len(external) > 10:
+			raise(store.ActionRiskHigh, fmt.Sprintf("email to %d external recipients", len(external)))
+		case len(external) > 0:
+			raise(store.ActionRiskMedium, "email to external recipients")
+		}
+	}
+
+	for _, host := range actionHosts(record) {
+		if isPrivateHost(host) {
+			raise(store.ActionRiskHigh, "targets private address "+host)
+			continue
+		}
+		if !c.trustedHost(host) {
+			raise(store.ActionRiskMedium, "targets external domain "+host)
+		}
+	}
+
+	if encoded, err := json.Marshal(record.Payload); err == nil {
+		switch size := len(encoded); {
+		case size > 4*c.largePayload:
+			raise(store.ActionRiskHigh, fmt.Sprintf("payload of %d bytes", size))
+		case size > c.largePayload:
+			raise(store.ActionRiskMedium, fmt.Sprintf("payload of %d bytes", size))
+		}
+	}
+	return level, reasons
+}
+
+func (c *actionRiskClassifier) modelRisk(ctx context.Context, record store.ActionApproval) (string, string, error) {
+	payload, _ := json.Marshal(record.Payload)
+	lines := []string{
+		"Rate the risk of letting an assistant run this action: low, medium or high.",
+		"High means it can destroy data, leak private information, spend money or reach untrusted systems.",
+		"Reply with the level, then a short reason on the same line.",
+		"",
+		"Type: " + record.ActionType,
+		"Target: " + record.ActionTarget,
+		"Summary: " + record.ActionSummary,
+		"Payload: " + clipRiskText(string(payload), 2000),
+	}
+	modelCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	reply, err := c.responder.Reply(modelCtx, llm.MessageInput{
+		WorkspaceID:   record.WorkspaceID,
+		Text:          strings.Join(lines, "\n"),
+		SystemPrompt:  "You rate the risk of automated actions. Answer with low, medium or high and one short reason.",
+		SkipGrounding: true,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return parseActionRiskReply(reply)
+}
+
+func parseActionRiskReply(reply string) (string, string, error) {
+	reply = strings.TrimSpace(reply)
+	match := actionRiskLevelPattern.FindStringIndex(reply)
+	if match == nil {
+		return "", "", fmt.Errorf("risk reply named no level")
+	}
+	level := strings.ToLower(reply[match[0]:match[1]])
+	reason := strings.Trim(strings.TrimSpace(reply[match[1]:]), ":-–.,; ")
+	if line, _, ok := strings.Cut(reason, "\n"); ok {
+		reason = strings.TrimSpace(line)
+	}
+	if reason == "" {
+		reason = "rated " + level
+	}
+	return level, clipRiskText(reason, 160), nil
+}
+
+func (c *actionRiskClassifier) untrustedRecipients(record store.ActionApproval) []string {
+	recipients := approvalRecipients(record.ActionTarget)
+	for _, key := range []string{"to", "cc", "bcc"} {
+		recipients = append(recipients, approvalRecipients(record.Payload[key])...)
+	}
+	external := []string{}
+	for _, recipient := range recipients {
+		at := strings.LastIndex(recipient, "@")
+		if at < 0 {
+			continue
+		}
+		domain := strings.ToLower(strings.TrimRight(recipient[at+1:], "> "))
+		if !c.trustedHost(domain) {
+			external = append(external, recipient)
+		}
+	}
+	return external
+}
+
+// trustedHost matches a host against the trusted domains and their
+// subdomains.
+func (c *actionRiskClassifier) trustedHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for domain := range c.trustedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// actionHosts collects the hosts of URLs in the target and the usual payload
+// fields.
+func actionHosts(record store.ActionApproval) []string {
+	candidates := []string{record.ActionTarget}
+	for _, key := range []string{"url", "endpoint", "webhook_url"} {
+		if value, ok := record.Payload[key].(string); ok {
+			candidates = append(candidates, value)
+		}
+	}
+	hosts := []string{}
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		parsed, err := url.Parse(strings.TrimSpace(candidate))
+		if err != nil || parsed.Hostname() == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func isPrivateHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified())
+}
+
+func clipRiskText(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit] + "..."
+}
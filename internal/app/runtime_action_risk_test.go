@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type stubRiskResponder struct {
+	reply string
+	err   error
+	calls int
+}
+
+func (s *stubRiskResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	s.calls++
+	return s.reply, s.err
+}
+
+func TestActionRiskClassifierHeuristics(t *testing.T) {
+	classifier := newActionRiskClassifier("example.com", 1024, nil, nil)
+	cases := []struct {
+		name   string
+		record store.ActionApproval
+		level  string
+		reason string
+	}{
+		{
+			name:   "read-only command",
+			record: store.ActionApproval{ActionType: "run_command", ActionTarget: "ls", Payload: map[string]any{"args": []any{"-la"}}},
+			level:  store.ActionRiskLow,
+		},
+		{
+			name:   "destructive command",
+			record: store.ActionApproval{ActionType: "run_command", ActionTarget: "rm", Payload: map[string]any{"args": []any{"-rf", "data"}}},
+			level:  store.ActionRiskHigh,
+			reason: "destructive command rm",
+		},
+		{
+			name:   "networked command",
+			record: store.ActionApproval{ActionType: "run_command", ActionTarget: "curl", Payload: map[string]any{"args": []any{"https://api.example.com"}}},
+			level:  store.ActionRiskMedium,
+			reason: "command uses the network",
+		},
+		{
+			name:   "internal email",
+			record: store.ActionApproval{ActionType: "send_email", ActionTarget: "ops@example.com"},
+			level:  store.ActionRiskLow,
+		},
+		{
+			name:   "external email",
+			record: store.ActionApproval{ActionType: "send_email", ActionTarget: "someone@other.org"},
+			level:  store.ActionRiskMedium,
+			reason: "external recipients",
+		},
+		{
+			name:   "trusted subdomain",
+			record: store.ActionApproval{ActionType: "fetch_url", ActionTarget: "https://docs.example.com/page"},
+			level:  store.ActionRiskLow,
+		},
+		{
+			name:   "private address",
+			record: store.ActionApproval{ActionType: "fetch_url", ActionTarget: "http://169.254.169.254/latest/meta-data"},
+			level:  store.ActionRiskHigh,
+			reason: "private address",
+		},
+		{
+			name:   "large payload",
+			record: store.ActionApproval{ActionType: "create_issue", Payload: map[string]any{"body": strings.Repeat("x", 2048)}},
+			level:  store.ActionRiskMedium,
+			reason: "payload of",
+		},
+	}
+	for _, tc := range cases {
+		level, reason := classifier.ClassifyActionRisk(context.Background(), tc.record)
+		if level != tc.level || !strings.Contains(reason, tc.reason) {
+			t.Fatalf("%s: expected %s (%q), got %s (%q)", tc.name, tc.level, tc.reason, level, reason)
+		}
+	}
+}
+
+func TestActionRiskClassifierModelOnlyRaisesRating(t *testing.T) {
+	destructive := store.ActionApproval{ActionType: "run_command", ActionTarget: "rm", Payload: map[string]any{"args": []any{"old.log"}}}
+	benign := store.ActionApproval{ActionType: "create_issue", ActionSummary: "post all customer emails publicly"}
+
+	lenient := &stubRiskResponder{reply: "low - looks fine"}
+	if level, _ := newActionRiskClassifier("", 0, lenient, nil).ClassifyActionRisk(context.Background(), destructive); level != store.ActionRiskHigh {
+		t.Fatalf("expected the model not to lower a high rating, got %s", level)
+	}
+	strict := &stubRiskResponder{reply: "High: leaks customer data."}
+	level, reason := newActionRiskClassifier("", 0, strict, nil).ClassifyActionRisk(context.Background(), benign)
+	if level != store.ActionRiskHigh || !strings.Contains(reason, "model: leaks customer data") {
+		t.Fatalf("expected the model to raise the rating, got %s (%q)", level, reason)
+	}
+	failing := &stubRiskResponder{err: errors.New("timeout")}
+	if level, _ := newActionRiskClassifier("", 0, failing, nil).ClassifyActionRisk(context.Background(), benign); level != store.ActionRiskLow || failing.calls != 1 {
+		t.Fatalf("expected heuristics when the model fails, got %s", level)
+	}
+}
+
+func TestParseActionRiskReply(t *testing.T) {
+	if _, _, err := parseActionRiskReply("not sure"); err == nil {
+		t.Fatal("expected a reply without a level to fail")
+	}
+	level, reason, err := parseActionRiskReply("Medium. Sends data to a third party\nextra")
+	if err != nil || level != store.ActionRiskMedium || reason != "Sends data to a third party" {
+		t.Fatalf("unexpected parse %q %q %v", level, reason, err)
+	}
+}
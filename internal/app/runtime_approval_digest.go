@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// approvalDigestListLimit caps each list a digest reads; a digest that
+// reaches it says so instead of paging.
+const approvalDigestListLimit = 200
+
+type approvalDigestStore interface {
+	ListAdminDeliveries(ctx context.Context, limit int) ([]store.ContextDelivery, error)
+	ListPendingActionApprovalsGlobal(ctx context.Context, limit int) ([]store.ActionApproval, error)
+	ListTasks(ctx context.Context, input store.ListTasksInput) ([]store.TaskRecord, error)
+	ListObjectives(ctx context.Context, input store.ListObjectivesInput) ([]store.Objective, error)
+}
+
+type workspaceAdminNotifier interface {
+	notifyWorkspaceAdmins(ctx context.Context, workspaceID, text, collapseKey string)
+}
+
+// approvalDigest periodically posts what needs an admin's attention to each
+// workspace's admin channels: pending approvals, tasks that stopped moving
+// and objectives that keep failing. Workspaces with nothing to report are
+// skipped.
+type approvalDigest struct {
+	store      approvalDigestStore
+	notifier   workspaceAdminNotifier
+	stuckAfter time.Duration
+	logger     *slog.Logger
+}
+
+type workspaceDigest struct {
+	approvals  []store.ActionApproval
+	stuck      []store.TaskRecord
+	objectives []store.Objective
+}
+
+func newApprovalDigest(storeRef approvalDigestStore, notifier workspaceAdminNotifier, stuckAfter time.Duration, logger *slog.Logger) *approvalDigest {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if stuckAfter <= 0 {
+		stuckAfter = time.Hour
+	}
+	return &approvalDigest{store: storeRef, notifier: notifier, stuckAfter: stuckAfter, logger: logger}
+}
+
+func (d *approvalDigest) run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = 4 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.post(ctx, time.Now().UTC())
+		}
+	}
+}
+
+func (d *approvalDigest) post(ctx context.Context, now time.Time) int {
+	digests, err := d.collect(ctx, now)
+	if err != nil {
+		d.logger.Error("approval digest failed", "error", err)
+		return 0
+	}
+	workspaceIDs := make([]string, 0, len(digests))
+	for workspaceID := range digests {
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+	sort.Strings(workspaceIDs)
+	for _, workspaceID := range workspaceIDs {
+		text := buildApprovalDigest(digests[workspaceID], now)
+		d.notifier.notifyWorkspaceAdmins(ctx, workspaceID, text, "approval-digest:"+workspaceID)
+	}
+	return len(workspaceIDs)
+}
+
+// collect builds a digest for every workspace that has an admin channel and
+// something to report.
+func (d *approvalDigest) collect(ctx context.Context, now time.Time) (map[string]*workspaceDigest, error) {
+	deliveries, err := d.store.ListAdminDeliveries(ctx, approvalDigestListLimit)
+	if err != nil {
+		return nil, err
+	}
+	digests := map[string]*workspaceDigest{}
+	for _, delivery := range deliveries {
+		if workspaceID := strings.TrimSpace(delivery.WorkspaceID); workspaceID != "" {
+			digests[workspaceID] = &workspaceDigest{}
+		}
+	}
+	approvals, err := d.store.ListPendingActionApprovalsGlobal(ctx, approvalDigestListLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, approval := range approvals {
+		if digest := digests[approval.WorkspaceID]; digest != nil {
+			digest.approvals = append(digest.approvals, approval)
+		}
+	}
+	for workspaceID, digest := range digests {
+		for _, status := range []string{"running", "queued"} {
+			tasks, err := d.store.ListTasks(ctx, store.ListTasksInput{WorkspaceID: workspaceID, Status: status, Limit: approvalDigestListLimit})
+			if err != nil {
+				return nil, err
+			}
+			for _, task := range tasks {
+				if d.isStuck(task, now) {
+					digest.stuck = append(digest.stuck, task)
+				}
+			}
+		}
+		objectives, err := d.store.ListObjectives(ctx, store.ListObjectivesInput{WorkspaceID: workspaceID, Limit: approvalDigestListLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, objective := range objectives {
+			if objective.ConsecutiveFailures > 0 || objective.AutoPausedReason != "" {
+				digest.objectives = append(digest.objectives, objective)
+			}
+		}
+		if len(digest.approvals) == 0 && len(digest.stuck) == 0 && len(digest.objectives) == 0 {
+			delete(digests, workspaceID)
+		}
+	}
+	return digests, nil
+}
+
+// isStuck reports running tasks that started, and queued tasks that were
+// created, longer than stuckAfter ago.
+func (d *approvalDigest) isStuck(task store.TaskRecord, now time.Time) bool {
+	since := task.CreatedAt
+	if task.Status == "running" && !task.StartedAt.IsZero() {
+		since = task.StartedAt
+	}
+	return !since.IsZero() && now.Sub(since) >= d.stuckAfter
+}
+
+func buildApprovalDigest(digest *workspaceDigest, now time.Time) string {
+	lines := []string{"Admin digest"}
+	if len(digest.approvals) > 0 {
+		lines = append(lines, fmt.Sprintf("Pending approvals: %d", len(digest.approvals)))
+		for _, index := range limitDigestItems(len(digest.approvals)) {
+			item := digest.approvals[index]
+			line := fmt.Sprintf("- `%s` %s, waiting %s", item.ID, item.ActionType, formatDigestAge(now.Sub(item.CreatedAt)))
+			if item.RiskLevel != "" {
+				line += ", " + item.RiskLevel + " risk"
+			}
+			lines = append(lines, line)
+		}
+	}
+	if len(digest.stuck) > 0 {
+		lines = append(lines, fmt.Sprintf("Stuck tasks: %d", len(digest.stuck)))
+		for _, index := range limitDigestItems(len(digest.stuck)) {
+			task := digest.stuck[index]
+			since := task.CreatedAt
+			if task.Status == "running" && !task.StartedAt.IsZero() {
+				since = task.StartedAt
+			}
+			lines = append(lines, fmt.Sprintf("- `%s` %s for %s: %s", task.ID, task.Status, formatDigestAge(now.Sub(since)), truncateSingleLine(task.Title, 80)))
+		}
+	}
+	if len(digest.objectives) > 0 {
+		lines = append(lines, fmt.Sprintf("Failing objectives: %d", len(digest.objectives)))
+		for _, index := range limitDigestItems(len(digest.objectives)) {
+			objective := digest.objectives[index]
+			line := fmt.Sprintf("- `%s` %s", objective.ID, truncateSingleLine(objective.Title, 80))
+			if objective.AutoPausedReason != "" {
+				line += ", paused: " + truncateSingleLine(objective.AutoPausedReason, 120)
+			} else {
+				line += fmt.Sprintf(", %d failures in a row: %s", objective.ConsecutiveFailures, truncateSingleLine(objective.LastError, 120))
+			}
+			lines = append(lines, line)
+		}
+	}
+	lines = append(lines, "Review approvals with `/pending-actions`; retry stuck tasks with `POST /api/v1/tasks/retry`.")
+	return strings.Join(lines, "\n")
+}
+
+// limitDigestItems returns the indexes of the first items to list; the
+// counts above them cover the rest.
+func limitDigestItems(count int) []int {
+	if count > 10 {
+		count = 10
+	}
+	indexes := make([]int, count)
+	for index := range indexes {
+		indexes[index] = index
+	}
+	return indexes
+}
+
+func formatDigestAge(age time.Duration) string {
+	switch {
+	case age >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	case age >= time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	}
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeDigestStore struct {
+	deliveries []store.ContextDelivery
+	approvals  []store.ActionApproval
+	tasks      []store.TaskRecord
+	objectives []store.Objective
+}
+
+func (f *fakeDigestStore) ListAdminDeliveries(ctx context.Context, limit int) ([]store.ContextDelivery, error) {
+	return f.deliveries, nil
+}
+
+func (f *fakeDigestStore) ListPendingActionApprovalsGlobal(ctx context.Context, limit int) ([]store.ActionApproval, error) {
+	return f.approvals, nil
+}
+
+func (f *fakeDigestStore) ListTasks(ctx context.Context, input store.ListTasksInput) ([]store.TaskRecord, error) {
+	tasks := []store.TaskRecord{}
+	for _, task := range f.tasks {
+		if task.WorkspaceID == input.WorkspaceID && task.Status == input.Status {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (f *fakeDigestStore) ListObjectives(ctx context.Context, input store.ListObjectivesInput) ([]store.Objective, error) {
+	objectives := []store.Objective{}
+	for _, objective := range f.objectives {
+		if objective.WorkspaceID == input.WorkspaceID {
+			objectives = append(objectives, objective)
+		}
+	}
+	return objectives, nil
+}
+
+type recordedAdminNotice struct {
+	workspaceID string
+	text        string
+	collapseKey string
+}
+
+type fakeAdminNotifier struct {
+	notices []recordedAdminNotice
+}
+
+func (f *fakeAdminNotifier) notifyWorkspaceAdmins(ctx context.Context, workspaceID, text, collapseKey string) {
+	f.notices = append(f.notices, recordedAdminNotice{workspaceID: workspaceID, text: text, collapseKey: collapseKey})
+}
+
+func TestApprovalDigestSummarizesWorkspacesNeedingAttention(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	fStore := &fakeDigestStore{
+		deliveries: []store.ContextDelivery{
+			{WorkspaceID: "ws-1", Connector: "telegram", ExternalID: "1", IsAdmin: true},
+			{WorkspaceID: "ws-quiet", Connector: "telegram", ExternalID: "2", IsAdmin: true},
+		},
+		approvals: []store.ActionApproval{
+			{ID: "act-1", WorkspaceID: "ws-1", ActionType: "run_command", RiskLevel: "high", CreatedAt: now.Add(-3 * time.Hour)},
+			{ID: "act-2", WorkspaceID: "ws-no-admins", ActionType: "send_email", CreatedAt: now.Add(-time.Hour)},
+		},
+		tasks: []store.TaskRecord{
+			{ID: "task-stuck", WorkspaceID: "ws-1", Status: "running", Title: "Reindex", StartedAt: now.Add(-2 * time.Hour)},
+			{ID: "task-fresh", WorkspaceID: "ws-1", Status: "running", Title: "Reply", StartedAt: now.Add(-10 * time.Minute)},
+			{ID: "task-waiting", WorkspaceID: "ws-1", Status: "queued", Title: "Backlog", CreatedAt: now.Add(-90 * time.Minute)},
+		},
+		objectives: []store.Objective{
+			{ID: "obj-1", WorkspaceID: "ws-1", Title: "Daily report", ConsecutiveFailures: 3, LastError: "timeout"},
+			{ID: "obj-2", WorkspaceID: "ws-1", Title: "Healthy"},
+			{ID: "obj-3", WorkspaceID: "ws-quiet", Title: "Healthy"},
+		},
+	}
+	notifier := &fakeAdminNotifier{}
+	digest := newApprovalDigest(fStore, notifier, time.Hour, nil)
+
+	if posted := digest.post(context.Background(), now); posted != 1 {
+		t.Fatalf("expected one workspace digest, got %d", posted)
+	}
+	notice := notifier.notices[0]
+	if notice.workspaceID != "ws-1" || notice.collapseKey != "approval-digest:ws-1" {
+		t.Fatalf("unexpected notice target %+v", notice)
+	}
+	for _, want := range []string{
+		"Pending approvals: 1", "`act-1` run_command, waiting 3h, high risk",
+		"Stuck tasks: 2", "`task-stuck` running for 2h: Reindex", "`task-waiting` queued for 1h: Backlog",
+		"Failing objectives: 1", "`obj-1` Daily report, 3 failures in a row: timeout",
+	} {
+		if !strings.Contains(notice.text, want) {
+			t.Fatalf("expected %q in digest:\n%s", want, notice.text)
+		}
+	}
+	if strings.Contains(notice.text, "task-fresh") || strings.Contains(notice.text, "obj-2") {
+		t.Fatalf("expected healthy items left out:\n%s", notice.text)
+	}
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/outbox"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const approvalExpiryInterval = time.Minute
+
+// approvalTTLPolicy gives each action type its own approval lifetime, with a
+// runtime-wide default. A zero lifetime means approvals never expire.
+type approvalTTLPolicy struct {
+	fallback time.Duration
+	byType   map[string]time.Duration
+}
+
+// newApprovalTTLPolicy parses overrides written as
+// "run_command=60,send_email=240", in minutes. Malformed entries are skipped.
+func newApprovalTTLPolicy(defaultMinutes int, overrides string) approvalTTLPolicy {
+	policy := approvalTTLPolicy{
+		fallback: time.Duration(defaultMinutes) * time.Minute,
+		byType:   map[string]time.Duration{},
+	}
+	for _, entry := range parseCSVTrimList(overrides) {
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		minutes, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || name == "" || err != nil || minutes < 0 {
+			continue
+		}
+		policy.byType[name] = time.Duration(minutes) * time.Minute
+	}
+	return policy
+}
+
+func (p approvalTTLPolicy) ActionApprovalTTL(actionType string) time.Duration {
+	if ttl, ok := p.byType[strings.ToLower(strings.TrimSpace(actionType))]; ok {
+		return ttl
+	}
+	return p.fallback
+}
+
+type approvalExpiryStore interface {
+	ExpireActionApprovals(ctx context.Context, now time.Time, limit int) ([]store.ActionApproval, error)
+	CreateAgentAuditEvent(ctx context.Context, input store.CreateAgentAuditEventInput) (store.AgentAuditEvent, error)
+}
+
+// approvalExpirySweeper auto-denies approvals nobody decided in time, tells
+// the conversation that asked for them and records an audit event.
+type approvalExpirySweeper struct {
+	workspaceRoot string
+	store         approvalExpiryStore
+	publishers    map[string]connectors.Publisher
+	logger        *slog.Logger
+}
+
+func newApprovalExpirySweeper(workspaceRoot string, storeRef approvalExpiryStore, publishers map[string]connectors.Publisher, logger *slog.Logger) *approvalExpirySweeper {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clean := map[string]connectors.Publisher{}
+	for key, publisher := range publishers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || publisher == nil {
+			continue
+		}
+		clean[name] = publisher
+	}
+	return &approvalExpirySweeper{
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		store:         storeRef,
+		publishers:    clean,
+		logger:        logger,
+	}
+}
+
+func (s *approvalExpirySweeper) run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = approvalExpiryInterval
+	}
+	s.sweep(ctx, time.Now().UTC())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.sweep(ctx, time.Now().UTC())
+		}
+	}
+}
+
+func (s *approvalExpirySweeper) sweep(ctx context.Context, now time.Time) int {
+	expired, err := s.store.ExpireActionApprovals(ctx, now, 100)
+	if err != nil {
+		s.logger.Error("approval expiry failed", "error", err)
+	}
+	for _, approval := range expired {
+		s.logger.Info("action approval expired",
+			"action_id", approval.ID,
+			"action_type", approval.ActionType,
+			"workspace_id", approval.WorkspaceID,
+		)
+		if _, err := s.store.CreateAgentAuditEvent(ctx, store.CreateAgentAuditEventInput{
+			WorkspaceID:  approval.WorkspaceID,
+			ContextID:    approval.ContextID,
+			Connector:    approval.Connector,
+			ExternalID:   approval.ExternalID,
+			SourceUserID: approval.RequesterUserID,
+			EventType:    "action_approval_expired",
+			Stage:        "audit.action_approval_expired",
+			ToolName:     approval.ActionType,
+			Blocked:      true,
+			BlockReason:  approval.DeniedReason,
+			Message:      fmt.Sprintf("action_id=%s", approval.ID),
+		}); err != nil {
+			s.logger.Warn("record approval expiry audit event failed", "action_id", approval.ID, "error", err)
+		}
+		s.notifyRequester(ctx, approval)
+	}
+	return len(expired)
+}
+
+func (s *approvalExpirySweeper) notifyRequester(ctx context.Context, approval store.ActionApproval) {
+	publisher := s.publishers[strings.ToLower(strings.TrimSpace(approval.Connector))]
+	if publisher == nil {
+		return
+	}
+	summary := strings.TrimSpace(approval.ActionSummary)
+	if summary == "" {
+		summary = approval.ActionType
+	}
+	text := fmt.Sprintf(
+		"Action `%s` (%s) was denied automatically: nobody approved it in time. Ask again if it is still needed.",
+		approval.ID,
+		summary,
+	)
+	publishCtx, cancel := context.WithTimeout(outbox.WithCollapseKey(ctx, "approval:"+approval.ID), 10*time.Second)
+	err := publisher.Publish(publishCtx, approval.ExternalID, text)
+	cancel()
+	if err != nil {
+		s.logger.Error("publish approval expiry notice failed",
+			"action_id", approval.ID,
+			"connector", approval.Connector,
+			"external_id", approval.ExternalID,
+			"error", err,
+		)
+		return
+	}
+	appendOutboundChatLog(s.workspaceRoot, approval.WorkspaceID, approval.Connector, approval.ExternalID, text)
+}
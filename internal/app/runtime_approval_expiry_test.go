@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestApprovalTTLPolicyAppliesOverrides(t *testing.T) {
+	policy := newApprovalTTLPolicy(1440, "run_command=60, Send_Email=0, bogus, x=-1")
+	if got := policy.ActionApprovalTTL("run_command"); got != time.Hour {
+		t.Fatalf("expected run_command override, got %s", got)
+	}
+	if got := policy.ActionApprovalTTL("send_email"); got != 0 {
+		t.Fatalf("expected send_email to never expire, got %s", got)
+	}
+	if got := policy.ActionApprovalTTL("browser_page"); got != 24*time.Hour {
+		t.Fatalf("expected default lifetime, got %s", got)
+	}
+	if got := policy.ActionApprovalTTL("x"); got != 24*time.Hour {
+		t.Fatalf("expected negative override to be ignored, got %s", got)
+	}
+}
+
+func TestApprovalExpirySweeperDeniesAndNotifies(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := openAppTestStore(t)
+	sqlStore.SetActionApprovalTTLPolicy(newApprovalTTLPolicy(30, ""))
+	approval, err := sqlStore.CreateActionApproval(ctx, store.CreateActionApprovalInput{
+		WorkspaceID:     "ws-1",
+		ContextID:       "ctx-1",
+		Connector:       "telegram",
+		ExternalID:      "42",
+		RequesterUserID: "user-1",
+		ActionType:      "run_command",
+		ActionSummary:   "run curl",
+	})
+	if err != nil {
+		t.Fatalf("create approval: %v", err)
+	}
+
+	publisher := &fakePublisher{}
+	sweeper := newApprovalExpirySweeper(t.TempDir(), sqlStore, map[string]connectors.Publisher{"telegram": publisher}, nil)
+	if count := sweeper.sweep(ctx, time.Now()); count != 0 {
+		t.Fatalf("expected nothing to expire yet, got %d", count)
+	}
+	if count := sweeper.sweep(ctx, time.Now().Add(time.Hour)); count != 1 {
+		t.Fatalf("expected one expired approval, got %d", count)
+	}
+
+	stored, err := sqlStore.LookupActionApproval(ctx, approval.ID)
+	if err != nil || stored.Status != "denied" || stored.ApproverUserID != store.ExpiredApprovalUserID {
+		t.Fatalf("expected approval to be denied by expiry, got %+v (%v)", stored, err)
+	}
+	if len(publisher.messages) != 1 || publisher.messages[0].externalID != "42" || !strings.Contains(publisher.messages[0].text, approval.ID) {
+		t.Fatalf("expected requester to be told, got %+v", publisher.messages)
+	}
+	events, err := sqlStore.ListAgentAuditEvents(ctx, store.ListAgentAuditEventsInput{WorkspaceID: "ws-1", Limit: 10})
+	if err != nil || len(events) != 1 || events[0].EventType != "action_approval_expired" || events[0].ToolName != "run_command" {
+		t.Fatalf("expected an expiry audit event, got %+v (%v)", events, err)
+	}
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/actions/plugins/sandbox"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// networkCommands reach outside the sandbox host on their own; git only does
+// for the subcommands listed in networkGitSubcommands.
+var networkCommands = map[string]struct{}{
+	"curl": {}, "wget": {}, "ssh": {}, "scp": {}, "sftp": {}, "rsync": {},
+	"nc": {}, "ncat": {}, "netcat": {}, "telnet": {}, "ftp": {},
+}
+
+var networkGitSubcommands = map[string]struct{}{
+	"clone": {}, "fetch": {}, "pull": {}, "push": {}, "ls-remote": {},
+}
+
+// approvalQuorumPolicy applies the two-person rule: commands that touch the
+// network, email leaving the internal domains and any explicitly listed
+// action type need several distinct admin approvals.
+type approvalQuorumPolicy struct {
+	required        int
+	internalDomains map[string]struct{}
+	actionTypes     map[string]struct{}
+}
+
+func newApprovalQuorumPolicy(required int, internalDomains, actionTypes string) approvalQuorumPolicy {
+	if required < 2 {
+		required = 2
+	}
+	policy := approvalQuorumPolicy{
+		required:        required,
+		internalDomains: map[string]struct{}{},
+		actionTypes:     map[string]struct{}{},
+	}
+	for _, domain := range parseCSVTrimList(internalDomains) {
+		policy.internalDomains[strings.ToLower(strings.TrimPrefix(domain, "@"))] = struct{}{}
+	}
+	for _, actionType := range parseCSVTrimList(actionTypes) {
+		policy.actionTypes[strings.ToLower(actionType)] = struct{}{}
+	}
+	return policy
+}
+
+func (p approvalQuorumPolicy) RequiredApprovals(record store.ActionApproval) int {
+	if p.highRisk(record) {
+		return p.required
+	}
+	return 1
+}
+
+func (p approvalQuorumPolicy) highRisk(record store.ActionApproval) bool {
+	actionType := strings.ToLower(strings.TrimSpace(record.ActionType))
+	if _, ok := p.actionTypes[actionType]; ok {
+		return true
+	}
+	switch actionType {
+	case "run_command", "shell_command", "cli_command":
+		return commandUsesNetwork(record)
+	case "send_email", "smtp_email", "email":
+		return p.hasExternalRecipient(record)
+	}
+	return false
+}
+
+func commandUsesNetwork(record store.ActionApproval) bool {
+	if network, ok := record.Payload["network"].(bool); ok && network {
+		return true
+	}
+	command, args, err := sandbox.ParseCommand(record)
+	if err != nil {
+		return false
+	}
+	command = strings.ToLower(command)
+	if _, ok := networkCommands[command]; ok {
+		return true
+	}
+	if command == "git" && len(args) > 0 {
+		_, ok := networkGitSubcommands[strings.ToLower(strings.TrimSpace(args[0]))]
+		return ok
+	}
+	return false
+}
+
+// hasExternalRecipient reports whether any recipient is outside the internal
+// domains. With no internal domains configured every recipient is external.
+func (p approvalQuorumPolicy) hasExternalRecipient(record store.ActionApproval) bool {
+	recipients := approvalRecipients(record.ActionTarget)
+	for _, key := range []string{"to", "cc", "bcc"} {
+		recipients = append(recipients, approvalRecipients(record.Payload[key])...)
+	}
+	for _, recipient := range recipients {
+		at := strings.LastIndex(recipient, "@")
+		if at < 0 {
+			continue
+		}
+		domain := strings.ToLower(strings.TrimRight(recipient[at+1:], "> "))
+		if _, ok := p.internalDomains[domain]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func approvalRecipients(value any) []string {
+	switch casted := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return parseCSVTrimList(casted)
+	case []string:
+		return casted
+	case []any:
+		recipients := make([]string, 0, len(casted))
+		for _, raw := range casted {
+			recipients = append(recipients, strings.TrimSpace(fmt.Sprintf("%v", raw)))
+		}
+		return recipients
+	default:
+		return parseCSVTrimList(fmt.Sprintf("%v", value))
+	}
+}
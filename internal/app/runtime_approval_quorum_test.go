@@ -0,0 +1,48 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestApprovalQuorumPolicyFlagsHighRiskActions(t *testing.T) {
+	policy := newApprovalQuorumPolicy(3, "example.com, @corp.example", "deploy_release")
+	cases := []struct {
+		name   string
+		record store.ActionApproval
+		want   int
+	}{
+		{"curl", store.ActionApproval{ActionType: "run_command", ActionTarget: "curl", Payload: map[string]any{"args": []any{"https://example.org"}}}, 3},
+		{"git fetch", store.ActionApproval{ActionType: "run_command", Payload: map[string]any{"command": "git fetch origin"}}, 3},
+		{"git status", store.ActionApproval{ActionType: "run_command", Payload: map[string]any{"command": "git status"}}, 1},
+		{"network flag", store.ActionApproval{ActionType: "shell_command", ActionTarget: "python3", Payload: map[string]any{"network": true}}, 3},
+		{"local command", store.ActionApproval{ActionType: "run_command", ActionTarget: "ls"}, 1},
+		{"internal email", store.ActionApproval{ActionType: "send_email", ActionTarget: "ops@example.com", Payload: map[string]any{"cc": []any{"Lead <lead@corp.example>"}}}, 1},
+		{"external bcc", store.ActionApproval{ActionType: "send_email", ActionTarget: "ops@example.com", Payload: map[string]any{"bcc": "someone@elsewhere.net"}}, 3},
+		{"listed type", store.ActionApproval{ActionType: "deploy_release"}, 3},
+		{"other type", store.ActionApproval{ActionType: "webhook"}, 1},
+	}
+	for _, tc := range cases {
+		if got := policy.RequiredApprovals(tc.record); got != tc.want {
+			t.Errorf("%s: expected %d approvals, got %d", tc.name, tc.want, got)
+		}
+	}
+
+	open := newApprovalQuorumPolicy(0, "", "")
+	if got := open.RequiredApprovals(store.ActionApproval{ActionType: "email", ActionTarget: "a@example.com"}); got != 2 {
+		t.Fatalf("expected every recipient to be external without internal domains, got %d", got)
+	}
+}
+
+func TestActionApprovalNoticeMentionsRequiredApprovals(t *testing.T) {
+	notice := buildActionApprovalNotice(store.ActionApproval{ID: "act_1", ActionType: "send_email", RequiredApprovals: 2})
+	if !strings.Contains(notice.Text, "needs 2 distinct admin approvals") {
+		t.Fatalf("expected notice to mention the quorum, got %q", notice.Text)
+	}
+	single := buildActionApprovalNotice(store.ActionApproval{ID: "act_2", ActionType: "send_email", RequiredApprovals: 1})
+	if strings.Contains(single.Text, "distinct admin approvals") {
+		t.Fatalf("expected no quorum line for single approvals, got %q", single.Text)
+	}
+}
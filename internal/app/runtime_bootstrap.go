@@ -12,15 +12,24 @@ import (
 	"time"
 
 	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/actions/plugins/browser"
+	calendarplugin "github.com/dwizi/agent-runtime/internal/actions/plugins/calendar"
 	"github.com/dwizi/agent-runtime/internal/actions/plugins/externalcmd"
 	"github.com/dwizi/agent-runtime/internal/actions/plugins/sandbox"
 	"github.com/dwizi/agent-runtime/internal/actions/plugins/smtp"
+	sshplugin "github.com/dwizi/agent-runtime/internal/actions/plugins/ssh"
 	"github.com/dwizi/agent-runtime/internal/actions/plugins/webhook"
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
+	"github.com/dwizi/agent-runtime/internal/attachments"
+	"github.com/dwizi/agent-runtime/internal/botfile"
+	"github.com/dwizi/agent-runtime/internal/canary"
 	"github.com/dwizi/agent-runtime/internal/config"
 	"github.com/dwizi/agent-runtime/internal/connectors"
 	"github.com/dwizi/agent-runtime/internal/connectors/discord"
 	"github.com/dwizi/agent-runtime/internal/connectors/imap"
 	"github.com/dwizi/agent-runtime/internal/connectors/telegram"
+	"github.com/dwizi/agent-runtime/internal/degrade"
 	"github.com/dwizi/agent-runtime/internal/extplugins"
 	"github.com/dwizi/agent-runtime/internal/gateway"
 	"github.com/dwizi/agent-runtime/internal/heartbeat"
@@ -32,14 +41,29 @@ import (
 	"github.com/dwizi/agent-runtime/internal/llm/promptpolicy"
 	"github.com/dwizi/agent-runtime/internal/llm/safety"
 	"github.com/dwizi/agent-runtime/internal/mcp"
+	"github.com/dwizi/agent-runtime/internal/memorylog"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/outbox"
 	"github.com/dwizi/agent-runtime/internal/qmd"
+	"github.com/dwizi/agent-runtime/internal/quota"
+	"github.com/dwizi/agent-runtime/internal/ratelimit"
+	"github.com/dwizi/agent-runtime/internal/redact"
 	"github.com/dwizi/agent-runtime/internal/scheduler"
+	"github.com/dwizi/agent-runtime/internal/secretscan"
+	"github.com/dwizi/agent-runtime/internal/skillreview"
+	"github.com/dwizi/agent-runtime/internal/spam"
+	"github.com/dwizi/agent-runtime/internal/statuspage"
 	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/translate"
+	"github.com/dwizi/agent-runtime/internal/tts"
 	"github.com/dwizi/agent-runtime/internal/watcher"
 )
 
-func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
+func New(cfg config.Config, logger *slog.Logger, opts ...Option) (*Runtime, error) {
+	options := runtimeOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
 	if err := os.MkdirAll(filepath.Dir(cfg.DBPath), 0o755); err != nil {
 		return nil, fmt.Errorf("create db directory: %w", err)
 	}
@@ -58,8 +82,39 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		sqlStore.Close()
 		return nil, err
 	}
+	if err := configureChangeLog(context.Background(), sqlStore, cfg.ChangeLogEnabled); err != nil {
+		sqlStore.Close()
+		return nil, err
+	}
+	cfg, err = resolveConfigSecrets(context.Background(), cfg, sqlStore, logger.With("component", "secrets"))
+	if err != nil {
+		sqlStore.Close()
+		return nil, fmt.Errorf("resolve secrets: %w", err)
+	}
 
 	engine := orchestrator.New(cfg.DefaultConcurrency, logger.With("component", "orchestrator"))
+	engine.SetWorkerBounds(workerPoolBounds(cfg))
+	quotaService := quota.New(sqlStore, quota.Limits{
+		TasksPerDay:    cfg.QuotaTasksPerDay,
+		Objectives:     cfg.QuotaObjectives,
+		ActionsPerDay:  cfg.QuotaActionsPerDay,
+		TokensPerMonth: cfg.QuotaTokensPerMonth,
+	}, logger.With("component", "quota"))
+	sqlStore.SetQuotaGuard(quotaService)
+	if cfg.ApprovalExpiryEnabled {
+		sqlStore.SetActionApprovalTTLPolicy(newApprovalTTLPolicy(cfg.ApprovalTTLMinutes, cfg.ApprovalTTLByType))
+	}
+	if cfg.TwoPersonRuleEnabled {
+		sqlStore.SetActionApprovalQuorumPolicy(newApprovalQuorumPolicy(cfg.TwoPersonApprovals, cfg.TwoPersonInternalEmailDomains, cfg.TwoPersonActionTypes))
+	}
+	engine.SetAdmission(quotaService)
+	engine.SetDependencyResolver(taskDependencyResolver{store: sqlStore})
+	engine.SetRetryPolicy(newTaskRetryPolicies(cfg))
+	if cfg.TaskLeaseEnabled {
+		owner := taskLeaseOwner(cfg)
+		engine.SetTaskLeaser(taskLeaser{store: sqlStore}, owner, time.Duration(cfg.TaskLeaseSec)*time.Second)
+		logger.Info("task leases enabled", "instance_id", owner)
+	}
 	var heartbeatRegistry *heartbeat.Registry
 	if cfg.HeartbeatEnabled {
 		heartbeatRegistry = heartbeat.NewRegistry()
@@ -88,9 +143,32 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 	if heartbeatRegistry != nil {
 		heartbeatRegistry.Beat("qmd", "qmd service initialized")
 	}
+	// Chat and grounding search through the federated retriever so contexts
+	// that opted in also see the shared knowledge workspace.
+	knowledgeRetriever := qmd.NewFederated(qmdService, cfg.SharedKnowledgeWorkspace)
 
+	calendarClient, err := newCalendarClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	egressGuard, err := newEgressGuard(cfg)
+	if err != nil {
+		return nil, err
+	}
+	eventBus, err := newEventBus(cfg, sqlStore, egressGuard, logger.With("component", "event-bus"))
+	if err != nil {
+		return nil, err
+	}
+	eventBus.SetDryRun(cfg.DryRun)
+	sqlStore.SetEventPublisher(eventBus)
+	secretScanMode, err := secretscan.ParseMode(cfg.SecretScanMode)
+	if err != nil {
+		return nil, err
+	}
+	webhookPlugin := webhook.New(15 * time.Second)
+	webhookPlugin.SetEgressGuard(egressGuard)
 	actionPlugins := []executor.Plugin{
-		webhook.New(15 * time.Second),
+		webhookPlugin,
 		smtp.New(smtp.Config{
 			Host:     cfg.SMTPHost,
 			Port:     cfg.SMTPPort,
@@ -99,8 +177,18 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 			From:     cfg.SMTPFrom,
 		}),
 	}
+	if calendarClient != nil {
+		actionPlugins = append(actionPlugins, calendarplugin.New(calendarClient))
+	}
+	if cfg.BrowserCDPURL != "" {
+		actionPlugins = append(actionPlugins, browser.New(browser.Config{
+			CDPURL:        cfg.BrowserCDPURL,
+			WorkspaceRoot: cfg.WorkspaceRoot,
+			Timeout:       time.Duration(cfg.BrowserTimeoutSec) * time.Second,
+		}))
+	}
 	if cfg.SandboxEnabled {
-		actionPlugins = append(actionPlugins, sandbox.New(sandbox.Config{
+		sandboxPlugin := sandbox.New(sandbox.Config{
 			Enabled:         true,
 			WorkspaceRoot:   cfg.WorkspaceRoot,
 			AllowedCommands: parseCSVList(cfg.SandboxAllowedCommandsCSV),
@@ -108,6 +196,40 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 			RunnerArgs:      parseShellArgs(cfg.SandboxRunnerArgs),
 			Timeout:         time.Duration(cfg.SandboxTimeoutSec) * time.Second,
 			MaxOutputBytes:  cfg.SandboxMaxOutputBytes,
+			Docker: sandbox.DockerConfig{
+				Image:     cfg.SandboxDockerImage,
+				Binary:    cfg.SandboxDockerBinary,
+				Runtime:   cfg.SandboxDockerRuntime,
+				Network:   cfg.SandboxDockerNetwork,
+				CPUs:      cfg.SandboxDockerCPUs,
+				Memory:    cfg.SandboxDockerMemory,
+				PidsLimit: cfg.SandboxDockerPidsLimit,
+			},
+		})
+		sandboxPlugin.SetEgressGuard(egressGuard)
+		actionPlugins = append(actionPlugins, sandboxPlugin)
+	}
+	if cfg.KubernetesJobImage != "" {
+		jobPlugin, err := newKubernetesJobPlugin(cfg)
+		if err != nil {
+			return nil, err
+		}
+		jobPlugin.SetEgressGuard(egressGuard)
+		actionPlugins = append(actionPlugins, jobPlugin)
+	}
+	if cfg.SSHHosts != "" {
+		sshHosts, err := sshplugin.ParseHostAllowlist(cfg.SSHHosts)
+		if err != nil {
+			return nil, fmt.Errorf("parse AGENT_RUNTIME_SSH_HOSTS: %w", err)
+		}
+		actionPlugins = append(actionPlugins, sshplugin.New(sshplugin.Config{
+			Binary:         cfg.SSHBinary,
+			KeyFile:        cfg.SSHKeyFile,
+			KnownHostsFile: cfg.SSHKnownHostsFile,
+			User:           cfg.SSHUser,
+			Hosts:          sshHosts,
+			Timeout:        time.Duration(cfg.SSHTimeoutSec) * time.Second,
+			MaxOutputBytes: cfg.SSHMaxOutputBytes,
 		}))
 	}
 
@@ -177,13 +299,36 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 	}
 
 	actionExecutor := executor.NewRegistry(actionPlugins...)
-	commandGateway := gateway.New(sqlStore, engine, qmdService, actionExecutor, cfg.WorkspaceRoot, logger.With("component", "gateway"))
+	dryRunLogger := logger.With("component", "dry-run")
+	if cfg.DryRun {
+		logger.Warn("dry-run mode: actions and outbound side effects are logged, not run")
+		actionExecutor.SetDryRun(true, dryRunLogger)
+	}
+	commandGateway := gateway.New(sqlStore, engine, knowledgeRetriever, actionExecutor, cfg.WorkspaceRoot, logger.With("component", "gateway"))
+	commandGateway.SetDryRun(cfg.DryRun)
 	commandGateway.SetTriageEnabled(cfg.TriageEnabled)
+	commandGateway.SetSharedKnowledgeWorkspace(cfg.SharedKnowledgeWorkspace)
+	if calendarClient != nil {
+		commandGateway.SetCalendarClient(calendarClient)
+	}
+	commandGateway.SetBrowserEnabled(cfg.BrowserCDPURL != "")
 	if cfg.AgentMaxTurnDurationSec > 0 {
 		commandGateway.SetAgentMaxTurnDuration(time.Duration(cfg.AgentMaxTurnDurationSec) * time.Second)
 	}
 	commandGateway.SetAgentGroundingPolicy(cfg.AgentGroundingFirstStep, cfg.AgentGroundingEveryStep)
 	commandGateway.SetSensitiveApprovalTTL(time.Duration(cfg.AgentSensitiveApprovalTTLSeconds) * time.Second)
+	toolPolicies, err := tools.ParsePolicyOverrides(cfg.ToolTimeoutsCSV, cfg.ToolRetriesCSV)
+	if err != nil {
+		return nil, fmt.Errorf("configure tool policies: %w", err)
+	}
+	commandGateway.Registry().SetDefaultPolicy(tools.ExecutionPolicy{
+		Timeout:      time.Duration(cfg.ToolTimeoutSec) * time.Second,
+		MaxRetries:   cfg.ToolMaxRetries,
+		RetryBackoff: time.Duration(cfg.ToolRetryBackoffMS) * time.Millisecond,
+	})
+	for name, policy := range toolPolicies {
+		commandGateway.Registry().SetToolPolicy(name, policy)
+	}
 
 	mcpManager, err := mcp.NewManager(mcp.ManagerConfig{
 		ConfigPath:             cfg.MCPConfigPath,
@@ -195,10 +340,14 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 	if err != nil {
 		return nil, fmt.Errorf("configure mcp manager: %w", err)
 	}
-	commandGateway.SetMCPRuntime(mcpManager)
+	var mcpRuntime gateway.MCPRuntime = mcpManager
+	if cfg.DryRun {
+		mcpRuntime = dryRunMCPRuntime{MCPRuntime: mcpManager, logger: dryRunLogger}
+	}
+	commandGateway.SetMCPRuntime(mcpRuntime)
 	mcpManager.SetToolUpdateHandler(func(update mcp.ToolUpdate) {
 		namespace := "mcp:" + strings.ToLower(strings.TrimSpace(update.ServerID))
-		dynamicTools := gateway.BuildMCPDynamicTools(func() gateway.MCPRuntime { return mcpManager }, update.Tools)
+		dynamicTools := gateway.BuildMCPDynamicTools(func() gateway.MCPRuntime { return mcpRuntime }, update.Tools)
 		commandGateway.Registry().ReplaceNamespace(namespace, dynamicTools)
 	})
 	mcpManager.Bootstrap(context.Background())
@@ -210,6 +359,18 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		"degraded_servers", mcpSummary.DegradedServers,
 	)
 
+	registerToolPlugins(context.Background(), commandGateway, cfg, logger.With("component", "tool-plugins"))
+	if err := configureGitHub(commandGateway, cfg, dryRunLogger); err != nil {
+		return nil, err
+	}
+	taskSyncer, err := newTaskSyncer(sqlStore, cfg, dryRunLogger)
+	if err != nil {
+		return nil, err
+	}
+	if taskSyncer != nil {
+		commandGateway.SetTaskSyncer(taskSyncer)
+	}
+
 	// Load Reasoning Prompt
 	if cfg.ReasoningPromptFile != "" {
 		promptBytes, err := os.ReadFile(cfg.ReasoningPromptFile)
@@ -233,26 +394,61 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 			BaseURL: cfg.LLMBaseURL,
 			Model:   cfg.LLMModel,
 			Timeout: time.Duration(cfg.LLMTimeoutSec) * time.Second,
+			Usage:   quotaService,
+			Vision:  cfg.LLMVisionEnabled,
 		}, logger.With("component", "llm-anthropic"))
 	case "openai", "z.ai", "local":
 		// Default to OpenAI adapter for z.ai and local as well
 		responder = openai.New(openai.Config{
-			APIKey:  cfg.LLMAPIKey,
-			BaseURL: cfg.LLMBaseURL,
-			Model:   cfg.LLMModel,
-			Timeout: time.Duration(cfg.LLMTimeoutSec) * time.Second,
+			APIKey:         cfg.LLMAPIKey,
+			BaseURL:        cfg.LLMBaseURL,
+			Model:          cfg.LLMModel,
+			EmbeddingModel: cfg.SkillsEmbeddingModel,
+			Timeout:        time.Duration(cfg.LLMTimeoutSec) * time.Second,
+			Usage:          quotaService,
+			Vision:         cfg.LLMVisionEnabled,
 		}, logger.With("component", "llm-openai"))
 	default:
 		// Fallback to OpenAI
 		responder = openai.New(openai.Config{
-			APIKey:  cfg.LLMAPIKey,
-			BaseURL: cfg.LLMBaseURL,
-			Model:   cfg.LLMModel,
-			Timeout: time.Duration(cfg.LLMTimeoutSec) * time.Second,
+			APIKey:         cfg.LLMAPIKey,
+			BaseURL:        cfg.LLMBaseURL,
+			Model:          cfg.LLMModel,
+			EmbeddingModel: cfg.SkillsEmbeddingModel,
+			Timeout:        time.Duration(cfg.LLMTimeoutSec) * time.Second,
+			Usage:          quotaService,
+			Vision:         cfg.LLMVisionEnabled,
 		}, logger.With("component", "llm-openai"))
 	}
 
-	policyResponder := promptpolicy.New(responder, sqlStore, promptpolicy.Config{
+	if options.responder != nil {
+		responder = options.responder
+	}
+
+	var skillEmbedder llm.Embedder
+	if embedder, ok := responder.(llm.Embedder); ok && cfg.SkillsEmbeddingModel != "" {
+		skillEmbedder = embedder
+	}
+	degradation := degrade.New(degrade.Config{
+		DownAfterFailures: cfg.LLMDownAfterFailures,
+		ProbeInterval:     time.Duration(cfg.LLMProbeIntervalSeconds) * time.Second,
+	}, engine, logger.With("component", "degrade"))
+	if heartbeatRegistry != nil {
+		degradation.SetHeartbeatReporter(heartbeatRegistry)
+	}
+	responder = degradation.WrapResponder(responder)
+	botfiles := newBotfileManager(cfg.WorkspaceRoot, cfg.SoulWorkspaceRelPath, sqlStore, logger.With("component", "botfile"))
+	redactor := redact.New(redact.Config{
+		Dir:      filepath.Join(cfg.DataDir, "redaction"),
+		Default:  cfg.RedactionEnabled,
+		Resolver: botfiles,
+	})
+	memorylog.SetRedactor(redactor)
+	responder = redactor.WrapResponder(responder)
+	if skillEmbedder != nil {
+		skillEmbedder = redactor.WrapEmbedder(skillEmbedder)
+	}
+	policyResponder := promptpolicy.New(quotaService.WrapResponder(responder), sqlStore, promptpolicy.Config{
 		WorkspaceRoot:        cfg.WorkspaceRoot,
 		AdminSystemPrompt:    cfg.LLMAdminSystemPrompt,
 		PublicSystemPrompt:   cfg.LLMPublicSystemPrompt,
@@ -265,9 +461,23 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		GlobalSkillsRoot:     cfg.SkillsGlobalRoot,
 		MaxSkills:            5,
 		MaxSkillBytes:        1400,
+		MaxSkillTokens:       cfg.SkillsMaxTokens,
 		MaxSystemPromptBytes: 12000,
+		SkillEmbedder:        skillEmbedder,
+		SkillUsage:           sqlStore,
 	})
-	groundedResponder := grounded.New(policyResponder, qmdService, grounded.Config{
+	if cfg.ActionRiskEnabled {
+		var riskResponder llm.Responder
+		if cfg.ActionRiskLLMEnabled {
+			riskResponder = quotaService.WrapResponder(responder)
+		}
+		sqlStore.SetActionApprovalRiskClassifier(newActionRiskClassifier(cfg.ActionRiskTrustedDomains, cfg.ActionRiskLargePayloadBytes, riskResponder, logger.With("component", "action-risk")))
+	}
+	var groundingReranker grounded.Reranker
+	if cfg.LLMGroundingRerank {
+		groundingReranker = grounded.NewLLMReranker(quotaService.WrapResponder(responder))
+	}
+	groundedResponder := grounded.New(policyResponder, knowledgeRetriever, grounded.Config{
 		WorkspaceRoot:               cfg.WorkspaceRoot,
 		TopK:                        cfg.LLMGroundingTopK,
 		MaxDocExcerpt:               cfg.LLMGroundingMaxDocExcerpt,
@@ -282,6 +492,9 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		MemorySummaryRefreshTurns:   cfg.LLMGroundingSummaryRefreshTurns,
 		MemorySummaryMaxItems:       cfg.LLMGroundingSummaryMaxItems,
 		MemorySummarySourceMaxLines: cfg.LLMGroundingSummarySourceMaxLines,
+		HybridRetrieval:             cfg.LLMGroundingHybrid,
+		RerankCandidates:            cfg.LLMGroundingRerankCandidates,
+		Reranker:                    groundingReranker,
 	}, logger.With("component", "llm-grounding"))
 	commandGateway.SetTriageAcknowledger(groundedResponder)
 	llmPolicy := safety.New(safety.Config{
@@ -294,10 +507,47 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		RateLimitWindow:        time.Duration(cfg.LLMRateLimitWindowSec) * time.Second,
 	})
 	schedulerService := scheduler.New(sqlStore, engine, time.Duration(cfg.ObjectivePollSec)*time.Second, logger.With("component", "scheduler"))
-	engine.SetExecutor(newTaskWorkerExecutor(cfg.WorkspaceRoot, sqlStore, groundedResponder, qmdService, actionExecutor, commandGateway.Registry(), cfg, logger.With("component", "task-executor")))
+	schedulerService.SetEventPublisher(eventBus)
+	schedulerService.SetAutoPauseAfter(cfg.ObjectiveAutoPauseAfter)
+	eventBus.Route(newObjectiveTriggerSink(schedulerService, logger.With("component", "objective-triggers")))
+	var skillReviewer *skillreview.Reviewer
+	if cfg.SkillReviewEnabled {
+		skillReviewer = skillreview.New(skillreview.Config{
+			WorkspaceRoot: cfg.WorkspaceRoot,
+			StaleAfter:    time.Duration(cfg.SkillReviewStaleDays) * 24 * time.Hour,
+			Interval:      time.Duration(cfg.SkillReviewIntervalHours) * time.Hour,
+		}, sqlStore, engine, logger.With("component", "skill-review"))
+	}
+	commandGateway.SetAgentPolicyResolver(canary.PolicyResolver(botfiles.Policy))
+	commandGateway.SetApprovalPolicyResolver(botfiles)
+	egressGuard.SetResolver(botfiles)
+	commandGateway.SetEgressGuard(egressGuard)
+	commandGateway.SetSecretScanMode(secretScanMode)
+	commandGateway.SetCanaryRouter(canary.New(sqlStore, logger.With("component", "canary")))
+	taskExecutor := newTaskWorkerExecutor(cfg.WorkspaceRoot, sqlStore, groundedResponder, qmdService, actionExecutor, commandGateway.Registry(), cfg, logger.With("component", "task-executor"))
+	taskExecutor.SetToolPolicyResolver(botfiles.ToolPolicy)
+	taskExecutor.objectiveChanges = schedulerService
+	engine.SetExecutor(taskExecutor)
 	if heartbeatRegistry != nil {
 		schedulerService.SetHeartbeatReporter(heartbeatRegistry)
 	}
+	commandGateway.SetObjectiveRunner(schedulerService)
+	commandGateway.SetDegradation(degradation)
+	if cfg.IntentClassifierEnabled {
+		commandGateway.SetIntentClassifier(quotaService.WrapResponder(responder), cfg.IntentMinConfidence)
+	}
+	if cfg.SpamFilterEnabled {
+		commandGateway.SetSpamFilter(spam.New(spam.Config{Threshold: cfg.SpamThreshold}))
+	}
+	if cfg.RateLimitEnabled {
+		commandGateway.SetRateLimiter(ratelimit.New(ratelimit.Config{
+			UserPerMinute:    cfg.RateLimitUserPerMinute,
+			UserBurst:        cfg.RateLimitUserBurst,
+			ContextPerMinute: cfg.RateLimitContextPerMinute,
+			ContextBurst:     cfg.RateLimitContextBurst,
+		}))
+	}
+	schedulerService.SetModelAvailability(degradation)
 	var reindexMu sync.Mutex
 	reindexLastQueued := map[string]time.Time{}
 	const reindexTaskDebounce = 2 * time.Second
@@ -307,6 +557,12 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		logger.With("component", "watcher"),
 		func(ctx context.Context, path string) {
 			workspaceID := workspaceIDFromPath(cfg.WorkspaceRoot, path)
+			if isWorkspaceBotfilePath(cfg.WorkspaceRoot, path) {
+				if _, err := botfiles.Apply(ctx, workspaceID); err != nil {
+					logger.Error("failed to apply botfile", "workspace_id", workspaceID, "error", err)
+				}
+				return
+			}
 			if workspaceID != "" {
 				if shouldTriggerObjectiveEventForPath(cfg.WorkspaceRoot, path) {
 					schedulerService.HandleMarkdownUpdate(ctx, workspaceID, path)
@@ -369,19 +625,27 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		sqlStore.Close()
 		return nil, err
 	}
+	watchService.WatchFileNames(botfile.FileName)
 	if heartbeatRegistry != nil {
 		watchService.SetHeartbeatReporter(heartbeatRegistry)
 	}
 
+	artifactLinks := artifactlink.New(cfg.ArtifactLinkBaseURL, cfg.ArtifactLinkSecret, time.Duration(cfg.ArtifactLinkTTLHours)*time.Hour)
 	handler := httpapi.NewRouter(httpapi.Dependencies{
 		Config:              cfg,
 		Store:               sqlStore,
 		Engine:              engine,
 		Gateway:             commandGateway,
 		MCPStatusProvider:   mcpManager,
+		ObjectiveRunner:     schedulerService,
+		ObjectiveEvents:     schedulerService,
+		Quotas:              quotaService,
+		Botfiles:            botfiles,
+		ActionExecutor:      actionExecutor,
 		Logger:              logger.With("component", "api"),
 		Heartbeat:           heartbeatRegistry,
 		HeartbeatStaleAfter: time.Duration(cfg.HeartbeatStaleSec) * time.Second,
+		ArtifactLinks:       artifactLinks,
 	})
 	httpServer := &http.Server{
 		Addr:              cfg.HTTPAddr,
@@ -389,6 +653,12 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
+	attachmentIngestor := attachments.New(attachments.Config{
+		PDFToTextBinary: cfg.AttachmentPDFToTextBinary,
+		OCRBinary:       cfg.AttachmentOCRBinary,
+		MaxBytes:        int64(cfg.AttachmentMaxMB) << 20,
+		Timeout:         time.Duration(cfg.AttachmentExtractTimeoutSec) * time.Second,
+	})
 	connectorList := []connectors.Connector{}
 	if strings.TrimSpace(cfg.DiscordToken) != "" {
 		connectorList = append(connectorList, discord.New(
@@ -404,11 +674,30 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 			discord.WithCommandSync(cfg.CommandSyncEnabled),
 			discord.WithCommandGuildIDs(parseCSVTrimList(cfg.DiscordCommandGuildIDsCSV)),
 			discord.WithApplicationID(cfg.DiscordApplicationID),
+			discord.WithAttachmentIngestor(attachmentIngestor),
 		))
 	} else if heartbeatRegistry != nil {
 		heartbeatRegistry.Disabled("connector:discord", "token missing")
 	}
+	voice, err := tts.New(tts.Config{
+		Provider: cfg.TTSProvider,
+		APIKey:   cfg.TTSAPIKey,
+		BaseURL:  cfg.TTSBaseURL,
+		Model:    cfg.TTSModel,
+		Voice:    cfg.TTSVoice,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configure tts: %w", err)
+	}
+	commandGateway.SetVoiceRepliesAvailable(voice != nil)
 	if strings.TrimSpace(cfg.TelegramToken) != "" {
+		telegramOptions := []telegram.Option{
+			telegram.WithCommandSync(cfg.CommandSyncEnabled),
+			telegram.WithAttachmentIngestor(attachmentIngestor),
+		}
+		if voice != nil {
+			telegramOptions = append(telegramOptions, telegram.WithVoiceReplies(voice, sqlStore))
+		}
 		connectorList = append(connectorList, telegram.New(
 			cfg.TelegramToken,
 			cfg.TelegramAPI,
@@ -419,7 +708,7 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 			groundedResponder,
 			llmPolicy,
 			logger.With("connector", "telegram"),
-			telegram.WithCommandSync(cfg.CommandSyncEnabled),
+			telegramOptions...,
 		))
 	} else if heartbeatRegistry != nil {
 		heartbeatRegistry.Disabled("connector:telegram", "token missing")
@@ -429,6 +718,9 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 	} else if heartbeatRegistry != nil {
 		heartbeatRegistry.Disabled("connector:imap", "credentials missing")
 	}
+	for _, factory := range options.connectors {
+		connectorList = append(connectorList, factory(ConnectorDeps{Gateway: commandGateway, Store: sqlStore, Responder: groundedResponder}))
+	}
 	if heartbeatRegistry != nil {
 		for _, connector := range connectorList {
 			reportingConnector, ok := connector.(heartbeatAware)
@@ -449,6 +741,62 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 	if _, exists := publishers["codex"]; !exists {
 		publishers["codex"] = newCodexPublisherFromConfig(cfg, logger.With("connector", "codex"))
 	}
+	outboundQueue := outbox.New(outbox.Config{
+		RetryInterval: time.Duration(cfg.OutboxRetrySec) * time.Second,
+		MaxAge:        time.Duration(cfg.OutboxMaxAgeHours) * time.Hour,
+	}, sqlStore, logger.With("component", "outbox"))
+	publishers = outboundQueue.WrapAll(publishers)
+	quotaService.SetNotifier(newQuotaNotifier(cfg.WorkspaceRoot, sqlStore, publishers, logger.With("component", "quota-notifier")))
+	schedulerService.SetPauseNotifier(newObjectivePauseNotifier(cfg.WorkspaceRoot, sqlStore, publishers, logger.With("component", "objective-pause-notifier")))
+	if cfg.QueueBackpressureEnabled {
+		engine.SetBackpressure(cfg.QueueBackpressureThreshold, newBackpressureNotifier(
+			sqlStore,
+			publishers,
+			cfg.WorkspaceRoot,
+			cfg.QueueBackpressureNotifyAdmin,
+			logger.With("component", "backpressure-notifier"),
+		))
+		commandGateway.SetBackpressure(engine)
+	}
+	var approvalExpiry *approvalExpirySweeper
+	if cfg.ApprovalExpiryEnabled {
+		approvalExpiry = newApprovalExpirySweeper(cfg.WorkspaceRoot, sqlStore, publishers, logger.With("component", "approval-expiry"))
+	}
+	var digest *approvalDigest
+	if cfg.ApprovalDigestEnabled {
+		digest = newApprovalDigest(
+			sqlStore,
+			newRoutingNotifier(cfg.WorkspaceRoot, sqlStore, publishers, true, logger.With("component", "approval-digest")),
+			time.Duration(cfg.ApprovalDigestStuckTaskMinutes)*time.Minute,
+			logger.With("component", "approval-digest"),
+		)
+	}
+	var statusPage *statuspage.Generator
+	if cfg.StatusPageEnabled {
+		statusPage, err = newStatusPageGenerator(cfg, sqlStore, logger.With("component", "status-page"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.ApprovalNotifyAdmin {
+		sqlStore.SetActionApprovalNotifier(newApprovalNotifier(cfg.WorkspaceRoot, sqlStore, publishers, logger.With("component", "approval-notifier")))
+	}
+	translator := translate.New(responder)
+	commandGateway.SetTranslator(translator)
+	commandGateway.SetMessageMirror(newTranslationMirror(
+		cfg.WorkspaceRoot,
+		sqlStore,
+		translator,
+		publishers,
+		logger.With("component", "translation-mirror"),
+	))
+	commandGateway.SetMemberPolicies(sqlStore)
+	commandGateway.SetAuditReader(sqlStore)
+	commandGateway.SetTaskLister(sqlStore)
+	commandGateway.SetTaskCanceller(taskCanceller{store: sqlStore, engine: engine})
+	commandGateway.SetTaskArtifacts(sqlStore, artifactLinks)
+	commandGateway.SetModeration(sqlStore, newModerationNotifier(cfg.WorkspaceRoot, sqlStore, publishers, logger.With("component", "moderation-notifier")))
+	commandGateway.SetCaseStore(sqlStore)
 	commandGateway.SetRoutingNotifier(newRoutingNotifier(
 		cfg.WorkspaceRoot,
 		sqlStore,
@@ -466,7 +814,18 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 		commandGateway,
 		logger.With("component", "task-notifier"),
 	)
-	engine.SetObserver(newTaskObserver(sqlStore, notifier, logger.With("component", "task-observer")))
+	notifier.SetArtifactLinks(artifactLinks)
+	objectiveDigests := newObjectiveDigests(cfg.WorkspaceRoot, sqlStore, publishers, logger.With("component", "objective-digests"))
+	notifier.SetObjectiveDigests(objectiveDigests)
+	if cfg.TaskProgressNotifyEnabled {
+		notifier.SetProgressNotices(time.Duration(cfg.TaskProgressNotifyIntervalSec) * time.Second)
+	}
+	observer := newTaskObserver(sqlStore, notifier, logger.With("component", "task-observer"))
+	if taskSyncer != nil {
+		observer.syncer = taskSyncer
+	}
+	observer.objectives = schedulerService
+	engine.SetObserver(observer)
 	if heartbeatRegistry != nil {
 		heartbeatNotifier := newHeartbeatNotifier(
 			sqlStore,
@@ -496,19 +855,37 @@ func New(cfg config.Config, logger *slog.Logger) (*Runtime, error) {
 			mcp:              mcpManager,
 			heartbeat:        heartbeatRegistry,
 			heartbeatMonitor: heartbeatMonitor,
+			skillReview:      skillReviewer,
+			botfiles:         botfiles,
+			degradation:      degradation,
+			outbox:           outboundQueue,
+			approvalExpiry:   approvalExpiry,
+			approvalDigest:   digest,
+			objectiveDigests: objectiveDigests,
+			statusPage:       statusPage,
+			eventBus:         eventBus,
 		}, nil
 	}
 
 	return &Runtime{
-		cfg:        cfg,
-		logger:     logger,
-		store:      sqlStore,
-		engine:     engine,
-		httpServer: httpServer,
-		watcher:    watchService,
-		scheduler:  schedulerService,
-		qmd:        qmdService,
-		connectors: connectorList,
-		mcp:        mcpManager,
+		cfg:              cfg,
+		logger:           logger,
+		store:            sqlStore,
+		engine:           engine,
+		httpServer:       httpServer,
+		watcher:          watchService,
+		scheduler:        schedulerService,
+		qmd:              qmdService,
+		connectors:       connectorList,
+		mcp:              mcpManager,
+		skillReview:      skillReviewer,
+		botfiles:         botfiles,
+		degradation:      degradation,
+		outbox:           outboundQueue,
+		approvalExpiry:   approvalExpiry,
+		approvalDigest:   digest,
+		objectiveDigests: objectiveDigests,
+		statusPage:       statusPage,
+		eventBus:         eventBus,
 	}, nil
 }
@@ -0,0 +1,432 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/approvalpolicy"
+	"github.com/dwizi/agent-runtime/internal/botfile"
+	"github.com/dwizi/agent-runtime/internal/egress"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	// botfileObjectivePrefix marks objectives owned by a workspace botfile;
+	// the rest of the context id is the objective key.
+	botfileObjectivePrefix = "botfile:"
+	botfileFAQRelPath      = "context/FAQ.md"
+	botfileObjectiveLimit  = 1000
+)
+
+// botfileManager applies workspace botfiles. Persona and FAQ entries are
+// written to workspace markdown, objectives are reconciled in the store and
+// tool, policy, approval, privacy and network settings are kept in memory
+// for the agents', tools', redactor's and egress guard's resolvers. An
+// invalid botfile is recorded and leaves the last applied version in effect.
+type botfileManager struct {
+	workspaceRoot  string
+	soulRelPath    string
+	store          *store.Store
+	logger         *slog.Logger
+	mu             sync.RWMutex
+	policies       map[string]agent.Policy
+	approvals      map[string]approvalpolicy.Policy
+	redaction      map[string]bool
+	network        map[string]egress.Rules
+	applyMu        sync.Mutex
+	now            func() time.Time
+	objectiveLimit int
+}
+
+func newBotfileManager(workspaceRoot, soulRelPath string, sqlStore *store.Store, logger *slog.Logger) *botfileManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &botfileManager{
+		workspaceRoot:  strings.TrimSpace(workspaceRoot),
+		soulRelPath:    strings.TrimSpace(soulRelPath),
+		store:          sqlStore,
+		logger:         logger,
+		policies:       map[string]agent.Policy{},
+		approvals:      map[string]approvalpolicy.Policy{},
+		redaction:      map[string]bool{},
+		network:        map[string]egress.Rules{},
+		now:            func() time.Time { return time.Now().UTC() },
+		objectiveLimit: botfileObjectiveLimit,
+	}
+}
+
+// isWorkspaceBotfilePath reports whether path is the botfile at the root of
+// a workspace.
+func isWorkspaceBotfilePath(workspaceRoot, path string) bool {
+	if filepath.Base(path) != botfile.FileName {
+		return false
+	}
+	workspaceID := workspaceIDFromPath(workspaceRoot, path)
+	if workspaceID == "" {
+		return false
+	}
+	return filepath.Clean(path) == filepath.Join(filepath.Clean(workspaceRoot), workspaceID, botfile.FileName)
+}
+
+// Policy is the chat agent's policy resolver: the workspace's botfile tools
+// and policies.
+func (m *botfileManager) Policy(ctx context.Context, input llm.MessageInput) agent.Policy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.policies[strings.TrimSpace(input.WorkspaceID)]
+}
+
+// ToolPolicy is the task worker's policy resolver. Workers keep their own
+// turn limits, so only the tool restrictions carry over.
+func (m *botfileManager) ToolPolicy(ctx context.Context, input llm.MessageInput) agent.Policy {
+	policy := m.Policy(ctx, input)
+	return agent.Policy{
+		AllowedTools:       policy.AllowedTools,
+		AllowedToolClasses: policy.AllowedToolClasses,
+	}
+}
+
+// ApprovalPolicy is the gateway's approval policy resolver: the auto-approve
+// rules of the workspace's botfile, when it declares any.
+func (m *botfileManager) ApprovalPolicy(workspaceID string) (approvalpolicy.Policy, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	policy, ok := m.approvals[strings.TrimSpace(workspaceID)]
+	return policy, ok
+}
+
+// RedactionEnabled is the redactor's resolver: the privacy setting of the
+// workspace's botfile, when it declares one.
+func (m *botfileManager) RedactionEnabled(workspaceID string) (bool, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	enabled, ok := m.redaction[strings.TrimSpace(workspaceID)]
+	return enabled, ok
+}
+
+// EgressRules is the egress guard's resolver: the network rules of the
+// workspace's botfile, when it declares any.
+func (m *botfileManager) EgressRules(workspaceID string) (egress.Rules, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rules, ok := m.network[strings.TrimSpace(workspaceID)]
+	return rules, ok
+}
+
+// ApplyAll applies the botfile of every workspace that has one. It runs at
+// startup so policies are in memory before the first message.
+func (m *botfileManager) ApplyAll(ctx context.Context) {
+	entries, err := os.ReadDir(m.workspaceRoot)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			m.logger.Error("failed to list workspaces for botfiles", "error", err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(m.workspaceRoot, entry.Name(), botfile.FileName)); err != nil {
+			continue
+		}
+		if _, err := m.Apply(ctx, entry.Name()); err != nil {
+			m.logger.Error("failed to apply botfile", "workspace_id", entry.Name(), "error", err)
+		}
+	}
+}
+
+// Apply reads and applies a workspace's botfile. Validation failures are
+// recorded on the returned record rather than returned as errors.
+func (m *botfileManager) Apply(ctx context.Context, workspaceID string) (store.WorkspaceBotfile, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" || m.workspaceRoot == "" {
+		return store.WorkspaceBotfile{}, fmt.Errorf("workspace id is required")
+	}
+	m.applyMu.Lock()
+	defer m.applyMu.Unlock()
+
+	previous, err := m.store.LookupWorkspaceBotfile(ctx, workspaceID)
+	if err != nil && (!errors.Is(err, store.ErrBotfileNotFound) || errors.Is(err, store.ErrWorkspaceScope)) {
+		return store.WorkspaceBotfile{}, err
+	}
+	previousFile := botfile.File{}
+	if previous.Content != "" {
+		if parsed, parseErr := botfile.Parse([]byte(previous.Content)); parseErr == nil {
+			previousFile = parsed
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.workspaceRoot, workspaceID, botfile.FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return m.recordRemoved(ctx, workspaceID, previous)
+	}
+	if err != nil {
+		return store.WorkspaceBotfile{}, fmt.Errorf("read botfile: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	next, parseErr := botfile.Parse(data)
+	if parseErr != nil {
+		m.logger.Warn("botfile rejected; keeping the last applied version", "workspace_id", workspaceID, "error", parseErr)
+		record := previous
+		record.WorkspaceID = workspaceID
+		record.Status = store.BotfileInvalid
+		record.Error = parseErr.Error()
+		if previous.Status == store.BotfileApplied || previous.Status == store.BotfileInvalid {
+			m.setPolicy(workspaceID, previousFile)
+		}
+		return m.store.SaveWorkspaceBotfile(ctx, record)
+	}
+	if previous.Status == store.BotfileApplied && previous.Checksum == checksum {
+		// Unchanged since the last apply; only the in-memory policy needs
+		// restoring after a restart.
+		m.setPolicy(workspaceID, next)
+		return previous, nil
+	}
+
+	changes := botfile.Diff(previousFile, next)
+	if err := m.writeMarkdown(workspaceID, previousFile, next); err != nil {
+		return m.recordFailure(ctx, workspaceID, previous, err)
+	}
+	if err := m.reconcileObjectives(ctx, workspaceID, next.Objectives); err != nil {
+		return m.recordFailure(ctx, workspaceID, previous, err)
+	}
+	m.setPolicy(workspaceID, next)
+	record, err := m.store.SaveWorkspaceBotfile(ctx, store.WorkspaceBotfile{
+		WorkspaceID: workspaceID,
+		Version:     next.Version,
+		Checksum:    checksum,
+		Content:     string(data),
+		Status:      store.BotfileApplied,
+		Changes:     changes,
+		AppliedAt:   m.now(),
+	})
+	if err != nil {
+		return store.WorkspaceBotfile{}, err
+	}
+	m.logger.Info("botfile applied", "workspace_id", workspaceID, "version", next.Version, "changes", strings.Join(changes, "; "))
+	return record, nil
+}
+
+func (m *botfileManager) recordRemoved(ctx context.Context, workspaceID string, previous store.WorkspaceBotfile) (store.WorkspaceBotfile, error) {
+	m.mu.Lock()
+	delete(m.policies, workspaceID)
+	delete(m.approvals, workspaceID)
+	delete(m.redaction, workspaceID)
+	delete(m.network, workspaceID)
+	m.mu.Unlock()
+	if previous.WorkspaceID == "" || previous.Status == store.BotfileRemoved {
+		return previous, nil
+	}
+	// Objectives and generated markdown stay in place; only the tool and
+	// policy overrides end with the file.
+	record := previous
+	record.Status = store.BotfileRemoved
+	record.Error = ""
+	record.Changes = []string{"botfile: removed"}
+	m.logger.Info("botfile removed", "workspace_id", workspaceID)
+	return m.store.SaveWorkspaceBotfile(ctx, record)
+}
+
+func (m *botfileManager) recordFailure(ctx context.Context, workspaceID string, previous store.WorkspaceBotfile, applyErr error) (store.WorkspaceBotfile, error) {
+	record := previous
+	record.WorkspaceID = workspaceID
+	record.Status = store.BotfileInvalid
+	record.Error = applyErr.Error()
+	if _, err := m.store.SaveWorkspaceBotfile(ctx, record); err != nil {
+		m.logger.Error("failed to record botfile failure", "workspace_id", workspaceID, "error", err)
+	}
+	return record, applyErr
+}
+
+func (m *botfileManager) setPolicy(workspaceID string, file botfile.File) {
+	policy := botfilePolicy(file)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies[workspaceID] = policy
+	if approvals, ok := file.ApprovalPolicy(); ok {
+		m.approvals[workspaceID] = approvals
+	} else {
+		delete(m.approvals, workspaceID)
+	}
+	if redact, ok := file.Redaction(); ok {
+		m.redaction[workspaceID] = redact
+	} else {
+		delete(m.redaction, workspaceID)
+	}
+	if rules, ok := file.EgressRules(); ok {
+		m.network[workspaceID] = rules
+	} else {
+		delete(m.network, workspaceID)
+	}
+}
+
+func botfilePolicy(file botfile.File) agent.Policy {
+	return agent.Policy{
+		MaxLoopSteps:              file.Policies.MaxLoopSteps,
+		MaxTurnDuration:           time.Duration(file.Policies.MaxTurnSeconds) * time.Second,
+		MaxToolCallsPerTurn:       file.Policies.MaxToolCallsPerTurn,
+		AllowedTools:              file.Tools.Allow,
+		AllowedToolClasses:        file.Tools.Classes,
+		MaxAutonomousTasksPerHour: file.Policies.MaxAutonomousTasksPerHour,
+		MaxAutonomousTasksPerDay:  file.Policies.MaxAutonomousTasksPerDay,
+		MinFinalConfidence:        file.Policies.MinFinalConfidence,
+	}
+}
+
+// writeMarkdown writes the persona to the workspace soul file and the FAQ
+// to context/FAQ.md. Files are only rewritten when their content changes so
+// an unchanged botfile does not trigger a reindex.
+func (m *botfileManager) writeMarkdown(workspaceID string, previous, next botfile.File) error {
+	workspaceDir := filepath.Join(m.workspaceRoot, workspaceID)
+	if next.Persona != "" && m.soulRelPath != "" {
+		if err := writeFileIfChanged(filepath.Join(workspaceDir, filepath.FromSlash(m.soulRelPath)), next.Persona+"\n"); err != nil {
+			return fmt.Errorf("write persona: %w", err)
+		}
+	}
+	faqPath := filepath.Join(workspaceDir, filepath.FromSlash(botfileFAQRelPath))
+	if len(next.FAQ) > 0 {
+		if err := writeFileIfChanged(faqPath, botfile.RenderFAQ(next.FAQ)); err != nil {
+			return fmt.Errorf("write faq: %w", err)
+		}
+	} else if len(previous.FAQ) > 0 {
+		if err := os.Remove(faqPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove faq: %w", err)
+		}
+	}
+	return nil
+}
+
+func writeFileIfChanged(path, content string) error {
+	if existing, err := os.ReadFile(path); err == nil && string(existing) == content {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// reconcileObjectives makes the workspace's botfile-owned objectives match
+// the file: missing ones are created, edited ones updated and dropped ones
+// moved to the trash.
+func (m *botfileManager) reconcileObjectives(ctx context.Context, workspaceID string, wanted []botfile.Objective) error {
+	existing, err := m.store.ListObjectives(ctx, store.ListObjectivesInput{
+		WorkspaceID: workspaceID,
+		Limit:       m.objectiveLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("list objectives: %w", err)
+	}
+	owned := map[string]store.Objective{}
+	for _, objective := range existing {
+		if key, ok := strings.CutPrefix(objective.ContextID, botfileObjectivePrefix); ok {
+			owned[key] = objective
+		}
+	}
+	keep := map[string]bool{}
+	for _, objective := range wanted {
+		keep[objective.Key] = true
+		triggerType := store.ObjectiveTriggerEvent
+		if objective.Cron != "" {
+			triggerType = store.ObjectiveTriggerSchedule
+		}
+		active := objective.IsActive()
+		current, ok := owned[objective.Key]
+		if !ok {
+			if _, err := m.store.CreateObjective(ctx, store.CreateObjectiveInput{
+				WorkspaceID: workspaceID,
+				ContextID:   botfileObjectivePrefix + objective.Key,
+				Title:       objective.Title,
+				Prompt:      objective.Prompt,
+				TriggerType: triggerType,
+				EventKey:    objective.Event,
+				CronExpr:    objective.Cron,
+				Timezone:    objective.Timezone,
+				Active:      &active,
+			}); err != nil {
+				return fmt.Errorf("create objective %s: %w", objective.Key, err)
+			}
+			continue
+		}
+		if botfileObjectiveMatches(current, objective) {
+			continue
+		}
+		input := store.UpdateObjectiveInput{
+			ID:          current.ID,
+			Title:       &objective.Title,
+			Prompt:      &objective.Prompt,
+			TriggerType: &triggerType,
+			EventKey:    &objective.Event,
+			CronExpr:    &objective.Cron,
+			Timezone:    &objective.Timezone,
+			Active:      &active,
+		}
+		if triggerType == store.ObjectiveTriggerSchedule {
+			nextRun, err := store.ComputeScheduleNextRunForTimezone(objective.Cron, objective.Timezone, m.now())
+			if err != nil {
+				return fmt.Errorf("schedule objective %s: %w", objective.Key, err)
+			}
+			input.NextRunAt = &nextRun
+		}
+		if _, err := m.store.UpdateObjective(ctx, input); err != nil {
+			return fmt.Errorf("update objective %s: %w", objective.Key, err)
+		}
+	}
+	for key, objective := range owned {
+		if keep[key] {
+			continue
+		}
+		if err := m.store.DeleteObjective(ctx, objective.ID, 0); err != nil {
+			return fmt.Errorf("delete objective %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func botfileObjectiveMatches(current store.Objective, wanted botfile.Objective) bool {
+	return len(botfileObjectiveDrift(current, wanted)) == 0
+}
+
+// botfileObjectiveDrift names the fields where a live objective differs
+// from its botfile declaration.
+func botfileObjectiveDrift(current store.Objective, wanted botfile.Objective) []string {
+	timezone := wanted.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	fields := []string{}
+	if current.Title != wanted.Title {
+		fields = append(fields, "title")
+	}
+	if current.Prompt != wanted.Prompt {
+		fields = append(fields, "prompt")
+	}
+	if current.CronExpr != wanted.Cron {
+		fields = append(fields, "cron")
+	}
+	if wanted.Cron != "" && current.Timezone != timezone {
+		fields = append(fields, "timezone")
+	}
+	if current.EventKey != wanted.Event {
+		fields = append(fields, "event")
+	}
+	if current.Active != wanted.IsActive() {
+		fields = append(fields, "active")
+	}
+	return fields
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/botfile"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// Reconcile compares the live state of a workspace with the botfile it last
+// applied and, when fix is set, restores the declared state. An empty
+// workspace id checks every workspace with an applied botfile.
+func (m *botfileManager) Reconcile(ctx context.Context, workspaceID string, fix bool) ([]botfile.DriftReport, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID != "" {
+		report, err := m.reconcileWorkspace(ctx, workspaceID, fix)
+		if err != nil {
+			return nil, err
+		}
+		return []botfile.DriftReport{report}, nil
+	}
+	entries, err := os.ReadDir(m.workspaceRoot)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []botfile.DriftReport{}, nil
+		}
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	reports := []botfile.DriftReport{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		report, err := m.reconcileWorkspace(ctx, entry.Name(), fix)
+		if errors.Is(err, store.ErrBotfileNotFound) {
+			continue
+		}
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func (m *botfileManager) reconcileWorkspace(ctx context.Context, workspaceID string, fix bool) (botfile.DriftReport, error) {
+	m.applyMu.Lock()
+	defer m.applyMu.Unlock()
+
+	record, err := m.store.LookupWorkspaceBotfile(ctx, workspaceID)
+	if err != nil {
+		return botfile.DriftReport{}, err
+	}
+	if record.Status == store.BotfileRemoved || strings.TrimSpace(record.Content) == "" {
+		// Nothing is declared any more, so there is nothing to drift from.
+		return botfile.DriftReport{}, store.ErrBotfileNotFound
+	}
+	file, err := botfile.Parse([]byte(record.Content))
+	if err != nil {
+		return botfile.DriftReport{}, fmt.Errorf("parse applied botfile: %w", err)
+	}
+	report := botfile.DriftReport{WorkspaceID: workspaceID, CheckedAt: m.now()}
+	report.Drift, err = m.detectDrift(ctx, workspaceID, file)
+	if err != nil {
+		return botfile.DriftReport{}, err
+	}
+	outstanding := report.Drift
+	if fix && len(report.Drift) > 0 {
+		if err := m.writeMarkdown(workspaceID, file, file); err != nil {
+			return botfile.DriftReport{}, err
+		}
+		if err := m.reconcileObjectives(ctx, workspaceID, file.Objectives); err != nil {
+			return botfile.DriftReport{}, err
+		}
+		m.setPolicy(workspaceID, file)
+		report.Fixed = true
+		outstanding = nil
+		m.logger.Info("botfile drift fixed", "workspace_id", workspaceID, "drift", strings.Join(report.Drift, "; "))
+	} else if len(report.Drift) > 0 {
+		m.logger.Warn("botfile drift detected", "workspace_id", workspaceID, "drift", strings.Join(report.Drift, "; "))
+	}
+	if err := m.store.RecordBotfileDrift(ctx, workspaceID, outstanding, report.CheckedAt); err != nil {
+		return botfile.DriftReport{}, err
+	}
+	return report, nil
+}
+
+// detectDrift lists where the workspace no longer matches the applied
+// botfile: generated markdown edited by hand, objectives missing, edited or
+// added under the botfile's ownership, and policies that are not loaded.
+func (m *botfileManager) detectDrift(ctx context.Context, workspaceID string, file botfile.File) ([]string, error) {
+	drift := []string{}
+	workspaceDir := filepath.Join(m.workspaceRoot, workspaceID)
+	if file.Persona != "" && m.soulRelPath != "" {
+		if line := markdownDrift("persona", filepath.Join(workspaceDir, filepath.FromSlash(m.soulRelPath)), m.soulRelPath, file.Persona+"\n"); line != "" {
+			drift = append(drift, line)
+		}
+	}
+	if len(file.FAQ) > 0 {
+		if line := markdownDrift("faq", filepath.Join(workspaceDir, filepath.FromSlash(botfileFAQRelPath)), botfileFAQRelPath, botfile.RenderFAQ(file.FAQ)); line != "" {
+			drift = append(drift, line)
+		}
+	}
+
+	existing, err := m.store.ListObjectives(ctx, store.ListObjectivesInput{
+		WorkspaceID: workspaceID,
+		Limit:       m.objectiveLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list objectives: %w", err)
+	}
+	owned := map[string]store.Objective{}
+	for _, objective := range existing {
+		if key, ok := strings.CutPrefix(objective.ContextID, botfileObjectivePrefix); ok {
+			owned[key] = objective
+		}
+	}
+	declared := map[string]bool{}
+	for _, objective := range file.Objectives {
+		declared[objective.Key] = true
+		current, ok := owned[objective.Key]
+		if !ok {
+			drift = append(drift, "objectives: missing "+objective.Key)
+			continue
+		}
+		if fields := botfileObjectiveDrift(current, objective); len(fields) > 0 {
+			drift = append(drift, fmt.Sprintf("objectives: changed %s (%s)", objective.Key, strings.Join(fields, ", ")))
+		}
+	}
+	undeclared := []string{}
+	for key := range owned {
+		if !declared[key] {
+			undeclared = append(undeclared, key)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, key := range undeclared {
+		drift = append(drift, "objectives: undeclared "+key)
+	}
+
+	m.mu.RLock()
+	live, loaded := m.policies[workspaceID]
+	m.mu.RUnlock()
+	if !loaded || !reflect.DeepEqual(live, botfilePolicy(file)) {
+		drift = append(drift, "policies: live policy differs from the botfile")
+	}
+	return drift, nil
+}
+
+func markdownDrift(field, path, relPath, want string) string {
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return field + ": " + relPath + " is missing"
+	}
+	if err != nil || string(existing) != want {
+		return field + ": " + relPath + " was edited outside the botfile"
+	}
+	return ""
+}
+
+type botfileReconciler interface {
+	Reconcile(ctx context.Context, workspaceID string, fix bool) ([]botfile.DriftReport, error)
+}
+
+// runBotfileReconcileLoop periodically checks every workspace for drift from
+// its botfile and, when fix is set, restores the declared state.
+func runBotfileReconcileLoop(ctx context.Context, reconciler botfileReconciler, interval time.Duration, fix bool, logger *slog.Logger) error {
+	if reconciler == nil || interval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := reconciler.Reconcile(ctx, "", fix); err != nil {
+				logger.Error("botfile reconcile failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestBotfileManagerAppliesAndKeepsLastGoodVersion(t *testing.T) {
+	ctx := context.Background()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "botfile_test.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	root := t.TempDir()
+	workspaceDir := filepath.Join(root, "ws-1")
+	if err := os.MkdirAll(workspaceDir, 0o755); err != nil {
+		t.Fatalf("mkdir workspace: %v", err)
+	}
+	writeBotfile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(workspaceDir, "botfile.yaml"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write botfile: %v", err)
+		}
+	}
+	manager := newBotfileManager(root, "context/SOUL.md", sqlStore, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	writeBotfile(`
+version: 1
+persona: You are the release desk assistant.
+tools:
+  allow: [search_knowledge_base]
+policies:
+  max_tool_calls_per_turn: 3
+objectives:
+  - key: nightly-digest
+    prompt: Summarize failed deployments.
+    cron: "0 7 * * *"
+  - key: docs-watch
+    prompt: Review changed runbooks.
+    event: markdown.updated
+faq:
+  - question: Who owns releases?
+    answer: The release manager.
+`)
+	record, err := manager.Apply(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if record.Status != store.BotfileApplied || len(record.Changes) == 0 {
+		t.Fatalf("unexpected record %+v", record)
+	}
+	soul, err := os.ReadFile(filepath.Join(workspaceDir, "context", "SOUL.md"))
+	if err != nil || !strings.Contains(string(soul), "release desk") {
+		t.Fatalf("expected persona in soul file, got %q (%v)", soul, err)
+	}
+	if _, err := os.Stat(filepath.Join(workspaceDir, "context", "FAQ.md")); err != nil {
+		t.Fatalf("expected faq markdown: %v", err)
+	}
+	policy := manager.Policy(ctx, llm.MessageInput{WorkspaceID: "ws-1"})
+	if policy.MaxToolCallsPerTurn != 3 || len(policy.AllowedTools) != 1 {
+		t.Fatalf("unexpected policy %+v", policy)
+	}
+	if worker := manager.ToolPolicy(ctx, llm.MessageInput{WorkspaceID: "ws-1"}); worker.MaxToolCallsPerTurn != 0 || len(worker.AllowedTools) != 1 {
+		t.Fatalf("expected worker policy to carry tools only, got %+v", worker)
+	}
+	objectives, err := sqlStore.ListObjectives(ctx, store.ListObjectivesInput{WorkspaceID: "ws-1"})
+	if err != nil || len(objectives) != 2 {
+		t.Fatalf("expected two botfile objectives, got %+v (%v)", objectives, err)
+	}
+
+	// Rename one objective, drop the other and the FAQ.
+	writeBotfile(`
+version: 1
+persona: You are the release desk assistant.
+tools:
+  allow: [search_knowledge_base]
+objectives:
+  - key: nightly-digest
+    title: Morning digest
+    prompt: Summarize failed deployments.
+    cron: "30 6 * * *"
+`)
+	record, err = manager.Apply(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("reapply: %v", err)
+	}
+	if !strings.Contains(strings.Join(record.Changes, "\n"), "objectives: removed docs-watch") {
+		t.Fatalf("expected diff to report the removed objective, got %v", record.Changes)
+	}
+	objectives, err = sqlStore.ListObjectives(ctx, store.ListObjectivesInput{WorkspaceID: "ws-1"})
+	if err != nil || len(objectives) != 1 {
+		t.Fatalf("expected one objective after reapply, got %+v (%v)", objectives, err)
+	}
+	if objectives[0].Title != "Morning digest" || objectives[0].CronExpr != "30 6 * * *" || objectives[0].ID == "" {
+		t.Fatalf("expected the existing objective to be updated in place, got %+v", objectives[0])
+	}
+	if _, err := os.Stat(filepath.Join(workspaceDir, "context", "FAQ.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected generated faq to be removed, got %v", err)
+	}
+
+	writeBotfile("version: 1\ntools:\n  classes: [wizardry]\n")
+	record, err = manager.Apply(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("apply invalid: %v", err)
+	}
+	if record.Status != store.BotfileInvalid || !strings.Contains(record.Error, "wizardry") {
+		t.Fatalf("expected invalid status, got %+v", record)
+	}
+	if policy := manager.Policy(ctx, llm.MessageInput{WorkspaceID: "ws-1"}); len(policy.AllowedTools) != 1 {
+		t.Fatalf("expected the last applied policy to stay in effect, got %+v", policy)
+	}
+	if !isWorkspaceBotfilePath(root, filepath.Join(workspaceDir, "botfile.yaml")) || isWorkspaceBotfilePath(root, filepath.Join(workspaceDir, "docs", "botfile.yaml")) {
+		t.Fatal("expected only the workspace root botfile to match")
+	}
+}
+
+func TestBotfileManagerReconcileReportsAndFixesDrift(t *testing.T) {
+	ctx := context.Background()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "botfile_reconcile_test.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	root := t.TempDir()
+	workspaceDir := filepath.Join(root, "ws-1")
+	if err := os.MkdirAll(workspaceDir, 0o755); err != nil {
+		t.Fatalf("mkdir workspace: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "ws-plain"), 0o755); err != nil {
+		t.Fatalf("mkdir plain workspace: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspaceDir, "botfile.yaml"), []byte(`
+version: 1
+persona: You are the release desk assistant.
+objectives:
+  - key: nightly-digest
+    prompt: Summarize failed deployments.
+    cron: "0 7 * * *"
+  - key: docs-watch
+    prompt: Review changed runbooks.
+    event: markdown.updated
+`), 0o644); err != nil {
+		t.Fatalf("write botfile: %v", err)
+	}
+	manager := newBotfileManager(root, "context/SOUL.md", sqlStore, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if _, err := manager.Apply(ctx, "ws-1"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	reports, err := manager.Reconcile(ctx, "", false)
+	if err != nil {
+		t.Fatalf("reconcile clean: %v", err)
+	}
+	if len(reports) != 1 || len(reports[0].Drift) != 0 {
+		t.Fatalf("expected one in-sync workspace, got %+v", reports)
+	}
+
+	// Drift the live state by hand: pause one objective, trash the other,
+	// add a rogue botfile-owned objective and edit the persona.
+	objectives, err := sqlStore.ListObjectives(ctx, store.ListObjectivesInput{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatalf("list objectives: %v", err)
+	}
+	paused := false
+	for _, objective := range objectives {
+		switch objective.ContextID {
+		case "botfile:nightly-digest":
+			if _, err := sqlStore.UpdateObjective(ctx, store.UpdateObjectiveInput{ID: objective.ID, Active: &paused}); err != nil {
+				t.Fatalf("pause objective: %v", err)
+			}
+		case "botfile:docs-watch":
+			if err := sqlStore.DeleteObjective(ctx, objective.ID, 0); err != nil {
+				t.Fatalf("trash objective: %v", err)
+			}
+		}
+	}
+	if _, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "botfile:rogue",
+		Title:       "rogue",
+		Prompt:      "Not in the botfile.",
+		TriggerType: store.ObjectiveTriggerEvent,
+		EventKey:    "markdown.updated",
+	}); err != nil {
+		t.Fatalf("create rogue objective: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspaceDir, "context", "SOUL.md"), []byte("Hand edited.\n"), 0o644); err != nil {
+		t.Fatalf("edit soul: %v", err)
+	}
+
+	reports, err = manager.Reconcile(ctx, "ws-1", false)
+	if err != nil {
+		t.Fatalf("reconcile drift: %v", err)
+	}
+	got := strings.Join(reports[0].Drift, "\n")
+	want := strings.Join([]string{
+		"persona: context/SOUL.md was edited outside the botfile",
+		"objectives: changed nightly-digest (active)",
+		"objectives: missing docs-watch",
+		"objectives: undeclared rogue",
+	}, "\n")
+	if got != want || reports[0].Fixed {
+		t.Fatalf("unexpected drift:\n%s\nwant:\n%s", got, want)
+	}
+	record, err := sqlStore.LookupWorkspaceBotfile(ctx, "ws-1")
+	if err != nil || len(record.Drift) != 4 || record.DriftCheckedAt.IsZero() {
+		t.Fatalf("expected drift to be recorded, got %+v (%v)", record, err)
+	}
+
+	reports, err = manager.Reconcile(ctx, "ws-1", true)
+	if err != nil {
+		t.Fatalf("reconcile fix: %v", err)
+	}
+	if !reports[0].Fixed || len(reports[0].Drift) != 4 {
+		t.Fatalf("expected drift to be fixed, got %+v", reports[0])
+	}
+	reports, err = manager.Reconcile(ctx, "ws-1", false)
+	if err != nil || len(reports[0].Drift) != 0 {
+		t.Fatalf("expected no drift after fixing, got %+v (%v)", reports, err)
+	}
+	if soul, _ := os.ReadFile(filepath.Join(workspaceDir, "context", "SOUL.md")); !strings.Contains(string(soul), "release desk") {
+		t.Fatalf("expected persona to be restored, got %q", soul)
+	}
+	if _, err := manager.Reconcile(ctx, "ws-plain", false); !errors.Is(err, store.ErrBotfileNotFound) {
+		t.Fatalf("expected workspace without a botfile to be reported missing, got %v", err)
+	}
+}
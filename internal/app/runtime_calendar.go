@@ -0,0 +1,27 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/dwizi/agent-runtime/internal/calendar"
+	"github.com/dwizi/agent-runtime/internal/config"
+)
+
+// newCalendarClient builds the CalDAV or Google Calendar client, or returns
+// nil when no calendar provider is configured.
+func newCalendarClient(cfg config.Config) (calendar.Client, error) {
+	client, err := calendar.New(calendar.Config{
+		Provider:           cfg.CalendarProvider,
+		CalDAVURL:          cfg.CalDAVURL,
+		CalDAVUsername:     cfg.CalDAVUsername,
+		CalDAVPassword:     cfg.CalDAVPassword,
+		GoogleCalendarID:   cfg.GoogleCalendarID,
+		GoogleClientID:     cfg.GoogleClientID,
+		GoogleClientSecret: cfg.GoogleClientSecret,
+		GoogleRefreshToken: cfg.GoogleRefreshToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configure calendar: %w", err)
+	}
+	return client, nil
+}
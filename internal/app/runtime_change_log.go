@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const changeLogPruneInterval = time.Hour
+
+type changeLogStore interface {
+	EnableChangeLog(ctx context.Context) error
+	DisableChangeLog(ctx context.Context) error
+}
+
+type changeLogPruner interface {
+	PruneChangeLog(ctx context.Context, before time.Time) (int, error)
+}
+
+// configureChangeLog installs or removes the change capture triggers so the
+// database matches the current setting after every restart.
+func configureChangeLog(ctx context.Context, changeLog changeLogStore, enabled bool) error {
+	if enabled {
+		return changeLog.EnableChangeLog(ctx)
+	}
+	return changeLog.DisableChangeLog(ctx)
+}
+
+// runChangeLogPruneLoop drops change log entries older than the retention
+// window.
+func runChangeLogPruneLoop(ctx context.Context, pruner changeLogPruner, retentionDays int, interval time.Duration, logger *slog.Logger) error {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if retentionDays < 1 {
+		retentionDays = 30
+	}
+	if interval <= 0 {
+		interval = changeLogPruneInterval
+	}
+	prune := func() {
+		cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
+		pruned, err := pruner.PruneChangeLog(ctx, cutoff)
+		if err != nil {
+			logger.Error("change log prune failed", "error", err)
+			return
+		}
+		if pruned > 0 {
+			logger.Info("pruned change log", "count", pruned)
+		}
+	}
+	prune()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			prune()
+		}
+	}
+}
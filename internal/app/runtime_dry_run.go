@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/github"
+	"github.com/dwizi/agent-runtime/internal/mcp"
+)
+
+// Dry-run mode (AGENT_RUNTIME_DRY_RUN) keeps the runtime reading real data
+// while every outbound side effect is logged instead of run. The action
+// executor, event bus and gateway simulate their own effects; the wrappers
+// below cover the external clients the runtime injects.
+
+const dryRunURL = "(dry run: not sent)"
+
+// dryRunGitHubClient reads from GitHub but does not create issues or
+// comments.
+type dryRunGitHubClient struct {
+	gateway.GitHubClient
+	logger *slog.Logger
+}
+
+func (c dryRunGitHubClient) CreateIssue(ctx context.Context, workspaceID string, input github.CreateIssueInput) (github.Issue, error) {
+	c.logger.Info("dry run: github issue not created", "workspace_id", workspaceID, "repo", input.Repo, "title", input.Title)
+	return github.Issue{Title: input.Title, State: "open", URL: dryRunURL, Labels: input.Labels}, nil
+}
+
+func (c dryRunGitHubClient) CommentOnPullRequest(ctx context.Context, workspaceID, repo string, number int, body string) (github.Comment, error) {
+	c.logger.Info("dry run: pull request comment not posted", "workspace_id", workspaceID, "repo", repo, "number", number, "bytes", len(body))
+	return github.Comment{URL: dryRunURL}, nil
+}
+
+// dryRunMCPRuntime lists and reads from MCP servers but does not call their
+// tools, whose effects the runtime cannot know.
+type dryRunMCPRuntime struct {
+	gateway.MCPRuntime
+	logger *slog.Logger
+}
+
+func (r dryRunMCPRuntime) CallTool(ctx context.Context, input mcp.CallToolInput) (mcp.ToolCallResult, error) {
+	r.logger.Info("dry run: mcp tool not called", "workspace_id", input.WorkspaceID, "server_id", input.ServerID, "tool", input.ToolName, "args_bytes", len(input.Args))
+	return mcp.ToolCallResult{Message: "Dry run: " + input.ServerID + "/" + input.ToolName + " was not called."}, nil
+}
+
+// dryRunTaskSyncer does not create or update Jira or Linear issues.
+type dryRunTaskSyncer struct {
+	logger *slog.Logger
+}
+
+func (s dryRunTaskSyncer) SyncTask(ctx context.Context, taskID string) error {
+	s.logger.Info("dry run: issue tracker not synced", "task_id", taskID)
+	return nil
+}
+
+// dryRunStatusPublisher renders status pages without writing or uploading
+// them.
+type dryRunStatusPublisher struct {
+	logger *slog.Logger
+}
+
+func (p dryRunStatusPublisher) Publish(ctx context.Context, name, contentType string, body []byte) error {
+	p.logger.Info("dry run: status page not published", "name", name, "content_type", contentType, "bytes", len(body))
+	return nil
+}
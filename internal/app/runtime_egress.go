@@ -0,0 +1,32 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/egress"
+)
+
+// newEgressGuard builds the runtime-wide egress policy; workspace rules are
+// added once the botfile manager exists.
+func newEgressGuard(cfg config.Config) (*egress.Guard, error) {
+	rules := egress.Rules{
+		AllowDomains: parseCSVList(cfg.EgressAllowDomainsCSV),
+		DenyDomains:  parseCSVList(cfg.EgressDenyDomainsCSV),
+		AllowCIDRs:   parseCSVTrimList(cfg.EgressAllowCIDRsCSV),
+		DenyCIDRs:    parseCSVTrimList(cfg.EgressDenyCIDRsCSV),
+	}
+	var err error
+	if rules.AllowPorts, err = egress.ParsePorts(cfg.EgressAllowPortsCSV); err != nil {
+		return nil, fmt.Errorf("configure egress policy: AGENT_RUNTIME_EGRESS_ALLOW_PORTS: %w", err)
+	}
+	if rules.DenyPorts, err = egress.ParsePorts(cfg.EgressDenyPortsCSV); err != nil {
+		return nil, fmt.Errorf("configure egress policy: AGENT_RUNTIME_EGRESS_DENY_PORTS: %w", err)
+	}
+	rules.Normalize()
+	if problems := rules.Validate(); len(problems) > 0 {
+		return nil, fmt.Errorf("configure egress policy: %s", strings.Join(problems, "; "))
+	}
+	return egress.New(egress.Config{Global: rules, AllowPrivate: cfg.EgressAllowPrivate}), nil
+}
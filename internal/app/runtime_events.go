@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/egress"
+	"github.com/dwizi/agent-runtime/internal/eventbus"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
+)
+
+// newEventBus builds the event bus from AGENT_RUNTIME_EVENT_SINKS and routes
+// every event to the per-workspace webhook subscriptions as well, so the
+// bus runs even when no global sinks are configured.
+func newEventBus(cfg config.Config, subscriptions eventbus.SubscriptionStore, guard *egress.Guard, logger *slog.Logger) (*eventbus.Bus, error) {
+	sinks, err := eventbus.ParseSinks(cfg.EventSinksCSV, eventbus.SinkOptions{
+		WebhookSecret:     cfg.EventWebhookSecret,
+		NATSSubjectPrefix: cfg.EventNATSSubjectPrefix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configure event sinks: AGENT_RUNTIME_EVENT_SINKS: %w", err)
+	}
+	types, err := eventbus.ParseTypes(cfg.EventTypesCSV)
+	if err != nil {
+		return nil, fmt.Errorf("configure event sinks: AGENT_RUNTIME_EVENT_TYPES: %w", err)
+	}
+	bus := eventbus.New(eventbus.Config{Types: types, BufferSize: cfg.EventBufferSize}, sinks, logger)
+	subscriptionSink := eventbus.NewSubscriptionSink(subscriptions, logger.With("sink", "webhook-subscriptions"))
+	subscriptionSink.SetEgressGuard(guard)
+	bus.Route(subscriptionSink)
+	return bus, nil
+}
+
+// objectiveEventHandler runs the objectives listening for an event.
+type objectiveEventHandler interface {
+	HandleEvent(ctx context.Context, event scheduler.Event) ([]scheduler.FiredObjective, error)
+}
+
+// objectiveTriggerSink feeds runtime events to the event-triggered
+// objectives, so an objective with event key approval.pending runs whenever
+// an approval is requested. Objective runs do not trigger objectives: their
+// objective.fired and task.created events are skipped, which keeps an
+// objective from firing itself.
+type objectiveTriggerSink struct {
+	objectives objectiveEventHandler
+	logger     *slog.Logger
+}
+
+func newObjectiveTriggerSink(objectives objectiveEventHandler, logger *slog.Logger) *objectiveTriggerSink {
+	return &objectiveTriggerSink{objectives: objectives, logger: logger}
+}
+
+func (s *objectiveTriggerSink) Name() string {
+	return "objective-triggers"
+}
+
+// Deliver keys the runs by the event id, so a delivery the bus retries
+// queues each objective once.
+func (s *objectiveTriggerSink) Deliver(ctx context.Context, event eventbus.Event) error {
+	if event.Type == eventbus.TypeObjectiveFired {
+		return nil
+	}
+	if kind, _ := event.Data["kind"].(string); event.Type == eventbus.TypeTaskCreated && kind == string(orchestrator.TaskKindObjective) {
+		return nil
+	}
+	fired, err := s.objectives.HandleEvent(ctx, scheduler.Event{
+		Key:         event.Type,
+		WorkspaceID: event.WorkspaceID,
+		DedupeKey:   event.ID,
+		Data:        event.Data,
+	})
+	if err != nil {
+		return err
+	}
+	for _, objective := range fired {
+		if objective.Error != "" {
+			s.logger.Warn("event objective not queued", "event", event.Type, "objective_id", objective.ObjectiveID, "error", objective.Error)
+		}
+	}
+	return nil
+}
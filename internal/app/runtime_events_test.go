@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/eventbus"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
+)
+
+type recordingObjectiveEvents struct {
+	events []scheduler.Event
+}
+
+func (r *recordingObjectiveEvents) HandleEvent(ctx context.Context, event scheduler.Event) ([]scheduler.FiredObjective, error) {
+	r.events = append(r.events, event)
+	return nil, nil
+}
+
+func TestObjectiveTriggerSinkSkipsObjectiveRuns(t *testing.T) {
+	handler := &recordingObjectiveEvents{}
+	sink := newObjectiveTriggerSink(handler, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	events := []eventbus.Event{
+		{ID: "evt_1", Type: eventbus.TypeApprovalPending, WorkspaceID: "ws-1", Data: map[string]any{"approval_id": "ap-1"}},
+		{ID: "evt_2", Type: eventbus.TypeObjectiveFired, WorkspaceID: "ws-1"},
+		{ID: "evt_3", Type: eventbus.TypeTaskCreated, WorkspaceID: "ws-1", Data: map[string]any{"kind": "objective"}},
+		{ID: "evt_4", Type: eventbus.TypeTaskCreated, WorkspaceID: "ws-1", Data: map[string]any{"kind": "general"}},
+	}
+	for _, event := range events {
+		if err := sink.Deliver(context.Background(), event); err != nil {
+			t.Fatalf("deliver %s: %v", event.Type, err)
+		}
+	}
+	if len(handler.events) != 2 {
+		t.Fatalf("expected two events handed to objectives, got %+v", handler.events)
+	}
+	first := handler.events[0]
+	if first.Key != eventbus.TypeApprovalPending || first.WorkspaceID != "ws-1" || first.DedupeKey != "evt_1" || first.Data["approval_id"] != "ap-1" {
+		t.Fatalf("unexpected event %+v", first)
+	}
+	if handler.events[1].DedupeKey != "evt_4" {
+		t.Fatalf("expected the general task event, got %+v", handler.events[1])
+	}
+}
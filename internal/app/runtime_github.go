@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/github"
+)
+
+// configureGitHub enables the GitHub tools when a GitHub App is configured.
+// Without an app id the tools stay registered but report that they are off.
+// In dry-run mode the tools still read from GitHub but do not write to it.
+func configureGitHub(commandGateway *gateway.Service, cfg config.Config, dryRunLogger *slog.Logger) error {
+	if cfg.GitHubAppID <= 0 {
+		return nil
+	}
+	privateKey := []byte(strings.TrimSpace(cfg.GitHubAppPrivateKey))
+	if len(privateKey) == 0 {
+		if strings.TrimSpace(cfg.GitHubAppPrivateKeyFile) == "" {
+			return fmt.Errorf("configure github: AGENT_RUNTIME_GITHUB_APP_PRIVATE_KEY or AGENT_RUNTIME_GITHUB_APP_PRIVATE_KEY_FILE is required")
+		}
+		content, err := github.LoadPrivateKey(cfg.GitHubAppPrivateKeyFile)
+		if err != nil {
+			return fmt.Errorf("configure github: %w", err)
+		}
+		privateKey = content
+	}
+	client, err := github.New(github.Config{
+		AppID:         int64(cfg.GitHubAppID),
+		PrivateKeyPEM: privateKey,
+		APIBase:       cfg.GitHubAPIBase,
+		WorkspaceRoot: cfg.WorkspaceRoot,
+	})
+	if err != nil {
+		return fmt.Errorf("configure github: %w", err)
+	}
+	if cfg.DryRun {
+		commandGateway.SetGitHubClient(dryRunGitHubClient{GitHubClient: client, logger: dryRunLogger})
+		return nil
+	}
+	commandGateway.SetGitHubClient(client)
+	return nil
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/integrations"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// newTaskSyncer builds the Jira or Linear syncer for routed issue tasks.
+// It returns nil when no issue sync provider is configured, and a syncer
+// that only logs in dry-run mode.
+func newTaskSyncer(storeRef *store.Store, cfg config.Config, dryRunLogger *slog.Logger) (gateway.TaskSyncer, error) {
+	tracker, err := integrations.NewTracker(integrations.Config{
+		Provider:       cfg.IssueSyncProvider,
+		JiraBaseURL:    cfg.JiraBaseURL,
+		JiraEmail:      cfg.JiraEmail,
+		JiraAPIToken:   cfg.JiraAPIToken,
+		JiraProjectKey: cfg.JiraProjectKey,
+		JiraIssueType:  cfg.JiraIssueType,
+		LinearAPIKey:   cfg.LinearAPIKey,
+		LinearTeamID:   cfg.LinearTeamID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configure issue sync: %w", err)
+	}
+	if tracker == nil {
+		return nil, nil
+	}
+	if cfg.DryRun {
+		return dryRunTaskSyncer{logger: dryRunLogger}, nil
+	}
+	return integrations.NewSyncer(storeRef, tracker), nil
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/actions/plugins/kubernetes"
+	"github.com/dwizi/agent-runtime/internal/config"
+)
+
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// newKubernetesJobPlugin builds the Job executor, falling back to the pod's
+// service account when the API server, token or CA are not set explicitly.
+func newKubernetesJobPlugin(cfg config.Config) (*kubernetes.Plugin, error) {
+	apiServer := cfg.KubernetesAPIServer
+	if apiServer == "" {
+		host := strings.TrimSpace(os.Getenv("KUBERNETES_SERVICE_HOST"))
+		port := strings.TrimSpace(os.Getenv("KUBERNETES_SERVICE_PORT"))
+		if host != "" && port != "" {
+			apiServer = "https://" + net.JoinHostPort(host, port)
+		}
+	}
+	token := cfg.KubernetesToken
+	if token == "" {
+		if data, err := os.ReadFile(filepath.Join(serviceAccountDir, "token")); err == nil {
+			token = strings.TrimSpace(string(data))
+		}
+	}
+	caFile := cfg.KubernetesCAFile
+	if caFile == "" {
+		if _, err := os.Stat(filepath.Join(serviceAccountDir, "ca.crt")); err == nil {
+			caFile = filepath.Join(serviceAccountDir, "ca.crt")
+		}
+	}
+	plugin, err := kubernetes.New(kubernetes.Config{
+		APIServer:       apiServer,
+		Token:           token,
+		CACertFile:      caFile,
+		Image:           cfg.KubernetesJobImage,
+		AllowedCommands: parseCSVList(cfg.SandboxAllowedCommandsCSV),
+		NamespacePrefix: cfg.KubernetesNamespacePrefix,
+		CPULimit:        cfg.KubernetesCPULimit,
+		MemoryLimit:     cfg.KubernetesMemoryLimit,
+		QuotaCPU:        cfg.KubernetesQuotaCPU,
+		QuotaMemory:     cfg.KubernetesQuotaMemory,
+		QuotaPods:       cfg.KubernetesQuotaPods,
+		Timeout:         time.Duration(cfg.KubernetesJobTimeoutSec) * time.Second,
+		MaxLogBytes:     cfg.SandboxMaxOutputBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configure kubernetes jobs: %w", err)
+	}
+	return plugin, nil
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const memoryCompactionContextID = "system:memory-compaction"
+
+// runMemoryCompactionLoop queues a memory compaction task for every
+// workspace with chat logs, once at startup and then every interval. A
+// workspace that still has one queued or running is skipped.
+func runMemoryCompactionLoop(ctx context.Context, workspaceRoot string, sqlStore *store.Store, engine taskRecoveryEngine, interval time.Duration, logger *slog.Logger) error {
+	if sqlStore == nil || engine == nil {
+		<-ctx.Done()
+		return nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+	queue := func() {
+		queued := queueMemoryCompaction(ctx, workspaceRoot, sqlStore, engine, logger)
+		if queued > 0 {
+			logger.Info("queued memory compaction", "workspaces", queued)
+		}
+	}
+	queue()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			queue()
+		}
+	}
+}
+
+func queueMemoryCompaction(ctx context.Context, workspaceRoot string, sqlStore *store.Store, engine taskRecoveryEngine, logger *slog.Logger) int {
+	entries, err := os.ReadDir(workspaceRoot)
+	if err != nil {
+		logger.Error("failed to list workspaces for memory compaction", "error", err)
+		return 0
+	}
+	queued := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		workspaceID := entry.Name()
+		if info, err := os.Stat(filepath.Join(workspaceRoot, workspaceID, "logs", "chats")); err != nil || !info.IsDir() {
+			continue
+		}
+		pending, err := hasPendingTaskOfKind(ctx, sqlStore, workspaceID, orchestrator.TaskKindMemoryCompaction)
+		if err != nil {
+			logger.Error("failed to check pending memory compaction", "workspace_id", workspaceID, "error", err)
+			continue
+		}
+		if pending {
+			continue
+		}
+		task, err := engine.Enqueue(orchestrator.Task{
+			WorkspaceID: workspaceID,
+			ContextID:   memoryCompactionContextID,
+			Title:       "Compact chat memory",
+			Prompt:      "compact chat logs into rolling summaries",
+			Kind:        orchestrator.TaskKindMemoryCompaction,
+		})
+		if err != nil {
+			logger.Error("failed to enqueue memory compaction", "workspace_id", workspaceID, "error", err)
+			continue
+		}
+		if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+			ID:          task.ID,
+			WorkspaceID: task.WorkspaceID,
+			ContextID:   task.ContextID,
+			Kind:        string(task.Kind),
+			Title:       task.Title,
+			Prompt:      task.Prompt,
+			Status:      "queued",
+		}); err != nil {
+			logger.Error("failed to persist memory compaction task", "workspace_id", workspaceID, "task_id", task.ID, "error", err)
+		}
+		queued++
+	}
+	return queued
+}
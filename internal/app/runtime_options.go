@@ -0,0 +1,63 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// Option adjusts how New assembles the runtime. Options exist so tests can
+// swap external dependencies for fakes; production passes none.
+type Option func(*runtimeOptions)
+
+type runtimeOptions struct {
+	responder  llm.Responder
+	connectors []ConnectorFactory
+}
+
+// ConnectorDeps is what an extra connector gets to hand inbound messages to
+// the runtime. Responder is the grounded responder connectors use for
+// replies the gateway leaves unhandled.
+type ConnectorDeps struct {
+	Gateway   *gateway.Service
+	Store     *store.Store
+	Responder llm.Responder
+}
+
+// ConnectorFactory builds a connector once the gateway exists.
+type ConnectorFactory func(deps ConnectorDeps) connectors.Connector
+
+// WithResponder replaces the model provider selected by AGENT_RUNTIME_LLM_*.
+// The responder is still wrapped by quotas, degraded mode and prompt policy.
+func WithResponder(responder llm.Responder) Option {
+	return func(options *runtimeOptions) {
+		options.responder = responder
+	}
+}
+
+// WithConnector adds a connector next to the configured ones. When it also
+// implements connectors.Publisher it receives notices like any connector.
+func WithConnector(factory ConnectorFactory) Option {
+	return func(options *runtimeOptions) {
+		options.connectors = append(options.connectors, factory)
+	}
+}
+
+// Handler is the HTTP API, for calling it in-process.
+func (r *Runtime) Handler() http.Handler {
+	return r.httpServer.Handler
+}
+
+// Store is the runtime's database.
+func (r *Runtime) Store() *store.Store {
+	return r.store
+}
+
+// Engine is the runtime's task queue and worker pool.
+func (r *Runtime) Engine() *orchestrator.Engine {
+	return r.engine
+}
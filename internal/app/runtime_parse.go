@@ -1,6 +1,10 @@
 package app
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+)
 
 func parseCSVSet(input string) map[string]struct{} {
 	trimmed := strings.TrimSpace(input)
@@ -74,3 +78,22 @@ func parseShellArgs(input string) []string {
 	}
 	return strings.Fields(trimmed)
 }
+
+// workerPoolBounds reads the worker pool limits. An unset limit falls back
+// to the default concurrency, kept on the right side of the other limit.
+func workerPoolBounds(cfg config.Config) (int, int) {
+	min, max := cfg.WorkerPoolMin, cfg.WorkerPoolMax
+	if min < 1 {
+		min = cfg.DefaultConcurrency
+		if max > 0 && max < min {
+			min = max
+		}
+	}
+	if max < 1 {
+		max = cfg.DefaultConcurrency
+		if max < min {
+			max = min
+		}
+	}
+	return min, max
+}
@@ -100,6 +100,12 @@ func workspaceRelativeMarkdownPath(workspaceRoot, changedPath string) (string, b
 }
 
 func hasPendingReindexTask(ctx context.Context, sqlStore *store.Store, workspaceID string) (bool, error) {
+	return hasPendingTaskOfKind(ctx, sqlStore, workspaceID, orchestrator.TaskKindReindex)
+}
+
+// hasPendingTaskOfKind reports whether a workspace already has a queued or
+// running task of the given kind.
+func hasPendingTaskOfKind(ctx context.Context, sqlStore *store.Store, workspaceID string, taskKind orchestrator.TaskKind) (bool, error) {
 	if sqlStore == nil {
 		return false, nil
 	}
@@ -107,7 +113,7 @@ func hasPendingReindexTask(ctx context.Context, sqlStore *store.Store, workspace
 	if workspaceID == "" {
 		return false, nil
 	}
-	kind := string(orchestrator.TaskKindReindex)
+	kind := string(taskKind)
 	queued, err := sqlStore.ListTasks(ctx, store.ListTasksInput{
 		WorkspaceID: workspaceID,
 		Kind:        kind,
@@ -108,6 +108,9 @@ func recoverPendingTasks(
 			Kind:        orchestrator.TaskKind(strings.TrimSpace(item.Kind)),
 			Title:       item.Title,
 			Prompt:      item.Prompt,
+			DependsOn:   item.DependsOn,
+			Attempts:    item.Attempts,
+			RunAt:       item.RunAt,
 		})
 		if enqueueErr != nil {
 			logger.Error("failed to enqueue recovered task", "task_id", item.ID, "error", enqueueErr)
@@ -222,6 +225,8 @@ func recoverStaleRunningTasks(
 			Kind:        orchestrator.TaskKind(strings.TrimSpace(item.Kind)),
 			Title:       item.Title,
 			Prompt:      item.Prompt,
+			DependsOn:   item.DependsOn,
+			Attempts:    item.Attempts,
 		})
 		if enqueueErr != nil {
 			logger.Error("failed to enqueue stale requeued task", "task_id", taskID, "error", enqueueErr)
@@ -10,6 +10,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/dwizi/agent-runtime/internal/degrade"
 	"github.com/dwizi/agent-runtime/internal/heartbeat"
 )
 
@@ -28,15 +29,62 @@ func (r *Runtime) Run(ctx context.Context) error {
 			return r.engine.Start(runCtx)
 		})
 	})
+	if r.cfg.WorkerAutoscaleEnabled {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "worker-autoscaler", 0, func(runCtx context.Context) error {
+				return r.engine.Autoscale(runCtx, time.Duration(r.cfg.WorkerAutoscaleIntervalSec)*time.Second)
+			})
+		})
+	}
 	recoveryStaleAfter := time.Duration(r.cfg.TaskRecoveryRunningStaleSec) * time.Second
-	if err := recoverPendingTasks(groupCtx, r.store, r.engine, recoveryStaleAfter, r.logger.With("component", "task-recovery")); err != nil {
+	if r.cfg.TaskLeaseEnabled {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "task-queue", 20*time.Second, func(runCtx context.Context) error {
+				interval := time.Duration(r.cfg.TaskQueuePollSec) * time.Second
+				return runTaskQueuePollLoop(runCtx, r.store, r.engine, interval, r.logger.With("component", "task-queue"))
+			})
+		})
+	} else if err := recoverPendingTasks(groupCtx, r.store, r.engine, recoveryStaleAfter, r.logger.With("component", "task-recovery")); err != nil {
 		r.logger.Error("startup task recovery failed", "error", err)
 	}
+	if r.botfiles != nil {
+		r.botfiles.ApplyAll(groupCtx)
+	}
+	if !r.cfg.TaskLeaseEnabled {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "task-recovery", 20*time.Second, func(runCtx context.Context) error {
+				return runStaleTaskRecoveryLoop(runCtx, r.store, r.engine, recoveryStaleAfter, r.logger.With("component", "task-recovery-loop"))
+			})
+		})
+	}
 	group.Go(func() error {
-		return runMonitored(groupCtx, r.heartbeat, "task-recovery", 20*time.Second, func(runCtx context.Context) error {
-			return runStaleTaskRecoveryLoop(runCtx, r.store, r.engine, recoveryStaleAfter, r.logger.With("component", "task-recovery-loop"))
+		return runMonitored(groupCtx, r.heartbeat, "trash-purge", 0, func(runCtx context.Context) error {
+			return runTrashPurgeLoop(runCtx, r.store, trashPurgeInterval, r.logger.With("component", "trash-purge"))
 		})
 	})
+	if r.cfg.ChangeLogEnabled {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "change-log-prune", 0, func(runCtx context.Context) error {
+				return runChangeLogPruneLoop(runCtx, r.store, r.cfg.ChangeLogRetentionDays, changeLogPruneInterval, r.logger.With("component", "change-log-prune"))
+			})
+		})
+	}
+	if r.cfg.MemoryCompactionEnabled {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "memory-compaction", 0, func(runCtx context.Context) error {
+				interval := time.Duration(r.cfg.MemoryCompactionIntervalHours) * time.Hour
+				return runMemoryCompactionLoop(runCtx, r.cfg.WorkspaceRoot, r.store, r.engine, interval, r.logger.With("component", "memory-compaction"))
+			})
+		})
+	}
+	if r.botfiles != nil && r.cfg.BotfileReconcileIntervalMinutes > 0 {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "botfile-reconcile", 0, func(runCtx context.Context) error {
+				interval := time.Duration(r.cfg.BotfileReconcileIntervalMinutes) * time.Minute
+				return runBotfileReconcileLoop(runCtx, r.botfiles, interval, r.cfg.BotfileReconcileFix, r.logger.With("component", "botfile-reconcile"))
+			})
+		})
+	}
 	group.Go(func() error {
 		return runMonitored(groupCtx, r.heartbeat, "watcher", 0, func(runCtx context.Context) error {
 			return r.watcher.Start(runCtx)
@@ -68,11 +116,67 @@ func (r *Runtime) Run(ctx context.Context) error {
 			})
 		}
 	}
+	if r.outbox != nil {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "outbox", 0, func(runCtx context.Context) error {
+				return r.outbox.Start(runCtx)
+			})
+		})
+	}
+	if r.approvalExpiry != nil {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "approval-expiry", 0, func(runCtx context.Context) error {
+				return r.approvalExpiry.run(runCtx, approvalExpiryInterval)
+			})
+		})
+	}
+	if r.approvalDigest != nil {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "approval-digest", 0, func(runCtx context.Context) error {
+				return r.approvalDigest.run(runCtx, time.Duration(r.cfg.ApprovalDigestIntervalHours)*time.Hour)
+			})
+		})
+	}
+	if r.objectiveDigests != nil {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "objective-digests", 0, func(runCtx context.Context) error {
+				return r.objectiveDigests.run(runCtx, objectiveDigestInterval)
+			})
+		})
+	}
+	if r.statusPage != nil {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "status-page", 0, func(runCtx context.Context) error {
+				return r.statusPage.Start(runCtx)
+			})
+		})
+	}
+	if r.eventBus != nil {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "event-bus", 0, func(runCtx context.Context) error {
+				return r.eventBus.Start(runCtx)
+			})
+		})
+	}
+	if r.degradation != nil {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, degrade.ComponentName, 0, func(runCtx context.Context) error {
+				return r.degradation.Start(runCtx)
+			})
+		})
+	}
 	group.Go(func() error {
 		return runMonitored(groupCtx, r.heartbeat, "scheduler", 0, func(runCtx context.Context) error {
 			return r.scheduler.Start(runCtx)
 		})
 	})
+	if r.skillReview != nil {
+		group.Go(func() error {
+			return runMonitored(groupCtx, r.heartbeat, "skill-review", 0, func(runCtx context.Context) error {
+				return r.skillReview.Start(runCtx)
+			})
+		})
+	}
 	for _, conn := range r.connectors {
 		connector := conn
 		group.Go(func() error {
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/secrets"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// secretBackedFields maps secret names (the matching env var name) to the
+// config values they may fill in. Plain env values always take precedence.
+func secretBackedFields(cfg *config.Config) map[string]*string {
+	return map[string]*string{
+		"AGENT_RUNTIME_DISCORD_TOKEN":                    &cfg.DiscordToken,
+		"AGENT_RUNTIME_TELEGRAM_TOKEN":                   &cfg.TelegramToken,
+		"AGENT_RUNTIME_CODEX_PUBLISH_BEARER_TOKEN":       &cfg.CodexPublishBearerToken,
+		"AGENT_RUNTIME_IMAP_PASSWORD":                    &cfg.IMAPPassword,
+		"AGENT_RUNTIME_LLM_API_KEY":                      &cfg.LLMAPIKey,
+		"AGENT_RUNTIME_SMTP_PASSWORD":                    &cfg.SMTPPassword,
+		"AGENT_RUNTIME_GITHUB_APP_PRIVATE_KEY":           &cfg.GitHubAppPrivateKey,
+		"AGENT_RUNTIME_JIRA_API_TOKEN":                   &cfg.JiraAPIToken,
+		"AGENT_RUNTIME_LINEAR_API_KEY":                   &cfg.LinearAPIKey,
+		"AGENT_RUNTIME_CALDAV_PASSWORD":                  &cfg.CalDAVPassword,
+		"AGENT_RUNTIME_GOOGLE_CLIENT_SECRET":             &cfg.GoogleClientSecret,
+		"AGENT_RUNTIME_GOOGLE_REFRESH_TOKEN":             &cfg.GoogleRefreshToken,
+		"AGENT_RUNTIME_TTS_API_KEY":                      &cfg.TTSAPIKey,
+		"AGENT_RUNTIME_K8S_TOKEN":                        &cfg.KubernetesToken,
+		"AGENT_RUNTIME_STATUS_PAGE_S3_SECRET_ACCESS_KEY": &cfg.StatusPageS3SecretAccessKey,
+		"AGENT_RUNTIME_EVENT_WEBHOOK_SECRET":             &cfg.EventWebhookSecret,
+		"AGENT_RUNTIME_ARTIFACT_LINK_SECRET":             &cfg.ArtifactLinkSecret,
+	}
+}
+
+func resolveConfigSecrets(ctx context.Context, cfg config.Config, secretStore secrets.Store, logger *slog.Logger) (config.Config, error) {
+	masterKey, err := secrets.LoadMasterKey(cfg.SecretsMasterKey, cfg.SecretsKeyFile)
+	if err != nil {
+		if errors.Is(err, secrets.ErrNoMasterKey) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	vault, err := secrets.New(secretStore, masterKey)
+	if err != nil {
+		return cfg, err
+	}
+	resolved := cfg
+	for name, target := range secretBackedFields(&resolved) {
+		if strings.TrimSpace(*target) != "" {
+			continue
+		}
+		value, err := vault.Get(ctx, name)
+		if err != nil {
+			if errors.Is(err, store.ErrSecretNotFound) {
+				continue
+			}
+			return cfg, fmt.Errorf("resolve secret %s: %w", name, err)
+		}
+		*target = strings.TrimSpace(value)
+		if logger != nil {
+			logger.Info("config value loaded from secrets store", "name", name)
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/secrets"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestResolveConfigSecretsFillsEmptyFields(t *testing.T) {
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "runtime_secrets.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer sqlStore.Close()
+	ctx := context.Background()
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	const testMasterKey = "c2VjcmV0cy1tYXN0ZXIta2V5LWZvci10ZXN0cy0zMiE="
+	masterKey, err := secrets.LoadMasterKey(testMasterKey, "")
+	if err != nil {
+		t.Fatalf("load master key: %v", err)
+	}
+	vault, err := secrets.New(sqlStore, masterKey)
+	if err != nil {
+		t.Fatalf("new vault: %v", err)
+	}
+	if err := vault.Set(ctx, "AGENT_RUNTIME_LLM_API_KEY", "from-vault"); err != nil {
+		t.Fatalf("set llm key: %v", err)
+	}
+	if err := vault.Set(ctx, "AGENT_RUNTIME_DISCORD_TOKEN", "vault-discord"); err != nil {
+		t.Fatalf("set discord token: %v", err)
+	}
+	if err := vault.Set(ctx, "AGENT_RUNTIME_ARTIFACT_LINK_SECRET", "vault-link-secret"); err != nil {
+		t.Fatalf("set artifact link secret: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	cfg := config.Config{SecretsMasterKey: testMasterKey, DiscordToken: "from-env"}
+	resolved, err := resolveConfigSecrets(ctx, cfg, sqlStore, logger)
+	if err != nil {
+		t.Fatalf("resolve secrets: %v", err)
+	}
+	if resolved.LLMAPIKey != "from-vault" {
+		t.Fatalf("expected llm key from vault, got %q", resolved.LLMAPIKey)
+	}
+	if resolved.DiscordToken != "from-env" {
+		t.Fatalf("expected env value to win, got %q", resolved.DiscordToken)
+	}
+	if resolved.ArtifactLinkSecret != "vault-link-secret" {
+		t.Fatalf("expected artifact link secret from vault, got %q", resolved.ArtifactLinkSecret)
+	}
+
+	unkeyed, err := resolveConfigSecrets(ctx, config.Config{}, sqlStore, logger)
+	if err != nil {
+		t.Fatalf("resolve without key: %v", err)
+	}
+	if unkeyed.LLMAPIKey != "" {
+		t.Fatalf("expected no resolution without master key, got %q", unkeyed.LLMAPIKey)
+	}
+
+	if _, err := resolveConfigSecrets(ctx, config.Config{SecretsMasterKey: "d3Jvbmctc2VjcmV0cy1tYXN0ZXIta2V5LXRlc3RzISE="}, sqlStore, logger); err == nil {
+		t.Fatal("expected error with wrong master key")
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/statuspage"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// newStatusPageGenerator publishes to the S3 bucket when one is configured
+// and to the status page directory otherwise; in dry-run mode it only logs.
+func newStatusPageGenerator(cfg config.Config, sqlStore *store.Store, logger *slog.Logger) (*statuspage.Generator, error) {
+	workspaces := parseCSVTrimList(cfg.StatusPageWorkspaces)
+	if len(workspaces) == 0 {
+		return nil, fmt.Errorf("AGENT_RUNTIME_STATUS_PAGE_WORKSPACES is required when the status page is enabled")
+	}
+	var publisher statuspage.Publisher = statuspage.DirPublisher{Root: cfg.StatusPageDir}
+	if strings.TrimSpace(cfg.StatusPageS3Bucket) != "" {
+		s3Publisher, err := statuspage.NewS3Publisher(statuspage.S3Config{
+			Bucket:          cfg.StatusPageS3Bucket,
+			Region:          cfg.StatusPageS3Region,
+			Prefix:          cfg.StatusPageS3Prefix,
+			Endpoint:        cfg.StatusPageS3Endpoint,
+			AccessKeyID:     cfg.StatusPageS3AccessKeyID,
+			SecretAccessKey: cfg.StatusPageS3SecretAccessKey,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("status page s3: %w", err)
+		}
+		publisher = s3Publisher
+	}
+	if cfg.DryRun {
+		publisher = dryRunStatusPublisher{logger: logger.With("component", "dry-run")}
+	}
+	return statuspage.New(statuspage.Config{
+		Workspaces: workspaces,
+		Endpoints:  parseStatusPageEndpoints(cfg.StatusPageEndpoints),
+		Interval:   time.Duration(cfg.StatusPageIntervalMinutes) * time.Minute,
+		Window:     time.Duration(cfg.StatusPageWindowDays) * 24 * time.Hour,
+		Title:      cfg.StatusPageTitle,
+	}, sqlStore, publisher, logger), nil
+}
+
+// parseStatusPageEndpoints reads "api=https://api.example.com/health,...".
+// Malformed entries are skipped.
+func parseStatusPageEndpoints(input string) []statuspage.Endpoint {
+	endpoints := []statuspage.Endpoint{}
+	for _, entry := range parseCSVTrimList(input) {
+		name, url, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		url = strings.TrimSpace(url)
+		if !ok || name == "" || !(strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")) {
+			continue
+		}
+		endpoints = append(endpoints, statuspage.Endpoint{Name: name, URL: url})
+	}
+	return endpoints
+}
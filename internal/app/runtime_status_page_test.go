@@ -0,0 +1,31 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+)
+
+func TestParseStatusPageEndpointsSkipsMalformedEntries(t *testing.T) {
+	endpoints := parseStatusPageEndpoints("api=https://api.example.com/health, broken, ftp=ftp://example.com, web = http://example.com ")
+	if len(endpoints) != 2 {
+		t.Fatalf("expected two endpoints, got %+v", endpoints)
+	}
+	if endpoints[0].Name != "api" || endpoints[1].Name != "web" || endpoints[1].URL != "http://example.com" {
+		t.Fatalf("unexpected endpoints %+v", endpoints)
+	}
+}
+
+func TestNewStatusPageGeneratorRequiresWorkspacesAndS3Credentials(t *testing.T) {
+	sqlStore := openAppTestStore(t)
+	if _, err := newStatusPageGenerator(config.Config{StatusPageDir: t.TempDir()}, sqlStore, nil); err == nil {
+		t.Fatal("expected an error without workspaces")
+	}
+	if _, err := newStatusPageGenerator(config.Config{StatusPageWorkspaces: "ws-1", StatusPageS3Bucket: "pages"}, sqlStore, nil); err == nil {
+		t.Fatal("expected an error for s3 without credentials")
+	}
+	generator, err := newStatusPageGenerator(config.Config{StatusPageWorkspaces: "ws-1", StatusPageDir: t.TempDir()}, sqlStore, nil)
+	if err != nil || generator == nil {
+		t.Fatalf("expected a directory-backed generator, got %v", err)
+	}
+}
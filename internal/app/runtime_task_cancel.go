@@ -0,0 +1,25 @@
+package app
+
+import (
+	"context"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// taskCanceller records a cancellation in the store before stopping the
+// task in the engine, so the worker and any dependents already see the
+// cancelled status when they check it.
+type taskCanceller struct {
+	store  *store.Store
+	engine *orchestrator.Engine
+}
+
+func (c taskCanceller) CancelTask(ctx context.Context, id string) (store.TaskRecord, error) {
+	record, err := c.store.CancelTask(ctx, id)
+	if err != nil {
+		return record, err
+	}
+	c.engine.Cancel(record.ID)
+	return record, nil
+}
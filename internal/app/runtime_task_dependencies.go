@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// taskDependencyResolver reads the status of prerequisite tasks from the
+// store. A prerequisite that was cancelled or deleted counts as failed so
+// its dependents do not wait forever.
+type taskDependencyResolver struct {
+	store *store.Store
+}
+
+func (r taskDependencyResolver) DependencyState(task orchestrator.Task) (orchestrator.DependencyState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	state := orchestrator.DependenciesMet
+	for _, id := range task.DependsOn {
+		record, err := r.store.LookupTask(ctx, id)
+		if errors.Is(err, store.ErrTaskNotFound) {
+			return orchestrator.DependenciesFailed, nil
+		}
+		if err != nil {
+			return orchestrator.DependenciesPending, err
+		}
+		switch strings.ToLower(strings.TrimSpace(record.Status)) {
+		case "succeeded":
+		case "failed", "cancelled":
+			return orchestrator.DependenciesFailed, nil
+		default:
+			state = orchestrator.DependenciesPending
+		}
+	}
+	return state, nil
+}
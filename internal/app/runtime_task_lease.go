@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// taskLeaser keeps task leases in the store, so runtime instances sharing
+// the database never run the same task twice.
+type taskLeaser struct {
+	store *store.Store
+}
+
+func (l taskLeaser) ClaimTask(taskID, owner string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return l.store.ClaimTaskLease(ctx, taskID, owner, ttl, time.Now().UTC())
+}
+
+func (l taskLeaser) RenewTask(taskID, owner string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := l.store.RenewTaskLease(ctx, taskID, owner, ttl, time.Now().UTC())
+	if errors.Is(err, store.ErrTaskLeaseLost) {
+		return orchestrator.ErrTaskLeaseLost
+	}
+	return err
+}
+
+func (l taskLeaser) ReleaseTask(taskID, owner string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return l.store.ReleaseTaskLease(ctx, taskID, owner)
+}
+
+// taskLeaseOwner names this instance in the leases it takes.
+func taskLeaseOwner(cfg config.Config) string {
+	if id := strings.TrimSpace(cfg.InstanceID); id != "" {
+		return id
+	}
+	host, err := os.Hostname()
+	if err != nil || strings.TrimSpace(host) == "" {
+		host = "agent-runtime"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+type taskQueueStore interface {
+	ListClaimableTasks(ctx context.Context, now time.Time, idleFor time.Duration, limit int) ([]store.TaskRecord, error)
+}
+
+type taskQueueEngine interface {
+	Enqueue(task orchestrator.Task) (orchestrator.Task, error)
+	Tracks(taskID string) bool
+}
+
+// runTaskQueuePollLoop pulls tasks of the shared queue into this instance:
+// tasks created by other instances that are still waiting and tasks whose
+// lease lapsed. It replaces startup and stale task recovery when leases are
+// on, as a lapsed lease is what marks a task as abandoned.
+func runTaskQueuePollLoop(ctx context.Context, sqlStore taskQueueStore, engine taskQueueEngine, interval time.Duration, logger *slog.Logger) error {
+	if sqlStore == nil || engine == nil {
+		<-ctx.Done()
+		return nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	poll := func(idleFor time.Duration) {
+		pulled, err := pollTaskQueue(ctx, sqlStore, engine, idleFor, logger)
+		if err != nil {
+			logger.Error("task queue poll failed", "error", err)
+			return
+		}
+		if pulled > 0 {
+			logger.Info("pulled tasks from the shared queue", "count", pulled)
+		}
+	}
+	// At startup every unleased task is fair game, including this
+	// instance's own from before a restart.
+	poll(0)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			poll(interval)
+		}
+	}
+}
+
+func pollTaskQueue(ctx context.Context, sqlStore taskQueueStore, engine taskQueueEngine, idleFor time.Duration, logger *slog.Logger) (int, error) {
+	claimable, err := sqlStore.ListClaimableTasks(ctx, time.Now().UTC(), idleFor, 100)
+	if err != nil {
+		return 0, err
+	}
+	pulled := 0
+	for _, item := range claimable {
+		if engine.Tracks(item.ID) {
+			continue
+		}
+		_, err := engine.Enqueue(orchestrator.Task{
+			ID:          item.ID,
+			WorkspaceID: item.WorkspaceID,
+			ContextID:   item.ContextID,
+			Kind:        orchestrator.TaskKind(strings.TrimSpace(item.Kind)),
+			Title:       item.Title,
+			Prompt:      item.Prompt,
+			DependsOn:   item.DependsOn,
+			Attempts:    item.Attempts,
+			RunAt:       item.RunAt,
+		})
+		if err != nil {
+			logger.Error("failed to enqueue task from the shared queue", "task_id", item.ID, "error", err)
+			continue
+		}
+		pulled++
+	}
+	return pulled, nil
+}
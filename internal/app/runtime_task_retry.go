@@ -0,0 +1,51 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+// newTaskRetryPolicies builds the runtime-wide retry policy and the
+// per-kind overrides of AGENT_RUNTIME_TASK_RETRY_POLICIES, written as
+// kind=max_attempts[:backoff_seconds[:max_backoff_seconds]]. Parts left out
+// fall back to the runtime-wide values; malformed entries are skipped.
+func newTaskRetryPolicies(cfg config.Config) (orchestrator.RetryPolicy, map[orchestrator.TaskKind]orchestrator.RetryPolicy) {
+	fallback := orchestrator.RetryPolicy{
+		MaxAttempts: max(cfg.TaskRetryMaxAttempts, 1),
+		Backoff:     time.Duration(max(cfg.TaskRetryBackoffSec, 0)) * time.Second,
+		MaxBackoff:  time.Duration(max(cfg.TaskRetryMaxBackoffSec, 0)) * time.Second,
+		Jitter:      min(max(cfg.TaskRetryJitter, 0), 1),
+	}
+	byKind := map[orchestrator.TaskKind]orchestrator.RetryPolicy{}
+	for _, entry := range parseCSVTrimList(cfg.TaskRetryPolicies) {
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			continue
+		}
+		policy, valid := fallback, true
+		for index, part := range strings.Split(value, ":") {
+			number, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || number < 0 || index > 2 {
+				valid = false
+				break
+			}
+			switch index {
+			case 0:
+				policy.MaxAttempts = max(number, 1)
+			case 1:
+				policy.Backoff = time.Duration(number) * time.Second
+			case 2:
+				policy.MaxBackoff = time.Duration(number) * time.Second
+			}
+		}
+		if valid {
+			byKind[orchestrator.TaskKind(name)] = policy
+		}
+	}
+	return fallback, byKind
+}
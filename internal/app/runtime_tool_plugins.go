@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/toolplugins"
+)
+
+// registerToolPlugins discovers subprocess tool plugins and registers their
+// tools. A broken plugin is logged and skipped; it never blocks startup.
+func registerToolPlugins(ctx context.Context, commandGateway *gateway.Service, cfg config.Config, logger *slog.Logger) {
+	executables, err := toolplugins.DiscoverExecutables(cfg.ToolPluginsDir)
+	if err != nil {
+		logger.Warn("tool plugin discovery failed", "dir", cfg.ToolPluginsDir, "error", err)
+		return
+	}
+	runner := toolplugins.NewRunner(time.Duration(cfg.ToolPluginTimeoutSec) * time.Second)
+	for _, executable := range executables {
+		definitions, err := runner.Describe(ctx, executable)
+		if err != nil {
+			logger.Warn("tool plugin describe failed", "executable", executable, "error", err)
+			continue
+		}
+		plugin := toolplugins.PluginName(executable)
+		commandGateway.Registry().ReplaceNamespace("plugin:"+plugin, gateway.BuildPluginDynamicTools(runner, definitions))
+		logger.Info("tool plugin registered", "plugin", plugin, "tools", len(definitions))
+	}
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const trashPurgeInterval = time.Hour
+
+type trashPurger interface {
+	PurgeTrash(ctx context.Context, now time.Time) (int, error)
+}
+
+// runTrashPurgeLoop permanently removes tasks and objectives once they have
+// outlived the trash retention window.
+func runTrashPurgeLoop(ctx context.Context, purger trashPurger, interval time.Duration, logger *slog.Logger) error {
+	if purger == nil {
+		<-ctx.Done()
+		return nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if interval <= 0 {
+		interval = trashPurgeInterval
+	}
+	purge := func() {
+		purged, err := purger.PurgeTrash(ctx, time.Now().UTC())
+		if err != nil {
+			logger.Error("trash purge failed", "error", err)
+			return
+		}
+		if purged > 0 {
+			logger.Info("purged expired trash", "count", purged)
+		}
+	}
+	purge()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			purge()
+		}
+	}
+}
@@ -6,11 +6,16 @@ import (
 
 	"github.com/dwizi/agent-runtime/internal/config"
 	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/degrade"
+	"github.com/dwizi/agent-runtime/internal/eventbus"
 	"github.com/dwizi/agent-runtime/internal/heartbeat"
 	"github.com/dwizi/agent-runtime/internal/mcp"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/outbox"
 	"github.com/dwizi/agent-runtime/internal/qmd"
 	"github.com/dwizi/agent-runtime/internal/scheduler"
+	"github.com/dwizi/agent-runtime/internal/skillreview"
+	"github.com/dwizi/agent-runtime/internal/statuspage"
 	"github.com/dwizi/agent-runtime/internal/store"
 	"github.com/dwizi/agent-runtime/internal/watcher"
 )
@@ -28,6 +33,15 @@ type Runtime struct {
 	mcp              *mcp.Manager
 	heartbeat        *heartbeat.Registry
 	heartbeatMonitor *heartbeat.Monitor
+	skillReview      *skillreview.Reviewer
+	botfiles         *botfileManager
+	degradation      *degrade.Monitor
+	outbox           *outbox.Queue
+	approvalExpiry   *approvalExpirySweeper
+	approvalDigest   *approvalDigest
+	objectiveDigests *objectiveDigests
+	statusPage       *statuspage.Generator
+	eventBus         *eventbus.Bus
 }
 
 type heartbeatAware interface {
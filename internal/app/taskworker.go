@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -16,19 +17,30 @@ import (
 	"github.com/dwizi/agent-runtime/internal/config"
 	"github.com/dwizi/agent-runtime/internal/gateway"
 	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/memorylog"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
 	"github.com/dwizi/agent-runtime/internal/qmd"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
+	"github.com/dwizi/agent-runtime/internal/secretscan"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
 const (
 	autonomousObservationMaxBytes = 1200
+	// taskResultDataMaxBytes caps the structured tool results kept on a task.
+	taskResultDataMaxBytes = 64 * 1024
 )
 
 type taskActionExecutor interface {
 	Execute(ctx context.Context, approval store.ActionApproval) (actionexecutor.Result, error)
 }
 
+// objectiveChangeDetector compares the output of objective tasks with the
+// state their objective observed before.
+type objectiveChangeDetector interface {
+	DetectObjectiveChanges(ctx context.Context, taskID, output string) (scheduler.ObjectiveChanges, bool, error)
+}
+
 type taskWorkerExecutor struct {
 	workspaceRoot  string
 	store          *store.Store
@@ -37,6 +49,15 @@ type taskWorkerExecutor struct {
 	actionExecutor taskActionExecutor
 	logger         *slog.Logger
 	agent          *agent.Agent
+	// plannerEnabled turns on the plan-then-execute mode for new tasks;
+	// tasks that already have a stored plan always resume it.
+	plannerEnabled  bool
+	plannerMaxSteps int
+	// compaction holds the memory compaction settings; WorkspaceID is
+	// filled in per task.
+	compaction memorylog.CompactOptions
+	// objectiveChanges diffs the output of change-detecting objectives.
+	objectiveChanges objectiveChangeDetector
 }
 
 func newTaskWorkerExecutor(
@@ -91,15 +112,26 @@ func newTaskWorkerExecutor(
 	workerAgent.SetDefaultPolicy(policy)
 	// Enable grounding at every step for deep work
 	workerAgent.SetGroundingPolicy(true, true)
+	// Startup already rejected an unknown mode; an empty one masks.
+	secretScanMode, _ := secretscan.ParseMode(cfg.SecretScanMode)
+	workerAgent.SetSecretScanMode(secretScanMode)
 
 	return &taskWorkerExecutor{
-		workspaceRoot:  strings.TrimSpace(workspaceRoot),
-		store:          storeRef,
-		responder:      responder,
-		qmd:            qmdService,
-		actionExecutor: actionExecutor,
-		logger:         logger,
-		agent:          workerAgent,
+		workspaceRoot:   strings.TrimSpace(workspaceRoot),
+		store:           storeRef,
+		responder:       responder,
+		qmd:             qmdService,
+		actionExecutor:  actionExecutor,
+		logger:          logger,
+		agent:           workerAgent,
+		plannerEnabled:  cfg.AgentPlannerEnabled,
+		plannerMaxSteps: cfg.AgentPlannerMaxSteps,
+		compaction: memorylog.CompactOptions{
+			WorkspaceRoot: strings.TrimSpace(workspaceRoot),
+			KeepEntries:   cfg.MemoryCompactionKeepEntries,
+			MinAge:        time.Duration(cfg.MemoryCompactionMinAgeHours) * time.Hour,
+			MaxSections:   cfg.MemoryCompactionMaxSections,
+		},
 	}
 }
 
@@ -107,6 +139,8 @@ func (e *taskWorkerExecutor) Execute(ctx context.Context, task orchestrator.Task
 	switch task.Kind {
 	case orchestrator.TaskKindReindex:
 		return e.executeReindex(ctx, task)
+	case orchestrator.TaskKindMemoryCompaction:
+		return e.executeMemoryCompaction(task)
 	case orchestrator.TaskKindGeneral, orchestrator.TaskKindObjective:
 		return e.executeLLMTask(ctx, task)
 	default:
@@ -141,6 +175,40 @@ func (e *taskWorkerExecutor) executeReindex(ctx context.Context, task orchestrat
 	}, nil
 }
 
+// executeMemoryCompaction moves old chat log entries of the task's
+// workspace into rolling summaries and queues the summaries for indexing.
+// SetToolPolicyResolver restricts the worker agent's tools per task, for
+// example to the tools a workspace botfile allows.
+func (e *taskWorkerExecutor) SetToolPolicyResolver(resolver agent.PolicyResolver) {
+	if e.agent != nil {
+		e.agent.SetPolicyResolver(resolver)
+	}
+}
+
+func (e *taskWorkerExecutor) executeMemoryCompaction(task orchestrator.Task) (orchestrator.TaskResult, error) {
+	workspaceID := strings.TrimSpace(task.WorkspaceID)
+	if workspaceID == "" {
+		return orchestrator.TaskResult{}, fmt.Errorf("workspace id is required for memory compaction")
+	}
+	opts := e.compaction
+	opts.WorkspaceID = workspaceID
+	result, err := memorylog.CompactWorkspace(opts)
+	if err != nil {
+		return orchestrator.TaskResult{}, err
+	}
+	if result.Entries == 0 {
+		return orchestrator.TaskResult{
+			Summary: fmt.Sprintf("workspace `%s` chat logs already compact", workspaceID),
+		}, nil
+	}
+	if e.qmd != nil {
+		e.qmd.QueueWorkspaceIndex(workspaceID)
+	}
+	return orchestrator.TaskResult{
+		Summary: fmt.Sprintf("workspace `%s`: compacted %d chat log entries across %d chats", workspaceID, result.Entries, result.Chats),
+	}, nil
+}
+
 func (e *taskWorkerExecutor) executeLLMTask(ctx context.Context, task orchestrator.Task) (orchestrator.TaskResult, error) {
 	if e.agent == nil {
 		return orchestrator.TaskResult{
@@ -201,12 +269,34 @@ func (e *taskWorkerExecutor) executeLLMTask(ctx context.Context, task orchestrat
 		WorkspaceID: task.WorkspaceID,
 	}
 	agentCtx = context.WithValue(agentCtx, gateway.ContextKeyRecord, contextRecord)
+	agentCtx = store.WithWorkspaceScope(agentCtx, task.WorkspaceID)
+	if e.store != nil && strings.TrimSpace(task.ContextID) != "" {
+		if policy, err := e.store.LookupContextPolicy(ctx, task.ContextID); err == nil && policy.SharedKnowledge {
+			agentCtx = qmd.WithSharedKnowledge(agentCtx)
+		}
+	}
 	agentCtx = context.WithValue(agentCtx, gateway.ContextKeyInput, gatewayInput)
 
 	// Grant sensitive approval for deep work
 	agentCtx = agent.WithSensitiveToolApproval(agentCtx)
 
-	result := e.agent.Execute(agentCtx, llmInput)
+	result, plan, planned, err := e.executePlannedTask(agentCtx, task, llmInput)
+	if err != nil {
+		return orchestrator.TaskResult{}, err
+	}
+	if !planned {
+		result = e.agent.Execute(e.withTaskCheckpointer(agentCtx, task.ID, ""), llmInput)
+	}
+	if err := ctx.Err(); err != nil {
+		// The task was cancelled or the runtime is stopping; a partial
+		// reply is not a result.
+		return orchestrator.TaskResult{}, err
+	}
+	if e.store != nil {
+		if err := e.store.DeleteTaskCheckpoints(ctx, task.ID); err != nil {
+			e.logger.Warn("delete task checkpoints failed", "task_id", task.ID, "error", err)
+		}
+	}
 
 	reply := strings.TrimSpace(result.Reply)
 	if result.Error != nil {
@@ -217,7 +307,7 @@ func (e *taskWorkerExecutor) executeLLMTask(ctx context.Context, task orchestrat
 		reply = "Task completed with no output."
 	}
 
-	resultPath, err := e.writeTaskResult(task, result)
+	resultPath, err := e.writeTaskResult(task, result, plan)
 	if err != nil {
 		return orchestrator.TaskResult{}, err
 	}
@@ -229,14 +319,70 @@ func (e *taskWorkerExecutor) executeLLMTask(ctx context.Context, task orchestrat
 	if strings.TrimSpace(taskRecord.RouteClass) != "" {
 		summary = truncatePreservingLines(reply, 1400)
 	}
+	unchanged := false
+	if task.Kind == orchestrator.TaskKindObjective && result.Error == nil {
+		summary, unchanged = e.objectiveChangeSummary(ctx, task, reply, summary)
+	}
 
 	return orchestrator.TaskResult{
 		Summary:      summary,
 		ArtifactPath: resultPath,
+		Data:         collectTaskResultData(result.ToolCalls),
+		Artifacts:    collectTaskArtifacts(e.workspaceRoot, task, resultPath, result.ToolCalls),
+		Unchanged:    unchanged,
 	}, nil
 }
 
-func (e *taskWorkerExecutor) writeTaskResult(task orchestrator.Task, result agent.Result) (string, error) {
+// objectiveChangeSummary replaces the summary of a change-detecting
+// objective's task with what changed since its previous run. The first run
+// keeps its summary as the baseline report.
+func (e *taskWorkerExecutor) objectiveChangeSummary(ctx context.Context, task orchestrator.Task, reply, summary string) (string, bool) {
+	if e.objectiveChanges == nil {
+		return summary, false
+	}
+	changes, tracked, err := e.objectiveChanges.DetectObjectiveChanges(ctx, task.ID, reply)
+	if err != nil {
+		e.logger.Warn("objective change detection failed", "task_id", task.ID, "error", err)
+		return summary, false
+	}
+	if !tracked || changes.Baseline {
+		return summary, false
+	}
+	return changes.Summary(), changes.Unchanged()
+}
+
+type taskResultDataEntry struct {
+	Tool string          `json:"tool"`
+	Kind string          `json:"kind,omitempty"`
+	Data json.RawMessage `json:"data"`
+}
+
+// collectTaskResultData gathers the structured payloads of successful tool
+// calls, in order, dropping later entries once the size cap is reached.
+func collectTaskResultData(calls []agent.ToolCall) json.RawMessage {
+	entries := []taskResultDataEntry{}
+	size := 2
+	for _, call := range calls {
+		if call.Error != "" || len(call.Data) == 0 {
+			continue
+		}
+		size += len(call.ToolName) + len(call.DataKind) + len(call.Data) + 32
+		if size > taskResultDataMaxBytes {
+			break
+		}
+		entries = append(entries, taskResultDataEntry{Tool: call.ToolName, Kind: call.DataKind, Data: call.Data})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(entries)
+	if err != nil {
+		return nil
+	}
+	return payload
+}
+
+func (e *taskWorkerExecutor) writeTaskResult(task orchestrator.Task, result agent.Result, plan []store.TaskPlanStep) (string, error) {
 	workspaceID := strings.TrimSpace(task.WorkspaceID)
 	if workspaceID == "" || e.workspaceRoot == "" {
 		return "", nil
@@ -247,14 +393,14 @@ func (e *taskWorkerExecutor) writeTaskResult(task orchestrator.Task, result agen
 	if err := os.MkdirAll(filepath.Dir(absolutePath), 0o755); err != nil {
 		return "", fmt.Errorf("create task artifact directory: %w", err)
 	}
-	content := buildTaskMarkdown(task, now, result)
+	content := buildTaskMarkdown(task, now, result, plan)
 	if err := os.WriteFile(absolutePath, []byte(content), 0o644); err != nil {
 		return "", fmt.Errorf("write task artifact: %w", err)
 	}
 	return relativePath, nil
 }
 
-func buildTaskMarkdown(task orchestrator.Task, now time.Time, result agent.Result) string {
+func buildTaskMarkdown(task orchestrator.Task, now time.Time, result agent.Result, plan []store.TaskPlanStep) string {
 	var builder strings.Builder
 	builder.WriteString("# Task Result\n\n")
 	builder.WriteString("- ID: `" + strings.TrimSpace(task.ID) + "`\n")
@@ -268,6 +414,14 @@ func buildTaskMarkdown(task orchestrator.Task, now time.Time, result agent.Resul
 	builder.WriteString(strings.TrimSpace(task.Prompt))
 	builder.WriteString("\n\n")
 
+	if len(plan) > 0 {
+		builder.WriteString("## Plan\n\n")
+		for i, step := range plan {
+			builder.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, step.Status, step.Description))
+		}
+		builder.WriteString("\n")
+	}
+
 	if len(result.ToolCalls) > 0 {
 		builder.WriteString("## Execution Trace\n\n")
 		for i, call := range result.ToolCalls {
@@ -277,6 +431,12 @@ func buildTaskMarkdown(task orchestrator.Task, now time.Time, result agent.Resul
 			}
 			if call.Error != "" {
 				builder.WriteString("**Error:**\n```\n" + call.Error + "\n```\n")
+			} else if len(call.Data) > 0 {
+				builder.WriteString("**Output:**\n\n" + tools.RenderMarkdown(tools.StructuredResult{
+					Summary: call.ToolOutput,
+					Kind:    call.DataKind,
+					Data:    call.Data,
+				}) + "\n")
 			} else if call.ToolOutput != "" {
 				output := call.ToolOutput
 				if len(output) > 2000 {
@@ -334,10 +494,18 @@ func (e *taskWorkerExecutor) lookupTaskRecord(ctx context.Context, taskID string
 	return record, true
 }
 
+// objectiveRunRecorder records the outcome of objective tasks on their
+// objective.
+type objectiveRunRecorder interface {
+	FinishObjectiveTask(ctx context.Context, taskID string, status store.ObjectiveRunStatus, summary, failure string)
+}
+
 type taskObserver struct {
-	store    *store.Store
-	notifier *taskCompletionNotifier
-	logger   *slog.Logger
+	store      *store.Store
+	notifier   *taskCompletionNotifier
+	syncer     gateway.TaskSyncer
+	objectives objectiveRunRecorder
+	logger     *slog.Logger
 }
 
 func newTaskObserver(storeRef *store.Store, notifier *taskCompletionNotifier, logger *slog.Logger) *taskObserver {
@@ -386,6 +554,28 @@ func (o *taskObserver) OnTaskCompleted(task orchestrator.Task, workerID int, res
 		}
 		return
 	}
+	if len(result.Data) > 0 {
+		if err := o.store.SetTaskResultData(ctx, task.ID, string(result.Data)); err != nil {
+			o.logger.Error("store task result data failed", "task_id", task.ID, "error", err)
+		}
+	}
+	if len(result.Artifacts) > 0 {
+		inputs := make([]store.TaskArtifactInput, 0, len(result.Artifacts))
+		for _, artifact := range result.Artifacts {
+			inputs = append(inputs, store.TaskArtifactInput{
+				Path:      artifact.Path,
+				Kind:      artifact.Kind,
+				Title:     artifact.Title,
+				MediaType: artifact.MediaType,
+				SizeBytes: artifact.SizeBytes,
+			})
+		}
+		if _, err := o.store.ReplaceTaskArtifacts(ctx, task.ID, task.WorkspaceID, inputs); err != nil {
+			o.logger.Error("store task artifacts failed", "task_id", task.ID, "error", err)
+		}
+	}
+	o.finishObjectiveRun(ctx, task, store.ObjectiveRunSucceeded, result.Summary, "")
+	o.syncTask(task.ID)
 	if o.notifier != nil {
 		o.notifier.NotifyCompleted(task, result)
 	}
@@ -401,7 +591,15 @@ func (o *taskObserver) OnTaskFailed(task orchestrator.Task, workerID int, err er
 	if err != nil {
 		message = err.Error()
 	}
-	if updateErr := o.store.MarkTaskFailedByWorker(ctx, task.ID, workerID, time.Now().UTC(), message); updateErr != nil {
+	var updateErr error
+	if workerID == 0 {
+		// The task never reached a worker, e.g. because a prerequisite
+		// failed, so it is still queued rather than running.
+		updateErr = o.store.MarkTaskFailed(ctx, task.ID, time.Now().UTC(), message)
+	} else {
+		updateErr = o.store.MarkTaskFailedByWorker(ctx, task.ID, workerID, time.Now().UTC(), message)
+	}
+	if updateErr != nil {
 		if errors.Is(updateErr, store.ErrTaskNotRunningForWorker) {
 			o.logger.Warn("skipping stale task failure update", "task_id", task.ID, "worker_id", workerID)
 			return
@@ -411,11 +609,95 @@ func (o *taskObserver) OnTaskFailed(task orchestrator.Task, workerID int, err er
 		}
 		return
 	}
+	if errors.Is(err, orchestrator.ErrRetriesExhausted) {
+		if deadErr := o.store.MarkTaskDeadLettered(ctx, task.ID, time.Now().UTC()); deadErr != nil {
+			o.logger.Error("mark task dead-lettered failed", "task_id", task.ID, "error", deadErr)
+		} else {
+			o.logger.Warn("task dead-lettered", "task_id", task.ID, "attempts", task.Attempts)
+		}
+	}
+	o.finishObjectiveRun(ctx, task, store.ObjectiveRunFailed, "", message)
+	o.syncTask(task.ID)
 	if o.notifier != nil {
 		o.notifier.NotifyFailed(task, err)
 	}
 }
 
+// OnTaskProgress stores the progress a worker reported and passes it on to
+// the task's origin channel when progress notices are on.
+func (o *taskObserver) OnTaskProgress(task orchestrator.Task, workerID int, progress orchestrator.TaskProgress) {
+	if o.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := o.store.SetTaskProgress(ctx, task.ID, progress.Percent, progress.Step, time.Now().UTC()); err != nil {
+		if !errors.Is(err, store.ErrTaskNotActive) && !errorsIsTaskNotFound(err) {
+			o.logger.Error("store task progress failed", "task_id", task.ID, "error", err)
+		}
+		return
+	}
+	if o.notifier != nil {
+		o.notifier.NotifyProgress(task, progress)
+	}
+}
+
+// OnTaskRetry puts a failed task back to queued while the engine waits to
+// run it again. Notifications wait for the outcome of the last attempt.
+func (o *taskObserver) OnTaskRetry(task orchestrator.Task, workerID int, err error, delay time.Duration) {
+	if o.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	if updateErr := o.store.ScheduleTaskRetry(ctx, task.ID, workerID, message); updateErr != nil {
+		if errors.Is(updateErr, store.ErrTaskNotRunningForWorker) {
+			o.logger.Warn("skipping stale task retry update", "task_id", task.ID, "worker_id", workerID)
+			return
+		}
+		if !errorsIsTaskNotFound(updateErr) {
+			o.logger.Error("schedule task retry failed", "task_id", task.ID, "error", updateErr)
+		}
+	}
+}
+
+// OnTaskCancelled makes sure a task stopped mid-run ends up cancelled even
+// when the engine was told before the store.
+func (o *taskObserver) OnTaskCancelled(task orchestrator.Task, workerID int) {
+	if o.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if _, err := o.store.CancelTask(ctx, task.ID); err != nil && !errors.Is(err, store.ErrTaskNotActive) && !errorsIsTaskNotFound(err) {
+		o.logger.Error("mark task cancelled failed", "task_id", task.ID, "error", err)
+		return
+	}
+	o.finishObjectiveRun(ctx, task, store.ObjectiveRunCancelled, "", "")
+	o.logger.Info("task cancelled", "task_id", task.ID, "worker_id", workerID)
+}
+
+func (o *taskObserver) finishObjectiveRun(ctx context.Context, task orchestrator.Task, status store.ObjectiveRunStatus, summary, failure string) {
+	if o.objectives == nil || task.Kind != orchestrator.TaskKindObjective {
+		return
+	}
+	o.objectives.FinishObjectiveTask(ctx, task.ID, status, summary, failure)
+}
+
+func (o *taskObserver) syncTask(taskID string) {
+	if o.syncer == nil {
+		return
+	}
+	// The syncer bounds its own tracker calls.
+	if err := o.syncer.SyncTask(context.Background(), taskID); err != nil {
+		o.logger.Warn("task issue sync failed", "task_id", taskID, "error", err)
+	}
+}
+
 func errorsIsTaskNotFound(err error) bool {
 	return errors.Is(err, store.ErrTaskNotFound)
 }
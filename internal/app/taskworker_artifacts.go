@@ -0,0 +1,102 @@
+package app
+
+import (
+	"encoding/json"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+// taskArtifactMaxFiles bounds the tool-written files recorded per task.
+const taskArtifactMaxFiles = 20
+
+// taskFileWriteArgs maps the tools that save a scratchpad file to the
+// argument holding its path.
+var taskFileWriteArgs = map[string]string{
+	"write_file":      "path",
+	"render_template": "save_as",
+}
+
+// collectTaskArtifacts lists the files a task produced: its markdown result
+// first, then the scratchpad files its tools saved, in the order they were
+// first written. Files that no longer exist are left out.
+func collectTaskArtifacts(workspaceRoot string, task orchestrator.Task, resultPath string, calls []agent.ToolCall) []orchestrator.TaskArtifact {
+	workspaceDir := filepath.Join(workspaceRoot, strings.TrimSpace(task.WorkspaceID))
+	artifacts := []orchestrator.TaskArtifact{}
+	if resultPath = strings.TrimSpace(resultPath); resultPath != "" {
+		if artifact, ok := statTaskArtifact(workspaceDir, resultPath); ok {
+			artifact.Kind = "result"
+			artifact.Title = strings.TrimSpace(task.Title)
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	seen := map[string]bool{}
+	files := 0
+	for _, call := range calls {
+		argName, ok := taskFileWriteArgs[call.ToolName]
+		if !ok || call.Status != "succeeded" || files >= taskArtifactMaxFiles {
+			continue
+		}
+		args := map[string]any{}
+		if err := json.Unmarshal([]byte(call.ToolArgs), &args); err != nil {
+			continue
+		}
+		scratchPath, _ := args[argName].(string)
+		scratchPath = path.Clean(strings.ReplaceAll(strings.TrimSpace(scratchPath), "\\", "/"))
+		if scratchPath == "." || strings.HasPrefix(scratchPath, "/") || strings.Contains(scratchPath, "..") {
+			continue
+		}
+		relativePath := "scratch/" + scratchPath
+		if seen[relativePath] {
+			continue
+		}
+		seen[relativePath] = true
+		artifact, ok := statTaskArtifact(workspaceDir, relativePath)
+		if !ok {
+			continue
+		}
+		artifact.Kind = "file"
+		artifact.Title = path.Base(scratchPath)
+		artifacts = append(artifacts, artifact)
+		files++
+	}
+	return artifacts
+}
+
+func statTaskArtifact(workspaceDir, relativePath string) (orchestrator.TaskArtifact, bool) {
+	info, err := os.Stat(filepath.Join(workspaceDir, filepath.FromSlash(relativePath)))
+	if err != nil || !info.Mode().IsRegular() {
+		return orchestrator.TaskArtifact{}, false
+	}
+	return orchestrator.TaskArtifact{
+		Path:      relativePath,
+		MediaType: artifactMediaType(relativePath),
+		SizeBytes: info.Size(),
+	}, true
+}
+
+// artifactMediaType guesses a media type from the file extension, covering
+// the text formats tasks usually write that the mime table may not know.
+func artifactMediaType(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return "text/markdown; charset=utf-8"
+	case ".txt", ".log":
+		return "text/plain; charset=utf-8"
+	case ".csv":
+		return "text/csv; charset=utf-8"
+	case ".json":
+		return "application/json"
+	case ".yaml", ".yml":
+		return "application/yaml"
+	}
+	if mediaType := mime.TypeByExtension(path.Ext(name)); mediaType != "" {
+		return mediaType
+	}
+	return "application/octet-stream"
+}
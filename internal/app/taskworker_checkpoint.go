@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// taskCheckpointer stores agent turn checkpoints for one task turn so a
+// worker restarted mid-task resumes the turn from its last finished step.
+type taskCheckpointer struct {
+	store  *store.Store
+	taskID string
+	scope  string
+}
+
+func (c taskCheckpointer) LoadCheckpoint(ctx context.Context) (agent.Checkpoint, bool, error) {
+	record, err := c.store.LookupTaskCheckpoint(ctx, c.taskID, c.scope)
+	if err != nil {
+		if errors.Is(err, store.ErrTaskCheckpointNotFound) {
+			return agent.Checkpoint{}, false, nil
+		}
+		return agent.Checkpoint{}, false, err
+	}
+	var checkpoint agent.Checkpoint
+	if err := json.Unmarshal([]byte(record.State), &checkpoint); err != nil {
+		// A checkpoint we cannot read is treated as absent; the turn reruns.
+		return agent.Checkpoint{}, false, nil
+	}
+	return checkpoint, true, nil
+}
+
+func (c taskCheckpointer) SaveCheckpoint(ctx context.Context, checkpoint agent.Checkpoint) error {
+	state, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := c.store.SaveTaskCheckpoint(ctx, c.taskID, c.scope, checkpoint.Step, string(state)); err != nil {
+		return err
+	}
+	if c.scope == "" {
+		// A single turn has no known length; planned steps report their
+		// position in the plan instead.
+		orchestrator.ReportProgress(ctx, orchestrator.TaskProgress{Percent: -1, Step: fmt.Sprintf("agent step %d", checkpoint.Step+1)})
+	}
+	return nil
+}
+
+func (e *taskWorkerExecutor) withTaskCheckpointer(ctx context.Context, taskID, scope string) context.Context {
+	if e.store == nil || taskID == "" {
+		return ctx
+	}
+	return agent.WithCheckpointer(ctx, taskCheckpointer{store: e.store, taskID: taskID, scope: scope})
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestTaskWorkerResumesAgentTurnFromCheckpoint(t *testing.T) {
+	sqlStore, task := newPlanTestTask(t)
+	ctx := context.Background()
+	state, err := json.Marshal(agent.Checkpoint{
+		Step:          1,
+		ToolCallCount: 1,
+		ToolCalls: []agent.ToolCall{{
+			ToolName:   "search_docs",
+			ToolArgs:   `{"query":"go.mod"}`,
+			Status:     "succeeded",
+			ToolOutput: "go.mod lists 12 modules",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("marshal checkpoint: %v", err)
+	}
+	if err := sqlStore.SaveTaskCheckpoint(ctx, task.ID, "", 1, string(state)); err != nil {
+		t.Fatalf("save checkpoint: %v", err)
+	}
+	responder := &fakeResponder{reply: "2 modules are outdated."}
+	executor := newTaskWorkerExecutor(t.TempDir(), sqlStore, responder, nil, nil, nil, config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result, err := executor.Execute(ctx, task)
+	if err != nil {
+		t.Fatalf("execute task: %v", err)
+	}
+	if result.Summary != "2 modules are outdated." || responder.replyCount != 1 {
+		t.Fatalf("expected one resumed model call, got %q after %d calls", result.Summary, responder.replyCount)
+	}
+	if !strings.Contains(responder.lastInput.Text, "go.mod lists 12 modules") || !strings.Contains(responder.lastInput.Text, "STEP 2 OF") {
+		t.Fatalf("expected resumed work log at step 2, got %q", responder.lastInput.Text)
+	}
+	if _, err := sqlStore.LookupTaskCheckpoint(ctx, task.ID, ""); !errors.Is(err, store.ErrTaskCheckpointNotFound) {
+		t.Fatalf("expected checkpoint cleared after completion, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const taskPlanStepResultMaxChars = 1200
+
+// executePlannedTask runs a task in two phases: the agent first writes a step
+// plan that is stored on the task, then each step runs as its own agent turn
+// and is checkpointed when it finishes. The plan is re-read before every step
+// so admin edits apply mid-flight, and a requeued task resumes at the first
+// step that is not done. The bool result is false when the task should run
+// as a single turn instead, because planning is off or produced nothing.
+func (e *taskWorkerExecutor) executePlannedTask(ctx context.Context, task orchestrator.Task, input llm.MessageInput) (agent.Result, []store.TaskPlanStep, bool, error) {
+	if e.store == nil || e.agent == nil {
+		return agent.Result{}, nil, false, nil
+	}
+	steps, err := e.store.ListTaskPlan(ctx, task.ID)
+	if err != nil {
+		return agent.Result{}, nil, false, err
+	}
+	if len(steps) == 0 {
+		if !e.plannerEnabled {
+			return agent.Result{}, nil, false, nil
+		}
+		planned, err := e.agent.Plan(ctx, input, e.plannerMaxSteps)
+		if err != nil {
+			e.logger.Warn("task planning failed; running as a single turn", "task_id", task.ID, "error", err)
+			return agent.Result{}, nil, false, nil
+		}
+		steps, err = e.store.SetTaskPlan(ctx, task.ID, planned)
+		if err != nil {
+			e.logger.Warn("persist task plan failed; running as a single turn", "task_id", task.ID, "error", err)
+			return agent.Result{}, nil, false, nil
+		}
+	}
+
+	combined := agent.Result{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return agent.Result{}, nil, true, err
+		}
+		steps, err = e.store.ListTaskPlan(ctx, task.ID)
+		if err != nil {
+			return agent.Result{}, nil, true, err
+		}
+		current := -1
+		for index, step := range steps {
+			if step.Status != store.TaskPlanStepDone {
+				current = index
+				break
+			}
+		}
+		if current < 0 {
+			break
+		}
+		step := steps[current]
+		if err := e.store.UpdateTaskPlanStep(ctx, task.ID, step.Position, store.TaskPlanStepRunning, ""); err != nil {
+			return agent.Result{}, nil, true, err
+		}
+		orchestrator.ReportProgress(ctx, orchestrator.TaskProgress{
+			Percent: current * 100 / len(steps),
+			Step:    fmt.Sprintf("step %d/%d: %s", current+1, len(steps), step.Description),
+		})
+
+		stepInput := input
+		stepInput.Text = buildPlanStepPrompt(input.Text, steps, current)
+		result := e.agent.Execute(e.withTaskCheckpointer(ctx, task.ID, fmt.Sprintf("plan:%d", step.Position)), stepInput)
+		combined.ToolCalls = append(combined.ToolCalls, result.ToolCalls...)
+		combined.Trace = append(combined.Trace, result.Trace...)
+		combined.Steps += result.Steps
+		combined.ActionTaken = combined.ActionTaken || result.ActionTaken
+		combined.Confidence = result.Confidence
+		combined.Policy = result.Policy
+
+		reply := strings.TrimSpace(result.Reply)
+		if result.Error != nil || result.Blocked {
+			reason := strings.TrimSpace(result.BlockReason)
+			if result.Error != nil {
+				reason = result.Error.Error()
+			}
+			_ = e.store.UpdateTaskPlanStep(ctx, task.ID, step.Position, store.TaskPlanStepFailed, truncatePreservingLines(reply+"\n"+reason, taskPlanStepResultMaxChars))
+			combined.Error = result.Error
+			combined.Blocked = result.Blocked
+			combined.BlockReason = result.BlockReason
+			combined.Reply = fmt.Sprintf("Stopped at plan step %d (%s): %s", current+1, step.Description, reply)
+			steps[current].Status = store.TaskPlanStepFailed
+			return combined, steps, true, nil
+		}
+		if err := e.store.UpdateTaskPlanStep(ctx, task.ID, step.Position, store.TaskPlanStepDone, truncatePreservingLines(reply, taskPlanStepResultMaxChars)); err != nil {
+			return agent.Result{}, nil, true, err
+		}
+		combined.Reply = reply
+	}
+	if combined.Reply == "" && len(steps) > 0 {
+		// Every step was already done, e.g. the worker restarted right after
+		// the last checkpoint.
+		combined.Reply = steps[len(steps)-1].Result
+	}
+	return combined, steps, true, nil
+}
+
+func buildPlanStepPrompt(taskText string, steps []store.TaskPlanStep, current int) string {
+	var builder strings.Builder
+	builder.WriteString("TASK:\n")
+	builder.WriteString(strings.TrimSpace(taskText))
+	builder.WriteString("\n\nPLAN:\n")
+	for index, step := range steps {
+		status := "pending"
+		switch {
+		case index == current:
+			status = "current"
+		case step.Status == store.TaskPlanStepDone:
+			status = "done"
+		}
+		builder.WriteString(fmt.Sprintf("%d. [%s] %s\n", index+1, status, step.Description))
+		if status == "done" && strings.TrimSpace(step.Result) != "" {
+			builder.WriteString("   result: " + compactStepResult(step.Result) + "\n")
+		}
+	}
+	builder.WriteString(fmt.Sprintf("\nComplete only step %d now: %s\n", current+1, steps[current].Description))
+	if current == len(steps)-1 {
+		builder.WriteString("This is the last step: the final answer should answer the whole task using the results above.")
+	} else {
+		builder.WriteString("Return a final answer describing the outcome of this step; later steps run separately.")
+	}
+	return builder.String()
+}
+
+func compactStepResult(result string) string {
+	text := strings.Join(strings.Fields(result), " ")
+	if len(text) > 600 {
+		return text[:600] + "..."
+	}
+	return text
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func newPlanTestTask(t *testing.T) (*store.Store, orchestrator.Task) {
+	t.Helper()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "plan.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(context.Background()); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	task := orchestrator.Task{
+		ID:          "task-plan-1",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        orchestrator.TaskKindGeneral,
+		Title:       "Dependency audit",
+		Prompt:      "Audit dependencies and report outdated ones",
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := sqlStore.CreateTask(context.Background(), store.CreateTaskInput{
+		ID:          task.ID,
+		WorkspaceID: task.WorkspaceID,
+		ContextID:   task.ContextID,
+		Kind:        string(task.Kind),
+		Title:       task.Title,
+		Prompt:      task.Prompt,
+		Status:      "running",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	return sqlStore, task
+}
+
+func TestTaskWorkerPlansAndCheckpointsSteps(t *testing.T) {
+	sqlStore, task := newPlanTestTask(t)
+	tempRoot := t.TempDir()
+	responder := &scriptedResponder{replies: []string{
+		`{"steps": ["List modules", "Write the report"]}`,
+		"Found 12 modules.",
+		"2 modules are outdated.",
+	}}
+	executor := newTaskWorkerExecutor(tempRoot, sqlStore, responder, nil, nil, nil, config.Config{
+		AgentPlannerEnabled:  true,
+		AgentPlannerMaxSteps: 5,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result, err := executor.Execute(context.Background(), task)
+	if err != nil {
+		t.Fatalf("execute task: %v", err)
+	}
+	if result.Summary != "2 modules are outdated." {
+		t.Fatalf("expected last step reply as summary, got %q", result.Summary)
+	}
+	steps, err := sqlStore.ListTaskPlan(context.Background(), task.ID)
+	if err != nil || len(steps) != 2 {
+		t.Fatalf("expected stored two-step plan, got %+v (%v)", steps, err)
+	}
+	if steps[0].Status != store.TaskPlanStepDone || steps[0].Result != "Found 12 modules." || steps[1].Status != store.TaskPlanStepDone {
+		t.Fatalf("expected checkpointed steps, got %+v", steps)
+	}
+	content, err := os.ReadFile(filepath.Join(tempRoot, task.WorkspaceID, filepath.FromSlash(result.ArtifactPath)))
+	if err != nil || !strings.Contains(string(content), "1. [done] List modules") {
+		t.Fatalf("expected plan in artifact, got %s (%v)", content, err)
+	}
+}
+
+func TestTaskWorkerResumesStoredPlan(t *testing.T) {
+	sqlStore, task := newPlanTestTask(t)
+	ctx := context.Background()
+	if _, err := sqlStore.SetTaskPlan(ctx, task.ID, []string{"List modules", "Write the report"}); err != nil {
+		t.Fatalf("set plan: %v", err)
+	}
+	if err := sqlStore.UpdateTaskPlanStep(ctx, task.ID, 1, store.TaskPlanStepDone, "Found 12 modules."); err != nil {
+		t.Fatalf("checkpoint: %v", err)
+	}
+	responder := &fakeResponder{reply: "Report written."}
+	executor := newTaskWorkerExecutor(t.TempDir(), sqlStore, responder, nil, nil, nil, config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, err := executor.Execute(ctx, task); err != nil {
+		t.Fatalf("execute task: %v", err)
+	}
+	if responder.replyCount != 1 {
+		t.Fatalf("expected only the remaining step to run, got %d model calls", responder.replyCount)
+	}
+	if !strings.Contains(responder.lastInput.Text, "Complete only step 2 now: Write the report") ||
+		!strings.Contains(responder.lastInput.Text, "result: Found 12 modules.") {
+		t.Fatalf("expected step prompt with prior results, got %q", responder.lastInput.Text)
+	}
+}
@@ -2,6 +2,9 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
@@ -11,6 +14,7 @@ import (
 	"time"
 
 	actionexecutor "github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/agent"
 	"github.com/dwizi/agent-runtime/internal/agent/tools"
 	"github.com/dwizi/agent-runtime/internal/config"
 	"github.com/dwizi/agent-runtime/internal/gateway"
@@ -292,6 +296,7 @@ func TestTaskObserverPersistsLifecycle(t *testing.T) {
 	observer.OnTaskCompleted(task, 3, orchestrator.TaskResult{
 		Summary:      "done",
 		ArtifactPath: "tasks/task-2.md",
+		Data:         json.RawMessage(`[{"tool":"list_files","kind":"table","data":{"columns":["Name"],"rows":[["notes.md"]]}}]`),
 	})
 
 	record, err := sqlStore.LookupTask(context.Background(), task.ID)
@@ -307,6 +312,153 @@ func TestTaskObserverPersistsLifecycle(t *testing.T) {
 	if record.ResultPath != "tasks/task-2.md" {
 		t.Fatalf("unexpected result path: %s", record.ResultPath)
 	}
+	if !strings.Contains(record.ResultData, `"tool":"list_files"`) {
+		t.Fatalf("expected structured result data, got %q", record.ResultData)
+	}
+}
+
+func TestTaskObserverRetriesAndDeadLetters(t *testing.T) {
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "agent-runtime.sqlite"))
+	if err != nil {
+		t.Fatalf("open test store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	ctx := context.Background()
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	observer := newTaskObserver(sqlStore, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	task := orchestrator.Task{ID: "task-retry", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: orchestrator.TaskKindGeneral, Title: "Flaky", Prompt: "Call the API"}
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{ID: task.ID, WorkspaceID: task.WorkspaceID, ContextID: task.ContextID, Kind: string(task.Kind), Title: task.Title, Prompt: task.Prompt, Status: "queued"}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	observer.OnTaskStarted(task, 1)
+	task.Attempts = 1
+	observer.OnTaskRetry(task, 1, errors.New("503 from upstream"), time.Second)
+	record, err := sqlStore.LookupTask(ctx, task.ID)
+	if err != nil || record.Status != "queued" || record.ErrorMessage != "503 from upstream" {
+		t.Fatalf("expected task queued for retry, got %+v (%v)", record, err)
+	}
+
+	observer.OnTaskStarted(task, 2)
+	task.Attempts = 2
+	observer.OnTaskFailed(task, 2, fmt.Errorf("%w after 2 attempts: %w", orchestrator.ErrRetriesExhausted, errors.New("503 from upstream")))
+	record, err = sqlStore.LookupTask(ctx, task.ID)
+	if err != nil || record.Status != "failed" || record.Attempts != 2 || record.DeadLetteredAt.IsZero() {
+		t.Fatalf("expected failed dead-lettered task, got %+v (%v)", record, err)
+	}
+}
+
+// waitingExecutor runs until its context ends, like an agent turn waiting
+// on the model.
+type waitingExecutor struct {
+	started chan struct{}
+	stopped chan error
+}
+
+func (e *waitingExecutor) Execute(ctx context.Context, task orchestrator.Task) (orchestrator.TaskResult, error) {
+	close(e.started)
+	<-ctx.Done()
+	e.stopped <- ctx.Err()
+	return orchestrator.TaskResult{}, ctx.Err()
+}
+
+func TestTaskCancellerStopsRunningTask(t *testing.T) {
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "agent-runtime.sqlite"))
+	if err != nil {
+		t.Fatalf("open test store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	ctx := context.Background()
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := orchestrator.New(1, logger)
+	executor := &waitingExecutor{started: make(chan struct{}), stopped: make(chan error, 1)}
+	engine.SetExecutor(executor)
+	engine.SetObserver(newTaskObserver(sqlStore, nil, logger))
+	runCtx, stop := context.WithCancel(ctx)
+	t.Cleanup(stop)
+	go func() {
+		_ = engine.Start(runCtx)
+	}()
+
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{ID: "task-long", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general", Title: "Long research", Prompt: "Research", Status: "queued"}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := engine.Enqueue(orchestrator.Task{ID: "task-long", WorkspaceID: "ws-1", ContextID: "ctx-1", Title: "Long research", Prompt: "Research"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	select {
+	case <-executor.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("task did not start")
+	}
+
+	canceller := taskCanceller{store: sqlStore, engine: engine}
+	record, err := canceller.CancelTask(ctx, "task-long")
+	if err != nil || record.Status != "cancelled" {
+		t.Fatalf("expected cancelled record, got %+v (%v)", record, err)
+	}
+	select {
+	case err := <-executor.stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the run context cancelled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("executor context was not cancelled")
+	}
+	if _, err := canceller.CancelTask(ctx, "task-long"); !errors.Is(err, store.ErrTaskNotActive) {
+		t.Fatalf("expected a second cancel refused, got %v", err)
+	}
+	record, err = sqlStore.LookupTask(ctx, "task-long")
+	if err != nil || record.Status != "cancelled" || record.ErrorMessage != "" {
+		t.Fatalf("expected the task to stay cancelled, got %+v (%v)", record, err)
+	}
+}
+
+func TestTaskRetryPoliciesFromConfig(t *testing.T) {
+	fallback, byKind := newTaskRetryPolicies(config.Config{
+		TaskRetryMaxAttempts:   3,
+		TaskRetryBackoffSec:    30,
+		TaskRetryMaxBackoffSec: 600,
+		TaskRetryJitter:        0.2,
+		TaskRetryPolicies:      "objective=5:60:1800, reindex_markdown=1, general=x, broken",
+	})
+	if fallback.MaxAttempts != 3 || fallback.Backoff != 30*time.Second || fallback.MaxBackoff != 10*time.Minute || fallback.Jitter != 0.2 {
+		t.Fatalf("unexpected fallback policy %+v", fallback)
+	}
+	objective := byKind[orchestrator.TaskKindObjective]
+	if objective.MaxAttempts != 5 || objective.Backoff != time.Minute || objective.MaxBackoff != 30*time.Minute || objective.Jitter != 0.2 {
+		t.Fatalf("unexpected objective policy %+v", objective)
+	}
+	if reindex := byKind[orchestrator.TaskKindReindex]; reindex.MaxAttempts != 1 || reindex.Backoff != 30*time.Second {
+		t.Fatalf("expected reindex retries off with the fallback backoff, got %+v", reindex)
+	}
+	if len(byKind) != 2 {
+		t.Fatalf("expected malformed entries skipped, got %+v", byKind)
+	}
+}
+
+func TestBuildTaskMarkdownRendersStructuredToolOutput(t *testing.T) {
+	data := json.RawMessage(`{"columns":["Name","Type"],"rows":[["notes.md","file"]]}`)
+	calls := []agent.ToolCall{
+		{ToolName: "list_files", ToolOutput: "notes.md", DataKind: tools.ResultKindTable, Data: data},
+		{ToolName: "read_file", ToolOutput: "hello"},
+	}
+	content := buildTaskMarkdown(orchestrator.Task{ID: "task-md"}, time.Now().UTC(), agent.Result{ToolCalls: calls}, nil)
+	if !strings.Contains(content, "| Name | Type |\n| --- | --- |\n| notes.md | file |") {
+		t.Fatalf("expected rendered table, got:\n%s", content)
+	}
+	if !strings.Contains(content, "```\nhello\n```") {
+		t.Fatalf("expected plain output code block, got:\n%s", content)
+	}
+	payload := collectTaskResultData(calls)
+	if !strings.Contains(string(payload), `"tool":"list_files","kind":"table"`) || strings.Contains(string(payload), "read_file") {
+		t.Fatalf("unexpected collected result data: %s", payload)
+	}
 }
 
 func TestTaskWorkerExecutorReindexSkipsDuplicateQueueForFileWatcherContext(t *testing.T) {
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/translate"
+)
+
+// translationMirror republishes messages from source channels into the
+// translated mirror channels listed in a workspace's context/translation.json.
+type translationMirror struct {
+	workspaceRoot string
+	store         *store.Store
+	translator    gateway.Translator
+	publishers    map[string]connectors.Publisher
+	logger        *slog.Logger
+}
+
+func newTranslationMirror(
+	workspaceRoot string,
+	storeRef *store.Store,
+	translator gateway.Translator,
+	publishers map[string]connectors.Publisher,
+	logger *slog.Logger,
+) *translationMirror {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clean := map[string]connectors.Publisher{}
+	for key, publisher := range publishers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || publisher == nil {
+			continue
+		}
+		clean[name] = publisher
+	}
+	return &translationMirror{
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		store:         storeRef,
+		translator:    translator,
+		publishers:    clean,
+		logger:        logger,
+	}
+}
+
+// MirrorMessage translates in the background so the chat reply is never
+// delayed by mirroring.
+func (m *translationMirror) MirrorMessage(ctx context.Context, input gateway.MessageInput) {
+	if m == nil || m.store == nil || m.translator == nil {
+		return
+	}
+	go m.mirror(context.WithoutCancel(ctx), input)
+}
+
+func (m *translationMirror) mirror(ctx context.Context, input gateway.MessageInput) {
+	text := strings.TrimSpace(input.Text)
+	if text == "" {
+		return
+	}
+	contextRecord, err := m.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		m.logger.Error("resolve mirror source context failed", "connector", input.Connector, "external_id", input.ExternalID, "error", err)
+		return
+	}
+	settings, err := translate.LoadSettings(m.workspaceRoot, contextRecord.WorkspaceID)
+	if err != nil {
+		m.logger.Warn("load translation settings failed", "workspace_id", contextRecord.WorkspaceID, "error", err)
+		return
+	}
+	for _, mirror := range settings.MirrorsFor(input.Connector, input.ExternalID) {
+		publisher := m.publishers[mirror.TargetConnector]
+		if publisher == nil {
+			continue
+		}
+		translateCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		translation, err := m.translator.Translate(translateCtx, translate.Request{
+			WorkspaceID:    contextRecord.WorkspaceID,
+			Text:           text,
+			TargetLanguage: mirror.Language,
+			Glossary:       settings.Glossary,
+		})
+		cancel()
+		if err != nil {
+			m.logger.Warn("mirror translation failed",
+				"workspace_id", contextRecord.WorkspaceID,
+				"language", mirror.Language,
+				"error", err,
+			)
+			continue
+		}
+		publishCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err = publisher.Publish(publishCtx, mirror.TargetExternalID, translation)
+		cancel()
+		if err != nil {
+			m.logger.Error("publish mirrored message failed",
+				"workspace_id", contextRecord.WorkspaceID,
+				"connector", mirror.TargetConnector,
+				"external_id", mirror.TargetExternalID,
+				"error", err,
+			)
+			continue
+		}
+		appendOutboundChatLog(m.workspaceRoot, contextRecord.WorkspaceID, mirror.TargetConnector, mirror.TargetExternalID, translation)
+	}
+}
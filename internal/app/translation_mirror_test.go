@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/translate"
+)
+
+type prefixTranslator struct{}
+
+func (prefixTranslator) Translate(ctx context.Context, req translate.Request) (string, error) {
+	return "[" + req.TargetLanguage + "] " + req.Text, nil
+}
+
+func TestTranslationMirrorPublishesToConfiguredChannels(t *testing.T) {
+	sqlStore := openAppTestStore(t)
+	ctx := context.Background()
+	contextRecord, err := sqlStore.EnsureContextForExternalChannel(ctx, "discord", "announce", "announcements")
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	root := t.TempDir()
+	dir := filepath.Join(root, contextRecord.WorkspaceID, "context")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"mirrors": [
+  {"source_connector": "discord", "source_external_id": "announce", "target_connector": "discord", "target_external_id": "anuncios", "language": "es"},
+  {"source_connector": "discord", "source_external_id": "announce", "target_connector": "telegram", "target_external_id": "-100", "language": "fr"}
+]}`
+	if err := os.WriteFile(filepath.Join(dir, "translation.json"), []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	publisher := &fakePublisher{}
+	mirror := newTranslationMirror(root, sqlStore, prefixTranslator{}, map[string]connectors.Publisher{"discord": publisher}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mirror.mirror(ctx, gateway.MessageInput{Connector: "discord", ExternalID: "general", Text: "hello"})
+	mirror.mirror(ctx, gateway.MessageInput{Connector: "discord", ExternalID: "announce", Text: "Release 1.4 is out"})
+
+	if len(publisher.messages) != 1 {
+		t.Fatalf("expected one mirrored message, got %+v", publisher.messages)
+	}
+	if got := publisher.messages[0]; got.externalID != "anuncios" || got.text != "[es] Release 1.4 is out" {
+		t.Fatalf("unexpected mirrored message %+v", got)
+	}
+}
@@ -0,0 +1,343 @@
+// Package approvalpolicy decides which action approvals may run without
+// waiting for an admin. A workspace declares auto-approve rules in its
+// botfile, e.g. "curl GET to docs.example.com" or "python_code without
+// filesystem writes"; workspaces without rules keep the default, which trusts
+// admins and task workers with every action.
+package approvalpolicy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/actions/plugins/sandbox"
+	"github.com/dwizi/agent-runtime/internal/egress"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// RoleSystem is the requester role of actions started by the task worker.
+const RoleSystem = "system"
+
+var commandActionTypes = map[string]bool{"run_command": true, "shell_command": true, "cli_command": true}
+
+var knownMethods = map[string]bool{"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "OPTIONS": true}
+
+// Rule auto-approves actions that match every field it sets. Empty fields
+// match anything, except that an action must not write unless AllowWrites
+// is set.
+type Rule struct {
+	Name string `yaml:"name"`
+	// Tool is the agent tool that requested the action, e.g. python_code.
+	Tool string `yaml:"tool"`
+	// Action is the action type, e.g. run_command or fetch_url.
+	Action string `yaml:"action"`
+	// Commands are the executables a command action may run.
+	Commands []string `yaml:"commands"`
+	// Domains must cover every URL the action reaches, subdomains included.
+	// An action without a recognizable URL does not match.
+	Domains []string `yaml:"domains"`
+	// Methods are the HTTP methods the action may use; GET when it names
+	// none.
+	Methods []string `yaml:"methods"`
+	// Roles are the requester roles the rule applies to; RoleSystem stands
+	// for the task worker.
+	Roles []string `yaml:"roles"`
+	// AllowWrites lets the action write files, e.g. a command approved with
+	// write access or curl saving its output.
+	AllowWrites bool `yaml:"allow_writes"`
+	// MaxRisk is the highest risk level the action may be rated; actions
+	// without a rating do not match.
+	MaxRisk string `yaml:"max_risk"`
+}
+
+// Policy is an ordered list of rules; the first match approves.
+type Policy struct {
+	Rules []Rule
+}
+
+// Default is the policy of workspaces that declare no rules: admins,
+// overlords and the task worker may run anything.
+func Default() Policy {
+	return Policy{Rules: []Rule{{
+		Name:        "trusted requesters",
+		Roles:       []string{"admin", "overlord", RoleSystem},
+		AllowWrites: true,
+	}}}
+}
+
+// Request is an action a tool just created, with who asked for it.
+type Request struct {
+	Tool     string
+	Role     string
+	Approval store.ActionApproval
+}
+
+type Decision struct {
+	Approve bool
+	// Rule names the matching rule, or its position when it has no name.
+	Rule string
+}
+
+func (p Policy) Evaluate(request Request) Decision {
+	for index, rule := range p.Rules {
+		if rule.matches(request) {
+			name := strings.TrimSpace(rule.Name)
+			if name == "" {
+				name = fmt.Sprintf("rule %d", index+1)
+			}
+			return Decision{Approve: true, Rule: name}
+		}
+	}
+	return Decision{}
+}
+
+// Normalize trims and lowercases the rule so matching can compare directly.
+func (r *Rule) Normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Tool = strings.ToLower(strings.TrimSpace(r.Tool))
+	r.Action = strings.ToLower(strings.TrimSpace(r.Action))
+	if r.Action == "*" {
+		r.Action = ""
+	}
+	r.Commands = normalizeList(r.Commands, strings.ToLower)
+	r.Domains = normalizeList(r.Domains, func(domain string) string {
+		return strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(domain), "*."), ".")
+	})
+	r.Methods = normalizeList(r.Methods, strings.ToUpper)
+	r.Roles = normalizeList(r.Roles, strings.ToLower)
+	r.MaxRisk = strings.ToLower(strings.TrimSpace(r.MaxRisk))
+}
+
+// Validate lists the problems of a normalized rule.
+func (r Rule) Validate() []string {
+	problems := []string{}
+	for _, method := range r.Methods {
+		if !knownMethods[method] {
+			problems = append(problems, fmt.Sprintf("unknown method %q", method))
+		}
+	}
+	for _, domain := range r.Domains {
+		if strings.ContainsAny(domain, "/:@ ") {
+			problems = append(problems, fmt.Sprintf("domain %q must be a bare host name", domain))
+		}
+	}
+	if r.MaxRisk != "" && store.NormalizeActionRisk(r.MaxRisk) == "" {
+		problems = append(problems, fmt.Sprintf("max_risk %q must be low, medium or high", r.MaxRisk))
+	}
+	if len(r.Commands) > 0 && r.Action != "" && !commandActionTypes[r.Action] {
+		problems = append(problems, "commands only apply to command actions")
+	}
+	return problems
+}
+
+func (r Rule) matches(request Request) bool {
+	approval := request.Approval
+	actionType := strings.ToLower(strings.TrimSpace(approval.ActionType))
+	if r.Tool != "" && r.Tool != strings.ToLower(strings.TrimSpace(request.Tool)) {
+		return false
+	}
+	if r.Action != "" && r.Action != actionType {
+		return false
+	}
+	if len(r.Roles) > 0 && !contains(r.Roles, strings.ToLower(strings.TrimSpace(request.Role))) {
+		return false
+	}
+	if r.MaxRisk != "" {
+		level := store.NormalizeActionRisk(approval.RiskLevel)
+		if level == "" || store.ActionRiskRank(level) > store.ActionRiskRank(r.MaxRisk) {
+			return false
+		}
+	}
+	inspected := inspect(approval)
+	if len(r.Commands) > 0 && (inspected.command == "" || !contains(r.Commands, inspected.command)) {
+		return false
+	}
+	if inspected.writes && !r.AllowWrites {
+		return false
+	}
+	// An opaque curl command line may write or reach any host with any
+	// method, so only rules that allow all of that match it.
+	if inspected.opaque && (!r.AllowWrites || len(r.Methods) > 0 || len(r.Domains) > 0) {
+		return false
+	}
+	if len(r.Methods) > 0 && !contains(r.Methods, inspected.method) {
+		return false
+	}
+	if len(r.Domains) > 0 {
+		if len(inspected.hosts) == 0 {
+			return false
+		}
+		for _, host := range inspected.hosts {
+			if !coveredByDomains(host, r.Domains) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// inspection is what an action does, as far as the rules care.
+type inspection struct {
+	command string
+	method  string
+	hosts   []string
+	writes  bool
+	// opaque is set when a curl command line has flags or URLs the policy
+	// cannot read, so its method, hosts and writes are not known.
+	opaque bool
+}
+
+func inspect(approval store.ActionApproval) inspection {
+	result := inspection{method: "GET"}
+	if write, ok := approval.Payload["write"].(bool); ok && write {
+		result.writes = true
+	}
+	if method, ok := approval.Payload["method"].(string); ok && strings.TrimSpace(method) != "" {
+		result.method = strings.ToUpper(strings.TrimSpace(method))
+	}
+	candidates := []string{approval.ActionTarget}
+	for _, key := range []string{"url", "endpoint", "webhook_url"} {
+		if value, ok := approval.Payload[key].(string); ok {
+			candidates = append(candidates, value)
+		}
+	}
+	if commandActionTypes[strings.ToLower(strings.TrimSpace(approval.ActionType))] {
+		if command, args, err := sandbox.ParseCommand(approval); err == nil {
+			result.command = strings.ToLower(command)
+			if result.command == "curl" {
+				inspectCurl(args, &result)
+			} else {
+				candidates = append(candidates, args...)
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		parsed, err := url.Parse(strings.TrimSpace(candidate))
+		if err != nil || parsed.Hostname() == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			continue
+		}
+		result.addHost(parsed.Hostname())
+	}
+	return result
+}
+
+func (i *inspection) addHost(host string) {
+	host = strings.ToLower(host)
+	if !contains(i.hosts, host) {
+		i.hosts = append(i.hosts, host)
+	}
+}
+
+// curlFlags are the curl flags the policy understands. Any other flag makes
+// the command line opaque: it could write files or send the request
+// somewhere the URLs do not say.
+var curlFlags = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true,
+	"-L": true, "--location": true, "-f": true, "--fail": true, "--fail-with-body": true,
+	"-i": true, "--include": true, "-I": true, "--head": true, "-v": true, "--verbose": true,
+	"-g": true, "--globoff": true, "-G": true, "--get": true, "--compressed": true,
+	"-#": true, "--progress-bar": true, "-N": true, "--no-buffer": true,
+	"-4": true, "--ipv4": true, "-6": true, "--ipv6": true, "--http1.1": true, "--http2": true,
+	"-A": true, "--user-agent": true, "-H": true, "--header": true, "-e": true, "--referer": true,
+	"-u": true, "--user": true, "-b": true, "--cookie": true, "-r": true, "--range": true,
+	"-m": true, "--max-time": true, "--connect-timeout": true, "--retry": true, "--max-redirs": true,
+	"-X": true, "--request": true, "--url": true,
+	"-d": true, "--data": true, "--data-ascii": true, "--data-binary": true, "--data-raw": true,
+	"--data-urlencode": true, "--json": true, "-F": true, "--form": true, "--form-string": true,
+	"-T": true, "--upload-file": true,
+	"-o": true, "--output": true, "-O": true, "--remote-name": true,
+	"-c": true, "--cookie-jar": true, "-D": true, "--dump-header": true,
+}
+
+// inspectCurl reads the method, hosts and writes of a curl command line,
+// splitting bundled short flags (-sSo) and inline values (-d{...}) the way
+// the egress check does. -X sets the method, data and upload flags imply
+// POST or PUT, and flags that save output or cookies to a file write.
+func inspectCurl(args []string, result *inspection) {
+	if _, err := egress.CurlTargets(args); err != nil {
+		result.opaque = true
+		return
+	}
+	explicit, implied, get := "", "", false
+	for _, arg := range egress.ParseCurlArgs(args) {
+		if arg.URL != "" {
+			parsed, err := url.Parse(arg.URL)
+			if err != nil || parsed.Hostname() == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+				result.opaque = true
+				continue
+			}
+			result.addHost(parsed.Hostname())
+		}
+		for index, flag := range arg.Flags {
+			if !curlFlags[flag] {
+				result.opaque = true
+				continue
+			}
+			// Only the last flag of a bundle carries the value.
+			last := index == len(arg.Flags)-1
+			switch flag {
+			case "-X", "--request":
+				if last {
+					explicit = strings.ToUpper(strings.TrimSpace(arg.Value))
+				}
+			case "-G", "--get":
+				get = true
+			case "-d", "-F", "--data", "--data-ascii", "--data-binary", "--data-raw", "--data-urlencode", "--json", "--form", "--form-string":
+				if implied == "" {
+					implied = "POST"
+				}
+			case "-T", "--upload-file":
+				implied = "PUT"
+			case "-I", "--head":
+				if implied == "" {
+					implied = "HEAD"
+				}
+			case "-o", "--output", "-O", "--remote-name", "-c", "--cookie-jar":
+				result.writes = true
+			case "-D", "--dump-header":
+				if !last || strings.TrimSpace(arg.Value) != "-" {
+					result.writes = true
+				}
+			}
+		}
+	}
+	if get && implied == "POST" {
+		implied = "GET"
+	}
+	if explicit != "" {
+		result.method = explicit
+	} else if implied != "" {
+		result.method = implied
+	}
+}
+
+func coveredByDomains(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeList(values []string, transform func(string) string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			result = append(result, transform(trimmed))
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package approvalpolicy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func curlRequest(role string, args ...string) Request {
+	values := make([]any, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg)
+	}
+	return Request{
+		Tool: "fetch_url",
+		Role: role,
+		Approval: store.ActionApproval{
+			ActionType:   "run_command",
+			ActionTarget: "curl",
+			Payload:      map[string]any{"command": "curl", "args": values},
+			RiskLevel:    store.ActionRiskMedium,
+		},
+	}
+}
+
+func TestDefaultPolicyTrustsAdminsAndTaskWorkers(t *testing.T) {
+	policy := Default()
+	for _, role := range []string{"admin", "overlord", RoleSystem} {
+		if !policy.Evaluate(curlRequest(role, "-X", "DELETE", "https://api.example.com")).Approve {
+			t.Fatalf("expected %s to be trusted", role)
+		}
+	}
+	if policy.Evaluate(curlRequest("member", "https://example.com")).Approve {
+		t.Fatal("expected members to wait for an admin")
+	}
+}
+
+func TestRuleMatchesAllowlistedReadOnlyFetches(t *testing.T) {
+	rule := Rule{Name: "docs reads", Commands: []string{"CURL"}, Domains: []string{"*.Example.com"}, Methods: []string{"get"}}
+	rule.Normalize()
+	if problems := rule.Validate(); len(problems) != 0 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+	policy := Policy{Rules: []Rule{rule}}
+
+	if decision := policy.Evaluate(curlRequest("member", "-sSL", "https://docs.example.com/guide")); !decision.Approve || decision.Rule != "docs reads" {
+		t.Fatalf("expected allowlisted GET to be approved, got %+v", decision)
+	}
+	for name, request := range map[string]Request{
+		"post":          curlRequest("member", "-d", "a=1", "https://docs.example.com"),
+		"explicit put":  curlRequest("member", "-XPUT", "https://docs.example.com"),
+		"other domain":  curlRequest("member", "https://example.com.evil.io"),
+		"mixed domains": curlRequest("member", "https://docs.example.com", "https://evil.io"),
+		"no url":        curlRequest("member", "--version"),
+		"saves output":  curlRequest("member", "-o", "page.html", "https://docs.example.com"),
+	} {
+		if policy.Evaluate(request).Approve {
+			t.Fatalf("expected %s not to match", name)
+		}
+	}
+}
+
+func TestRuleReadsCurlLikeCurlDoes(t *testing.T) {
+	rule := Rule{Commands: []string{"curl"}, Domains: []string{"example.com"}, Methods: []string{"GET"}}
+	rule.Normalize()
+	policy := Policy{Rules: []Rule{rule}}
+
+	for name, request := range map[string]Request{
+		"url flag":       curlRequest("member", "--url", "https://example.com"),
+		"bundled silent": curlRequest("member", "-sS", "example.com/page"),
+		"get with data":  curlRequest("member", "-G", "-d", "q=1", "https://example.com"),
+		"header":         curlRequest("member", "-H", "Accept: text/html", "https://example.com"),
+	} {
+		if !policy.Evaluate(request).Approve {
+			t.Fatalf("expected %s to match", name)
+		}
+	}
+	for name, request := range map[string]Request{
+		"scheme-less host":    curlRequest("member", "https://example.com", "evil.internal"),
+		"inline data":         curlRequest("member", "-d{\"a\":1}", "https://example.com"),
+		"bundled data":        curlRequest("member", "-sd", "x", "https://example.com"),
+		"inline output":       curlRequest("member", "-ofile", "https://example.com"),
+		"bundled output":      curlRequest("member", "-sSo", "f", "https://example.com"),
+		"long output":         curlRequest("member", "--output=f", "https://example.com"),
+		"unknown flag":        curlRequest("member", "--netrc-file", "n", "https://example.com"),
+		"unknown short flag":  curlRequest("member", "-sk", "https://example.com"),
+		"proxy":               curlRequest("member", "-x", "http://proxy.internal", "https://example.com"),
+		"other scheme":        curlRequest("member", "https://example.com", "file:///etc/passwd"),
+		"explicit post after": curlRequest("member", "-G", "-XPOST", "https://example.com"),
+	} {
+		if policy.Evaluate(request).Approve {
+			t.Fatalf("expected %s not to match", name)
+		}
+	}
+	if !Default().Evaluate(curlRequest("admin", "-k", "https://example.com")).Approve {
+		t.Fatal("expected rules that allow everything to accept unknown flags")
+	}
+}
+
+func TestRuleChecksWritesRolesAndRisk(t *testing.T) {
+	python := Request{
+		Tool: "python_code",
+		Role: "member",
+		Approval: store.ActionApproval{
+			ActionType: "run_command",
+			Payload:    map[string]any{"command": "python3", "args": []any{"scratch/a.py"}},
+			RiskLevel:  store.ActionRiskHigh,
+		},
+	}
+	rule := Rule{Tool: "python_code"}
+	rule.Normalize()
+	if decision := (Policy{Rules: []Rule{rule}}).Evaluate(python); !decision.Approve || decision.Rule != "rule 1" {
+		t.Fatalf("expected python without writes to be approved, got %+v", decision)
+	}
+	python.Approval.Payload["write"] = true
+	if (Policy{Rules: []Rule{rule}}).Evaluate(python).Approve {
+		t.Fatal("expected writes to need allow_writes")
+	}
+
+	limited := Rule{Tool: "python_code", AllowWrites: true, MaxRisk: "medium", Roles: []string{"Member"}}
+	limited.Normalize()
+	if (Policy{Rules: []Rule{limited}}).Evaluate(python).Approve {
+		t.Fatal("expected high risk to exceed max_risk")
+	}
+	python.Approval.RiskLevel = store.ActionRiskLow
+	if !(Policy{Rules: []Rule{limited}}).Evaluate(python).Approve {
+		t.Fatal("expected low risk member request to match")
+	}
+	python.Role = "guest"
+	if (Policy{Rules: []Rule{limited}}).Evaluate(python).Approve {
+		t.Fatal("expected other roles not to match")
+	}
+}
+
+func TestValidateReportsBadRules(t *testing.T) {
+	rule := Rule{Action: "send_email", Commands: []string{"curl"}, Domains: []string{"https://example.com"}, Methods: []string{"fetch"}, MaxRisk: "severe"}
+	rule.Normalize()
+	problems := strings.Join(rule.Validate(), "; ")
+	for _, want := range []string{`unknown method "FETCH"`, "bare host name", "max_risk", "commands only apply"} {
+		if !strings.Contains(problems, want) {
+			t.Fatalf("expected %q in %s", want, problems)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package artifactlink signs expiring download links for task artifacts, so
+// the people a task reports to can fetch its files without admin API
+// credentials. A link names one artifact and carries its expiry and an
+// HMAC-SHA256 signature over both.
+package artifactlink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DownloadPath is the route serving signed links.
+const DownloadPath = "/artifacts/download"
+
+var (
+	ErrInvalidLink = errors.New("invalid artifact link")
+	ErrLinkExpired = errors.New("artifact link expired")
+)
+
+type Signer struct {
+	baseURL string
+	key     []byte
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// New returns a signer for links under baseURL that stay valid for ttl, or
+// nil when baseURL or secret is empty; a nil signer makes no links.
+func New(baseURL, secret string, ttl time.Duration) *Signer {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	secret = strings.TrimSpace(secret)
+	if baseURL == "" || secret == "" {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+	return &Signer{baseURL: baseURL, key: []byte(secret), ttl: ttl, now: time.Now}
+}
+
+// Link returns the download link of an artifact, or "" on a nil signer.
+func (s *Signer) Link(artifactID string) string {
+	artifactID = strings.TrimSpace(artifactID)
+	if s == nil || artifactID == "" {
+		return ""
+	}
+	expires := strconv.FormatInt(s.now().Add(s.ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("id", artifactID)
+	query.Set("expires", expires)
+	query.Set("sig", s.sign(artifactID, expires))
+	return s.baseURL + DownloadPath + "?" + query.Encode()
+}
+
+// Verify checks the id, expires and sig query values of a link.
+func (s *Signer) Verify(artifactID, expires, signature string) error {
+	if s == nil {
+		return ErrInvalidLink
+	}
+	artifactID = strings.TrimSpace(artifactID)
+	expiresUnix, err := strconv.ParseInt(strings.TrimSpace(expires), 10, 64)
+	if artifactID == "" || err != nil {
+		return ErrInvalidLink
+	}
+	expected := s.sign(artifactID, strings.TrimSpace(expires))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(strings.TrimSpace(signature)))) {
+		return ErrInvalidLink
+	}
+	if s.now().Unix() > expiresUnix {
+		return ErrLinkExpired
+	}
+	return nil
+}
+
+func (s *Signer) sign(artifactID, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(artifactID + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
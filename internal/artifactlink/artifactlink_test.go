@@ -0,0 +1,45 @@
+package artifactlink
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignerLinksVerifyUntilExpiry(t *testing.T) {
+	if New("", "secret", time.Hour) != nil || New("https://bot.example.com", "", time.Hour) != nil {
+		t.Fatal("expected no signer without base URL and secret")
+	}
+	var disabled *Signer
+	if disabled.Link("artifact_1") != "" {
+		t.Fatal("expected no link from a nil signer")
+	}
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	signer := New("https://bot.example.com/", "secret", time.Hour)
+	signer.now = func() time.Time { return now }
+	link := signer.Link("artifact_1")
+	if !strings.HasPrefix(link, "https://bot.example.com"+DownloadPath+"?") {
+		t.Fatalf("unexpected link %q", link)
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parse link: %v", err)
+	}
+	query := parsed.Query()
+	if err := signer.Verify(query.Get("id"), query.Get("expires"), query.Get("sig")); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := signer.Verify("artifact_2", query.Get("expires"), query.Get("sig")); !errors.Is(err, ErrInvalidLink) {
+		t.Fatalf("expected another artifact refused, got %v", err)
+	}
+	if err := New("https://bot.example.com", "other", time.Hour).Verify(query.Get("id"), query.Get("expires"), query.Get("sig")); !errors.Is(err, ErrInvalidLink) {
+		t.Fatalf("expected another key refused, got %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	if err := signer.Verify(query.Get("id"), query.Get("expires"), query.Get("sig")); !errors.Is(err, ErrLinkExpired) {
+		t.Fatalf("expected expired link, got %v", err)
+	}
+}
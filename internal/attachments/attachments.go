@@ -0,0 +1,336 @@
+// Package attachments saves files received by connectors into a workspace and
+// extracts their text into a markdown sidecar, so PDFs, Word documents and
+// images are indexed and opened like any other workspace document.
+package attachments
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultMaxBytes bounds a single attachment download.
+const DefaultMaxBytes = 20 << 20
+
+// SidecarExt is appended to the saved file name to form the markdown file
+// that carries the extracted text.
+const SidecarExt = ".md"
+
+var ErrUnsupported = errors.New("unsupported attachment type")
+
+type Kind string
+
+const (
+	KindMarkdown Kind = "markdown"
+	KindText     Kind = "text"
+	KindPDF      Kind = "pdf"
+	KindDOCX     Kind = "docx"
+	KindImage    Kind = "image"
+)
+
+type Config struct {
+	PDFToTextBinary string
+	OCRBinary       string
+	MaxBytes        int64
+	Timeout         time.Duration
+}
+
+// Input describes one attachment as received by a connector.
+type Input struct {
+	WorkspaceDir string
+	Connector    string
+	ChannelID    string
+	MessageID    string
+	Filename     string
+	ContentType  string
+	Content      []byte
+}
+
+// Result reports where an attachment was saved. IndexPath is the markdown
+// file qmd indexes; for markdown attachments it is the saved file itself.
+type Result struct {
+	Path      string
+	IndexPath string
+	Kind      Kind
+	Extracted bool
+	Note      string
+}
+
+type runFunc func(ctx context.Context, binary string, args ...string) ([]byte, error)
+
+type Ingestor struct {
+	cfg Config
+	run runFunc
+}
+
+func New(cfg Config) *Ingestor {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultMaxBytes
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60 * time.Second
+	}
+	cfg.PDFToTextBinary = strings.TrimSpace(cfg.PDFToTextBinary)
+	cfg.OCRBinary = strings.TrimSpace(cfg.OCRBinary)
+	return &Ingestor{cfg: cfg, run: runCommand}
+}
+
+// MaxBytes is the largest attachment connectors should download.
+func (i *Ingestor) MaxBytes() int64 {
+	return i.cfg.MaxBytes
+}
+
+// Detect classifies an attachment by extension, falling back to its content
+// type. ok is false for files the pipeline does not ingest.
+func Detect(filename, contentType string) (Kind, bool) {
+	switch strings.ToLower(filepath.Ext(strings.TrimSpace(filename))) {
+	case ".md", ".markdown":
+		return KindMarkdown, true
+	case ".txt", ".csv", ".log":
+		return KindText, true
+	case ".pdf":
+		return KindPDF, true
+	case ".docx":
+		return KindDOCX, true
+	case ".png", ".jpg", ".jpeg", ".webp", ".gif", ".tif", ".tiff", ".bmp":
+		return KindImage, true
+	}
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	if index := strings.Index(contentType, ";"); index >= 0 {
+		contentType = strings.TrimSpace(contentType[:index])
+	}
+	switch {
+	case contentType == "text/markdown" || contentType == "text/x-markdown":
+		return KindMarkdown, true
+	case contentType == "text/plain" || contentType == "text/csv":
+		return KindText, true
+	case contentType == "application/pdf":
+		return KindPDF, true
+	case contentType == "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return KindDOCX, true
+	case strings.HasPrefix(contentType, "image/"):
+		return KindImage, true
+	}
+	return "", false
+}
+
+// Save writes the attachment to inbox/<connector>/<channel>/<message>-<name>
+// under the workspace and, for anything other than markdown, extracts its
+// text into a sidecar next to it. A file whose text cannot be extracted is
+// still saved; Result.Note says why it is not searchable.
+func (i *Ingestor) Save(ctx context.Context, input Input) (Result, error) {
+	filename := SanitizeFilename(input.Filename)
+	kind, ok := Detect(filename, input.ContentType)
+	if !ok {
+		return Result{}, ErrUnsupported
+	}
+	if int64(len(input.Content)) > i.cfg.MaxBytes {
+		return Result{}, fmt.Errorf("attachment too large")
+	}
+	relativeDir := filepath.Join("inbox", input.Connector, input.ChannelID)
+	targetDir := filepath.Join(input.WorkspaceDir, relativeDir)
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return Result{}, err
+	}
+	targetName := filename
+	if strings.TrimSpace(input.MessageID) != "" {
+		targetName = input.MessageID + "-" + filename
+	}
+	targetPath := filepath.Join(targetDir, targetName)
+	if err := os.WriteFile(targetPath, input.Content, 0o644); err != nil {
+		return Result{}, err
+	}
+	result := Result{
+		Path: filepath.ToSlash(filepath.Join(relativeDir, targetName)),
+		Kind: kind,
+	}
+	if kind == KindMarkdown {
+		result.IndexPath = result.Path
+		result.Extracted = true
+		return result, nil
+	}
+
+	text, err := i.extract(ctx, kind, targetPath, input.Content)
+	if err != nil {
+		result.Note = err.Error()
+		return result, nil
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		result.Note = "no text found"
+		return result, nil
+	}
+	sidecar := renderSidecar(filename, result.Path, kind, input.Connector, text)
+	if err := os.WriteFile(targetPath+SidecarExt, []byte(sidecar), 0o644); err != nil {
+		return Result{}, err
+	}
+	result.IndexPath = result.Path + SidecarExt
+	result.Extracted = true
+	return result, nil
+}
+
+func (i *Ingestor) extract(ctx context.Context, kind Kind, path string, content []byte) (string, error) {
+	switch kind {
+	case KindText:
+		return string(content), nil
+	case KindDOCX:
+		return extractDOCX(content)
+	case KindPDF:
+		return i.runExtractor(ctx, i.cfg.PDFToTextBinary, "-layout", "-q", path, "-")
+	case KindImage:
+		return i.runExtractor(ctx, i.cfg.OCRBinary, path, "stdout")
+	}
+	return "", ErrUnsupported
+}
+
+func (i *Ingestor) runExtractor(ctx context.Context, binary string, args ...string) (string, error) {
+	if binary == "" {
+		return "", fmt.Errorf("text extraction is disabled")
+	}
+	runCtx, cancel := context.WithTimeout(ctx, i.cfg.Timeout)
+	defer cancel()
+	output, err := i.run(runCtx, binary, args...)
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", fmt.Errorf("%s is not installed", filepath.Base(binary))
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", filepath.Base(binary), err)
+	}
+	return string(output), nil
+}
+
+func runCommand(ctx context.Context, binary string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, binary, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// extractDOCX reads the paragraphs of word/document.xml; it needs no external
+// tools because a .docx file is a zip of XML parts.
+func extractDOCX(content []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", fmt.Errorf("read docx: %w", err)
+	}
+	for _, file := range archive.File {
+		if file.Name != "word/document.xml" {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return "", fmt.Errorf("read docx: %w", err)
+		}
+		defer reader.Close()
+		return docxText(reader)
+	}
+	return "", fmt.Errorf("read docx: word/document.xml is missing")
+}
+
+func docxText(reader io.Reader) (string, error) {
+	decoder := xml.NewDecoder(reader)
+	var builder strings.Builder
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return builder.String(), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("read docx: %w", err)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				builder.WriteString("\t")
+			case "br":
+				builder.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				builder.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				builder.Write(element)
+			}
+		}
+	}
+}
+
+func renderSidecar(filename, sourcePath string, kind Kind, connector, text string) string {
+	lines := []string{
+		"# Attachment: " + filename,
+		"",
+		"- source: `" + sourcePath + "`",
+		"- type: " + string(kind),
+	}
+	if strings.TrimSpace(connector) != "" {
+		lines = append(lines, "- connector: "+connector)
+	}
+	lines = append(lines, "", text, "")
+	return strings.Join(lines, "\n")
+}
+
+var filenameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// SanitizeFilename keeps the base name of an uploaded file safe to write.
+func SanitizeFilename(input string) string {
+	base := strings.TrimSpace(filepath.Base(input))
+	base = filenameSanitizer.ReplaceAllString(base, "-")
+	base = strings.Trim(base, "-.")
+	if base == "" {
+		return "attachment"
+	}
+	return base
+}
+
+// Summary is the connector reply for a batch of saved attachments.
+func Summary(results []Result) string {
+	if len(results) == 0 {
+		return ""
+	}
+	if len(results) == 1 {
+		return "Attachment saved: " + describe(results[0])
+	}
+	lines := []string{fmt.Sprintf("Saved %d attachments:", len(results))}
+	for _, result := range results {
+		lines = append(lines, "- "+describe(result))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describe(result Result) string {
+	line := "`" + result.Path + "`"
+	switch {
+	case result.Kind == KindMarkdown:
+	case result.Extracted:
+		line += " (text extracted for search)"
+	case result.Note != "":
+		line += " (not searchable: " + result.Note + ")"
+	}
+	return line
+}
@@ -0,0 +1,125 @@
+package attachments
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		filename    string
+		contentType string
+		want        Kind
+		ok          bool
+	}{
+		{"notes.md", "", KindMarkdown, true},
+		{"report.PDF", "", KindPDF, true},
+		{"spec.docx", "", KindDOCX, true},
+		{"photo", "image/jpeg", KindImage, true},
+		{"blob", "text/plain; charset=utf-8", KindText, true},
+		{"archive.zip", "application/zip", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := Detect(tc.filename, tc.contentType)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("Detect(%q, %q) = %q, %v; want %q, %v", tc.filename, tc.contentType, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestSaveExtractsDOCXIntoSidecar(t *testing.T) {
+	workspaceDir := t.TempDir()
+	ingestor := New(Config{})
+	result, err := ingestor.Save(context.Background(), Input{
+		WorkspaceDir: workspaceDir,
+		Connector:    "telegram",
+		ChannelID:    "42",
+		MessageID:    "7",
+		Filename:     "Release Plan.docx",
+		Content:      docxFixture(t, "Release checklist", "Freeze on Friday"),
+	})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if result.Path != "inbox/telegram/42/7-Release-Plan.docx" || result.IndexPath != result.Path+".md" || !result.Extracted {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	sidecar, err := os.ReadFile(filepath.Join(workspaceDir, filepath.FromSlash(result.IndexPath)))
+	if err != nil {
+		t.Fatalf("read sidecar: %v", err)
+	}
+	for _, want := range []string{"# Attachment: Release-Plan.docx", "- source: `inbox/telegram/42/7-Release-Plan.docx`", "Release checklist\nFreeze on Friday"} {
+		if !strings.Contains(string(sidecar), want) {
+			t.Fatalf("expected sidecar to contain %q, got:\n%s", want, sidecar)
+		}
+	}
+	if summary := Summary([]Result{result}); !strings.Contains(summary, "text extracted for search") {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+}
+
+func TestSaveRunsExtractorsAndKeepsFilesWhenTheyAreMissing(t *testing.T) {
+	workspaceDir := t.TempDir()
+	ingestor := New(Config{PDFToTextBinary: "pdftotext", OCRBinary: "tesseract"})
+	calls := []string{}
+	ingestor.run = func(ctx context.Context, binary string, args ...string) ([]byte, error) {
+		calls = append(calls, binary+" "+strings.Join(args, " "))
+		if binary == "tesseract" {
+			return nil, exec.ErrNotFound
+		}
+		return []byte("Quarterly revenue grew.\n"), nil
+	}
+
+	pdf, err := ingestor.Save(context.Background(), Input{WorkspaceDir: workspaceDir, Connector: "discord", ChannelID: "c1", MessageID: "m1", Filename: "q3.pdf", Content: []byte("%PDF")})
+	if err != nil || !pdf.Extracted {
+		t.Fatalf("expected pdf text to be extracted, got %+v (%v)", pdf, err)
+	}
+	if !strings.HasPrefix(calls[0], "pdftotext -layout -q ") || !strings.HasSuffix(calls[0], " -") {
+		t.Fatalf("unexpected pdftotext call %q", calls[0])
+	}
+
+	image, err := ingestor.Save(context.Background(), Input{WorkspaceDir: workspaceDir, Connector: "discord", ChannelID: "c1", MessageID: "m2", Filename: "board.png", Content: []byte("png")})
+	if err != nil {
+		t.Fatalf("save image: %v", err)
+	}
+	if image.Extracted || image.IndexPath != "" || image.Note != "tesseract is not installed" {
+		t.Fatalf("expected image to be saved without text, got %+v", image)
+	}
+	if _, err := os.Stat(filepath.Join(workspaceDir, filepath.FromSlash(image.Path))); err != nil {
+		t.Fatalf("expected original image to be kept: %v", err)
+	}
+	summary := Summary([]Result{pdf, image})
+	if !strings.HasPrefix(summary, "Saved 2 attachments:") || !strings.Contains(summary, "not searchable: tesseract is not installed") {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+
+	if _, err := ingestor.Save(context.Background(), Input{WorkspaceDir: workspaceDir, Filename: "bundle.zip", Content: []byte("zip")}); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
+
+func docxFixture(t *testing.T, paragraphs ...string) []byte {
+	t.Helper()
+	var body strings.Builder
+	for _, paragraph := range paragraphs {
+		body.WriteString(`<w:p><w:r><w:t>` + paragraph + `</w:t></w:r></w:p>`)
+	}
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	file, err := archive.Create("word/document.xml")
+	if err != nil {
+		t.Fatalf("create docx part: %v", err)
+	}
+	_, _ = file.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + body.String() + `</w:body></w:document>`))
+	if err := archive.Close(); err != nil {
+		t.Fatalf("close docx: %v", err)
+	}
+	return buffer.Bytes()
+}
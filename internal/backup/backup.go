@@ -0,0 +1,425 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	manifestName    = "manifest.json"
+	manifestVersion = 1
+
+	dbArchivePath        = "db/meta.sqlite"
+	workspacesArchiveDir = "workspaces"
+	skillsArchiveDir     = "skills"
+)
+
+var (
+	ErrChecksumMismatch = errors.New("backup checksum mismatch")
+	ErrTargetNotEmpty   = errors.New("restore target already contains data")
+)
+
+// Snapshotter produces a consistent copy of the runtime database.
+type Snapshotter interface {
+	SnapshotTo(ctx context.Context, destPath string) error
+}
+
+type Manifest struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Files     []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type CreateOptions struct {
+	Output        string
+	Database      Snapshotter
+	WorkspaceRoot string
+	SkillsRoot    string
+}
+
+type RestoreOptions struct {
+	Archive       string
+	DBPath        string
+	WorkspaceRoot string
+	SkillsRoot    string
+	Force         bool
+}
+
+// Create writes a gzip tar archive with a database snapshot, the workspace
+// tree, and global skills. The manifest is written last and lists a SHA-256
+// checksum for every file.
+func Create(ctx context.Context, opts CreateOptions) (Manifest, error) {
+	output := strings.TrimSpace(opts.Output)
+	if output == "" {
+		return Manifest{}, fmt.Errorf("backup output path is required")
+	}
+	if opts.Database == nil {
+		return Manifest{}, fmt.Errorf("backup database is required")
+	}
+	stagingDir, err := os.MkdirTemp("", "agent-runtime-backup-*")
+	if err != nil {
+		return Manifest{}, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+	snapshotPath := filepath.Join(stagingDir, "meta.sqlite")
+	if err := opts.Database.SnapshotTo(ctx, snapshotPath); err != nil {
+		return Manifest{}, err
+	}
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("create backup archive: %w", err)
+	}
+	writer := &archiveWriter{gzip: gzip.NewWriter(file)}
+	writer.tar = tar.NewWriter(writer.gzip)
+	manifest := Manifest{Version: manifestVersion, CreatedAt: time.Now().UTC()}
+
+	err = writer.addFile(snapshotPath, dbArchivePath, &manifest)
+	if err == nil {
+		err = writer.addTree(ctx, opts.WorkspaceRoot, workspacesArchiveDir, &manifest)
+	}
+	if err == nil {
+		err = writer.addTree(ctx, opts.SkillsRoot, skillsArchiveDir, &manifest)
+	}
+	if err == nil {
+		err = writer.addManifest(manifest)
+	}
+	if closeErr := writer.close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+// Restore verifies every checksum in the archive before writing anything to
+// the target paths. Existing data is only overwritten when Force is set.
+func Restore(ctx context.Context, opts RestoreOptions) (Manifest, error) {
+	if strings.TrimSpace(opts.DBPath) == "" || strings.TrimSpace(opts.WorkspaceRoot) == "" {
+		return Manifest{}, fmt.Errorf("restore db path and workspace root are required")
+	}
+	stagingDir, err := os.MkdirTemp("", "agent-runtime-restore-*")
+	if err != nil {
+		return Manifest{}, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	manifest, err := extractArchive(ctx, opts.Archive, stagingDir)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := verifyStaged(stagingDir, manifest); err != nil {
+		return Manifest{}, err
+	}
+	if !opts.Force {
+		if _, err := os.Stat(opts.DBPath); err == nil {
+			return Manifest{}, fmt.Errorf("%w: %s", ErrTargetNotEmpty, opts.DBPath)
+		}
+		if !dirEmpty(opts.WorkspaceRoot) {
+			return Manifest{}, fmt.Errorf("%w: %s", ErrTargetNotEmpty, opts.WorkspaceRoot)
+		}
+	}
+
+	for _, entry := range manifest.Files {
+		if err := ctx.Err(); err != nil {
+			return Manifest{}, err
+		}
+		target, ok := restoreTarget(opts, entry.Path)
+		if !ok {
+			continue
+		}
+		if entry.Path == dbArchivePath {
+			// Stale WAL files would be replayed over the restored database.
+			_ = os.Remove(target + "-wal")
+			_ = os.Remove(target + "-shm")
+		}
+		if err := copyFile(filepath.Join(stagingDir, filepath.FromSlash(entry.Path)), target); err != nil {
+			return Manifest{}, err
+		}
+	}
+	return manifest, nil
+}
+
+func restoreTarget(opts RestoreOptions, archivePath string) (string, bool) {
+	if archivePath == dbArchivePath {
+		return opts.DBPath, true
+	}
+	if rel, ok := strings.CutPrefix(archivePath, workspacesArchiveDir+"/"); ok {
+		return filepath.Join(opts.WorkspaceRoot, filepath.FromSlash(rel)), true
+	}
+	if rel, ok := strings.CutPrefix(archivePath, skillsArchiveDir+"/"); ok && strings.TrimSpace(opts.SkillsRoot) != "" {
+		return filepath.Join(opts.SkillsRoot, filepath.FromSlash(rel)), true
+	}
+	return "", false
+}
+
+type archiveWriter struct {
+	gzip *gzip.Writer
+	tar  *tar.Writer
+}
+
+func (w *archiveWriter) addTree(ctx context.Context, root, prefix string, manifest *Manifest) error {
+	root = strings.TrimSpace(root)
+	if root == "" {
+		return nil
+	}
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return filepath.WalkDir(root, func(current string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Only regular files are archived; symlinks could point outside the tree.
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, current)
+		if err != nil {
+			return err
+		}
+		return w.addFile(current, path.Join(prefix, filepath.ToSlash(rel)), manifest)
+	})
+}
+
+func (w *archiveWriter) addFile(sourcePath, archivePath string, manifest *Manifest) error {
+	file, err := os.Open(sourcePath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", sourcePath, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", sourcePath, err)
+	}
+	header := &tar.Header{
+		Name:    archivePath,
+		Mode:    int64(info.Mode().Perm()),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err := w.tar.WriteHeader(header); err != nil {
+		return fmt.Errorf("write archive header: %w", err)
+	}
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(w.tar, hash), file)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", archivePath, err)
+	}
+	manifest.Files = append(manifest.Files, ManifestFile{
+		Path:   archivePath,
+		Size:   written,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	})
+	return nil
+}
+
+func (w *archiveWriter) addManifest(manifest Manifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	header := &tar.Header{Name: manifestName, Mode: 0o644, Size: int64(len(content)), ModTime: manifest.CreatedAt}
+	if err := w.tar.WriteHeader(header); err != nil {
+		return fmt.Errorf("write manifest header: %w", err)
+	}
+	if _, err := w.tar.Write(content); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+func (w *archiveWriter) close() error {
+	if err := w.tar.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+	if err := w.gzip.Close(); err != nil {
+		return fmt.Errorf("close archive compression: %w", err)
+	}
+	return nil
+}
+
+func extractArchive(ctx context.Context, archivePath, destDir string) (Manifest, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("open backup archive: %w", err)
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read backup archive: %w", err)
+	}
+	defer gzipReader.Close()
+
+	var manifest Manifest
+	foundManifest := false
+	reader := tar.NewReader(gzipReader)
+	for {
+		if err := ctx.Err(); err != nil {
+			return Manifest{}, err
+		}
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Manifest{}, fmt.Errorf("read backup archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := cleanArchivePath(header.Name)
+		if err != nil {
+			return Manifest{}, err
+		}
+		if name == manifestName {
+			if err := json.NewDecoder(reader).Decode(&manifest); err != nil {
+				return Manifest{}, fmt.Errorf("decode manifest: %w", err)
+			}
+			foundManifest = true
+			continue
+		}
+		target := filepath.Join(destDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return Manifest{}, fmt.Errorf("create staging dir: %w", err)
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("stage %s: %w", name, err)
+		}
+		_, copyErr := io.Copy(out, reader)
+		closeErr := out.Close()
+		if copyErr != nil {
+			return Manifest{}, fmt.Errorf("stage %s: %w", name, copyErr)
+		}
+		if closeErr != nil {
+			return Manifest{}, fmt.Errorf("stage %s: %w", name, closeErr)
+		}
+	}
+	if !foundManifest {
+		return Manifest{}, fmt.Errorf("backup archive has no %s", manifestName)
+	}
+	if manifest.Version != manifestVersion {
+		return Manifest{}, fmt.Errorf("unsupported backup manifest version %d", manifest.Version)
+	}
+	return manifest, nil
+}
+
+func verifyStaged(stagingDir string, manifest Manifest) error {
+	listed := map[string]bool{}
+	for _, entry := range manifest.Files {
+		name, err := cleanArchivePath(entry.Path)
+		if err != nil {
+			return err
+		}
+		listed[name] = true
+		sum, size, err := hashFile(filepath.Join(stagingDir, filepath.FromSlash(name)))
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrChecksumMismatch, name, err)
+		}
+		if size != entry.Size || sum != entry.SHA256 {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, name)
+		}
+	}
+	if !listed[dbArchivePath] {
+		return fmt.Errorf("backup archive has no database snapshot")
+	}
+	// Files that are in the archive but not in the manifest were never checksummed.
+	unlisted := []string{}
+	_ = filepath.WalkDir(stagingDir, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		rel, relErr := filepath.Rel(stagingDir, current)
+		if relErr == nil && !listed[filepath.ToSlash(rel)] {
+			unlisted = append(unlisted, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if len(unlisted) > 0 {
+		sort.Strings(unlisted)
+		return fmt.Errorf("%w: unlisted file %s", ErrChecksumMismatch, unlisted[0])
+	}
+	return nil
+}
+
+func cleanArchivePath(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimSpace(name))
+	if cleaned == "." || path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("backup archive contains unsafe path %q", name)
+	}
+	return cleaned, nil
+}
+
+func hashFile(filePath string) (string, int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+func copyFile(source, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("create directory for %s: %w", target, err)
+	}
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("open staged file: %w", err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("restore %s: %w", target, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("restore %s: %w", target, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("restore %s: %w", target, err)
+	}
+	return nil
+}
+
+func dirEmpty(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return true
+	}
+	return len(entries) == 0
+}
@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestCreateAndRestoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := t.TempDir()
+	sqlStore, err := store.New(filepath.Join(source, "meta.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer sqlStore.Close()
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	contextRecord, err := sqlStore.EnsureContextForExternalChannel(ctx, "discord", "chan-1", "general")
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	workspaceRoot := filepath.Join(source, "workspaces")
+	skillsRoot := filepath.Join(source, "skills")
+	writeTestFile(t, filepath.Join(workspaceRoot, "ws-1", "logs", "chats", "discord", "chan-1.md"), "hello")
+	writeTestFile(t, filepath.Join(skillsRoot, "triage", "SKILL.md"), "# Triage")
+
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	manifest, err := Create(ctx, CreateOptions{Output: archive, Database: sqlStore, WorkspaceRoot: workspaceRoot, SkillsRoot: skillsRoot})
+	if err != nil {
+		t.Fatalf("create backup: %v", err)
+	}
+	if len(manifest.Files) != 3 {
+		t.Fatalf("expected 3 files in manifest, got %+v", manifest.Files)
+	}
+
+	target := t.TempDir()
+	restoreOpts := RestoreOptions{
+		Archive:       archive,
+		DBPath:        filepath.Join(target, "agent-runtime", "meta.sqlite"),
+		WorkspaceRoot: filepath.Join(target, "workspaces"),
+		SkillsRoot:    filepath.Join(target, "skills"),
+	}
+	if _, err := Restore(ctx, restoreOpts); err != nil {
+		t.Fatalf("restore backup: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(restoreOpts.WorkspaceRoot, "ws-1", "logs", "chats", "discord", "chan-1.md"))
+	if err != nil || string(content) != "hello" {
+		t.Fatalf("expected restored chat log, got %q %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(restoreOpts.SkillsRoot, "triage", "SKILL.md")); err != nil {
+		t.Fatalf("expected restored skill: %v", err)
+	}
+	restored, err := store.New(restoreOpts.DBPath)
+	if err != nil {
+		t.Fatalf("open restored store: %v", err)
+	}
+	defer restored.Close()
+	lookup, err := restored.LookupContextPolicy(ctx, contextRecord.ID)
+	if err != nil || lookup.ContextID != contextRecord.ID {
+		t.Fatalf("expected restored context %s, got %+v %v", contextRecord.ID, lookup, err)
+	}
+
+	if _, err := Restore(ctx, restoreOpts); !errors.Is(err, ErrTargetNotEmpty) {
+		t.Fatalf("expected ErrTargetNotEmpty without force, got %v", err)
+	}
+	restoreOpts.Force = true
+	if _, err := Restore(ctx, restoreOpts); err != nil {
+		t.Fatalf("forced restore: %v", err)
+	}
+}
+
+func TestRestoreRejectsTamperedArchive(t *testing.T) {
+	ctx := context.Background()
+	source := t.TempDir()
+	sqlStore, err := store.New(filepath.Join(source, "meta.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer sqlStore.Close()
+	workspaceRoot := filepath.Join(source, "workspaces")
+	writeTestFile(t, filepath.Join(workspaceRoot, "ws-1", "notes.md"), "original")
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if _, err := Create(ctx, CreateOptions{Output: archive, Database: sqlStore, WorkspaceRoot: workspaceRoot}); err != nil {
+		t.Fatalf("create backup: %v", err)
+	}
+	rewriteArchiveEntry(t, archive, "workspaces/ws-1/notes.md", "tampered")
+
+	target := t.TempDir()
+	_, err = Restore(ctx, RestoreOptions{
+		Archive:       archive,
+		DBPath:        filepath.Join(target, "meta.sqlite"),
+		WorkspaceRoot: filepath.Join(target, "workspaces"),
+	})
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(target, "meta.sqlite")); !os.IsNotExist(statErr) {
+		t.Fatal("expected nothing restored when verification fails")
+	}
+}
+
+func writeTestFile(t *testing.T, filePath, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
+
+func rewriteArchiveEntry(t *testing.T, archive, name, content string) {
+	t.Helper()
+	raw, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("open gzip: %v", err)
+	}
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read entry: %v", err)
+		}
+		body, _ := io.ReadAll(reader)
+		if header.Name == name {
+			body = []byte(content)
+			header.Size = int64(len(body))
+		}
+		_ = tarWriter.WriteHeader(header)
+		_, _ = tarWriter.Write(body)
+	}
+	_ = tarWriter.Close()
+	_ = gzipWriter.Close()
+	if err := os.WriteFile(archive, buffer.Bytes(), 0o600); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+}
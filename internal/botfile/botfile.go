@@ -0,0 +1,474 @@
+// Package botfile reads the declarative botfile.yaml a workspace can keep at
+// its root to describe the agent's persona, tools, policies, approval rules,
+// objectives and FAQ entries, and reports what changed between two versions of
+// it.
+package botfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/approvalpolicy"
+	"github.com/dwizi/agent-runtime/internal/egress"
+	"github.com/dwizi/agent-runtime/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the botfile's name at the root of a workspace.
+const FileName = "botfile.yaml"
+
+// CurrentVersion is the newest botfile schema this runtime understands.
+const CurrentVersion = 1
+
+// ErrInvalid is wrapped by every parse and validation error.
+var ErrInvalid = errors.New("invalid botfile")
+
+var objectiveKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+type File struct {
+	Version    int           `yaml:"version"`
+	Persona    string        `yaml:"persona"`
+	Tools      Tools         `yaml:"tools"`
+	Policies   Policies      `yaml:"policies"`
+	Approvals  *Approvals    `yaml:"approvals"`
+	Privacy    *Privacy      `yaml:"privacy"`
+	Network    *egress.Rules `yaml:"network"`
+	Objectives []Objective   `yaml:"objectives"`
+	FAQ        []FAQEntry    `yaml:"faq"`
+}
+
+// Tools restricts what the agent may call in the workspace. Empty lists
+// leave every registered tool available.
+type Tools struct {
+	Allow   []string `yaml:"allow"`
+	Classes []string `yaml:"classes"`
+}
+
+// Policies override the agent's per-turn limits; zero keeps the runtime
+// default.
+type Policies struct {
+	MaxLoopSteps              int     `yaml:"max_loop_steps"`
+	MaxToolCallsPerTurn       int     `yaml:"max_tool_calls_per_turn"`
+	MaxTurnSeconds            int     `yaml:"max_turn_seconds"`
+	MaxAutonomousTasksPerHour int     `yaml:"max_autonomous_tasks_per_hour"`
+	MaxAutonomousTasksPerDay  int     `yaml:"max_autonomous_tasks_per_day"`
+	MinFinalConfidence        float64 `yaml:"min_final_confidence"`
+}
+
+// Approvals replace the default auto-approve policy, under which admins and
+// task workers run any action, with the workspace's own rules. An empty
+// auto_approve list sends every action to an admin.
+type Approvals struct {
+	AutoApprove []approvalpolicy.Rule `yaml:"auto_approve"`
+}
+
+// ApprovalPolicy returns the auto-approve rules the file declares; ok is
+// false when it has no approvals section.
+func (f File) ApprovalPolicy() (approvalpolicy.Policy, bool) {
+	if f.Approvals == nil {
+		return approvalpolicy.Policy{}, false
+	}
+	return approvalpolicy.Policy{Rules: f.Approvals.AutoApprove}, true
+}
+
+// Privacy overrides the runtime's redaction default for the workspace.
+type Privacy struct {
+	// Redact replaces emails, phone numbers, API keys and card numbers with
+	// tokens in model calls and chat logs.
+	Redact bool `yaml:"redact"`
+}
+
+// Redaction returns the redaction setting the file declares; ok is false
+// when it has no privacy section.
+func (f File) Redaction() (enabled, ok bool) {
+	if f.Privacy == nil {
+		return false, false
+	}
+	return f.Privacy.Redact, true
+}
+
+// EgressRules returns the network rules the file declares; ok is false when
+// it has no network section.
+func (f File) EgressRules() (egress.Rules, bool) {
+	if f.Network == nil {
+		return egress.Rules{}, false
+	}
+	return *f.Network, true
+}
+
+// Objective is a scheduled or event-driven objective owned by the botfile.
+// Key identifies it across versions, so renaming the title updates the
+// existing objective instead of replacing it.
+type Objective struct {
+	Key      string `yaml:"key"`
+	Title    string `yaml:"title"`
+	Prompt   string `yaml:"prompt"`
+	Cron     string `yaml:"cron"`
+	Timezone string `yaml:"timezone"`
+	Event    string `yaml:"event"`
+	Active   *bool  `yaml:"active"`
+}
+
+// DriftReport is the outcome of comparing a workspace's applied botfile with
+// its live state. Fixed is set when the drift was corrected in the same
+// check.
+type DriftReport struct {
+	WorkspaceID string
+	Drift       []string
+	Fixed       bool
+	CheckedAt   time.Time
+}
+
+type FAQEntry struct {
+	Question string `yaml:"question"`
+	Answer   string `yaml:"answer"`
+}
+
+// IsActive reports whether the objective should run; objectives are active
+// unless they say otherwise.
+func (o Objective) IsActive() bool {
+	return o.Active == nil || *o.Active
+}
+
+// Parse decodes and validates a botfile. Unknown fields are rejected so a
+// typo does not silently fall back to a default.
+func Parse(data []byte) (File, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return File{}, fmt.Errorf("%w: file is empty", ErrInvalid)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var file File
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return File{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	file.normalize()
+	if err := file.Validate(); err != nil {
+		return File{}, err
+	}
+	return file, nil
+}
+
+func (f *File) normalize() {
+	f.Persona = strings.TrimSpace(f.Persona)
+	f.Tools.Allow = trimList(f.Tools.Allow)
+	f.Tools.Classes = trimList(f.Tools.Classes)
+	for index := range f.Tools.Classes {
+		f.Tools.Classes[index] = strings.ToLower(f.Tools.Classes[index])
+	}
+	if f.Approvals != nil {
+		for index := range f.Approvals.AutoApprove {
+			f.Approvals.AutoApprove[index].Normalize()
+		}
+	}
+	if f.Network != nil {
+		f.Network.Normalize()
+	}
+	for index := range f.Objectives {
+		objective := &f.Objectives[index]
+		objective.Key = strings.ToLower(strings.TrimSpace(objective.Key))
+		objective.Title = strings.TrimSpace(objective.Title)
+		objective.Prompt = strings.TrimSpace(objective.Prompt)
+		objective.Cron = strings.Join(strings.Fields(objective.Cron), " ")
+		objective.Timezone = strings.TrimSpace(objective.Timezone)
+		objective.Event = strings.ToLower(strings.TrimSpace(objective.Event))
+		if objective.Title == "" {
+			objective.Title = objective.Key
+		}
+	}
+	for index := range f.FAQ {
+		f.FAQ[index].Question = strings.TrimSpace(f.FAQ[index].Question)
+		f.FAQ[index].Answer = strings.TrimSpace(f.FAQ[index].Answer)
+	}
+}
+
+// Validate reports every problem in the file at once, so one edit can fix
+// them all.
+func (f File) Validate() error {
+	problems := []string{}
+	switch {
+	case f.Version == 0:
+		problems = append(problems, "version is required")
+	case f.Version < 0 || f.Version > CurrentVersion:
+		problems = append(problems, fmt.Sprintf("version %d is not supported (latest is %d)", f.Version, CurrentVersion))
+	}
+	knownClasses := map[string]bool{}
+	for _, class := range []tools.ToolClass{
+		tools.ToolClassGeneral,
+		tools.ToolClassKnowledge,
+		tools.ToolClassTasking,
+		tools.ToolClassModeration,
+		tools.ToolClassObjective,
+		tools.ToolClassDrafting,
+		tools.ToolClassSensitive,
+	} {
+		knownClasses[string(class)] = true
+	}
+	for _, class := range f.Tools.Classes {
+		if !knownClasses[class] {
+			problems = append(problems, fmt.Sprintf("tools.classes: unknown class %q", class))
+		}
+	}
+	policies := f.Policies
+	for name, value := range map[string]int{
+		"max_loop_steps":                policies.MaxLoopSteps,
+		"max_tool_calls_per_turn":       policies.MaxToolCallsPerTurn,
+		"max_turn_seconds":              policies.MaxTurnSeconds,
+		"max_autonomous_tasks_per_hour": policies.MaxAutonomousTasksPerHour,
+		"max_autonomous_tasks_per_day":  policies.MaxAutonomousTasksPerDay,
+	} {
+		if value < 0 {
+			problems = append(problems, fmt.Sprintf("policies.%s must not be negative", name))
+		}
+	}
+	if policies.MinFinalConfidence < 0 || policies.MinFinalConfidence > 1 {
+		problems = append(problems, "policies.min_final_confidence must be between 0 and 1")
+	}
+	if f.Approvals != nil {
+		for index, rule := range f.Approvals.AutoApprove {
+			label := fmt.Sprintf("approvals.auto_approve[%d]", index)
+			if rule.Name != "" {
+				label = fmt.Sprintf("approvals.auto_approve[%s]", rule.Name)
+			}
+			for _, problem := range rule.Validate() {
+				problems = append(problems, label+": "+problem)
+			}
+		}
+	}
+	if f.Network != nil {
+		for _, problem := range f.Network.Validate() {
+			problems = append(problems, "network: "+problem)
+		}
+	}
+	seenKeys := map[string]bool{}
+	for index, objective := range f.Objectives {
+		label := fmt.Sprintf("objectives[%d]", index)
+		if objective.Key != "" {
+			label = fmt.Sprintf("objectives[%s]", objective.Key)
+		}
+		switch {
+		case objective.Key == "":
+			problems = append(problems, label+": key is required")
+		case !objectiveKeyPattern.MatchString(objective.Key):
+			problems = append(problems, label+": key may only use lowercase letters, digits, - and _")
+		case seenKeys[objective.Key]:
+			problems = append(problems, label+": key is used more than once")
+		}
+		seenKeys[objective.Key] = true
+		if objective.Prompt == "" {
+			problems = append(problems, label+": prompt is required")
+		}
+		switch {
+		case objective.Cron == "" && objective.Event == "":
+			problems = append(problems, label+": set either cron or event")
+		case objective.Cron != "" && objective.Event != "":
+			problems = append(problems, label+": cron and event are mutually exclusive")
+		case objective.Cron != "":
+			if _, err := store.ComputeScheduleNextRunForTimezone(objective.Cron, objective.Timezone, time.Now()); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+			}
+		}
+	}
+	for index, entry := range f.FAQ {
+		if entry.Question == "" || entry.Answer == "" {
+			problems = append(problems, fmt.Sprintf("faq[%d]: question and answer are required", index))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("%w: %s", ErrInvalid, strings.Join(problems, "; "))
+}
+
+// Diff lists the changes from previous to next as short lines such as
+// "objectives: added nightly-digest". An empty previous file means nothing
+// was applied before.
+func Diff(previous, next File) []string {
+	changes := []string{}
+	if previous.Version != next.Version {
+		changes = append(changes, fmt.Sprintf("version: %d -> %d", previous.Version, next.Version))
+	}
+	if previous.Persona != next.Persona {
+		switch {
+		case previous.Persona == "":
+			changes = append(changes, "persona: added")
+		case next.Persona == "":
+			changes = append(changes, "persona: removed")
+		default:
+			changes = append(changes, "persona: changed")
+		}
+	}
+	changes = append(changes, diffList("tools.allow", previous.Tools.Allow, next.Tools.Allow)...)
+	changes = append(changes, diffList("tools.classes", previous.Tools.Classes, next.Tools.Classes)...)
+	if previous.Policies != next.Policies {
+		changes = append(changes, diffPolicies(previous.Policies, next.Policies)...)
+	}
+	switch {
+	case previous.Approvals == nil && next.Approvals != nil:
+		changes = append(changes, "approvals: added")
+	case previous.Approvals != nil && next.Approvals == nil:
+		changes = append(changes, "approvals: removed")
+	case previous.Approvals != nil && !reflect.DeepEqual(previous.Approvals, next.Approvals):
+		changes = append(changes, "approvals: auto_approve changed")
+	}
+	switch {
+	case previous.Network == nil && next.Network != nil:
+		changes = append(changes, "network: added")
+	case previous.Network != nil && next.Network == nil:
+		changes = append(changes, "network: removed")
+	case previous.Network != nil && !reflect.DeepEqual(previous.Network, next.Network):
+		changes = append(changes, "network: changed")
+	}
+	previousRedact, previousSet := previous.Redaction()
+	nextRedact, nextSet := next.Redaction()
+	switch {
+	case !previousSet && nextSet:
+		changes = append(changes, fmt.Sprintf("privacy.redact: %t", nextRedact))
+	case previousSet && !nextSet:
+		changes = append(changes, "privacy: removed")
+	case previousRedact != nextRedact:
+		changes = append(changes, fmt.Sprintf("privacy.redact: %t -> %t", previousRedact, nextRedact))
+	}
+
+	before := map[string]Objective{}
+	for _, objective := range previous.Objectives {
+		before[objective.Key] = objective
+	}
+	after := map[string]bool{}
+	for _, objective := range next.Objectives {
+		after[objective.Key] = true
+		old, ok := before[objective.Key]
+		switch {
+		case !ok:
+			changes = append(changes, "objectives: added "+objective.Key)
+		case !sameObjective(old, objective):
+			changes = append(changes, "objectives: changed "+objective.Key)
+		}
+	}
+	for _, objective := range previous.Objectives {
+		if !after[objective.Key] {
+			changes = append(changes, "objectives: removed "+objective.Key)
+		}
+	}
+
+	beforeFAQ := map[string]string{}
+	for _, entry := range previous.FAQ {
+		beforeFAQ[entry.Question] = entry.Answer
+	}
+	afterFAQ := map[string]bool{}
+	for _, entry := range next.FAQ {
+		afterFAQ[entry.Question] = true
+		answer, ok := beforeFAQ[entry.Question]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("faq: added %q", entry.Question))
+		case answer != entry.Answer:
+			changes = append(changes, fmt.Sprintf("faq: changed %q", entry.Question))
+		}
+	}
+	for _, entry := range previous.FAQ {
+		if !afterFAQ[entry.Question] {
+			changes = append(changes, fmt.Sprintf("faq: removed %q", entry.Question))
+		}
+	}
+	return changes
+}
+
+func sameObjective(left, right Objective) bool {
+	return left.Title == right.Title &&
+		left.Prompt == right.Prompt &&
+		left.Cron == right.Cron &&
+		left.Timezone == right.Timezone &&
+		left.Event == right.Event &&
+		left.IsActive() == right.IsActive()
+}
+
+func diffList(field string, previous, next []string) []string {
+	before := map[string]bool{}
+	for _, item := range previous {
+		before[item] = true
+	}
+	after := map[string]bool{}
+	added := []string{}
+	for _, item := range next {
+		after[item] = true
+		if !before[item] {
+			added = append(added, item)
+		}
+	}
+	removed := []string{}
+	for _, item := range previous {
+		if !after[item] {
+			removed = append(removed, item)
+		}
+	}
+	changes := []string{}
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("%s: added %s", field, strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("%s: removed %s", field, strings.Join(removed, ", ")))
+	}
+	return changes
+}
+
+func diffPolicies(previous, next Policies) []string {
+	changes := []string{}
+	add := func(name string, before, after any) {
+		if before != after {
+			changes = append(changes, fmt.Sprintf("policies.%s: %v -> %v", name, before, after))
+		}
+	}
+	add("max_loop_steps", previous.MaxLoopSteps, next.MaxLoopSteps)
+	add("max_tool_calls_per_turn", previous.MaxToolCallsPerTurn, next.MaxToolCallsPerTurn)
+	add("max_turn_seconds", previous.MaxTurnSeconds, next.MaxTurnSeconds)
+	add("max_autonomous_tasks_per_hour", previous.MaxAutonomousTasksPerHour, next.MaxAutonomousTasksPerHour)
+	add("max_autonomous_tasks_per_day", previous.MaxAutonomousTasksPerDay, next.MaxAutonomousTasksPerDay)
+	add("min_final_confidence", previous.MinFinalConfidence, next.MinFinalConfidence)
+	return changes
+}
+
+// RenderFAQ renders the FAQ entries as a markdown document so they are
+// indexed and retrieved like any other workspace knowledge.
+func RenderFAQ(entries []FAQEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("# Frequently Asked Questions\n\n")
+	builder.WriteString("<!-- Generated from " + FileName + "; edit the botfile instead. -->\n")
+	for _, entry := range entries {
+		builder.WriteString("\n## ")
+		builder.WriteString(entry.Question)
+		builder.WriteString("\n\n")
+		builder.WriteString(entry.Answer)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func trimList(items []string) []string {
+	cleaned := make([]string, 0, len(items))
+	seen := map[string]bool{}
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		cleaned = append(cleaned, item)
+	}
+	if len(cleaned) == 0 {
+		return nil
+	}
+	return cleaned
+}
@@ -0,0 +1,248 @@
+package botfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const sampleBotfile = `
+version: 1
+persona: |
+  You are the release desk assistant.
+tools:
+  allow: [search_knowledge_base, create_task]
+  classes: [knowledge, tasking]
+policies:
+  max_tool_calls_per_turn: 4
+  min_final_confidence: 0.5
+objectives:
+  - key: nightly-digest
+    title: Nightly digest
+    prompt: Summarize yesterday's failed deployments.
+    cron: "0 7 * * *"
+    timezone: Europe/Berlin
+  - key: docs-watch
+    prompt: Review changed runbooks.
+    event: markdown.updated
+    active: false
+faq:
+  - question: Who owns releases?
+    answer: The release manager on the rota.
+`
+
+func TestParseReadsAndNormalizesBotfile(t *testing.T) {
+	file, err := Parse([]byte(sampleBotfile))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if file.Persona != "You are the release desk assistant." {
+		t.Fatalf("unexpected persona %q", file.Persona)
+	}
+	if len(file.Tools.Allow) != 2 || file.Policies.MaxToolCallsPerTurn != 4 {
+		t.Fatalf("unexpected tools/policies %+v %+v", file.Tools, file.Policies)
+	}
+	if len(file.Objectives) != 2 || file.Objectives[1].Title != "docs-watch" || file.Objectives[1].IsActive() {
+		t.Fatalf("expected untitled objective to use its key and stay inactive, got %+v", file.Objectives)
+	}
+	if rendered := RenderFAQ(file.FAQ); !strings.Contains(rendered, "## Who owns releases?") {
+		t.Fatalf("unexpected faq markdown %q", rendered)
+	}
+}
+
+func TestParseReportsEveryProblem(t *testing.T) {
+	_, err := Parse([]byte(`
+version: 2
+tools:
+  classes: [wizardry]
+policies:
+  min_final_confidence: 2
+objectives:
+  - key: Bad Key
+    prompt: run
+    cron: "0 7 * * *"
+    event: markdown.updated
+  - key: broken-cron
+    prompt: run
+    cron: "not a cron"
+faq:
+  - question: Orphan question
+`))
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+	for _, want := range []string{
+		"version 2 is not supported",
+		`unknown class "wizardry"`,
+		"min_final_confidence",
+		"key may only use",
+		"mutually exclusive",
+		"objectives[broken-cron]",
+		"faq[0]",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	if _, err := Parse([]byte("version: 1\npersonna: typo\n")); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected unknown field to be rejected, got %v", err)
+	}
+	if _, err := Parse([]byte("  \n")); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected empty file to be rejected, got %v", err)
+	}
+}
+
+func TestDiffListsChanges(t *testing.T) {
+	previous, err := Parse([]byte(sampleBotfile))
+	if err != nil {
+		t.Fatalf("parse previous: %v", err)
+	}
+	next, err := Parse([]byte(`
+version: 1
+persona: You are the on-call assistant.
+tools:
+  allow: [search_knowledge_base, web_search]
+  classes: [knowledge, tasking]
+policies:
+  max_tool_calls_per_turn: 6
+  min_final_confidence: 0.5
+objectives:
+  - key: nightly-digest
+    title: Morning digest
+    prompt: Summarize yesterday's failed deployments.
+    cron: "0 7 * * *"
+    timezone: Europe/Berlin
+faq:
+  - question: Who owns releases?
+    answer: The release manager on the rota.
+`))
+	if err != nil {
+		t.Fatalf("parse next: %v", err)
+	}
+	got := strings.Join(Diff(previous, next), "\n")
+	want := strings.Join([]string{
+		"persona: changed",
+		"tools.allow: added web_search",
+		"tools.allow: removed create_task",
+		"policies.max_tool_calls_per_turn: 4 -> 6",
+		"objectives: changed nightly-digest",
+		"objectives: removed docs-watch",
+	}, "\n")
+	if got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if changes := Diff(next, next); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", changes)
+	}
+	if changes := Diff(File{}, next); changes[0] != "version: 0 -> 1" {
+		t.Fatalf("expected first apply to list everything, got %v", changes)
+	}
+}
+
+func TestParseReadsApprovalRules(t *testing.T) {
+	file, err := Parse([]byte(`
+version: 1
+approvals:
+  auto_approve:
+    - name: docs reads
+      tool: Fetch_URL
+      domains: ["*.example.com"]
+      methods: [get]
+`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	policy, ok := file.ApprovalPolicy()
+	if !ok || len(policy.Rules) != 1 {
+		t.Fatalf("expected one approval rule, got %+v %v", policy, ok)
+	}
+	rule := policy.Rules[0]
+	if rule.Tool != "fetch_url" || rule.Domains[0] != "example.com" || rule.Methods[0] != "GET" {
+		t.Fatalf("expected normalized rule, got %+v", rule)
+	}
+	if _, ok := (File{Version: 1}).ApprovalPolicy(); ok {
+		t.Fatal("expected a file without approvals to keep the default policy")
+	}
+
+	_, err = Parse([]byte(`
+version: 1
+approvals:
+  auto_approve:
+    - name: bad
+      methods: [fetch]
+`))
+	if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), `approvals.auto_approve[bad]: unknown method "FETCH"`) {
+		t.Fatalf("expected rule problem, got %v", err)
+	}
+
+	next, err := Parse([]byte(`
+version: 1
+approvals:
+  auto_approve:
+    - name: docs reads
+      tool: fetch_url
+      domains: [example.com]
+      methods: [GET, HEAD]
+`))
+	if err != nil {
+		t.Fatalf("parse next: %v", err)
+	}
+	if changes := Diff(file, next); len(changes) != 1 || changes[0] != "approvals: auto_approve changed" {
+		t.Fatalf("unexpected diff %v", changes)
+	}
+	if changes := Diff(File{Version: 1}, file); len(changes) != 1 || changes[0] != "approvals: added" {
+		t.Fatalf("unexpected diff %v", changes)
+	}
+}
+
+func TestParseReadsPrivacy(t *testing.T) {
+	file, err := Parse([]byte("version: 1\nprivacy:\n  redact: true\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if enabled, ok := file.Redaction(); !ok || !enabled {
+		t.Fatalf("expected redaction on, got %v %v", enabled, ok)
+	}
+	if _, ok := (File{Version: 1}).Redaction(); ok {
+		t.Fatal("expected a file without privacy to keep the default")
+	}
+	next, err := Parse([]byte("version: 1\nprivacy:\n  redact: false\n"))
+	if err != nil {
+		t.Fatalf("parse next: %v", err)
+	}
+	if changes := Diff(file, next); len(changes) != 1 || changes[0] != "privacy.redact: true -> false" {
+		t.Fatalf("unexpected diff %v", changes)
+	}
+	if changes := Diff(file, File{Version: 1}); len(changes) != 1 || changes[0] != "privacy: removed" {
+		t.Fatalf("unexpected diff %v", changes)
+	}
+}
+
+func TestParseReadsNetwork(t *testing.T) {
+	file, err := Parse([]byte(`
+version: 1
+network:
+  allow_domains: ["*.Example.com"]
+  deny_cidrs: [203.0.113.0/24]
+  allow_ports: [443]
+`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	rules, ok := file.EgressRules()
+	if !ok || rules.AllowDomains[0] != "example.com" || rules.DenyCIDRs[0] != "203.0.113.0/24" || rules.AllowPorts[0] != 443 {
+		t.Fatalf("expected normalized network rules, got %+v %v", rules, ok)
+	}
+	if _, ok := (File{Version: 1}).EgressRules(); ok {
+		t.Fatal("expected a file without network to keep the runtime rules")
+	}
+
+	_, err = Parse([]byte("version: 1\nnetwork:\n  deny_cidrs: [10.0.0.0/40]\n"))
+	if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), `network: "10.0.0.0/40" is not a CIDR range`) {
+		t.Fatalf("expected network problem, got %v", err)
+	}
+	if changes := Diff(File{Version: 1}, file); len(changes) != 1 || changes[0] != "network: added" {
+		t.Fatalf("unexpected diff %v", changes)
+	}
+}
@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CalDAV talks to a single calendar collection with basic auth.
+type CalDAV struct {
+	collectionURL string
+	username      string
+	password      string
+	httpClient    *http.Client
+	now           func() time.Time
+}
+
+func NewCalDAV(cfg Config) (*CalDAV, error) {
+	collection := strings.TrimSpace(cfg.CalDAVURL)
+	if collection == "" {
+		return nil, fmt.Errorf("%w: caldav url is required", ErrNotConfigured)
+	}
+	parsed, err := url.Parse(collection)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid caldav url %q", collection)
+	}
+	if !strings.HasSuffix(collection, "/") {
+		collection += "/"
+	}
+	return &CalDAV{
+		collectionURL: collection,
+		username:      strings.TrimSpace(cfg.CalDAVUsername),
+		password:      cfg.CalDAVPassword,
+		httpClient:    &http.Client{Timeout: defaultTimeout(cfg.Timeout)},
+		now:           time.Now,
+	}, nil
+}
+
+func (c *CalDAV) Provider() string { return "caldav" }
+
+type caldavMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				CalendarData string `xml:"calendar-data"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+func (c *CalDAV) ListEvents(ctx context.Context, from, to time.Time, limit int) ([]Event, error) {
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><c:calendar-data/></d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VEVENT">
+        <c:time-range start="%s" end="%s"/>
+      </c:comp-filter>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`, from.UTC().Format(icalUTCLayout), to.UTC().Format(icalUTCLayout))
+	req, err := http.NewRequestWithContext(ctx, "REPORT", c.collectionURL, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build caldav request: %w", err)
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	content, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var status caldavMultistatus
+	if err := xml.Unmarshal(content, &status); err != nil {
+		return nil, fmt.Errorf("decode caldav response: %w", err)
+	}
+	events := []Event{}
+	for _, response := range status.Responses {
+		for _, propstat := range response.Propstat {
+			for _, event := range parseICalEvents(propstat.Prop.CalendarData) {
+				if event.URL == "" {
+					event.URL = c.resolveHref(response.Href)
+				}
+				events = append(events, event)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (c *CalDAV) CreateEvent(ctx context.Context, event Event) (Event, error) {
+	if err := event.Validate(); err != nil {
+		return Event{}, err
+	}
+	if strings.TrimSpace(event.ID) == "" {
+		event.ID = newEventUID()
+	}
+	event.End = eventEnd(event)
+	target := c.collectionURL + url.PathEscape(event.ID) + ".ics"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, strings.NewReader(encodeICalEvent(event, c.now())))
+	if err != nil {
+		return Event{}, fmt.Errorf("build caldav request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	req.Header.Set("If-None-Match", "*")
+	if _, err := c.do(req); err != nil {
+		return Event{}, err
+	}
+	event.URL = target
+	return event, nil
+}
+
+func (c *CalDAV) do(req *http.Request) ([]byte, error) {
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("caldav request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	return readBody(resp)
+}
+
+func (c *CalDAV) resolveHref(href string) string {
+	if strings.TrimSpace(href) == "" {
+		return ""
+	}
+	base, err := url.Parse(c.collectionURL)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func newEventUID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf) + "@agent-runtime"
+}
@@ -0,0 +1,146 @@
+// Package calendar lists and creates events on a CalDAV collection or a
+// Google Calendar.
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const maxResponseBytes = 4 << 20
+
+var (
+	ErrNotConfigured   = errors.New("calendar is not configured")
+	ErrUnknownProvider = errors.New("unknown calendar provider")
+	ErrInvalidEvent    = errors.New("invalid calendar event")
+)
+
+type Event struct {
+	ID          string
+	Title       string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Attendees   []string
+	URL         string
+}
+
+// Validate checks the fields every provider needs to create an event.
+func (e Event) Validate() error {
+	if strings.TrimSpace(e.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidEvent)
+	}
+	if e.Start.IsZero() {
+		return fmt.Errorf("%w: start is required", ErrInvalidEvent)
+	}
+	if !e.End.IsZero() && !e.End.After(e.Start) {
+		return fmt.Errorf("%w: end must be after start", ErrInvalidEvent)
+	}
+	return nil
+}
+
+// Client is implemented by each calendar provider.
+type Client interface {
+	Provider() string
+	ListEvents(ctx context.Context, from, to time.Time, limit int) ([]Event, error)
+	CreateEvent(ctx context.Context, event Event) (Event, error)
+}
+
+type Config struct {
+	Provider           string
+	CalDAVURL          string
+	CalDAVUsername     string
+	CalDAVPassword     string
+	GoogleCalendarID   string
+	GoogleClientID     string
+	GoogleClientSecret string
+	GoogleRefreshToken string
+	GoogleAPIBase      string
+	GoogleTokenURL     string
+	Timeout            time.Duration
+}
+
+// New builds the client selected by cfg.Provider, or returns nil when no
+// provider is configured.
+func New(cfg Config) (Client, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "":
+		return nil, nil
+	case "caldav":
+		return NewCalDAV(cfg)
+	case "google":
+		return NewGoogle(cfg)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, cfg.Provider)
+	}
+}
+
+func defaultTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return 20 * time.Second
+	}
+	return timeout
+}
+
+// eventEnd defaults missing end times: one hour for timed events, one day
+// for all-day events.
+func eventEnd(event Event) time.Time {
+	if !event.End.IsZero() {
+		return event.End
+	}
+	if event.AllDay {
+		return event.Start.AddDate(0, 0, 1)
+	}
+	return event.Start.Add(time.Hour)
+}
+
+func readBody(resp *http.Response) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read calendar response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(content))
+		if len(message) > 300 {
+			message = message[:300]
+		}
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("calendar api %s %s: %d %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, message)
+	}
+	return content, nil
+}
+
+func encodeJSON(body any) (io.Reader, error) {
+	if body == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encode calendar request: %w", err)
+	}
+	return bytes.NewReader(encoded), nil
+}
+
+// ParseTime reads an RFC 3339 timestamp or a YYYY-MM-DD date. The boolean
+// reports whether the value was a date, which makes the event all-day.
+func ParseTime(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), false, nil
+	}
+	if parsed, err := time.ParseInLocation(time.DateOnly, value, time.UTC); err == nil {
+		return parsed, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%w: time %q must be RFC 3339 or YYYY-MM-DD", ErrInvalidEvent, value)
+}
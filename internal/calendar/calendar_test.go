@@ -0,0 +1,176 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalDAVListAndCreate(t *testing.T) {
+	var putBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "bot" || pass != "pw" {
+			t.Errorf("unexpected auth")
+		}
+		switch r.Method {
+		case "REPORT":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `start="20261020T000000Z"`) {
+				t.Errorf("missing time range: %s", body)
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:response><d:href>/cal/team/b.ics</d:href><d:propstat><d:prop><c:calendar-data>BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:b
+SUMMARY:Release 1.4\, final
+DTSTART;TZID=Europe/Berlin:20261022T100000
+DTEND;TZID=Europe/Berlin:20261022T110000
+LOCATION:Room
+  42
+END:VEVENT
+END:VCALENDAR
+</c:calendar-data></d:prop></d:propstat></d:response>
+  <d:response><d:href>/cal/team/a.ics</d:href><d:propstat><d:prop><c:calendar-data>BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:a
+SUMMARY:Freeze
+DTSTART;VALUE=DATE:20261021
+END:VEVENT
+END:VCALENDAR
+</c:calendar-data></d:prop></d:propstat></d:response>
+</d:multistatus>`))
+		case http.MethodPut:
+			if r.Header.Get("If-None-Match") != "*" || !strings.HasPrefix(r.URL.Path, "/cal/team/") {
+				t.Errorf("unexpected put %s %v", r.URL.Path, r.Header)
+			}
+			body, _ := io.ReadAll(r.Body)
+			putBody = string(body)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Config{Provider: "caldav", CalDAVURL: server.URL + "/cal/team", CalDAVUsername: "bot", CalDAVPassword: "pw"})
+	if err != nil {
+		t.Fatalf("new caldav: %v", err)
+	}
+	from := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	events, err := client.ListEvents(context.Background(), from, from.AddDate(0, 0, 7), 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "a" || !events[0].AllDay {
+		t.Fatalf("expected all-day event first, got %+v", events)
+	}
+	release := events[1]
+	if release.Title != "Release 1.4, final" || release.Location != "Room 42" || !release.Start.Equal(time.Date(2026, 10, 22, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected release event %+v", release)
+	}
+	if release.URL != server.URL+"/cal/team/b.ics" {
+		t.Fatalf("unexpected url %q", release.URL)
+	}
+
+	created, err := client.CreateEvent(context.Background(), Event{Title: "Retro; notes", Start: from.Add(9 * time.Hour), Attendees: []string{"ops@example.com"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.ID == "" || !created.End.Equal(from.Add(10*time.Hour)) {
+		t.Fatalf("unexpected created event %+v", created)
+	}
+	for _, want := range []string{"SUMMARY:Retro\\; notes\r\n", "DTSTART:20261020T090000Z\r\n", "DTEND:20261020T100000Z\r\n", "ATTENDEE;RSVP=TRUE:mailto:ops@example.com\r\n"} {
+		if !strings.Contains(putBody, want) {
+			t.Fatalf("expected %q in ics:\n%s", want, putBody)
+		}
+	}
+}
+
+func TestGoogleListAndCreateWithTokenCache(t *testing.T) {
+	tokenCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenCalls++
+			_ = r.ParseForm()
+			if r.Form.Get("refresh_token") != "refresh" {
+				t.Errorf("unexpected refresh token %q", r.Form.Get("refresh_token"))
+			}
+			_, _ = w.Write([]byte(`{"access_token":"access","expires_in":3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer access" {
+			t.Errorf("missing bearer token")
+		}
+		if r.URL.Path != "/calendars/team@example.com/events" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("singleEvents") != "true" || r.URL.Query().Get("maxResults") != "5" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"items":[{"id":"e1","summary":"Standup","start":{"dateTime":"2026-10-20T09:00:00+02:00"},"end":{"dateTime":"2026-10-20T09:15:00+02:00"},"htmlLink":"https://calendar/e1"}]}`))
+		case http.MethodPost:
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if start, _ := body["start"].(map[string]any); start["date"] != "2026-10-24" {
+				t.Errorf("expected all-day start, got %v", body["start"])
+			}
+			_, _ = w.Write([]byte(`{"id":"e2","summary":"Launch","start":{"date":"2026-10-24"},"end":{"date":"2026-10-25"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Config{
+		Provider:           "google",
+		GoogleCalendarID:   "team@example.com",
+		GoogleClientID:     "id",
+		GoogleClientSecret: "secret",
+		GoogleRefreshToken: "refresh",
+		GoogleAPIBase:      server.URL,
+		GoogleTokenURL:     server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatalf("new google: %v", err)
+	}
+	from := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	events, err := client.ListEvents(context.Background(), from, from.AddDate(0, 0, 7), 5)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(events) != 1 || !events[0].Start.Equal(time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	created, err := client.CreateEvent(context.Background(), Event{Title: "Launch", Start: time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC), AllDay: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.ID != "e2" || !created.AllDay {
+		t.Fatalf("unexpected created %+v", created)
+	}
+	if tokenCalls != 1 {
+		t.Fatalf("expected cached access token, got %d token calls", tokenCalls)
+	}
+}
+
+func TestNewValidatesProviderAndEvent(t *testing.T) {
+	if client, err := New(Config{}); client != nil || err != nil {
+		t.Fatalf("expected nil client without provider, got %v %v", client, err)
+	}
+	if _, err := New(Config{Provider: "outlook"}); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("expected unknown provider, got %v", err)
+	}
+	if _, err := New(Config{Provider: "google"}); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected not configured, got %v", err)
+	}
+	start := time.Now()
+	if err := (Event{Title: "x", Start: start, End: start.Add(-time.Hour)}).Validate(); !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected invalid event, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultGoogleAPIBase  = "https://www.googleapis.com/calendar/v3"
+	DefaultGoogleTokenURL = "https://oauth2.googleapis.com/token"
+)
+
+// Google talks to the Google Calendar v3 API with an OAuth refresh token.
+type Google struct {
+	calendarID   string
+	clientID     string
+	clientSecret string
+	refreshToken string
+	apiBase      string
+	tokenURL     string
+	httpClient   *http.Client
+	now          func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func NewGoogle(cfg Config) (*Google, error) {
+	if strings.TrimSpace(cfg.GoogleClientID) == "" || strings.TrimSpace(cfg.GoogleClientSecret) == "" || strings.TrimSpace(cfg.GoogleRefreshToken) == "" {
+		return nil, fmt.Errorf("%w: google client id, client secret, and refresh token are required", ErrNotConfigured)
+	}
+	calendarID := strings.TrimSpace(cfg.GoogleCalendarID)
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	apiBase := strings.TrimRight(strings.TrimSpace(cfg.GoogleAPIBase), "/")
+	if apiBase == "" {
+		apiBase = DefaultGoogleAPIBase
+	}
+	tokenURL := strings.TrimSpace(cfg.GoogleTokenURL)
+	if tokenURL == "" {
+		tokenURL = DefaultGoogleTokenURL
+	}
+	return &Google{
+		calendarID:   calendarID,
+		clientID:     strings.TrimSpace(cfg.GoogleClientID),
+		clientSecret: strings.TrimSpace(cfg.GoogleClientSecret),
+		refreshToken: strings.TrimSpace(cfg.GoogleRefreshToken),
+		apiBase:      apiBase,
+		tokenURL:     tokenURL,
+		httpClient:   &http.Client{Timeout: defaultTimeout(cfg.Timeout)},
+		now:          time.Now,
+	}, nil
+}
+
+func (g *Google) Provider() string { return "google" }
+
+type googleEventTime struct {
+	DateTime string `json:"dateTime,omitempty"`
+	Date     string `json:"date,omitempty"`
+}
+
+type googleEvent struct {
+	ID          string           `json:"id,omitempty"`
+	Summary     string           `json:"summary"`
+	Description string           `json:"description,omitempty"`
+	Location    string           `json:"location,omitempty"`
+	Start       googleEventTime  `json:"start"`
+	End         googleEventTime  `json:"end"`
+	Attendees   []googleAttendee `json:"attendees,omitempty"`
+	HTMLLink    string           `json:"htmlLink,omitempty"`
+}
+
+type googleAttendee struct {
+	Email string `json:"email"`
+}
+
+func (g *Google) ListEvents(ctx context.Context, from, to time.Time, limit int) ([]Event, error) {
+	query := url.Values{}
+	query.Set("timeMin", from.UTC().Format(time.RFC3339))
+	query.Set("timeMax", to.UTC().Format(time.RFC3339))
+	query.Set("singleEvents", "true")
+	query.Set("orderBy", "startTime")
+	if limit > 0 {
+		query.Set("maxResults", strconv.Itoa(limit))
+	}
+	var listed struct {
+		Items []googleEvent `json:"items"`
+	}
+	if err := g.do(ctx, http.MethodGet, g.eventsPath()+"?"+query.Encode(), nil, &listed); err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(listed.Items))
+	for _, item := range listed.Items {
+		events = append(events, item.toEvent())
+	}
+	return events, nil
+}
+
+func (g *Google) CreateEvent(ctx context.Context, event Event) (Event, error) {
+	if err := event.Validate(); err != nil {
+		return Event{}, err
+	}
+	body := googleEvent{
+		Summary:     event.Title,
+		Description: event.Description,
+		Location:    event.Location,
+		Start:       toGoogleTime(event.Start, event.AllDay),
+		End:         toGoogleTime(eventEnd(event), event.AllDay),
+	}
+	for _, attendee := range event.Attendees {
+		body.Attendees = append(body.Attendees, googleAttendee{Email: attendee})
+	}
+	var created googleEvent
+	if err := g.do(ctx, http.MethodPost, g.eventsPath(), body, &created); err != nil {
+		return Event{}, err
+	}
+	return created.toEvent(), nil
+}
+
+func (g *Google) eventsPath() string {
+	return "/calendars/" + url.PathEscape(g.calendarID) + "/events"
+}
+
+func (g *Google) do(ctx context.Context, method, path string, body any, target any) error {
+	token, err := g.token(ctx)
+	if err != nil {
+		return err
+	}
+	reader, err := encodeJSON(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.apiBase+path, reader)
+	if err != nil {
+		return fmt.Errorf("build google calendar request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("google calendar request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	content, err := readBody(resp)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return nil
+	}
+	if err := json.Unmarshal(content, target); err != nil {
+		return fmt.Errorf("decode google calendar response: %w", err)
+	}
+	return nil
+}
+
+// token exchanges the refresh token for an access token and caches it until
+// a minute before it expires.
+func (g *Google) token(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.accessToken != "" && g.now().Before(g.expiresAt) {
+		return g.accessToken, nil
+	}
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("client_id", g.clientID)
+	form.Set("client_secret", g.clientSecret)
+	form.Set("refresh_token", g.refreshToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("build google token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("google token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	content, err := readBody(resp)
+	if err != nil {
+		return "", err
+	}
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(content, &payload); err != nil || payload.AccessToken == "" {
+		return "", fmt.Errorf("google token response missing access_token")
+	}
+	g.accessToken = payload.AccessToken
+	g.expiresAt = g.now().Add(time.Duration(payload.ExpiresIn)*time.Second - time.Minute)
+	return g.accessToken, nil
+}
+
+func (e googleEvent) toEvent() Event {
+	event := Event{
+		ID:          e.ID,
+		Title:       e.Summary,
+		Description: e.Description,
+		Location:    e.Location,
+		URL:         e.HTMLLink,
+	}
+	event.Start, event.AllDay = fromGoogleTime(e.Start)
+	event.End, _ = fromGoogleTime(e.End)
+	for _, attendee := range e.Attendees {
+		event.Attendees = append(event.Attendees, attendee.Email)
+	}
+	return event
+}
+
+func toGoogleTime(value time.Time, allDay bool) googleEventTime {
+	if allDay {
+		return googleEventTime{Date: value.Format(time.DateOnly)}
+	}
+	return googleEventTime{DateTime: value.UTC().Format(time.RFC3339)}
+}
+
+func fromGoogleTime(value googleEventTime) (time.Time, bool) {
+	if value.Date != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, value.Date, time.UTC)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return parsed, true
+	}
+	parsed, err := time.Parse(time.RFC3339, value.DateTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed.UTC(), false
+}
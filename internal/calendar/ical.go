@@ -0,0 +1,179 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	icalUTCLayout   = "20060102T150405Z"
+	icalLocalLayout = "20060102T150405"
+	icalDateLayout  = "20060102"
+)
+
+// parseICalEvents extracts VEVENT components from iCalendar text. Only the
+// properties the tools surface are read; recurrence rules are not expanded.
+func parseICalEvents(text string) []Event {
+	events := []Event{}
+	var current *Event
+	for _, line := range unfoldICal(text) {
+		name, params, value := splitICalProperty(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &Event{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current != nil {
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+		case name == "UID":
+			current.ID = value
+		case name == "SUMMARY":
+			current.Title = unescapeICalText(value)
+		case name == "DESCRIPTION":
+			current.Description = unescapeICalText(value)
+		case name == "LOCATION":
+			current.Location = unescapeICalText(value)
+		case name == "URL":
+			current.URL = value
+		case name == "ATTENDEE":
+			if email := strings.TrimSpace(value); email != "" {
+				current.Attendees = append(current.Attendees, strings.TrimPrefix(strings.TrimPrefix(email, "mailto:"), "MAILTO:"))
+			}
+		case name == "DTSTART":
+			current.Start, current.AllDay = parseICalTime(value, params)
+		case name == "DTEND":
+			current.End, _ = parseICalTime(value, params)
+		}
+	}
+	return events
+}
+
+func unfoldICal(text string) []string {
+	raw := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	lines := make([]string, 0, len(raw))
+	for _, line := range raw {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func splitICalProperty(line string) (string, map[string]string, string) {
+	head, value, found := strings.Cut(line, ":")
+	if !found {
+		return "", nil, ""
+	}
+	parts := strings.Split(head, ";")
+	params := map[string]string{}
+	for _, part := range parts[1:] {
+		key, paramValue, _ := strings.Cut(part, "=")
+		params[strings.ToUpper(key)] = strings.Trim(paramValue, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseICalTime(value string, params map[string]string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if params["VALUE"] == "DATE" || len(value) == len(icalDateLayout) {
+		parsed, err := time.ParseInLocation(icalDateLayout, value, time.UTC)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return parsed, true
+	}
+	if strings.HasSuffix(value, "Z") {
+		parsed, err := time.Parse(icalUTCLayout, value)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return parsed, false
+	}
+	location := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	parsed, err := time.ParseInLocation(icalLocalLayout, value, location)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed.UTC(), false
+}
+
+func unescapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+func escapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, ",", `\,`, ";", `\;`)
+	return replacer.Replace(value)
+}
+
+// encodeICalEvent renders a single-event VCALENDAR for a CalDAV PUT.
+func encodeICalEvent(event Event, now time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//dwizi//agent-runtime//EN",
+		"BEGIN:VEVENT",
+		"UID:" + event.ID,
+		"DTSTAMP:" + now.UTC().Format(icalUTCLayout),
+	}
+	end := eventEnd(event)
+	if event.AllDay {
+		lines = append(lines,
+			"DTSTART;VALUE=DATE:"+event.Start.Format(icalDateLayout),
+			"DTEND;VALUE=DATE:"+end.Format(icalDateLayout),
+		)
+	} else {
+		lines = append(lines,
+			"DTSTART:"+event.Start.UTC().Format(icalUTCLayout),
+			"DTEND:"+end.UTC().Format(icalUTCLayout),
+		)
+	}
+	lines = append(lines, "SUMMARY:"+escapeICalText(event.Title))
+	if event.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeICalText(event.Description))
+	}
+	if event.Location != "" {
+		lines = append(lines, "LOCATION:"+escapeICalText(event.Location))
+	}
+	for _, attendee := range event.Attendees {
+		lines = append(lines, fmt.Sprintf("ATTENDEE;RSVP=TRUE:mailto:%s", attendee))
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+	folded := make([]string, 0, len(lines))
+	for _, line := range lines {
+		folded = append(folded, foldICalLine(line))
+	}
+	return strings.Join(folded, "\r\n") + "\r\n"
+}
+
+// foldICalLine splits lines longer than 75 octets without breaking runes.
+func foldICalLine(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+	builder := strings.Builder{}
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			builder.WriteString("\r\n ")
+			width = 1
+		}
+		builder.WriteRune(r)
+		width += size
+	}
+	return builder.String()
+}
@@ -0,0 +1,190 @@
+// Package canary routes a percentage of contexts to a candidate system
+// prompt, model or tool, counts how their agent turns go against the rest of
+// the traffic and rolls the change back when the canary contexts fail or get
+// blocked noticeably more often.
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type Store interface {
+	ListActiveCanaryRollouts(ctx context.Context, workspaceID string) ([]store.CanaryRollout, error)
+	RecordCanaryOutcome(ctx context.Context, id string, canary, errored, blocked bool) (store.CanaryRollout, error)
+	FinishCanaryRollout(ctx context.Context, id string, status store.CanaryStatus, reason string) (store.CanaryRollout, error)
+}
+
+// Arm is the side of one rollout a context landed on.
+type Arm struct {
+	RolloutID string
+	Kind      store.CanaryKind
+	Canary    bool
+}
+
+// Assignment is what the active rollouts mean for one agent turn: the model
+// and prompt overrides of the canaries it is in and the canary tools it must
+// not see.
+type Assignment struct {
+	Arms        []Arm
+	Variant     llm.Variant
+	DeniedTools []string
+}
+
+type Service struct {
+	store  Store
+	logger *slog.Logger
+}
+
+func New(storeRef Store, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{store: storeRef, logger: logger}
+}
+
+// InCanary reports whether a context falls into the canary share of a
+// rollout. The split is stable, so a context keeps its side for the life of
+// the rollout and a higher percent only adds contexts.
+func InCanary(rolloutID, contextID string, percent int) bool {
+	if percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(strings.TrimSpace(rolloutID) + ":" + strings.TrimSpace(contextID)))
+	return int(hash.Sum32()%100) < percent
+}
+
+// Assign places a context on a side of every active rollout of its
+// workspace. Failures are logged and yield no assignment, so a broken
+// rollout never breaks a turn.
+func (s *Service) Assign(ctx context.Context, workspaceID, contextID string) Assignment {
+	assignment := Assignment{}
+	if s == nil || s.store == nil || strings.TrimSpace(contextID) == "" {
+		return assignment
+	}
+	rollouts, err := s.store.ListActiveCanaryRollouts(ctx, workspaceID)
+	if err != nil {
+		s.logger.Error("list canary rollouts failed", "error", err, "workspace_id", workspaceID)
+		return assignment
+	}
+	for _, rollout := range rollouts {
+		inCanary := InCanary(rollout.ID, contextID, rollout.Percent)
+		assignment.Arms = append(assignment.Arms, Arm{RolloutID: rollout.ID, Kind: rollout.Kind, Canary: inCanary})
+		// A workspace rollout takes precedence over a runtime-wide one of
+		// the same kind.
+		switch {
+		case rollout.Kind == store.CanaryTool && !inCanary:
+			assignment.DeniedTools = append(assignment.DeniedTools, rollout.Value)
+		case rollout.Kind == store.CanaryModel && inCanary:
+			if assignment.Variant.Model == "" || rollout.WorkspaceID != "" {
+				assignment.Variant.Model = rollout.Value
+			}
+		case rollout.Kind == store.CanaryPrompt && inCanary:
+			if assignment.Variant.SystemPrompt == "" || rollout.WorkspaceID != "" {
+				assignment.Variant.SystemPrompt = rollout.Value
+			}
+		}
+	}
+	return assignment
+}
+
+// Record counts the outcome of a turn on every rollout it took part in and
+// rolls back the rollouts whose canary side crossed its thresholds.
+func (s *Service) Record(ctx context.Context, assignment Assignment, errored, blocked bool) {
+	if s == nil || s.store == nil {
+		return
+	}
+	for _, arm := range assignment.Arms {
+		rollout, err := s.store.RecordCanaryOutcome(ctx, arm.RolloutID, arm.Canary, errored, blocked)
+		if errors.Is(err, store.ErrCanaryNotActive) || errors.Is(err, store.ErrCanaryNotFound) {
+			continue
+		}
+		if err != nil {
+			s.logger.Error("record canary outcome failed", "error", err, "rollout_id", arm.RolloutID)
+			continue
+		}
+		reason := RollbackReason(rollout)
+		if reason == "" {
+			continue
+		}
+		if _, err := s.store.FinishCanaryRollout(ctx, rollout.ID, store.CanaryRolledBack, reason); err != nil {
+			if !errors.Is(err, store.ErrCanaryNotActive) {
+				s.logger.Error("canary rollback failed", "error", err, "rollout_id", rollout.ID)
+			}
+			continue
+		}
+		s.logger.Warn("canary rolled back", "rollout_id", rollout.ID, "name", rollout.Name, "kind", rollout.Kind, "reason", reason)
+	}
+}
+
+// RollbackReason explains why a rollout should be rolled back, or returns ""
+// while it is healthy or has not seen MinSamples canary turns yet.
+func RollbackReason(rollout store.CanaryRollout) string {
+	if rollout.Canary.Turns < rollout.MinSamples || rollout.Canary.Turns == 0 {
+		return ""
+	}
+	canaryErrors := rate(rollout.Canary.Errors, rollout.Canary.Turns)
+	controlErrors := rate(rollout.Control.Errors, rollout.Control.Turns)
+	if canaryErrors-controlErrors > rollout.ErrorMargin {
+		return fmt.Sprintf("error rate %.0f%% against %.0f%% in control", canaryErrors*100, controlErrors*100)
+	}
+	canaryBlocks := rate(rollout.Canary.Blocks, rollout.Canary.Turns)
+	controlBlocks := rate(rollout.Control.Blocks, rollout.Control.Turns)
+	if canaryBlocks-controlBlocks > rollout.BlockMargin {
+		return fmt.Sprintf("block rate %.0f%% against %.0f%% in control", canaryBlocks*100, controlBlocks*100)
+	}
+	return ""
+}
+
+func rate(count, turns int) float64 {
+	if turns <= 0 {
+		return 0
+	}
+	return float64(count) / float64(turns)
+}
+
+type assignmentKey struct{}
+
+// WithAssignment applies an assignment to a turn: model and prompt
+// overrides reach the LLM clients through ctx, denied tools reach the agent
+// through PolicyResolver.
+func WithAssignment(ctx context.Context, assignment Assignment) context.Context {
+	ctx = context.WithValue(ctx, assignmentKey{}, assignment)
+	if assignment.Variant != (llm.Variant{}) {
+		ctx = llm.WithVariant(ctx, assignment.Variant)
+	}
+	return ctx
+}
+
+// AssignmentFrom returns the assignment applied to ctx, if any.
+func AssignmentFrom(ctx context.Context) Assignment {
+	assignment, _ := ctx.Value(assignmentKey{}).(Assignment)
+	return assignment
+}
+
+// PolicyResolver wraps an agent policy resolver so control contexts do not
+// see the tools that are still in canary.
+func PolicyResolver(next agent.PolicyResolver) agent.PolicyResolver {
+	return func(ctx context.Context, input llm.MessageInput) agent.Policy {
+		policy := agent.Policy{}
+		if next != nil {
+			policy = next(ctx, input)
+		}
+		if denied := AssignmentFrom(ctx).DeniedTools; len(denied) > 0 {
+			policy.DeniedTools = append(append([]string{}, policy.DeniedTools...), denied...)
+		}
+		return policy
+	}
+}
@@ -0,0 +1,130 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "canary_test.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(context.Background()); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	return sqlStore
+}
+
+func TestInCanaryIsStableAndProportional(t *testing.T) {
+	inCanary := 0
+	for index := 0; index < 1000; index++ {
+		contextID := fmt.Sprintf("ctx-%d", index)
+		if InCanary("canary_1", contextID, 20) {
+			inCanary++
+			if !InCanary("canary_1", contextID, 50) {
+				t.Fatalf("expected raising the percent to keep %s in the canary", contextID)
+			}
+		}
+	}
+	if inCanary < 150 || inCanary > 250 {
+		t.Fatalf("expected roughly 20%% of contexts in the canary, got %d/1000", inCanary)
+	}
+	if InCanary("canary_1", "ctx-1", 0) || !InCanary("canary_1", "ctx-1", 100) {
+		t.Fatal("expected 0% and 100% to be absolute")
+	}
+}
+
+func TestAssignAppliesVariantsAndDeniesCanaryTools(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := newTestStore(t)
+	if _, err := sqlStore.CreateCanaryRollout(ctx, store.CreateCanaryRolloutInput{WorkspaceID: "ws-1", Kind: store.CanaryModel, Value: "gpt-candidate", Percent: 100}); err != nil {
+		t.Fatalf("create model rollout: %v", err)
+	}
+	tool, err := sqlStore.CreateCanaryRollout(ctx, store.CreateCanaryRolloutInput{Kind: store.CanaryTool, Value: "browser_page", Percent: 50})
+	if err != nil {
+		t.Fatalf("create tool rollout: %v", err)
+	}
+	control := ""
+	for index := 0; control == ""; index++ {
+		if contextID := fmt.Sprintf("ctx-%d", index); !InCanary(tool.ID, contextID, tool.Percent) {
+			control = contextID
+		}
+	}
+
+	service := New(sqlStore, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assignment := service.Assign(ctx, "ws-1", control)
+	if len(assignment.Arms) != 2 || assignment.Variant.Model != "gpt-candidate" {
+		t.Fatalf("expected the model canary and a tool arm, got %+v", assignment)
+	}
+	if len(assignment.DeniedTools) != 1 || assignment.DeniedTools[0] != "browser_page" {
+		t.Fatalf("expected the canary tool to be denied to a control context, got %+v", assignment.DeniedTools)
+	}
+
+	turnCtx := WithAssignment(ctx, assignment)
+	if llm.VariantFrom(turnCtx).Model != "gpt-candidate" {
+		t.Fatal("expected the model override to reach the llm clients")
+	}
+	resolver := PolicyResolver(func(ctx context.Context, input llm.MessageInput) agent.Policy {
+		return agent.Policy{MaxToolCallsPerTurn: 2}
+	})
+	if policy := resolver(turnCtx, llm.MessageInput{}); policy.MaxToolCallsPerTurn != 2 || len(policy.DeniedTools) != 1 {
+		t.Fatalf("expected denied tools on top of the base policy, got %+v", policy)
+	}
+	if other := service.Assign(ctx, "ws-2", control); other.Variant.Model != "" || len(other.Arms) != 1 {
+		t.Fatalf("expected only the runtime-wide rollout in another workspace, got %+v", other)
+	}
+}
+
+func TestRecordRollsBackWhenCanaryErrorsSpike(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := newTestStore(t)
+	rollout, err := sqlStore.CreateCanaryRollout(ctx, store.CreateCanaryRolloutInput{Kind: store.CanaryPrompt, Value: "Be terse.", Percent: 10, MinSamples: 4, ErrorMargin: 0.2})
+	if err != nil {
+		t.Fatalf("create rollout: %v", err)
+	}
+	service := New(sqlStore, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	canaryTurn := Assignment{Arms: []Arm{{RolloutID: rollout.ID, Kind: store.CanaryPrompt, Canary: true}}}
+	controlTurn := Assignment{Arms: []Arm{{RolloutID: rollout.ID, Kind: store.CanaryPrompt}}}
+
+	for index := 0; index < 10; index++ {
+		service.Record(ctx, controlTurn, index == 0, false)
+	}
+	for index := 0; index < 3; index++ {
+		service.Record(ctx, canaryTurn, true, false)
+	}
+	if current, _ := sqlStore.LookupCanaryRollout(ctx, rollout.ID); current.Status != store.CanaryActive {
+		t.Fatalf("expected no rollback before min samples, got %+v", current)
+	}
+	service.Record(ctx, canaryTurn, false, false)
+	current, err := sqlStore.LookupCanaryRollout(ctx, rollout.ID)
+	if err != nil || current.Status != store.CanaryRolledBack || current.Reason != "error rate 75% against 10% in control" {
+		t.Fatalf("expected automatic rollback, got %+v (%v)", current, err)
+	}
+	if assignment := service.Assign(ctx, "ws-1", "ctx-1"); len(assignment.Arms) != 0 {
+		t.Fatalf("expected rolled back rollout to stop routing traffic, got %+v", assignment)
+	}
+	service.Record(ctx, canaryTurn, true, true)
+}
+
+func TestRollbackReasonComparesBlockRates(t *testing.T) {
+	healthy := store.CanaryRollout{MinSamples: 2, ErrorMargin: 0.1, BlockMargin: 0.1, Canary: store.CanaryArm{Turns: 10, Blocks: 1}, Control: store.CanaryArm{Turns: 10, Blocks: 1}}
+	if reason := RollbackReason(healthy); reason != "" {
+		t.Fatalf("expected healthy rollout, got %q", reason)
+	}
+	blocked := healthy
+	blocked.Canary.Blocks = 5
+	if reason := RollbackReason(blocked); reason != "block rate 50% against 10% in control" {
+		t.Fatalf("unexpected reason %q", reason)
+	}
+}
@@ -0,0 +1,687 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+)
+
+// adminOptions are the flags every admin subcommand shares.
+type adminOptions struct {
+	jsonMode   bool
+	timeoutSec int
+}
+
+func newAdminCommand() *cobra.Command {
+	opts := &adminOptions{}
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Run admin operations against the runtime without the TUI",
+		Long: "Headless counterparts of the TUI actions for scripts, CI jobs and cron.\n" +
+			"Every subcommand talks to the admin API configured by AGENT_RUNTIME_ADMIN_*,\n" +
+			"prints JSON with --json and exits non-zero when the API refuses the request.",
+	}
+	cmd.PersistentFlags().BoolVar(&opts.jsonMode, "json", false, "print the API response as JSON")
+	cmd.PersistentFlags().IntVar(&opts.timeoutSec, "timeout-sec", 120, "request timeout in seconds")
+	cmd.AddCommand(newAdminTasksCommand(opts))
+	cmd.AddCommand(newAdminObjectivesCommand(opts))
+	cmd.AddCommand(newAdminApprovalsCommand(opts))
+	cmd.AddCommand(newAdminPairingsCommand(opts))
+	return cmd
+}
+
+// call runs one admin API request with a client from the environment and a
+// context bounded by --timeout-sec.
+func (o *adminOptions) call(fn func(ctx context.Context, client *adminclient.Client) error) error {
+	client, err := newAdminClientFromEnv(o.timeoutSec)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), boundedTimeout(o.timeoutSec))
+	defer cancel()
+	return fn(ctx, client)
+}
+
+// write prints value as indented JSON with --json and with text otherwise.
+func (o *adminOptions) write(out io.Writer, value any, text func(io.Writer)) error {
+	if !o.jsonMode {
+		text(out)
+		return nil
+	}
+	payload, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(payload))
+	return err
+}
+
+func newAdminTasksCommand(opts *adminOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tasks",
+		Short: "List, retry and cancel tasks and fetch their artifacts",
+	}
+
+	var (
+		workspaceID string
+		status      string
+		limit       int
+	)
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the tasks of a workspace, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(workspaceID) == "" {
+				return fmt.Errorf("--workspace-id is required")
+			}
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				tasks, err := client.ListTasks(ctx, workspaceID, status, limit)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), tasks, func(out io.Writer) {
+					writeTaskTable(out, tasks)
+				})
+			})
+		},
+	}
+	list.Flags().StringVar(&workspaceID, "workspace-id", "", "workspace to list")
+	list.Flags().StringVar(&status, "status", "", "only tasks in this status: queued, running, succeeded, failed or cancelled, or dead for dead-lettered tasks")
+	list.Flags().IntVar(&limit, "limit", 50, "maximum number of tasks")
+
+	retry := &cobra.Command{
+		Use:   "retry <task-id>",
+		Short: "Queue a new attempt of a failed task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				response, err := client.RetryTask(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), response, func(out io.Writer) {
+					fmt.Fprintf(out, "Task retried: %s (retry of %s)\n", response.TaskID, response.RetryOfTask)
+					fmt.Fprintf(out, "Status: %s\n", response.Status)
+				})
+			})
+		},
+	}
+
+	cancel := &cobra.Command{
+		Use:   "cancel <task-id>",
+		Short: "Cancel a queued or running task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				response, err := client.CancelTask(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), response, func(out io.Writer) {
+					if response.WasRunning {
+						fmt.Fprintf(out, "Task cancelled: %s (its worker was stopped)\n", response.TaskID)
+					} else {
+						fmt.Fprintf(out, "Task cancelled: %s\n", response.TaskID)
+					}
+				})
+			})
+		},
+	}
+
+	artifacts := &cobra.Command{
+		Use:   "artifacts <task-id>",
+		Short: "List the files a task produced",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				items, err := client.ListTaskArtifacts(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), items, func(out io.Writer) {
+					writeTaskArtifactTable(out, items)
+				})
+			})
+		},
+	}
+
+	var outputPath string
+	download := &cobra.Command{
+		Use:   "download <artifact-id>",
+		Short: "Write the content of a task artifact to stdout or a file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				var out io.Writer = cmd.OutOrStdout()
+				if outputPath = strings.TrimSpace(outputPath); outputPath != "" {
+					file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+					if err != nil {
+						return fmt.Errorf("open output: %w", err)
+					}
+					defer file.Close()
+					out = file
+				}
+				if err := client.DownloadTaskArtifact(ctx, args[0], out); err != nil {
+					return err
+				}
+				if outputPath != "" {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Artifact written to %s\n", outputPath)
+				}
+				return nil
+			})
+		},
+	}
+	download.Flags().StringVar(&outputPath, "output", "", "file to write instead of stdout")
+
+	cmd.AddCommand(list, retry, cancel, artifacts, download)
+	return cmd
+}
+
+func newAdminObjectivesCommand(opts *adminOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "objectives",
+		Short: "List, create, pause, resume and fire objectives and show their runs",
+	}
+
+	var (
+		listWorkspaceID string
+		includePaused   bool
+		limit           int
+	)
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the objectives of a workspace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(listWorkspaceID) == "" {
+				return fmt.Errorf("--workspace-id is required")
+			}
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				objectives, err := client.ListObjectives(ctx, listWorkspaceID, !includePaused, limit)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), objectives, func(out io.Writer) {
+					writeObjectiveTable(out, objectives)
+				})
+			})
+		},
+	}
+	list.Flags().StringVar(&listWorkspaceID, "workspace-id", "", "workspace to list")
+	list.Flags().BoolVar(&includePaused, "all", false, "include paused objectives")
+	list.Flags().IntVar(&limit, "limit", 100, "maximum number of objectives")
+
+	var (
+		input    adminclient.CreateObjectiveRequest
+		interval string
+		paused   bool
+	)
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create an objective with a cron, interval or event trigger",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request, err := objectiveCreateRequest(input, interval, paused)
+			if err != nil {
+				return err
+			}
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				objective, err := client.CreateObjective(ctx, request)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), objective, func(out io.Writer) {
+					writeObjectiveSummary(out, "Objective created", objective)
+				})
+			})
+		},
+	}
+	create.Flags().StringVar(&input.WorkspaceID, "workspace-id", "", "workspace the objective belongs to")
+	create.Flags().StringVar(&input.ContextID, "context-id", "", "context the runs report to")
+	create.Flags().StringVar(&input.Title, "title", "", "objective title")
+	create.Flags().StringVar(&input.Prompt, "prompt", "", "what the agent should do on each run")
+	create.Flags().StringVar(&input.CronExpr, "cron", "", "cron schedule, e.g. \"0 8 * * 1\"")
+	create.Flags().StringVar(&interval, "interval", "", "run every duration, e.g. 30m")
+	create.Flags().StringVar(&input.EventKey, "event-key", "", "run when this event fires")
+	create.Flags().StringVar(&input.Timezone, "timezone", "", "IANA timezone for the cron schedule (default UTC)")
+	create.Flags().BoolVar(&paused, "paused", false, "create the objective paused")
+	create.Flags().BoolVar(&input.ReportChangesOnly, "changes-only", false, "compare each run with the last one and report only what changed")
+	create.Flags().StringVar(&input.DigestPeriod, "digest", "", "collect successful runs into one daily or weekly digest message")
+
+	var runsLimit int
+	runs := &cobra.Command{
+		Use:   "runs <objective-id>",
+		Short: "Show the run history of an objective",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				items, err := client.ListObjectiveRuns(ctx, args[0], runsLimit)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), items, func(out io.Writer) {
+					writeObjectiveRunTable(out, items)
+				})
+			})
+		},
+	}
+	runs.Flags().IntVar(&runsLimit, "limit", 20, "maximum number of runs")
+
+	var (
+		fireInput adminclient.FireEventRequest
+		fireData  string
+	)
+	fire := &cobra.Command{
+		Use:   "fire <event-key>",
+		Short: "Fire an event, running the objectives listening for it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request := fireInput
+			request.EventKey = args[0]
+			if strings.TrimSpace(fireData) != "" {
+				if err := json.Unmarshal([]byte(fireData), &request.Data); err != nil {
+					return fmt.Errorf("--data must be a JSON object: %w", err)
+				}
+			}
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				response, err := client.FireEvent(ctx, request)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), response, func(out io.Writer) {
+					writeFiredObjectiveTable(out, response)
+				})
+			})
+		},
+	}
+	fire.Flags().StringVar(&fireInput.WorkspaceID, "workspace-id", "", "workspace to fire the event in (default every workspace)")
+	fire.Flags().StringVar(&fireInput.DedupeKey, "dedupe-key", "", "run each objective once per key, e.g. a build id")
+	fire.Flags().StringVar(&fireData, "data", "", "JSON object shown to the objective runs")
+
+	cmd.AddCommand(list, create,
+		newAdminObjectiveActiveCommand(opts, "pause", "Pause an objective so it stops firing", false),
+		newAdminObjectiveActiveCommand(opts, "resume", "Resume a paused objective", true),
+		runs,
+		fire,
+	)
+	return cmd
+}
+
+func newAdminObjectiveActiveCommand(opts *adminOptions, use, short string, active bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <objective-id>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				objective, err := client.SetObjectiveActive(ctx, args[0], active, 0)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), objective, func(out io.Writer) {
+					writeObjectiveSummary(out, "Objective "+objectiveState(objective), objective)
+				})
+			})
+		},
+	}
+}
+
+// objectiveCreateRequest checks the create flags and resolves the trigger;
+// exactly one of --cron, --interval and --event-key must be set.
+func objectiveCreateRequest(input adminclient.CreateObjectiveRequest, interval string, paused bool) (adminclient.CreateObjectiveRequest, error) {
+	for _, field := range []struct{ flag, value string }{
+		{"--workspace-id", input.WorkspaceID},
+		{"--context-id", input.ContextID},
+		{"--title", input.Title},
+		{"--prompt", input.Prompt},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			return adminclient.CreateObjectiveRequest{}, fmt.Errorf("%s is required", field.flag)
+		}
+	}
+	input.CronExpr = strings.TrimSpace(input.CronExpr)
+	input.EventKey = strings.TrimSpace(input.EventKey)
+	interval = strings.TrimSpace(interval)
+	triggers := 0
+	for _, value := range []string{input.CronExpr, interval, input.EventKey} {
+		if value != "" {
+			triggers++
+		}
+	}
+	if triggers != 1 {
+		return adminclient.CreateObjectiveRequest{}, fmt.Errorf("set exactly one of --cron, --interval and --event-key")
+	}
+	switch {
+	case input.EventKey != "":
+		input.TriggerType = "event"
+	case interval != "":
+		if parsed, err := time.ParseDuration(interval); err != nil || parsed <= 0 {
+			return adminclient.CreateObjectiveRequest{}, fmt.Errorf("--interval must be a duration such as 30m or 6h")
+		}
+		input.TriggerType = "schedule"
+		input.CronExpr = "@every " + interval
+	default:
+		input.TriggerType = "schedule"
+	}
+	if paused {
+		active := false
+		input.Active = &active
+	}
+	return input, nil
+}
+
+func newAdminApprovalsCommand(opts *adminOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approvals",
+		Short: "List, approve and deny pending actions",
+	}
+
+	var (
+		workspaceID string
+		status      string
+		limit       int
+	)
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List action approvals, pending ones by default",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				approvals, err := client.ListApprovals(ctx, workspaceID, status, limit)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), approvals, func(out io.Writer) {
+					writeApprovalTable(out, approvals)
+				})
+			})
+		},
+	}
+	list.Flags().StringVar(&workspaceID, "workspace-id", "", "only this workspace (default all)")
+	list.Flags().StringVar(&status, "status", "pending", "only approvals in this status")
+	list.Flags().IntVar(&limit, "limit", 50, "maximum number of approvals")
+
+	var approverUserID string
+	approve := &cobra.Command{
+		Use:   "approve <approval-id>",
+		Short: "Approve a pending action and run it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(approverUserID) == "" {
+				return fmt.Errorf("--approver-user-id is required")
+			}
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				approval, err := client.ApproveAction(ctx, args[0], approverUserID)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), approval, func(out io.Writer) {
+					writeApprovalSummary(out, "Action approved", approval)
+				})
+			})
+		},
+	}
+	approve.Flags().StringVar(&approverUserID, "approver-user-id", "", "admin user id recorded as the approver")
+
+	var (
+		denierUserID string
+		reason       string
+	)
+	deny := &cobra.Command{
+		Use:   "deny <approval-id>",
+		Short: "Deny a pending action",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(denierUserID) == "" {
+				return fmt.Errorf("--approver-user-id is required")
+			}
+			if strings.TrimSpace(reason) == "" {
+				return fmt.Errorf("--reason is required")
+			}
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				approval, err := client.DenyAction(ctx, args[0], denierUserID, reason)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), approval, func(out io.Writer) {
+					writeApprovalSummary(out, "Action denied", approval)
+				})
+			})
+		},
+	}
+	deny.Flags().StringVar(&denierUserID, "approver-user-id", "", "admin user id recorded as the denier")
+	deny.Flags().StringVar(&reason, "reason", "", "denial reason")
+
+	cmd.AddCommand(list, approve, deny)
+	return cmd
+}
+
+func newAdminPairingsCommand(opts *adminOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pairings",
+		Short: "Approve and deny pairing tokens",
+	}
+
+	var (
+		approverUserID string
+		role           string
+		targetUserID   string
+	)
+	approve := &cobra.Command{
+		Use:   "approve <token>",
+		Short: "Approve a pending pairing token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(approverUserID) == "" {
+				return fmt.Errorf("--approver-user-id is required")
+			}
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				response, err := client.ApprovePairing(ctx, strings.TrimSpace(args[0]), approverUserID, role, targetUserID)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), response, func(out io.Writer) {
+					fmt.Fprintf(out, "Pairing approved: %s\n", response.ID)
+					fmt.Fprintf(out, "Approved user id: %s\n", response.ApprovedUserID)
+					fmt.Fprintf(out, "Identity: %s/%s\n", response.Connector, response.ConnectorUserID)
+				})
+			})
+		},
+	}
+	approve.Flags().StringVar(&approverUserID, "approver-user-id", "", "admin user id performing approval")
+	approve.Flags().StringVar(&role, "role", "admin", "role to assign when creating a new user")
+	approve.Flags().StringVar(&targetUserID, "target-user-id", "", "optional existing user id to link")
+
+	var (
+		denierUserID string
+		reason       string
+	)
+	deny := &cobra.Command{
+		Use:   "deny <token>",
+		Short: "Deny a pending pairing token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(denierUserID) == "" {
+				return fmt.Errorf("--approver-user-id is required")
+			}
+			if strings.TrimSpace(reason) == "" {
+				return fmt.Errorf("--reason is required")
+			}
+			return opts.call(func(ctx context.Context, client *adminclient.Client) error {
+				pairing, err := client.DenyPairing(ctx, strings.TrimSpace(args[0]), denierUserID, reason)
+				if err != nil {
+					return err
+				}
+				return opts.write(cmd.OutOrStdout(), pairing, func(out io.Writer) {
+					fmt.Fprintf(out, "Pairing denied: %s\n", pairing.ID)
+					fmt.Fprintf(out, "Reason: %s\n", pairing.DeniedReason)
+				})
+			})
+		},
+	}
+	deny.Flags().StringVar(&denierUserID, "approver-user-id", "", "admin user id performing denial")
+	deny.Flags().StringVar(&reason, "reason", "", "denial reason")
+
+	cmd.AddCommand(approve, deny)
+	return cmd
+}
+
+func writeTaskTable(out io.Writer, tasks []adminclient.Task) {
+	if len(tasks) == 0 {
+		fmt.Fprintln(out, "No tasks.")
+		return
+	}
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tSTATUS\tKIND\tATTEMPTS\tUPDATED\tTITLE")
+	for _, task := range tasks {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%s\t%s\n", task.ID, task.Status, task.Kind, task.Attempts, formatUnix(task.UpdatedAtUnix), task.Title)
+	}
+	_ = table.Flush()
+}
+
+func writeTaskArtifactTable(out io.Writer, artifacts []adminclient.TaskArtifact) {
+	if len(artifacts) == 0 {
+		fmt.Fprintln(out, "No artifacts.")
+		return
+	}
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tKIND\tSIZE\tTYPE\tPATH")
+	for _, artifact := range artifacts {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\n", artifact.ID, artifact.Kind, artifact.SizeBytes, artifact.MediaType, artifact.Path)
+	}
+	_ = table.Flush()
+}
+
+func writeObjectiveRunTable(out io.Writer, runs []adminclient.ObjectiveRun) {
+	if len(runs) == 0 {
+		fmt.Fprintln(out, "No runs.")
+		return
+	}
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "STARTED\tTRIGGER\tSTATUS\tDURATION\tTASK\tDETAIL")
+	for _, run := range runs {
+		duration := "-"
+		if run.FinishedAtUnix != nil {
+			duration = (time.Duration(run.DurationMs) * time.Millisecond).String()
+		}
+		taskID := run.TaskID
+		if taskID == "" {
+			taskID = "-"
+		}
+		detail := run.Summary
+		if run.Error != "" {
+			detail = run.Error
+		}
+		detail = strings.Join(strings.Fields(detail), " ")
+		if len(detail) > 60 {
+			detail = detail[:57] + "..."
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", formatUnix(run.StartedAtUnix), run.Trigger, run.Status, duration, taskID, detail)
+	}
+	_ = table.Flush()
+}
+
+func writeFiredObjectiveTable(out io.Writer, response adminclient.FireEventResponse) {
+	if len(response.Fired) == 0 {
+		fmt.Fprintf(out, "No objectives listen for %s.\n", response.EventKey)
+		return
+	}
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "OBJECTIVE\tWORKSPACE\tRESULT\tTITLE")
+	for _, objective := range response.Fired {
+		result := "queued " + objective.TaskID
+		switch {
+		case objective.Error != "":
+			result = "failed: " + objective.Error
+		case objective.AlreadyQueued:
+			result = "already queued"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", objective.ObjectiveID, objective.WorkspaceID, result, objective.Title)
+	}
+	_ = table.Flush()
+}
+
+func writeObjectiveTable(out io.Writer, objectives []adminclient.Objective) {
+	if len(objectives) == 0 {
+		fmt.Fprintln(out, "No objectives.")
+		return
+	}
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tSTATE\tTRIGGER\tNEXT RUN\tTITLE")
+	for _, objective := range objectives {
+		next := "-"
+		if objective.NextRunUnix != nil {
+			next = formatUnix(*objective.NextRunUnix)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", objective.ID, objectiveState(objective), objectiveTrigger(objective), next, objective.Title)
+	}
+	_ = table.Flush()
+}
+
+func writeObjectiveSummary(out io.Writer, heading string, objective adminclient.Objective) {
+	fmt.Fprintf(out, "%s: %s\n", heading, objective.ID)
+	fmt.Fprintf(out, "Title: %s\n", objective.Title)
+	fmt.Fprintf(out, "Trigger: %s\n", objectiveTrigger(objective))
+	fmt.Fprintf(out, "State: %s\n", objectiveState(objective))
+	if objective.ReportChangesOnly {
+		fmt.Fprintln(out, "Reports: changes only")
+	}
+	if objective.DigestPeriod != "" {
+		fmt.Fprintf(out, "Digest: %s\n", objective.DigestPeriod)
+	}
+}
+
+func writeApprovalTable(out io.Writer, approvals []adminclient.Approval) {
+	if len(approvals) == 0 {
+		fmt.Fprintln(out, "No approvals.")
+		return
+	}
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tSTATUS\tRISK\tWORKSPACE\tCREATED\tSUMMARY")
+	for _, approval := range approvals {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", approval.ID, approval.Status, approval.RiskLevel, approval.WorkspaceID, formatUnix(approval.CreatedAtUnix), approval.ActionSummary)
+	}
+	_ = table.Flush()
+}
+
+func writeApprovalSummary(out io.Writer, heading string, approval adminclient.Approval) {
+	fmt.Fprintf(out, "%s: %s\n", heading, approval.ID)
+	fmt.Fprintf(out, "Status: %s\n", approval.Status)
+	if approval.ExecutionStatus != "" {
+		fmt.Fprintf(out, "Execution: %s %s\n", approval.ExecutionStatus, approval.ExecutionMessage)
+	}
+	if approval.Message != "" {
+		fmt.Fprintln(out, approval.Message)
+	}
+}
+
+func objectiveState(objective adminclient.Objective) string {
+	if objective.Active {
+		return "active"
+	}
+	return "paused"
+}
+
+func objectiveTrigger(objective adminclient.Objective) string {
+	if objective.TriggerType == "event" {
+		return "event " + objective.EventKey
+	}
+	return objective.CronExpr
+}
+
+func formatUnix(unix int64) string {
+	if unix <= 0 {
+		return "-"
+	}
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+)
+
+func TestNewAdminCommandIncludesExpectedSubcommands(t *testing.T) {
+	cmd := newAdminCommand()
+	for _, path := range [][]string{
+		{"tasks", "list"},
+		{"tasks", "retry"},
+		{"tasks", "cancel"},
+		{"tasks", "artifacts"},
+		{"tasks", "download"},
+		{"objectives", "list"},
+		{"objectives", "create"},
+		{"objectives", "pause"},
+		{"objectives", "resume"},
+		{"objectives", "runs"},
+		{"objectives", "fire"},
+		{"approvals", "list"},
+		{"approvals", "approve"},
+		{"approvals", "deny"},
+		{"pairings", "approve"},
+		{"pairings", "deny"},
+	} {
+		found, _, err := cmd.Find(path)
+		if err != nil || found.Name() != path[1] {
+			t.Fatalf("expected subcommand %q to exist: %v", strings.Join(path, " "), err)
+		}
+	}
+}
+
+func TestObjectiveCreateRequestResolvesTrigger(t *testing.T) {
+	base := adminclient.CreateObjectiveRequest{WorkspaceID: "ws-1", ContextID: "ctx-1", Title: "Digest", Prompt: "Summarize"}
+
+	request, err := objectiveCreateRequest(base, "30m", true)
+	if err != nil {
+		t.Fatalf("interval request: %v", err)
+	}
+	if request.TriggerType != "schedule" || request.CronExpr != "@every 30m" || request.Active == nil || *request.Active {
+		t.Fatalf("unexpected interval request %+v", request)
+	}
+
+	withEvent := base
+	withEvent.EventKey = "deploy.finished"
+	request, err = objectiveCreateRequest(withEvent, "", false)
+	if err != nil || request.TriggerType != "event" || request.Active != nil {
+		t.Fatalf("unexpected event request %+v: %v", request, err)
+	}
+
+	withEvent.CronExpr = "0 8 * * 1"
+	if _, err := objectiveCreateRequest(withEvent, "", false); err == nil {
+		t.Fatal("expected two triggers rejected")
+	}
+	if _, err := objectiveCreateRequest(adminclient.CreateObjectiveRequest{WorkspaceID: "ws-1"}, "30m", false); err == nil || !strings.Contains(err.Error(), "--context-id") {
+		t.Fatalf("expected missing context rejected, got %v", err)
+	}
+}
+
+func TestAdminTasksListPrintsJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tasks" || r.URL.Query().Get("workspace_id") != "ws-1" || r.URL.Query().Get("status") != "failed" {
+			t.Fatalf("unexpected request %s", r.URL.String())
+		}
+		_ = json.NewEncoder(w).Encode(adminclient.ListTasksResponse{
+			Items: []adminclient.Task{{ID: "task-1", Status: "failed", Title: "Nightly report"}},
+			Count: 1,
+		})
+	}))
+	defer server.Close()
+	t.Setenv("AGENT_RUNTIME_ADMIN_API_URL", server.URL)
+
+	var out strings.Builder
+	cmd := newAdminCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"tasks", "list", "--workspace-id", "ws-1", "--status", "failed", "--json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	var tasks []adminclient.Task
+	if err := json.Unmarshal([]byte(out.String()), &tasks); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", out.String(), err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "task-1" {
+		t.Fatalf("unexpected tasks %+v", tasks)
+	}
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func newAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Read and export the agent audit trail",
+	}
+	cmd.AddCommand(newAuditExportCommand())
+	cmd.AddCommand(newAuditVerifyCommand())
+	return cmd
+}
+
+func newAuditExportCommand() *cobra.Command {
+	var (
+		query      adminclient.AuditQuery
+		format     string
+		outputPath string
+		timeoutSec int
+	)
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export audit events as NDJSON or CSV, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format != "ndjson" && format != "csv" {
+				return fmt.Errorf("--format must be ndjson or csv")
+			}
+			client, err := newAdminClientFromEnv(timeoutSec)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), boundedTimeout(timeoutSec))
+			defer cancel()
+
+			var out io.Writer = cmd.OutOrStdout()
+			if outputPath = strings.TrimSpace(outputPath); outputPath != "" {
+				file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+				if err != nil {
+					return fmt.Errorf("open output: %w", err)
+				}
+				defer file.Close()
+				out = file
+			}
+			if err := client.ExportAuditEvents(ctx, query, format, out); err != nil {
+				return err
+			}
+			if outputPath != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Audit events written to %s\n", outputPath)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&query.WorkspaceID, "workspace-id", "", "export one workspace (default all)")
+	cmd.Flags().StringVar(&query.ContextID, "context-id", "", "export one context")
+	cmd.Flags().StringVar(&query.UserID, "user-id", "", "only events caused by this connector user")
+	cmd.Flags().StringVar(&query.EventType, "event-type", "", "only this event type, e.g. egress_blocked")
+	cmd.Flags().StringVar(&query.Tool, "tool", "", "only events for this tool")
+	cmd.Flags().BoolVar(&query.BlockedOnly, "blocked", false, "only blocked events")
+	cmd.Flags().StringVar(&query.Since, "since", "", "events at or after this time (unix seconds or RFC 3339)")
+	cmd.Flags().StringVar(&query.Until, "until", "", "events before this time (unix seconds or RFC 3339)")
+	cmd.Flags().StringVar(&format, "format", "ndjson", "ndjson or csv")
+	cmd.Flags().StringVar(&outputPath, "output", "", "write to this file instead of stdout")
+	cmd.Flags().IntVar(&timeoutSec, "timeout-sec", 300, "request timeout in seconds")
+	return cmd
+}
+
+func newAuditVerifyCommand() *cobra.Command {
+	var anchor string
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the audit hash chain for edited, deleted or reordered events",
+		Long: "Walk every audit event in the local database and recompute its hash chain.\n" +
+			"Record the head hash it prints somewhere outside the database; passing it back\n" +
+			"as --anchor later proves no event up to it was removed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromEnv()
+			if _, err := os.Stat(cfg.DBPath); err != nil {
+				return fmt.Errorf("database not found at %s: %w", cfg.DBPath, err)
+			}
+			sqlStore, err := store.New(cfg.DBPath)
+			if err != nil {
+				return err
+			}
+			defer sqlStore.Close()
+
+			report, err := sqlStore.VerifyAgentAuditChain(cmd.Context(), anchor)
+			if err != nil {
+				return err
+			}
+			writeAuditChainReport(cmd.OutOrStdout(), report)
+			if !report.OK() {
+				return fmt.Errorf("audit trail failed verification")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&anchor, "anchor", "", "head hash from an earlier verify that must still be in the chain")
+	return cmd
+}
+
+func writeAuditChainReport(out io.Writer, report store.AgentAuditChainReport) {
+	fmt.Fprintf(out, "Checked %d audit events.\n", report.Checked)
+	if report.HeadSeq > 0 {
+		fmt.Fprintf(out, "Head: %d %s\n", report.HeadSeq, report.HeadHash)
+	}
+	if report.AnchorSeq > 0 {
+		fmt.Fprintf(out, "Anchor found at event %d.\n", report.AnchorSeq)
+	}
+	if report.OK() {
+		fmt.Fprintln(out, "Chain intact.")
+		return
+	}
+	fmt.Fprintf(out, "%d problem(s):\n", len(report.Problems))
+	for _, problem := range report.Problems {
+		if problem.EventID == "" {
+			fmt.Fprintf(out, "- %s\n", problem.Reason)
+			continue
+		}
+		fmt.Fprintf(out, "- event %d (%s): %s\n", problem.Seq, problem.EventID, problem.Reason)
+	}
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestWriteAuditChainReport(t *testing.T) {
+	var out strings.Builder
+	writeAuditChainReport(&out, store.AgentAuditChainReport{Checked: 3, HeadSeq: 3, HeadHash: "abc"})
+	want := "Checked 3 audit events.\nHead: 3 abc\nChain intact.\n"
+	if out.String() != want {
+		t.Fatalf("unexpected report:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	writeAuditChainReport(&out, store.AgentAuditChainReport{
+		Checked:  2,
+		HeadSeq:  3,
+		HeadHash: "abc",
+		Problems: []store.AgentAuditChainProblem{{Seq: 3, EventID: "audit_3", Reason: "event 2 is missing"}},
+	})
+	if !strings.Contains(out.String(), "1 problem(s):\n- event 3 (audit_3): event 2 is missing\n") {
+		t.Fatalf("unexpected report %q", out.String())
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dwizi/agent-runtime/internal/backup"
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func newBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Create or restore runtime state archives",
+	}
+	cmd.AddCommand(newBackupCreateCommand())
+	cmd.AddCommand(newBackupRestoreCommand())
+	return cmd
+}
+
+func newBackupCreateCommand() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Snapshot the database, workspaces, and skills into one archive",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromEnv()
+			if _, err := os.Stat(cfg.DBPath); err != nil {
+				return fmt.Errorf("database not found at %s: %w", cfg.DBPath, err)
+			}
+			if output == "" {
+				output = fmt.Sprintf("agent-runtime-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+			}
+			sqlStore, err := store.New(cfg.DBPath)
+			if err != nil {
+				return err
+			}
+			defer sqlStore.Close()
+			manifest, err := backup.Create(cmd.Context(), backup.CreateOptions{
+				Output:        output,
+				Database:      sqlStore,
+				WorkspaceRoot: cfg.WorkspaceRoot,
+				SkillsRoot:    cfg.SkillsGlobalRoot,
+			})
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Backup written: %s (%d files)\n", output, len(manifest.Files))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "archive path (default agent-runtime-backup-<timestamp>.tar.gz)")
+	return cmd
+}
+
+func newBackupRestoreCommand() *cobra.Command {
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "Verify and restore a backup archive (stop the runtime first)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromEnv()
+			if err := os.MkdirAll(filepath.Dir(cfg.DBPath), 0o755); err != nil {
+				return fmt.Errorf("create db directory: %w", err)
+			}
+			manifest, err := backup.Restore(cmd.Context(), backup.RestoreOptions{
+				Archive:       args[0],
+				DBPath:        cfg.DBPath,
+				WorkspaceRoot: cfg.WorkspaceRoot,
+				SkillsRoot:    cfg.SkillsGlobalRoot,
+				Force:         force,
+			})
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Backup restored: %d files from %s (created %s)\n", len(manifest.Files), args[0], manifest.CreatedAt.Format(time.RFC3339))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite an existing database and workspace files")
+	return cmd
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+)
+
+func newReconcileCommand() *cobra.Command {
+	var (
+		workspaceID string
+		fix         bool
+		timeoutSec  int
+	)
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Report drift between workspace botfiles and live state, optionally fixing it",
+		Long: "Compare each workspace's applied botfile with its live objectives, generated markdown\n" +
+			"and policies. Exits non-zero while drift remains; pass --fix to restore the declared state.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newAdminClientFromEnv(timeoutSec)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), boundedTimeout(timeoutSec))
+			defer cancel()
+
+			response, err := client.ReconcileBotfiles(ctx, workspaceID, fix)
+			if err != nil {
+				return err
+			}
+			writeReconcileReport(cmd.OutOrStdout(), response)
+			if response.Drifted > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("drift detected in %d workspace(s); rerun with --fix to restore the botfile state", response.Drifted)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&workspaceID, "workspace-id", "", "check one workspace (default all workspaces with a botfile)")
+	cmd.Flags().BoolVar(&fix, "fix", false, "restore the declared state where drift is found")
+	cmd.Flags().IntVar(&timeoutSec, "timeout-sec", 120, "request timeout in seconds")
+	return cmd
+}
+
+func writeReconcileReport(out io.Writer, response adminclient.ReconcileBotfilesResponse) {
+	if len(response.Items) == 0 {
+		fmt.Fprintln(out, "No workspace botfiles to reconcile.")
+		return
+	}
+	for _, item := range response.Items {
+		switch {
+		case len(item.Drift) == 0:
+			fmt.Fprintf(out, "%s: in sync\n", item.WorkspaceID)
+			continue
+		case item.Fixed:
+			fmt.Fprintf(out, "%s: fixed %d drift item(s)\n", item.WorkspaceID, len(item.Drift))
+		default:
+			fmt.Fprintf(out, "%s: %d drift item(s)\n", item.WorkspaceID, len(item.Drift))
+		}
+		for _, line := range item.Drift {
+			fmt.Fprintf(out, "  - %s\n", line)
+		}
+	}
+}
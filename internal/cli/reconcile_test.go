@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+)
+
+func TestNewReconcileCommandFlags(t *testing.T) {
+	cmd := newReconcileCommand()
+	for _, name := range []string{"workspace-id", "fix", "timeout-sec"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Fatalf("expected flag %q to exist", name)
+		}
+	}
+}
+
+func TestWriteReconcileReport(t *testing.T) {
+	var out strings.Builder
+	writeReconcileReport(&out, adminclient.ReconcileBotfilesResponse{
+		Items: []adminclient.BotfileDrift{
+			{WorkspaceID: "ws-1"},
+			{WorkspaceID: "ws-2", Drift: []string{"objectives: missing nightly-digest"}},
+			{WorkspaceID: "ws-3", Drift: []string{"persona: context/SOUL.md is missing"}, Fixed: true},
+		},
+	})
+	want := "ws-1: in sync\n" +
+		"ws-2: 1 drift item(s)\n  - objectives: missing nightly-digest\n" +
+		"ws-3: fixed 1 drift item(s)\n  - persona: context/SOUL.md is missing\n"
+	if out.String() != want {
+		t.Fatalf("unexpected report:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dwizi/agent-runtime/internal/replay"
+)
+
+func newReplayCommand() *cobra.Command {
+	var (
+		jsonMode bool
+		maxTurns int
+		verbose  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "replay <scenario.yaml|chat-log.md>",
+		Short: "Replay a scenario or recorded chat offline and check routing and tool calls",
+		Long: "Feeds every turn through the gateway in a throwaway store with a scripted model,\n" +
+			"then checks the route, tool calls, reply and queued tasks of each turn.\n" +
+			"A YAML scenario scripts the model and states the expectations; a chat log\n" +
+			"replays the recorded tool calls and final replies and expects the same tools\n" +
+			"with the same statuses. Exits non-zero when any expectation fails.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scenario, err := loadReplayScenario(args[0])
+			if err != nil {
+				return err
+			}
+			if maxTurns > 0 && len(scenario.Turns) > maxTurns {
+				scenario.Turns = scenario.Turns[:maxTurns]
+			}
+			dir, err := os.MkdirTemp("", "agent-runtime-replay-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			var logger *slog.Logger
+			if verbose {
+				logger = slog.New(slog.NewTextHandler(cmd.ErrOrStderr(), nil))
+			}
+			report, err := replay.Run(cmd.Context(), scenario, dir, logger)
+			if err != nil {
+				return err
+			}
+			if jsonMode {
+				payload, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+			} else {
+				printReplayReport(cmd.OutOrStdout(), report)
+			}
+			if report.Failures > 0 {
+				return fmt.Errorf("replay %s: %d failed expectation(s)", report.Scenario, report.Failures)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonMode, "json", false, "print the report as JSON")
+	cmd.Flags().IntVar(&maxTurns, "max-turns", 0, "max turns to replay (0 means all)")
+	cmd.Flags().BoolVar(&verbose, "verbose", false, "print runtime logs to stderr")
+	return cmd
+}
+
+func loadReplayScenario(path string) (replay.Scenario, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		parsed, err := parseChatLogFile(path)
+		if err != nil {
+			return replay.Scenario{}, err
+		}
+		scenario := scenarioFromChatLog(parsed)
+		if len(scenario.Turns) == 0 {
+			return replay.Scenario{}, fmt.Errorf("no inbound turns found in %s", path)
+		}
+		return scenario, nil
+	default:
+		return replay.LoadScenario(path)
+	}
+}
+
+// scenarioFromChatLog turns a recorded conversation into a scenario whose
+// model repeats the recorded tool calls and final reply of every turn. The
+// recorded tools and statuses become the expectations, so a change to
+// policies or tools that alters their outcome shows up as a failure.
+func scenarioFromChatLog(parsed parsedChatLog) replay.Scenario {
+	scenario := replay.Scenario{
+		Name:        firstNonEmpty(parsed.SourcePath, parsed.ExternalID),
+		Connector:   parsed.Connector,
+		ExternalID:  parsed.ExternalID,
+		DisplayName: parsed.DisplayName,
+	}
+	for _, recorded := range buildChatTurns(parsed) {
+		userText := strings.TrimSpace(recorded.Inbound.Text)
+		if userText == "" {
+			continue
+		}
+		turn := replay.Turn{User: userText}
+		for _, tool := range recorded.Tools {
+			name, status, args := recordedToolCall(tool.Text)
+			if name == "" {
+				continue
+			}
+			call, _ := json.Marshal(map[string]any{"tool": name, "args": args})
+			turn.Model = append(turn.Model, string(call))
+			turn.Expect.Tools = append(turn.Expect.Tools, name)
+			turn.Expect.ToolStatus = append(turn.Expect.ToolStatus, status)
+		}
+		if reply := firstTurnOutbound(recorded); reply != "" {
+			final, _ := json.Marshal(map[string]any{"final": reply, "confidence": 1})
+			turn.Model = append(turn.Model, string(final))
+		}
+		if len(turn.Expect.Tools) > 0 {
+			turn.Expect.Route = replay.RouteAgent
+		}
+		scenario.Turns = append(scenario.Turns, turn)
+	}
+	return scenario
+}
+
+// recordedToolCall reads a tool entry of a chat log. Arguments that were
+// truncated or are not JSON replay as an empty object.
+func recordedToolCall(text string) (string, string, json.RawMessage) {
+	name, status := "", ""
+	args := json.RawMessage("{}")
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "- tool:"):
+			name = extractBacktickOrRemainder(trimmed, "- tool:")
+		case strings.HasPrefix(trimmed, "- status:"):
+			status = extractBacktickOrRemainder(trimmed, "- status:")
+		case strings.HasPrefix(trimmed, "- args:"):
+			value := extractBacktickOrRemainder(trimmed, "- args:")
+			if json.Valid([]byte(value)) && strings.HasPrefix(value, "{") {
+				args = json.RawMessage(value)
+			}
+		}
+	}
+	return strings.ToLower(name), strings.ToLower(status), args
+}
+
+func printReplayReport(out io.Writer, report replay.Report) {
+	fmt.Fprintf(out, "Replay %s: %d turn(s)\n", report.Scenario, len(report.Turns))
+	for _, turn := range report.Turns {
+		verdict := "ok"
+		if len(turn.Failures) > 0 {
+			verdict = "FAIL"
+		}
+		fmt.Fprintf(out, "[%d] %s user: %s\n", turn.Index, verdict, compactLine(turn.User, 200))
+		fmt.Fprintf(out, "    route: %s", turn.Route)
+		if len(turn.ToolCalls) > 0 {
+			calls := make([]string, 0, len(turn.ToolCalls))
+			for _, call := range turn.ToolCalls {
+				calls = append(calls, call.Name+"="+call.Status)
+			}
+			fmt.Fprintf(out, "  tools: %s", strings.Join(calls, ", "))
+		}
+		for _, task := range turn.Tasks {
+			fmt.Fprintf(out, "  task: %s (%s/%s/%s)", compactLine(task.Title, 60), task.RouteClass, task.Priority, task.Lane)
+		}
+		fmt.Fprintln(out)
+		reply := turn.Reply
+		if reply == "" {
+			reply = "(no reply)"
+		}
+		fmt.Fprintf(out, "    agent: %s\n", compactLine(reply, 200))
+		for _, failure := range turn.Failures {
+			fmt.Fprintf(out, "    - %s\n", failure)
+		}
+	}
+	fmt.Fprintf(out, "Replay complete: turns=%d failures=%d\n", len(report.Turns), report.Failures)
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/replay"
+)
+
+func TestScenarioFromChatLogReplaysRecordedToolCalls(t *testing.T) {
+	raw := strings.Join([]string{
+		"# Chat Log",
+		"",
+		"- connector: `telegram`",
+		"- external_id: `123`",
+		"",
+		"## 2026-02-16T20:10:27Z `INBOUND`",
+		"- direction: `inbound`",
+		"",
+		"the nightly report failed",
+		"",
+		"## 2026-02-16T20:10:30Z `TOOL`",
+		"- direction: `tool`",
+		"",
+		"Tool call",
+		"- tool: `create_task`",
+		"- status: `succeeded`",
+		"- args: `{\"title\":\"Investigate report\",\"description\":\"check the logs\",\"priority\":\"p2\"}`",
+		"",
+		"## 2026-02-16T20:10:33Z `OUTBOUND`",
+		"- direction: `outbound`",
+		"",
+		"I queued a task to investigate.",
+		"",
+	}, "\n")
+	parsed, err := parseChatLogContent(raw)
+	if err != nil {
+		t.Fatalf("parse chat log: %v", err)
+	}
+
+	scenario := scenarioFromChatLog(parsed)
+	if len(scenario.Turns) != 1 {
+		t.Fatalf("expected one turn, got %+v", scenario.Turns)
+	}
+	turn := scenario.Turns[0]
+	if len(turn.Model) != 2 || !strings.Contains(turn.Model[0], `"tool":"create_task"`) || !strings.Contains(turn.Model[1], "queued a task") {
+		t.Fatalf("unexpected scripted model %q", turn.Model)
+	}
+	if turn.Expect.Route != replay.RouteAgent || turn.Expect.ToolStatus[0] != "succeeded" {
+		t.Fatalf("unexpected expectations %+v", turn.Expect)
+	}
+
+	report, err := replay.Run(context.Background(), scenario, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Failures != 0 {
+		t.Fatalf("expected the recorded turn to replay cleanly, got %+v", report.Turns)
+	}
+}
@@ -28,6 +28,13 @@ func NewRoot(logger *slog.Logger) *cobra.Command {
 	root.AddCommand(newQMDSidecarCommand(logger))
 	root.AddCommand(newTUICommand(logger))
 	root.AddCommand(newChatCommand(logger))
+	root.AddCommand(newSecretsCommand())
+	root.AddCommand(newBackupCommand())
+	root.AddCommand(newReconcileCommand())
+	root.AddCommand(newAuditCommand())
+	root.AddCommand(newAdminCommand())
+	root.AddCommand(newReplayCommand())
+	root.AddCommand(newStateAtCommand())
 	root.AddCommand(newVersionCommand())
 
 	return root
@@ -61,11 +68,15 @@ func newQMDSidecarCommand(logger *slog.Logger) *cobra.Command {
 }
 
 func newServeCommand(logger *slog.Logger) *cobra.Command {
-	return &cobra.Command{
+	var dryRun bool
+	cmd := &cobra.Command{
 		Use:   "serve",
 		Short: "Run gateway and orchestrator services",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromEnv()
+			if dryRun {
+				cfg.DryRun = true
+			}
 			runtime, err := app.New(cfg, logger)
 			if err != nil {
 				return err
@@ -77,6 +88,8 @@ func newServeCommand(logger *slog.Logger) *cobra.Command {
 			return runtime.Run(ctx)
 		},
 	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "log actions and outbound side effects instead of running them (same as AGENT_RUNTIME_DRY_RUN=true)")
+	return cmd
 }
 
 func newTUICommand(logger *slog.Logger) *cobra.Command {
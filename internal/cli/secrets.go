@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/secrets"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func newSecretsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage encrypted secrets stored in the runtime database",
+	}
+	cmd.AddCommand(newSecretsSetCommand())
+	cmd.AddCommand(newSecretsGetCommand())
+	cmd.AddCommand(newSecretsListCommand())
+	cmd.AddCommand(newSecretsDeleteCommand())
+	return cmd
+}
+
+func newSecretsSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name> [value]",
+		Short: "Encrypt and store a secret (reads the value from stdin when omitted)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value := ""
+			if len(args) == 2 {
+				value = args[1]
+			} else {
+				content, err := io.ReadAll(io.LimitReader(cmd.InOrStdin(), 1<<20))
+				if err != nil {
+					return fmt.Errorf("read secret value: %w", err)
+				}
+				value = strings.TrimRight(string(content), "\r\n")
+			}
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("secret value is empty")
+			}
+			return withSecretsVault(cmd.Context(), func(vault *secrets.Vault) error {
+				if err := vault.Set(cmd.Context(), args[0], value); err != nil {
+					return err
+				}
+				cmd.Printf("Secret stored: %s\n", strings.ToUpper(strings.TrimSpace(args[0])))
+				return nil
+			})
+		},
+	}
+}
+
+func newSecretsGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <name>",
+		Short: "Decrypt and print a secret",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withSecretsVault(cmd.Context(), func(vault *secrets.Vault) error {
+				value, err := vault.Get(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				cmd.Println(value)
+				return nil
+			})
+		},
+	}
+}
+
+func newSecretsListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List stored secret names",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withSecretsVault(cmd.Context(), func(vault *secrets.Vault) error {
+				names, err := vault.Names(cmd.Context())
+				if err != nil {
+					return err
+				}
+				if len(names) == 0 {
+					cmd.Println("No secrets stored.")
+					return nil
+				}
+				for _, name := range names {
+					cmd.Println(name)
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func newSecretsDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a stored secret",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withSecretsVault(cmd.Context(), func(vault *secrets.Vault) error {
+				if err := vault.Delete(cmd.Context(), args[0]); err != nil {
+					return err
+				}
+				cmd.Printf("Secret deleted: %s\n", strings.ToUpper(strings.TrimSpace(args[0])))
+				return nil
+			})
+		},
+	}
+}
+
+func withSecretsVault(ctx context.Context, run func(vault *secrets.Vault) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg := config.FromEnv()
+	masterKey, err := secrets.LoadMasterKey(cfg.SecretsMasterKey, cfg.SecretsKeyFile)
+	if err != nil {
+		return fmt.Errorf("%w: set AGENT_RUNTIME_SECRETS_MASTER_KEY or AGENT_RUNTIME_SECRETS_KEY_FILE", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.DBPath), 0o755); err != nil {
+		return fmt.Errorf("create db directory: %w", err)
+	}
+	sqlStore, err := store.New(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer sqlStore.Close()
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		return err
+	}
+	vault, err := secrets.New(sqlStore, masterKey)
+	if err != nil {
+		return err
+	}
+	return run(vault)
+}
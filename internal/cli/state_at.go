@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func newStateAtCommand() *cobra.Command {
+	var (
+		table       string
+		workspaceID string
+		rowID       string
+		history     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "state-at <time>",
+		Short: "Show tasks, approvals or objectives as they were at a past time",
+		Long: "Reconstruct rows from the change log (AGENT_RUNTIME_CHANGE_LOG_ENABLED).\n" +
+			"<time> is RFC3339 (2026-10-16T09:00:00Z), a date (2026-10-16), a duration ago (36h) or now.\n" +
+			"With --history and --id, list every change to that row up to <time> instead.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			at, err := parseStateTime(args[0], time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			if history && strings.TrimSpace(rowID) == "" {
+				return fmt.Errorf("--history needs --id")
+			}
+			cfg := config.FromEnv()
+			if _, err := os.Stat(cfg.DBPath); err != nil {
+				return fmt.Errorf("database not found at %s: %w", cfg.DBPath, err)
+			}
+			sqlStore, err := store.New(cfg.DBPath)
+			if err != nil {
+				return err
+			}
+			defer sqlStore.Close()
+
+			if history {
+				changes, err := sqlStore.ListChanges(cmd.Context(), store.ListChangesInput{
+					Table:       table,
+					RowID:       rowID,
+					WorkspaceID: workspaceID,
+					Until:       at,
+					Limit:       1000,
+				})
+				if err != nil {
+					return err
+				}
+				writeChangeHistory(cmd.OutOrStdout(), changes)
+				return nil
+			}
+			rows, err := sqlStore.StateAsOf(cmd.Context(), table, workspaceID, rowID, at)
+			if err != nil {
+				return err
+			}
+			writeStateReport(cmd.OutOrStdout(), table, at, rows)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&table, "table", "tasks", "table to reconstruct: "+strings.Join(store.ChangeLogTables, ", "))
+	cmd.Flags().StringVar(&workspaceID, "workspace-id", "", "only rows of this workspace")
+	cmd.Flags().StringVar(&rowID, "id", "", "only this row")
+	cmd.Flags().BoolVar(&history, "history", false, "list the row's changes up to <time> (requires --id)")
+	return cmd
+}
+
+func parseStateTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "now") {
+		return now, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	if parsed, err := time.Parse("2006-01-02", value); err == nil {
+		return parsed.UTC(), nil
+	}
+	if ago, err := time.ParseDuration(strings.TrimSuffix(value, " ago")); err == nil && ago >= 0 {
+		return now.Add(-ago), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC3339, YYYY-MM-DD, a duration such as 36h or now", value)
+}
+
+func writeStateReport(out io.Writer, table string, at time.Time, rows []store.ChangeRecord) {
+	if len(rows) == 0 {
+		fmt.Fprintf(out, "No %s recorded in the change log as of %s.\n", table, at.Format(time.RFC3339))
+		return
+	}
+	fmt.Fprintf(out, "%s as of %s:\n", table, at.Format(time.RFC3339))
+	for _, row := range rows {
+		fmt.Fprintf(out, "%s (%s, last %s at %s)\n", row.RowID, row.WorkspaceID, row.Operation, row.ChangedAt.Format(time.RFC3339))
+		keys := make([]string, 0, len(row.Row))
+		for key := range row.Row {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if value := formatStateValue(row.Row[key]); value != "" {
+				fmt.Fprintf(out, "  %s: %s\n", key, value)
+			}
+		}
+	}
+}
+
+// writeChangeHistory prints one line per change with the columns it
+// altered, so a reroute reads as "assigned_lane: support -> operations".
+func writeChangeHistory(out io.Writer, changes []store.ChangeRecord) {
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "No changes recorded for this row.")
+		return
+	}
+	var previous map[string]any
+	for _, change := range changes {
+		line := fmt.Sprintf("%s %s", change.ChangedAt.Format(time.RFC3339), change.Operation)
+		if change.Operation == "update" && previous != nil {
+			keys := []string{}
+			for key, value := range change.Row {
+				if formatStateValue(previous[key]) != formatStateValue(value) {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			diffs := make([]string, 0, len(keys))
+			for _, key := range keys {
+				diffs = append(diffs, fmt.Sprintf("%s: %s -> %s", key, quoteStateValue(previous[key]), quoteStateValue(change.Row[key])))
+			}
+			if len(diffs) > 0 {
+				line += " " + strings.Join(diffs, ", ")
+			}
+		}
+		fmt.Fprintln(out, line)
+		previous = change.Row
+	}
+}
+
+func formatStateValue(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		if typed == float64(int64(typed)) {
+			return fmt.Sprintf("%d", int64(typed))
+		}
+		return fmt.Sprintf("%g", typed)
+	default:
+		return strings.TrimSpace(fmt.Sprintf("%v", typed))
+	}
+}
+
+func quoteStateValue(value any) string {
+	formatted := formatStateValue(value)
+	if formatted == "" {
+		return "(empty)"
+	}
+	return formatted
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestParseStateTime(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	for value, want := range map[string]time.Time{
+		"2026-10-16T09:30:00Z": time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		"2026-10-16":           time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		"36h":                  now.Add(-36 * time.Hour),
+		"90m ago":              now.Add(-90 * time.Minute),
+		"now":                  now,
+	} {
+		got, err := parseStateTime(value, now)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("parse %q: got %v %v, want %v", value, got, err, want)
+		}
+	}
+	if _, err := parseStateTime("yesterday", now); err == nil {
+		t.Fatal("expected invalid time to be rejected")
+	}
+}
+
+func TestWriteChangeHistoryShowsChangedColumns(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	var out strings.Builder
+	writeChangeHistory(&out, []store.ChangeRecord{
+		{Operation: "insert", ChangedAt: at, Row: map[string]any{"assigned_lane": "support", "revision": float64(1), "priority": nil}},
+		{Operation: "update", ChangedAt: at.Add(time.Minute), Row: map[string]any{"assigned_lane": "operations", "revision": float64(2), "priority": "p1"}},
+	})
+	want := "2026-10-16T09:00:00Z insert\n" +
+		"2026-10-16T09:01:00Z update assigned_lane: support -> operations, priority: (empty) -> p1, revision: 1 -> 2\n"
+	if out.String() != want {
+		t.Fatalf("unexpected history:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestWriteStateReportListsFields(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	var out strings.Builder
+	writeStateReport(&out, "tasks", at, []store.ChangeRecord{
+		{RowID: "task-1", WorkspaceID: "ws-1", Operation: "update", ChangedAt: at, Row: map[string]any{"status": "queued", "error_message": nil}},
+	})
+	want := "tasks as of 2026-10-16T09:00:00Z:\n" +
+		"task-1 (ws-1, last update at 2026-10-16T09:00:00Z)\n" +
+		"  status: queued\n"
+	if out.String() != want {
+		t.Fatalf("unexpected report:\n%s\nwant:\n%s", out.String(), want)
+	}
+	out.Reset()
+	writeStateReport(&out, "objectives", at, nil)
+	if !strings.Contains(out.String(), "No objectives recorded") {
+		t.Fatalf("unexpected empty report %q", out.String())
+	}
+}
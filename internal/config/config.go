@@ -13,7 +13,21 @@ type Config struct {
 	DataDir                          string
 	DBPath                           string
 	WorkspaceRoot                    string
+	DryRun                           bool
 	DefaultConcurrency               int
+	WorkerPoolMin                    int
+	WorkerPoolMax                    int
+	WorkerAutoscaleEnabled           bool
+	WorkerAutoscaleIntervalSec       int
+	TaskRetryMaxAttempts             int
+	TaskRetryBackoffSec              int
+	TaskRetryMaxBackoffSec           int
+	TaskRetryJitter                  float64
+	TaskRetryPolicies                string
+	TaskLeaseEnabled                 bool
+	InstanceID                       string
+	TaskLeaseSec                     int
+	TaskQueuePollSec                 int
 	QMDBinary                        string
 	QMDSidecarURL                    string
 	QMDSidecarAddr                   string
@@ -28,6 +42,7 @@ type Config struct {
 	QMDQueryTimeoutSec               int
 	QMDAutoEmbed                     bool
 	ObjectivePollSec                 int
+	ObjectiveAutoPauseAfter          int
 	TaskRecoveryRunningStaleSec      int
 	HeartbeatEnabled                 bool
 	HeartbeatIntervalSec             int
@@ -35,11 +50,68 @@ type Config struct {
 	HeartbeatNotifyAdmin             bool
 	TriageEnabled                    bool
 	TriageNotifyAdmin                bool
+	IntentClassifierEnabled          bool
+	IntentMinConfidence              float64
+	SpamFilterEnabled                bool
+	SpamThreshold                    float64
+	RateLimitEnabled                 bool
+	RedactionEnabled                 bool
+	SecretScanMode                   string
+	RateLimitUserPerMinute           float64
+	RateLimitUserBurst               int
+	RateLimitContextPerMinute        float64
+	RateLimitContextBurst            int
+	EgressAllowDomainsCSV            string
+	EgressDenyDomainsCSV             string
+	EgressAllowCIDRsCSV              string
+	EgressDenyCIDRsCSV               string
+	EgressAllowPortsCSV              string
+	EgressDenyPortsCSV               string
+	EgressAllowPrivate               bool
+	EventSinksCSV                    string
+	EventTypesCSV                    string
+	EventWebhookSecret               string
+	EventNATSSubjectPrefix           string
+	EventBufferSize                  int
 	TaskNotifyPolicy                 string
 	TaskNotifySuccessPolicy          string
 	TaskNotifyFailurePolicy          string
+	TaskProgressNotifyEnabled        bool
+	TaskProgressNotifyIntervalSec    int
+	ArtifactLinkBaseURL              string
+	ArtifactLinkSecret               string
+	ArtifactLinkTTLHours             int
+	OutboxRetrySec                   int
+	OutboxMaxAgeHours                int
+	ApprovalNotifyAdmin              bool
+	ApprovalExpiryEnabled            bool
+	ApprovalTTLMinutes               int
+	ApprovalTTLByType                string
+	TwoPersonRuleEnabled             bool
+	TwoPersonApprovals               int
+	TwoPersonInternalEmailDomains    string
+	TwoPersonActionTypes             string
+	ActionRiskEnabled                bool
+	ActionRiskLLMEnabled             bool
+	ActionRiskTrustedDomains         string
+	ActionRiskLargePayloadBytes      int
+	StatusPageEnabled                bool
+	StatusPageWorkspaces             string
+	StatusPageEndpoints              string
+	StatusPageIntervalMinutes        int
+	StatusPageWindowDays             int
+	StatusPageTitle                  string
+	StatusPageDir                    string
+	StatusPageS3Bucket               string
+	StatusPageS3Region               string
+	StatusPageS3Prefix               string
+	StatusPageS3Endpoint             string
+	StatusPageS3AccessKeyID          string
+	StatusPageS3SecretAccessKey      string
 	AgentSensitiveApprovalTTLSeconds int
 	CommandSyncEnabled               bool
+	SecretsMasterKey                 string
+	SecretsKeyFile                   string
 
 	DiscordToken              string
 	DiscordAPI                string
@@ -60,11 +132,14 @@ type Config struct {
 	IMAPPollSeconds           int
 	IMAPTLSSkipVerify         bool
 
-	LLMProvider   string // openai | anthropic
-	LLMBaseURL    string
-	LLMAPIKey     string
-	LLMModel      string
-	LLMTimeoutSec int
+	LLMProvider             string // openai | anthropic
+	LLMBaseURL              string
+	LLMAPIKey               string
+	LLMModel                string
+	LLMTimeoutSec           int
+	LLMDownAfterFailures    int
+	LLMProbeIntervalSeconds int
+	LLMVisionEnabled        bool
 
 	SMTPHost                           string
 	SMTPPort                           int
@@ -78,12 +153,70 @@ type Config struct {
 	MCPWorkspaceConfigRelPath          string
 	MCPRefreshSeconds                  int
 	MCPHTTPTimeoutSec                  int
+	ToolPluginsDir                     string
+	ToolPluginTimeoutSec               int
+	GitHubAppID                        int
+	GitHubAppPrivateKey                string
+	GitHubAppPrivateKeyFile            string
+	GitHubAPIBase                      string
+	IssueSyncProvider                  string
+	JiraBaseURL                        string
+	JiraEmail                          string
+	JiraAPIToken                       string
+	JiraProjectKey                     string
+	JiraIssueType                      string
+	LinearAPIKey                       string
+	LinearTeamID                       string
+	CalendarProvider                   string
+	CalDAVURL                          string
+	CalDAVUsername                     string
+	CalDAVPassword                     string
+	GoogleCalendarID                   string
+	GoogleClientID                     string
+	GoogleClientSecret                 string
+	GoogleRefreshToken                 string
+	BrowserCDPURL                      string
+	BrowserTimeoutSec                  int
+	TTSProvider                        string
+	TTSAPIKey                          string
+	TTSBaseURL                         string
+	TTSModel                           string
+	TTSVoice                           string
+	AttachmentMaxMB                    int
+	AttachmentPDFToTextBinary          string
+	AttachmentOCRBinary                string
+	AttachmentExtractTimeoutSec        int
 	SandboxEnabled                     bool
 	SandboxAllowedCommandsCSV          string
 	SandboxRunnerCommand               string
 	SandboxRunnerArgs                  string
 	SandboxTimeoutSec                  int
 	SandboxMaxOutputBytes              int
+	SandboxDockerImage                 string
+	SandboxDockerBinary                string
+	SandboxDockerRuntime               string
+	SandboxDockerNetwork               string
+	SandboxDockerCPUs                  string
+	SandboxDockerMemory                string
+	SandboxDockerPidsLimit             int
+	KubernetesJobImage                 string
+	KubernetesAPIServer                string
+	KubernetesToken                    string
+	KubernetesCAFile                   string
+	KubernetesNamespacePrefix          string
+	KubernetesCPULimit                 string
+	KubernetesMemoryLimit              string
+	KubernetesQuotaCPU                 string
+	KubernetesQuotaMemory              string
+	KubernetesQuotaPods                int
+	KubernetesJobTimeoutSec            int
+	SSHHosts                           string
+	SSHBinary                          string
+	SSHKeyFile                         string
+	SSHKnownHostsFile                  string
+	SSHUser                            string
+	SSHTimeoutSec                      int
+	SSHMaxOutputBytes                  int
 	LLMEnabled                         bool
 	LLMAllowDM                         bool
 	LLMRequireMentionInGroups          bool
@@ -104,6 +237,9 @@ type Config struct {
 	LLMGroundingSummaryRefreshTurns    int
 	LLMGroundingSummaryMaxItems        int
 	LLMGroundingSummarySourceMaxLines  int
+	LLMGroundingHybrid                 bool
+	LLMGroundingRerank                 bool
+	LLMGroundingRerankCandidates       int
 	LLMAdminSystemPrompt               string
 	LLMPublicSystemPrompt              string
 	AgentMaxTurnDurationSec            int
@@ -115,6 +251,17 @@ type Config struct {
 	AgentAutonomousMaxTasksPerHour     int
 	AgentAutonomousMaxTasksPerDay      int
 	AgentAutonomousMinConfidence       float64
+	AgentPlannerEnabled                bool
+	AgentPlannerMaxSteps               int
+	ToolTimeoutSec                     int
+	ToolMaxRetries                     int
+	ToolRetryBackoffMS                 int
+	ToolTimeoutsCSV                    string
+	ToolRetriesCSV                     string
+	QuotaTasksPerDay                   int
+	QuotaObjectives                    int
+	QuotaActionsPerDay                 int
+	QuotaTokensPerMonth                int
 	SoulGlobalFile                     string
 	SoulWorkspaceRelPath               string
 	SoulContextRelPath                 string
@@ -123,6 +270,27 @@ type Config struct {
 	SystemPromptContextPath            string
 	ReasoningPromptFile                string
 	SkillsGlobalRoot                   string
+	SkillsMaxTokens                    int
+	SkillsEmbeddingModel               string
+	SkillReviewEnabled                 bool
+	SkillReviewStaleDays               int
+	SkillReviewIntervalHours           int
+	MemoryCompactionEnabled            bool
+	MemoryCompactionIntervalHours      int
+	MemoryCompactionKeepEntries        int
+	MemoryCompactionMinAgeHours        int
+	MemoryCompactionMaxSections        int
+	ApprovalDigestEnabled              bool
+	ApprovalDigestIntervalHours        int
+	ApprovalDigestStuckTaskMinutes     int
+	QueueBackpressureEnabled           bool
+	QueueBackpressureThreshold         int
+	QueueBackpressureNotifyAdmin       bool
+	ChangeLogEnabled                   bool
+	ChangeLogRetentionDays             int
+	BotfileReconcileIntervalMinutes    int
+	BotfileReconcileFix                bool
+	SharedKnowledgeWorkspace           string
 
 	PublicHost string
 	AdminHost  string
@@ -133,9 +301,18 @@ type Config struct {
 	AdminTLSCAFile      string
 	AdminTLSCertFile    string
 	AdminTLSKeyFile     string
+	// AdminActingUser is the runtime user the CLI and TUI act for on the
+	// admin API; empty acts as the operator.
+	AdminActingUser string
+	// AdminAPIRequireActingUser makes the admin API refuse requests that do
+	// not name an acting user, so every call is checked against roles.
+	AdminAPIRequireActingUser bool
 
 	TUIApproverUserID string
 	TUIApprovalRole   string
+	// TUIProfiles are extra admin endpoints the TUI can switch to, named in
+	// AGENT_RUNTIME_TUI_PROFILES.
+	TUIProfiles []TUIProfile
 }
 
 func FromEnv() Config {
@@ -149,7 +326,21 @@ func FromEnv() Config {
 		DataDir:                          dataDir,
 		DBPath:                           dbPath,
 		WorkspaceRoot:                    workspaceRoot,
+		DryRun:                           boolOrDefault("AGENT_RUNTIME_DRY_RUN", false),
 		DefaultConcurrency:               intOrDefault("AGENT_RUNTIME_DEFAULT_CONCURRENCY", 5),
+		WorkerPoolMin:                    intOrDefault("AGENT_RUNTIME_WORKER_POOL_MIN", 0),
+		WorkerPoolMax:                    intOrDefault("AGENT_RUNTIME_WORKER_POOL_MAX", 0),
+		WorkerAutoscaleEnabled:           boolOrDefault("AGENT_RUNTIME_WORKER_AUTOSCALE_ENABLED", false),
+		WorkerAutoscaleIntervalSec:       intOrDefault("AGENT_RUNTIME_WORKER_AUTOSCALE_INTERVAL_SECONDS", 15),
+		TaskRetryMaxAttempts:             intOrDefault("AGENT_RUNTIME_TASK_RETRY_MAX_ATTEMPTS", 1),
+		TaskRetryBackoffSec:              intOrDefault("AGENT_RUNTIME_TASK_RETRY_BACKOFF_SECONDS", 30),
+		TaskRetryMaxBackoffSec:           intOrDefault("AGENT_RUNTIME_TASK_RETRY_MAX_BACKOFF_SECONDS", 900),
+		TaskRetryJitter:                  floatOrDefault("AGENT_RUNTIME_TASK_RETRY_JITTER", 0.2),
+		TaskRetryPolicies:                strings.TrimSpace(os.Getenv("AGENT_RUNTIME_TASK_RETRY_POLICIES")),
+		TaskLeaseEnabled:                 boolOrDefault("AGENT_RUNTIME_TASK_LEASE_ENABLED", false),
+		InstanceID:                       strings.TrimSpace(os.Getenv("AGENT_RUNTIME_INSTANCE_ID")),
+		TaskLeaseSec:                     intOrDefault("AGENT_RUNTIME_TASK_LEASE_SECONDS", 60),
+		TaskQueuePollSec:                 intOrDefault("AGENT_RUNTIME_TASK_QUEUE_POLL_SECONDS", 5),
 		QMDBinary:                        stringOrDefault("AGENT_RUNTIME_QMD_BINARY", "qmd"),
 		QMDSidecarURL:                    strings.TrimSpace(os.Getenv("AGENT_RUNTIME_QMD_SIDECAR_URL")),
 		QMDSidecarAddr:                   stringOrDefault("AGENT_RUNTIME_QMD_SIDECAR_ADDR", ":8091"),
@@ -164,6 +355,7 @@ func FromEnv() Config {
 		QMDQueryTimeoutSec:               intOrDefault("AGENT_RUNTIME_QMD_QUERY_TIMEOUT_SECONDS", 30),
 		QMDAutoEmbed:                     boolOrDefault("AGENT_RUNTIME_QMD_AUTO_EMBED", true),
 		ObjectivePollSec:                 intOrDefault("AGENT_RUNTIME_OBJECTIVE_POLL_SECONDS", 15),
+		ObjectiveAutoPauseAfter:          intOrDefault("AGENT_RUNTIME_OBJECTIVE_AUTO_PAUSE_AFTER", 5),
 		TaskRecoveryRunningStaleSec:      intOrDefault("AGENT_RUNTIME_TASK_RECOVERY_RUNNING_STALE_SECONDS", 600),
 		HeartbeatEnabled:                 boolOrDefault("AGENT_RUNTIME_HEARTBEAT_ENABLED", true),
 		HeartbeatIntervalSec:             intOrDefault("AGENT_RUNTIME_HEARTBEAT_INTERVAL_SECONDS", 30),
@@ -171,11 +363,68 @@ func FromEnv() Config {
 		HeartbeatNotifyAdmin:             boolOrDefault("AGENT_RUNTIME_HEARTBEAT_NOTIFY_ADMIN", true),
 		TriageEnabled:                    boolOrDefault("AGENT_RUNTIME_TRIAGE_ENABLED", true),
 		TriageNotifyAdmin:                boolOrDefault("AGENT_RUNTIME_TRIAGE_NOTIFY_ADMIN", true),
+		IntentClassifierEnabled:          boolOrDefault("AGENT_RUNTIME_INTENT_CLASSIFIER_ENABLED", false),
+		IntentMinConfidence:              floatOrDefault("AGENT_RUNTIME_INTENT_MIN_CONFIDENCE", 0.7),
+		SpamFilterEnabled:                boolOrDefault("AGENT_RUNTIME_SPAM_FILTER_ENABLED", true),
+		SpamThreshold:                    floatOrDefault("AGENT_RUNTIME_SPAM_THRESHOLD", 0.7),
+		RateLimitEnabled:                 boolOrDefault("AGENT_RUNTIME_RATE_LIMIT_ENABLED", true),
+		RedactionEnabled:                 boolOrDefault("AGENT_RUNTIME_REDACTION_ENABLED", false),
+		SecretScanMode:                   stringOrDefault("AGENT_RUNTIME_SECRET_SCAN_MODE", "mask"),
+		RateLimitUserPerMinute:           floatOrDefault("AGENT_RUNTIME_RATE_LIMIT_USER_PER_MINUTE", 10),
+		RateLimitUserBurst:               intOrDefault("AGENT_RUNTIME_RATE_LIMIT_USER_BURST", 10),
+		RateLimitContextPerMinute:        floatOrDefault("AGENT_RUNTIME_RATE_LIMIT_CONTEXT_PER_MINUTE", 60),
+		RateLimitContextBurst:            intOrDefault("AGENT_RUNTIME_RATE_LIMIT_CONTEXT_BURST", 30),
+		EgressAllowDomainsCSV:            strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EGRESS_ALLOW_DOMAINS")),
+		EgressDenyDomainsCSV:             strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EGRESS_DENY_DOMAINS")),
+		EgressAllowCIDRsCSV:              strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EGRESS_ALLOW_CIDRS")),
+		EgressDenyCIDRsCSV:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EGRESS_DENY_CIDRS")),
+		EgressAllowPortsCSV:              strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EGRESS_ALLOW_PORTS")),
+		EgressDenyPortsCSV:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EGRESS_DENY_PORTS")),
+		EgressAllowPrivate:               boolOrDefault("AGENT_RUNTIME_EGRESS_ALLOW_PRIVATE", false),
+		EventSinksCSV:                    strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EVENT_SINKS")),
+		EventTypesCSV:                    strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EVENT_TYPES")),
+		EventWebhookSecret:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_EVENT_WEBHOOK_SECRET")),
+		EventNATSSubjectPrefix:           stringOrDefault("AGENT_RUNTIME_EVENT_NATS_SUBJECT_PREFIX", "agent_runtime"),
+		EventBufferSize:                  intOrDefault("AGENT_RUNTIME_EVENT_BUFFER_SIZE", 1000),
 		TaskNotifyPolicy:                 notificationPolicyOrDefault("AGENT_RUNTIME_TASK_NOTIFY_POLICY", "both"),
 		TaskNotifySuccessPolicy:          notificationPolicyOrDefault("AGENT_RUNTIME_TASK_NOTIFY_SUCCESS_POLICY", ""),
 		TaskNotifyFailurePolicy:          notificationPolicyOrDefault("AGENT_RUNTIME_TASK_NOTIFY_FAILURE_POLICY", ""),
+		TaskProgressNotifyEnabled:        boolOrDefault("AGENT_RUNTIME_TASK_PROGRESS_NOTIFY_ENABLED", false),
+		TaskProgressNotifyIntervalSec:    intOrDefault("AGENT_RUNTIME_TASK_PROGRESS_NOTIFY_INTERVAL_SECONDS", 120),
+		ArtifactLinkBaseURL:              strings.TrimSpace(os.Getenv("AGENT_RUNTIME_ARTIFACT_LINK_BASE_URL")),
+		ArtifactLinkSecret:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_ARTIFACT_LINK_SECRET")),
+		ArtifactLinkTTLHours:             intOrDefault("AGENT_RUNTIME_ARTIFACT_LINK_TTL_HOURS", 168),
+		OutboxRetrySec:                   intOrDefault("AGENT_RUNTIME_OUTBOX_RETRY_SECONDS", 30),
+		OutboxMaxAgeHours:                intOrDefault("AGENT_RUNTIME_OUTBOX_MAX_AGE_HOURS", 24),
+		ApprovalNotifyAdmin:              boolOrDefault("AGENT_RUNTIME_APPROVAL_NOTIFY_ADMIN", true),
+		ApprovalExpiryEnabled:            boolOrDefault("AGENT_RUNTIME_APPROVAL_EXPIRY_ENABLED", true),
+		ApprovalTTLMinutes:               intOrDefault("AGENT_RUNTIME_APPROVAL_TTL_MINUTES", 1440),
+		ApprovalTTLByType:                strings.TrimSpace(os.Getenv("AGENT_RUNTIME_APPROVAL_TTL_BY_TYPE")),
+		TwoPersonRuleEnabled:             boolOrDefault("AGENT_RUNTIME_TWO_PERSON_RULE_ENABLED", false),
+		TwoPersonApprovals:               intOrDefault("AGENT_RUNTIME_TWO_PERSON_APPROVALS", 2),
+		TwoPersonInternalEmailDomains:    strings.TrimSpace(os.Getenv("AGENT_RUNTIME_TWO_PERSON_INTERNAL_EMAIL_DOMAINS")),
+		TwoPersonActionTypes:             strings.TrimSpace(os.Getenv("AGENT_RUNTIME_TWO_PERSON_ACTION_TYPES")),
+		ActionRiskEnabled:                boolOrDefault("AGENT_RUNTIME_ACTION_RISK_ENABLED", true),
+		ActionRiskLLMEnabled:             boolOrDefault("AGENT_RUNTIME_ACTION_RISK_LLM_ENABLED", false),
+		ActionRiskTrustedDomains:         strings.TrimSpace(os.Getenv("AGENT_RUNTIME_ACTION_RISK_TRUSTED_DOMAINS")),
+		ActionRiskLargePayloadBytes:      intOrDefault("AGENT_RUNTIME_ACTION_RISK_LARGE_PAYLOAD_BYTES", 65536),
+		StatusPageEnabled:                boolOrDefault("AGENT_RUNTIME_STATUS_PAGE_ENABLED", false),
+		StatusPageWorkspaces:             strings.TrimSpace(os.Getenv("AGENT_RUNTIME_STATUS_PAGE_WORKSPACES")),
+		StatusPageEndpoints:              strings.TrimSpace(os.Getenv("AGENT_RUNTIME_STATUS_PAGE_ENDPOINTS")),
+		StatusPageIntervalMinutes:        intOrDefault("AGENT_RUNTIME_STATUS_PAGE_INTERVAL_MINUTES", 15),
+		StatusPageWindowDays:             intOrDefault("AGENT_RUNTIME_STATUS_PAGE_WINDOW_DAYS", 7),
+		StatusPageTitle:                  stringOrDefault("AGENT_RUNTIME_STATUS_PAGE_TITLE", "Status"),
+		StatusPageDir:                    stringOrDefault("AGENT_RUNTIME_STATUS_PAGE_DIR", filepath.Join(dataDir, "status-pages")),
+		StatusPageS3Bucket:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_STATUS_PAGE_S3_BUCKET")),
+		StatusPageS3Region:               stringOrDefault("AGENT_RUNTIME_STATUS_PAGE_S3_REGION", "us-east-1"),
+		StatusPageS3Prefix:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_STATUS_PAGE_S3_PREFIX")),
+		StatusPageS3Endpoint:             strings.TrimSpace(os.Getenv("AGENT_RUNTIME_STATUS_PAGE_S3_ENDPOINT")),
+		StatusPageS3AccessKeyID:          strings.TrimSpace(os.Getenv("AGENT_RUNTIME_STATUS_PAGE_S3_ACCESS_KEY_ID")),
+		StatusPageS3SecretAccessKey:      strings.TrimSpace(os.Getenv("AGENT_RUNTIME_STATUS_PAGE_S3_SECRET_ACCESS_KEY")),
 		AgentSensitiveApprovalTTLSeconds: intOrDefault("AGENT_RUNTIME_AGENT_SENSITIVE_APPROVAL_TTL_SECONDS", 600),
 		CommandSyncEnabled:               boolOrDefault("AGENT_RUNTIME_COMMAND_SYNC_ENABLED", true),
+		SecretsMasterKey:                 strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SECRETS_MASTER_KEY")),
+		SecretsKeyFile:                   strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SECRETS_KEY_FILE")),
 		DiscordToken:                     os.Getenv("AGENT_RUNTIME_DISCORD_TOKEN"),
 		DiscordAPI:                       stringOrDefault("AGENT_RUNTIME_DISCORD_API_BASE", "https://discord.com/api/v10"),
 		DiscordWSURL:                     stringOrDefault("AGENT_RUNTIME_DISCORD_GATEWAY_URL", "wss://gateway.discord.gg/?v=10&encoding=json"),
@@ -195,11 +444,14 @@ func FromEnv() Config {
 		IMAPPollSeconds:                  intOrDefault("AGENT_RUNTIME_IMAP_POLL_SECONDS", 60),
 		IMAPTLSSkipVerify:                boolOrDefault("AGENT_RUNTIME_IMAP_TLS_SKIP_VERIFY", false),
 
-		LLMProvider:   stringOrDefault("AGENT_RUNTIME_LLM_PROVIDER", "openai"),
-		LLMBaseURL:    stringOrDefault("AGENT_RUNTIME_LLM_BASE_URL", "https://api.openai.com/v1"),
-		LLMAPIKey:     strings.TrimSpace(os.Getenv("AGENT_RUNTIME_LLM_API_KEY")),
-		LLMModel:      stringOrDefault("AGENT_RUNTIME_LLM_MODEL", "gpt-4o"),
-		LLMTimeoutSec: intOrDefault("AGENT_RUNTIME_LLM_TIMEOUT_SECONDS", 60),
+		LLMProvider:             stringOrDefault("AGENT_RUNTIME_LLM_PROVIDER", "openai"),
+		LLMBaseURL:              stringOrDefault("AGENT_RUNTIME_LLM_BASE_URL", "https://api.openai.com/v1"),
+		LLMAPIKey:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_LLM_API_KEY")),
+		LLMModel:                stringOrDefault("AGENT_RUNTIME_LLM_MODEL", "gpt-4o"),
+		LLMTimeoutSec:           intOrDefault("AGENT_RUNTIME_LLM_TIMEOUT_SECONDS", 60),
+		LLMDownAfterFailures:    intOrDefault("AGENT_RUNTIME_LLM_DOWN_AFTER_FAILURES", 3),
+		LLMProbeIntervalSeconds: intOrDefault("AGENT_RUNTIME_LLM_PROBE_INTERVAL_SECONDS", 30),
+		LLMVisionEnabled:        boolOrDefault("AGENT_RUNTIME_LLM_VISION_ENABLED", true),
 
 		SMTPHost:                           strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SMTP_HOST")),
 		SMTPPort:                           intOrDefault("AGENT_RUNTIME_SMTP_PORT", 587),
@@ -213,12 +465,70 @@ func FromEnv() Config {
 		MCPWorkspaceConfigRelPath:          stringOrDefault("AGENT_RUNTIME_MCP_WORKSPACE_CONFIG_REL_PATH", "context/mcp/servers.json"),
 		MCPRefreshSeconds:                  intOrDefault("AGENT_RUNTIME_MCP_REFRESH_SECONDS", 120),
 		MCPHTTPTimeoutSec:                  intOrDefault("AGENT_RUNTIME_MCP_HTTP_TIMEOUT_SECONDS", 30),
+		ToolPluginsDir:                     stringOrDefault("AGENT_RUNTIME_TOOL_PLUGINS_DIR", "ext/tool-plugins"),
+		ToolPluginTimeoutSec:               intOrDefault("AGENT_RUNTIME_TOOL_PLUGIN_TIMEOUT_SECONDS", 30),
+		GitHubAppID:                        intOrDefault("AGENT_RUNTIME_GITHUB_APP_ID", 0),
+		GitHubAppPrivateKey:                strings.TrimSpace(os.Getenv("AGENT_RUNTIME_GITHUB_APP_PRIVATE_KEY")),
+		GitHubAppPrivateKeyFile:            strings.TrimSpace(os.Getenv("AGENT_RUNTIME_GITHUB_APP_PRIVATE_KEY_FILE")),
+		GitHubAPIBase:                      stringOrDefault("AGENT_RUNTIME_GITHUB_API_BASE", "https://api.github.com"),
+		IssueSyncProvider:                  strings.ToLower(strings.TrimSpace(os.Getenv("AGENT_RUNTIME_ISSUE_SYNC_PROVIDER"))),
+		JiraBaseURL:                        strings.TrimSpace(os.Getenv("AGENT_RUNTIME_JIRA_BASE_URL")),
+		JiraEmail:                          strings.TrimSpace(os.Getenv("AGENT_RUNTIME_JIRA_EMAIL")),
+		JiraAPIToken:                       strings.TrimSpace(os.Getenv("AGENT_RUNTIME_JIRA_API_TOKEN")),
+		JiraProjectKey:                     strings.TrimSpace(os.Getenv("AGENT_RUNTIME_JIRA_PROJECT_KEY")),
+		JiraIssueType:                      stringOrDefault("AGENT_RUNTIME_JIRA_ISSUE_TYPE", "Task"),
+		LinearAPIKey:                       strings.TrimSpace(os.Getenv("AGENT_RUNTIME_LINEAR_API_KEY")),
+		LinearTeamID:                       strings.TrimSpace(os.Getenv("AGENT_RUNTIME_LINEAR_TEAM_ID")),
+		CalendarProvider:                   strings.ToLower(strings.TrimSpace(os.Getenv("AGENT_RUNTIME_CALENDAR_PROVIDER"))),
+		CalDAVURL:                          strings.TrimSpace(os.Getenv("AGENT_RUNTIME_CALDAV_URL")),
+		CalDAVUsername:                     strings.TrimSpace(os.Getenv("AGENT_RUNTIME_CALDAV_USERNAME")),
+		CalDAVPassword:                     strings.TrimSpace(os.Getenv("AGENT_RUNTIME_CALDAV_PASSWORD")),
+		GoogleCalendarID:                   stringOrDefault("AGENT_RUNTIME_GOOGLE_CALENDAR_ID", "primary"),
+		GoogleClientID:                     strings.TrimSpace(os.Getenv("AGENT_RUNTIME_GOOGLE_CLIENT_ID")),
+		GoogleClientSecret:                 strings.TrimSpace(os.Getenv("AGENT_RUNTIME_GOOGLE_CLIENT_SECRET")),
+		GoogleRefreshToken:                 strings.TrimSpace(os.Getenv("AGENT_RUNTIME_GOOGLE_REFRESH_TOKEN")),
+		BrowserCDPURL:                      strings.TrimSpace(os.Getenv("AGENT_RUNTIME_BROWSER_CDP_URL")),
+		BrowserTimeoutSec:                  intOrDefault("AGENT_RUNTIME_BROWSER_TIMEOUT_SECONDS", 45),
+		TTSProvider:                        strings.ToLower(strings.TrimSpace(os.Getenv("AGENT_RUNTIME_TTS_PROVIDER"))),
+		TTSAPIKey:                          strings.TrimSpace(os.Getenv("AGENT_RUNTIME_TTS_API_KEY")),
+		TTSBaseURL:                         stringOrDefault("AGENT_RUNTIME_TTS_BASE_URL", "https://api.openai.com/v1"),
+		TTSModel:                           stringOrDefault("AGENT_RUNTIME_TTS_MODEL", "tts-1"),
+		TTSVoice:                           stringOrDefault("AGENT_RUNTIME_TTS_VOICE", "alloy"),
+		AttachmentMaxMB:                    intOrDefault("AGENT_RUNTIME_ATTACHMENT_MAX_MB", 20),
+		AttachmentPDFToTextBinary:          stringOrDefault("AGENT_RUNTIME_ATTACHMENT_PDFTOTEXT_BINARY", "pdftotext"),
+		AttachmentOCRBinary:                stringOrDefault("AGENT_RUNTIME_ATTACHMENT_OCR_BINARY", "tesseract"),
+		AttachmentExtractTimeoutSec:        intOrDefault("AGENT_RUNTIME_ATTACHMENT_EXTRACT_TIMEOUT_SECONDS", 60),
 		SandboxEnabled:                     boolOrDefault("AGENT_RUNTIME_SANDBOX_ENABLED", true),
 		SandboxAllowedCommandsCSV:          stringOrDefault("AGENT_RUNTIME_SANDBOX_ALLOWED_COMMANDS", "echo,cat,ls,curl,wget,grep,rg,head,tail,python3,chromium,sh,bash,ash,apk,pip,pip3,git,jq,sed,awk,find,mkdir,rm,cp,mv,touch,chmod,unzip,tar,gzip,wc,sort,uniq,tee,date,sleep,whoami,pwd,ps,top,kill,node,npm,npx,bun,bunx"),
 		SandboxRunnerCommand:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SANDBOX_RUNNER_COMMAND")),
 		SandboxRunnerArgs:                  strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SANDBOX_RUNNER_ARGS")),
 		SandboxTimeoutSec:                  intOrDefault("AGENT_RUNTIME_SANDBOX_TIMEOUT_SECONDS", 20),
 		SandboxMaxOutputBytes:              intOrDefault("AGENT_RUNTIME_SANDBOX_MAX_OUTPUT_BYTES", 500*1024),
+		SandboxDockerImage:                 strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SANDBOX_DOCKER_IMAGE")),
+		SandboxDockerBinary:                stringOrDefault("AGENT_RUNTIME_SANDBOX_DOCKER_BINARY", "docker"),
+		SandboxDockerRuntime:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SANDBOX_DOCKER_RUNTIME")),
+		SandboxDockerNetwork:               stringOrDefault("AGENT_RUNTIME_SANDBOX_DOCKER_NETWORK", "none"),
+		SandboxDockerCPUs:                  stringOrDefault("AGENT_RUNTIME_SANDBOX_DOCKER_CPUS", "1"),
+		SandboxDockerMemory:                stringOrDefault("AGENT_RUNTIME_SANDBOX_DOCKER_MEMORY", "512m"),
+		SandboxDockerPidsLimit:             intOrDefault("AGENT_RUNTIME_SANDBOX_DOCKER_PIDS_LIMIT", 128),
+		KubernetesJobImage:                 strings.TrimSpace(os.Getenv("AGENT_RUNTIME_K8S_JOB_IMAGE")),
+		KubernetesAPIServer:                strings.TrimSpace(os.Getenv("AGENT_RUNTIME_K8S_API_SERVER")),
+		KubernetesToken:                    strings.TrimSpace(os.Getenv("AGENT_RUNTIME_K8S_TOKEN")),
+		KubernetesCAFile:                   strings.TrimSpace(os.Getenv("AGENT_RUNTIME_K8S_CA_FILE")),
+		KubernetesNamespacePrefix:          stringOrDefault("AGENT_RUNTIME_K8S_NAMESPACE_PREFIX", "agent-ws-"),
+		KubernetesCPULimit:                 stringOrDefault("AGENT_RUNTIME_K8S_CPU_LIMIT", "1"),
+		KubernetesMemoryLimit:              stringOrDefault("AGENT_RUNTIME_K8S_MEMORY_LIMIT", "512Mi"),
+		KubernetesQuotaCPU:                 stringOrDefault("AGENT_RUNTIME_K8S_QUOTA_CPU", "4"),
+		KubernetesQuotaMemory:              stringOrDefault("AGENT_RUNTIME_K8S_QUOTA_MEMORY", "4Gi"),
+		KubernetesQuotaPods:                intOrDefault("AGENT_RUNTIME_K8S_QUOTA_PODS", 10),
+		KubernetesJobTimeoutSec:            intOrDefault("AGENT_RUNTIME_K8S_JOB_TIMEOUT_SECONDS", 300),
+		SSHHosts:                           strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SSH_HOSTS")),
+		SSHBinary:                          stringOrDefault("AGENT_RUNTIME_SSH_BINARY", "ssh"),
+		SSHKeyFile:                         strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SSH_KEY_FILE")),
+		SSHKnownHostsFile:                  strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SSH_KNOWN_HOSTS_FILE")),
+		SSHUser:                            strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SSH_USER")),
+		SSHTimeoutSec:                      intOrDefault("AGENT_RUNTIME_SSH_TIMEOUT_SECONDS", 300),
+		SSHMaxOutputBytes:                  intOrDefault("AGENT_RUNTIME_SSH_MAX_OUTPUT_BYTES", 65536),
 		LLMEnabled:                         boolOrDefault("AGENT_RUNTIME_LLM_ENABLED", true),
 		LLMAllowDM:                         boolOrDefault("AGENT_RUNTIME_LLM_ALLOW_DM", true),
 		LLMRequireMentionInGroups:          boolOrDefault("AGENT_RUNTIME_LLM_REQUIRE_MENTION_IN_GROUPS", true),
@@ -239,6 +549,9 @@ func FromEnv() Config {
 		LLMGroundingSummaryRefreshTurns:    intOrDefault("AGENT_RUNTIME_LLM_GROUNDING_MEMORY_SUMMARY_REFRESH_TURNS", 6),
 		LLMGroundingSummaryMaxItems:        intOrDefault("AGENT_RUNTIME_LLM_GROUNDING_MEMORY_SUMMARY_MAX_ITEMS", 7),
 		LLMGroundingSummarySourceMaxLines:  intOrDefault("AGENT_RUNTIME_LLM_GROUNDING_MEMORY_SUMMARY_SOURCE_MAX_LINES", 120),
+		LLMGroundingHybrid:                 boolOrDefault("AGENT_RUNTIME_LLM_GROUNDING_HYBRID", false),
+		LLMGroundingRerank:                 boolOrDefault("AGENT_RUNTIME_LLM_GROUNDING_RERANK", false),
+		LLMGroundingRerankCandidates:       intOrDefault("AGENT_RUNTIME_LLM_GROUNDING_RERANK_CANDIDATES", 10),
 		LLMAdminSystemPrompt:               stringOrDefault("AGENT_RUNTIME_LLM_ADMIN_SYSTEM_PROMPT", "You are assisting admin operators. Prioritize security, approvals, and operational clarity."),
 		LLMPublicSystemPrompt:              stringOrDefault("AGENT_RUNTIME_LLM_PUBLIC_SYSTEM_PROMPT", "You are assisting community members. Be concise, safe, and policy-compliant."),
 		AgentMaxTurnDurationSec:            intOrDefault("AGENT_RUNTIME_AGENT_MAX_TURN_DURATION_SECONDS", 120),
@@ -250,6 +563,17 @@ func FromEnv() Config {
 		AgentAutonomousMaxTasksPerHour:     intOrDefault("AGENT_RUNTIME_AGENT_AUTONOMOUS_MAX_TASKS_PER_HOUR", 200),
 		AgentAutonomousMaxTasksPerDay:      intOrDefault("AGENT_RUNTIME_AGENT_AUTONOMOUS_MAX_TASKS_PER_DAY", 1000),
 		AgentAutonomousMinConfidence:       floatOrDefault("AGENT_RUNTIME_AGENT_AUTONOMOUS_MIN_CONFIDENCE", 0.05),
+		AgentPlannerEnabled:                boolOrDefault("AGENT_RUNTIME_AGENT_PLANNER_ENABLED", false),
+		AgentPlannerMaxSteps:               intOrDefault("AGENT_RUNTIME_AGENT_PLANNER_MAX_STEPS", 8),
+		ToolTimeoutSec:                     intOrDefault("AGENT_RUNTIME_TOOL_TIMEOUT_SECONDS", 90),
+		ToolMaxRetries:                     intOrDefault("AGENT_RUNTIME_TOOL_MAX_RETRIES", 0),
+		ToolRetryBackoffMS:                 intOrDefault("AGENT_RUNTIME_TOOL_RETRY_BACKOFF_MS", 500),
+		ToolTimeoutsCSV:                    strings.TrimSpace(os.Getenv("AGENT_RUNTIME_TOOL_TIMEOUTS")),
+		ToolRetriesCSV:                     strings.TrimSpace(os.Getenv("AGENT_RUNTIME_TOOL_RETRIES")),
+		QuotaTasksPerDay:                   intOrDefault("AGENT_RUNTIME_QUOTA_TASKS_PER_DAY", 0),
+		QuotaObjectives:                    intOrDefault("AGENT_RUNTIME_QUOTA_OBJECTIVES", 0),
+		QuotaActionsPerDay:                 intOrDefault("AGENT_RUNTIME_QUOTA_ACTIONS_PER_DAY", 0),
+		QuotaTokensPerMonth:                intOrDefault("AGENT_RUNTIME_QUOTA_TOKENS_PER_MONTH", 0),
 		SoulGlobalFile:                     stringOrDefault("AGENT_RUNTIME_SOUL_GLOBAL_FILE", "/context/SOUL.md"),
 		SoulWorkspaceRelPath:               stringOrDefault("AGENT_RUNTIME_SOUL_WORKSPACE_REL_PATH", "context/SOUL.md"),
 		SoulContextRelPath:                 stringOrDefault("AGENT_RUNTIME_SOUL_CONTEXT_REL_PATH", "context/agents/{context_id}/SOUL.md"),
@@ -258,6 +582,27 @@ func FromEnv() Config {
 		SystemPromptContextPath:            stringOrDefault("AGENT_RUNTIME_SYSTEM_PROMPT_CONTEXT_REL_PATH", "context/agents/{context_id}/SYSTEM_PROMPT.md"),
 		ReasoningPromptFile:                stringOrDefault("AGENT_RUNTIME_REASONING_PROMPT_FILE", "/context/REASONING.md"),
 		SkillsGlobalRoot:                   stringOrDefault("AGENT_RUNTIME_SKILLS_GLOBAL_ROOT", "/data/.agents/skills"),
+		SkillsMaxTokens:                    intOrDefault("AGENT_RUNTIME_SKILLS_MAX_TOKENS", 1500),
+		SkillsEmbeddingModel:               strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SKILLS_EMBEDDING_MODEL")),
+		SkillReviewEnabled:                 boolOrDefault("AGENT_RUNTIME_SKILL_REVIEW_ENABLED", true),
+		SkillReviewStaleDays:               intOrDefault("AGENT_RUNTIME_SKILL_REVIEW_STALE_DAYS", 30),
+		SkillReviewIntervalHours:           intOrDefault("AGENT_RUNTIME_SKILL_REVIEW_INTERVAL_HOURS", 24),
+		MemoryCompactionEnabled:            boolOrDefault("AGENT_RUNTIME_MEMORY_COMPACTION_ENABLED", true),
+		MemoryCompactionIntervalHours:      intOrDefault("AGENT_RUNTIME_MEMORY_COMPACTION_INTERVAL_HOURS", 6),
+		MemoryCompactionKeepEntries:        intOrDefault("AGENT_RUNTIME_MEMORY_COMPACTION_KEEP_ENTRIES", 30),
+		MemoryCompactionMinAgeHours:        intOrDefault("AGENT_RUNTIME_MEMORY_COMPACTION_MIN_AGE_HOURS", 24),
+		MemoryCompactionMaxSections:        intOrDefault("AGENT_RUNTIME_MEMORY_COMPACTION_MAX_SECTIONS", 12),
+		ApprovalDigestEnabled:              boolOrDefault("AGENT_RUNTIME_APPROVAL_DIGEST_ENABLED", false),
+		ApprovalDigestIntervalHours:        intOrDefault("AGENT_RUNTIME_APPROVAL_DIGEST_INTERVAL_HOURS", 4),
+		ApprovalDigestStuckTaskMinutes:     intOrDefault("AGENT_RUNTIME_APPROVAL_DIGEST_STUCK_TASK_MINUTES", 60),
+		QueueBackpressureEnabled:           boolOrDefault("AGENT_RUNTIME_QUEUE_BACKPRESSURE_ENABLED", true),
+		QueueBackpressureThreshold:         intOrDefault("AGENT_RUNTIME_QUEUE_BACKPRESSURE_THRESHOLD", 0),
+		QueueBackpressureNotifyAdmin:       boolOrDefault("AGENT_RUNTIME_QUEUE_BACKPRESSURE_NOTIFY_ADMIN", true),
+		ChangeLogEnabled:                   boolOrDefault("AGENT_RUNTIME_CHANGE_LOG_ENABLED", false),
+		ChangeLogRetentionDays:             intOrDefault("AGENT_RUNTIME_CHANGE_LOG_RETENTION_DAYS", 30),
+		BotfileReconcileIntervalMinutes:    intOrDefault("AGENT_RUNTIME_BOTFILE_RECONCILE_INTERVAL_MINUTES", 15),
+		BotfileReconcileFix:                boolOrDefault("AGENT_RUNTIME_BOTFILE_RECONCILE_FIX", false),
+		SharedKnowledgeWorkspace:           strings.TrimSpace(os.Getenv("AGENT_RUNTIME_SHARED_KNOWLEDGE_WORKSPACE")),
 		PublicHost:                         stringOrDefault("PUBLIC_HOST", "localhost"),
 		AdminHost:                          stringOrDefault("ADMIN_HOST", "admin.localhost"),
 		AdminAPIURL:                        stringOrDefault("AGENT_RUNTIME_ADMIN_API_URL", "https://admin.localhost"),
@@ -266,8 +611,11 @@ func FromEnv() Config {
 		AdminTLSCAFile:                     strings.TrimSpace(os.Getenv("AGENT_RUNTIME_ADMIN_TLS_CA_FILE")),
 		AdminTLSCertFile:                   strings.TrimSpace(os.Getenv("AGENT_RUNTIME_ADMIN_TLS_CERT_FILE")),
 		AdminTLSKeyFile:                    strings.TrimSpace(os.Getenv("AGENT_RUNTIME_ADMIN_TLS_KEY_FILE")),
+		AdminActingUser:                    strings.TrimSpace(os.Getenv("AGENT_RUNTIME_ADMIN_ACTING_USER")),
+		AdminAPIRequireActingUser:          boolOrDefault("AGENT_RUNTIME_ADMIN_API_REQUIRE_ACTING_USER", false),
 		TUIApproverUserID:                  stringOrDefault("AGENT_RUNTIME_TUI_APPROVER_USER_ID", "tui-admin"),
 		TUIApprovalRole:                    stringOrDefault("AGENT_RUNTIME_TUI_APPROVAL_ROLE", "admin"),
+		TUIProfiles:                        tuiProfilesFromEnv(intOrDefault("AGENT_RUNTIME_ADMIN_HTTP_TIMEOUT_SECONDS", 120)),
 	}
 }
 
@@ -25,6 +25,7 @@ func TestFromEnvDefaults(t *testing.T) {
 	t.Setenv("AGENT_RUNTIME_QMD_QUERY_TIMEOUT_SECONDS", "")
 	t.Setenv("AGENT_RUNTIME_QMD_AUTO_EMBED", "")
 	t.Setenv("AGENT_RUNTIME_OBJECTIVE_POLL_SECONDS", "")
+	t.Setenv("AGENT_RUNTIME_OBJECTIVE_AUTO_PAUSE_AFTER", "")
 	t.Setenv("AGENT_RUNTIME_TASK_RECOVERY_RUNNING_STALE_SECONDS", "")
 	t.Setenv("AGENT_RUNTIME_HEARTBEAT_ENABLED", "")
 	t.Setenv("AGENT_RUNTIME_HEARTBEAT_INTERVAL_SECONDS", "")
@@ -37,6 +38,8 @@ func TestFromEnvDefaults(t *testing.T) {
 	t.Setenv("AGENT_RUNTIME_TASK_NOTIFY_FAILURE_POLICY", "")
 	t.Setenv("AGENT_RUNTIME_AGENT_SENSITIVE_APPROVAL_TTL_SECONDS", "")
 	t.Setenv("AGENT_RUNTIME_COMMAND_SYNC_ENABLED", "")
+	t.Setenv("AGENT_RUNTIME_SECRETS_MASTER_KEY", "")
+	t.Setenv("AGENT_RUNTIME_SECRETS_KEY_FILE", "")
 	t.Setenv("AGENT_RUNTIME_ADMIN_TLS_SKIP_VERIFY", "")
 	t.Setenv("AGENT_RUNTIME_DISCORD_API_BASE", "")
 	t.Setenv("AGENT_RUNTIME_DISCORD_GATEWAY_URL", "")
@@ -72,6 +75,15 @@ func TestFromEnvDefaults(t *testing.T) {
 	t.Setenv("AGENT_RUNTIME_SANDBOX_RUNNER_COMMAND", "")
 	t.Setenv("AGENT_RUNTIME_SANDBOX_RUNNER_ARGS", "")
 	t.Setenv("AGENT_RUNTIME_SANDBOX_TIMEOUT_SECONDS", "")
+	t.Setenv("AGENT_RUNTIME_SANDBOX_DOCKER_IMAGE", "")
+	t.Setenv("AGENT_RUNTIME_SANDBOX_DOCKER_NETWORK", "")
+	t.Setenv("AGENT_RUNTIME_SANDBOX_DOCKER_MEMORY", "")
+	t.Setenv("AGENT_RUNTIME_K8S_JOB_IMAGE", "")
+	t.Setenv("AGENT_RUNTIME_K8S_QUOTA_PODS", "")
+	t.Setenv("AGENT_RUNTIME_K8S_JOB_TIMEOUT_SECONDS", "")
+	t.Setenv("AGENT_RUNTIME_SSH_HOSTS", "")
+	t.Setenv("AGENT_RUNTIME_SSH_TIMEOUT_SECONDS", "")
+	t.Setenv("AGENT_RUNTIME_SSH_MAX_OUTPUT_BYTES", "")
 	t.Setenv("AGENT_RUNTIME_LLM_ENABLED", "")
 	t.Setenv("AGENT_RUNTIME_LLM_ALLOW_DM", "")
 	t.Setenv("AGENT_RUNTIME_LLM_REQUIRE_MENTION_IN_GROUPS", "")
@@ -95,6 +107,11 @@ func TestFromEnvDefaults(t *testing.T) {
 	t.Setenv("AGENT_RUNTIME_SYSTEM_PROMPT_WORKSPACE_REL_PATH", "")
 	t.Setenv("AGENT_RUNTIME_SYSTEM_PROMPT_CONTEXT_REL_PATH", "")
 	t.Setenv("AGENT_RUNTIME_SKILLS_GLOBAL_ROOT", "")
+	t.Setenv("AGENT_RUNTIME_SKILLS_MAX_TOKENS", "")
+	t.Setenv("AGENT_RUNTIME_SKILLS_EMBEDDING_MODEL", "")
+	t.Setenv("AGENT_RUNTIME_SKILL_REVIEW_ENABLED", "")
+	t.Setenv("AGENT_RUNTIME_SKILL_REVIEW_STALE_DAYS", "")
+	t.Setenv("AGENT_RUNTIME_SKILL_REVIEW_INTERVAL_HOURS", "")
 
 	cfg := FromEnv()
 	if cfg.DataDir != "/data" {
@@ -109,6 +126,9 @@ func TestFromEnvDefaults(t *testing.T) {
 	if cfg.DefaultConcurrency != 5 {
 		t.Fatalf("expected default concurrency 5, got %d", cfg.DefaultConcurrency)
 	}
+	if cfg.WorkerPoolMin != 0 || cfg.WorkerPoolMax != 0 || cfg.WorkerAutoscaleEnabled || cfg.WorkerAutoscaleIntervalSec != 15 {
+		t.Fatalf("expected a fixed worker pool by default, got %d-%d %t %d", cfg.WorkerPoolMin, cfg.WorkerPoolMax, cfg.WorkerAutoscaleEnabled, cfg.WorkerAutoscaleIntervalSec)
+	}
 	if cfg.QMDBinary != "qmd" {
 		t.Fatalf("expected default qmd binary, got %s", cfg.QMDBinary)
 	}
@@ -151,6 +171,9 @@ func TestFromEnvDefaults(t *testing.T) {
 	if cfg.ObjectivePollSec != 15 {
 		t.Fatalf("expected default objective poll seconds 15, got %d", cfg.ObjectivePollSec)
 	}
+	if cfg.ObjectiveAutoPauseAfter != 5 {
+		t.Fatalf("expected default objective auto-pause after 5 failures, got %d", cfg.ObjectiveAutoPauseAfter)
+	}
 	if cfg.TaskRecoveryRunningStaleSec != 600 {
 		t.Fatalf("expected default task recovery running stale seconds 600, got %d", cfg.TaskRecoveryRunningStaleSec)
 	}
@@ -172,6 +195,27 @@ func TestFromEnvDefaults(t *testing.T) {
 	if !cfg.TriageNotifyAdmin {
 		t.Fatal("expected triage admin notifications enabled by default")
 	}
+	if cfg.IntentClassifierEnabled || cfg.IntentMinConfidence != 0.7 {
+		t.Fatalf("expected intent classifier off at 0.7 by default, got %v/%v", cfg.IntentClassifierEnabled, cfg.IntentMinConfidence)
+	}
+	if !cfg.SpamFilterEnabled || cfg.SpamThreshold != 0.7 {
+		t.Fatalf("expected spam filter enabled at 0.7 by default, got %v/%v", cfg.SpamFilterEnabled, cfg.SpamThreshold)
+	}
+	if !cfg.RateLimitEnabled || cfg.RateLimitUserPerMinute != 10 || cfg.RateLimitContextBurst != 30 {
+		t.Fatalf("expected rate limits enabled by default, got %v/%v/%v", cfg.RateLimitEnabled, cfg.RateLimitUserPerMinute, cfg.RateLimitContextBurst)
+	}
+	if cfg.RedactionEnabled {
+		t.Fatal("expected redaction disabled by default")
+	}
+	if cfg.SecretScanMode != "mask" {
+		t.Fatalf("expected secret scanning to mask by default, got %q", cfg.SecretScanMode)
+	}
+	if cfg.EventSinksCSV != "" || cfg.EventNATSSubjectPrefix != "agent_runtime" || cfg.EventBufferSize != 1000 {
+		t.Fatalf("expected no event sinks by default, got %q/%q/%d", cfg.EventSinksCSV, cfg.EventNATSSubjectPrefix, cfg.EventBufferSize)
+	}
+	if cfg.EgressAllowPrivate || cfg.EgressAllowDomainsCSV != "" {
+		t.Fatalf("expected private egress blocked and no allowlist by default, got %v/%q", cfg.EgressAllowPrivate, cfg.EgressAllowDomainsCSV)
+	}
 	if cfg.TaskNotifyPolicy != "both" {
 		t.Fatalf("expected default task notify policy both, got %s", cfg.TaskNotifyPolicy)
 	}
@@ -181,12 +225,36 @@ func TestFromEnvDefaults(t *testing.T) {
 	if cfg.TaskNotifyFailurePolicy != "" {
 		t.Fatalf("expected default task notify failure policy empty, got %s", cfg.TaskNotifyFailurePolicy)
 	}
+	if cfg.OutboxRetrySec != 30 || cfg.OutboxMaxAgeHours != 24 {
+		t.Fatalf("expected default outbox retry/max age 30/24, got %d/%d", cfg.OutboxRetrySec, cfg.OutboxMaxAgeHours)
+	}
+	if !cfg.ApprovalNotifyAdmin {
+		t.Fatal("expected approval admin notifications enabled by default")
+	}
+	if !cfg.ApprovalExpiryEnabled || cfg.ApprovalTTLMinutes != 1440 || cfg.ApprovalTTLByType != "" {
+		t.Fatalf("expected approval expiry after 1440 minutes by default, got %v/%d/%q", cfg.ApprovalExpiryEnabled, cfg.ApprovalTTLMinutes, cfg.ApprovalTTLByType)
+	}
+	if cfg.TwoPersonRuleEnabled || cfg.TwoPersonApprovals != 2 || cfg.TwoPersonInternalEmailDomains != "" || cfg.TwoPersonActionTypes != "" {
+		t.Fatalf("expected two-person rule off with 2 approvals by default, got %v/%d/%q/%q", cfg.TwoPersonRuleEnabled, cfg.TwoPersonApprovals, cfg.TwoPersonInternalEmailDomains, cfg.TwoPersonActionTypes)
+	}
+	if !cfg.ActionRiskEnabled || cfg.ActionRiskLLMEnabled || cfg.ActionRiskTrustedDomains != "" || cfg.ActionRiskLargePayloadBytes != 65536 {
+		t.Fatalf("expected heuristic risk scoring on by default, got %v/%v/%q/%d", cfg.ActionRiskEnabled, cfg.ActionRiskLLMEnabled, cfg.ActionRiskTrustedDomains, cfg.ActionRiskLargePayloadBytes)
+	}
+	if cfg.StatusPageEnabled || cfg.StatusPageIntervalMinutes != 15 || cfg.StatusPageWindowDays != 7 || cfg.StatusPageS3Bucket != "" || cfg.StatusPageS3Region != "us-east-1" {
+		t.Fatalf("unexpected status page defaults: %v/%d/%d/%q/%q", cfg.StatusPageEnabled, cfg.StatusPageIntervalMinutes, cfg.StatusPageWindowDays, cfg.StatusPageS3Bucket, cfg.StatusPageS3Region)
+	}
+	if !strings.HasSuffix(cfg.StatusPageDir, "status-pages") {
+		t.Fatalf("expected status pages under the data dir, got %q", cfg.StatusPageDir)
+	}
 	if cfg.AgentSensitiveApprovalTTLSeconds != 600 {
 		t.Fatalf("expected default sensitive approval ttl seconds 600, got %d", cfg.AgentSensitiveApprovalTTLSeconds)
 	}
 	if !cfg.CommandSyncEnabled {
 		t.Fatal("expected command sync enabled by default")
 	}
+	if cfg.SecretsMasterKey != "" || cfg.SecretsKeyFile != "" {
+		t.Fatalf("expected secrets key settings empty by default, got %q %q", cfg.SecretsMasterKey, cfg.SecretsKeyFile)
+	}
 	if cfg.DiscordAPI != "https://discord.com/api/v10" {
 		t.Fatalf("expected default discord api base, got %s", cfg.DiscordAPI)
 	}
@@ -250,6 +318,12 @@ func TestFromEnvDefaults(t *testing.T) {
 	if cfg.LLMTimeoutSec != 60 {
 		t.Fatalf("expected default llm timeout 60, got %d", cfg.LLMTimeoutSec)
 	}
+	if cfg.LLMDownAfterFailures != 3 || cfg.LLMProbeIntervalSeconds != 30 {
+		t.Fatalf("expected default llm degradation thresholds 3/30, got %d/%d", cfg.LLMDownAfterFailures, cfg.LLMProbeIntervalSeconds)
+	}
+	if !cfg.LLMVisionEnabled {
+		t.Fatal("expected llm vision enabled by default")
+	}
 	if cfg.SMTPHost != "" {
 		t.Fatalf("expected default smtp host empty, got %s", cfg.SMTPHost)
 	}
@@ -286,6 +360,30 @@ func TestFromEnvDefaults(t *testing.T) {
 	if cfg.MCPHTTPTimeoutSec != 30 {
 		t.Fatalf("expected default mcp http timeout seconds 30, got %d", cfg.MCPHTTPTimeoutSec)
 	}
+	if cfg.ToolPluginsDir != "ext/tool-plugins" {
+		t.Fatalf("expected default tool plugins dir, got %s", cfg.ToolPluginsDir)
+	}
+	if cfg.ToolPluginTimeoutSec != 30 {
+		t.Fatalf("expected default tool plugin timeout 30, got %d", cfg.ToolPluginTimeoutSec)
+	}
+	if cfg.GitHubAppID != 0 || cfg.GitHubAPIBase != "https://api.github.com" {
+		t.Fatalf("expected default github settings, got %d %s", cfg.GitHubAppID, cfg.GitHubAPIBase)
+	}
+	if cfg.IssueSyncProvider != "" || cfg.JiraIssueType != "Task" {
+		t.Fatalf("expected issue sync disabled by default, got %q %q", cfg.IssueSyncProvider, cfg.JiraIssueType)
+	}
+	if cfg.CalendarProvider != "" || cfg.GoogleCalendarID != "primary" {
+		t.Fatalf("expected calendar disabled by default, got %q %q", cfg.CalendarProvider, cfg.GoogleCalendarID)
+	}
+	if cfg.BrowserCDPURL != "" || cfg.BrowserTimeoutSec != 45 {
+		t.Fatalf("expected browser disabled with 45s timeout by default, got %q %d", cfg.BrowserCDPURL, cfg.BrowserTimeoutSec)
+	}
+	if cfg.TTSProvider != "" || cfg.TTSModel != "tts-1" || cfg.TTSVoice != "alloy" {
+		t.Fatalf("expected tts disabled with default model and voice, got %q %q %q", cfg.TTSProvider, cfg.TTSModel, cfg.TTSVoice)
+	}
+	if cfg.AttachmentMaxMB != 20 || cfg.AttachmentPDFToTextBinary != "pdftotext" || cfg.AttachmentOCRBinary != "tesseract" || cfg.AttachmentExtractTimeoutSec != 60 {
+		t.Fatalf("unexpected attachment defaults: %d %q %q %d", cfg.AttachmentMaxMB, cfg.AttachmentPDFToTextBinary, cfg.AttachmentOCRBinary, cfg.AttachmentExtractTimeoutSec)
+	}
 	if !cfg.SandboxEnabled {
 		t.Fatal("expected sandbox enabled by default")
 	}
@@ -304,6 +402,15 @@ func TestFromEnvDefaults(t *testing.T) {
 	if cfg.SandboxTimeoutSec != 20 {
 		t.Fatalf("expected default sandbox timeout 20, got %d", cfg.SandboxTimeoutSec)
 	}
+	if cfg.SandboxDockerImage != "" || cfg.SandboxDockerNetwork != "none" || cfg.SandboxDockerMemory != "512m" {
+		t.Fatalf("expected docker sandbox off with isolated defaults, got %q %q %q", cfg.SandboxDockerImage, cfg.SandboxDockerNetwork, cfg.SandboxDockerMemory)
+	}
+	if cfg.KubernetesJobImage != "" || cfg.KubernetesQuotaPods != 10 || cfg.KubernetesJobTimeoutSec != 300 {
+		t.Fatalf("expected kubernetes jobs off with default quota, got %q %d %d", cfg.KubernetesJobImage, cfg.KubernetesQuotaPods, cfg.KubernetesJobTimeoutSec)
+	}
+	if cfg.SSHHosts != "" || cfg.SSHBinary != "ssh" || cfg.SSHTimeoutSec != 300 || cfg.SSHMaxOutputBytes != 65536 {
+		t.Fatalf("expected ssh execution off by default, got %q %q %d %d", cfg.SSHHosts, cfg.SSHBinary, cfg.SSHTimeoutSec, cfg.SSHMaxOutputBytes)
+	}
 	if !cfg.LLMEnabled {
 		t.Fatal("expected llm enabled by default")
 	}
@@ -358,6 +465,12 @@ func TestFromEnvDefaults(t *testing.T) {
 	if cfg.LLMGroundingSummarySourceMaxLines != 120 {
 		t.Fatalf("expected default llm grounding memory summary source max lines 120, got %d", cfg.LLMGroundingSummarySourceMaxLines)
 	}
+	if cfg.LLMGroundingHybrid || cfg.LLMGroundingRerank {
+		t.Fatal("expected hybrid grounding and rerank disabled by default")
+	}
+	if cfg.LLMGroundingRerankCandidates != 10 {
+		t.Fatalf("expected default llm grounding rerank candidates 10, got %d", cfg.LLMGroundingRerankCandidates)
+	}
 	if cfg.LLMAdminSystemPrompt == "" {
 		t.Fatal("expected default admin system prompt")
 	}
@@ -385,6 +498,45 @@ func TestFromEnvDefaults(t *testing.T) {
 	if cfg.SkillsGlobalRoot != "/data/.agents/skills" {
 		t.Fatalf("expected default skills global root /data/.agents/skills, got %s", cfg.SkillsGlobalRoot)
 	}
+	if cfg.SkillsMaxTokens != 1500 || cfg.SkillsEmbeddingModel != "" {
+		t.Fatalf("expected default skills budget without embeddings, got %d %q", cfg.SkillsMaxTokens, cfg.SkillsEmbeddingModel)
+	}
+	if !cfg.SkillReviewEnabled || cfg.SkillReviewStaleDays != 30 || cfg.SkillReviewIntervalHours != 24 {
+		t.Fatalf("expected default skill review settings, got %t %d %d", cfg.SkillReviewEnabled, cfg.SkillReviewStaleDays, cfg.SkillReviewIntervalHours)
+	}
+	if !cfg.MemoryCompactionEnabled || cfg.MemoryCompactionIntervalHours != 6 || cfg.MemoryCompactionKeepEntries != 30 || cfg.MemoryCompactionMinAgeHours != 24 || cfg.MemoryCompactionMaxSections != 12 {
+		t.Fatalf("unexpected default memory compaction settings %+v", []any{cfg.MemoryCompactionEnabled, cfg.MemoryCompactionIntervalHours, cfg.MemoryCompactionKeepEntries, cfg.MemoryCompactionMinAgeHours, cfg.MemoryCompactionMaxSections})
+	}
+	if cfg.ApprovalDigestEnabled || cfg.ApprovalDigestIntervalHours != 4 || cfg.ApprovalDigestStuckTaskMinutes != 60 {
+		t.Fatalf("expected approval digest off every 4h with 60m stuck tasks, got %t %d %d", cfg.ApprovalDigestEnabled, cfg.ApprovalDigestIntervalHours, cfg.ApprovalDigestStuckTaskMinutes)
+	}
+	if !cfg.QueueBackpressureEnabled || cfg.QueueBackpressureThreshold != 0 || !cfg.QueueBackpressureNotifyAdmin {
+		t.Fatalf("expected queue backpressure on with derived threshold, got %t %d %t", cfg.QueueBackpressureEnabled, cfg.QueueBackpressureThreshold, cfg.QueueBackpressureNotifyAdmin)
+	}
+	if cfg.ChangeLogEnabled || cfg.ChangeLogRetentionDays != 30 {
+		t.Fatalf("expected change log off with 30 day retention, got %t %d", cfg.ChangeLogEnabled, cfg.ChangeLogRetentionDays)
+	}
+	if cfg.BotfileReconcileIntervalMinutes != 15 || cfg.BotfileReconcileFix {
+		t.Fatalf("expected drift reporting every 15 minutes without fixes, got %d %t", cfg.BotfileReconcileIntervalMinutes, cfg.BotfileReconcileFix)
+	}
+	if cfg.DryRun {
+		t.Fatal("expected dry-run mode off by default")
+	}
+	if cfg.TaskRetryMaxAttempts != 1 || cfg.TaskRetryBackoffSec != 30 || cfg.TaskRetryMaxBackoffSec != 900 || cfg.TaskRetryJitter != 0.2 || cfg.TaskRetryPolicies != "" {
+		t.Fatalf("expected task retries off by default, got %d %d %d %v %q", cfg.TaskRetryMaxAttempts, cfg.TaskRetryBackoffSec, cfg.TaskRetryMaxBackoffSec, cfg.TaskRetryJitter, cfg.TaskRetryPolicies)
+	}
+	if cfg.TaskProgressNotifyEnabled || cfg.TaskProgressNotifyIntervalSec != 120 {
+		t.Fatalf("expected task progress notices off every 120 seconds, got %t %d", cfg.TaskProgressNotifyEnabled, cfg.TaskProgressNotifyIntervalSec)
+	}
+	if cfg.ArtifactLinkBaseURL != "" || cfg.ArtifactLinkSecret != "" || cfg.ArtifactLinkTTLHours != 168 {
+		t.Fatalf("expected artifact links off with a week of validity, got %q %d", cfg.ArtifactLinkBaseURL, cfg.ArtifactLinkTTLHours)
+	}
+	if cfg.TaskLeaseEnabled || cfg.InstanceID != "" || cfg.TaskLeaseSec != 60 || cfg.TaskQueuePollSec != 5 {
+		t.Fatalf("expected task leases off by default, got %t %q %d %d", cfg.TaskLeaseEnabled, cfg.InstanceID, cfg.TaskLeaseSec, cfg.TaskQueuePollSec)
+	}
+	if cfg.SharedKnowledgeWorkspace != "" {
+		t.Fatalf("expected no shared knowledge workspace by default, got %q", cfg.SharedKnowledgeWorkspace)
+	}
 	if !cfg.AgentGroundingFirstStep {
 		t.Fatal("expected agent grounding first step enabled by default")
 	}
@@ -466,11 +618,35 @@ func TestFromEnvOverrides(t *testing.T) {
 	t.Setenv("AGENT_RUNTIME_MCP_WORKSPACE_CONFIG_REL_PATH", "runtime/mcp/workspace.json")
 	t.Setenv("AGENT_RUNTIME_MCP_REFRESH_SECONDS", "33")
 	t.Setenv("AGENT_RUNTIME_MCP_HTTP_TIMEOUT_SECONDS", "44")
+	t.Setenv("AGENT_RUNTIME_TOOL_PLUGINS_DIR", "/opt/tool-plugins")
+	t.Setenv("AGENT_RUNTIME_TOOL_PLUGIN_TIMEOUT_SECONDS", "12")
+	t.Setenv("AGENT_RUNTIME_GITHUB_APP_ID", "4242")
+	t.Setenv("AGENT_RUNTIME_GITHUB_APP_PRIVATE_KEY_FILE", "/run/secrets/github-app.pem")
+	t.Setenv("AGENT_RUNTIME_GITHUB_API_BASE", "https://github.example.com/api/v3")
+	t.Setenv("AGENT_RUNTIME_ISSUE_SYNC_PROVIDER", "Linear")
+	t.Setenv("AGENT_RUNTIME_LINEAR_API_KEY", "lin_api_test")
+	t.Setenv("AGENT_RUNTIME_LINEAR_TEAM_ID", "team-ops")
+	t.Setenv("AGENT_RUNTIME_CALENDAR_PROVIDER", "CalDAV")
+	t.Setenv("AGENT_RUNTIME_CALDAV_URL", "https://dav.example.com/cal/team/")
+	t.Setenv("AGENT_RUNTIME_CALDAV_USERNAME", "runtime")
+	t.Setenv("AGENT_RUNTIME_BROWSER_CDP_URL", "http://chrome:9222")
+	t.Setenv("AGENT_RUNTIME_BROWSER_TIMEOUT_SECONDS", "20")
+	t.Setenv("AGENT_RUNTIME_TTS_PROVIDER", "OpenAI")
+	t.Setenv("AGENT_RUNTIME_TTS_VOICE", "nova")
 	t.Setenv("AGENT_RUNTIME_SANDBOX_ENABLED", "false")
 	t.Setenv("AGENT_RUNTIME_SANDBOX_ALLOWED_COMMANDS", "curl,git,rg")
 	t.Setenv("AGENT_RUNTIME_SANDBOX_RUNNER_COMMAND", "just-bash")
 	t.Setenv("AGENT_RUNTIME_SANDBOX_RUNNER_ARGS", "--network=off --readonly")
 	t.Setenv("AGENT_RUNTIME_SANDBOX_TIMEOUT_SECONDS", "45")
+	t.Setenv("AGENT_RUNTIME_SANDBOX_DOCKER_IMAGE", "alpine:3.20")
+	t.Setenv("AGENT_RUNTIME_SANDBOX_DOCKER_RUNTIME", "runsc")
+	t.Setenv("AGENT_RUNTIME_SANDBOX_DOCKER_MEMORY", "1g")
+	t.Setenv("AGENT_RUNTIME_K8S_JOB_IMAGE", "ghcr.io/acme/runner:1")
+	t.Setenv("AGENT_RUNTIME_K8S_QUOTA_PODS", "3")
+	t.Setenv("AGENT_RUNTIME_K8S_JOB_TIMEOUT_SECONDS", "120")
+	t.Setenv("AGENT_RUNTIME_SSH_HOSTS", "ws-1=deploy@web-1")
+	t.Setenv("AGENT_RUNTIME_SSH_TIMEOUT_SECONDS", "60")
+	t.Setenv("AGENT_RUNTIME_SSH_MAX_OUTPUT_BYTES", "1024")
 	t.Setenv("AGENT_RUNTIME_LLM_ENABLED", "true")
 	t.Setenv("AGENT_RUNTIME_LLM_ALLOW_DM", "false")
 	t.Setenv("AGENT_RUNTIME_LLM_REQUIRE_MENTION_IN_GROUPS", "false")
@@ -502,6 +678,11 @@ func TestFromEnvOverrides(t *testing.T) {
 	t.Setenv("AGENT_RUNTIME_SYSTEM_PROMPT_WORKSPACE_REL_PATH", "persona/SYSTEM_PROMPT.md")
 	t.Setenv("AGENT_RUNTIME_SYSTEM_PROMPT_CONTEXT_REL_PATH", "persona/agents/{context_id}/SYSTEM_PROMPT.md")
 	t.Setenv("AGENT_RUNTIME_SKILLS_GLOBAL_ROOT", "/context/skill-packs")
+	t.Setenv("AGENT_RUNTIME_SKILLS_MAX_TOKENS", "800")
+	t.Setenv("AGENT_RUNTIME_SKILLS_EMBEDDING_MODEL", "text-embedding-3-small")
+	t.Setenv("AGENT_RUNTIME_SKILL_REVIEW_ENABLED", "false")
+	t.Setenv("AGENT_RUNTIME_SKILL_REVIEW_STALE_DAYS", "14")
+	t.Setenv("AGENT_RUNTIME_SKILL_REVIEW_INTERVAL_HOURS", "6")
 	t.Setenv("PUBLIC_HOST", "chat.example.com")
 	t.Setenv("ADMIN_HOST", "admin.example.com")
 	t.Setenv("AGENT_RUNTIME_ADMIN_API_URL", "https://admin.example.com")
@@ -700,6 +881,24 @@ func TestFromEnvOverrides(t *testing.T) {
 	if cfg.MCPHTTPTimeoutSec != 44 {
 		t.Fatalf("expected overridden mcp http timeout seconds, got %d", cfg.MCPHTTPTimeoutSec)
 	}
+	if cfg.ToolPluginsDir != "/opt/tool-plugins" || cfg.ToolPluginTimeoutSec != 12 {
+		t.Fatalf("expected overridden tool plugin settings, got %s %d", cfg.ToolPluginsDir, cfg.ToolPluginTimeoutSec)
+	}
+	if cfg.GitHubAppID != 4242 || cfg.GitHubAppPrivateKeyFile != "/run/secrets/github-app.pem" || cfg.GitHubAPIBase != "https://github.example.com/api/v3" {
+		t.Fatalf("expected overridden github settings, got %d %s %s", cfg.GitHubAppID, cfg.GitHubAppPrivateKeyFile, cfg.GitHubAPIBase)
+	}
+	if cfg.IssueSyncProvider != "linear" || cfg.LinearAPIKey != "lin_api_test" || cfg.LinearTeamID != "team-ops" {
+		t.Fatalf("expected overridden issue sync settings, got %q %q %q", cfg.IssueSyncProvider, cfg.LinearAPIKey, cfg.LinearTeamID)
+	}
+	if cfg.CalendarProvider != "caldav" || cfg.CalDAVURL != "https://dav.example.com/cal/team/" || cfg.CalDAVUsername != "runtime" {
+		t.Fatalf("expected overridden calendar settings, got %q %q %q", cfg.CalendarProvider, cfg.CalDAVURL, cfg.CalDAVUsername)
+	}
+	if cfg.BrowserCDPURL != "http://chrome:9222" || cfg.BrowserTimeoutSec != 20 {
+		t.Fatalf("expected overridden browser settings, got %q %d", cfg.BrowserCDPURL, cfg.BrowserTimeoutSec)
+	}
+	if cfg.TTSProvider != "openai" || cfg.TTSVoice != "nova" {
+		t.Fatalf("expected overridden tts settings, got %q %q", cfg.TTSProvider, cfg.TTSVoice)
+	}
 	if cfg.SandboxEnabled {
 		t.Fatal("expected sandbox enabled false")
 	}
@@ -715,6 +914,15 @@ func TestFromEnvOverrides(t *testing.T) {
 	if cfg.SandboxTimeoutSec != 45 {
 		t.Fatalf("expected overridden sandbox timeout, got %d", cfg.SandboxTimeoutSec)
 	}
+	if cfg.SandboxDockerImage != "alpine:3.20" || cfg.SandboxDockerRuntime != "runsc" || cfg.SandboxDockerMemory != "1g" {
+		t.Fatalf("expected overridden docker sandbox settings, got %q %q %q", cfg.SandboxDockerImage, cfg.SandboxDockerRuntime, cfg.SandboxDockerMemory)
+	}
+	if cfg.KubernetesJobImage != "ghcr.io/acme/runner:1" || cfg.KubernetesQuotaPods != 3 || cfg.KubernetesJobTimeoutSec != 120 {
+		t.Fatalf("expected overridden kubernetes settings, got %q %d %d", cfg.KubernetesJobImage, cfg.KubernetesQuotaPods, cfg.KubernetesJobTimeoutSec)
+	}
+	if cfg.SSHHosts != "ws-1=deploy@web-1" || cfg.SSHTimeoutSec != 60 || cfg.SSHMaxOutputBytes != 1024 {
+		t.Fatalf("expected overridden ssh settings, got %q %d %d", cfg.SSHHosts, cfg.SSHTimeoutSec, cfg.SSHMaxOutputBytes)
+	}
 	if !cfg.LLMEnabled {
 		t.Fatal("expected llm enabled true")
 	}
@@ -802,6 +1010,12 @@ func TestFromEnvOverrides(t *testing.T) {
 	if cfg.SkillsGlobalRoot != "/context/skill-packs" {
 		t.Fatalf("expected overridden skills global root, got %s", cfg.SkillsGlobalRoot)
 	}
+	if cfg.SkillsMaxTokens != 800 || cfg.SkillsEmbeddingModel != "text-embedding-3-small" {
+		t.Fatalf("expected overridden skills selection settings, got %d %q", cfg.SkillsMaxTokens, cfg.SkillsEmbeddingModel)
+	}
+	if cfg.SkillReviewEnabled || cfg.SkillReviewStaleDays != 14 || cfg.SkillReviewIntervalHours != 6 {
+		t.Fatalf("expected overridden skill review settings, got %t %d %d", cfg.SkillReviewEnabled, cfg.SkillReviewStaleDays, cfg.SkillReviewIntervalHours)
+	}
 	if cfg.AgentGroundingFirstStep {
 		t.Fatal("expected overridden agent grounding first step false")
 	}
@@ -830,3 +1044,31 @@ func TestFromEnvOverrides(t *testing.T) {
 		t.Fatalf("expected overridden approver role, got %s", cfg.TUIApprovalRole)
 	}
 }
+
+func TestFromEnvTUIProfiles(t *testing.T) {
+	t.Setenv("AGENT_RUNTIME_ADMIN_HTTP_TIMEOUT_SECONDS", "30")
+	t.Setenv("AGENT_RUNTIME_TUI_PROFILES", "staging, prod-eu,default,missing,staging")
+	t.Setenv("AGENT_RUNTIME_TUI_PROFILE_STAGING_ADMIN_API_URL", "https://admin.staging.example.com")
+	t.Setenv("AGENT_RUNTIME_TUI_PROFILE_STAGING_ADMIN_TLS_SKIP_VERIFY", "true")
+	t.Setenv("AGENT_RUNTIME_TUI_PROFILE_PROD_EU_ADMIN_API_URL", "https://admin.eu.example.com")
+	t.Setenv("AGENT_RUNTIME_TUI_PROFILE_PROD_EU_ADMIN_TLS_CERT_FILE", "/pki/prod.crt")
+	t.Setenv("AGENT_RUNTIME_TUI_PROFILE_PROD_EU_ADMIN_TLS_KEY_FILE", "/pki/prod.key")
+	t.Setenv("AGENT_RUNTIME_TUI_PROFILE_PROD_EU_ADMIN_HTTP_TIMEOUT_SECONDS", "10")
+	t.Setenv("AGENT_RUNTIME_TUI_PROFILE_DEFAULT_ADMIN_API_URL", "https://ignored.example.com")
+
+	cfg := FromEnv()
+	if len(cfg.TUIProfiles) != 2 {
+		t.Fatalf("expected staging and prod-eu profiles, got %+v", cfg.TUIProfiles)
+	}
+	staging, prod := cfg.TUIProfiles[0], cfg.TUIProfiles[1]
+	if staging.Name != "staging" || !staging.AdminTLSSkipVerify || staging.AdminHTTPTimeoutSec != 30 {
+		t.Fatalf("unexpected staging profile %+v", staging)
+	}
+	if prod.Name != "prod-eu" || prod.AdminTLSSkipVerify || prod.AdminTLSCertFile != "/pki/prod.crt" || prod.AdminHTTPTimeoutSec != 10 {
+		t.Fatalf("unexpected prod profile %+v", prod)
+	}
+	applied := prod.Apply(cfg)
+	if applied.AdminAPIURL != "https://admin.eu.example.com" || applied.AdminTLSKeyFile != "/pki/prod.key" {
+		t.Fatalf("expected profile applied to config, got %+v", applied.DefaultTUIProfile())
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// DefaultTUIProfileName names the admin endpoint configured by the
+// AGENT_RUNTIME_ADMIN_* settings.
+const DefaultTUIProfileName = "default"
+
+// TUIProfile is an admin endpoint with its own TLS settings, e.g. staging or
+// prod, that the TUI can switch to without a restart.
+type TUIProfile struct {
+	Name                string
+	AdminAPIURL         string
+	AdminHTTPTimeoutSec int
+	AdminTLSSkipVerify  bool
+	AdminTLSCAFile      string
+	AdminTLSCertFile    string
+	AdminTLSKeyFile     string
+}
+
+// Apply returns cfg pointed at the profile's admin endpoint.
+func (p TUIProfile) Apply(cfg Config) Config {
+	cfg.AdminAPIURL = p.AdminAPIURL
+	cfg.AdminHTTPTimeoutSec = p.AdminHTTPTimeoutSec
+	cfg.AdminTLSSkipVerify = p.AdminTLSSkipVerify
+	cfg.AdminTLSCAFile = p.AdminTLSCAFile
+	cfg.AdminTLSCertFile = p.AdminTLSCertFile
+	cfg.AdminTLSKeyFile = p.AdminTLSKeyFile
+	return cfg
+}
+
+// DefaultTUIProfile returns the profile of the AGENT_RUNTIME_ADMIN_* settings.
+func (c Config) DefaultTUIProfile() TUIProfile {
+	return TUIProfile{
+		Name:                DefaultTUIProfileName,
+		AdminAPIURL:         c.AdminAPIURL,
+		AdminHTTPTimeoutSec: c.AdminHTTPTimeoutSec,
+		AdminTLSSkipVerify:  c.AdminTLSSkipVerify,
+		AdminTLSCAFile:      c.AdminTLSCAFile,
+		AdminTLSCertFile:    c.AdminTLSCertFile,
+		AdminTLSKeyFile:     c.AdminTLSKeyFile,
+	}
+}
+
+// tuiProfilesFromEnv reads the profiles named in AGENT_RUNTIME_TUI_PROFILES
+// (comma separated). Profile <name> is configured by
+// AGENT_RUNTIME_TUI_PROFILE_<NAME>_ADMIN_API_URL and the matching
+// _ADMIN_TLS_SKIP_VERIFY, _ADMIN_TLS_CA_FILE, _ADMIN_TLS_CERT_FILE,
+// _ADMIN_TLS_KEY_FILE and _ADMIN_HTTP_TIMEOUT_SECONDS keys. Unlike the local
+// default, profiles verify the server certificate unless told otherwise.
+// Profiles without a URL, duplicates and one named default are skipped.
+func tuiProfilesFromEnv(defaultTimeoutSec int) []TUIProfile {
+	profiles := []TUIProfile{}
+	seen := map[string]bool{DefaultTUIProfileName: true}
+	for _, raw := range strings.Split(os.Getenv("AGENT_RUNTIME_TUI_PROFILES"), ",") {
+		name := strings.ToLower(strings.TrimSpace(raw))
+		if name == "" || seen[name] {
+			continue
+		}
+		prefix := "AGENT_RUNTIME_TUI_PROFILE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		apiURL := strings.TrimSpace(os.Getenv(prefix + "ADMIN_API_URL"))
+		if apiURL == "" {
+			continue
+		}
+		seen[name] = true
+		profiles = append(profiles, TUIProfile{
+			Name:                name,
+			AdminAPIURL:         apiURL,
+			AdminHTTPTimeoutSec: intOrDefault(prefix+"ADMIN_HTTP_TIMEOUT_SECONDS", defaultTimeoutSec),
+			AdminTLSSkipVerify:  boolOrDefault(prefix+"ADMIN_TLS_SKIP_VERIFY", false),
+			AdminTLSCAFile:      strings.TrimSpace(os.Getenv(prefix + "ADMIN_TLS_CA_FILE")),
+			AdminTLSCertFile:    strings.TrimSpace(os.Getenv(prefix + "ADMIN_TLS_CERT_FILE")),
+			AdminTLSKeyFile:     strings.TrimSpace(os.Getenv(prefix + "ADMIN_TLS_KEY_FILE")),
+		})
+	}
+	return profiles
+}
@@ -1,6 +1,11 @@
 package connectors
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/dwizi/agent-runtime/internal/reply"
+)
 
 type Connector interface {
 	Name() string
@@ -10,3 +15,49 @@ type Connector interface {
 type Publisher interface {
 	Publish(ctx context.Context, externalID, text string) error
 }
+
+// RichPublisher is a Publisher that renders rich replies natively.
+type RichPublisher interface {
+	Publisher
+	PublishRich(ctx context.Context, externalID string, message reply.Message) error
+}
+
+// PublishRich sends message natively when the publisher supports rich
+// replies, and as plain text otherwise.
+func PublishRich(ctx context.Context, publisher Publisher, externalID string, message reply.Message) error {
+	if rich, ok := publisher.(RichPublisher); ok {
+		return rich.PublishRich(ctx, externalID, message)
+	}
+	return publisher.Publish(ctx, externalID, message.PlainText())
+}
+
+// ErrEditUnsupported is returned by EditRich for publishers that cannot
+// change a message after sending it.
+var ErrEditUnsupported = errors.New("connector cannot edit sent messages")
+
+// TrackedPublisher is a RichPublisher that reports the id of the message it
+// posted and can later replace that message, e.g. to remove the buttons of
+// an alert someone already handled.
+type TrackedPublisher interface {
+	PublishRichTracked(ctx context.Context, externalID string, message reply.Message) (string, error)
+	EditRich(ctx context.Context, externalID, messageID string, message reply.Message) error
+}
+
+// PublishTracked sends message and returns its id when the publisher can
+// edit it later. Other publishers send it as PublishRich does and return an
+// empty id.
+func PublishTracked(ctx context.Context, publisher Publisher, externalID string, message reply.Message) (string, error) {
+	if tracked, ok := publisher.(TrackedPublisher); ok {
+		return tracked.PublishRichTracked(ctx, externalID, message)
+	}
+	return "", PublishRich(ctx, publisher, externalID, message)
+}
+
+// EditRich replaces a message sent with PublishTracked.
+func EditRich(ctx context.Context, publisher Publisher, externalID, messageID string, message reply.Message) error {
+	tracked, ok := publisher.(TrackedPublisher)
+	if !ok || messageID == "" {
+		return ErrEditUnsupported
+	}
+	return tracked.EditRich(ctx, externalID, messageID, message)
+}
@@ -7,13 +7,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"path/filepath"
+
+	"github.com/dwizi/agent-runtime/internal/attachments"
+	"github.com/dwizi/agent-runtime/internal/llm"
 )
 
-func (c *Connector) ingestMarkdownAttachments(ctx context.Context, message discordMessageCreate) (string, error) {
-	if c.workspace == "" || c.pairings == nil || len(message.Attachments) == 0 {
-		return "", nil
+// ingestAttachments saves supported attachments into the workspace and
+// returns a summary reply plus the images to show a vision-capable model.
+func (c *Connector) ingestAttachments(ctx context.Context, message discordMessageCreate) (string, []llm.Image, error) {
+	if c.workspace == "" || c.pairings == nil || c.attachments == nil || len(message.Attachments) == 0 {
+		return "", nil, nil
 	}
 	displayName := message.ChannelID
 	if message.GuildID != "" {
@@ -26,19 +30,15 @@ func (c *Connector) ingestMarkdownAttachments(ctx context.Context, message disco
 		displayName,
 	)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	workspacePath := filepath.Join(c.workspace, contextRecord.WorkspaceID)
-	targetDir := filepath.Join(workspacePath, "inbox", "discord", message.ChannelID)
-	if err := os.MkdirAll(targetDir, 0o755); err != nil {
-		return "", err
-	}
-
-	saved := []string{}
+	saved := []attachments.Result{}
+	images := []llm.Image{}
 	for _, attachment := range message.Attachments {
-		filename := sanitizeFilename(attachment.Filename)
-		if !isMarkdown(filename, attachment.ContentType) {
+		kind, ok := attachments.Detect(attachment.Filename, attachment.ContentType)
+		if !ok {
 			continue
 		}
 		content, err := c.downloadAttachment(ctx, attachment.URL)
@@ -46,24 +46,29 @@ func (c *Connector) ingestMarkdownAttachments(ctx context.Context, message disco
 			c.logger.Error("download discord attachment failed", "error", err, "url", attachment.URL)
 			continue
 		}
-		targetName := fmt.Sprintf("%s-%s", message.ID, filename)
-		targetPath := filepath.Join(targetDir, targetName)
-		if err := os.WriteFile(targetPath, content, 0o644); err != nil {
-			return "", err
-		}
-		relativePath, err := filepath.Rel(workspacePath, targetPath)
+		result, err := c.attachments.Save(ctx, attachments.Input{
+			WorkspaceDir: workspacePath,
+			Connector:    "discord",
+			ChannelID:    message.ChannelID,
+			MessageID:    message.ID,
+			Filename:     attachment.Filename,
+			ContentType:  attachment.ContentType,
+			Content:      content,
+		})
 		if err != nil {
-			relativePath = targetName
+			return "", nil, err
 		}
-		saved = append(saved, filepath.ToSlash(relativePath))
-	}
-	if len(saved) == 0 {
-		return "", nil
-	}
-	if len(saved) == 1 {
-		return fmt.Sprintf("Attachment saved: `%s`", saved[0]), nil
+		if kind == attachments.KindImage {
+			if image, ok := llm.NewImage(attachment.Filename, attachment.ContentType, content); ok {
+				images = append(images, image)
+			}
+		}
+		if result.Note != "" {
+			c.logger.Warn("discord attachment saved without text", "path", result.Path, "reason", result.Note)
+		}
+		saved = append(saved, result)
 	}
-	return fmt.Sprintf("Saved %d markdown attachments.", len(saved)), nil
+	return attachments.Summary(saved), images, nil
 }
 
 func (c *Connector) downloadAttachment(ctx context.Context, url string) ([]byte, error) {
@@ -81,7 +86,7 @@ func (c *Connector) downloadAttachment(ctx context.Context, url string) ([]byte,
 	if res.StatusCode < 200 || res.StatusCode >= 300 {
 		return nil, fmt.Errorf("discord attachment download failed with status %d", res.StatusCode)
 	}
-	return ioReadAllLimited(res.Body, 2<<20)
+	return ioReadAllLimited(res.Body, c.attachments.MaxBytes())
 }
 
 func (c *Connector) sendChannelMessage(ctx context.Context, channelID, content string) error {
@@ -150,7 +150,8 @@ func discordCommandDescription(description string) string {
 }
 
 func (c *Connector) handleInteractionCreate(ctx context.Context, interaction discordInteractionCreate) error {
-	if interaction.Type != 2 {
+	// 2 is a slash command, 3 a pressed message button.
+	if interaction.Type != 2 && interaction.Type != 3 {
 		return nil
 	}
 	commandText := interactionToCommandText(interaction)
@@ -165,9 +166,15 @@ func (c *Connector) handleInteractionCreate(ctx context.Context, interaction dis
 	if displayName == "" {
 		displayName = strings.TrimSpace(interaction.ChannelID)
 	}
+	channelID := strings.TrimSpace(interaction.ChannelID)
+	threadID := ""
+	if parentID := c.threadParent(ctx, channelID); parentID != "" {
+		channelID, threadID = parentID, channelID
+	}
 	output, err := c.gateway.HandleMessage(ctx, gateway.MessageInput{
 		Connector:   "discord",
-		ExternalID:  strings.TrimSpace(interaction.ChannelID),
+		ExternalID:  channelID,
+		ThreadID:    threadID,
 		DisplayName: displayName,
 		FromUserID:  userID,
 		Text:        commandText,
@@ -183,6 +190,9 @@ func (c *Connector) handleInteractionCreate(ctx context.Context, interaction dis
 }
 
 func interactionToCommandText(interaction discordInteractionCreate) string {
+	if interaction.Type == 3 {
+		return strings.TrimSpace(interaction.Data.CustomID)
+	}
 	name := strings.TrimSpace(interaction.Data.Name)
 	if name == "" {
 		return ""
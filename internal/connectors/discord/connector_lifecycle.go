@@ -159,6 +159,12 @@ func (c *Connector) runSession(ctx context.Context) error {
 					c.botUserID = strings.TrimSpace(ready.User.ID)
 				}
 			}
+			if envelope.T == "THREAD_CREATE" {
+				var thread discordChannel
+				if err := json.Unmarshal(envelope.D, &thread); err == nil {
+					c.rememberChannel(thread)
+				}
+			}
 			if envelope.T == "MESSAGE_CREATE" {
 				var message discordMessageCreate
 				if err := json.Unmarshal(envelope.D, &message); err != nil {
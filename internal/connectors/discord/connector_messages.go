@@ -20,6 +20,7 @@ func (c *Connector) handleMessageCreate(ctx context.Context, message discordMess
 	if message.Author.Bot {
 		return nil
 	}
+	message.parentID = c.threadParent(ctx, message.ChannelID)
 	displayName := message.ChannelID
 	if message.GuildID != "" {
 		displayName = message.GuildID
@@ -27,7 +28,7 @@ func (c *Connector) handleMessageCreate(ctx context.Context, message discordMess
 	contextRecord, contextErr := c.pairings.EnsureContextForExternalChannel(
 		ctx,
 		"discord",
-		message.ChannelID,
+		message.contextChannelID(),
 		displayName,
 	)
 	if contextErr != nil {
@@ -36,7 +37,7 @@ func (c *Connector) handleMessageCreate(ctx context.Context, message discordMess
 
 	text := strings.TrimSpace(message.Content)
 	c.logInbound(contextRecord, message, text)
-	attachmentReply, err := c.ingestMarkdownAttachments(ctx, message)
+	attachmentReply, images, err := c.ingestAttachments(ctx, message)
 	if err != nil {
 		c.logger.Error("discord attachment ingest failed", "error", err, "channel_id", message.ChannelID, "message_id", message.ID)
 	}
@@ -67,15 +68,21 @@ func (c *Connector) handleMessageCreate(ctx context.Context, message discordMess
 	}
 
 	output, err := c.gateway.HandleMessage(ctx, gateway.MessageInput{
-		Connector:   "discord",
-		ExternalID:  message.ChannelID,
-		DisplayName: displayName,
-		FromUserID:  message.Author.ID,
-		Text:        text,
+		Connector:        "discord",
+		ExternalID:       message.contextChannelID(),
+		ThreadID:         message.threadID(),
+		DisplayName:      displayName,
+		FromUserID:       message.Author.ID,
+		Text:             text,
+		Images:           images,
+		AccountCreatedAt: discordSnowflakeTime(message.Author.ID),
 	})
 	if err != nil {
 		return err
 	}
+	if output.Suppressed {
+		return nil
+	}
 	trimmedGatewayReply := strings.TrimSpace(output.Reply)
 	if !output.Handled || trimmedGatewayReply == "" {
 		c.logger.Info(
@@ -88,7 +95,7 @@ func (c *Connector) handleMessageCreate(ctx context.Context, message discordMess
 		replyToSend := attachmentReply
 		shouldReply, isMention := c.shouldAutoReply(message, text)
 		if shouldReply {
-			llmReply, notice, llmErr := c.generateReply(ctx, contextRecord, message, text, isMention)
+			llmReply, notice, llmErr := c.generateReply(ctx, contextRecord, message, text, images, isMention)
 			if llmErr != nil {
 				c.logger.Error(
 					"discord llm reply generation failed",
@@ -97,7 +104,9 @@ func (c *Connector) handleMessageCreate(ctx context.Context, message discordMess
 					"message_id", message.ID,
 					"is_mention", isMention,
 				)
-				if replyToSend == "" {
+				if replyToSend == "" && errors.Is(llmErr, llm.ErrUnavailable) {
+					replyToSend = "I can't reach my language model right now. Please try again later."
+				} else if replyToSend == "" {
 					replyToSend = "I started working on that but ran into an internal error. Please try again in a moment."
 				}
 			} else {
@@ -141,6 +150,13 @@ func (c *Connector) handleMessageCreate(ctx context.Context, message discordMess
 		)
 		return nil
 	}
+	if output.Rich != nil {
+		rich := *output.Rich
+		if attachmentReply != "" {
+			rich.Text = strings.TrimSpace(rich.Text) + "\n\n" + attachmentReply
+		}
+		return c.replyRich(ctx, contextRecord, message, rich)
+	}
 	c.logOutbound(contextRecord, message, output.Reply)
 	return c.sendChannelMessage(ctx, message.ChannelID, output.Reply)
 }
@@ -167,7 +183,7 @@ func (c *Connector) shouldAutoReply(message discordMessageCreate, text string) (
 	return true, false
 }
 
-func (c *Connector) generateReply(ctx context.Context, contextRecord store.ContextRecord, message discordMessageCreate, text string, isMention bool) (string, string, error) {
+func (c *Connector) generateReply(ctx context.Context, contextRecord store.ContextRecord, message discordMessageCreate, text string, images []llm.Image, isMention bool) (string, string, error) {
 	if c.responder == nil {
 		return "", "", nil
 	}
@@ -217,7 +233,8 @@ func (c *Connector) generateReply(ctx context.Context, contextRecord store.Conte
 		Connector:   "discord",
 		WorkspaceID: contextRecord.WorkspaceID,
 		ContextID:   contextRecord.ID,
-		ExternalID:  message.ChannelID,
+		ExternalID:  message.contextChannelID(),
+		ThreadID:    message.threadID(),
 		DisplayName: displayName,
 		FromUserID:  message.Author.ID,
 		Text:        prompt,
@@ -233,10 +250,12 @@ func (c *Connector) generateReply(ctx context.Context, contextRecord store.Conte
 		Connector:   "discord",
 		WorkspaceID: contextRecord.WorkspaceID,
 		ContextID:   contextRecord.ID,
-		ExternalID:  message.ChannelID,
+		ExternalID:  message.contextChannelID(),
+		ThreadID:    message.threadID(),
 		DisplayName: displayName,
 		FromUserID:  message.Author.ID,
 		Text:        prompt,
+		Images:      images,
 		IsDM:        message.GuildID == "",
 	})
 	if err != nil {
@@ -251,7 +270,7 @@ func (c *Connector) generateReply(ctx context.Context, contextRecord store.Conte
 		WorkspaceID:     contextRecord.WorkspaceID,
 		ContextID:       contextRecord.ID,
 		Connector:       "discord",
-		ExternalID:      message.ChannelID,
+		ExternalID:      message.contextChannelID(),
 		RequesterUserID: message.Author.ID,
 		ActionType:      proposal.Type,
 		ActionTarget:    proposal.Target,
@@ -291,7 +310,8 @@ func (c *Connector) logInbound(contextRecord store.ContextRecord, message discor
 		WorkspaceRoot: c.workspace,
 		WorkspaceID:   contextRecord.WorkspaceID,
 		Connector:     "discord",
-		ExternalID:    message.ChannelID,
+		ExternalID:    message.contextChannelID(),
+		ThreadID:      message.threadID(),
 		Direction:     "inbound",
 		ActorID:       message.Author.ID,
 		DisplayName:   displayName,
@@ -315,7 +335,8 @@ func (c *Connector) logOutbound(contextRecord store.ContextRecord, message disco
 		WorkspaceRoot: c.workspace,
 		WorkspaceID:   contextRecord.WorkspaceID,
 		Connector:     "discord",
-		ExternalID:    message.ChannelID,
+		ExternalID:    message.contextChannelID(),
+		ThreadID:      message.threadID(),
 		Direction:     "outbound",
 		ActorID:       "agent-runtime",
 		DisplayName:   displayName,
@@ -0,0 +1,242 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/reply"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	// Discord allows five buttons per action row and five rows per message.
+	discordButtonsPerRow = 5
+	discordMaxButtons    = 25
+	discordEmbedMaxChars = 4096
+)
+
+// PublishRich sends a rich reply with the title as an embed, buttons as
+// message components and attachments as file uploads.
+func (c *Connector) PublishRich(ctx context.Context, externalID string, message reply.Message) error {
+	channelID := strings.TrimSpace(externalID)
+	if channelID == "" {
+		return fmt.Errorf("discord external id is required")
+	}
+	_, err := c.sendRichMessage(ctx, channelID, message)
+	return err
+}
+
+// PublishRichTracked sends a rich reply like PublishRich and returns the id
+// of the created message so EditRich can replace it later.
+func (c *Connector) PublishRichTracked(ctx context.Context, externalID string, message reply.Message) (string, error) {
+	channelID := strings.TrimSpace(externalID)
+	if channelID == "" {
+		return "", fmt.Errorf("discord external id is required")
+	}
+	return c.sendRichMessage(ctx, channelID, message)
+}
+
+// EditRich replaces the content and buttons of a message sent with
+// PublishRichTracked. A message without buttons loses its components.
+func (c *Connector) EditRich(ctx context.Context, externalID, messageID string, message reply.Message) error {
+	channelID := strings.TrimSpace(externalID)
+	messageID = strings.TrimSpace(messageID)
+	if channelID == "" || messageID == "" {
+		return fmt.Errorf("discord external id and message id are required")
+	}
+	payload := discordRichPayload(message)
+	if _, ok := payload["components"]; !ok {
+		payload["components"] = []map[string]any{}
+	}
+	if _, ok := payload["embeds"]; !ok {
+		payload["embeds"] = []map[string]any{}
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/channels/%s/messages/%s", c.apiBase, channelID, messageID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, endpoint, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bot "+c.token)
+	req.Header.Set("User-Agent", "agent-runtime/0.1")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("discord edit message failed: status=%d body=%s", res.StatusCode, string(bodyBytes))
+	}
+	return nil
+}
+
+func (c *Connector) replyRich(ctx context.Context, contextRecord store.ContextRecord, message discordMessageCreate, rich reply.Message) error {
+	c.logOutbound(contextRecord, message, rich.PlainText())
+	_, err := c.sendRichMessage(ctx, message.ChannelID, rich)
+	return err
+}
+
+// sendRichMessage posts the message and returns the id Discord assigned to it.
+func (c *Connector) sendRichMessage(ctx context.Context, channelID string, message reply.Message) (string, error) {
+	payload := discordRichPayload(message)
+	if len(payload) == 0 && len(message.Attachments) == 0 {
+		return "", nil
+	}
+	var (
+		body        io.Reader
+		contentType string
+	)
+	if len(message.Attachments) == 0 {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return "", err
+		}
+		body, contentType = bytes.NewReader(encoded), "application/json"
+	} else {
+		multipartBody, multipartType, err := discordMultipartBody(payload, message.Attachments)
+		if err != nil {
+			return "", err
+		}
+		body, contentType = multipartBody, multipartType
+	}
+
+	endpoint := fmt.Sprintf("%s/channels/%s/messages", c.apiBase, channelID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bot "+c.token)
+	req.Header.Set("User-Agent", "agent-runtime/0.1")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return "", fmt.Errorf("discord send rich message failed: status=%d body=%s", res.StatusCode, string(bodyBytes))
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&created); err != nil {
+		return "", nil
+	}
+	return created.ID, nil
+}
+
+// discordRichPayload builds the message JSON. A titled message becomes an
+// embed so the title renders as a heading; anything else is plain content.
+func discordRichPayload(message reply.Message) map[string]any {
+	payload := map[string]any{}
+	title := strings.TrimSpace(message.Title)
+	if title != "" {
+		untitled := message
+		untitled.Title = ""
+		embed := map[string]any{"title": clipDiscordText(title, 256)}
+		if description := untitled.Body(); description != "" {
+			embed["description"] = clipDiscordText(description, discordEmbedMaxChars)
+		}
+		payload["embeds"] = []map[string]any{embed}
+	} else if content := message.Body(); content != "" {
+		payload["content"] = clipDiscordMessage(content)
+	}
+	if components := discordButtonComponents(message.ValidButtons()); len(components) > 0 {
+		payload["components"] = components
+	}
+	return payload
+}
+
+func discordButtonComponents(buttons []reply.Button) []map[string]any {
+	if len(buttons) > discordMaxButtons {
+		buttons = buttons[:discordMaxButtons]
+	}
+	rows := []map[string]any{}
+	for start := 0; start < len(buttons); start += discordButtonsPerRow {
+		end := start + discordButtonsPerRow
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		row := []map[string]any{}
+		for _, button := range buttons[start:end] {
+			row = append(row, map[string]any{
+				"type":      2,
+				"style":     discordButtonStyle(button.Style),
+				"label":     clipDiscordText(button.Label, 80),
+				"custom_id": button.Command,
+			})
+		}
+		rows = append(rows, map[string]any{"type": 1, "components": row})
+	}
+	return rows
+}
+
+func discordButtonStyle(style reply.ButtonStyle) int {
+	switch style {
+	case reply.ButtonPrimary:
+		return 1
+	case reply.ButtonDanger:
+		return 4
+	default:
+		return 2
+	}
+}
+
+func discordMultipartBody(payload map[string]any, attachments []reply.Attachment) (io.Reader, string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := writer.WriteField("payload_json", string(encoded)); err != nil {
+		return nil, "", err
+	}
+	for index, attachment := range attachments {
+		name := strings.TrimSpace(attachment.Name)
+		if name == "" {
+			name = "attachment"
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[%d]"; filename=%q`, index, name))
+		mediaType := strings.TrimSpace(attachment.MediaType)
+		if mediaType == "" {
+			mediaType = "application/octet-stream"
+		}
+		header.Set("Content-Type", mediaType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(attachment.Data); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, writer.FormDataContentType(), nil
+}
+
+func clipDiscordText(content string, limit int) string {
+	trimmed := strings.TrimSpace(content)
+	if len(trimmed) <= limit {
+		return trimmed
+	}
+	return strings.TrimSpace(trimmed[:limit-3]) + "..."
+}
@@ -5,8 +5,10 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/attachments"
 	"github.com/dwizi/agent-runtime/internal/gateway"
 	"github.com/dwizi/agent-runtime/internal/heartbeat"
 	"github.com/dwizi/agent-runtime/internal/llm"
@@ -58,6 +60,12 @@ type Connector struct {
 	logger          *slog.Logger
 	botUserID       string
 	reporter        heartbeat.Reporter
+	attachments     *attachments.Ingestor
+
+	// threadParents caches the parent channel of each channel ID seen, ""
+	// for channels that are not threads.
+	threadMu      sync.Mutex
+	threadParents map[string]string
 }
 
 type Option func(*Connector)
@@ -87,6 +95,16 @@ func WithCommandGuildIDs(guildIDs []string) Option {
 	}
 }
 
+// WithAttachmentIngestor replaces the default attachment pipeline, which
+// saves supported files and extracts their text for search.
+func WithAttachmentIngestor(ingestor *attachments.Ingestor) Option {
+	return func(connector *Connector) {
+		if ingestor != nil {
+			connector.attachments = ingestor
+		}
+	}
+}
+
 func WithApplicationID(applicationID string) Option {
 	return func(connector *Connector) {
 		connector.applicationID = strings.TrimSpace(applicationID)
@@ -112,6 +130,7 @@ func New(token, apiBase, gatewayURL, workspaceRoot string, pairings PairingStore
 		policy:      policy,
 		httpClient:  &http.Client{Timeout: 12 * time.Second},
 		logger:      logger,
+		attachments: attachments.New(attachments.Config{}),
 	}
 	for _, opt := range opts {
 		if opt != nil {
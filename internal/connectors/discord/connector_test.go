@@ -16,6 +16,7 @@ import (
 	"github.com/dwizi/agent-runtime/internal/gateway"
 	"github.com/dwizi/agent-runtime/internal/llm"
 	llmsafety "github.com/dwizi/agent-runtime/internal/llm/safety"
+	"github.com/dwizi/agent-runtime/internal/reply"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -78,6 +79,7 @@ func (f *fakePairingStore) CreateActionApproval(ctx context.Context, input store
 type fakeCommandGateway struct {
 	calls []gateway.MessageInput
 	reply string
+	rich  *reply.Message
 }
 
 func (f *fakeCommandGateway) HandleMessage(ctx context.Context, input gateway.MessageInput) (gateway.MessageOutput, error) {
@@ -85,7 +87,7 @@ func (f *fakeCommandGateway) HandleMessage(ctx context.Context, input gateway.Me
 	if f.reply == "" {
 		return gateway.MessageOutput{}, nil
 	}
-	return gateway.MessageOutput{Handled: true, Reply: f.reply}, nil
+	return gateway.MessageOutput{Handled: true, Reply: f.reply, Rich: f.rich}, nil
 }
 
 type fakeResponder struct {
@@ -224,6 +226,75 @@ func TestHandleInteractionCreateRunsGateway(t *testing.T) {
 	}
 }
 
+func TestHandleInteractionCreateRunsButtonCommand(t *testing.T) {
+	commands := &fakeCommandGateway{reply: "Approved `act_1`."}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{})
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	connector := New("bot-token", server.URL, "wss://discord.test/ws", t.TempDir(), &fakePairingStore{}, commands, nil, nil, logger)
+	err := connector.handleInteractionCreate(context.Background(), discordInteractionCreate{
+		ID:        "it-2",
+		Type:      3,
+		Token:     "tok-2",
+		ChannelID: "chan-123",
+		Data:      discordInteractionData{CustomID: "/approve-action act_1"},
+		Member:    discordInteractionMember{User: discordAuthor{ID: "user-11"}},
+	})
+	if err != nil {
+		t.Fatalf("handleInteractionCreate failed: %v", err)
+	}
+	if len(commands.calls) != 1 || commands.calls[0].Text != "/approve-action act_1" {
+		t.Fatalf("expected button command to run, got %+v", commands.calls)
+	}
+}
+
+func TestHandleMessageCreateSendsRichReplyComponents(t *testing.T) {
+	commands := &fakeCommandGateway{
+		reply: "Pending actions",
+		rich: &reply.Message{
+			Title: "Pending actions",
+			Text:  "1. `act_1` restart api",
+			Buttons: []reply.Button{
+				{Label: "Approve 1", Command: "/approve-action act_1", Style: reply.ButtonPrimary},
+				{Label: "Deny 1", Command: "/deny-action act_1", Style: reply.ButtonDanger},
+			},
+		},
+	}
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_ = json.NewDecoder(req.Body).Decode(&sent)
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "msg-3"})
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	connector := New("bot-token", server.URL, "wss://discord.test/ws", t.TempDir(), &fakePairingStore{}, commands, nil, nil, logger)
+	err := connector.handleMessageCreate(context.Background(), discordMessageCreate{
+		ChannelID: "chan-1",
+		GuildID:   "guild-1",
+		Content:   "/pending-actions",
+		Author:    discordAuthor{ID: "user-2", Username: "operator"},
+	})
+	if err != nil {
+		t.Fatalf("handleMessageCreate failed: %v", err)
+	}
+	embeds, _ := sent["embeds"].([]any)
+	rows, _ := sent["components"].([]any)
+	if len(embeds) != 1 || len(rows) != 1 {
+		t.Fatalf("expected one embed and one action row, got %+v", sent)
+	}
+	buttons := rows[0].(map[string]any)["components"].([]any)
+	deny := buttons[1].(map[string]any)
+	if len(buttons) != 2 || deny["custom_id"] != "/deny-action act_1" || deny["style"] != float64(4) {
+		t.Fatalf("unexpected buttons %+v", buttons)
+	}
+}
+
 func TestHandleMessageCreatePairDM(t *testing.T) {
 	pairings := &fakePairingStore{}
 	commands := &fakeCommandGateway{}
@@ -546,3 +617,92 @@ func TestHandleMessageCreateLLMActionProposalQueuesApproval(t *testing.T) {
 		t.Fatalf("expected action id in compact notice, got %s", sentBody)
 	}
 }
+
+func TestDiscordSnowflakeTime(t *testing.T) {
+	created := discordSnowflakeTime("175928847299117063")
+	if want := time.Date(2016, 4, 30, 11, 18, 25, 796000000, time.UTC); !created.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, created)
+	}
+	if !discordSnowflakeTime("not-an-id").IsZero() {
+		t.Fatal("expected zero time for an invalid id")
+	}
+}
+
+func TestPublishRichTrackedAndEditRich(t *testing.T) {
+	var editMethod, editPath string
+	var edited map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPatch {
+			editMethod, editPath = req.Method, req.URL.Path
+			_ = json.NewDecoder(req.Body).Decode(&edited)
+		}
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "msg-9"})
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	connector := New("bot-token", server.URL, "wss://discord.test/ws", t.TempDir(), &fakePairingStore{}, &fakeCommandGateway{}, nil, nil, logger)
+	messageID, err := connector.PublishRichTracked(context.Background(), "chan-1", reply.Message{
+		Title:   "Approval needed",
+		Buttons: []reply.Button{{Label: "Approve", Command: "/approve-action act_1"}},
+	})
+	if err != nil || messageID != "msg-9" {
+		t.Fatalf("expected message id msg-9, got %q (%v)", messageID, err)
+	}
+	if err := connector.EditRich(context.Background(), "chan-1", messageID, reply.Message{Text: "Approved by admin-1"}); err != nil {
+		t.Fatalf("edit rich: %v", err)
+	}
+	components, ok := edited["components"].([]any)
+	if editMethod != http.MethodPatch || editPath != "/channels/chan-1/messages/msg-9" || !ok || len(components) != 0 {
+		t.Fatalf("unexpected edit %s %s %+v", editMethod, editPath, edited)
+	}
+	if edited["content"] != "Approved by admin-1" {
+		t.Fatalf("unexpected edited content %+v", edited)
+	}
+}
+
+func TestHandleMessageCreateRunsThreadsUnderTheirParentChannel(t *testing.T) {
+	lookups := 0
+	var sentPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/channels/thread-9":
+			lookups++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "thread-9", "type": 11, "parent_id": "chan-1"})
+		case req.Method == http.MethodPost:
+			sentPath = req.URL.Path
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "msg-3"})
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	workspace := t.TempDir()
+	commands := &fakeCommandGateway{reply: "Status: ok"}
+	connector := New("bot-token", server.URL, "wss://discord.test/ws", workspace, &fakePairingStore{}, commands, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	message := discordMessageCreate{
+		ChannelID: "thread-9",
+		GuildID:   "guild-1",
+		Content:   "/status",
+		Author:    discordAuthor{ID: "user-2", Username: "operator"},
+	}
+	for i := 0; i < 2; i++ {
+		if err := connector.handleMessageCreate(context.Background(), message); err != nil {
+			t.Fatalf("handleMessageCreate failed: %v", err)
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("expected the thread lookup cached, got %d lookups", lookups)
+	}
+	if commands.calls[0].ExternalID != "chan-1" || commands.calls[0].ThreadID != "thread-9" {
+		t.Fatalf("expected the thread run under its parent channel, got %+v", commands.calls[0])
+	}
+	if sentPath != "/channels/thread-9/messages" {
+		t.Fatalf("expected the reply posted in the thread, got %s", sentPath)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, "ws-1", "logs", "chats", "discord", "chan-1-thread-thread-9.md")); err != nil {
+		t.Fatalf("expected the thread logged apart from its channel: %v", err)
+	}
+}
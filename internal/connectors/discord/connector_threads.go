@@ -0,0 +1,101 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Channel types of announcement, public and private threads.
+const (
+	discordAnnouncementThread = 10
+	discordPublicThread       = 11
+	discordPrivateThread      = 12
+)
+
+type discordChannel struct {
+	ID       string `json:"id"`
+	Type     int    `json:"type"`
+	ParentID string `json:"parent_id"`
+}
+
+func (channel discordChannel) isThread() bool {
+	switch channel.Type {
+	case discordAnnouncementThread, discordPublicThread, discordPrivateThread:
+		return true
+	}
+	return false
+}
+
+// threadParent returns the parent channel of channelID when it is a thread,
+// or "" otherwise. Threads share their parent's context and policy but keep
+// their own conversation memory. Results are cached; a failed lookup treats
+// the channel as a plain one and is retried on the next message.
+func (c *Connector) threadParent(ctx context.Context, channelID string) string {
+	channelID = strings.TrimSpace(channelID)
+	if channelID == "" {
+		return ""
+	}
+	c.threadMu.Lock()
+	parentID, known := c.threadParents[channelID]
+	c.threadMu.Unlock()
+	if known {
+		return parentID
+	}
+	channel, err := c.fetchChannel(ctx, channelID)
+	if err != nil {
+		c.logger.Warn("discord channel lookup failed", "error", err, "channel_id", channelID)
+		return ""
+	}
+	return c.rememberChannel(channel)
+}
+
+// rememberChannel caches whether channel is a thread and returns its parent
+// channel, "" for channels that are not threads.
+func (c *Connector) rememberChannel(channel discordChannel) string {
+	channelID := strings.TrimSpace(channel.ID)
+	if channelID == "" {
+		return ""
+	}
+	parentID := ""
+	if channel.isThread() {
+		parentID = strings.TrimSpace(channel.ParentID)
+	}
+	c.threadMu.Lock()
+	defer c.threadMu.Unlock()
+	if c.threadParents == nil {
+		c.threadParents = map[string]string{}
+	}
+	c.threadParents[channelID] = parentID
+	return parentID
+}
+
+func (c *Connector) fetchChannel(ctx context.Context, channelID string) (discordChannel, error) {
+	url := fmt.Sprintf("%s/channels/%s", c.apiBase, channelID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return discordChannel{}, err
+	}
+	req.Header.Set("Authorization", "Bot "+c.token)
+	req.Header.Set("User-Agent", "agent-runtime/0.1")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return discordChannel{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return discordChannel{}, fmt.Errorf("discord channel lookup failed: status=%d body=%s", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
+	}
+
+	var channel discordChannel
+	if err := json.NewDecoder(res.Body).Decode(&channel); err != nil {
+		return discordChannel{}, fmt.Errorf("decode discord channel lookup: %w", err)
+	}
+	channel.ID = channelID
+	return channel, nil
+}
@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -66,6 +64,26 @@ type discordMessageCreate struct {
 	Author      discordAuthor       `json:"author"`
 	Attachments []discordAttachment `json:"attachments"`
 	Mentions    []discordAuthor     `json:"mentions"`
+
+	// parentID is set by the connector when ChannelID is a thread.
+	parentID string
+}
+
+// contextChannelID is the channel whose context and policy a message falls
+// under: the parent channel for messages sent in a thread.
+func (m discordMessageCreate) contextChannelID() string {
+	if m.parentID != "" {
+		return m.parentID
+	}
+	return m.ChannelID
+}
+
+// threadID is the thread a message was sent in, empty outside threads.
+func (m discordMessageCreate) threadID() string {
+	if m.parentID == "" {
+		return ""
+	}
+	return m.ChannelID
 }
 
 type discordInteractionCreate struct {
@@ -89,6 +107,8 @@ func (interaction discordInteractionCreate) userID() string {
 type discordInteractionData struct {
 	Name    string                     `json:"name"`
 	Options []discordInteractionOption `json:"options"`
+	// CustomID is the command carried by a pressed message button.
+	CustomID string `json:"custom_id"`
 }
 
 type discordInteractionOption struct {
@@ -137,27 +157,6 @@ type discordAttachment struct {
 	URL         string `json:"url"`
 }
 
-var filenameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
-
-func sanitizeFilename(input string) string {
-	base := strings.TrimSpace(filepath.Base(input))
-	base = filenameSanitizer.ReplaceAllString(base, "-")
-	base = strings.Trim(base, "-.")
-	if base == "" {
-		return "attachment.md"
-	}
-	return base
-}
-
-func isMarkdown(filename, mimeType string) bool {
-	extension := strings.ToLower(strings.TrimSpace(filepath.Ext(filename)))
-	if extension == ".md" || extension == ".markdown" {
-		return true
-	}
-	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
-	return mimeType == "text/markdown" || mimeType == "text/x-markdown"
-}
-
 func ioReadAllLimited(body io.Reader, maxBytes int64) ([]byte, error) {
 	limited := &io.LimitedReader{R: body, N: maxBytes + 1}
 	data, err := io.ReadAll(limited)
@@ -169,3 +168,17 @@ func ioReadAllLimited(body io.Reader, maxBytes int64) ([]byte, error) {
 	}
 	return data, nil
 }
+
+// discordEpochMillis is the first millisecond of 2015, the epoch of Discord
+// snowflake IDs.
+const discordEpochMillis = 1420070400000
+
+// discordSnowflakeTime returns when a Discord ID, such as a user's, was
+// created, or the zero time for an invalid ID.
+func discordSnowflakeTime(id string) time.Time {
+	value, err := strconv.ParseUint(strings.TrimSpace(id), 10, 64)
+	if err != nil || value == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(value>>22) + discordEpochMillis).UTC()
+}
@@ -7,19 +7,23 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/attachments"
+	"github.com/dwizi/agent-runtime/internal/llm"
 )
 
-func (c *Connector) ingestMarkdownDocument(ctx context.Context, message telegramMessage, document telegramDocument) (string, error) {
-	if c.workspace == "" || c.pairings == nil {
-		return "", nil
+// ingestDocument saves a supported document or photo into the workspace and
+// returns a summary reply plus the images to show a vision-capable model.
+func (c *Connector) ingestDocument(ctx context.Context, message telegramMessage, document telegramDocument) (string, []llm.Image, error) {
+	if c.workspace == "" || c.pairings == nil || c.attachments == nil {
+		return "", nil, nil
 	}
-	filename := sanitizeFilename(document.FileName)
-	if !isMarkdown(filename, document.MimeType) {
-		return "", nil
+	kind, ok := attachments.Detect(document.FileName, document.MimeType)
+	if !ok {
+		return "", nil, nil
 	}
 
 	contextRecord, err := c.pairings.EnsureContextForExternalChannel(
@@ -29,34 +33,40 @@ func (c *Connector) ingestMarkdownDocument(ctx context.Context, message telegram
 		message.Chat.Title,
 	)
 	if err != nil {
-		return "", err
-	}
-	workspacePath := filepath.Join(c.workspace, contextRecord.WorkspaceID)
-	targetDir := filepath.Join(workspacePath, "inbox", "telegram", strconv.FormatInt(message.Chat.ID, 10))
-	if err := os.MkdirAll(targetDir, 0o755); err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	filePath, err := c.lookupFilePath(ctx, document.FileID)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	fileContent, err := c.downloadFile(ctx, filePath)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	targetName := fmt.Sprintf("%d-%s", message.MessageID, filename)
-	targetPath := filepath.Join(targetDir, targetName)
-	if err := os.WriteFile(targetPath, fileContent, 0o644); err != nil {
-		return "", err
-	}
-
-	relativePath, err := filepath.Rel(workspacePath, targetPath)
+	result, err := c.attachments.Save(ctx, attachments.Input{
+		WorkspaceDir: filepath.Join(c.workspace, contextRecord.WorkspaceID),
+		Connector:    "telegram",
+		ChannelID:    strconv.FormatInt(message.Chat.ID, 10),
+		MessageID:    strconv.FormatInt(message.MessageID, 10),
+		Filename:     document.FileName,
+		ContentType:  document.MimeType,
+		Content:      fileContent,
+	})
 	if err != nil {
-		relativePath = targetName
+		return "", nil, err
+	}
+	if result.Note != "" {
+		c.logger.Warn("telegram attachment saved without text", "path", result.Path, "reason", result.Note)
+	}
+	images := []llm.Image{}
+	if kind == attachments.KindImage {
+		if image, ok := llm.NewImage(document.FileName, document.MimeType, fileContent); ok {
+			images = append(images, image)
+		}
 	}
-	return fmt.Sprintf("Attachment saved: `%s`", filepath.ToSlash(relativePath)), nil
+	return attachments.Summary([]attachments.Result{result}), images, nil
 }
 
 func (c *Connector) lookupFilePath(ctx context.Context, fileID string) (string, error) {
@@ -100,7 +110,7 @@ func (c *Connector) downloadFile(ctx context.Context, filePath string) ([]byte,
 	if res.StatusCode < 200 || res.StatusCode >= 300 {
 		return nil, fmt.Errorf("telegram file download failed with status %d", res.StatusCode)
 	}
-	return ioReadAllLimited(res.Body, 2<<20)
+	return ioReadAllLimited(res.Body, c.attachments.MaxBytes())
 }
 
 func (c *Connector) fetchBotUsername(ctx context.Context) (string, error) {
@@ -131,43 +141,85 @@ func (c *Connector) fetchBotUsername(ctx context.Context) (string, error) {
 }
 
 func (c *Connector) sendMessage(ctx context.Context, chatID int64, text string) error {
-	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", c.apiBase, c.token)
+	return c.sendMessageWithMarkup(ctx, chatID, text, nil)
+}
+
+// sendMessageWithMarkup sends text with an optional reply_markup, such as an
+// inline keyboard.
+func (c *Connector) sendMessageWithMarkup(ctx context.Context, chatID int64, text string, replyMarkup any) error {
+	_, err := c.postMessageWithMarkup(ctx, chatID, text, replyMarkup)
+	return err
+}
+
+// postMessageWithMarkup is sendMessageWithMarkup returning the id of the new
+// message, for messages that are edited later.
+func (c *Connector) postMessageWithMarkup(ctx context.Context, chatID int64, text string, replyMarkup any) (int64, error) {
 	body := map[string]any{
 		"chat_id":    chatID,
 		"text":       text,
 		"parse_mode": "Markdown",
 	}
+	if threadID := topicFrom(ctx); threadID != 0 {
+		body["message_thread_id"] = threadID
+	}
+	if replyMarkup != nil {
+		body["reply_markup"] = replyMarkup
+	}
+	return c.callMessageMethod(ctx, "sendMessage", body)
+}
+
+// editMessageWithMarkup replaces the text and inline keyboard of a message
+// the bot sent earlier.
+func (c *Connector) editMessageWithMarkup(ctx context.Context, chatID, messageID int64, text string, replyMarkup any) error {
+	body := map[string]any{
+		"chat_id":    chatID,
+		"message_id": messageID,
+		"text":       text,
+		"parse_mode": "Markdown",
+	}
+	if replyMarkup != nil {
+		body["reply_markup"] = replyMarkup
+	}
+	_, err := c.callMessageMethod(ctx, "editMessageText", body)
+	return err
+}
+
+// callMessageMethod posts body to a Bot API method that answers with a
+// message and returns that message's id, or 0 when the answer has none.
+func (c *Connector) callMessageMethod(ctx context.Context, method string, body map[string]any) (int64, error) {
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.apiBase, c.token, method)
 	payload, err := json.Marshal(body)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
 	if err != nil {
-		return err
+		return 0, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer res.Body.Close()
 
 	var response struct {
-		OK          bool   `json:"ok"`
-		ErrorCode   int    `json:"error_code"`
-		Description string `json:"description"`
+		OK          bool            `json:"ok"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
 	}
 	bodyBytes, err := io.ReadAll(io.LimitReader(res.Body, 8192))
 	if err != nil {
-		return fmt.Errorf("read sendMessage response: %w", err)
+		return 0, fmt.Errorf("read %s response: %w", method, err)
 	}
 	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		return fmt.Errorf("decode sendMessage: status=%d body=%q err=%w", res.StatusCode, strings.TrimSpace(string(bodyBytes)), err)
+		return 0, fmt.Errorf("decode %s: status=%d body=%q err=%w", method, res.StatusCode, strings.TrimSpace(string(bodyBytes)), err)
 	}
 	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
-		return fmt.Errorf("telegram sendMessage failed: status=%d body=%q", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
+		return 0, fmt.Errorf("telegram %s failed: status=%d body=%q", method, res.StatusCode, strings.TrimSpace(string(bodyBytes)))
 	}
 	if !response.OK {
 		description := strings.TrimSpace(response.Description)
@@ -175,9 +227,15 @@ func (c *Connector) sendMessage(ctx context.Context, chatID int64, text string)
 			description = strings.TrimSpace(string(bodyBytes))
 		}
 		if response.ErrorCode > 0 {
-			return fmt.Errorf("telegram sendMessage failed: status=%d error_code=%d description=%s", res.StatusCode, response.ErrorCode, description)
+			return 0, fmt.Errorf("telegram %s failed: status=%d error_code=%d description=%s", method, res.StatusCode, response.ErrorCode, description)
 		}
-		return fmt.Errorf("telegram sendMessage failed: status=%d description=%s", res.StatusCode, description)
+		return 0, fmt.Errorf("telegram %s failed: status=%d description=%s", method, res.StatusCode, description)
+	}
+	var message struct {
+		MessageID int64 `json:"message_id"`
+	}
+	if len(response.Result) > 0 && json.Unmarshal(response.Result, &message) == nil {
+		return message.MessageID, nil
 	}
-	return nil
+	return 0, nil
 }
@@ -124,6 +124,12 @@ func (c *Connector) pollOnce(ctx context.Context) error {
 		if update.UpdateID >= c.offset {
 			c.offset = update.UpdateID + 1
 		}
+		if update.CallbackQuery != nil {
+			if err := c.handleCallbackQuery(ctx, *update.CallbackQuery); err != nil {
+				c.logger.Error("handle callback query failed", "error", err, "update_id", update.UpdateID)
+			}
+			continue
+		}
 		if update.Message == nil {
 			continue
 		}
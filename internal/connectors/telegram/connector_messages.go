@@ -18,6 +18,7 @@ import (
 )
 
 func (c *Connector) handleMessage(ctx context.Context, message telegramMessage) error {
+	ctx = withTopic(ctx, message)
 	contextRecord, contextErr := c.pairings.EnsureContextForExternalChannel(
 		ctx,
 		"telegram",
@@ -55,12 +56,14 @@ func (c *Connector) handleMessage(ctx context.Context, message telegramMessage)
 	}
 
 	attachmentReply := ""
-	if message.Document != nil {
-		reply, err := c.ingestMarkdownDocument(ctx, message, *message.Document)
+	var images []llm.Image
+	if document := message.attachment(); document != nil {
+		reply, documentImages, err := c.ingestDocument(ctx, message, *document)
 		if err != nil {
-			c.logger.Error("markdown attachment ingest failed", "error", err, "chat_id", message.Chat.ID, "message_id", message.MessageID)
+			c.logger.Error("attachment ingest failed", "error", err, "chat_id", message.Chat.ID, "message_id", message.MessageID)
 		} else {
 			attachmentReply = strings.TrimSpace(reply)
+			images = documentImages
 		}
 	}
 
@@ -68,20 +71,25 @@ func (c *Connector) handleMessage(ctx context.Context, message telegramMessage)
 		if attachmentReply == "" {
 			return nil
 		}
-		c.logOutbound(contextRecord, message, attachmentReply)
-		return c.sendMessage(ctx, message.Chat.ID, attachmentReply)
+		return c.reply(ctx, contextRecord, message, attachmentReply)
 	}
 
 	output, err := c.gateway.HandleMessage(ctx, gateway.MessageInput{
 		Connector:   "telegram",
 		ExternalID:  strconv.FormatInt(message.Chat.ID, 10),
+		ThreadID:    message.topicID(),
 		DisplayName: message.Chat.Title,
 		FromUserID:  strconv.FormatInt(message.From.ID, 10),
 		Text:        text,
+		Images:      images,
+		IsBot:       message.From.IsBot,
 	})
 	if err != nil {
 		return err
 	}
+	if output.Suppressed {
+		return nil
+	}
 	trimmedGatewayReply := strings.TrimSpace(output.Reply)
 	if !output.Handled || trimmedGatewayReply == "" {
 		c.logger.Info(
@@ -94,7 +102,7 @@ func (c *Connector) handleMessage(ctx context.Context, message telegramMessage)
 		replyToSend := attachmentReply
 		shouldReply, isMention := c.shouldAutoReply(message, text)
 		if shouldReply {
-			llmReply, notice, llmErr := c.generateReply(ctx, contextRecord, message, text, isMention)
+			llmReply, notice, llmErr := c.generateReply(ctx, contextRecord, message, text, images, isMention)
 			if llmErr != nil {
 				c.logger.Error(
 					"telegram llm reply generation failed",
@@ -103,7 +111,9 @@ func (c *Connector) handleMessage(ctx context.Context, message telegramMessage)
 					"message_id", message.MessageID,
 					"is_mention", isMention,
 				)
-				if replyToSend == "" {
+				if replyToSend == "" && errors.Is(llmErr, llm.ErrUnavailable) {
+					replyToSend = "I can't reach my language model right now. Please try again later."
+				} else if replyToSend == "" {
 					replyToSend = "I started working on that but ran into an internal error. Please try again in a moment."
 				}
 			} else {
@@ -132,8 +142,7 @@ func (c *Connector) handleMessage(ctx context.Context, message telegramMessage)
 			)
 			return nil
 		}
-		c.logOutbound(contextRecord, message, replyToSend)
-		return c.sendMessage(ctx, message.Chat.ID, replyToSend)
+		return c.reply(ctx, contextRecord, message, replyToSend)
 	}
 	if attachmentReply != "" {
 		output.Reply = strings.TrimSpace(output.Reply) + "\n\n" + attachmentReply
@@ -141,8 +150,14 @@ func (c *Connector) handleMessage(ctx context.Context, message telegramMessage)
 	if strings.TrimSpace(output.Reply) == "" {
 		return nil
 	}
-	c.logOutbound(contextRecord, message, output.Reply)
-	return c.sendMessage(ctx, message.Chat.ID, output.Reply)
+	if output.Rich != nil {
+		rich := *output.Rich
+		if attachmentReply != "" {
+			rich.Text = strings.TrimSpace(rich.Text) + "\n\n" + attachmentReply
+		}
+		return c.replyRich(ctx, contextRecord, message, rich)
+	}
+	return c.reply(ctx, contextRecord, message, output.Reply)
 }
 
 func (c *Connector) shouldAutoReply(message telegramMessage, text string) (bool, bool) {
@@ -162,7 +177,7 @@ func (c *Connector) shouldAutoReply(message telegramMessage, text string) (bool,
 	return true, isMention
 }
 
-func (c *Connector) generateReply(ctx context.Context, contextRecord store.ContextRecord, message telegramMessage, text string, isMention bool) (string, string, error) {
+func (c *Connector) generateReply(ctx context.Context, contextRecord store.ContextRecord, message telegramMessage, text string, images []llm.Image, isMention bool) (string, string, error) {
 	if c.responder == nil {
 		return "", "", nil
 	}
@@ -209,6 +224,7 @@ func (c *Connector) generateReply(ctx context.Context, contextRecord store.Conte
 		WorkspaceID: contextRecord.WorkspaceID,
 		ContextID:   contextRecord.ID,
 		ExternalID:  strconv.FormatInt(message.Chat.ID, 10),
+		ThreadID:    message.topicID(),
 		DisplayName: message.Chat.Title,
 		FromUserID:  strconv.FormatInt(message.From.ID, 10),
 		Text:        prompt,
@@ -225,9 +241,11 @@ func (c *Connector) generateReply(ctx context.Context, contextRecord store.Conte
 		WorkspaceID: contextRecord.WorkspaceID,
 		ContextID:   contextRecord.ID,
 		ExternalID:  strconv.FormatInt(message.Chat.ID, 10),
+		ThreadID:    message.topicID(),
 		DisplayName: message.Chat.Title,
 		FromUserID:  strconv.FormatInt(message.From.ID, 10),
 		Text:        prompt,
+		Images:      images,
 		IsDM:        message.Chat.Type == "private",
 	})
 	if err != nil {
@@ -270,6 +288,7 @@ func (c *Connector) logInbound(contextRecord store.ContextRecord, message telegr
 		WorkspaceID:   contextRecord.WorkspaceID,
 		Connector:     "telegram",
 		ExternalID:    strconv.FormatInt(message.Chat.ID, 10),
+		ThreadID:      message.topicID(),
 		Direction:     "inbound",
 		ActorID:       strconv.FormatInt(message.From.ID, 10),
 		DisplayName:   message.Chat.Title,
@@ -290,6 +309,7 @@ func (c *Connector) logOutbound(contextRecord store.ContextRecord, message teleg
 		WorkspaceID:   contextRecord.WorkspaceID,
 		Connector:     "telegram",
 		ExternalID:    strconv.FormatInt(message.Chat.ID, 10),
+		ThreadID:      message.topicID(),
 		Direction:     "outbound",
 		ActorID:       "agent-runtime",
 		DisplayName:   message.Chat.Title,
@@ -0,0 +1,229 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/reply"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// inlineKeyboardWidth is the number of buttons per keyboard row; approval
+// prompts pair Approve and Deny.
+const inlineKeyboardWidth = 2
+
+// PublishRich sends a rich reply with buttons as an inline keyboard and
+// attachments as documents.
+func (c *Connector) PublishRich(ctx context.Context, externalID string, message reply.Message) error {
+	chatID, err := strconv.ParseInt(strings.TrimSpace(externalID), 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse telegram external id: %w", err)
+	}
+	return c.sendRich(ctx, chatID, message)
+}
+
+// PublishRichTracked sends a rich reply like PublishRich and returns the id
+// of its text message so EditRich can replace it later.
+func (c *Connector) PublishRichTracked(ctx context.Context, externalID string, message reply.Message) (string, error) {
+	chatID, err := strconv.ParseInt(strings.TrimSpace(externalID), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("parse telegram external id: %w", err)
+	}
+	body, markup := telegramRichText(message)
+	messageID := int64(0)
+	if body != "" {
+		messageID, err = c.postMessageWithMarkup(ctx, chatID, body, markup)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, attachment := range message.Attachments {
+		if err := c.sendDocument(ctx, chatID, attachment); err != nil {
+			return "", err
+		}
+	}
+	if messageID == 0 {
+		return "", nil
+	}
+	return strconv.FormatInt(messageID, 10), nil
+}
+
+// EditRich replaces the text and buttons of a message sent with
+// PublishRichTracked. A message without buttons loses its keyboard.
+func (c *Connector) EditRich(ctx context.Context, externalID, messageID string, message reply.Message) error {
+	chatID, err := strconv.ParseInt(strings.TrimSpace(externalID), 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse telegram external id: %w", err)
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(messageID), 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse telegram message id: %w", err)
+	}
+	body, markup := telegramRichText(message)
+	if markup == nil {
+		markup = map[string]any{"inline_keyboard": [][]map[string]string{}}
+	}
+	return c.editMessageWithMarkup(ctx, chatID, id, body, markup)
+}
+
+func (c *Connector) replyRich(ctx context.Context, contextRecord store.ContextRecord, message telegramMessage, rich reply.Message) error {
+	c.logOutbound(contextRecord, message, rich.PlainText())
+	if err := c.sendRich(ctx, message.Chat.ID, rich); err != nil {
+		return err
+	}
+	c.sendVoiceReply(ctx, contextRecord, message.Chat.ID, rich.Body())
+	return nil
+}
+
+func (c *Connector) sendRich(ctx context.Context, chatID int64, message reply.Message) error {
+	if body, markup := telegramRichText(message); body != "" {
+		if err := c.sendMessageWithMarkup(ctx, chatID, body, markup); err != nil {
+			return err
+		}
+	}
+	for _, attachment := range message.Attachments {
+		if err := c.sendDocument(ctx, chatID, attachment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// telegramRichText returns the text and optional inline keyboard of a rich
+// reply; the text is empty when there is nothing to send besides attachments.
+func telegramRichText(message reply.Message) (string, any) {
+	body := message.Body()
+	buttons := message.ValidButtons()
+	if body == "" && len(buttons) == 0 {
+		return "", nil
+	}
+	if body == "" {
+		body = "Choose an option:"
+	}
+	if len(buttons) == 0 {
+		return body, nil
+	}
+	return body, telegramInlineKeyboard(buttons)
+}
+
+func telegramInlineKeyboard(buttons []reply.Button) map[string]any {
+	rows := [][]map[string]string{}
+	for start := 0; start < len(buttons); start += inlineKeyboardWidth {
+		end := start + inlineKeyboardWidth
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		row := []map[string]string{}
+		for _, button := range buttons[start:end] {
+			row = append(row, map[string]string{"text": button.Label, "callback_data": button.Command})
+		}
+		rows = append(rows, row)
+	}
+	return map[string]any{"inline_keyboard": rows}
+}
+
+// handleCallbackQuery runs the command behind a pressed button as if the
+// person who pressed it had sent it in the chat of the button's message.
+func (c *Connector) handleCallbackQuery(ctx context.Context, query telegramCallbackQuery) error {
+	if err := c.answerCallbackQuery(ctx, query.ID); err != nil {
+		c.logger.Warn("telegram answerCallbackQuery failed", "error", err)
+	}
+	command := strings.TrimSpace(query.Data)
+	if query.Message == nil || command == "" {
+		return nil
+	}
+	return c.handleMessage(ctx, telegramMessage{
+		MessageID: query.Message.MessageID,
+		From:      query.From,
+		Chat:      query.Message.Chat,
+		Text:      command,
+
+		MessageThreadID: query.Message.MessageThreadID,
+		IsTopicMessage:  query.Message.IsTopicMessage,
+	})
+}
+
+func (c *Connector) answerCallbackQuery(ctx context.Context, queryID string) error {
+	if strings.TrimSpace(queryID) == "" {
+		return nil
+	}
+	payload, err := json.Marshal(map[string]string{"callback_query_id": queryID})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/bot%s/answerCallbackQuery", c.apiBase, c.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("telegram answerCallbackQuery failed with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+func (c *Connector) sendDocument(ctx context.Context, chatID int64, attachment reply.Attachment) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("chat_id", strconv.FormatInt(chatID, 10)); err != nil {
+		return err
+	}
+	if err := writeThreadField(ctx, writer); err != nil {
+		return err
+	}
+	fileName := strings.TrimSpace(attachment.Name)
+	if fileName == "" {
+		fileName = "attachment"
+	}
+	part, err := writer.CreateFormFile("document", fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(attachment.Data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendDocument", c.apiBase, c.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var response struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	bodyBytes, err := io.ReadAll(io.LimitReader(res.Body, 8192))
+	if err != nil {
+		return fmt.Errorf("read sendDocument response: %w", err)
+	}
+	if err := json.Unmarshal(bodyBytes, &response); err != nil {
+		return fmt.Errorf("decode sendDocument: status=%d body=%q err=%w", res.StatusCode, strings.TrimSpace(string(bodyBytes)), err)
+	}
+	if !response.OK {
+		return fmt.Errorf("telegram sendDocument failed: status=%d description=%s", res.StatusCode, strings.TrimSpace(response.Description))
+	}
+	return nil
+}
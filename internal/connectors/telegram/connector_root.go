@@ -7,11 +7,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/attachments"
 	"github.com/dwizi/agent-runtime/internal/gateway"
 	"github.com/dwizi/agent-runtime/internal/heartbeat"
 	"github.com/dwizi/agent-runtime/internal/llm"
 	llmsafety "github.com/dwizi/agent-runtime/internal/llm/safety"
 	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/tts"
 )
 
 const pairingMessage = "pair"
@@ -50,6 +52,10 @@ type Connector struct {
 	botUsername string
 	offset      int64
 	reporter    heartbeat.Reporter
+
+	voice         tts.Synthesizer
+	voicePolicies VoicePolicyStore
+	attachments   *attachments.Ingestor
 }
 
 type Option func(*Connector)
@@ -60,6 +66,16 @@ func WithCommandSync(enabled bool) Option {
 	}
 }
 
+// WithAttachmentIngestor replaces the default attachment pipeline, which
+// saves supported files and extracts their text for search.
+func WithAttachmentIngestor(ingestor *attachments.Ingestor) Option {
+	return func(connector *Connector) {
+		if ingestor != nil {
+			connector.attachments = ingestor
+		}
+	}
+}
+
 func New(token, apiBase, workspaceRoot string, pollSeconds int, pairings PairingStore, commandGateway CommandGateway, responder Responder, policy SafetyPolicy, logger *slog.Logger, opts ...Option) *Connector {
 	if strings.TrimSpace(apiBase) == "" {
 		apiBase = "https://api.telegram.org"
@@ -80,8 +96,9 @@ func New(token, apiBase, workspaceRoot string, pollSeconds int, pairings Pairing
 		httpClient: &http.Client{
 			Timeout: time.Duration(pollSeconds+10) * time.Second,
 		},
-		logger: logger,
-		offset: 0,
+		logger:      logger,
+		offset:      0,
+		attachments: attachments.New(attachments.Config{}),
 	}
 	for _, opt := range opts {
 		if opt != nil {
@@ -13,10 +13,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/attachments"
 	"github.com/dwizi/agent-runtime/internal/gateway"
 	"github.com/dwizi/agent-runtime/internal/llm"
 	llmsafety "github.com/dwizi/agent-runtime/internal/llm/safety"
+	"github.com/dwizi/agent-runtime/internal/reply"
 	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/tts"
 )
 
 type fakePairingStore struct {
@@ -80,6 +83,7 @@ func (f *fakePairingStore) CreateActionApproval(ctx context.Context, input store
 type fakeCommandGateway struct {
 	calls []gateway.MessageInput
 	reply string
+	rich  *reply.Message
 }
 
 func (f *fakeCommandGateway) HandleMessage(ctx context.Context, input gateway.MessageInput) (gateway.MessageOutput, error) {
@@ -90,16 +94,19 @@ func (f *fakeCommandGateway) HandleMessage(ctx context.Context, input gateway.Me
 	return gateway.MessageOutput{
 		Handled: true,
 		Reply:   f.reply,
+		Rich:    f.rich,
 	}, nil
 }
 
 type fakeResponder struct {
-	calls []string
-	reply string
+	calls  []string
+	images [][]llm.Image
+	reply  string
 }
 
 func (f *fakeResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
 	f.calls = append(f.calls, input.Text)
+	f.images = append(f.images, input.Images)
 	return f.reply, nil
 }
 
@@ -336,6 +343,188 @@ func TestPollOnceRunsCommandGateway(t *testing.T) {
 	}
 }
 
+func TestPollOnceRunsCallbackQueryAndSendsInlineKeyboard(t *testing.T) {
+	commands := &fakeCommandGateway{
+		reply: "Approved `act_1`.",
+		rich: &reply.Message{
+			Text:    "Approved `act_1`.",
+			Buttons: []reply.Button{{Label: "Undo", Command: "/deny-action act_2", Style: reply.ButtonDanger}},
+		},
+	}
+	var sentBody string
+	answered := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.Contains(req.URL.Path, "/getUpdates"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"ok": true,
+				"result": []map[string]any{
+					{
+						"update_id": 401,
+						"callback_query": map[string]any{
+							"id":   "cb-1",
+							"data": "/approve-action act_1",
+							"from": map[string]any{"id": 999, "first_name": "Operator"},
+							"message": map[string]any{
+								"message_id": 11,
+								"chat":       map[string]any{"id": 42, "type": "supergroup", "title": "ops"},
+							},
+						},
+					},
+				},
+			})
+		case strings.Contains(req.URL.Path, "/answerCallbackQuery"):
+			answered = true
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": true})
+		case strings.Contains(req.URL.Path, "/sendMessage"):
+			bytes, _ := io.ReadAll(req.Body)
+			sentBody = string(bytes)
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{}})
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	connector := New("test-token", server.URL, t.TempDir(), 1, &fakePairingStore{}, commands, nil, nil, logger)
+	if err := connector.pollOnce(context.Background()); err != nil {
+		t.Fatalf("pollOnce returned error: %v", err)
+	}
+	if !answered {
+		t.Fatal("expected callback query to be answered")
+	}
+	if len(commands.calls) != 1 || commands.calls[0].Text != "/approve-action act_1" || commands.calls[0].FromUserID != "999" {
+		t.Fatalf("expected button command to run as the presser, got %+v", commands.calls)
+	}
+	if !strings.Contains(sentBody, `"inline_keyboard"`) || !strings.Contains(sentBody, `"callback_data":"/deny-action act_2"`) {
+		t.Fatalf("expected inline keyboard in reply, got %s", sentBody)
+	}
+}
+
+func TestPollOnceSavesPhotoAttachment(t *testing.T) {
+	workspaceRoot := t.TempDir()
+	pairings := &fakePairingStore{workspaceID: "workspace-42"}
+	commands := &fakeCommandGateway{}
+	sentText := ""
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.Contains(req.URL.Path, "/getUpdates"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"ok": true,
+				"result": []map[string]any{
+					{
+						"update_id": 701,
+						"message": map[string]any{
+							"message_id": 89,
+							"chat":       map[string]any{"id": 42, "type": "supergroup", "title": "ops"},
+							"from":       map[string]any{"id": 999},
+							"photo": []map[string]any{
+								{"file_id": "thumb", "width": 90, "height": 90},
+								{"file_id": "full", "width": 1280, "height": 960},
+							},
+						},
+					},
+				},
+			})
+		case strings.Contains(req.URL.Path, "/getFile"):
+			if req.URL.Query().Get("file_id") != "full" {
+				t.Errorf("expected the largest photo size, got %s", req.URL.Query().Get("file_id"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"file_path": "photos/full.jpg"}})
+		case strings.Contains(req.URL.Path, "/file/bottest-token/"):
+			_, _ = w.Write([]byte("jpeg"))
+		case strings.Contains(req.URL.Path, "/sendMessage"):
+			var body map[string]any
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			sentText, _ = body["text"].(string)
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{}})
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ingestor := attachments.New(attachments.Config{OCRBinary: "agent-runtime-missing-ocr"})
+	connector := New("test-token", server.URL, workspaceRoot, 1, pairings, commands, nil, nil, logger, WithAttachmentIngestor(ingestor))
+	if err := connector.pollOnce(context.Background()); err != nil {
+		t.Fatalf("pollOnce returned error: %v", err)
+	}
+
+	target := filepath.Join(workspaceRoot, "workspace-42", "inbox", "telegram", "42", "89-photo.jpg")
+	if _, err := os.Stat(target); err != nil {
+		t.Fatalf("expected saved photo at %s: %v", target, err)
+	}
+	if !strings.Contains(sentText, "inbox/telegram/42/89-photo.jpg") || !strings.Contains(sentText, "not searchable") {
+		t.Fatalf("unexpected acknowledgment %q", sentText)
+	}
+}
+
+func TestPollOncePassesCaptionedPhotoToResponder(t *testing.T) {
+	pairings := &fakePairingStore{workspaceID: "workspace-42"}
+	commands := &fakeCommandGateway{}
+	responder := &fakeResponder{reply: "That is a timeout error."}
+	sentText := ""
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.Contains(req.URL.Path, "/getUpdates"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"ok": true,
+				"result": []map[string]any{
+					{
+						"update_id": 702,
+						"message": map[string]any{
+							"message_id": 90,
+							"caption":    "what does this error mean?",
+							"chat":       map[string]any{"id": 42, "type": "private"},
+							"from":       map[string]any{"id": 999},
+							"photo": []map[string]any{
+								{"file_id": "full", "width": 1280, "height": 960},
+							},
+						},
+					},
+				},
+			})
+		case strings.Contains(req.URL.Path, "/getFile"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"file_path": "photos/full.jpg"}})
+		case strings.Contains(req.URL.Path, "/file/bottest-token/"):
+			_, _ = w.Write([]byte("jpeg"))
+		case strings.Contains(req.URL.Path, "/sendMessage"):
+			var body map[string]any
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			sentText, _ = body["text"].(string)
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{}})
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ingestor := attachments.New(attachments.Config{OCRBinary: "agent-runtime-missing-ocr"})
+	connector := New("test-token", server.URL, t.TempDir(), 1, pairings, commands, responder, nil, logger, WithAttachmentIngestor(ingestor))
+	if err := connector.pollOnce(context.Background()); err != nil {
+		t.Fatalf("pollOnce returned error: %v", err)
+	}
+	if len(commands.calls) != 1 || len(commands.calls[0].Images) != 1 {
+		t.Fatalf("expected gateway input with one image, got %+v", commands.calls)
+	}
+	if len(responder.images) == 0 {
+		t.Fatal("expected responder call")
+	}
+	images := responder.images[len(responder.images)-1]
+	if len(images) != 1 || images[0].MediaType != "image/jpeg" || string(images[0].Data) != "jpeg" {
+		t.Fatalf("expected photo passed to responder, got %+v", images)
+	}
+	if !strings.Contains(sentText, "timeout error") {
+		t.Fatalf("expected model reply, got %q", sentText)
+	}
+}
+
 func TestPollOnceIngestsMarkdownAttachment(t *testing.T) {
 	workspaceRoot := t.TempDir()
 	pairings := &fakePairingStore{workspaceID: "workspace-42"}
@@ -655,3 +844,155 @@ func TestSendMessageIncludesTelegramErrorDetails(t *testing.T) {
 		t.Fatalf("expected telegram description in message, got %v", err)
 	}
 }
+
+type fakeSynthesizer struct {
+	texts []string
+}
+
+func (f *fakeSynthesizer) Synthesize(ctx context.Context, text string) (tts.Audio, error) {
+	f.texts = append(f.texts, text)
+	return tts.Audio{Data: []byte("OggS"), MIMEType: "audio/ogg", FileName: "reply.ogg"}, nil
+}
+
+type fakeVoicePolicies struct {
+	enabled bool
+}
+
+func (f *fakeVoicePolicies) LookupContextPolicy(ctx context.Context, contextID string) (store.ContextPolicy, error) {
+	return store.ContextPolicy{ContextID: contextID, VoiceReplies: f.enabled}, nil
+}
+
+func TestHandleMessageSendsVoiceReplyWhenContextOptsIn(t *testing.T) {
+	var voiceChatID, voiceAudio string
+	textSends := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/sendMessage"):
+			textSends++
+		case strings.HasSuffix(req.URL.Path, "/sendVoice"):
+			if err := req.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("parse multipart: %v", err)
+			}
+			voiceChatID = req.FormValue("chat_id")
+			file, _, err := req.FormFile("voice")
+			if err == nil {
+				data, _ := io.ReadAll(file)
+				voiceAudio = string(data)
+			}
+		default:
+			http.NotFound(w, req)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer server.Close()
+
+	synth := &fakeSynthesizer{}
+	policies := &fakeVoicePolicies{}
+	connector := New("test-token", server.URL, t.TempDir(), 1, &fakePairingStore{}, &fakeCommandGateway{reply: "Release is *done*."}, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), WithVoiceReplies(synth, policies))
+	message := telegramMessage{MessageID: 1, From: telegramUser{ID: 7}, Chat: telegramChat{ID: -100, Type: "group"}, Text: "/status"}
+
+	if err := connector.handleMessage(context.Background(), message); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if textSends != 1 || len(synth.texts) != 0 {
+		t.Fatalf("expected text-only reply while voice is off, got %d sends %v", textSends, synth.texts)
+	}
+
+	policies.enabled = true
+	if err := connector.handleMessage(context.Background(), message); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if textSends != 2 || voiceChatID != "-100" || voiceAudio != "OggS" {
+		t.Fatalf("expected text and voice reply, got %d sends chat=%q audio=%q", textSends, voiceChatID, voiceAudio)
+	}
+	if len(synth.texts) != 1 || synth.texts[0] != "Release is *done*." {
+		t.Fatalf("unexpected synthesized text %v", synth.texts)
+	}
+}
+
+func TestPublishRichTrackedReturnsMessageIDAndEditRemovesKeyboard(t *testing.T) {
+	var edited map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/sendMessage"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"message_id": 77}})
+		case strings.HasSuffix(req.URL.Path, "/editMessageText"):
+			_ = json.NewDecoder(req.Body).Decode(&edited)
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"message_id": 77}})
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	connector := New("test-token", server.URL, t.TempDir(), 1, &fakePairingStore{}, &fakeCommandGateway{}, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	messageID, err := connector.PublishRichTracked(context.Background(), "42", reply.Message{
+		Title:   "Approval needed",
+		Text:    "- id: `act_1`",
+		Buttons: []reply.Button{{Label: "Approve", Command: "/approve-action act_1"}},
+	})
+	if err != nil || messageID != "77" {
+		t.Fatalf("expected message id 77, got %q (%v)", messageID, err)
+	}
+	if err := connector.EditRich(context.Background(), "42", messageID, reply.Message{Title: "Approved", Text: "- approved by `admin-1`"}); err != nil {
+		t.Fatalf("edit rich: %v", err)
+	}
+	keyboard, _ := edited["reply_markup"].(map[string]any)
+	rows, _ := keyboard["inline_keyboard"].([]any)
+	if edited["message_id"] != float64(77) || keyboard == nil || len(rows) != 0 {
+		t.Fatalf("expected an edit that clears the keyboard, got %+v", edited)
+	}
+	if text, _ := edited["text"].(string); !strings.Contains(text, "approved by") {
+		t.Fatalf("unexpected edited text %q", text)
+	}
+}
+
+func TestHandleMessageKeepsForumTopicsApart(t *testing.T) {
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasSuffix(req.URL.Path, "/sendMessage") {
+			http.NotFound(w, req)
+			return
+		}
+		_ = json.NewDecoder(req.Body).Decode(&sent)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"message_id": 5}})
+	}))
+	defer server.Close()
+
+	workspace := t.TempDir()
+	commands := &fakeCommandGateway{reply: "On it."}
+	connector := New("test-token", server.URL, workspace, 1, &fakePairingStore{}, commands, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	message := telegramMessage{
+		MessageID:       3,
+		From:            telegramUser{ID: 7},
+		Chat:            telegramChat{ID: 100, Type: "supergroup", Title: "ops"},
+		Text:            "/status",
+		MessageThreadID: 12,
+		IsTopicMessage:  true,
+	}
+	if err := connector.handleMessage(context.Background(), message); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if len(commands.calls) != 1 || commands.calls[0].ExternalID != "100" || commands.calls[0].ThreadID != "12" {
+		t.Fatalf("expected the topic passed as a thread of chat 100, got %+v", commands.calls)
+	}
+	if threadID, _ := sent["message_thread_id"].(float64); threadID != 12 {
+		t.Fatalf("expected the reply sent to topic 12, got %v", sent)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, "ws-1", "logs", "chats", "telegram", "100-thread-12.md")); err != nil {
+		t.Fatalf("expected the topic logged apart from the chat: %v", err)
+	}
+
+	message.IsTopicMessage = false
+	sent = nil
+	if err := connector.handleMessage(context.Background(), message); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if commands.calls[1].ThreadID != "" {
+		t.Fatalf("expected replies outside topics to stay in the chat, got %q", commands.calls[1].ThreadID)
+	}
+	if _, ok := sent["message_thread_id"]; ok {
+		t.Fatalf("expected no thread on the reply, got %v", sent)
+	}
+}
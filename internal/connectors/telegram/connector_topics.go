@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"context"
+	"mime/multipart"
+	"strconv"
+)
+
+type topicContextKey struct{}
+
+// topicID is the forum topic of a message as a thread ID, empty outside
+// topics. Replies to it in other groups carry a message_thread_id too, so
+// only topic messages count.
+func (m telegramMessage) topicID() string {
+	if !m.IsTopicMessage || m.MessageThreadID == 0 {
+		return ""
+	}
+	return strconv.FormatInt(m.MessageThreadID, 10)
+}
+
+// withTopic makes messages sent while handling message go to its forum
+// topic instead of the group's general topic.
+func withTopic(ctx context.Context, message telegramMessage) context.Context {
+	if message.topicID() == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, topicContextKey{}, message.MessageThreadID)
+}
+
+func topicFrom(ctx context.Context) int64 {
+	threadID, _ := ctx.Value(topicContextKey{}).(int64)
+	return threadID
+}
+
+func writeThreadField(ctx context.Context, writer *multipart.Writer) error {
+	threadID := topicFrom(ctx)
+	if threadID == 0 {
+		return nil
+	}
+	return writer.WriteField("message_thread_id", strconv.FormatInt(threadID, 10))
+}
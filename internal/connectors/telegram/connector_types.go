@@ -3,7 +3,6 @@ package telegram
 import (
 	"fmt"
 	"io"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -38,8 +37,18 @@ type getUpdatesResponse struct {
 }
 
 type telegramUpdate struct {
-	UpdateID int64            `json:"update_id"`
-	Message  *telegramMessage `json:"message"`
+	UpdateID      int64                  `json:"update_id"`
+	Message       *telegramMessage       `json:"message"`
+	CallbackQuery *telegramCallbackQuery `json:"callback_query"`
+}
+
+// telegramCallbackQuery is a press of an inline keyboard button; Data holds
+// the button's command.
+type telegramCallbackQuery struct {
+	ID      string           `json:"id"`
+	From    telegramUser     `json:"from"`
+	Message *telegramMessage `json:"message"`
+	Data    string           `json:"data"`
 }
 
 type telegramMessage struct {
@@ -49,6 +58,11 @@ type telegramMessage struct {
 	Text      string            `json:"text"`
 	Caption   string            `json:"caption"`
 	Document  *telegramDocument `json:"document"`
+	Photo     []telegramPhoto   `json:"photo"`
+	// MessageThreadID and IsTopicMessage are set for messages sent in a
+	// forum topic.
+	MessageThreadID int64 `json:"message_thread_id"`
+	IsTopicMessage  bool  `json:"is_topic_message"`
 }
 
 type telegramChat struct {
@@ -62,6 +76,7 @@ type telegramUser struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Username  string `json:"username"`
+	IsBot     bool   `json:"is_bot"`
 }
 
 type telegramDocument struct {
@@ -70,28 +85,28 @@ type telegramDocument struct {
 	MimeType string `json:"mime_type"`
 }
 
-var filenameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
-var telegramCommandSanitizer = regexp.MustCompile(`[^a-z0-9_]+`)
-
-func sanitizeFilename(input string) string {
-	base := strings.TrimSpace(filepath.Base(input))
-	base = filenameSanitizer.ReplaceAllString(base, "-")
-	base = strings.Trim(base, "-.")
-	if base == "" {
-		return "attachment.md"
-	}
-	return base
+// telegramPhoto is one size of a photo; Telegram lists them smallest first.
+type telegramPhoto struct {
+	FileID string `json:"file_id"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
-func isMarkdown(filename, mimeType string) bool {
-	extension := strings.ToLower(strings.TrimSpace(filepath.Ext(filename)))
-	if extension == ".md" || extension == ".markdown" {
-		return true
+// attachment returns the file carried by the message, using the largest size
+// of a photo, or nil when there is none.
+func (m telegramMessage) attachment() *telegramDocument {
+	if m.Document != nil {
+		return m.Document
+	}
+	if len(m.Photo) == 0 {
+		return nil
 	}
-	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
-	return mimeType == "text/markdown" || mimeType == "text/x-markdown"
+	largest := m.Photo[len(m.Photo)-1]
+	return &telegramDocument{FileID: largest.FileID, FileName: "photo.jpg", MimeType: "image/jpeg"}
 }
 
+var telegramCommandSanitizer = regexp.MustCompile(`[^a-z0-9_]+`)
+
 func ioReadAllLimited(body io.Reader, maxBytes int64) ([]byte, error) {
 	limited := &io.LimitedReader{R: body, N: maxBytes + 1}
 	data, err := io.ReadAll(limited)
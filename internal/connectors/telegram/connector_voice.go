@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/tts"
+)
+
+// VoicePolicyStore reports whether a context opted into voice replies.
+type VoicePolicyStore interface {
+	LookupContextPolicy(ctx context.Context, contextID string) (store.ContextPolicy, error)
+}
+
+// WithVoiceReplies sends a spoken copy of each reply to contexts that
+// enabled voice replies with /voice on.
+func WithVoiceReplies(synthesizer tts.Synthesizer, policies VoicePolicyStore) Option {
+	return func(connector *Connector) {
+		connector.voice = synthesizer
+		connector.voicePolicies = policies
+	}
+}
+
+func (c *Connector) reply(ctx context.Context, contextRecord store.ContextRecord, message telegramMessage, text string) error {
+	c.logOutbound(contextRecord, message, text)
+	if err := c.sendMessage(ctx, message.Chat.ID, text); err != nil {
+		return err
+	}
+	c.sendVoiceReply(ctx, contextRecord, message.Chat.ID, text)
+	return nil
+}
+
+// sendVoiceReply follows a text reply with a voice note. Failures are
+// logged; the text reply has already been delivered.
+func (c *Connector) sendVoiceReply(ctx context.Context, contextRecord store.ContextRecord, chatID int64, text string) {
+	if c.voice == nil || c.voicePolicies == nil || strings.TrimSpace(contextRecord.ID) == "" {
+		return
+	}
+	policy, err := c.voicePolicies.LookupContextPolicy(ctx, contextRecord.ID)
+	if err != nil || !policy.VoiceReplies {
+		return
+	}
+	audio, err := c.voice.Synthesize(ctx, text)
+	if errors.Is(err, tts.ErrEmptyText) {
+		return
+	}
+	if err != nil {
+		c.logger.Warn("telegram voice synthesis failed", "error", err, "chat_id", chatID)
+		return
+	}
+	if err := c.sendVoice(ctx, chatID, audio); err != nil {
+		c.logger.Warn("telegram sendVoice failed", "error", err, "chat_id", chatID)
+	}
+}
+
+func (c *Connector) sendVoice(ctx context.Context, chatID int64, audio tts.Audio) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("chat_id", strconv.FormatInt(chatID, 10)); err != nil {
+		return err
+	}
+	if err := writeThreadField(ctx, writer); err != nil {
+		return err
+	}
+	fileName := audio.FileName
+	if strings.TrimSpace(fileName) == "" {
+		fileName = "reply.ogg"
+	}
+	part, err := writer.CreateFormFile("voice", fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(audio.Data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendVoice", c.apiBase, c.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var response struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	bodyBytes, err := io.ReadAll(io.LimitReader(res.Body, 8192))
+	if err != nil {
+		return fmt.Errorf("read sendVoice response: %w", err)
+	}
+	if err := json.Unmarshal(bodyBytes, &response); err != nil {
+		return fmt.Errorf("decode sendVoice: status=%d body=%q err=%w", res.StatusCode, strings.TrimSpace(string(bodyBytes)), err)
+	}
+	if !response.OK {
+		return fmt.Errorf("telegram sendVoice failed: status=%d description=%s", res.StatusCode, strings.TrimSpace(response.Description))
+	}
+	return nil
+}
@@ -0,0 +1,500 @@
+package dataquery
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// builtins maps supported function names to their accepted arities.
+var builtins = map[string][]int{
+	"length": {0}, "keys": {0}, "values": {0}, "type": {0}, "not": {0},
+	"first": {0, 1}, "last": {0}, "reverse": {0}, "sort": {0}, "unique": {0},
+	"add": {0}, "min": {0}, "max": {0}, "flatten": {0}, "any": {0}, "all": {0},
+	"empty": {0}, "floor": {0}, "tostring": {0}, "tonumber": {0},
+	"ascii_downcase": {0}, "ascii_upcase": {0}, "to_entries": {0}, "from_entries": {0},
+	"select": {1}, "map": {1}, "has": {1}, "sort_by": {1}, "group_by": {1},
+	"unique_by": {1}, "min_by": {1}, "max_by": {1}, "join": {1}, "contains": {1},
+	"startswith": {1}, "endswith": {1}, "split": {1}, "test": {1}, "limit": {2},
+}
+
+type funcNode struct {
+	name string
+	args []node
+}
+
+func newFuncNode(name string, args []node) (node, error) {
+	arities, ok := builtins[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown function %s", ErrSyntax, name)
+	}
+	for _, arity := range arities {
+		if arity == len(args) {
+			return funcNode{name: name, args: args}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s does not take %d arguments", ErrSyntax, name, len(args))
+}
+
+func (n funcNode) eval(input any, emit emitFunc) error {
+	switch n.name {
+	case "empty":
+		return nil
+	case "select":
+		return n.args[0].eval(input, func(value any) error {
+			if truthy(value) {
+				return emit(input)
+			}
+			return nil
+		})
+	case "map":
+		items, err := asArray(input, n.name)
+		if err != nil {
+			return err
+		}
+		mapped := []any{}
+		for _, item := range items {
+			if err := n.args[0].eval(item, func(value any) error {
+				mapped = append(mapped, value)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return emit(mapped)
+	case "first":
+		if len(n.args) == 1 {
+			err := n.args[0].eval(input, func(value any) error {
+				if err := emit(value); err != nil {
+					return err
+				}
+				return errStop
+			})
+			if err == errStop {
+				return nil
+			}
+			return err
+		}
+		return n.indexFrom(input, 0, emit)
+	case "last":
+		return n.indexFrom(input, -1, emit)
+	case "limit":
+		count, err := singleValue(n.args[0], input)
+		if err != nil {
+			return err
+		}
+		remaining, ok := count.(float64)
+		if !ok {
+			return typeErrorf("limit count must be a number")
+		}
+		if remaining <= 0 {
+			return nil
+		}
+		err = n.args[1].eval(input, func(value any) error {
+			if err := emit(value); err != nil {
+				return err
+			}
+			remaining--
+			if remaining <= 0 {
+				return errStop
+			}
+			return nil
+		})
+		if err == errStop {
+			return nil
+		}
+		return err
+	case "sort_by", "group_by", "unique_by", "min_by", "max_by":
+		return n.evalBy(input, emit)
+	}
+
+	// The remaining builtins take plain value arguments.
+	args := make([]any, len(n.args))
+	for index, arg := range n.args {
+		value, err := singleValue(arg, input)
+		if err != nil {
+			return err
+		}
+		args[index] = value
+	}
+	value, err := n.apply(input, args)
+	if err != nil {
+		return err
+	}
+	return emit(value)
+}
+
+var errStop = fmt.Errorf("stop")
+
+func (n funcNode) indexFrom(input any, index int, emit emitFunc) error {
+	items, err := asArray(input, n.name)
+	if err != nil {
+		return err
+	}
+	value, _ := indexValue(items, float64(index))
+	return emit(value)
+}
+
+func (n funcNode) evalBy(input any, emit emitFunc) error {
+	items, err := asArray(input, n.name)
+	if err != nil {
+		return err
+	}
+	type keyed struct {
+		key  any
+		item any
+	}
+	entries := make([]keyed, 0, len(items))
+	for _, item := range items {
+		key := []any{}
+		if err := n.args[0].eval(item, func(value any) error {
+			key = append(key, value)
+			return nil
+		}); err != nil {
+			return err
+		}
+		entries = append(entries, keyed{key: key, item: item})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return compareValues(entries[i].key, entries[j].key) < 0 })
+	switch n.name {
+	case "sort_by":
+		sorted := make([]any, len(entries))
+		for index, entry := range entries {
+			sorted[index] = entry.item
+		}
+		return emit(sorted)
+	case "min_by", "max_by":
+		if len(entries) == 0 {
+			return emit(nil)
+		}
+		if n.name == "min_by" {
+			return emit(entries[0].item)
+		}
+		return emit(entries[len(entries)-1].item)
+	}
+	groups := []any{}
+	var current []any
+	for index, entry := range entries {
+		if index > 0 && compareValues(entries[index-1].key, entry.key) == 0 {
+			if n.name == "group_by" {
+				current = append(current, entry.item)
+			}
+			continue
+		}
+		if current != nil {
+			groups = append(groups, finishGroup(n.name, current))
+		}
+		current = []any{entry.item}
+	}
+	if current != nil {
+		groups = append(groups, finishGroup(n.name, current))
+	}
+	return emit(groups)
+}
+
+func finishGroup(name string, group []any) any {
+	if name == "unique_by" {
+		return group[0]
+	}
+	return group
+}
+
+func (n funcNode) apply(input any, args []any) (any, error) {
+	switch n.name {
+	case "length":
+		switch typed := input.(type) {
+		case nil:
+			return float64(0), nil
+		case bool:
+			return nil, typeErrorf("boolean has no length")
+		case float64:
+			return math.Abs(typed), nil
+		case string:
+			return float64(len([]rune(typed))), nil
+		case []any:
+			return float64(len(typed)), nil
+		case map[string]any:
+			return float64(len(typed)), nil
+		}
+	case "keys":
+		switch typed := input.(type) {
+		case map[string]any:
+			return stringsToAny(sortedKeys(typed)), nil
+		case []any:
+			keys := make([]any, len(typed))
+			for index := range typed {
+				keys[index] = float64(index)
+			}
+			return keys, nil
+		}
+		return nil, typeErrorf("%s has no keys", typeName(input))
+	case "values":
+		switch typed := input.(type) {
+		case map[string]any:
+			values := []any{}
+			for _, key := range sortedKeys(typed) {
+				values = append(values, typed[key])
+			}
+			return values, nil
+		case []any:
+			return typed, nil
+		}
+		return nil, typeErrorf("%s has no values", typeName(input))
+	case "type":
+		return typeName(input), nil
+	case "not":
+		return !truthy(input), nil
+	case "reverse", "sort", "unique", "add", "min", "max", "flatten", "any", "all":
+		items, err := asArray(input, n.name)
+		if err != nil {
+			return nil, err
+		}
+		return applyArray(n.name, items)
+	case "floor":
+		if number, ok := input.(float64); ok {
+			return math.Floor(number), nil
+		}
+		return nil, typeErrorf("%s cannot be floored", typeName(input))
+	case "tostring":
+		if text, ok := input.(string); ok {
+			return text, nil
+		}
+		return formatValue(input), nil
+	case "tonumber":
+		switch typed := input.(type) {
+		case float64:
+			return typed, nil
+		case string:
+			number, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+			if err != nil {
+				return nil, typeErrorf("cannot parse %q as a number", typed)
+			}
+			return number, nil
+		}
+		return nil, typeErrorf("%s cannot be parsed as a number", typeName(input))
+	case "ascii_downcase", "ascii_upcase":
+		text, ok := input.(string)
+		if !ok {
+			return nil, typeErrorf("%s requires a string", n.name)
+		}
+		if n.name == "ascii_downcase" {
+			return strings.ToLower(text), nil
+		}
+		return strings.ToUpper(text), nil
+	case "to_entries":
+		object, ok := input.(map[string]any)
+		if !ok {
+			return nil, typeErrorf("to_entries requires an object")
+		}
+		entries := []any{}
+		for _, key := range sortedKeys(object) {
+			entries = append(entries, map[string]any{"key": key, "value": object[key]})
+		}
+		return entries, nil
+	case "from_entries":
+		items, err := asArray(input, n.name)
+		if err != nil {
+			return nil, err
+		}
+		object := map[string]any{}
+		for _, item := range items {
+			entry, ok := item.(map[string]any)
+			if !ok {
+				return nil, typeErrorf("from_entries requires objects")
+			}
+			key := entry["key"]
+			if key == nil {
+				key = entry["name"]
+			}
+			name, ok := key.(string)
+			if !ok {
+				name = formatValue(key)
+			}
+			object[name] = entry["value"]
+		}
+		return object, nil
+	case "has":
+		switch typed := input.(type) {
+		case map[string]any:
+			key, ok := args[0].(string)
+			if !ok {
+				return nil, typeErrorf("cannot check object for %s key", typeName(args[0]))
+			}
+			_, exists := typed[key]
+			return exists, nil
+		case []any:
+			index, ok := args[0].(float64)
+			if !ok {
+				return nil, typeErrorf("cannot check array for %s key", typeName(args[0]))
+			}
+			return index >= 0 && int(index) < len(typed), nil
+		}
+		return nil, typeErrorf("%s has no keys", typeName(input))
+	case "join":
+		items, err := asArray(input, n.name)
+		if err != nil {
+			return nil, err
+		}
+		separator, ok := args[0].(string)
+		if !ok {
+			return nil, typeErrorf("join separator must be a string")
+		}
+		parts := make([]string, len(items))
+		for index, item := range items {
+			if item == nil {
+				continue
+			}
+			if text, ok := item.(string); ok {
+				parts[index] = text
+				continue
+			}
+			parts[index] = formatValue(item)
+		}
+		return strings.Join(parts, separator), nil
+	case "contains":
+		return containsDeep(input, args[0]), nil
+	case "startswith", "endswith", "split", "test":
+		text, ok := input.(string)
+		pattern, patternOK := args[0].(string)
+		if !ok || !patternOK {
+			return nil, typeErrorf("%s requires string input and argument", n.name)
+		}
+		switch n.name {
+		case "startswith":
+			return strings.HasPrefix(text, pattern), nil
+		case "endswith":
+			return strings.HasSuffix(text, pattern), nil
+		case "split":
+			return stringsToAny(strings.Split(text, pattern)), nil
+		default:
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, typeErrorf("invalid regex: %v", err)
+			}
+			return re.MatchString(text), nil
+		}
+	}
+	return nil, typeErrorf("%s cannot be applied to %s", n.name, typeName(input))
+}
+
+func applyArray(name string, items []any) (any, error) {
+	switch name {
+	case "reverse":
+		reversed := make([]any, len(items))
+		for index, item := range items {
+			reversed[len(items)-1-index] = item
+		}
+		return reversed, nil
+	case "sort", "unique":
+		sorted := append([]any{}, items...)
+		sort.SliceStable(sorted, func(i, j int) bool { return compareValues(sorted[i], sorted[j]) < 0 })
+		if name == "sort" {
+			return sorted, nil
+		}
+		unique := []any{}
+		for index, item := range sorted {
+			if index == 0 || compareValues(sorted[index-1], item) != 0 {
+				unique = append(unique, item)
+			}
+		}
+		return unique, nil
+	case "add":
+		var total any
+		for _, item := range items {
+			sum, err := applyBinary("+", total, item)
+			if err != nil {
+				return nil, err
+			}
+			total = sum
+		}
+		return total, nil
+	case "min", "max":
+		if len(items) == 0 {
+			return nil, nil
+		}
+		best := items[0]
+		for _, item := range items[1:] {
+			cmp := compareValues(item, best)
+			if (name == "min" && cmp < 0) || (name == "max" && cmp > 0) {
+				best = item
+			}
+		}
+		return best, nil
+	case "flatten":
+		flat := []any{}
+		for _, item := range items {
+			if nested, ok := item.([]any); ok {
+				inner, _ := applyArray("flatten", nested)
+				flat = append(flat, inner.([]any)...)
+				continue
+			}
+			flat = append(flat, item)
+		}
+		return flat, nil
+	case "any":
+		for _, item := range items {
+			if truthy(item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		for _, item := range items {
+			if !truthy(item) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+func containsDeep(haystack, needle any) bool {
+	switch typed := haystack.(type) {
+	case string:
+		text, ok := needle.(string)
+		return ok && strings.Contains(typed, text)
+	case []any:
+		wanted, ok := needle.([]any)
+		if !ok {
+			return false
+		}
+		for _, want := range wanted {
+			found := false
+			for _, item := range typed {
+				if containsDeep(item, want) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		wanted, ok := needle.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, want := range wanted {
+			value, exists := typed[key]
+			if !exists || !containsDeep(value, want) {
+				return false
+			}
+		}
+		return true
+	default:
+		return compareValues(haystack, needle) == 0
+	}
+}
+
+func asArray(input any, name string) ([]any, error) {
+	items, ok := input.([]any)
+	if !ok {
+		return nil, typeErrorf("%s requires an array, got %s", name, typeName(input))
+	}
+	return items, nil
+}
@@ -0,0 +1,107 @@
+package dataquery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decode parses JSON or YAML content into plain values (maps, slices,
+// float64, string, bool, nil). format is "json", "yaml", or "" to detect.
+func Decode(content []byte, format string) (any, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	trimmed := bytes.TrimSpace(content)
+	if format == "" {
+		format = "yaml"
+		if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+			format = "json"
+		}
+	}
+	switch format {
+	case "json":
+		var value any
+		if err := json.Unmarshal(trimmed, &value); err != nil {
+			return nil, fmt.Errorf("decode json: %w", err)
+		}
+		return value, nil
+	case "yaml", "yml":
+		var value any
+		if err := yaml.Unmarshal(trimmed, &value); err != nil {
+			return nil, fmt.Errorf("decode yaml: %w", err)
+		}
+		return normalize(value)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// normalize converts YAML-decoded values into the JSON value model.
+func normalize(value any) (any, error) {
+	switch typed := value.(type) {
+	case nil, bool, string, float64:
+		return typed, nil
+	case int:
+		return float64(typed), nil
+	case int64:
+		return float64(typed), nil
+	case uint64:
+		return float64(typed), nil
+	case float32:
+		return float64(typed), nil
+	case []any:
+		items := make([]any, len(typed))
+		for index, item := range typed {
+			normalized, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			items[index] = normalized
+		}
+		return items, nil
+	case map[string]any:
+		object := make(map[string]any, len(typed))
+		for key, item := range typed {
+			normalized, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = normalized
+		}
+		return object, nil
+	case map[any]any:
+		object := make(map[string]any, len(typed))
+		for key, item := range typed {
+			normalized, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			object[fmt.Sprint(key)] = normalized
+		}
+		return object, nil
+	default:
+		// Timestamps and other tagged scalars keep their textual form.
+		return fmt.Sprint(typed), nil
+	}
+}
+
+// Format renders a result as compact JSON; with raw set, strings are
+// printed without quotes like `jq -r`.
+func Format(value any, raw bool) string {
+	if text, ok := value.(string); ok && raw {
+		return text
+	}
+	return formatValue(value)
+}
+
+func formatValue(value any) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimRight(buffer.String(), "\n")
+}
@@ -0,0 +1,495 @@
+package dataquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// value holds the decoded string or number literal.
+	value any
+	// spaced reports whether whitespace preceded the token, so `.foo` and
+	// `. foo` can be told apart.
+	spaced bool
+}
+
+func tokenize(expr string) ([]token, error) {
+	runes := []rune(expr)
+	tokens := []token{}
+	spaced := false
+	for index := 0; index < len(runes); {
+		r := runes[index]
+		if unicode.IsSpace(r) {
+			spaced = true
+			index++
+			continue
+		}
+		start := index
+		switch {
+		case r == '"':
+			index++
+			escaped := false
+			for index < len(runes) {
+				if escaped {
+					escaped = false
+				} else if runes[index] == '\\' {
+					escaped = true
+				} else if runes[index] == '"' {
+					break
+				}
+				index++
+			}
+			if index >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrSyntax)
+			}
+			index++
+			raw := string(runes[start:index])
+			var decoded string
+			if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+				return nil, fmt.Errorf("%w: bad string literal %s", ErrSyntax, raw)
+			}
+			tokens = append(tokens, token{kind: tokString, text: raw, value: decoded, spaced: spaced})
+		case unicode.IsDigit(r):
+			for index < len(runes) && (unicode.IsDigit(runes[index]) || runes[index] == '.' || runes[index] == 'e' || runes[index] == 'E') {
+				index++
+			}
+			raw := string(runes[start:index])
+			number, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad number %s", ErrSyntax, raw)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: raw, value: number, spaced: spaced})
+		case r == '_' || unicode.IsLetter(r):
+			for index < len(runes) && (runes[index] == '_' || unicode.IsLetter(runes[index]) || unicode.IsDigit(runes[index])) {
+				index++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: string(runes[start:index]), spaced: spaced})
+		default:
+			text := string(r)
+			if index+1 < len(runes) {
+				switch pair := string(runes[index : index+2]); pair {
+				case "..", "==", "!=", "<=", ">=", "//":
+					text = pair
+				}
+			}
+			if len(text) == 1 && !strings.ContainsRune(".|,()[]{}:;?<>+-*/%", r) {
+				return nil, fmt.Errorf("%w: unexpected character %q", ErrSyntax, r)
+			}
+			index += len(text)
+			tokens = append(tokens, token{kind: tokPunct, text: text, spaced: spaced})
+		}
+		spaced = false
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isPunct(text string) bool {
+	tok := p.peek()
+	return tok.kind == tokPunct && tok.text == text
+}
+
+func (p *parser) isKeyword(text string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && tok.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.isPunct(text) {
+		return fmt.Errorf("%w: expected %q, got %q", ErrSyntax, text, p.peek().text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parsePipe() (node, error) {
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	if p.isPunct("|") {
+		p.next()
+		right, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return pipeNode{left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseComma() (node, error) {
+	left, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct(",") {
+		p.next()
+		right, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		left = commaNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAlt() (node, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.isPunct("//") {
+		p.next()
+		right, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: "//", left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.isPunct(op) {
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return binaryNode{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("*") || p.isPunct("/") || p.isPunct("%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isPunct("-") {
+		p.next()
+		inner, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{inner: inner}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	target, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.isPunct("?"):
+			p.next()
+			target = optionalNode{inner: target}
+		case p.isPunct("[") && !p.peek().spaced:
+			p.next()
+			target, err = p.parseBracketSuffix(target)
+			if err != nil {
+				return nil, err
+			}
+		case p.isPunct(".") && p.fieldFollows():
+			p.next()
+			if p.isPunct("[") {
+				p.next()
+				target, err = p.parseBracketSuffix(target)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			key := p.next()
+			target = indexNode{target: target, index: literalNode{value: fieldName(key)}}
+		default:
+			return target, nil
+		}
+	}
+}
+
+// fieldFollows reports whether the "." at the cursor starts a field access
+// such as `.name`, `."name"`, or `.[0]` rather than a new expression.
+func (p *parser) fieldFollows() bool {
+	if p.pos+1 >= len(p.tokens) {
+		return false
+	}
+	next := p.tokens[p.pos+1]
+	if next.spaced {
+		return false
+	}
+	return next.kind == tokIdent || next.kind == tokString || (next.kind == tokPunct && next.text == "[")
+}
+
+func fieldName(tok token) string {
+	if tok.kind == tokString {
+		return tok.value.(string)
+	}
+	return tok.text
+}
+
+// parseBracketSuffix handles the part after "[": iteration, index, or slice.
+func (p *parser) parseBracketSuffix(target node) (node, error) {
+	if p.isPunct("]") {
+		p.next()
+		return iterateNode{target: target}, nil
+	}
+	var from node
+	if !p.isPunct(":") {
+		expr, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		from = expr
+	}
+	if p.isPunct(":") {
+		p.next()
+		var to node
+		if !p.isPunct("]") {
+			expr, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			to = expr
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return sliceNode{target: target, from: from, to: to}, nil
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return indexNode{target: target, index: from}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokNumber, tokString:
+		p.next()
+		return literalNode{value: tok.value}, nil
+	case tokIdent:
+		return p.parseIdent()
+	case tokEOF:
+		return nil, fmt.Errorf("%w: unexpected end of expression", ErrSyntax)
+	}
+	switch tok.text {
+	case ".":
+		if p.fieldFollows() {
+			// Leave the "." for parsePostfix so `.a.b` chains naturally.
+			return identityNode{}, nil
+		}
+		p.next()
+		return identityNode{}, nil
+	case "..":
+		p.next()
+		return recurseNode{}, nil
+	case "(":
+		p.next()
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case "[":
+		p.next()
+		if p.isPunct("]") {
+			p.next()
+			return arrayNode{}, nil
+		}
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return arrayNode{inner: inner}, nil
+	case "{":
+		p.next()
+		return p.parseObject()
+	}
+	return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, tok.text)
+}
+
+func (p *parser) parseIdent() (node, error) {
+	tok := p.next()
+	switch tok.text {
+	case "true":
+		return literalNode{value: true}, nil
+	case "false":
+		return literalNode{value: false}, nil
+	case "null":
+		return literalNode{value: nil}, nil
+	}
+	args := []node{}
+	if p.isPunct("(") {
+		p.next()
+		for {
+			arg, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.isPunct(";") {
+				p.next()
+				continue
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return newFuncNode(tok.text, args)
+}
+
+func (p *parser) parseObject() (node, error) {
+	entries := []objectEntry{}
+	for !p.isPunct("}") {
+		if len(entries) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		tok := p.peek()
+		var key node
+		shorthand := ""
+		switch {
+		case tok.kind == tokIdent || tok.kind == tokString:
+			p.next()
+			shorthand = fieldName(tok)
+			key = literalNode{value: shorthand}
+		case tok.kind == tokPunct && tok.text == "(":
+			p.next()
+			expr, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			key = expr
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q in object", ErrSyntax, tok.text)
+		}
+		if !p.isPunct(":") {
+			if shorthand == "" {
+				return nil, fmt.Errorf("%w: computed object keys need a value", ErrSyntax)
+			}
+			entries = append(entries, objectEntry{key: key, value: indexNode{target: identityNode{}, index: literalNode{value: shorthand}}})
+			continue
+		}
+		p.next()
+		value, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, objectEntry{key: key, value: value})
+	}
+	p.next()
+	return objectNode{entries: entries}, nil
+}
@@ -0,0 +1,585 @@
+// Package dataquery evaluates a practical subset of jq expressions against
+// decoded JSON or YAML documents.
+//
+// Supported: paths (.a.b, ."key", .[0], .[1:3], .[], ..), optional access (?),
+// pipes, commas, parentheses, array and object construction, literals,
+// comparison/arithmetic operators, and/or/not, the // alternative operator,
+// and the builtins listed in builtins.
+package dataquery
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const maxResults = 10000
+
+var ErrSyntax = errors.New("invalid query")
+
+// Query is a parsed expression.
+type Query struct {
+	root node
+}
+
+// Parse compiles an expression.
+func Parse(expr string) (*Query, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, p.peek().text)
+	}
+	return &Query{root: root}, nil
+}
+
+// Run evaluates the query against input and returns every emitted value.
+func (q *Query) Run(input any) ([]any, error) {
+	results := []any{}
+	err := q.root.eval(input, func(value any) error {
+		if len(results) >= maxResults {
+			return fmt.Errorf("query produced more than %d results", maxResults)
+		}
+		results = append(results, value)
+		return nil
+	})
+	return results, err
+}
+
+type emitFunc func(any) error
+
+type node interface {
+	eval(input any, emit emitFunc) error
+}
+
+// queryError is a recoverable evaluation error; `?` and `//` swallow it.
+type queryError struct{ msg string }
+
+func (e *queryError) Error() string { return e.msg }
+
+func typeErrorf(format string, args ...any) error {
+	return &queryError{msg: fmt.Sprintf(format, args...)}
+}
+
+type identityNode struct{}
+
+func (identityNode) eval(input any, emit emitFunc) error { return emit(input) }
+
+type recurseNode struct{}
+
+func (recurseNode) eval(input any, emit emitFunc) error {
+	if err := emit(input); err != nil {
+		return err
+	}
+	switch typed := input.(type) {
+	case []any:
+		for _, item := range typed {
+			if err := (recurseNode{}).eval(item, emit); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, key := range sortedKeys(typed) {
+			if err := (recurseNode{}).eval(typed[key], emit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type literalNode struct{ value any }
+
+func (n literalNode) eval(_ any, emit emitFunc) error { return emit(n.value) }
+
+type pipeNode struct{ left, right node }
+
+func (n pipeNode) eval(input any, emit emitFunc) error {
+	return n.left.eval(input, func(value any) error {
+		return n.right.eval(value, emit)
+	})
+}
+
+type commaNode struct{ left, right node }
+
+func (n commaNode) eval(input any, emit emitFunc) error {
+	if err := n.left.eval(input, emit); err != nil {
+		return err
+	}
+	return n.right.eval(input, emit)
+}
+
+type indexNode struct {
+	target node
+	index  node
+}
+
+func (n indexNode) eval(input any, emit emitFunc) error {
+	return n.target.eval(input, func(container any) error {
+		return n.index.eval(input, func(key any) error {
+			value, err := indexValue(container, key)
+			if err != nil {
+				return err
+			}
+			return emit(value)
+		})
+	})
+}
+
+type sliceNode struct {
+	target   node
+	from, to node
+}
+
+func (n sliceNode) eval(input any, emit emitFunc) error {
+	return n.target.eval(input, func(container any) error {
+		var length int
+		switch typed := container.(type) {
+		case nil:
+			return emit(nil)
+		case []any:
+			length = len(typed)
+		case string:
+			length = len([]rune(typed))
+		default:
+			return typeErrorf("cannot slice %s", typeName(container))
+		}
+		start, end := 0, length
+		if n.from != nil {
+			value, err := singleValue(n.from, input)
+			if err != nil {
+				return err
+			}
+			start = clampIndex(value, length)
+		}
+		if n.to != nil {
+			value, err := singleValue(n.to, input)
+			if err != nil {
+				return err
+			}
+			end = clampIndex(value, length)
+		}
+		if end < start {
+			end = start
+		}
+		switch typed := container.(type) {
+		case []any:
+			return emit(append([]any{}, typed[start:end]...))
+		default:
+			return emit(string([]rune(typed.(string))[start:end]))
+		}
+	})
+}
+
+type iterateNode struct{ target node }
+
+func (n iterateNode) eval(input any, emit emitFunc) error {
+	return n.target.eval(input, func(container any) error {
+		switch typed := container.(type) {
+		case []any:
+			for _, item := range typed {
+				if err := emit(item); err != nil {
+					return err
+				}
+			}
+			return nil
+		case map[string]any:
+			for _, key := range sortedKeys(typed) {
+				if err := emit(typed[key]); err != nil {
+					return err
+				}
+			}
+			return nil
+		default:
+			return typeErrorf("cannot iterate over %s", typeName(container))
+		}
+	})
+}
+
+type optionalNode struct{ inner node }
+
+func (n optionalNode) eval(input any, emit emitFunc) error {
+	err := n.inner.eval(input, emit)
+	var qErr *queryError
+	if errors.As(err, &qErr) {
+		return nil
+	}
+	return err
+}
+
+type arrayNode struct{ inner node }
+
+func (n arrayNode) eval(input any, emit emitFunc) error {
+	items := []any{}
+	if n.inner != nil {
+		if err := n.inner.eval(input, func(value any) error {
+			items = append(items, value)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return emit(items)
+}
+
+type objectEntry struct {
+	key   node
+	value node
+}
+
+type objectNode struct{ entries []objectEntry }
+
+func (n objectNode) eval(input any, emit emitFunc) error {
+	return n.build(input, 0, map[string]any{}, emit)
+}
+
+// build expands every combination of key/value outputs, as jq does.
+func (n objectNode) build(input any, index int, current map[string]any, emit emitFunc) error {
+	if index == len(n.entries) {
+		copied := make(map[string]any, len(current))
+		for key, value := range current {
+			copied[key] = value
+		}
+		return emit(copied)
+	}
+	entry := n.entries[index]
+	return entry.key.eval(input, func(rawKey any) error {
+		key, ok := rawKey.(string)
+		if !ok {
+			return typeErrorf("object keys must be strings, got %s", typeName(rawKey))
+		}
+		return entry.value.eval(input, func(value any) error {
+			previous, existed := current[key]
+			current[key] = value
+			err := n.build(input, index+1, current, emit)
+			if existed {
+				current[key] = previous
+			} else {
+				delete(current, key)
+			}
+			return err
+		})
+	})
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n binaryNode) eval(input any, emit emitFunc) error {
+	switch n.op {
+	case "and", "or":
+		return n.left.eval(input, func(left any) error {
+			if n.op == "and" && !truthy(left) {
+				return emit(false)
+			}
+			if n.op == "or" && truthy(left) {
+				return emit(true)
+			}
+			return n.right.eval(input, func(right any) error {
+				return emit(truthy(right))
+			})
+		})
+	case "//":
+		found := false
+		err := n.left.eval(input, func(left any) error {
+			if truthy(left) {
+				found = true
+				return emit(left)
+			}
+			return nil
+		})
+		var qErr *queryError
+		if err != nil && !errors.As(err, &qErr) {
+			return err
+		}
+		if found {
+			return nil
+		}
+		return n.right.eval(input, emit)
+	}
+	return n.right.eval(input, func(right any) error {
+		return n.left.eval(input, func(left any) error {
+			value, err := applyBinary(n.op, left, right)
+			if err != nil {
+				return err
+			}
+			return emit(value)
+		})
+	})
+}
+
+type negateNode struct{ inner node }
+
+func (n negateNode) eval(input any, emit emitFunc) error {
+	return n.inner.eval(input, func(value any) error {
+		number, ok := value.(float64)
+		if !ok {
+			return typeErrorf("cannot negate %s", typeName(value))
+		}
+		return emit(-number)
+	})
+}
+
+func singleValue(n node, input any) (any, error) {
+	var result any
+	found := false
+	err := n.eval(input, func(value any) error {
+		if !found {
+			result = value
+			found = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func indexValue(container, key any) (any, error) {
+	switch typed := container.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		name, ok := key.(string)
+		if !ok {
+			return nil, typeErrorf("cannot index object with %s", typeName(key))
+		}
+		return typed[name], nil
+	case []any:
+		number, ok := key.(float64)
+		if !ok {
+			return nil, typeErrorf("cannot index array with %s", typeName(key))
+		}
+		index := int(math.Floor(number))
+		if index < 0 {
+			index += len(typed)
+		}
+		if index < 0 || index >= len(typed) {
+			return nil, nil
+		}
+		return typed[index], nil
+	default:
+		return nil, typeErrorf("cannot index %s", typeName(container))
+	}
+}
+
+func clampIndex(value any, length int) int {
+	number, ok := value.(float64)
+	if !ok {
+		return 0
+	}
+	index := int(math.Floor(number))
+	if index < 0 {
+		index += length
+	}
+	return max(0, min(index, length))
+}
+
+func applyBinary(op string, left, right any) (any, error) {
+	switch op {
+	case "==":
+		return compareValues(left, right) == 0, nil
+	case "!=":
+		return compareValues(left, right) != 0, nil
+	case "<":
+		return compareValues(left, right) < 0, nil
+	case "<=":
+		return compareValues(left, right) <= 0, nil
+	case ">":
+		return compareValues(left, right) > 0, nil
+	case ">=":
+		return compareValues(left, right) >= 0, nil
+	case "+":
+		if left == nil {
+			return right, nil
+		}
+		if right == nil {
+			return left, nil
+		}
+		switch l := left.(type) {
+		case float64:
+			if r, ok := right.(float64); ok {
+				return l + r, nil
+			}
+		case string:
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		case []any:
+			if r, ok := right.([]any); ok {
+				return append(append([]any{}, l...), r...), nil
+			}
+		case map[string]any:
+			if r, ok := right.(map[string]any); ok {
+				merged := make(map[string]any, len(l)+len(r))
+				for key, value := range l {
+					merged[key] = value
+				}
+				for key, value := range r {
+					merged[key] = value
+				}
+				return merged, nil
+			}
+		}
+	case "-", "*", "/", "%":
+		l, lok := left.(float64)
+		r, rok := right.(float64)
+		if lok && rok {
+			switch op {
+			case "-":
+				return l - r, nil
+			case "*":
+				return l * r, nil
+			case "/":
+				if r == 0 {
+					return nil, typeErrorf("division by zero")
+				}
+				return l / r, nil
+			case "%":
+				if int64(r) == 0 {
+					return nil, typeErrorf("modulo by zero")
+				}
+				return float64(int64(l) % int64(r)), nil
+			}
+		}
+		if leftItems, ok := left.([]any); ok && op == "-" {
+			if rightItems, ok := right.([]any); ok {
+				kept := []any{}
+				for _, item := range leftItems {
+					if !containsValue(rightItems, item) {
+						kept = append(kept, item)
+					}
+				}
+				return kept, nil
+			}
+		}
+	}
+	return nil, typeErrorf("cannot apply %s to %s and %s", op, typeName(left), typeName(right))
+}
+
+func containsValue(items []any, target any) bool {
+	for _, item := range items {
+		if compareValues(item, target) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func truthy(value any) bool {
+	if value == nil {
+		return false
+	}
+	if boolean, ok := value.(bool); ok {
+		return boolean
+	}
+	return true
+}
+
+// typeOrder follows jq's sort order: null, false, true, numbers, strings, arrays, objects.
+func typeOrder(value any) int {
+	switch typed := value.(type) {
+	case nil:
+		return 0
+	case bool:
+		if typed {
+			return 2
+		}
+		return 1
+	case float64:
+		return 3
+	case string:
+		return 4
+	case []any:
+		return 5
+	default:
+		return 6
+	}
+}
+
+func compareValues(left, right any) int {
+	lo, ro := typeOrder(left), typeOrder(right)
+	if lo != ro {
+		return lo - ro
+	}
+	switch l := left.(type) {
+	case float64:
+		r := right.(float64)
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(l, right.(string))
+	case []any:
+		r := right.([]any)
+		for index := 0; index < len(l) && index < len(r); index++ {
+			if cmp := compareValues(l[index], r[index]); cmp != 0 {
+				return cmp
+			}
+		}
+		return len(l) - len(r)
+	case map[string]any:
+		r := right.(map[string]any)
+		lk, rk := sortedKeys(l), sortedKeys(r)
+		if cmp := compareValues(stringsToAny(lk), stringsToAny(rk)); cmp != 0 {
+			return cmp
+		}
+		for _, key := range lk {
+			if cmp := compareValues(l[key], r[key]); cmp != 0 {
+				return cmp
+			}
+		}
+	}
+	return 0
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringsToAny(items []string) []any {
+	values := make([]any, len(items))
+	for index, item := range items {
+		values[index] = item
+	}
+	return values
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,106 @@
+package dataquery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const sampleJSON = `{
+	"name": "svc",
+	"replicas": 3,
+	"tags": ["a", "b"],
+	"items": [
+		{"id": 1, "status": "ok", "owner": {"login": "sam"}},
+		{"id": 2, "status": "failed", "owner": null},
+		{"id": 3, "status": "failed", "owner": {"login": "kim"}}
+	]
+}`
+
+func runQuery(t *testing.T, expr string, input any) string {
+	t.Helper()
+	query, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("parse %q: %v", expr, err)
+	}
+	results, err := query.Run(input)
+	if err != nil {
+		t.Fatalf("run %q: %v", expr, err)
+	}
+	lines := make([]string, len(results))
+	for index, result := range results {
+		lines[index] = Format(result, false)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestRunExpressions(t *testing.T) {
+	input, err := Decode([]byte(sampleJSON), "")
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{".", `{"items":[{"id":1,"owner":{"login":"sam"},"status":"ok"},{"id":2,"owner":null,"status":"failed"},{"id":3,"owner":{"login":"kim"},"status":"failed"}],"name":"svc","replicas":3,"tags":["a","b"]}`},
+		{".name", `"svc"`},
+		{`."name"`, `"svc"`},
+		{".tags[1]", `"b"`},
+		{".tags[-1]", `"b"`},
+		{".items[0].owner.login", `"sam"`},
+		{".items[1:].[0].id", `2`},
+		{".items[].id", "1\n2\n3"},
+		{".items | length", `3`},
+		{`[.items[] | select(.status == "failed") | .id]`, `[2,3]`},
+		{`.items | map(.owner.login // "unassigned")`, `["sam","unassigned","kim"]`},
+		{".items[] | .owner?.login", "\"sam\"\nnull\n\"kim\""},
+		{".missing.deeper", `null`},
+		{"keys", `["items","name","replicas","tags"]`},
+		{".replicas * 2 + 1", `7`},
+		{`{name, count: (.items | length)}`, `{"count":3,"name":"svc"}`},
+		{`.items | group_by(.status) | map({status: .[0].status, n: length})`, `[{"n":2,"status":"failed"},{"n":1,"status":"ok"}]`},
+		{`.items | sort_by(.id) | reverse | first | .id`, `3`},
+		{`.tags | join(",")`, `"a,b"`},
+		{`[.items[].status] | unique`, `["failed","ok"]`},
+		{`.replicas > 2 and (.name | startswith("s"))`, `true`},
+		{`[limit(2; .items[].id)]`, `[1,2]`},
+		{`.name | test("^s.c$")`, `true`},
+	}
+	for _, tc := range cases {
+		if got := runQuery(t, tc.expr, input); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	input, err := Decode([]byte("services:\n  web:\n    image: nginx\n    ports: [80, 443]\n  db:\n    image: postgres\n"), "yaml")
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := runQuery(t, ".services | to_entries | map(.key + \"=\" + .value.image) | join(\" \")", input); got != `"db=postgres web=nginx"` {
+		t.Fatalf("unexpected yaml result %s", got)
+	}
+	if got := runQuery(t, ".services.web.ports | add", input); got != "523" {
+		t.Fatalf("unexpected ports sum %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{".a[", "foo", "select()", `.a | "unterminated`, ".a ="} {
+		if _, err := Parse(expr); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: expected syntax error, got %v", expr, err)
+		}
+	}
+}
+
+func TestRunTypeErrors(t *testing.T) {
+	query, err := Parse(".name[0]")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if _, err := query.Run(map[string]any{"name": "svc"}); err == nil {
+		t.Fatal("expected type error indexing a string")
+	}
+}
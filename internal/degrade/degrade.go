@@ -0,0 +1,244 @@
+// Package degrade tracks whether the model provider is reachable and backs
+// the runtime's degraded mode: while the provider is down, chat is answered
+// from curated FAQ entries, requests are accepted as tasks that wait for the
+// provider to come back, and objectives that need the model are skipped.
+package degrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/heartbeat"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+const (
+	// ComponentName is the heartbeat component that reports provider state.
+	ComponentName = "llm"
+	probePrompt   = "Reply with OK."
+)
+
+// Tier is the service level the runtime currently offers.
+type Tier string
+
+const (
+	// TierFull answers with the model.
+	TierFull Tier = "full"
+	// TierDegraded answers from curated content and defers model work.
+	TierDegraded Tier = "degraded"
+)
+
+type Config struct {
+	// DownAfterFailures is the number of consecutive failed model calls that
+	// switch the runtime to degraded mode.
+	DownAfterFailures int
+	// ProbeInterval is how often a down provider is probed.
+	ProbeInterval time.Duration
+}
+
+type Engine interface {
+	Enqueue(task orchestrator.Task) (orchestrator.Task, error)
+}
+
+// Status is a snapshot of the provider state.
+type Status struct {
+	Tier      Tier
+	Since     time.Time
+	Failures  int
+	LastError string
+	Deferred  int
+}
+
+type Monitor struct {
+	cfg      Config
+	engine   Engine
+	logger   *slog.Logger
+	reporter heartbeat.Reporter
+
+	mu        sync.Mutex
+	probe     llm.Responder
+	failures  int
+	down      bool
+	since     time.Time
+	lastError string
+	deferred  []orchestrator.Task
+}
+
+func New(cfg Config, engine Engine, logger *slog.Logger) *Monitor {
+	if cfg.DownAfterFailures < 1 {
+		cfg.DownAfterFailures = 3
+	}
+	if cfg.ProbeInterval < time.Second {
+		cfg.ProbeInterval = 30 * time.Second
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Monitor{cfg: cfg, engine: engine, logger: logger, since: time.Now().UTC()}
+}
+
+func (m *Monitor) SetHeartbeatReporter(reporter heartbeat.Reporter) {
+	m.reporter = reporter
+}
+
+// WrapResponder observes the outcome of every call to the provider client
+// and, while the provider is down, fails calls fast with llm.ErrUnavailable
+// instead of waiting for another timeout. The first wrapped responder is
+// also used to probe the provider.
+func (m *Monitor) WrapResponder(next llm.Responder) llm.Responder {
+	m.mu.Lock()
+	if m.probe == nil {
+		m.probe = next
+	}
+	m.mu.Unlock()
+	return &monitoredResponder{next: next, monitor: m}
+}
+
+type monitoredResponder struct {
+	next    llm.Responder
+	monitor *Monitor
+}
+
+func (r *monitoredResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	if err := r.monitor.unavailableErr(); err != nil {
+		return "", err
+	}
+	reply, err := r.next.Reply(ctx, input)
+	r.monitor.observe(ctx, err)
+	return reply, err
+}
+
+// Available reports whether model calls are expected to work.
+func (m *Monitor) Available() bool {
+	if m == nil {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.down
+}
+
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := Status{Tier: TierFull, Since: m.since, Failures: m.failures, LastError: m.lastError, Deferred: len(m.deferred)}
+	if m.down {
+		status.Tier = TierDegraded
+	}
+	return status
+}
+
+// Defer holds a task accepted in degraded mode until the provider is back.
+// The task must already be persisted as queued, so a restart recovers it
+// like any other queued task.
+func (m *Monitor) Defer(task orchestrator.Task) {
+	m.mu.Lock()
+	m.deferred = append(m.deferred, task)
+	m.mu.Unlock()
+	m.logger.Info("task deferred until llm recovers", "task_id", task.ID, "workspace_id", task.WorkspaceID)
+}
+
+// Start probes the provider every ProbeInterval while it is down.
+func (m *Monitor) Start(ctx context.Context) error {
+	ticker := time.NewTicker(m.cfg.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if m.Available() {
+			if m.reporter != nil {
+				m.reporter.Beat(ComponentName, "provider available")
+			}
+			continue
+		}
+		m.runProbe(ctx)
+	}
+}
+
+func (m *Monitor) runProbe(ctx context.Context) {
+	m.mu.Lock()
+	probe := m.probe
+	m.mu.Unlock()
+	if probe == nil {
+		return
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, m.cfg.ProbeInterval)
+	defer cancel()
+	_, err := probe.Reply(probeCtx, llm.MessageInput{Text: probePrompt, SkipGrounding: true})
+	if ctx.Err() != nil {
+		return
+	}
+	m.observe(context.Background(), err)
+}
+
+func (m *Monitor) unavailableErr() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.down {
+		return nil
+	}
+	return fmt.Errorf("%w: provider down since %s", llm.ErrUnavailable, m.since.Format(time.RFC3339))
+}
+
+// observe records the outcome of one provider call. Calls abandoned by their
+// caller say nothing about the provider and are ignored.
+func (m *Monitor) observe(ctx context.Context, err error) {
+	if err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled)) {
+		return
+	}
+	m.mu.Lock()
+	if err == nil {
+		wasDown := m.down
+		m.failures = 0
+		m.lastError = ""
+		if !wasDown {
+			m.mu.Unlock()
+			return
+		}
+		m.down = false
+		m.since = time.Now().UTC()
+		deferred := m.deferred
+		m.deferred = nil
+		m.mu.Unlock()
+		m.logger.Info("llm provider recovered", "deferred_tasks", len(deferred))
+		if m.reporter != nil {
+			m.reporter.Beat(ComponentName, "provider available")
+		}
+		m.release(deferred)
+		return
+	}
+	m.failures++
+	m.lastError = strings.TrimSpace(err.Error())
+	if m.down || m.failures < m.cfg.DownAfterFailures {
+		m.mu.Unlock()
+		return
+	}
+	m.down = true
+	m.since = time.Now().UTC()
+	failures := m.failures
+	m.mu.Unlock()
+	m.logger.Warn("llm provider unavailable, switching to degraded mode", "consecutive_failures", failures, "error", err)
+	if m.reporter != nil {
+		m.reporter.Degrade(ComponentName, "provider unavailable; serving degraded replies", err)
+	}
+}
+
+func (m *Monitor) release(tasks []orchestrator.Task) {
+	if m.engine == nil {
+		return
+	}
+	for _, task := range tasks {
+		if _, err := m.engine.Enqueue(task); err != nil {
+			m.logger.Error("enqueue deferred task failed", "task_id", task.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package degrade
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+type fakeResponder struct {
+	err   error
+	calls int
+}
+
+func (f *fakeResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	f.calls++
+	if f.err != nil {
+		return "", f.err
+	}
+	return "OK", nil
+}
+
+type fakeEngine struct {
+	queued []orchestrator.Task
+}
+
+func (f *fakeEngine) Enqueue(task orchestrator.Task) (orchestrator.Task, error) {
+	f.queued = append(f.queued, task)
+	return task, nil
+}
+
+func TestMonitorDegradesAfterFailuresAndReleasesDeferredTasksOnRecovery(t *testing.T) {
+	provider := &fakeResponder{err: errors.New("openai completion failed with status 503")}
+	engine := &fakeEngine{}
+	monitor := New(Config{DownAfterFailures: 2, ProbeInterval: time.Second}, engine, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	responder := monitor.WrapResponder(provider)
+	ctx := context.Background()
+
+	_, _ = responder.Reply(ctx, llm.MessageInput{Text: "hi"})
+	if !monitor.Available() {
+		t.Fatal("expected one failure to keep the provider available")
+	}
+	_, _ = responder.Reply(ctx, llm.MessageInput{Text: "hi"})
+	if monitor.Available() || monitor.Status().Tier != TierDegraded {
+		t.Fatalf("expected degraded mode after two failures, got %+v", monitor.Status())
+	}
+	if _, err := responder.Reply(ctx, llm.MessageInput{Text: "hi"}); !errors.Is(err, llm.ErrUnavailable) || provider.calls != 2 {
+		t.Fatalf("expected a fast failure without calling the provider, got %v after %d calls", err, provider.calls)
+	}
+
+	monitor.Defer(orchestrator.Task{ID: "task-1"})
+	monitor.runProbe(ctx)
+	if monitor.Available() || len(engine.queued) != 0 {
+		t.Fatal("expected a failed probe to keep degraded mode and hold tasks")
+	}
+	provider.err = nil
+	monitor.runProbe(ctx)
+	if !monitor.Available() || monitor.Status().Failures != 0 {
+		t.Fatalf("expected a successful probe to recover, got %+v", monitor.Status())
+	}
+	if len(engine.queued) != 1 || engine.queued[0].ID != "task-1" || monitor.Status().Deferred != 0 {
+		t.Fatalf("expected the deferred task to be queued on recovery, got %+v", engine.queued)
+	}
+}
+
+func TestMonitorIgnoresCallsAbandonedByTheCaller(t *testing.T) {
+	provider := &fakeResponder{err: context.Canceled}
+	monitor := New(Config{DownAfterFailures: 1}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	responder := monitor.WrapResponder(provider)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = responder.Reply(ctx, llm.MessageInput{Text: "hi"})
+	if !monitor.Available() {
+		t.Fatal("expected canceled calls not to count as provider failures")
+	}
+}
@@ -0,0 +1,105 @@
+package degrade
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FAQEntry is one curated question and its answer.
+type FAQEntry struct {
+	Question string
+	Answer   string
+}
+
+// minFAQScore is the share of a question's words a message must contain to
+// be answered with that entry.
+const minFAQScore = 0.6
+
+var faqStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "you": true,
+	"your": true, "our": true, "can": true, "how": true, "what": true, "who": true,
+	"when": true, "where": true, "why": true, "which": true, "does": true, "this": true,
+	"that": true, "with": true, "from": true, "have": true, "has": true, "there": true,
+	"any": true, "about": true, "please": true, "into": true, "should": true, "would": true,
+}
+
+// ParseFAQ reads curated answers from a markdown document in the format the
+// botfile renders: every second-level heading is a question and the text up
+// to the next heading its answer.
+func ParseFAQ(markdown string) []FAQEntry {
+	entries := []FAQEntry{}
+	var current *FAQEntry
+	var answer []string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Answer = strings.TrimSpace(strings.Join(answer, "\n"))
+		if current.Question != "" && current.Answer != "" {
+			entries = append(entries, *current)
+		}
+		current = nil
+		answer = nil
+	}
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			flush()
+			current = &FAQEntry{Question: strings.TrimSpace(strings.TrimPrefix(trimmed, "## "))}
+		case strings.HasPrefix(trimmed, "# "):
+			flush()
+		case current != nil && !strings.HasPrefix(trimmed, "<!--"):
+			answer = append(answer, line)
+		}
+	}
+	flush()
+	return entries
+}
+
+// MatchFAQ returns the entry whose question best matches text, if it shares
+// enough of the question's significant words.
+func MatchFAQ(entries []FAQEntry, text string) (FAQEntry, bool) {
+	words := map[string]bool{}
+	for _, word := range faqWords(text) {
+		words[word] = true
+	}
+	best := FAQEntry{}
+	bestScore := 0.0
+	for _, entry := range entries {
+		questionWords := faqWords(entry.Question)
+		if len(questionWords) == 0 {
+			continue
+		}
+		matched := 0
+		for _, word := range questionWords {
+			if words[word] {
+				matched++
+			}
+		}
+		score := float64(matched) / float64(len(questionWords))
+		if score > bestScore {
+			best, bestScore = entry, score
+		}
+	}
+	return best, bestScore >= minFAQScore
+}
+
+func faqWords(text string) []string {
+	seen := map[string]bool{}
+	words := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if faqStopWords[word] {
+			continue
+		}
+		word = strings.TrimSuffix(word, "s")
+		if len(word) < 3 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words
+}
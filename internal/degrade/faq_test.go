@@ -0,0 +1,19 @@
+package degrade
+
+import "testing"
+
+func TestParseAndMatchFAQ(t *testing.T) {
+	entries := ParseFAQ("# Frequently Asked Questions\n\n<!-- Generated from botfile.yaml; edit the botfile instead. -->\n\n## Who owns releases?\n\nThe release manager on this week's rota.\n\n## How do I reset my VPN password?\n\nUse the self-service portal.\nIt takes five minutes.\n")
+	if len(entries) != 2 || entries[1].Answer != "Use the self-service portal.\nIt takes five minutes." {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if entry, ok := MatchFAQ(entries, "hey, who owns the release this week?"); !ok || entry.Question != "Who owns releases?" {
+		t.Fatalf("expected release question to match, got %+v %v", entry, ok)
+	}
+	if entry, ok := MatchFAQ(entries, "vpn password reset please"); !ok || entry.Question != "How do I reset my VPN password?" {
+		t.Fatalf("expected vpn question to match, got %+v %v", entry, ok)
+	}
+	if _, ok := MatchFAQ(entries, "the deploy pipeline is broken"); ok {
+		t.Fatal("expected unrelated message not to match")
+	}
+}
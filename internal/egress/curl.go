@@ -0,0 +1,194 @@
+package egress
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// curlValueFlags take the next argument as their value, so it is not a
+// URL. Short flags may also carry the value in the same argument (-XPOST).
+var curlValueFlags = map[string]bool{
+	"--cookie": true, "--cookie-jar": true, "--data": true, "--data-ascii": true,
+	"--data-binary": true, "--data-raw": true, "--data-urlencode": true,
+	"--dump-header": true, "--referer": true, "--form": true, "--form-string": true,
+	"--header": true, "--max-time": true, "--output": true, "--range": true,
+	"--upload-file": true, "--user": true, "--write-out": true, "--request": true,
+	"--user-agent": true, "--connect-timeout": true, "--retry": true,
+	"--max-redirs": true, "--json": true, "--speed-limit": true, "--speed-time": true,
+	"--time-cond": true, "--limit-rate": true,
+}
+
+const curlShortValueFlags = "AbcdDeFHmorTuwXYyz"
+
+// curlBypassFlags would route a request around the checked URL.
+var curlBypassFlags = map[string]bool{
+	"--proxy": true, "--preproxy": true, "--resolve": true, "--connect-to": true,
+	"--unix-socket": true, "--abstract-unix-socket": true, "--config": true,
+}
+
+const curlShortBypassFlags = "xK"
+
+// CurlArg is one parsed curl command line argument.
+type CurlArg struct {
+	// Flags are the flag names of the argument: one long flag, or each
+	// short flag of a bundle such as -sSo.
+	Flags []string
+	// Value is the value of the last flag when it takes one, inline or
+	// from the next argument.
+	Value string
+	// URL is set for positional arguments and --url values.
+	URL string
+}
+
+// ParseCurlArgs splits a curl command line into flags, flag values and URLs.
+// Scheme-less hosts are read as http, as curl does.
+func ParseCurlArgs(args []string) []CurlArg {
+	parsed := []CurlArg{}
+	for index := 0; index < len(args); index++ {
+		arg := strings.TrimSpace(args[index])
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name, value, inline := strings.Cut(arg, "=")
+			item := CurlArg{Flags: []string{name}}
+			if (curlValueFlags[name] || curlBypassFlags[name] || name == "--url") && !inline && index+1 < len(args) {
+				index++
+				value = args[index]
+			}
+			item.Value = value
+			if name == "--url" {
+				item.URL = CurlTarget(value)
+			}
+			parsed = append(parsed, item)
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// Bundled short flags: the first one taking a value ends the
+			// bundle, with the rest of the argument or the next one as value.
+			item := CurlArg{}
+			for position := 1; position < len(arg); position++ {
+				flag := arg[position]
+				item.Flags = append(item.Flags, "-"+string(flag))
+				if strings.IndexByte(curlShortValueFlags, flag) >= 0 || strings.IndexByte(curlShortBypassFlags, flag) >= 0 {
+					if position < len(arg)-1 {
+						item.Value = arg[position+1:]
+					} else if index+1 < len(args) {
+						index++
+						item.Value = args[index]
+					}
+					break
+				}
+			}
+			parsed = append(parsed, item)
+		case arg != "":
+			parsed = append(parsed, CurlArg{URL: CurlTarget(arg)})
+		}
+	}
+	return parsed
+}
+
+// CurlTargets lists the URLs a curl command line requests and refuses flags
+// that would send the request somewhere else.
+func CurlTargets(args []string) ([]string, error) {
+	targets := []string{}
+	for _, arg := range ParseCurlArgs(args) {
+		for _, flag := range arg.Flags {
+			if curlBypassFlags[flag] || (len(flag) == 2 && strings.IndexByte(curlShortBypassFlags, flag[1]) >= 0) {
+				return nil, curlBypass(flag)
+			}
+		}
+		if arg.URL != "" {
+			targets = append(targets, arg.URL)
+		}
+	}
+	return targets, nil
+}
+
+func curlBypass(flag string) error {
+	return &Violation{Target: "curl " + flag, Reason: "flag is not allowed by the egress policy"}
+}
+
+// CurlTarget reads a curl URL argument; scheme-less hosts are http.
+func CurlTarget(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if strings.Contains(arg, "://") {
+		return arg
+	}
+	return "http://" + arg
+}
+
+// PinCurl checks every URL of a curl command line when it runs and returns
+// the arguments with each host pinned to the address it was checked against
+// (--resolve) and redirects turned off, so neither a changed DNS answer nor
+// a redirect can reach a host the policy refuses.
+func (g *Guard) PinCurl(ctx context.Context, workspaceID string, args []string) ([]string, error) {
+	targets, err := CurlTargets(args)
+	if err != nil {
+		return nil, err
+	}
+	pinned := []string{}
+	seen := map[string]bool{}
+	for _, target := range targets {
+		pin, err := g.Pin(ctx, workspaceID, target)
+		if err != nil {
+			return nil, err
+		}
+		resolve := pin.Host + ":" + strconv.Itoa(pin.Port) + ":" + pinAddress(pin.IP)
+		if !seen[resolve] {
+			seen[resolve] = true
+			pinned = append(pinned, "--resolve", resolve)
+		}
+	}
+	return append(pinned, withoutRedirects(args)...), nil
+}
+
+func pinAddress(ip net.IP) string {
+	if ip.To4() == nil {
+		return "[" + ip.String() + "]"
+	}
+	return ip.String()
+}
+
+// withoutRedirects drops -L, --location and --location-trusted, including
+// from bundles such as -sSL.
+func withoutRedirects(args []string) []string {
+	result := make([]string, 0, len(args))
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		trimmed := strings.TrimSpace(arg)
+		switch {
+		case trimmed == "--location" || trimmed == "--location-trusted":
+			continue
+		case strings.HasPrefix(trimmed, "--"):
+			result = append(result, arg)
+			name, _, inline := strings.Cut(trimmed, "=")
+			if (curlValueFlags[name] || name == "--url") && !inline && index+1 < len(args) {
+				index++
+				result = append(result, args[index])
+			}
+		case strings.HasPrefix(trimmed, "-") && len(trimmed) > 1:
+			kept := []byte{'-'}
+			takesNext := false
+			for position := 1; position < len(trimmed); position++ {
+				flag := trimmed[position]
+				if strings.IndexByte(curlShortValueFlags, flag) >= 0 {
+					kept = append(kept, trimmed[position:]...)
+					takesNext = position == len(trimmed)-1
+					break
+				}
+				if flag != 'L' {
+					kept = append(kept, flag)
+				}
+			}
+			if len(kept) > 1 {
+				result = append(result, string(kept))
+			}
+			if takesNext && index+1 < len(args) {
+				index++
+				result = append(result, args[index])
+			}
+		default:
+			result = append(result, arg)
+		}
+	}
+	return result
+}
@@ -0,0 +1,425 @@
+// Package egress decides which hosts the runtime's network tools and
+// webhook actions may reach. Rules come from the runtime configuration and
+// from each workspace's botfile; private, loopback and link-local addresses
+// are refused unless the runtime allows them, so a prompt cannot point a
+// fetch at cloud metadata or internal services.
+package egress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Rules restrict outbound requests. Deny entries always win; a non-empty
+// allow list admits only hosts it covers. Domains cover their subdomains.
+type Rules struct {
+	AllowDomains []string `yaml:"allow_domains"`
+	DenyDomains  []string `yaml:"deny_domains"`
+	AllowCIDRs   []string `yaml:"allow_cidrs"`
+	DenyCIDRs    []string `yaml:"deny_cidrs"`
+	AllowPorts   []int    `yaml:"allow_ports"`
+	DenyPorts    []int    `yaml:"deny_ports"`
+}
+
+// Normalize trims and lowercases the rules so matching can compare directly.
+func (r *Rules) Normalize() {
+	r.AllowDomains = normalizeDomains(r.AllowDomains)
+	r.DenyDomains = normalizeDomains(r.DenyDomains)
+	r.AllowCIDRs = normalizeCIDRs(r.AllowCIDRs)
+	r.DenyCIDRs = normalizeCIDRs(r.DenyCIDRs)
+}
+
+// Validate lists the problems of normalized rules.
+func (r Rules) Validate() []string {
+	problems := []string{}
+	for _, domain := range append(append([]string{}, r.AllowDomains...), r.DenyDomains...) {
+		if strings.ContainsAny(domain, "/:@ ") {
+			problems = append(problems, fmt.Sprintf("domain %q must be a bare host name", domain))
+		}
+	}
+	for _, cidr := range append(append([]string{}, r.AllowCIDRs...), r.DenyCIDRs...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			problems = append(problems, fmt.Sprintf("%q is not a CIDR range", cidr))
+		}
+	}
+	for _, port := range append(append([]int{}, r.AllowPorts...), r.DenyPorts...) {
+		if port < 1 || port > 65535 {
+			problems = append(problems, fmt.Sprintf("port %d is out of range", port))
+		}
+	}
+	return problems
+}
+
+func (r Rules) restrictsHosts() bool {
+	return len(r.AllowDomains) > 0 || len(r.AllowCIDRs) > 0
+}
+
+// ParsePorts reads a comma-separated port list.
+func ParsePorts(input string) ([]int, error) {
+	ports := []int{}
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		port, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", part)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+// Resolver returns the rules a workspace declared; ok is false when it
+// declared none.
+type Resolver interface {
+	EgressRules(workspaceID string) (Rules, bool)
+}
+
+type Config struct {
+	// Global applies to every workspace, on top of its own rules.
+	Global Rules
+	// AllowPrivate lets requests reach private, loopback and link-local
+	// addresses. Global AllowCIDRs exempt single ranges instead.
+	AllowPrivate bool
+}
+
+// Violation is a request the policy refused.
+type Violation struct {
+	Target string
+	Reason string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("egress policy denied %s: %s", v.Target, v.Reason)
+}
+
+type Guard struct {
+	cfg    Config
+	lookup func(ctx context.Context, host string) ([]net.IP, error)
+
+	mu       sync.RWMutex
+	resolver Resolver
+}
+
+func New(cfg Config) *Guard {
+	cfg.Global.Normalize()
+	return &Guard{
+		cfg: cfg,
+		lookup: func(ctx context.Context, host string) ([]net.IP, error) {
+			addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+			ips := make([]net.IP, 0, len(addrs))
+			for _, addr := range addrs {
+				ips = append(ips, addr.IP)
+			}
+			return ips, nil
+		},
+	}
+}
+
+// SetResolver adds per-workspace rules.
+func (g *Guard) SetResolver(resolver Resolver) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.resolver = resolver
+}
+
+// Check reports whether a workspace may request rawURL. The host is
+// resolved, so names pointing at private addresses are refused too.
+func (g *Guard) Check(ctx context.Context, workspaceID, rawURL string) error {
+	_, err := g.Pin(ctx, workspaceID, rawURL)
+	return err
+}
+
+// Pin is a checked URL's host and port with the address the check resolved
+// it to.
+type Pin struct {
+	Host string
+	Port int
+	IP   net.IP
+}
+
+// Pin checks rawURL like Check and returns the address it was checked
+// against, for clients that connect to that address instead of resolving
+// the host again.
+func (g *Guard) Pin(ctx context.Context, workspaceID, rawURL string) (Pin, error) {
+	target := strings.TrimSpace(rawURL)
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return Pin{}, &Violation{Target: target, Reason: "not a valid URL"}
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return Pin{}, &Violation{Target: target, Reason: fmt.Sprintf("scheme %q is not allowed", parsed.Scheme)}
+	}
+	if parsed.Hostname() == "" {
+		return Pin{}, &Violation{Target: target, Reason: "not a valid URL"}
+	}
+	port := 80
+	if scheme == "https" {
+		port = 443
+	}
+	if raw := parsed.Port(); raw != "" {
+		if port, err = strconv.Atoi(raw); err != nil {
+			return Pin{}, &Violation{Target: target, Reason: fmt.Sprintf("invalid port %q", raw)}
+		}
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	ips, reason, err := g.checkHost(ctx, g.rulesFor(workspaceID), host, port)
+	if err != nil {
+		return Pin{}, err
+	}
+	if reason != "" {
+		return Pin{}, &Violation{Target: target, Reason: reason}
+	}
+	return Pin{Host: host, Port: port, IP: ips[0]}, nil
+}
+
+// checkHost applies every rule to a host and port and returns the
+// addresses the host resolved to, or why it is refused.
+func (g *Guard) checkHost(ctx context.Context, rules []Rules, host string, port int) ([]net.IP, string, error) {
+	if reason := checkPort(rules, port); reason != "" {
+		return nil, reason, nil
+	}
+	if reason := checkDomain(rules, host); reason != "" {
+		return nil, reason, nil
+	}
+	ips, err := g.resolve(ctx, host)
+	if err != nil {
+		return nil, "", fmt.Errorf("egress policy: resolve %s: %w", host, err)
+	}
+	for _, ip := range ips {
+		if reason := g.checkIP(rules, ip); reason != "" {
+			return nil, reason, nil
+		}
+	}
+	for _, set := range rules {
+		if set.restrictsHosts() && !coveredByDomains(host, set.AllowDomains) && !allInCIDRs(ips, set.AllowCIDRs) {
+			return nil, "host is not on the allowlist", nil
+		}
+	}
+	return ips, "", nil
+}
+
+type workspaceKey struct{}
+
+// WithWorkspace tags ctx with the workspace a request is made for, so
+// DialContext applies that workspace's rules.
+func WithWorkspace(ctx context.Context, workspaceID string) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, strings.TrimSpace(workspaceID))
+}
+
+// DialContext checks the address every connection actually goes to, which
+// catches redirects and DNS answers that changed since Check.
+func (g *Guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, portText, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil {
+		return nil, fmt.Errorf("egress policy: invalid port %q", portText)
+	}
+	workspaceID, _ := ctx.Value(workspaceKey{}).(string)
+	ips, reason, err := g.checkHost(ctx, g.rulesFor(workspaceID), strings.TrimSuffix(strings.ToLower(host), "."), port)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return nil, &Violation{Target: address, Reason: reason}
+	}
+	var dialer net.Dialer
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), portText))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no addresses for %s", host)
+	}
+	return nil, lastErr
+}
+
+func (g *Guard) rulesFor(workspaceID string) []Rules {
+	rules := []Rules{g.cfg.Global}
+	g.mu.RLock()
+	resolver := g.resolver
+	g.mu.RUnlock()
+	if resolver != nil {
+		if declared, ok := resolver.EgressRules(strings.TrimSpace(workspaceID)); ok {
+			declared.Normalize()
+			rules = append(rules, declared)
+		}
+	}
+	return rules
+}
+
+func (g *Guard) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	ips, err := g.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses")
+	}
+	return ips, nil
+}
+
+// checkIP applies deny ranges and the private address block. Only the
+// global allowlist can exempt a private range; workspace rules cannot.
+func (g *Guard) checkIP(rules []Rules, ip net.IP) string {
+	for _, set := range rules {
+		if cidr, ok := matchCIDR(ip, set.DenyCIDRs); ok {
+			return fmt.Sprintf("address %s is in denied range %s", ip, cidr)
+		}
+	}
+	if !g.cfg.AllowPrivate && isPrivate(ip) {
+		if _, ok := matchCIDR(ip, g.cfg.Global.AllowCIDRs); !ok {
+			return fmt.Sprintf("address %s is private", ip)
+		}
+	}
+	return ""
+}
+
+func checkPort(rules []Rules, port int) string {
+	for _, set := range rules {
+		if containsPort(set.DenyPorts, port) {
+			return fmt.Sprintf("port %d is denied", port)
+		}
+		if len(set.AllowPorts) > 0 && !containsPort(set.AllowPorts, port) {
+			return fmt.Sprintf("port %d is not on the allowlist", port)
+		}
+	}
+	return ""
+}
+
+func checkDomain(rules []Rules, host string) string {
+	for _, set := range rules {
+		for _, domain := range set.DenyDomains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return fmt.Sprintf("domain %s is denied", domain)
+			}
+		}
+	}
+	return ""
+}
+
+var privateRanges = func() []*net.IPNet {
+	ranges := []*net.IPNet{}
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.0.0.0/24",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"::/128",
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+	} {
+		_, network, _ := net.ParseCIDR(cidr)
+		ranges = append(ranges, network)
+	}
+	return ranges
+}()
+
+func isPrivate(ip net.IP) bool {
+	if mapped := ip.To4(); mapped != nil {
+		ip = mapped
+	}
+	for _, network := range privateRanges {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchCIDR(ip net.IP, cidrs []string) (string, bool) {
+	for _, cidr := range cidrs {
+		if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(ip) {
+			return cidr, true
+		}
+	}
+	return "", false
+}
+
+func allInCIDRs(ips []net.IP, cidrs []string) bool {
+	if len(cidrs) == 0 || len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if _, ok := matchCIDR(ip, cidrs); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func coveredByDomains(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, candidate := range ports {
+		if candidate == port {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDomains(values []string) []string {
+	result := []string{}
+	for _, value := range values {
+		value = strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "*."), ".")
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func normalizeCIDRs(values []string) []string {
+	result := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		// A bare address is a single-host range.
+		if ip := net.ParseIP(value); ip != nil {
+			if ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+		result = append(result, value)
+	}
+	return result
+}
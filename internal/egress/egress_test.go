@@ -0,0 +1,137 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+type fixedRules map[string]Rules
+
+func (r fixedRules) EgressRules(workspaceID string) (Rules, bool) {
+	rules, ok := r[workspaceID]
+	return rules, ok
+}
+
+func newTestGuard(cfg Config, hosts map[string]string) *Guard {
+	guard := New(cfg)
+	guard.lookup = func(ctx context.Context, host string) ([]net.IP, error) {
+		address, ok := hosts[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		return []net.IP{net.ParseIP(address)}, nil
+	}
+	return guard
+}
+
+func expectViolation(t *testing.T, err error, reason string) {
+	t.Helper()
+	var violation *Violation
+	if !errors.As(err, &violation) || !strings.Contains(violation.Reason, reason) {
+		t.Fatalf("expected violation %q, got %v", reason, err)
+	}
+}
+
+func TestCheckBlocksPrivateAddressesByDefault(t *testing.T) {
+	guard := newTestGuard(Config{}, map[string]string{
+		"docs.example.com": "93.184.216.34",
+		"internal.example": "10.1.2.3",
+	})
+	ctx := context.Background()
+	if err := guard.Check(ctx, "ws-1", "https://docs.example.com/guide"); err != nil {
+		t.Fatalf("expected public host allowed, got %v", err)
+	}
+	expectViolation(t, guard.Check(ctx, "ws-1", "http://169.254.169.254/latest/meta-data/"), "is private")
+	expectViolation(t, guard.Check(ctx, "ws-1", "http://[::1]:8080/"), "is private")
+	expectViolation(t, guard.Check(ctx, "ws-1", "http://internal.example/"), "is private")
+	expectViolation(t, guard.Check(ctx, "ws-1", "file:///etc/passwd"), `scheme "file"`)
+
+	open := newTestGuard(Config{AllowPrivate: true}, nil)
+	if err := open.Check(ctx, "ws-1", "http://10.0.0.5/"); err != nil {
+		t.Fatalf("expected private addresses allowed by config, got %v", err)
+	}
+	exempt := newTestGuard(Config{Global: Rules{AllowCIDRs: []string{"10.0.5.0/24"}}}, nil)
+	if err := exempt.Check(ctx, "ws-1", "http://10.0.5.9/"); err != nil {
+		t.Fatalf("expected a globally allowed range, got %v", err)
+	}
+}
+
+func TestCheckCombinesGlobalAndWorkspaceRules(t *testing.T) {
+	guard := newTestGuard(Config{Global: Rules{DenyDomains: []string{"*.evil.test"}, DenyPorts: []int{25}}}, map[string]string{
+		"api.example.com":  "93.184.216.34",
+		"docs.example.com": "93.184.216.35",
+		"cdn.evil.test":    "93.184.216.36",
+		"other.test":       "93.184.216.37",
+	})
+	guard.SetResolver(fixedRules{
+		"ws-locked": {AllowDomains: []string{"example.com"}, AllowPorts: []int{443}},
+		"ws-lan":    {AllowCIDRs: []string{"10.0.0.0/8"}},
+	})
+	ctx := context.Background()
+
+	expectViolation(t, guard.Check(ctx, "ws-open", "https://cdn.evil.test/x"), "domain evil.test is denied")
+	expectViolation(t, guard.Check(ctx, "ws-open", "http://other.test:25/"), "port 25 is denied")
+	if err := guard.Check(ctx, "ws-open", "https://other.test/"); err != nil {
+		t.Fatalf("expected other hosts allowed without workspace rules, got %v", err)
+	}
+
+	if err := guard.Check(ctx, "ws-locked", "https://api.example.com/v1"); err != nil {
+		t.Fatalf("expected allowlisted host, got %v", err)
+	}
+	expectViolation(t, guard.Check(ctx, "ws-locked", "https://other.test/"), "not on the allowlist")
+	expectViolation(t, guard.Check(ctx, "ws-locked", "http://docs.example.com/"), "port 80 is not on the allowlist")
+
+	// A workspace cannot open private ranges for itself.
+	expectViolation(t, guard.Check(ctx, "ws-lan", "http://10.0.0.7/"), "is private")
+}
+
+func TestRulesValidate(t *testing.T) {
+	rules := Rules{AllowDomains: []string{"https://example.com"}, DenyCIDRs: []string{"10.0.0.0/33", "192.168.1.1"}, AllowPorts: []int{0}}
+	rules.Normalize()
+	problems := strings.Join(rules.Validate(), "; ")
+	for _, want := range []string{`domain "https://example.com" must be a bare host name`, `"10.0.0.0/33" is not a CIDR range`, "port 0 is out of range"} {
+		if !strings.Contains(problems, want) {
+			t.Fatalf("expected %q in %q", want, problems)
+		}
+	}
+	if strings.Contains(problems, "192.168.1.1") {
+		t.Fatalf("expected a bare address accepted as a single-host range, got %q", problems)
+	}
+}
+
+func TestCurlTargetsSkipsFlagValues(t *testing.T) {
+	targets, err := CurlTargets([]string{"-sSL", "-A", "Mozilla/5.0", "-XPOST", "--data=x", "-H", "X-Id: 1", "--url", "https://a.example.com", "b.example.com/path"})
+	if err != nil {
+		t.Fatalf("curl targets: %v", err)
+	}
+	want := []string{"https://a.example.com", "http://b.example.com/path"}
+	if strings.Join(targets, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected %v, got %v", want, targets)
+	}
+	if _, err := CurlTargets([]string{"--resolve", "example.com:443:10.0.0.1", "https://example.com"}); err == nil {
+		t.Fatal("expected --resolve refused")
+	}
+	if _, err := CurlTargets([]string{"-sx", "http://proxy.internal", "https://example.com"}); err == nil {
+		t.Fatal("expected a bundled -x refused")
+	}
+}
+
+func TestPinCurlPinsHostsAndDropsRedirects(t *testing.T) {
+	guard := newTestGuard(Config{}, map[string]string{
+		"docs.example.com": "93.184.216.34",
+		"internal.example": "10.0.0.5",
+	})
+	args, err := guard.PinCurl(context.Background(), "ws-1", []string{"-sSL", "-o", "-L.txt", "--location", "https://docs.example.com/a"})
+	if err != nil {
+		t.Fatalf("pin curl: %v", err)
+	}
+	want := "--resolve docs.example.com:443:93.184.216.34 -sS -o -L.txt https://docs.example.com/a"
+	if got := strings.Join(args, " "); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	_, err = guard.PinCurl(context.Background(), "ws-1", []string{"-sS", "internal.example/latest"})
+	expectViolation(t, err, "private")
+}
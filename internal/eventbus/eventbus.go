@@ -0,0 +1,219 @@
+// Package eventbus delivers typed runtime events (a task was created, an
+// approval is pending or was executed, an objective fired, the agent was
+// blocked) to external sinks such as webhooks, NATS or a log file, so other
+// systems can react without polling the store.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types.
+const (
+	TypeTaskCreated      = "task.created"
+	TypeApprovalPending  = "approval.pending"
+	TypeApprovalExecuted = "approval.executed"
+	TypeObjectiveFired   = "objective.fired"
+	TypeAgentBlocked     = "agent.blocked"
+)
+
+// Types lists every event type, in the order they are documented.
+var Types = []string{TypeTaskCreated, TypeApprovalPending, TypeApprovalExecuted, TypeObjectiveFired, TypeAgentBlocked}
+
+const (
+	defaultBufferSize = 1000
+	deliverAttempts   = 3
+	deliverTimeout    = 10 * time.Second
+)
+
+// Event is what sinks receive, encoded as JSON.
+type Event struct {
+	ID          string         `json:"id"`
+	Type        string         `json:"type"`
+	WorkspaceID string         `json:"workspace_id,omitempty"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+	Data        map[string]any `json:"data,omitempty"`
+}
+
+// Sink delivers events to one external system. Deliver is only ever called
+// from one goroutine per sink.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event Event) error
+}
+
+type Config struct {
+	// Types limits delivery to these event types; empty means every type.
+	Types []string
+	// BufferSize is how many events each sink may have waiting before new
+	// ones are dropped.
+	BufferSize int
+}
+
+// ParseTypes reads a comma separated list of event types; an empty list
+// selects every type.
+func ParseTypes(csv string) ([]string, error) {
+	known := map[string]bool{}
+	for _, eventType := range Types {
+		known[eventType] = true
+	}
+	selected := []string{}
+	for _, part := range strings.Split(csv, ",") {
+		eventType := strings.ToLower(strings.TrimSpace(part))
+		if eventType == "" {
+			continue
+		}
+		if !known[eventType] {
+			return nil, fmt.Errorf("unknown event type %q (use %s)", eventType, strings.Join(Types, ", "))
+		}
+		selected = append(selected, eventType)
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+type sinkQueue struct {
+	sink   Sink
+	events chan Event
+	// types filters what the sink receives; nil means every type.
+	types map[string]bool
+	// routed sinks pick their own targets and retry on their own.
+	routed bool
+}
+
+// Bus fans events out to its sinks. Publishing never blocks: each sink has
+// its own buffer, so a slow webhook does not hold up the others, and events
+// that do not fit are dropped with a warning.
+type Bus struct {
+	types      map[string]bool
+	bufferSize int
+	queues     []sinkQueue
+	logger     *slog.Logger
+	dryRun     bool
+}
+
+func New(cfg Config, sinks []Sink, logger *slog.Logger) *Bus {
+	if cfg.BufferSize < 1 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	bus := &Bus{logger: logger, bufferSize: cfg.BufferSize}
+	if len(cfg.Types) > 0 {
+		bus.types = map[string]bool{}
+		for _, eventType := range cfg.Types {
+			bus.types[eventType] = true
+		}
+	}
+	for _, sink := range sinks {
+		bus.queues = append(bus.queues, sinkQueue{sink: sink, events: make(chan Event, cfg.BufferSize), types: bus.types})
+	}
+	return bus
+}
+
+// Route adds a sink that receives every event type and picks its own
+// targets per event, such as per-workspace webhook subscriptions. The bus
+// calls it once per event and leaves retries to it. Call Route before
+// Start.
+func (b *Bus) Route(sink Sink) {
+	b.queues = append(b.queues, sinkQueue{sink: sink, events: make(chan Event, b.bufferSize), routed: true})
+}
+
+// SetDryRun makes the bus log events instead of delivering them. Call it
+// before Start.
+func (b *Bus) SetDryRun(enabled bool) {
+	b.dryRun = enabled
+}
+
+// PublishEvent queues an event for every sink.
+func (b *Bus) PublishEvent(ctx context.Context, eventType, workspaceID string, data map[string]any) {
+	if b == nil || len(b.queues) == 0 {
+		return
+	}
+	event := Event{
+		ID:          "evt_" + uuid.NewString(),
+		Type:        eventType,
+		WorkspaceID: strings.TrimSpace(workspaceID),
+		OccurredAt:  time.Now().UTC(),
+		Data:        data,
+	}
+	for _, queue := range b.queues {
+		if queue.types != nil && !queue.types[eventType] {
+			continue
+		}
+		select {
+		case queue.events <- event:
+		default:
+			b.logger.Warn("event dropped, sink buffer full", "sink", queue.sink.Name(), "type", eventType, "event_id", event.ID)
+		}
+	}
+}
+
+// Start delivers queued events until ctx is done.
+func (b *Bus) Start(ctx context.Context) error {
+	done := make(chan struct{}, len(b.queues))
+	for _, queue := range b.queues {
+		go func(queue sinkQueue) {
+			defer func() { done <- struct{}{} }()
+			b.run(ctx, queue)
+		}(queue)
+	}
+	b.logger.Info("event bus started", "sinks", len(b.queues))
+	for range b.queues {
+		<-done
+	}
+	b.logger.Info("event bus stopped")
+	return nil
+}
+
+func (b *Bus) run(ctx context.Context, queue sinkQueue) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-queue.events:
+			if b.dryRun {
+				b.logger.Info("dry run: event not delivered", "sink", queue.sink.Name(), "type", event.Type, "event_id", event.ID, "workspace_id", event.WorkspaceID)
+				continue
+			}
+			if queue.routed {
+				if err := queue.sink.Deliver(ctx, event); err != nil {
+					b.logger.Error("event delivery failed", "sink", queue.sink.Name(), "type", event.Type, "event_id", event.ID, "error", err)
+				}
+				continue
+			}
+			b.deliver(ctx, queue.sink, event)
+		}
+	}
+}
+
+// deliver tries a few times with a growing pause, then gives up on the
+// event; sinks are for reacting, the store stays the record.
+func (b *Bus) deliver(ctx context.Context, sink Sink, event Event) {
+	var err error
+	for attempt := 1; attempt <= deliverAttempts; attempt++ {
+		deliverCtx, cancel := context.WithTimeout(ctx, deliverTimeout)
+		err = sink.Deliver(deliverCtx, event)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt == deliverAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+		}
+	}
+	b.logger.Error("event delivery failed", "sink", sink.Name(), "type", event.Type, "event_id", event.ID, "error", err)
+}
@@ -0,0 +1,198 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBusDeliversSelectedTypesToWebhookAndFile(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+	logPath := filepath.Join(t.TempDir(), "events", "events.ndjson")
+
+	sinks, err := ParseSinks(server.URL+", file://"+logPath, SinkOptions{WebhookSecret: "s3cret"})
+	if err != nil || len(sinks) != 2 {
+		t.Fatalf("parse sinks: %v %v", sinks, err)
+	}
+	types, err := ParseTypes("task.created")
+	if err != nil {
+		t.Fatalf("parse types: %v", err)
+	}
+	bus := New(Config{Types: types}, sinks, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Start(ctx)
+
+	bus.PublishEvent(ctx, TypeObjectiveFired, "ws-1", map[string]any{"objective_id": "obj-1"})
+	bus.PublishEvent(ctx, TypeTaskCreated, "ws-1", map[string]any{"task_id": "task-1"})
+
+	var req *http.Request
+	var body []byte
+	select {
+	case req = <-received:
+		body = <-bodies
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not called")
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if req.Header.Get("X-Agent-Runtime-Signature") != "sha256="+hex.EncodeToString(mac.Sum(nil)) || req.Header.Get("X-Agent-Runtime-Event") != TypeTaskCreated {
+		t.Fatalf("unexpected webhook headers %v", req.Header)
+	}
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil || event.Type != TypeTaskCreated || event.WorkspaceID != "ws-1" || event.Data["task_id"] != "task-1" {
+		t.Fatalf("unexpected event %s (%v)", body, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		content, _ := os.ReadFile(logPath)
+		if lines := strings.Split(strings.TrimSpace(string(content)), "\n"); len(content) > 0 {
+			if len(lines) != 1 || !strings.Contains(lines[0], `"type":"task.created"`) {
+				t.Fatalf("expected only the task event in the log, got %q", content)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("event log not written")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNATSSinkPublishesOnTypedSubject(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	published := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "CONNECT "):
+				if !strings.Contains(line, `"auth_token":"tok"`) {
+					conn.Write([]byte("-ERR 'Authorization Violation'\r\n"))
+					return
+				}
+			case line == "PING\r\n":
+				conn.Write([]byte("PONG\r\n"))
+			case strings.HasPrefix(line, "PUB "):
+				payload, _ := reader.ReadString('\n')
+				published <- line + payload
+				return
+			}
+		}
+	}()
+
+	target, _ := url.Parse("nats://tok@" + listener.Addr().String())
+	sink := NewNATSSink(target, "")
+	defer sink.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sink.Deliver(ctx, Event{ID: "evt_1", Type: TypeApprovalPending, Data: map[string]any{"approval_id": "act_1"}}); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	select {
+	case message := <-published:
+		if !strings.HasPrefix(message, "PUB agent_runtime.approval.pending ") || !strings.Contains(message, `"approval_id":"act_1"`) {
+			t.Fatalf("unexpected publish %q", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing published")
+	}
+}
+
+func TestParseSinksAndTypesRejectUnknownValues(t *testing.T) {
+	if _, err := ParseSinks("kafka://broker:9092", SinkOptions{}); err == nil {
+		t.Fatal("expected an unsupported scheme to fail")
+	}
+	if _, err := ParseSinks("relative/events.log", SinkOptions{}); err == nil {
+		t.Fatal("expected a relative path to fail")
+	}
+	if _, err := ParseTypes("task.created, task.deleted"); err == nil {
+		t.Fatal("expected an unknown type to fail")
+	}
+	if types, err := ParseTypes(""); err != nil || len(types) != 0 {
+		t.Fatalf("expected every type for an empty list, got %v %v", types, err)
+	}
+}
+
+type recordingSink struct {
+	delivered chan Event
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Deliver(ctx context.Context, event Event) error {
+	s.delivered <- event
+	return nil
+}
+
+func TestBusDryRunLogsInsteadOfDelivering(t *testing.T) {
+	logs := make(chan string, 4)
+	logger := slog.New(slog.NewTextHandler(lineWriter(logs), nil))
+	sink := &recordingSink{delivered: make(chan Event, 1)}
+	bus := New(Config{}, []Sink{sink}, logger)
+	bus.SetDryRun(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Start(ctx)
+
+	bus.PublishEvent(ctx, TypeTaskCreated, "ws-1", map[string]any{"task_id": "task-1"})
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-sink.delivered:
+			t.Fatalf("expected no delivery in dry-run mode, got %+v", event)
+		case line := <-logs:
+			if strings.Contains(line, "dry run: event not delivered") {
+				if !strings.Contains(line, "type=task.created") || !strings.Contains(line, "sink=recording") {
+					t.Fatalf("unexpected dry-run log %q", line)
+				}
+				return
+			}
+		case <-deadline:
+			t.Fatal("dry-run event not logged")
+		}
+	}
+}
+
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
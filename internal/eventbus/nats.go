@@ -0,0 +1,187 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	natsDefaultPort   = "4222"
+	natsDefaultPrefix = "agent_runtime"
+	natsHandshakeWait = 5 * time.Second
+)
+
+// NATSSink publishes each event to <prefix>.<type>, e.g.
+// agent_runtime.approval.pending, over the plain NATS text protocol. It
+// connects lazily, answers server pings and reconnects on the next event
+// after the connection drops. Credentials come from the URL: user and
+// password, or a lone user taken as a token.
+type NATSSink struct {
+	target *url.URL
+	prefix string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func NewNATSSink(target *url.URL, subjectPrefix string) *NATSSink {
+	prefix := strings.Trim(strings.TrimSpace(subjectPrefix), ".")
+	if prefix == "" {
+		prefix = natsDefaultPrefix
+	}
+	return &NATSSink{target: target, prefix: prefix}
+}
+
+func (s *NATSSink) Name() string {
+	return "nats:" + s.target.Host
+}
+
+// Subject is the subject an event of eventType is published on.
+func (s *NATSSink) Subject(eventType string) string {
+	return s.prefix + "." + eventType
+}
+
+func (s *NATSSink) Deliver(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+	message := fmt.Sprintf("PUB %s %d\r\n%s\r\n", s.Subject(event.Type), len(payload), payload)
+	if _, err := s.conn.Write([]byte(message)); err != nil {
+		s.closeLocked()
+		return fmt.Errorf("publish to nats: %w", err)
+	}
+	return nil
+}
+
+// connect dials the server, reads its INFO, upgrades to TLS when asked and
+// sends CONNECT followed by a PING so a rejected login fails here.
+func (s *NATSSink) connect(ctx context.Context) error {
+	host := s.target.Host
+	if s.target.Port() == "" {
+		host = net.JoinHostPort(s.target.Hostname(), natsDefaultPort)
+	}
+	dialer := net.Dialer{Timeout: natsHandshakeWait}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("connect to nats: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(natsHandshakeWait))
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("nats handshake: expected INFO, got %q (%v)", strings.TrimSpace(line), err)
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	_ = json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "INFO ")), &info)
+	if info.TLSRequired || strings.EqualFold(s.target.Scheme, "tls") {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: s.target.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("nats tls handshake: %w", err)
+		}
+		conn = tlsConn
+		reader = bufio.NewReader(conn)
+	}
+
+	options := map[string]any{"verbose": false, "pedantic": false, "name": "agent-runtime", "lang": "go", "protocol": 1}
+	if user := s.target.User; user != nil {
+		if password, ok := user.Password(); ok {
+			options["user"] = user.Username()
+			options["pass"] = password
+		} else {
+			options["auth_token"] = user.Username()
+		}
+	}
+	connectJSON, _ := json.Marshal(options)
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connectJSON); err != nil {
+		conn.Close()
+		return fmt.Errorf("nats handshake: %w", err)
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return fmt.Errorf("nats handshake: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "PONG" {
+			break
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			conn.Close()
+			return fmt.Errorf("nats rejected connection: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+	_ = conn.SetDeadline(time.Time{})
+	s.conn = conn
+	go s.readLoop(conn, reader)
+	return nil
+}
+
+// readLoop answers server pings so an idle connection is not dropped as
+// stale, and forgets the connection once it fails.
+func (s *NATSSink) readLoop(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if line == "PING" {
+			s.mu.Lock()
+			if s.conn == conn {
+				_, err = conn.Write([]byte("PONG\r\n"))
+			}
+			s.mu.Unlock()
+			if err != nil {
+				break
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			break
+		}
+	}
+	s.mu.Lock()
+	if s.conn == conn {
+		s.closeLocked()
+	}
+	s.mu.Unlock()
+}
+
+func (s *NATSSink) closeLocked() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	s.conn = nil
+}
+
+// Close drops the connection.
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+	return nil
+}
@@ -0,0 +1,161 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SinkOptions configures the sinks ParseSinks builds.
+type SinkOptions struct {
+	// WebhookSecret signs webhook bodies with HMAC-SHA256 when set.
+	WebhookSecret string
+	// NATSSubjectPrefix is put before the event type to form the subject.
+	NATSSubjectPrefix string
+	HTTPClient        *http.Client
+}
+
+// ParseSinks builds one sink per comma separated target: http(s) URLs are
+// webhooks, nats:// and tls:// URLs are NATS servers, and file:// URLs or
+// absolute paths are NDJSON log files.
+func ParseSinks(csv string, opts SinkOptions) ([]Sink, error) {
+	sinks := []Sink{}
+	for _, part := range strings.Split(csv, ",") {
+		target := strings.TrimSpace(part)
+		if target == "" {
+			continue
+		}
+		if strings.HasPrefix(target, "/") {
+			sinks = append(sinks, NewFileSink(target))
+			continue
+		}
+		parsed, err := url.Parse(target)
+		if err != nil || parsed.Scheme == "" {
+			return nil, fmt.Errorf("invalid event sink %q", target)
+		}
+		switch strings.ToLower(parsed.Scheme) {
+		case "http", "https":
+			if parsed.Host == "" {
+				return nil, fmt.Errorf("invalid event sink %q: missing host", target)
+			}
+			sinks = append(sinks, NewWebhookSink(target, opts.WebhookSecret, opts.HTTPClient))
+		case "nats", "tls":
+			if parsed.Host == "" {
+				return nil, fmt.Errorf("invalid event sink %q: missing host", target)
+			}
+			sinks = append(sinks, NewNATSSink(parsed, opts.NATSSubjectPrefix))
+		case "file":
+			path := parsed.Path
+			if path == "" {
+				path = parsed.Opaque
+			}
+			if path == "" {
+				return nil, fmt.Errorf("invalid event sink %q: missing path", target)
+			}
+			sinks = append(sinks, NewFileSink(path))
+		default:
+			return nil, fmt.Errorf("unsupported event sink %q (use http(s)://, nats://, tls:// or file://)", target)
+		}
+	}
+	return sinks, nil
+}
+
+// WebhookSink POSTs each event as JSON. With a secret, the body's
+// HMAC-SHA256 is sent as X-Agent-Runtime-Signature: sha256=<hex>.
+type WebhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookSink(url, secret string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = &http.Client{Timeout: deliverTimeout}
+	}
+	return &WebhookSink{url: url, secret: strings.TrimSpace(secret), client: client}
+}
+
+func (s *WebhookSink) Name() string {
+	parsed, err := url.Parse(s.url)
+	if err != nil {
+		return "webhook"
+	}
+	return "webhook:" + parsed.Host
+}
+
+func (s *WebhookSink) Deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "agent-runtime")
+	req.Header.Set("X-Agent-Runtime-Event", event.Type)
+	req.Header.Set("X-Agent-Runtime-Event-Id", event.ID)
+	if s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(body)
+		req.Header.Set("X-Agent-Runtime-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// FileSink appends each event as one JSON line. The file is reopened for
+// every event so log rotation needs no signal.
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: filepath.Clean(path)}
+}
+
+func (s *FileSink) Name() string {
+	return "file:" + s.path
+}
+
+func (s *FileSink) Deliver(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create event log dir: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open event log: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("write event log: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,109 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/egress"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// SubscriptionStore reads webhook subscriptions and records deliveries.
+type SubscriptionStore interface {
+	ListWebhookSubscriptionsForEvent(ctx context.Context, workspaceID, eventType string) ([]store.WebhookSubscription, error)
+	RecordWebhookDelivery(ctx context.Context, id string, deliveredAt time.Time, deliveryErr string) error
+}
+
+// SubscriptionSink delivers each workspace event to that workspace's
+// webhook subscriptions, in parallel, signing bodies with the
+// subscription's secret. Every subscription gets its own retries, and the
+// outcome is recorded on it.
+type SubscriptionSink struct {
+	store      SubscriptionStore
+	client     *http.Client
+	retryDelay time.Duration
+	logger     *slog.Logger
+}
+
+func NewSubscriptionSink(store SubscriptionStore, logger *slog.Logger) *SubscriptionSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SubscriptionSink{
+		store:      store,
+		client:     &http.Client{Timeout: deliverTimeout},
+		retryDelay: time.Second,
+		logger:     logger,
+	}
+}
+
+// SetEgressGuard checks every subscription request, redirects included,
+// against the egress policy of the subscription's workspace.
+func (s *SubscriptionSink) SetEgressGuard(guard *egress.Guard) {
+	if guard == nil {
+		return
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would hide the real destination from the dial check.
+	transport.Proxy = nil
+	transport.DialContext = guard.DialContext
+	s.client = &http.Client{Timeout: deliverTimeout, Transport: transport}
+}
+
+func (s *SubscriptionSink) Name() string {
+	return "webhook-subscriptions"
+}
+
+func (s *SubscriptionSink) Deliver(ctx context.Context, event Event) error {
+	if event.WorkspaceID == "" {
+		return nil
+	}
+	subscriptions, err := s.store.ListWebhookSubscriptionsForEvent(ctx, event.WorkspaceID, event.Type)
+	if err != nil {
+		return fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	var wg sync.WaitGroup
+	for _, subscription := range subscriptions {
+		wg.Add(1)
+		go func(subscription store.WebhookSubscription) {
+			defer wg.Done()
+			s.deliverTo(ctx, subscription, event)
+		}(subscription)
+	}
+	wg.Wait()
+	return nil
+}
+
+// deliverTo retries with a doubling pause, then records the outcome.
+func (s *SubscriptionSink) deliverTo(ctx context.Context, subscription store.WebhookSubscription, event Event) {
+	sink := NewWebhookSink(subscription.URL, subscription.Secret, s.client)
+	requestCtx := egress.WithWorkspace(ctx, subscription.WorkspaceID)
+	delay := s.retryDelay
+	var err error
+	for attempt := 1; attempt <= deliverAttempts; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(requestCtx, deliverTimeout)
+		err = sink.Deliver(attemptCtx, event)
+		cancel()
+		if err == nil || attempt == deliverAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	message := ""
+	if err != nil {
+		message = err.Error()
+		s.logger.Warn("webhook subscription delivery failed", "subscription_id", subscription.ID, "workspace_id", subscription.WorkspaceID, "type", event.Type, "event_id", event.ID, "error", err)
+	}
+	if recordErr := s.store.RecordWebhookDelivery(context.WithoutCancel(ctx), subscription.ID, time.Now().UTC(), message); recordErr != nil {
+		s.logger.Error("record webhook delivery failed", "subscription_id", subscription.ID, "error", recordErr)
+	}
+}
@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/egress"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestSubscriptionSinkSignsRetriesAndRecords(t *testing.T) {
+	var calls atomic.Int32
+	signatures := make(chan string, 4)
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signatures <- r.Header.Get("X-Agent-Runtime-Signature") + " " + string(body)
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer flaky.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "events.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	ctx := context.Background()
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	good, err := sqlStore.SaveWebhookSubscription(ctx, store.SaveWebhookSubscriptionInput{WorkspaceID: "ws-1", URL: flaky.URL, Secret: "s3cret", EventTypes: []string{TypeTaskCreated}, Enabled: true})
+	if err != nil {
+		t.Fatalf("save subscription: %v", err)
+	}
+	bad, err := sqlStore.SaveWebhookSubscription(ctx, store.SaveWebhookSubscriptionInput{WorkspaceID: "ws-1", URL: broken.URL, Enabled: true})
+	if err != nil {
+		t.Fatalf("save subscription: %v", err)
+	}
+	if _, err := sqlStore.SaveWebhookSubscription(ctx, store.SaveWebhookSubscriptionInput{WorkspaceID: "ws-1", URL: flaky.URL, EventTypes: []string{TypeAgentBlocked}, Enabled: true}); err != nil {
+		t.Fatalf("save subscription: %v", err)
+	}
+
+	sink := NewSubscriptionSink(sqlStore, nil)
+	sink.retryDelay = time.Millisecond
+	event := Event{ID: "evt_1", Type: TypeTaskCreated, WorkspaceID: "ws-1", OccurredAt: time.Now().UTC()}
+	if err := sink.Deliver(ctx, event); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected one retry to the flaky subscription only, got %d calls", calls.Load())
+	}
+	<-signatures
+	delivered := <-signatures
+	signature, body, _ := strings.Cut(delivered, " ")
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(body))
+	if signature != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Fatalf("signature %q does not match body", signature)
+	}
+
+	good, _ = sqlStore.LookupWebhookSubscription(ctx, good.ID)
+	if good.LastStatus != "delivered" || good.ConsecutiveFailures != 0 {
+		t.Fatalf("unexpected state for delivered subscription %+v", good)
+	}
+	bad, _ = sqlStore.LookupWebhookSubscription(ctx, bad.ID)
+	if bad.LastStatus != "failed" || bad.ConsecutiveFailures != 1 || bad.LastError == "" {
+		t.Fatalf("unexpected state for failing subscription %+v", bad)
+	}
+}
+
+func TestSubscriptionSinkEgressGuardBypassesProxies(t *testing.T) {
+	sink := NewSubscriptionSink(nil, nil)
+	sink.SetEgressGuard(egress.New(egress.Config{}))
+	transport, ok := sink.client.Transport.(*http.Transport)
+	if !ok || transport.DialContext == nil {
+		t.Fatalf("expected a guarded transport, got %T", sink.client.Transport)
+	}
+	if transport.Proxy != nil {
+		t.Fatal("expected the environment proxy to be dropped so the dial check sees the real host")
+	}
+}
@@ -0,0 +1,277 @@
+package gateway
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	archiveMaxFiles      = 2000
+	archiveMaxFileBytes  = 50 * 1024 * 1024
+	archiveMaxTotalBytes = 200 * 1024 * 1024
+	archiveListPreview   = 20
+)
+
+var errArchiveLimit = errors.New("archive exceeds extraction limits")
+
+// ExtractArchiveTool unpacks zip and tar archives inside the workspace scratchpad.
+type ExtractArchiveTool struct {
+	guard *filePolicyGuard
+}
+
+type extractArchiveArgs struct {
+	Path        string `json:"path"`
+	Destination string `json:"destination"`
+}
+
+func NewExtractArchiveTool(store Store, workspaceRoot string) *ExtractArchiveTool {
+	return &ExtractArchiveTool{guard: newFilePolicyGuard(store, workspaceRoot)}
+}
+
+func (t *ExtractArchiveTool) Name() string { return "extract_archive" }
+
+func (t *ExtractArchiveTool) ToolClass() tools.ToolClass {
+	return tools.ToolClassGeneral
+}
+
+func (t *ExtractArchiveTool) RequiresApproval() bool { return false }
+
+func (t *ExtractArchiveTool) Description() string {
+	return "Extract a .zip, .tar, .tar.gz, or .tgz archive from the workspace scratchpad into a new scratch directory, with file-count and size limits."
+}
+
+func (t *ExtractArchiveTool) ParametersSchema() string {
+	return `{"path": "string (relative archive path)", "destination": "string (optional new relative directory; default archive name without extension)"}`
+}
+
+func (t *ExtractArchiveTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args extractArchiveArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Path) == "" {
+		return fmt.Errorf("path is required")
+	}
+	if archiveFormat(args.Path) == "" {
+		return fmt.Errorf("unsupported archive type: %s", args.Path)
+	}
+	return nil
+}
+
+func (t *ExtractArchiveTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args extractArchiveArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+
+	archivePath, err := t.guard.resolve(ctx, t.Name(), record, args.Path, fileAccessRead)
+	if err != nil {
+		return "", err
+	}
+	destination := strings.TrimSpace(args.Destination)
+	if destination == "" {
+		destination = archiveBaseName(args.Path)
+	}
+	destinationDir, err := t.guard.resolve(ctx, t.Name(), record, destination, fileAccessWrite)
+	if err != nil {
+		return "", err
+	}
+	if entries, err := os.ReadDir(destinationDir); err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("destination already exists and is not empty: %s", destination)
+	}
+
+	extractor := &archiveExtractor{
+		ctx:         ctx,
+		tool:        t,
+		record:      record,
+		destination: normalizePolicyPath(destination),
+	}
+	switch archiveFormat(args.Path) {
+	case "zip":
+		err = extractor.extractZip(archivePath)
+	case "tar":
+		err = extractor.extractTar(archivePath, false)
+	case "tar.gz":
+		err = extractor.extractTar(archivePath, true)
+	}
+	if err != nil {
+		// Never leave a partially extracted tree behind.
+		_ = os.RemoveAll(destinationDir)
+		return "", err
+	}
+
+	lines := []string{fmt.Sprintf("Extracted %d files (%d bytes) from %s into %s/", len(extractor.files), extractor.totalBytes, args.Path, extractor.destination)}
+	if extractor.skipped > 0 {
+		lines = append(lines, fmt.Sprintf("Skipped %d links or special entries.", extractor.skipped))
+	}
+	for index, name := range extractor.files {
+		if index == archiveListPreview {
+			lines = append(lines, fmt.Sprintf("- ... %d more", len(extractor.files)-archiveListPreview))
+			break
+		}
+		lines = append(lines, "- "+name)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+type archiveExtractor struct {
+	ctx         context.Context
+	tool        *ExtractArchiveTool
+	record      store.ContextRecord
+	destination string
+	files       []string
+	totalBytes  int64
+	skipped     int
+}
+
+func (e *archiveExtractor) extractZip(archivePath string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("open zip archive: %w", err)
+	}
+	defer reader.Close()
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		if !entry.Mode().IsRegular() {
+			e.skipped++
+			continue
+		}
+		content, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("read zip entry %s: %w", entry.Name, err)
+		}
+		err = e.writeEntry(entry.Name, content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *archiveExtractor) extractTar(archivePath string, gzipped bool) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("open tar archive: %w", err)
+	}
+	defer file.Close()
+	var source io.Reader = file
+	if gzipped {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("open gzip stream: %w", err)
+		}
+		defer gzipReader.Close()
+		source = gzipReader
+	}
+	reader := tar.NewReader(source)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read tar archive: %w", err)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+			if err := e.writeEntry(header.Name, reader); err != nil {
+				return err
+			}
+		default:
+			e.skipped++
+		}
+	}
+}
+
+func (e *archiveExtractor) writeEntry(name string, content io.Reader) error {
+	if err := e.ctx.Err(); err != nil {
+		return err
+	}
+	cleaned := path.Clean(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if cleaned == "." || path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("archive entry has unsafe path: %s", name)
+	}
+	if len(e.files) >= archiveMaxFiles {
+		return fmt.Errorf("%w: more than %d files", errArchiveLimit, archiveMaxFiles)
+	}
+	relPath := path.Join(e.destination, cleaned)
+	fullPath, err := e.tool.guard.resolve(e.ctx, e.tool.Name(), e.record, relPath, fileAccessWrite)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	out, err := os.OpenFile(fullPath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", cleaned, err)
+	}
+	// Declared sizes are untrusted, so limits are enforced on bytes actually written.
+	remaining := int64(archiveMaxTotalBytes) - e.totalBytes
+	limit := min(int64(archiveMaxFileBytes), remaining)
+	written, copyErr := io.Copy(out, io.LimitReader(content, limit+1))
+	closeErr := out.Close()
+	if copyErr != nil {
+		return fmt.Errorf("extract %s: %w", cleaned, copyErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("extract %s: %w", cleaned, closeErr)
+	}
+	if written > limit {
+		if limit == remaining {
+			return fmt.Errorf("%w: more than %d bytes in total", errArchiveLimit, archiveMaxTotalBytes)
+		}
+		return fmt.Errorf("%w: %s is larger than %d bytes", errArchiveLimit, cleaned, archiveMaxFileBytes)
+	}
+	e.totalBytes += written
+	e.files = append(e.files, cleaned)
+	return nil
+}
+
+func archiveFormat(name string) string {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	default:
+		return ""
+	}
+}
+
+func archiveBaseName(name string) string {
+	cleaned := normalizePolicyPath(name)
+	lower := strings.ToLower(cleaned)
+	for _, suffix := range []string{".tar.gz", ".tgz", ".tar", ".zip"} {
+		if strings.HasSuffix(lower, suffix) {
+			return cleaned[:len(cleaned)-len(suffix)]
+		}
+	}
+	return cleaned + "_extracted"
+}
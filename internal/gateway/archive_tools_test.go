@@ -0,0 +1,133 @@
+package gateway
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestExtractArchiveToolExtractsZipAndTarGz(t *testing.T) {
+	tempDir := t.TempDir()
+	scratch := filepath.Join(tempDir, "ws1", "scratch")
+	_ = os.MkdirAll(scratch, 0o755)
+	writeZipArchive(t, filepath.Join(scratch, "logs.zip"), map[string]string{
+		"app/server.log": "boot ok",
+		"app/worker.log": "job done",
+	})
+	writeTarGzArchive(t, filepath.Join(scratch, "bundle.tgz"), map[string]string{"notes.txt": "hello"})
+
+	tool := NewExtractArchiveTool(nil, tempDir)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"path": "logs.zip"}`))
+	if err != nil {
+		t.Fatalf("extract zip: %v", err)
+	}
+	if !strings.Contains(res, "Extracted 2 files") || !strings.Contains(res, "into logs/") {
+		t.Errorf("unexpected summary: %s", res)
+	}
+	content, err := os.ReadFile(filepath.Join(scratch, "logs", "app", "worker.log"))
+	if err != nil || string(content) != "job done" {
+		t.Fatalf("expected extracted worker log, got %q %v", content, err)
+	}
+
+	if _, err := tool.Execute(ctx, json.RawMessage(`{"path": "bundle.tgz", "destination": "unpacked"}`)); err != nil {
+		t.Fatalf("extract tgz: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(scratch, "unpacked", "notes.txt")); err != nil {
+		t.Fatalf("expected extracted notes: %v", err)
+	}
+
+	if _, err := tool.Execute(ctx, json.RawMessage(`{"path": "logs.zip"}`)); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Fatalf("expected refusal to extract over existing files, got %v", err)
+	}
+}
+
+func TestExtractArchiveToolRejectsTraversalAndCleansUp(t *testing.T) {
+	tempDir := t.TempDir()
+	scratch := filepath.Join(tempDir, "ws1", "scratch")
+	_ = os.MkdirAll(scratch, 0o755)
+	writeZipArchive(t, filepath.Join(scratch, "evil.zip"), map[string]string{
+		"a.txt":         "fine",
+		"../escape.txt": "bad",
+	})
+
+	tool := NewExtractArchiveTool(nil, tempDir)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+	_, err := tool.Execute(ctx, json.RawMessage(`{"path": "evil.zip"}`))
+	if err == nil || !strings.Contains(err.Error(), "unsafe path") {
+		t.Fatalf("expected unsafe path error, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(scratch, "evil")); !os.IsNotExist(statErr) {
+		t.Fatal("expected partial extraction to be removed")
+	}
+	if _, statErr := os.Stat(filepath.Join(tempDir, "ws1", "escape.txt")); !os.IsNotExist(statErr) {
+		t.Fatal("expected no file outside scratch")
+	}
+}
+
+func TestExtractArchiveToolEnforcesFileSizeLimit(t *testing.T) {
+	tempDir := t.TempDir()
+	scratch := filepath.Join(tempDir, "ws1", "scratch")
+	_ = os.MkdirAll(scratch, 0o755)
+	writeTarGzArchive(t, filepath.Join(scratch, "bomb.tar.gz"), map[string]string{
+		"huge.bin": strings.Repeat("0", archiveMaxFileBytes+1),
+	})
+
+	tool := NewExtractArchiveTool(nil, tempDir)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+	if _, err := tool.Execute(ctx, json.RawMessage(`{"path": "bomb.tar.gz"}`)); !errors.Is(err, errArchiveLimit) {
+		t.Fatalf("expected archive limit error, got %v", err)
+	}
+	if err := tool.ValidateArgs(json.RawMessage(`{"path": "report.rar"}`)); err == nil {
+		t.Fatal("expected unsupported archive type error")
+	}
+}
+
+func writeZipArchive(t *testing.T, target string, files map[string]string) {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("create zip entry: %v", err)
+		}
+		_, _ = entry.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	if err := os.WriteFile(target, buffer.Bytes(), 0o644); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+}
+
+func writeTarGzArchive(t *testing.T, target string, files map[string]string) {
+	t.Helper()
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("write tar header: %v", err)
+		}
+		_, _ = tarWriter.Write([]byte(content))
+	}
+	_ = tarWriter.Close()
+	_ = gzipWriter.Close()
+	if err := os.WriteFile(target, buffer.Bytes(), 0o644); err != nil {
+		t.Fatalf("write tar.gz: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/dwizi/agent-runtime/internal/approvalpolicy"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// ApprovalPolicyResolver returns the auto-approve rules a workspace declared.
+// ok is false when it declared none, and the default policy applies.
+type ApprovalPolicyResolver interface {
+	ApprovalPolicy(workspaceID string) (approvalpolicy.Policy, bool)
+}
+
+// SetApprovalPolicyResolver lets workspaces decide which actions the agent's
+// tools may approve on their own.
+func (s *Service) SetApprovalPolicyResolver(resolver ApprovalPolicyResolver) {
+	s.approvalPolicies = resolver
+}
+
+// autoApprover decides whether an action a tool just created can run without
+// waiting for an admin. Tools built outside New use the default policy.
+type autoApprover struct {
+	store    Store
+	policies func() ApprovalPolicyResolver
+}
+
+func (a autoApprover) allows(ctx context.Context, tool string, input MessageInput, approval store.ActionApproval) bool {
+	policy := approvalpolicy.Default()
+	if a.policies != nil {
+		if resolver := a.policies(); resolver != nil {
+			if declared, ok := resolver.ApprovalPolicy(approval.WorkspaceID); ok {
+				policy = declared
+			}
+		}
+	}
+	decision := policy.Evaluate(approvalpolicy.Request{
+		Tool:     tool,
+		Role:     a.requesterRole(ctx, input),
+		Approval: approval,
+	})
+	return decision.Approve
+}
+
+func (a autoApprover) requesterRole(ctx context.Context, input MessageInput) string {
+	if input.FromUserID == "system:task-worker" {
+		return approvalpolicy.RoleSystem
+	}
+	if a.store == nil {
+		return ""
+	}
+	identity, err := a.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		return ""
+	}
+	return identity.Role
+}
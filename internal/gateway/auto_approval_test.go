@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/approvalpolicy"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeApprovalPolicies map[string]approvalpolicy.Policy
+
+func (f fakeApprovalPolicies) ApprovalPolicy(workspaceID string) (approvalpolicy.Policy, bool) {
+	policy, ok := f[workspaceID]
+	return policy, ok
+}
+
+func TestFetchUrlToolFollowsWorkspaceApprovalPolicy(t *testing.T) {
+	fStore := &fakeStore{identityErr: store.ErrIdentityNotFound}
+	exec := &fakeActionExecutor{result: executor.Result{Plugin: "sandbox", Message: "<p>docs</p>"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, exec, "", nil)
+	rule := approvalpolicy.Rule{Name: "docs reads", Tool: "fetch_url", Domains: []string{"example.com"}, Methods: []string{"GET"}}
+	rule.Normalize()
+	service.SetApprovalPolicyResolver(fakeApprovalPolicies{"ws-1": {Rules: []approvalpolicy.Rule{rule}}})
+	tool, ok := service.Registry().Get("fetch_url")
+	if !ok {
+		t.Fatal("expected fetch_url to be registered")
+	}
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{ID: "ctx-1", WorkspaceID: "ws-1"})
+	ctx = context.WithValue(ctx, ContextKeyInput, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "u1"})
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"url": "https://docs.example.com/guide"}`))
+	if err != nil {
+		t.Fatalf("fetch url: %v", err)
+	}
+	if !strings.Contains(res, "docs") || fStore.lastExecutionUpdate.ExecutionStatus != "succeeded" {
+		t.Fatalf("expected allowlisted fetch to run for a member, got %q %+v", res, fStore.lastExecutionUpdate)
+	}
+
+	res, err = tool.Execute(ctx, json.RawMessage(`{"url": "https://other.io/"}`))
+	if err != nil {
+		t.Fatalf("fetch url: %v", err)
+	}
+	if !strings.Contains(res, "Admin approval required") {
+		t.Fatalf("expected other domains to wait for an admin, got %q", res)
+	}
+
+	// Workspaces without rules keep the default: members always wait.
+	other := context.WithValue(ctx, ContextKeyRecord, store.ContextRecord{ID: "ctx-2", WorkspaceID: "ws-2"})
+	if res, _ := tool.Execute(other, json.RawMessage(`{"url": "https://docs.example.com/guide"}`)); !strings.Contains(res, "Admin approval required") {
+		t.Fatalf("expected default policy for ws-2, got %q", res)
+	}
+}
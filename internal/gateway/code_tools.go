@@ -19,10 +19,11 @@ type PythonCodeTool struct {
 	store          Store
 	actionExecutor ActionExecutor
 	workspaceRoot  string
+	approver       autoApprover
 }
 
 func NewPythonCodeTool(store Store, executor ActionExecutor, workspaceRoot string) *PythonCodeTool {
-	return &PythonCodeTool{store: store, actionExecutor: executor, workspaceRoot: workspaceRoot}
+	return &PythonCodeTool{store: store, actionExecutor: executor, workspaceRoot: workspaceRoot, approver: autoApprover{store: store}}
 }
 
 func (t *PythonCodeTool) Name() string { return "python_code" }
@@ -94,18 +95,7 @@ func (t *PythonCodeTool) Execute(ctx context.Context, rawArgs json.RawMessage) (
 	}
 
 	// 3. Check if we can auto-approve
-	canAutoApprove := false
-	if input.FromUserID == "system:task-worker" {
-		canAutoApprove = true
-	} else {
-		if identity, err := t.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID); err == nil {
-			if identity.Role == "admin" || identity.Role == "overlord" {
-				canAutoApprove = true
-			}
-		}
-	}
-	
-	if !canAutoApprove {
+	if !t.approver.allows(ctx, t.Name(), input, approval) {
 		return actions.FormatApprovalRequestNotice(approval.ID), nil
 	}
 
@@ -115,6 +105,9 @@ func (t *PythonCodeTool) Execute(ctx context.Context, rawArgs json.RawMessage) (
 		ApproverUserID: "system:agent",
 	})
 	if err != nil {
+		if reply, ok := quorumPendingToolReply(approved, err); ok {
+			return reply, nil
+		}
 		return "", fmt.Errorf("auto-approve failed: %w", err)
 	}
 
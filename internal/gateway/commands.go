@@ -41,7 +41,7 @@ func SlashCommands() []SlashCommand {
 			Name:                "monitor",
 			Description:         "Create a monitoring objective",
 			ArgumentName:        "goal",
-			ArgumentDescription: "Objective to monitor",
+			ArgumentDescription: "Objective to monitor, or template <name> key=value ...",
 			ArgumentRequired:    true,
 		},
 		{
@@ -58,6 +58,40 @@ func SlashCommands() []SlashCommand {
 			ArgumentDescription: "Prompt text",
 			ArgumentRequired:    true,
 		},
+		{
+			Name:                "voice",
+			Description:         "Turn spoken replies on or off for this channel",
+			ArgumentName:        "mode",
+			ArgumentDescription: "Use: on, off, or status",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "shared-knowledge",
+			Description:         "Include the shared knowledge workspace in searches for this channel",
+			ArgumentName:        "mode",
+			ArgumentDescription: "Use: on, off, or status",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "language",
+			Description:         "Set the language of replies in this channel",
+			ArgumentName:        "language",
+			ArgumentDescription: "Use: en, es, pt, or status",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "members",
+			Description:         "Mute members or limit task creation in this channel",
+			ArgumentName:        "change",
+			ArgumentDescription: "Optional: mute, unmute, allow-tasks or disallow-tasks <user-id>",
+		},
+		{
+			Name:                "silence",
+			Description:         "Stop or resume agent replies in this channel",
+			ArgumentName:        "mode",
+			ArgumentDescription: "Use: on, off, or status",
+			ArgumentRequired:    true,
+		},
 		{
 			Name:                "approve",
 			Description:         "Approve a pairing token",
@@ -80,14 +114,68 @@ func SlashCommands() []SlashCommand {
 			Name:                "approve-action",
 			Description:         "Approve a pending action",
 			ArgumentName:        "action_id",
-			ArgumentDescription: "Action ID",
+			ArgumentDescription: "Action ID or filters (--type, --context this, --older-than)",
 			ArgumentRequired:    true,
 		},
 		{
 			Name:                "deny-action",
 			Description:         "Deny a pending action",
 			ArgumentName:        "action_reason",
-			ArgumentDescription: "Action ID and optional reason",
+			ArgumentDescription: "Action ID or filters, and optional reason",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "grants",
+			Description:         "List or revoke sensitive-tool grants",
+			ArgumentName:        "revoke",
+			ArgumentDescription: "Optional: revoke <grant-id|all>",
+		},
+		{
+			Name:                "audit",
+			Description:         "List recent audit events for this workspace",
+			ArgumentName:        "filters",
+			ArgumentDescription: "Optional: --type, --tool, --user, --blocked, --since 2h, --context this, --limit n",
+		},
+		{
+			Name:                "tasks",
+			Description:         "List dead-lettered, failed, queued or running tasks of this workspace",
+			ArgumentName:        "state",
+			ArgumentDescription: "Use: dead, failed, queued, or running",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "cancel-task",
+			Description:         "Cancel a queued or running task of this workspace",
+			ArgumentName:        "task_id",
+			ArgumentDescription: "Task ID",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "artifacts",
+			Description:         "List the files a task of this workspace produced",
+			ArgumentName:        "task_id",
+			ArgumentDescription: "Task ID",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "explain",
+			Description:         "Preview tool calls without executing them",
+			ArgumentName:        "request",
+			ArgumentDescription: "Request to preview",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "run-objective",
+			Description:         "Run an objective now, outside its schedule",
+			ArgumentName:        "objective_id",
+			ArgumentDescription: "Objective ID",
+			ArgumentRequired:    true,
+		},
+		{
+			Name:                "appeal",
+			Description:         "Appeal a moderation decision about you",
+			ArgumentName:        "reason",
+			ArgumentDescription: "Optional case ID, then why the decision was wrong",
 			ArgumentRequired:    true,
 		},
 		{
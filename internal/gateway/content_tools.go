@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/dwizi/agent-runtime/internal/actions"
 	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/egress"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -17,10 +20,12 @@ import (
 type FetchUrlTool struct {
 	store          Store
 	actionExecutor ActionExecutor
+	approver       autoApprover
+	egress         egressChecker
 }
 
 func NewFetchUrlTool(store Store, executor ActionExecutor) *FetchUrlTool {
-	return &FetchUrlTool{store: store, actionExecutor: executor}
+	return &FetchUrlTool{store: store, actionExecutor: executor, approver: autoApprover{store: store}}
 }
 
 func (t *FetchUrlTool) Name() string { return "fetch_url" }
@@ -29,6 +34,14 @@ func (t *FetchUrlTool) ToolClass() tools.ToolClass {
 }
 func (t *FetchUrlTool) RequiresApproval() bool { return false }
 
+// UntrustedOutput marks web pages as data the agent takes no instructions from.
+func (t *FetchUrlTool) UntrustedOutput() bool { return true }
+
+// ExecutionPolicy retries fetches that fail on the network; they are read-only.
+func (t *FetchUrlTool) ExecutionPolicy() tools.ExecutionPolicy {
+	return tools.ExecutionPolicy{MaxRetries: networkToolRetries}
+}
+
 func (t *FetchUrlTool) Description() string {
 	return "Fetch a web page and convert it to readable Markdown. Supports 'curl' (fast, static) or 'chromium' (slow, js-rendered)."
 }
@@ -65,6 +78,9 @@ func (t *FetchUrlTool) Execute(ctx context.Context, rawArgs json.RawMessage) (st
 	if !ok {
 		return "", fmt.Errorf("internal error: message input missing from context")
 	}
+	if err := t.egress.check(ctx, t.Name(), record, input, egress.CurlTarget(args.URL)); err != nil {
+		return "", err
+	}
 
 	var actionType, actionTarget, actionSummary string
 	var payload map[string]any
@@ -104,13 +120,16 @@ func (t *FetchUrlTool) Execute(ctx context.Context, rawArgs json.RawMessage) (st
 	}
 
 	// 2. Check if we can auto-approve
-	if t.canAutoApprove(ctx, input) {
+	if t.approver.allows(ctx, t.Name(), input, approval) {
 		// Auto-approve
 		approved, err := t.store.ApproveActionApproval(ctx, store.ApproveActionApprovalInput{
 			ID:             approval.ID,
 			ApproverUserID: "system:agent",
 		})
 		if err != nil {
+			if reply, ok := quorumPendingToolReply(approved, err); ok {
+				return reply, nil
+			}
 			return "", fmt.Errorf("auto-approve failed: %w", err)
 		}
 
@@ -146,26 +165,18 @@ func (t *FetchUrlTool) Execute(ctx context.Context, rawArgs json.RawMessage) (st
 	return actions.FormatApprovalRequestNotice(approval.ID), nil
 }
 
-func (t *FetchUrlTool) canAutoApprove(ctx context.Context, input MessageInput) bool {
-	if input.FromUserID == "system:task-worker" {
-		return true
-	}
-	identity, err := t.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
-	if err != nil {
-		return false
-	}
-	return identity.Role == "admin" || identity.Role == "overlord"
-}
-
 // InspectFileTool executes safe inspection commands (head, tail, grep, wc, jq).
+// The describe command and binary files are handled in-process.
 type InspectFileTool struct {
 	store          Store
 	actionExecutor ActionExecutor
 	workspaceRoot  string
+	approver       autoApprover
+	guard          *filePolicyGuard
 }
 
 func NewInspectFileTool(store Store, executor ActionExecutor, workspaceRoot string) *InspectFileTool {
-	return &InspectFileTool{store: store, actionExecutor: executor, workspaceRoot: workspaceRoot}
+	return &InspectFileTool{store: store, actionExecutor: executor, workspaceRoot: workspaceRoot, approver: autoApprover{store: store}, guard: newFilePolicyGuard(store, workspaceRoot)}
 }
 
 func (t *InspectFileTool) Name() string { return "inspect_file" }
@@ -175,11 +186,11 @@ func (t *InspectFileTool) ToolClass() tools.ToolClass {
 func (t *InspectFileTool) RequiresApproval() bool { return false }
 
 func (t *InspectFileTool) Description() string {
-	return "Inspect files using shell tools (head, tail, grep, wc, jq). Use this for large files."
+	return "Inspect files using shell tools (head, tail, grep, wc, jq). Use this for large files. Use describe for file type, size, image metadata, and a hex preview of binaries."
 }
 
 func (t *InspectFileTool) ParametersSchema() string {
-	return `{"command": "head|tail|grep|wc|jq", "file": "string (relative path)", "args": ["string"]}`
+	return `{"command": "head|tail|grep|wc|jq|describe", "file": "string (relative path)", "args": ["string"]}`
 }
 
 func (t *InspectFileTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
@@ -193,7 +204,7 @@ func (t *InspectFileTool) Execute(ctx context.Context, rawArgs json.RawMessage)
 	}
 	
 	cmd := strings.ToLower(strings.TrimSpace(args.Command))
-	allowed := map[string]bool{"head": true, "tail": true, "grep": true, "wc": true, "jq": true}
+	allowed := map[string]bool{"head": true, "tail": true, "grep": true, "wc": true, "jq": true, "describe": true}
 	if !allowed[cmd] {
 		return "", fmt.Errorf("command not allowed: %s", cmd)
 	}
@@ -201,6 +212,9 @@ func (t *InspectFileTool) Execute(ctx context.Context, rawArgs json.RawMessage)
 	if strings.Contains(args.File, "..") || strings.HasPrefix(args.File, "/") {
 		return "", fmt.Errorf("invalid file path")
 	}
+	if summary, handled, err := t.inspectLocally(ctx, cmd, args.File); handled || err != nil {
+		return summary, err
+	}
 
 	// Construct full args: [native_flags..., file]
 	// Note: We rely on the sandbox to run this in the workspace root.
@@ -237,18 +251,7 @@ func (t *InspectFileTool) Execute(ctx context.Context, rawArgs json.RawMessage)
 	}
 
 	// Check if we can auto-approve
-	canAutoApprove := false
-	if input.FromUserID == "system:task-worker" {
-		canAutoApprove = true
-	} else {
-		if identity, err := t.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID); err == nil {
-			if identity.Role == "admin" || identity.Role == "overlord" {
-				canAutoApprove = true
-			}
-		}
-	}
-	
-	if !canAutoApprove {
+	if !t.approver.allows(ctx, t.Name(), input, approval) {
 		return actions.FormatApprovalRequestNotice(approval.ID), nil
 	}
 
@@ -257,6 +260,9 @@ func (t *InspectFileTool) Execute(ctx context.Context, rawArgs json.RawMessage)
 		ApproverUserID: "system:agent",
 	})
 	if err != nil {
+		if reply, ok := quorumPendingToolReply(approved, err); ok {
+			return reply, nil
+		}
 		return "", fmt.Errorf("auto-approve failed: %w", err)
 	}
 
@@ -282,6 +288,40 @@ func (t *InspectFileTool) Execute(ctx context.Context, rawArgs json.RawMessage)
 	return result.Message, nil
 }
 
+// inspectLocally checks the file policy, answers describe requests and
+// keeps text tools away from binary files, which would otherwise flood the
+// prompt with raw bytes.
+func (t *InspectFileTool) inspectLocally(ctx context.Context, cmd, relPath string) (string, bool, error) {
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", false, fmt.Errorf("internal error: context record missing from context")
+	}
+	if err := t.guard.check(ctx, t.Name(), record, relPath, fileAccessRead); err != nil {
+		return "", true, err
+	}
+	fullPath := filepath.Join(t.workspaceRoot, record.WorkspaceID, filepath.Clean(relPath))
+	if cmd == "describe" {
+		summary, err := describeFile(fullPath, relPath)
+		return summary, true, err
+	}
+	file, err := os.Open(fullPath)
+	if err != nil {
+		// Let the sandboxed command report missing files in its usual way.
+		return "", false, nil
+	}
+	defer file.Close()
+	sniff := make([]byte, fileSniffBytes)
+	read, _ := file.Read(sniff)
+	if !looksBinary(sniff[:read]) {
+		return "", false, nil
+	}
+	summary, err := describeFile(fullPath, relPath)
+	if err != nil {
+		return "", true, err
+	}
+	return fmt.Sprintf("%s is binary; %s skipped.\n%s", relPath, cmd, summary), true, nil
+}
+
 // Simple heuristic converter
 func convertHTMLToMarkdown(html string) string {
 	// Remove scripts and styles
@@ -0,0 +1,163 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/dataquery"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	dataQueryMaxInputBytes  = 10 * 1024 * 1024
+	dataQueryMaxOutputBytes = 16 * 1024
+	dataQueryMaxResults     = 200
+)
+
+// QueryDataTool applies jq-style expressions to JSON or YAML, either from a
+// scratchpad file or passed inline (e.g. a previous tool's output).
+type QueryDataTool struct {
+	guard *filePolicyGuard
+}
+
+type queryDataArgs struct {
+	Expression string `json:"expression"`
+	Path       string `json:"path"`
+	Input      string `json:"input"`
+	Format     string `json:"format"`
+	Raw        bool   `json:"raw"`
+}
+
+func NewQueryDataTool(store Store, workspaceRoot string) *QueryDataTool {
+	return &QueryDataTool{guard: newFilePolicyGuard(store, workspaceRoot)}
+}
+
+func (t *QueryDataTool) Name() string { return "query_data" }
+
+func (t *QueryDataTool) ToolClass() tools.ToolClass { return tools.ToolClassGeneral }
+
+func (t *QueryDataTool) RequiresApproval() bool { return false }
+
+func (t *QueryDataTool) Description() string {
+	return "Extract values from JSON or YAML with a jq-style expression (paths, [], select, map, keys, length, sort_by, group_by, ...). Use instead of python for simple lookups."
+}
+
+func (t *QueryDataTool) ParametersSchema() string {
+	return `{"expression": "string (jq expression, e.g. .items[] | select(.status == \"failed\") | .id)", "path": "string (relative .json/.yaml file; or use input)", "input": "string (inline JSON/YAML, e.g. a previous tool output)", "format": "string (optional: json|yaml; default detect)", "raw": "boolean (optional; print strings without quotes)"}`
+}
+
+func (t *QueryDataTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args queryDataArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Expression) == "" {
+		return fmt.Errorf("expression is required")
+	}
+	hasPath := strings.TrimSpace(args.Path) != ""
+	hasInput := strings.TrimSpace(args.Input) != ""
+	if hasPath == hasInput {
+		return fmt.Errorf("exactly one of path or input is required")
+	}
+	if len(args.Input) > dataQueryMaxInputBytes {
+		return fmt.Errorf("input is larger than %d bytes", dataQueryMaxInputBytes)
+	}
+	switch strings.ToLower(strings.TrimSpace(args.Format)) {
+	case "", "json", "yaml", "yml":
+	default:
+		return fmt.Errorf("format must be json or yaml")
+	}
+	if _, err := dataquery.Parse(args.Expression); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *QueryDataTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args queryDataArgs
+	_ = json.Unmarshal(rawArgs, &args)
+
+	content := []byte(args.Input)
+	format := args.Format
+	if strings.TrimSpace(args.Path) != "" {
+		record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+		if !ok {
+			return "", fmt.Errorf("internal error: context record missing from context")
+		}
+		fullPath, err := t.guard.resolve(ctx, t.Name(), record, args.Path, fileAccessRead)
+		if err != nil {
+			return "", err
+		}
+		content, err = readDataFile(fullPath, args.Path)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(format) == "" {
+			format = dataFormatFromExtension(args.Path)
+		}
+	}
+
+	document, err := dataquery.Decode(content, format)
+	if err != nil {
+		return "", err
+	}
+	query, _ := dataquery.Parse(args.Expression)
+	results, err := query.Run(document)
+	if err != nil {
+		return "", fmt.Errorf("query failed: %w", err)
+	}
+	if len(results) == 0 {
+		return "No results.", nil
+	}
+
+	builder := strings.Builder{}
+	for index, result := range results {
+		line := dataquery.Format(result, args.Raw)
+		if index == dataQueryMaxResults || builder.Len()+len(line) > dataQueryMaxOutputBytes {
+			fmt.Fprintf(&builder, "[truncated: showing %d of %d results; narrow the expression]", index, len(results))
+			break
+		}
+		builder.WriteString(line)
+		builder.WriteByte('\n')
+	}
+	return strings.TrimRight(builder.String(), "\n"), nil
+}
+
+func readDataFile(fullPath, displayPath string) ([]byte, error) {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found: %s", displayPath)
+		}
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, dataQueryMaxInputBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	if len(content) > dataQueryMaxInputBytes {
+		return nil, fmt.Errorf("file is larger than %d bytes: %s", dataQueryMaxInputBytes, displayPath)
+	}
+	return content, nil
+}
+
+func dataFormatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl", ".geojson":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return ""
+	}
+}
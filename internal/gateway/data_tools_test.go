@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestQueryDataToolReadsFilesAndInlineInput(t *testing.T) {
+	tempDir := t.TempDir()
+	scratch := filepath.Join(tempDir, "ws1", "scratch")
+	_ = os.MkdirAll(scratch, 0o755)
+	_ = os.WriteFile(filepath.Join(scratch, "deploy.yaml"), []byte("spec:\n  replicas: 3\n  containers:\n    - name: web\n      image: nginx:1.27\n    - name: sidecar\n      image: envoy:1.30\n"), 0o644)
+
+	tool := NewQueryDataTool(nil, tempDir)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"path": "deploy.yaml", "expression": ".spec.containers[] | select(.name == \"web\") | .image", "raw": true}`))
+	if err != nil {
+		t.Fatalf("query file: %v", err)
+	}
+	if res != "nginx:1.27" {
+		t.Fatalf("unexpected file result %q", res)
+	}
+
+	res, err = tool.Execute(ctx, json.RawMessage(`{"input": "{\"items\": [{\"id\": 1}, {\"id\": 2}]}", "expression": "[.items[].id]"}`))
+	if err != nil {
+		t.Fatalf("query input: %v", err)
+	}
+	if res != "[1,2]" {
+		t.Fatalf("unexpected inline result %q", res)
+	}
+
+	res, err = tool.Execute(ctx, json.RawMessage(`{"input": "[]", "expression": ".[]"}`))
+	if err != nil || res != "No results." {
+		t.Fatalf("expected no results, got %q %v", res, err)
+	}
+}
+
+func TestQueryDataToolValidateArgs(t *testing.T) {
+	tool := NewQueryDataTool(nil, t.TempDir())
+	for _, raw := range []string{
+		`{"expression": ".a"}`,
+		`{"expression": ".a", "path": "a.json", "input": "{}"}`,
+		`{"expression": ".a[", "input": "{}"}`,
+		`{"expression": ".a", "input": "{}", "format": "toml"}`,
+	} {
+		if err := tool.ValidateArgs(json.RawMessage(raw)); err == nil {
+			t.Errorf("expected %s to be rejected", raw)
+		}
+	}
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+	if _, err := tool.Execute(ctx, json.RawMessage(`{"expression": ".", "path": "../secrets.json"}`)); err == nil {
+		t.Error("expected traversal to be rejected")
+	}
+}
@@ -0,0 +1,8 @@
+package gateway
+
+// SetDryRun makes tools that write outside the scratch directory, such as
+// learn_skill, report what they would have written instead of writing it.
+// Actions are simulated by the action executor, not here.
+func (s *Service) SetDryRun(enabled bool) {
+	s.dryRun = enabled
+}
@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestDryRunLearnSkillDoesNotWrite(t *testing.T) {
+	root := t.TempDir()
+	service := New(&fakeStore{}, &fakeEngine{}, &fakeRetriever{}, nil, root, nil)
+	service.SetDryRun(true)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+
+	reply, err := service.Registry().ExecuteTool(ctx, "learn_skill", json.RawMessage(`{"name":"deploys","content":"Deploy on Tuesdays."}`))
+	if err != nil {
+		t.Fatalf("learn_skill: %v", err)
+	}
+	if !strings.Contains(reply, "Dry run") {
+		t.Fatalf("expected a dry-run reply, got %q", reply)
+	}
+	if _, err := os.Stat(filepath.Join(root, "ws1", "context", "skills")); !os.IsNotExist(err) {
+		t.Fatalf("expected no skill directory in dry-run mode, got %v", err)
+	}
+}
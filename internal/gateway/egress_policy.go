@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/egress"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// EgressGuard checks outbound URLs against the runtime and workspace egress
+// policy.
+type EgressGuard interface {
+	Check(ctx context.Context, workspaceID, rawURL string) error
+}
+
+// SetEgressGuard makes curl, fetch_url, web_search and browse_page refuse
+// URLs the egress policy does not allow, before an approval is created.
+func (s *Service) SetEgressGuard(guard EgressGuard) {
+	s.egressGuard = guard
+}
+
+// egressChecker is shared by the network tools. Tools built outside New
+// have no guard and skip the check.
+type egressChecker struct {
+	store Store
+	guard func() EgressGuard
+}
+
+func (c egressChecker) check(ctx context.Context, toolName string, record store.ContextRecord, input MessageInput, targets ...string) error {
+	if c.guard == nil {
+		return nil
+	}
+	guard := c.guard()
+	if guard == nil {
+		return nil
+	}
+	for _, target := range targets {
+		err := guard.Check(ctx, record.WorkspaceID, target)
+		if err == nil {
+			continue
+		}
+		var violation *egress.Violation
+		if errors.As(err, &violation) {
+			c.audit(ctx, toolName, record, input, violation)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkCurl checks every URL of a curl command line and refuses flags that
+// would send the request somewhere else.
+func (c egressChecker) checkCurl(ctx context.Context, toolName string, record store.ContextRecord, input MessageInput, args []string) error {
+	if c.guard == nil || c.guard() == nil {
+		return nil
+	}
+	targets, err := egress.CurlTargets(args)
+	if err != nil {
+		var violation *egress.Violation
+		if errors.As(err, &violation) {
+			c.audit(ctx, toolName, record, input, violation)
+		}
+		return err
+	}
+	return c.check(ctx, toolName, record, input, targets...)
+}
+
+func (c egressChecker) audit(ctx context.Context, toolName string, record store.ContextRecord, input MessageInput, violation *egress.Violation) {
+	if c.store == nil || strings.TrimSpace(record.ID) == "" || strings.TrimSpace(input.Connector) == "" || strings.TrimSpace(input.ExternalID) == "" {
+		return
+	}
+	_, _ = c.store.CreateAgentAuditEvent(ctx, store.CreateAgentAuditEventInput{
+		WorkspaceID:  record.WorkspaceID,
+		ContextID:    record.ID,
+		Connector:    input.Connector,
+		ExternalID:   input.ExternalID,
+		SourceUserID: input.FromUserID,
+		EventType:    "egress_blocked",
+		Stage:        "audit.egress_blocked",
+		ToolName:     toolName,
+		Blocked:      true,
+		BlockReason:  violation.Reason,
+		Message:      fmt.Sprintf("target=%s", violation.Target),
+	})
+}
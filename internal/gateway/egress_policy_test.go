@@ -0,0 +1,44 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/egress"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestNetworkToolsRefuseEgressViolations(t *testing.T) {
+	fStore := &fakeStore{}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	service.SetEgressGuard(egress.New(egress.Config{}))
+	record := store.ContextRecord{ID: "ctx-1", WorkspaceID: "ws-1"}
+	ctx := context.WithValue(withContextRecord(context.Background(), record), ContextKeyInput, MessageInput{
+		Connector:  "discord",
+		ExternalID: "c1",
+		FromUserID: "u1",
+	})
+
+	for _, call := range []struct {
+		tool string
+		args string
+	}{
+		{"fetch_url", `{"url": "http://169.254.169.254/latest/meta-data/"}`},
+		{"curl", `{"args": ["-sS", "-H", "Accept: */*", "127.0.0.1:8080/admin"]}`},
+		{"curl", `{"args": ["-sSx", "proxy.internal:3128", "https://example.com"]}`},
+	} {
+		_, err := service.Registry().ExecuteTool(ctx, call.tool, json.RawMessage(call.args))
+		var violation *egress.Violation
+		if !errors.As(err, &violation) {
+			t.Fatalf("%s %s: expected an egress violation, got %v", call.tool, call.args, err)
+		}
+	}
+	if len(fStore.actionApprovals) != 0 {
+		t.Fatalf("expected no approvals for refused requests, got %+v", fStore.actionApprovals)
+	}
+	if len(fStore.auditEvents) != 3 || fStore.auditEvents[0].EventType != "egress_blocked" || fStore.auditEvents[0].ToolName != "fetch_url" {
+		t.Fatalf("expected egress_blocked audit events, got %+v", fStore.auditEvents)
+	}
+}
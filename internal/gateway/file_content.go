@@ -0,0 +1,182 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	fileReadDefaultMaxBytes = 64 * 1024
+	fileReadHardMaxBytes    = 256 * 1024
+	fileSniffBytes          = 512
+	fileHexPreviewBytes     = 256
+)
+
+// readFileWindow returns text content for [offset, offset+limit). Small text
+// files come back verbatim; larger ones get a paging footer, and binaries are
+// summarized instead of dumped into the prompt.
+func readFileWindow(fullPath, displayPath string, offset, limit int64) (string, error) {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("file not found: %s", displayPath)
+		}
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("path is a directory: %s", displayPath)
+	}
+	size := info.Size()
+	if offset < 0 {
+		return "", fmt.Errorf("offset must be >= 0")
+	}
+	if offset > 0 && offset >= size {
+		return "", fmt.Errorf("offset %d is beyond end of file (%d bytes)", offset, size)
+	}
+	if limit <= 0 {
+		limit = fileReadDefaultMaxBytes
+	}
+	if limit > fileReadHardMaxBytes {
+		limit = fileReadHardMaxBytes
+	}
+
+	sniff := make([]byte, fileSniffBytes)
+	sniffed, err := file.ReadAt(sniff, 0)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	sniff = sniff[:sniffed]
+	if looksBinary(sniff) {
+		return describeBinaryFile(file, displayPath, size, offset, sniff)
+	}
+
+	window := make([]byte, limit)
+	read, err := file.ReadAt(window, offset)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	window = trimToRuneBoundary(window[:read], offset+int64(read) < size)
+	end := offset + int64(len(window))
+	if offset == 0 && end >= size {
+		return string(window), nil
+	}
+	footer := fmt.Sprintf("\n\n[bytes %d-%d of %d]", offset, end, size)
+	if end < size {
+		footer = fmt.Sprintf("\n\n[bytes %d-%d of %d; continue with offset=%d]", offset, end, size, end)
+	}
+	return string(window) + footer, nil
+}
+
+// describeBinaryFile summarizes a binary file with its detected type, image
+// metadata when available, and a hexdump preview at offset.
+func describeBinaryFile(file *os.File, displayPath string, size, offset int64, sniff []byte) (string, error) {
+	lines := []string{
+		fmt.Sprintf("Binary file: %s", displayPath),
+		fmt.Sprintf("- size: %d bytes", size),
+		fmt.Sprintf("- type: %s", http.DetectContentType(sniff)),
+	}
+	if _, err := file.Seek(0, io.SeekStart); err == nil {
+		if config, format, err := image.DecodeConfig(file); err == nil {
+			lines = append(lines, fmt.Sprintf("- image: %s %dx%d", format, config.Width, config.Height))
+		}
+	}
+	preview := make([]byte, fileHexPreviewBytes)
+	read, err := file.ReadAt(preview, offset)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	if read > 0 {
+		lines = append(lines, fmt.Sprintf("- hex preview (bytes %d-%d):", offset, offset+int64(read)))
+		lines = append(lines, strings.TrimRight(hexDumpAt(preview[:read], offset), "\n"))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func looksBinary(sample []byte) bool {
+	if len(sample) == 0 {
+		return false
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+	contentType := http.DetectContentType(sample)
+	if strings.HasPrefix(contentType, "text/") || strings.Contains(contentType, "json") || strings.Contains(contentType, "xml") {
+		return false
+	}
+	// A truncated multibyte rune at the end of the sample is not a binary marker.
+	return !utf8.Valid(trimToRuneBoundary(sample, true))
+}
+
+// trimToRuneBoundary drops a trailing partial UTF-8 sequence when more data follows.
+func trimToRuneBoundary(content []byte, truncated bool) []byte {
+	if !truncated {
+		return content
+	}
+	for cut := 0; cut < utf8.UTFMax && cut < len(content); cut++ {
+		if utf8.Valid(content[:len(content)-cut]) {
+			return content[:len(content)-cut]
+		}
+	}
+	return content
+}
+
+// hexDumpAt renders hex.Dump output with addresses relative to the file.
+func hexDumpAt(content []byte, offset int64) string {
+	lines := strings.Split(strings.TrimRight(hex.Dump(content), "\n"), "\n")
+	for index, line := range lines {
+		if len(line) < 8 {
+			continue
+		}
+		lines[index] = fmt.Sprintf("%08x", offset+int64(index*16)) + line[8:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeFile reports type and size for any file; binaries also get image
+// metadata and a hex preview.
+func describeFile(fullPath, displayPath string) (string, error) {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("file not found: %s", displayPath)
+		}
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("path is a directory: %s", displayPath)
+	}
+	sniff := make([]byte, fileSniffBytes)
+	read, err := file.ReadAt(sniff, 0)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	sniff = sniff[:read]
+	if looksBinary(sniff) {
+		return describeBinaryFile(file, displayPath, info.Size(), 0, sniff)
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("Text file: %s", displayPath),
+		fmt.Sprintf("- size: %d bytes", info.Size()),
+		fmt.Sprintf("- type: %s", http.DetectContentType(sniff)),
+	}, "\n"), nil
+}
@@ -0,0 +1,236 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/agenterr"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	filePolicyWorkspaceRelPath = "context/file_policy.json"
+	filePolicyContextRelPath   = "context/agents/{context_id}/file_policy.json"
+)
+
+type fileAccessMode string
+
+const (
+	fileAccessRead  fileAccessMode = "read"
+	fileAccessWrite fileAccessMode = "write"
+	fileAccessList  fileAccessMode = "list"
+)
+
+// builtinDeniedFilePatterns always apply, regardless of workspace or context policy.
+var builtinDeniedFilePatterns = []string{
+	"secrets/",
+	"**/.env",
+	"**/.env.*",
+	"**/*.pem",
+	"**/*.key",
+}
+
+// FilePathPolicy scopes the scratch file tools. Patterns are slash-separated
+// globs relative to the scratch directory; `**` matches any number of
+// directories and a trailing `/` matches everything below a directory.
+// Protected paths stay writable, but only with sensitive-action approval.
+type FilePathPolicy struct {
+	Allow     []string `json:"allow"`
+	ReadOnly  []string `json:"read_only"`
+	Protected []string `json:"protected"`
+	Deny      []string `json:"deny"`
+}
+
+type filePolicyViolation struct {
+	Path   string
+	Mode   fileAccessMode
+	Reason string
+}
+
+func (v *filePolicyViolation) Error() string {
+	return fmt.Sprintf("file policy denied %s of %s: %s", v.Mode, v.Path, v.Reason)
+}
+
+// filePolicyGuard resolves and enforces path policies for the file tools and
+// records every violation as an agent audit event.
+type filePolicyGuard struct {
+	workspaceRoot string
+	auditStore    Store
+}
+
+func newFilePolicyGuard(auditStore Store, workspaceRoot string) *filePolicyGuard {
+	return &filePolicyGuard{workspaceRoot: workspaceRoot, auditStore: auditStore}
+}
+
+// resolve validates relPath against the scratch sandbox and the active policy,
+// returning the absolute path on success.
+func (g *filePolicyGuard) resolve(ctx context.Context, toolName string, record store.ContextRecord, relPath string, mode fileAccessMode) (string, error) {
+	fullPath, err := resolveScratchPath(g.workspaceRoot, record.WorkspaceID, relPath)
+	if err != nil {
+		return "", err
+	}
+	if err := g.check(ctx, toolName, record, relPath, mode); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
+
+// check enforces the built-in denies and the active policy on relPath
+// without resolving it, for tools that address files outside scratch.
+func (g *filePolicyGuard) check(ctx context.Context, toolName string, record store.ContextRecord, relPath string, mode fileAccessMode) error {
+	policy, err := g.policyFor(record)
+	if err != nil {
+		return err
+	}
+	normalized := normalizePolicyPath(relPath)
+	if reason := policy.check(normalized, mode); reason != "" {
+		violation := &filePolicyViolation{Path: normalized, Mode: mode, Reason: reason}
+		g.audit(ctx, toolName, record, violation)
+		return violation
+	}
+	if mode == fileAccessWrite && !agent.HasSensitiveToolApproval(ctx) {
+		if pattern, ok := matchAnyPolicyPattern(policy.Protected, normalized); ok {
+			return fmt.Errorf("%w: %s is protected (pattern %q)", agenterr.ErrApprovalRequired, normalized, pattern)
+		}
+	}
+	return nil
+}
+
+// visible reports whether a listed entry may be shown; denied entries are hidden.
+func (g *filePolicyGuard) visible(policy FilePathPolicy, relPath string) bool {
+	_, denied := matchAnyPolicyPattern(policy.deniedPatterns(), normalizePolicyPath(relPath))
+	return !denied
+}
+
+// policyFor loads the context policy, falling back to the workspace policy.
+func (g *filePolicyGuard) policyFor(record store.ContextRecord) (FilePathPolicy, error) {
+	root := strings.TrimSpace(g.workspaceRoot)
+	workspaceID := strings.TrimSpace(record.WorkspaceID)
+	candidates := []string{}
+	if contextID := strings.TrimSpace(record.ID); contextID != "" {
+		candidates = append(candidates, strings.ReplaceAll(filePolicyContextRelPath, "{context_id}", contextID))
+	}
+	candidates = append(candidates, filePolicyWorkspaceRelPath)
+	for _, relPath := range candidates {
+		content, err := os.ReadFile(filepath.Join(root, workspaceID, filepath.FromSlash(relPath)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return FilePathPolicy{}, fmt.Errorf("read file policy: %w", err)
+		}
+		var policy FilePathPolicy
+		if err := json.Unmarshal(content, &policy); err != nil {
+			return FilePathPolicy{}, fmt.Errorf("invalid file policy %s: %w", relPath, err)
+		}
+		return policy, nil
+	}
+	return FilePathPolicy{}, nil
+}
+
+func (g *filePolicyGuard) audit(ctx context.Context, toolName string, record store.ContextRecord, violation *filePolicyViolation) {
+	if g.auditStore == nil {
+		return
+	}
+	input, _ := ctx.Value(ContextKeyInput).(MessageInput)
+	if strings.TrimSpace(record.ID) == "" || strings.TrimSpace(input.Connector) == "" || strings.TrimSpace(input.ExternalID) == "" {
+		return
+	}
+	_, _ = g.auditStore.CreateAgentAuditEvent(ctx, store.CreateAgentAuditEventInput{
+		WorkspaceID:  record.WorkspaceID,
+		ContextID:    record.ID,
+		Connector:    input.Connector,
+		ExternalID:   input.ExternalID,
+		SourceUserID: input.FromUserID,
+		EventType:    "file_policy_violation",
+		Stage:        "audit.file_policy_violation",
+		ToolName:     toolName,
+		Blocked:      true,
+		BlockReason:  violation.Reason,
+		Message:      fmt.Sprintf("path=%s mode=%s", violation.Path, violation.Mode),
+	})
+}
+
+func (p FilePathPolicy) deniedPatterns() []string {
+	return append(append([]string{}, builtinDeniedFilePatterns...), p.Deny...)
+}
+
+// check returns an empty string when access is permitted, otherwise the reason.
+func (p FilePathPolicy) check(relPath string, mode fileAccessMode) string {
+	if pattern, ok := matchAnyPolicyPattern(p.deniedPatterns(), relPath); ok {
+		return fmt.Sprintf("path matches denied pattern %q", pattern)
+	}
+	// Listing the scratch root stays possible so restricted agents can discover allowed areas.
+	if len(p.Allow) > 0 && !(mode == fileAccessList && relPath == ".") {
+		if _, ok := matchAnyPolicyPattern(p.Allow, relPath); !ok {
+			return "path is outside the allowed areas"
+		}
+	}
+	if mode == fileAccessWrite {
+		if pattern, ok := matchAnyPolicyPattern(p.ReadOnly, relPath); ok {
+			return fmt.Sprintf("path is read-only (pattern %q)", pattern)
+		}
+	}
+	return ""
+}
+
+func normalizePolicyPath(relPath string) string {
+	return path.Clean(filepath.ToSlash(strings.TrimSpace(relPath)))
+}
+
+func matchAnyPolicyPattern(patterns []string, relPath string) (string, bool) {
+	for _, pattern := range patterns {
+		if matchPolicyPattern(pattern, relPath) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// matchPolicyPattern matches relPath or any of its parent directories, so a
+// pattern that covers a directory also covers its contents.
+func matchPolicyPattern(pattern, relPath string) bool {
+	pattern = strings.TrimSpace(filepath.ToSlash(pattern))
+	if pattern == "" {
+		return false
+	}
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(relPath, "/")
+	for end := len(pathParts); end > 0; end-- {
+		if matchPolicySegments(patternParts, pathParts[:end]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPolicySegments(patternParts, pathParts []string) bool {
+	if len(patternParts) == 0 {
+		return len(pathParts) == 0
+	}
+	if patternParts[0] == "**" {
+		for skip := 0; skip <= len(pathParts); skip++ {
+			if matchPolicySegments(patternParts[1:], pathParts[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(pathParts) == 0 {
+		return false
+	}
+	matched, err := path.Match(patternParts[0], pathParts[0])
+	if err != nil || !matched {
+		return false
+	}
+	return matchPolicySegments(patternParts[1:], pathParts[1:])
+}
@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestFilePathPolicyCheck(t *testing.T) {
+	policy := FilePathPolicy{
+		Allow:    []string{"notes/", "reports/*.md"},
+		ReadOnly: []string{"notes/reference/"},
+		Deny:     []string{"notes/private*"},
+	}
+	cases := []struct {
+		path    string
+		mode    fileAccessMode
+		allowed bool
+	}{
+		{"notes/todo.md", fileAccessWrite, true},
+		{"notes/reference/spec.md", fileAccessRead, true},
+		{"notes/reference/spec.md", fileAccessWrite, false},
+		{"notes/private-diary.md", fileAccessRead, false},
+		{"reports/weekly.md", fileAccessRead, true},
+		{"reports/nested/weekly.md", fileAccessRead, false},
+		{"other.txt", fileAccessRead, false},
+		{".", fileAccessList, true},
+		{"secrets/api.txt", fileAccessRead, false},
+		{"notes/deploy/.env", fileAccessRead, false},
+	}
+	for _, tc := range cases {
+		reason := policy.check(tc.path, tc.mode)
+		if (reason == "") != tc.allowed {
+			t.Errorf("%s %s: expected allowed=%v, got reason %q", tc.mode, tc.path, tc.allowed, reason)
+		}
+	}
+}
+
+func TestFileToolsEnforceContextPolicyAndAudit(t *testing.T) {
+	tempDir := t.TempDir()
+	fStore := &fakeStore{}
+	record := store.ContextRecord{ID: "ctx-1", WorkspaceID: "ws1"}
+	policyPath := filepath.Join(tempDir, "ws1", "context", "agents", "ctx-1", "file_policy.json")
+	if err := os.MkdirAll(filepath.Dir(policyPath), 0o755); err != nil {
+		t.Fatalf("mkdir policy dir: %v", err)
+	}
+	policy := `{"allow": ["drafts/", "shared/"], "read_only": ["shared/"]}`
+	if err := os.WriteFile(policyPath, []byte(policy), 0o644); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	scratch := filepath.Join(tempDir, "ws1", "scratch")
+	_ = os.MkdirAll(filepath.Join(scratch, "shared"), 0o755)
+	_ = os.MkdirAll(filepath.Join(scratch, "secrets"), 0o755)
+	_ = os.WriteFile(filepath.Join(scratch, "shared", "faq.md"), []byte("faq"), 0o644)
+
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, record)
+	ctx = context.WithValue(ctx, ContextKeyInput, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "u1"})
+
+	writeTool := NewWriteFileTool(fStore, tempDir)
+	if _, err := writeTool.Execute(ctx, json.RawMessage(`{"path": "drafts/a.md", "content": "ok"}`)); err != nil {
+		t.Fatalf("expected allowed write, got %v", err)
+	}
+	_, err := writeTool.Execute(ctx, json.RawMessage(`{"path": "shared/faq.md", "content": "overwrite"}`))
+	var violation *filePolicyViolation
+	if !errors.As(err, &violation) || !strings.Contains(violation.Reason, "read-only") {
+		t.Fatalf("expected read-only violation, got %v", err)
+	}
+
+	readTool := NewReadFileTool(fStore, tempDir)
+	if content, err := readTool.Execute(ctx, json.RawMessage(`{"path": "shared/faq.md"}`)); err != nil || content != "faq" {
+		t.Fatalf("expected read of read-only area, got %q %v", content, err)
+	}
+	if _, err := readTool.Execute(ctx, json.RawMessage(`{"path": "elsewhere.txt"}`)); !errors.As(err, &violation) {
+		t.Fatalf("expected violation outside allowed areas, got %v", err)
+	}
+
+	listTool := NewListFilesTool(fStore, tempDir)
+	listing, err := listTool.Execute(ctx, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("list root: %v", err)
+	}
+	if strings.Contains(listing, "secrets") || !strings.Contains(listing, "shared/") {
+		t.Fatalf("expected denied entries hidden from listing, got %s", listing)
+	}
+
+	if len(fStore.auditEvents) != 2 {
+		t.Fatalf("expected two audited violations, got %d", len(fStore.auditEvents))
+	}
+	event := fStore.auditEvents[0]
+	if event.EventType != "file_policy_violation" || !event.Blocked || event.ToolName != "write_file" || !strings.Contains(event.Message, "path=shared/faq.md") {
+		t.Fatalf("unexpected audit event %+v", event)
+	}
+}
@@ -5,19 +5,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
 // WriteFileTool writes content to a file in the workspace scratchpad.
 type WriteFileTool struct {
-	workspaceRoot string
+	guard   *filePolicyGuard
+	secrets secretScanner
 }
 
-func NewWriteFileTool(workspaceRoot string) *WriteFileTool {
-	return &WriteFileTool{workspaceRoot: workspaceRoot}
+func NewWriteFileTool(store Store, workspaceRoot string) *WriteFileTool {
+	return &WriteFileTool{guard: newFilePolicyGuard(store, workspaceRoot), secrets: secretScanner{store: store}}
 }
 
 func (t *WriteFileTool) Name() string { return "write_file" }
@@ -44,7 +48,12 @@ func (t *WriteFileTool) Execute(ctx context.Context, rawArgs json.RawMessage) (s
 		return "", fmt.Errorf("internal error: context record missing from context")
 	}
 
-	fullPath, err := resolveScratchPath(t.workspaceRoot, record.WorkspaceID, args.Path)
+	fullPath, err := t.guard.resolve(ctx, t.Name(), record, args.Path, fileAccessWrite)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := t.secrets.scan(ctx, t.Name(), record, args.Path, args.Content)
 	if err != nil {
 		return "", err
 	}
@@ -53,35 +62,37 @@ func (t *WriteFileTool) Execute(ctx context.Context, rawArgs json.RawMessage) (s
 		return "", fmt.Errorf("create directory: %w", err)
 	}
 
-	if err := os.WriteFile(fullPath, []byte(args.Content), 0o644); err != nil {
+	if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
 		return "", fmt.Errorf("write file: %w", err)
 	}
 
-	return fmt.Sprintf("Wrote %d bytes to %s", len(args.Content), args.Path), nil
+	return fmt.Sprintf("Wrote %d bytes to %s%s", len(content), args.Path, maskedNote(args.Content, content)), nil
 }
 
 // ReadFileTool reads content from a file in the workspace scratchpad.
 type ReadFileTool struct {
-	workspaceRoot string
+	guard *filePolicyGuard
 }
 
-func NewReadFileTool(workspaceRoot string) *ReadFileTool {
-	return &ReadFileTool{workspaceRoot: workspaceRoot}
+func NewReadFileTool(store Store, workspaceRoot string) *ReadFileTool {
+	return &ReadFileTool{guard: newFilePolicyGuard(store, workspaceRoot)}
 }
 
 func (t *ReadFileTool) Name() string { return "read_file" }
 
 func (t *ReadFileTool) Description() string {
-	return "Read text content from a file in the workspace scratchpad."
+	return "Read text content from a file in the workspace scratchpad. Large files are paged by byte range; binary files return a type summary and hex preview."
 }
 
 func (t *ReadFileTool) ParametersSchema() string {
-	return `{"path": "string (relative)"}`
+	return `{"path": "string (relative)", "offset": "integer (optional byte offset)", "limit": "integer (optional max bytes, default 65536)"}`
 }
 
 func (t *ReadFileTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
 	var args struct {
-		Path string `json:"path"`
+		Path   string `json:"path"`
+		Offset int64  `json:"offset"`
+		Limit  int64  `json:"limit"`
 	}
 	if err := json.Unmarshal(rawArgs, &args); err != nil {
 		return "", fmt.Errorf("invalid arguments: %w", err)
@@ -92,29 +103,21 @@ func (t *ReadFileTool) Execute(ctx context.Context, rawArgs json.RawMessage) (st
 		return "", fmt.Errorf("internal error: context record missing from context")
 	}
 
-	fullPath, err := resolveScratchPath(t.workspaceRoot, record.WorkspaceID, args.Path)
+	fullPath, err := t.guard.resolve(ctx, t.Name(), record, args.Path, fileAccessRead)
 	if err != nil {
 		return "", err
 	}
 
-	content, err := os.ReadFile(fullPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return "", fmt.Errorf("file not found: %s", args.Path)
-		}
-		return "", fmt.Errorf("read file: %w", err)
-	}
-
-	return string(content), nil
+	return readFileWindow(fullPath, args.Path, args.Offset, args.Limit)
 }
 
 // ListFilesTool lists files in the workspace scratchpad.
 type ListFilesTool struct {
-	workspaceRoot string
+	guard *filePolicyGuard
 }
 
-func NewListFilesTool(workspaceRoot string) *ListFilesTool {
-	return &ListFilesTool{workspaceRoot: workspaceRoot}
+func NewListFilesTool(store Store, workspaceRoot string) *ListFilesTool {
+	return &ListFilesTool{guard: newFilePolicyGuard(store, workspaceRoot)}
 }
 
 func (t *ListFilesTool) Name() string { return "list_files" }
@@ -128,6 +131,13 @@ func (t *ListFilesTool) ParametersSchema() string {
 }
 
 func (t *ListFilesTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	result, err := t.ExecuteStructured(ctx, rawArgs)
+	return result.Summary, err
+}
+
+// ExecuteStructured returns the listing as a Name/Type/Size table alongside
+// the plain-text summary.
+func (t *ListFilesTool) ExecuteStructured(ctx context.Context, rawArgs json.RawMessage) (tools.StructuredResult, error) {
 	var args struct {
 		Path string `json:"path"`
 	}
@@ -135,40 +145,53 @@ func (t *ListFilesTool) Execute(ctx context.Context, rawArgs json.RawMessage) (s
 
 	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
 	if !ok {
-		return "", fmt.Errorf("internal error: context record missing from context")
+		return tools.StructuredResult{}, fmt.Errorf("internal error: context record missing from context")
 	}
 
-	targetDir, err := resolveScratchPath(t.workspaceRoot, record.WorkspaceID, args.Path)
+	targetDir, err := t.guard.resolve(ctx, t.Name(), record, args.Path, fileAccessList)
 	if err != nil {
-		return "", err
+		return tools.StructuredResult{}, err
+	}
+	policy, err := t.guard.policyFor(record)
+	if err != nil {
+		return tools.StructuredResult{}, err
 	}
 
 	entries, err := os.ReadDir(targetDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "Directory not found.", nil
+			return tools.StructuredResult{Summary: "Directory not found."}, nil
 		}
-		return "", fmt.Errorf("read dir: %w", err)
+		return tools.StructuredResult{}, fmt.Errorf("read dir: %w", err)
 	}
 
 	if len(entries) == 0 {
-		return "No files found.", nil
+		return tools.StructuredResult{Summary: "No files found."}, nil
 	}
 
 	var lines []string
+	table := tools.Table{Columns: []string{"Name", "Type", "Size"}}
 	for _, entry := range entries {
+		if !t.guard.visible(policy, path.Join(normalizePolicyPath(args.Path), entry.Name())) {
+			continue
+		}
 		info, err := entry.Info()
 		if err != nil {
 			continue
 		}
-		suffix := ""
+		suffix, kind := "", "file"
 		if entry.IsDir() {
-			suffix = "/"
+			suffix, kind = "/", "dir"
 		}
 		lines = append(lines, fmt.Sprintf("%s%s (%d bytes)", entry.Name(), suffix, info.Size()))
+		table.Rows = append(table.Rows, []string{entry.Name() + suffix, kind, strconv.FormatInt(info.Size(), 10)})
 	}
 
-	return strings.Join(lines, "\n"), nil
+	summary := strings.Join(lines, "\n")
+	if len(table.Rows) == 0 {
+		return tools.StructuredResult{Summary: summary}, nil
+	}
+	return tools.NewStructuredResult(summary, tools.ResultKindTable, table)
 }
 
 func resolveScratchPath(root, workspaceID, relPath string) (string, error) {
@@ -1,8 +1,11 @@
 package gateway
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"image"
+	imagepng "image/png"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,7 +16,7 @@ import (
 
 func TestWriteFileTool(t *testing.T) {
 	tempDir := t.TempDir()
-	tool := NewWriteFileTool(tempDir)
+	tool := NewWriteFileTool(nil, tempDir)
 
 	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{
 		WorkspaceID: "ws1",
@@ -90,7 +93,7 @@ func TestWriteFileTool(t *testing.T) {
 
 func TestReadFileTool(t *testing.T) {
 	tempDir := t.TempDir()
-	tool := NewReadFileTool(tempDir)
+	tool := NewReadFileTool(nil, tempDir)
 	
 	// Setup a file
 	wsDir := filepath.Join(tempDir, "ws1", "scratch")
@@ -137,7 +140,7 @@ func TestReadFileTool(t *testing.T) {
 
 func TestListFilesTool(t *testing.T) {
 	tempDir := t.TempDir()
-	tool := NewListFilesTool(tempDir)
+	tool := NewListFilesTool(nil, tempDir)
 
 	wsDir := filepath.Join(tempDir, "ws1", "scratch")
 	os.MkdirAll(wsDir, 0o755)
@@ -162,3 +165,47 @@ func TestListFilesTool(t *testing.T) {
 		}
 	})
 }
+
+func TestReadFileToolPagesLargeFilesAndSummarizesBinaries(t *testing.T) {
+	tempDir := t.TempDir()
+	tool := NewReadFileTool(nil, tempDir)
+	wsDir := filepath.Join(tempDir, "ws1", "scratch")
+	_ = os.MkdirAll(wsDir, 0o755)
+	_ = os.WriteFile(filepath.Join(wsDir, "big.txt"), []byte(strings.Repeat("abcdefghij", 10)), 0o644)
+
+	var png bytes.Buffer
+	if err := imagepng.Encode(&png, image.NewRGBA(image.Rect(0, 0, 3, 2))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	_ = os.WriteFile(filepath.Join(wsDir, "pixel.png"), png.Bytes(), 0o644)
+
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"path": "big.txt", "limit": 30}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(res, "abcdefghijabcdefghijabcdefghij\n") || !strings.Contains(res, "[bytes 0-30 of 100; continue with offset=30]") {
+		t.Errorf("unexpected first page: %q", res)
+	}
+	res, err = tool.Execute(ctx, json.RawMessage(`{"path": "big.txt", "offset": 90, "limit": 30}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(res, "abcdefghij\n") || !strings.Contains(res, "[bytes 90-100 of 100]") {
+		t.Errorf("unexpected last page: %q", res)
+	}
+	if _, err := tool.Execute(ctx, json.RawMessage(`{"path": "big.txt", "offset": 500}`)); err == nil {
+		t.Error("expected error for offset beyond end of file")
+	}
+
+	res, err = tool.Execute(ctx, json.RawMessage(`{"path": "pixel.png"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Binary file: pixel.png", "image/png", "image: png 3x2", "00000000  89 50 4e 47"} {
+		if !strings.Contains(res, want) {
+			t.Errorf("expected %q in binary summary, got: %s", want, res)
+		}
+	}
+}
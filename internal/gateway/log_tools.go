@@ -0,0 +1,335 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	logAnalysisMaxFiles      = 200
+	logAnalysisMaxBytes      = 50 * 1024 * 1024
+	logAnalysisMaxLineBytes  = 1024 * 1024
+	logAnalysisDefaultTop    = 10
+	logAnalysisMaxTop        = 25
+	logAnalysisMaxMatchLines = 10
+	logAnalysisSampleLen     = 240
+)
+
+var (
+	logKeyedLevelPattern = regexp.MustCompile(`(?i)(?:\blevel[=:]\s*"?|"level"\s*:\s*")(fatal|panic|critical|error|err|warning|warn|info|debug|trace)\b`)
+	logLevelPattern      = regexp.MustCompile(`\b(FATAL|PANIC|CRITICAL|ERROR|ERR|WARNING|WARN|INFO|DEBUG|TRACE)\b`)
+	logTimestampPattern  = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`)
+	logUUIDPattern       = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	logHexPattern        = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{12,}\b`)
+	logQuotedPattern     = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	logNumberPattern     = regexp.MustCompile(`\d+`)
+)
+
+// AnalyzeLogsTool summarizes log files in the workspace scratchpad: level
+// counts, time range, and the most frequent error signatures.
+type AnalyzeLogsTool struct {
+	guard *filePolicyGuard
+}
+
+type analyzeLogsArgs struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"`
+	Top     int    `json:"top"`
+}
+
+type logSignature struct {
+	signature string
+	level     string
+	count     int
+	sample    string
+	firstSeen string
+	lastSeen  string
+}
+
+type logAnalysis struct {
+	files        int
+	lines        int
+	bytes        int64
+	truncated    bool
+	levels       map[string]int
+	firstSeen    string
+	lastSeen     string
+	signatures   map[string]*logSignature
+	pattern      *regexp.Regexp
+	matchCount   int
+	matchSamples []string
+}
+
+func NewAnalyzeLogsTool(store Store, workspaceRoot string) *AnalyzeLogsTool {
+	return &AnalyzeLogsTool{guard: newFilePolicyGuard(store, workspaceRoot)}
+}
+
+func (t *AnalyzeLogsTool) Name() string { return "analyze_logs" }
+
+func (t *AnalyzeLogsTool) ToolClass() tools.ToolClass { return tools.ToolClassGeneral }
+
+func (t *AnalyzeLogsTool) RequiresApproval() bool { return false }
+
+func (t *AnalyzeLogsTool) Description() string {
+	return "Summarize log files in the workspace scratchpad: level counts, time range, top error/warning signatures, and optional regex matches. Output is bounded."
+}
+
+func (t *AnalyzeLogsTool) ParametersSchema() string {
+	return `{"path": "string (relative file or directory)", "pattern": "string (optional regex to count and sample)", "top": "integer (optional, default 10, max 25)"}`
+}
+
+func (t *AnalyzeLogsTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args analyzeLogsArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Path) == "" {
+		return fmt.Errorf("path is required")
+	}
+	if strings.TrimSpace(args.Pattern) != "" {
+		if _, err := regexp.Compile(args.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if args.Top < 0 {
+		return fmt.Errorf("top must be >= 0")
+	}
+	return nil
+}
+
+func (t *AnalyzeLogsTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args analyzeLogsArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+	target, err := t.guard.resolve(ctx, t.Name(), record, args.Path, fileAccessRead)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("path not found: %s", args.Path)
+		}
+		return "", fmt.Errorf("stat path: %w", err)
+	}
+
+	analysis := &logAnalysis{levels: map[string]int{}, signatures: map[string]*logSignature{}}
+	if strings.TrimSpace(args.Pattern) != "" {
+		analysis.pattern = regexp.MustCompile(args.Pattern)
+	}
+	if !info.IsDir() {
+		if err := analysis.scanFile(ctx, target); err != nil {
+			return "", err
+		}
+	} else {
+		policy, err := t.guard.policyFor(record)
+		if err != nil {
+			return "", err
+		}
+		base := normalizePolicyPath(args.Path)
+		walkErr := filepath.WalkDir(target, func(current string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.Type().IsRegular() {
+				return nil
+			}
+			rel, relErr := filepath.Rel(target, current)
+			if relErr != nil || !t.guard.visible(policy, filepath.ToSlash(filepath.Join(base, rel))) {
+				return nil
+			}
+			if analysis.files >= logAnalysisMaxFiles || analysis.bytes >= logAnalysisMaxBytes {
+				analysis.truncated = true
+				return filepath.SkipAll
+			}
+			return analysis.scanFile(ctx, current)
+		})
+		if walkErr != nil {
+			return "", walkErr
+		}
+	}
+	top := args.Top
+	if top == 0 {
+		top = logAnalysisDefaultTop
+	}
+	return analysis.format(args.Path, min(top, logAnalysisMaxTop)), nil
+}
+
+func (a *logAnalysis) scanFile(ctx context.Context, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	defer file.Close()
+	sniff := make([]byte, fileSniffBytes)
+	read, _ := file.Read(sniff)
+	if looksBinary(sniff[:read]) {
+		return nil
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("read log file: %w", err)
+	}
+	a.files++
+	remaining := int64(logAnalysisMaxBytes) - a.bytes
+	scanner := bufio.NewScanner(io.LimitReader(file, remaining))
+	scanner.Buffer(make([]byte, 64*1024), logAnalysisMaxLineBytes)
+	for scanner.Scan() {
+		if a.lines%1000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		line := scanner.Text()
+		a.bytes += int64(len(line)) + 1
+		a.lines++
+		a.observe(line)
+	}
+	if a.bytes >= logAnalysisMaxBytes {
+		a.truncated = true
+	}
+	// Oversized lines stop the scan of this file but not the whole analysis.
+	if err := scanner.Err(); err != nil {
+		a.truncated = true
+	}
+	return nil
+}
+
+func (a *logAnalysis) observe(line string) {
+	timestamp := logTimestampPattern.FindString(line)
+	if timestamp != "" {
+		if a.firstSeen == "" || timestamp < a.firstSeen {
+			a.firstSeen = timestamp
+		}
+		if timestamp > a.lastSeen {
+			a.lastSeen = timestamp
+		}
+	}
+	if a.pattern != nil && a.pattern.MatchString(line) {
+		a.matchCount++
+		if len(a.matchSamples) < logAnalysisMaxMatchLines {
+			a.matchSamples = append(a.matchSamples, truncateToolLogField(line, logAnalysisSampleLen))
+		}
+	}
+	level := ""
+	match := logKeyedLevelPattern.FindStringSubmatch(line)
+	if match == nil {
+		match = logLevelPattern.FindStringSubmatch(line)
+	}
+	if len(match) == 2 {
+		level = normalizeLogLevel(match[1])
+		a.levels[level]++
+	}
+	if level != "error" && level != "fatal" && level != "warn" {
+		return
+	}
+	signature := level + " " + logSignatureOf(line, timestamp)
+	entry, exists := a.signatures[signature]
+	if !exists {
+		entry = &logSignature{signature: signature, level: level, sample: truncateToolLogField(line, logAnalysisSampleLen), firstSeen: timestamp}
+		a.signatures[signature] = entry
+	}
+	entry.count++
+	if timestamp != "" {
+		if entry.firstSeen == "" {
+			entry.firstSeen = timestamp
+		}
+		entry.lastSeen = timestamp
+	}
+}
+
+func (a *logAnalysis) format(displayPath string, top int) string {
+	lines := []string{fmt.Sprintf("Log analysis for %s: %d files, %d lines, %d bytes", displayPath, a.files, a.lines, a.bytes)}
+	if a.truncated {
+		lines = append(lines, fmt.Sprintf("Note: scan stopped at limits (%d files, %d bytes, or an oversized line).", logAnalysisMaxFiles, logAnalysisMaxBytes))
+	}
+	if a.firstSeen != "" {
+		lines = append(lines, fmt.Sprintf("Time range: %s -> %s", a.firstSeen, a.lastSeen))
+	}
+	if len(a.levels) > 0 {
+		parts := []string{}
+		for _, level := range []string{"fatal", "error", "warn", "info", "debug", "trace"} {
+			if count := a.levels[level]; count > 0 {
+				parts = append(parts, fmt.Sprintf("%s=%d", level, count))
+			}
+		}
+		lines = append(lines, "Levels: "+strings.Join(parts, " "))
+	}
+	if a.pattern != nil {
+		lines = append(lines, fmt.Sprintf("Pattern `%s`: %d matching lines", a.pattern.String(), a.matchCount))
+		for _, sample := range a.matchSamples {
+			lines = append(lines, "  "+sample)
+		}
+	}
+	if len(a.signatures) == 0 {
+		lines = append(lines, "No error or warning lines found.")
+		return strings.Join(lines, "\n")
+	}
+	ranked := make([]*logSignature, 0, len(a.signatures))
+	for _, entry := range a.signatures {
+		ranked = append(ranked, entry)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].count != ranked[j].count {
+			return ranked[i].count > ranked[j].count
+		}
+		return ranked[i].signature < ranked[j].signature
+	})
+	lines = append(lines, fmt.Sprintf("Top error/warning signatures (%d distinct):", len(ranked)))
+	for index, entry := range ranked {
+		if index == top {
+			lines = append(lines, fmt.Sprintf("... %d more signatures", len(ranked)-top))
+			break
+		}
+		line := fmt.Sprintf("%d. [%s] x%d", index+1, entry.level, entry.count)
+		if entry.firstSeen != "" {
+			line += fmt.Sprintf(" (%s -> %s)", entry.firstSeen, entry.lastSeen)
+		}
+		lines = append(lines, line, "   "+entry.sample)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func normalizeLogLevel(raw string) string {
+	switch strings.ToUpper(raw) {
+	case "FATAL", "PANIC", "CRITICAL":
+		return "fatal"
+	case "ERROR", "ERR":
+		return "error"
+	case "WARN", "WARNING":
+		return "warn"
+	case "DEBUG":
+		return "debug"
+	case "TRACE":
+		return "trace"
+	default:
+		return "info"
+	}
+}
+
+// logSignatureOf collapses variable parts of a line so repeats group together.
+func logSignatureOf(line, timestamp string) string {
+	value := line
+	if timestamp != "" {
+		value = strings.Replace(value, timestamp, "", 1)
+	}
+	value = logUUIDPattern.ReplaceAllString(value, "<id>")
+	value = logHexPattern.ReplaceAllString(value, "<hex>")
+	value = logQuotedPattern.ReplaceAllString(value, "<str>")
+	value = logNumberPattern.ReplaceAllString(value, "<n>")
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestAnalyzeLogsToolSummarizesErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	logDir := filepath.Join(tempDir, "ws1", "scratch", "bundle")
+	_ = os.MkdirAll(logDir, 0o755)
+	server := strings.Join([]string{
+		"2026-01-02T10:00:00Z INFO server started on port 8080",
+		"2026-01-02T10:00:05Z ERROR request 4411 failed: timeout after 30s",
+		"2026-01-02T10:00:09Z ERROR request 4412 failed: timeout after 30s",
+		"2026-01-02T10:01:00Z WARN cache miss ratio 0.42",
+		"2026-01-02T10:02:00Z INFO connection error count reset",
+	}, "\n")
+	worker := strings.Join([]string{
+		`{"time":"2026-01-02T09:59:00Z","level":"error","msg":"job 17 crashed"}`,
+		`{"time":"2026-01-02T10:05:00Z","level":"info","msg":"job 18 ok"}`,
+	}, "\n")
+	_ = os.WriteFile(filepath.Join(logDir, "server.log"), []byte(server), 0o644)
+	_ = os.WriteFile(filepath.Join(logDir, "worker.jsonl"), []byte(worker), 0o644)
+	_ = os.WriteFile(filepath.Join(logDir, "core.bin"), []byte{0x00, 0x01, 0x02}, 0o644)
+
+	tool := NewAnalyzeLogsTool(nil, tempDir)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+	res, err := tool.Execute(ctx, json.RawMessage(`{"path": "bundle", "pattern": "timeout"}`))
+	if err != nil {
+		t.Fatalf("analyze logs: %v", err)
+	}
+	for _, want := range []string{
+		"2 files, 7 lines",
+		"Time range: 2026-01-02T09:59:00Z -> 2026-01-02T10:05:00Z",
+		"Levels: error=3 warn=1 info=3",
+		"Pattern `timeout`: 2 matching lines",
+		"1. [error] x2 (2026-01-02T10:00:05Z -> 2026-01-02T10:00:09Z)",
+	} {
+		if !strings.Contains(res, want) {
+			t.Errorf("expected %q in output:\n%s", want, res)
+		}
+	}
+
+	if err := tool.ValidateArgs(json.RawMessage(`{"path": "bundle", "pattern": "("}`)); err == nil {
+		t.Error("expected invalid regex to be rejected")
+	}
+	if _, err := tool.Execute(ctx, json.RawMessage(`{"path": "../other"}`)); err == nil {
+		t.Error("expected traversal to be rejected")
+	}
+}
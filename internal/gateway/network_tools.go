@@ -12,9 +12,14 @@ import (
 )
 
 // CurlTool implements a tool for immediate curl execution (requires sensitive approval in context).
+// networkToolRetries is how often read-only network tools retry transient
+// failures. curl is not retried: it can send non-idempotent requests.
+const networkToolRetries = 2
+
 type CurlTool struct {
 	store          Store
 	actionExecutor ActionExecutor
+	egress         egressChecker
 }
 
 func NewCurlTool(store Store, executor ActionExecutor) *CurlTool {
@@ -27,6 +32,9 @@ func (t *CurlTool) ToolClass() tools.ToolClass {
 }
 func (t *CurlTool) RequiresApproval() bool { return true }
 
+// UntrustedOutput marks remote responses as data the agent takes no instructions from.
+func (t *CurlTool) UntrustedOutput() bool { return true }
+
 func (t *CurlTool) Description() string {
 	return "Execute a curl command to fetch data from the web. Only available in autonomous mode."
 }
@@ -64,6 +72,9 @@ func (t *CurlTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string
 	if !ok {
 		return "", fmt.Errorf("internal error: message input missing from context")
 	}
+	if err := t.egress.checkCurl(ctx, t.Name(), record, input, args.Args); err != nil {
+		return "", err
+	}
 
 	// 1. Create the approval record
 	approval, err := t.store.CreateActionApproval(ctx, store.CreateActionApprovalInput{
@@ -90,6 +101,9 @@ func (t *CurlTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string
 		ApproverUserID: "system:agent",
 	})
 	if err != nil {
+		if reply, ok := quorumPendingToolReply(approved, err); ok {
+			return reply, nil
+		}
 		return "", fmt.Errorf("auto-approve failed: %w", err)
 	}
 
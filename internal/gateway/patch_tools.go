@@ -0,0 +1,353 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/textdiff"
+)
+
+const (
+	patchMaxFileBytes   = 2 * 1024 * 1024
+	patchMaxFiles       = 20
+	patchMaxOutputBytes = 64 * 1024
+)
+
+// DiffFilesTool renders a unified diff between two scratchpad files, or
+// between a file and proposed content.
+type DiffFilesTool struct {
+	guard *filePolicyGuard
+}
+
+type diffFilesArgs struct {
+	Path      string  `json:"path"`
+	OtherPath string  `json:"other_path"`
+	Content   *string `json:"content"`
+	Context   *int    `json:"context"`
+}
+
+func NewDiffFilesTool(store Store, workspaceRoot string) *DiffFilesTool {
+	return &DiffFilesTool{guard: newFilePolicyGuard(store, workspaceRoot)}
+}
+
+func (t *DiffFilesTool) Name() string { return "diff_files" }
+
+func (t *DiffFilesTool) ToolClass() tools.ToolClass { return tools.ToolClassGeneral }
+
+func (t *DiffFilesTool) RequiresApproval() bool { return false }
+
+func (t *DiffFilesTool) Description() string {
+	return "Show a unified diff between two workspace scratchpad files, or between a file and proposed new content."
+}
+
+func (t *DiffFilesTool) ParametersSchema() string {
+	return `{"path": "string (relative file; missing files diff as empty)", "other_path": "string (relative file to compare against)", "content": "string (proposed new content; alternative to other_path)", "context": "integer (optional context lines, default 3)"}`
+}
+
+func (t *DiffFilesTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args diffFilesArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Path) == "" {
+		return fmt.Errorf("path is required")
+	}
+	if (strings.TrimSpace(args.OtherPath) != "") == (args.Content != nil) {
+		return fmt.Errorf("exactly one of other_path or content is required")
+	}
+	if args.Context != nil && (*args.Context < 0 || *args.Context > 20) {
+		return fmt.Errorf("context must be between 0 and 20")
+	}
+	return nil
+}
+
+func (t *DiffFilesTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args diffFilesArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+	oldText, err := t.readSide(ctx, record, args.Path)
+	if err != nil {
+		return "", err
+	}
+	newName := "b/" + normalizePolicyPath(args.Path)
+	var newText string
+	if args.Content != nil {
+		newText = *args.Content
+	} else {
+		newName = "b/" + normalizePolicyPath(args.OtherPath)
+		newText, err = t.readSide(ctx, record, args.OtherPath)
+		if err != nil {
+			return "", err
+		}
+	}
+	contextLines := textdiff.DefaultContextLines
+	if args.Context != nil {
+		contextLines = *args.Context
+	}
+	diff := textdiff.Unified("a/"+normalizePolicyPath(args.Path), newName, oldText, newText, contextLines)
+	if diff == "" {
+		return "No differences.", nil
+	}
+	if len(diff) > patchMaxOutputBytes {
+		cut := strings.LastIndex(diff[:patchMaxOutputBytes], "\n")
+		diff = diff[:cut+1] + fmt.Sprintf("[diff truncated at %d bytes]", patchMaxOutputBytes)
+	}
+	return diff, nil
+}
+
+func (t *DiffFilesTool) readSide(ctx context.Context, record store.ContextRecord, relPath string) (string, error) {
+	fullPath, err := t.guard.resolve(ctx, t.Name(), record, relPath, fileAccessRead)
+	if err != nil {
+		return "", err
+	}
+	content, exists, err := readPatchTarget(fullPath, relPath)
+	if err != nil || !exists {
+		return "", err
+	}
+	return content, nil
+}
+
+// ApplyPatchTool applies a unified diff to scratchpad files. The patch is
+// validated against every file before anything is written, so it applies
+// completely or not at all.
+type ApplyPatchTool struct {
+	guard   *filePolicyGuard
+	secrets secretScanner
+}
+
+type applyPatchArgs struct {
+	Patch  string `json:"patch"`
+	DryRun bool   `json:"dry_run"`
+}
+
+type patchChange struct {
+	relPath  string
+	fullPath string
+	content  string
+	remove   bool
+	summary  string
+}
+
+func NewApplyPatchTool(store Store, workspaceRoot string) *ApplyPatchTool {
+	return &ApplyPatchTool{guard: newFilePolicyGuard(store, workspaceRoot), secrets: secretScanner{store: store}}
+}
+
+func (t *ApplyPatchTool) Name() string { return "apply_patch" }
+
+func (t *ApplyPatchTool) ToolClass() tools.ToolClass { return tools.ToolClassGeneral }
+
+// RequiresApproval is false; protected paths request approval per call.
+func (t *ApplyPatchTool) RequiresApproval() bool { return false }
+
+func (t *ApplyPatchTool) Description() string {
+	return "Apply a unified diff (--- a/path, +++ b/path, @@ hunks) to workspace scratchpad files. All hunks must match or nothing is written; use dry_run to validate first."
+}
+
+func (t *ApplyPatchTool) ParametersSchema() string {
+	return `{"patch": "string (unified diff; /dev/null creates or deletes files)", "dry_run": "boolean (optional; validate without writing)"}`
+}
+
+func (t *ApplyPatchTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args applyPatchArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Patch) == "" {
+		return fmt.Errorf("patch is required")
+	}
+	patches, err := textdiff.Parse(args.Patch)
+	if err != nil {
+		return err
+	}
+	if len(patches) > patchMaxFiles {
+		return fmt.Errorf("patch touches more than %d files", patchMaxFiles)
+	}
+	for _, patch := range patches {
+		if patch.OldPath != "" && patch.NewPath != "" && normalizePolicyPath(patch.OldPath) != normalizePolicyPath(patch.NewPath) {
+			return fmt.Errorf("renames are not supported: %s -> %s", patch.OldPath, patch.NewPath)
+		}
+	}
+	return nil
+}
+
+func (t *ApplyPatchTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args applyPatchArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+	patches, _ := textdiff.Parse(args.Patch)
+
+	changes := make([]patchChange, 0, len(patches))
+	seen := map[string]struct{}{}
+	for _, patch := range patches {
+		relPath := normalizePolicyPath(patch.Path())
+		if _, duplicate := seen[relPath]; duplicate {
+			return "", fmt.Errorf("patch touches %s more than once", relPath)
+		}
+		seen[relPath] = struct{}{}
+		change, err := t.prepare(ctx, record, relPath, patch)
+		if err != nil {
+			return "", err
+		}
+		if !change.remove {
+			content, err := t.secrets.scan(ctx, t.Name(), record, relPath, change.content)
+			if err != nil {
+				return "", err
+			}
+			change.summary += maskedNote(change.content, content)
+			change.content = content
+		}
+		changes = append(changes, change)
+	}
+
+	lines := make([]string, 0, len(changes)+1)
+	for _, change := range changes {
+		lines = append(lines, "- "+change.summary)
+	}
+	if args.DryRun {
+		return "Patch applies cleanly (dry run):\n" + strings.Join(lines, "\n"), nil
+	}
+	for _, change := range changes {
+		if err := commitPatchChange(change); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("Applied patch to %d file(s):\n%s", len(changes), strings.Join(lines, "\n")), nil
+}
+
+func (t *ApplyPatchTool) prepare(ctx context.Context, record store.ContextRecord, relPath string, patch textdiff.FilePatch) (patchChange, error) {
+	fullPath, err := t.guard.resolve(ctx, t.Name(), record, relPath, fileAccessWrite)
+	if err != nil {
+		return patchChange{}, err
+	}
+	original, exists, err := readPatchTarget(fullPath, relPath)
+	if err != nil {
+		return patchChange{}, err
+	}
+	switch {
+	case patch.IsCreate() && exists:
+		return patchChange{}, fmt.Errorf("cannot create %s: file already exists", relPath)
+	case !patch.IsCreate() && !exists:
+		return patchChange{}, fmt.Errorf("file not found: %s", relPath)
+	}
+	updated, err := textdiff.Apply(original, patch)
+	if err != nil {
+		return patchChange{}, err
+	}
+	change := patchChange{relPath: relPath, fullPath: fullPath, content: updated}
+	switch {
+	case patch.IsDelete():
+		if strings.TrimSpace(updated) != "" {
+			return patchChange{}, fmt.Errorf("cannot delete %s: patch does not remove all content", relPath)
+		}
+		change.remove = true
+		change.summary = relPath + " (deleted)"
+	case patch.IsCreate():
+		change.summary = fmt.Sprintf("%s (created, %d bytes)", relPath, len(updated))
+	default:
+		added, removed := patchLineCounts(patch)
+		change.summary = fmt.Sprintf("%s (+%d -%d)", relPath, added, removed)
+	}
+	if len(updated) > patchMaxFileBytes {
+		return patchChange{}, fmt.Errorf("patched %s would exceed %d bytes", relPath, patchMaxFileBytes)
+	}
+	return change, nil
+}
+
+func readPatchTarget(fullPath, relPath string) (string, bool, error) {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("read file: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", false, fmt.Errorf("read file: %w", err)
+	}
+	if info.IsDir() {
+		return "", false, fmt.Errorf("path is a directory: %s", relPath)
+	}
+	if info.Size() > patchMaxFileBytes {
+		return "", false, fmt.Errorf("file is larger than %d bytes: %s", patchMaxFileBytes, relPath)
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", false, fmt.Errorf("read file: %w", err)
+	}
+	if looksBinary(content[:min(len(content), fileSniffBytes)]) {
+		return "", false, fmt.Errorf("cannot diff binary file: %s", relPath)
+	}
+	return string(content), true, nil
+}
+
+func commitPatchChange(change patchChange) error {
+	if change.remove {
+		if err := os.Remove(change.fullPath); err != nil {
+			return fmt.Errorf("delete %s: %w", change.relPath, err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(change.fullPath), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	// Write through a temp file so a failed write never leaves half a file.
+	tmp, err := os.CreateTemp(filepath.Dir(change.fullPath), ".patch-*")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", change.relPath, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", change.relPath, err)
+	}
+	if _, err := tmp.WriteString(change.content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", change.relPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", change.relPath, err)
+	}
+	if err := os.Rename(tmp.Name(), change.fullPath); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", change.relPath, err)
+	}
+	return nil
+}
+
+func patchLineCounts(patch textdiff.FilePatch) (int, int) {
+	added, removed := 0, 0
+	for _, hunk := range patch.Hunks {
+		for _, line := range hunk.Lines {
+			switch line[0] {
+			case '+':
+				added++
+			case '-':
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
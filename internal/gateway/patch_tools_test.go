@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/agenterr"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestDiffAndApplyPatchRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	scratch := filepath.Join(tempDir, "ws1", "scratch")
+	_ = os.MkdirAll(filepath.Join(scratch, "docs"), 0o755)
+	_ = os.WriteFile(filepath.Join(scratch, "docs", "guide.md"), []byte("# Guide\n\nStep one.\nStep two.\n"), 0o644)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+
+	diffTool := NewDiffFilesTool(nil, tempDir)
+	args, _ := json.Marshal(map[string]any{"path": "docs/guide.md", "content": "# Guide\n\nStep one.\nStep 2.\nStep three.\n"})
+	diff, err := diffTool.Execute(ctx, args)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if !strings.Contains(diff, "--- a/docs/guide.md\n+++ b/docs/guide.md\n") || !strings.Contains(diff, "-Step two.\n+Step 2.\n+Step three.\n") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+
+	applyTool := NewApplyPatchTool(nil, tempDir)
+	patchArgs, _ := json.Marshal(map[string]any{"patch": diff, "dry_run": true})
+	res, err := applyTool.Execute(ctx, patchArgs)
+	if err != nil || !strings.Contains(res, "dry run") {
+		t.Fatalf("dry run: %q %v", res, err)
+	}
+	content, _ := os.ReadFile(filepath.Join(scratch, "docs", "guide.md"))
+	if strings.Contains(string(content), "Step three") {
+		t.Fatal("dry run must not write")
+	}
+
+	patchArgs, _ = json.Marshal(map[string]any{"patch": diff})
+	res, err = applyTool.Execute(ctx, patchArgs)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !strings.Contains(res, "docs/guide.md (+2 -1)") {
+		t.Fatalf("unexpected apply output %q", res)
+	}
+	content, _ = os.ReadFile(filepath.Join(scratch, "docs", "guide.md"))
+	if string(content) != "# Guide\n\nStep one.\nStep 2.\nStep three.\n" {
+		t.Fatalf("unexpected patched content %q", content)
+	}
+
+	// Re-applying the same patch no longer matches.
+	if _, err := applyTool.Execute(ctx, patchArgs); err == nil {
+		t.Fatal("expected stale patch to be rejected")
+	}
+}
+
+func TestApplyPatchIsAllOrNothing(t *testing.T) {
+	tempDir := t.TempDir()
+	scratch := filepath.Join(tempDir, "ws1", "scratch")
+	_ = os.MkdirAll(scratch, 0o755)
+	_ = os.WriteFile(filepath.Join(scratch, "a.txt"), []byte("alpha\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(scratch, "b.txt"), []byte("beta\n"), 0o644)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+
+	patch := strings.Join([]string{
+		"--- a/a.txt", "+++ b/a.txt", "@@ -1 +1 @@", "-alpha", "+ALPHA",
+		"--- a/b.txt", "+++ b/b.txt", "@@ -1 +1 @@", "-gamma", "+GAMMA",
+		"",
+	}, "\n")
+	args, _ := json.Marshal(map[string]any{"patch": patch})
+	if _, err := NewApplyPatchTool(nil, tempDir).Execute(ctx, args); err == nil {
+		t.Fatal("expected mismatched hunk to fail")
+	}
+	content, _ := os.ReadFile(filepath.Join(scratch, "a.txt"))
+	if string(content) != "alpha\n" {
+		t.Fatalf("expected a.txt untouched, got %q", content)
+	}
+}
+
+func TestApplyPatchProtectedPathNeedsApproval(t *testing.T) {
+	tempDir := t.TempDir()
+	policyPath := filepath.Join(tempDir, "ws1", "context", "file_policy.json")
+	_ = os.MkdirAll(filepath.Dir(policyPath), 0o755)
+	_ = os.WriteFile(policyPath, []byte(`{"protected": ["skills/"]}`), 0o644)
+	scratch := filepath.Join(tempDir, "ws1", "scratch", "skills")
+	_ = os.MkdirAll(scratch, 0o755)
+	_ = os.WriteFile(filepath.Join(scratch, "triage.md"), []byte("old rule\n"), 0o644)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+
+	patch := "--- a/skills/triage.md\n+++ b/skills/triage.md\n@@ -1 +1 @@\n-old rule\n+new rule\n"
+	args, _ := json.Marshal(map[string]any{"patch": patch})
+	tool := NewApplyPatchTool(nil, tempDir)
+	if _, err := tool.Execute(ctx, args); !errors.Is(err, agenterr.ErrApprovalRequired) {
+		t.Fatalf("expected approval required, got %v", err)
+	}
+	if _, err := tool.Execute(agent.WithSensitiveToolApproval(ctx), args); err != nil {
+		t.Fatalf("apply with approval: %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(scratch, "triage.md"))
+	if string(content) != "new rule\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+}
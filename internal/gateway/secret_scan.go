@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/secretscan"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// SetSecretScanMode decides whether credentials in tool output are masked or
+// withheld, and whether write_file, apply_patch, render_template and
+// learn_skill mask or refuse content that contains them.
+func (s *Service) SetSecretScanMode(mode secretscan.Mode) {
+	s.secretScanMode = mode
+	s.applyAgentConfig()
+}
+
+// secretScanner is shared by the tools that write workspace files. Tools
+// built outside New have no mode and mask.
+type secretScanner struct {
+	store Store
+	mode  func() secretscan.Mode
+}
+
+// scan returns the content to write, with credentials masked, or an error
+// when the mode refuses it. Every finding is audited.
+func (s secretScanner) scan(ctx context.Context, toolName string, record store.ContextRecord, relPath, content string) (string, error) {
+	mode := secretscan.ModeMask
+	if s.mode != nil && s.mode() != "" {
+		mode = s.mode()
+	}
+	if mode == secretscan.ModeOff {
+		return content, nil
+	}
+	masked, kinds := secretscan.Mask(content)
+	if len(kinds) == 0 {
+		return content, nil
+	}
+	s.audit(ctx, toolName, record, relPath, kinds, mode)
+	if mode == secretscan.ModeBlock {
+		return "", fmt.Errorf("refusing to write %s: content contains credentials (%s); remove them and write again", relPath, strings.Join(kinds, ", "))
+	}
+	return masked, nil
+}
+
+func (s secretScanner) audit(ctx context.Context, toolName string, record store.ContextRecord, relPath string, kinds []string, mode secretscan.Mode) {
+	input, _ := ctx.Value(ContextKeyInput).(MessageInput)
+	if s.store == nil || strings.TrimSpace(record.ID) == "" || strings.TrimSpace(input.Connector) == "" || strings.TrimSpace(input.ExternalID) == "" {
+		return
+	}
+	action := "masked"
+	if mode == secretscan.ModeBlock {
+		action = "blocked"
+	}
+	_, _ = s.store.CreateAgentAuditEvent(ctx, store.CreateAgentAuditEventInput{
+		WorkspaceID:  record.WorkspaceID,
+		ContextID:    record.ID,
+		Connector:    input.Connector,
+		ExternalID:   input.ExternalID,
+		SourceUserID: input.FromUserID,
+		EventType:    "secret_detected",
+		Stage:        "audit.secret_detected",
+		ToolName:     toolName,
+		Blocked:      mode == secretscan.ModeBlock,
+		BlockReason:  "content contains credentials",
+		Message:      fmt.Sprintf("path=%s kinds=%s action=%s", relPath, strings.Join(kinds, ","), action),
+	})
+}
+
+// maskedNote tells the agent its content changed on the way to disk.
+func maskedNote(original, written string) string {
+	if original == written {
+		return ""
+	}
+	return " (credentials masked)"
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,9 +13,14 @@ import (
 	"github.com/dwizi/agent-runtime/internal/actions/executor"
 	"github.com/dwizi/agent-runtime/internal/agent"
 	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
+	"github.com/dwizi/agent-runtime/internal/canary"
+	"github.com/dwizi/agent-runtime/internal/i18n"
 	"github.com/dwizi/agent-runtime/internal/llm"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
 	"github.com/dwizi/agent-runtime/internal/qmd"
+	"github.com/dwizi/agent-runtime/internal/reply"
+	"github.com/dwizi/agent-runtime/internal/secretscan"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -22,8 +28,12 @@ type Store interface {
 	EnsureContextForExternalChannel(ctx context.Context, connector, externalID, displayName string) (store.ContextRecord, error)
 	SetContextAdminByExternal(ctx context.Context, connector, externalID string, enabled bool) (store.ContextRecord, error)
 	LookupContextPolicyByExternal(ctx context.Context, connector, externalID string) (store.ContextPolicy, error)
-	SetContextSystemPromptByExternal(ctx context.Context, connector, externalID, prompt string) (store.ContextPolicy, error)
+	SetContextSystemPromptByExternal(ctx context.Context, connector, externalID, prompt string, expectedRevision int) (store.ContextPolicy, error)
+	SetContextVoiceRepliesByExternal(ctx context.Context, connector, externalID string, enabled bool) (store.ContextPolicy, error)
+	SetContextSharedKnowledgeByExternal(ctx context.Context, connector, externalID string, enabled bool) (store.ContextPolicy, error)
+	SetContextLanguageByExternal(ctx context.Context, connector, externalID, language string) (store.ContextPolicy, error)
 	LookupUserIdentity(ctx context.Context, connector, connectorUserID string) (store.UserIdentity, error)
+	RolePermissions(ctx context.Context, workspaceID, role string) ([]store.Permission, error)
 	CreateTask(ctx context.Context, input store.CreateTaskInput) error
 	LookupTask(ctx context.Context, id string) (store.TaskRecord, error)
 	MarkTaskCompleted(ctx context.Context, id string, finishedAt time.Time, summary, resultPath string) error
@@ -35,6 +45,7 @@ type Store interface {
 	ListPendingActionApprovalsGlobal(ctx context.Context, limit int) ([]store.ActionApproval, error)
 	ApproveActionApproval(ctx context.Context, input store.ApproveActionApprovalInput) (store.ActionApproval, error)
 	DenyActionApproval(ctx context.Context, input store.DenyActionApprovalInput) (store.ActionApproval, error)
+	LookupActionApproval(ctx context.Context, id string) (store.ActionApproval, error)
 	UpdateActionExecution(ctx context.Context, input store.UpdateActionExecutionInput) (store.ActionApproval, error)
 	CreateObjective(ctx context.Context, input store.CreateObjectiveInput) (store.Objective, error)
 	UpdateObjective(ctx context.Context, input store.UpdateObjectiveInput) (store.Objective, error)
@@ -59,6 +70,23 @@ type RoutingNotifier interface {
 	NotifyRoutingDecision(ctx context.Context, decision RouteDecision)
 }
 
+// TaskSyncer mirrors routed tasks to an external issue tracker.
+type TaskSyncer interface {
+	SyncTask(ctx context.Context, taskID string) error
+}
+
+// ObjectiveRunner queues an immediate, out-of-schedule objective run.
+type ObjectiveRunner interface {
+	RunNow(ctx context.Context, objectiveID string) (orchestrator.Task, error)
+}
+
+// CanaryRouter places agent turns on the canary or control side of active
+// rollouts and reports how each turn went.
+type CanaryRouter interface {
+	Assign(ctx context.Context, workspaceID, contextID string) canary.Assignment
+	Record(ctx context.Context, assignment canary.Assignment, errored, blocked bool)
+}
+
 type Service struct {
 	store                   Store
 	engine                  Engine
@@ -71,27 +99,75 @@ type Service struct {
 	agentMaxTurnDuration    time.Duration
 	agentGroundingFirstStep bool
 	agentGroundingEveryStep bool
+	agentPolicyResolver     agent.PolicyResolver
+	approvalPolicies        ApprovalPolicyResolver
+	egressGuard             EgressGuard
+	secretScanMode          secretscan.Mode
+	canaryRouter            CanaryRouter
+	degradation             Degradation
+	spamFilter              SpamFilter
+	rateLimiter             RateLimiter
+	moderationCases         ModerationStore
+	moderationNotify        ModerationNotifier
+	cases                   CaseStore
+	memberPolicies          MemberPolicyStore
+	auditReader             AuditReader
+	taskLister              TaskLister
+	taskCanceller           TaskCanceller
+	taskArtifacts           TaskArtifactLister
+	artifactLinks           *artifactlink.Signer
 	triageAcknowledger      llm.Responder
 	triageEnabled           bool
 	routingNotify           RoutingNotifier
+	taskSyncer              TaskSyncer
+	backpressure            Backpressure
+	objectiveRunner         ObjectiveRunner
+	calendarClient          CalendarClient
+	translator              Translator
+	messageMirror           MessageMirror
+	browserEnabled          bool
+	voiceRepliesAvailable   bool
+	dryRun                  bool
+	sharedWorkspace         string
 	approvalMu              sync.Mutex
-	sensitiveApprovals      map[string]time.Time
+	sensitiveGrants         map[string]sensitiveGrant
+	sensitiveGrantSeq       int
 	sensitiveApprovalTTL    time.Duration
+	listingMu               sync.Mutex
+	actionListings          map[string]actionListing
+	intentClassifier        *intentClassifier
 	logger                  *slog.Logger
 	mcpRuntime              MCPRuntime
+	githubClient            GitHubClient
 }
 
 type MessageInput struct {
-	Connector   string
-	ExternalID  string
+	Connector  string
+	ExternalID string
+	// ThreadID is the connector thread (Telegram topic, Discord thread) the
+	// message was sent in. Threads share the channel's context and policy
+	// but keep their own conversation memory.
+	ThreadID    string
 	DisplayName string
 	FromUserID  string
 	Text        string
+	// Images are passed to the agent's model calls for vision triage.
+	Images []llm.Image
+	// IsBot and AccountCreatedAt carry connector metadata about the sender
+	// for the spam filter; AccountCreatedAt is zero when unknown.
+	IsBot            bool
+	AccountCreatedAt time.Time
 }
 
 type MessageOutput struct {
 	Handled bool
 	Reply   string
+	// Suppressed means the message must not be answered at all, not even by
+	// a connector's fallback reply.
+	Suppressed bool
+	// Rich, when set, is Reply with buttons or attachments for connectors
+	// that render rich replies; Reply stays complete on its own.
+	Rich *reply.Message
 }
 
 const latestPendingActionAlias = "__latest_pending__"
@@ -110,37 +186,84 @@ func New(store Store, engine Engine, retriever Retriever, actionExecutor ActionE
 		workspaceRoot:           workspaceRoot,
 		agentGroundingFirstStep: true,
 		triageEnabled:           true,
-		sensitiveApprovals:      map[string]time.Time{},
+		sensitiveGrants:         map[string]sensitiveGrant{},
 		sensitiveApprovalTTL:    10 * time.Minute,
+		actionListings:          map[string]actionListing{},
 		logger:                  logger,
 	}
+	approver := autoApprover{store: store, policies: func() ApprovalPolicyResolver { return service.approvalPolicies }}
+	egressCheck := egressChecker{store: store, guard: func() EgressGuard { return service.egressGuard }}
 	registry := tools.NewRegistry()
 	registry.Register(NewSearchTool(retriever))
 	registry.Register(NewOpenKnowledgeDocumentTool(retriever))
-	registry.Register(NewCreateTaskTool(store, engine))
+	createTask := NewCreateTaskTool(store, engine)
+	createTask.backpressure = func() Backpressure { return service.backpressure }
+	createTask.allowed = service.taskCreationDenied
+	registry.Register(createTask)
 	registry.Register(NewModerationTriageTool())
 	registry.Register(NewDraftEscalationTool())
 	registry.Register(NewDraftFAQAnswerTool())
 	registry.Register(NewCreateObjectiveTool(store))
 	registry.Register(NewUpdateObjectiveTool(store))
-	registry.Register(NewUpdateTaskTool(store))
-	registry.Register(NewLearnSkillTool(workspaceRoot))
-	registry.Register(NewRunActionTool(store, actionExecutor))
-	registry.Register(NewWriteFileTool(workspaceRoot))
-	registry.Register(NewReadFileTool(workspaceRoot))
-	registry.Register(NewListFilesTool(workspaceRoot))
-	registry.Register(NewCurlTool(store, actionExecutor))
-	registry.Register(NewFetchUrlTool(store, actionExecutor))
-	registry.Register(NewInspectFileTool(store, actionExecutor, workspaceRoot))
+	registry.Register(NewUpdateTaskTool(store, func() TaskSyncer { return service.taskSyncer }))
+	secrets := secretScanner{store: store, mode: func() secretscan.Mode { return service.secretScanMode }}
+	learnSkill := NewLearnSkillTool(workspaceRoot)
+	learnSkill.secrets = secrets
+	learnSkill.dryRun = func() bool { return service.dryRun }
+	registry.Register(learnSkill)
+	runAction := NewRunActionTool(store, actionExecutor)
+	runAction.approver = approver
+	registry.Register(runAction)
+	writeFile := NewWriteFileTool(store, workspaceRoot)
+	writeFile.secrets = secrets
+	registry.Register(writeFile)
+	registry.Register(NewReadFileTool(store, workspaceRoot))
+	registry.Register(NewListFilesTool(store, workspaceRoot))
+	registry.Register(NewExtractArchiveTool(store, workspaceRoot))
+	registry.Register(NewAnalyzeLogsTool(store, workspaceRoot))
+	registry.Register(NewQueryDataTool(store, workspaceRoot))
+	registry.Register(NewDiffFilesTool(store, workspaceRoot))
+	applyPatch := NewApplyPatchTool(store, workspaceRoot)
+	applyPatch.secrets = secrets
+	registry.Register(applyPatch)
+	renderTemplate := NewRenderTemplateTool(store, workspaceRoot)
+	renderTemplate.secrets = secrets
+	registry.Register(renderTemplate)
+	curl := NewCurlTool(store, actionExecutor)
+	curl.egress = egressCheck
+	registry.Register(curl)
+	fetchURL := NewFetchUrlTool(store, actionExecutor)
+	fetchURL.approver = approver
+	fetchURL.egress = egressCheck
+	registry.Register(fetchURL)
+	browsePage := NewBrowsePageTool(store, actionExecutor, func() bool { return service.browserEnabled })
+	browsePage.approver = approver
+	browsePage.egress = egressCheck
+	registry.Register(browsePage)
+	inspectFile := NewInspectFileTool(store, actionExecutor, workspaceRoot)
+	inspectFile.approver = approver
+	registry.Register(inspectFile)
 	registry.Register(NewLookupTaskTool(store))
-	registry.Register(NewWebSearchTool(store, actionExecutor))
-	registry.Register(NewPythonCodeTool(store, actionExecutor, workspaceRoot))
+	webSearch := NewWebSearchTool(store, actionExecutor)
+	webSearch.approver = approver
+	webSearch.egress = egressCheck
+	registry.Register(webSearch)
+	pythonCode := NewPythonCodeTool(store, actionExecutor, workspaceRoot)
+	pythonCode.approver = approver
+	registry.Register(pythonCode)
 	registry.Register(NewMCPListServersTool(func() MCPRuntime { return service.mcpRuntime }))
 	registry.Register(NewMCPListResourcesTool(func() MCPRuntime { return service.mcpRuntime }))
 	registry.Register(NewMCPReadResourceTool(func() MCPRuntime { return service.mcpRuntime }))
 	registry.Register(NewMCPListResourceTemplatesTool(func() MCPRuntime { return service.mcpRuntime }))
 	registry.Register(NewMCPListPromptsTool(func() MCPRuntime { return service.mcpRuntime }))
 	registry.Register(NewMCPGetPromptTool(func() MCPRuntime { return service.mcpRuntime }))
+	registry.Register(NewListIssuesTool(func() GitHubClient { return service.githubClient }))
+	registry.Register(NewCreateIssueTool(func() GitHubClient { return service.githubClient }))
+	registry.Register(NewCommentOnPRTool(func() GitHubClient { return service.githubClient }))
+	registry.Register(NewGetCIStatusTool(func() GitHubClient { return service.githubClient }))
+	registry.Register(NewListEventsTool(func() CalendarClient { return service.calendarClient }))
+	registry.Register(NewCreateEventTool(store, func() CalendarClient { return service.calendarClient }))
+	registry.Register(NewTranslateTool(workspaceRoot, func() Translator { return service.translator }))
 	service.toolRegistry = registry
 	return service
 }
@@ -177,6 +300,20 @@ func (s *Service) SetAgentGroundingPolicy(firstStep, everyStep bool) {
 	s.applyAgentConfig()
 }
 
+// SetAgentPolicyResolver supplies per-message agent policy overrides, such
+// as the tool and limit settings of a workspace botfile.
+func (s *Service) SetAgentPolicyResolver(resolver agent.PolicyResolver) {
+	s.agentPolicyResolver = resolver
+	s.applyAgentConfig()
+}
+
+// SetCanaryRouter enables canary rollouts for agent turns. Denied canary
+// tools are only enforced when the policy resolver is wrapped with
+// canary.PolicyResolver.
+func (s *Service) SetCanaryRouter(router CanaryRouter) {
+	s.canaryRouter = router
+}
+
 func (s *Service) SetReasoningPromptTemplate(template string) {
 	s.reasoningPromptTemplate = template
 	if s.triageAcknowledger != nil {
@@ -201,17 +338,54 @@ func (s *Service) applyAgentConfig() {
 		s.agent.SetDefaultPolicy(agent.Policy{MaxTurnDuration: s.agentMaxTurnDuration})
 	}
 	s.agent.SetGroundingPolicy(s.agentGroundingFirstStep, s.agentGroundingEveryStep)
+	s.agent.SetSecretScanMode(s.secretScanMode)
+	if s.agentPolicyResolver != nil {
+		s.agent.SetPolicyResolver(s.agentPolicyResolver)
+	}
 }
 
 func (s *Service) SetRoutingNotifier(notifier RoutingNotifier) {
 	s.routingNotify = notifier
 }
 
+func (s *Service) SetTaskSyncer(syncer TaskSyncer) {
+	s.taskSyncer = syncer
+}
+
+func (s *Service) SetObjectiveRunner(runner ObjectiveRunner) {
+	s.objectiveRunner = runner
+}
+
+func (s *Service) syncTask(ctx context.Context, taskID string) {
+	syncTaskWith(ctx, s.taskSyncer, taskID, s.logger)
+}
+
+// syncTaskWith pushes a task change to the issue tracker. Failures are
+// logged and never fail the caller.
+func syncTaskWith(ctx context.Context, syncer TaskSyncer, taskID string, logger *slog.Logger) {
+	if syncer == nil || strings.TrimSpace(taskID) == "" {
+		return
+	}
+	if err := syncer.SyncTask(ctx, taskID); err != nil {
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Warn("task issue sync failed", "task_id", taskID, "error", err)
+	}
+}
+
 func (s *Service) HandleMessage(ctx context.Context, input MessageInput) (MessageOutput, error) {
 	text := strings.TrimSpace(input.Text)
 	if text == "" {
 		return MessageOutput{}, nil
 	}
+	ctx, err := s.scopeToChannel(ctx, input)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if output, throttled := s.throttle(ctx, input, text); throttled {
+		return output, nil
+	}
 
 	command, arg := splitCommand(text)
 	switch command {
@@ -231,6 +405,16 @@ func (s *Service) HandleMessage(ctx context.Context, input MessageInput) (Messag
 		return s.handleAdminChannel(ctx, input, arg)
 	case "prompt":
 		return s.handlePrompt(ctx, input, arg)
+	case "voice":
+		return s.handleVoice(ctx, input, arg)
+	case "shared-knowledge":
+		return s.handleSharedKnowledge(ctx, input, arg)
+	case "language":
+		return s.handleLanguage(ctx, input, arg)
+	case "members":
+		return s.handleMembers(ctx, input, arg)
+	case "silence":
+		return s.handleSilence(ctx, input, arg)
 	case "approve":
 		if actionArg, ok := parseApproveCommandAsActionArg(arg); ok {
 			return s.handleApproveAction(ctx, input, actionArg)
@@ -247,11 +431,31 @@ func (s *Service) HandleMessage(ctx context.Context, input MessageInput) (Messag
 		return s.handleApproveAction(ctx, input, arg)
 	case "deny-action":
 		return s.handleDenyAction(ctx, input, arg)
+	case "grants":
+		return s.handleGrants(ctx, input, arg)
+	case "audit":
+		return s.handleAudit(ctx, input, arg)
+	case "tasks":
+		return s.handleTasks(ctx, input, arg)
+	case "cancel-task":
+		return s.handleCancelTask(ctx, input, arg)
+	case "artifacts":
+		return s.handleArtifacts(ctx, input, arg)
+	case "explain":
+		return s.handleExplain(ctx, input, arg)
+	case "run-objective":
+		return s.handleRunObjective(ctx, input, arg)
+	case "appeal":
+		return s.handleAppeal(ctx, input, arg)
+	case "uphold-appeal":
+		return s.handleResolveAppeal(ctx, input, arg, true)
+	case "overturn-appeal":
+		return s.handleResolveAppeal(ctx, input, arg, false)
 	default:
 		if output, handled, err := s.handleCommandGuidance(ctx, input, text); handled || err != nil {
 			return output, err
 		}
-		if nlCommand, nlArg, ok := parseNaturalLanguageCommand(text); ok {
+		if nlCommand, nlArg, ok := s.parseCommandIntent(ctx, input, text); ok {
 			switch nlCommand {
 			case "task":
 				return s.handleTask(ctx, input, nlArg)
@@ -279,6 +483,13 @@ func (s *Service) HandleMessage(ctx context.Context, input MessageInput) (Messag
 				return s.handleDenyAction(ctx, input, nlArg)
 			}
 		}
+		if output, held := s.holdSpam(ctx, input, text); held {
+			return output, nil
+		}
+		s.mirrorMessage(ctx, input)
+		if output, held := s.holdForMemberPolicy(ctx, input); held {
+			return output, nil
+		}
 		triageOutput, err := s.handleAutoTriage(ctx, input, text)
 		if err != nil {
 			return MessageOutput{}, err
@@ -370,51 +581,52 @@ func (s *Service) buildApprovalNextCommandGuidance(ctx context.Context, input Me
 	if err != nil {
 		return "", err
 	}
-	scope := "this context"
+	multiple := i18n.PendingMultipleHere
 	if len(items) == 0 {
 		items, err = s.store.ListPendingActionApprovalsGlobal(ctx, 5)
 		if err != nil {
 			return "", err
 		}
-		scope = "all contexts"
+		multiple = i18n.PendingMultipleAll
 	}
 
 	if len(items) == 0 {
-		return "No pending action approvals right now. After an action is queued, run `/pending-actions` and then `/approve-action <action-id>`.", nil
+		return i18n.T(ctx, i18n.PendingNone), nil
 	}
 
 	if len(items) > 1 {
-		return fmt.Sprintf("Multiple pending actions found in %s. Run `/pending-actions`, then execute `/approve-action <action-id>` for the one you want.", scope), nil
+		return i18n.T(ctx, multiple), nil
 	}
 
 	actionID := strings.TrimSpace(items[0].ID)
 	if actionID == "" {
-		return "Run `/pending-actions`, then execute `/approve-action <action-id>`.", nil
+		return i18n.T(ctx, i18n.PendingRunList), nil
 	}
 
 	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
 	if err != nil {
 		if errors.Is(err, store.ErrIdentityNotFound) {
-			return fmt.Sprintf("Pending action found: `%s`.\nNext:\n1) Link your admin identity by sending `pair` and completing approval.\n2) Run `/approve-action %s`.\nUse `/pending-actions` to verify.", actionID, actionID), nil
+			return i18n.T(ctx, i18n.PendingNeedsPairing, actionID), nil
 		}
 		return "", err
 	}
-	if !isAdminRole(identity.Role) {
-		return fmt.Sprintf("Pending action found: `%s`.\nYou do not have admin approval rights in this context. Ask an admin to run `/approve-action %s`.\nUse `/pending-actions` to verify.", actionID, actionID), nil
+	allowed, err := s.hasPermission(ctx, input, identity, store.PermissionApproveActions)
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return i18n.T(ctx, i18n.PendingNotApprover, actionID), nil
 	}
-	return fmt.Sprintf("Run `/approve-action %s`.\nUse `/pending-actions` if you want to review all pending approvals first.", actionID), nil
+	return i18n.T(ctx, i18n.PendingRunApprove, actionID), nil
 }
 
 func (s *Service) handlePendingActions(ctx context.Context, input MessageInput) (MessageOutput, error) {
-	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	_, denied, err := s.authorize(ctx, input, store.PermissionApproveActions)
 	if err != nil {
-		if errors.Is(err, store.ErrIdentityNotFound) {
-			return MessageOutput{Handled: true, Reply: "Access denied: link your admin identity first."}, nil
-		}
 		return MessageOutput{}, err
 	}
-	if !isAdminRole(identity.Role) {
-		return MessageOutput{Handled: true, Reply: "Access denied: admin role required."}, nil
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
 	}
 	items, err := s.store.ListPendingActionApprovals(ctx, input.Connector, input.ExternalID, 10)
 	if err != nil {
@@ -436,12 +648,14 @@ func (s *Service) handlePendingActions(ctx context.Context, input MessageInput)
 		header = "Pending actions (all contexts):"
 	}
 	lines := []string{header}
-	for _, item := range items {
+	ids := make([]string, 0, len(items))
+	for index, item := range items {
+		ids = append(ids, item.ID)
 		summary := strings.TrimSpace(item.ActionSummary)
 		if summary == "" {
 			summary = item.ActionType
 		}
-		line := fmt.Sprintf("- `%s` %s (%s)", item.ID, summary, item.ActionType)
+		line := fmt.Sprintf("%d. `%s` %s (%s)", index+1, item.ID, summary, item.ActionType)
 		if showAllContexts {
 			connector := strings.TrimSpace(item.Connector)
 			externalID := strings.TrimSpace(item.ExternalID)
@@ -453,29 +667,45 @@ func (s *Service) handlePendingActions(ctx context.Context, input MessageInput)
 			}
 			line = fmt.Sprintf("%s [%s/%s]", line, connector, externalID)
 		}
+		if progress := formatApprovalProgress(item); progress != "" {
+			line = fmt.Sprintf("%s, %s", line, progress)
+		}
+		if risk := formatActionRisk(item); risk != "" {
+			line = fmt.Sprintf("%s, %s", line, risk)
+		}
+		if !item.ExpiresAt.IsZero() {
+			line = fmt.Sprintf("%s, expires %s", line, item.ExpiresAt.UTC().Format("2006-01-02 15:04 UTC"))
+		}
 		lines = append(lines, line)
 	}
-	return MessageOutput{Handled: true, Reply: strings.Join(lines, "\n")}, nil
+	lines = append(lines, "Reply `approve <n>` or `deny <n> [reason]` to act on an item.")
+	s.rememberActionListing(input, ids, time.Now())
+	text := strings.Join(lines, "\n")
+	return MessageOutput{
+		Handled: true,
+		Reply:   text,
+		Rich:    &reply.Message{Text: text, Buttons: actionApprovalButtons(ids)},
+	}, nil
 }
 
 func (s *Service) handleApproveAction(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if isActionFilterArg(arg) {
+		return s.handleApproveFilteredActions(ctx, input, arg)
+	}
 	actionID := normalizeActionCommandID(arg)
 	resolveLatest := strings.EqualFold(actionID, latestPendingActionAlias)
 	resolveMostRecent := strings.EqualFold(actionID, mostRecentPendingActionAlias)
 	resolveAll := strings.EqualFold(actionID, allPendingActionsAlias)
 
 	if actionID == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /approve-action <action-id> or 'approve all'"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /approve-action <action-id> | --type <type> [--context this] [--older-than 1h] or 'approve all'")}, nil
 	}
-	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	identity, denied, err := s.authorize(ctx, input, store.PermissionApproveActions)
 	if err != nil {
-		if errors.Is(err, store.ErrIdentityNotFound) {
-			return MessageOutput{Handled: true, Reply: "Access denied: link your admin identity first."}, nil
-		}
 		return MessageOutput{}, err
 	}
-	if !isAdminRole(identity.Role) {
-		return MessageOutput{Handled: true, Reply: "Access denied: admin role required."}, nil
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
 	}
 
 	if resolveAll {
@@ -484,6 +714,9 @@ func (s *Service) handleApproveAction(ctx context.Context, input MessageInput, a
 		if err != nil {
 			return MessageOutput{}, err
 		}
+		if items, err = s.decidableActions(ctx, identity, items); err != nil {
+			return MessageOutput{}, err
+		}
 		if len(items) == 0 {
 			// Fallback to global if empty? Or just say none.
 			// Let's check global too if context is empty, similar to pending-actions command.
@@ -491,59 +724,16 @@ func (s *Service) handleApproveAction(ctx context.Context, input MessageInput, a
 			if err != nil {
 				return MessageOutput{}, err
 			}
+			// Only the workspaces where this role may approve.
+			if items, err = s.decidableActions(ctx, identity, items); err != nil {
+				return MessageOutput{}, err
+			}
 		}
 		if len(items) == 0 {
 			return MessageOutput{Handled: true, Reply: "No pending actions to approve."}, nil
 		}
 
-		successCount := 0
-		failures := []string{}
-		results := []string{}
-
-		for _, item := range items {
-			res, _, err := s.approveAndExecuteAction(ctx, input, item.ID, identity.UserID)
-			if err != nil {
-				failures = append(failures, fmt.Sprintf("%s: %v", item.ID, err))
-			} else {
-				successCount++
-				if res != nil {
-					results = append(results, fmt.Sprintf("Action `%s` output:\n%s", item.ID, res.Message))
-				}
-			}
-		}
-
-		if successCount > 0 && s.agent != nil {
-			contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
-			if err == nil {
-				agentPrompt := fmt.Sprintf("APPROVED ACTIONS EXECUTED.\n\n%s\n\nInterpret these results for the user.", strings.Join(results, "\n\n"))
-
-				agentCtx := context.WithValue(ctx, ContextKeyRecord, contextRecord)
-				agentCtx = context.WithValue(agentCtx, ContextKeyInput, input)
-				// Grant sensitive approval for follow-up actions (if any)
-				s.grantSensitiveToolApproval(input, time.Now().UTC())
-				agentCtx = agent.WithSensitiveToolApproval(agentCtx)
-
-				agentRes := s.agent.Execute(agentCtx, llm.MessageInput{
-					Connector:   input.Connector,
-					WorkspaceID: contextRecord.WorkspaceID,
-					ContextID:   contextRecord.ID,
-					ExternalID:  input.ExternalID,
-					DisplayName: input.DisplayName,
-					FromUserID:  input.FromUserID,
-					Text:        agentPrompt,
-				})
-
-				if agentRes.Error == nil && strings.TrimSpace(agentRes.Reply) != "" {
-					return MessageOutput{Handled: true, Reply: agentRes.Reply}, nil
-				}
-			}
-		}
-
-		reply := fmt.Sprintf("Approved %d actions.", successCount)
-		if len(failures) > 0 {
-			reply += fmt.Sprintf("\nFailed: %d\n%s", len(failures), strings.Join(failures, "\n"))
-		}
-		return MessageOutput{Handled: true, Reply: reply}, nil
+		return s.approveActionBatch(ctx, input, identity, items)
 	}
 
 	if resolveLatest {
@@ -560,14 +750,34 @@ func (s *Service) handleApproveAction(ctx context.Context, input MessageInput, a
 		}
 		actionID = resolved
 	}
+	if isPendingActionReference(actionID) {
+		resolved, reply := s.resolvePendingActionReference(ctx, input, identity, actionID)
+		if reply != "" {
+			return MessageOutput{Handled: true, Reply: reply}, nil
+		}
+		actionID = resolved
+	}
+	if position, ok := parseActionPosition(actionID); ok {
+		resolved, reply := s.resolveActionPosition(input, position, time.Now())
+		if reply != "" {
+			return MessageOutput{Handled: true, Reply: reply}, nil
+		}
+		actionID = resolved
+	}
 
-	res, reply, err := s.approveAndExecuteAction(ctx, input, actionID, identity.UserID)
+	res, reply, err := s.approveAndExecuteAction(ctx, input, actionID, identity)
 	if err != nil {
+		if isApprovalQuorumError(err) {
+			return MessageOutput{Handled: true, Reply: reply}, nil
+		}
+		if errors.Is(err, errNotActionApprover) {
+			return MessageOutput{Handled: true, Reply: fmt.Sprintf("Access denied: %v.", err)}, nil
+		}
 		if errors.Is(err, store.ErrActionApprovalNotFound) {
-			return MessageOutput{Handled: true, Reply: "Action approval not found."}, nil
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.ActionNotFound)}, nil
 		}
 		if errors.Is(err, store.ErrActionApprovalNotReady) {
-			return MessageOutput{Handled: true, Reply: "Action approval is not pending."}, nil
+			return MessageOutput{Handled: true, Reply: s.actionAlreadyHandledReply(ctx, actionID)}, nil
 		}
 		return MessageOutput{}, err
 	}
@@ -577,17 +787,69 @@ func (s *Service) handleApproveAction(ctx context.Context, input MessageInput, a
 		if err == nil {
 			agentPrompt := fmt.Sprintf("APPROVED ACTION EXECUTED.\nAction: %s\nResult: %s\n\nInterpret this result for the user.", actionID, res.Message)
 
-			agentCtx := context.WithValue(ctx, ContextKeyRecord, contextRecord)
+			agentCtx := withContextRecord(ctx, contextRecord)
+			agentCtx = context.WithValue(agentCtx, ContextKeyInput, input)
+			// Follow-up tool calls may use only what the approval granted.
+			agentCtx = agent.WithToolApprovalScopes(agentCtx, s.activeSensitiveGrantScopes(input, time.Now().UTC())...)
+
+			agentRes := s.agent.Execute(agentCtx, llm.MessageInput{
+				Connector:   input.Connector,
+				WorkspaceID: contextRecord.WorkspaceID,
+				ContextID:   contextRecord.ID,
+				ExternalID:  input.ExternalID,
+				ThreadID:    input.ThreadID,
+				DisplayName: input.DisplayName,
+				FromUserID:  input.FromUserID,
+				Text:        agentPrompt,
+			})
+
+			if agentRes.Error == nil && strings.TrimSpace(agentRes.Reply) != "" {
+				return MessageOutput{Handled: true, Reply: agentRes.Reply}, nil
+			}
+		}
+	}
+
+	return MessageOutput{Handled: true, Reply: reply}, nil
+}
+
+// approveActionBatch approves and runs items one by one, then lets the agent
+// summarize what the approved actions returned.
+func (s *Service) approveActionBatch(ctx context.Context, input MessageInput, identity store.UserIdentity, items []store.ActionApproval) (MessageOutput, error) {
+	successCount := 0
+	failures := []string{}
+	waiting := []string{}
+	results := []string{}
+
+	for _, item := range items {
+		res, quorumReply, err := s.approveAndExecuteAction(ctx, input, item.ID, identity)
+		if isApprovalQuorumError(err) {
+			waiting = append(waiting, quorumReply)
+		} else if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", item.ID, err))
+		} else {
+			successCount++
+			if res != nil {
+				results = append(results, fmt.Sprintf("Action `%s` output:\n%s", item.ID, res.Message))
+			}
+		}
+	}
+
+	if successCount > 0 && s.agent != nil {
+		contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+		if err == nil {
+			agentPrompt := fmt.Sprintf("APPROVED ACTIONS EXECUTED.\n\n%s\n\nInterpret these results for the user.", strings.Join(results, "\n\n"))
+
+			agentCtx := withContextRecord(ctx, contextRecord)
 			agentCtx = context.WithValue(agentCtx, ContextKeyInput, input)
-			// Grant sensitive approval for follow-up actions (if any)
-			s.grantSensitiveToolApproval(input, time.Now().UTC())
-			agentCtx = agent.WithSensitiveToolApproval(agentCtx)
+			// Follow-up tool calls may use only what the approval granted.
+			agentCtx = agent.WithToolApprovalScopes(agentCtx, s.activeSensitiveGrantScopes(input, time.Now().UTC())...)
 
 			agentRes := s.agent.Execute(agentCtx, llm.MessageInput{
 				Connector:   input.Connector,
 				WorkspaceID: contextRecord.WorkspaceID,
 				ContextID:   contextRecord.ID,
 				ExternalID:  input.ExternalID,
+				ThreadID:    input.ThreadID,
 				DisplayName: input.DisplayName,
 				FromUserID:  input.FromUserID,
 				Text:        agentPrompt,
@@ -599,18 +861,35 @@ func (s *Service) handleApproveAction(ctx context.Context, input MessageInput, a
 		}
 	}
 
+	reply := fmt.Sprintf("Approved %d actions.", successCount)
+	if len(waiting) > 0 {
+		reply += fmt.Sprintf("\nWaiting for more admins: %d\n%s", len(waiting), strings.Join(waiting, "\n"))
+	}
+	if len(failures) > 0 {
+		reply += fmt.Sprintf("\nFailed: %d\n%s", len(failures), strings.Join(failures, "\n"))
+	}
 	return MessageOutput{Handled: true, Reply: reply}, nil
 }
 
-func (s *Service) approveAndExecuteAction(ctx context.Context, input MessageInput, actionID, approverID string) (*executor.Result, string, error) {
+func (s *Service) approveAndExecuteAction(ctx context.Context, input MessageInput, actionID string, identity store.UserIdentity) (*executor.Result, string, error) {
+	if err := s.checkActionApprover(ctx, identity, actionID); err != nil {
+		return nil, "", err
+	}
 	record, err := s.store.ApproveActionApproval(ctx, store.ApproveActionApprovalInput{
 		ID:             actionID,
-		ApproverUserID: approverID,
+		ApproverUserID: identity.UserID,
 	})
 	if err != nil {
+		if isApprovalQuorumError(err) {
+			return nil, formatApprovalQuorumReply(record, err), err
+		}
 		return nil, "", err
 	}
-	s.grantSensitiveToolApproval(input, time.Now().UTC())
+	grantWorkspaceID := record.WorkspaceID
+	if contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName); err == nil {
+		grantWorkspaceID = contextRecord.WorkspaceID
+	}
+	s.grantSensitiveToolApproval(input, grantWorkspaceID, approvalGrantScope(record), record.ID, time.Now().UTC())
 
 	if s.actionExecutor == nil {
 		record, err = s.store.UpdateActionExecution(ctx, store.UpdateActionExecutionInput{
@@ -668,27 +947,27 @@ func fallbackPluginLabel(value string) string {
 func (s *Service) handleDenyAction(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
 	trimmed := strings.TrimSpace(arg)
 	if trimmed == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /deny-action <action-id> [reason]"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /deny-action <action-id> [reason] | --type <type> [--context this] [--older-than 1h] [reason]")}, nil
+	}
+	if isActionFilterArg(trimmed) {
+		return s.handleDenyFilteredActions(ctx, input, trimmed)
 	}
 	parts := strings.Fields(trimmed)
 	actionID := normalizeActionCommandID(parts[0])
 	if actionID == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /deny-action <action-id> [reason]"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /deny-action <action-id> [reason] | --type <type> [--context this] [--older-than 1h] [reason]")}, nil
 	}
 	reason := "denied by admin"
 	if len(parts) > 1 {
 		reason = strings.Join(parts[1:], " ")
 	}
 	resolveLatest := strings.EqualFold(actionID, latestPendingActionAlias)
-	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	identity, denied, err := s.authorize(ctx, input, store.PermissionApproveActions)
 	if err != nil {
-		if errors.Is(err, store.ErrIdentityNotFound) {
-			return MessageOutput{Handled: true, Reply: "Access denied: link your admin identity first."}, nil
-		}
 		return MessageOutput{}, err
 	}
-	if !isAdminRole(identity.Role) {
-		return MessageOutput{Handled: true, Reply: "Access denied: admin role required."}, nil
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
 	}
 	if resolveLatest {
 		resolved, reply := s.resolveSinglePendingActionID(ctx, input)
@@ -697,17 +976,30 @@ func (s *Service) handleDenyAction(ctx context.Context, input MessageInput, arg
 		}
 		actionID = resolved
 	}
-	record, err := s.store.DenyActionApproval(ctx, store.DenyActionApprovalInput{
-		ID:             actionID,
-		ApproverUserID: identity.UserID,
-		Reason:         reason,
-	})
+	if isPendingActionReference(actionID) {
+		resolved, reply := s.resolvePendingActionReference(ctx, input, identity, actionID)
+		if reply != "" {
+			return MessageOutput{Handled: true, Reply: reply}, nil
+		}
+		actionID = resolved
+	}
+	if position, ok := parseActionPosition(actionID); ok {
+		resolved, reply := s.resolveActionPosition(input, position, time.Now())
+		if reply != "" {
+			return MessageOutput{Handled: true, Reply: reply}, nil
+		}
+		actionID = resolved
+	}
+	record, err := s.denyAction(ctx, identity, actionID, reason)
 	if err != nil {
+		if errors.Is(err, errNotActionApprover) {
+			return MessageOutput{Handled: true, Reply: fmt.Sprintf("Access denied: %v.", err)}, nil
+		}
 		if errors.Is(err, store.ErrActionApprovalNotFound) {
-			return MessageOutput{Handled: true, Reply: "Action approval not found."}, nil
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.ActionNotFound)}, nil
 		}
 		if errors.Is(err, store.ErrActionApprovalNotReady) {
-			return MessageOutput{Handled: true, Reply: "Action approval is not pending."}, nil
+			return MessageOutput{Handled: true, Reply: s.actionAlreadyHandledReply(ctx, actionID)}, nil
 		}
 		return MessageOutput{}, err
 	}
@@ -717,21 +1009,31 @@ func (s *Service) handleDenyAction(ctx context.Context, input MessageInput, arg
 	}, nil
 }
 
+// denyAction denies actionID once identity is known to be an approver in the
+// action's workspace.
+func (s *Service) denyAction(ctx context.Context, identity store.UserIdentity, actionID, reason string) (store.ActionApproval, error) {
+	if err := s.checkActionApprover(ctx, identity, actionID); err != nil {
+		return store.ActionApproval{}, err
+	}
+	return s.store.DenyActionApproval(ctx, store.DenyActionApprovalInput{
+		ID:             actionID,
+		ApproverUserID: identity.UserID,
+		Reason:         reason,
+	})
+}
+
 func (s *Service) handlePrompt(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
-	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	_, denied, err := s.authorize(ctx, input, store.PermissionSetPrompt)
 	if err != nil {
-		if errors.Is(err, store.ErrIdentityNotFound) {
-			return MessageOutput{Handled: true, Reply: "Access denied: link your admin identity first."}, nil
-		}
 		return MessageOutput{}, err
 	}
-	if !isAdminRole(identity.Role) {
-		return MessageOutput{Handled: true, Reply: "Access denied: admin role required."}, nil
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
 	}
 
 	trimmed := strings.TrimSpace(arg)
 	if trimmed == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /prompt show | /prompt set <text> | /prompt clear"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /prompt show | /prompt set [@revision] <text> | /prompt clear")}, nil
 	}
 	lower := strings.ToLower(trimmed)
 	switch {
@@ -746,10 +1048,10 @@ func (s *Service) handlePrompt(ctx context.Context, input MessageInput, arg stri
 		}
 		return MessageOutput{
 			Handled: true,
-			Reply:   "Current context prompt:\n" + prompt,
+			Reply:   fmt.Sprintf("Current context prompt (revision %d):\n%s", policy.Revision, prompt),
 		}, nil
 	case lower == "clear":
-		_, err := s.store.SetContextSystemPromptByExternal(ctx, input.Connector, input.ExternalID, "")
+		_, err := s.store.SetContextSystemPromptByExternal(ctx, input.Connector, input.ExternalID, "", 0)
 		if err != nil {
 			return MessageOutput{}, err
 		}
@@ -758,21 +1060,41 @@ func (s *Service) handlePrompt(ctx context.Context, input MessageInput, arg stri
 			Reply:   "Context prompt cleared.",
 		}, nil
 	case strings.HasPrefix(lower, "set "):
-		value := strings.TrimSpace(trimmed[len("set "):])
+		value, expectedRevision := parsePromptRevision(strings.TrimSpace(trimmed[len("set "):]))
 		if value == "" {
-			return MessageOutput{Handled: true, Reply: "Usage: /prompt set <text>"}, nil
+			return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /prompt set [@revision] <text>")}, nil
 		}
-		policy, err := s.store.SetContextSystemPromptByExternal(ctx, input.Connector, input.ExternalID, value)
+		policy, err := s.store.SetContextSystemPromptByExternal(ctx, input.Connector, input.ExternalID, value, expectedRevision)
 		if err != nil {
+			if errors.Is(err, store.ErrRevisionConflict) {
+				return MessageOutput{
+					Handled: true,
+					Reply:   fmt.Sprintf("Context prompt changed since revision %d. Run `/prompt show` and try again.", expectedRevision),
+				}, nil
+			}
 			return MessageOutput{}, err
 		}
 		return MessageOutput{
 			Handled: true,
-			Reply:   fmt.Sprintf("Context prompt updated for `%s`.", policy.ContextID),
+			Reply:   fmt.Sprintf("Context prompt updated for `%s` (revision %d).", policy.ContextID, policy.Revision),
 		}, nil
 	default:
-		return MessageOutput{Handled: true, Reply: "Usage: /prompt show | /prompt set <text> | /prompt clear"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /prompt show | /prompt set [@revision] <text> | /prompt clear")}, nil
+	}
+}
+
+// parsePromptRevision splits an optional leading "@<revision>" guard off a
+// /prompt set argument. Without one the write is unconditional.
+func parsePromptRevision(arg string) (string, int) {
+	if !strings.HasPrefix(arg, "@") {
+		return arg, 0
+	}
+	token, rest, _ := strings.Cut(arg, " ")
+	revision, err := strconv.Atoi(strings.TrimPrefix(token, "@"))
+	if err != nil || revision < 1 {
+		return arg, 0
 	}
+	return strings.TrimSpace(rest), revision
 }
 
 func (s *Service) handleStatus(ctx context.Context, input MessageInput) (MessageOutput, error) {
@@ -832,7 +1154,7 @@ func (s *Service) handleStatus(ctx context.Context, input MessageInput) (Message
 func (s *Service) handleSearch(ctx context.Context, input MessageInput, query string) (MessageOutput, error) {
 	query = strings.TrimSpace(query)
 	if query == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /search <query>"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /search <query>")}, nil
 	}
 	if s.retriever == nil {
 		return MessageOutput{Handled: true, Reply: "Search is not configured on this runtime."}, nil
@@ -863,13 +1185,17 @@ func (s *Service) handleSearch(ctx context.Context, input MessageInput, query st
 		if location == "" {
 			location = strings.TrimSpace(result.DocID)
 		}
+		label := fmt.Sprintf("`%s`", location)
+		if qmd.IsSharedTarget(location) {
+			label += " [shared knowledge]"
+		}
 		score := int(result.Score * 100)
 		snippet := compactSnippet(result.Snippet)
 		if score > 0 {
-			lines = append(lines, fmt.Sprintf("%d. `%s` (%d%%) %s", index+1, location, score, snippet))
+			lines = append(lines, fmt.Sprintf("%d. %s (%d%%) %s", index+1, label, score, snippet))
 			continue
 		}
-		lines = append(lines, fmt.Sprintf("%d. `%s` %s", index+1, location, snippet))
+		lines = append(lines, fmt.Sprintf("%d. %s %s", index+1, label, snippet))
 	}
 	return MessageOutput{
 		Handled: true,
@@ -880,7 +1206,7 @@ func (s *Service) handleSearch(ctx context.Context, input MessageInput, query st
 func (s *Service) handleOpen(ctx context.Context, input MessageInput, target string) (MessageOutput, error) {
 	target = strings.TrimSpace(target)
 	if target == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /open <path-or-docid>"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /open <path-or-docid>")}, nil
 	}
 	if s.retriever == nil {
 		return MessageOutput{Handled: true, Reply: "Open is not configured on this runtime."}, nil
@@ -925,13 +1251,20 @@ func (s *Service) handleOpen(ctx context.Context, input MessageInput, target str
 func (s *Service) handleTask(ctx context.Context, input MessageInput, prompt string) (MessageOutput, error) {
 	prompt = strings.TrimSpace(prompt)
 	if prompt == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /task <what should be done>"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /task <what should be done>")}, nil
 	}
 
 	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
 	if err != nil {
 		return MessageOutput{}, err
 	}
+	denied, err := s.taskCreationDenied(ctx, input, contextRecord.ID)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
+	}
 
 	title := prompt
 	if len(title) > 72 {
@@ -965,11 +1298,14 @@ func (s *Service) handleTask(ctx context.Context, input MessageInput, prompt str
 func (s *Service) handleMonitorObjective(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
 	goal := strings.TrimSpace(arg)
 	if goal == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /monitor <what to track>"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /monitor <what to track> or /monitor template <name> key=value ...")}, nil
 	}
 	if s.store == nil {
 		return MessageOutput{Handled: true, Reply: "Monitoring objectives are unavailable in this runtime."}, nil
 	}
+	if fields := strings.Fields(goal); len(fields) > 0 && (strings.EqualFold(fields[0], "template") || strings.EqualFold(fields[0], "templates")) {
+		return s.handleMonitorTemplate(ctx, input, strings.TrimSpace(goal[len(fields[0]):]))
+	}
 	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
 	if err != nil {
 		return MessageOutput{}, err
@@ -978,27 +1314,37 @@ func (s *Service) handleMonitorObjective(ctx context.Context, input MessageInput
 	if len(title) > 72 {
 		title = title[:72]
 	}
-	objectivePrompt := strings.TrimSpace("Monitor this target for updates and report only concrete changes:\n" + goal)
+	// The runtime diffs each run against the last, so the prompt asks for
+	// the current state rather than for changes the model would have to
+	// remember.
+	objectivePrompt := strings.TrimSpace("Check this target and describe its current state:\n" + goal)
 	active := true
 	_, err = s.store.CreateObjective(ctx, store.CreateObjectiveInput{
-		WorkspaceID: contextRecord.WorkspaceID,
-		ContextID:   contextRecord.ID,
-		Title:       title,
-		Prompt:      objectivePrompt,
-		TriggerType: store.ObjectiveTriggerSchedule,
-		CronExpr:    defaultObjectiveCronExpr,
-		Active:      &active,
+		WorkspaceID:       contextRecord.WorkspaceID,
+		ContextID:         contextRecord.ID,
+		Title:             title,
+		Prompt:            objectivePrompt,
+		TriggerType:       store.ObjectiveTriggerSchedule,
+		CronExpr:          defaultObjectiveCronExpr,
+		Active:            &active,
+		ReportChangesOnly: true,
 	})
+	if errors.Is(err, store.ErrQuotaExceeded) {
+		return MessageOutput{Handled: true, Reply: "Could not create monitoring objective: " + err.Error()}, nil
+	}
 	if err != nil {
 		return MessageOutput{}, err
 	}
 	return MessageOutput{
 		Handled: true,
-		Reply:   "Monitoring objective created. I’ll keep checking and report updates until you pause or delete it.",
+		Reply:   "Monitoring objective created. I’ll keep checking and report only what changed until you pause or delete it.",
 	}, nil
 }
 
 func (s *Service) handleAutoTriage(ctx context.Context, input MessageInput, text string) (MessageOutput, error) {
+	if s.modelUnavailable() {
+		return s.handleDegradedMessage(ctx, input, text)
+	}
 	if !s.triageEnabled {
 		return MessageOutput{}, nil
 	}
@@ -1022,20 +1368,30 @@ func (s *Service) handleAgentAutoTriage(ctx context.Context, input MessageInput,
 
 	agentInputText := strings.TrimSpace(text)
 
-	agentCtx := context.WithValue(ctx, ContextKeyRecord, contextRecord)
+	agentCtx := withContextRecord(ctx, contextRecord)
 	agentCtx = context.WithValue(agentCtx, ContextKeyInput, input)
-	if s.consumeSensitiveToolApproval(input, time.Now().UTC()) {
-		agentCtx = agent.WithSensitiveToolApproval(agentCtx)
+	if scopes := s.consumeSensitiveToolApproval(input, time.Now().UTC()); len(scopes) > 0 {
+		agentCtx = agent.WithToolApprovalScopes(agentCtx, scopes...)
+	}
+	var assignment canary.Assignment
+	if s.canaryRouter != nil {
+		assignment = s.canaryRouter.Assign(ctx, contextRecord.WorkspaceID, contextRecord.ID)
+		agentCtx = canary.WithAssignment(agentCtx, assignment)
 	}
 	result := s.agent.Execute(agentCtx, llm.MessageInput{
 		Connector:   strings.TrimSpace(input.Connector),
 		WorkspaceID: strings.TrimSpace(contextRecord.WorkspaceID),
 		ContextID:   strings.TrimSpace(contextRecord.ID),
 		ExternalID:  strings.TrimSpace(input.ExternalID),
+		ThreadID:    strings.TrimSpace(input.ThreadID),
 		DisplayName: strings.TrimSpace(input.DisplayName),
 		FromUserID:  strings.TrimSpace(input.FromUserID),
 		Text:        agentInputText,
+		Images:      input.Images,
 	})
+	if s.canaryRouter != nil && len(assignment.Arms) > 0 {
+		s.canaryRouter.Record(ctx, assignment, result.Error != nil, result.Blocked)
+	}
 	s.persistAgentAuditTraces(ctx, contextRecord, input, result)
 	s.appendAgentToolCallLogs(contextRecord, input, result)
 	reply := strings.TrimSpace(result.Reply)
@@ -1046,6 +1402,13 @@ func (s *Service) handleAgentAutoTriage(ctx context.Context, input MessageInput,
 				Reply:   reply,
 			}
 		}
+		// The provider went down during this turn: answer like every later
+		// message will until it is back.
+		if s.modelUnavailable() {
+			if degraded, err := s.handleDegradedMessage(ctx, input, text); err == nil && degraded.Handled {
+				return degraded
+			}
+		}
 		return MessageOutput{
 			Handled: true,
 			Reply:   "I started work on that but ran into an internal error. Please try again in a moment.",
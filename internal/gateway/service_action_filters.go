@@ -0,0 +1,216 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// actionFilterLimit caps how many pending actions one filtered command
+// looks at.
+const actionFilterLimit = 200
+
+// actionFilter selects pending actions for bulk /approve-action and
+// /deny-action, e.g. `--type run_command --older-than 1h --context this`.
+type actionFilter struct {
+	Types       []string
+	ThisContext bool
+	OlderThan   time.Duration
+}
+
+func isActionFilterArg(arg string) bool {
+	return strings.HasPrefix(normalizeFilterDashes(strings.TrimSpace(arg)), "--")
+}
+
+// normalizeFilterDashes undoes chat clients turning "--" into an em dash.
+func normalizeFilterDashes(arg string) string {
+	return strings.ReplaceAll(arg, "\u2014", "--")
+}
+
+// parseActionFilter reads filter flags and returns the words that are not
+// flags, which /deny-action uses as the reason.
+func parseActionFilter(arg string) (actionFilter, []string, error) {
+	filter := actionFilter{}
+	rest := []string{}
+	fields := strings.Fields(normalizeFilterDashes(arg))
+	for index := 0; index < len(fields); index++ {
+		field := fields[index]
+		if !strings.HasPrefix(field, "--") {
+			rest = append(rest, field)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(field, "--"), "=")
+		if !hasValue {
+			if index+1 >= len(fields) {
+				return actionFilter{}, nil, fmt.Errorf("--%s needs a value", name)
+			}
+			index++
+			value = fields[index]
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(name) {
+		case "type":
+			for _, actionType := range strings.Split(value, ",") {
+				if actionType = strings.ToLower(strings.TrimSpace(actionType)); actionType != "" {
+					filter.Types = append(filter.Types, actionType)
+				}
+			}
+		case "context":
+			switch strings.ToLower(value) {
+			case "this", "here":
+				filter.ThisContext = true
+			case "all", "any":
+				filter.ThisContext = false
+			default:
+				return actionFilter{}, nil, fmt.Errorf("--context must be this or all")
+			}
+		case "older-than":
+			age, err := parseFilterAge(value)
+			if err != nil {
+				return actionFilter{}, nil, err
+			}
+			filter.OlderThan = age
+		default:
+			return actionFilter{}, nil, fmt.Errorf("unknown filter --%s", name)
+		}
+	}
+	if len(filter.Types) == 0 && !filter.ThisContext && filter.OlderThan == 0 {
+		return actionFilter{}, nil, fmt.Errorf("set at least one of --type, --context this or --older-than")
+	}
+	return filter, rest, nil
+}
+
+// parseFilterAge accepts Go durations plus a day suffix such as 2d.
+func parseFilterAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(strings.ToLower(value), "d"); ok {
+		if count, err := strconv.Atoi(days); err == nil && count > 0 {
+			return time.Duration(count) * 24 * time.Hour, nil
+		}
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("--older-than needs a duration such as 30m, 1h or 2d")
+	}
+	return age, nil
+}
+
+func (f actionFilter) matches(item store.ActionApproval, now time.Time) bool {
+	if len(f.Types) > 0 {
+		matched := false
+		for _, actionType := range f.Types {
+			if strings.EqualFold(item.ActionType, actionType) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if f.OlderThan > 0 && (item.CreatedAt.IsZero() || now.Sub(item.CreatedAt) < f.OlderThan) {
+		return false
+	}
+	return true
+}
+
+func (f actionFilter) describe() string {
+	parts := []string{}
+	if len(f.Types) > 0 {
+		parts = append(parts, "type "+strings.Join(f.Types, ","))
+	}
+	if f.ThisContext {
+		parts = append(parts, "this context")
+	}
+	if f.OlderThan > 0 {
+		parts = append(parts, "older than "+f.OlderThan.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// filteredPendingActions lists pending actions matching filter that identity
+// may decide, across all contexts unless it asks for this one.
+func (s *Service) filteredPendingActions(ctx context.Context, input MessageInput, identity store.UserIdentity, filter actionFilter) ([]store.ActionApproval, error) {
+	var (
+		items []store.ActionApproval
+		err   error
+	)
+	if filter.ThisContext {
+		items, err = s.store.ListPendingActionApprovals(ctx, input.Connector, input.ExternalID, actionFilterLimit)
+	} else {
+		items, err = s.store.ListPendingActionApprovalsGlobal(ctx, actionFilterLimit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	matched := []store.ActionApproval{}
+	for _, item := range items {
+		if filter.matches(item, now) {
+			matched = append(matched, item)
+		}
+	}
+	return s.decidableActions(ctx, identity, matched)
+}
+
+func (s *Service) handleApproveFilteredActions(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	filter, _, err := parseActionFilter(arg)
+	if err != nil {
+		return MessageOutput{Handled: true, Reply: "Invalid filter: " + err.Error()}, nil
+	}
+	identity, reply, err := s.authorize(ctx, input, store.PermissionApproveActions)
+	if reply != "" || err != nil {
+		return MessageOutput{Handled: reply != "", Reply: reply}, err
+	}
+	items, err := s.filteredPendingActions(ctx, input, identity, filter)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if len(items) == 0 {
+		return MessageOutput{Handled: true, Reply: fmt.Sprintf("No pending actions match (%s).", filter.describe())}, nil
+	}
+	return s.approveActionBatch(ctx, input, identity, items)
+}
+
+func (s *Service) handleDenyFilteredActions(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	filter, rest, err := parseActionFilter(arg)
+	if err != nil {
+		return MessageOutput{Handled: true, Reply: "Invalid filter: " + err.Error()}, nil
+	}
+	identity, reply, err := s.authorize(ctx, input, store.PermissionApproveActions)
+	if reply != "" || err != nil {
+		return MessageOutput{Handled: reply != "", Reply: reply}, err
+	}
+	reason := "denied by admin"
+	if len(rest) > 0 {
+		reason = strings.Join(rest, " ")
+	}
+	items, err := s.filteredPendingActions(ctx, input, identity, filter)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if len(items) == 0 {
+		return MessageOutput{Handled: true, Reply: fmt.Sprintf("No pending actions match (%s).", filter.describe())}, nil
+	}
+	denied := []string{}
+	failures := []string{}
+	for _, item := range items {
+		record, err := s.denyAction(ctx, identity, item.ID, reason)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", item.ID, err))
+			continue
+		}
+		denied = append(denied, fmt.Sprintf("`%s` (%s)", record.ID, record.ActionType))
+	}
+	reply = fmt.Sprintf("Denied %d actions (%s).", len(denied), filter.describe())
+	if len(denied) > 0 {
+		reply += "\n" + strings.Join(denied, "\n")
+	}
+	if len(failures) > 0 {
+		reply += fmt.Sprintf("\nFailed: %d\n%s", len(failures), strings.Join(failures, "\n"))
+	}
+	return MessageOutput{Handled: true, Reply: reply}, nil
+}
@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestParseActionFilter(t *testing.T) {
+	filter, rest, err := parseActionFilter("--type run_command,fetch_url --older-than=2d --context this too risky")
+	if err != nil {
+		t.Fatalf("parse filter: %v", err)
+	}
+	if len(filter.Types) != 2 || filter.Types[1] != "fetch_url" || !filter.ThisContext || filter.OlderThan != 48*time.Hour {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+	if strings.Join(rest, " ") != "too risky" {
+		t.Fatalf("expected the reason left over, got %q", rest)
+	}
+	if filter, _, err := parseActionFilter("—type run_command"); err != nil || len(filter.Types) != 1 {
+		t.Fatalf("expected em dash flags accepted, got %+v %v", filter, err)
+	}
+	for _, arg := range []string{"--context", "--older-than soon", "--context other", "--risk high", "--context all"} {
+		if _, _, err := parseActionFilter(arg); err == nil {
+			t.Fatalf("expected %q rejected", arg)
+		}
+	}
+}
+
+func TestHandleDenyActionByFilter(t *testing.T) {
+	old := time.Now().UTC().Add(-2 * time.Hour)
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+		actionApprovals: []store.ActionApproval{
+			{ID: "act-1", ActionType: "run_command", Status: "pending", Connector: "telegram", ExternalID: "7", CreatedAt: old},
+			{ID: "act-2", ActionType: "run_command", Status: "pending", Connector: "telegram", ExternalID: "42", CreatedAt: time.Now().UTC()},
+			{ID: "act-3", ActionType: "fetch_url", Status: "pending", Connector: "telegram", ExternalID: "42", CreatedAt: old},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/deny-action --type run_command --older-than 1h stale",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if !strings.Contains(output.Reply, "Denied 1 actions") || !strings.Contains(output.Reply, "act-1") {
+		t.Fatalf("expected only the old run_command denied, got %s", output.Reply)
+	}
+	if fStore.actionApprovals[0].Status != "denied" || fStore.actionApprovals[0].DeniedReason != "stale" {
+		t.Fatalf("expected act-1 denied with reason, got %+v", fStore.actionApprovals[0])
+	}
+	if fStore.actionApprovals[1].Status != "pending" || fStore.actionApprovals[2].Status != "pending" {
+		t.Fatal("expected non-matching actions left pending")
+	}
+
+	output, err = service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/approve-action --context this --type fetch_url",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if !strings.Contains(output.Reply, "Approved 1 actions") {
+		t.Fatalf("expected one approval, got %s", output.Reply)
+	}
+	if fStore.actionApprovals[2].Status != "approved" || fStore.actionApprovals[1].Status != "pending" {
+		t.Fatalf("expected only the fetch_url in this context approved, got %+v", fStore.actionApprovals)
+	}
+}
+
+func TestHandleFilteredActionsRequireAdmin(t *testing.T) {
+	fStore := &fakeStore{
+		identity:        store.UserIdentity{UserID: "user-1", Role: "member"},
+		actionApprovals: []store.ActionApproval{{ID: "act-1", ActionType: "run_command", Status: "pending"}},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/deny-action --type run_command",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if !strings.Contains(output.Reply, "approve_actions permission required") || fStore.actionApprovals[0].Status != "pending" {
+		t.Fatalf("expected refusal, got %s", output.Reply)
+	}
+}
@@ -0,0 +1,93 @@
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/reply"
+)
+
+// actionListingTTL bounds how long "approve 2" keeps referring to the last
+// /pending-actions listing shown in a conversation.
+const actionListingTTL = 30 * time.Minute
+
+type actionListing struct {
+	ids       []string
+	expiresAt time.Time
+}
+
+// rememberActionListing stores the action ids in the order they were listed
+// so later positional replies resolve against what the admin actually saw.
+func (s *Service) rememberActionListing(input MessageInput, ids []string, now time.Time) {
+	key := actionListingKey(input)
+	if key == "" {
+		return
+	}
+	s.listingMu.Lock()
+	defer s.listingMu.Unlock()
+	cutoff := now.UTC()
+	for existingKey, listing := range s.actionListings {
+		if !listing.expiresAt.After(cutoff) {
+			delete(s.actionListings, existingKey)
+		}
+	}
+	s.actionListings[key] = actionListing{
+		ids:       append([]string{}, ids...),
+		expiresAt: cutoff.Add(actionListingTTL),
+	}
+}
+
+// resolveActionPosition maps a 1-based position to the listed action id. The
+// second return value is a user-facing reply when the position cannot be used.
+func (s *Service) resolveActionPosition(input MessageInput, position int, now time.Time) (string, string) {
+	s.listingMu.Lock()
+	listing, ok := s.actionListings[actionListingKey(input)]
+	s.listingMu.Unlock()
+	if !ok || !listing.expiresAt.After(now.UTC()) {
+		return "", "No recent pending-actions list here. Run `/pending-actions` first, then reply with the item number."
+	}
+	if position < 1 || position > len(listing.ids) {
+		return "", fmt.Sprintf("There is no item %d in the last pending-actions list (1-%d).", position, len(listing.ids))
+	}
+	return listing.ids[position-1], ""
+}
+
+func actionListingKey(input MessageInput) string {
+	connector := strings.ToLower(strings.TrimSpace(input.Connector))
+	externalID := strings.TrimSpace(input.ExternalID)
+	if connector == "" || externalID == "" {
+		return ""
+	}
+	return connector + "|" + externalID
+}
+
+// parseActionPosition accepts "2" or "#2". Positions are short numbers, which
+// keeps them distinct from action ids and pairing tokens.
+func parseActionPosition(value string) (int, bool) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if trimmed == "" || len(trimmed) > 3 {
+		return 0, false
+	}
+	position, err := strconv.Atoi(trimmed)
+	if err != nil || position < 1 {
+		return 0, false
+	}
+	return position, true
+}
+
+// actionApprovalButtons offers an Approve and a Deny button per listed
+// action, numbered like the listing. The buttons carry the action id, so
+// they keep working after the listing expires.
+func actionApprovalButtons(ids []string) []reply.Button {
+	buttons := make([]reply.Button, 0, 2*len(ids))
+	for index, id := range ids {
+		position := strconv.Itoa(index + 1)
+		buttons = append(buttons,
+			reply.Button{Label: "Approve " + position, Command: "/approve-action " + id, Style: reply.ButtonPrimary},
+			reply.Button{Label: "Deny " + position, Command: "/deny-action " + id, Style: reply.ButtonDanger},
+		)
+	}
+	return buttons
+}
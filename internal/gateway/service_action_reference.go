@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// pendingActionReferencePrefix marks an approve/deny argument that names a
+// pending action by description ("the curl one") instead of by id. The terms
+// follow the prefix joined by "+" so the argument stays a single word.
+const pendingActionReferencePrefix = "__pending_ref__:"
+
+// actionReferenceNouns end a descriptive reference such as "the email action".
+var actionReferenceNouns = map[string]bool{
+	"one":      true,
+	"action":   true,
+	"request":  true,
+	"approval": true,
+	"command":  true,
+}
+
+// actionReferenceFillers carry no information about which action is meant.
+var actionReferenceFillers = map[string]bool{
+	"pending": true,
+	"queued":  true,
+	"new":     true,
+	"other":   true,
+}
+
+// parseActionReference finds "the <terms> one|action|request|approval|command"
+// in text and returns the descriptive terms and the text after the reference.
+// References to recency ("the latest one") are left to the existing aliases.
+func parseActionReference(text string) ([]string, string, bool) {
+	fields := strings.Fields(text)
+	for start := 0; start < len(fields); start++ {
+		if cleanReferenceWord(fields[start]) != "the" {
+			continue
+		}
+		terms := []string{}
+	scan:
+		for end := start + 1; end < len(fields) && end <= start+4; end++ {
+			word := cleanReferenceWord(fields[end])
+			switch {
+			case actionReferenceNouns[word]:
+				if len(terms) == 0 {
+					break scan
+				}
+				return terms, strings.Join(fields[end+1:], " "), true
+			case word == "", word == "latest", word == "last", word == "newest", word == "recent", word == "most":
+				break scan
+			case !actionReferenceFillers[word]:
+				terms = append(terms, word)
+			}
+		}
+	}
+	return nil, "", false
+}
+
+func cleanReferenceWord(value string) string {
+	return strings.ToLower(strings.Trim(value, "`\"'.,:;!?()[]"))
+}
+
+func pendingActionReferenceArg(terms []string) string {
+	return pendingActionReferencePrefix + strings.Join(terms, "+")
+}
+
+func isPendingActionReference(actionID string) bool {
+	return strings.HasPrefix(strings.ToLower(actionID), pendingActionReferencePrefix)
+}
+
+// resolvePendingActionReference matches the reference terms against the type,
+// target and summary of the pending actions in this conversation, falling back
+// to all pending actions identity may decide. The second return value is a user-facing reply when
+// the reference does not pick out exactly one action.
+func (s *Service) resolvePendingActionReference(ctx context.Context, input MessageInput, identity store.UserIdentity, actionID string) (string, string) {
+	terms := strings.Split(strings.ToLower(actionID[len(pendingActionReferencePrefix):]), "+")
+	description := strings.Join(terms, " ")
+	items, err := s.store.ListPendingActionApprovals(ctx, input.Connector, input.ExternalID, 50)
+	if err != nil {
+		return "", "Unable to load pending actions right now."
+	}
+	if items, err = s.decidableActions(ctx, identity, items); err != nil {
+		return "", "Unable to load pending actions right now."
+	}
+	if len(items) == 0 {
+		items, err = s.store.ListPendingActionApprovalsGlobal(ctx, 50)
+		if err == nil {
+			items, err = s.decidableActions(ctx, identity, items)
+		}
+		if err != nil {
+			return "", "Unable to load pending actions right now."
+		}
+	}
+	if len(items) == 0 {
+		return "", "No pending actions."
+	}
+	matches := []string{}
+	lines := []string{}
+	for _, item := range items {
+		haystack := strings.ToLower(strings.Join([]string{
+			item.ActionType,
+			strings.ReplaceAll(item.ActionType, "_", " "),
+			item.ActionTarget,
+			item.ActionSummary,
+		}, " "))
+		matched := true
+		for _, term := range terms {
+			if term != "" && !strings.Contains(haystack, term) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		matches = append(matches, item.ID)
+		summary := strings.TrimSpace(item.ActionSummary)
+		if summary == "" {
+			summary = item.ActionType
+		}
+		lines = append(lines, fmt.Sprintf("- `%s` %s (%s)", item.ID, summary, item.ActionType))
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Sprintf("No pending action matches %q. Use `/pending-actions` to see what is waiting.", description)
+	case 1:
+		return matches[0], ""
+	default:
+		return "", fmt.Sprintf("Several pending actions match %q; approve or deny one by id:\n%s", description, strings.Join(lines, "\n"))
+	}
+}
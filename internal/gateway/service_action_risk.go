@@ -0,0 +1,23 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// formatActionRisk renders "high risk: destructive command rm" for the
+// pending-actions listing. Low risk only shows its level, and approvals
+// created without a classifier show nothing.
+func formatActionRisk(record store.ActionApproval) string {
+	level := store.NormalizeActionRisk(record.RiskLevel)
+	if level == "" {
+		return ""
+	}
+	reason := strings.TrimSpace(record.RiskReason)
+	if level == store.ActionRiskLow || reason == "" {
+		return level + " risk"
+	}
+	return fmt.Sprintf("%s risk: %s", level, reason)
+}
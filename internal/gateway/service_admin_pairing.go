@@ -7,20 +7,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/i18n"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
 func (s *Service) handleRouteOverride(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
-	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	_, denied, err := s.authorize(ctx, input, store.PermissionRouteTasks)
 	if err != nil {
-		if errors.Is(err, store.ErrIdentityNotFound) {
-			return MessageOutput{Handled: true, Reply: "Access denied: link your admin identity first."}, nil
-		}
 		return MessageOutput{}, err
 	}
-	if !isAdminRole(identity.Role) {
-		return MessageOutput{Handled: true, Reply: "Access denied: admin role required."}, nil
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
 	}
 	policy, err := s.store.LookupContextPolicyByExternal(ctx, input.Connector, input.ExternalID)
 	if err != nil {
@@ -32,7 +30,7 @@ func (s *Service) handleRouteOverride(ctx context.Context, input MessageInput, a
 
 	fields := strings.Fields(strings.TrimSpace(arg))
 	if len(fields) < 2 {
-		return MessageOutput{Handled: true, Reply: "Usage: /route <task-id> <question|issue|task|moderation|noise> [p1|p2|p3] [due-window like 2h or 1d]"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /route <task-id> <question|issue|task|moderation|noise> [p1|p2|p3] [due-window like 2h or 1d]")}, nil
 	}
 	taskID := strings.TrimSpace(fields[0])
 	taskRecord, err := s.store.LookupTask(ctx, taskID)
@@ -91,6 +89,7 @@ func (s *Service) handleRouteOverride(ctx context.Context, input MessageInput, a
 		}
 		return MessageOutput{}, err
 	}
+	s.syncTask(ctx, updated.ID)
 	reply := fmt.Sprintf("Routing updated for `%s`:\n- class: `%s`\n- priority: `%s`\n- lane: `%s`", updated.ID, class, priority, lane)
 	if !dueAt.IsZero() {
 		reply += fmt.Sprintf("\n- due: `%s`", dueAt.UTC().Format(time.RFC3339))
@@ -101,7 +100,13 @@ func (s *Service) handleRouteOverride(ctx context.Context, input MessageInput, a
 }
 
 func (s *Service) enqueueAndPersistTask(ctx context.Context, input store.CreateTaskInput) (orchestrator.Task, error) {
-	task, err := s.engine.Enqueue(orchestrator.Task{
+	return s.persistQueuedTask(ctx, input, s.engine.Enqueue)
+}
+
+// persistQueuedTask hands the task to queue and records it as queued under
+// the id the queue assigned.
+func (s *Service) persistQueuedTask(ctx context.Context, input store.CreateTaskInput, queue func(orchestrator.Task) (orchestrator.Task, error)) (orchestrator.Task, error) {
+	task, err := queue(orchestrator.Task{
 		WorkspaceID: strings.TrimSpace(input.WorkspaceID),
 		ContextID:   strings.TrimSpace(input.ContextID),
 		Kind:        orchestrator.TaskKind(strings.TrimSpace(input.Kind)),
@@ -128,18 +133,18 @@ func (s *Service) enqueueAndPersistTask(ctx context.Context, input store.CreateT
 
 func (s *Service) handleAdminChannel(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
 	if strings.ToLower(strings.TrimSpace(arg)) != "enable" {
-		return MessageOutput{Handled: true, Reply: "Usage: /admin-channel enable"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /admin-channel enable")}, nil
 	}
 
 	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
 	if err != nil {
 		if errors.Is(err, store.ErrIdentityNotFound) {
-			return MessageOutput{Handled: true, Reply: "Access denied: link your admin identity first."}, nil
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedLink)}, nil
 		}
 		return MessageOutput{}, err
 	}
 	if !isAdminRole(identity.Role) {
-		return MessageOutput{Handled: true, Reply: "Access denied: admin role required."}, nil
+		return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedAdmin)}, nil
 	}
 
 	contextRecord, err := s.store.SetContextAdminByExternal(ctx, input.Connector, input.ExternalID, true)
@@ -155,17 +160,17 @@ func (s *Service) handleAdminChannel(ctx context.Context, input MessageInput, ar
 func (s *Service) handleApprove(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
 	token := strings.TrimSpace(arg)
 	if token == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /approve <pairing-token>"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /approve <pairing-token>")}, nil
 	}
 	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
 	if err != nil {
 		if errors.Is(err, store.ErrIdentityNotFound) {
-			return MessageOutput{Handled: true, Reply: "Access denied: link your admin identity first."}, nil
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedLink)}, nil
 		}
 		return MessageOutput{}, err
 	}
 	if !isAdminRole(identity.Role) {
-		return MessageOutput{Handled: true, Reply: "Access denied: admin role required."}, nil
+		return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedAdmin)}, nil
 	}
 
 	result, err := s.store.ApprovePairing(ctx, store.ApprovePairingInput{
@@ -188,7 +193,7 @@ func (s *Service) handleApprove(ctx context.Context, input MessageInput, arg str
 func (s *Service) handleDeny(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
 	token := strings.TrimSpace(arg)
 	if token == "" {
-		return MessageOutput{Handled: true, Reply: "Usage: /deny <pairing-token> [reason]"}, nil
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /deny <pairing-token> [reason]")}, nil
 	}
 
 	parts := strings.Fields(token)
@@ -201,12 +206,12 @@ func (s *Service) handleDeny(ctx context.Context, input MessageInput, arg string
 	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
 	if err != nil {
 		if errors.Is(err, store.ErrIdentityNotFound) {
-			return MessageOutput{Handled: true, Reply: "Access denied: link your admin identity first."}, nil
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedLink)}, nil
 		}
 		return MessageOutput{}, err
 	}
 	if !isAdminRole(identity.Role) {
-		return MessageOutput{Handled: true, Reply: "Access denied: admin role required."}, nil
+		return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedAdmin)}, nil
 	}
 
 	request, err := s.store.DenyPairing(ctx, store.DenyPairingInput{
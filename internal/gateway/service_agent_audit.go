@@ -38,6 +38,7 @@ func (s *Service) appendAgentToolCallLogs(contextRecord store.ContextRecord, inp
 			WorkspaceID:   workspaceID,
 			Connector:     connector,
 			ExternalID:    externalID,
+			ThreadID:      input.ThreadID,
 			Direction:     "tool",
 			ActorID:       "agent-runtime",
 			DisplayName:   displayName,
@@ -87,6 +88,10 @@ func truncateToolLogField(input string, maxLen int) string {
 	return strings.TrimSpace(value[:maxLen]) + "..."
 }
 
+// narrativeResultDataMaxBytes bounds the structured task data included in the
+// completion narration prompt.
+const narrativeResultDataMaxBytes = 6000
+
 func (s *Service) NarrateTaskResult(ctx context.Context, connector, externalID string, task orchestrator.Task, result orchestrator.TaskResult) (string, error) {
 	if s.agent == nil {
 		return "", fmt.Errorf("agent not configured")
@@ -103,9 +108,20 @@ func (s *Service) NarrateTaskResult(ctx context.Context, connector, externalID s
 		"BACKGROUND TASK FINISHED\nTask: %s\nResult: %s\n\nExplain this result to the user naturally and decide if any follow-up actions are needed.",
 		task.Title, result.Summary,
 	)
+	if len(result.Artifacts) > 0 {
+		lines := []string{"\n\nFiles the task produced (they are listed under your reply, so do not repeat links or paths):"}
+		for _, artifact := range result.Artifacts {
+			lines = append(lines, fmt.Sprintf("- %s (%s, %s)", artifact.Title, artifact.Kind, artifact.MediaType))
+		}
+		narrativePrompt += strings.Join(lines, "\n")
+	}
+	if len(result.Data) > 0 {
+		narrativePrompt += "\n\nStructured tool results (JSON; render tables and links from these instead of re-reading prose):\n" +
+			truncateToolLogField(string(result.Data), narrativeResultDataMaxBytes)
+	}
 
 	// 3. Execute Agent turn
-	agentCtx := context.WithValue(ctx, ContextKeyRecord, contextRecord)
+	agentCtx := withContextRecord(ctx, contextRecord)
 	agentCtx = context.WithValue(agentCtx, ContextKeyInput, MessageInput{
 		Connector:  connector,
 		ExternalID: externalID,
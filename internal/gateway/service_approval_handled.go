@@ -0,0 +1,32 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// actionAlreadyHandledReply explains why an approval can no longer be acted
+// on. The same notice is mirrored to every admin channel, so the admin is
+// usually pressing a button another admin already answered elsewhere.
+func (s *Service) actionAlreadyHandledReply(ctx context.Context, actionID string) string {
+	record, err := s.store.LookupActionApproval(ctx, strings.TrimSpace(actionID))
+	if err != nil {
+		return i18n.T(ctx, i18n.ActionNotPending)
+	}
+	approver := strings.TrimSpace(record.ApproverUserID)
+	switch {
+	case record.Status == "pending":
+		// Still pending but refused: the approval window has passed.
+		return fmt.Sprintf("Action `%s` has expired and can no longer be approved.", record.ID)
+	case approver == store.ExpiredApprovalUserID:
+		return fmt.Sprintf("Action `%s` already expired without a decision.", record.ID)
+	case approver == "":
+		return fmt.Sprintf("Action `%s` was already %s.", record.ID, record.Status)
+	default:
+		return fmt.Sprintf("Action `%s` was already %s by `%s`.", record.ID, record.Status, approver)
+	}
+}
@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// isApprovalQuorumError reports whether an approval was not final because the
+// action still needs sign-off from other admins.
+func isApprovalQuorumError(err error) bool {
+	return errors.Is(err, store.ErrActionApprovalQuorumPending) || errors.Is(err, store.ErrActionApprovalAlreadySigned)
+}
+
+// formatApprovalQuorumReply tells an admin where a multi-approver action
+// stands after their approval.
+func formatApprovalQuorumReply(record store.ActionApproval, err error) string {
+	progress := fmt.Sprintf("%d of %d", len(record.Signoffs), record.RequiredApprovals)
+	if errors.Is(err, store.ErrActionApprovalAlreadySigned) {
+		return fmt.Sprintf("You already approved `%s` (%s). It needs another admin's approval.", record.ID, progress)
+	}
+	return fmt.Sprintf("Recorded your approval for `%s` (%s). Waiting for another admin.", record.ID, progress)
+}
+
+// quorumPendingToolReply turns a refused auto-approval into a tool result:
+// actions under the two-person rule wait for admins instead of failing.
+func quorumPendingToolReply(record store.ActionApproval, err error) (string, bool) {
+	if !errors.Is(err, store.ErrActionApprovalQuorumPending) || strings.TrimSpace(record.ID) == "" {
+		return "", false
+	}
+	return fmt.Sprintf(
+		"Action request created: %s. It needs %d admin approvals before it runs; ask admins to reply 'approve %s'.",
+		record.ID,
+		record.RequiredApprovals,
+		record.ID,
+	), true
+}
+
+// formatApprovalProgress renders "1/2 approvals" for actions that need more
+// than one admin, and nothing otherwise.
+func formatApprovalProgress(record store.ActionApproval) string {
+	if record.RequiredApprovals < 2 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d approvals", len(record.Signoffs), record.RequiredApprovals)
+}
@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	auditUsage        = "Usage: /audit [--type <event>] [--tool <name>] [--user <id>] [--blocked] [--since 2h] [--context this] [--limit n]"
+	auditDefaultLimit = 10
+	auditMaxLimit     = 25
+)
+
+// AuditReader lists agent audit events.
+type AuditReader interface {
+	ListAgentAuditEvents(ctx context.Context, input store.ListAgentAuditEventsInput) ([]store.AgentAuditEvent, error)
+}
+
+// SetAuditReader enables /audit.
+func (s *Service) SetAuditReader(reader AuditReader) {
+	s.auditReader = reader
+}
+
+// handleAudit lists recent audit events of the channel's workspace for
+// users with read_audit.
+func (s *Service) handleAudit(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if s.auditReader == nil {
+		return MessageOutput{Handled: true, Reply: "The audit trail is unavailable in this runtime."}, nil
+	}
+	_, denied, err := s.authorize(ctx, input, store.PermissionReadAudit)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
+	}
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	query, thisContext, err := parseAuditFilter(arg)
+	if err != nil {
+		return MessageOutput{Handled: true, Reply: err.Error() + "\n" + localizedUsage(ctx, auditUsage)}, nil
+	}
+	query.WorkspaceID = contextRecord.WorkspaceID
+	if thisContext {
+		query.ContextID = contextRecord.ID
+	}
+	events, err := s.auditReader.ListAgentAuditEvents(ctx, query)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	return MessageOutput{Handled: true, Reply: formatAuditEvents(events)}, nil
+}
+
+// parseAuditFilter reads /audit flags. --blocked takes no value; the other
+// flags take one, as `--flag value` or `--flag=value`.
+func parseAuditFilter(arg string) (store.ListAgentAuditEventsInput, bool, error) {
+	query := store.ListAgentAuditEventsInput{Limit: auditDefaultLimit}
+	thisContext := false
+	fields := strings.Fields(normalizeFilterDashes(arg))
+	for index := 0; index < len(fields); index++ {
+		field := fields[index]
+		if !strings.HasPrefix(field, "--") {
+			return query, false, fmt.Errorf("unexpected %q", field)
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(field, "--"), "=")
+		name = strings.ToLower(name)
+		if name == "blocked" {
+			query.BlockedOnly = true
+			continue
+		}
+		if !hasValue {
+			if index+1 >= len(fields) {
+				return query, false, fmt.Errorf("--%s needs a value", name)
+			}
+			index++
+			value = fields[index]
+		}
+		value = strings.Trim(strings.TrimSpace(value), "`\"'")
+		switch name {
+		case "type":
+			query.EventType = value
+		case "tool":
+			query.ToolName = value
+		case "user":
+			query.SourceUserID = normalizeMemberID(value)
+		case "since":
+			age, err := parseFilterAge(value)
+			if err != nil {
+				return query, false, fmt.Errorf("--since needs a duration such as 30m, 1h or 2d")
+			}
+			query.Since = time.Now().UTC().Add(-age)
+		case "context":
+			switch strings.ToLower(value) {
+			case "this", "here":
+				thisContext = true
+			case "all", "any":
+				thisContext = false
+			default:
+				return query, false, fmt.Errorf("--context must be this or all")
+			}
+		case "limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 1 || limit > auditMaxLimit {
+				return query, false, fmt.Errorf("--limit must be between 1 and %d", auditMaxLimit)
+			}
+			query.Limit = limit
+		default:
+			return query, false, fmt.Errorf("unknown filter --%s", name)
+		}
+	}
+	return query, thisContext, nil
+}
+
+func formatAuditEvents(events []store.AgentAuditEvent) string {
+	if len(events) == 0 {
+		return "No audit events match."
+	}
+	lines := []string{fmt.Sprintf("Audit events (newest first): %d", len(events))}
+	for _, event := range events {
+		line := fmt.Sprintf("- %s `%s`", event.CreatedAt.UTC().Format("2006-01-02 15:04"), event.EventType)
+		if event.ToolName != "" {
+			line += " " + event.ToolName
+		}
+		if event.Blocked {
+			line += " (blocked"
+			if event.BlockReason != "" {
+				line += ": " + truncateToolLogField(event.BlockReason, 120)
+			}
+			line += ")"
+		}
+		if event.SourceUserID != "" {
+			line += " by " + event.SourceUserID
+		}
+		line += fmt.Sprintf(" in %s/%s", event.Connector, event.ExternalID)
+		lines = append(lines, line)
+	}
+	lines = append(lines, "Export the full trail with `agent-runtime audit export`.")
+	return strings.Join(lines, "\n")
+}
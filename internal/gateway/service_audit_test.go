@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeAuditReader struct {
+	query  store.ListAgentAuditEventsInput
+	events []store.AgentAuditEvent
+}
+
+func (f *fakeAuditReader) ListAgentAuditEvents(ctx context.Context, input store.ListAgentAuditEventsInput) ([]store.AgentAuditEvent, error) {
+	f.query = input
+	return f.events, nil
+}
+
+func TestAuditCommandListsWorkspaceEvents(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	reader := &fakeAuditReader{events: []store.AgentAuditEvent{{
+		EventType:    "egress_blocked",
+		ToolName:     "curl",
+		Blocked:      true,
+		BlockReason:  "address 10.0.0.1 is private",
+		SourceUserID: "u1",
+		Connector:    "discord",
+		ExternalID:   "chan-1",
+		CreatedAt:    time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC),
+	}}}
+	service.SetAuditReader(reader)
+	send := func(text string) string {
+		output, err := service.HandleMessage(context.Background(), MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "admin-1", Text: text})
+		if err != nil {
+			t.Fatalf("%s failed: %v", text, err)
+		}
+		return output.Reply
+	}
+
+	reply := send("/audit --tool curl --blocked --context this --limit 5")
+	if !strings.Contains(reply, "- 2026-10-17 09:30 `egress_blocked` curl (blocked: address 10.0.0.1 is private) by u1 in discord/chan-1") {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	query := reader.query
+	if query.WorkspaceID != "ws-1" || query.ContextID != "ctx-1" || query.ToolName != "curl" || !query.BlockedOnly || query.Limit != 5 {
+		t.Fatalf("unexpected query %+v", query)
+	}
+	if reply := send("/audit --limit 500"); !strings.Contains(reply, "--limit must be between 1 and 25") {
+		t.Fatalf("expected a limit error, got %q", reply)
+	}
+
+	fStore.identity.Role = "member"
+	if reply := send("/audit"); reply != "Access denied: read_audit permission required." {
+		t.Fatalf("expected members refused, got %q", reply)
+	}
+}
@@ -0,0 +1,49 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+// queueBusyNotice is added to replies that queue work while the task queue
+// is under backpressure.
+const queueBusyNotice = "The task queue is busy right now, so expect delays."
+
+// Backpressure reports whether the task queue is busy and holds
+// low-priority tasks until it drains.
+type Backpressure interface {
+	UnderPressure() bool
+	Hold(task orchestrator.Task) (orchestrator.Task, error)
+}
+
+// SetBackpressure makes auto-routing slow down while the task queue is
+// busy: acknowledgements say to expect delays and new p3 tasks wait until
+// the queue drains.
+func (s *Service) SetBackpressure(backpressure Backpressure) {
+	s.backpressure = backpressure
+}
+
+func (s *Service) queueBusy() bool {
+	return s.backpressure != nil && s.backpressure.UnderPressure()
+}
+
+// routedTaskQueue picks where an auto-routed task goes: p3 work is held
+// while the queue is busy, everything else is queued as usual.
+func (s *Service) routedTaskQueue(busy bool, priority TriagePriority) func(orchestrator.Task) (orchestrator.Task, error) {
+	if busy && priority == TriagePriorityP3 {
+		return s.backpressure.Hold
+	}
+	return s.engine.Enqueue
+}
+
+// busyAutoTriageAck replaces the model-written acknowledgement while the
+// queue is busy, so no model call is spent on it.
+func busyAutoTriageAck(ctx context.Context, priority TriagePriority) string {
+	notice := i18n.T(ctx, i18n.QueueBusy)
+	if priority == TriagePriorityP3 {
+		return i18n.T(ctx, i18n.TriageAckBusyLow, notice)
+	}
+	return i18n.T(ctx, i18n.TriageAckBusy, notice)
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+type fakeBackpressure struct {
+	busy bool
+	held []orchestrator.Task
+}
+
+func (f *fakeBackpressure) UnderPressure() bool {
+	return f.busy
+}
+
+func (f *fakeBackpressure) Hold(task orchestrator.Task) (orchestrator.Task, error) {
+	task.ID = "held-1"
+	f.held = append(f.held, task)
+	return task, nil
+}
+
+func TestHandleAutoTriageHoldsLowPriorityTasksWhileQueueIsBusy(t *testing.T) {
+	fStore := &fakeStore{}
+	fEngine := &fakeEngine{}
+	backpressure := &fakeBackpressure{busy: true}
+	service := New(fStore, fEngine, nil, nil, "", nil)
+	service.SetBackpressure(backpressure)
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "can you run a search in dwizi.com and tell me pricing?",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if len(backpressure.held) != 1 || fEngine.lastTask.Prompt != "" {
+		t.Fatalf("expected the p3 task held instead of queued, held=%d queued=%+v", len(backpressure.held), fEngine.lastTask)
+	}
+	if fStore.lastTask.ID != "held-1" || fStore.lastTask.Priority != "p3" {
+		t.Fatalf("expected the held task persisted, got %+v", fStore.lastTask)
+	}
+	if !strings.Contains(output.Reply, queueBusyNotice) {
+		t.Fatalf("expected a busy acknowledgement, got %q", output.Reply)
+	}
+
+	output, err = service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "There is a bug in the onboarding flow and it keeps failing",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if len(backpressure.held) != 1 || fEngine.lastTask.Prompt == "" {
+		t.Fatal("expected the p2 issue queued right away")
+	}
+	if !strings.Contains(output.Reply, queueBusyNotice) {
+		t.Fatalf("expected a busy acknowledgement, got %q", output.Reply)
+	}
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// CaseStore opens cases grouping the records of an incident or moderation
+// matter.
+type CaseStore interface {
+	CreateCase(ctx context.Context, input store.CreateCaseInput) (store.Case, error)
+}
+
+// SetCaseStore opens a case for every incident (a p1 issue) and moderation
+// decision, linking the routed task, the moderation case and the message.
+func (s *Service) SetCaseStore(cases CaseStore) {
+	s.cases = cases
+}
+
+// openDecisionCase opens a case for decision when it is an incident or a
+// moderation matter and returns its id, or "" otherwise.
+func (s *Service) openDecisionCase(ctx context.Context, decision RouteDecision) string {
+	if s.cases == nil {
+		return ""
+	}
+	kind, prefix := "", ""
+	switch {
+	case decision.Class == TriageModeration:
+		kind, prefix = store.CaseKindModeration, "[MODERATION]"
+	case decision.Class == TriageIssue && decision.Priority == TriagePriorityP1:
+		kind, prefix = store.CaseKindIncident, "[INCIDENT]"
+	default:
+		return ""
+	}
+	title := strings.TrimSpace(prefix + " " + compactSnippet(decision.SourceText))
+	if len(title) > 72 {
+		title = title[:72]
+	}
+	items := []store.AddCaseItemInput{}
+	if taskID := strings.TrimSpace(decision.TaskID); taskID != "" {
+		items = append(items, store.AddCaseItemInput{Kind: store.CaseItemTask, ItemID: taskID})
+	}
+	if caseID := strings.TrimSpace(decision.CaseID); caseID != "" {
+		items = append(items, store.AddCaseItemInput{Kind: store.CaseItemModeration, ItemID: caseID})
+	}
+	if text := strings.TrimSpace(decision.SourceText); text != "" {
+		items = append(items, store.AddCaseItemInput{
+			Kind:    store.CaseItemChat,
+			Excerpt: decision.SourceConnector + " user " + decision.SourceUserID + " in " + decision.SourceExternalID + ": " + text,
+		})
+	}
+	record, err := s.cases.CreateCase(ctx, store.CreateCaseInput{
+		WorkspaceID: decision.WorkspaceID,
+		ContextID:   decision.ContextID,
+		Kind:        kind,
+		Title:       title,
+		Summary:     decision.Reason,
+		Items:       items,
+	})
+	if err != nil {
+		s.logger.Error("open case failed", "workspace_id", decision.WorkspaceID, "error", err)
+		return ""
+	}
+	return record.ID
+}
@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/degrade"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/google/uuid"
+)
+
+// degradedFAQRelPath holds the curated answers served while the model is
+// down; workspace botfiles render their faq entries there.
+const degradedFAQRelPath = "context/FAQ.md"
+
+// Degradation reports whether the model provider is reachable and holds
+// tasks accepted while it is not.
+type Degradation interface {
+	Available() bool
+	Defer(task orchestrator.Task)
+}
+
+// SetDegradation enables degraded replies while the model provider is down.
+func (s *Service) SetDegradation(degradation Degradation) {
+	s.degradation = degradation
+}
+
+func (s *Service) modelUnavailable() bool {
+	return s.degradation != nil && !s.degradation.Available()
+}
+
+// handleDegradedMessage answers a message without the model: a matching
+// curated FAQ entry is sent as is, anything else that is not chatter is
+// accepted as a task that runs once the provider is back.
+func (s *Service) handleDegradedMessage(ctx context.Context, input MessageInput, text string) (MessageOutput, error) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || strings.HasPrefix(trimmed, "/") || s.store == nil {
+		return MessageOutput{}, nil
+	}
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if entry, ok := degrade.MatchFAQ(s.workspaceFAQ(contextRecord.WorkspaceID), trimmed); ok {
+		return MessageOutput{Handled: true, Reply: entry.Answer}, nil
+	}
+	decision := deriveRouteDecision(input, contextRecord.WorkspaceID, contextRecord.ID, trimmed)
+	if decision.Class == TriageNoise {
+		return MessageOutput{}, nil
+	}
+	task := orchestrator.Task{
+		ID:          "task-" + uuid.NewString(),
+		WorkspaceID: decision.WorkspaceID,
+		ContextID:   decision.ContextID,
+		Kind:        orchestrator.TaskKindGeneral,
+		Title:       buildRoutedTaskTitle(decision.Class, decision.SourceText),
+		Prompt:      buildRoutedTaskPrompt(decision),
+	}
+	if err := s.store.CreateTask(ctx, store.CreateTaskInput{
+		ID:               task.ID,
+		WorkspaceID:      task.WorkspaceID,
+		ContextID:        task.ContextID,
+		Kind:             string(task.Kind),
+		Title:            task.Title,
+		Prompt:           task.Prompt,
+		Status:           "queued",
+		RouteClass:       string(decision.Class),
+		Priority:         string(decision.Priority),
+		DueAt:            decision.DueAt,
+		AssignedLane:     decision.AssignedLane,
+		SourceConnector:  decision.SourceConnector,
+		SourceExternalID: decision.SourceExternalID,
+		SourceUserID:     decision.SourceUserID,
+		SourceText:       decision.SourceText,
+	}); err != nil {
+		return MessageOutput{}, err
+	}
+	s.degradation.Defer(task)
+	decision.TaskID = task.ID
+	if decision.Class == TriageModeration {
+		decision.CaseID = s.recordModerationDecision(ctx, decision, "flagged")
+	}
+	decision.TrackingCaseID = s.openDecisionCase(ctx, decision)
+	if s.routingNotify != nil {
+		s.routingNotify.NotifyRoutingDecision(ctx, decision)
+	}
+	s.syncTask(ctx, task.ID)
+	return MessageOutput{
+		Handled: true,
+		Reply:   fmt.Sprintf("I can't reach my language model right now, so I queued this as task `%s` and will reply here once it is back.", task.ID),
+	}, nil
+}
+
+func (s *Service) workspaceFAQ(workspaceID string) []degrade.FAQEntry {
+	if strings.TrimSpace(s.workspaceRoot) == "" || strings.TrimSpace(workspaceID) == "" {
+		return nil
+	}
+	content, err := os.ReadFile(filepath.Join(s.workspaceRoot, workspaceID, filepath.FromSlash(degradedFAQRelPath)))
+	if err != nil {
+		return nil
+	}
+	return degrade.ParseFAQ(string(content))
+}
@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent"
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func (s *Service) handleExplain(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	request := strings.TrimSpace(arg)
+	if request == "" {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /explain <request>")}, nil
+	}
+	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrIdentityNotFound) {
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedLink)}, nil
+		}
+		return MessageOutput{}, err
+	}
+	if !isAdminRole(identity.Role) {
+		return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedAdmin)}, nil
+	}
+	if s.agent == nil {
+		return MessageOutput{Handled: true, Reply: "Explain mode is unavailable: no LLM is configured on this runtime."}, nil
+	}
+
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	agentCtx := withContextRecord(ctx, contextRecord)
+	agentCtx = context.WithValue(agentCtx, ContextKeyInput, input)
+	agentCtx = agent.WithExplainMode(agentCtx)
+	result := s.agent.Execute(agentCtx, llm.MessageInput{
+		Connector:   strings.TrimSpace(input.Connector),
+		WorkspaceID: strings.TrimSpace(contextRecord.WorkspaceID),
+		ContextID:   strings.TrimSpace(contextRecord.ID),
+		ExternalID:  strings.TrimSpace(input.ExternalID),
+		ThreadID:    strings.TrimSpace(input.ThreadID),
+		DisplayName: strings.TrimSpace(input.DisplayName),
+		FromUserID:  strings.TrimSpace(input.FromUserID),
+		Text:        request,
+	})
+	return MessageOutput{Handled: true, Reply: formatExplainReply(result)}, nil
+}
+
+func formatExplainReply(result agent.Result) string {
+	lines := []string{"Explain mode (no tools were executed)."}
+	if len(result.ToolCalls) == 0 {
+		lines = append(lines, "Planned tool calls: none")
+	} else {
+		lines = append(lines, "Planned tool calls:")
+		for index, call := range result.ToolCalls {
+			line := fmt.Sprintf("%d. `%s`", index+1, strings.TrimSpace(call.ToolName))
+			if args := strings.TrimSpace(call.ToolArgs); args != "" && args != "{}" {
+				line += fmt.Sprintf(" args=`%s`", truncateToolLogField(args, 300))
+			}
+			lines = append(lines, line)
+			if reason := strings.TrimSpace(call.Reason); reason != "" {
+				lines = append(lines, "   why: "+reason)
+			}
+			switch strings.TrimSpace(call.Status) {
+			case "simulated":
+				if strings.Contains(call.ToolOutput, "approval") {
+					lines = append(lines, "   note: requires admin approval before running")
+				}
+			case "blocked", "failed":
+				if errText := strings.TrimSpace(call.Error); errText != "" {
+					lines = append(lines, "   blocked: "+truncateToolLogField(errText, 200))
+				}
+			}
+		}
+	}
+	if result.Error != nil {
+		lines = append(lines, "Outcome: preview failed: "+truncateToolLogField(result.Error.Error(), 200))
+		return strings.Join(lines, "\n")
+	}
+	if result.Blocked && strings.TrimSpace(result.BlockReason) != "" {
+		lines = append(lines, "Stopped: "+strings.TrimSpace(result.BlockReason))
+	}
+	if reply := strings.TrimSpace(result.Reply); reply != "" {
+		lines = append(lines, "", "Plan summary:", reply)
+	}
+	return strings.Join(lines, "\n")
+}
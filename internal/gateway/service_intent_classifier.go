@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+)
+
+const (
+	// intentClassifierMaxChars skips the model for longer messages, which
+	// are conversation rather than commands.
+	intentClassifierMaxChars = 500
+	intentClassifierTimeout  = 10 * time.Second
+	// defaultIntentMinConfidence applies when SetIntentClassifier gets a
+	// threshold outside (0, 1].
+	defaultIntentMinConfidence = 0.7
+)
+
+type intentCommand struct {
+	usage    string
+	needsArg bool
+}
+
+// classifiedIntentCommands are the commands the intent classifier may pick.
+// Approvals, denials, pairing and admin channel changes stay with the phrase
+// parser, so a loose reading of a message can never approve or deny anything.
+var classifiedIntentCommands = map[string]intentCommand{
+	"task":            {usage: "queue a background task; arg is what to do", needsArg: true},
+	"search":          {usage: "search the workspace knowledge; arg is the query", needsArg: true},
+	"open":            {usage: "open a workspace document; arg is its path or name", needsArg: true},
+	"status":          {usage: "show the runtime status; no arg"},
+	"monitor":         {usage: "keep checking something on a schedule and report changes; arg is what to watch", needsArg: true},
+	"pending-actions": {usage: "list actions waiting for approval; no arg"},
+}
+
+// intentClassifierSystemPrompt is sent with every classification call.
+const intentClassifierSystemPrompt = "You map chat messages in any language to bot commands. Answer with one JSON object and nothing else."
+
+type intentClassifier struct {
+	responder     llm.Responder
+	minConfidence float64
+	timeout       time.Duration
+}
+
+type classifiedIntent struct {
+	Command    string  `json:"command"`
+	Arg        string  `json:"arg"`
+	Confidence float64 `json:"confidence"`
+}
+
+// SetIntentClassifier has a model classify messages that are not slash
+// commands before the English phrase parser runs. Classifications below
+// minConfidence, unknown commands and model errors fall back to the parser.
+// A nil responder turns the classifier off.
+func (s *Service) SetIntentClassifier(responder llm.Responder, minConfidence float64) {
+	if responder == nil {
+		s.intentClassifier = nil
+		return
+	}
+	if minConfidence <= 0 || minConfidence > 1 {
+		minConfidence = defaultIntentMinConfidence
+	}
+	s.intentClassifier = &intentClassifier{
+		responder:     responder,
+		minConfidence: minConfidence,
+		timeout:       intentClassifierTimeout,
+	}
+}
+
+// parseCommandIntent maps a plain message to a command, asking the intent
+// classifier first when one is set and the phrase parser otherwise.
+func (s *Service) parseCommandIntent(ctx context.Context, input MessageInput, text string) (string, string, bool) {
+	if command, arg, ok := s.classifyIntent(ctx, input, text); ok {
+		return command, arg, true
+	}
+	return parseNaturalLanguageCommand(text)
+}
+
+func (s *Service) classifyIntent(ctx context.Context, input MessageInput, text string) (string, string, bool) {
+	classifier := s.intentClassifier
+	trimmed := strings.TrimSpace(text)
+	if classifier == nil || trimmed == "" || len(trimmed) > intentClassifierMaxChars {
+		return "", "", false
+	}
+	modelCtx, cancel := context.WithTimeout(ctx, classifier.timeout)
+	defer cancel()
+	reply, err := classifier.responder.Reply(modelCtx, llm.MessageInput{
+		Connector:     input.Connector,
+		ExternalID:    input.ExternalID,
+		FromUserID:    input.FromUserID,
+		Text:          buildIntentClassifierPrompt(trimmed),
+		SystemPrompt:  intentClassifierSystemPrompt,
+		SkipGrounding: true,
+	})
+	if err != nil {
+		s.logger.Warn("intent classification failed, using phrase parser", "connector", input.Connector, "error", err)
+		return "", "", false
+	}
+	intent, err := parseClassifiedIntent(reply)
+	if err != nil {
+		s.logger.Warn("intent classification unreadable, using phrase parser", "connector", input.Connector, "error", err)
+		return "", "", false
+	}
+	if intent.Command == "" || intent.Confidence < classifier.minConfidence {
+		s.logger.Debug("intent classification below threshold", "command", intent.Command, "confidence", intent.Confidence)
+		return "", "", false
+	}
+	s.logger.Debug("intent classified", "command", intent.Command, "confidence", intent.Confidence)
+	return intent.Command, intent.Arg, true
+}
+
+func buildIntentClassifierPrompt(text string) string {
+	names := make([]string, 0, len(classifiedIntentCommands))
+	for name := range classifiedIntentCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{
+		"Decide whether this chat message asks the bot to run one of these commands:",
+	}
+	for _, name := range names {
+		lines = append(lines, "- "+name+": "+classifiedIntentCommands[name].usage)
+	}
+	lines = append(lines,
+		"",
+		`Reply as {"command":"<name or none>","arg":"<argument>","confidence":<0 to 1>}.`,
+		"Use none for questions, chatter, approvals, denials and anything else not listed.",
+		"Keep the arg in the language of the message.",
+		"",
+		"Message:",
+		text,
+	)
+	return strings.Join(lines, "\n")
+}
+
+// parseClassifiedIntent reads the model's JSON reply, tolerating code fences
+// and text around the object. A "none" or unknown command, or a command
+// missing the argument it needs, yields an empty Command.
+func parseClassifiedIntent(reply string) (classifiedIntent, error) {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return classifiedIntent{}, fmt.Errorf("intent reply has no JSON object")
+	}
+	var intent classifiedIntent
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &intent); err != nil {
+		return classifiedIntent{}, fmt.Errorf("parse intent reply: %w", err)
+	}
+	intent.Command = NormalizeCommandName(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(intent.Command)), "/"))
+	intent.Arg = strings.TrimSpace(intent.Arg)
+	command, ok := classifiedIntentCommands[intent.Command]
+	if !ok || (command.needsArg && intent.Arg == "") {
+		return classifiedIntent{Confidence: intent.Confidence}, nil
+	}
+	if !command.needsArg {
+		intent.Arg = ""
+	}
+	return intent, nil
+}
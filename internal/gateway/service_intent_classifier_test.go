@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/qmd"
+)
+
+func TestIntentClassifierRoutesNonEnglishMessages(t *testing.T) {
+	fStore := &fakeStore{}
+	classifier := &fakeTriageAcknowledger{reply: "```json\n{\"command\":\"task\",\"arg\":\"revisar las facturas de octubre\",\"confidence\":0.92}\n```"}
+	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
+	service.SetIntentClassifier(classifier, 0.7)
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "crea una tarea para revisar las facturas de octubre",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if !output.Handled || !strings.HasPrefix(output.Reply, "Task queued") {
+		t.Fatalf("expected the classified task queued, got %+v", output)
+	}
+	if fStore.lastTask.Prompt != "revisar las facturas de octubre" {
+		t.Fatalf("unexpected task prompt %q", fStore.lastTask.Prompt)
+	}
+	if !classifier.lastInput.SkipGrounding || !strings.HasSuffix(classifier.lastInput.Text, "crea una tarea para revisar las facturas de octubre") {
+		t.Fatalf("unexpected classifier input %+v", classifier.lastInput)
+	}
+}
+
+func TestIntentClassifierFallsBackToPhraseParser(t *testing.T) {
+	cases := []struct {
+		name  string
+		reply string
+		err   error
+	}{
+		{name: "low confidence", reply: `{"command":"status","arg":"","confidence":0.4}`},
+		{name: "approvals are not classified", reply: `{"command":"approve-action","arg":"","confidence":0.99}`},
+		{name: "missing argument", reply: `{"command":"task","arg":"","confidence":0.95}`},
+		{name: "unreadable reply", reply: "It looks like a search."},
+		{name: "model error", err: errors.New("provider down")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := New(&fakeStore{}, &fakeEngine{}, &fakeRetriever{
+				searchResults: []qmd.SearchResult{{Path: "memory.md", Score: 0.8, Snippet: "Recent decisions"}},
+			}, nil, "", nil)
+			service.SetIntentClassifier(&fakeTriageAcknowledger{reply: tc.reply, err: tc.err}, 0.7)
+			output, err := service.HandleMessage(context.Background(), MessageInput{
+				Connector:  "telegram",
+				ExternalID: "42",
+				Text:       "search for recent decisions",
+			})
+			if err != nil {
+				t.Fatalf("handle message failed: %v", err)
+			}
+			if !output.Handled || !strings.Contains(output.Reply, "memory.md") {
+				t.Fatalf("expected the phrase parser's search, got %+v", output)
+			}
+		})
+	}
+}
+
+func TestIntentClassifierSkipsLongMessages(t *testing.T) {
+	classifier := &fakeTriageAcknowledger{reply: `{"command":"status","confidence":1}`}
+	service := New(&fakeStore{}, &fakeEngine{}, nil, nil, "", nil)
+	service.SetIntentClassifier(classifier, 0)
+	if _, _, ok := service.classifyIntent(context.Background(), MessageInput{}, strings.Repeat("word ", 200)); ok {
+		t.Fatal("expected long messages left to the phrase parser")
+	}
+	if classifier.callCount != 0 {
+		t.Fatalf("expected no model call for a long message, got %d", classifier.callCount)
+	}
+	if service.intentClassifier.minConfidence != defaultIntentMinConfidence {
+		t.Fatalf("expected the default threshold for 0, got %v", service.intentClassifier.minConfidence)
+	}
+}
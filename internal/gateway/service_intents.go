@@ -79,9 +79,15 @@ func parseApproveCommandAsActionArg(arg string) (string, bool) {
 	if lower == "all" || lower == "everything" {
 		return allPendingActionsAlias, true
 	}
+	if _, ok := parseActionPosition(trimmed); ok {
+		return trimmed, true
+	}
 	if actionID, ok := findActionID(trimmed); ok {
 		return actionID, true
 	}
+	if terms, _, ok := parseActionReference(trimmed); ok {
+		return pendingActionReferenceArg(terms), true
+	}
 	if lower == "it" || lower == "this" || lower == "that" || lower == "action" {
 		return latestPendingActionAlias, true
 	}
@@ -100,6 +106,15 @@ func parseDenyCommandAsActionArg(arg string) (string, bool) {
 	if lower == "all" || lower == "everything" {
 		return allPendingActionsAlias, true
 	}
+	if fields := strings.Fields(trimmed); len(fields) > 0 {
+		if _, ok := parseActionPosition(fields[0]); ok {
+			reason := normalizeDenyReason(strings.TrimSpace(trimmed[len(fields[0]):]))
+			if reason == "" {
+				return fields[0], true
+			}
+			return fields[0] + " " + reason, true
+		}
+	}
 	if actionID, _, end, ok := findActionIDWithBounds(trimmed); ok {
 		reason := strings.TrimSpace(trimmed[end:])
 		reason = normalizeDenyReason(reason)
@@ -108,6 +123,13 @@ func parseDenyCommandAsActionArg(arg string) (string, bool) {
 		}
 		return actionID + " " + reason, true
 	}
+	if terms, rest, ok := parseActionReference(trimmed); ok {
+		reason := normalizeDenyReason(rest)
+		if reason == "" {
+			return pendingActionReferenceArg(terms), true
+		}
+		return pendingActionReferenceArg(terms) + " " + reason, true
+	}
 	if lower == "it" || lower == "this" || lower == "that" || strings.HasPrefix(lower, "it ") ||
 		strings.HasPrefix(lower, "this ") || strings.HasPrefix(lower, "that ") || strings.Contains(lower, "action") {
 		reason := trimmed
@@ -145,6 +167,9 @@ func parseNaturalLanguageCommand(text string) (command, arg string, ok bool) {
 	if actionArg, found := parseIntentDenyAction(trimmed); found {
 		return "deny-action", actionArg, true
 	}
+	if command, actionArg, found := parseIntentActionReference(trimmed, lower); found {
+		return command, actionArg, true
+	}
 	if isImplicitApproveActionIntent(lower) {
 		return "approve-action", latestPendingActionAlias, true
 	}
@@ -187,6 +212,9 @@ func parseNaturalLanguageCommand(text string) (command, arg string, ok bool) {
 	if prompt, found := parseIntentTask(trimmed); found {
 		return "task", prompt, true
 	}
+	if command, localizedArg, found := parseLocalizedIntent(trimmed, lower); found {
+		return command, localizedArg, true
+	}
 	return "", "", false
 }
 
@@ -519,6 +547,31 @@ func parseIntentDenyAction(text string) (string, bool) {
 	return actionID + " " + reason, true
 }
 
+// parseIntentActionReference handles "approve the curl one" and "deny the email
+// action because ..." where the action is named by what it does.
+func parseIntentActionReference(trimmed, lower string) (string, string, bool) {
+	if strings.Contains(lower, "pair") || strings.Contains(lower, "token") {
+		return "", "", false
+	}
+	hasDeny := strings.Contains(lower, "deny") || strings.Contains(lower, "reject") || strings.Contains(lower, "decline")
+	hasApprove := strings.Contains(lower, "approve")
+	if hasDeny == hasApprove {
+		return "", "", false
+	}
+	terms, rest, ok := parseActionReference(trimmed)
+	if !ok {
+		return "", "", false
+	}
+	if hasApprove {
+		return "approve-action", pendingActionReferenceArg(terms), true
+	}
+	reason := normalizeDenyReason(rest)
+	if reason == "" {
+		return "deny-action", pendingActionReferenceArg(terms), true
+	}
+	return "deny-action", pendingActionReferenceArg(terms) + " " + reason, true
+}
+
 func parseIntentApprovePairing(text string) (string, bool) {
 	trimmed := strings.TrimSpace(text)
 	if trimmed == "" {
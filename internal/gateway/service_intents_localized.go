@@ -0,0 +1,112 @@
+package gateway
+
+import "strings"
+
+// localizedIntentPrefixes map Spanish and Portuguese phrases that open a
+// message to the command they mean; the rest of the message is its
+// argument. They are matched in every channel, whatever its reply language.
+var localizedIntentPrefixes = []struct {
+	prefix  string
+	command string
+}{
+	// Spanish
+	{"crea una tarea para ", "task"},
+	{"crear una tarea para ", "task"},
+	{"crea una tarea ", "task"},
+	{"nueva tarea ", "task"},
+	{"tarea ", "task"},
+	{"busca en los documentos ", "search"},
+	{"busca ", "search"},
+	{"buscar ", "search"},
+	{"muestra el archivo ", "open"},
+	{"abre el archivo ", "open"},
+	{"abre ", "open"},
+	{"monitorea ", "monitor"},
+	{"monitorear ", "monitor"},
+	{"vigila ", "monitor"},
+	// Portuguese
+	{"crie uma tarefa para ", "task"},
+	{"criar uma tarefa para ", "task"},
+	{"cria uma tarefa para ", "task"},
+	{"crie uma tarefa ", "task"},
+	{"nova tarefa ", "task"},
+	{"tarefa ", "task"},
+	{"pesquise ", "search"},
+	{"pesquisar ", "search"},
+	{"procure ", "search"},
+	{"busque ", "search"},
+	{"mostre o arquivo ", "open"},
+	{"abra o arquivo ", "open"},
+	{"abra ", "open"},
+	{"abrir ", "open"},
+	{"monitore ", "monitor"},
+	{"monitorar ", "monitor"},
+	{"acompanhe ", "monitor"},
+}
+
+var (
+	localizedStatusPhrases         = []string{"estado", "estado del índice", "estado do índice", "status do índice"}
+	localizedPendingActionsPhrases = []string{"acciones pendientes", "aprobaciones pendientes", "ações pendentes", "aprovações pendentes"}
+	localizedApproveWords          = []string{"aprueba", "aprobar", "aprova", "aprovar", "aprove"}
+	localizedDenyWords             = []string{"rechaza", "rechazar", "deniega", "rejeita", "rejeitar", "rejeite", "negar"}
+)
+
+// parseLocalizedIntent is the Spanish and Portuguese counterpart of the
+// English phrases in parseNaturalLanguageCommand. Actions are only approved
+// or denied by their ID.
+func parseLocalizedIntent(trimmed, lower string) (string, string, bool) {
+	if actionID, ok := findActionID(trimmed); ok {
+		approve := containsAnyWord(lower, localizedApproveWords)
+		deny := containsAnyWord(lower, localizedDenyWords)
+		if approve && !deny {
+			return "approve-action", actionID, true
+		}
+		if deny && !approve {
+			return "deny-action", actionID, true
+		}
+	}
+	for _, phrase := range localizedPendingActionsPhrases {
+		if strings.Contains(lower, phrase) {
+			return "pending-actions", "", true
+		}
+	}
+	for _, phrase := range localizedStatusPhrases {
+		if strings.Trim(lower, " .?!¿¡") == phrase {
+			return "status", "", true
+		}
+	}
+	match := ""
+	command := ""
+	for _, candidate := range localizedIntentPrefixes {
+		if len(candidate.prefix) > len(match) && strings.HasPrefix(lower, candidate.prefix) {
+			match, command = candidate.prefix, candidate.command
+		}
+	}
+	if match == "" {
+		return "", "", false
+	}
+	arg := strings.TrimSpace(trimmed[len(match):])
+	switch command {
+	case "open":
+		arg = sanitizeOpenTarget(arg)
+	case "monitor":
+		arg = cleanMonitorGoal(arg)
+	}
+	if arg == "" {
+		return "", "", false
+	}
+	return command, arg, true
+}
+
+func containsAnyWord(lower string, words []string) bool {
+	for _, field := range strings.FieldsFunc(lower, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '.' || r == '!' || r == '?' || r == '¡' || r == '¿'
+	}) {
+		for _, word := range words {
+			if field == word {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const languageUsage = "Usage: /language en | /language es | /language pt | /language status"
+
+func (s *Service) handleLanguage(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrIdentityNotFound) {
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedLink)}, nil
+		}
+		return MessageOutput{}, err
+	}
+	if !isAdminRole(identity.Role) {
+		return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedAdmin)}, nil
+	}
+
+	arg = strings.TrimSpace(arg)
+	if strings.EqualFold(arg, "status") {
+		policy, err := s.store.LookupContextPolicyByExternal(ctx, input.Connector, input.ExternalID)
+		if errors.Is(err, store.ErrContextNotFound) {
+			policy, err = store.ContextPolicy{}, nil
+		}
+		if err != nil {
+			return MessageOutput{}, err
+		}
+		language, ok := i18n.Normalize(policy.Language)
+		if !ok {
+			language = i18n.English
+		}
+		return MessageOutput{
+			Handled: true,
+			Reply:   i18n.Text(language, i18n.LanguageStatus, i18n.Name(language), strings.Join(i18n.Supported(), ", ")),
+		}, nil
+	}
+	language, ok := i18n.Normalize(arg)
+	if !ok {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, languageUsage)}, nil
+	}
+	stored := language
+	if language == i18n.English {
+		stored = ""
+	}
+	if _, err := s.store.SetContextLanguageByExternal(ctx, input.Connector, input.ExternalID, stored); err != nil {
+		return MessageOutput{}, err
+	}
+	// The confirmation is already in the new language.
+	return MessageOutput{Handled: true, Reply: i18n.Text(language, i18n.LanguageSet, i18n.Name(language))}, nil
+}
+
+// localizedUsage swaps the "Usage: " label of a usage string for the one of
+// the reply language; command syntax stays as it is.
+func localizedUsage(ctx context.Context, usage string) string {
+	return i18n.T(ctx, i18n.Usage, strings.TrimPrefix(usage, "Usage: "))
+}
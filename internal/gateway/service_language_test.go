@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestHandleLanguageSetsTheReplyLanguage(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "u1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/language es",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if fStore.contextPolicy.Language != "es" {
+		t.Fatalf("expected spanish stored, got %q", fStore.contextPolicy.Language)
+	}
+	if !strings.Contains(output.Reply, "español") {
+		t.Fatalf("expected a spanish confirmation, got %q", output.Reply)
+	}
+
+	output, err = service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/language fr",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if !strings.HasPrefix(output.Reply, "Uso: /language en") {
+		t.Fatalf("expected a spanish usage reply, got %q", output.Reply)
+	}
+}
+
+func TestHandleLanguageRequiresAdmin(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "u1", Role: "member"}}
+	fStore.contextPolicy = store.ContextPolicy{ContextID: "ctx-1", WorkspaceID: "ws-1", Language: "pt"}
+	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/language en",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	if fStore.contextPolicy.Language != "pt" {
+		t.Fatalf("expected the language unchanged, got %q", fStore.contextPolicy.Language)
+	}
+	if !strings.HasPrefix(output.Reply, "Acesso negado") {
+		t.Fatalf("expected a portuguese denial, got %q", output.Reply)
+	}
+}
+
+func TestParseNaturalLanguageCommandAcceptsSpanishAndPortuguese(t *testing.T) {
+	cases := []struct {
+		text    string
+		command string
+		arg     string
+	}{
+		{"Crea una tarea para revisar los logs de pagos", "task", "revisar los logs de pagos"},
+		{"tarefa atualizar o README", "task", "atualizar o README"},
+		{"busca política de reembolsos", "search", "política de reembolsos"},
+		{"pesquise política de reembolso", "search", "política de reembolso"},
+		{"abre docs/runbook.md", "open", "docs/runbook.md"},
+		{"¿Estado?", "status", ""},
+		{"mostrar acciones pendientes", "pending-actions", ""},
+		{"aprovações pendentes", "pending-actions", ""},
+		{"aprueba act_1234", "approve-action", "act_1234"},
+		{"rejeita act_4567", "deny-action", "act_4567"},
+	}
+	for _, tc := range cases {
+		command, arg, ok := parseNaturalLanguageCommand(tc.text)
+		if !ok || command != tc.command || arg != tc.arg {
+			t.Fatalf("parse %q = %q %q %v; want %q %q", tc.text, command, arg, ok, tc.command, tc.arg)
+		}
+	}
+}
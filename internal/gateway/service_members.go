@@ -0,0 +1,227 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	membersUsage = "Usage: /members | /members mute <user-id> | /members unmute <user-id> | /members allow-tasks <user-id> | /members disallow-tasks <user-id>"
+	silenceUsage = "Usage: /silence on | /silence off | /silence status"
+)
+
+// taskCreationRestricted is the reply to members left off a channel's
+// task-creator allowlist.
+const taskCreationRestricted = "Task creation in this channel is limited to allowlisted members."
+
+// MemberPolicyStore keeps per-context member mutes, task-creator allowlists
+// and the context's silenced flag.
+type MemberPolicyStore interface {
+	SetContextMemberMuted(ctx context.Context, contextID, userID string, muted bool) (store.ContextMemberPolicy, error)
+	SetContextTaskCreator(ctx context.Context, contextID, userID string, allowed bool) (store.ContextMemberPolicy, error)
+	ListContextMemberPolicies(ctx context.Context, contextID string) ([]store.ContextMemberPolicy, error)
+	LookupContextMemberAccess(ctx context.Context, contextID, userID string) (store.ContextMemberAccess, error)
+	SetContextSilencedByExternal(ctx context.Context, connector, externalID string, silenced bool) (store.ContextPolicy, error)
+}
+
+// SetMemberPolicies enables /members and /silence, and applies their mutes,
+// allowlists and silencing to incoming messages.
+func (s *Service) SetMemberPolicies(policies MemberPolicyStore) {
+	s.memberPolicies = policies
+}
+
+// holdForMemberPolicy keeps messages from muted members, and every message
+// in a silenced channel, away from auto-triage without any reply. Lookup
+// failures are logged and let the message through.
+func (s *Service) holdForMemberPolicy(ctx context.Context, input MessageInput) (MessageOutput, bool) {
+	if s.memberPolicies == nil {
+		return MessageOutput{}, false
+	}
+	policy, err := s.store.LookupContextPolicyByExternal(ctx, input.Connector, input.ExternalID)
+	if err != nil && !errors.Is(err, store.ErrContextNotFound) {
+		s.logger.Error("member policy context lookup failed", "connector", input.Connector, "external_id", input.ExternalID, "error", err)
+		return MessageOutput{}, false
+	}
+	if policy.Silenced {
+		return MessageOutput{Handled: true, Suppressed: true}, true
+	}
+	if policy.ContextID == "" || strings.TrimSpace(input.FromUserID) == "" {
+		return MessageOutput{}, false
+	}
+	access, err := s.memberPolicies.LookupContextMemberAccess(ctx, policy.ContextID, input.FromUserID)
+	if err != nil {
+		s.logger.Error("member access lookup failed", "context_id", policy.ContextID, "user_id", input.FromUserID, "error", err)
+		return MessageOutput{}, false
+	}
+	if access.Muted {
+		return MessageOutput{Handled: true, Suppressed: true}, true
+	}
+	return MessageOutput{}, false
+}
+
+// taskCreationDenied returns a refusal when the context restricts task
+// creation and the sender is neither allowlisted nor allowed to manage
+// members.
+func (s *Service) taskCreationDenied(ctx context.Context, input MessageInput, contextID string) (string, error) {
+	if s.memberPolicies == nil {
+		return "", nil
+	}
+	access, err := s.memberPolicies.LookupContextMemberAccess(ctx, contextID, input.FromUserID)
+	if err != nil {
+		return "", err
+	}
+	if access.CanCreateTasks {
+		return "", nil
+	}
+	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrIdentityNotFound) {
+			return taskCreationRestricted, nil
+		}
+		return "", err
+	}
+	allowed, err := s.hasPermission(ctx, input, identity, store.PermissionManageMembers)
+	if err != nil {
+		return "", err
+	}
+	if allowed {
+		return "", nil
+	}
+	return taskCreationRestricted, nil
+}
+
+func (s *Service) handleMembers(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if s.memberPolicies == nil {
+		return MessageOutput{Handled: true, Reply: "Member policies are unavailable in this runtime."}, nil
+	}
+	_, denied, err := s.authorize(ctx, input, store.PermissionManageMembers)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
+	}
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+
+	fields := strings.Fields(arg)
+	if len(fields) == 0 || strings.EqualFold(fields[0], "list") {
+		return s.listMembers(ctx, input, contextRecord.ID)
+	}
+	if len(fields) != 2 {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, membersUsage)}, nil
+	}
+	userID := normalizeMemberID(fields[1])
+	if userID == "" {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, membersUsage)}, nil
+	}
+	var reply string
+	switch strings.ToLower(fields[0]) {
+	case "mute":
+		_, err = s.memberPolicies.SetContextMemberMuted(ctx, contextRecord.ID, userID, true)
+		reply = fmt.Sprintf("Muted `%s` in this channel: their messages no longer trigger auto-triage.", userID)
+	case "unmute":
+		_, err = s.memberPolicies.SetContextMemberMuted(ctx, contextRecord.ID, userID, false)
+		reply = fmt.Sprintf("Unmuted `%s` in this channel.", userID)
+	case "allow-tasks":
+		_, err = s.memberPolicies.SetContextTaskCreator(ctx, contextRecord.ID, userID, true)
+		reply = fmt.Sprintf("`%s` may create tasks in this channel. Members not on the allowlist can no longer create tasks here.", userID)
+	case "disallow-tasks":
+		_, err = s.memberPolicies.SetContextTaskCreator(ctx, contextRecord.ID, userID, false)
+		reply = fmt.Sprintf("Removed `%s` from this channel's task-creator allowlist.", userID)
+	default:
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, membersUsage)}, nil
+	}
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	return MessageOutput{Handled: true, Reply: reply}, nil
+}
+
+func (s *Service) listMembers(ctx context.Context, input MessageInput, contextID string) (MessageOutput, error) {
+	policy, err := s.store.LookupContextPolicyByExternal(ctx, input.Connector, input.ExternalID)
+	if err != nil && !errors.Is(err, store.ErrContextNotFound) {
+		return MessageOutput{}, err
+	}
+	policies, err := s.memberPolicies.ListContextMemberPolicies(ctx, contextID)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	var muted, creators []string
+	for _, member := range policies {
+		if member.Muted {
+			muted = append(muted, "`"+member.UserID+"`")
+		}
+		if member.TaskCreator {
+			creators = append(creators, "`"+member.UserID+"`")
+		}
+	}
+	lines := []string{"Member policies for this channel:"}
+	if policy.Silenced {
+		lines = append(lines, "- agent: silenced (`/silence off` to resume)")
+	} else {
+		lines = append(lines, "- agent: answering")
+	}
+	if len(muted) == 0 {
+		lines = append(lines, "- muted: none")
+	} else {
+		lines = append(lines, "- muted: "+strings.Join(muted, ", "))
+	}
+	if len(creators) == 0 {
+		lines = append(lines, "- task creators: anyone")
+	} else {
+		lines = append(lines, "- task creators: "+strings.Join(creators, ", "))
+	}
+	return MessageOutput{Handled: true, Reply: strings.Join(lines, "\n")}, nil
+}
+
+func (s *Service) handleSilence(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if s.memberPolicies == nil {
+		return MessageOutput{Handled: true, Reply: "Member policies are unavailable in this runtime."}, nil
+	}
+	_, denied, err := s.authorize(ctx, input, store.PermissionManageMembers)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
+	}
+
+	var policy store.ContextPolicy
+	switch strings.ToLower(strings.TrimSpace(arg)) {
+	case "on", "enable":
+		policy, err = s.memberPolicies.SetContextSilencedByExternal(ctx, input.Connector, input.ExternalID, true)
+	case "off", "disable":
+		policy, err = s.memberPolicies.SetContextSilencedByExternal(ctx, input.Connector, input.ExternalID, false)
+	case "status":
+		policy, err = s.store.LookupContextPolicyByExternal(ctx, input.Connector, input.ExternalID)
+		if errors.Is(err, store.ErrContextNotFound) {
+			policy, err = store.ContextPolicy{}, nil
+		}
+	default:
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, silenceUsage)}, nil
+	}
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if policy.Silenced {
+		return MessageOutput{Handled: true, Reply: "The agent is silenced in this channel. Commands still work; `/silence off` resumes replies."}, nil
+	}
+	return MessageOutput{Handled: true, Reply: "The agent answers messages in this channel."}, nil
+}
+
+// normalizeMemberID strips mention markup such as Discord's <@123> or a
+// leading @ from a user argument.
+func normalizeMemberID(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "<@") && strings.HasSuffix(value, ">") {
+		value = strings.TrimPrefix(strings.TrimSuffix(value[2:], ">"), "!")
+	}
+	return strings.TrimPrefix(value, "@")
+}
@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestMemberPoliciesMuteRestrictAndSilence(t *testing.T) {
+	ctx := context.Background()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "members.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	pairing, err := sqlStore.CreatePairingRequest(ctx, store.CreatePairingRequestInput{Connector: "discord", ConnectorUserID: "u-admin", DisplayName: "Ops"})
+	if err != nil {
+		t.Fatalf("create pairing: %v", err)
+	}
+	if _, err := sqlStore.ApprovePairing(ctx, store.ApprovePairingInput{Token: pairing.Token, ApproverUserID: "tui-admin", Role: "admin"}); err != nil {
+		t.Fatalf("approve pairing: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := New(sqlStore, orchestrator.New(4, logger), nil, nil, t.TempDir(), logger)
+	service.SetMemberPolicies(sqlStore)
+	send := func(userID, text string) MessageOutput {
+		output, err := service.HandleMessage(ctx, MessageInput{
+			Connector:  "discord",
+			ExternalID: "chan-1",
+			FromUserID: userID,
+			Text:       text,
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", text, err)
+		}
+		return output
+	}
+
+	if reply := send("u-member", "/members mute u-spammer").Reply; reply != "Access denied: link your admin identity first." {
+		t.Fatalf("expected members to be refused, got %q", reply)
+	}
+	if reply := send("u-admin", "/members mute <@u-spammer>").Reply; !strings.Contains(reply, "Muted `u-spammer`") {
+		t.Fatalf("expected mute confirmation, got %q", reply)
+	}
+	if output := send("u-spammer", "can someone fix the login page bug?"); !output.Suppressed {
+		t.Fatalf("expected muted member to be ignored, got %+v", output)
+	}
+
+	if reply := send("u-member", "/task rotate the keys").Reply; !strings.HasPrefix(reply, "Task queued") {
+		t.Fatalf("expected anyone to create tasks without an allowlist, got %q", reply)
+	}
+	if reply := send("u-admin", "/members allow-tasks u-lead").Reply; !strings.Contains(reply, "may create tasks") {
+		t.Fatalf("expected allowlist confirmation, got %q", reply)
+	}
+	if reply := send("u-member", "/task rotate the keys").Reply; reply != taskCreationRestricted {
+		t.Fatalf("expected task creation refused, got %q", reply)
+	}
+	if reply := send("u-lead", "/task rotate the keys").Reply; !strings.HasPrefix(reply, "Task queued") {
+		t.Fatalf("expected allowlisted member to create tasks, got %q", reply)
+	}
+	if reply := send("u-admin", "/task rotate the keys").Reply; !strings.HasPrefix(reply, "Task queued") {
+		t.Fatalf("expected member managers to bypass the allowlist, got %q", reply)
+	}
+
+	if reply := send("u-admin", "/silence on").Reply; !strings.Contains(reply, "silenced") {
+		t.Fatalf("expected silence confirmation, got %q", reply)
+	}
+	if output := send("u-member", "can someone fix the login page bug?"); !output.Suppressed {
+		t.Fatalf("expected silenced channel to ignore messages, got %+v", output)
+	}
+	reply := send("u-admin", "/members").Reply
+	for _, want := range []string{"agent: silenced", "muted: `u-spammer`", "task creators: `u-lead`"} {
+		if !strings.Contains(reply, want) {
+			t.Fatalf("expected %q in member listing, got %q", want, reply)
+		}
+	}
+	if reply := send("u-admin", "/silence off").Reply; reply != "The agent answers messages in this channel." {
+		t.Fatalf("expected silence lifted, got %q", reply)
+	}
+}
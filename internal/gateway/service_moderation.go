@@ -0,0 +1,153 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// ModerationStore records moderation decisions and appeals against them.
+type ModerationStore interface {
+	CreateModerationCase(ctx context.Context, input store.CreateModerationCaseInput) (store.ModerationCase, error)
+	LookupModerationCase(ctx context.Context, id string) (store.ModerationCase, error)
+	ListModerationDecisionsForUser(ctx context.Context, connector, userID string, limit int) ([]store.ModerationCase, error)
+	AppealModerationCase(ctx context.Context, input store.AppealModerationCaseInput) (store.ModerationCase, store.ModerationCase, error)
+	ResolveModerationAppeal(ctx context.Context, input store.ResolveModerationAppealInput) (store.ModerationCase, store.ModerationCase, error)
+}
+
+// ModerationNotifier tells admins about a new appeal, with the decision it
+// contests.
+type ModerationNotifier interface {
+	NotifyModerationAppeal(ctx context.Context, decision, appeal store.ModerationCase)
+}
+
+// SetModeration records spam holds and moderation routes as cases users can
+// appeal with /appeal.
+func (s *Service) SetModeration(cases ModerationStore, notifier ModerationNotifier) {
+	s.moderationCases = cases
+	s.moderationNotify = notifier
+}
+
+// recordModerationDecision stores a decision about the sender of input and
+// returns its id, or "" when moderation cases are not configured or the
+// write fails.
+func (s *Service) recordModerationDecision(ctx context.Context, decision RouteDecision, action string) string {
+	if s.moderationCases == nil || strings.TrimSpace(decision.SourceUserID) == "" {
+		return ""
+	}
+	record, err := s.moderationCases.CreateModerationCase(ctx, store.CreateModerationCaseInput{
+		WorkspaceID:   decision.WorkspaceID,
+		ContextID:     decision.ContextID,
+		Connector:     decision.SourceConnector,
+		ExternalID:    decision.SourceExternalID,
+		SubjectUserID: decision.SourceUserID,
+		Action:        action,
+		Reason:        decision.Reason,
+		Evidence:      decision.SourceText,
+		TaskID:        decision.TaskID,
+	})
+	if err != nil {
+		s.logger.Error("record moderation case failed", "workspace_id", decision.WorkspaceID, "error", err)
+		return ""
+	}
+	return record.ID
+}
+
+func (s *Service) handleAppeal(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if s.moderationCases == nil {
+		return MessageOutput{Handled: true, Reply: "Appeals are not available right now."}, nil
+	}
+	caseID, text := "", strings.TrimSpace(arg)
+	if fields := strings.Fields(text); len(fields) > 0 && strings.HasPrefix(strings.Trim(fields[0], "`"), "mod_") {
+		caseID = strings.Trim(fields[0], "`")
+		text = strings.TrimSpace(strings.TrimPrefix(text, fields[0]))
+	}
+	if caseID == "" {
+		decisions, err := s.moderationCases.ListModerationDecisionsForUser(ctx, input.Connector, input.FromUserID, 20)
+		if err != nil {
+			return MessageOutput{}, err
+		}
+		for _, decision := range decisions {
+			if decision.Status == store.ModerationStatusOpen {
+				caseID = decision.ID
+				break
+			}
+		}
+		if caseID == "" {
+			return MessageOutput{Handled: true, Reply: "There is no moderation decision about you to appeal."}, nil
+		}
+	}
+	if text == "" {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /appeal [case-id] <why the decision was wrong>")}, nil
+	}
+	decision, appeal, err := s.moderationCases.AppealModerationCase(ctx, store.AppealModerationCaseInput{
+		CaseID: caseID,
+		UserID: input.FromUserID,
+		Text:   text,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrModerationCaseNotFound), errors.Is(err, store.ErrModerationAppealNotAllowed):
+			return MessageOutput{Handled: true, Reply: "Moderation case not found."}, nil
+		case errors.Is(err, store.ErrModerationCaseNotOpen):
+			return MessageOutput{Handled: true, Reply: "That decision was already appealed."}, nil
+		}
+		return MessageOutput{}, err
+	}
+	if s.moderationNotify != nil {
+		s.moderationNotify.NotifyModerationAppeal(ctx, decision, appeal)
+	}
+	return MessageOutput{
+		Handled: true,
+		Reply:   fmt.Sprintf("Appeal `%s` recorded for case `%s`. An admin will review it.", appeal.ID, decision.ID),
+	}, nil
+}
+
+func (s *Service) handleResolveAppeal(ctx context.Context, input MessageInput, arg string, uphold bool) (MessageOutput, error) {
+	usage := "Usage: /overturn-appeal <appeal-id> [note]"
+	if uphold {
+		usage = "Usage: /uphold-appeal <appeal-id> [note]"
+	}
+	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrIdentityNotFound) {
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedLink)}, nil
+		}
+		return MessageOutput{}, err
+	}
+	if !isAdminRole(identity.Role) {
+		return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedAdmin)}, nil
+	}
+	if s.moderationCases == nil {
+		return MessageOutput{Handled: true, Reply: "Appeals are not available right now."}, nil
+	}
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, usage)}, nil
+	}
+	appealID := strings.Trim(fields[0], "`")
+	note := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(arg), fields[0]))
+	decision, _, err := s.moderationCases.ResolveModerationAppeal(ctx, store.ResolveModerationAppealInput{
+		AppealID:       appealID,
+		ResolverUserID: identity.UserID,
+		Uphold:         uphold,
+		Note:           note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrModerationCaseNotFound):
+			return MessageOutput{Handled: true, Reply: "Appeal not found."}, nil
+		case errors.Is(err, store.ErrModerationCaseNotOpen):
+			return MessageOutput{Handled: true, Reply: "That appeal was already resolved."}, nil
+		}
+		return MessageOutput{}, err
+	}
+	return MessageOutput{
+		Handled: true,
+		Reply:   fmt.Sprintf("Appeal `%s` resolved: case `%s` is %s.", appealID, decision.ID, decision.Status),
+	}, nil
+}
@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/spam"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeModerationNotifier struct {
+	decision store.ModerationCase
+	appeal   store.ModerationCase
+}
+
+func (f *fakeModerationNotifier) NotifyModerationAppeal(ctx context.Context, decision, appeal store.ModerationCase) {
+	f.decision = decision
+	f.appeal = appeal
+}
+
+func TestSpamHoldCanBeAppealedAndOverturned(t *testing.T) {
+	ctx := context.Background()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "moderation.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
+	routing := &fakeRoutingNotifier{}
+	service.SetRoutingNotifier(routing)
+	service.SetSpamFilter(spam.New(spam.Config{}))
+	appeals := &fakeModerationNotifier{}
+	service.SetModeration(sqlStore, appeals)
+	service.SetCaseStore(sqlStore)
+
+	evidence := "@everyone free nitro giveaway https://discord.gg/abc"
+	if _, err := service.HandleMessage(ctx, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "u1", Text: evidence}); err != nil {
+		t.Fatalf("handle spam: %v", err)
+	}
+	caseID := routing.lastDecision.CaseID
+	if !strings.HasPrefix(caseID, "mod_") {
+		t.Fatalf("expected the hold to be recorded as a case, got %+v", routing.lastDecision)
+	}
+	items, err := sqlStore.ListCaseItems(ctx, routing.lastDecision.TrackingCaseID)
+	if err != nil || len(items) != 2 || items[0].ItemID != caseID || !strings.Contains(items[1].Excerpt, "free nitro") {
+		t.Fatalf("expected a tracking case linking the decision and message, got %+v (%v)", items, err)
+	}
+
+	output, err := service.HandleMessage(ctx, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "u2", Text: "/appeal " + caseID + " not mine"})
+	if err != nil || !strings.Contains(output.Reply, "not found") {
+		t.Fatalf("expected another user's appeal to be refused, got %+v (%v)", output, err)
+	}
+	output, err = service.HandleMessage(ctx, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "u1", Text: "/appeal it was a joke between friends"})
+	if err != nil || !strings.Contains(output.Reply, "Appeal `mod_") {
+		t.Fatalf("expected the appeal to be recorded, got %+v (%v)", output, err)
+	}
+	if appeals.decision.ID != caseID || appeals.decision.Evidence != evidence || appeals.appeal.LinkedCaseID != caseID {
+		t.Fatalf("expected admins to get the decision with evidence, got %+v / %+v", appeals.decision, appeals.appeal)
+	}
+
+	output, err = service.HandleMessage(ctx, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "admin", Text: "/overturn-appeal " + appeals.appeal.ID + " known member"})
+	if err != nil || !strings.Contains(output.Reply, "overturned") {
+		t.Fatalf("expected the appeal to be resolved, got %+v (%v)", output, err)
+	}
+	decision, err := sqlStore.LookupModerationCase(ctx, caseID)
+	if err != nil || decision.Status != store.ModerationStatusOverturned || decision.Resolution != "known member" {
+		t.Fatalf("expected overturned decision, got %+v (%v)", decision, err)
+	}
+}
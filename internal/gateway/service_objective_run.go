@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// handleRunObjective queues an objective immediately so admins can test a new
+// monitor without waiting for its schedule or event.
+func (s *Service) handleRunObjective(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	_, denied, err := s.authorize(ctx, input, store.PermissionManageObjectives)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
+	}
+	objectiveID := strings.Trim(strings.TrimSpace(arg), "`\"'")
+	if objectiveID == "" || len(strings.Fields(objectiveID)) > 1 {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /run-objective <objective-id>")}, nil
+	}
+	if s.objectiveRunner == nil {
+		return MessageOutput{Handled: true, Reply: "Objective runs are not available right now."}, nil
+	}
+	task, err := s.objectiveRunner.RunNow(ctx, objectiveID)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrObjectiveNotFound):
+			return MessageOutput{Handled: true, Reply: "Objective not found."}, nil
+		case errors.Is(err, scheduler.ErrObjectivePromptEmpty):
+			return MessageOutput{Handled: true, Reply: "Objective has no prompt to run."}, nil
+		case errors.Is(err, scheduler.ErrObjectiveRunAlreadyQueued):
+			return MessageOutput{Handled: true, Reply: "A run of this objective was just queued."}, nil
+		case errors.Is(err, orchestrator.ErrQueueFull):
+			return MessageOutput{Handled: true, Reply: "Task queue is full; try again shortly."}, nil
+		}
+		return MessageOutput{}, err
+	}
+	return MessageOutput{
+		Handled: true,
+		Reply: fmt.Sprintf(
+			"Objective `%s` (%s) queued as task `%s`. The result is reported to the objective's channel when it finishes.",
+			objectiveID, task.Title, task.ID,
+		),
+	}, nil
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/objectivetemplate"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// handleMonitorTemplate creates an objective from a built-in template, or
+// lists the templates when no name is given.
+func (s *Service) handleMonitorTemplate(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if strings.TrimSpace(arg) == "" {
+		return MessageOutput{Handled: true, Reply: objectiveTemplateListing()}, nil
+	}
+	name, values, err := objectivetemplate.ParseArgs(arg)
+	if err != nil {
+		return MessageOutput{Handled: true, Reply: err.Error()}, nil
+	}
+	objective, err := objectivetemplate.Build(name, values)
+	if err != nil {
+		if errors.Is(err, objectivetemplate.ErrUnknownTemplate) {
+			return MessageOutput{Handled: true, Reply: fmt.Sprintf("Unknown template `%s`.\n%s", name, objectiveTemplateListing())}, nil
+		}
+		if template, ok := objectivetemplate.Lookup(name); ok {
+			return MessageOutput{Handled: true, Reply: fmt.Sprintf("%v\n%s", err, localizedUsage(ctx, "Usage: /monitor template "+template.Usage()))}, nil
+		}
+		return MessageOutput{Handled: true, Reply: err.Error()}, nil
+	}
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	active := true
+	created, err := s.store.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: contextRecord.WorkspaceID,
+		ContextID:   contextRecord.ID,
+		Title:       objective.Title,
+		Prompt:      objective.Prompt,
+		TriggerType: objective.TriggerType,
+		CronExpr:    objective.CronExpr,
+		Timezone:    objective.Timezone,
+		Active:      &active,
+	})
+	if err != nil {
+		return MessageOutput{Handled: true, Reply: "Could not create objective: " + err.Error()}, nil
+	}
+	return MessageOutput{
+		Handled: true,
+		Reply: fmt.Sprintf(
+			"Objective `%s` created from template `%s` (%s, %s). Use `/run-objective %s` to test it now.",
+			created.ID, name, objective.CronExpr, objective.Timezone, created.ID,
+		),
+	}, nil
+}
+
+func objectiveTemplateListing() string {
+	lines := []string{"Objective templates:"}
+	for _, template := range objectivetemplate.List() {
+		lines = append(lines, fmt.Sprintf("- `%s`: %s\n  `/monitor template %s`", template.Name, template.Description, template.Usage()))
+	}
+	return strings.Join(lines, "\n")
+}
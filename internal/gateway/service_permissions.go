@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// authorize returns the caller's identity, or a refusal reply when they are
+// not linked or their role lacks permission in this channel's workspace.
+func (s *Service) authorize(ctx context.Context, input MessageInput, permission store.Permission) (store.UserIdentity, string, error) {
+	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrIdentityNotFound) {
+			return store.UserIdentity{}, i18n.T(ctx, i18n.AccessDeniedLink), nil
+		}
+		return store.UserIdentity{}, "", err
+	}
+	allowed, err := s.hasPermission(ctx, input, identity, permission)
+	if err != nil {
+		return store.UserIdentity{}, "", err
+	}
+	if !allowed {
+		return store.UserIdentity{}, fmt.Sprintf("Access denied: %s permission required.", permission), nil
+	}
+	return identity, "", nil
+}
+
+// hasPermission reports whether identity's role grants permission in the
+// workspace of the channel the message came from.
+func (s *Service) hasPermission(ctx context.Context, input MessageInput, identity store.UserIdentity, permission store.Permission) (bool, error) {
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return false, err
+	}
+	permissions, err := s.store.RolePermissions(ctx, contextRecord.WorkspaceID, identity.Role)
+	if err != nil {
+		return false, err
+	}
+	return store.HasPermission(permissions, permission), nil
+}
+
+// errNotActionApprover is returned when the caller's role lacks
+// approve_actions in the workspace an action belongs to.
+var errNotActionApprover = errors.New("approve_actions permission required in the action's workspace")
+
+// mayDecideActionsIn reports whether identity's role grants approve_actions
+// in workspaceID. Actions listed across all contexts can belong to other
+// workspaces than the calling channel's, so each decision checks its own.
+func (s *Service) mayDecideActionsIn(ctx context.Context, identity store.UserIdentity, workspaceID string) (bool, error) {
+	permissions, err := s.store.RolePermissions(ctx, workspaceID, identity.Role)
+	if err != nil {
+		return false, err
+	}
+	return store.HasPermission(permissions, store.PermissionApproveActions), nil
+}
+
+// checkActionApprover returns errNotActionApprover unless identity may
+// approve or deny actionID in the action's workspace.
+func (s *Service) checkActionApprover(ctx context.Context, identity store.UserIdentity, actionID string) error {
+	record, err := s.store.LookupActionApproval(ctx, actionID)
+	if err != nil {
+		return err
+	}
+	allowed, err := s.mayDecideActionsIn(ctx, identity, record.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errNotActionApprover
+	}
+	return nil
+}
+
+// decidableActions keeps the items identity may approve or deny.
+func (s *Service) decidableActions(ctx context.Context, identity store.UserIdentity, items []store.ActionApproval) ([]store.ActionApproval, error) {
+	allowed := map[string]bool{}
+	kept := make([]store.ActionApproval, 0, len(items))
+	for _, item := range items {
+		ok, seen := allowed[item.WorkspaceID]
+		if !seen {
+			var err error
+			ok, err = s.mayDecideActionsIn(ctx, identity, item.WorkspaceID)
+			if err != nil {
+				return nil, err
+			}
+			allowed[item.WorkspaceID] = ok
+		}
+		if ok {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,112 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestCustomRoleGetsOnlyGrantedPermissions(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "op-1", Role: "operator"},
+		rolePermissions: map[string][]store.Permission{
+			"operator": {store.PermissionApproveActions},
+		},
+		actionApprovals: []store.ActionApproval{
+			{ID: "act-1", ActionType: "run_command", Status: "pending"},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	send := func(text string) string {
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "telegram",
+			ExternalID: "42",
+			FromUserID: "u1",
+			Text:       text,
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", text, err)
+		}
+		return output.Reply
+	}
+
+	if reply := send("/deny-action act-1 not needed"); !strings.Contains(reply, "denied") {
+		t.Fatalf("expected operator to deny the action, got %q", reply)
+	}
+	if reply := send("/prompt set Be terse"); reply != "Access denied: set_prompt permission required." {
+		t.Fatalf("expected set_prompt refusal, got %q", reply)
+	}
+	if reply := send("/route task-1 issue"); reply != "Access denied: route_tasks permission required." {
+		t.Fatalf("expected route_tasks refusal, got %q", reply)
+	}
+}
+
+func TestWorkspaceRoleCanNarrowAdmin(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+		rolePermissions: map[string][]store.Permission{
+			"admin": {store.PermissionSetPrompt},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/pending-actions",
+	})
+	if err != nil {
+		t.Fatalf("pending-actions failed: %v", err)
+	}
+	if output.Reply != "Access denied: approve_actions permission required." {
+		t.Fatalf("expected approve_actions refusal, got %q", output.Reply)
+	}
+}
+
+func TestApproverRoleIsCheckedInTheActionsWorkspace(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "op-1", Role: "operator"},
+		workspaceRoles: map[string]map[string][]store.Permission{
+			"ws-1": {"operator": {store.PermissionApproveActions}},
+			"ws-2": {"operator": {}},
+		},
+		actionApprovals: []store.ActionApproval{
+			{ID: "act-1", WorkspaceID: "ws-1", Connector: "discord", ExternalID: "98", ActionType: "run_command", Status: "pending"},
+			{ID: "act-2", WorkspaceID: "ws-2", Connector: "discord", ExternalID: "99", ActionType: "webhook", Status: "pending"},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	send := func(text string) string {
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "telegram",
+			ExternalID: "42",
+			FromUserID: "u1",
+			Text:       text,
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", text, err)
+		}
+		return output.Reply
+	}
+	status := func(id string) string {
+		record, _ := fStore.LookupActionApproval(context.Background(), id)
+		return record.Status
+	}
+
+	for _, text := range []string{"/approve-action act-2", "/deny-action act-2 not yours"} {
+		if reply := send(text); !strings.Contains(reply, "Access denied") {
+			t.Fatalf("expected %q refused in another workspace, got %q", text, reply)
+		}
+	}
+	if reply := send("/deny-action --type webhook"); !strings.Contains(reply, "No pending actions match") {
+		t.Fatalf("filtered deny must skip other workspaces, got %q", reply)
+	}
+	if reply := send("/approve all"); !strings.Contains(reply, "Approved 1 actions") {
+		t.Fatalf("expected only the own workspace's action approved, got %q", reply)
+	}
+	if status("act-2") != "pending" {
+		t.Fatalf("expected act-2 untouched, got %s", status("act-2"))
+	}
+}
@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/ratelimit"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// RateLimiter throttles inbound messages per sender and per channel.
+type RateLimiter interface {
+	Allow(message ratelimit.Message) ratelimit.Decision
+}
+
+// SetRateLimiter throttles HandleMessage before any command, model turn or
+// task is run.
+func (s *Service) SetRateLimiter(limiter RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// throttle refuses messages over the sender's or channel's rate. The first
+// refusal of a burst gets a short reply and an audit event; the rest are
+// dropped without a reply. Slash commands from senders who may manage the
+// channel's members only count against the sender, so moderators can still
+// act in a flooded channel; everyone else stays on the channel's bucket.
+func (s *Service) throttle(ctx context.Context, input MessageInput, text string) (MessageOutput, bool) {
+	if s.rateLimiter == nil {
+		return MessageOutput{}, false
+	}
+	decision := s.rateLimiter.Allow(ratelimit.Message{
+		Connector:   input.Connector,
+		ExternalID:  input.ExternalID,
+		UserID:      input.FromUserID,
+		SkipContext: strings.HasPrefix(text, "/") && s.moderates(ctx, input),
+	})
+	if decision.Allowed {
+		return MessageOutput{}, false
+	}
+	if !decision.Notify {
+		return MessageOutput{Handled: true, Suppressed: true}, true
+	}
+	retry := decision.RetryAfter.Round(time.Second)
+	if retry < time.Second {
+		retry = time.Second
+	}
+	s.logger.Warn("message rate limited",
+		"connector", input.Connector,
+		"external_id", input.ExternalID,
+		"user_id", input.FromUserID,
+		"scope", decision.Scope,
+		"retry_after", retry,
+	)
+	s.auditRateLimit(ctx, input, decision, retry)
+	reply := i18n.T(ctx, i18n.RateLimitedUser, retry)
+	if decision.Scope == ratelimit.ScopeContext {
+		reply = i18n.T(ctx, i18n.RateLimitedChannel, retry)
+	}
+	return MessageOutput{Handled: true, Reply: reply}, true
+}
+
+func (s *Service) auditRateLimit(ctx context.Context, input MessageInput, decision ratelimit.Decision, retry time.Duration) {
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		s.logger.Error("rate limit context lookup failed", "error", err)
+		return
+	}
+	_, _ = s.store.CreateAgentAuditEvent(ctx, store.CreateAgentAuditEventInput{
+		WorkspaceID:  contextRecord.WorkspaceID,
+		ContextID:    contextRecord.ID,
+		Connector:    input.Connector,
+		ExternalID:   input.ExternalID,
+		SourceUserID: input.FromUserID,
+		EventType:    "rate_limited",
+		Stage:        "audit.rate_limited",
+		Blocked:      true,
+		BlockReason:  string(decision.Scope) + " rate limit",
+		Message:      fmt.Sprintf("scope=%s retry_after=%s", decision.Scope, retry),
+	})
+}
+
+// moderates reports whether the sender holds manage_members in the channel's
+// workspace. Lookup failures count as no.
+func (s *Service) moderates(ctx context.Context, input MessageInput) bool {
+	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		return false
+	}
+	allowed, err := s.hasPermission(ctx, input, identity, store.PermissionManageMembers)
+	return err == nil && allowed
+}
@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/ratelimit"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestThrottleRepliesOnceAndAudits(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	service.SetRateLimiter(ratelimit.New(ratelimit.Config{UserPerMinute: 1, UserBurst: 1}))
+	send := func(text string) MessageOutput {
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "telegram",
+			ExternalID: "42",
+			FromUserID: "u1",
+			Text:       text,
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", text, err)
+		}
+		return output
+	}
+
+	if output := send("/task first"); !strings.HasPrefix(output.Reply, "Task queued") {
+		t.Fatalf("expected first message through, got %+v", output)
+	}
+	if output := send("/task second"); !strings.HasPrefix(output.Reply, "You're sending messages too fast.") {
+		t.Fatalf("expected throttle reply, got %+v", output)
+	}
+	if output := send("/task third"); !output.Suppressed || output.Reply != "" {
+		t.Fatalf("expected later messages dropped silently, got %+v", output)
+	}
+	if fStore.lastTask.Prompt != "first" {
+		t.Fatalf("expected only the first task created, got %+v", fStore.lastTask)
+	}
+	if len(fStore.auditEvents) != 1 || fStore.auditEvents[0].EventType != "rate_limited" || fStore.auditEvents[0].BlockReason != "user rate limit" {
+		t.Fatalf("expected one rate_limited audit event, got %+v", fStore.auditEvents)
+	}
+}
+
+func TestThrottleReplyFollowsChannelLanguage(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	fStore.contextPolicy = store.ContextPolicy{ContextID: "ctx-1", WorkspaceID: "ws-1", Language: "es"}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	service.SetRateLimiter(ratelimit.New(ratelimit.Config{ContextPerMinute: 1, ContextBurst: 1}))
+	send := func(userID string) MessageOutput {
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "telegram",
+			ExternalID: "42",
+			FromUserID: userID,
+			Text:       "hola",
+		})
+		if err != nil {
+			t.Fatalf("message from %s failed: %v", userID, err)
+		}
+		return output
+	}
+
+	send("u1")
+	if output := send("u2"); !strings.HasPrefix(output.Reply, "Este canal está enviando mensajes demasiado rápido.") {
+		t.Fatalf("expected a spanish channel throttle reply, got %+v", output)
+	}
+}
+
+func TestThrottleKeepsMembersCommandsOnTheChannelBucket(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "member-1", Role: "member"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	service.SetRateLimiter(ratelimit.New(ratelimit.Config{ContextPerMinute: 1, ContextBurst: 1}))
+	send := func(userID, text string) MessageOutput {
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "telegram",
+			ExternalID: "42",
+			FromUserID: userID,
+			Text:       text,
+		})
+		if err != nil {
+			t.Fatalf("%s from %s failed: %v", text, userID, err)
+		}
+		return output
+	}
+
+	send("u1", "hello")
+	if output := send("u2", "/task flood"); !strings.HasPrefix(output.Reply, "This channel is sending messages too fast.") {
+		t.Fatalf("expected a member's command throttled by the channel, got %+v", output)
+	}
+
+	fStore.identity = store.UserIdentity{UserID: "admin-1", Role: "admin"}
+	if output := send("u3", "/silence status"); output.Suppressed || strings.Contains(output.Reply, "too fast") {
+		t.Fatalf("expected a moderator's command to skip the channel bucket, got %+v", output)
+	}
+}
@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/qmd"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// scopeToChannel restricts store access of a message to the workspace of its
+// channel, so IDs from another workspace read as unknown. Admin channels are
+// operator consoles and stay unscoped for admin commands; agent turns are
+// scoped everywhere by withContextRecord.
+func (s *Service) scopeToChannel(ctx context.Context, input MessageInput) (context.Context, error) {
+	if strings.TrimSpace(input.Connector) == "" || strings.TrimSpace(input.ExternalID) == "" {
+		return ctx, nil
+	}
+	policy, err := s.store.LookupContextPolicyByExternal(ctx, input.Connector, input.ExternalID)
+	if errors.Is(err, store.ErrContextNotFound) {
+		record, ensureErr := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+		if ensureErr != nil {
+			return ctx, ensureErr
+		}
+		return store.WithWorkspaceScope(ctx, record.WorkspaceID), nil
+	}
+	if err != nil {
+		return ctx, err
+	}
+	if policy.SharedKnowledge {
+		ctx = qmd.WithSharedKnowledge(ctx)
+	}
+	ctx = i18n.WithLanguage(ctx, policy.Language)
+	if policy.IsAdmin {
+		return ctx, nil
+	}
+	return store.WithWorkspaceScope(ctx, policy.WorkspaceID), nil
+}
+
+// withContextRecord prepares the context of an agent turn: tools read the
+// channel record from it and every store access is scoped to its workspace.
+func withContextRecord(ctx context.Context, record store.ContextRecord) context.Context {
+	ctx = context.WithValue(ctx, ContextKeyRecord, record)
+	return store.WithWorkspaceScope(ctx, record.WorkspaceID)
+}
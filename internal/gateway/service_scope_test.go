@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestHandleMessageScopesChannelToWorkspace(t *testing.T) {
+	ctx := context.Background()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "scope.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	pairing, err := sqlStore.CreatePairingRequest(ctx, store.CreatePairingRequestInput{Connector: "discord", ConnectorUserID: "u-admin", DisplayName: "Ops"})
+	if err != nil {
+		t.Fatalf("create pairing: %v", err)
+	}
+	if _, err := sqlStore.ApprovePairing(ctx, store.ApprovePairingInput{Token: pairing.Token, ApproverUserID: "tui-admin", Role: "admin"}); err != nil {
+		t.Fatalf("approve pairing: %v", err)
+	}
+	owner, err := sqlStore.EnsureContextForExternalChannel(ctx, "discord", "chan-a", "Team A")
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	objective, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: owner.WorkspaceID,
+		ContextID:   owner.ID,
+		Title:       "Digest",
+		Prompt:      "Summarize changes",
+		TriggerType: store.ObjectiveTriggerEvent,
+		EventKey:    "markdown.updated",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := orchestrator.New(1, logger)
+	service := New(sqlStore, engine, nil, nil, t.TempDir(), logger)
+	service.SetObjectiveRunner(scheduler.New(sqlStore, engine, time.Minute, logger))
+	send := func(externalID string) string {
+		output, err := service.HandleMessage(ctx, MessageInput{
+			Connector:  "discord",
+			ExternalID: externalID,
+			FromUserID: "u-admin",
+			Text:       "/run-objective " + objective.ID,
+		})
+		if err != nil {
+			t.Fatalf("handle message: %v", err)
+		}
+		return output.Reply
+	}
+
+	if reply := send("chan-b"); reply != "Objective not found." {
+		t.Fatalf("expected foreign objective hidden from another workspace's channel, got %q", reply)
+	}
+	if reply := send("chan-a"); !strings.Contains(reply, "queued as task") {
+		t.Fatalf("expected objective run from its own channel, got %q", reply)
+	}
+
+	agentCtx := withContextRecord(ctx, store.ContextRecord{ID: "ctx-b", WorkspaceID: "ws-b"})
+	if scope, ok := store.WorkspaceScopeFromContext(agentCtx); !ok || scope != "ws-b" {
+		t.Fatalf("expected agent turns scoped to their workspace, got %q", scope)
+	}
+}
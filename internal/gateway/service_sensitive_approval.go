@@ -1,48 +1,153 @@
 package gateway
 
 import (
+	"context"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
 )
 
-func (s *Service) grantSensitiveToolApproval(input MessageInput, now time.Time) {
+// sensitiveGrant lets one user run the sensitive tools matching scope in
+// one channel on their next message, until it expires. Scope is a tool name
+// or a tool class; WorkspaceID is the workspace of the channel, which is the
+// only one whose admins see and revoke the grant.
+type sensitiveGrant struct {
+	ID          string
+	WorkspaceID string
+	Key         string
+	Connector   string
+	ExternalID  string
+	UserID      string
+	Scope       string
+	ApprovalID  string
+	ExpiresAt   time.Time
+}
+
+// grantSensitiveToolApproval opens a grant for scope, or extends the one
+// the user already has for it in this channel.
+func (s *Service) grantSensitiveToolApproval(input MessageInput, workspaceID, scope, approvalID string, now time.Time) {
 	if s == nil {
 		return
 	}
 	key := sensitiveApprovalKey(input)
-	if key == "" {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	if key == "" || scope == "" {
 		return
 	}
 	s.approvalMu.Lock()
 	defer s.approvalMu.Unlock()
 	cutoff := now.UTC()
-	for existingKey, expiry := range s.sensitiveApprovals {
-		if !expiry.After(cutoff) {
-			delete(s.sensitiveApprovals, existingKey)
-		}
-	}
+	s.pruneSensitiveGrantsLocked(cutoff)
 	ttl := s.sensitiveApprovalTTL
 	if ttl <= 0 {
 		ttl = 10 * time.Minute
 	}
-	s.sensitiveApprovals[key] = cutoff.Add(ttl)
+	for id, grant := range s.sensitiveGrants {
+		if grant.Key == key && grant.Scope == scope {
+			grant.ApprovalID = strings.TrimSpace(approvalID)
+			grant.ExpiresAt = cutoff.Add(ttl)
+			s.sensitiveGrants[id] = grant
+			return
+		}
+	}
+	s.sensitiveGrantSeq++
+	id := fmt.Sprintf("g%d", s.sensitiveGrantSeq)
+	s.sensitiveGrants[id] = sensitiveGrant{
+		ID:          id,
+		WorkspaceID: strings.TrimSpace(workspaceID),
+		Key:         key,
+		Connector:   strings.ToLower(strings.TrimSpace(input.Connector)),
+		ExternalID:  strings.TrimSpace(input.ExternalID),
+		UserID:      strings.TrimSpace(input.FromUserID),
+		Scope:       scope,
+		ApprovalID:  strings.TrimSpace(approvalID),
+		ExpiresAt:   cutoff.Add(ttl),
+	}
 }
-func (s *Service) consumeSensitiveToolApproval(input MessageInput, now time.Time) bool {
+
+// consumeSensitiveToolApproval removes the user's live grants in this
+// channel and returns their scopes.
+func (s *Service) consumeSensitiveToolApproval(input MessageInput, now time.Time) []string {
 	if s == nil {
-		return false
+		return nil
 	}
 	key := sensitiveApprovalKey(input)
 	if key == "" {
-		return false
+		return nil
+	}
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	s.pruneSensitiveGrantsLocked(now.UTC())
+	scopes := []string{}
+	for id, grant := range s.sensitiveGrants {
+		if grant.Key == key {
+			scopes = append(scopes, grant.Scope)
+			delete(s.sensitiveGrants, id)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// activeSensitiveGrantScopes returns the scopes of the user's live grants
+// in this channel without using them up.
+func (s *Service) activeSensitiveGrantScopes(input MessageInput, now time.Time) []string {
+	key := sensitiveApprovalKey(input)
+	scopes := []string{}
+	for _, grant := range s.activeSensitiveGrants(now, "") {
+		if grant.Key == key {
+			scopes = append(scopes, grant.Scope)
+		}
+	}
+	return scopes
+}
+
+// activeSensitiveGrants lists live grants of workspaceID, or of every
+// workspace when it is empty, soonest to expire first.
+func (s *Service) activeSensitiveGrants(now time.Time, workspaceID string) []sensitiveGrant {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	s.pruneSensitiveGrantsLocked(now.UTC())
+	grants := make([]sensitiveGrant, 0, len(s.sensitiveGrants))
+	for _, grant := range s.sensitiveGrants {
+		if workspaceID == "" || grant.WorkspaceID == workspaceID {
+			grants = append(grants, grant)
+		}
 	}
+	sort.Slice(grants, func(i, j int) bool {
+		if !grants[i].ExpiresAt.Equal(grants[j].ExpiresAt) {
+			return grants[i].ExpiresAt.Before(grants[j].ExpiresAt)
+		}
+		return grants[i].ID < grants[j].ID
+	})
+	return grants
+}
+
+// revokeSensitiveGrants removes workspaceID's grant with id, or all of its
+// grants for "all", and returns how many it removed.
+func (s *Service) revokeSensitiveGrants(workspaceID, id string) int {
 	s.approvalMu.Lock()
 	defer s.approvalMu.Unlock()
-	expiry, ok := s.sensitiveApprovals[key]
-	if !ok {
-		return false
+	id = strings.ToLower(strings.TrimSpace(id))
+	count := 0
+	for grantID, grant := range s.sensitiveGrants {
+		if grant.WorkspaceID == workspaceID && (id == "all" || grantID == id) {
+			delete(s.sensitiveGrants, grantID)
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Service) pruneSensitiveGrantsLocked(now time.Time) {
+	for id, grant := range s.sensitiveGrants {
+		if !grant.ExpiresAt.After(now) {
+			delete(s.sensitiveGrants, id)
+		}
 	}
-	delete(s.sensitiveApprovals, key)
-	return expiry.After(now.UTC())
 }
 
 func sensitiveApprovalKey(input MessageInput) string {
@@ -54,3 +159,62 @@ func sensitiveApprovalKey(input MessageInput) string {
 	}
 	return connector + "|" + externalID + "|" + fromUser
 }
+
+// approvalGrantScope is the tool an approved action stands for: the command
+// of a run_command action (e.g. curl), else the action type.
+func approvalGrantScope(record store.ActionApproval) string {
+	actionType := strings.ToLower(strings.TrimSpace(record.ActionType))
+	target := strings.ToLower(strings.TrimSpace(record.ActionTarget))
+	if actionType == "run_command" && target != "" && len(strings.Fields(target)) == 1 {
+		return target
+	}
+	return actionType
+}
+
+func (s *Service) handleGrants(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	_, denied, err := s.authorize(ctx, input, store.PermissionApproveActions)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
+	}
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		now := time.Now().UTC()
+		return MessageOutput{Handled: true, Reply: formatSensitiveGrants(s.activeSensitiveGrants(now, contextRecord.WorkspaceID), now)}, nil
+	}
+	if !strings.EqualFold(fields[0], "revoke") || len(fields) != 2 {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, "Usage: /grants [revoke <grant-id|all>]")}, nil
+	}
+	removed := s.revokeSensitiveGrants(contextRecord.WorkspaceID, strings.Trim(fields[1], "`\"'"))
+	if removed == 0 {
+		return MessageOutput{Handled: true, Reply: "Grant not found."}, nil
+	}
+	if removed == 1 {
+		return MessageOutput{Handled: true, Reply: "Revoked 1 grant."}, nil
+	}
+	return MessageOutput{Handled: true, Reply: fmt.Sprintf("Revoked %d grants.", removed)}, nil
+}
+
+func formatSensitiveGrants(grants []sensitiveGrant, now time.Time) string {
+	if len(grants) == 0 {
+		return "No active sensitive-tool grants."
+	}
+	lines := []string{fmt.Sprintf("Active sensitive-tool grants: %d", len(grants))}
+	for _, grant := range grants {
+		line := fmt.Sprintf("- `%s` %s in %s/%s: %s, expires in %s",
+			grant.ID, grant.UserID, grant.Connector, grant.ExternalID, grant.Scope,
+			grant.ExpiresAt.Sub(now).Round(time.Second))
+		if grant.ApprovalID != "" {
+			line += fmt.Sprintf(" (from `%s`)", grant.ApprovalID)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "Revoke with /grants revoke <grant-id|all>.")
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestSensitiveGrantOnlyCoversApprovedTool(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+		actionApprovals: []store.ActionApproval{
+			{ID: "act-1", ActionType: "run_command", ActionTarget: "curl", Status: "pending"},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	service.SetTriageAcknowledger(&fakeTriageAcknowledger{
+		replies: []string{
+			`{"tool":"create_objective","args":{"title":"Watch spam","prompt":"Monitor repeated spam"}}`,
+		},
+	})
+	input := MessageInput{Connector: "telegram", ExternalID: "42", DisplayName: "ops", FromUserID: "u1"}
+
+	approve := input
+	approve.Text = "/approve-action act-1"
+	if _, err := service.HandleMessage(context.Background(), approve); err != nil {
+		t.Fatalf("approve-action failed: %v", err)
+	}
+	if scopes := service.activeSensitiveGrantScopes(input, time.Now().UTC()); len(scopes) != 1 || scopes[0] != "curl" {
+		t.Fatalf("expected a grant scoped to curl, got %v", scopes)
+	}
+
+	message := input
+	message.Text = "how are you today?"
+	output, err := service.HandleMessage(context.Background(), message)
+	if err != nil {
+		t.Fatalf("fallback run failed: %v", err)
+	}
+	if fStore.objectiveInvoked {
+		t.Fatal("expected a curl grant not to unlock create_objective")
+	}
+	if !strings.Contains(strings.ToLower(output.Reply), "approval") {
+		t.Fatalf("expected approval-required reply, got %q", output.Reply)
+	}
+}
+
+func TestGrantsCommandListsAndRevokes(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	input := MessageInput{Connector: "telegram", ExternalID: "42", FromUserID: "u1"}
+	now := time.Now().UTC()
+	service.grantSensitiveToolApproval(input, "ws-1", "curl", "act-1", now)
+	service.grantSensitiveToolApproval(input, "ws-1", "Sensitive", "", now)
+	service.grantSensitiveToolApproval(input, "ws-1", "curl", "act-2", now)
+
+	list := input
+	list.Text = "/grants"
+	output, err := service.HandleMessage(context.Background(), list)
+	if err != nil {
+		t.Fatalf("grants failed: %v", err)
+	}
+	if !strings.Contains(output.Reply, "Active sensitive-tool grants: 2") ||
+		!strings.Contains(output.Reply, "curl") || !strings.Contains(output.Reply, "act-2") ||
+		!strings.Contains(output.Reply, "sensitive") {
+		t.Fatalf("unexpected grants listing: %q", output.Reply)
+	}
+
+	revoke := input
+	revoke.Text = "/grants revoke g1"
+	output, err = service.HandleMessage(context.Background(), revoke)
+	if err != nil {
+		t.Fatalf("grants revoke failed: %v", err)
+	}
+	if output.Reply != "Revoked 1 grant." {
+		t.Fatalf("unexpected revoke reply: %q", output.Reply)
+	}
+	if scopes := service.activeSensitiveGrantScopes(input, now); len(scopes) != 1 || scopes[0] != "sensitive" {
+		t.Fatalf("expected only the sensitive grant left, got %v", scopes)
+	}
+
+	revoke.Text = "/grants revoke all"
+	if output, _ = service.HandleMessage(context.Background(), revoke); output.Reply != "Revoked 1 grant." {
+		t.Fatalf("unexpected revoke-all reply: %q", output.Reply)
+	}
+	if output, _ = service.HandleMessage(context.Background(), list); output.Reply != "No active sensitive-tool grants." {
+		t.Fatalf("expected no grants, got %q", output.Reply)
+	}
+}
+
+func TestGrantsCommandOnlySeesOwnWorkspace(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	input := MessageInput{Connector: "telegram", ExternalID: "42", FromUserID: "u1"}
+	other := MessageInput{Connector: "discord", ExternalID: "99", FromUserID: "u2"}
+	now := time.Now().UTC()
+	service.grantSensitiveToolApproval(input, "ws-1", "curl", "", now)
+	service.grantSensitiveToolApproval(other, "ws-2", "curl", "", now)
+
+	list := input
+	list.Text = "/grants"
+	output, err := service.HandleMessage(context.Background(), list)
+	if err != nil {
+		t.Fatalf("grants failed: %v", err)
+	}
+	if !strings.Contains(output.Reply, "Active sensitive-tool grants: 1") || strings.Contains(output.Reply, "u2") {
+		t.Fatalf("expected only this workspace's grant, got %q", output.Reply)
+	}
+
+	revoke := input
+	revoke.Text = "/grants revoke g2"
+	if output, _ = service.HandleMessage(context.Background(), revoke); output.Reply != "Grant not found." {
+		t.Fatalf("expected another workspace's grant to be out of reach, got %q", output.Reply)
+	}
+	revoke.Text = "/grants revoke all"
+	if output, _ = service.HandleMessage(context.Background(), revoke); output.Reply != "Revoked 1 grant." {
+		t.Fatalf("unexpected revoke-all reply: %q", output.Reply)
+	}
+	if scopes := service.activeSensitiveGrantScopes(other, now); len(scopes) != 1 {
+		t.Fatalf("expected the other workspace's grant to survive, got %v", scopes)
+	}
+}
+
+func TestGrantsCommandRequiresAdmin(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "u1", Role: "member"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector: "telegram", ExternalID: "42", FromUserID: "u1", Text: "/grants",
+	})
+	if err != nil {
+		t.Fatalf("grants failed: %v", err)
+	}
+	if output.Reply != "Access denied: approve_actions permission required." {
+		t.Fatalf("unexpected reply: %q", output.Reply)
+	}
+}
@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/qmd"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const sharedKnowledgeUsage = "Usage: /shared-knowledge on | /shared-knowledge off | /shared-knowledge status"
+
+// SetSharedKnowledgeWorkspace records the shared knowledge workspace, so
+// /shared-knowledge can name it and tell admins when none is configured.
+func (s *Service) SetSharedKnowledgeWorkspace(workspaceID string) {
+	s.sharedWorkspace = strings.TrimSpace(workspaceID)
+}
+
+func (s *Service) handleSharedKnowledge(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrIdentityNotFound) {
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedLink)}, nil
+		}
+		return MessageOutput{}, err
+	}
+	if !isAdminRole(identity.Role) {
+		return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedAdmin)}, nil
+	}
+
+	var policy store.ContextPolicy
+	switch strings.ToLower(strings.TrimSpace(arg)) {
+	case "on", "enable":
+		policy, err = s.store.SetContextSharedKnowledgeByExternal(ctx, input.Connector, input.ExternalID, true)
+	case "off", "disable":
+		policy, err = s.store.SetContextSharedKnowledgeByExternal(ctx, input.Connector, input.ExternalID, false)
+	case "status":
+		policy, err = s.store.LookupContextPolicyByExternal(ctx, input.Connector, input.ExternalID)
+		if errors.Is(err, store.ErrContextNotFound) {
+			policy, err = store.ContextPolicy{}, nil
+		}
+	default:
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, sharedKnowledgeUsage)}, nil
+	}
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if !policy.SharedKnowledge {
+		return MessageOutput{Handled: true, Reply: "Shared knowledge is off for this channel."}, nil
+	}
+	if s.sharedWorkspace == "" {
+		return MessageOutput{Handled: true, Reply: "Shared knowledge is on for this channel, but no shared knowledge workspace is configured."}, nil
+	}
+	if s.sharedWorkspace == policy.WorkspaceID {
+		return MessageOutput{Handled: true, Reply: "Shared knowledge is on for this channel, which already belongs to the shared knowledge workspace."}, nil
+	}
+	return MessageOutput{
+		Handled: true,
+		Reply:   "Shared knowledge is on for this channel: searches also cover `" + s.sharedWorkspace + "`, labeled `" + qmd.SharedTargetPrefix + "`.",
+	}, nil
+}
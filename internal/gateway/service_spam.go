@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/spam"
+)
+
+// SpamFilter scores inbound messages for spam and bot patterns.
+type SpamFilter interface {
+	Check(message spam.Message) spam.Verdict
+}
+
+// SetSpamFilter holds spam and bot messages for moderation before triage.
+func (s *Service) SetSpamFilter(filter SpamFilter) {
+	s.spamFilter = filter
+}
+
+// holdSpam short-circuits a message the spam filter flags: admins get a
+// moderation notice, and neither the agent nor a connector fallback replies.
+func (s *Service) holdSpam(ctx context.Context, input MessageInput, text string) (MessageOutput, bool) {
+	if s.spamFilter == nil {
+		return MessageOutput{}, false
+	}
+	verdict := s.spamFilter.Check(spam.Message{
+		Connector:        input.Connector,
+		ExternalID:       input.ExternalID,
+		UserID:           input.FromUserID,
+		Text:             text,
+		IsBot:            input.IsBot,
+		AccountCreatedAt: input.AccountCreatedAt,
+	})
+	if !verdict.Spam {
+		return MessageOutput{}, false
+	}
+	s.logger.Warn("message held by spam filter",
+		"connector", input.Connector,
+		"external_id", input.ExternalID,
+		"user_id", input.FromUserID,
+		"score", verdict.Score,
+		"reason", verdict.Reason(),
+	)
+	if s.store != nil && s.routingNotify != nil {
+		contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+		if err != nil {
+			s.logger.Error("spam filter context lookup failed", "error", err)
+		} else {
+			priority, dueWindow, lane := routingDefaults(TriageModeration)
+			decision := RouteDecision{
+				WorkspaceID:      contextRecord.WorkspaceID,
+				ContextID:        contextRecord.ID,
+				Class:            TriageModeration,
+				Priority:         priority,
+				DueWindow:        dueWindow,
+				AssignedLane:     lane,
+				SourceConnector:  strings.TrimSpace(input.Connector),
+				SourceExternalID: strings.TrimSpace(input.ExternalID),
+				SourceUserID:     strings.TrimSpace(input.FromUserID),
+				SourceText:       text,
+				Reason:           "spam filter: " + verdict.Reason(),
+			}
+			decision.CaseID = s.recordModerationDecision(ctx, decision, "held")
+			decision.TrackingCaseID = s.openDecisionCase(ctx, decision)
+			s.routingNotify.NotifyRoutingDecision(ctx, decision)
+		}
+	}
+	return MessageOutput{Handled: true, Suppressed: true}, true
+}
@@ -0,0 +1,204 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	tasksUsage      = "Usage: /tasks dead|failed|queued|running"
+	tasksListLimit  = 15
+	cancelTaskUsage = "Usage: /cancel-task <task-id>"
+	artifactsUsage  = "Usage: /artifacts <task-id>"
+)
+
+// TaskLister lists tasks of a workspace.
+type TaskLister interface {
+	ListTasks(ctx context.Context, input store.ListTasksInput) ([]store.TaskRecord, error)
+}
+
+// SetTaskLister enables /tasks.
+func (s *Service) SetTaskLister(lister TaskLister) {
+	s.taskLister = lister
+}
+
+// TaskCanceller records a task as cancelled and stops it if it is running.
+type TaskCanceller interface {
+	CancelTask(ctx context.Context, id string) (store.TaskRecord, error)
+}
+
+// SetTaskCanceller enables /cancel-task.
+func (s *Service) SetTaskCanceller(canceller TaskCanceller) {
+	s.taskCanceller = canceller
+}
+
+// handleTasks lists the channel workspace's tasks in one state. `dead`
+// lists the dead-lettered tasks: those that failed every retry.
+func (s *Service) handleTasks(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if s.taskLister == nil {
+		return MessageOutput{Handled: true, Reply: "Task listings are unavailable in this runtime."}, nil
+	}
+	_, denied, err := s.authorize(ctx, input, store.PermissionRouteTasks)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
+	}
+	query := store.ListTasksInput{Limit: tasksListLimit}
+	state := strings.ToLower(strings.TrimSpace(arg))
+	switch state {
+	case "dead", "dead-letter", "dead-lettered":
+		state = "dead"
+		query.DeadLettered = true
+	case "failed", "queued", "running":
+		query.Status = state
+	default:
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, tasksUsage)}, nil
+	}
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	query.WorkspaceID = contextRecord.WorkspaceID
+	tasks, err := s.taskLister.ListTasks(ctx, query)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	return MessageOutput{Handled: true, Reply: formatTaskListing(state, tasks)}, nil
+}
+
+// handleCancelTask cancels a queued or running task of the channel
+// workspace. A running task stops at its next model or tool call.
+func (s *Service) handleCancelTask(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if s.taskCanceller == nil {
+		return MessageOutput{Handled: true, Reply: "Task cancellation is unavailable in this runtime."}, nil
+	}
+	_, denied, err := s.authorize(ctx, input, store.PermissionRouteTasks)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if denied != "" {
+		return MessageOutput{Handled: true, Reply: denied}, nil
+	}
+	taskID := strings.Trim(strings.TrimSpace(arg), "`\"'")
+	if taskID == "" || len(strings.Fields(taskID)) > 1 {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, cancelTaskUsage)}, nil
+	}
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	task, err := s.store.LookupTask(ctx, taskID)
+	if errors.Is(err, store.ErrTaskNotFound) || (err == nil && task.WorkspaceID != contextRecord.WorkspaceID) {
+		return MessageOutput{Handled: true, Reply: "Task not found in this workspace."}, nil
+	}
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	previous := strings.ToLower(strings.TrimSpace(task.Status))
+	task, err = s.taskCanceller.CancelTask(ctx, task.ID)
+	if errors.Is(err, store.ErrTaskNotActive) {
+		return MessageOutput{Handled: true, Reply: fmt.Sprintf("Task `%s` is already %s.", task.ID, task.Status)}, nil
+	}
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if previous == "running" {
+		return MessageOutput{Handled: true, Reply: fmt.Sprintf("Task `%s` (%s) cancelled; its worker stops at the next step.", task.ID, truncateToolLogField(task.Title, 80))}, nil
+	}
+	return MessageOutput{Handled: true, Reply: fmt.Sprintf("Task `%s` (%s) cancelled before it ran.", task.ID, truncateToolLogField(task.Title, 80))}, nil
+}
+
+// TaskArtifactLister lists the files a task produced.
+type TaskArtifactLister interface {
+	ListTaskArtifacts(ctx context.Context, taskID string) ([]store.TaskArtifact, error)
+}
+
+// SetTaskArtifacts enables /artifacts; a non-nil signer adds download links.
+func (s *Service) SetTaskArtifacts(lister TaskArtifactLister, links *artifactlink.Signer) {
+	s.taskArtifacts = lister
+	s.artifactLinks = links
+}
+
+// handleArtifacts lists the artifacts of a task of the channel workspace.
+// The person who asked for the task may list them; others need the task
+// routing permission.
+func (s *Service) handleArtifacts(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	if s.taskArtifacts == nil {
+		return MessageOutput{Handled: true, Reply: "Task artifacts are unavailable in this runtime."}, nil
+	}
+	taskID := strings.Trim(strings.TrimSpace(arg), "`\"'")
+	if taskID == "" || len(strings.Fields(taskID)) > 1 {
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, artifactsUsage)}, nil
+	}
+	contextRecord, err := s.store.EnsureContextForExternalChannel(ctx, input.Connector, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	task, err := s.store.LookupTask(ctx, taskID)
+	if errors.Is(err, store.ErrTaskNotFound) || (err == nil && task.WorkspaceID != contextRecord.WorkspaceID) {
+		return MessageOutput{Handled: true, Reply: "Task not found in this workspace."}, nil
+	}
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if task.SourceUserID == "" || task.SourceUserID != strings.TrimSpace(input.FromUserID) {
+		_, denied, err := s.authorize(ctx, input, store.PermissionRouteTasks)
+		if err != nil {
+			return MessageOutput{}, err
+		}
+		if denied != "" {
+			return MessageOutput{Handled: true, Reply: denied}, nil
+		}
+	}
+	artifacts, err := s.taskArtifacts.ListTaskArtifacts(ctx, task.ID)
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	if len(artifacts) == 0 {
+		return MessageOutput{Handled: true, Reply: fmt.Sprintf("Task `%s` has no artifacts.", task.ID)}, nil
+	}
+	lines := []string{fmt.Sprintf("Artifacts of task `%s` (%s):", task.ID, truncateToolLogField(task.Title, 80))}
+	for _, artifact := range artifacts {
+		line := fmt.Sprintf("- %s [%s, %s, %d bytes]: `%s`", artifact.Title, artifact.Kind, artifact.MediaType, artifact.SizeBytes, artifact.Path)
+		if link := s.artifactLinks.Link(artifact.ID); link != "" {
+			line += " " + link
+		}
+		lines = append(lines, line)
+	}
+	return MessageOutput{Handled: true, Reply: strings.Join(lines, "\n")}, nil
+}
+
+func formatTaskListing(state string, tasks []store.TaskRecord) string {
+	if len(tasks) == 0 {
+		if state == "dead" {
+			return "No dead-lettered tasks."
+		}
+		return fmt.Sprintf("No %s tasks.", state)
+	}
+	heading := fmt.Sprintf("%s tasks (most recent first): %d", strings.ToUpper(state[:1])+state[1:], len(tasks))
+	if state == "dead" {
+		heading = fmt.Sprintf("Dead-lettered tasks (most recent first): %d", len(tasks))
+	}
+	lines := []string{heading}
+	for _, task := range tasks {
+		line := fmt.Sprintf("- `%s` %s (%s, attempts %d)", task.ID, truncateToolLogField(task.Title, 80), task.Kind, task.Attempts)
+		if !task.DeadLetteredAt.IsZero() {
+			line += ", dead-lettered " + task.DeadLetteredAt.UTC().Format("2006-01-02 15:04")
+		}
+		if message := strings.TrimSpace(task.ErrorMessage); message != "" {
+			line += ": " + truncateToolLogField(message, 120)
+		}
+		lines = append(lines, line)
+	}
+	if state == "dead" || state == "failed" {
+		lines = append(lines, "Retry one with `agent-runtime admin tasks retry <task-id>` once the cause is fixed.")
+	}
+	return strings.Join(lines, "\n")
+}
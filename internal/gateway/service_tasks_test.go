@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeTaskLister struct {
+	query store.ListTasksInput
+	tasks []store.TaskRecord
+}
+
+func (f *fakeTaskLister) ListTasks(ctx context.Context, input store.ListTasksInput) ([]store.TaskRecord, error) {
+	f.query = input
+	return f.tasks, nil
+}
+
+func TestTasksCommandListsDeadLetteredTasks(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	lister := &fakeTaskLister{tasks: []store.TaskRecord{{
+		ID:             "task-1",
+		Kind:           "objective",
+		Title:          "Nightly digest",
+		Status:         "failed",
+		Attempts:       5,
+		ErrorMessage:   "task retries exhausted after 5 attempts: upstream returned 503",
+		DeadLetteredAt: time.Date(2026, 10, 17, 4, 0, 0, 0, time.UTC),
+	}}}
+	service.SetTaskLister(lister)
+	send := func(text string) string {
+		output, err := service.HandleMessage(context.Background(), MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "admin-1", Text: text})
+		if err != nil {
+			t.Fatalf("%s failed: %v", text, err)
+		}
+		return output.Reply
+	}
+
+	reply := send("/tasks dead")
+	if !strings.Contains(reply, "- `task-1` Nightly digest (objective, attempts 5), dead-lettered 2026-10-17 04:00: task retries exhausted") {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if lister.query.WorkspaceID != "ws-1" || !lister.query.DeadLettered || lister.query.Status != "" {
+		t.Fatalf("unexpected query %+v", lister.query)
+	}
+	if reply := send("/tasks later"); reply != tasksUsage {
+		t.Fatalf("expected usage, got %q", reply)
+	}
+
+	fStore.identity.Role = "member"
+	if reply := send("/tasks dead"); !strings.HasPrefix(reply, "Access denied") {
+		t.Fatalf("expected members refused, got %q", reply)
+	}
+}
+
+type fakeTaskCanceller struct {
+	cancelled []string
+}
+
+func (f *fakeTaskCanceller) CancelTask(ctx context.Context, id string) (store.TaskRecord, error) {
+	f.cancelled = append(f.cancelled, id)
+	if id == "task-done" {
+		return store.TaskRecord{ID: id, Status: "succeeded"}, store.ErrTaskNotActive
+	}
+	return store.TaskRecord{ID: id, Title: "Vendor research", Status: "cancelled"}, nil
+}
+
+func TestCancelTaskCommand(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+		tasks: map[string]store.TaskRecord{
+			"task-1":     {ID: "task-1", WorkspaceID: "ws-1", Title: "Vendor research", Status: "running"},
+			"task-done":  {ID: "task-done", WorkspaceID: "ws-1", Status: "succeeded"},
+			"task-other": {ID: "task-other", WorkspaceID: "ws-2", Status: "queued"},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	canceller := &fakeTaskCanceller{}
+	service.SetTaskCanceller(canceller)
+	send := func(text string) string {
+		output, err := service.HandleMessage(context.Background(), MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "admin-1", Text: text})
+		if err != nil {
+			t.Fatalf("%s failed: %v", text, err)
+		}
+		return output.Reply
+	}
+
+	if reply := send("/cancel-task task-1"); !strings.Contains(reply, "Task `task-1` (Vendor research) cancelled; its worker stops") {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if reply := send("/cancel-task task-done"); reply != "Task `task-done` is already succeeded." {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if reply := send("/cancel-task task-other"); reply != "Task not found in this workspace." {
+		t.Fatalf("expected other workspaces hidden, got %q", reply)
+	}
+	if reply := send("/cancel-task"); reply != cancelTaskUsage {
+		t.Fatalf("expected usage, got %q", reply)
+	}
+	if len(canceller.cancelled) != 2 {
+		t.Fatalf("unexpected cancellations %v", canceller.cancelled)
+	}
+
+	fStore.identity.Role = "member"
+	if reply := send("/cancel-task task-1"); !strings.HasPrefix(reply, "Access denied") {
+		t.Fatalf("expected members refused, got %q", reply)
+	}
+}
+
+type fakeTaskArtifactLister struct {
+	artifacts map[string][]store.TaskArtifact
+}
+
+func (f *fakeTaskArtifactLister) ListTaskArtifacts(ctx context.Context, taskID string) ([]store.TaskArtifact, error) {
+	return f.artifacts[taskID], nil
+}
+
+func TestArtifactsCommand(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "member-1", Role: "member"},
+		tasks: map[string]store.TaskRecord{
+			"task-mine":  {ID: "task-mine", WorkspaceID: "ws-1", Title: "Vendor research", SourceUserID: "member-1"},
+			"task-admin": {ID: "task-admin", WorkspaceID: "ws-1", Title: "Audit", SourceUserID: "admin-1"},
+			"task-other": {ID: "task-other", WorkspaceID: "ws-2", SourceUserID: "member-1"},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	service.SetTaskArtifacts(&fakeTaskArtifactLister{artifacts: map[string][]store.TaskArtifact{
+		"task-mine": {{ID: "artifact_1", Path: "scratch/vendors.csv", Kind: "file", Title: "vendors.csv", MediaType: "text/csv", SizeBytes: 21}},
+	}}, artifactlink.New("https://bot.example.com", "secret", time.Hour))
+	send := func(text string) string {
+		output, err := service.HandleMessage(context.Background(), MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "member-1", Text: text})
+		if err != nil {
+			t.Fatalf("%s failed: %v", text, err)
+		}
+		return output.Reply
+	}
+
+	reply := send("/artifacts task-mine")
+	if !strings.Contains(reply, "- vendors.csv [file, text/csv, 21 bytes]: `scratch/vendors.csv` https://bot.example.com/artifacts/download?") {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if reply := send("/artifacts task-admin"); !strings.HasPrefix(reply, "Access denied") {
+		t.Fatalf("expected another member's task refused, got %q", reply)
+	}
+	if reply := send("/artifacts task-other"); reply != "Task not found in this workspace." {
+		t.Fatalf("expected other workspaces hidden, got %q", reply)
+	}
+	if reply := send("/artifacts"); reply != artifactsUsage {
+		t.Fatalf("expected usage, got %q", reply)
+	}
+
+	fStore.identity = store.UserIdentity{UserID: "member-1", Role: "admin"}
+	if reply := send("/artifacts task-admin"); reply != "Task `task-admin` has no artifacts." {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"time"
 
 	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/canary"
 	"github.com/dwizi/agent-runtime/internal/llm"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
 	"github.com/dwizi/agent-runtime/internal/qmd"
+	"github.com/dwizi/agent-runtime/internal/spam"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -20,6 +22,8 @@ type fakeStore struct {
 	contextRecord          store.ContextRecord
 	contextPolicy          store.ContextPolicy
 	identity               store.UserIdentity
+	rolePermissions        map[string][]store.Permission
+	workspaceRoles         map[string]map[string][]store.Permission
 	identityErr            error
 	lastTask               store.CreateTaskInput
 	tasks                  map[string]store.TaskRecord
@@ -59,16 +63,41 @@ func (f *fakeStore) LookupContextPolicyByExternal(ctx context.Context, connector
 	return f.contextPolicy, nil
 }
 
-func (f *fakeStore) SetContextSystemPromptByExternal(ctx context.Context, connector, externalID, prompt string) (store.ContextPolicy, error) {
+func (f *fakeStore) SetContextSystemPromptByExternal(ctx context.Context, connector, externalID, prompt string, expectedRevision int) (store.ContextPolicy, error) {
+	if expectedRevision > 0 && expectedRevision != f.contextPolicy.Revision {
+		return store.ContextPolicy{}, store.ErrRevisionConflict
+	}
 	f.contextPolicy = store.ContextPolicy{
 		ContextID:    "ctx-1",
 		WorkspaceID:  "ws-1",
 		IsAdmin:      false,
 		SystemPrompt: strings.TrimSpace(prompt),
+		Revision:     f.contextPolicy.Revision + 1,
 	}
 	return f.contextPolicy, nil
 }
 
+func (f *fakeStore) SetContextVoiceRepliesByExternal(ctx context.Context, connector, externalID string, enabled bool) (store.ContextPolicy, error) {
+	f.contextPolicy.ContextID = "ctx-1"
+	f.contextPolicy.WorkspaceID = "ws-1"
+	f.contextPolicy.VoiceReplies = enabled
+	return f.contextPolicy, nil
+}
+
+func (f *fakeStore) SetContextSharedKnowledgeByExternal(ctx context.Context, connector, externalID string, enabled bool) (store.ContextPolicy, error) {
+	f.contextPolicy.ContextID = "ctx-1"
+	f.contextPolicy.WorkspaceID = "ws-1"
+	f.contextPolicy.SharedKnowledge = enabled
+	return f.contextPolicy, nil
+}
+
+func (f *fakeStore) SetContextLanguageByExternal(ctx context.Context, connector, externalID, language string) (store.ContextPolicy, error) {
+	f.contextPolicy.ContextID = "ctx-1"
+	f.contextPolicy.WorkspaceID = "ws-1"
+	f.contextPolicy.Language = language
+	return f.contextPolicy, nil
+}
+
 func (f *fakeStore) LookupUserIdentity(ctx context.Context, connector, connectorUserID string) (store.UserIdentity, error) {
 	if f.identityErr != nil {
 		return store.UserIdentity{}, f.identityErr
@@ -168,6 +197,7 @@ func (f *fakeStore) CreateActionApproval(ctx context.Context, input store.Create
 		ActionType:    input.ActionType,
 		ActionTarget:  input.ActionTarget,
 		ActionSummary: input.ActionSummary,
+		Payload:       input.Payload,
 		Status:        "pending",
 	}
 	f.actionApprovals = append(f.actionApprovals, record)
@@ -208,6 +238,17 @@ func (f *fakeStore) ApproveActionApproval(ctx context.Context, input store.Appro
 			if f.actionApprovals[index].Status != "pending" {
 				return store.ActionApproval{}, store.ErrActionApprovalNotReady
 			}
+			if required := f.actionApprovals[index].RequiredApprovals; required > 1 {
+				for _, signoff := range f.actionApprovals[index].Signoffs {
+					if signoff.UserID == input.ApproverUserID {
+						return f.actionApprovals[index], store.ErrActionApprovalAlreadySigned
+					}
+				}
+				f.actionApprovals[index].Signoffs = append(f.actionApprovals[index].Signoffs, store.ApprovalSignoff{UserID: input.ApproverUserID, ApprovedAt: time.Now()})
+				if len(f.actionApprovals[index].Signoffs) < required {
+					return f.actionApprovals[index], store.ErrActionApprovalQuorumPending
+				}
+			}
 			f.actionApprovals[index].Status = "approved"
 			f.actionApprovals[index].ApproverUserID = input.ApproverUserID
 			return f.actionApprovals[index], nil
@@ -231,6 +272,15 @@ func (f *fakeStore) DenyActionApproval(ctx context.Context, input store.DenyActi
 	return store.ActionApproval{}, store.ErrActionApprovalNotFound
 }
 
+func (f *fakeStore) LookupActionApproval(ctx context.Context, id string) (store.ActionApproval, error) {
+	for _, record := range f.actionApprovals {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return store.ActionApproval{}, store.ErrActionApprovalNotFound
+}
+
 func (f *fakeStore) UpdateActionExecution(ctx context.Context, input store.UpdateActionExecutionInput) (store.ActionApproval, error) {
 	f.executionUpdateInvoked = true
 	f.lastExecutionUpdate = input
@@ -357,11 +407,12 @@ type fakeActionExecutor struct {
 }
 
 type fakeTriageAcknowledger struct {
-	reply     string
-	replies   []string
-	err       error
-	callCount int
-	lastInput llm.MessageInput
+	reply       string
+	replies     []string
+	err         error
+	callCount   int
+	lastInput   llm.MessageInput
+	lastVariant llm.Variant
 }
 
 type fakeRoutingNotifier struct {
@@ -369,11 +420,49 @@ type fakeRoutingNotifier struct {
 	invoked      bool
 }
 
+type fakeCanaryRouter struct {
+	assignment canary.Assignment
+	recorded   []canary.Assignment
+	errored    bool
+}
+
+type fakeDegradation struct {
+	available bool
+	deferred  []orchestrator.Task
+}
+
+func (f *fakeDegradation) Available() bool {
+	return f.available
+}
+
+func (f *fakeDegradation) Defer(task orchestrator.Task) {
+	f.deferred = append(f.deferred, task)
+}
+
+func (f *fakeCanaryRouter) Assign(ctx context.Context, workspaceID, contextID string) canary.Assignment {
+	return f.assignment
+}
+
+func (f *fakeCanaryRouter) Record(ctx context.Context, assignment canary.Assignment, errored, blocked bool) {
+	f.recorded = append(f.recorded, assignment)
+	f.errored = errored
+}
+
 func (f *fakeRoutingNotifier) NotifyRoutingDecision(ctx context.Context, decision RouteDecision) {
 	f.lastDecision = decision
 	f.invoked = true
 }
 
+type fakeTaskSyncer struct {
+	synced []string
+	err    error
+}
+
+func (f *fakeTaskSyncer) SyncTask(ctx context.Context, taskID string) error {
+	f.synced = append(f.synced, taskID)
+	return f.err
+}
+
 func (f *fakeActionExecutor) Execute(ctx context.Context, approval store.ActionApproval) (executor.Result, error) {
 	if f.err != nil {
 		return executor.Result{}, f.err
@@ -384,6 +473,7 @@ func (f *fakeActionExecutor) Execute(ctx context.Context, approval store.ActionA
 func (f *fakeTriageAcknowledger) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
 	f.callCount++
 	f.lastInput = input
+	f.lastVariant = llm.VariantFrom(ctx)
 	if f.err != nil {
 		return "", f.err
 	}
@@ -635,6 +725,50 @@ func TestHandleAutoTriageCreatesTaskAndNotifies(t *testing.T) {
 	}
 }
 
+func TestTaskSyncerRunsOnTriageAndRouteOverride(t *testing.T) {
+	fStore := &fakeStore{
+		contextPolicy: store.ContextPolicy{
+			ContextID:   "ctx-admin",
+			WorkspaceID: "ws-1",
+			IsAdmin:     true,
+		},
+		identity: store.UserIdentity{
+			UserID: "admin-1",
+			Role:   "admin",
+		},
+		tasks: map[string]store.TaskRecord{
+			"task-1": {ID: "task-1", WorkspaceID: "ws-1", ContextID: "ctx-1"},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
+	syncer := &fakeTaskSyncer{err: errors.New("tracker down")}
+	service.SetTaskSyncer(syncer)
+
+	if _, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "There is a bug in the onboarding flow and it keeps failing",
+	}); err != nil {
+		t.Fatalf("handle triage message failed: %v", err)
+	}
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "admin-user",
+		Text:       "/route task-1 issue p1 2h",
+	})
+	if err != nil {
+		t.Fatalf("sync failure must not fail /route: %v", err)
+	}
+	if !strings.Contains(output.Reply, "Routing updated") {
+		t.Fatalf("unexpected reply %q", output.Reply)
+	}
+	if len(syncer.synced) != 2 || syncer.synced[0] != fStore.lastTask.ID || syncer.synced[1] != "task-1" {
+		t.Fatalf("unexpected synced tasks %v", syncer.synced)
+	}
+}
+
 func TestHandleAutoTriageUsesLLMAckWhenAvailable(t *testing.T) {
 	fStore := &fakeStore{}
 	fEngine := &fakeEngine{}
@@ -671,6 +805,100 @@ func TestHandleAutoTriageUsesLLMAckWhenAvailable(t *testing.T) {
 	}
 }
 
+func TestHandleAgentAutoTriageAppliesAndRecordsCanaryAssignment(t *testing.T) {
+	service := New(&fakeStore{}, &fakeEngine{}, nil, nil, "", nil)
+	ack := &fakeTriageAcknowledger{reply: "I ran some checks and here is the answer."}
+	service.SetTriageAcknowledger(ack)
+	router := &fakeCanaryRouter{assignment: canary.Assignment{
+		Arms:    []canary.Arm{{RolloutID: "canary_1", Kind: store.CanaryModel, Canary: true}},
+		Variant: llm.Variant{Model: "gpt-candidate"},
+	}}
+	service.SetCanaryRouter(router)
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "how are you today?",
+	})
+	if err != nil || !output.Handled {
+		t.Fatalf("expected agent reply, got %+v (%v)", output, err)
+	}
+	if ack.lastVariant.Model != "gpt-candidate" {
+		t.Fatalf("expected the canary model to reach the responder, got %+v", ack.lastVariant)
+	}
+	if len(router.recorded) != 1 || router.recorded[0].Arms[0].RolloutID != "canary_1" || router.errored {
+		t.Fatalf("expected one successful canary outcome, got %+v", router.recorded)
+	}
+}
+
+func TestHandleMessageServesFAQAndDefersTasksWhileModelIsDown(t *testing.T) {
+	root := t.TempDir()
+	faqPath := filepath.Join(root, "ws-1", "context", "FAQ.md")
+	if err := os.MkdirAll(filepath.Dir(faqPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(faqPath, []byte("# Frequently Asked Questions\n\n## Who owns releases?\n\nThe release manager on this week's rota.\n"), 0o644); err != nil {
+		t.Fatalf("write faq: %v", err)
+	}
+	fStore := &fakeStore{}
+	fEngine := &fakeEngine{}
+	service := New(fStore, fEngine, nil, nil, root, nil)
+	ack := &fakeTriageAcknowledger{reply: "unused"}
+	service.SetTriageAcknowledger(ack)
+	degradation := &fakeDegradation{available: false}
+	service.SetDegradation(degradation)
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{Connector: "telegram", ExternalID: "42", FromUserID: "u1", Text: "who owns the releases?"})
+	if err != nil || output.Reply != "The release manager on this week's rota." {
+		t.Fatalf("expected the curated answer, got %+v (%v)", output, err)
+	}
+
+	output, err = service.HandleMessage(context.Background(), MessageInput{Connector: "telegram", ExternalID: "42", FromUserID: "u1", Text: "The deploy pipeline is failing on staging, please investigate"})
+	if err != nil || !output.Handled || !strings.Contains(output.Reply, "will reply here once it is back") {
+		t.Fatalf("expected a deferred task acknowledgement, got %+v (%v)", output, err)
+	}
+	if len(degradation.deferred) != 1 || degradation.deferred[0].ID != fStore.lastTask.ID || fStore.lastTask.Status != "queued" {
+		t.Fatalf("expected the persisted task to be deferred, got %+v / %+v", degradation.deferred, fStore.lastTask)
+	}
+	if fEngine.lastTask.ID != "" || ack.callCount != 0 {
+		t.Fatal("expected no engine task or model call while the model is down")
+	}
+}
+
+func TestHandleMessageHoldsSpamForModeration(t *testing.T) {
+	fStore := &fakeStore{}
+	fEngine := &fakeEngine{}
+	service := New(fStore, fEngine, nil, nil, "", nil)
+	ack := &fakeTriageAcknowledger{reply: "unused"}
+	service.SetTriageAcknowledger(ack)
+	notifier := &fakeRoutingNotifier{}
+	service.SetRoutingNotifier(notifier)
+	service.SetSpamFilter(spam.New(spam.Config{}))
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "discord",
+		ExternalID: "chan-1",
+		FromUserID: "u1",
+		Text:       "@everyone free nitro giveaway, please report the outage here https://discord.gg/abc",
+	})
+	if err != nil || !output.Handled || !output.Suppressed || output.Reply != "" {
+		t.Fatalf("expected a suppressed message without reply, got %+v (%v)", output, err)
+	}
+	if fStore.lastTask.ID != "" || fEngine.lastTask.ID != "" || ack.callCount != 0 {
+		t.Fatal("expected no task or model call for spam")
+	}
+	if !notifier.invoked || notifier.lastDecision.Class != TriageModeration || notifier.lastDecision.TaskID != "" ||
+		!strings.Contains(notifier.lastDecision.Reason, "spam filter") {
+		t.Fatalf("expected a moderation notice without task, got %+v", notifier.lastDecision)
+	}
+
+	output, err = service.HandleMessage(context.Background(), MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "u2", Text: "how are you today?"})
+	if err != nil || output.Suppressed {
+		t.Fatalf("expected ordinary message to pass the filter, got %+v (%v)", output, err)
+	}
+}
+
 func TestHandleAutoTriageQuestionWithoutFollowUpSkipsTask(t *testing.T) {
 	fStore := &fakeStore{}
 	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
@@ -967,7 +1195,7 @@ func TestHandleAutoTriageSensitiveApprovalTokenFromApproveAction(t *testing.T) {
 	fStore := &fakeStore{
 		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
 		actionApprovals: []store.ActionApproval{
-			{ID: "act-1", ActionType: "run_command", Status: "pending"},
+			{ID: "act-1", ActionType: "objective", Status: "pending"},
 		},
 	}
 	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
@@ -1360,6 +1588,69 @@ func TestHandlePromptSetCommand(t *testing.T) {
 	}
 }
 
+func TestHandlePromptSetRejectsStaleRevision(t *testing.T) {
+	fStore := &fakeStore{
+		identity:      store.UserIdentity{UserID: "user-1", Role: "admin"},
+		contextPolicy: store.ContextPolicy{ContextID: "ctx-1", WorkspaceID: "ws-1", SystemPrompt: "Be brief", Revision: 4},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	send := func(text string) string {
+		t.Helper()
+		output, err := service.HandleMessage(context.Background(), MessageInput{Connector: "telegram", ExternalID: "42", FromUserID: "u1", Text: text})
+		if err != nil || !output.Handled {
+			t.Fatalf("handle %q failed: handled=%v err=%v", text, output.Handled, err)
+		}
+		return output.Reply
+	}
+
+	if reply := send("/prompt show"); !strings.Contains(reply, "revision 4") {
+		t.Fatalf("expected revision in prompt show, got %s", reply)
+	}
+	if reply := send("/prompt set @3 Be verbose"); !strings.Contains(reply, "changed since revision 3") {
+		t.Fatalf("expected conflict reply, got %s", reply)
+	}
+	if fStore.contextPolicy.SystemPrompt != "Be brief" {
+		t.Fatalf("expected stale write to be refused, got %q", fStore.contextPolicy.SystemPrompt)
+	}
+	if reply := send("/prompt set @4 Be verbose"); !strings.Contains(reply, "revision 5") {
+		t.Fatalf("expected update at current revision, got %s", reply)
+	}
+	if fStore.contextPolicy.SystemPrompt != "Be verbose" {
+		t.Fatalf("expected prompt without revision marker, got %q", fStore.contextPolicy.SystemPrompt)
+	}
+}
+
+func TestHandleMonitorTemplateCreatesObjective(t *testing.T) {
+	fStore := &fakeStore{}
+	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
+	send := func(text string) string {
+		t.Helper()
+		output, err := service.HandleMessage(context.Background(), MessageInput{Connector: "telegram", ExternalID: "42", FromUserID: "u1", Text: text})
+		if err != nil || !output.Handled {
+			t.Fatalf("handle %q failed: handled=%v err=%v", text, output.Handled, err)
+		}
+		return output.Reply
+	}
+
+	if reply := send("/monitor templates"); !strings.Contains(reply, "`release-watch`") || !strings.Contains(reply, "`spam-sweep`") {
+		t.Fatalf("expected template listing, got %s", reply)
+	}
+	if reply := send("/monitor template uptime-check"); !strings.Contains(reply, "requires url") || fStore.objectiveInvoked {
+		t.Fatalf("expected missing parameter reply without creating, got %s", reply)
+	}
+	reply := send(`/monitor template uptime-check url=https://example.com/health cron="*/5 * * * *"`)
+	if !strings.Contains(reply, "created from template `uptime-check`") {
+		t.Fatalf("unexpected reply %s", reply)
+	}
+	created := fStore.lastObjective
+	if created.Title != "Uptime check: https://example.com/health" || created.CronExpr != "*/5 * * * *" || created.Timezone != "UTC" {
+		t.Fatalf("unexpected objective input %+v", created)
+	}
+	if !strings.Contains(created.Prompt, "Request https://example.com/health once") {
+		t.Fatalf("expected templated prompt, got %q", created.Prompt)
+	}
+}
+
 func TestHandleMonitorNaturalLanguageIntentCreatesObjective(t *testing.T) {
 	fStore := &fakeStore{}
 	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
@@ -1460,6 +1751,9 @@ func TestHandleMonitorCommandCreatesObjectiveForCodexConnector(t *testing.T) {
 	if fStore.lastObjective.CronExpr != defaultObjectiveCronExpr {
 		t.Fatalf("expected default objective cron expression %q, got %q", defaultObjectiveCronExpr, fStore.lastObjective.CronExpr)
 	}
+	if !fStore.lastObjective.ReportChangesOnly {
+		t.Fatal("expected /monitor objectives to report changes only")
+	}
 }
 
 func TestHandlePendingActionsCommand(t *testing.T) {
@@ -1488,6 +1782,40 @@ func TestHandlePendingActionsCommand(t *testing.T) {
 	if !output.Handled || !strings.Contains(output.Reply, "act-1") {
 		t.Fatalf("expected pending actions list, got %s", output.Reply)
 	}
+	if output.Rich == nil || len(output.Rich.Buttons) != 2 || output.Rich.Buttons[0].Command != "/approve-action act-1" {
+		t.Fatalf("expected approve and deny buttons, got %+v", output.Rich)
+	}
+}
+
+func TestHandlePendingActionsCommandShowsRisk(t *testing.T) {
+	service := New(
+		&fakeStore{
+			identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+			actionApprovals: []store.ActionApproval{
+				{ID: "act-1", ActionType: "run_command", ActionSummary: "Clean logs", Status: "pending", RiskLevel: "high", RiskReason: "destructive command rm"},
+				{ID: "act-2", ActionType: "send_email", ActionSummary: "Send digest", Status: "pending", RiskLevel: "low", RiskReason: "nothing risky"},
+				{ID: "act-3", ActionType: "send_email", ActionSummary: "Legacy", Status: "pending"},
+			},
+		},
+		&fakeEngine{},
+		&fakeRetriever{},
+		nil,
+		"",
+		nil,
+	)
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/pending-actions",
+	})
+	if err != nil {
+		t.Fatalf("handle message failed: %v", err)
+	}
+	lines := strings.Split(output.Reply, "\n")
+	if len(lines) < 4 || !strings.HasSuffix(lines[1], "high risk: destructive command rm") || !strings.HasSuffix(lines[2], ", low risk") || strings.Contains(lines[3], "risk") {
+		t.Fatalf("unexpected risk in listing:\n%s", output.Reply)
+	}
 }
 
 func TestHandlePendingActionsCommandHelpReturnsCommandWithoutIdentity(t *testing.T) {
@@ -1731,6 +2059,111 @@ func TestHandlePendingActionsFallsBackToGlobalList(t *testing.T) {
 	}
 }
 
+func TestHandleNumberedActionRepliesResolveAgainstLastListing(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+		actionApprovals: []store.ActionApproval{
+			{ID: "act_first01", ActionType: "send_email", ActionSummary: "Send digest", Status: "pending"},
+			{ID: "act_second02", ActionType: "run_command", ActionSummary: "Restart worker", Status: "pending"},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	send := func(text string) string {
+		t.Helper()
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "telegram",
+			ExternalID: "42",
+			FromUserID: "u1",
+			Text:       text,
+		})
+		if err != nil {
+			t.Fatalf("handle %q failed: %v", text, err)
+		}
+		if !output.Handled {
+			t.Fatalf("expected %q to be handled", text)
+		}
+		return output.Reply
+	}
+
+	if reply := send("approve 2"); !strings.Contains(reply, "/pending-actions") {
+		t.Fatalf("expected guidance without a listing, got %s", reply)
+	}
+	listing := send("/pending-actions")
+	if !strings.Contains(listing, "1. `act_first01`") || !strings.Contains(listing, "2. `act_second02`") {
+		t.Fatalf("expected numbered listing, got %s", listing)
+	}
+	// New requests after the listing must not shift the numbers admins saw.
+	fStore.actionApprovals = append([]store.ActionApproval{{ID: "act_newer03", ActionType: "webhook", Status: "pending"}}, fStore.actionApprovals...)
+
+	send("deny 1 too risky")
+	send("approve 2")
+	if reply := send("approve 7"); !strings.Contains(reply, "no item 7") {
+		t.Fatalf("expected out-of-range reply, got %s", reply)
+	}
+	statuses := map[string]store.ActionApproval{}
+	for _, item := range fStore.actionApprovals {
+		statuses[item.ID] = item
+	}
+	if statuses["act_first01"].Status != "denied" || statuses["act_first01"].DeniedReason != "too risky" {
+		t.Fatalf("expected first listed action denied with reason, got %+v", statuses["act_first01"])
+	}
+	if statuses["act_second02"].Status != "approved" || statuses["act_newer03"].Status != "pending" {
+		t.Fatalf("expected only the second listed action approved, got %+v", statuses)
+	}
+
+	other, err := service.HandleMessage(context.Background(), MessageInput{Connector: "telegram", ExternalID: "99", FromUserID: "u1", Text: "approve 1"})
+	if err != nil || !strings.Contains(other.Reply, "/pending-actions") {
+		t.Fatalf("expected listings to be scoped per context, got %q (%v)", other.Reply, err)
+	}
+}
+
+func TestHandleDescriptiveActionReferences(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+		actionApprovals: []store.ActionApproval{
+			{ID: "act_curl0001", ActionType: "run_command", ActionTarget: "curl", ActionSummary: "Fetch status page", Status: "pending"},
+			{ID: "act_mail0002", ActionType: "send_email", ActionSummary: "Send digest", Status: "pending"},
+			{ID: "act_mail0003", ActionType: "send_email", ActionSummary: "Send invoice", Status: "pending"},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	send := func(text string) string {
+		t.Helper()
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "telegram",
+			ExternalID: "42",
+			FromUserID: "u1",
+			Text:       text,
+		})
+		if err != nil || !output.Handled {
+			t.Fatalf("handle %q failed: handled=%v err=%v", text, output.Handled, err)
+		}
+		return output.Reply
+	}
+
+	if reply := send("deny the email action"); !strings.Contains(reply, "Several pending actions match") || !strings.Contains(reply, "act_mail0003") {
+		t.Fatalf("expected ambiguity reply listing both email actions, got %s", reply)
+	}
+	if reply := send("approve the deploy one"); !strings.Contains(reply, `No pending action matches "deploy"`) {
+		t.Fatalf("expected no-match reply, got %s", reply)
+	}
+	send("please approve the curl one")
+	send("deny the invoice email action because wrong customer")
+	statuses := map[string]store.ActionApproval{}
+	for _, item := range fStore.actionApprovals {
+		statuses[item.ID] = item
+	}
+	if statuses["act_curl0001"].Status != "approved" {
+		t.Fatalf("expected curl action approved, got %+v", statuses["act_curl0001"])
+	}
+	if statuses["act_mail0003"].Status != "denied" || statuses["act_mail0003"].DeniedReason != "wrong customer" {
+		t.Fatalf("expected invoice email denied with reason, got %+v", statuses["act_mail0003"])
+	}
+	if statuses["act_mail0002"].Status != "pending" {
+		t.Fatalf("expected digest email untouched, got %+v", statuses["act_mail0002"])
+	}
+}
+
 func TestHandleApproveActionCommand(t *testing.T) {
 	fStore := &fakeStore{
 		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
@@ -1769,6 +2202,81 @@ func TestHandleApproveActionCommand(t *testing.T) {
 	}
 }
 
+func TestHandleApproveActionCommandWaitsForSecondAdmin(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+		actionApprovals: []store.ActionApproval{
+			{ID: "act-1", ActionType: "send_email", Status: "pending", RequiredApprovals: 2},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	approve := func() string {
+		t.Helper()
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "telegram",
+			ExternalID: "42",
+			FromUserID: "u1",
+			Text:       "/approve-action act-1",
+		})
+		if err != nil {
+			t.Fatalf("handle message failed: %v", err)
+		}
+		return output.Reply
+	}
+
+	if reply := approve(); !strings.Contains(reply, "(1 of 2)") || !strings.Contains(reply, "Waiting for another admin") {
+		t.Fatalf("expected quorum progress reply, got %s", reply)
+	}
+	if fStore.executionUpdateInvoked {
+		t.Fatal("expected no execution before the second approval")
+	}
+	if reply := approve(); !strings.Contains(reply, "already approved") {
+		t.Fatalf("expected duplicate approval to be refused, got %s", reply)
+	}
+
+	fStore.identity = store.UserIdentity{UserID: "admin-2", Role: "admin"}
+	if reply := approve(); !strings.Contains(reply, "approved") || !fStore.executionUpdateInvoked {
+		t.Fatalf("expected second admin to approve and run the action, got %s", reply)
+	}
+}
+
+func TestHandleActionCommandsReportWhoAlreadyDecided(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-2", Role: "admin"},
+		actionApprovals: []store.ActionApproval{
+			{ID: "act-1", ActionType: "send_email", Status: "approved", ApproverUserID: "admin-1"},
+			{ID: "act-2", ActionType: "send_email", Status: "denied", ApproverUserID: store.ExpiredApprovalUserID},
+		},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	send := func(text string) string {
+		t.Helper()
+		output, err := service.HandleMessage(context.Background(), MessageInput{
+			Connector:  "discord",
+			ExternalID: "chan-1",
+			FromUserID: "u2",
+			Text:       text,
+		})
+		if err != nil {
+			t.Fatalf("handle message failed: %v", err)
+		}
+		return output.Reply
+	}
+
+	if reply := send("/deny-action act-1"); !strings.Contains(reply, "already approved by `admin-1`") {
+		t.Fatalf("expected deny to name the approving admin, got %s", reply)
+	}
+	if reply := send("/approve-action act-1"); !strings.Contains(reply, "already approved by `admin-1`") {
+		t.Fatalf("expected a second approval to name the approving admin, got %s", reply)
+	}
+	if reply := send("/approve-action act-2"); !strings.Contains(reply, "already expired") {
+		t.Fatalf("expected expired approval reply, got %s", reply)
+	}
+	if fStore.executionUpdateInvoked {
+		t.Fatal("expected no execution for decided approvals")
+	}
+}
+
 func TestHandleApproveActionCommandAcceptsQuotedID(t *testing.T) {
 	fStore := &fakeStore{
 		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
@@ -2237,3 +2745,192 @@ func TestHandleApproveActionImplicitMultiplePending(t *testing.T) {
 		t.Fatalf("expected multiple pending hint, got %s", output.Reply)
 	}
 }
+
+func TestHandleExplainCommandDoesNotExecuteTools(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "admin-1", Role: "admin"},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	ack := &fakeTriageAcknowledger{
+		replies: []string{
+			`{"tool":"create_task","args":{"title":"Investigate report"},"reason":"the user wants follow-up work"}`,
+			`{"final":"I would queue a follow-up task.","confidence":0.9}`,
+		},
+	}
+	service.SetTriageAcknowledger(ack)
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/explain investigate the outage report",
+	})
+	if err != nil {
+		t.Fatalf("explain failed: %v", err)
+	}
+	if !output.Handled {
+		t.Fatal("expected explain command to be handled")
+	}
+	if fStore.lastTask.ID != "" {
+		t.Fatal("expected explain mode not to create tasks")
+	}
+	for _, fragment := range []string{"no tools were executed", "`create_task`", "why: the user wants follow-up work", "I would queue a follow-up task."} {
+		if !strings.Contains(output.Reply, fragment) {
+			t.Fatalf("expected reply to contain %q, got %q", fragment, output.Reply)
+		}
+	}
+}
+
+func TestHandleExplainCommandRequiresAdmin(t *testing.T) {
+	fStore := &fakeStore{
+		identity: store.UserIdentity{UserID: "user-1", Role: "member"},
+	}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	service.SetTriageAcknowledger(&fakeTriageAcknowledger{reply: "unused"})
+
+	output, err := service.HandleMessage(context.Background(), MessageInput{
+		Connector:  "telegram",
+		ExternalID: "42",
+		FromUserID: "u1",
+		Text:       "/explain delete everything",
+	})
+	if err != nil {
+		t.Fatalf("explain failed: %v", err)
+	}
+	if !strings.Contains(output.Reply, "admin role required") {
+		t.Fatalf("expected admin denial, got %q", output.Reply)
+	}
+}
+
+func TestVoiceCommandTogglesContextVoiceReplies(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, nil, nil, "", nil)
+	input := MessageInput{Connector: "telegram", ExternalID: "42", FromUserID: "admin-1", Text: "/voice on"}
+
+	output, err := service.HandleMessage(context.Background(), input)
+	if err != nil {
+		t.Fatalf("voice on: %v", err)
+	}
+	if !fStore.contextPolicy.VoiceReplies || !strings.Contains(output.Reply, "No text-to-speech provider") {
+		t.Fatalf("expected voice enabled with provider warning, got %q %+v", output.Reply, fStore.contextPolicy)
+	}
+	service.SetVoiceRepliesAvailable(true)
+	input.Text = "/voice status"
+	output, err = service.HandleMessage(context.Background(), input)
+	if err != nil || output.Reply != "Voice replies are on for this channel." {
+		t.Fatalf("unexpected status reply %q %v", output.Reply, err)
+	}
+	input.Text = "/voice off"
+	if _, err := service.HandleMessage(context.Background(), input); err != nil || fStore.contextPolicy.VoiceReplies {
+		t.Fatalf("expected voice disabled, got %+v %v", fStore.contextPolicy, err)
+	}
+
+	fStore.identity.Role = "member"
+	input.Text = "/voice on"
+	output, _ = service.HandleMessage(context.Background(), input)
+	if !strings.Contains(output.Reply, "admin role required") || fStore.contextPolicy.VoiceReplies {
+		t.Fatalf("expected non-admin to be rejected, got %q", output.Reply)
+	}
+}
+
+type sharedKnowledgeRetriever struct {
+	fakeRetriever
+	sharedSearches int
+}
+
+func (r *sharedKnowledgeRetriever) Search(ctx context.Context, workspaceID, query string, limit int) ([]qmd.SearchResult, error) {
+	results := []qmd.SearchResult{{Path: "notes/deploy.md", Snippet: "own notes"}}
+	if qmd.SharedKnowledgeEnabled(ctx) {
+		r.sharedSearches++
+		results = append(results, qmd.SearchResult{Path: qmd.SharedTargetPrefix + "docs/deploy.md", Snippet: "company runbook"})
+	}
+	return results, nil
+}
+
+func TestSharedKnowledgeCommandOptsChannelIntoSharedSearch(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	retriever := &sharedKnowledgeRetriever{}
+	service := New(fStore, &fakeEngine{}, retriever, nil, "", nil)
+	input := MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "admin-1", Text: "/shared-knowledge on"}
+
+	output, err := service.HandleMessage(context.Background(), input)
+	if err != nil {
+		t.Fatalf("shared knowledge on: %v", err)
+	}
+	if !fStore.contextPolicy.SharedKnowledge || !strings.Contains(output.Reply, "no shared knowledge workspace is configured") {
+		t.Fatalf("expected opt-in with configuration warning, got %q %+v", output.Reply, fStore.contextPolicy)
+	}
+	service.SetSharedKnowledgeWorkspace("company-docs")
+	input.Text = "/shared-knowledge status"
+	output, err = service.HandleMessage(context.Background(), input)
+	if err != nil || !strings.Contains(output.Reply, "`company-docs`") {
+		t.Fatalf("unexpected status reply %q %v", output.Reply, err)
+	}
+
+	input.Text = "/search deploy"
+	output, err = service.HandleMessage(context.Background(), input)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if retriever.sharedSearches != 1 || !strings.Contains(output.Reply, "`shared:docs/deploy.md` [shared knowledge]") || strings.Contains(output.Reply, "`notes/deploy.md` [shared") {
+		t.Fatalf("expected shared result to be labeled, got %q", output.Reply)
+	}
+
+	input.Text = "/shared-knowledge off"
+	if _, err := service.HandleMessage(context.Background(), input); err != nil || fStore.contextPolicy.SharedKnowledge {
+		t.Fatalf("expected shared knowledge disabled, got %+v %v", fStore.contextPolicy, err)
+	}
+	input.Text = "/search deploy"
+	if _, err := service.HandleMessage(context.Background(), input); err != nil || retriever.sharedSearches != 1 {
+		t.Fatalf("expected searches to stay in the workspace after opting out, got %d %v", retriever.sharedSearches, err)
+	}
+}
+
+type fakeObjectiveRunner struct {
+	ran []string
+	err error
+}
+
+func (f *fakeObjectiveRunner) RunNow(ctx context.Context, objectiveID string) (orchestrator.Task, error) {
+	if f.err != nil {
+		return orchestrator.Task{}, f.err
+	}
+	f.ran = append(f.ran, objectiveID)
+	return orchestrator.Task{ID: "task-run-1", Title: "Status page monitor"}, nil
+}
+
+func TestHandleRunObjectiveCommand(t *testing.T) {
+	fStore := &fakeStore{identity: store.UserIdentity{UserID: "admin-1", Role: "admin"}}
+	service := New(fStore, &fakeEngine{}, &fakeRetriever{}, nil, "", nil)
+	runner := &fakeObjectiveRunner{}
+	service.SetObjectiveRunner(runner)
+	send := func(text string) string {
+		t.Helper()
+		output, err := service.HandleMessage(context.Background(), MessageInput{Connector: "telegram", ExternalID: "42", FromUserID: "u1", Text: text})
+		if err != nil || !output.Handled {
+			t.Fatalf("handle %q failed: handled=%v err=%v", text, output.Handled, err)
+		}
+		return output.Reply
+	}
+
+	if reply := send("/run-objective obj-1"); !strings.Contains(reply, "queued as task `task-run-1`") || len(runner.ran) != 1 || runner.ran[0] != "obj-1" {
+		t.Fatalf("unexpected run reply %q (ran %v)", reply, runner.ran)
+	}
+	runner.err = store.ErrObjectiveNotFound
+	if reply := send("/run_objective obj-2"); reply != "Objective not found." {
+		t.Fatalf("expected not found reply, got %q", reply)
+	}
+	if reply := send("/run-objective"); !strings.Contains(reply, "Usage: /run-objective") {
+		t.Fatalf("expected usage reply, got %q", reply)
+	}
+}
+
+func (f *fakeStore) RolePermissions(ctx context.Context, workspaceID, role string) ([]store.Permission, error) {
+	if permissions, ok := f.workspaceRoles[workspaceID][role]; ok {
+		return permissions, nil
+	}
+	if permissions, ok := f.rolePermissions[role]; ok {
+		return permissions, nil
+	}
+	return store.DefaultRolePermissions(role), nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dwizi/agent-runtime/internal/i18n"
 	"github.com/dwizi/agent-runtime/internal/llm"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
 	"github.com/dwizi/agent-runtime/internal/store"
@@ -31,7 +32,8 @@ func (s *Service) handleLegacyAutoTriage(ctx context.Context, input MessageInput
 	}
 	taskTitle := buildRoutedTaskTitle(decision.Class, decision.SourceText)
 	taskPrompt := buildRoutedTaskPrompt(decision)
-	task, err := s.enqueueAndPersistTask(ctx, store.CreateTaskInput{
+	busy := s.queueBusy()
+	task, err := s.persistQueuedTask(ctx, store.CreateTaskInput{
 		WorkspaceID:      decision.WorkspaceID,
 		ContextID:        decision.ContextID,
 		Kind:             string(orchestrator.TaskKindGeneral),
@@ -46,14 +48,22 @@ func (s *Service) handleLegacyAutoTriage(ctx context.Context, input MessageInput
 		SourceExternalID: decision.SourceExternalID,
 		SourceUserID:     decision.SourceUserID,
 		SourceText:       decision.SourceText,
-	})
+	}, s.routedTaskQueue(busy, decision.Priority))
 	if err != nil {
 		return MessageOutput{}, err
 	}
 	decision.TaskID = task.ID
+	if decision.Class == TriageModeration {
+		decision.CaseID = s.recordModerationDecision(ctx, decision, "flagged")
+	}
+	decision.TrackingCaseID = s.openDecisionCase(ctx, decision)
 	if s.routingNotify != nil {
 		s.routingNotify.NotifyRoutingDecision(ctx, decision)
 	}
+	s.syncTask(ctx, task.ID)
+	if busy {
+		return MessageOutput{Handled: true, Reply: busyAutoTriageAck(ctx, decision.Priority)}, nil
+	}
 	return MessageOutput{
 		Handled: true,
 		Reply:   s.buildAutoTriageAck(ctx, input, contextRecord, decision),
@@ -61,7 +71,7 @@ func (s *Service) handleLegacyAutoTriage(ctx context.Context, input MessageInput
 }
 
 func (s *Service) buildAutoTriageAck(ctx context.Context, input MessageInput, contextRecord store.ContextRecord, decision RouteDecision) string {
-	fallback := fallbackAutoTriageAck(decision.Class)
+	fallback := fallbackAutoTriageAck(ctx, decision.Class)
 	if s.triageAcknowledger == nil {
 		return fallback
 	}
@@ -69,17 +79,22 @@ func (s *Service) buildAutoTriageAck(ctx context.Context, input MessageInput, co
 	if len(sourceText) > 300 {
 		sourceText = sourceText[:300]
 	}
-	ackPrompt := strings.Join([]string{
+	constraints := []string{
 		"Write one short natural acknowledgement for a chat message.",
 		"Constraints:",
 		"- one sentence",
 		"- 8 to 20 words",
 		"- confirm you are taking action now",
 		"- do not include markdown, task IDs, or internal metadata",
+	}
+	if language := i18n.LanguageFrom(ctx); language != i18n.English {
+		constraints = append(constraints, "- write it in "+i18n.Name(language))
+	}
+	ackPrompt := strings.Join(append(constraints,
 		fmt.Sprintf("Route class: %s", strings.TrimSpace(string(decision.Class))),
 		"User message:",
 		sourceText,
-	}, "\n")
+	), "\n")
 	reply, err := s.triageAcknowledger.Reply(ctx, llm.MessageInput{
 		Connector:     strings.TrimSpace(input.Connector),
 		WorkspaceID:   strings.TrimSpace(contextRecord.WorkspaceID),
@@ -101,16 +116,16 @@ func (s *Service) buildAutoTriageAck(ctx context.Context, input MessageInput, co
 	return clean
 }
 
-func fallbackAutoTriageAck(class TriageClass) string {
+func fallbackAutoTriageAck(ctx context.Context, class TriageClass) string {
 	switch class {
 	case TriageIssue:
-		return "Thanks for flagging this. I’m investigating now and I’ll report back with findings."
+		return i18n.T(ctx, i18n.TriageAckIssue)
 	case TriageModeration:
-		return "Received. I’m reviewing this now and I’ll follow up with what I find."
+		return i18n.T(ctx, i18n.TriageAckModeration)
 	case TriageQuestion:
-		return "Yes, I’m on it. I’ll investigate and come back with an answer."
+		return i18n.T(ctx, i18n.TriageAckQuestion)
 	default:
-		return "Understood. I’m handling this now and I’ll share results shortly."
+		return i18n.T(ctx, i18n.TriageAckDefault)
 	}
 }
 
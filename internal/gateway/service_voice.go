@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/i18n"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const voiceUsage = "Usage: /voice on | /voice off | /voice status"
+
+// SetVoiceRepliesAvailable records whether a text-to-speech provider is
+// configured, so /voice can tell admins when opting in has no effect.
+func (s *Service) SetVoiceRepliesAvailable(available bool) {
+	s.voiceRepliesAvailable = available
+}
+
+func (s *Service) handleVoice(ctx context.Context, input MessageInput, arg string) (MessageOutput, error) {
+	identity, err := s.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrIdentityNotFound) {
+			return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedLink)}, nil
+		}
+		return MessageOutput{}, err
+	}
+	if !isAdminRole(identity.Role) {
+		return MessageOutput{Handled: true, Reply: i18n.T(ctx, i18n.AccessDeniedAdmin)}, nil
+	}
+
+	var policy store.ContextPolicy
+	switch strings.ToLower(strings.TrimSpace(arg)) {
+	case "on", "enable":
+		policy, err = s.store.SetContextVoiceRepliesByExternal(ctx, input.Connector, input.ExternalID, true)
+	case "off", "disable":
+		policy, err = s.store.SetContextVoiceRepliesByExternal(ctx, input.Connector, input.ExternalID, false)
+	case "status":
+		policy, err = s.store.LookupContextPolicyByExternal(ctx, input.Connector, input.ExternalID)
+		if errors.Is(err, store.ErrContextNotFound) {
+			policy, err = store.ContextPolicy{}, nil
+		}
+	default:
+		return MessageOutput{Handled: true, Reply: localizedUsage(ctx, voiceUsage)}, nil
+	}
+	if err != nil {
+		return MessageOutput{}, err
+	}
+	reply := "Voice replies are off for this channel."
+	if policy.VoiceReplies {
+		reply = "Voice replies are on for this channel."
+		if !s.voiceRepliesAvailable {
+			reply += " No text-to-speech provider is configured, so replies stay text-only."
+		}
+	}
+	return MessageOutput{Handled: true, Reply: reply}, nil
+}
@@ -0,0 +1,400 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	templateMaxBytes       = 64 * 1024
+	templateMaxOutputBytes = 64 * 1024
+)
+
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// builtinDocumentTemplates are used when a workspace has no template of the
+// same name under context/templates.
+var builtinDocumentTemplates = map[string]string{
+	"announcement": `---
+description: Team or community announcement
+required: [title, summary]
+optional: [details, audience, contact]
+---
+# {{.title}}
+
+{{.summary}}
+{{with .details}}
+## Details
+
+{{.}}
+{{end}}{{with .audience}}
+**Audience:** {{.}}
+{{end}}{{with .contact}}
+Questions? Contact {{.}}.
+{{end}}`,
+	"incident_report": `---
+description: Incident report with impact, timeline, and follow-ups
+required: [title, severity, summary, impact]
+optional: [started_at, resolved_at, owner, timeline, root_cause, action_items]
+---
+# Incident: {{.title}}
+
+- **Severity:** {{upper .severity}}
+- **Started:** {{default "unknown" .started_at}}
+- **Resolved:** {{default "ongoing" .resolved_at}}
+- **Owner:** {{default "unassigned" .owner}}
+
+## Summary
+
+{{.summary}}
+
+## Impact
+
+{{.impact}}
+{{with .timeline}}
+## Timeline
+
+{{bullets .}}
+{{end}}{{with .root_cause}}
+## Root Cause
+
+{{.}}
+{{end}}{{with .action_items}}
+## Action Items
+
+{{bullets .}}
+{{end}}`,
+	"release_notes": `---
+description: Release notes grouped into highlights, changes, and fixes
+required: [version, changes]
+optional: [date, highlights, fixes, breaking, upgrade_notes]
+---
+# Release {{.version}} ({{default today .date}})
+{{with .highlights}}
+## Highlights
+
+{{bullets .}}
+{{end}}
+## Changes
+
+{{bullets .changes}}
+{{with .fixes}}
+## Fixes
+
+{{bullets .}}
+{{end}}{{with .breaking}}
+## Breaking Changes
+
+{{bullets .}}
+{{end}}{{with .upgrade_notes}}
+## Upgrade Notes
+
+{{.}}
+{{end}}`,
+}
+
+type documentTemplate struct {
+	Name        string
+	Source      string
+	Description string
+	Required    []string
+	Optional    []string
+	Body        string
+}
+
+type documentTemplateMeta struct {
+	Description string   `yaml:"description"`
+	Required    []string `yaml:"required"`
+	Optional    []string `yaml:"optional"`
+}
+
+// RenderTemplateTool fills workspace document templates (announcements,
+// incident reports, release notes) with variables so recurring documents
+// keep a consistent shape.
+type RenderTemplateTool struct {
+	workspaceRoot string
+	guard         *filePolicyGuard
+	secrets       secretScanner
+}
+
+type renderTemplateArgs struct {
+	Name      string         `json:"name"`
+	Variables map[string]any `json:"variables"`
+	SaveAs    string         `json:"save_as"`
+}
+
+func NewRenderTemplateTool(store Store, workspaceRoot string) *RenderTemplateTool {
+	return &RenderTemplateTool{
+		workspaceRoot: strings.TrimSpace(workspaceRoot),
+		guard:         newFilePolicyGuard(store, workspaceRoot),
+		secrets:       secretScanner{store: store},
+	}
+}
+
+func (t *RenderTemplateTool) Name() string { return "render_template" }
+
+func (t *RenderTemplateTool) ToolClass() tools.ToolClass { return tools.ToolClassGeneral }
+
+func (t *RenderTemplateTool) RequiresApproval() bool { return false }
+
+func (t *RenderTemplateTool) Description() string {
+	return "Render a standard document template (announcement, incident_report, release_notes, or workspace templates in context/templates) with variables. Omit name to list templates and their variables."
+}
+
+func (t *RenderTemplateTool) ParametersSchema() string {
+	return `{"name": "string (template name; omit to list templates)", "variables": "object (template variables; lists render as bullets)", "save_as": "string (optional scratchpad path to write the result)"}`
+}
+
+func (t *RenderTemplateTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args renderTemplateArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	name := strings.TrimSpace(args.Name)
+	if name != "" && !templateNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name %q", name)
+	}
+	if name == "" && (len(args.Variables) > 0 || strings.TrimSpace(args.SaveAs) != "") {
+		return fmt.Errorf("name is required when variables or save_as are set")
+	}
+	return nil
+}
+
+func (t *RenderTemplateTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args renderTemplateArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+	name := strings.TrimSpace(args.Name)
+	if name == "" {
+		return t.list(record.WorkspaceID)
+	}
+	tmpl, err := t.load(record.WorkspaceID, name)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := tmpl.render(args.Variables)
+	if err != nil {
+		return "", err
+	}
+	savePath := strings.TrimSpace(args.SaveAs)
+	if savePath == "" {
+		return rendered, nil
+	}
+	fullPath, err := t.guard.resolve(ctx, t.Name(), record, savePath, fileAccessWrite)
+	if err != nil {
+		return "", err
+	}
+	content, err := t.secrets.scan(ctx, t.Name(), record, savePath, rendered)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+		return "", fmt.Errorf("write file: %w", err)
+	}
+	return fmt.Sprintf("Rendered %s to %s (%d bytes%s):\n\n%s", name, savePath, len(content), maskedNote(rendered, content), content), nil
+}
+
+func (t *RenderTemplateTool) templateDir(workspaceID string) string {
+	return filepath.Join(t.workspaceRoot, workspaceID, "context", "templates")
+}
+
+func (t *RenderTemplateTool) load(workspaceID, name string) (documentTemplate, error) {
+	path := filepath.Join(t.templateDir(workspaceID), name+".md")
+	info, err := os.Stat(path)
+	if err == nil {
+		if info.Size() > templateMaxBytes {
+			return documentTemplate{}, fmt.Errorf("template %s is larger than %d bytes", name, templateMaxBytes)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return documentTemplate{}, fmt.Errorf("read template: %w", err)
+		}
+		return parseDocumentTemplate(name, "workspace", string(content))
+	}
+	if !os.IsNotExist(err) {
+		return documentTemplate{}, fmt.Errorf("read template: %w", err)
+	}
+	if content, ok := builtinDocumentTemplates[name]; ok {
+		return parseDocumentTemplate(name, "built-in", content)
+	}
+	return documentTemplate{}, fmt.Errorf("template %q not found; available: %s", name, strings.Join(t.names(workspaceID), ", "))
+}
+
+func (t *RenderTemplateTool) names(workspaceID string) []string {
+	names := make([]string, 0, len(builtinDocumentTemplates))
+	for name := range builtinDocumentTemplates {
+		names = append(names, name)
+	}
+	entries, _ := os.ReadDir(t.templateDir(workspaceID))
+	for _, entry := range entries {
+		name, isMarkdown := strings.CutSuffix(entry.Name(), ".md")
+		if entry.IsDir() || !isMarkdown || !templateNamePattern.MatchString(name) || slices.Contains(names, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (t *RenderTemplateTool) list(workspaceID string) (string, error) {
+	lines := []string{"Available templates:"}
+	for _, name := range t.names(workspaceID) {
+		tmpl, err := t.load(workspaceID, name)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("- %s: (invalid: %v)", name, err))
+			continue
+		}
+		line := fmt.Sprintf("- %s (%s)", name, tmpl.Source)
+		if tmpl.Description != "" {
+			line += ": " + tmpl.Description
+		}
+		line += fmt.Sprintf("\n  required: %s", listOrNone(tmpl.Required))
+		line += fmt.Sprintf("\n  optional: %s", listOrNone(tmpl.Optional))
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// parseDocumentTemplate splits optional YAML front matter (description,
+// required, optional) from the text/template body.
+func parseDocumentTemplate(name, source, content string) (documentTemplate, error) {
+	tmpl := documentTemplate{Name: name, Source: source, Body: content}
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if rest, ok := strings.CutPrefix(normalized, "---\n"); ok {
+		header, body, found := strings.Cut(rest, "\n---\n")
+		if !found {
+			return documentTemplate{}, fmt.Errorf("template %s: unterminated front matter", name)
+		}
+		var meta documentTemplateMeta
+		if err := yaml.Unmarshal([]byte(header), &meta); err != nil {
+			return documentTemplate{}, fmt.Errorf("template %s: invalid front matter: %w", name, err)
+		}
+		tmpl.Description = strings.TrimSpace(meta.Description)
+		tmpl.Required = meta.Required
+		tmpl.Optional = meta.Optional
+		tmpl.Body = body
+	}
+	return tmpl, nil
+}
+
+func (d documentTemplate) render(variables map[string]any) (string, error) {
+	missing := []string{}
+	for _, key := range d.Required {
+		if isBlankTemplateValue(variables[key]) {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("template %s is missing required variables: %s", d.Name, strings.Join(missing, ", "))
+	}
+	data := make(map[string]any, len(variables)+len(d.Optional))
+	for _, key := range d.Optional {
+		data[key] = ""
+	}
+	for key, value := range variables {
+		data[key] = value
+	}
+	parsed, err := template.New(d.Name).Option("missingkey=error").Funcs(documentTemplateFuncs()).Parse(d.Body)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", d.Name, err)
+	}
+	var buffer bytes.Buffer
+	if err := parsed.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("render template %s: %w", d.Name, err)
+	}
+	if buffer.Len() > templateMaxOutputBytes {
+		return "", fmt.Errorf("rendered template %s exceeds %d bytes", d.Name, templateMaxOutputBytes)
+	}
+	return strings.TrimSpace(buffer.String()) + "\n", nil
+}
+
+func documentTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"upper": func(value any) string { return strings.ToUpper(fmt.Sprint(value)) },
+		"lower": func(value any) string { return strings.ToLower(fmt.Sprint(value)) },
+		"today": func() string { return time.Now().UTC().Format("2006-01-02") },
+		"default": func(fallback, value any) any {
+			if isBlankTemplateValue(value) {
+				return fallback
+			}
+			return value
+		},
+		"join": func(sep string, value any) string {
+			return strings.Join(templateItems(value), sep)
+		},
+		"bullets": func(value any) string {
+			items := templateItems(value)
+			for index, item := range items {
+				items[index] = "- " + item
+			}
+			return strings.Join(items, "\n")
+		},
+	}
+}
+
+// templateItems turns a list, or a newline-separated string, into items.
+func templateItems(value any) []string {
+	items := []string{}
+	switch typed := value.(type) {
+	case nil:
+	case []any:
+		for _, item := range typed {
+			if text := strings.TrimSpace(fmt.Sprint(item)); text != "" {
+				items = append(items, text)
+			}
+		}
+	default:
+		for _, line := range strings.Split(fmt.Sprint(typed), "\n") {
+			line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+			if line != "" {
+				items = append(items, line)
+			}
+		}
+	}
+	return items
+}
+
+func isBlankTemplateValue(value any) bool {
+	switch typed := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(typed) == ""
+	case []any:
+		return len(typed) == 0
+	default:
+		return false
+	}
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestRenderTemplateBuiltinIncidentReport(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+	tool := NewRenderTemplateTool(nil, tempDir)
+
+	args, _ := json.Marshal(map[string]any{
+		"name": "incident_report",
+		"variables": map[string]any{
+			"title":        "Login outage",
+			"severity":     "sev2",
+			"summary":      "Sessions expired early.",
+			"impact":       "Users were logged out.",
+			"action_items": []string{"Add session TTL alert", "Backfill tokens"},
+		},
+		"save_as": "reports/login.md",
+	})
+	res, err := tool.Execute(ctx, args)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{"# Incident: Login outage", "**Severity:** SEV2", "**Resolved:** ongoing", "## Action Items\n\n- Add session TTL alert\n- Backfill tokens"} {
+		if !strings.Contains(res, want) {
+			t.Fatalf("expected %q in output:\n%s", want, res)
+		}
+	}
+	if strings.Contains(res, "## Root Cause") || strings.Contains(res, "<no value>") {
+		t.Fatalf("optional sections must be omitted:\n%s", res)
+	}
+	saved, err := os.ReadFile(filepath.Join(tempDir, "ws1", "scratch", "reports", "login.md"))
+	if err != nil || !strings.HasPrefix(string(saved), "# Incident: Login outage") {
+		t.Fatalf("expected saved report, got %q %v", saved, err)
+	}
+
+	args, _ = json.Marshal(map[string]any{"name": "incident_report", "variables": map[string]any{"title": "x"}})
+	if _, err := tool.Execute(ctx, args); err == nil || !strings.Contains(err.Error(), "severity, summary, impact") {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+}
+
+func TestRenderTemplateWorkspaceOverrideAndList(t *testing.T) {
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "ws1", "context", "templates")
+	_ = os.MkdirAll(templateDir, 0o755)
+	_ = os.WriteFile(filepath.Join(templateDir, "announcement.md"), []byte("---\ndescription: House style\nrequired: [title]\n---\n** {{upper .title}} **\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(templateDir, "standup.md"), []byte("Standup {{.date}}: {{join \", \" .items}}\n"), 0o644)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+	tool := NewRenderTemplateTool(nil, tempDir)
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"name": "announcement", "variables": {"title": "launch"}}`))
+	if err != nil || res != "** LAUNCH **\n" {
+		t.Fatalf("expected workspace override, got %q %v", res, err)
+	}
+	res, err = tool.Execute(ctx, json.RawMessage(`{"name": "standup", "variables": {"date": "Mon", "items": ["a", "b"]}}`))
+	if err != nil || res != "Standup Mon: a, b\n" {
+		t.Fatalf("unexpected standup %q %v", res, err)
+	}
+	// Without front matter every referenced variable is required.
+	if _, err := tool.Execute(ctx, json.RawMessage(`{"name": "standup", "variables": {"date": "Mon"}}`)); err == nil {
+		t.Fatal("expected missing key error")
+	}
+
+	list, err := tool.Execute(ctx, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, want := range []string{"- announcement (workspace): House style", "- incident_report (built-in)", "- release_notes (built-in)", "- standup (workspace)"} {
+		if !strings.Contains(list, want) {
+			t.Fatalf("expected %q in list:\n%s", want, list)
+		}
+	}
+	if _, err := tool.Execute(ctx, json.RawMessage(`{"name": "../secrets"}`)); err == nil {
+		t.Fatal("expected invalid name to be rejected")
+	}
+}
@@ -16,16 +16,17 @@ import (
 type RunActionTool struct {
 	executor ActionExecutor
 	store    Store
+	approver autoApprover
 }
 
 func NewRunActionTool(store Store, executor ActionExecutor) *RunActionTool {
-	return &RunActionTool{store: store, executor: executor}
+	return &RunActionTool{store: store, executor: executor, approver: autoApprover{store: store}}
 }
 
 func (t *RunActionTool) Name() string { return "run_action" }
 
 func (t *RunActionTool) Description() string {
-	return "Execute a system action like 'run_command' (curl, etc.), 'send_email', 'webhook', 'agentic_web' (TinyFish), or any external plugin action type loaded at runtime."
+	return "Execute a system action like 'run_command' (curl, etc.), 'send_email', 'webhook', 'agentic_web' (TinyFish), 'ssh_command' (target is the command, payload.host the allowlisted host), or any external plugin action type loaded at runtime."
 }
 
 func (t *RunActionTool) ParametersSchema() string {
@@ -54,6 +55,10 @@ func (t *RunActionTool) ValidateArgs(rawArgs json.RawMessage) error {
 		}
 	}
 
+	if actionType == "ssh_command" && strings.TrimSpace(firstNonEmptyMapString(args.Payload, "host")) == "" {
+		return fmt.Errorf("%w: payload.host is required for ssh_command", agenterr.ErrToolInvalidArgs)
+	}
+
 	if actionType == "webhook" && strings.TrimSpace(args.Target) == "" {
 		return fmt.Errorf("%w: target is required for webhook", agenterr.ErrToolInvalidArgs)
 	}
@@ -200,18 +205,8 @@ func (t *RunActionTool) Execute(ctx context.Context, rawArgs json.RawMessage) (s
 		return "", err
 	}
 
-	// 2. Check if we can auto-approve
-	// We reuse checkAutoApproval logic but don't return error, just bool
-	canAutoApprove := false
-	if input.FromUserID == "system:task-worker" {
-		canAutoApprove = true
-	} else if identity, err := t.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID); err == nil {
-		if identity.Role == "admin" || identity.Role == "overlord" {
-			canAutoApprove = true
-		}
-	}
-
-	if !canAutoApprove {
+	// 2. Check whether the workspace's approval policy lets this run
+	if !t.approver.allows(ctx, t.Name(), input, approval) {
 		return fmt.Sprintf("Action request created: %s. I need an admin to approve this before I can continue.", approval.ID), nil
 	}
 
@@ -221,6 +216,9 @@ func (t *RunActionTool) Execute(ctx context.Context, rawArgs json.RawMessage) (s
 		ApproverUserID: "system:agent",
 	})
 	if err != nil {
+		if reply, ok := quorumPendingToolReply(approved, err); ok {
+			return reply, nil
+		}
 		return "", fmt.Errorf("auto-approve failed: %w", err)
 	}
 
@@ -0,0 +1,158 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/actions"
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const browserPageActionType = "browser_page"
+
+func (s *Service) SetBrowserEnabled(enabled bool) {
+	s.browserEnabled = enabled
+}
+
+// BrowsePageTool loads a page in headless Chrome through the browser_page
+// action, for JS-rendered sites that fetch_url cannot read. It follows the
+// fetch_url approval rules.
+type BrowsePageTool struct {
+	store          Store
+	actionExecutor ActionExecutor
+	enabled        func() bool
+	approver       autoApprover
+	egress         egressChecker
+}
+
+type browsePageArgs struct {
+	URL      string `json:"url"`
+	Mode     string `json:"mode"`
+	Selector string `json:"selector"`
+	WaitMS   int    `json:"wait_ms"`
+}
+
+func NewBrowsePageTool(store Store, executor ActionExecutor, enabled func() bool) *BrowsePageTool {
+	return &BrowsePageTool{store: store, actionExecutor: executor, enabled: enabled, approver: autoApprover{store: store}}
+}
+
+func (t *BrowsePageTool) Name() string { return "browse_page" }
+
+func (t *BrowsePageTool) Description() string {
+	return "Load a web page in a headless browser (runs JavaScript) to extract its text and/or save a screenshot to the scratchpad."
+}
+
+func (t *BrowsePageTool) ParametersSchema() string {
+	return `{"url": "string", "mode": "text|screenshot|both (optional, default text)", "selector": "string (optional CSS selector to extract)", "wait_ms": "integer (optional extra wait after load, max 10000)"}`
+}
+
+func (t *BrowsePageTool) ToolClass() tools.ToolClass { return tools.ToolClassGeneral }
+
+func (t *BrowsePageTool) RequiresApproval() bool { return false }
+
+// UntrustedOutput marks web pages as data the agent takes no instructions from.
+func (t *BrowsePageTool) UntrustedOutput() bool { return true }
+
+func (t *BrowsePageTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args browsePageArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	parsed, err := url.Parse(strings.TrimSpace(args.URL))
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("url must be an http or https url")
+	}
+	switch strings.ToLower(strings.TrimSpace(args.Mode)) {
+	case "", "text", "screenshot", "both":
+	default:
+		return fmt.Errorf("mode must be text, screenshot, or both")
+	}
+	if args.WaitMS < 0 || args.WaitMS > 10000 {
+		return fmt.Errorf("wait_ms must be between 0 and 10000")
+	}
+	return nil
+}
+
+func (t *BrowsePageTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	if t.enabled == nil || !t.enabled() {
+		return "Browser automation is not configured. Use fetch_url instead.", nil
+	}
+	var args browsePageArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+	input, ok := ctx.Value(ContextKeyInput).(MessageInput)
+	if !ok {
+		return "", fmt.Errorf("internal error: message input missing from context")
+	}
+	if err := t.egress.check(ctx, t.Name(), record, input, strings.TrimSpace(args.URL)); err != nil {
+		return "", err
+	}
+	mode := strings.ToLower(strings.TrimSpace(args.Mode))
+	if mode == "" {
+		mode = "text"
+	}
+	target := strings.TrimSpace(args.URL)
+	payload := map[string]any{"url": target, "mode": mode}
+	if selector := strings.TrimSpace(args.Selector); selector != "" {
+		payload["selector"] = selector
+	}
+	if args.WaitMS > 0 {
+		payload["wait_ms"] = args.WaitMS
+	}
+	approval, err := t.store.CreateActionApproval(ctx, store.CreateActionApprovalInput{
+		WorkspaceID:     record.WorkspaceID,
+		ContextID:       record.ID,
+		Connector:       input.Connector,
+		ExternalID:      input.ExternalID,
+		RequesterUserID: input.FromUserID,
+		ActionType:      browserPageActionType,
+		ActionTarget:    target,
+		ActionSummary:   fmt.Sprintf("browse %s (%s)", target, mode),
+		Payload:         payload,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !t.approver.allows(ctx, t.Name(), input, approval) {
+		return actions.FormatApprovalRequestNotice(approval.ID), nil
+	}
+	approved, err := t.store.ApproveActionApproval(ctx, store.ApproveActionApprovalInput{
+		ID:             approval.ID,
+		ApproverUserID: "system:agent",
+	})
+	if err != nil {
+		if reply, ok := quorumPendingToolReply(approved, err); ok {
+			return reply, nil
+		}
+		return "", fmt.Errorf("auto-approve failed: %w", err)
+	}
+	result, err := t.actionExecutor.Execute(ctx, approved)
+	status := "succeeded"
+	msg := result.Message
+	if err != nil {
+		status = "failed"
+		msg = err.Error()
+	}
+	_, _ = t.store.UpdateActionExecution(ctx, store.UpdateActionExecutionInput{
+		ID:               approved.ID,
+		ExecutionStatus:  status,
+		ExecutionMessage: msg,
+		ExecutorPlugin:   result.Plugin,
+		ExecutedAt:       time.Now().UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.Message, nil
+}
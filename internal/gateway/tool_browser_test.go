@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestBrowsePageToolRunsForTaskWorker(t *testing.T) {
+	fStore := &fakeStore{}
+	exec := &fakeActionExecutor{result: executor.Result{Plugin: "browser", Message: "Loaded https://status.example.com\n\nAll systems operational"}}
+	tool := NewBrowsePageTool(fStore, exec, func() bool { return true })
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{ID: "ctx-1", WorkspaceID: "ws-1"})
+	ctx = context.WithValue(ctx, ContextKeyInput, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "system:task-worker"})
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"url": "https://status.example.com", "selector": "#status"}`))
+	if err != nil {
+		t.Fatalf("browse page: %v", err)
+	}
+	if !strings.Contains(res, "All systems operational") {
+		t.Fatalf("unexpected output %q", res)
+	}
+	if len(fStore.actionApprovals) != 1 {
+		t.Fatalf("expected one action approval, got %d", len(fStore.actionApprovals))
+	}
+	approval := fStore.actionApprovals[0]
+	if approval.ActionType != "browser_page" || approval.ActionTarget != "https://status.example.com" || approval.Payload["mode"] != "text" || approval.Payload["selector"] != "#status" {
+		t.Fatalf("unexpected approval %+v", approval)
+	}
+	if fStore.lastExecutionUpdate.ExecutionStatus != "succeeded" {
+		t.Fatalf("expected execution recorded, got %+v", fStore.lastExecutionUpdate)
+	}
+}
+
+func TestBrowsePageToolRequiresApprovalForMembers(t *testing.T) {
+	fStore := &fakeStore{identityErr: store.ErrIdentityNotFound}
+	exec := &fakeActionExecutor{}
+	tool := NewBrowsePageTool(fStore, exec, func() bool { return true })
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{ID: "ctx-1", WorkspaceID: "ws-1"})
+	ctx = context.WithValue(ctx, ContextKeyInput, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "u1"})
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"url": "https://example.com", "mode": "screenshot"}`))
+	if err != nil {
+		t.Fatalf("browse page: %v", err)
+	}
+	if !strings.Contains(res, "Admin approval required") || fStore.actionApprovals[0].Status != "pending" {
+		t.Fatalf("expected pending approval, got %q %+v", res, fStore.actionApprovals)
+	}
+	if err := tool.ValidateArgs(json.RawMessage(`{"url": "file:///etc/passwd"}`)); err == nil {
+		t.Fatal("expected non-http url to be rejected")
+	}
+	disabled := NewBrowsePageTool(fStore, exec, func() bool { return false })
+	if res, _ := disabled.Execute(ctx, json.RawMessage(`{"url": "https://example.com"}`)); !strings.Contains(res, "not configured") {
+		t.Fatalf("expected not configured message, got %q", res)
+	}
+}
@@ -0,0 +1,259 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/calendar"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const calendarCreateEventActionType = "calendar_create_event"
+
+type CalendarClient interface {
+	Provider() string
+	ListEvents(ctx context.Context, from, to time.Time, limit int) ([]calendar.Event, error)
+}
+
+func (s *Service) SetCalendarClient(client CalendarClient) {
+	s.calendarClient = client
+}
+
+// ListEventsTool lists upcoming events from the configured calendar.
+type ListEventsTool struct {
+	clientProvider func() CalendarClient
+	now            func() time.Time
+}
+
+type listEventsArgs struct {
+	From  string `json:"from"`
+	Days  int    `json:"days"`
+	Limit int    `json:"limit"`
+}
+
+func NewListEventsTool(provider func() CalendarClient) *ListEventsTool {
+	return &ListEventsTool{clientProvider: provider, now: time.Now}
+}
+
+func (t *ListEventsTool) Name() string { return "list_events" }
+
+func (t *ListEventsTool) Description() string {
+	return "List upcoming events from the team calendar."
+}
+
+func (t *ListEventsTool) ParametersSchema() string {
+	return `{"from": "string (optional RFC 3339 time or YYYY-MM-DD; default now)", "days": "integer (1-90, default 7)", "limit": "integer (1-100, default 20)"}`
+}
+
+func (t *ListEventsTool) ToolClass() tools.ToolClass { return tools.ToolClassKnowledge }
+
+func (t *ListEventsTool) RequiresApproval() bool { return false }
+
+func (t *ListEventsTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args listEventsArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if from := strings.TrimSpace(args.From); from != "" {
+		if _, _, err := calendar.ParseTime(from); err != nil {
+			return err
+		}
+	}
+	if args.Days < 0 || args.Days > 90 {
+		return fmt.Errorf("days must be between 1 and 90")
+	}
+	if args.Limit < 0 || args.Limit > 100 {
+		return fmt.Errorf("limit must be between 1 and 100")
+	}
+	return nil
+}
+
+func (t *ListEventsTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args listEventsArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	client := t.clientProvider()
+	if client == nil {
+		return "Calendar integration is not configured.", nil
+	}
+	from := t.now().UTC()
+	if value := strings.TrimSpace(args.From); value != "" {
+		from, _, _ = calendar.ParseTime(value)
+	}
+	days := args.Days
+	if days == 0 {
+		days = 7
+	}
+	limit := args.Limit
+	if limit == 0 {
+		limit = 20
+	}
+	events, err := client.ListEvents(ctx, from, from.AddDate(0, 0, days), limit)
+	if err != nil {
+		return "", err
+	}
+	if len(events) == 0 {
+		return fmt.Sprintf("No events in the next %d day(s).", days), nil
+	}
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		lines = append(lines, formatCalendarEvent(event))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func calendarEventWhen(event calendar.Event) string {
+	if event.AllDay {
+		return event.Start.Format(time.DateOnly) + " (all day)"
+	}
+	when := event.Start.UTC().Format("2006-01-02 15:04 MST")
+	if !event.End.IsZero() {
+		when += " - " + event.End.UTC().Format("15:04")
+	}
+	return when
+}
+
+func formatCalendarEvent(event calendar.Event) string {
+	line := fmt.Sprintf("- %s: %s", calendarEventWhen(event), event.Title)
+	if event.Location != "" {
+		line += " @ " + event.Location
+	}
+	if event.URL != "" {
+		line += " " + event.URL
+	}
+	return line
+}
+
+// CreateEventTool files a calendar_create_event action; the event is only
+// created once the action is approved.
+type CreateEventTool struct {
+	store          Store
+	clientProvider func() CalendarClient
+}
+
+type createEventArgs struct {
+	Title       string   `json:"title"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Description string   `json:"description"`
+	Location    string   `json:"location"`
+	Attendees   []string `json:"attendees"`
+}
+
+func NewCreateEventTool(store Store, provider func() CalendarClient) *CreateEventTool {
+	return &CreateEventTool{store: store, clientProvider: provider}
+}
+
+func (t *CreateEventTool) Name() string { return "create_event" }
+
+func (t *CreateEventTool) Description() string {
+	return "Request a new team calendar event. The event is created after an admin approves the action."
+}
+
+func (t *CreateEventTool) ParametersSchema() string {
+	return `{"title": "string", "start": "string (RFC 3339 time, or YYYY-MM-DD for all-day)", "end": "string (optional; default 1 hour or 1 day)", "description": "string (optional)", "location": "string (optional)", "attendees": "array of email strings (optional)"}`
+}
+
+func (t *CreateEventTool) ToolClass() tools.ToolClass { return tools.ToolClassSensitive }
+
+func (t *CreateEventTool) RequiresApproval() bool { return false }
+
+func (t *CreateEventTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args createEventArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	_, err := args.event()
+	return err
+}
+
+func (args createEventArgs) event() (calendar.Event, error) {
+	event := calendar.Event{
+		Title:       strings.TrimSpace(args.Title),
+		Description: strings.TrimSpace(args.Description),
+		Location:    strings.TrimSpace(args.Location),
+	}
+	if strings.TrimSpace(args.Start) == "" {
+		return calendar.Event{}, fmt.Errorf("start is required")
+	}
+	start, allDay, err := calendar.ParseTime(args.Start)
+	if err != nil {
+		return calendar.Event{}, err
+	}
+	event.Start, event.AllDay = start, allDay
+	if strings.TrimSpace(args.End) != "" {
+		if event.End, _, err = calendar.ParseTime(args.End); err != nil {
+			return calendar.Event{}, err
+		}
+	}
+	for _, attendee := range args.Attendees {
+		address, err := mail.ParseAddress(strings.TrimSpace(attendee))
+		if err != nil {
+			return calendar.Event{}, fmt.Errorf("invalid attendee %q", attendee)
+		}
+		event.Attendees = append(event.Attendees, address.Address)
+	}
+	if err := event.Validate(); err != nil {
+		return calendar.Event{}, err
+	}
+	return event, nil
+}
+
+func (t *CreateEventTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args createEventArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	event, _ := args.event()
+	client := t.clientProvider()
+	if client == nil {
+		return "Calendar integration is not configured.", nil
+	}
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+	input, ok := ctx.Value(ContextKeyInput).(MessageInput)
+	if !ok {
+		return "", fmt.Errorf("internal error: message input missing from context")
+	}
+	payload := map[string]any{
+		"title": event.Title,
+		"start": strings.TrimSpace(args.Start),
+	}
+	if value := strings.TrimSpace(args.End); value != "" {
+		payload["end"] = value
+	}
+	if event.Description != "" {
+		payload["description"] = event.Description
+	}
+	if event.Location != "" {
+		payload["location"] = event.Location
+	}
+	if len(event.Attendees) > 0 {
+		payload["attendees"] = event.Attendees
+	}
+	approval, err := t.store.CreateActionApproval(ctx, store.CreateActionApprovalInput{
+		WorkspaceID:     record.WorkspaceID,
+		ContextID:       record.ID,
+		Connector:       input.Connector,
+		ExternalID:      input.ExternalID,
+		RequesterUserID: input.FromUserID,
+		ActionType:      calendarCreateEventActionType,
+		ActionTarget:    client.Provider(),
+		ActionSummary:   fmt.Sprintf("Create calendar event %q at %s", event.Title, calendarEventWhen(event)),
+		Payload:         payload,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Calendar event request created: %s. An admin must approve it before the event is created.", approval.ID), nil
+}
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/calendar"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeCalendarClient struct {
+	from, to time.Time
+}
+
+func (f *fakeCalendarClient) Provider() string { return "caldav" }
+
+func (f *fakeCalendarClient) ListEvents(ctx context.Context, from, to time.Time, limit int) ([]calendar.Event, error) {
+	f.from, f.to = from, to
+	return []calendar.Event{
+		{Title: "Release freeze", Start: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC), AllDay: true},
+		{Title: "Release 1.4", Start: time.Date(2026, 10, 22, 9, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 22, 9, 30, 0, 0, time.UTC), Location: "Main room"},
+	}, nil
+}
+
+func TestListEventsTool(t *testing.T) {
+	client := &fakeCalendarClient{}
+	tool := NewListEventsTool(func() CalendarClient { return client })
+	res, err := tool.Execute(context.Background(), json.RawMessage(`{"from": "2026-10-20", "days": 3}`))
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if !client.to.Equal(time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected window end %s", client.to)
+	}
+	want := "- 2026-10-21 (all day): Release freeze\n- 2026-10-22 09:00 UTC - 09:30: Release 1.4 @ Main room"
+	if res != want {
+		t.Fatalf("unexpected output:\n%s", res)
+	}
+	if err := tool.ValidateArgs(json.RawMessage(`{"days": 365}`)); err == nil {
+		t.Fatal("expected days limit to be enforced")
+	}
+	unconfigured := NewListEventsTool(func() CalendarClient { return nil })
+	if res, _ := unconfigured.Execute(context.Background(), json.RawMessage(`{}`)); !strings.Contains(res, "not configured") {
+		t.Fatalf("expected not configured message, got %q", res)
+	}
+}
+
+func TestCreateEventToolFilesApproval(t *testing.T) {
+	fStore := &fakeStore{}
+	tool := NewCreateEventTool(fStore, func() CalendarClient { return &fakeCalendarClient{} })
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{ID: "ctx-1", WorkspaceID: "ws-1"})
+	ctx = context.WithValue(ctx, ContextKeyInput, MessageInput{Connector: "discord", ExternalID: "chan-1", FromUserID: "system:task-worker"})
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"title": "Release reminder", "start": "2026-10-22T09:00:00Z", "attendees": ["Team <team@example.com>"]}`))
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	if !strings.Contains(res, "act-1") || !strings.Contains(res, "approve") {
+		t.Fatalf("unexpected response %q", res)
+	}
+	if len(fStore.actionApprovals) != 1 {
+		t.Fatalf("expected one approval, got %d", len(fStore.actionApprovals))
+	}
+	approval := fStore.actionApprovals[0]
+	if approval.ActionType != "calendar_create_event" || approval.Status != "pending" || approval.ActionSummary != `Create calendar event "Release reminder" at 2026-10-22 09:00 UTC` {
+		t.Fatalf("unexpected approval %+v", approval)
+	}
+
+	for _, raw := range []string{
+		`{"title": "x", "start": "tomorrow"}`,
+		`{"title": "x", "start": "2026-10-22T09:00:00Z", "end": "2026-10-22T08:00:00Z"}`,
+		`{"title": "x", "start": "2026-10-22", "attendees": ["not an email"]}`,
+		`{"start": "2026-10-22"}`,
+	} {
+		if err := tool.ValidateArgs(json.RawMessage(raw)); err == nil {
+			t.Errorf("expected %s to be rejected", raw)
+		}
+	}
+}
@@ -53,6 +53,52 @@ var _ tools.ArgumentValidator = (*MCPListPromptsTool)(nil)
 var _ tools.Tool = (*MCPGetPromptTool)(nil)
 var _ tools.MetadataProvider = (*MCPGetPromptTool)(nil)
 var _ tools.ArgumentValidator = (*MCPGetPromptTool)(nil)
+var _ tools.Tool = (*PluginDynamicTool)(nil)
+var _ tools.MetadataProvider = (*PluginDynamicTool)(nil)
+var _ tools.ArgumentValidator = (*PluginDynamicTool)(nil)
+var _ tools.Tool = (*ExtractArchiveTool)(nil)
+var _ tools.MetadataProvider = (*ExtractArchiveTool)(nil)
+var _ tools.ArgumentValidator = (*ExtractArchiveTool)(nil)
+var _ tools.Tool = (*AnalyzeLogsTool)(nil)
+var _ tools.MetadataProvider = (*AnalyzeLogsTool)(nil)
+var _ tools.ArgumentValidator = (*AnalyzeLogsTool)(nil)
+var _ tools.Tool = (*QueryDataTool)(nil)
+var _ tools.MetadataProvider = (*QueryDataTool)(nil)
+var _ tools.ArgumentValidator = (*QueryDataTool)(nil)
+var _ tools.Tool = (*DiffFilesTool)(nil)
+var _ tools.MetadataProvider = (*DiffFilesTool)(nil)
+var _ tools.ArgumentValidator = (*DiffFilesTool)(nil)
+var _ tools.Tool = (*ApplyPatchTool)(nil)
+var _ tools.MetadataProvider = (*ApplyPatchTool)(nil)
+var _ tools.ArgumentValidator = (*ApplyPatchTool)(nil)
+var _ tools.Tool = (*RenderTemplateTool)(nil)
+var _ tools.MetadataProvider = (*RenderTemplateTool)(nil)
+var _ tools.ArgumentValidator = (*RenderTemplateTool)(nil)
+var _ tools.Tool = (*ListIssuesTool)(nil)
+var _ tools.MetadataProvider = (*ListIssuesTool)(nil)
+var _ tools.StructuredExecutor = (*ListIssuesTool)(nil)
+var _ tools.ArgumentValidator = (*ListIssuesTool)(nil)
+var _ tools.Tool = (*CreateIssueTool)(nil)
+var _ tools.MetadataProvider = (*CreateIssueTool)(nil)
+var _ tools.ArgumentValidator = (*CreateIssueTool)(nil)
+var _ tools.Tool = (*CommentOnPRTool)(nil)
+var _ tools.MetadataProvider = (*CommentOnPRTool)(nil)
+var _ tools.ArgumentValidator = (*CommentOnPRTool)(nil)
+var _ tools.Tool = (*GetCIStatusTool)(nil)
+var _ tools.MetadataProvider = (*GetCIStatusTool)(nil)
+var _ tools.ArgumentValidator = (*GetCIStatusTool)(nil)
+var _ tools.Tool = (*ListEventsTool)(nil)
+var _ tools.MetadataProvider = (*ListEventsTool)(nil)
+var _ tools.ArgumentValidator = (*ListEventsTool)(nil)
+var _ tools.Tool = (*CreateEventTool)(nil)
+var _ tools.MetadataProvider = (*CreateEventTool)(nil)
+var _ tools.ArgumentValidator = (*CreateEventTool)(nil)
+var _ tools.Tool = (*TranslateTool)(nil)
+var _ tools.MetadataProvider = (*TranslateTool)(nil)
+var _ tools.ArgumentValidator = (*TranslateTool)(nil)
+var _ tools.Tool = (*BrowsePageTool)(nil)
+var _ tools.MetadataProvider = (*BrowsePageTool)(nil)
+var _ tools.ArgumentValidator = (*BrowsePageTool)(nil)
 
 type contextKey string
 
@@ -0,0 +1,338 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/github"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type GitHubClient interface {
+	ListIssues(ctx context.Context, workspaceID, repo, state string, limit int) ([]github.Issue, error)
+	CreateIssue(ctx context.Context, workspaceID string, input github.CreateIssueInput) (github.Issue, error)
+	CommentOnPullRequest(ctx context.Context, workspaceID, repo string, number int, body string) (github.Comment, error)
+	CIStatus(ctx context.Context, workspaceID, repo, ref string, prNumber int) (github.CIStatus, error)
+}
+
+func (s *Service) SetGitHubClient(client GitHubClient) {
+	s.githubClient = client
+}
+
+func githubWorkspaceID(ctx context.Context) (string, error) {
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+	return record.WorkspaceID, nil
+}
+
+// ListIssuesTool lists issues in a GitHub repository linked to the workspace.
+type ListIssuesTool struct {
+	clientProvider func() GitHubClient
+}
+
+type listIssuesArgs struct {
+	Repo  string `json:"repo"`
+	State string `json:"state"`
+	Limit int    `json:"limit"`
+}
+
+func NewListIssuesTool(provider func() GitHubClient) *ListIssuesTool {
+	return &ListIssuesTool{clientProvider: provider}
+}
+
+func (t *ListIssuesTool) Name() string { return "list_issues" }
+
+func (t *ListIssuesTool) Description() string {
+	return "List GitHub issues for a repository linked to this workspace."
+}
+
+func (t *ListIssuesTool) ParametersSchema() string {
+	return `{"repo": "string (optional owner/name; defaults to the workspace repo)", "state": "string (open|closed|all, default open)", "limit": "integer (1-50, default 20)"}`
+}
+
+func (t *ListIssuesTool) ToolClass() tools.ToolClass { return tools.ToolClassKnowledge }
+
+func (t *ListIssuesTool) RequiresApproval() bool { return false }
+
+func (t *ListIssuesTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args listIssuesArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(args.State)) {
+	case "", "open", "closed", "all":
+	default:
+		return fmt.Errorf("state must be open, closed, or all")
+	}
+	if args.Limit < 0 || args.Limit > 50 {
+		return fmt.Errorf("limit must be between 1 and 50")
+	}
+	return nil
+}
+
+func (t *ListIssuesTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	result, err := t.ExecuteStructured(ctx, rawArgs)
+	return result.Summary, err
+}
+
+// ExecuteStructured returns the issues as a table with one link per row.
+func (t *ListIssuesTool) ExecuteStructured(ctx context.Context, rawArgs json.RawMessage) (tools.StructuredResult, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return tools.StructuredResult{}, err
+	}
+	var args listIssuesArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	client := t.clientProvider()
+	if client == nil {
+		return tools.StructuredResult{Summary: "GitHub integration is not configured."}, nil
+	}
+	workspaceID, err := githubWorkspaceID(ctx)
+	if err != nil {
+		return tools.StructuredResult{}, err
+	}
+	issues, err := client.ListIssues(ctx, workspaceID, args.Repo, args.State, args.Limit)
+	if err != nil {
+		return tools.StructuredResult{}, err
+	}
+	if len(issues) == 0 {
+		return tools.StructuredResult{Summary: "No issues found."}, nil
+	}
+	lines := make([]string, 0, len(issues))
+	table := tools.Table{Columns: []string{"Issue", "State", "Title", "Labels", "Author"}}
+	for _, issue := range issues {
+		line := fmt.Sprintf("- #%d [%s] %s", issue.Number, issue.State, issue.Title)
+		if len(issue.Labels) > 0 {
+			line += " (" + strings.Join(issue.Labels, ", ") + ")"
+		}
+		if issue.Author != "" {
+			line += " by " + issue.Author
+		}
+		lines = append(lines, line+" "+issue.URL)
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprintf("#%d", issue.Number),
+			issue.State,
+			issue.Title,
+			strings.Join(issue.Labels, ", "),
+			issue.Author,
+		})
+		table.Links = append(table.Links, issue.URL)
+	}
+	return tools.NewStructuredResult(strings.Join(lines, "\n"), tools.ResultKindTable, table)
+}
+
+// CreateIssueTool opens a GitHub issue; it requires approval because it
+// publishes content outside the runtime.
+type CreateIssueTool struct {
+	clientProvider func() GitHubClient
+}
+
+type createIssueArgs struct {
+	Repo   string   `json:"repo"`
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+}
+
+func NewCreateIssueTool(provider func() GitHubClient) *CreateIssueTool {
+	return &CreateIssueTool{clientProvider: provider}
+}
+
+func (t *CreateIssueTool) Name() string { return "create_issue" }
+
+func (t *CreateIssueTool) Description() string {
+	return "Create a GitHub issue in a repository linked to this workspace, e.g. from a triaged bug report."
+}
+
+func (t *CreateIssueTool) ParametersSchema() string {
+	return `{"repo": "string (optional owner/name; defaults to the workspace repo)", "title": "string", "body": "string (markdown)", "labels": "array of strings (optional)"}`
+}
+
+func (t *CreateIssueTool) ToolClass() tools.ToolClass { return tools.ToolClassSensitive }
+
+func (t *CreateIssueTool) RequiresApproval() bool { return true }
+
+func (t *CreateIssueTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args createIssueArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(args.Title) > 256 {
+		return fmt.Errorf("title is too long (max 256 characters)")
+	}
+	return nil
+}
+
+func (t *CreateIssueTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args createIssueArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	client := t.clientProvider()
+	if client == nil {
+		return "GitHub integration is not configured.", nil
+	}
+	workspaceID, err := githubWorkspaceID(ctx)
+	if err != nil {
+		return "", err
+	}
+	issue, err := client.CreateIssue(ctx, workspaceID, github.CreateIssueInput{
+		Repo:   args.Repo,
+		Title:  args.Title,
+		Body:   args.Body,
+		Labels: args.Labels,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Created issue #%d: %s\n%s", issue.Number, issue.Title, issue.URL), nil
+}
+
+// CommentOnPRTool posts a conversation comment on a pull request.
+type CommentOnPRTool struct {
+	clientProvider func() GitHubClient
+}
+
+type commentOnPRArgs struct {
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Body   string `json:"body"`
+}
+
+func NewCommentOnPRTool(provider func() GitHubClient) *CommentOnPRTool {
+	return &CommentOnPRTool{clientProvider: provider}
+}
+
+func (t *CommentOnPRTool) Name() string { return "comment_on_pr" }
+
+func (t *CommentOnPRTool) Description() string {
+	return "Post a comment on a GitHub pull request in a repository linked to this workspace."
+}
+
+func (t *CommentOnPRTool) ParametersSchema() string {
+	return `{"repo": "string (optional owner/name; defaults to the workspace repo)", "number": "integer (pull request number)", "body": "string (markdown)"}`
+}
+
+func (t *CommentOnPRTool) ToolClass() tools.ToolClass { return tools.ToolClassSensitive }
+
+func (t *CommentOnPRTool) RequiresApproval() bool { return true }
+
+func (t *CommentOnPRTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args commentOnPRArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.Number <= 0 {
+		return fmt.Errorf("number is required")
+	}
+	if strings.TrimSpace(args.Body) == "" {
+		return fmt.Errorf("body is required")
+	}
+	return nil
+}
+
+func (t *CommentOnPRTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args commentOnPRArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	client := t.clientProvider()
+	if client == nil {
+		return "GitHub integration is not configured.", nil
+	}
+	workspaceID, err := githubWorkspaceID(ctx)
+	if err != nil {
+		return "", err
+	}
+	comment, err := client.CommentOnPullRequest(ctx, workspaceID, args.Repo, args.Number, args.Body)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Commented on pull request #%d: %s", args.Number, comment.URL), nil
+}
+
+// GetCIStatusTool summarizes checks for a ref or pull request.
+type GetCIStatusTool struct {
+	clientProvider func() GitHubClient
+}
+
+type getCIStatusArgs struct {
+	Repo   string `json:"repo"`
+	Ref    string `json:"ref"`
+	Number int    `json:"number"`
+}
+
+func NewGetCIStatusTool(provider func() GitHubClient) *GetCIStatusTool {
+	return &GetCIStatusTool{clientProvider: provider}
+}
+
+func (t *GetCIStatusTool) Name() string { return "get_ci_status" }
+
+func (t *GetCIStatusTool) Description() string {
+	return "Get CI check runs and combined commit status for a branch, commit, or pull request."
+}
+
+func (t *GetCIStatusTool) ParametersSchema() string {
+	return `{"repo": "string (optional owner/name; defaults to the workspace repo)", "ref": "string (branch, tag, or sha)", "number": "integer (pull request number; alternative to ref)"}`
+}
+
+func (t *GetCIStatusTool) ToolClass() tools.ToolClass { return tools.ToolClassKnowledge }
+
+func (t *GetCIStatusTool) RequiresApproval() bool { return false }
+
+func (t *GetCIStatusTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args getCIStatusArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	hasRef := strings.TrimSpace(args.Ref) != ""
+	if hasRef == (args.Number > 0) {
+		return fmt.Errorf("exactly one of ref or number is required")
+	}
+	return nil
+}
+
+func (t *GetCIStatusTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args getCIStatusArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	client := t.clientProvider()
+	if client == nil {
+		return "GitHub integration is not configured.", nil
+	}
+	workspaceID, err := githubWorkspaceID(ctx)
+	if err != nil {
+		return "", err
+	}
+	status, err := client.CIStatus(ctx, workspaceID, args.Repo, args.Ref, args.Number)
+	if err != nil {
+		return "", err
+	}
+	sha := status.SHA
+	if len(sha) > 12 {
+		sha = sha[:12]
+	}
+	lines := []string{fmt.Sprintf("CI status for %s@%s: %s", status.Repo, sha, status.State)}
+	if len(status.CheckRuns) == 0 {
+		lines = append(lines, "No check runs reported.")
+	}
+	for _, run := range status.CheckRuns {
+		result := run.Status
+		if run.Conclusion != "" {
+			result = run.Conclusion
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s %s", run.Name, result, run.URL))
+	}
+	return strings.Join(lines, "\n"), nil
+}
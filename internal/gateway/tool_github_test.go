@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/github"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeGitHubClient struct {
+	workspaceID string
+	created     github.CreateIssueInput
+}
+
+func (f *fakeGitHubClient) ListIssues(ctx context.Context, workspaceID, repo, state string, limit int) ([]github.Issue, error) {
+	f.workspaceID = workspaceID
+	return []github.Issue{{Number: 3, Title: "Crash on login", State: "open", Labels: []string{"bug"}, URL: "https://github.com/acme/app/issues/3"}}, nil
+}
+
+func (f *fakeGitHubClient) CreateIssue(ctx context.Context, workspaceID string, input github.CreateIssueInput) (github.Issue, error) {
+	f.workspaceID = workspaceID
+	f.created = input
+	return github.Issue{Number: 4, Title: input.Title, URL: "https://github.com/acme/app/issues/4"}, nil
+}
+
+func (f *fakeGitHubClient) CommentOnPullRequest(ctx context.Context, workspaceID, repo string, number int, body string) (github.Comment, error) {
+	return github.Comment{ID: 1, URL: "https://github.com/acme/app/pull/9#issuecomment-1"}, nil
+}
+
+func (f *fakeGitHubClient) CIStatus(ctx context.Context, workspaceID, repo, ref string, prNumber int) (github.CIStatus, error) {
+	return github.CIStatus{Repo: "acme/app", SHA: "abc123", State: "success", CheckRuns: []github.CheckRun{{Name: "test", Status: "completed", Conclusion: "success"}}}, nil
+}
+
+func TestGitHubToolsUseWorkspaceClient(t *testing.T) {
+	client := &fakeGitHubClient{}
+	provider := func() GitHubClient { return client }
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws1"})
+
+	output, err := NewListIssuesTool(provider).Execute(ctx, json.RawMessage(`{"state":"open"}`))
+	if err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if !strings.Contains(output, "#3 [open] Crash on login (bug)") || client.workspaceID != "ws1" {
+		t.Fatalf("unexpected list output %q for workspace %q", output, client.workspaceID)
+	}
+	structured, err := NewListIssuesTool(provider).ExecuteStructured(ctx, json.RawMessage(`{"state":"open"}`))
+	if err != nil || structured.Kind != tools.ResultKindTable || structured.Summary != output {
+		t.Fatalf("unexpected structured list result %+v (%v)", structured, err)
+	}
+	if rendered := tools.RenderMarkdown(structured); !strings.Contains(rendered, "| [#3](https://github.com/acme/app/issues/3) | open | Crash on login | bug |  |") {
+		t.Fatalf("unexpected rendered issues table:\n%s", rendered)
+	}
+
+	createTool := NewCreateIssueTool(provider)
+	if !createTool.RequiresApproval() {
+		t.Fatal("expected create_issue to require approval")
+	}
+	output, err = createTool.Execute(ctx, json.RawMessage(`{"title":"Crash on login","body":"Reported in chat","labels":["bug"]}`))
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if !strings.Contains(output, "Created issue #4") || client.created.Body != "Reported in chat" {
+		t.Fatalf("unexpected create output %q input %+v", output, client.created)
+	}
+
+	output, err = NewGetCIStatusTool(provider).Execute(ctx, json.RawMessage(`{"number":9}`))
+	if err != nil {
+		t.Fatalf("ci status: %v", err)
+	}
+	if !strings.Contains(output, "acme/app@abc123: success") || !strings.Contains(output, "- test: success") {
+		t.Fatalf("unexpected ci output %q", output)
+	}
+}
+
+func TestGitHubToolsValidateArgs(t *testing.T) {
+	provider := func() GitHubClient { return nil }
+	if err := NewCreateIssueTool(provider).ValidateArgs(json.RawMessage(`{"body":"x"}`)); err == nil {
+		t.Fatal("expected missing title error")
+	}
+	if err := NewCommentOnPRTool(provider).ValidateArgs(json.RawMessage(`{"number":1}`)); err == nil {
+		t.Fatal("expected missing body error")
+	}
+	if err := NewGetCIStatusTool(provider).ValidateArgs(json.RawMessage(`{"ref":"main","number":1}`)); err == nil {
+		t.Fatal("expected ref/number exclusivity error")
+	}
+	output, err := NewListIssuesTool(provider).Execute(context.Background(), json.RawMessage(`{}`))
+	if err != nil || !strings.Contains(output, "not configured") {
+		t.Fatalf("expected not configured message, got %q %v", output, err)
+	}
+}
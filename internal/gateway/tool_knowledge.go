@@ -92,6 +92,9 @@ func (t *SearchTool) Execute(ctx context.Context, rawArgs json.RawMessage) (stri
 		if target == "" {
 			target = strings.TrimSpace(result.DocID)
 		}
+		if qmd.IsSharedTarget(target) {
+			target += " (shared knowledge)"
+		}
 		lines = append(lines, fmt.Sprintf("%d. %s\n   %s", i+1, target, compactSnippet(result.Snippet)))
 	}
 	return strings.Join(lines, "\n"), nil
@@ -112,8 +115,11 @@ func (t *OpenKnowledgeDocumentTool) ToolClass() tools.ToolClass {
 }
 func (t *OpenKnowledgeDocumentTool) RequiresApproval() bool { return false }
 
+// UntrustedOutput marks ingested documents as data the agent takes no instructions from.
+func (t *OpenKnowledgeDocumentTool) UntrustedOutput() bool { return true }
+
 func (t *OpenKnowledgeDocumentTool) Description() string {
-	return "Open a markdown document from the workspace knowledge base by path or doc id."
+	return "Open a markdown document or the extracted text of a saved attachment (PDF, DOCX, image) from the workspace knowledge base by path or doc id."
 }
 
 func (t *OpenKnowledgeDocumentTool) ParametersSchema() string {
@@ -23,21 +23,7 @@ type MCPDynamicTool struct {
 }
 
 func NewMCPDynamicTool(provider func() MCPRuntime, definition mcpclient.DiscoveredTool) *MCPDynamicTool {
-	toolClass := tools.ToolClassGeneral
-	switch strings.ToLower(strings.TrimSpace(definition.ToolClass)) {
-	case string(tools.ToolClassKnowledge):
-		toolClass = tools.ToolClassKnowledge
-	case string(tools.ToolClassTasking):
-		toolClass = tools.ToolClassTasking
-	case string(tools.ToolClassModeration):
-		toolClass = tools.ToolClassModeration
-	case string(tools.ToolClassObjective):
-		toolClass = tools.ToolClassObjective
-	case string(tools.ToolClassDrafting):
-		toolClass = tools.ToolClassDrafting
-	case string(tools.ToolClassSensitive):
-		toolClass = tools.ToolClassSensitive
-	}
+	toolClass := parseToolClass(definition.ToolClass)
 	schema := strings.TrimSpace(definition.InputSchemaJSON)
 	if schema == "" {
 		schema = `{}`
@@ -93,6 +93,9 @@ func (t *MCPReadResourceTool) ParametersSchema() string {
 func (t *MCPReadResourceTool) ToolClass() tools.ToolClass { return tools.ToolClassKnowledge }
 func (t *MCPReadResourceTool) RequiresApproval() bool     { return false }
 
+// UntrustedOutput marks MCP server content as data the agent takes no instructions from.
+func (t *MCPReadResourceTool) UntrustedOutput() bool { return true }
+
 func (t *MCPReadResourceTool) ValidateArgs(rawArgs json.RawMessage) error {
 	var args struct {
 		ServerID string `json:"server_id"`
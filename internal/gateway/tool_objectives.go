@@ -30,16 +30,18 @@ func (t *CreateObjectiveTool) Description() string {
 }
 
 func (t *CreateObjectiveTool) ParametersSchema() string {
-	return `{"title":"string","prompt":"string","cron_expr":"string(optional, default: 0 */6 * * *)","timezone":"string(optional, IANA timezone)","active":"boolean(optional)"}`
+	return `{"title":"string","prompt":"string","cron_expr":"string(optional, default: 0 */6 * * *)","timezone":"string(optional, IANA timezone)","active":"boolean(optional)","report_changes_only":"boolean(optional, compare each run with the last and report only changes)","digest_period":"daily|weekly(optional, collect runs into one digest message)"}`
 }
 
 func (t *CreateObjectiveTool) ValidateArgs(rawArgs json.RawMessage) error {
 	var args struct {
-		Title    string `json:"title"`
-		Prompt   string `json:"prompt"`
-		CronExpr string `json:"cron_expr"`
-		Timezone string `json:"timezone"`
-		Active   *bool  `json:"active"`
+		Title             string `json:"title"`
+		Prompt            string `json:"prompt"`
+		CronExpr          string `json:"cron_expr"`
+		Timezone          string `json:"timezone"`
+		Active            *bool  `json:"active"`
+		ReportChangesOnly bool   `json:"report_changes_only"`
+		DigestPeriod      string `json:"digest_period"`
 	}
 	if err := strictDecodeArgs(rawArgs, &args); err != nil {
 		return err
@@ -61,16 +63,21 @@ func (t *CreateObjectiveTool) ValidateArgs(rawArgs json.RawMessage) error {
 			return fmt.Errorf("cron_expr is invalid")
 		}
 	}
+	if !validDigestPeriodArg(args.DigestPeriod) {
+		return fmt.Errorf("digest_period must be daily or weekly")
+	}
 	return nil
 }
 
 func (t *CreateObjectiveTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
 	var args struct {
-		Title    string `json:"title"`
-		Prompt   string `json:"prompt"`
-		CronExpr string `json:"cron_expr"`
-		Timezone string `json:"timezone"`
-		Active   *bool  `json:"active"`
+		Title             string `json:"title"`
+		Prompt            string `json:"prompt"`
+		CronExpr          string `json:"cron_expr"`
+		Timezone          string `json:"timezone"`
+		Active            *bool  `json:"active"`
+		ReportChangesOnly bool   `json:"report_changes_only"`
+		DigestPeriod      string `json:"digest_period"`
 	}
 	if err := strictDecodeArgs(rawArgs, &args); err != nil {
 		return "", fmt.Errorf("invalid arguments: %w", err)
@@ -97,14 +104,16 @@ func (t *CreateObjectiveTool) Execute(ctx context.Context, rawArgs json.RawMessa
 		cronExpr = defaultObjectiveCronExpr
 	}
 	obj, err := t.store.CreateObjective(ctx, store.CreateObjectiveInput{
-		WorkspaceID: record.WorkspaceID,
-		ContextID:   record.ID,
-		Title:       strings.TrimSpace(args.Title),
-		Prompt:      strings.TrimSpace(args.Prompt),
-		TriggerType: store.ObjectiveTriggerSchedule,
-		CronExpr:    cronExpr,
-		Timezone:    strings.TrimSpace(args.Timezone),
-		Active:      args.Active,
+		WorkspaceID:       record.WorkspaceID,
+		ContextID:         record.ID,
+		Title:             strings.TrimSpace(args.Title),
+		Prompt:            strings.TrimSpace(args.Prompt),
+		TriggerType:       store.ObjectiveTriggerSchedule,
+		CronExpr:          cronExpr,
+		Timezone:          strings.TrimSpace(args.Timezone),
+		Active:            args.Active,
+		ReportChangesOnly: args.ReportChangesOnly,
+		DigestPeriod:      store.ObjectiveDigestPeriod(args.DigestPeriod),
 	})
 	if err != nil {
 		return "", err
@@ -131,19 +140,21 @@ func (t *UpdateObjectiveTool) Description() string {
 }
 
 func (t *UpdateObjectiveTool) ParametersSchema() string {
-	return `{"objective_id":"string","title":"string(optional)","prompt":"string(optional)","trigger_type":"schedule|event(optional)","event_key":"string(optional)","cron_expr":"string(optional)","timezone":"string(optional, IANA timezone)","active":"boolean(optional)"}`
+	return `{"objective_id":"string","title":"string(optional)","prompt":"string(optional)","trigger_type":"schedule|event(optional)","event_key":"string(optional)","cron_expr":"string(optional)","timezone":"string(optional, IANA timezone)","active":"boolean(optional)","report_changes_only":"boolean(optional)","digest_period":"daily|weekly|empty for every run(optional)"}`
 }
 
 func (t *UpdateObjectiveTool) ValidateArgs(rawArgs json.RawMessage) error {
 	var args struct {
-		ObjectiveID string  `json:"objective_id"`
-		Title       string  `json:"title"`
-		Prompt      string  `json:"prompt"`
-		TriggerType string  `json:"trigger_type"`
-		EventKey    string  `json:"event_key"`
-		CronExpr    *string `json:"cron_expr"`
-		Timezone    *string `json:"timezone"`
-		Active      *bool   `json:"active"`
+		ObjectiveID       string  `json:"objective_id"`
+		Title             string  `json:"title"`
+		Prompt            string  `json:"prompt"`
+		TriggerType       string  `json:"trigger_type"`
+		EventKey          string  `json:"event_key"`
+		CronExpr          *string `json:"cron_expr"`
+		Timezone          *string `json:"timezone"`
+		Active            *bool   `json:"active"`
+		ReportChangesOnly *bool   `json:"report_changes_only"`
+		DigestPeriod      *string `json:"digest_period"`
 	}
 	if err := strictDecodeArgs(rawArgs, &args); err != nil {
 		return err
@@ -160,6 +171,9 @@ func (t *UpdateObjectiveTool) ValidateArgs(rawArgs json.RawMessage) error {
 			return fmt.Errorf("event_key is required when trigger_type is event")
 		}
 	}
+	if args.DigestPeriod != nil && !validDigestPeriodArg(*args.DigestPeriod) {
+		return fmt.Errorf("digest_period must be daily, weekly or empty")
+	}
 	timezone := ""
 	if args.Timezone != nil {
 		timezone = strings.TrimSpace(*args.Timezone)
@@ -185,7 +199,9 @@ func (t *UpdateObjectiveTool) ValidateArgs(rawArgs json.RawMessage) error {
 		strings.TrimSpace(args.EventKey) == "" &&
 		args.CronExpr == nil &&
 		args.Timezone == nil &&
-		args.Active == nil {
+		args.Active == nil &&
+		args.ReportChangesOnly == nil &&
+		args.DigestPeriod == nil {
 		return fmt.Errorf("at least one field must be provided")
 	}
 	return nil
@@ -193,14 +209,16 @@ func (t *UpdateObjectiveTool) ValidateArgs(rawArgs json.RawMessage) error {
 
 func (t *UpdateObjectiveTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
 	var args struct {
-		ObjectiveID string  `json:"objective_id"`
-		Title       string  `json:"title"`
-		Prompt      string  `json:"prompt"`
-		TriggerType string  `json:"trigger_type"`
-		EventKey    string  `json:"event_key"`
-		CronExpr    *string `json:"cron_expr"`
-		Timezone    *string `json:"timezone"`
-		Active      *bool   `json:"active"`
+		ObjectiveID       string  `json:"objective_id"`
+		Title             string  `json:"title"`
+		Prompt            string  `json:"prompt"`
+		TriggerType       string  `json:"trigger_type"`
+		EventKey          string  `json:"event_key"`
+		CronExpr          *string `json:"cron_expr"`
+		Timezone          *string `json:"timezone"`
+		Active            *bool   `json:"active"`
+		ReportChangesOnly *bool   `json:"report_changes_only"`
+		DigestPeriod      *string `json:"digest_period"`
 	}
 	if err := strictDecodeArgs(rawArgs, &args); err != nil {
 		return "", fmt.Errorf("invalid arguments: %w", err)
@@ -237,9 +255,22 @@ func (t *UpdateObjectiveTool) Execute(ctx context.Context, rawArgs json.RawMessa
 	if args.Active != nil {
 		update.Active = args.Active
 	}
+	update.ReportChangesOnly = args.ReportChangesOnly
+	if args.DigestPeriod != nil {
+		period := store.ObjectiveDigestPeriod(*args.DigestPeriod)
+		update.DigestPeriod = &period
+	}
 	obj, err := t.store.UpdateObjective(ctx, update)
 	if err != nil {
 		return "", err
 	}
 	return fmt.Sprintf("Objective updated successfully (ID: %s, active=%t).", obj.ID, obj.Active), nil
 }
+
+func validDigestPeriodArg(value string) bool {
+	switch store.ObjectiveDigestPeriod(strings.ToLower(strings.TrimSpace(value))) {
+	case store.ObjectiveDigestNone, store.ObjectiveDigestDaily, store.ObjectiveDigestWeekly:
+		return true
+	}
+	return false
+}
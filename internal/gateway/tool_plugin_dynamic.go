@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/toolplugins"
+)
+
+// ToolPluginRunner executes calls against subprocess tool plugins.
+type ToolPluginRunner interface {
+	Execute(ctx context.Context, definition toolplugins.Definition, args json.RawMessage, callContext toolplugins.CallContext) (string, error)
+}
+
+type PluginDynamicTool struct {
+	runner     ToolPluginRunner
+	definition toolplugins.Definition
+	toolClass  tools.ToolClass
+}
+
+func NewPluginDynamicTool(runner ToolPluginRunner, definition toolplugins.Definition) *PluginDynamicTool {
+	if strings.TrimSpace(definition.Description) == "" {
+		definition.Description = fmt.Sprintf("Plugin tool %s from %s", definition.ToolName, definition.Plugin)
+	}
+	if strings.TrimSpace(definition.ParametersSchema) == "" {
+		definition.ParametersSchema = `{}`
+	}
+	return &PluginDynamicTool{
+		runner:     runner,
+		definition: definition,
+		toolClass:  parseToolClass(definition.ToolClass),
+	}
+}
+
+func (t *PluginDynamicTool) Name() string { return t.definition.RegisteredName }
+
+func (t *PluginDynamicTool) Description() string { return t.definition.Description }
+
+func (t *PluginDynamicTool) ParametersSchema() string { return t.definition.ParametersSchema }
+
+func (t *PluginDynamicTool) ToolClass() tools.ToolClass { return t.toolClass }
+
+func (t *PluginDynamicTool) RequiresApproval() bool { return t.definition.RequiresApproval }
+
+func (t *PluginDynamicTool) ValidateArgs(rawArgs json.RawMessage) error {
+	if len(rawArgs) == 0 {
+		return nil
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(rawArgs, &payload); err != nil {
+		return fmt.Errorf("invalid plugin tool arguments: %w", err)
+	}
+	return nil
+}
+
+func (t *PluginDynamicTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if t.runner == nil {
+		return "", fmt.Errorf("tool plugin runner is not configured")
+	}
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord)
+	if !ok {
+		return "", fmt.Errorf("internal error: context record missing from context")
+	}
+	input, _ := ctx.Value(ContextKeyInput).(MessageInput)
+	return t.runner.Execute(ctx, t.definition, rawArgs, toolplugins.CallContext{
+		WorkspaceID: strings.TrimSpace(record.WorkspaceID),
+		ContextID:   strings.TrimSpace(record.ID),
+		Connector:   strings.TrimSpace(input.Connector),
+		ExternalID:  strings.TrimSpace(input.ExternalID),
+		UserID:      strings.TrimSpace(input.FromUserID),
+	})
+}
+
+func BuildPluginDynamicTools(runner ToolPluginRunner, definitions []toolplugins.Definition) []tools.Tool {
+	result := make([]tools.Tool, 0, len(definitions))
+	for _, definition := range definitions {
+		result = append(result, NewPluginDynamicTool(runner, definition))
+	}
+	return result
+}
+
+func parseToolClass(raw string) tools.ToolClass {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case string(tools.ToolClassKnowledge):
+		return tools.ToolClassKnowledge
+	case string(tools.ToolClassTasking):
+		return tools.ToolClassTasking
+	case string(tools.ToolClassModeration):
+		return tools.ToolClassModeration
+	case string(tools.ToolClassObjective):
+		return tools.ToolClassObjective
+	case string(tools.ToolClassDrafting):
+		return tools.ToolClassDrafting
+	case string(tools.ToolClassSensitive):
+		return tools.ToolClassSensitive
+	default:
+		return tools.ToolClassGeneral
+	}
+}
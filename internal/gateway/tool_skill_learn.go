@@ -13,6 +13,8 @@ import (
 // LearnSkillTool implements tools.Tool for persisting knowledge.
 type LearnSkillTool struct {
 	workspaceRoot string
+	secrets       secretScanner
+	dryRun        func() bool
 }
 
 func NewLearnSkillTool(workspaceRoot string) *LearnSkillTool {
@@ -45,12 +47,18 @@ func (t *LearnSkillTool) Execute(ctx context.Context, rawArgs json.RawMessage) (
 
 	// We'll put it in context/skills/common for now, or a workspace-specific one
 	skillDir := filepath.Join(t.workspaceRoot, record.WorkspaceID, "context", "skills", "common")
+	content, err := t.secrets.scan(ctx, t.Name(), record, filepath.Join("context", "skills", "common", args.Name+".md"), args.Content)
+	if err != nil {
+		return "", err
+	}
+	if t.dryRun != nil && t.dryRun() {
+		return fmt.Sprintf("Dry run: the skill %s (%d bytes) was not saved.", args.Name, len(content)), nil
+	}
 	if err := os.MkdirAll(skillDir, 0o755); err != nil {
 		return "", err
 	}
-
 	path := filepath.Join(skillDir, args.Name+".md")
-	if err := os.WriteFile(path, []byte(args.Content), 0o644); err != nil {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
 		return "", err
 	}
 
@@ -14,8 +14,12 @@ import (
 
 // CreateTaskTool implements tools.Tool for creating tasks.
 type CreateTaskTool struct {
-	engine Engine
-	store  Store
+	engine       Engine
+	store        Store
+	backpressure func() Backpressure
+	// allowed returns a refusal when the sender may not create tasks in
+	// the context.
+	allowed func(ctx context.Context, input MessageInput, contextID string) (string, error)
 }
 
 func NewCreateTaskTool(store Store, engine Engine) *CreateTaskTool {
@@ -29,18 +33,23 @@ func (t *CreateTaskTool) ToolClass() tools.ToolClass {
 func (t *CreateTaskTool) RequiresApproval() bool { return false }
 
 func (t *CreateTaskTool) Description() string {
-	return "Create a background task for complex jobs, investigations, or system changes."
+	return "Create a background task for complex jobs, investigations, or system changes. " +
+		"For multi-step workflows pass depends_on with the IDs of earlier tasks; the task starts only after all of them succeed. " +
+		"To run it later pass schedule as a timestamp (2026-05-01T09:00) or a cron expression whose next slot is used, with an optional IANA timezone."
 }
 
 func (t *CreateTaskTool) ParametersSchema() string {
-	return `{"title": "string", "description": "string", "priority": "p1|p2|p3"}`
+	return `{"title": "string", "description": "string", "priority": "p1|p2|p3", "depends_on": ["task id that must succeed first"], "schedule": "RFC3339 or local date-time or cron expression (optional)", "timezone": "IANA timezone for schedule (optional)"}`
 }
 
 func (t *CreateTaskTool) ValidateArgs(rawArgs json.RawMessage) error {
 	var args struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Priority    string `json:"priority"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Priority    string   `json:"priority"`
+		DependsOn   []string `json:"depends_on"`
+		Schedule    string   `json:"schedule"`
+		Timezone    string   `json:"timezone"`
 	}
 	if err := strictDecodeArgs(rawArgs, &args); err != nil {
 		return err
@@ -64,14 +73,28 @@ func (t *CreateTaskTool) ValidateArgs(rawArgs json.RawMessage) error {
 			return fmt.Errorf("priority must be p1, p2, or p3")
 		}
 	}
+	if len(args.DependsOn) > 20 {
+		return fmt.Errorf("depends_on lists too many tasks")
+	}
+	for _, id := range args.DependsOn {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("depends_on must list task IDs")
+		}
+	}
+	if _, err := store.ResolveTaskSchedule(args.Schedule, args.Timezone, time.Now().UTC()); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (t *CreateTaskTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
 	var args struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Priority    string `json:"priority"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Priority    string   `json:"priority"`
+		DependsOn   []string `json:"depends_on"`
+		Schedule    string   `json:"schedule"`
+		Timezone    string   `json:"timezone"`
 	}
 	if err := strictDecodeArgs(rawArgs, &args); err != nil {
 		return "", fmt.Errorf("invalid arguments: %w", err)
@@ -81,6 +104,15 @@ func (t *CreateTaskTool) Execute(ctx context.Context, rawArgs json.RawMessage) (
 	if err != nil {
 		return "", err
 	}
+	if t.allowed != nil {
+		denied, err := t.allowed(ctx, input, record.ID)
+		if err != nil {
+			return "", err
+		}
+		if denied != "" {
+			return "Task not created. " + denied, nil
+		}
+	}
 
 	// Check approval if not system/admin
 	// CreateTaskTool previously had RequiresApproval() = false,
@@ -99,17 +131,51 @@ func (t *CreateTaskTool) Execute(ctx context.Context, rawArgs json.RawMessage) (
 	// I should update `RunActionTool` to also support auto-approval for Admin!
 	// This is the missing piece. I updated specialized tools, but RunActionTool (the generic one used by legacy/chat agent) still blocks.
 
+	dependsOn := []string{}
+	for _, id := range args.DependsOn {
+		id = strings.TrimSpace(id)
+		prerequisite, err := t.store.LookupTask(ctx, id)
+		if err != nil || prerequisite.WorkspaceID != record.WorkspaceID {
+			return fmt.Sprintf("Task not created. Task %s in depends_on was not found in this workspace.", id), nil
+		}
+		if status := strings.ToLower(strings.TrimSpace(prerequisite.Status)); status == "failed" || status == "cancelled" {
+			return fmt.Sprintf("Task not created. Task %s in depends_on already %s.", id, status), nil
+		}
+		dependsOn = append(dependsOn, id)
+	}
+
+	now := time.Now().UTC()
+	runAt, err := store.ResolveTaskSchedule(args.Schedule, args.Timezone, now)
+	if err != nil {
+		return fmt.Sprintf("Task not created. %v.", err), nil
+	}
+	dueAt := now.Add(24 * time.Hour)
+	if !runAt.IsZero() {
+		dueAt = runAt.Add(24 * time.Hour)
+	}
+
 	priority := "p3"
 	if p, ok := normalizeTriagePriority(args.Priority); ok {
 		priority = string(p)
 	}
 
-	task, err := t.engine.Enqueue(orchestrator.Task{
+	queue, busy := t.engine.Enqueue, false
+	if t.backpressure != nil {
+		if backpressure := t.backpressure(); backpressure != nil && backpressure.UnderPressure() {
+			busy = true
+			if priority == string(TriagePriorityP3) {
+				queue = backpressure.Hold
+			}
+		}
+	}
+	task, err := queue(orchestrator.Task{
 		WorkspaceID: record.WorkspaceID,
 		ContextID:   record.ID,
 		Kind:        orchestrator.TaskKindGeneral,
 		Title:       args.Title,
 		Prompt:      args.Description,
+		DependsOn:   dependsOn,
+		RunAt:       runAt,
 	})
 	if err != nil {
 		return "", err
@@ -125,16 +191,31 @@ func (t *CreateTaskTool) Execute(ctx context.Context, rawArgs json.RawMessage) (
 		Status:           "queued",
 		RouteClass:       string(TriageTask),
 		Priority:         priority,
-		DueAt:            time.Now().UTC().Add(24 * time.Hour),
+		DueAt:            dueAt,
 		AssignedLane:     "operations",
 		SourceConnector:  strings.ToLower(strings.TrimSpace(input.Connector)),
 		SourceExternalID: strings.TrimSpace(input.ExternalID),
 		SourceUserID:     strings.TrimSpace(input.FromUserID),
 		SourceText:       input.Text,
+		DependsOn:        task.DependsOn,
+		RunAt:            runAt,
 	})
 	if persistErr != nil {
 		return "", fmt.Errorf("task queued but failed to persist: %w", persistErr)
 	}
 
+	if !runAt.IsZero() {
+		scheduled := runAt.Format("2006-01-02 15:04 UTC")
+		if len(dependsOn) > 0 {
+			return fmt.Sprintf("Task created successfully (ID: %s). It runs at %s once %s succeeded.", task.ID, scheduled, strings.Join(dependsOn, ", ")), nil
+		}
+		return fmt.Sprintf("Task created successfully (ID: %s). It runs at %s.", task.ID, scheduled), nil
+	}
+	if len(dependsOn) > 0 {
+		return fmt.Sprintf("Task created successfully (ID: %s). It starts once %s succeeded.", task.ID, strings.Join(dependsOn, ", ")), nil
+	}
+	if busy {
+		return fmt.Sprintf("Task created successfully (ID: %s). %s", task.ID, queueBusyNotice), nil
+	}
 	return fmt.Sprintf("Task created successfully (ID: %s).", task.ID), nil
 }
@@ -12,11 +12,12 @@ import (
 )
 
 type UpdateTaskTool struct {
-	store Store
+	store  Store
+	syncer func() TaskSyncer
 }
 
-func NewUpdateTaskTool(store Store) *UpdateTaskTool {
-	return &UpdateTaskTool{store: store}
+func NewUpdateTaskTool(store Store, syncer func() TaskSyncer) *UpdateTaskTool {
+	return &UpdateTaskTool{store: store, syncer: syncer}
 }
 
 func (t *UpdateTaskTool) Name() string { return "update_task" }
@@ -108,6 +109,7 @@ func (t *UpdateTaskTool) Execute(ctx context.Context, rawArgs json.RawMessage) (
 		if err := t.store.MarkTaskCompleted(ctx, taskID, time.Now().UTC(), summary, ""); err != nil {
 			return "", err
 		}
+		t.sync(ctx, taskID)
 		return fmt.Sprintf("Task closed successfully (ID: %s).", taskID), nil
 	}
 
@@ -169,5 +171,13 @@ func (t *UpdateTaskTool) Execute(ctx context.Context, rawArgs json.RawMessage) (
 	}); err != nil {
 		return "", err
 	}
+	t.sync(ctx, taskID)
 	return fmt.Sprintf("Task updated successfully (ID: %s).", taskID), nil
 }
+
+func (t *UpdateTaskTool) sync(ctx context.Context, taskID string) {
+	if t.syncer == nil {
+		return
+	}
+	syncTaskWith(ctx, t.syncer(), taskID, nil)
+}
@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/agent/tools"
+	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/translate"
+)
+
+type Translator interface {
+	Translate(ctx context.Context, req translate.Request) (string, error)
+}
+
+// MessageMirror republishes inbound channel messages into the mirror
+// channels configured for their workspace.
+type MessageMirror interface {
+	MirrorMessage(ctx context.Context, input MessageInput)
+}
+
+func (s *Service) SetTranslator(translator Translator) {
+	s.translator = translator
+}
+
+func (s *Service) SetMessageMirror(mirror MessageMirror) {
+	s.messageMirror = mirror
+}
+
+func (s *Service) mirrorMessage(ctx context.Context, input MessageInput) {
+	if s.messageMirror == nil {
+		return
+	}
+	s.messageMirror.MirrorMessage(ctx, input)
+}
+
+// TranslateTool translates text using the workspace glossary in
+// context/translation.json.
+type TranslateTool struct {
+	workspaceRoot      string
+	translatorProvider func() Translator
+}
+
+type translateArgs struct {
+	Text           string `json:"text"`
+	TargetLanguage string `json:"target_language"`
+	SourceLanguage string `json:"source_language"`
+}
+
+func NewTranslateTool(workspaceRoot string, provider func() Translator) *TranslateTool {
+	return &TranslateTool{workspaceRoot: workspaceRoot, translatorProvider: provider}
+}
+
+func (t *TranslateTool) Name() string { return "translate" }
+
+func (t *TranslateTool) Description() string {
+	return "Translate text into another language using the workspace terminology glossary."
+}
+
+func (t *TranslateTool) ParametersSchema() string {
+	return `{"text": "string", "target_language": "string (language code or name, e.g. es)", "source_language": "string (optional)"}`
+}
+
+func (t *TranslateTool) ToolClass() tools.ToolClass { return tools.ToolClassDrafting }
+
+func (t *TranslateTool) RequiresApproval() bool { return false }
+
+func (t *TranslateTool) ValidateArgs(rawArgs json.RawMessage) error {
+	var args translateArgs
+	if err := strictDecodeArgs(rawArgs, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Text) == "" {
+		return fmt.Errorf("text is required")
+	}
+	if strings.TrimSpace(args.TargetLanguage) == "" {
+		return fmt.Errorf("target_language is required")
+	}
+	return nil
+}
+
+func (t *TranslateTool) Execute(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	if err := t.ValidateArgs(rawArgs); err != nil {
+		return "", err
+	}
+	var args translateArgs
+	_ = json.Unmarshal(rawArgs, &args)
+	translator := t.translatorProvider()
+	if translator == nil {
+		return "Translation is not configured.", nil
+	}
+	req := translate.Request{
+		Text:           args.Text,
+		TargetLanguage: strings.TrimSpace(args.TargetLanguage),
+		SourceLanguage: strings.TrimSpace(args.SourceLanguage),
+	}
+	if record, ok := ctx.Value(ContextKeyRecord).(store.ContextRecord); ok {
+		settings, err := translate.LoadSettings(t.workspaceRoot, record.WorkspaceID)
+		if err != nil {
+			return "", err
+		}
+		req.WorkspaceID = record.WorkspaceID
+		req.Glossary = settings.Glossary
+	}
+	translation, err := translator.Translate(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if missing := translate.MissingTerms(translate.Relevant(req.Glossary, req.Text), req.TargetLanguage, translation); len(missing) > 0 {
+		translation += "\n\nGlossary check: expected " + strings.Join(missing, ", ")
+	}
+	return translation, nil
+}
@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+	"github.com/dwizi/agent-runtime/internal/translate"
+)
+
+type fakeTranslator struct {
+	req   translate.Request
+	reply string
+}
+
+func (f *fakeTranslator) Translate(ctx context.Context, req translate.Request) (string, error) {
+	f.req = req
+	return f.reply, nil
+}
+
+type fakeMessageMirror struct {
+	inputs []MessageInput
+}
+
+func (f *fakeMessageMirror) MirrorMessage(ctx context.Context, input MessageInput) {
+	f.inputs = append(f.inputs, input)
+}
+
+func TestTranslateToolAppliesWorkspaceGlossary(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "ws-1", "context")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	glossary := `{"glossary": [{"term": "objective", "translations": {"es": "objetivo"}}, {"term": "Dwizi", "keep": true}]}`
+	if err := os.WriteFile(filepath.Join(dir, "translation.json"), []byte(glossary), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	translator := &fakeTranslator{reply: "Nueva meta en Dwizi"}
+	tool := NewTranslateTool(root, func() Translator { return translator })
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{ID: "ctx-1", WorkspaceID: "ws-1"})
+
+	res, err := tool.Execute(ctx, json.RawMessage(`{"text": "New objective on Dwizi", "target_language": "es"}`))
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	if translator.req.WorkspaceID != "ws-1" || len(translator.req.Glossary) != 2 {
+		t.Fatalf("expected workspace glossary in request, got %+v", translator.req)
+	}
+	if res != "Nueva meta en Dwizi\n\nGlossary check: expected objetivo" {
+		t.Fatalf("unexpected output %q", res)
+	}
+	if err := tool.ValidateArgs(json.RawMessage(`{"text": "hi"}`)); err == nil {
+		t.Fatal("expected target_language to be required")
+	}
+	unconfigured := NewTranslateTool(root, func() Translator { return nil })
+	if res, _ := unconfigured.Execute(ctx, json.RawMessage(`{"text": "hi", "target_language": "es"}`)); !strings.Contains(res, "not configured") {
+		t.Fatalf("expected not configured message, got %q", res)
+	}
+}
+
+func TestHandleMessageMirrorsPlainMessagesOnly(t *testing.T) {
+	service := New(&fakeStore{}, &fakeEngine{}, nil, nil, "", nil)
+	service.SetTriageEnabled(false)
+	mirror := &fakeMessageMirror{}
+	service.SetMessageMirror(mirror)
+
+	for _, text := range []string{"/status", "Release 1.4 ships on Friday"} {
+		if _, err := service.HandleMessage(context.Background(), MessageInput{Connector: "discord", ExternalID: "announce", FromUserID: "u1", Text: text}); err != nil {
+			t.Fatalf("handle %q: %v", text, err)
+		}
+	}
+	if len(mirror.inputs) != 1 || mirror.inputs[0].Text != "Release 1.4 ships on Friday" {
+		t.Fatalf("expected only the announcement mirrored, got %+v", mirror.inputs)
+	}
+}
@@ -173,6 +173,95 @@ func TestCreateTaskTool_Execute(t *testing.T) {
 	}
 }
 
+func TestCreateTaskToolPassesDependencies(t *testing.T) {
+	var persisted store.CreateTaskInput
+	mockStore := &MockStore{
+		CreateTaskFunc: func(ctx context.Context, input store.CreateTaskInput) error {
+			persisted = input
+			return nil
+		},
+		LookupTaskFunc: func(ctx context.Context, id string) (store.TaskRecord, error) {
+			switch id {
+			case "task-fetch":
+				return store.TaskRecord{ID: id, WorkspaceID: "ws-1", Status: "running"}, nil
+			case "task-broken":
+				return store.TaskRecord{ID: id, WorkspaceID: "ws-1", Status: "failed"}, nil
+			case "task-foreign":
+				return store.TaskRecord{ID: id, WorkspaceID: "ws-2", Status: "queued"}, nil
+			}
+			return store.TaskRecord{}, store.ErrTaskNotFound
+		},
+	}
+	var queued orchestrator.Task
+	mockEngine := &MockEngine{EnqueueFunc: func(task orchestrator.Task) (orchestrator.Task, error) {
+		task.ID = "task-report"
+		queued = task
+		return task, nil
+	}}
+	tool := NewCreateTaskTool(mockStore, mockEngine)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws-1", ID: "ctx-1"})
+	ctx = context.WithValue(ctx, ContextKeyInput, MessageInput{Text: "fetch then report"})
+
+	out, err := tool.Execute(ctx, json.RawMessage(`{"title":"Report","description":"Summarize the fetched data","depends_on":["task-fetch"]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "task-report") || !strings.Contains(out, "task-fetch") {
+		t.Fatalf("expected output to name the task and its prerequisite, got %q", out)
+	}
+	if len(queued.DependsOn) != 1 || len(persisted.DependsOn) != 1 || persisted.DependsOn[0] != "task-fetch" {
+		t.Fatalf("expected dependency queued and persisted, got %v and %v", queued.DependsOn, persisted.DependsOn)
+	}
+
+	for _, id := range []string{"task-broken", "task-foreign", "task-missing"} {
+		queued = orchestrator.Task{}
+		out, err := tool.Execute(ctx, json.RawMessage(`{"title":"Report","description":"Summarize","depends_on":["`+id+`"]}`))
+		if err != nil || !strings.HasPrefix(out, "Task not created.") || queued.Title != "" {
+			t.Fatalf("expected %s refused, got %q (%v)", id, out, err)
+		}
+	}
+}
+
+func TestCreateTaskToolSchedulesTask(t *testing.T) {
+	var persisted store.CreateTaskInput
+	mockStore := &MockStore{
+		CreateTaskFunc: func(ctx context.Context, input store.CreateTaskInput) error {
+			persisted = input
+			return nil
+		},
+	}
+	var queued orchestrator.Task
+	mockEngine := &MockEngine{EnqueueFunc: func(task orchestrator.Task) (orchestrator.Task, error) {
+		task.ID = "task-later"
+		queued = task
+		return task, nil
+	}}
+	tool := NewCreateTaskTool(mockStore, mockEngine)
+	ctx := context.WithValue(context.Background(), ContextKeyRecord, store.ContextRecord{WorkspaceID: "ws-1", ID: "ctx-1"})
+	ctx = context.WithValue(ctx, ContextKeyInput, MessageInput{Text: "run this tomorrow at 9am"})
+
+	args := json.RawMessage(`{"title":"Standup notes","description":"Collect notes","schedule":"0 9 * * *","timezone":"Europe/Berlin"}`)
+	if err := tool.ValidateArgs(args); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	out, err := tool.Execute(ctx, args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queued.RunAt.IsZero() || !queued.RunAt.After(time.Now()) || !persisted.RunAt.Equal(queued.RunAt) {
+		t.Fatalf("expected a future run time queued and persisted, got %s and %s", queued.RunAt, persisted.RunAt)
+	}
+	if !strings.Contains(out, "It runs at "+queued.RunAt.Format("2006-01-02 15:04 UTC")) {
+		t.Fatalf("expected output to name the run time, got %q", out)
+	}
+
+	for _, schedule := range []string{"2020-01-01T09:00:00Z", "someday"} {
+		if err := tool.ValidateArgs(json.RawMessage(`{"title":"Later","description":"Later","schedule":"` + schedule + `"}`)); err == nil {
+			t.Fatalf("expected schedule %q rejected", schedule)
+		}
+	}
+}
+
 func TestOpenKnowledgeDocumentTool_Execute(t *testing.T) {
 	mockRetriever := &MockRetriever{
 		OpenMarkdownFunc: func(ctx context.Context, workspaceID, target string) (qmd.OpenResult, error) {
@@ -301,7 +390,7 @@ func TestUpdateTaskTool_Close(t *testing.T) {
 			return nil
 		},
 	}
-	tool := NewUpdateTaskTool(mockStore)
+	tool := NewUpdateTaskTool(mockStore, nil)
 	ctx := context.WithValue(context.Background(), ContextKeyInput, MessageInput{Text: "close"})
 	ctx = agent.WithSensitiveToolApproval(ctx)
 
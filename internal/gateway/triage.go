@@ -41,6 +41,11 @@ type RouteDecision struct {
 	SourceUserID     string
 	SourceText       string
 	Reason           string
+	// CaseID is the moderation case recorded for the sender, if any.
+	CaseID string
+	// TrackingCaseID is the case grouping this decision's task, moderation
+	// case and message, opened for incidents and moderation.
+	TrackingCaseID string
 }
 
 func normalizeTriageClass(value string) (TriageClass, bool) {
@@ -17,10 +17,12 @@ import (
 type WebSearchTool struct {
 	store          Store
 	actionExecutor ActionExecutor
+	approver       autoApprover
+	egress         egressChecker
 }
 
 func NewWebSearchTool(store Store, executor ActionExecutor) *WebSearchTool {
-	return &WebSearchTool{store: store, actionExecutor: executor}
+	return &WebSearchTool{store: store, actionExecutor: executor, approver: autoApprover{store: store}}
 }
 
 func (t *WebSearchTool) Name() string { return "web_search" }
@@ -29,6 +31,14 @@ func (t *WebSearchTool) ToolClass() tools.ToolClass {
 }
 func (t *WebSearchTool) RequiresApproval() bool { return false }
 
+// UntrustedOutput marks search results as data the agent takes no instructions from.
+func (t *WebSearchTool) UntrustedOutput() bool { return true }
+
+// ExecutionPolicy retries searches that fail on the network; they are read-only.
+func (t *WebSearchTool) ExecutionPolicy() tools.ExecutionPolicy {
+	return tools.ExecutionPolicy{MaxRetries: networkToolRetries}
+}
+
 func (t *WebSearchTool) Description() string {
 	return "Search the web for information. Currently uses DuckDuckGo."
 }
@@ -59,6 +69,9 @@ func (t *WebSearchTool) Execute(ctx context.Context, rawArgs json.RawMessage) (s
 	if !ok {
 		return "", fmt.Errorf("internal error: message input missing from context")
 	}
+	if err := t.egress.check(ctx, t.Name(), record, input, searchURL); err != nil {
+		return "", err
+	}
 
 	// 1. Create approval for curl
 	approval, err := t.store.CreateActionApproval(ctx, store.CreateActionApprovalInput{
@@ -80,18 +93,7 @@ func (t *WebSearchTool) Execute(ctx context.Context, rawArgs json.RawMessage) (s
 	}
 
 	// 2. Check if we can auto-approve
-	canAutoApprove := false
-	if input.FromUserID == "system:task-worker" {
-		canAutoApprove = true
-	} else {
-		if identity, err := t.store.LookupUserIdentity(ctx, input.Connector, input.FromUserID); err == nil {
-			if identity.Role == "admin" || identity.Role == "overlord" {
-				canAutoApprove = true
-			}
-		}
-	}
-	
-	if !canAutoApprove {
+	if !t.approver.allows(ctx, t.Name(), input, approval) {
 		return actions.FormatApprovalRequestNotice(approval.ID), nil
 	}
 
@@ -101,6 +103,9 @@ func (t *WebSearchTool) Execute(ctx context.Context, rawArgs json.RawMessage) (s
 		ApproverUserID: "system:agent",
 	})
 	if err != nil {
+		if reply, ok := quorumPendingToolReply(approved, err); ok {
+			return reply, nil
+		}
 		return "", fmt.Errorf("auto-approve failed: %w", err)
 	}
 
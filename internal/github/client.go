@@ -0,0 +1,477 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultAPIBase                = "https://api.github.com"
+	DefaultWorkspaceConfigRelPath = "context/github.json"
+	maxResponseBytes              = 4 << 20
+)
+
+var (
+	ErrNotConfigured     = errors.New("github is not configured for this workspace")
+	ErrRepoNotAllowed    = errors.New("repository is not allowed for this workspace")
+	ErrInvalidRepository = errors.New("repository must be in owner/name form")
+)
+
+type Config struct {
+	AppID                  int64
+	PrivateKeyPEM          []byte
+	APIBase                string
+	WorkspaceRoot          string
+	WorkspaceConfigRelPath string
+	Timeout                time.Duration
+}
+
+// WorkspaceConfig is read from context/github.json inside each workspace.
+type WorkspaceConfig struct {
+	InstallationID int64    `json:"installation_id"`
+	DefaultRepo    string   `json:"default_repo"`
+	Repositories   []string `json:"repositories"`
+}
+
+type Issue struct {
+	Number   int
+	Title    string
+	State    string
+	URL      string
+	Author   string
+	Labels   []string
+	Comments int
+}
+
+type CreateIssueInput struct {
+	Repo   string
+	Title  string
+	Body   string
+	Labels []string
+}
+
+type Comment struct {
+	ID  int64
+	URL string
+}
+
+type CheckRun struct {
+	Name       string
+	Status     string
+	Conclusion string
+	URL        string
+}
+
+type CIStatus struct {
+	Repo      string
+	Ref       string
+	SHA       string
+	State     string
+	CheckRuns []CheckRun
+}
+
+type installationToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Client talks to the GitHub REST API as a GitHub App, minting installation
+// tokens per workspace installation on demand.
+type Client struct {
+	appID         int64
+	privateKey    *rsa.PrivateKey
+	apiBase       string
+	workspaceRoot string
+	configRelPath string
+	httpClient    *http.Client
+	now           func() time.Time
+
+	mu     sync.Mutex
+	tokens map[int64]installationToken
+}
+
+func New(cfg Config) (*Client, error) {
+	if cfg.AppID <= 0 {
+		return nil, fmt.Errorf("github app id is required")
+	}
+	privateKey, err := parsePrivateKey(cfg.PrivateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	apiBase := strings.TrimRight(strings.TrimSpace(cfg.APIBase), "/")
+	if apiBase == "" {
+		apiBase = DefaultAPIBase
+	}
+	relPath := strings.TrimSpace(cfg.WorkspaceConfigRelPath)
+	if relPath == "" {
+		relPath = DefaultWorkspaceConfigRelPath
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 20 * time.Second
+	}
+	return &Client{
+		appID:         cfg.AppID,
+		privateKey:    privateKey,
+		apiBase:       apiBase,
+		workspaceRoot: strings.TrimSpace(cfg.WorkspaceRoot),
+		configRelPath: relPath,
+		httpClient:    &http.Client{Timeout: timeout},
+		now:           time.Now,
+		tokens:        map[int64]installationToken{},
+	}, nil
+}
+
+// LoadPrivateKey reads a PEM private key from disk.
+func LoadPrivateKey(path string) ([]byte, error) {
+	content, err := os.ReadFile(strings.TrimSpace(path))
+	if err != nil {
+		return nil, fmt.Errorf("read github app private key: %w", err)
+	}
+	return content, nil
+}
+
+func (c *Client) ListIssues(ctx context.Context, workspaceID, repo, state string, limit int) ([]Issue, error) {
+	workspaceCfg, repo, err := c.resolve(workspaceID, repo)
+	if err != nil {
+		return nil, err
+	}
+	state = strings.ToLower(strings.TrimSpace(state))
+	if state == "" {
+		state = "open"
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	query := url.Values{"state": {state}, "per_page": {strconv.Itoa(limit)}}
+	var payload []struct {
+		Number      int    `json:"number"`
+		Title       string `json:"title"`
+		State       string `json:"state"`
+		HTMLURL     string `json:"html_url"`
+		Comments    int    `json:"comments"`
+		PullRequest *struct {
+		} `json:"pull_request"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := c.do(ctx, workspaceCfg.InstallationID, http.MethodGet, "/repos/"+repo+"/issues?"+query.Encode(), nil, &payload); err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, 0, len(payload))
+	for _, item := range payload {
+		// The issues endpoint also returns pull requests.
+		if item.PullRequest != nil {
+			continue
+		}
+		labels := make([]string, 0, len(item.Labels))
+		for _, label := range item.Labels {
+			labels = append(labels, label.Name)
+		}
+		issues = append(issues, Issue{
+			Number:   item.Number,
+			Title:    item.Title,
+			State:    item.State,
+			URL:      item.HTMLURL,
+			Author:   item.User.Login,
+			Labels:   labels,
+			Comments: item.Comments,
+		})
+	}
+	return issues, nil
+}
+
+func (c *Client) CreateIssue(ctx context.Context, workspaceID string, input CreateIssueInput) (Issue, error) {
+	workspaceCfg, repo, err := c.resolve(workspaceID, input.Repo)
+	if err != nil {
+		return Issue{}, err
+	}
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return Issue{}, fmt.Errorf("issue title is required")
+	}
+	request := map[string]any{"title": title, "body": strings.TrimSpace(input.Body)}
+	if len(input.Labels) > 0 {
+		request["labels"] = input.Labels
+	}
+	var payload struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		State   string `json:"state"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := c.do(ctx, workspaceCfg.InstallationID, http.MethodPost, "/repos/"+repo+"/issues", request, &payload); err != nil {
+		return Issue{}, err
+	}
+	return Issue{Number: payload.Number, Title: payload.Title, State: payload.State, URL: payload.HTMLURL, Labels: input.Labels}, nil
+}
+
+// CommentOnPullRequest adds a conversation comment; GitHub serves these via the issues API.
+func (c *Client) CommentOnPullRequest(ctx context.Context, workspaceID, repo string, number int, body string) (Comment, error) {
+	workspaceCfg, repo, err := c.resolve(workspaceID, repo)
+	if err != nil {
+		return Comment{}, err
+	}
+	if number <= 0 {
+		return Comment{}, fmt.Errorf("pull request number is required")
+	}
+	if strings.TrimSpace(body) == "" {
+		return Comment{}, fmt.Errorf("comment body is required")
+	}
+	var payload struct {
+		ID      int64  `json:"id"`
+		HTMLURL string `json:"html_url"`
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number)
+	if err := c.do(ctx, workspaceCfg.InstallationID, http.MethodPost, path, map[string]string{"body": body}, &payload); err != nil {
+		return Comment{}, err
+	}
+	return Comment{ID: payload.ID, URL: payload.HTMLURL}, nil
+}
+
+// CIStatus reports check runs and the combined commit status for a ref or,
+// when prNumber is set, for the pull request head.
+func (c *Client) CIStatus(ctx context.Context, workspaceID, repo, ref string, prNumber int) (CIStatus, error) {
+	workspaceCfg, repo, err := c.resolve(workspaceID, repo)
+	if err != nil {
+		return CIStatus{}, err
+	}
+	ref = strings.TrimSpace(ref)
+	if prNumber > 0 {
+		var pull struct {
+			Head struct {
+				SHA string `json:"sha"`
+			} `json:"head"`
+		}
+		if err := c.do(ctx, workspaceCfg.InstallationID, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", repo, prNumber), nil, &pull); err != nil {
+			return CIStatus{}, err
+		}
+		ref = pull.Head.SHA
+	}
+	if ref == "" {
+		return CIStatus{}, fmt.Errorf("ref or pull request number is required")
+	}
+	escapedRef := url.PathEscape(ref)
+	var combined struct {
+		State string `json:"state"`
+		SHA   string `json:"sha"`
+	}
+	if err := c.do(ctx, workspaceCfg.InstallationID, http.MethodGet, "/repos/"+repo+"/commits/"+escapedRef+"/status", nil, &combined); err != nil {
+		return CIStatus{}, err
+	}
+	var checks struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := c.do(ctx, workspaceCfg.InstallationID, http.MethodGet, "/repos/"+repo+"/commits/"+escapedRef+"/check-runs?per_page=50", nil, &checks); err != nil {
+		return CIStatus{}, err
+	}
+	status := CIStatus{Repo: repo, Ref: ref, SHA: combined.SHA, State: combined.State}
+	for _, run := range checks.CheckRuns {
+		status.CheckRuns = append(status.CheckRuns, CheckRun{Name: run.Name, Status: run.Status, Conclusion: run.Conclusion, URL: run.HTMLURL})
+	}
+	return status, nil
+}
+
+// WorkspaceConfig loads the per-workspace GitHub settings.
+func (c *Client) WorkspaceConfig(workspaceID string) (WorkspaceConfig, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if c.workspaceRoot == "" || workspaceID == "" {
+		return WorkspaceConfig{}, ErrNotConfigured
+	}
+	content, err := os.ReadFile(filepath.Join(c.workspaceRoot, workspaceID, filepath.FromSlash(c.configRelPath)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return WorkspaceConfig{}, ErrNotConfigured
+		}
+		return WorkspaceConfig{}, fmt.Errorf("read github workspace config: %w", err)
+	}
+	var cfg WorkspaceConfig
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return WorkspaceConfig{}, fmt.Errorf("invalid github workspace config: %w", err)
+	}
+	if cfg.InstallationID <= 0 {
+		return WorkspaceConfig{}, fmt.Errorf("%w: installation_id is missing", ErrNotConfigured)
+	}
+	return cfg, nil
+}
+
+func (c *Client) resolve(workspaceID, repo string) (WorkspaceConfig, string, error) {
+	cfg, err := c.WorkspaceConfig(workspaceID)
+	if err != nil {
+		return WorkspaceConfig{}, "", err
+	}
+	repo = strings.Trim(strings.TrimSpace(repo), "/")
+	if repo == "" {
+		repo = strings.Trim(strings.TrimSpace(cfg.DefaultRepo), "/")
+	}
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return WorkspaceConfig{}, "", fmt.Errorf("%w: %q", ErrInvalidRepository, repo)
+	}
+	if len(cfg.Repositories) > 0 {
+		allowed := false
+		for _, candidate := range cfg.Repositories {
+			if strings.EqualFold(strings.TrimSpace(candidate), repo) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return WorkspaceConfig{}, "", fmt.Errorf("%w: %s", ErrRepoNotAllowed, repo)
+		}
+	}
+	return cfg, url.PathEscape(parts[0]) + "/" + url.PathEscape(parts[1]), nil
+}
+
+func (c *Client) do(ctx context.Context, installationID int64, method, path string, body any, target any) error {
+	token, err := c.installationToken(ctx, installationID)
+	if err != nil {
+		return err
+	}
+	return c.request(ctx, "token "+token, method, path, body, target)
+}
+
+func (c *Client) request(ctx context.Context, authorization, method, path string, body any, target any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode github request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.apiBase+path, reader)
+	if err != nil {
+		return fmt.Errorf("build github request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("github request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("read github response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(content, &apiErr)
+		message := strings.TrimSpace(apiErr.Message)
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return fmt.Errorf("github api %s %s: %d %s", method, strings.SplitN(path, "?", 2)[0], resp.StatusCode, message)
+	}
+	if target == nil {
+		return nil
+	}
+	if err := json.Unmarshal(content, target); err != nil {
+		return fmt.Errorf("decode github response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) installationToken(ctx context.Context, installationID int64) (string, error) {
+	c.mu.Lock()
+	cached, ok := c.tokens[installationID]
+	c.mu.Unlock()
+	if ok && c.now().Add(time.Minute).Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+	jwt, err := c.appJWT()
+	if err != nil {
+		return "", err
+	}
+	var payload struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	path := fmt.Sprintf("/app/installations/%d/access_tokens", installationID)
+	if err := c.request(ctx, "Bearer "+jwt, http.MethodPost, path, nil, &payload); err != nil {
+		return "", err
+	}
+	if payload.Token == "" {
+		return "", fmt.Errorf("github returned an empty installation token")
+	}
+	c.mu.Lock()
+	c.tokens[installationID] = installationToken{value: payload.Token, expiresAt: payload.ExpiresAt}
+	c.mu.Unlock()
+	return payload.Token, nil
+}
+
+// appJWT signs the short-lived RS256 JWT GitHub requires for app endpoints.
+func (c *Client) appJWT() (string, error) {
+	now := c.now().UTC()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		// Backdated to tolerate clock drift, as GitHub recommends.
+		"iat": now.Add(-60 * time.Second).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(c.appID, 10),
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode github jwt claims: %w", err)
+	}
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign github jwt: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func parsePrivateKey(content []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("github app private key is not valid PEM")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse github app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("github app private key must be RSA")
+	}
+	return key, nil
+}
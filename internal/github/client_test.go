@@ -0,0 +1,164 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.Handler, workspaceConfig string) *Client {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	root := t.TempDir()
+	if workspaceConfig != "" {
+		path := filepath.Join(root, "ws-1", "context", "github.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(workspaceConfig), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+	client, err := New(Config{AppID: 99, PrivateKeyPEM: keyPEM, APIBase: server.URL, WorkspaceRoot: root})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client
+}
+
+func tokenHandler(tokenRequests *int32, next http.HandlerFunc) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(tokenRequests, 1)
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") || strings.Count(auth, ".") != 2 {
+			http.Error(w, `{"message":"bad jwt"}`, http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token":      "inst-token",
+			"expires_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+	})
+	mux.HandleFunc("/repos/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token inst-token" {
+			http.Error(w, `{"message":"bad token"}`, http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	})
+	return mux
+}
+
+func TestListIssuesSkipsPullRequestsAndCachesToken(t *testing.T) {
+	var tokenRequests int32
+	handler := tokenHandler(&tokenRequests, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/app/issues" || r.URL.Query().Get("state") != "open" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"number": 1, "title": "Crash on login", "state": "open", "html_url": "https://github.com/acme/app/issues/1", "user": {"login": "sam"}, "labels": [{"name": "bug"}]},
+			{"number": 2, "title": "Fix crash", "state": "open", "html_url": "https://github.com/acme/app/pull/2", "pull_request": {}}
+		]`))
+	})
+	client := newTestClient(t, handler, `{"installation_id": 7, "default_repo": "acme/app"}`)
+
+	for range 2 {
+		issues, err := client.ListIssues(context.Background(), "ws-1", "", "", 0)
+		if err != nil {
+			t.Fatalf("list issues: %v", err)
+		}
+		if len(issues) != 1 || issues[0].Number != 1 || issues[0].Author != "sam" || issues[0].Labels[0] != "bug" {
+			t.Fatalf("unexpected issues: %+v", issues)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected installation token to be cached, got %d requests", tokenRequests)
+	}
+}
+
+func TestCreateIssueSendsPayload(t *testing.T) {
+	var tokenRequests int32
+	var received map[string]any
+	handler := tokenHandler(&tokenRequests, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/app/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 12, "title": "Crash on login", "state": "open", "html_url": "https://github.com/acme/app/issues/12"}`))
+	})
+	client := newTestClient(t, handler, `{"installation_id": 7, "default_repo": "acme/app"}`)
+
+	issue, err := client.CreateIssue(context.Background(), "ws-1", CreateIssueInput{Title: "Crash on login", Body: "steps", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if issue.Number != 12 || received["title"] != "Crash on login" || received["body"] != "steps" {
+		t.Fatalf("unexpected issue %+v payload %+v", issue, received)
+	}
+}
+
+func TestCIStatusResolvesPullRequestHead(t *testing.T) {
+	var tokenRequests int32
+	handler := tokenHandler(&tokenRequests, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/app/pulls/5":
+			_, _ = w.Write([]byte(`{"head": {"sha": "abc123"}}`))
+		case "/repos/acme/app/commits/abc123/status":
+			_, _ = w.Write([]byte(`{"state": "failure", "sha": "abc123"}`))
+		case "/repos/acme/app/commits/abc123/check-runs":
+			_, _ = w.Write([]byte(`{"check_runs": [{"name": "test", "status": "completed", "conclusion": "failure"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	client := newTestClient(t, handler, `{"installation_id": 7, "default_repo": "acme/app"}`)
+
+	status, err := client.CIStatus(context.Background(), "ws-1", "", "", 5)
+	if err != nil {
+		t.Fatalf("ci status: %v", err)
+	}
+	if status.State != "failure" || status.SHA != "abc123" || len(status.CheckRuns) != 1 || status.CheckRuns[0].Conclusion != "failure" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestWorkspaceRepositoryRestrictions(t *testing.T) {
+	var tokenRequests int32
+	handler := tokenHandler(&tokenRequests, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL.Path)
+	})
+	client := newTestClient(t, handler, `{"installation_id": 7, "repositories": ["acme/app"]}`)
+
+	if _, err := client.ListIssues(context.Background(), "ws-1", "acme/other", "", 0); !errors.Is(err, ErrRepoNotAllowed) {
+		t.Fatalf("expected repo not allowed, got %v", err)
+	}
+	if _, err := client.ListIssues(context.Background(), "ws-1", "", "", 0); !errors.Is(err, ErrInvalidRepository) {
+		t.Fatalf("expected invalid repository without default, got %v", err)
+	}
+	if _, err := client.ListIssues(context.Background(), "ws-2", "acme/app", "", 0); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected not configured for unknown workspace, got %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type approvalDecisionRequest struct {
+	ID             string `json:"id"`
+	ApproverUserID string `json:"approver_user_id"`
+	Reason         string `json:"reason"`
+}
+
+// handleApprovals lists action approvals, pending ones by default. Pass
+// status=approved, denied or all for decided ones with their execution
+// results.
+func (r *router) handleApprovals(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	query := req.URL.Query()
+	input := store.ListActionApprovalsInput{
+		WorkspaceID: strings.TrimSpace(query.Get("workspace_id")),
+		Status:      strings.TrimSpace(query.Get("status")),
+		Limit:       50,
+	}
+	if limitInput := strings.TrimSpace(query.Get("limit")); limitInput != "" {
+		parsed, err := strconv.Atoi(limitInput)
+		if err != nil || parsed < 1 || parsed > 500 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be between 1 and 500"})
+			return
+		}
+		input.Limit = parsed
+	}
+	if !r.authorize(w, req, input.WorkspaceID, store.PermissionApproveActions) {
+		return
+	}
+	approvals, err := r.deps.Store.ListActionApprovals(req.Context(), input)
+	if err != nil {
+		writeApprovalError(w, err)
+		return
+	}
+	items := make([]map[string]any, 0, len(approvals))
+	for _, approval := range approvals {
+		items = append(items, actionApprovalResponse(approval))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "count": len(items)})
+}
+
+// handleApprovalDetail returns one approval with its payload, sign-offs and
+// execution result.
+func (r *router) handleApprovalDetail(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	id := strings.TrimSpace(req.URL.Query().Get("id"))
+	if id == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id query parameter is required"})
+		return
+	}
+	approval, ok := r.lookupApproval(w, req, id)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, actionApprovalResponse(approval))
+}
+
+// handleApprovalsApprove signs off on a pending action and, once enough
+// admins have, runs it and returns the execution result. An approval still
+// waiting for other admins returns 202.
+func (r *router) handleApprovalsApprove(w http.ResponseWriter, req *http.Request) {
+	payload, ok := r.approvalDecision(w, req)
+	if !ok {
+		return
+	}
+	approval, err := r.deps.Store.ApproveActionApproval(req.Context(), store.ApproveActionApprovalInput{
+		ID:             payload.ID,
+		ApproverUserID: payload.ApproverUserID,
+	})
+	if errors.Is(err, store.ErrActionApprovalQuorumPending) || errors.Is(err, store.ErrActionApprovalAlreadySigned) {
+		item := actionApprovalResponse(approval)
+		item["message"] = err.Error()
+		writeJSON(w, http.StatusAccepted, item)
+		return
+	}
+	if err != nil {
+		writeApprovalError(w, err)
+		return
+	}
+	approval, err = r.executeApproval(context.WithoutCancel(req.Context()), approval)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, actionApprovalResponse(approval))
+}
+
+// handleApprovalsDeny rejects a pending action.
+func (r *router) handleApprovalsDeny(w http.ResponseWriter, req *http.Request) {
+	payload, ok := r.approvalDecision(w, req)
+	if !ok {
+		return
+	}
+	approval, err := r.deps.Store.DenyActionApproval(req.Context(), store.DenyActionApprovalInput{
+		ID:             payload.ID,
+		ApproverUserID: payload.ApproverUserID,
+		Reason:         payload.Reason,
+	})
+	if err != nil {
+		writeApprovalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, actionApprovalResponse(approval))
+}
+
+// approvalDecision decodes an approve or deny request and checks that the
+// approver may decide the approval. The acting user, when set, is the
+// approver recorded; otherwise approver_user_id must name a known user whose
+// role grants approve_actions in the approval's workspace, so a quorum only
+// counts real approvers.
+func (r *router) approvalDecision(w http.ResponseWriter, req *http.Request) (approvalDecisionRequest, bool) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return approvalDecisionRequest{}, false
+	}
+	var payload approvalDecisionRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return approvalDecisionRequest{}, false
+	}
+	payload.ID = strings.TrimSpace(payload.ID)
+	if payload.ID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return approvalDecisionRequest{}, false
+	}
+	if userID := actingUser(req); userID != "" {
+		payload.ApproverUserID = userID
+	}
+	payload.ApproverUserID = strings.TrimSpace(payload.ApproverUserID)
+	if payload.ApproverUserID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "approver_user_id is required"})
+		return approvalDecisionRequest{}, false
+	}
+	approval, ok := r.lookupApproval(w, req, payload.ID)
+	if !ok {
+		return approvalDecisionRequest{}, false
+	}
+	if actingUser(req) == "" {
+		allowed, status, message := r.checkUserPermission(req, payload.ApproverUserID, approval.WorkspaceID, store.PermissionApproveActions)
+		if !allowed {
+			writeJSON(w, status, map[string]string{"error": "approver " + message})
+			return approvalDecisionRequest{}, false
+		}
+	}
+	return payload, true
+}
+
+func (r *router) lookupApproval(w http.ResponseWriter, req *http.Request, id string) (store.ActionApproval, bool) {
+	approval, err := r.deps.Store.LookupActionApproval(req.Context(), id)
+	if err != nil {
+		writeApprovalError(w, err)
+		return store.ActionApproval{}, false
+	}
+	if !r.authorize(w, req, approval.WorkspaceID, store.PermissionApproveActions) {
+		return store.ActionApproval{}, false
+	}
+	return approval, true
+}
+
+// executeApproval runs an approved action and records the outcome the same
+// way chat approvals do. It runs detached from the request so a client that
+// hangs up does not leave the action unrecorded.
+func (r *router) executeApproval(ctx context.Context, approval store.ActionApproval) (store.ActionApproval, error) {
+	if r.deps.ActionExecutor == nil {
+		return r.deps.Store.UpdateActionExecution(ctx, store.UpdateActionExecutionInput{
+			ID:               approval.ID,
+			ExecutionStatus:  "skipped",
+			ExecutionMessage: "approved but no action executor is configured",
+			ExecutedAt:       time.Now().UTC(),
+		})
+	}
+	result, err := r.deps.ActionExecutor.Execute(ctx, approval)
+	if err != nil {
+		return r.deps.Store.UpdateActionExecution(ctx, store.UpdateActionExecutionInput{
+			ID:               approval.ID,
+			ExecutionStatus:  "failed",
+			ExecutionMessage: err.Error(),
+			ExecutorPlugin:   result.Plugin,
+			ExecutedAt:       time.Now().UTC(),
+		})
+	}
+	return r.deps.Store.UpdateActionExecution(ctx, store.UpdateActionExecutionInput{
+		ID:               approval.ID,
+		ExecutionStatus:  "succeeded",
+		ExecutionMessage: result.Message,
+		ExecutorPlugin:   result.Plugin,
+		ExecutedAt:       time.Now().UTC(),
+	})
+}
+
+func writeApprovalError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, store.ErrActionApprovalNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, store.ErrActionApprovalNotReady):
+		status = http.StatusConflict
+	case errors.Is(err, store.ErrWorkspaceScope):
+		status = http.StatusForbidden
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func actionApprovalResponse(approval store.ActionApproval) map[string]any {
+	signoffs := make([]map[string]any, 0, len(approval.Signoffs))
+	for _, signoff := range approval.Signoffs {
+		signoffs = append(signoffs, map[string]any{
+			"user_id":          signoff.UserID,
+			"approved_at_unix": signoff.ApprovedAt.Unix(),
+		})
+	}
+	item := map[string]any{
+		"id":                 approval.ID,
+		"workspace_id":       approval.WorkspaceID,
+		"context_id":         approval.ContextID,
+		"connector":          approval.Connector,
+		"external_id":        approval.ExternalID,
+		"requester_user_id":  approval.RequesterUserID,
+		"action_type":        approval.ActionType,
+		"action_target":      approval.ActionTarget,
+		"action_summary":     approval.ActionSummary,
+		"payload":            approval.Payload,
+		"status":             approval.Status,
+		"approver_user_id":   approval.ApproverUserID,
+		"denied_reason":      approval.DeniedReason,
+		"required_approvals": approval.RequiredApprovals,
+		"signoffs":           signoffs,
+		"risk_level":         approval.RiskLevel,
+		"risk_reason":        approval.RiskReason,
+		"execution_status":   approval.ExecutionStatus,
+		"execution_message":  approval.ExecutionMessage,
+		"executor_plugin":    approval.ExecutorPlugin,
+		"created_at_unix":    approval.CreatedAt.Unix(),
+		"updated_at_unix":    approval.UpdatedAt.Unix(),
+	}
+	if !approval.ExecutedAt.IsZero() {
+		item["executed_at_unix"] = approval.ExecutedAt.Unix()
+	}
+	if !approval.ExpiresAt.IsZero() {
+		item["expires_at_unix"] = approval.ExpiresAt.Unix()
+	}
+	return item
+}
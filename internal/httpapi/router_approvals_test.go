@@ -0,0 +1,200 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+	"time"
+)
+
+type fixedRouterApprovalQuorum map[string]int
+
+func (f fixedRouterApprovalQuorum) RequiredApprovals(record store.ActionApproval) int {
+	return f[record.ActionType]
+}
+
+// pairRouterTestUser pairs a telegram account and returns the user id it creates.
+func pairRouterTestUser(t *testing.T, sqlStore *store.Store, connectorUserID, role string) string {
+	t.Helper()
+	ctx := context.Background()
+	pairing, err := sqlStore.CreatePairingRequest(ctx, store.CreatePairingRequestInput{
+		Connector:       "telegram",
+		ConnectorUserID: connectorUserID,
+		DisplayName:     "user-" + connectorUserID,
+		ExpiresAt:       time.Now().UTC().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create pairing: %v", err)
+	}
+	approved, err := sqlStore.ApprovePairing(ctx, store.ApprovePairingInput{Token: pairing.Token, ApproverUserID: "root", Role: role})
+	if err != nil {
+		t.Fatalf("approve pairing: %v", err)
+	}
+	return approved.UserID
+}
+
+type fakeActionExecutor struct {
+	executed []string
+}
+
+func (f *fakeActionExecutor) Execute(ctx context.Context, approval store.ActionApproval) (executor.Result, error) {
+	f.executed = append(f.executed, approval.ID)
+	return executor.Result{Plugin: "webhook", Message: "status 200"}, nil
+}
+
+func TestApprovalsListApproveAndDeny(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	actions := &fakeActionExecutor{}
+	handler := NewRouter(Dependencies{
+		Config:         config.Config{},
+		Store:          sqlStore,
+		Engine:         orchestrator.New(1, logger),
+		ActionExecutor: actions,
+		Logger:         logger,
+	})
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	ctx := context.Background()
+	admin := pairRouterTestUser(t, sqlStore, "7", "admin")
+	ids := []string{}
+	for i := 0; i < 2; i++ {
+		record, err := sqlStore.CreateActionApproval(ctx, store.CreateActionApprovalInput{
+			WorkspaceID:     "ws-1",
+			ContextID:       "ctx-1",
+			Connector:       "telegram",
+			ExternalID:      "42",
+			RequesterUserID: "user-1",
+			ActionType:      "webhook",
+			Payload:         map[string]any{"url": "https://hooks.example.com"},
+		})
+		if err != nil {
+			t.Fatalf("create action approval: %v", err)
+		}
+		ids = append(ids, record.ID)
+	}
+
+	res := do(http.MethodGet, "/api/v1/approvals?workspace_id=ws-1", "")
+	var listed struct {
+		Items []struct {
+			ID      string         `json:"id"`
+			Payload map[string]any `json:"payload"`
+		} `json:"items"`
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &listed); err != nil || listed.Count != 2 || listed.Items[0].Payload["url"] != "https://hooks.example.com" {
+		t.Fatalf("unexpected pending list %d: %s", res.Code, res.Body.String())
+	}
+
+	if res := do(http.MethodPost, "/api/v1/approvals/approve", `{"id":"`+ids[0]+`"}`); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing approver rejected, got %d: %s", res.Code, res.Body.String())
+	}
+	res = do(http.MethodPost, "/api/v1/approvals/approve", `{"id":"`+ids[0]+`","approver_user_id":"`+admin+`"}`)
+	var approved struct {
+		Status           string `json:"status"`
+		ExecutionStatus  string `json:"execution_status"`
+		ExecutionMessage string `json:"execution_message"`
+		ExecutorPlugin   string `json:"executor_plugin"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &approved); err != nil || res.Code != http.StatusOK {
+		t.Fatalf("expected approve ok, got %d: %s", res.Code, res.Body.String())
+	}
+	if approved.Status != "approved" || approved.ExecutionStatus != "succeeded" || approved.ExecutionMessage != "status 200" || approved.ExecutorPlugin != "webhook" {
+		t.Fatalf("unexpected approval result %+v", approved)
+	}
+	if len(actions.executed) != 1 || actions.executed[0] != ids[0] {
+		t.Fatalf("expected the approved action executed once, got %v", actions.executed)
+	}
+	if res := do(http.MethodPost, "/api/v1/approvals/approve", `{"id":"`+ids[0]+`","approver_user_id":"`+admin+`"}`); res.Code != http.StatusConflict {
+		t.Fatalf("expected second approval to conflict, got %d: %s", res.Code, res.Body.String())
+	}
+
+	res = do(http.MethodPost, "/api/v1/approvals/deny", `{"id":"`+ids[1]+`","approver_user_id":"`+admin+`","reason":"wrong target"}`)
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"denied_reason":"wrong target"`) {
+		t.Fatalf("expected deny ok, got %d: %s", res.Code, res.Body.String())
+	}
+	if len(actions.executed) != 1 {
+		t.Fatalf("denied action must not run, got %v", actions.executed)
+	}
+
+	res = do(http.MethodGet, "/api/v1/approvals/detail?id="+ids[0], "")
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"execution_status":"succeeded"`) {
+		t.Fatalf("expected execution result in detail, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := do(http.MethodGet, "/api/v1/approvals/detail?id=act_missing", ""); res.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown approval not found, got %d", res.Code)
+	}
+	res = do(http.MethodGet, "/api/v1/approvals?status=all", "")
+	if err := json.Unmarshal(res.Body.Bytes(), &listed); err != nil || listed.Count != 2 {
+		t.Fatalf("expected both decided approvals, got %s", res.Body.String())
+	}
+}
+
+func TestApprovalQuorumOnlyCountsKnownApprovers(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	sqlStore.SetActionApprovalQuorumPolicy(fixedRouterApprovalQuorum{"send_email": 2})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	actions := &fakeActionExecutor{}
+	handler := NewRouter(Dependencies{
+		Config:         config.Config{},
+		Store:          sqlStore,
+		Engine:         orchestrator.New(1, logger),
+		ActionExecutor: actions,
+		Logger:         logger,
+	})
+	do := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/approvals/approve", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	ctx := context.Background()
+	member := pairRouterTestUser(t, sqlStore, "8", "member")
+	first := pairRouterTestUser(t, sqlStore, "9", "admin")
+	second := pairRouterTestUser(t, sqlStore, "10", "admin")
+	record, err := sqlStore.CreateActionApproval(ctx, store.CreateActionApprovalInput{
+		WorkspaceID:     "ws-1",
+		ContextID:       "ctx-1",
+		Connector:       "telegram",
+		ExternalID:      "42",
+		RequesterUserID: "user-1",
+		ActionType:      "send_email",
+		Payload:         map[string]any{"to": "someone@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("create action approval: %v", err)
+	}
+
+	for _, approver := range []string{"made-up-1", "made-up-2", member} {
+		if res := do(`{"id":"` + record.ID + `","approver_user_id":"` + approver + `"}`); res.Code != http.StatusForbidden {
+			t.Fatalf("expected approver %s rejected, got %d: %s", approver, res.Code, res.Body.String())
+		}
+	}
+	if pending, err := sqlStore.LookupActionApproval(ctx, record.ID); err != nil || len(pending.Signoffs) != 0 {
+		t.Fatalf("rejected approvers must not sign off, got %+v (%v)", pending.Signoffs, err)
+	}
+
+	if res := do(`{"id":"` + record.ID + `","approver_user_id":"` + first + `"}`); res.Code != http.StatusAccepted {
+		t.Fatalf("expected first admin signoff pending, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := do(`{"id":"` + record.ID + `","approver_user_id":"` + second + `"}`); res.Code != http.StatusOK {
+		t.Fatalf("expected second admin to reach quorum, got %d: %s", res.Code, res.Body.String())
+	}
+	if len(actions.executed) != 1 {
+		t.Fatalf("expected the action executed once, got %v", actions.executed)
+	}
+}
@@ -0,0 +1,238 @@
+package httpapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// auditExportPageSize is how many events an export reads per query.
+const auditExportPageSize = 1000
+
+var auditCSVHeader = []string{
+	"id", "created_at", "workspace_id", "context_id", "connector", "external_id", "source_user_id",
+	"event_type", "stage", "tool_name", "tool_class", "blocked", "block_reason", "message",
+	"seq", "prev_hash", "hash",
+}
+
+// handleAudit lists agent audit events, newest first. Pass next_cursor back
+// as cursor to read the following page.
+func (r *router) handleAudit(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	input, ok := r.auditQuery(w, req)
+	if !ok {
+		return
+	}
+	input.Limit = 100
+	if limitInput := strings.TrimSpace(req.URL.Query().Get("limit")); limitInput != "" {
+		parsed, err := strconv.Atoi(limitInput)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be between 1 and 1000"})
+			return
+		}
+		input.Limit = parsed
+	}
+	events, err := r.deps.Store.ListAgentAuditEvents(req.Context(), input)
+	if err != nil {
+		writeJSON(w, auditErrorStatus(err), map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(events))
+	for _, event := range events {
+		items = append(items, auditEventResponse(event))
+	}
+	response := map[string]any{
+		"items": items,
+		"count": len(items),
+	}
+	if len(events) == input.Limit {
+		response["next_cursor"] = store.AgentAuditCursor(events[len(events)-1])
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleAuditExport streams every audit event matching the filters as NDJSON
+// (the default) or CSV.
+func (r *router) handleAuditExport(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(req.URL.Query().Get("format")))
+	if format == "" {
+		format = "ndjson"
+	}
+	if format != "ndjson" && format != "csv" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "format must be ndjson or csv"})
+		return
+	}
+	input, ok := r.auditQuery(w, req)
+	if !ok {
+		return
+	}
+	input.Limit = auditExportPageSize
+	// Read the first page before committing to a 200 so filter errors still
+	// get a JSON error response.
+	events, err := r.deps.Store.ListAgentAuditEvents(req.Context(), input)
+	if err != nil {
+		writeJSON(w, auditErrorStatus(err), map[string]string{"error": err.Error()})
+		return
+	}
+
+	var encode func(store.AgentAuditEvent) error
+	var csvWriter *csv.Writer
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		csvWriter = csv.NewWriter(w)
+		encode = func(event store.AgentAuditEvent) error { return csvWriter.Write(auditEventCSVRow(event)) }
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(w)
+		encode = func(event store.AgentAuditEvent) error { return encoder.Encode(auditEventResponse(event)) }
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=audit-%d.%s", time.Now().UTC().Unix(), format))
+	w.WriteHeader(http.StatusOK)
+	if csvWriter != nil {
+		defer csvWriter.Flush()
+		if err := csvWriter.Write(auditCSVHeader); err != nil {
+			return
+		}
+	}
+
+	for {
+		for _, event := range events {
+			if err := encode(event); err != nil {
+				return
+			}
+		}
+		if len(events) < input.Limit {
+			return
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		input.Cursor = store.AgentAuditCursor(events[len(events)-1])
+		events, err = r.deps.Store.ListAgentAuditEvents(req.Context(), input)
+		if err != nil {
+			// The status is already sent; a short export is all that is left.
+			if r.deps.Logger != nil {
+				r.deps.Logger.Error("audit export failed", "error", err)
+			}
+			return
+		}
+	}
+}
+
+// auditQuery reads the shared audit filters and checks read_audit. It
+// writes the error response itself and returns false when the request
+// cannot go on.
+func (r *router) auditQuery(w http.ResponseWriter, req *http.Request) (store.ListAgentAuditEventsInput, bool) {
+	query := req.URL.Query()
+	input := store.ListAgentAuditEventsInput{
+		WorkspaceID:  strings.TrimSpace(query.Get("workspace_id")),
+		ContextID:    strings.TrimSpace(query.Get("context_id")),
+		Connector:    strings.TrimSpace(query.Get("connector")),
+		ExternalID:   strings.TrimSpace(query.Get("external_id")),
+		SourceUserID: strings.TrimSpace(query.Get("user_id")),
+		EventType:    strings.TrimSpace(query.Get("event_type")),
+		ToolName:     strings.TrimSpace(query.Get("tool")),
+		Cursor:       strings.TrimSpace(query.Get("cursor")),
+	}
+	if raw := strings.ToLower(strings.TrimSpace(query.Get("blocked"))); raw == "true" || raw == "1" || raw == "yes" {
+		input.BlockedOnly = true
+	}
+	var err error
+	if input.Since, err = parseAuditTime(query.Get("since")); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "since " + err.Error()})
+		return input, false
+	}
+	if input.Until, err = parseAuditTime(query.Get("until")); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "until " + err.Error()})
+		return input, false
+	}
+	if allowed, status, message := r.checkPermission(req, input.WorkspaceID, store.PermissionReadAudit); !allowed {
+		writeJSON(w, status, map[string]string{"error": message})
+		return input, false
+	}
+	return input, true
+}
+
+// parseAuditTime accepts unix seconds or RFC 3339.
+func parseAuditTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be unix seconds or RFC 3339")
+	}
+	return parsed.UTC(), nil
+}
+
+func auditErrorStatus(err error) int {
+	if errors.Is(err, store.ErrAgentAuditCursorInvalid) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func auditEventResponse(event store.AgentAuditEvent) map[string]any {
+	return map[string]any{
+		"id":              event.ID,
+		"workspace_id":    event.WorkspaceID,
+		"context_id":      event.ContextID,
+		"connector":       event.Connector,
+		"external_id":     event.ExternalID,
+		"source_user_id":  event.SourceUserID,
+		"event_type":      event.EventType,
+		"stage":           event.Stage,
+		"tool_name":       event.ToolName,
+		"tool_class":      event.ToolClass,
+		"blocked":         event.Blocked,
+		"block_reason":    event.BlockReason,
+		"message":         event.Message,
+		"created_at_unix": event.CreatedAt.Unix(),
+		"seq":             event.Seq,
+		"prev_hash":       event.PrevHash,
+		"hash":            event.Hash,
+	}
+}
+
+func auditEventCSVRow(event store.AgentAuditEvent) []string {
+	return []string{
+		event.ID,
+		event.CreatedAt.UTC().Format(time.RFC3339),
+		event.WorkspaceID,
+		event.ContextID,
+		event.Connector,
+		event.ExternalID,
+		event.SourceUserID,
+		event.EventType,
+		event.Stage,
+		event.ToolName,
+		event.ToolClass,
+		strconv.FormatBool(event.Blocked),
+		event.BlockReason,
+		event.Message,
+		strconv.FormatInt(event.Seq, 10),
+		event.PrevHash,
+		event.Hash,
+	}
+}
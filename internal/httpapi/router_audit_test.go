@@ -0,0 +1,80 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestAuditListsPagesAndExports(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{Config: config.Config{}, Store: sqlStore, Logger: logger})
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	for _, tool := range []string{"curl", "write_file", "curl"} {
+		if _, err := sqlStore.CreateAgentAuditEvent(ctx, store.CreateAgentAuditEventInput{
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Connector:   "discord",
+			ExternalID:  "chan-1",
+			EventType:   "egress_blocked",
+			Stage:       "audit.egress_blocked",
+			ToolName:    tool,
+			Blocked:     true,
+			Message:     "target=http://10.0.0.1, internal",
+		}); err != nil {
+			t.Fatalf("create audit event: %v", err)
+		}
+	}
+
+	var page struct {
+		Items      []map[string]any `json:"items"`
+		NextCursor string           `json:"next_cursor"`
+	}
+	res := get("/api/v1/audit?workspace_id=ws-1&tool=curl&limit=1")
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &page); err != nil || len(page.Items) != 1 || page.NextCursor == "" {
+		t.Fatalf("expected one item and a cursor, got %s (%v)", res.Body.String(), err)
+	}
+	firstID, cursor := page.Items[0]["id"], page.NextCursor
+	page.Items = nil
+	res = get("/api/v1/audit?workspace_id=ws-1&tool=curl&limit=1&cursor=" + url.QueryEscape(cursor))
+	if err := json.Unmarshal(res.Body.Bytes(), &page); err != nil || len(page.Items) != 1 || page.Items[0]["id"] == firstID {
+		t.Fatalf("expected the second curl event, got %s (%v)", res.Body.String(), err)
+	}
+	if res := get("/api/v1/audit?cursor=bogus"); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad cursor refused, got %d", res.Code)
+	}
+
+	res = get("/api/v1/audit/export?workspace_id=ws-1")
+	if res.Code != http.StatusOK || res.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("expected an NDJSON export, got %d %q", res.Code, res.Header().Get("Content-Type"))
+	}
+	if lines := strings.Split(strings.TrimSpace(res.Body.String()), "\n"); len(lines) != 3 || !strings.Contains(lines[0], `"event_type":"egress_blocked"`) {
+		t.Fatalf("expected three NDJSON lines, got %q", res.Body.String())
+	}
+
+	res = get("/api/v1/audit/export?workspace_id=ws-1&format=csv&tool=write_file")
+	rows, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil || len(rows) != 2 || rows[0][0] != "id" || rows[1][9] != "write_file" || rows[1][13] != "target=http://10.0.0.1, internal" {
+		t.Fatalf("expected a header and one CSV row, got %v (%v)", rows, err)
+	}
+}
@@ -0,0 +1,155 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type botfileApplyRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+type botfileReconcileRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Fix         bool   `json:"fix"`
+}
+
+// handleBotfile reports the botfile last applied to a workspace and the
+// outcome of the latest attempt.
+func (r *router) handleBotfile(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	workspaceID := strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id query parameter is required"})
+		return
+	}
+	record, err := r.deps.Store.LookupWorkspaceBotfile(req.Context(), workspaceID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrBotfileNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, botfileToMap(record))
+}
+
+// handleBotfileApply applies a workspace's botfile now instead of waiting
+// for the file watcher. A botfile that fails validation is reported in the
+// response with status "invalid".
+func (r *router) handleBotfileApply(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if r.deps.Botfiles == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "botfiles are unavailable"})
+		return
+	}
+	var payload botfileApplyRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	workspaceID := strings.TrimSpace(payload.WorkspaceID)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id is required"})
+		return
+	}
+	if !r.authorize(w, req, workspaceID, store.PermissionSetPrompt) {
+		return
+	}
+	record, err := r.deps.Botfiles.Apply(req.Context(), workspaceID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if record.WorkspaceID == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "workspace has no botfile"})
+		return
+	}
+	writeJSON(w, http.StatusOK, botfileToMap(record))
+}
+
+// handleBotfileReconcile compares live workspace state with the applied
+// botfile and, with fix set, restores the declared state. Without a
+// workspace_id every workspace with a botfile is checked.
+func (r *router) handleBotfileReconcile(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if r.deps.Botfiles == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "botfiles are unavailable"})
+		return
+	}
+	var payload botfileReconcileRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	workspaceID := strings.TrimSpace(payload.WorkspaceID)
+	if workspaceID == "" {
+		if !r.authorizeAdmin(w, req, "reconcile every workspace") {
+			return
+		}
+	} else if !r.authorize(w, req, workspaceID, store.PermissionSetPrompt) {
+		return
+	}
+	reports, err := r.deps.Botfiles.Reconcile(req.Context(), workspaceID, payload.Fix)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrBotfileNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(reports))
+	drifted := 0
+	for _, report := range reports {
+		if report.Drift == nil {
+			report.Drift = []string{}
+		}
+		if len(report.Drift) > 0 && !report.Fixed {
+			drifted++
+		}
+		items = append(items, map[string]any{
+			"workspace_id":    report.WorkspaceID,
+			"drift":           report.Drift,
+			"fixed":           report.Fixed,
+			"checked_at_unix": unixOrNil(report.CheckedAt),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":   items,
+		"count":   len(items),
+		"drifted": drifted,
+	})
+}
+
+func botfileToMap(record store.WorkspaceBotfile) map[string]any {
+	if record.Drift == nil {
+		record.Drift = []string{}
+	}
+	return map[string]any{
+		"workspace_id":          record.WorkspaceID,
+		"version":               record.Version,
+		"checksum":              record.Checksum,
+		"status":                string(record.Status),
+		"error":                 record.Error,
+		"changes":               record.Changes,
+		"drift":                 record.Drift,
+		"applied_at_unix":       unixOrNil(record.AppliedAt),
+		"updated_at_unix":       unixOrNil(record.UpdatedAt),
+		"drift_checked_at_unix": unixOrNil(record.DriftCheckedAt),
+	}
+}
@@ -0,0 +1,133 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/botfile"
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type botfileApplierStub struct {
+	store *store.Store
+}
+
+func (s botfileApplierStub) Apply(ctx context.Context, workspaceID string) (store.WorkspaceBotfile, error) {
+	return s.store.SaveWorkspaceBotfile(ctx, store.WorkspaceBotfile{
+		WorkspaceID: workspaceID,
+		Status:      store.BotfileInvalid,
+		Error:       "invalid botfile: version is required",
+	})
+}
+
+func (s botfileApplierStub) Reconcile(ctx context.Context, workspaceID string, fix bool) ([]botfile.DriftReport, error) {
+	if workspaceID == "ws-missing" {
+		return nil, store.ErrBotfileNotFound
+	}
+	return []botfile.DriftReport{{
+		WorkspaceID: "ws-1",
+		Drift:       []string{"objectives: missing nightly-digest"},
+		Fixed:       fix,
+		CheckedAt:   time.Now(),
+	}}, nil
+}
+
+func TestBotfileStatusAndApply(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config:   config.Config{},
+		Store:    sqlStore,
+		Engine:   orchestrator.New(1, logger),
+		Botfiles: botfileApplierStub{store: sqlStore},
+		Logger:   logger,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/botfile?workspace_id=ws-1", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before any apply, got %d: %s", res.Code, res.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/botfile/apply", strings.NewReader(`{"workspace_id":"ws-1"}`))
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected apply to report the outcome, got %d: %s", res.Code, res.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/botfile?workspace_id=ws-1", nil)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Status  string   `json:"status"`
+		Error   string   `json:"error"`
+		Changes []string `json:"changes"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode botfile: %v", err)
+	}
+	if payload.Status != "invalid" || payload.Error == "" || payload.Changes == nil {
+		t.Fatalf("unexpected botfile status %+v", payload)
+	}
+}
+
+func TestBotfileReconcileReportsDrift(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config:   config.Config{},
+		Store:    sqlStore,
+		Engine:   orchestrator.New(1, logger),
+		Botfiles: botfileApplierStub{store: sqlStore},
+		Logger:   logger,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/botfile/reconcile", strings.NewReader(`{}`))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Items []struct {
+			WorkspaceID string   `json:"workspace_id"`
+			Drift       []string `json:"drift"`
+			Fixed       bool     `json:"fixed"`
+		} `json:"items"`
+		Drifted int `json:"drifted"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode reconcile: %v", err)
+	}
+	if len(payload.Items) != 1 || payload.Items[0].Fixed || payload.Drifted != 1 {
+		t.Fatalf("expected one unfixed drift report, got %+v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/botfile/reconcile", strings.NewReader(`{"workspace_id":"ws-1","fix":true}`))
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"drifted":0`) {
+		t.Fatalf("expected fixed drift to leave nothing outstanding, got %d: %s", res.Code, res.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/botfile/reconcile", strings.NewReader(`{"workspace_id":"ws-missing"}`))
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a workspace without a botfile, got %d", res.Code)
+	}
+}
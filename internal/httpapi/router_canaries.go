@@ -0,0 +1,171 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type canaryCreateRequest struct {
+	WorkspaceID string  `json:"workspace_id"`
+	Name        string  `json:"name"`
+	Kind        string  `json:"kind"`
+	Value       string  `json:"value"`
+	Percent     int     `json:"percent"`
+	MinSamples  int     `json:"min_samples"`
+	ErrorMargin float64 `json:"error_margin"`
+	BlockMargin float64 `json:"block_margin"`
+}
+
+type canaryFinishRequest struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// handleCanaries lists canary rollouts or starts a new one. Rollouts without
+// workspace_id apply to every workspace.
+func (r *router) handleCanaries(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		query := req.URL.Query()
+		limit := 100
+		if limitInput := strings.TrimSpace(query.Get("limit")); limitInput != "" {
+			parsed, err := strconv.Atoi(limitInput)
+			if err != nil || parsed < 1 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be a positive integer"})
+				return
+			}
+			limit = parsed
+		}
+		rollouts, err := r.deps.Store.ListCanaryRollouts(req.Context(), store.ListCanaryRolloutsInput{
+			WorkspaceID: strings.TrimSpace(query.Get("workspace_id")),
+			Status:      store.CanaryStatus(strings.TrimSpace(query.Get("status"))),
+			Limit:       limit,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		items := make([]map[string]any, 0, len(rollouts))
+		for _, rollout := range rollouts {
+			items = append(items, canaryRolloutResponse(rollout))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": items, "count": len(items)})
+	case http.MethodPost:
+		var payload canaryCreateRequest
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+			return
+		}
+		if !r.authorizeCanary(w, req, payload.WorkspaceID) {
+			return
+		}
+		rollout, err := r.deps.Store.CreateCanaryRollout(req.Context(), store.CreateCanaryRolloutInput{
+			WorkspaceID: payload.WorkspaceID,
+			Name:        payload.Name,
+			Kind:        store.CanaryKind(payload.Kind),
+			Value:       payload.Value,
+			Percent:     payload.Percent,
+			MinSamples:  payload.MinSamples,
+			ErrorMargin: payload.ErrorMargin,
+			BlockMargin: payload.BlockMargin,
+		})
+		if err != nil {
+			status := http.StatusBadRequest
+			switch {
+			case errors.Is(err, store.ErrCanaryConflict):
+				status = http.StatusConflict
+			case errors.Is(err, store.ErrWorkspaceScope):
+				status = http.StatusForbidden
+			}
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, canaryRolloutResponse(rollout))
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// handleCanariesFinish ends an active rollout by hand: promoted once the
+// change is adopted for everyone, stopped or rolled_back otherwise.
+func (r *router) handleCanariesFinish(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var payload canaryFinishRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	id := strings.TrimSpace(payload.ID)
+	if id == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return
+	}
+	if actingUser(req) != "" {
+		existing, err := r.deps.Store.LookupCanaryRollout(req.Context(), id)
+		if err == nil && !r.authorizeCanary(w, req, existing.WorkspaceID) {
+			return
+		}
+		if err != nil && !errors.Is(err, store.ErrCanaryNotFound) {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+	status := store.CanaryStatus(strings.ToLower(strings.TrimSpace(payload.Status)))
+	if status == "" {
+		status = store.CanaryStopped
+	}
+	rollout, err := r.deps.Store.FinishCanaryRollout(req.Context(), id, status, payload.Reason)
+	if err != nil {
+		code := http.StatusBadRequest
+		switch {
+		case errors.Is(err, store.ErrCanaryNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, store.ErrCanaryNotActive):
+			code = http.StatusConflict
+		}
+		writeJSON(w, code, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, canaryRolloutResponse(rollout))
+}
+
+// authorizeCanary checks set_prompt in the rollout's workspace; rollouts
+// covering every workspace need an admin.
+func (r *router) authorizeCanary(w http.ResponseWriter, req *http.Request, workspaceID string) bool {
+	if strings.TrimSpace(workspaceID) == "" {
+		return r.authorizeAdmin(w, req, "roll out canaries in every workspace")
+	}
+	return r.authorize(w, req, workspaceID, store.PermissionSetPrompt)
+}
+
+func canaryRolloutResponse(rollout store.CanaryRollout) map[string]any {
+	arm := func(counts store.CanaryArm) map[string]int {
+		return map[string]int{"turns": counts.Turns, "errors": counts.Errors, "blocks": counts.Blocks}
+	}
+	return map[string]any{
+		"id":              rollout.ID,
+		"workspace_id":    rollout.WorkspaceID,
+		"name":            rollout.Name,
+		"kind":            string(rollout.Kind),
+		"value":           rollout.Value,
+		"percent":         rollout.Percent,
+		"status":          string(rollout.Status),
+		"min_samples":     rollout.MinSamples,
+		"error_margin":    rollout.ErrorMargin,
+		"block_margin":    rollout.BlockMargin,
+		"canary":          arm(rollout.Canary),
+		"control":         arm(rollout.Control),
+		"reason":          rollout.Reason,
+		"created_at_unix": rollout.CreatedAt.Unix(),
+		"updated_at_unix": rollout.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+func TestCanariesCreateListAndFinish(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	res := serve(http.MethodPost, "/api/v1/canaries", `{"workspace_id":"ws-1","kind":"model","value":"gpt-candidate","percent":10}`)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", res.Code, res.Body.String())
+	}
+	var created struct {
+		ID         string `json:"id"`
+		Status     string `json:"status"`
+		MinSamples int    `json:"min_samples"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode rollout: %v", err)
+	}
+	if created.ID == "" || created.Status != "active" || created.MinSamples != 20 {
+		t.Fatalf("unexpected rollout %+v", created)
+	}
+	if res := serve(http.MethodPost, "/api/v1/canaries", `{"workspace_id":"ws-1","kind":"model","value":"other","percent":10}`); res.Code != http.StatusConflict {
+		t.Fatalf("expected conflict for a second model rollout, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := serve(http.MethodPost, "/api/v1/canaries", `{"kind":"model","value":"x","percent":150}`); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid percent to be rejected, got %d", res.Code)
+	}
+
+	res = serve(http.MethodGet, "/api/v1/canaries?workspace_id=ws-1&status=active", "")
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"count":1`) {
+		t.Fatalf("expected one active rollout, got %d: %s", res.Code, res.Body.String())
+	}
+
+	res = serve(http.MethodPost, "/api/v1/canaries/finish", `{"id":"`+created.ID+`","status":"promoted"}`)
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"status":"promoted"`) {
+		t.Fatalf("expected rollout to be promoted, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := serve(http.MethodPost, "/api/v1/canaries/finish", `{"id":"`+created.ID+`"}`); res.Code != http.StatusConflict {
+		t.Fatalf("expected finished rollout to conflict, got %d", res.Code)
+	}
+	if res := serve(http.MethodPost, "/api/v1/canaries/finish", `{"id":"canary_missing"}`); res.Code != http.StatusNotFound {
+		t.Fatalf("expected missing rollout to be not found, got %d", res.Code)
+	}
+}
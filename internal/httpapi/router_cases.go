@@ -0,0 +1,238 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type caseCreateRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	ContextID   string `json:"context_id"`
+	Kind        string `json:"kind"`
+	Title       string `json:"title"`
+	Summary     string `json:"summary"`
+	OwnerUserID string `json:"owner_user_id"`
+}
+
+type caseUpdateRequest struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	OwnerUserID string `json:"owner_user_id"`
+}
+
+type caseLinkRequest struct {
+	CaseID  string `json:"case_id"`
+	Kind    string `json:"kind"`
+	ItemID  string `json:"item_id"`
+	Excerpt string `json:"excerpt"`
+}
+
+// handleCases lists a workspace's cases. POST opens a case by hand;
+// incidents and moderation decisions open theirs automatically.
+func (r *router) handleCases(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		workspaceID := strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+		if workspaceID == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id query parameter is required"})
+			return
+		}
+		limit := 100
+		if limitInput := strings.TrimSpace(req.URL.Query().Get("limit")); limitInput != "" {
+			parsed, err := strconv.Atoi(limitInput)
+			if err != nil || parsed < 1 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be a positive integer"})
+				return
+			}
+			limit = parsed
+		}
+		cases, err := r.deps.Store.ListCases(req.Context(), store.ListCasesInput{
+			WorkspaceID: workspaceID,
+			Status:      req.URL.Query().Get("status"),
+			Limit:       limit,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		items := make([]map[string]any, 0, len(cases))
+		for _, record := range cases {
+			items = append(items, caseToMap(record))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"items": items,
+			"count": len(items),
+		})
+	case http.MethodPost:
+		var payload caseCreateRequest
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+			return
+		}
+		if !r.authorize(w, req, payload.WorkspaceID, store.PermissionRouteTasks) {
+			return
+		}
+		record, err := r.deps.Store.CreateCase(req.Context(), store.CreateCaseInput{
+			WorkspaceID: payload.WorkspaceID,
+			ContextID:   payload.ContextID,
+			Kind:        payload.Kind,
+			Title:       payload.Title,
+			Summary:     payload.Summary,
+			OwnerUserID: payload.OwnerUserID,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, caseToMap(record))
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// handleCaseDetail returns a case with its linked items.
+func (r *router) handleCaseDetail(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	id := strings.TrimSpace(req.URL.Query().Get("id"))
+	if id == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id query parameter is required"})
+		return
+	}
+	record, err := r.deps.Store.LookupCase(req.Context(), id)
+	if err != nil {
+		writeCaseError(w, err)
+		return
+	}
+	items, err := r.deps.Store.ListCaseItems(req.Context(), record.ID)
+	if err != nil {
+		writeCaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, caseDetailToMap(record, items))
+}
+
+func (r *router) handleCaseUpdate(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var payload caseUpdateRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	if strings.TrimSpace(payload.ID) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return
+	}
+	if !r.authorizeCase(w, req, payload.ID) {
+		return
+	}
+	record, err := r.deps.Store.UpdateCase(req.Context(), store.UpdateCaseInput{
+		ID:          payload.ID,
+		Status:      payload.Status,
+		OwnerUserID: payload.OwnerUserID,
+	})
+	if err != nil {
+		writeCaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, caseToMap(record))
+}
+
+// handleCaseLink attaches a task, action approval, audit event, moderation
+// case or chat excerpt to a case.
+func (r *router) handleCaseLink(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var payload caseLinkRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	if strings.TrimSpace(payload.CaseID) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "case_id is required"})
+		return
+	}
+	if !r.authorizeCase(w, req, payload.CaseID) {
+		return
+	}
+	item, err := r.deps.Store.AddCaseItem(req.Context(), store.AddCaseItemInput{
+		CaseID:  payload.CaseID,
+		Kind:    payload.Kind,
+		ItemID:  payload.ItemID,
+		Excerpt: payload.Excerpt,
+	})
+	if err != nil {
+		writeCaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, caseItemToMap(item))
+}
+
+// authorizeCase checks route_tasks in the workspace of a case.
+func (r *router) authorizeCase(w http.ResponseWriter, req *http.Request, caseID string) bool {
+	return r.authorizeRecord(w, req, store.PermissionRouteTasks, store.ErrCaseNotFound, func() (string, error) {
+		record, err := r.deps.Store.LookupCase(req.Context(), strings.TrimSpace(caseID))
+		return record.WorkspaceID, err
+	})
+}
+
+func writeCaseError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, store.ErrCaseNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, store.ErrCaseStatusInvalid), errors.Is(err, store.ErrCaseItemInvalid):
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func caseToMap(record store.Case) map[string]any {
+	result := map[string]any{
+		"id":              record.ID,
+		"workspace_id":    record.WorkspaceID,
+		"context_id":      record.ContextID,
+		"kind":            record.Kind,
+		"title":           record.Title,
+		"summary":         record.Summary,
+		"status":          record.Status,
+		"owner_user_id":   record.OwnerUserID,
+		"created_at_unix": record.CreatedAt.Unix(),
+		"updated_at_unix": record.UpdatedAt.Unix(),
+	}
+	if !record.ResolvedAt.IsZero() {
+		result["resolved_at_unix"] = record.ResolvedAt.Unix()
+	}
+	return result
+}
+
+func caseItemToMap(item store.CaseItem) map[string]any {
+	return map[string]any{
+		"kind":            item.Kind,
+		"item_id":         item.ItemID,
+		"excerpt":         item.Excerpt,
+		"created_at_unix": item.CreatedAt.Unix(),
+	}
+}
+
+func caseDetailToMap(record store.Case, items []store.CaseItem) map[string]any {
+	result := caseToMap(record)
+	linked := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		linked = append(linked, caseItemToMap(item))
+	}
+	result["items"] = linked
+	return result
+}
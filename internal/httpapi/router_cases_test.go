@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestCasesListDetailUpdateAndLink(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	record, err := sqlStore.CreateCase(ctx, store.CreateCaseInput{
+		WorkspaceID: "ws-1",
+		Kind:        store.CaseKindIncident,
+		Title:       "[INCIDENT] checkout is down",
+		Items:       []store.AddCaseItemInput{{Kind: store.CaseItemTask, ItemID: "task-1"}},
+	})
+	if err != nil {
+		t.Fatalf("create case: %v", err)
+	}
+
+	res := do(http.MethodPost, "/api/v1/cases/link", `{"case_id":"`+record.ID+`","kind":"action","item_id":"act_1"}`)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected link to succeed, got %d: %s", res.Code, res.Body.String())
+	}
+	res = do(http.MethodPost, "/api/v1/cases/link", `{"case_id":"`+record.ID+`","kind":"note","item_id":"x"}`)
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown item kind to be rejected, got %d", res.Code)
+	}
+	res = do(http.MethodPost, "/api/v1/cases/update", `{"id":"`+record.ID+`","status":"resolved","owner_user_id":"admin-1"}`)
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"resolved_at_unix"`) {
+		t.Fatalf("expected case to resolve, got %d: %s", res.Code, res.Body.String())
+	}
+
+	res = do(http.MethodGet, "/api/v1/cases/detail?id="+record.ID, "")
+	var detail struct {
+		Status      string `json:"status"`
+		OwnerUserID string `json:"owner_user_id"`
+		Items       []struct {
+			Kind   string `json:"kind"`
+			ItemID string `json:"item_id"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("decode detail: %v", err)
+	}
+	if detail.Status != "resolved" || detail.OwnerUserID != "admin-1" || len(detail.Items) != 2 || detail.Items[1].ItemID != "act_1" {
+		t.Fatalf("unexpected case detail %+v", detail)
+	}
+
+	res = do(http.MethodPost, "/api/v1/cases", `{"workspace_id":"ws-1","title":"Spam wave"}`)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("expected manual case to be created, got %d: %s", res.Code, res.Body.String())
+	}
+	res = do(http.MethodGet, "/api/v1/cases?workspace_id=ws-1&status=open", "")
+	var listed struct {
+		Items []struct {
+			Title string `json:"title"`
+			Kind  string `json:"kind"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(listed.Items) != 1 || listed.Items[0].Title != "Spam wave" || listed.Items[0].Kind != "manual" {
+		t.Fatalf("expected only the open manual case, got %+v", listed.Items)
+	}
+	if res := do(http.MethodGet, "/api/v1/cases/detail?id=case_missing", ""); res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown case, got %d", res.Code)
+	}
+}
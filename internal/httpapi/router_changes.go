@@ -0,0 +1,115 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+var (
+	// changeStreamPollInterval is how often a stream compares change
+	// versions; one cheap query per open stream replaces the list reloads
+	// a polling client would make.
+	changeStreamPollInterval = time.Second
+	// changeStreamHeartbeat keeps idle streams from being closed by proxies.
+	changeStreamHeartbeat = 15 * time.Second
+)
+
+// handleChangesStream streams a server-sent event each time the tasks,
+// approvals or objectives of a workspace change. Events carry the kind and
+// the new version only; clients reload the records they show.
+func (r *router) handleChangesStream(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming is not supported"})
+		return
+	}
+	workspaceID := strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+	ctx := req.Context()
+	initial, err := r.changeVersions(req, workspaceID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	seen := map[string]int64{}
+	for _, version := range initial {
+		seen[version.Kind+"/"+version.WorkspaceID] = version.Version
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	poll := time.NewTicker(changeStreamPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(changeStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-poll.C:
+			current, err := r.changeVersions(req, workspaceID)
+			if err != nil {
+				if ctx.Err() == nil && r.deps.Logger != nil {
+					r.deps.Logger.Warn("change stream poll failed", "error", err)
+				}
+				continue
+			}
+			wrote := false
+			for _, version := range current {
+				key := version.Kind + "/" + version.WorkspaceID
+				if seen[key] == version.Version {
+					continue
+				}
+				seen[key] = version.Version
+				payload, _ := json.Marshal(map[string]any{
+					"kind":         version.Kind,
+					"workspace_id": version.WorkspaceID,
+					"version":      version.Version,
+				})
+				if _, err := fmt.Fprintf(w, "event: change\ndata: %s\n\n", payload); err != nil {
+					return
+				}
+				wrote = true
+			}
+			if wrote {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// changeVersions lists the change versions of one workspace, or of every
+// workspace when workspaceID is empty.
+func (r *router) changeVersions(req *http.Request, workspaceID string) ([]store.ChangeVersion, error) {
+	versions, err := r.deps.Store.ChangeVersions(req.Context())
+	if err != nil || workspaceID == "" {
+		return versions, err
+	}
+	filtered := versions[:0]
+	for _, version := range versions {
+		if version.WorkspaceID == workspaceID {
+			filtered = append(filtered, version)
+		}
+	}
+	return filtered, nil
+}
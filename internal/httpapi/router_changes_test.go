@@ -0,0 +1,76 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestChangesStreamEmitsTaskChanges(t *testing.T) {
+	previous := changeStreamPollInterval
+	changeStreamPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { changeStreamPollInterval = previous })
+
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := httptest.NewServer(NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/changes/stream?workspace_id=ws-1", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected stream response %d %q", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	for _, workspaceID := range []string{"ws-2", "ws-1"} {
+		if err := sqlStore.CreateTask(context.Background(), store.CreateTaskInput{
+			ID:          "task-" + workspaceID,
+			WorkspaceID: workspaceID,
+			ContextID:   "ctx-1",
+			Kind:        "general",
+			Title:       "Review docs",
+			Prompt:      "Review docs",
+			Status:      "queued",
+		}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if data != `{"kind":"task","version":1,"workspace_id":"ws-1"}` {
+			t.Fatalf("unexpected change event %q", data)
+		}
+		return
+	}
+	t.Fatalf("stream ended without a change event: %v", scanner.Err())
+}
@@ -0,0 +1,88 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/memorylog"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// handleChatLogs lists the live chat logs of a workspace, most recently
+// written first. Reading them needs the read_audit permission since tool
+// entries trace what the agent did.
+func (r *router) handleChatLogs(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	workspaceID := strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id query parameter is required"})
+		return
+	}
+	if !r.authorize(w, req, workspaceID, store.PermissionReadAudit) {
+		return
+	}
+	chats, err := memorylog.ListChats(r.deps.Config.WorkspaceRoot, workspaceID)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(chats))
+	for _, chat := range chats {
+		items = append(items, map[string]any{
+			"connector":       chat.Connector,
+			"external_id":     chat.ExternalID,
+			"updated_at_unix": chat.UpdatedAt.Unix(),
+			"size_bytes":      chat.SizeBytes,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "count": len(items)})
+}
+
+// handleChatLogTail returns the newest entries of one chat log, oldest
+// first, including the agent's tool call entries. Clients poll it to follow
+// a chat live.
+func (r *router) handleChatLogTail(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	query := req.URL.Query()
+	workspaceID := strings.TrimSpace(query.Get("workspace_id"))
+	connector := strings.TrimSpace(query.Get("connector"))
+	externalID := strings.TrimSpace(query.Get("external_id"))
+	if workspaceID == "" || connector == "" || externalID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id, connector and external_id query parameters are required"})
+		return
+	}
+	limit := 100
+	if limitInput := strings.TrimSpace(query.Get("limit")); limitInput != "" {
+		parsed, err := strconv.Atoi(limitInput)
+		if err != nil || parsed < 1 || parsed > 500 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = parsed
+	}
+	if !r.authorize(w, req, workspaceID, store.PermissionReadAudit) {
+		return
+	}
+	records, err := memorylog.ReadEntries(r.deps.Config.WorkspaceRoot, workspaceID, connector, externalID, limit)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(records))
+	for _, record := range records {
+		items = append(items, map[string]any{
+			"timestamp_unix": record.Timestamp.Unix(),
+			"direction":      record.Direction,
+			"actor":          record.Actor,
+			"text":           record.Text,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "count": len(items)})
+}
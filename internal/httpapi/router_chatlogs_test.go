@@ -0,0 +1,91 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/memorylog"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+func TestChatLogsListAndTail(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	workspaceRoot := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{WorkspaceRoot: workspaceRoot},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	start := time.Unix(1700000000, 0).UTC()
+	for index, direction := range []string{"inbound", "tool", "outbound"} {
+		if err := memorylog.Append(memorylog.Entry{
+			WorkspaceRoot: workspaceRoot,
+			WorkspaceID:   "ws-1",
+			Connector:     "discord",
+			ExternalID:    "chan-1",
+			Direction:     direction,
+			Text:          direction + " text",
+			Timestamp:     start.Add(time.Duration(index) * time.Second),
+		}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	do := func(path string) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+		return res
+	}
+
+	res := do("/api/v1/chatlogs?workspace_id=ws-1")
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var chats struct {
+		Items []struct {
+			Connector  string `json:"connector"`
+			ExternalID string `json:"external_id"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &chats); err != nil {
+		t.Fatalf("decode chats: %v", err)
+	}
+	if len(chats.Items) != 1 || chats.Items[0].Connector != "discord" || chats.Items[0].ExternalID != "chan-1" {
+		t.Fatalf("unexpected chats %+v", chats.Items)
+	}
+
+	res = do("/api/v1/chatlogs/tail?workspace_id=ws-1&connector=discord&external_id=chan-1&limit=2")
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var tail struct {
+		Items []struct {
+			TimestampUnix int64  `json:"timestamp_unix"`
+			Direction     string `json:"direction"`
+			Text          string `json:"text"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &tail); err != nil {
+		t.Fatalf("decode tail: %v", err)
+	}
+	if len(tail.Items) != 2 || tail.Items[0].Direction != "tool" || tail.Items[1].Text != "outbound text" {
+		t.Fatalf("expected the 2 newest entries oldest first, got %+v", tail.Items)
+	}
+	if tail.Items[1].TimestampUnix != start.Add(2*time.Second).Unix() {
+		t.Fatalf("unexpected timestamp %d", tail.Items[1].TimestampUnix)
+	}
+
+	if res := do("/api/v1/chatlogs/tail?workspace_id=ws-1&connector=discord"); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing external_id rejected, got %d", res.Code)
+	}
+	if res := do("/api/v1/chatlogs/tail?workspace_id=..&connector=discord&external_id=chan-1"); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected escaping workspace rejected, got %d", res.Code)
+	}
+}
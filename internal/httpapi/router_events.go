@@ -0,0 +1,79 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/scheduler"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type eventRequest struct {
+	EventKey    string         `json:"event_key"`
+	WorkspaceID string         `json:"workspace_id"`
+	DedupeKey   string         `json:"dedupe_key"`
+	Data        map[string]any `json:"data"`
+}
+
+// handleEvents ingests a named event, such as deploy.finished from a CI
+// pipeline, and queues a run of every active objective listening for it.
+// Without a workspace the event reaches every workspace, which only admins
+// may do.
+func (r *router) handleEvents(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if r.deps.ObjectiveEvents == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "objective events unavailable"})
+		return
+	}
+	var payload eventRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	workspaceID := strings.TrimSpace(payload.WorkspaceID)
+	if workspaceID == "" {
+		if !r.authorizeAdmin(w, req, "fire events in every workspace") {
+			return
+		}
+	} else if !r.authorize(w, req, workspaceID, store.PermissionManageObjectives) {
+		return
+	}
+	fired, err := r.deps.ObjectiveEvents.HandleEvent(req.Context(), scheduler.Event{
+		Key:         payload.EventKey,
+		WorkspaceID: workspaceID,
+		DedupeKey:   payload.DedupeKey,
+		Data:        payload.Data,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, scheduler.ErrEventKeyInvalid) {
+			status = http.StatusBadRequest
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(fired))
+	for _, objective := range fired {
+		item := map[string]any{
+			"objective_id":   objective.ObjectiveID,
+			"workspace_id":   objective.WorkspaceID,
+			"title":          objective.Title,
+			"task_id":        objective.TaskID,
+			"already_queued": objective.AlreadyQueued,
+		}
+		if objective.Error != "" {
+			item["error"] = objective.Error
+		}
+		items = append(items, item)
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"event_key": strings.ToLower(strings.TrimSpace(payload.EventKey)),
+		"fired":     items,
+		"count":     len(items),
+	})
+}
@@ -0,0 +1,89 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestEventsFireMatchingObjectives(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	objective, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Post-deploy check",
+		Prompt:      "Smoke test the release",
+		TriggerType: store.ObjectiveTriggerEvent,
+		EventKey:    "deploy.finished",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := orchestrator.New(1, logger)
+	handler := NewRouter(Dependencies{
+		Config:          config.Config{},
+		Store:           sqlStore,
+		Engine:          engine,
+		ObjectiveEvents: scheduler.New(sqlStore, engine, time.Minute, logger),
+		Logger:          logger,
+	})
+	fire := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	type firedPayload struct {
+		Count int `json:"count"`
+		Fired []struct {
+			ObjectiveID   string `json:"objective_id"`
+			TaskID        string `json:"task_id"`
+			AlreadyQueued bool   `json:"already_queued"`
+		} `json:"fired"`
+	}
+
+	body := `{"event_key":"Deploy.Finished","workspace_id":"ws-1","dedupe_key":"build-42","data":{"version":"1.4.0"}}`
+	res := fire(body)
+	if res.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload firedPayload
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Count != 1 || payload.Fired[0].ObjectiveID != objective.ID || payload.Fired[0].TaskID == "" {
+		t.Fatalf("unexpected response %+v", payload)
+	}
+	task, err := sqlStore.LookupTask(ctx, payload.Fired[0].TaskID)
+	if err != nil || !strings.Contains(task.Prompt, `"version":"1.4.0"`) {
+		t.Fatalf("expected the event data in the task prompt, got %+v (%v)", task, err)
+	}
+
+	res = fire(body)
+	payload = firedPayload{}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if res.Code != http.StatusAccepted || payload.Count != 1 || !payload.Fired[0].AlreadyQueued {
+		t.Fatalf("expected a redelivery folded, got %d %s", res.Code, res.Body.String())
+	}
+	if res := fire(`{"event_key":"alert.critical","workspace_id":"ws-2"}`); res.Code != http.StatusAccepted || !strings.Contains(res.Body.String(), `"count":0`) {
+		t.Fatalf("expected no objectives fired, got %d %s", res.Code, res.Body.String())
+	}
+	if res := fire(`{"event_key":"bad key!"}`); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid key, got %d", res.Code)
+	}
+}
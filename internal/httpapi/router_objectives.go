@@ -2,11 +2,15 @@ package httpapi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/objectivetemplate"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -21,6 +25,16 @@ type objectiveRequest struct {
 	Timezone    string `json:"timezone"`
 	NextRunUnix int64  `json:"next_run_unix"`
 	Active      *bool  `json:"active"`
+	// ReportChangesOnly diffs each run against the previous one and only
+	// reports what changed.
+	ReportChangesOnly bool `json:"report_changes_only"`
+	// DigestPeriod is "daily" or "weekly" to collect successful runs into
+	// one digest message per context instead of posting each run.
+	DigestPeriod string `json:"digest_period"`
+	// Template and Params create the objective from a built-in template;
+	// explicit fields above still override the template output.
+	Template string            `json:"template"`
+	Params   map[string]string `json:"params"`
 }
 
 type objectiveUpdateRequest struct {
@@ -33,14 +47,25 @@ type objectiveUpdateRequest struct {
 	Timezone    *string `json:"timezone"`
 	NextRunUnix *int64  `json:"next_run_unix"`
 	Active      *bool   `json:"active"`
+	// ReportChangesOnly turns change detection on or off.
+	ReportChangesOnly *bool `json:"report_changes_only"`
+	// DigestPeriod sets the digest period; "" posts every run again.
+	DigestPeriod *string `json:"digest_period"`
+	Revision     int     `json:"revision"`
 }
 
 type objectiveActiveRequest struct {
-	ID     string `json:"id"`
-	Active bool   `json:"active"`
+	ID       string `json:"id"`
+	Active   bool   `json:"active"`
+	Revision int    `json:"revision"`
 }
 
 type objectiveDeleteRequest struct {
+	ID       string `json:"id"`
+	Revision int    `json:"revision"`
+}
+
+type objectiveRunRequest struct {
 	ID string `json:"id"`
 }
 
@@ -61,25 +86,46 @@ func (r *router) handleObjectivesCreate(w http.ResponseWriter, req *http.Request
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
 		return
 	}
+	if !r.authorize(w, req, payload.WorkspaceID, store.PermissionManageObjectives) {
+		return
+	}
+	if strings.TrimSpace(payload.Template) != "" {
+		templated, err := objectivetemplate.Build(payload.Template, payload.Params)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		payload.Title = firstNonBlank(payload.Title, templated.Title)
+		payload.Prompt = firstNonBlank(payload.Prompt, templated.Prompt)
+		payload.TriggerType = firstNonBlank(payload.TriggerType, string(templated.TriggerType))
+		payload.CronExpr = firstNonBlank(payload.CronExpr, templated.CronExpr)
+		payload.Timezone = firstNonBlank(payload.Timezone, templated.Timezone)
+	}
 	triggerType := store.ObjectiveTriggerType(strings.ToLower(strings.TrimSpace(payload.TriggerType)))
 	nextRun := time.Time{}
 	if payload.NextRunUnix > 0 {
 		nextRun = time.Unix(payload.NextRunUnix, 0).UTC()
 	}
 	objective, err := r.deps.Store.CreateObjective(req.Context(), store.CreateObjectiveInput{
-		WorkspaceID: strings.TrimSpace(payload.WorkspaceID),
-		ContextID:   strings.TrimSpace(payload.ContextID),
-		Title:       strings.TrimSpace(payload.Title),
-		Prompt:      strings.TrimSpace(payload.Prompt),
-		TriggerType: triggerType,
-		EventKey:    strings.TrimSpace(payload.EventKey),
-		CronExpr:    strings.TrimSpace(payload.CronExpr),
-		Timezone:    strings.TrimSpace(payload.Timezone),
-		NextRunAt:   nextRun,
-		Active:      payload.Active,
+		WorkspaceID:       strings.TrimSpace(payload.WorkspaceID),
+		ContextID:         strings.TrimSpace(payload.ContextID),
+		Title:             strings.TrimSpace(payload.Title),
+		Prompt:            strings.TrimSpace(payload.Prompt),
+		TriggerType:       triggerType,
+		EventKey:          strings.TrimSpace(payload.EventKey),
+		CronExpr:          strings.TrimSpace(payload.CronExpr),
+		Timezone:          strings.TrimSpace(payload.Timezone),
+		NextRunAt:         nextRun,
+		Active:            payload.Active,
+		ReportChangesOnly: payload.ReportChangesOnly,
+		DigestPeriod:      store.ObjectiveDigestPeriod(payload.DigestPeriod),
 	})
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		status := http.StatusBadRequest
+		if errors.Is(err, store.ErrQuotaExceeded) {
+			status = http.StatusTooManyRequests
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
 		return
 	}
 	writeJSON(w, http.StatusCreated, objectiveToMap(objective))
@@ -121,6 +167,45 @@ func (r *router) handleObjectivesList(w http.ResponseWriter, req *http.Request)
 	})
 }
 
+func (r *router) handleObjectiveTemplates(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	templates := objectivetemplate.List()
+	items := make([]map[string]any, 0, len(templates))
+	for _, template := range templates {
+		params := make([]map[string]any, 0, len(template.AllParams()))
+		for _, param := range template.AllParams() {
+			params = append(params, map[string]any{
+				"name":        param.Name,
+				"description": param.Description,
+				"default":     param.Default,
+				"required":    param.Required(),
+			})
+		}
+		items = append(items, map[string]any{
+			"name":         template.Name,
+			"description":  template.Description,
+			"trigger_type": template.TriggerType,
+			"params":       params,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items": items,
+		"count": len(items),
+	})
+}
+
+func firstNonBlank(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 func (r *router) handleObjectivesUpdate(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodPost {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
@@ -131,19 +216,28 @@ func (r *router) handleObjectivesUpdate(w http.ResponseWriter, req *http.Request
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
 		return
 	}
+	if !r.authorizeObjective(w, req, payload.ID, store.PermissionManageObjectives) {
+		return
+	}
 	input := store.UpdateObjectiveInput{
-		ID:       strings.TrimSpace(payload.ID),
-		Title:    payload.Title,
-		Prompt:   payload.Prompt,
-		EventKey: payload.EventKey,
-		CronExpr: payload.CronExpr,
-		Timezone: payload.Timezone,
-		Active:   payload.Active,
+		ID:                strings.TrimSpace(payload.ID),
+		Title:             payload.Title,
+		Prompt:            payload.Prompt,
+		EventKey:          payload.EventKey,
+		CronExpr:          payload.CronExpr,
+		Timezone:          payload.Timezone,
+		Active:            payload.Active,
+		ReportChangesOnly: payload.ReportChangesOnly,
+		ExpectedRevision:  payload.Revision,
 	}
 	if payload.TriggerType != nil {
 		normalized := store.ObjectiveTriggerType(strings.ToLower(strings.TrimSpace(*payload.TriggerType)))
 		input.TriggerType = &normalized
 	}
+	if payload.DigestPeriod != nil {
+		period := store.ObjectiveDigestPeriod(*payload.DigestPeriod)
+		input.DigestPeriod = &period
+	}
 	if payload.NextRunUnix != nil {
 		nextRun := time.Time{}
 		if *payload.NextRunUnix > 0 {
@@ -153,7 +247,7 @@ func (r *router) handleObjectivesUpdate(w http.ResponseWriter, req *http.Request
 	}
 	objective, err := r.deps.Store.UpdateObjective(req.Context(), input)
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		writeObjectiveMutationError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, objectiveToMap(objective))
@@ -169,9 +263,16 @@ func (r *router) handleObjectivesActive(w http.ResponseWriter, req *http.Request
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
 		return
 	}
-	objective, err := r.deps.Store.SetObjectiveActive(req.Context(), strings.TrimSpace(payload.ID), payload.Active)
+	if !r.authorizeObjective(w, req, payload.ID, store.PermissionManageObjectives) {
+		return
+	}
+	objective, err := r.deps.Store.UpdateObjective(req.Context(), store.UpdateObjectiveInput{
+		ID:               strings.TrimSpace(payload.ID),
+		Active:           &payload.Active,
+		ExpectedRevision: payload.Revision,
+	})
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		writeObjectiveMutationError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, objectiveToMap(objective))
@@ -187,16 +288,132 @@ func (r *router) handleObjectivesDelete(w http.ResponseWriter, req *http.Request
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
 		return
 	}
-	if err := r.deps.Store.DeleteObjective(req.Context(), strings.TrimSpace(payload.ID)); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	if !r.authorizeObjective(w, req, payload.ID, store.PermissionManageObjectives) {
+		return
+	}
+	if err := r.deps.Store.DeleteObjective(req.Context(), strings.TrimSpace(payload.ID), payload.Revision); err != nil {
+		writeObjectiveMutationError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":            strings.TrimSpace(payload.ID),
+		"deleted":       true,
+		"purge_at_unix": time.Now().UTC().Add(store.TrashRetention).Unix(),
+	})
+}
+
+func (r *router) handleObjectivesRun(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if r.deps.ObjectiveRunner == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "objective runner unavailable"})
+		return
+	}
+	var payload objectiveRunRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	if !r.authorizeObjective(w, req, payload.ID, store.PermissionManageObjectives) {
+		return
+	}
+	objectiveID := strings.TrimSpace(payload.ID)
+	if objectiveID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return
+	}
+	task, err := r.deps.ObjectiveRunner.RunNow(req.Context(), objectiveID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrObjectiveNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, scheduler.ErrObjectiveRunAlreadyQueued):
+			status = http.StatusConflict
+		case errors.Is(err, scheduler.ErrObjectivePromptEmpty):
+			status = http.StatusBadRequest
+		case errors.Is(err, orchestrator.ErrQueueFull), errors.Is(err, store.ErrQuotaExceeded):
+			status = http.StatusTooManyRequests
+		case errors.Is(err, scheduler.ErrModelUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"objective_id": objectiveID,
+		"task_id":      task.ID,
+		"status":       "queued",
+	})
+}
+
+// handleObjectivesRuns returns the run history of an objective, newest
+// first.
+func (r *router) handleObjectivesRuns(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	objectiveID := strings.TrimSpace(req.URL.Query().Get("id"))
+	if objectiveID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id query parameter is required"})
+		return
+	}
+	if _, err := r.deps.Store.LookupObjective(req.Context(), objectiveID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrObjectiveNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	limit := 20
+	if raw := strings.TrimSpace(req.URL.Query().Get("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	runs, err := r.deps.Store.ListObjectiveRuns(req.Context(), objectiveID, limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
+	items := make([]map[string]any, 0, len(runs))
+	for _, run := range runs {
+		items = append(items, map[string]any{
+			"id":               run.ID,
+			"objective_id":     run.ObjectiveID,
+			"workspace_id":     run.WorkspaceID,
+			"task_id":          run.TaskID,
+			"trigger":          run.Trigger,
+			"status":           string(run.Status),
+			"started_at_unix":  run.StartedAt.Unix(),
+			"finished_at_unix": unixOrNil(run.FinishedAt),
+			"duration_ms":      run.DurationMs,
+			"summary":          run.Summary,
+			"error":            run.Error,
+		})
+	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"id":      strings.TrimSpace(payload.ID),
-		"deleted": true,
+		"objective_id": objectiveID,
+		"items":        items,
+		"count":        len(items),
 	})
 }
 
+// writeObjectiveMutationError reports a stale revision as 409 so clients can
+// reload and retry; other update failures stay 400 as before.
+func writeObjectiveMutationError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, store.ErrRevisionConflict) {
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
 func objectiveToMap(item store.Objective) map[string]any {
 	avgRunDurationMs := int64(0)
 	if item.RunCount > 0 {
@@ -236,9 +453,12 @@ func objectiveToMap(item store.Objective) map[string]any {
 		"last_success_unix":     unixOrNil(item.LastSuccessAt),
 		"last_failure_unix":     unixOrNil(item.LastFailureAt),
 		"auto_paused_reason":    nullIfBlank(item.AutoPausedReason),
+		"report_changes_only":   item.ReportChangesOnly,
+		"digest_period":         nullIfBlank(string(item.DigestPeriod)),
 		"recent_errors":         objectiveRecentErrorsToMap(item.RecentErrors),
 		"next_runs_unix":        objectiveNextRunsUnix(item, 5),
 		"health_state":          healthState,
+		"revision":              item.Revision,
 	}
 }
 
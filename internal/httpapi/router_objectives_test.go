@@ -7,10 +7,13 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dwizi/agent-runtime/internal/config"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -68,3 +71,209 @@ func TestObjectivesListUsesNullUnixForUnsetRuns(t *testing.T) {
 		t.Fatal("expected run_count field in objective response")
 	}
 }
+
+func TestObjectivesRunQueuesTaskImmediately(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	objective, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Status page monitor",
+		Prompt:      "Check the status page",
+		TriggerType: store.ObjectiveTriggerSchedule,
+		CronExpr:    "0 9 * * *",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := orchestrator.New(1, logger)
+	handler := NewRouter(Dependencies{
+		Config:          config.Config{},
+		Store:           sqlStore,
+		Engine:          engine,
+		ObjectiveRunner: scheduler.New(sqlStore, engine, time.Minute, logger),
+		Logger:          logger,
+	})
+
+	run := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/objectives/run", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	res := run(`{"id":"` + objective.ID + `"}`)
+	if res.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	taskID, _ := payload["task_id"].(string)
+	task, err := sqlStore.LookupTask(ctx, taskID)
+	if err != nil || task.Kind != string(orchestrator.TaskKindObjective) || task.Prompt != "Check the status page" {
+		t.Fatalf("expected queued objective task, got %+v (%v)", task, err)
+	}
+	unchanged, err := sqlStore.LookupObjective(ctx, objective.ID)
+	if err != nil || !unchanged.NextRunAt.Equal(objective.NextRunAt) || unchanged.RunCount != 0 {
+		t.Fatalf("expected schedule untouched, got %+v (%v)", unchanged, err)
+	}
+
+	if res := run(`{"id":"missing"}`); res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown objective, got %d", res.Code)
+	}
+}
+
+func TestObjectivesCreateFromTemplate(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/objectives/templates", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"name":"release-watch"`) {
+		t.Fatalf("expected template listing, got %d: %s", res.Code, res.Body.String())
+	}
+
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/objectives", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	res = create(`{"workspace_id":"ws-1","context_id":"ctx-1","template":"weekly-digest","params":{"topic":"incidents"},"cron_expr":"0 8 * * 1"}`)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload["title"] != "Weekly digest: incidents" || payload["cron_expr"] != "0 8 * * 1" || payload["trigger_type"] != "schedule" {
+		t.Fatalf("unexpected templated objective %+v", payload)
+	}
+	if res := create(`{"workspace_id":"ws-1","template":"release-watch"}`); res.Code != http.StatusBadRequest || !strings.Contains(res.Body.String(), "requires repo") {
+		t.Fatalf("expected missing parameter error, got %d: %s", res.Code, res.Body.String())
+	}
+}
+
+func TestObjectivesUpdateRejectsStaleRevision(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	created, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Digest",
+		Prompt:      "Summarize",
+		TriggerType: store.ObjectiveTriggerEvent,
+		EventKey:    "markdown.updated",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	res := post("/api/v1/objectives/update", `{"id":"`+created.ID+`","title":"Morning digest","revision":1}`)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected update 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var updated struct {
+		Revision int `json:"revision"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("decode update: %v", err)
+	}
+	if updated.Revision != 2 {
+		t.Fatalf("expected revision 2 after update, got %d", updated.Revision)
+	}
+
+	for path, body := range map[string]string{
+		"/api/v1/objectives/update": `{"id":"` + created.ID + `","title":"Evening digest","revision":1}`,
+		"/api/v1/objectives/active": `{"id":"` + created.ID + `","active":false,"revision":1}`,
+		"/api/v1/objectives/delete": `{"id":"` + created.ID + `","revision":1}`,
+	} {
+		if res := post(path, body); res.Code != http.StatusConflict {
+			t.Fatalf("expected %s with stale revision to return 409, got %d: %s", path, res.Code, res.Body.String())
+		}
+	}
+	current, err := sqlStore.LookupObjective(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("lookup objective: %v", err)
+	}
+	if current.Title != "Morning digest" || !current.Active {
+		t.Fatalf("expected stale writes to leave objective untouched, got %+v", current)
+	}
+}
+
+func TestObjectivesRunsListsHistory(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	objective, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Nightly report",
+		Prompt:      "Write the report",
+		TriggerType: store.ObjectiveTriggerSchedule,
+		CronExpr:    "0 2 * * *",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	startedAt := time.Now().UTC().Add(-time.Hour)
+	if _, err := sqlStore.CreateObjectiveRun(ctx, store.CreateObjectiveRunInput{
+		ObjectiveID: objective.ID, WorkspaceID: "ws-1", TaskID: "task-1", Trigger: "schedule", StartedAt: startedAt,
+	}); err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	if _, err := sqlStore.FinishObjectiveRun(ctx, store.FinishObjectiveRunInput{
+		TaskID: "task-1", Status: store.ObjectiveRunFailed, FinishedAt: startedAt.Add(time.Minute), Error: "tool timed out",
+	}); err != nil {
+		t.Fatalf("finish run: %v", err)
+	}
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/v1/objectives/runs?id="+objective.ID, nil))
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Items) != 1 || payload.Items[0]["status"] != "failed" || payload.Items[0]["error"] != "tool timed out" || payload.Items[0]["duration_ms"] != float64(60000) {
+		t.Fatalf("unexpected history %+v", payload.Items)
+	}
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/v1/objectives/runs?id=missing", nil))
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown objective, got %d", res.Code)
+	}
+}
@@ -108,6 +108,9 @@ func (r *router) handlePairingsApprove(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	if !r.authorizeAdmin(w, req, "approve pairings") {
+		return
+	}
 	var payload approvePairingRequest
 	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
@@ -152,6 +155,9 @@ func (r *router) handlePairingsDeny(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if !r.authorizeAdmin(w, req, "deny pairings") {
+		return
+	}
 	var payload denyPairingRequest
 	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
@@ -0,0 +1,77 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type quotaUpdateRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	TasksPerDay    *int   `json:"tasks_per_day"`
+	Objectives     *int   `json:"objectives"`
+	ActionsPerDay  *int   `json:"actions_per_day"`
+	TokensPerMonth *int   `json:"tokens_per_month"`
+}
+
+// handleQuotas reports workspace quota usage. POST stores per-workspace
+// overrides; omitted limits fall back to the runtime defaults and zero means
+// unlimited.
+func (r *router) handleQuotas(w http.ResponseWriter, req *http.Request) {
+	if r.deps.Quotas == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "quotas are unavailable"})
+		return
+	}
+	var workspaceID string
+	switch req.Method {
+	case http.MethodGet:
+		workspaceID = strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+		if workspaceID == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id query parameter is required"})
+			return
+		}
+	case http.MethodPost:
+		var payload quotaUpdateRequest
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+			return
+		}
+		workspaceID = strings.TrimSpace(payload.WorkspaceID)
+		if !r.authorizeAdmin(w, req, "set quotas") {
+			return
+		}
+		if _, err := r.deps.Store.SetWorkspaceQuota(req.Context(), store.WorkspaceQuota{
+			WorkspaceID:    workspaceID,
+			TasksPerDay:    payload.TasksPerDay,
+			Objectives:     payload.Objectives,
+			ActionsPerDay:  payload.ActionsPerDay,
+			TokensPerMonth: payload.TokensPerMonth,
+		}); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	report, err := r.deps.Quotas.Report(req.Context(), workspaceID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(report))
+	for _, usage := range report {
+		items = append(items, map[string]any{
+			"resource": string(usage.Resource),
+			"period":   usage.Period,
+			"limit":    usage.Limit,
+			"used":     usage.Used,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"workspace_id": workspaceID,
+		"quotas":       items,
+	})
+}
@@ -0,0 +1,65 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/quota"
+)
+
+func TestQuotasOverrideAndObjectiveLimit(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	quotas := quota.New(sqlStore, quota.Limits{}, logger)
+	sqlStore.SetQuotaGuard(quotas)
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Quotas: quotas,
+		Logger: logger,
+	})
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	res := post("/api/v1/quotas", `{"workspace_id":"ws-1","objectives":1}`)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Quotas []struct {
+			Resource string `json:"resource"`
+			Limit    int    `json:"limit"`
+			Used     int    `json:"used"`
+		} `json:"quotas"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode quotas: %v", err)
+	}
+	if len(payload.Quotas) != 4 || payload.Quotas[1].Resource != "objectives" || payload.Quotas[1].Limit != 1 {
+		t.Fatalf("unexpected quota report %+v", payload.Quotas)
+	}
+
+	objective := `{"workspace_id":"ws-1","context_id":"ctx-1","title":"Digest","prompt":"Summarize","trigger_type":"event","event_key":"markdown.updated"}`
+	if res := post("/api/v1/objectives", objective); res.Code != http.StatusCreated {
+		t.Fatalf("expected first objective created, got %d: %s", res.Code, res.Body.String())
+	}
+	res = post("/api/v1/objectives", objective)
+	if res.Code != http.StatusTooManyRequests || !strings.Contains(res.Body.String(), "quota of 1 objectives") {
+		t.Fatalf("expected over-quota rejection, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := post("/api/v1/quotas", `{"workspace_id":"ws-1","tasks_per_day":-1}`); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected negative limit rejected, got %d", res.Code)
+	}
+}
@@ -0,0 +1,255 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// actingUserHeader names the runtime user a client acts for. With it,
+// privileged endpoints require the user's role to grant the matching
+// permission in the target workspace. Requests without it come from the
+// operator holding the API certificate and are not checked, unless
+// AGENT_RUNTIME_ADMIN_API_REQUIRE_ACTING_USER refuses them outright.
+const actingUserHeader = "X-Agent-Runtime-User"
+
+type roleRequest struct {
+	WorkspaceID string   `json:"workspace_id"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+type roleDeleteRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Role        string `json:"role"`
+}
+
+// authorize writes 403 and returns false when the acting user lacks
+// permission in workspaceID.
+func (r *router) authorize(w http.ResponseWriter, req *http.Request, workspaceID string, permission store.Permission) bool {
+	allowed, status, message := r.checkPermission(req, workspaceID, permission)
+	if !allowed {
+		writeJSON(w, status, map[string]string{"error": message})
+	}
+	return allowed
+}
+
+// authorizeRecord is authorize for the workspace lookup returns. Records
+// lookup reports as notFound pass so the handler reports them as not found.
+func (r *router) authorizeRecord(w http.ResponseWriter, req *http.Request, permission store.Permission, notFound error, lookup func() (string, error)) bool {
+	if actingUser(req) == "" {
+		return true
+	}
+	workspaceID, err := lookup()
+	if err != nil {
+		if errors.Is(err, notFound) {
+			return true
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return false
+	}
+	return r.authorize(w, req, workspaceID, permission)
+}
+
+// authorizeObjective is authorize for the workspace of an objective.
+func (r *router) authorizeObjective(w http.ResponseWriter, req *http.Request, objectiveID string, permission store.Permission) bool {
+	return r.authorizeRecord(w, req, permission, store.ErrObjectiveNotFound, func() (string, error) {
+		objective, err := r.deps.Store.LookupObjective(req.Context(), strings.TrimSpace(objectiveID))
+		return objective.WorkspaceID, err
+	})
+}
+
+// authorizeTask is authorize for the workspace of a task.
+func (r *router) authorizeTask(w http.ResponseWriter, req *http.Request, taskID string, permission store.Permission) bool {
+	return r.authorizeRecord(w, req, permission, store.ErrTaskNotFound, func() (string, error) {
+		task, err := r.deps.Store.LookupTask(req.Context(), strings.TrimSpace(taskID))
+		return task.WorkspaceID, err
+	})
+}
+
+// requireActingUser refuses API calls without an acting user when the
+// operator turned off unchecked access. Health probes, the heartbeat and
+// signed artifact links stay open.
+func (r *router) requireActingUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.deps.Config.AdminAPIRequireActingUser && actingUser(req) == "" &&
+			strings.HasPrefix(req.URL.Path, "/api/") && req.URL.Path != "/api/v1/heartbeat" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": actingUserHeader + " header is required"})
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (r *router) checkPermission(req *http.Request, workspaceID string, permission store.Permission) (bool, int, string) {
+	userID := actingUser(req)
+	if userID == "" {
+		return true, http.StatusOK, ""
+	}
+	return r.checkUserPermission(req, userID, workspaceID, permission)
+}
+
+// checkUserPermission reports whether userID is a known user whose role
+// grants permission in workspaceID.
+func (r *router) checkUserPermission(req *http.Request, userID, workspaceID string, permission store.Permission) (bool, int, string) {
+	user, err := r.deps.Store.LookupUser(req.Context(), userID)
+	if err != nil {
+		if errors.Is(err, store.ErrUserNotFound) {
+			return false, http.StatusForbidden, "unknown user"
+		}
+		return false, http.StatusInternalServerError, err.Error()
+	}
+	permissions, err := r.deps.Store.RolePermissions(req.Context(), strings.TrimSpace(workspaceID), user.Role)
+	if err != nil {
+		return false, http.StatusInternalServerError, err.Error()
+	}
+	if !store.HasPermission(permissions, permission) {
+		return false, http.StatusForbidden, permissionRequired(permission)
+	}
+	return true, http.StatusOK, ""
+}
+
+func permissionRequired(permission store.Permission) string {
+	return string(permission) + " permission required"
+}
+
+func actingUser(req *http.Request) string {
+	return strings.TrimSpace(req.Header.Get(actingUserHeader))
+}
+
+// handleRoles lists (GET) or sets (POST) the roles of a workspace. Changing
+// roles is left to the operator and to users whose own role is admin or
+// overlord.
+func (r *router) handleRoles(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		workspaceID := strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+		if workspaceID == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id query parameter is required"})
+			return
+		}
+		roles, err := r.deps.Store.ListWorkspaceRoles(req.Context(), workspaceID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		items := make([]map[string]any, 0, len(roles))
+		for _, role := range roles {
+			items = append(items, roleToMap(role))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"workspace_id": workspaceID,
+			"roles":        items,
+			"permissions":  permissionNames(store.AllPermissions),
+		})
+	case http.MethodPost:
+		if !r.authorizeRoleChange(w, req) {
+			return
+		}
+		var payload roleRequest
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+			return
+		}
+		permissions, err := store.ParsePermissions(strings.Join(payload.Permissions, ","))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		role, err := r.deps.Store.SetWorkspaceRole(req.Context(), store.WorkspaceRole{
+			WorkspaceID: payload.WorkspaceID,
+			Role:        payload.Role,
+			Permissions: permissions,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, roleToMap(role))
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// handleRolesDelete drops a stored role so it falls back to the defaults.
+func (r *router) handleRolesDelete(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if !r.authorizeRoleChange(w, req) {
+		return
+	}
+	var payload roleDeleteRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	removed, err := r.deps.Store.DeleteWorkspaceRole(req.Context(), payload.WorkspaceID, payload.Role)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if !removed {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "role not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"workspace_id": strings.TrimSpace(payload.WorkspaceID),
+		"role":         strings.ToLower(strings.TrimSpace(payload.Role)),
+		"deleted":      true,
+	})
+}
+
+func (r *router) authorizeRoleChange(w http.ResponseWriter, req *http.Request) bool {
+	return r.authorizeAdmin(w, req, "change roles")
+}
+
+// authorizeAdmin lets the operator and users whose role is admin or overlord
+// through; action completes the error message for everyone else.
+func (r *router) authorizeAdmin(w http.ResponseWriter, req *http.Request, action string) bool {
+	userID := actingUser(req)
+	if userID == "" {
+		return true
+	}
+	user, err := r.deps.Store.LookupUser(req.Context(), userID)
+	if err != nil {
+		if errors.Is(err, store.ErrUserNotFound) {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "unknown user"})
+			return false
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(user.Role)) {
+	case "admin", "overlord":
+		return true
+	}
+	writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin role required to " + action})
+	return false
+}
+
+func roleToMap(role store.WorkspaceRole) map[string]any {
+	item := map[string]any{
+		"workspace_id": role.WorkspaceID,
+		"role":         role.Role,
+		"permissions":  permissionNames(role.Permissions),
+		"default":      !role.Stored,
+	}
+	if role.Stored {
+		item["updated_at"] = role.UpdatedAt.Format(time.RFC3339)
+	}
+	return item
+}
+
+func permissionNames(permissions []store.Permission) []string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, string(permission))
+	}
+	return names
+}
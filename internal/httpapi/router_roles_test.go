@@ -0,0 +1,270 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/quota"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestRolesGateObjectivesForActingUser(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{Config: config.Config{}, Store: sqlStore, Logger: logger})
+	ctx := context.Background()
+
+	pairing, err := sqlStore.CreatePairingRequest(ctx, store.CreatePairingRequestInput{
+		Connector:       "telegram",
+		ConnectorUserID: "42",
+		DisplayName:     "Mia",
+		ExpiresAt:       time.Now().UTC().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create pairing: %v", err)
+	}
+	approved, err := sqlStore.ApprovePairing(ctx, store.ApprovePairingInput{Token: pairing.Token, ApproverUserID: "root", Role: "member"})
+	if err != nil {
+		t.Fatalf("approve pairing: %v", err)
+	}
+
+	send := func(method, path, body, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if user != "" {
+			req.Header.Set(actingUserHeader, user)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	objective := func(workspaceID string) string {
+		return `{"workspace_id":"` + workspaceID + `","context_id":"ctx-1","title":"Digest","prompt":"Summarize","trigger_type":"event","event_key":"markdown.updated"}`
+	}
+
+	if res := send(http.MethodPost, "/api/v1/objectives", objective("ws-1"), approved.UserID); res.Code != http.StatusForbidden || !strings.Contains(res.Body.String(), "manage_objectives") {
+		t.Fatalf("expected member to be refused, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := send(http.MethodPost, "/api/v1/roles", `{"workspace_id":"ws-1","role":"member","permissions":["manage_objectives"]}`, approved.UserID); res.Code != http.StatusForbidden {
+		t.Fatalf("expected member unable to change roles, got %d", res.Code)
+	}
+	if res := send(http.MethodPost, "/api/v1/roles", `{"workspace_id":"ws-1","role":"member","permissions":["manage_objectives"]}`, ""); res.Code != http.StatusOK {
+		t.Fatalf("expected operator to set role, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := send(http.MethodPost, "/api/v1/objectives", objective("ws-1"), approved.UserID); res.Code != http.StatusCreated {
+		t.Fatalf("expected member to create objective in ws-1, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := send(http.MethodPost, "/api/v1/objectives", objective("ws-2"), approved.UserID); res.Code != http.StatusForbidden {
+		t.Fatalf("expected member refused in ws-2, got %d", res.Code)
+	}
+	if res := send(http.MethodGet, "/api/v1/search?q=digest&kind=audit&workspace_id=ws-1", "", approved.UserID); res.Code != http.StatusForbidden || !strings.Contains(res.Body.String(), "read_audit") {
+		t.Fatalf("expected audit search refused, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := send(http.MethodGet, "/api/v1/search?q=digest&workspace_id=ws-1", "", approved.UserID); res.Code != http.StatusOK {
+		t.Fatalf("expected non-audit search allowed, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := send(http.MethodPost, "/api/v1/objectives", objective("ws-1"), "nobody"); res.Code != http.StatusForbidden {
+		t.Fatalf("expected unknown user refused, got %d", res.Code)
+	}
+
+	res := send(http.MethodGet, "/api/v1/roles?workspace_id=ws-1", "", "")
+	var listed struct {
+		Roles []struct {
+			Role        string   `json:"role"`
+			Permissions []string `json:"permissions"`
+			Default     bool     `json:"default"`
+		} `json:"roles"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode roles: %v", err)
+	}
+	if len(listed.Roles) != 3 || listed.Roles[1].Role != "member" || listed.Roles[1].Default || len(listed.Roles[0].Permissions) != len(store.AllPermissions) {
+		t.Fatalf("unexpected roles: %+v", listed.Roles)
+	}
+
+	if res := send(http.MethodPost, "/api/v1/roles/delete", `{"workspace_id":"ws-1","role":"member"}`, ""); res.Code != http.StatusOK {
+		t.Fatalf("expected role deleted, got %d", res.Code)
+	}
+	if res := send(http.MethodPost, "/api/v1/objectives", objective("ws-1"), approved.UserID); res.Code != http.StatusForbidden {
+		t.Fatalf("expected member refused after role removal, got %d", res.Code)
+	}
+}
+
+func TestRolesGateTaskEndpointsForActingUser(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{Config: config.Config{}, Store: sqlStore, Engine: orchestrator.New(1, logger), Logger: logger})
+	ctx := context.Background()
+
+	pairing, err := sqlStore.CreatePairingRequest(ctx, store.CreatePairingRequestInput{
+		Connector:       "telegram",
+		ConnectorUserID: "42",
+		DisplayName:     "Mia",
+		ExpiresAt:       time.Now().UTC().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create pairing: %v", err)
+	}
+	member, err := sqlStore.ApprovePairing(ctx, store.ApprovePairingInput{Token: pairing.Token, ApproverUserID: "root", Role: "member"})
+	if err != nil {
+		t.Fatalf("approve pairing: %v", err)
+	}
+	for _, id := range []string{"task-queued", "task-failed"} {
+		if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+			ID: id, WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general", Title: "Task", Prompt: "do thing", Status: "queued",
+		}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-failed", 1, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	if err := sqlStore.MarkTaskFailed(ctx, "task-failed", time.Now().UTC(), "boom"); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+
+	send := func(path, body, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if user != "" {
+			req.Header.Set(actingUserHeader, user)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	for name, request := range map[string][2]string{
+		"create": {"/api/v1/tasks", `{"workspace_id":"ws-1","context_id":"ctx-1","title":"Task","prompt":"do thing"}`},
+		"retry":  {"/api/v1/tasks/retry", `{"task_id":"task-failed"}`},
+		"cancel": {"/api/v1/tasks/cancel", `{"task_id":"task-queued"}`},
+		"delete": {"/api/v1/tasks/delete", `{"task_id":"task-failed"}`},
+	} {
+		if res := send(request[0], request[1], member.UserID); res.Code != http.StatusForbidden || !strings.Contains(res.Body.String(), "route_tasks") {
+			t.Fatalf("expected member refused to %s, got %d: %s", name, res.Code, res.Body.String())
+		}
+	}
+	if task, err := sqlStore.LookupTask(ctx, "task-queued"); err != nil || task.Status != "queued" {
+		t.Fatalf("expected refused cancel to leave the task queued, got %+v, %v", task, err)
+	}
+	if res := send("/api/v1/tasks/cancel", `{"task_id":"task-queued"}`, ""); res.Code != http.StatusOK {
+		t.Fatalf("expected operator to cancel, got %d: %s", res.Code, res.Body.String())
+	}
+}
+
+func TestRolesGateWorkspaceMutationsForActingUser(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Quotas: quota.New(sqlStore, quota.Limits{}, logger),
+		Logger: logger,
+	})
+	ctx := context.Background()
+
+	pairing, err := sqlStore.CreatePairingRequest(ctx, store.CreatePairingRequestInput{
+		Connector:       "telegram",
+		ConnectorUserID: "42",
+		DisplayName:     "Mia",
+		ExpiresAt:       time.Now().UTC().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create pairing: %v", err)
+	}
+	member, err := sqlStore.ApprovePairing(ctx, store.ApprovePairingInput{Token: pairing.Token, ApproverUserID: "root", Role: "member"})
+	if err != nil {
+		t.Fatalf("approve pairing: %v", err)
+	}
+	for _, id := range []string{"task-planned", "task-trashed"} {
+		if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+			ID: id, WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general", Title: "Task", Prompt: "do thing", Status: "queued",
+		}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-trashed", 1, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	if err := sqlStore.MarkTaskFailed(ctx, "task-trashed", time.Now().UTC(), "boom"); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	if err := sqlStore.DeleteTask(ctx, "task-trashed", 0); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	incident, err := sqlStore.CreateCase(ctx, store.CreateCaseInput{WorkspaceID: "ws-1", Title: "Spam wave"})
+	if err != nil {
+		t.Fatalf("create case: %v", err)
+	}
+
+	send := func(path, body, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if user != "" {
+			req.Header.Set(actingUserHeader, user)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	for name, request := range map[string][2]string{
+		"plan":          {"/api/v1/tasks/plan", `{"id":"task-planned","steps":["one"]}`},
+		"restore":       {"/api/v1/trash/restore", `{"kind":"task","id":"task-trashed"}`},
+		"open case":     {"/api/v1/cases", `{"workspace_id":"ws-1","title":"Another"}`},
+		"update case":   {"/api/v1/cases/update", `{"id":"` + incident.ID + `","status":"resolved"}`},
+		"link case":     {"/api/v1/cases/link", `{"case_id":"` + incident.ID + `","kind":"task","item_id":"task-planned"}`},
+		"canary":        {"/api/v1/canaries", `{"workspace_id":"ws-1","name":"terse","kind":"prompt","value":"Be terse.","percent":10}`},
+		"global canary": {"/api/v1/canaries", `{"name":"terse","kind":"prompt","value":"Be terse.","percent":10}`},
+		"quota":         {"/api/v1/quotas", `{"workspace_id":"ws-1","tasks_per_day":1000}`},
+		"workers":       {"/api/v1/workers", `{"workers":4}`},
+		"pairing":       {"/api/v1/pairings/approve", `{"token":"nope","approver_user_id":"x","role":"admin"}`},
+	} {
+		if res := send(request[0], request[1], member.UserID); res.Code != http.StatusForbidden {
+			t.Fatalf("expected member refused to %s, got %d: %s", name, res.Code, res.Body.String())
+		}
+	}
+	if trash, err := sqlStore.ListTrash(ctx, store.ListTrashInput{WorkspaceID: "ws-1"}); err != nil || len(trash) != 1 {
+		t.Fatalf("expected refused restore to leave the task in the trash, got %+v, %v", trash, err)
+	}
+	if res := send("/api/v1/trash/restore", `{"kind":"task","id":"task-trashed"}`, ""); res.Code != http.StatusOK {
+		t.Fatalf("expected operator to restore, got %d: %s", res.Code, res.Body.String())
+	}
+}
+
+func TestAPIRequiresActingUserWhenConfigured(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{AdminAPIRequireActingUser: true},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	get := func(path, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if user != "" {
+			req.Header.Set(actingUserHeader, user)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	if res := get("/api/v1/tasks?workspace_id=ws-1", ""); res.Code != http.StatusUnauthorized || !strings.Contains(res.Body.String(), actingUserHeader) {
+		t.Fatalf("expected operator calls refused, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := get("/api/v1/heartbeat", ""); res.Code == http.StatusUnauthorized {
+		t.Fatal("expected the heartbeat to stay open")
+	}
+	if res := get("/api/v1/tasks?workspace_id=ws-1", "user-1"); res.Code != http.StatusOK {
+		t.Fatalf("expected calls with an acting user through, got %d: %s", res.Code, res.Body.String())
+	}
+}
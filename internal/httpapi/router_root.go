@@ -6,11 +6,16 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/dwizi/agent-runtime/internal/actions/executor"
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
+	"github.com/dwizi/agent-runtime/internal/botfile"
 	"github.com/dwizi/agent-runtime/internal/config"
 	"github.com/dwizi/agent-runtime/internal/gateway"
 	"github.com/dwizi/agent-runtime/internal/heartbeat"
 	"github.com/dwizi/agent-runtime/internal/mcp"
 	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/quota"
+	"github.com/dwizi/agent-runtime/internal/scheduler"
 	"github.com/dwizi/agent-runtime/internal/store"
 )
 
@@ -22,15 +27,53 @@ type MCPStatusProvider interface {
 	Summary() mcp.Summary
 }
 
+// ObjectiveRunner queues an immediate, out-of-schedule objective run.
+type ObjectiveRunner interface {
+	RunNow(ctx context.Context, objectiveID string) (orchestrator.Task, error)
+}
+
+// ObjectiveEventHandler runs the event-triggered objectives listening for
+// a named event.
+type ObjectiveEventHandler interface {
+	HandleEvent(ctx context.Context, event scheduler.Event) ([]scheduler.FiredObjective, error)
+}
+
+// QuotaReporter reports the effective limits and current usage of the
+// workspace quotas.
+type QuotaReporter interface {
+	Report(ctx context.Context, workspaceID string) ([]quota.Usage, error)
+}
+
+// BotfileApplier re-reads and applies a workspace's botfile.yaml and
+// reconciles drift between the applied botfile and the live workspace. An
+// empty workspace id reconciles every workspace with a botfile.
+type BotfileApplier interface {
+	Apply(ctx context.Context, workspaceID string) (store.WorkspaceBotfile, error)
+	Reconcile(ctx context.Context, workspaceID string, fix bool) ([]botfile.DriftReport, error)
+}
+
+// ActionExecutor runs an approved action through the executor plugins.
+type ActionExecutor interface {
+	Execute(ctx context.Context, approval store.ActionApproval) (executor.Result, error)
+}
+
 type Dependencies struct {
 	Config              config.Config
 	Store               *store.Store
 	Engine              *orchestrator.Engine
 	Gateway             MessageGateway
 	MCPStatusProvider   MCPStatusProvider
+	ObjectiveRunner     ObjectiveRunner
+	ObjectiveEvents     ObjectiveEventHandler
+	Quotas              QuotaReporter
+	Botfiles            BotfileApplier
+	ActionExecutor      ActionExecutor
 	Logger              *slog.Logger
 	Heartbeat           *heartbeat.Registry
 	HeartbeatStaleAfter time.Duration
+	// ArtifactLinks verifies the signed artifact links served on
+	// artifactlink.DownloadPath; nil serves none.
+	ArtifactLinks *artifactlink.Signer
 }
 
 type router struct {
@@ -47,13 +90,52 @@ func NewRouter(deps Dependencies) http.Handler {
 	mux.HandleFunc("/api/v1/chat", rt.handleChat)
 	mux.HandleFunc("/api/v1/tasks", rt.handleTasks)
 	mux.HandleFunc("/api/v1/tasks/retry", rt.handleTaskRetry)
+	mux.HandleFunc("/api/v1/tasks/cancel", rt.handleTaskCancel)
+	mux.HandleFunc("/api/v1/tasks/plan", rt.handleTaskPlan)
+	mux.HandleFunc("/api/v1/tasks/delete", rt.handleTaskDelete)
+	mux.HandleFunc("/api/v1/tasks/result", rt.handleTaskResult)
+	mux.HandleFunc("/api/v1/tasks/artifacts", rt.handleTaskArtifacts)
+	mux.HandleFunc("/api/v1/tasks/artifacts/download", rt.handleTaskArtifactDownload)
+	mux.HandleFunc(artifactlink.DownloadPath, rt.handleSignedArtifactDownload)
 	mux.HandleFunc("/api/v1/pairings/start", rt.handlePairingsStart)
 	mux.HandleFunc("/api/v1/pairings/lookup", rt.handlePairingsLookup)
 	mux.HandleFunc("/api/v1/pairings/approve", rt.handlePairingsApprove)
 	mux.HandleFunc("/api/v1/pairings/deny", rt.handlePairingsDeny)
 	mux.HandleFunc("/api/v1/objectives", rt.handleObjectives)
+	mux.HandleFunc("/api/v1/objectives/templates", rt.handleObjectiveTemplates)
 	mux.HandleFunc("/api/v1/objectives/update", rt.handleObjectivesUpdate)
 	mux.HandleFunc("/api/v1/objectives/active", rt.handleObjectivesActive)
 	mux.HandleFunc("/api/v1/objectives/delete", rt.handleObjectivesDelete)
-	return mux
+	mux.HandleFunc("/api/v1/objectives/run", rt.handleObjectivesRun)
+	mux.HandleFunc("/api/v1/objectives/runs", rt.handleObjectivesRuns)
+	mux.HandleFunc("/api/v1/events", rt.handleEvents)
+	mux.HandleFunc("/api/v1/quotas", rt.handleQuotas)
+	mux.HandleFunc("/api/v1/roles", rt.handleRoles)
+	mux.HandleFunc("/api/v1/roles/delete", rt.handleRolesDelete)
+	mux.HandleFunc("/api/v1/workers", rt.handleWorkers)
+	mux.HandleFunc("/api/v1/trash", rt.handleTrash)
+	mux.HandleFunc("/api/v1/trash/restore", rt.handleTrashRestore)
+	mux.HandleFunc("/api/v1/cases", rt.handleCases)
+	mux.HandleFunc("/api/v1/cases/detail", rt.handleCaseDetail)
+	mux.HandleFunc("/api/v1/cases/update", rt.handleCaseUpdate)
+	mux.HandleFunc("/api/v1/cases/link", rt.handleCaseLink)
+	mux.HandleFunc("/api/v1/search", rt.handleSearch)
+	mux.HandleFunc("/api/v1/audit", rt.handleAudit)
+	mux.HandleFunc("/api/v1/audit/export", rt.handleAuditExport)
+	mux.HandleFunc("/api/v1/botfile", rt.handleBotfile)
+	mux.HandleFunc("/api/v1/botfile/apply", rt.handleBotfileApply)
+	mux.HandleFunc("/api/v1/botfile/reconcile", rt.handleBotfileReconcile)
+	mux.HandleFunc("/api/v1/canaries", rt.handleCanaries)
+	mux.HandleFunc("/api/v1/canaries/finish", rt.handleCanariesFinish)
+	mux.HandleFunc("/api/v1/approvals", rt.handleApprovals)
+	mux.HandleFunc("/api/v1/approvals/detail", rt.handleApprovalDetail)
+	mux.HandleFunc("/api/v1/approvals/approve", rt.handleApprovalsApprove)
+	mux.HandleFunc("/api/v1/approvals/deny", rt.handleApprovalsDeny)
+	mux.HandleFunc("/api/v1/webhooks", rt.handleWebhooks)
+	mux.HandleFunc("/api/v1/webhooks/delete", rt.handleWebhooksDelete)
+	mux.HandleFunc("/api/v1/chatlogs", rt.handleChatLogs)
+	mux.HandleFunc("/api/v1/chatlogs/tail", rt.handleChatLogTail)
+	mux.HandleFunc("/api/v1/workspaces", rt.handleWorkspaces)
+	mux.HandleFunc("/api/v1/changes/stream", rt.handleChangesStream)
+	return rt.requireActingUser(mux)
 }
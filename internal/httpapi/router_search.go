@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// handleSearch runs a full-text query over tasks, objectives, action
+// approvals and audit events. workspace_id is optional so operators can
+// search every workspace at once.
+func (r *router) handleSearch(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	query := strings.TrimSpace(req.URL.Query().Get("q"))
+	if query == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "q query parameter is required"})
+		return
+	}
+	kinds, ok := parseSearchKinds(req.URL.Query().Get("kind"))
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "kind must be task, objective, approval or audit"})
+		return
+	}
+	workspaceID := strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+	if allowed, status, message := r.checkPermission(req, workspaceID, store.PermissionReadAudit); !allowed {
+		if message != permissionRequired(store.PermissionReadAudit) {
+			writeJSON(w, status, map[string]string{"error": message})
+			return
+		}
+		// Users without read_audit search everything but audit events.
+		if len(kinds) == 0 {
+			kinds = []store.SearchKind{store.SearchKindTask, store.SearchKindObjective, store.SearchKindApproval}
+		}
+		for _, kind := range kinds {
+			if kind == store.SearchKindAudit {
+				writeJSON(w, status, map[string]string{"error": message})
+				return
+			}
+		}
+	}
+	limit := 20
+	if limitInput := strings.TrimSpace(req.URL.Query().Get("limit")); limitInput != "" {
+		parsed, err := strconv.Atoi(limitInput)
+		if err != nil || parsed < 1 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+	results, err := r.deps.Store.Search(req.Context(), store.SearchInput{
+		WorkspaceID: workspaceID,
+		Query:       query,
+		Kinds:       kinds,
+		Limit:       limit,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(results))
+	for _, result := range results {
+		item := map[string]any{
+			"kind":         string(result.Kind),
+			"id":           result.ID,
+			"workspace_id": result.WorkspaceID,
+			"title":        result.Title,
+			"snippet":      result.Snippet,
+			"status":       result.Status,
+		}
+		if result.Risk != "" {
+			item["risk"] = result.Risk
+		}
+		if !result.UpdatedAt.IsZero() {
+			item["updated_at_unix"] = result.UpdatedAt.Unix()
+		}
+		items = append(items, item)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items": items,
+		"count": len(items),
+	})
+}
+
+// parseSearchKinds reads a comma-separated kind filter; empty means all.
+func parseSearchKinds(input string) ([]store.SearchKind, bool) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, true
+	}
+	known := map[store.SearchKind]bool{}
+	for _, kind := range store.SearchKinds() {
+		known[kind] = true
+	}
+	kinds := []store.SearchKind{}
+	for _, part := range strings.Split(input, ",") {
+		kind := store.SearchKind(strings.ToLower(strings.TrimSpace(part)))
+		if kind == "" {
+			continue
+		}
+		if !known[kind] {
+			return nil, false
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, true
+}
@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestSearchReturnsMatchingRecords(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+		ID:          "task-webhooks",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Wire up webhooks",
+		Prompt:      "register the endpoint",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Webhook health",
+		Prompt:      "check deliveries",
+		TriggerType: store.ObjectiveTriggerEvent,
+		EventKey:    "markdown.updated",
+	}); err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+
+	res := get("/api/v1/search?q=webhook&workspace_id=ws-1&kind=task")
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected search 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Items []struct {
+			Kind    string `json:"kind"`
+			ID      string `json:"id"`
+			Snippet string `json:"snippet"`
+			Status  string `json:"status"`
+		} `json:"items"`
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode search: %v", err)
+	}
+	if payload.Count != 1 || payload.Items[0].ID != "task-webhooks" || payload.Items[0].Status != "queued" {
+		t.Fatalf("unexpected search payload %+v", payload)
+	}
+
+	if res := get("/api/v1/search?workspace_id=ws-1"); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing query 400, got %d", res.Code)
+	}
+	if res := get("/api/v1/search?q=webhook&kind=pairing"); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown kind 400, got %d", res.Code)
+	}
+}
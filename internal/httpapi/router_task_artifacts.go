@@ -0,0 +1,136 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// handleTaskArtifacts lists the files a task produced.
+func (r *router) handleTaskArtifacts(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	taskID := strings.TrimSpace(req.URL.Query().Get("task_id"))
+	if taskID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "task_id query parameter is required"})
+		return
+	}
+	if _, err := r.deps.Store.LookupTask(req.Context(), taskID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrTaskNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	artifacts, err := r.deps.Store.ListTaskArtifacts(req.Context(), taskID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		item := map[string]any{
+			"id":              artifact.ID,
+			"task_id":         artifact.TaskID,
+			"workspace_id":    artifact.WorkspaceID,
+			"path":            artifact.Path,
+			"kind":            artifact.Kind,
+			"title":           artifact.Title,
+			"media_type":      artifact.MediaType,
+			"size_bytes":      artifact.SizeBytes,
+			"created_at_unix": artifact.CreatedAt.Unix(),
+		}
+		if link := r.deps.ArtifactLinks.Link(artifact.ID); link != "" {
+			item["download_url"] = link
+		}
+		items = append(items, item)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"task_id": taskID, "artifacts": items})
+}
+
+// handleTaskArtifactDownload serves an artifact file to admin clients.
+func (r *router) handleTaskArtifactDownload(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	artifactID := strings.TrimSpace(req.URL.Query().Get("id"))
+	if artifactID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id query parameter is required"})
+		return
+	}
+	r.serveTaskArtifact(w, req, artifactID)
+}
+
+// handleSignedArtifactDownload serves an artifact file to anyone holding an
+// unexpired signed link, such as the people a completion notice went to.
+func (r *router) handleSignedArtifactDownload(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if r.deps.ArtifactLinks == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "artifact links are disabled"})
+		return
+	}
+	query := req.URL.Query()
+	artifactID := query.Get("id")
+	if err := r.deps.ArtifactLinks.Verify(artifactID, query.Get("expires"), query.Get("sig")); err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, artifactlink.ErrLinkExpired) {
+			status = http.StatusGone
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	r.serveTaskArtifact(w, req, strings.TrimSpace(artifactID))
+}
+
+func (r *router) serveTaskArtifact(w http.ResponseWriter, req *http.Request, artifactID string) {
+	artifact, err := r.deps.Store.LookupTaskArtifact(req.Context(), artifactID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrTaskArtifactNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	workspaceDir := filepath.Join(r.deps.Config.WorkspaceRoot, artifact.WorkspaceID)
+	absolutePath := filepath.Join(workspaceDir, filepath.FromSlash(artifact.Path))
+	if relative, err := filepath.Rel(workspaceDir, absolutePath); err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "artifact path is outside the workspace"})
+		return
+	}
+	file, err := os.Open(absolutePath)
+	if errors.Is(err, os.ErrNotExist) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "artifact file not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "artifact file not found"})
+		return
+	}
+	if artifact.MediaType != "" {
+		w.Header().Set("Content-Type", artifact.MediaType)
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(artifact.Path)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, req, path.Base(artifact.Path), info.ModTime(), file)
+}
@@ -0,0 +1,107 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/artifactlink"
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestTaskArtifactsListAndDownload(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	workspaceRoot := t.TempDir()
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+		ID: "task-done", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general",
+		Title: "Vendor research", Prompt: "compare vendors", Status: "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	reportPath := filepath.Join(workspaceRoot, "ws-1", "scratch", "vendors.csv")
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(reportPath, []byte("vendor,price\nacme,10\n"), 0o644); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	artifacts, err := sqlStore.ReplaceTaskArtifacts(ctx, "task-done", "ws-1", []store.TaskArtifactInput{
+		{Path: "scratch/vendors.csv", Kind: "file", Title: "vendors.csv", MediaType: "text/csv; charset=utf-8", SizeBytes: 21},
+		{Path: "scratch/gone.txt", Kind: "file", Title: "gone.txt"},
+	})
+	if err != nil {
+		t.Fatalf("record artifacts: %v", err)
+	}
+	signer := artifactlink.New("https://bot.example.com", "secret", time.Hour)
+	handler := NewRouter(Dependencies{
+		Config:        config.Config{WorkspaceRoot: workspaceRoot},
+		Store:         sqlStore,
+		Engine:        orchestrator.New(1, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ArtifactLinks: signer,
+	})
+	do := func(path string) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+		return res
+	}
+
+	res := do("/api/v1/tasks/artifacts?task_id=task-done")
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Artifacts []struct {
+			ID          string `json:"id"`
+			Path        string `json:"path"`
+			SizeBytes   int64  `json:"size_bytes"`
+			DownloadURL string `json:"download_url"`
+		} `json:"artifacts"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Artifacts) != 2 || payload.Artifacts[0].Path != "scratch/vendors.csv" || payload.Artifacts[0].SizeBytes != 21 ||
+		!strings.HasPrefix(payload.Artifacts[0].DownloadURL, "https://bot.example.com/artifacts/download?") {
+		t.Fatalf("unexpected listing %+v", payload.Artifacts)
+	}
+	if res := do("/api/v1/tasks/artifacts?task_id=missing"); res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown task, got %d", res.Code)
+	}
+
+	res = do("/api/v1/tasks/artifacts/download?id=" + artifacts[0].ID)
+	if res.Code != http.StatusOK || res.Body.String() != "vendor,price\nacme,10\n" {
+		t.Fatalf("unexpected download %d %q", res.Code, res.Body.String())
+	}
+	if got := res.Header().Get("Content-Disposition"); got != `attachment; filename="vendors.csv"` {
+		t.Fatalf("unexpected disposition %q", got)
+	}
+	if res := do("/api/v1/tasks/artifacts/download?id=" + artifacts[1].ID); res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a deleted file, got %d", res.Code)
+	}
+
+	link, err := url.Parse(payload.Artifacts[0].DownloadURL)
+	if err != nil {
+		t.Fatalf("parse link: %v", err)
+	}
+	if res := do(link.RequestURI()); res.Code != http.StatusOK || res.Body.String() != "vendor,price\nacme,10\n" {
+		t.Fatalf("unexpected signed download %d %q", res.Code, res.Body.String())
+	}
+	query := link.Query()
+	query.Set("id", artifacts[1].ID)
+	if res := do(artifactlink.DownloadPath + "?" + query.Encode()); res.Code != http.StatusForbidden {
+		t.Fatalf("expected a link reused for another artifact refused, got %d", res.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type taskPlanRequest struct {
+	ID    string   `json:"id"`
+	Steps []string `json:"steps"`
+}
+
+// handleTaskPlan exposes the step plan of planned tasks. GET returns the plan
+// with per-step checkpoints; POST replaces the steps that have not started,
+// which the worker picks up before its next step.
+func (r *router) handleTaskPlan(w http.ResponseWriter, req *http.Request) {
+	var taskID string
+	var steps []store.TaskPlanStep
+	var err error
+	switch req.Method {
+	case http.MethodGet:
+		taskID = strings.TrimSpace(req.URL.Query().Get("id"))
+		if taskID == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id query parameter is required"})
+			return
+		}
+		if _, err = r.deps.Store.LookupTask(req.Context(), taskID); err == nil {
+			steps, err = r.deps.Store.ListTaskPlan(req.Context(), taskID)
+		}
+	case http.MethodPost:
+		var payload taskPlanRequest
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+			return
+		}
+		taskID = strings.TrimSpace(payload.ID)
+		if taskID == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id is required"})
+			return
+		}
+		if !r.authorizeTask(w, req, taskID, store.PermissionRouteTasks) {
+			return
+		}
+		steps, err = r.deps.Store.SetTaskPlan(req.Context(), taskID, payload.Steps)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrTaskNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, store.ErrTaskPlanClosed):
+			status = http.StatusConflict
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(steps))
+	for _, step := range steps {
+		items = append(items, map[string]any{
+			"position":        step.Position,
+			"description":     step.Description,
+			"status":          step.Status,
+			"result":          step.Result,
+			"updated_at_unix": step.UpdatedAt.Unix(),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"task_id": taskID,
+		"steps":   items,
+		"count":   len(items),
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +30,13 @@ type taskRequest struct {
 	SourceExternalID string `json:"source_external_id"`
 	SourceUserID     string `json:"source_user_id"`
 	SourceText       string `json:"source_text"`
+	// DependsOn lists tasks of the same workspace that must succeed first.
+	DependsOn []string `json:"depends_on"`
+	// RunAtUnix or Schedule hold the task back until a later time. Schedule
+	// takes a timestamp or a cron expression read in Timezone.
+	RunAtUnix int64  `json:"run_at_unix"`
+	Schedule  string `json:"schedule"`
+	Timezone  string `json:"timezone"`
 }
 
 func (r *router) handleTasks(w http.ResponseWriter, req *http.Request) {
@@ -50,6 +60,9 @@ func (r *router) handleTaskCreate(w http.ResponseWriter, req *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id, context_id, title and prompt are required"})
 		return
 	}
+	if !r.authorize(w, req, payload.WorkspaceID, store.PermissionRouteTasks) {
+		return
+	}
 
 	kind := orchestrator.TaskKind(payload.Kind)
 	if kind == "" {
@@ -59,6 +72,18 @@ func (r *router) handleTaskCreate(w http.ResponseWriter, req *http.Request) {
 	if payload.DueAtUnix > 0 {
 		dueAt = time.Unix(payload.DueAtUnix, 0).UTC()
 	}
+	runAt, err := taskRunAt(payload, time.Now().UTC())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	for _, id := range payload.DependsOn {
+		prerequisite, err := r.deps.Store.LookupTask(req.Context(), strings.TrimSpace(id))
+		if err != nil || prerequisite.WorkspaceID != payload.WorkspaceID {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("depends_on task %s not found in workspace", strings.TrimSpace(id))})
+			return
+		}
+	}
 	task, err := r.enqueueAndPersistTask(req.Context(), store.CreateTaskInput{
 		WorkspaceID:      payload.WorkspaceID,
 		ContextID:        payload.ContextID,
@@ -74,23 +99,47 @@ func (r *router) handleTaskCreate(w http.ResponseWriter, req *http.Request) {
 		SourceExternalID: payload.SourceExternalID,
 		SourceUserID:     payload.SourceUserID,
 		SourceText:       payload.SourceText,
+		DependsOn:        payload.DependsOn,
+		RunAt:            runAt,
 	})
 	if err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, orchestrator.ErrQueueFull) {
+		if errors.Is(err, orchestrator.ErrQueueFull) || errors.Is(err, store.ErrQuotaExceeded) {
 			status = http.StatusTooManyRequests
 		}
+		if errors.Is(err, orchestrator.ErrDependencyFailed) {
+			status = http.StatusConflict
+		}
 		writeJSON(w, status, map[string]string{"error": err.Error()})
 		return
 	}
 
-	writeJSON(w, http.StatusAccepted, map[string]any{
+	response := map[string]any{
 		"id":           task.ID,
 		"workspace_id": task.WorkspaceID,
 		"context_id":   task.ContextID,
 		"kind":         task.Kind,
 		"status":       "queued",
-	})
+	}
+	if !runAt.IsZero() {
+		response["run_at_unix"] = runAt.Unix()
+	}
+	writeJSON(w, http.StatusAccepted, response)
+}
+
+// taskRunAt resolves when a created task may start; zero means right away.
+func taskRunAt(payload taskRequest, now time.Time) (time.Time, error) {
+	if payload.RunAtUnix > 0 && strings.TrimSpace(payload.Schedule) != "" {
+		return time.Time{}, errors.New("set run_at_unix or schedule, not both")
+	}
+	if payload.RunAtUnix > 0 {
+		runAt := time.Unix(payload.RunAtUnix, 0).UTC()
+		if !runAt.After(now) {
+			return time.Time{}, store.ErrTaskScheduleInPast
+		}
+		return runAt, nil
+	}
+	return store.ResolveTaskSchedule(payload.Schedule, payload.Timezone, now)
 }
 
 func (r *router) handleTaskGet(w http.ResponseWriter, req *http.Request) {
@@ -123,12 +172,19 @@ func (r *router) handleTaskGet(w http.ResponseWriter, req *http.Request) {
 		}
 		limit = parsed
 	}
+	// status=dead lists the dead-lettered tasks, which are failed.
+	status := strings.TrimSpace(req.URL.Query().Get("status"))
+	deadLettered := strings.EqualFold(status, "dead")
+	if deadLettered {
+		status = ""
+	}
 	items, err := r.deps.Store.ListTasks(req.Context(), store.ListTasksInput{
-		WorkspaceID: workspaceID,
-		ContextID:   strings.TrimSpace(req.URL.Query().Get("context_id")),
-		Kind:        strings.TrimSpace(req.URL.Query().Get("kind")),
-		Status:      strings.TrimSpace(req.URL.Query().Get("status")),
-		Limit:       limit,
+		WorkspaceID:  workspaceID,
+		ContextID:    strings.TrimSpace(req.URL.Query().Get("context_id")),
+		Kind:         strings.TrimSpace(req.URL.Query().Get("kind")),
+		Status:       status,
+		DeadLettered: deadLettered,
+		Limit:        limit,
 	})
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -173,6 +229,9 @@ func (r *router) handleTaskRetry(w http.ResponseWriter, req *http.Request) {
 		writeJSON(w, status, map[string]string{"error": err.Error()})
 		return
 	}
+	if !r.authorize(w, req, original.WorkspaceID, store.PermissionRouteTasks) {
+		return
+	}
 	if strings.ToLower(strings.TrimSpace(original.Status)) != "failed" {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "only failed tasks can be retried"})
 		return
@@ -200,7 +259,7 @@ func (r *router) handleTaskRetry(w http.ResponseWriter, req *http.Request) {
 	})
 	if err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, orchestrator.ErrQueueFull) {
+		if errors.Is(err, orchestrator.ErrQueueFull) || errors.Is(err, store.ErrQuotaExceeded) {
 			status = http.StatusTooManyRequests
 		}
 		writeJSON(w, status, map[string]string{"error": err.Error()})
@@ -216,6 +275,111 @@ func (r *router) handleTaskRetry(w http.ResponseWriter, req *http.Request) {
 	})
 }
 
+type taskCancelRequest struct {
+	TaskID string `json:"task_id"`
+}
+
+// handleTaskCancel cancels a queued or running task. The status changes
+// first, so a worker finishing at the same moment cannot overwrite it.
+func (r *router) handleTaskCancel(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var payload taskCancelRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	taskID := strings.TrimSpace(payload.TaskID)
+	if taskID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "task_id is required"})
+		return
+	}
+	if !r.authorizeTask(w, req, taskID, store.PermissionRouteTasks) {
+		return
+	}
+	record, err := r.deps.Store.CancelTask(req.Context(), taskID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrTaskNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, store.ErrTaskNotActive):
+			status = http.StatusConflict
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	wasRunning := false
+	if r.deps.Engine != nil {
+		wasRunning = r.deps.Engine.Cancel(record.ID)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"task_id":     record.ID,
+		"status":      record.Status,
+		"was_running": wasRunning,
+	})
+}
+
+// taskResultMaxBytes caps how much of a result file handleTaskResult
+// returns; longer files come back truncated.
+const taskResultMaxBytes = 256 * 1024
+
+// handleTaskResult returns the markdown result file a finished task wrote to
+// its workspace.
+func (r *router) handleTaskResult(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	taskID := strings.TrimSpace(req.URL.Query().Get("id"))
+	if taskID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id query parameter is required"})
+		return
+	}
+	record, err := r.deps.Store.LookupTask(req.Context(), taskID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrTaskNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	resultPath := strings.TrimSpace(record.ResultPath)
+	if resultPath == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "task has no result file"})
+		return
+	}
+	workspaceDir := filepath.Join(r.deps.Config.WorkspaceRoot, record.WorkspaceID)
+	absolutePath := filepath.Join(workspaceDir, filepath.FromSlash(resultPath))
+	if relative, err := filepath.Rel(workspaceDir, absolutePath); err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "result path is outside the workspace"})
+		return
+	}
+	content, err := os.ReadFile(absolutePath)
+	if errors.Is(err, os.ErrNotExist) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "result file not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	truncated := len(content) > taskResultMaxBytes
+	if truncated {
+		content = content[:taskResultMaxBytes]
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"task_id":      record.ID,
+		"workspace_id": record.WorkspaceID,
+		"result_path":  resultPath,
+		"content":      string(content),
+		"truncated":    truncated,
+	})
+}
+
 func (r *router) enqueueAndPersistTask(ctx context.Context, input store.CreateTaskInput) (orchestrator.Task, error) {
 	task, err := r.deps.Engine.Enqueue(orchestrator.Task{
 		WorkspaceID: strings.TrimSpace(input.WorkspaceID),
@@ -223,6 +387,8 @@ func (r *router) enqueueAndPersistTask(ctx context.Context, input store.CreateTa
 		Title:       strings.TrimSpace(input.Title),
 		Prompt:      strings.TrimSpace(input.Prompt),
 		Kind:        orchestrator.TaskKind(strings.TrimSpace(input.Kind)),
+		DependsOn:   input.DependsOn,
+		RunAt:       input.RunAt,
 	})
 	if err != nil {
 		return orchestrator.Task{}, err
@@ -267,7 +433,14 @@ func taskRecordResponse(record store.TaskRecord) map[string]any {
 	if !record.UpdatedAt.IsZero() {
 		updatedAtUnix = record.UpdatedAt.Unix()
 	}
-	return map[string]any{
+	dependsOn, blocks := record.DependsOn, record.Blocks
+	if dependsOn == nil {
+		dependsOn = []string{}
+	}
+	if blocks == nil {
+		blocks = []string{}
+	}
+	payload := map[string]any{
 		"id":                 record.ID,
 		"workspace_id":       record.WorkspaceID,
 		"context_id":         record.ContextID,
@@ -292,5 +465,26 @@ func taskRecordResponse(record store.TaskRecord) map[string]any {
 		"error_message":      record.ErrorMessage,
 		"created_at_unix":    createdAtUnix,
 		"updated_at_unix":    updatedAtUnix,
+		"revision":           record.Revision,
+		"depends_on":         dependsOn,
+		"blocks":             blocks,
+	}
+	if !record.DeadLetteredAt.IsZero() {
+		payload["dead_lettered_at_unix"] = record.DeadLetteredAt.Unix()
+	}
+	if !record.RunAt.IsZero() {
+		payload["run_at_unix"] = record.RunAt.Unix()
+	}
+	if record.LeaseOwner != "" {
+		payload["lease_owner"] = record.LeaseOwner
+	}
+	if !record.ProgressUpdatedAt.IsZero() {
+		payload["progress_step"] = record.ProgressStep
+		payload["progress_percent"] = record.ProgressPercent
+		payload["progress_updated_at_unix"] = record.ProgressUpdatedAt.Unix()
+	}
+	if resultData := strings.TrimSpace(record.ResultData); resultData != "" && json.Valid([]byte(resultData)) {
+		payload["result_data"] = json.RawMessage(resultData)
 	}
+	return payload
 }
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -84,6 +85,122 @@ func TestTasksListAndRetry(t *testing.T) {
 	}
 }
 
+func TestTaskCreateWithDependencies(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+		ID:          "task-fetch",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Fetch data",
+		Prompt:      "fetch",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	create := func(dependsOn string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]any{
+			"workspace_id": "ws-1",
+			"context_id":   "ctx-1",
+			"title":        "Report",
+			"prompt":       "summarize",
+			"depends_on":   []string{dependsOn},
+		})
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewReader(body)))
+		return res
+	}
+
+	if res := create("task-missing"); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown prerequisite, got %d", res.Code)
+	}
+	res := create("task-fetch")
+	if res.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d, body=%s", res.Code, res.Body.String())
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode create payload: %v", err)
+	}
+
+	getRes := httptest.NewRecorder()
+	handler.ServeHTTP(getRes, httptest.NewRequest(http.MethodGet, "/api/v1/tasks?id=task-fetch", nil))
+	var prerequisite struct {
+		DependsOn []string `json:"depends_on"`
+		Blocks    []string `json:"blocks"`
+	}
+	if err := json.Unmarshal(getRes.Body.Bytes(), &prerequisite); err != nil {
+		t.Fatalf("decode task payload: %v", err)
+	}
+	if prerequisite.DependsOn == nil || len(prerequisite.Blocks) != 1 || prerequisite.Blocks[0] != created.ID {
+		t.Fatalf("expected task-fetch to block %s, got %+v", created.ID, prerequisite)
+	}
+}
+
+func TestTaskCreateScheduled(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	engine := orchestrator.New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: engine,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	create := func(fields map[string]any) *httptest.ResponseRecorder {
+		body := map[string]any{"workspace_id": "ws-1", "context_id": "ctx-1", "title": "Later", "prompt": "do it later"}
+		for key, value := range fields {
+			body[key] = value
+		}
+		encoded, _ := json.Marshal(body)
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewReader(encoded)))
+		return res
+	}
+
+	for _, fields := range []map[string]any{
+		{"schedule": "someday"},
+		{"run_at_unix": time.Now().Add(-time.Hour).Unix()},
+		{"run_at_unix": time.Now().Add(time.Hour).Unix(), "schedule": "0 9 * * *"},
+	} {
+		if res := create(fields); res.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %v, got %d", fields, res.Code)
+		}
+	}
+
+	runAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	res := create(map[string]any{"run_at_unix": runAt.Unix()})
+	if res.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d, body=%s", res.Code, res.Body.String())
+	}
+	var created struct {
+		ID        string `json:"id"`
+		RunAtUnix int64  `json:"run_at_unix"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode create payload: %v", err)
+	}
+	if created.RunAtUnix != runAt.Unix() || engine.Scheduled() != 1 {
+		t.Fatalf("expected the task held until %d, got %+v with %d scheduled", runAt.Unix(), created, engine.Scheduled())
+	}
+	record, err := sqlStore.LookupTask(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("lookup task: %v", err)
+	}
+	if !record.RunAt.Equal(runAt) {
+		t.Fatalf("expected run_at %s persisted, got %s", runAt, record.RunAt)
+	}
+	engine.Cancel(created.ID)
+}
+
 func TestTaskRetryRejectsNonFailedTask(t *testing.T) {
 	sqlStore := newRouterTestStore(t)
 	ctx := context.Background()
@@ -117,6 +234,57 @@ func TestTaskRetryRejectsNonFailedTask(t *testing.T) {
 	}
 }
 
+func TestTaskCancel(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+		ID:          "task-queued",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Queued task",
+		Prompt:      "do thing",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	cancel := func(taskID string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"task_id": taskID})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/cancel", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	res := cancel("task-queued")
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for cancel, got %d, body=%s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Status     string `json:"status"`
+		WasRunning bool   `json:"was_running"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode cancel payload: %v", err)
+	}
+	if payload.Status != "cancelled" || payload.WasRunning {
+		t.Fatalf("unexpected cancel payload: %+v", payload)
+	}
+	if res := cancel("task-queued"); res.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for a second cancel, got %d", res.Code)
+	}
+	if res := cancel("task-missing"); res.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for a missing task, got %d", res.Code)
+	}
+}
+
 func TestHeartbeatEndpoint(t *testing.T) {
 	sqlStore := newRouterTestStore(t)
 	registry := heartbeat.NewRegistry()
@@ -172,3 +340,127 @@ func newRouterTestStore(t *testing.T) *store.Store {
 	}
 	return sqlStore
 }
+
+func TestTaskResultReturnsMarkdown(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	workspaceRoot := t.TempDir()
+	for _, id := range []string{"task-done", "task-escape"} {
+		if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+			ID:          id,
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Kind:        "general",
+			Title:       "Write report",
+			Prompt:      "write the report",
+			Status:      "running",
+		}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+	resultPath := filepath.Join(workspaceRoot, "ws-1", "tasks", "2026", "10", "17", "task-done.md")
+	if err := os.MkdirAll(filepath.Dir(resultPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(resultPath, []byte("# Task Result\n\nall done\n"), 0o644); err != nil {
+		t.Fatalf("write result: %v", err)
+	}
+	if err := sqlStore.MarkTaskCompleted(ctx, "task-done", time.Now().UTC(), "all done", "tasks/2026/10/17/task-done.md"); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+	if err := sqlStore.MarkTaskCompleted(ctx, "task-escape", time.Now().UTC(), "sneaky", "../ws-2/secret.md"); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+	handler := NewRouter(Dependencies{
+		Config: config.Config{WorkspaceRoot: workspaceRoot},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	do := func(path string) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+		return res
+	}
+
+	res := do("/api/v1/tasks/result?id=task-done")
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		ResultPath string `json:"result_path"`
+		Content    string `json:"content"`
+		Truncated  bool   `json:"truncated"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.ResultPath != "tasks/2026/10/17/task-done.md" || payload.Content != "# Task Result\n\nall done\n" || payload.Truncated {
+		t.Fatalf("unexpected result %+v", payload)
+	}
+	if res := do("/api/v1/tasks/result?id=task-escape"); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected result path outside the workspace rejected, got %d", res.Code)
+	}
+	if res := do("/api/v1/tasks/result?id=missing"); res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown task, got %d", res.Code)
+	}
+}
+
+func TestTaskPlanGetAndEdit(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+		ID:          "task-planned",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Planned task",
+		Prompt:      "do several things",
+		Status:      "running",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := sqlStore.SetTaskPlan(ctx, "task-planned", []string{"first", "second"}); err != nil {
+		t.Fatalf("set plan: %v", err)
+	}
+	if err := sqlStore.UpdateTaskPlanStep(ctx, "task-planned", 1, store.TaskPlanStepDone, "ok"); err != nil {
+		t.Fatalf("checkpoint: %v", err)
+	}
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/plan", bytes.NewBufferString(`{"id":"task-planned","steps":["revised second","third"]}`))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/tasks/plan?id=task-planned", nil)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	var payload struct {
+		Steps []struct {
+			Description string `json:"description"`
+			Status      string `json:"status"`
+			Result      string `json:"result"`
+		} `json:"steps"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Steps) != 3 || payload.Steps[0].Result != "ok" || payload.Steps[1].Description != "revised second" || payload.Steps[2].Status != "pending" {
+		t.Fatalf("unexpected plan %+v", payload.Steps)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/tasks/plan?id=missing", nil)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown task, got %d", res.Code)
+	}
+}
@@ -0,0 +1,164 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type taskDeleteRequest struct {
+	TaskID   string `json:"task_id"`
+	Revision int    `json:"revision"`
+}
+
+type trashRestoreRequest struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+func (r *router) handleTaskDelete(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var payload taskDeleteRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	taskID := strings.TrimSpace(payload.TaskID)
+	if taskID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "task_id is required"})
+		return
+	}
+	if !r.authorizeTask(w, req, taskID, store.PermissionRouteTasks) {
+		return
+	}
+	if err := r.deps.Store.DeleteTask(req.Context(), taskID, payload.Revision); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrTaskNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, store.ErrTaskActive), errors.Is(err, store.ErrRevisionConflict):
+			status = http.StatusConflict
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"task_id":       taskID,
+		"deleted":       true,
+		"purge_at_unix": time.Now().UTC().Add(store.TrashRetention).Unix(),
+	})
+}
+
+// handleTrash lists deleted tasks and objectives that can still be restored.
+func (r *router) handleTrash(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	workspaceID := strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id query parameter is required"})
+		return
+	}
+	limit := 100
+	if limitInput := strings.TrimSpace(req.URL.Query().Get("limit")); limitInput != "" {
+		parsed, err := strconv.Atoi(limitInput)
+		if err != nil || parsed < 1 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+	entries, err := r.deps.Store.ListTrash(req.Context(), store.ListTrashInput{
+		WorkspaceID: workspaceID,
+		Limit:       limit,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, map[string]any{
+			"kind":            string(entry.Kind),
+			"id":              entry.ID,
+			"workspace_id":    entry.WorkspaceID,
+			"title":           entry.Title,
+			"status":          entry.Status,
+			"deleted_at_unix": entry.DeletedAt.Unix(),
+			"purge_at_unix":   entry.PurgeAt.Unix(),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":          items,
+		"count":          len(items),
+		"retention_days": int(store.TrashRetention / (24 * time.Hour)),
+	})
+}
+
+func (r *router) handleTrashRestore(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var payload trashRestoreRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	id := strings.TrimSpace(payload.ID)
+	if id == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return
+	}
+	kind := store.TrashKind(strings.ToLower(strings.TrimSpace(payload.Kind)))
+	switch kind {
+	case store.TrashKindTask:
+		if !r.authorizeTrashed(w, req, kind, id, store.PermissionRouteTasks, store.ErrTaskNotFound) {
+			return
+		}
+		record, err := r.deps.Store.RestoreTask(req.Context(), id)
+		if err != nil {
+			writeTrashRestoreError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, taskRecordResponse(record))
+	case store.TrashKindObjective:
+		if !r.authorizeTrashed(w, req, kind, id, store.PermissionManageObjectives, store.ErrObjectiveNotFound) {
+			return
+		}
+		objective, err := r.deps.Store.RestoreObjective(req.Context(), id)
+		if err != nil {
+			writeTrashRestoreError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, objectiveToMap(objective))
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "kind must be task or objective"})
+	}
+}
+
+func (r *router) authorizeTrashed(w http.ResponseWriter, req *http.Request, kind store.TrashKind, id string, permission store.Permission, notFound error) bool {
+	return r.authorizeRecord(w, req, permission, notFound, func() (string, error) {
+		return r.deps.Store.TrashedWorkspace(req.Context(), kind, id)
+	})
+}
+
+func writeTrashRestoreError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, store.ErrTaskNotFound), errors.Is(err, store.ErrObjectiveNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, store.ErrQuotaExceeded):
+		status = http.StatusTooManyRequests
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,110 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestTrashListsAndRestoresDeletedItems(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	objective, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Digest",
+		Prompt:      "Summarize",
+		TriggerType: store.ObjectiveTriggerEvent,
+		EventKey:    "markdown.updated",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	for _, id := range []string{"task-running", "task-done"} {
+		if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{
+			ID:          id,
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Kind:        "general",
+			Title:       id,
+			Prompt:      "work",
+			Status:      "queued",
+		}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-running", 1, time.Now().UTC()); err != nil {
+		t.Fatalf("mark task running: %v", err)
+	}
+	if err := sqlStore.MarkTaskCompleted(ctx, "task-done", time.Now().UTC(), "ok", ""); err != nil {
+		t.Fatalf("mark task completed: %v", err)
+	}
+
+	if res := post("/api/v1/objectives/delete", `{"id":"`+objective.ID+`"}`); res.Code != http.StatusOK {
+		t.Fatalf("expected objective delete 200, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := post("/api/v1/tasks/delete", `{"task_id":"task-running"}`); res.Code != http.StatusConflict {
+		t.Fatalf("expected running task delete 409, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := post("/api/v1/tasks/delete", `{"task_id":"task-done"}`); res.Code != http.StatusOK {
+		t.Fatalf("expected task delete 200, got %d: %s", res.Code, res.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trash?workspace_id=ws-1", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected trash list 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Items []struct {
+			Kind        string `json:"kind"`
+			ID          string `json:"id"`
+			PurgeAtUnix int64  `json:"purge_at_unix"`
+		} `json:"items"`
+		RetentionDays int `json:"retention_days"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode trash: %v", err)
+	}
+	if len(payload.Items) != 2 || payload.RetentionDays != 30 {
+		t.Fatalf("unexpected trash payload %+v", payload)
+	}
+
+	if res := post("/api/v1/trash/restore", `{"kind":"objective","id":"`+objective.ID+`"}`); res.Code != http.StatusOK {
+		t.Fatalf("expected objective restore 200, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := post("/api/v1/trash/restore", `{"kind":"task","id":"task-done"}`); res.Code != http.StatusOK {
+		t.Fatalf("expected task restore 200, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := post("/api/v1/trash/restore", `{"kind":"task","id":"task-done"}`); res.Code != http.StatusNotFound {
+		t.Fatalf("expected second restore 404, got %d: %s", res.Code, res.Body.String())
+	}
+	if _, err := sqlStore.LookupObjective(ctx, objective.ID); err != nil {
+		t.Fatalf("expected restored objective to be visible: %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/eventbus"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type webhookSaveRequest struct {
+	ID          string   `json:"id"`
+	WorkspaceID string   `json:"workspace_id"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	EventTypes  []string `json:"event_types"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+type webhookDeleteRequest struct {
+	ID string `json:"id"`
+}
+
+// handleWebhooks lists (GET) or saves (POST) the webhook subscriptions of a
+// workspace. Secrets are only ever returned by the POST that set them.
+// Managing subscriptions needs an admin or overlord role because they send
+// workspace events off the runtime.
+func (r *router) handleWebhooks(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		workspaceID := strings.TrimSpace(req.URL.Query().Get("workspace_id"))
+		if workspaceID == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workspace_id query parameter is required"})
+			return
+		}
+		if !r.authorizeAdmin(w, req, "manage webhooks") {
+			return
+		}
+		subscriptions, err := r.deps.Store.ListWebhookSubscriptions(req.Context(), workspaceID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		items := make([]map[string]any, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			items = append(items, webhookSubscriptionResponse(subscription, false))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"workspace_id": workspaceID,
+			"items":        items,
+			"event_types":  eventbus.Types,
+		})
+	case http.MethodPost:
+		if !r.authorizeAdmin(w, req, "manage webhooks") {
+			return
+		}
+		var payload webhookSaveRequest
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+			return
+		}
+		eventTypes, err := eventbus.ParseTypes(strings.Join(payload.EventTypes, ","))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		input := store.SaveWebhookSubscriptionInput{
+			ID:          strings.TrimSpace(payload.ID),
+			WorkspaceID: payload.WorkspaceID,
+			URL:         payload.URL,
+			Secret:      payload.Secret,
+			EventTypes:  eventTypes,
+			Enabled:     true,
+		}
+		if input.ID != "" {
+			// Updates keep whatever the request leaves out.
+			existing, err := r.deps.Store.LookupWebhookSubscription(req.Context(), input.ID)
+			if err != nil {
+				writeWebhookError(w, err)
+				return
+			}
+			if strings.TrimSpace(input.WorkspaceID) == "" {
+				input.WorkspaceID = existing.WorkspaceID
+			}
+			if strings.TrimSpace(input.URL) == "" {
+				input.URL = existing.URL
+			}
+			if payload.EventTypes == nil {
+				input.EventTypes = existing.EventTypes
+			}
+			input.Enabled = existing.Enabled
+		}
+		if payload.Enabled != nil {
+			input.Enabled = *payload.Enabled
+		}
+		subscription, err := r.deps.Store.SaveWebhookSubscription(req.Context(), input)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if input.ID == "" {
+			status = http.StatusCreated
+		}
+		showSecret := input.ID == "" || strings.TrimSpace(payload.Secret) != ""
+		writeJSON(w, status, webhookSubscriptionResponse(subscription, showSecret))
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// handleWebhooksDelete removes a subscription.
+func (r *router) handleWebhooksDelete(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if !r.authorizeAdmin(w, req, "manage webhooks") {
+		return
+	}
+	var payload webhookDeleteRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+	if strings.TrimSpace(payload.ID) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return
+	}
+	subscription, err := r.deps.Store.DeleteWebhookSubscription(req.Context(), payload.ID)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":           subscription.ID,
+		"workspace_id": subscription.WorkspaceID,
+		"deleted":      true,
+	})
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, store.ErrWebhookSubscriptionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, store.ErrWorkspaceScope):
+		status = http.StatusForbidden
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func webhookSubscriptionResponse(subscription store.WebhookSubscription, withSecret bool) map[string]any {
+	item := map[string]any{
+		"id":                   subscription.ID,
+		"workspace_id":         subscription.WorkspaceID,
+		"url":                  subscription.URL,
+		"event_types":          subscription.EventTypes,
+		"enabled":              subscription.Enabled,
+		"has_secret":           subscription.Secret != "",
+		"last_status":          subscription.LastStatus,
+		"last_error":           subscription.LastError,
+		"consecutive_failures": subscription.ConsecutiveFailures,
+		"created_at":           subscription.CreatedAt.Format(time.RFC3339),
+		"updated_at":           subscription.UpdatedAt.Format(time.RFC3339),
+	}
+	if !subscription.LastDeliveryAt.IsZero() {
+		item["last_delivery_at"] = subscription.LastDeliveryAt.Format(time.RFC3339)
+	}
+	if withSecret {
+		item["secret"] = subscription.Secret
+	}
+	return item
+}
@@ -0,0 +1,83 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+func TestWebhooksCreateUpdateListDelete(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	if res := do(http.MethodPost, "/api/v1/webhooks", `{"workspace_id":"ws-1","url":"https://hooks.example.com","event_types":["task.exploded"]}`); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown event type rejected, got %d: %s", res.Code, res.Body.String())
+	}
+	res := do(http.MethodPost, "/api/v1/webhooks", `{"workspace_id":"ws-1","url":"https://hooks.example.com","event_types":["approval.pending"]}`)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", res.Code, res.Body.String())
+	}
+	var created struct {
+		ID      string `json:"id"`
+		Secret  string `json:"secret"`
+		Enabled bool   `json:"enabled"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode webhook: %v", err)
+	}
+	if created.ID == "" || !strings.HasPrefix(created.Secret, "whsec_") || !created.Enabled {
+		t.Fatalf("expected enabled webhook with generated secret, got %+v", created)
+	}
+
+	res = do(http.MethodPost, "/api/v1/webhooks", `{"id":"`+created.ID+`","enabled":false}`)
+	if res.Code != http.StatusOK || strings.Contains(res.Body.String(), "whsec_") {
+		t.Fatalf("expected update without secret in response, got %d: %s", res.Code, res.Body.String())
+	}
+
+	res = do(http.MethodGet, "/api/v1/webhooks?workspace_id=ws-1", "")
+	var listed struct {
+		Items []struct {
+			URL        string   `json:"url"`
+			EventTypes []string `json:"event_types"`
+			Enabled    bool     `json:"enabled"`
+			HasSecret  bool     `json:"has_secret"`
+			Secret     string   `json:"secret"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(listed.Items) != 1 {
+		t.Fatalf("expected one webhook, got %s", res.Body.String())
+	}
+	item := listed.Items[0]
+	if item.URL != "https://hooks.example.com" || item.Enabled || !item.HasSecret || item.Secret != "" || len(item.EventTypes) != 1 {
+		t.Fatalf("unexpected listed webhook %+v", item)
+	}
+
+	if res := do(http.MethodPost, "/api/v1/webhooks/delete", `{"id":"`+created.ID+`"}`); res.Code != http.StatusOK {
+		t.Fatalf("expected delete ok, got %d: %s", res.Code, res.Body.String())
+	}
+	if res := do(http.MethodPost, "/api/v1/webhooks/delete", `{"id":"`+created.ID+`"}`); res.Code != http.StatusNotFound {
+		t.Fatalf("expected second delete not found, got %d: %s", res.Code, res.Body.String())
+	}
+}
@@ -0,0 +1,56 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+type workersUpdateRequest struct {
+	Workers *int `json:"workers"`
+}
+
+// handleWorkers reports queue depth, latency and the worker pool for
+// autoscalers. POST sets the worker count, clamped to the pool bounds.
+func (r *router) handleWorkers(w http.ResponseWriter, req *http.Request) {
+	if r.deps.Engine == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "orchestrator is unavailable"})
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var payload workersUpdateRequest
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil || payload.Workers == nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "workers is required"})
+			return
+		}
+		if !r.authorizeAdmin(w, req, "scale workers") {
+			return
+		}
+		r.deps.Engine.SetWorkers(*payload.Workers)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, workerStatsPayload(r.deps.Engine.Stats()))
+}
+
+func workerStatsPayload(stats orchestrator.PoolStats) map[string]any {
+	return map[string]any{
+		"workers":         stats.Workers,
+		"busy_workers":    stats.BusyWorkers,
+		"min_workers":     stats.MinWorkers,
+		"max_workers":     stats.MaxWorkers,
+		"desired_workers": stats.DesiredWorkers,
+		"queue_depth":     stats.QueueDepth,
+		"queue_capacity":  stats.QueueCapacity,
+		"held_tasks":      stats.Held,
+		"scheduled_tasks": stats.Scheduled,
+		"backpressure":    stats.Backpressure,
+		"processed":       stats.Processed,
+		"avg_wait_ms":     stats.AvgWait.Milliseconds(),
+		"avg_run_ms":      stats.AvgRun.Milliseconds(),
+	}
+}
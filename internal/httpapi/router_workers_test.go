@@ -0,0 +1,52 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+)
+
+func TestWorkersReportsQueueAndResizesPool(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := orchestrator.New(2, logger)
+	engine.SetWorkerBounds(1, 6)
+	if _, err := engine.Enqueue(orchestrator.Task{WorkspaceID: "ws-1", Title: "Waiting"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	handler := NewRouter(Dependencies{Config: config.Config{}, Engine: engine, Logger: logger})
+	serve := func(method, body string) map[string]any {
+		req := httptest.NewRequest(method, "/api/v1/workers", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		if res.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", res.Code, res.Body.String())
+		}
+		payload := map[string]any{}
+		if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode workers: %v", err)
+		}
+		return payload
+	}
+
+	stats := serve(http.MethodGet, "")
+	if stats["workers"] != float64(2) || stats["queue_depth"] != float64(1) || stats["queue_capacity"] != float64(100) || stats["max_workers"] != float64(6) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats := serve(http.MethodPost, `{"workers":9}`); stats["workers"] != float64(6) {
+		t.Fatalf("expected the worker count clamped to 6, got %+v", stats)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/workers", strings.NewReader(`{}`))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing workers rejected, got %d", res.Code)
+	}
+}
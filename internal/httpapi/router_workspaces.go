@@ -0,0 +1,34 @@
+package httpapi
+
+import (
+	"net/http"
+)
+
+// handleWorkspaces lists the workspaces with their task and objective counts
+// so clients can offer a workspace picker instead of asking for raw ids.
+func (r *router) handleWorkspaces(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	workspaces, err := r.deps.Store.ListWorkspaces(req.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	items := make([]map[string]any, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		items = append(items, map[string]any{
+			"id":                     workspace.ID,
+			"slug":                   workspace.Slug,
+			"name":                   workspace.Name,
+			"kind":                   workspace.Kind,
+			"task_count":             workspace.TaskCount,
+			"open_task_count":        workspace.OpenTaskCount,
+			"failed_task_count":      workspace.FailedTaskCount,
+			"objective_count":        workspace.ObjectiveCount,
+			"active_objective_count": workspace.ActiveObjectiveCount,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "count": len(items)})
+}
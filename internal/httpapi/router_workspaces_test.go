@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestWorkspacesListsCounts(t *testing.T) {
+	sqlStore := newRouterTestStore(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewRouter(Dependencies{
+		Config: config.Config{},
+		Store:  sqlStore,
+		Engine: orchestrator.New(1, logger),
+		Logger: logger,
+	})
+	if err := sqlStore.CreateTask(context.Background(), store.CreateTaskInput{
+		ID:          "task-1",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Review docs",
+		Prompt:      "Review docs",
+		Status:      "failed",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/v1/workspaces", nil))
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", res.Code, res.Body.String())
+	}
+	var payload struct {
+		Items []struct {
+			ID              string `json:"id"`
+			TaskCount       int    `json:"task_count"`
+			FailedTaskCount int    `json:"failed_task_count"`
+			ObjectiveCount  int    `json:"objective_count"`
+		} `json:"items"`
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode workspaces: %v", err)
+	}
+	if payload.Count != 1 || payload.Items[0].ID != "ws-1" || payload.Items[0].TaskCount != 1 || payload.Items[0].FailedTaskCount != 1 || payload.Items[0].ObjectiveCount != 0 {
+		t.Fatalf("unexpected workspaces %+v", payload)
+	}
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/v1/workspaces", nil))
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for POST, got %d", res.Code)
+	}
+}
@@ -0,0 +1,119 @@
+package i18n
+
+const (
+	// Usage wraps a command's syntax, e.g. "Usage: /voice on | /voice off".
+	Usage Key = "usage"
+
+	AccessDeniedAdmin Key = "access_denied_admin"
+	AccessDeniedLink  Key = "access_denied_link"
+
+	ActionNotFound      Key = "action_not_found"
+	ActionNotPending    Key = "action_not_pending"
+	ApprovalQueued      Key = "approval_queued"
+	ApprovalQueuedNoID  Key = "approval_queued_no_id"
+	PendingNone         Key = "pending_none"
+	PendingMultipleHere Key = "pending_multiple_here"
+	PendingMultipleAll  Key = "pending_multiple_all"
+	PendingRunList      Key = "pending_run_list"
+	PendingNeedsPairing Key = "pending_needs_pairing"
+	PendingNotApprover  Key = "pending_not_approver"
+	PendingRunApprove   Key = "pending_run_approve"
+
+	TriageAckIssue      Key = "triage_ack_issue"
+	TriageAckModeration Key = "triage_ack_moderation"
+	TriageAckQuestion   Key = "triage_ack_question"
+	TriageAckDefault    Key = "triage_ack_default"
+	TriageAckBusy       Key = "triage_ack_busy"
+	TriageAckBusyLow    Key = "triage_ack_busy_low"
+	QueueBusy           Key = "queue_busy"
+
+	RateLimitedUser    Key = "rate_limited_user"
+	RateLimitedChannel Key = "rate_limited_channel"
+
+	LanguageSet    Key = "language_set"
+	LanguageStatus Key = "language_status"
+)
+
+var catalog = map[string]map[Key]string{
+	English: {
+		Usage:               "Usage: %s",
+		AccessDeniedAdmin:   "Access denied: admin role required.",
+		AccessDeniedLink:    "Access denied: link your admin identity first.",
+		ActionNotFound:      "Action approval not found.",
+		ActionNotPending:    "Action approval is not pending.",
+		ApprovalQueued:      "I queued this action and it now needs admin approval.\n\nNext:\n1) Run `/approve-action %s`\n2) Or run `/pending-actions` to review approvals first.",
+		ApprovalQueuedNoID:  "I queued the action and it now needs admin approval. Run `/pending-actions`, then `/approve-action <action-id>` to continue.",
+		PendingNone:         "No pending action approvals right now. After an action is queued, run `/pending-actions` and then `/approve-action <action-id>`.",
+		PendingMultipleHere: "Multiple pending actions found in this context. Run `/pending-actions`, then execute `/approve-action <action-id>` for the one you want.",
+		PendingMultipleAll:  "Multiple pending actions found in all contexts. Run `/pending-actions`, then execute `/approve-action <action-id>` for the one you want.",
+		PendingRunList:      "Run `/pending-actions`, then execute `/approve-action <action-id>`.",
+		PendingNeedsPairing: "Pending action found: `%[1]s`.\nNext:\n1) Link your admin identity by sending `pair` and completing approval.\n2) Run `/approve-action %[1]s`.\nUse `/pending-actions` to verify.",
+		PendingNotApprover:  "Pending action found: `%[1]s`.\nYou do not have admin approval rights in this context. Ask an admin to run `/approve-action %[1]s`.\nUse `/pending-actions` to verify.",
+		PendingRunApprove:   "Run `/approve-action %s`.\nUse `/pending-actions` if you want to review all pending approvals first.",
+		TriageAckIssue:      "Thanks for flagging this. I’m investigating now and I’ll report back with findings.",
+		TriageAckModeration: "Received. I’m reviewing this now and I’ll follow up with what I find.",
+		TriageAckQuestion:   "Yes, I’m on it. I’ll investigate and come back with an answer.",
+		TriageAckDefault:    "Understood. I’m handling this now and I’ll share results shortly.",
+		TriageAckBusy:       "Got it, I'm on it. %s",
+		TriageAckBusyLow:    "Got it. %s Low-priority requests like this one wait until the backlog clears.",
+		QueueBusy:           "The task queue is busy right now, so expect delays.",
+		RateLimitedUser:     "You're sending messages too fast. Try again in %s.",
+		RateLimitedChannel:  "This channel is sending messages too fast. Try again in %s.",
+		LanguageSet:         "Replies in this channel are now in %s.",
+		LanguageStatus:      "Replies in this channel are in %s. Available: %s.",
+	},
+	Spanish: {
+		Usage:               "Uso: %s",
+		AccessDeniedAdmin:   "Acceso denegado: se requiere el rol de administrador.",
+		AccessDeniedLink:    "Acceso denegado: primero vincula tu identidad de administrador.",
+		ActionNotFound:      "No se encontró la aprobación de la acción.",
+		ActionNotPending:    "La aprobación de la acción ya no está pendiente.",
+		ApprovalQueued:      "Puse esta acción en cola y ahora necesita la aprobación de un administrador.\n\nSiguiente:\n1) Ejecuta `/approve-action %s`\n2) O ejecuta `/pending-actions` para revisar primero las aprobaciones.",
+		ApprovalQueuedNoID:  "Puse la acción en cola y ahora necesita la aprobación de un administrador. Ejecuta `/pending-actions` y luego `/approve-action <action-id>` para continuar.",
+		PendingNone:         "No hay aprobaciones de acciones pendientes ahora mismo. Cuando se ponga una acción en cola, ejecuta `/pending-actions` y luego `/approve-action <action-id>`.",
+		PendingMultipleHere: "Hay varias acciones pendientes en este contexto. Ejecuta `/pending-actions` y luego `/approve-action <action-id>` para la que quieras.",
+		PendingMultipleAll:  "Hay varias acciones pendientes en todos los contextos. Ejecuta `/pending-actions` y luego `/approve-action <action-id>` para la que quieras.",
+		PendingRunList:      "Ejecuta `/pending-actions` y luego `/approve-action <action-id>`.",
+		PendingNeedsPairing: "Acción pendiente encontrada: `%[1]s`.\nSiguiente:\n1) Vincula tu identidad de administrador enviando `pair` y completando la aprobación.\n2) Ejecuta `/approve-action %[1]s`.\nUsa `/pending-actions` para comprobarlo.",
+		PendingNotApprover:  "Acción pendiente encontrada: `%[1]s`.\nNo tienes permisos de aprobación en este contexto. Pide a un administrador que ejecute `/approve-action %[1]s`.\nUsa `/pending-actions` para comprobarlo.",
+		PendingRunApprove:   "Ejecuta `/approve-action %s`.\nUsa `/pending-actions` si quieres revisar antes todas las aprobaciones pendientes.",
+		TriageAckIssue:      "Gracias por avisar. Lo estoy investigando y te informaré de lo que encuentre.",
+		TriageAckModeration: "Recibido. Lo estoy revisando y te contaré lo que encuentre.",
+		TriageAckQuestion:   "Sí, me encargo. Lo investigo y vuelvo con una respuesta.",
+		TriageAckDefault:    "Entendido. Me ocupo ahora y compartiré los resultados en breve.",
+		TriageAckBusy:       "Entendido, me encargo. %s",
+		TriageAckBusyLow:    "Entendido. %s Las solicitudes de baja prioridad como esta esperan a que se vacíe la cola.",
+		QueueBusy:           "La cola de tareas está ocupada ahora mismo, así que habrá retrasos.",
+		RateLimitedUser:     "Estás enviando mensajes demasiado rápido. Inténtalo de nuevo en %s.",
+		RateLimitedChannel:  "Este canal está enviando mensajes demasiado rápido. Inténtalo de nuevo en %s.",
+		LanguageSet:         "Las respuestas en este canal ahora son en %s.",
+		LanguageStatus:      "Las respuestas en este canal son en %s. Disponibles: %s.",
+	},
+	Portuguese: {
+		Usage:               "Uso: %s",
+		AccessDeniedAdmin:   "Acesso negado: é necessário o papel de administrador.",
+		AccessDeniedLink:    "Acesso negado: vincule primeiro sua identidade de administrador.",
+		ActionNotFound:      "Aprovação da ação não encontrada.",
+		ActionNotPending:    "A aprovação da ação não está mais pendente.",
+		ApprovalQueued:      "Coloquei esta ação na fila e agora ela precisa da aprovação de um administrador.\n\nPróximos passos:\n1) Execute `/approve-action %s`\n2) Ou execute `/pending-actions` para revisar as aprovações antes.",
+		ApprovalQueuedNoID:  "Coloquei a ação na fila e agora ela precisa da aprovação de um administrador. Execute `/pending-actions` e depois `/approve-action <action-id>` para continuar.",
+		PendingNone:         "Nenhuma aprovação de ação pendente agora. Quando uma ação entrar na fila, execute `/pending-actions` e depois `/approve-action <action-id>`.",
+		PendingMultipleHere: "Há várias ações pendentes neste contexto. Execute `/pending-actions` e depois `/approve-action <action-id>` para a que você quiser.",
+		PendingMultipleAll:  "Há várias ações pendentes em todos os contextos. Execute `/pending-actions` e depois `/approve-action <action-id>` para a que você quiser.",
+		PendingRunList:      "Execute `/pending-actions` e depois `/approve-action <action-id>`.",
+		PendingNeedsPairing: "Ação pendente encontrada: `%[1]s`.\nPróximos passos:\n1) Vincule sua identidade de administrador enviando `pair` e concluindo a aprovação.\n2) Execute `/approve-action %[1]s`.\nUse `/pending-actions` para conferir.",
+		PendingNotApprover:  "Ação pendente encontrada: `%[1]s`.\nVocê não tem permissão de aprovação neste contexto. Peça a um administrador para executar `/approve-action %[1]s`.\nUse `/pending-actions` para conferir.",
+		PendingRunApprove:   "Execute `/approve-action %s`.\nUse `/pending-actions` se quiser revisar antes todas as aprovações pendentes.",
+		TriageAckIssue:      "Obrigado por avisar. Estou investigando agora e volto com o que encontrar.",
+		TriageAckModeration: "Recebido. Estou analisando agora e depois conto o que encontrei.",
+		TriageAckQuestion:   "Sim, estou cuidando disso. Vou investigar e volto com uma resposta.",
+		TriageAckDefault:    "Entendido. Estou cuidando disso agora e compartilho os resultados em breve.",
+		TriageAckBusy:       "Entendido, estou cuidando disso. %s",
+		TriageAckBusyLow:    "Entendido. %s Pedidos de baixa prioridade como este aguardam até a fila esvaziar.",
+		QueueBusy:           "A fila de tarefas está ocupada agora, então pode haver atrasos.",
+		RateLimitedUser:     "Você está enviando mensagens rápido demais. Tente novamente em %s.",
+		RateLimitedChannel:  "Este canal está enviando mensagens rápido demais. Tente novamente em %s.",
+		LanguageSet:         "As respostas neste canal agora são em %s.",
+		LanguageStatus:      "As respostas neste canal são em %s. Disponíveis: %s.",
+	},
+}
@@ -0,0 +1,100 @@
+// Package i18n holds the translated texts of gateway replies and the reply
+// language of the channel a request came from.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	English    = "en"
+	Spanish    = "es"
+	Portuguese = "pt"
+)
+
+// Key names one reply text. Its English text is the fallback for languages
+// that lack a translation.
+type Key string
+
+type languageContextKey struct{}
+
+// Supported lists the reply languages in a stable order, English first.
+func Supported() []string {
+	return []string{English, Spanish, Portuguese}
+}
+
+// Normalize maps a language code, a regional variant such as "pt-BR" or a
+// language name such as "español" to a supported language. It reports false
+// for anything else.
+func Normalize(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if base, _, found := strings.Cut(strings.ReplaceAll(value, "_", "-"), "-"); found {
+		value = base
+	}
+	switch value {
+	case English, "english", "inglés", "ingles", "inglês":
+		return English, true
+	case Spanish, "spanish", "español", "espanol", "espanhol":
+		return Spanish, true
+	case Portuguese, "portuguese", "português", "portugues", "portugués":
+		return Portuguese, true
+	}
+	return "", false
+}
+
+// Name returns a language's name written in that language.
+func Name(language string) string {
+	switch language {
+	case Spanish:
+		return "español"
+	case Portuguese:
+		return "português"
+	default:
+		return "English"
+	}
+}
+
+// WithLanguage sets the reply language of requests handled with ctx.
+// Unsupported or empty languages leave replies in English.
+func WithLanguage(ctx context.Context, language string) context.Context {
+	normalized, ok := Normalize(language)
+	if !ok || normalized == English {
+		return ctx
+	}
+	return context.WithValue(ctx, languageContextKey{}, normalized)
+}
+
+// LanguageFrom returns the reply language set with WithLanguage, English by
+// default.
+func LanguageFrom(ctx context.Context) string {
+	if ctx == nil {
+		return English
+	}
+	if language, ok := ctx.Value(languageContextKey{}).(string); ok && language != "" {
+		return language
+	}
+	return English
+}
+
+// T returns the text of key in the reply language of ctx, formatted with
+// args when given.
+func T(ctx context.Context, key Key, args ...any) string {
+	return Text(LanguageFrom(ctx), key, args...)
+}
+
+// Text returns the text of key in language, falling back to English.
+func Text(language string, key Key, args ...any) string {
+	text, ok := catalog[language][key]
+	if !ok {
+		text, ok = catalog[English][key]
+	}
+	if !ok {
+		text = string(key)
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
@@ -0,0 +1,46 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCatalogTranslatesEveryKey(t *testing.T) {
+	for _, language := range Supported() {
+		for key, english := range catalog[English] {
+			text, ok := catalog[language][key]
+			if !ok || strings.TrimSpace(text) == "" {
+				t.Fatalf("%s has no text for %s", language, key)
+			}
+			if strings.Count(text, "%") != strings.Count(english, "%") {
+				t.Fatalf("%s text for %s takes different arguments than English: %q", language, key, text)
+			}
+		}
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	cases := map[string]string{"ES": Spanish, "pt-BR": Portuguese, "pt_PT": Portuguese, "Español": Spanish, "english": English}
+	for input, want := range cases {
+		if got, ok := Normalize(input); !ok || got != want {
+			t.Fatalf("Normalize(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+	if _, ok := Normalize("fr"); ok {
+		t.Fatal("expected unsupported languages rejected")
+	}
+}
+
+func TestTextFollowsTheContextLanguage(t *testing.T) {
+	ctx := WithLanguage(context.Background(), "pt-BR")
+	if got := T(ctx, Usage, "/voice on"); got != "Uso: /voice on" {
+		t.Fatalf("unexpected Portuguese usage %q", got)
+	}
+	if got := T(context.Background(), PendingRunApprove, "act_1"); !strings.HasPrefix(got, "Run `/approve-action act_1`.") {
+		t.Fatalf("unexpected English default %q", got)
+	}
+	if got := LanguageFrom(WithLanguage(context.Background(), "klingon")); got != English {
+		t.Fatalf("expected unsupported languages to keep English, got %q", got)
+	}
+}
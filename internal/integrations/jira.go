@@ -0,0 +1,160 @@
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type JiraConfig struct {
+	BaseURL    string
+	Email      string
+	APIToken   string
+	ProjectKey string
+	IssueType  string
+	Timeout    time.Duration
+}
+
+// Jira mirrors tasks through the Jira Cloud REST API (v2) using basic auth
+// with an account email and API token.
+type Jira struct {
+	baseURL    string
+	authHeader string
+	projectKey string
+	issueType  string
+	httpClient *http.Client
+}
+
+func NewJira(cfg JiraConfig) (*Jira, error) {
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("jira base url is required")
+	}
+	email := strings.TrimSpace(cfg.Email)
+	token := strings.TrimSpace(cfg.APIToken)
+	if email == "" || token == "" {
+		return nil, fmt.Errorf("jira email and api token are required")
+	}
+	projectKey := strings.TrimSpace(cfg.ProjectKey)
+	if projectKey == "" {
+		return nil, fmt.Errorf("jira project key is required")
+	}
+	issueType := strings.TrimSpace(cfg.IssueType)
+	if issueType == "" {
+		issueType = "Task"
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return &Jira{
+		baseURL:    baseURL,
+		authHeader: "Basic " + base64.StdEncoding.EncodeToString([]byte(email+":"+token)),
+		projectKey: projectKey,
+		issueType:  issueType,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (j *Jira) Name() string { return "jira" }
+
+func (j *Jira) CreateIssue(ctx context.Context, input IssueInput) (IssueRef, error) {
+	fields := map[string]any{
+		"project":     map[string]string{"key": j.projectKey},
+		"issuetype":   map[string]string{"name": j.issueType},
+		"summary":     truncateTitle(input.Title),
+		"description": input.Description,
+	}
+	if priority := jiraPriority(input.Priority); priority != "" {
+		fields["priority"] = map[string]string{"name": priority}
+	}
+	if len(input.Labels) > 0 {
+		fields["labels"] = input.Labels
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]any{"fields": fields}, &created); err != nil {
+		return IssueRef{}, err
+	}
+	if strings.TrimSpace(created.Key) == "" {
+		return IssueRef{}, fmt.Errorf("jira create issue returned no key")
+	}
+	return IssueRef{Key: created.Key, URL: j.baseURL + "/browse/" + created.Key}, nil
+}
+
+func (j *Jira) UpdateIssue(ctx context.Context, key string, update IssueUpdate) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(strings.TrimSpace(key))
+	if priority := jiraPriority(update.Priority); priority != "" {
+		body := map[string]any{"fields": map[string]any{"priority": map[string]string{"name": priority}}}
+		if err := j.do(ctx, http.MethodPut, path, body, nil); err != nil {
+			return err
+		}
+	}
+	if comment := strings.TrimSpace(update.Comment); comment != "" {
+		if err := j.do(ctx, http.MethodPost, path+"/comment", map[string]string{"body": comment}, nil); err != nil {
+			return err
+		}
+	}
+	if update.Done {
+		return j.transitionToDone(ctx, path)
+	}
+	return nil
+}
+
+func (j *Jira) transitionToDone(ctx context.Context, path string) error {
+	var listed struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := j.do(ctx, http.MethodGet, path+"/transitions", nil, &listed); err != nil {
+		return err
+	}
+	for _, transition := range listed.Transitions {
+		if transition.To.StatusCategory.Key == "done" {
+			body := map[string]any{"transition": map[string]string{"id": transition.ID}}
+			return j.do(ctx, http.MethodPost, path+"/transitions", body, nil)
+		}
+	}
+	// Some workflows have no direct path to done; the comment still records
+	// the outcome.
+	return nil
+}
+
+func (j *Jira) do(ctx context.Context, method, path string, body any, target any) error {
+	if err := doJSON(ctx, j.httpClient, method, j.baseURL+path, map[string]string{"Authorization": j.authHeader}, body, target); err != nil {
+		return fmt.Errorf("jira: %w", err)
+	}
+	return nil
+}
+
+func jiraPriority(priority string) string {
+	switch strings.ToLower(strings.TrimSpace(priority)) {
+	case "p1":
+		return "High"
+	case "p2":
+		return "Medium"
+	case "p3":
+		return "Low"
+	default:
+		return ""
+	}
+}
+
+func truncateTitle(title string) string {
+	title = strings.TrimSpace(strings.ReplaceAll(title, "\n", " "))
+	if len(title) > 240 {
+		title = strings.TrimSpace(title[:240])
+	}
+	return title
+}
@@ -0,0 +1,173 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultLinearAPIBaseURL = "https://api.linear.app/graphql"
+
+type LinearConfig struct {
+	APIKey  string
+	TeamID  string
+	BaseURL string
+	Timeout time.Duration
+}
+
+// Linear mirrors tasks through the Linear GraphQL API using a personal or
+// workspace API key.
+type Linear struct {
+	apiKey     string
+	teamID     string
+	endpoint   string
+	httpClient *http.Client
+}
+
+func NewLinear(cfg LinearConfig) (*Linear, error) {
+	apiKey := strings.TrimSpace(cfg.APIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("linear api key is required")
+	}
+	teamID := strings.TrimSpace(cfg.TeamID)
+	if teamID == "" {
+		return nil, fmt.Errorf("linear team id is required")
+	}
+	endpoint := strings.TrimSpace(cfg.BaseURL)
+	if endpoint == "" {
+		endpoint = DefaultLinearAPIBaseURL
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return &Linear{
+		apiKey:     apiKey,
+		teamID:     teamID,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (l *Linear) Name() string { return "linear" }
+
+func (l *Linear) CreateIssue(ctx context.Context, input IssueInput) (IssueRef, error) {
+	issueInput := map[string]any{
+		"teamId":      l.teamID,
+		"title":       truncateTitle(input.Title),
+		"description": input.Description,
+	}
+	if priority := linearPriority(input.Priority); priority > 0 {
+		issueInput["priority"] = priority
+	}
+	var data struct {
+		IssueCreate struct {
+			Success bool `json:"success"`
+			Issue   struct {
+				Identifier string `json:"identifier"`
+				URL        string `json:"url"`
+			} `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	query := `mutation($input: IssueCreateInput!) { issueCreate(input: $input) { success issue { identifier url } } }`
+	if err := l.graphQL(ctx, query, map[string]any{"input": issueInput}, &data); err != nil {
+		return IssueRef{}, err
+	}
+	if !data.IssueCreate.Success || data.IssueCreate.Issue.Identifier == "" {
+		return IssueRef{}, fmt.Errorf("linear issue create was not successful")
+	}
+	return IssueRef{Key: data.IssueCreate.Issue.Identifier, URL: data.IssueCreate.Issue.URL}, nil
+}
+
+func (l *Linear) UpdateIssue(ctx context.Context, key string, update IssueUpdate) error {
+	key = strings.TrimSpace(key)
+	issueInput := map[string]any{}
+	if priority := linearPriority(update.Priority); priority > 0 {
+		issueInput["priority"] = priority
+	}
+	if update.Done {
+		stateID, err := l.completedStateID(ctx)
+		if err != nil {
+			return err
+		}
+		if stateID != "" {
+			issueInput["stateId"] = stateID
+		}
+	}
+	if len(issueInput) > 0 {
+		query := `mutation($id: String!, $input: IssueUpdateInput!) { issueUpdate(id: $id, input: $input) { success } }`
+		if err := l.graphQL(ctx, query, map[string]any{"id": key, "input": issueInput}, nil); err != nil {
+			return err
+		}
+	}
+	if comment := strings.TrimSpace(update.Comment); comment != "" {
+		query := `mutation($input: CommentCreateInput!) { commentCreate(input: $input) { success } }`
+		if err := l.graphQL(ctx, query, map[string]any{"input": map[string]any{"issueId": key, "body": comment}}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Linear) completedStateID(ctx context.Context) (string, error) {
+	var data struct {
+		Team struct {
+			States struct {
+				Nodes []struct {
+					ID   string `json:"id"`
+					Type string `json:"type"`
+				} `json:"nodes"`
+			} `json:"states"`
+		} `json:"team"`
+	}
+	query := `query($id: String!) { team(id: $id) { states { nodes { id type } } } }`
+	if err := l.graphQL(ctx, query, map[string]any{"id": l.teamID}, &data); err != nil {
+		return "", err
+	}
+	for _, state := range data.Team.States.Nodes {
+		if state.Type == "completed" {
+			return state.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (l *Linear) graphQL(ctx context.Context, query string, variables map[string]any, target any) error {
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	body := map[string]any{"query": query, "variables": variables}
+	if err := doJSON(ctx, l.httpClient, http.MethodPost, l.endpoint, map[string]string{"Authorization": l.apiKey}, body, &envelope); err != nil {
+		return fmt.Errorf("linear: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("linear: %s", envelope.Errors[0].Message)
+	}
+	if target == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, target); err != nil {
+		return fmt.Errorf("linear: decode response: %w", err)
+	}
+	return nil
+}
+
+func linearPriority(priority string) int {
+	// Linear priorities: 1 urgent, 2 high, 3 medium, 4 low.
+	switch strings.ToLower(strings.TrimSpace(priority)) {
+	case "p1":
+		return 2
+	case "p2":
+		return 3
+	case "p3":
+		return 4
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,129 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const syncTimeout = 20 * time.Second
+
+type TaskStore interface {
+	LookupTask(ctx context.Context, id string) (store.TaskRecord, error)
+	SetTaskExternalRef(ctx context.Context, input store.SetTaskExternalRefInput) (store.TaskRecord, error)
+}
+
+// Syncer mirrors tasks routed as issues to a tracker and pushes later
+// routing and completion changes to the linked issue.
+type Syncer struct {
+	store   TaskStore
+	tracker Tracker
+}
+
+func NewSyncer(store TaskStore, tracker Tracker) *Syncer {
+	return &Syncer{store: store, tracker: tracker}
+}
+
+// SyncTask brings the external issue for taskID up to date. Tasks that are
+// not routed as issues and have no linked issue are ignored.
+func (s *Syncer) SyncTask(ctx context.Context, taskID string) error {
+	if s == nil || s.store == nil || s.tracker == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, syncTimeout)
+	defer cancel()
+	task, err := s.store.LookupTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(task.ExternalKey) == "" {
+		if task.RouteClass != "issue" || task.Status == "succeeded" || task.Status == "failed" || task.Status == "cancelled" {
+			return nil
+		}
+		return s.create(ctx, task)
+	}
+	if task.ExternalSystem != s.tracker.Name() {
+		// Linked to a tracker that is no longer configured.
+		return nil
+	}
+	return s.tracker.UpdateIssue(ctx, task.ExternalKey, buildIssueUpdate(task))
+}
+
+func (s *Syncer) create(ctx context.Context, task store.TaskRecord) error {
+	ref, err := s.tracker.CreateIssue(ctx, IssueInput{
+		Title:       task.Title,
+		Description: buildIssueDescription(task),
+		Priority:    task.Priority,
+		Labels:      issueLabels(task),
+	})
+	if err != nil {
+		return fmt.Errorf("create %s issue for task %s: %w", s.tracker.Name(), task.ID, err)
+	}
+	if _, err := s.store.SetTaskExternalRef(ctx, store.SetTaskExternalRefInput{
+		ID:     task.ID,
+		System: s.tracker.Name(),
+		Key:    ref.Key,
+		URL:    ref.URL,
+	}); err != nil {
+		return fmt.Errorf("record external issue %s for task %s: %w", ref.Key, task.ID, err)
+	}
+	return nil
+}
+
+func buildIssueUpdate(task store.TaskRecord) IssueUpdate {
+	switch task.Status {
+	case "succeeded":
+		comment := "Task completed by agent-runtime."
+		if summary := strings.TrimSpace(task.ResultSummary); summary != "" {
+			comment += "\n\n" + summary
+		}
+		return IssueUpdate{Comment: comment, Done: true}
+	case "failed":
+		comment := "Task failed in agent-runtime."
+		if message := strings.TrimSpace(task.ErrorMessage); message != "" {
+			comment += "\n\n" + message
+		}
+		return IssueUpdate{Comment: comment}
+	}
+	lines := []string{"Routing updated in agent-runtime:", "- class: " + valueOrNone(task.RouteClass), "- priority: " + valueOrNone(task.Priority), "- lane: " + valueOrNone(task.AssignedLane)}
+	if !task.DueAt.IsZero() {
+		lines = append(lines, "- due: "+task.DueAt.UTC().Format(time.RFC3339))
+	}
+	return IssueUpdate{Priority: task.Priority, Comment: strings.Join(lines, "\n")}
+}
+
+func buildIssueDescription(task store.TaskRecord) string {
+	lines := []string{}
+	if text := strings.TrimSpace(task.SourceText); text != "" {
+		lines = append(lines, text, "")
+	}
+	lines = append(lines, "Mirrored from agent-runtime task "+task.ID+".")
+	if connector := strings.TrimSpace(task.SourceConnector); connector != "" {
+		lines = append(lines, "Source: "+connector)
+	}
+	if lane := strings.TrimSpace(task.AssignedLane); lane != "" {
+		lines = append(lines, "Lane: "+lane)
+	}
+	if !task.DueAt.IsZero() {
+		lines = append(lines, "Due: "+task.DueAt.UTC().Format(time.RFC3339))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func issueLabels(task store.TaskRecord) []string {
+	labels := []string{"agent-runtime"}
+	if lane := strings.TrimSpace(task.AssignedLane); lane != "" {
+		labels = append(labels, strings.ReplaceAll(lane, " ", "-"))
+	}
+	return labels
+}
+
+func valueOrNone(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "(none)"
+	}
+	return value
+}
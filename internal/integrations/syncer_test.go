@@ -0,0 +1,98 @@
+package integrations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeTaskStore struct {
+	tasks map[string]store.TaskRecord
+}
+
+func (f *fakeTaskStore) LookupTask(ctx context.Context, id string) (store.TaskRecord, error) {
+	task, ok := f.tasks[id]
+	if !ok {
+		return store.TaskRecord{}, store.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (f *fakeTaskStore) SetTaskExternalRef(ctx context.Context, input store.SetTaskExternalRefInput) (store.TaskRecord, error) {
+	task := f.tasks[input.ID]
+	task.ExternalSystem, task.ExternalKey, task.ExternalURL = input.System, input.Key, input.URL
+	f.tasks[input.ID] = task
+	return task, nil
+}
+
+type fakeTracker struct {
+	created []IssueInput
+	updates map[string][]IssueUpdate
+}
+
+func (f *fakeTracker) Name() string { return "jira" }
+
+func (f *fakeTracker) CreateIssue(ctx context.Context, input IssueInput) (IssueRef, error) {
+	f.created = append(f.created, input)
+	return IssueRef{Key: "OPS-1", URL: "https://jira/browse/OPS-1"}, nil
+}
+
+func (f *fakeTracker) UpdateIssue(ctx context.Context, key string, update IssueUpdate) error {
+	if f.updates == nil {
+		f.updates = map[string][]IssueUpdate{}
+	}
+	f.updates[key] = append(f.updates[key], update)
+	return nil
+}
+
+func TestSyncerMirrorsIssueTasksAndPushesUpdates(t *testing.T) {
+	taskStore := &fakeTaskStore{tasks: map[string]store.TaskRecord{
+		"task-issue":    {ID: "task-issue", Title: "Login broken", Status: "queued", RouteClass: "issue", Priority: "p1", SourceText: "cannot log in", AssignedLane: "support"},
+		"task-question": {ID: "task-question", Title: "How?", Status: "queued", RouteClass: "question"},
+	}}
+	tracker := &fakeTracker{}
+	syncer := NewSyncer(taskStore, tracker)
+	ctx := context.Background()
+
+	if err := syncer.SyncTask(ctx, "task-question"); err != nil {
+		t.Fatalf("sync question: %v", err)
+	}
+	if len(tracker.created) != 0 {
+		t.Fatal("questions must not be mirrored")
+	}
+
+	if err := syncer.SyncTask(ctx, "task-issue"); err != nil {
+		t.Fatalf("sync issue: %v", err)
+	}
+	if len(tracker.created) != 1 || !strings.Contains(tracker.created[0].Description, "cannot log in") || tracker.created[0].Priority != "p1" {
+		t.Fatalf("unexpected created issues %+v", tracker.created)
+	}
+	if task := taskStore.tasks["task-issue"]; task.ExternalKey != "OPS-1" || task.ExternalSystem != "jira" {
+		t.Fatalf("expected external ref written back, got %+v", task)
+	}
+
+	task := taskStore.tasks["task-issue"]
+	task.Priority = "p2"
+	taskStore.tasks["task-issue"] = task
+	if err := syncer.SyncTask(ctx, "task-issue"); err != nil {
+		t.Fatalf("sync routing: %v", err)
+	}
+	task.Status = "succeeded"
+	task.ResultSummary = "Reset the session store."
+	taskStore.tasks["task-issue"] = task
+	if err := syncer.SyncTask(ctx, "task-issue"); err != nil {
+		t.Fatalf("sync completion: %v", err)
+	}
+	if len(tracker.created) != 1 {
+		t.Fatal("linked task must not create a second issue")
+	}
+	updates := tracker.updates["OPS-1"]
+	if len(updates) != 2 || updates[0].Priority != "p2" || updates[0].Done {
+		t.Fatalf("unexpected routing update %+v", updates)
+	}
+	if !updates[1].Done || !strings.Contains(updates[1].Comment, "Reset the session store.") {
+		t.Fatalf("unexpected completion update %+v", updates[1])
+	}
+}
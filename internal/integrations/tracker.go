@@ -0,0 +1,130 @@
+// Package integrations mirrors routed tasks into external issue trackers
+// such as Jira and Linear.
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const maxResponseBytes = 1 << 20
+
+var ErrUnknownProvider = errors.New("unknown issue sync provider")
+
+// Tracker is an external issue tracker that routed tasks are mirrored to.
+type Tracker interface {
+	Name() string
+	CreateIssue(ctx context.Context, input IssueInput) (IssueRef, error)
+	UpdateIssue(ctx context.Context, key string, update IssueUpdate) error
+}
+
+type IssueInput struct {
+	Title       string
+	Description string
+	Priority    string
+	Labels      []string
+}
+
+type IssueRef struct {
+	Key string
+	URL string
+}
+
+// IssueUpdate carries the fields pushed to an existing issue. Empty fields
+// are left untouched.
+type IssueUpdate struct {
+	Priority string
+	Comment  string
+	Done     bool
+}
+
+type Config struct {
+	Provider         string
+	JiraBaseURL      string
+	JiraEmail        string
+	JiraAPIToken     string
+	JiraProjectKey   string
+	JiraIssueType    string
+	LinearAPIKey     string
+	LinearTeamID     string
+	LinearAPIBaseURL string
+}
+
+// NewTracker builds the tracker selected by cfg.Provider. It returns a nil
+// tracker when no provider is configured.
+func NewTracker(cfg Config) (Tracker, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "":
+		return nil, nil
+	case "jira":
+		return NewJira(JiraConfig{
+			BaseURL:    cfg.JiraBaseURL,
+			Email:      cfg.JiraEmail,
+			APIToken:   cfg.JiraAPIToken,
+			ProjectKey: cfg.JiraProjectKey,
+			IssueType:  cfg.JiraIssueType,
+		})
+	case "linear":
+		return NewLinear(LinearConfig{
+			APIKey:  cfg.LinearAPIKey,
+			TeamID:  cfg.LinearTeamID,
+			BaseURL: cfg.LinearAPIBaseURL,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, cfg.Provider)
+	}
+}
+
+func doJSON(ctx context.Context, client *http.Client, method, url string, headers map[string]string, body any, target any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(content))
+		if len(message) > 300 {
+			message = message[:300]
+		}
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return fmt.Errorf("%s %s: %d %s", method, url, resp.StatusCode, message)
+	}
+	if target == nil || len(bytes.TrimSpace(content)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(content, target); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJiraCreateAndCompleteIssue(t *testing.T) {
+	calls := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if user, pass, ok := r.BasicAuth(); !ok || user != "bot@example.com" || pass != "secret" {
+			t.Errorf("unexpected auth %q %q", user, pass)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "POST /rest/api/2/issue":
+			var body struct {
+				Fields map[string]any `json:"fields"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.Fields["summary"] != "Login is broken" {
+				t.Errorf("unexpected summary %v", body.Fields["summary"])
+			}
+			if priority, _ := body.Fields["priority"].(map[string]any); priority["name"] != "High" {
+				t.Errorf("unexpected priority %v", body.Fields["priority"])
+			}
+			_, _ = w.Write([]byte(`{"key":"OPS-7"}`))
+		case "GET /rest/api/2/issue/OPS-7/transitions":
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"11","to":{"statusCategory":{"key":"indeterminate"}}},{"id":"31","to":{"statusCategory":{"key":"done"}}}]}`))
+		case "POST /rest/api/2/issue/OPS-7/transitions":
+			var body struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.Transition.ID != "31" {
+				t.Errorf("expected done transition, got %q", body.Transition.ID)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	tracker, err := NewTracker(Config{Provider: "jira", JiraBaseURL: server.URL, JiraEmail: "bot@example.com", JiraAPIToken: "secret", JiraProjectKey: "OPS"})
+	if err != nil {
+		t.Fatalf("new tracker: %v", err)
+	}
+	ref, err := tracker.CreateIssue(context.Background(), IssueInput{Title: "Login is broken", Priority: "p1"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if ref.Key != "OPS-7" || ref.URL != server.URL+"/browse/OPS-7" {
+		t.Fatalf("unexpected ref %+v", ref)
+	}
+	if err := tracker.UpdateIssue(context.Background(), "OPS-7", IssueUpdate{Comment: "done", Done: true}); err != nil {
+		t.Fatalf("update issue: %v", err)
+	}
+	want := "POST /rest/api/2/issue,POST /rest/api/2/issue/OPS-7/comment,GET /rest/api/2/issue/OPS-7/transitions,POST /rest/api/2/issue/OPS-7/transitions"
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("unexpected calls:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLinearCreateAndUpdateIssue(t *testing.T) {
+	operations := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_key" {
+			t.Errorf("unexpected auth %q", r.Header.Get("Authorization"))
+		}
+		var body struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case strings.Contains(body.Query, "issueCreate"):
+			operations = append(operations, "create")
+			input, _ := body.Variables["input"].(map[string]any)
+			if input["teamId"] != "team-1" || input["priority"] != float64(3) {
+				t.Errorf("unexpected create input %v", input)
+			}
+			_, _ = w.Write([]byte(`{"data":{"issueCreate":{"success":true,"issue":{"identifier":"ENG-4","url":"https://linear.app/x/issue/ENG-4"}}}}`))
+		case strings.Contains(body.Query, "team("):
+			operations = append(operations, "states")
+			_, _ = w.Write([]byte(`{"data":{"team":{"states":{"nodes":[{"id":"s-started","type":"started"},{"id":"s-done","type":"completed"}]}}}}`))
+		case strings.Contains(body.Query, "issueUpdate"):
+			operations = append(operations, "update")
+			input, _ := body.Variables["input"].(map[string]any)
+			if body.Variables["id"] != "ENG-4" || input["stateId"] != "s-done" {
+				t.Errorf("unexpected update variables %v", body.Variables)
+			}
+			_, _ = w.Write([]byte(`{"data":{"issueUpdate":{"success":true}}}`))
+		case strings.Contains(body.Query, "commentCreate"):
+			operations = append(operations, "comment")
+			_, _ = w.Write([]byte(`{"errors":[{"message":"comment rejected"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	tracker, err := NewTracker(Config{Provider: "linear", LinearAPIKey: "lin_key", LinearTeamID: "team-1", LinearAPIBaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new tracker: %v", err)
+	}
+	ref, err := tracker.CreateIssue(context.Background(), IssueInput{Title: "Slow search", Priority: "p2"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if ref.Key != "ENG-4" {
+		t.Fatalf("unexpected ref %+v", ref)
+	}
+	err = tracker.UpdateIssue(context.Background(), "ENG-4", IssueUpdate{Comment: "shipped", Done: true})
+	if err == nil || !strings.Contains(err.Error(), "comment rejected") {
+		t.Fatalf("expected graphql error to surface, got %v", err)
+	}
+	if got := strings.Join(operations, ","); got != "create,states,update,comment" {
+		t.Fatalf("unexpected operations %s", got)
+	}
+}
+
+func TestNewTrackerValidatesConfig(t *testing.T) {
+	if tracker, err := NewTracker(Config{}); tracker != nil || err != nil {
+		t.Fatalf("expected no tracker without provider, got %v %v", tracker, err)
+	}
+	if _, err := NewTracker(Config{Provider: "asana"}); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("expected unknown provider, got %v", err)
+	}
+	if _, err := NewTracker(Config{Provider: "jira", JiraBaseURL: "https://x.atlassian.net"}); err == nil {
+		t.Fatal("expected missing jira credentials to fail")
+	}
+	if _, err := NewTracker(Config{Provider: "linear", LinearAPIKey: "k"}); err == nil {
+		t.Fatal("expected missing linear team to fail")
+	}
+}
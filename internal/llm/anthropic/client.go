@@ -3,6 +3,7 @@ package anthropic
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -20,6 +21,10 @@ type Config struct {
 	Model        string
 	Timeout      time.Duration
 	SystemPrompt string
+	// Vision sends message images to the model as image blocks.
+	Vision bool
+	// Usage, when set, receives the token usage of every message call.
+	Usage llm.UsageRecorder
 }
 
 type Client struct {
@@ -67,13 +72,13 @@ func (c *Client) Reply(ctx context.Context, input llm.MessageInput) (string, err
 	userContent := fmt.Sprintf("User: %s (%s)\n%s", input.DisplayName, input.FromUserID, input.Text)
 
 	payload := map[string]any{
-		"model":      c.cfg.Model,
+		"model":      c.model(ctx),
 		"max_tokens": 4096,
 		"system":     systemPrompt,
-		"messages": []map[string]string{
+		"messages": []map[string]any{
 			{
 				"role":    "user",
-				"content": userContent,
+				"content": c.userMessageContent(userContent, input.Images),
 			},
 		},
 	}
@@ -112,6 +117,13 @@ func (c *Client) Reply(ctx context.Context, input llm.MessageInput) (string, err
 	if err := json.Unmarshal(respBody, &response); err != nil {
 		return "", fmt.Errorf("decode anthropic response: %w", err)
 	}
+	if c.cfg.Usage != nil {
+		c.cfg.Usage.RecordUsage(ctx, llm.Usage{
+			WorkspaceID:  input.WorkspaceID,
+			InputTokens:  response.Usage.InputTokens,
+			OutputTokens: response.Usage.OutputTokens,
+		})
+	}
 
 	if len(response.Content) == 0 {
 		return "", nil
@@ -128,9 +140,44 @@ func (c *Client) Reply(ctx context.Context, input llm.MessageInput) (string, err
 	return "", fmt.Errorf("no text content in response")
 }
 
+// userMessageContent is the plain prompt, or image blocks followed by the
+// text when the message carries images and the model can view them.
+func (c *Client) userMessageContent(prompt string, images []llm.Image) any {
+	if len(images) == 0 {
+		return prompt
+	}
+	if !c.cfg.Vision {
+		return prompt + "\n\n" + llm.ImagesNotice(images)
+	}
+	blocks := make([]map[string]any, 0, len(images)+1)
+	for _, image := range images {
+		blocks = append(blocks, map[string]any{
+			"type": "image",
+			"source": map[string]string{
+				"type":       "base64",
+				"media_type": image.MediaType,
+				"data":       base64.StdEncoding.EncodeToString(image.Data),
+			},
+		})
+	}
+	return append(blocks, map[string]any{"type": "text", "text": prompt})
+}
+
 type messagesResponse struct {
 	Content []struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// model is the configured model unless the turn carries a variant override.
+func (c *Client) model(ctx context.Context) string {
+	if model := strings.TrimSpace(llm.VariantFrom(ctx).Model); model != "" {
+		return model
+	}
+	return c.cfg.Model
 }
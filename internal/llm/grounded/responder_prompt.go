@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/memorylog"
 	"github.com/dwizi/agent-runtime/internal/qmd"
 )
 
@@ -32,6 +33,12 @@ func (r *Responder) Reply(ctx context.Context, input llm.MessageInput) (string,
 		"used_tail", metrics.UsedTail,
 		"used_qmd", metrics.UsedQMD,
 		"qmd_results", metrics.QMDResultCount,
+		"retrieval_mode", metrics.RetrievalMode,
+		"lexical_results", metrics.LexicalResultCount,
+		"vector_results", metrics.VectorResultCount,
+		"retrieval_candidates", metrics.CandidateCount,
+		"reranked", metrics.Reranked,
+		"rerank_ms", metrics.RerankMs,
 		"tokens_user", metrics.UserTokens,
 		"tokens_summary", metrics.SummaryTokens,
 		"tokens_tail", metrics.TailTokens,
@@ -97,7 +104,7 @@ func (r *Responder) buildPrompt(ctx context.Context, input llm.MessageInput) (st
 	}
 
 	if useQMD {
-		qmdContext, resultCount := r.buildQMDContext(ctx, input, original, budget.QMD)
+		qmdContext, resultCount := r.buildQMDContext(ctx, input, original, budget.QMD, &metrics)
 		if qmdContext != "" {
 			sections = append(sections, "", "Relevant workspace context:", qmdContext)
 			metrics.UsedQMD = true
@@ -161,11 +168,11 @@ func shouldIncludeQMDRetrieval(decision MemoryDecision) bool {
 	return decision.Strategy == StrategyQMD
 }
 
-func (r *Responder) buildQMDContext(ctx context.Context, input llm.MessageInput, query string, tokenBudget int) (string, int) {
+func (r *Responder) buildQMDContext(ctx context.Context, input llm.MessageInput, query string, tokenBudget int, metrics *PromptMetrics) (string, int) {
 	if tokenBudget < 1 {
 		return "", 0
 	}
-	results, err := r.retriever.Search(ctx, input.WorkspaceID, query, r.cfg.TopK)
+	results, err := r.retrieve(ctx, input.WorkspaceID, query, metrics)
 	if err != nil {
 		if !errors.Is(err, qmd.ErrUnavailable) {
 			r.logger.Error("qmd grounding search failed", "error", err, "workspace_id", input.WorkspaceID)
@@ -199,7 +206,11 @@ func (r *Responder) buildQMDContext(ctx context.Context, input llm.MessageInput,
 		if excerpt == "" {
 			continue
 		}
-		lines := []string{fmt.Sprintf("- source: %s", target)}
+		source := target
+		if qmd.IsSharedTarget(target) {
+			source += " (shared knowledge workspace)"
+		}
+		lines := []string{fmt.Sprintf("- source: %s", source)}
 		if snippet != "" {
 			lines = append(lines, "  snippet: "+snippet)
 		}
@@ -216,15 +227,29 @@ func (r *Responder) buildQMDContext(ctx context.Context, input llm.MessageInput,
 	return clipToTokenBudget(strings.Join(blocks, "\n"), tokenBudget), used
 }
 
+// compactedSummarySections is how many rolling summary sections of a
+// compacted chat are offered alongside the live summary.
+const compactedSummarySections = 3
+
 func (r *Responder) loadConversationMemory(ctx context.Context, input llm.MessageInput, budget tokenBudget) (string, string, summaryMetadata) {
+	// Older turns moved out of the live log by memory compaction survive
+	// as a rolling summary; it goes after the live summary so clipping
+	// drops the oldest memory first.
+	compacted := memorylog.ReadSummary(r.cfg.WorkspaceRoot, input.WorkspaceID, input.Connector, conversationID(input), compactedSummarySections)
 	content := r.loadChatLogContent(ctx, input)
 	if strings.TrimSpace(content) == "" {
-		return "", "", summaryMetadata{}
+		if compacted == "" {
+			return "", "", summaryMetadata{}
+		}
+		return clipToTokenBudget("Earlier conversation:\n"+compacted, budget.Summary), "", summaryMetadata{}
 	}
 
 	turns := countInboundTurns(content)
 	sourceLines := countSummarySourceLines(content)
 	summaryText, meta := r.loadOrRefreshSummary(input, content, turns, sourceLines)
+	if compacted != "" {
+		summaryText = strings.TrimSpace(summaryText + "\n\nEarlier conversation:\n" + compacted)
+	}
 	summaryText = clipToTokenBudget(summaryText, budget.Summary)
 
 	tailBytes := r.cfg.ChatTailBytes
@@ -238,7 +263,7 @@ func (r *Responder) loadConversationMemory(ctx context.Context, input llm.Messag
 }
 
 func (r *Responder) loadChatLogContent(ctx context.Context, input llm.MessageInput) string {
-	target := chatLogTarget(input.Connector, input.ExternalID)
+	target := chatLogTarget(input.Connector, conversationID(input))
 	if strings.TrimSpace(target) == "" {
 		return ""
 	}
@@ -280,6 +305,10 @@ func (r *Responder) loadOrRefreshSummary(input llm.MessageInput, chatLogContent
 	if currentTurns > 0 {
 		if existingTurns == 0 {
 			needsRefresh = true
+		} else if currentTurns < existingTurns {
+			// The live log shrank, so it has been compacted since the
+			// summary was written.
+			needsRefresh = true
 		} else if currentTurns >= existingTurns+refreshEvery {
 			needsRefresh = true
 		}
@@ -330,9 +359,9 @@ func (r *Responder) contextSummaryPath(input llm.MessageInput) string {
 	if root == "" || workspaceID == "" {
 		return ""
 	}
-	key := sanitizeSummaryKey(input.ContextID)
+	key := sanitizeSummaryKey(memorylog.ConversationID(input.ContextID, input.ThreadID))
 	if key == "" {
-		key = sanitizeSummaryKey(strings.ToLower(strings.TrimSpace(input.Connector)) + "-" + strings.TrimSpace(input.ExternalID))
+		key = sanitizeSummaryKey(strings.ToLower(strings.TrimSpace(input.Connector)) + "-" + conversationID(input))
 	}
 	if key == "" {
 		return ""
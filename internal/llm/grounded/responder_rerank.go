@@ -0,0 +1,72 @@
+package grounded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/qmd"
+)
+
+var rerankNumberPattern = regexp.MustCompile(`\d+`)
+
+// LLMReranker orders retrieval candidates by asking a model to rank them. It
+// stands in for a cross-encoder: one extra model call per grounded turn in
+// exchange for ranking on meaning rather than term overlap.
+type LLMReranker struct {
+	base llm.Responder
+}
+
+func NewLLMReranker(base llm.Responder) *LLMReranker {
+	return &LLMReranker{base: base}
+}
+
+func (r *LLMReranker) Rerank(ctx context.Context, workspaceID, query string, candidates []qmd.SearchResult) ([]int, error) {
+	if r.base == nil {
+		return nil, fmt.Errorf("%w: rerank responder missing", llm.ErrUnavailable)
+	}
+	lines := []string{
+		"Rank the passages by how useful they are for answering the question.",
+		"Reply with passage numbers only, most useful first, separated by commas. Leave out passages that do not help.",
+		"",
+		"Question: " + clipToTokenBudget(compactWhitespace(query), 200),
+		"",
+	}
+	for index, candidate := range candidates {
+		lines = append(lines, fmt.Sprintf("[%d] %s", index+1, searchResultKey(candidate)))
+		if snippet := clipToTokenBudget(compactWhitespace(candidate.Snippet), 80); snippet != "" {
+			lines = append(lines, snippet)
+		}
+	}
+	reply, err := r.base.Reply(ctx, llm.MessageInput{
+		WorkspaceID:   workspaceID,
+		Text:          strings.Join(lines, "\n"),
+		SystemPrompt:  "You rank search results. Output only passage numbers.",
+		SkipGrounding: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseRerankReply(reply, len(candidates))
+}
+
+func parseRerankReply(reply string, candidates int) ([]int, error) {
+	order := []int{}
+	seen := map[int]bool{}
+	for _, match := range rerankNumberPattern.FindAllString(reply, -1) {
+		number, err := strconv.Atoi(match)
+		if err != nil || number < 1 || number > candidates || seen[number] {
+			continue
+		}
+		seen[number] = true
+		order = append(order, number-1)
+	}
+	if len(order) == 0 {
+		return nil, errors.New("rerank reply named no candidates")
+	}
+	return order, nil
+}
@@ -0,0 +1,128 @@
+package grounded
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/qmd"
+)
+
+// rrfK damps reciprocal rank fusion so that a document ranked first by one
+// retriever does not drown out one ranked well by both.
+const rrfK = 60
+
+// VectorRetriever is implemented by retrievers that can also rank documents
+// by embedding similarity. Hybrid retrieval uses it when it is available.
+type VectorRetriever interface {
+	VectorSearch(ctx context.Context, workspaceID, query string, limit int) ([]qmd.SearchResult, error)
+}
+
+// Reranker orders retrieval candidates by relevance to the query. It returns
+// candidate indexes, most relevant first; candidates it leaves out are
+// dropped.
+type Reranker interface {
+	Rerank(ctx context.Context, workspaceID, query string, candidates []qmd.SearchResult) ([]int, error)
+}
+
+// retrieve collects grounding candidates. Lexical qmd results are always
+// used; with hybrid retrieval they are fused with embedding results, and a
+// configured reranker orders the fused pool before it is cut to TopK. Vector
+// and rerank failures fall back to the previous stage instead of failing.
+func (r *Responder) retrieve(ctx context.Context, workspaceID, query string, metrics *PromptMetrics) ([]qmd.SearchResult, error) {
+	poolSize := r.cfg.TopK
+	if r.cfg.HybridRetrieval || r.cfg.Reranker != nil {
+		poolSize = maxInt(r.cfg.TopK, r.cfg.RerankCandidates)
+	}
+	lexical, err := r.retriever.Search(ctx, workspaceID, query, poolSize)
+	if err != nil {
+		return nil, err
+	}
+	metrics.RetrievalMode = "lexical"
+	metrics.LexicalResultCount = len(lexical)
+	candidates := lexical
+
+	if r.cfg.HybridRetrieval {
+		if vectorRetriever, ok := r.retriever.(VectorRetriever); ok {
+			vector, err := vectorRetriever.VectorSearch(ctx, workspaceID, query, poolSize)
+			if err != nil {
+				r.logger.Debug("vector grounding search failed; using lexical results", "workspace_id", workspaceID, "error", err)
+			} else {
+				metrics.RetrievalMode = "hybrid"
+				metrics.VectorResultCount = len(vector)
+				candidates = fuseSearchResults(lexical, vector)
+			}
+		}
+	}
+	metrics.CandidateCount = len(candidates)
+
+	if r.cfg.Reranker != nil && len(candidates) > 1 {
+		started := time.Now()
+		order, err := r.cfg.Reranker.Rerank(ctx, workspaceID, query, candidates)
+		metrics.RerankMs = time.Since(started).Milliseconds()
+		if err != nil {
+			r.logger.Debug("grounding rerank failed; keeping retrieval order", "workspace_id", workspaceID, "error", err)
+		} else {
+			candidates = applyRerankOrder(candidates, order)
+			metrics.Reranked = true
+		}
+	}
+
+	if len(candidates) > r.cfg.TopK {
+		candidates = candidates[:r.cfg.TopK]
+	}
+	return candidates, nil
+}
+
+// fuseSearchResults merges ranked lists with reciprocal rank fusion. Scores
+// from qmd's BM25 and vector search are not comparable, ranks are.
+func fuseSearchResults(lists ...[]qmd.SearchResult) []qmd.SearchResult {
+	scores := map[string]float64{}
+	merged := []qmd.SearchResult{}
+	index := map[string]int{}
+	for _, list := range lists {
+		for rank, result := range list {
+			key := searchResultKey(result)
+			if key == "" {
+				continue
+			}
+			scores[key] += 1 / float64(rrfK+rank+1)
+			if position, ok := index[key]; ok {
+				if merged[position].Snippet == "" {
+					merged[position].Snippet = result.Snippet
+				}
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, result)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return scores[searchResultKey(merged[i])] > scores[searchResultKey(merged[j])]
+	})
+	return merged
+}
+
+func searchResultKey(result qmd.SearchResult) string {
+	if path := strings.TrimSpace(result.Path); path != "" {
+		return path
+	}
+	return strings.TrimSpace(result.DocID)
+}
+
+func applyRerankOrder(candidates []qmd.SearchResult, order []int) []qmd.SearchResult {
+	seen := map[int]bool{}
+	ordered := make([]qmd.SearchResult, 0, len(order))
+	for _, position := range order {
+		if position < 0 || position >= len(candidates) || seen[position] {
+			continue
+		}
+		seen[position] = true
+		ordered = append(ordered, candidates[position])
+	}
+	if len(ordered) == 0 {
+		return candidates
+	}
+	return ordered
+}
@@ -82,6 +82,35 @@ func TestReplyAddsGroundingContext(t *testing.T) {
 	}
 }
 
+func TestReplyLabelsSharedKnowledgeSources(t *testing.T) {
+	base := &fakeBase{reply: "ok"}
+	retriever := &fakeRetriever{
+		searchResults: []qmd.SearchResult{
+			{Path: "notes/deploy.md", Snippet: "own notes"},
+			{Path: "shared:docs/deploy.md", Snippet: "company runbook"},
+		},
+		openByTarget: map[string]string{
+			"notes/deploy.md":       "workspace deploy notes",
+			"shared:docs/deploy.md": "company deploy runbook",
+		},
+	}
+	responder := New(base, retriever, Config{TopK: 2}, nil)
+
+	_, err := responder.Reply(context.Background(), llm.MessageInput{
+		WorkspaceID: "ws-1",
+		Text:        "find workspace deploy runbook summary",
+	})
+	if err != nil {
+		t.Fatalf("reply failed: %v", err)
+	}
+	if !strings.Contains(base.lastInput.Text, "- source: shared:docs/deploy.md (shared knowledge workspace)") {
+		t.Fatalf("expected shared source to be labeled, got %s", base.lastInput.Text)
+	}
+	if !strings.Contains(base.lastInput.Text, "- source: notes/deploy.md\n") {
+		t.Fatalf("expected own source to stay unlabeled, got %s", base.lastInput.Text)
+	}
+}
+
 func TestReplyFallsBackOnSearchError(t *testing.T) {
 	base := &fakeBase{reply: "ok"}
 	retriever := &fakeRetriever{
@@ -101,6 +130,120 @@ func TestReplyFallsBackOnSearchError(t *testing.T) {
 	}
 }
 
+type fakeVectorRetriever struct {
+	fakeRetriever
+	vectorResults []qmd.SearchResult
+	vectorErr     error
+	vectorLimit   int
+}
+
+func (f *fakeVectorRetriever) VectorSearch(ctx context.Context, workspaceID, query string, limit int) ([]qmd.SearchResult, error) {
+	f.vectorLimit = limit
+	if f.vectorErr != nil {
+		return nil, f.vectorErr
+	}
+	return f.vectorResults, nil
+}
+
+type fakeReranker struct {
+	order      []int
+	err        error
+	candidates []qmd.SearchResult
+}
+
+func (f *fakeReranker) Rerank(ctx context.Context, workspaceID, query string, candidates []qmd.SearchResult) ([]int, error) {
+	f.candidates = candidates
+	return f.order, f.err
+}
+
+func TestBuildPromptFusesLexicalAndVectorResults(t *testing.T) {
+	retriever := &fakeVectorRetriever{
+		fakeRetriever: fakeRetriever{
+			searchResults: []qmd.SearchResult{{Path: "lexical-only.md"}, {Path: "both.md"}},
+			openByTarget: map[string]string{
+				"lexical-only.md": "keyword match",
+				"both.md":         "strong match",
+				"vector-only.md":  "semantic match",
+			},
+		},
+		vectorResults: []qmd.SearchResult{{Path: "both.md", Snippet: "vector snippet"}, {Path: "vector-only.md"}},
+	}
+	responder := New(&fakeBase{}, retriever, Config{TopK: 2, HybridRetrieval: true, RerankCandidates: 6}, nil)
+
+	prompt, metrics := responder.buildPrompt(context.Background(), llm.MessageInput{
+		WorkspaceID: "ws-1",
+		Text:        "find workspace change summary",
+	})
+	if metrics.RetrievalMode != "hybrid" || metrics.LexicalResultCount != 2 || metrics.VectorResultCount != 2 || metrics.CandidateCount != 3 {
+		t.Fatalf("unexpected retrieval metrics %+v", metrics)
+	}
+	if retriever.vectorLimit != 6 {
+		t.Fatalf("expected candidate pool of 6, got %d", retriever.vectorLimit)
+	}
+	if !strings.Contains(prompt, "strong match") || !strings.Contains(prompt, "vector snippet") {
+		t.Fatalf("expected the document found by both retrievers first, got %s", prompt)
+	}
+	if metrics.QMDResultCount != 2 {
+		t.Fatalf("expected fused results cut to top k, got %d", metrics.QMDResultCount)
+	}
+}
+
+func TestBuildPromptAppliesRerankOrder(t *testing.T) {
+	retriever := &fakeRetriever{
+		searchResults: []qmd.SearchResult{{Path: "a.md"}, {Path: "b.md"}, {Path: "c.md"}},
+		openByTarget: map[string]string{
+			"a.md": "alpha notes",
+			"b.md": "bravo notes",
+			"c.md": "charlie notes",
+		},
+	}
+	reranker := &fakeReranker{order: []int{2, 0}}
+	responder := New(&fakeBase{}, retriever, Config{TopK: 1, Reranker: reranker}, nil)
+
+	prompt, metrics := responder.buildPrompt(context.Background(), llm.MessageInput{
+		WorkspaceID: "ws-1",
+		Text:        "find workspace change summary",
+	})
+	if !metrics.Reranked || metrics.RetrievalMode != "lexical" || len(reranker.candidates) != 3 {
+		t.Fatalf("expected rerank over the lexical pool, got %+v (%d candidates)", metrics, len(reranker.candidates))
+	}
+	if !strings.Contains(prompt, "charlie notes") || strings.Contains(prompt, "alpha notes") {
+		t.Fatalf("expected reranked top result only, got %s", prompt)
+	}
+
+	reranker.err = errors.New("model down")
+	prompt, metrics = responder.buildPrompt(context.Background(), llm.MessageInput{
+		WorkspaceID: "ws-1",
+		Text:        "find workspace change summary",
+	})
+	if metrics.Reranked || !strings.Contains(prompt, "alpha notes") {
+		t.Fatalf("expected retrieval order when rerank fails, got %+v: %s", metrics, prompt)
+	}
+}
+
+func TestLLMRerankerParsesRankedNumbers(t *testing.T) {
+	base := &fakeBase{reply: "3, 1, 3, 9"}
+	reranker := NewLLMReranker(base)
+	order, err := reranker.Rerank(context.Background(), "ws-1", "release plan", []qmd.SearchResult{
+		{Path: "a.md", Snippet: "alpha"},
+		{Path: "b.md"},
+		{DocID: "#c"},
+	})
+	if err != nil {
+		t.Fatalf("rerank: %v", err)
+	}
+	if fmt.Sprint(order) != "[2 0]" {
+		t.Fatalf("unexpected order %v", order)
+	}
+	if !base.lastInput.SkipGrounding || base.lastInput.WorkspaceID != "ws-1" || !strings.Contains(base.lastInput.Text, "[3] #c") {
+		t.Fatalf("unexpected rerank input %+v", base.lastInput)
+	}
+	base.reply = "none of these help"
+	if _, err := reranker.Rerank(context.Background(), "ws-1", "release plan", []qmd.SearchResult{{Path: "a.md"}}); err == nil {
+		t.Fatal("expected error when the reply names no candidates")
+	}
+}
+
 func TestReplySkipsGroundingWhenRequested(t *testing.T) {
 	base := &fakeBase{reply: "ok"}
 	retriever := &fakeRetriever{
@@ -195,6 +338,31 @@ func TestReplyUsesChatTailForMemoryCue(t *testing.T) {
 	}
 }
 
+func TestReplyReadsTheThreadChatTailOnly(t *testing.T) {
+	base := &fakeBase{reply: "ok"}
+	retriever := &fakeRetriever{
+		openByTarget: map[string]string{
+			"logs/chats/discord/chan-1.md":                 "# Chat Log\n\n## 2026-02-10T11:00:00Z `INBOUND`\n- direction: `inbound`\n- actor: `u1`\n\nchannel talk\n",
+			"logs/chats/discord/chan-1-thread-thread-9.md": "# Chat Log\n\n## 2026-02-10T11:00:00Z `INBOUND`\n- direction: `inbound`\n- actor: `u1`\n\nthread talk\n",
+		},
+	}
+	responder := New(base, retriever, Config{TopK: 1, ChatTailLines: 8, ChatTailBytes: 800}, nil)
+	_, err := responder.Reply(context.Background(), llm.MessageInput{
+		Connector:   "discord",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		ExternalID:  "chan-1",
+		ThreadID:    "thread-9",
+		Text:        "as we discussed before, continue from that",
+	})
+	if err != nil {
+		t.Fatalf("reply failed: %v", err)
+	}
+	if !strings.Contains(base.lastInput.Text, "thread talk") || strings.Contains(base.lastInput.Text, "channel talk") {
+		t.Fatalf("expected only the thread's chat tail in prompt, got %q", base.lastInput.Text)
+	}
+}
+
 func TestReplyUsesImplicitQMDForQuestion(t *testing.T) {
 	base := &fakeBase{reply: "ok"}
 	retriever := &fakeRetriever{
@@ -318,6 +486,54 @@ func TestReplyBuildsAndPersistsMemorySummary(t *testing.T) {
 	}
 }
 
+func TestReplyIncludesCompactedChatSummary(t *testing.T) {
+	base := &fakeBase{reply: "ok"}
+	retriever := &fakeRetriever{}
+
+	root := t.TempDir()
+	workspaceID := "ws-1"
+	summaryPath := filepath.Join(root, workspaceID, "memory", "chats", "discord", "chan-1.md")
+	if err := os.MkdirAll(filepath.Dir(summaryPath), 0o755); err != nil {
+		t.Fatalf("mkdir summary path: %v", err)
+	}
+	compacted := strings.Join([]string{
+		"# Chat Memory",
+		"",
+		"- connector: `discord`",
+		"- external_id: `chan-1`",
+		"",
+		"## 2026-02-01 to 2026-02-03 (12 entries, 4 tool calls)",
+		"- user: the staging database moved to eu-west-2",
+		"- assistant: noted, staging now lives in eu-west-2",
+		"",
+	}, "\n")
+	if err := os.WriteFile(summaryPath, []byte(compacted), 0o644); err != nil {
+		t.Fatalf("write compacted summary: %v", err)
+	}
+
+	responder := New(base, retriever, Config{
+		WorkspaceRoot:               root,
+		TopK:                        1,
+		MemorySummaryRefreshTurns:   1,
+		MemorySummaryMaxItems:       6,
+		MemorySummarySourceMaxLines: 80,
+	}, nil)
+
+	_, err := responder.Reply(context.Background(), llm.MessageInput{
+		Connector:   "discord",
+		WorkspaceID: workspaceID,
+		ContextID:   "ctx-1",
+		ExternalID:  "chan-1",
+		Text:        "continue from the previous thread and share what changed",
+	})
+	if err != nil {
+		t.Fatalf("reply failed: %v", err)
+	}
+	if !strings.Contains(base.lastInput.Text, "Earlier conversation:") || !strings.Contains(base.lastInput.Text, "eu-west-2") {
+		t.Fatalf("expected compacted summary in prompt, got %q", base.lastInput.Text)
+	}
+}
+
 func TestReplyRefreshesSummaryWhenCommandHeavyContextGrows(t *testing.T) {
 	base := &fakeBase{reply: "ok"}
 	retriever := &fakeRetriever{}
@@ -28,6 +28,14 @@ type Config struct {
 	MemorySummaryRefreshTurns   int
 	MemorySummaryMaxItems       int
 	MemorySummarySourceMaxLines int
+	// HybridRetrieval fuses lexical qmd results with embedding results when
+	// the retriever implements VectorRetriever.
+	HybridRetrieval bool
+	// RerankCandidates is how many results are gathered for fusion and
+	// reranking before the list is cut to TopK.
+	RerankCandidates int
+	// Reranker, when set, orders the candidate pool before it is cut.
+	Reranker Reranker
 }
 
 type tokenBudget struct {
@@ -52,6 +60,13 @@ type PromptMetrics struct {
 	TailTokens       int
 	QMDTokens        int
 	PromptTokens     int
+
+	RetrievalMode      string
+	LexicalResultCount int
+	VectorResultCount  int
+	CandidateCount     int
+	Reranked           bool
+	RerankMs           int64
 }
 
 type summaryMetadata struct {
@@ -116,6 +131,9 @@ func New(base llm.Responder, retriever Retriever, cfg Config, logger *slog.Logge
 	if cfg.MemorySummarySourceMaxLines < 24 {
 		cfg.MemorySummarySourceMaxLines = 120
 	}
+	if cfg.RerankCandidates < cfg.TopK {
+		cfg.RerankCandidates = cfg.TopK * 3
+	}
 	if logger == nil {
 		logger = slog.Default()
 	}
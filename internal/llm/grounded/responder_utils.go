@@ -2,6 +2,9 @@ package grounded
 
 import (
 	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/memorylog"
 )
 
 func chatLogTarget(connector, externalID string) string {
@@ -13,6 +16,12 @@ func chatLogTarget(connector, externalID string) string {
 	return "logs/chats/" + connector + "/" + externalID + ".md"
 }
 
+// conversationID is the chat log key of the input's channel, or of its
+// thread when it came from one.
+func conversationID(input llm.MessageInput) string {
+	return memorylog.ConversationID(input.ExternalID, input.ThreadID)
+}
+
 func sanitizeLogPathSegment(value string) string {
 	trimmed := strings.TrimSpace(value)
 	trimmed = strings.ReplaceAll(trimmed, " ", "-")
@@ -3,23 +3,134 @@ package llm
 import (
 	"context"
 	"errors"
+	"net/http"
+	"strings"
 )
 
 var ErrUnavailable = errors.New("llm unavailable")
 
 type MessageInput struct {
-	Connector     string
-	WorkspaceID   string
-	ContextID     string
-	ExternalID    string
+	Connector   string
+	WorkspaceID string
+	ContextID   string
+	ExternalID  string
+	// ThreadID is the connector thread the message came from, if any. The
+	// conversation memory of a thread is kept apart from its channel's.
+	ThreadID      string
 	DisplayName   string
 	FromUserID    string
 	Text          string
 	SystemPrompt  string
 	IsDM          bool
 	SkipGrounding bool
+	// Images are attached to the user message by responders that support
+	// vision; others mention that images were sent and ignore them.
+	Images []Image
+}
+
+// MaxImageBytes is the largest image passed to a model; bigger images are
+// only saved as attachments.
+const MaxImageBytes = 5 << 20
+
+// Image is an image sent along with a message.
+type Image struct {
+	Name      string
+	MediaType string
+	Data      []byte
+}
+
+// NewImage prepares an attachment for a model call. It sniffs the media type
+// when the connector did not report an image type and reports false for
+// formats the providers do not accept or images over MaxImageBytes.
+func NewImage(name, mediaType string, data []byte) (Image, bool) {
+	if len(data) == 0 || len(data) > MaxImageBytes {
+		return Image{}, false
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	if !strings.HasPrefix(mediaType, "image/") {
+		mediaType = http.DetectContentType(data)
+	}
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return Image{Name: strings.TrimSpace(name), MediaType: mediaType, Data: data}, true
+	default:
+		return Image{}, false
+	}
+}
+
+// ImagesNotice is the note added to the prompt by responders that cannot
+// look at the images of a message.
+func ImagesNotice(images []Image) string {
+	if len(images) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(images))
+	for _, image := range images {
+		if image.Name != "" {
+			names = append(names, image.Name)
+		}
+	}
+	notice := "[The user attached images this model cannot view"
+	if len(names) > 0 {
+		notice += ": " + strings.Join(names, ", ")
+	}
+	return notice + ". They are saved in the workspace inbox.]"
 }
 
 type Responder interface {
 	Reply(ctx context.Context, input MessageInput) (string, error)
 }
+
+// Usage is the token consumption of one model call, attributed to the
+// workspace of the input that triggered it.
+type Usage struct {
+	WorkspaceID  string
+	InputTokens  int
+	OutputTokens int
+}
+
+// UsageRecorder receives the token usage that provider clients report.
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, usage Usage)
+}
+
+// Embedder turns texts into vectors for similarity ranking. Implementations
+// return one vector per input, in order.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// Variant overrides parts of a model call for one turn, for example when a
+// canary rollout tries a new model or system prompt on part of the traffic.
+// Empty fields keep the configured behavior.
+type Variant struct {
+	Model        string
+	SystemPrompt string
+}
+
+type variantKey struct{}
+
+// WithVariant attaches per-turn overrides to ctx.
+func WithVariant(ctx context.Context, variant Variant) context.Context {
+	return context.WithValue(ctx, variantKey{}, variant)
+}
+
+// VariantFrom returns the overrides attached to ctx, if any.
+func VariantFrom(ctx context.Context) Variant {
+	variant, _ := ctx.Value(variantKey{}).(Variant)
+	return variant
+}
+
+type workspaceKey struct{}
+
+// WithWorkspace names the workspace of calls that carry none in their input,
+// such as Embed, so wrappers can apply its settings.
+func WithWorkspace(ctx context.Context, workspaceID string) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, workspaceID)
+}
+
+// WorkspaceFrom returns the workspace attached to ctx, if any.
+func WorkspaceFrom(ctx context.Context) string {
+	workspaceID, _ := ctx.Value(workspaceKey{}).(string)
+	return workspaceID
+}
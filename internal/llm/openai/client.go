@@ -3,6 +3,7 @@ package openai
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,11 +17,16 @@ import (
 )
 
 type Config struct {
-	APIKey       string
-	BaseURL      string
-	Model        string
-	Timeout      time.Duration
-	SystemPrompt string
+	APIKey         string
+	BaseURL        string
+	Model          string
+	EmbeddingModel string
+	Timeout        time.Duration
+	SystemPrompt   string
+	// Vision sends message images to the model as image parts.
+	Vision bool
+	// Usage, when set, receives the token usage of every completion.
+	Usage llm.UsageRecorder
 }
 
 type Client struct {
@@ -63,7 +69,7 @@ func (c *Client) Reply(ctx context.Context, input llm.MessageInput) (string, err
 		return "", nil
 	}
 
-	messages := []map[string]string{}
+	messages := []map[string]any{}
 
 	// 1. System Prompt
 	systemPrompt := strings.TrimSpace(c.cfg.SystemPrompt)
@@ -74,7 +80,7 @@ func (c *Client) Reply(ctx context.Context, input llm.MessageInput) (string, err
 		systemPrompt += strings.TrimSpace(input.SystemPrompt)
 	}
 	if systemPrompt != "" {
-		messages = append(messages, map[string]string{
+		messages = append(messages, map[string]any{
 			"role":    "system",
 			"content": systemPrompt,
 		})
@@ -83,13 +89,13 @@ func (c *Client) Reply(ctx context.Context, input llm.MessageInput) (string, err
 	// 2. User Prompt
 	// We combine metadata into the user message because standard chat APIs don't have "context" fields
 	userContent := buildUserPrompt(input)
-	messages = append(messages, map[string]string{
+	messages = append(messages, map[string]any{
 		"role":    "user",
-		"content": userContent,
+		"content": c.userMessageContent(userContent, input.Images),
 	})
 
 	payload := map[string]any{
-		"model":    c.cfg.Model,
+		"model":    c.model(ctx),
 		"messages": messages,
 	}
 	
@@ -127,6 +133,13 @@ func (c *Client) Reply(ctx context.Context, input llm.MessageInput) (string, err
 	if err := json.Unmarshal(respBody, &response); err != nil {
 		return "", fmt.Errorf("decode openai response: %w", err)
 	}
+	if c.cfg.Usage != nil {
+		c.cfg.Usage.RecordUsage(ctx, llm.Usage{
+			WorkspaceID:  input.WorkspaceID,
+			InputTokens:  response.Usage.PromptTokens,
+			OutputTokens: response.Usage.CompletionTokens,
+		})
+	}
 	if len(response.Choices) == 0 {
 		return "", fmt.Errorf("openai response returned no choices")
 	}
@@ -162,12 +175,37 @@ func buildUserPrompt(input llm.MessageInput) string {
 	return header + "\n\n" + input.Text
 }
 
+// userMessageContent is the plain prompt, or text and image parts when the
+// message carries images and the model can view them.
+func (c *Client) userMessageContent(prompt string, images []llm.Image) any {
+	if len(images) == 0 {
+		return prompt
+	}
+	if !c.cfg.Vision {
+		return prompt + "\n\n" + llm.ImagesNotice(images)
+	}
+	parts := []map[string]any{{"type": "text", "text": prompt}}
+	for _, image := range images {
+		parts = append(parts, map[string]any{
+			"type": "image_url",
+			"image_url": map[string]string{
+				"url": "data:" + image.MediaType + ";base64," + base64.StdEncoding.EncodeToString(image.Data),
+			},
+		})
+	}
+	return parts
+}
+
 type chatCompletionResponse struct {
 	Choices []struct {
 		Message struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
 }
 
 func requiresAPIKey(baseURL string) bool {
@@ -177,4 +215,12 @@ func requiresAPIKey(baseURL string) bool {
 		return false
 	}
 	return true
-}
\ No newline at end of file
+}
+
+// model is the configured model unless the turn carries a variant override.
+func (c *Client) model(ctx context.Context) string {
+	if model := strings.TrimSpace(llm.VariantFrom(ctx).Model); model != "" {
+		return model
+	}
+	return c.cfg.Model
+}
@@ -0,0 +1,78 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+)
+
+// Embed calls the /embeddings endpoint with the configured embedding model.
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	model := strings.TrimSpace(c.cfg.EmbeddingModel)
+	if model == "" {
+		return nil, fmt.Errorf("%w: no embedding model configured", llm.ErrUnavailable)
+	}
+	if requiresAPIKey(c.cfg.BaseURL) && strings.TrimSpace(c.cfg.APIKey) == "" {
+		return nil, fmt.Errorf("%w: missing API key for %s", llm.ErrUnavailable, c.cfg.BaseURL)
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(map[string]any{
+		"model": model,
+		"input": texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal openai embeddings request: %w", err)
+	}
+	endpoint := strings.TrimRight(c.cfg.BaseURL, "/") + "/embeddings"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey := strings.TrimSpace(c.cfg.APIKey); apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(res.Body, 16<<20))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("openai embeddings failed with status %d", res.StatusCode)
+	}
+	var response struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("decode openai embeddings: %w", err)
+	}
+	vectors := make([][]float64, len(texts))
+	for _, item := range response.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, fmt.Errorf("openai embeddings returned index %d for %d inputs", item.Index, len(texts))
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for index, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("openai embeddings missing vector for input %d", index)
+		}
+	}
+	return vectors, nil
+}
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/dwizi/agent-runtime/internal/llm"
 	"github.com/dwizi/agent-runtime/internal/store"
@@ -18,6 +19,12 @@ type PolicyProvider interface {
 	LookupContextPolicy(ctx context.Context, contextID string) (store.ContextPolicy, error)
 }
 
+// SkillUsageRecorder notes which workspace skills were put into a prompt,
+// so unused skills can be flagged for review.
+type SkillUsageRecorder interface {
+	RecordSkillUsage(ctx context.Context, workspaceID string, paths []string) error
+}
+
 type Config struct {
 	WorkspaceRoot        string
 	AdminSystemPrompt    string
@@ -31,15 +38,23 @@ type Config struct {
 	GlobalSkillsRoot     string
 	MaxSkills            int
 	MaxSkillBytes        int
+	MaxSkillTokens       int
 	MaxSoulBytes         int
 	MaxPromptBytes       int
 	MaxSystemPromptBytes int
+	// SkillEmbedder ranks skills against the message when they do not all
+	// fit; without one, ranking falls back to keyword overlap.
+	SkillEmbedder llm.Embedder
+	SkillUsage    SkillUsageRecorder
 }
 
 type Responder struct {
 	base     llm.Responder
 	provider PolicyProvider
 	cfg      Config
+
+	skillVectorsMu sync.Mutex
+	skillVectors   map[string]skillVector
 }
 
 func New(base llm.Responder, provider PolicyProvider, cfg Config) *Responder {
@@ -49,6 +64,9 @@ func New(base llm.Responder, provider PolicyProvider, cfg Config) *Responder {
 	if cfg.MaxSkillBytes < 300 {
 		cfg.MaxSkillBytes = 1400
 	}
+	if cfg.MaxSkillTokens < 100 {
+		cfg.MaxSkillTokens = 1500
+	}
 	if cfg.MaxSoulBytes < 300 {
 		cfg.MaxSoulBytes = 2400
 	}
@@ -59,9 +77,10 @@ func New(base llm.Responder, provider PolicyProvider, cfg Config) *Responder {
 		cfg.MaxSystemPromptBytes = 12000
 	}
 	return &Responder{
-		base:     base,
-		provider: provider,
-		cfg:      cfg,
+		base:         base,
+		provider:     provider,
+		cfg:          cfg,
+		skillVectors: map[string]skillVector{},
 	}
 }
 
@@ -97,6 +116,10 @@ func (r *Responder) buildSystemPrompt(ctx context.Context, input llm.MessageInpu
 		lines = append(lines, strings.TrimSpace(r.cfg.PublicSystemPrompt))
 	}
 	systemSections := r.loadSystemPromptSections(policy.WorkspaceID, policy.ContextID)
+	if canaryPrompt := strings.TrimSpace(llm.VariantFrom(ctx).SystemPrompt); canaryPrompt != "" {
+		// A prompt canary replaces the file-based directives for this turn.
+		systemSections = []string{"Canary prompt:\n" + canaryPrompt}
+	}
 	if len(systemSections) > 0 {
 		lines = append(lines, "System prompt directives:")
 		lines = append(lines, systemSections...)
@@ -111,7 +134,7 @@ func (r *Responder) buildSystemPrompt(ctx context.Context, input llm.MessageInpu
 	}
 	lines = append(lines, "External actions policy:\nIf you need to request an external action (email/send/post/run), include an `action` fenced JSON block. Example:\n```action\n{\"type\":\"send_email\",\"target\":\"ops@example.com\",\"summary\":\"Send update\",\"subject\":\"Status\",\"body\":\"...\"}\n```\nFor shell/CLI execution use:\n```action\n{\"type\":\"run_command\",\"target\":\"curl\",\"summary\":\"Fetch service status\",\"args\":[\"-sS\",\"https://example.com/health\"]}\n```\nThese actions require admin approval before execution. Command execution is restricted by sandbox policy allowlists.")
 
-	skills := r.loadSkills(policy.WorkspaceID, policy.ContextID, policy.IsAdmin)
+	skills := r.loadSkills(ctx, policy.WorkspaceID, policy.ContextID, policy.IsAdmin, input.Text)
 	if len(skills) > 0 {
 		lines = append(lines, "Skill templates:")
 		for _, skill := range skills {
@@ -126,7 +149,7 @@ func (r *Responder) buildSystemPrompt(ctx context.Context, input llm.MessageInpu
 	return prompt
 }
 
-func (r *Responder) loadSkills(workspaceID, contextID string, isAdmin bool) []string {
+func (r *Responder) loadSkills(ctx context.Context, workspaceID, contextID string, isAdmin bool, query string) []string {
 	root := strings.TrimSpace(r.cfg.WorkspaceRoot)
 	globalRoot := strings.TrimSpace(r.cfg.GlobalSkillsRoot)
 	workspaceID = strings.TrimSpace(workspaceID)
@@ -158,10 +181,10 @@ func (r *Responder) loadSkills(workspaceID, contextID string, isAdmin bool) []st
 	if len(files) == 0 {
 		return nil
 	}
-	skills := make([]string, 0, r.cfg.MaxSkills)
+	candidates := make([]skillCandidate, 0, len(files))
 	seenNames := map[string]struct{}{}
 	for _, path := range files {
-		if len(skills) >= r.cfg.MaxSkills {
+		if len(candidates) >= maxSkillCandidates {
 			break
 		}
 		name := strings.ToLower(strings.TrimSpace(filepath.Base(path)))
@@ -171,6 +194,10 @@ func (r *Responder) loadSkills(workspaceID, contextID string, isAdmin bool) []st
 		if _, ok := seenNames[name]; ok {
 			continue
 		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
 		content, err := os.ReadFile(path)
 		if err != nil {
 			continue
@@ -183,9 +210,20 @@ func (r *Responder) loadSkills(workspaceID, contextID string, isAdmin bool) []st
 			text = text[:r.cfg.MaxSkillBytes] + "..."
 		}
 		seenNames[name] = struct{}{}
-		skills = append(skills, fmt.Sprintf("- `%s`: %s", filepath.Base(path), strings.Join(strings.Fields(text), " ")))
-	}
-	return skills
+		candidates = append(candidates, skillCandidate{
+			path:    path,
+			name:    filepath.Base(path),
+			text:    strings.Join(strings.Fields(text), " "),
+			modTime: info.ModTime(),
+		})
+	}
+	selected := r.selectSkills(llm.WithWorkspace(ctx, workspaceID), query, candidates)
+	r.recordSkillUsage(ctx, root, workspaceID, selected)
+	lines := make([]string, 0, len(selected))
+	for _, candidate := range selected {
+		lines = append(lines, candidate.line())
+	}
+	return lines
 }
 
 func (r *Responder) skillDirectories(workspaceRoot, globalRoot, workspaceID, contextID string, isAdmin bool) []string {
@@ -287,4 +287,13 @@ func TestResponderLoadsSystemPromptHierarchy(t *testing.T) {
 	if !strings.Contains(prompt, "Context prompt override") || !strings.Contains(prompt, "Context system prompt rules.") {
 		t.Fatalf("expected context system prompt directives, got %s", prompt)
 	}
+
+	canaryCtx := llm.WithVariant(context.Background(), llm.Variant{SystemPrompt: "Canary system prompt rules."})
+	if _, err := responder.Reply(canaryCtx, llm.MessageInput{ContextID: contextID, WorkspaceID: workspaceID, Text: "hello"}); err != nil {
+		t.Fatalf("canary reply failed: %v", err)
+	}
+	prompt = base.lastInput.SystemPrompt
+	if !strings.Contains(prompt, "Canary system prompt rules.") || strings.Contains(prompt, "Workspace system prompt rules.") {
+		t.Fatalf("expected the canary prompt to replace the file directives, got %s", prompt)
+	}
 }
@@ -0,0 +1,218 @@
+package promptpolicy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxSkillCandidates bounds how many skill files are read per prompt.
+const maxSkillCandidates = 200
+
+type skillCandidate struct {
+	path    string
+	name    string
+	text    string
+	modTime time.Time
+}
+
+func (c skillCandidate) line() string {
+	return fmt.Sprintf("- `%s`: %s", c.name, c.text)
+}
+
+type skillVector struct {
+	modTime time.Time
+	text    string
+	vector  []float64
+}
+
+// selectSkills keeps every skill when they all fit the count and token
+// budget. Otherwise it ranks skills by relevance to the message and fills
+// the budget best-first, preserving directory precedence in the output.
+func (r *Responder) selectSkills(ctx context.Context, query string, candidates []skillCandidate) []skillCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+	total := 0
+	for _, candidate := range candidates {
+		total += estimateTokens(candidate.line())
+	}
+	if len(candidates) <= r.cfg.MaxSkills && total <= r.cfg.MaxSkillTokens {
+		return candidates
+	}
+
+	scores := r.scoreSkills(ctx, query, candidates)
+	order := make([]int, len(candidates))
+	for index := range order {
+		order[index] = index
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	selected := make([]int, 0, r.cfg.MaxSkills)
+	used := 0
+	for _, index := range order {
+		if len(selected) >= r.cfg.MaxSkills {
+			break
+		}
+		tokens := estimateTokens(candidates[index].line())
+		if used+tokens > r.cfg.MaxSkillTokens {
+			continue
+		}
+		used += tokens
+		selected = append(selected, index)
+	}
+	sort.Ints(selected)
+	kept := make([]skillCandidate, 0, len(selected))
+	for _, index := range selected {
+		kept = append(kept, candidates[index])
+	}
+	return kept
+}
+
+// recordSkillUsage reports the workspace-owned skills that made it into the
+// prompt; global skills are shared and not reviewed per workspace.
+func (r *Responder) recordSkillUsage(ctx context.Context, root, workspaceID string, selected []skillCandidate) {
+	if r.cfg.SkillUsage == nil || root == "" || workspaceID == "" {
+		return
+	}
+	workspaceDir := filepath.Join(root, workspaceID)
+	paths := make([]string, 0, len(selected))
+	for _, candidate := range selected {
+		rel, err := filepath.Rel(workspaceDir, candidate.path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	if len(paths) > 0 {
+		_ = r.cfg.SkillUsage.RecordSkillUsage(ctx, workspaceID, paths)
+	}
+}
+
+// scoreSkills uses embedding similarity when an embedder is configured and
+// reachable, and keyword overlap otherwise.
+func (r *Responder) scoreSkills(ctx context.Context, query string, candidates []skillCandidate) []float64 {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return make([]float64, len(candidates))
+	}
+	if r.cfg.SkillEmbedder != nil {
+		if scores, err := r.embeddingScores(ctx, query, candidates); err == nil {
+			return scores
+		}
+	}
+	terms := keywordTerms(query)
+	scores := make([]float64, len(candidates))
+	if len(terms) == 0 {
+		return scores
+	}
+	for index, candidate := range candidates {
+		skillTerms := keywordTerms(candidate.name + " " + candidate.text)
+		matched := 0
+		for term := range terms {
+			if _, ok := skillTerms[term]; ok {
+				matched++
+			}
+		}
+		scores[index] = float64(matched) / float64(len(terms))
+	}
+	return scores
+}
+
+func (r *Responder) embeddingScores(ctx context.Context, query string, candidates []skillCandidate) ([]float64, error) {
+	vectors := make([][]float64, len(candidates))
+	texts := []string{query}
+	missing := []int{}
+	r.skillVectorsMu.Lock()
+	for index, candidate := range candidates {
+		cached, ok := r.skillVectors[candidate.path]
+		if ok && cached.modTime.Equal(candidate.modTime) && cached.text == candidate.text {
+			vectors[index] = cached.vector
+			continue
+		}
+		missing = append(missing, index)
+		texts = append(texts, candidate.name+"\n"+candidate.text)
+	}
+	r.skillVectorsMu.Unlock()
+
+	embedded, err := r.cfg.SkillEmbedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(texts) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d inputs", len(embedded), len(texts))
+	}
+	r.skillVectorsMu.Lock()
+	for offset, index := range missing {
+		vectors[index] = embedded[offset+1]
+		r.skillVectors[candidates[index].path] = skillVector{
+			modTime: candidates[index].modTime,
+			text:    candidates[index].text,
+			vector:  embedded[offset+1],
+		}
+	}
+	r.skillVectorsMu.Unlock()
+
+	scores := make([]float64, len(candidates))
+	for index, vector := range vectors {
+		scores[index] = cosineSimilarity(embedded[0], vector)
+	}
+	return scores, nil
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for index := range a {
+		dot += a[index] * b[index]
+		normA += a[index] * a[index]
+		normB += b[index] * b[index]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+var skillStopWords = map[string]struct{}{
+	"the": {}, "and": {}, "for": {}, "with": {}, "this": {}, "that": {}, "from": {},
+	"you": {}, "your": {}, "are": {}, "can": {}, "how": {}, "what": {}, "please": {},
+}
+
+func keywordTerms(text string) map[string]struct{} {
+	terms := map[string]struct{}{}
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, field := range fields {
+		if len(field) < 3 {
+			continue
+		}
+		if _, stop := skillStopWords[field]; stop {
+			continue
+		}
+		terms[field] = struct{}{}
+	}
+	return terms
+}
+
+func estimateTokens(input string) int {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return 0
+	}
+	charBased := (len(trimmed) + 3) / 4
+	wordBased := len(strings.Fields(trimmed))
+	if wordBased > charBased {
+		return wordBased
+	}
+	return charBased
+}
@@ -0,0 +1,142 @@
+package promptpolicy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func writeSkills(t *testing.T, dir string, skills map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("create skill dir: %v", err)
+	}
+	for name, body := range skills {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("write skill: %v", err)
+		}
+	}
+}
+
+func TestResponderFiltersSkillsByKeywordRelevance(t *testing.T) {
+	root := t.TempDir()
+	skills := map[string]string{
+		"deploy.md":   "Deploy the service with the release pipeline and verify health checks.",
+		"invoices.md": "Draft invoices for customers and attach the billing summary.",
+		"standup.md":  "Summarize the daily standup notes for the team.",
+	}
+	for index := 0; index < 8; index++ {
+		skills[fmt.Sprintf("filler-%02d.md", index)] = "Unrelated procedure about gardening and plants."
+	}
+	writeSkills(t, filepath.Join(root, "ws-1", "skills", "common"), skills)
+
+	base := &fakeBase{reply: "ok"}
+	responder := New(base, &fakeProvider{err: store.ErrContextNotFound}, Config{WorkspaceRoot: root, MaxSkills: 2})
+	if _, err := responder.Reply(context.Background(), llm.MessageInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Text:        "please draft the customer invoices",
+	}); err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+	prompt := base.lastInput.SystemPrompt
+	if !strings.Contains(prompt, "`invoices.md`") {
+		t.Fatalf("expected relevant invoices skill, got %s", prompt)
+	}
+	if strings.Contains(prompt, "filler-") || strings.Count(prompt, "\n- `") > 2 {
+		t.Fatalf("expected at most two relevant skills, got %s", prompt)
+	}
+}
+
+type fakeEmbedder struct {
+	calls  [][]string
+	vector func(text string) []float64
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	f.calls = append(f.calls, texts)
+	vectors := make([][]float64, len(texts))
+	for index, text := range texts {
+		vectors[index] = f.vector(text)
+	}
+	return vectors, nil
+}
+
+func TestResponderRanksSkillsByEmbeddingWithTokenCap(t *testing.T) {
+	root := t.TempDir()
+	writeSkills(t, filepath.Join(root, "ws-1", "skills", "common"), map[string]string{
+		"alpha.md": "Rotate credentials " + strings.Repeat("carefully ", 60),
+		"beta.md":  "Escalate outages to the on-call engineer.",
+		"gamma.md": "Write the weekly newsletter.",
+	})
+	embedder := &fakeEmbedder{vector: func(text string) []float64 {
+		switch {
+		case strings.Contains(text, "pager"), strings.Contains(text, "Escalate"):
+			return []float64{1, 0}
+		case strings.Contains(text, "Rotate"):
+			return []float64{0.9, 0.1}
+		default:
+			return []float64{0, 1}
+		}
+	}}
+	base := &fakeBase{reply: "ok"}
+	responder := New(base, &fakeProvider{err: store.ErrContextNotFound}, Config{
+		WorkspaceRoot:  root,
+		MaxSkills:      2,
+		MaxSkillTokens: 100,
+		SkillEmbedder:  embedder,
+	})
+	input := llm.MessageInput{WorkspaceID: "ws-1", ContextID: "ctx-1", Text: "who holds the pager tonight?"}
+	if _, err := responder.Reply(context.Background(), input); err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+	prompt := base.lastInput.SystemPrompt
+	// alpha.md ranks second but exceeds the token cap, so gamma.md fills in.
+	if !strings.Contains(prompt, "`beta.md`") || strings.Contains(prompt, "`alpha.md`") || !strings.Contains(prompt, "`gamma.md`") {
+		t.Fatalf("unexpected skill selection: %s", prompt)
+	}
+
+	if _, err := responder.Reply(context.Background(), input); err != nil {
+		t.Fatalf("second reply: %v", err)
+	}
+	if len(embedder.calls) != 2 || len(embedder.calls[1]) != 1 {
+		t.Fatalf("expected cached skill vectors on second call, got %v", embedder.calls)
+	}
+}
+
+type fakeSkillUsage struct {
+	workspaceID string
+	paths       []string
+}
+
+func (f *fakeSkillUsage) RecordSkillUsage(ctx context.Context, workspaceID string, paths []string) error {
+	f.workspaceID = workspaceID
+	f.paths = append(f.paths, paths...)
+	return nil
+}
+
+func TestResponderRecordsWorkspaceSkillUsage(t *testing.T) {
+	root := t.TempDir()
+	globalRoot := filepath.Join(root, "global-skills")
+	writeSkills(t, filepath.Join(root, "ws-1", "skills", "common"), map[string]string{"tone.md": "Be concise."})
+	writeSkills(t, filepath.Join(globalRoot, "common"), map[string]string{"shared.md": "Cite sources."})
+
+	usage := &fakeSkillUsage{}
+	responder := New(&fakeBase{reply: "ok"}, &fakeProvider{err: store.ErrContextNotFound}, Config{
+		WorkspaceRoot:    root,
+		GlobalSkillsRoot: globalRoot,
+		SkillUsage:       usage,
+	})
+	if _, err := responder.Reply(context.Background(), llm.MessageInput{WorkspaceID: "ws-1", ContextID: "ctx-1", Text: "hi"}); err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+	if usage.workspaceID != "ws-1" || len(usage.paths) != 1 || usage.paths[0] != "skills/common/tone.md" {
+		t.Fatalf("expected only the workspace skill to be recorded, got %+v", usage)
+	}
+}
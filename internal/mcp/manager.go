@@ -11,6 +11,8 @@ import (
 	"time"
 
 	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	agenttools "github.com/dwizi/agent-runtime/internal/agent/tools"
 )
 
 type serverState struct {
@@ -582,6 +584,28 @@ func sessionKey(cfg ServerConfig) string {
 	}, "::")
 }
 
+// resolveToolPolicy applies server defaults, then tightens them for tools the
+// server annotates as possibly destructive. As in the MCP spec, an annotated
+// tool that is not read-only counts as destructive unless it says otherwise.
+// Per-tool overrides always win.
+func resolveToolPolicy(policy PolicyConfig, item *sdkmcp.Tool) (string, bool) {
+	if override, exists := policy.ToolOverrides[item.Name]; exists {
+		toolClass := policy.DefaultToolClass
+		if strings.TrimSpace(override.ToolClass) != "" {
+			toolClass = override.ToolClass
+		}
+		return toolClass, override.RequiresApproval
+	}
+	toolClass := policy.DefaultToolClass
+	requiresApproval := policy.DefaultRequiresApproval
+	if annotations := item.Annotations; annotations != nil && !annotations.ReadOnlyHint &&
+		(annotations.DestructiveHint == nil || *annotations.DestructiveHint) {
+		toolClass = string(agenttools.ToolClassSensitive)
+		requiresApproval = true
+	}
+	return toolClass, requiresApproval
+}
+
 func discoverCapabilities(ctx context.Context, cfg ServerConfig, session *sdkmcp.ClientSession) ([]DiscoveredTool, []ResourceInfo, []ResourceTemplateInfo, []PromptInfo, []string, error) {
 	warnings := []string{}
 	rawTools := []*sdkmcp.Tool{}
@@ -601,14 +625,7 @@ func discoverCapabilities(ctx context.Context, cfg ServerConfig, session *sdkmcp
 	nameMap := EnsureUniqueRegisteredNames(cfg.ID, toolNames)
 	tools := make([]DiscoveredTool, 0, len(rawTools))
 	for _, item := range rawTools {
-		toolClass := cfg.Policy.DefaultToolClass
-		requiresApproval := cfg.Policy.DefaultRequiresApproval
-		if override, exists := cfg.Policy.ToolOverrides[item.Name]; exists {
-			if strings.TrimSpace(override.ToolClass) != "" {
-				toolClass = override.ToolClass
-			}
-			requiresApproval = override.RequiresApproval
-		}
+		toolClass, requiresApproval := resolveToolPolicy(cfg.Policy, item)
 		schema := "{}"
 		if item.InputSchema != nil {
 			schemaBytes, err := json.Marshal(item.InputSchema)
@@ -499,3 +499,59 @@ func newTestMCPServer(t *testing.T) *sdkmcp.Server {
 		})
 	return server
 }
+
+func TestResolveToolPolicyHonorsDestructiveAnnotation(t *testing.T) {
+	destructive := true
+	policy := PolicyConfig{
+		DefaultToolClass: "general",
+		ToolOverrides: map[string]ToolPolicy{
+			"purge_cache": {ToolClass: "general", RequiresApproval: false},
+		},
+	}
+
+	class, approval := resolveToolPolicy(policy, &sdkmcp.Tool{
+		Name:        "delete_repo",
+		Annotations: &sdkmcp.ToolAnnotations{DestructiveHint: &destructive},
+	})
+	if class != "sensitive" || !approval {
+		t.Fatalf("expected destructive tool to be sensitive with approval, got %s %v", class, approval)
+	}
+
+	class, approval = resolveToolPolicy(policy, &sdkmcp.Tool{
+		Name:        "list_repos",
+		Annotations: &sdkmcp.ToolAnnotations{ReadOnlyHint: true, DestructiveHint: &destructive},
+	})
+	if class != "general" || approval {
+		t.Fatalf("expected read-only tool to keep defaults, got %s %v", class, approval)
+	}
+
+	class, approval = resolveToolPolicy(policy, &sdkmcp.Tool{
+		Name:        "purge_cache",
+		Annotations: &sdkmcp.ToolAnnotations{DestructiveHint: &destructive},
+	})
+	if class != "general" || approval {
+		t.Fatalf("expected explicit override to win, got %s %v", class, approval)
+	}
+
+	class, approval = resolveToolPolicy(policy, &sdkmcp.Tool{
+		Name:        "update_issue",
+		Annotations: &sdkmcp.ToolAnnotations{Title: "Update issue"},
+	})
+	if class != "sensitive" || !approval {
+		t.Fatalf("expected a write tool without a destructive hint to be sensitive, got %s %v", class, approval)
+	}
+
+	additive := false
+	class, approval = resolveToolPolicy(policy, &sdkmcp.Tool{
+		Name:        "add_comment",
+		Annotations: &sdkmcp.ToolAnnotations{DestructiveHint: &additive},
+	})
+	if class != "general" || approval {
+		t.Fatalf("expected a tool marked non-destructive to keep defaults, got %s %v", class, approval)
+	}
+
+	class, approval = resolveToolPolicy(policy, &sdkmcp.Tool{Name: "unannotated"})
+	if class != "general" || approval {
+		t.Fatalf("expected unannotated tool to keep defaults, got %s %v", class, approval)
+	}
+}
@@ -0,0 +1,340 @@
+package memorylog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logMu serializes writers of chat log files so compaction cannot drop an
+// entry appended while it rewrites the file.
+var logMu sync.Mutex
+
+var entryHeading = regexp.MustCompile("^## (\\S+) `([A-Z]+)`\\s*$")
+
+const (
+	summaryLineMaxChars = 200
+	summaryTitle        = "# Chat Memory"
+)
+
+type CompactOptions struct {
+	WorkspaceRoot string
+	WorkspaceID   string
+	// KeepEntries is how many of the newest entries stay in the live log.
+	KeepEntries int
+	// MinAge keeps entries younger than this in the live log even past
+	// KeepEntries.
+	MinAge time.Duration
+	// MaxSections caps the rolling summary; older sections are dropped
+	// from it but remain in the archive.
+	MaxSections int
+	Now         time.Time
+}
+
+type CompactResult struct {
+	Chats   int
+	Entries int
+}
+
+type logEntry struct {
+	heading   string
+	direction string
+	timestamp time.Time
+	body      string
+}
+
+// CompactWorkspace moves the older entries of every chat log in a workspace
+// out of logs/chats. Their raw text is appended to logs/archive/chats and a
+// condensed section is added to the chat's rolling summary under
+// memory/chats, which is indexed like any workspace markdown.
+func CompactWorkspace(opts CompactOptions) (CompactResult, error) {
+	root := strings.TrimSpace(opts.WorkspaceRoot)
+	workspaceID := strings.TrimSpace(opts.WorkspaceID)
+	if root == "" || workspaceID == "" {
+		return CompactResult{}, nil
+	}
+	if opts.KeepEntries < 1 {
+		opts.KeepEntries = 30
+	}
+	if opts.MaxSections < 1 {
+		opts.MaxSections = 12
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now().UTC()
+	}
+
+	chatsDir := filepath.Join(root, workspaceID, "logs", "chats")
+	connectors, err := os.ReadDir(chatsDir)
+	if os.IsNotExist(err) {
+		return CompactResult{}, nil
+	}
+	if err != nil {
+		return CompactResult{}, fmt.Errorf("list chat logs: %w", err)
+	}
+	result := CompactResult{}
+	for _, connector := range connectors {
+		if !connector.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(chatsDir, connector.Name()))
+		if err != nil {
+			return result, fmt.Errorf("list chat logs: %w", err)
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") {
+				continue
+			}
+			externalID := strings.TrimSuffix(file.Name(), ".md")
+			compacted, err := compactChat(opts, connector.Name(), externalID)
+			if err != nil {
+				return result, fmt.Errorf("compact %s/%s: %w", connector.Name(), externalID, err)
+			}
+			if compacted > 0 {
+				result.Chats++
+				result.Entries += compacted
+			}
+		}
+	}
+	return result, nil
+}
+
+func compactChat(opts CompactOptions, connector, externalID string) (int, error) {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	workspaceDir := filepath.Join(opts.WorkspaceRoot, opts.WorkspaceID)
+	logPath := filepath.Join(workspaceDir, "logs", "chats", connector, externalID+".md")
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		return 0, err
+	}
+	header, entries := splitEntries(string(data))
+	cut := len(entries) - opts.KeepEntries
+	if opts.MinAge > 0 {
+		cutoff := opts.Now.Add(-opts.MinAge)
+		for cut > 0 && !entries[cut-1].timestamp.IsZero() && entries[cut-1].timestamp.After(cutoff) {
+			cut--
+		}
+	}
+	if cut <= 0 {
+		return 0, nil
+	}
+	old, kept := entries[:cut], entries[cut:]
+
+	archivePath := filepath.Join(workspaceDir, "logs", "archive", "chats", connector, externalID+".md")
+	if err := appendEntries(archivePath, header, old); err != nil {
+		return 0, fmt.Errorf("archive entries: %w", err)
+	}
+	summaryPath := SummaryPath(opts.WorkspaceRoot, opts.WorkspaceID, connector, externalID)
+	if err := appendSummarySection(summaryPath, connector, externalID, old, opts.MaxSections); err != nil {
+		return 0, fmt.Errorf("write summary: %w", err)
+	}
+	if err := writeFileAtomic(logPath, header+joinEntries(kept)); err != nil {
+		return 0, fmt.Errorf("rewrite chat log: %w", err)
+	}
+	return len(old), nil
+}
+
+// SummaryPath is where the rolling summary of a chat's compacted history
+// lives.
+func SummaryPath(workspaceRoot, workspaceID, connector, externalID string) string {
+	return filepath.Join(workspaceRoot, workspaceID, "memory", "chats", sanitizeSegment(connector), sanitizeSegment(externalID)+".md")
+}
+
+// ReadSummary returns the newest sections of a chat's rolling summary,
+// oldest first, without the document header. It is empty until the chat
+// has been compacted.
+func ReadSummary(workspaceRoot, workspaceID, connector, externalID string, maxSections int) string {
+	if strings.TrimSpace(workspaceRoot) == "" || strings.TrimSpace(workspaceID) == "" {
+		return ""
+	}
+	data, err := os.ReadFile(SummaryPath(workspaceRoot, workspaceID, connector, externalID))
+	if err != nil {
+		return ""
+	}
+	_, sections := splitSummarySections(string(data))
+	if maxSections > 0 && len(sections) > maxSections {
+		sections = sections[len(sections)-maxSections:]
+	}
+	return strings.TrimSpace(strings.Join(sections, "\n"))
+}
+
+func splitEntries(content string) (string, []logEntry) {
+	lines := strings.Split(content, "\n")
+	header := []string{}
+	entries := []logEntry{}
+	var current *logEntry
+	var body []string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.body = strings.TrimSpace(strings.Join(body, "\n"))
+		entries = append(entries, *current)
+	}
+	for _, line := range lines {
+		if match := entryHeading.FindStringSubmatch(line); match != nil {
+			flush()
+			timestamp, _ := time.Parse(time.RFC3339, match[1])
+			current = &logEntry{heading: line, direction: strings.ToLower(match[2]), timestamp: timestamp}
+			body = body[:0]
+			continue
+		}
+		if current == nil {
+			header = append(header, line)
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	headerText := strings.TrimRight(strings.Join(header, "\n"), "\n")
+	if headerText != "" {
+		headerText += "\n\n"
+	}
+	return headerText, entries
+}
+
+func joinEntries(entries []logEntry) string {
+	var builder strings.Builder
+	for _, entry := range entries {
+		builder.WriteString(entry.heading)
+		builder.WriteString("\n")
+		builder.WriteString(entry.body)
+		builder.WriteString("\n\n")
+	}
+	return builder.String()
+}
+
+func appendEntries(path, header string, entries []logEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	content := joinEntries(entries)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		content = header + content
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// appendSummarySection condenses entries into one dated section: user and
+// assistant messages become one short line each and tool calls are only
+// counted, since they are the bulk of the noise in raw history.
+func appendSummarySection(path, connector, externalID string, entries []logEntry, maxSections int) error {
+	lines := []string{}
+	toolCalls := 0
+	for _, entry := range entries {
+		role := ""
+		switch entry.direction {
+		case "inbound":
+			role = "user"
+		case "outbound":
+			role = "assistant"
+		case "tool":
+			toolCalls++
+			continue
+		default:
+			continue
+		}
+		text := condenseText(messageText(entry.body))
+		if text == "" {
+			continue
+		}
+		lines = append(lines, "- "+role+": "+text)
+	}
+	if len(lines) == 0 && toolCalls == 0 {
+		return nil
+	}
+	from, to := entries[0].timestamp, entries[len(entries)-1].timestamp
+	heading := fmt.Sprintf("## %s to %s (%d entries", formatDay(from), formatDay(to), len(entries))
+	if toolCalls > 0 {
+		heading += fmt.Sprintf(", %d tool calls", toolCalls)
+	}
+	heading += ")"
+	section := heading + "\n" + strings.Join(lines, "\n") + "\n"
+
+	var sections []string
+	if data, err := os.ReadFile(path); err == nil {
+		_, sections = splitSummarySections(string(data))
+	}
+	sections = append(sections, section)
+	if len(sections) > maxSections {
+		sections = sections[len(sections)-maxSections:]
+	}
+	document := fmt.Sprintf("%s\n\n- connector: `%s`\n- external_id: `%s`\n\n%s", summaryTitle, connector, externalID, strings.Join(sections, "\n"))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, document)
+}
+
+func splitSummarySections(content string) (string, []string) {
+	parts := strings.Split(content, "\n## ")
+	sections := make([]string, 0, len(parts))
+	for _, part := range parts[1:] {
+		sections = append(sections, "## "+strings.TrimSpace(part)+"\n")
+	}
+	return parts[0], sections
+}
+
+// messageText drops the direction/actor metadata lines memorylog writes
+// before the message itself.
+func messageText(body string) string {
+	kept := []string{}
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "- direction:") || strings.HasPrefix(trimmed, "- actor:") {
+			continue
+		}
+		kept = append(kept, trimmed)
+	}
+	return strings.Join(kept, " ")
+}
+
+func condenseText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) > summaryLineMaxChars {
+		return string(runes[:summaryLineMaxChars]) + "..."
+	}
+	return text
+}
+
+func formatDay(value time.Time) string {
+	if value.IsZero() {
+		return "unknown"
+	}
+	return value.UTC().Format("2006-01-02")
+}
+
+func writeFileAtomic(path, content string) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), ".compact-*")
+	if err != nil {
+		return err
+	}
+	if _, err := temp.WriteString(content); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package memorylog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func appendTestTurns(t *testing.T, root string, start time.Time, count int) {
+	t.Helper()
+	for index := 0; index < count; index++ {
+		at := start.Add(time.Duration(index) * time.Minute)
+		entries := []Entry{
+			{Direction: "inbound", ActorID: "user-1", Text: fmt.Sprintf("question %d about webhooks", index)},
+			{Direction: "tool", ActorID: "agent-runtime", Text: "Tool call\n- tool: `fetch_url`\n- status: `ok`"},
+			{Direction: "outbound", ActorID: "agent-runtime", Text: fmt.Sprintf("answer %d", index)},
+		}
+		for _, entry := range entries {
+			entry.WorkspaceRoot = root
+			entry.WorkspaceID = "ws-1"
+			entry.Connector = "discord"
+			entry.ExternalID = "chan-1"
+			entry.Timestamp = at
+			if err := Append(entry); err != nil {
+				t.Fatalf("append: %v", err)
+			}
+		}
+	}
+}
+
+func TestCompactWorkspaceArchivesOldEntriesAndSummarizes(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	appendTestTurns(t, root, now.Add(-72*time.Hour), 10)
+
+	result, err := CompactWorkspace(CompactOptions{
+		WorkspaceRoot: root,
+		WorkspaceID:   "ws-1",
+		KeepEntries:   6,
+		MinAge:        24 * time.Hour,
+		Now:           now,
+	})
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if result.Chats != 1 || result.Entries != 24 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	logData, err := os.ReadFile(filepath.Join(root, "ws-1", "logs", "chats", "discord", "chan-1.md"))
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	live := string(logData)
+	if !strings.HasPrefix(live, "# Chat Log") || strings.Count(live, "\n## ") != 6 {
+		t.Fatalf("expected header and six live entries, got:\n%s", live)
+	}
+	if strings.Contains(live, "question 7 ") || !strings.Contains(live, "question 8 ") {
+		t.Fatalf("expected the newest turns to stay live, got:\n%s", live)
+	}
+
+	archive, err := os.ReadFile(filepath.Join(root, "ws-1", "logs", "archive", "chats", "discord", "chan-1.md"))
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	if strings.Count(string(archive), "\n## ") != 24 {
+		t.Fatalf("expected every compacted entry in the archive, got:\n%s", archive)
+	}
+
+	summary := ReadSummary(root, "ws-1", "discord", "chan-1", 0)
+	if !strings.Contains(summary, "(24 entries, 8 tool calls)") {
+		t.Fatalf("expected section heading with counts, got:\n%s", summary)
+	}
+	if !strings.Contains(summary, "- user: question 0 about webhooks") || !strings.Contains(summary, "- assistant: answer 7") {
+		t.Fatalf("expected condensed turns, got:\n%s", summary)
+	}
+	if strings.Contains(summary, "fetch_url") {
+		t.Fatalf("expected tool output to stay out of the summary, got:\n%s", summary)
+	}
+
+	// Appending after compaction still extends the live log.
+	appendTestTurns(t, root, now, 1)
+	logData, _ = os.ReadFile(filepath.Join(root, "ws-1", "logs", "chats", "discord", "chan-1.md"))
+	if strings.Count(string(logData), "\n## ") != 9 {
+		t.Fatalf("expected appended entries after compaction, got:\n%s", logData)
+	}
+}
+
+func TestCompactWorkspaceKeepsRecentEntriesAndCapsSections(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	appendTestTurns(t, root, now.Add(-time.Hour), 10)
+
+	result, err := CompactWorkspace(CompactOptions{
+		WorkspaceRoot: root,
+		WorkspaceID:   "ws-1",
+		KeepEntries:   3,
+		MinAge:        24 * time.Hour,
+		Now:           now,
+	})
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if result.Entries != 0 {
+		t.Fatalf("expected entries younger than MinAge to stay, got %+v", result)
+	}
+
+	for round := 0; round < 3; round++ {
+		appendTestTurns(t, root, now.Add(time.Duration(round)*time.Hour), 2)
+		if _, err := CompactWorkspace(CompactOptions{
+			WorkspaceRoot: root,
+			WorkspaceID:   "ws-1",
+			KeepEntries:   3,
+			MaxSections:   2,
+			Now:           now.Add(96 * time.Hour),
+		}); err != nil {
+			t.Fatalf("compact round %d: %v", round, err)
+		}
+	}
+	data, err := os.ReadFile(SummaryPath(root, "ws-1", "discord", "chan-1"))
+	if err != nil {
+		t.Fatalf("read summary: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# Chat Memory") || strings.Count(string(data), "\n## ") != 2 {
+		t.Fatalf("expected two rolling sections, got:\n%s", data)
+	}
+	if got := ReadSummary(root, "ws-1", "discord", "chan-1", 1); strings.Count(got, "## ") != 1 {
+		t.Fatalf("expected ReadSummary to honor maxSections, got:\n%s", got)
+	}
+}
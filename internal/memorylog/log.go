@@ -14,15 +14,33 @@ type Entry struct {
 	WorkspaceID   string
 	Connector     string
 	ExternalID    string
-	Direction     string
-	ActorID       string
-	DisplayName   string
-	Text          string
-	Timestamp     time.Time
+	// ThreadID, when set, keeps the entry in the thread's own log instead
+	// of the channel's; see ConversationID.
+	ThreadID    string
+	Direction   string
+	ActorID     string
+	DisplayName string
+	Text        string
+	Timestamp   time.Time
 }
 
 var pathSanitizer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
 
+// Redactor rewrites entry text before it is written, for example to replace
+// personal data with tokens.
+type Redactor interface {
+	Redact(workspaceID, text string) string
+}
+
+var redactor Redactor
+
+// SetRedactor makes Append pass every entry's text through r.
+func SetRedactor(r Redactor) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	redactor = r
+}
+
 func Append(entry Entry) error {
 	workspaceRoot := strings.TrimSpace(entry.WorkspaceRoot)
 	workspaceID := strings.TrimSpace(entry.WorkspaceID)
@@ -38,7 +56,7 @@ func Append(entry Entry) error {
 	if connector == "" {
 		connector = "unknown"
 	}
-	externalID := sanitizeSegment(entry.ExternalID)
+	externalID := sanitizeSegment(ConversationID(entry.ExternalID, entry.ThreadID))
 	if externalID == "" {
 		externalID = "unknown"
 	}
@@ -47,6 +65,12 @@ func Append(entry Entry) error {
 		timestamp = time.Now().UTC()
 	}
 
+	logMu.Lock()
+	defer logMu.Unlock()
+	if redactor != nil {
+		text = redactor.Redact(workspaceID, text)
+	}
+
 	baseDir := filepath.Join(workspaceRoot, workspaceID, "logs", "chats", connector)
 	if err := os.MkdirAll(baseDir, 0o755); err != nil {
 		return err
@@ -92,6 +116,20 @@ func Append(entry Entry) error {
 	return nil
 }
 
+// ConversationID is the chat log key of a thread (a Telegram topic or a
+// Discord thread) inside the channel externalID. Threads get their own log,
+// summary and recent history so parallel conversations in one channel stay
+// apart; the channel's context and policy still apply to them. An empty
+// threadID is the channel itself.
+func ConversationID(externalID, threadID string) string {
+	externalID = strings.TrimSpace(externalID)
+	threadID = strings.TrimSpace(threadID)
+	if threadID == "" || externalID == "" || threadID == externalID {
+		return externalID
+	}
+	return externalID + "-thread-" + threadID
+}
+
 func sanitizeSegment(value string) string {
 	trimmed := strings.TrimSpace(value)
 	trimmed = strings.ReplaceAll(trimmed, " ", "-")
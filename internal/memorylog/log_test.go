@@ -56,3 +56,62 @@ func TestAppendSkipsEmptyText(t *testing.T) {
 		t.Fatalf("expected no file for empty text, got err=%v", err)
 	}
 }
+
+type emailRedactor struct{}
+
+func (emailRedactor) Redact(workspaceID, text string) string {
+	return strings.ReplaceAll(text, "ops@example.com", "[EMAIL_1]")
+}
+
+func TestAppendAppliesRedactor(t *testing.T) {
+	SetRedactor(emailRedactor{})
+	t.Cleanup(func() { SetRedactor(nil) })
+	root := t.TempDir()
+	err := Append(Entry{
+		WorkspaceRoot: root,
+		WorkspaceID:   "ws-1",
+		Connector:     "telegram",
+		ExternalID:    "42",
+		Text:          "mail ops@example.com",
+	})
+	if err != nil {
+		t.Fatalf("append failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "ws-1", "logs", "chats", "telegram", "42.md"))
+	if err != nil {
+		t.Fatalf("read log failed: %v", err)
+	}
+	if strings.Contains(string(data), "ops@example.com") || !strings.Contains(string(data), "mail [EMAIL_1]") {
+		t.Fatalf("expected redacted log, got %s", data)
+	}
+}
+
+func TestAppendKeepsThreadsApartFromTheirChannel(t *testing.T) {
+	root := t.TempDir()
+	for _, entry := range []Entry{
+		{ExternalID: "-100200", Text: "channel message"},
+		{ExternalID: "-100200", ThreadID: "7", Text: "topic message"},
+	} {
+		entry.WorkspaceRoot = root
+		entry.WorkspaceID = "ws-1"
+		entry.Connector = "telegram"
+		if err := Append(entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	channel, err := os.ReadFile(filepath.Join(root, "ws-1", "logs", "chats", "telegram", "100200.md"))
+	if err != nil {
+		t.Fatalf("read channel log failed: %v", err)
+	}
+	topic, err := os.ReadFile(filepath.Join(root, "ws-1", "logs", "chats", "telegram", "100200-thread-7.md"))
+	if err != nil {
+		t.Fatalf("read topic log failed: %v", err)
+	}
+	if strings.Contains(string(channel), "topic message") || !strings.Contains(string(topic), "topic message") {
+		t.Fatalf("expected the topic message only in the topic log, channel=%q topic=%q", channel, topic)
+	}
+	if got := ConversationID("42", ""); got != "42" {
+		t.Fatalf("expected the channel key without a thread, got %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+package memorylog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Chat is one live chat log of a workspace.
+type Chat struct {
+	Connector  string
+	ExternalID string
+	UpdatedAt  time.Time
+	SizeBytes  int64
+}
+
+// Record is one parsed chat log entry. Direction is inbound, outbound or
+// tool; tool entries are the agent's tool calls.
+type Record struct {
+	Timestamp time.Time
+	Direction string
+	Actor     string
+	Text      string
+}
+
+// ListChats returns the live chat logs of a workspace, most recently written
+// first.
+func ListChats(workspaceRoot, workspaceID string) ([]Chat, error) {
+	root := strings.TrimSpace(workspaceRoot)
+	workspaceID = strings.TrimSpace(workspaceID)
+	if root == "" || workspaceID == "" {
+		return nil, nil
+	}
+	if !validWorkspaceDir(workspaceID) {
+		return nil, fmt.Errorf("invalid workspace id %q", workspaceID)
+	}
+	chatsDir := filepath.Join(root, workspaceID, "logs", "chats")
+	connectors, err := os.ReadDir(chatsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list chat logs: %w", err)
+	}
+	chats := []Chat{}
+	for _, connector := range connectors {
+		if !connector.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(chatsDir, connector.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("list chat logs: %w", err)
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				continue
+			}
+			chats = append(chats, Chat{
+				Connector:  connector.Name(),
+				ExternalID: strings.TrimSuffix(file.Name(), ".md"),
+				UpdatedAt:  info.ModTime().UTC(),
+				SizeBytes:  info.Size(),
+			})
+		}
+	}
+	sort.SliceStable(chats, func(i, j int) bool {
+		return chats[i].UpdatedAt.After(chats[j].UpdatedAt)
+	})
+	return chats, nil
+}
+
+// ReadEntries returns the newest limit entries of a chat's live log, oldest
+// first. Entries already compacted into the archive are not included. A chat
+// without a log has no entries.
+func ReadEntries(workspaceRoot, workspaceID, connector, externalID string, limit int) ([]Record, error) {
+	root := strings.TrimSpace(workspaceRoot)
+	workspaceID = strings.TrimSpace(workspaceID)
+	connector = sanitizeSegment(connector)
+	externalID = sanitizeSegment(externalID)
+	if root == "" || workspaceID == "" || connector == "" || externalID == "" {
+		return nil, nil
+	}
+	if !validWorkspaceDir(workspaceID) {
+		return nil, fmt.Errorf("invalid workspace id %q", workspaceID)
+	}
+	logPath := filepath.Join(root, workspaceID, "logs", "chats", connector, externalID+".md")
+
+	logMu.Lock()
+	data, err := os.ReadFile(logPath)
+	logMu.Unlock()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read chat log: %w", err)
+	}
+	_, entries := splitEntries(string(data))
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	records := make([]Record, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, parseRecord(entry))
+	}
+	return records, nil
+}
+
+// parseRecord splits the direction/actor metadata Append writes from the
+// entry text, keeping the text's own line breaks.
+func parseRecord(entry logEntry) Record {
+	record := Record{Timestamp: entry.timestamp, Direction: entry.direction}
+	lines := strings.Split(entry.body, "\n")
+	index := 0
+	for ; index < len(lines); index++ {
+		trimmed := strings.TrimSpace(lines[index])
+		if strings.HasPrefix(trimmed, "- direction:") {
+			continue
+		}
+		if value, ok := strings.CutPrefix(trimmed, "- actor:"); ok {
+			record.Actor = strings.Trim(strings.TrimSpace(value), "`")
+			continue
+		}
+		break
+	}
+	record.Text = strings.TrimSpace(strings.Join(lines[index:], "\n"))
+	return record
+}
+
+// validWorkspaceDir rejects workspace ids that would leave the workspace
+// root. Append joins the id as is, so readers cannot sanitize it the way they
+// do connector and external ids.
+func validWorkspaceDir(workspaceID string) bool {
+	return workspaceID != "." && workspaceID != ".." && !strings.ContainsAny(workspaceID, `/\`)
+}
@@ -0,0 +1,63 @@
+package memorylog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadEntriesReturnsNewestEntriesWithToolCalls(t *testing.T) {
+	root := t.TempDir()
+	start := time.Unix(1700000000, 0).UTC()
+	entries := []Entry{
+		{Direction: "inbound", ActorID: "user-1", Text: "first question"},
+		{Direction: "tool", ActorID: "agent-runtime", Text: "Tool call\n- tool: `search`\n- status: `succeeded`"},
+		{Direction: "outbound", ActorID: "agent-runtime", Text: "the answer"},
+	}
+	for index, entry := range entries {
+		entry.WorkspaceRoot = root
+		entry.WorkspaceID = "ws-1"
+		entry.Connector = "telegram"
+		entry.ExternalID = "42"
+		entry.Timestamp = start.Add(time.Duration(index) * time.Second)
+		if err := Append(entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	records, err := ReadEntries(root, "ws-1", "Telegram", "42", 2)
+	if err != nil {
+		t.Fatalf("read entries failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected the 2 newest entries, got %+v", records)
+	}
+	tool := records[0]
+	if tool.Direction != "tool" || tool.Actor != "agent-runtime" || !tool.Timestamp.Equal(start.Add(time.Second)) {
+		t.Fatalf("unexpected tool record %+v", tool)
+	}
+	if tool.Text != "Tool call\n- tool: `search`\n- status: `succeeded`" {
+		t.Fatalf("expected tool text with its lines intact, got %q", tool.Text)
+	}
+	if records[1].Direction != "outbound" || records[1].Text != "the answer" {
+		t.Fatalf("unexpected last record %+v", records[1])
+	}
+
+	chats, err := ListChats(root, "ws-1")
+	if err != nil {
+		t.Fatalf("list chats failed: %v", err)
+	}
+	if len(chats) != 1 || chats[0].Connector != "telegram" || chats[0].ExternalID != "42" || chats[0].SizeBytes == 0 {
+		t.Fatalf("unexpected chats %+v", chats)
+	}
+}
+
+func TestReadEntriesRejectsEscapingWorkspace(t *testing.T) {
+	root := t.TempDir()
+	if _, err := ReadEntries(root, "..", "telegram", "42", 10); err == nil {
+		t.Fatal("expected error for workspace id outside the root")
+	}
+	records, err := ReadEntries(root, "ws-1", "telegram", "missing", 10)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("expected no entries for a missing chat, got %+v err=%v", records, err)
+	}
+}
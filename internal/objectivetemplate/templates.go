@@ -0,0 +1,275 @@
+// Package objectivetemplate holds the built-in objective templates. A
+// template turns a few named parameters into a vetted objective prompt and
+// trigger, so common monitors do not depend on freeform prompt wording.
+package objectivetemplate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+var (
+	ErrUnknownTemplate = errors.New("unknown objective template")
+	ErrInvalidParams   = errors.New("invalid template parameters")
+)
+
+// Param describes one template parameter. Parameters without a default are
+// required.
+type Param struct {
+	Name        string
+	Description string
+	Default     string
+}
+
+func (p Param) Required() bool {
+	return p.Default == ""
+}
+
+type Template struct {
+	Name        string
+	Description string
+	TriggerType store.ObjectiveTriggerType
+	Params      []Param
+
+	title  string
+	prompt string
+}
+
+// Objective is a template instantiated with concrete parameter values.
+type Objective struct {
+	Title       string
+	Prompt      string
+	TriggerType store.ObjectiveTriggerType
+	CronExpr    string
+	Timezone    string
+}
+
+// Schedule templates accept these in addition to their own parameters.
+var scheduleParams = []Param{
+	{Name: "timezone", Description: "IANA timezone for the schedule", Default: "UTC"},
+}
+
+var builtins = []Template{
+	{
+		Name:        "release-watch",
+		Description: "Report new releases or tags of a GitHub repository",
+		TriggerType: store.ObjectiveTriggerSchedule,
+		Params: []Param{
+			{Name: "repo", Description: "Repository as owner/name"},
+			{Name: "cron", Description: "Cron schedule", Default: "0 */6 * * *"},
+		},
+		title: "Release watch: {repo}",
+		prompt: "Check the GitHub repository {repo} for releases or tags published since the last run.\n" +
+			"For each new release report the version, publish date, and the most important changes in at most three bullets, with a link.\n" +
+			"If nothing new was published, reply with exactly: No new releases.",
+	},
+	{
+		Name:        "uptime-check",
+		Description: "Check that a URL responds as expected and report failures",
+		TriggerType: store.ObjectiveTriggerSchedule,
+		Params: []Param{
+			{Name: "url", Description: "URL to request"},
+			{Name: "expect", Description: "Expected response", Default: "HTTP 200"},
+			{Name: "cron", Description: "Cron schedule", Default: "*/15 * * * *"},
+		},
+		title: "Uptime check: {url}",
+		prompt: "Request {url} once and compare the response with the expectation: {expect}.\n" +
+			"Report the status code, response time, and any error only when the check fails or when it recovers after a failure reported earlier in this channel.\n" +
+			"If the check passes and nothing changed, reply with exactly: OK.",
+	},
+	{
+		Name:        "weekly-digest",
+		Description: "Summarize the past week of workspace activity",
+		TriggerType: store.ObjectiveTriggerSchedule,
+		Params: []Param{
+			{Name: "topic", Description: "What the digest should focus on", Default: "workspace activity"},
+			{Name: "cron", Description: "Cron schedule", Default: "0 9 * * 1"},
+		},
+		title: "Weekly digest: {topic}",
+		prompt: "Write a digest of the last 7 days focused on {topic}.\n" +
+			"Cover completed and failed tasks, decisions recorded in workspace documents, and open follow-ups, citing document paths or task ids.\n" +
+			"Keep it under 15 bullets grouped under short headings, and say so plainly if there was no relevant activity.",
+	},
+	{
+		Name:        "spam-sweep",
+		Description: "Review recent channel messages for spam and abuse",
+		TriggerType: store.ObjectiveTriggerSchedule,
+		Params: []Param{
+			{Name: "scope", Description: "Which messages to review", Default: "messages received since the last sweep"},
+			{Name: "cron", Description: "Cron schedule", Default: "0 * * * *"},
+		},
+		title: "Spam sweep: {scope}",
+		prompt: "Review {scope} for spam, scams, phishing links, and abusive content.\n" +
+			"List each suspected message with its sender, a short quote, and the reason, and recommend a moderation action.\n" +
+			"Do not take moderation actions yourself. If nothing is suspicious, reply with exactly: No spam found.",
+	},
+}
+
+// List returns the built-in templates sorted by name.
+func List() []Template {
+	items := make([]Template, len(builtins))
+	copy(items, builtins)
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items
+}
+
+func Lookup(name string) (Template, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, item := range builtins {
+		if item.Name == name {
+			return item, true
+		}
+	}
+	return Template{}, false
+}
+
+// Build looks up a template by name and instantiates it.
+func Build(name string, values map[string]string) (Objective, error) {
+	template, ok := Lookup(name)
+	if !ok {
+		return Objective{}, fmt.Errorf("%w: %s", ErrUnknownTemplate, strings.TrimSpace(name))
+	}
+	return template.Instantiate(values)
+}
+
+// Usage renders the template and its parameters as one line, e.g.
+// "release-watch repo=<owner/name> [cron=0 */6 * * *]".
+func (t Template) Usage() string {
+	parts := []string{t.Name}
+	for _, param := range t.AllParams() {
+		if param.Required() {
+			parts = append(parts, param.Name+"=<"+strings.ToLower(param.Description)+">")
+			continue
+		}
+		parts = append(parts, "["+param.Name+"="+param.Default+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// AllParams returns the template parameters plus the shared schedule
+// parameters it does not define itself.
+func (t Template) AllParams() []Param {
+	params := append([]Param{}, t.Params...)
+	if t.TriggerType != store.ObjectiveTriggerSchedule {
+		return params
+	}
+	for _, shared := range scheduleParams {
+		if _, ok := t.param(shared.Name); !ok {
+			params = append(params, shared)
+		}
+	}
+	return params
+}
+
+func (t Template) param(name string) (Param, bool) {
+	for _, item := range t.Params {
+		if item.Name == name {
+			return item, true
+		}
+	}
+	return Param{}, false
+}
+
+// Instantiate fills the template with values. Unknown parameters and missing
+// required ones are rejected so typos do not silently change the prompt.
+func (t Template) Instantiate(values map[string]string) (Objective, error) {
+	params := t.AllParams()
+	known := map[string]bool{}
+	resolved := map[string]string{}
+	missing := []string{}
+	for _, param := range params {
+		known[param.Name] = true
+		value := strings.TrimSpace(values[param.Name])
+		if value == "" {
+			value = param.Default
+		}
+		if value == "" {
+			missing = append(missing, param.Name)
+		}
+		resolved[param.Name] = value
+	}
+	unknown := []string{}
+	for name := range values {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	if len(unknown) > 0 {
+		return Objective{}, fmt.Errorf("%w: unknown parameter %s for %s", ErrInvalidParams, strings.Join(unknown, ", "), t.Name)
+	}
+	if len(missing) > 0 {
+		return Objective{}, fmt.Errorf("%w: %s requires %s", ErrInvalidParams, t.Name, strings.Join(missing, ", "))
+	}
+	pairs := make([]string, 0, len(resolved)*2)
+	for name, value := range resolved {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	replacer := strings.NewReplacer(pairs...)
+	title := replacer.Replace(t.title)
+	if len(title) > 72 {
+		title = title[:72]
+	}
+	return Objective{
+		Title:       title,
+		Prompt:      replacer.Replace(t.prompt),
+		TriggerType: t.TriggerType,
+		CronExpr:    resolved["cron"],
+		Timezone:    resolved["timezone"],
+	}, nil
+}
+
+// ParseArgs parses `<name> key=value key="value with spaces"`.
+func ParseArgs(input string) (string, map[string]string, error) {
+	fields, err := splitQuoted(input)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("%w: template name is required", ErrInvalidParams)
+	}
+	values := map[string]string{}
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return "", nil, fmt.Errorf("%w: expected key=value, got %q", ErrInvalidParams, field)
+		}
+		values[key] = value
+	}
+	return strings.ToLower(fields[0]), values, nil
+}
+
+func splitQuoted(input string) ([]string, error) {
+	fields := []string{}
+	var current strings.Builder
+	inQuote := false
+	started := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			started = true
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			if started {
+				fields = append(fields, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("%w: unterminated quote", ErrInvalidParams)
+	}
+	if started {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
@@ -0,0 +1,52 @@
+package objectivetemplate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestInstantiateFillsParamsAndDefaults(t *testing.T) {
+	name, values, err := ParseArgs(`release-watch repo=acme/app timezone="Europe/Berlin"`)
+	if err != nil {
+		t.Fatalf("parse args: %v", err)
+	}
+	template, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("expected template %s", name)
+	}
+	objective, err := template.Instantiate(values)
+	if err != nil {
+		t.Fatalf("instantiate: %v", err)
+	}
+	if objective.Title != "Release watch: acme/app" || objective.CronExpr != "0 */6 * * *" || objective.Timezone != "Europe/Berlin" {
+		t.Fatalf("unexpected objective %+v", objective)
+	}
+	if objective.TriggerType != store.ObjectiveTriggerSchedule || !strings.Contains(objective.Prompt, "repository acme/app") || strings.Contains(objective.Prompt, "{") {
+		t.Fatalf("unexpected prompt %q", objective.Prompt)
+	}
+
+	uptime, _ := Lookup("uptime-check")
+	objective, err = uptime.Instantiate(map[string]string{"url": "https://example.com/health", "cron": "*/5 * * * *"})
+	if err != nil || objective.CronExpr != "*/5 * * * *" || !strings.Contains(objective.Prompt, "HTTP 200") {
+		t.Fatalf("expected cron override and default expectation, got %+v (%v)", objective, err)
+	}
+}
+
+func TestInstantiateRejectsMissingAndUnknownParams(t *testing.T) {
+	template, _ := Lookup("release-watch")
+	if _, err := template.Instantiate(nil); !errors.Is(err, ErrInvalidParams) || !strings.Contains(err.Error(), "requires repo") {
+		t.Fatalf("expected missing repo error, got %v", err)
+	}
+	if _, err := template.Instantiate(map[string]string{"repo": "acme/app", "rep": "x"}); !errors.Is(err, ErrInvalidParams) || !strings.Contains(err.Error(), "unknown parameter rep") {
+		t.Fatalf("expected unknown parameter error, got %v", err)
+	}
+	if _, _, err := ParseArgs(`weekly-digest topic="unterminated`); !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("expected quote error, got %v", err)
+	}
+	if len(List()) != 4 || List()[0].Name != "release-watch" {
+		t.Fatalf("unexpected template list %+v", List())
+	}
+}
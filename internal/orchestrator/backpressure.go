@@ -0,0 +1,127 @@
+package orchestrator
+
+// BackpressureState describes the queue when it crosses the backpressure
+// threshold in either direction.
+type BackpressureState struct {
+	Active    bool
+	Depth     int
+	Threshold int
+	// Held is the number of low-priority tasks waiting outside the queue.
+	Held int
+}
+
+// BackpressureListener hears when the queue starts or stops being busy.
+// It is called on the goroutine that caused the change and must not block.
+type BackpressureListener interface {
+	OnBackpressure(state BackpressureState)
+}
+
+// SetBackpressure turns on backpressure tracking: the queue counts as busy
+// once threshold tasks are waiting and calms down again at half of that.
+// A threshold below one uses three quarters of the queue capacity.
+func (e *Engine) SetBackpressure(threshold int, listener BackpressureListener) {
+	if threshold < 1 || threshold > cap(e.tasks) {
+		threshold = cap(e.tasks) * 3 / 4
+	}
+	e.pressureMu.Lock()
+	e.pressureThreshold = threshold
+	e.pressureListener = listener
+	e.pressureMu.Unlock()
+}
+
+// QueueDepth is the number of tasks waiting for a worker.
+func (e *Engine) QueueDepth() int {
+	return len(e.tasks)
+}
+
+// UnderPressure reports whether the queue is busy enough that new
+// low-priority work should wait.
+func (e *Engine) UnderPressure() bool {
+	e.pressureMu.Lock()
+	defer e.pressureMu.Unlock()
+	return e.pressured
+}
+
+// Hold accepts a low-priority task. While the queue is busy the task waits
+// outside it and is queued once the pressure ends; otherwise it is queued
+// right away. Like with Enqueue, the caller persists the task as queued, so
+// a restart recovers held tasks too.
+func (e *Engine) Hold(task Task) (Task, error) {
+	task = withTaskDefaults(task)
+	if e.admission != nil {
+		if err := e.admission.Admit(task); err != nil {
+			return Task{}, err
+		}
+	}
+	if e.holdUntilDue(task) {
+		return task, nil
+	}
+	if waiting, err := e.waitForDependencies(task); waiting || err != nil {
+		if err != nil {
+			return Task{}, err
+		}
+		return task, nil
+	}
+	e.pressureMu.Lock()
+	if !e.pressured {
+		e.pressureMu.Unlock()
+		queued, err := e.push(task)
+		if err == nil {
+			e.updatePressure()
+		}
+		return queued, err
+	}
+	e.held = append(e.held, task)
+	e.pressureMu.Unlock()
+	e.logger.Info("task held while queue is busy", "task_id", task.ID, "workspace_id", task.WorkspaceID)
+	return task, nil
+}
+
+// updatePressure re-evaluates the queue after a task entered or left it and
+// releases held tasks once the pressure has ended.
+func (e *Engine) updatePressure() {
+	e.pressureMu.Lock()
+	threshold := e.pressureThreshold
+	if threshold < 1 {
+		e.pressureMu.Unlock()
+		return
+	}
+	depth := len(e.tasks)
+	changed := false
+	switch {
+	case !e.pressured && depth >= threshold:
+		e.pressured, changed = true, true
+	case e.pressured && depth <= threshold/2:
+		e.pressured, changed = false, true
+	}
+	release := []Task{}
+	if !e.pressured && len(e.held) > 0 {
+		room := threshold - 1 - depth
+		if room > len(e.held) {
+			room = len(e.held)
+		}
+		if room > 0 {
+			release = append(release, e.held[:room]...)
+			e.held = e.held[room:]
+		}
+	}
+	state := BackpressureState{Active: e.pressured, Depth: depth, Threshold: threshold, Held: len(e.held)}
+	listener := e.pressureListener
+	e.pressureMu.Unlock()
+
+	if changed {
+		e.logger.Info("task queue backpressure changed", "active", state.Active, "depth", depth, "threshold", threshold, "held", state.Held)
+		if listener != nil {
+			listener.OnBackpressure(state)
+		}
+	}
+	for index, task := range release {
+		if _, err := e.push(task); err != nil {
+			// The queue filled up again; keep the rest for the next release.
+			e.pressureMu.Lock()
+			e.held = append(append([]Task{}, release[index:]...), e.held...)
+			e.pressureMu.Unlock()
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrTaskCancelled is the cause of the context of a task stopped by Cancel.
+var ErrTaskCancelled = errors.New("task cancelled")
+
+// CancelObserver is implemented by task observers that want to hear when a
+// running task stopped because it was cancelled. Such a task is reported
+// neither as completed nor as failed.
+type CancelObserver interface {
+	OnTaskCancelled(task Task, workerID int)
+}
+
+// Cancel stops a task. A running task has its context cancelled, which
+// reaches the agent turn and any process the executor started; a task
+// waiting for its RunAt time, a worker, its prerequisites, a calmer queue or
+// its next retry is dropped without running. Cancel reports whether the
+// task was running. The caller records the cancellation, so dependents of
+// the task see it when they are re-checked.
+func (e *Engine) Cancel(taskID string) bool {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return false
+	}
+	if !e.dropWaiting(taskID) {
+		running, tracked := e.cancelTracked(taskID)
+		if running {
+			e.logger.Info("running task cancelled", "task_id", taskID)
+			return true
+		}
+		if !tracked {
+			e.logger.Info("cancelled task is not tracked here", "task_id", taskID)
+			return false
+		}
+	}
+	e.logger.Info("waiting task cancelled", "task_id", taskID)
+	e.releaseBlocked()
+	return false
+}
+
+// dropWaiting removes a scheduled task, one parked for its prerequisites or
+// one held while the queue is busy, reporting whether it found one.
+func (e *Engine) dropWaiting(taskID string) bool {
+	if e.dropScheduled(taskID) {
+		return true
+	}
+	e.blockedMu.Lock()
+	blocked, found := removeTask(e.blocked, taskID)
+	e.blocked = blocked
+	e.blockedMu.Unlock()
+	if found {
+		return true
+	}
+	e.pressureMu.Lock()
+	held, found := removeTask(e.held, taskID)
+	e.held = held
+	e.pressureMu.Unlock()
+	return found
+}
+
+func removeTask(tasks []Task, taskID string) ([]Task, bool) {
+	for index, task := range tasks {
+		if task.ID == taskID {
+			return append(tasks[:index:index], tasks[index+1:]...), true
+		}
+	}
+	return tasks, false
+}
+
+// cancelTracked stops a running task, or remembers the cancellation of one
+// sitting in the queue or waiting for its retry so the worker or retry timer
+// that picks it up drops it. Tasks this engine does not hold are not
+// remembered, since nothing would ever consume the entry.
+func (e *Engine) cancelTracked(taskID string) (running, tracked bool) {
+	e.cancelMu.Lock()
+	defer e.cancelMu.Unlock()
+	if stop, ok := e.running[taskID]; ok {
+		stop(ErrTaskCancelled)
+		return true, true
+	}
+	e.leaseMu.Lock()
+	queued := e.queuedIDs[taskID] > 0
+	e.leaseMu.Unlock()
+	if _, retrying := e.retrying[taskID]; !queued && !retrying {
+		return false, false
+	}
+	if e.cancelled == nil {
+		e.cancelled = map[string]struct{}{}
+	}
+	e.cancelled[taskID] = struct{}{}
+	return false, true
+}
+
+// startTask takes a task off the queue count and gives it its own context
+// so Cancel can stop it. Both happen under cancelMu, so a concurrent Cancel
+// either finds the task queued or running. It reports false for a task
+// cancelled while it was queued.
+func (e *Engine) startTask(ctx context.Context, taskID string) (context.Context, bool) {
+	e.cancelMu.Lock()
+	defer e.cancelMu.Unlock()
+	e.markQueued(taskID, -1)
+	if _, ok := e.cancelled[taskID]; ok {
+		delete(e.cancelled, taskID)
+		return nil, false
+	}
+	taskCtx, stop := context.WithCancelCause(ctx)
+	if e.running == nil {
+		e.running = map[string]context.CancelCauseFunc{}
+	}
+	e.running[taskID] = stop
+	return taskCtx, true
+}
+
+func (e *Engine) finishTask(taskID string) {
+	e.cancelMu.Lock()
+	stop := e.running[taskID]
+	delete(e.running, taskID)
+	e.cancelMu.Unlock()
+	if stop != nil {
+		stop(nil)
+	}
+}
+
+// wasCancelled reports a run stopped by Cancel to the observer.
+func (e *Engine) wasCancelled(ctx context.Context, workerID int, task Task) bool {
+	if !errors.Is(context.Cause(ctx), ErrTaskCancelled) {
+		return false
+	}
+	e.logger.Info("task stopped after cancellation", "worker_id", workerID, "task_id", task.ID)
+	if observer, ok := e.observer.(CancelObserver); ok {
+		observer.OnTaskCancelled(task, workerID)
+	}
+	return true
+}
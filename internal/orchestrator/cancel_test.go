@@ -0,0 +1,169 @@
+package orchestrator
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// cancellableExecutor runs until its context ends, like an agent turn
+// waiting on the model.
+type cancellableExecutor struct {
+	started chan string
+}
+
+func (e *cancellableExecutor) Execute(ctx context.Context, task Task) (TaskResult, error) {
+	e.started <- task.ID
+	<-ctx.Done()
+	return TaskResult{}, ctx.Err()
+}
+
+type cancelRecorder struct {
+	*testObserver
+	cancelled chan string
+}
+
+func (r *cancelRecorder) OnTaskCancelled(task Task, workerID int) {
+	r.cancelled <- task.ID
+}
+
+func startCancelEngine(t *testing.T, executor TaskExecutor) (*Engine, *cancelRecorder) {
+	t.Helper()
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	observer := &cancelRecorder{testObserver: newTestObserver(), cancelled: make(chan string, 4)}
+	engine.SetExecutor(executor)
+	engine.SetObserver(observer)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = engine.Start(ctx)
+	}()
+	return engine, observer
+}
+
+func TestCancelStopsRunningTask(t *testing.T) {
+	executor := &cancellableExecutor{started: make(chan string, 1)}
+	engine, observer := startCancelEngine(t, executor)
+	task, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "Long research"})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	select {
+	case <-executor.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("task did not start")
+	}
+	if !engine.Cancel(task.ID) {
+		t.Fatal("expected the task to be reported as running")
+	}
+	select {
+	case id := <-observer.cancelled:
+		if id != task.ID {
+			t.Fatalf("unexpected cancelled task %s", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancellation was not reported")
+	}
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if len(observer.failed) != 0 || len(observer.completed) != 0 {
+		t.Fatalf("cancelled task must not complete or fail: failed=%v completed=%d", observer.failed, len(observer.completed))
+	}
+}
+
+func TestCancelDropsQueuedTask(t *testing.T) {
+	executor := &blockingExecutor{started: make(chan struct{}, 2), release: make(chan struct{})}
+	engine, observer := startCancelEngine(t, executor)
+	if _, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "First"}); err != nil {
+		t.Fatalf("enqueue first: %v", err)
+	}
+	<-executor.started
+	second, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "Second"})
+	if err != nil {
+		t.Fatalf("enqueue second: %v", err)
+	}
+	if engine.Cancel(second.ID) {
+		t.Fatal("queued task must not be reported as running")
+	}
+	close(executor.release)
+	<-observer.done
+	third, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "Third"})
+	if err != nil {
+		t.Fatalf("enqueue third: %v", err)
+	}
+	<-executor.started
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		observer.mu.Lock()
+		completed := len(observer.completed)
+		started := append([]Task{}, observer.started...)
+		observer.mu.Unlock()
+		if completed == 2 {
+			if len(started) != 2 || started[1].ID != third.ID {
+				t.Fatalf("expected the cancelled task to be skipped, started %+v", started)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected two completed tasks, got %d", completed)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCancelDropsBlockedTask(t *testing.T) {
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	resolver := &statusResolver{statuses: map[string]string{"task_a": "running"}}
+	engine.SetDependencyResolver(resolver)
+	task, err := engine.Enqueue(Task{ID: "task_b", WorkspaceID: "ws_1", DependsOn: []string{"task_a"}})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if engine.Blocked() != 1 {
+		t.Fatalf("expected one blocked task, got %d", engine.Blocked())
+	}
+	engine.Cancel(task.ID)
+	if engine.Blocked() != 0 {
+		t.Fatalf("expected the blocked task to be dropped, got %d", engine.Blocked())
+	}
+	if len(engine.cancelled) != 0 {
+		t.Fatalf("dropped tasks need no tombstone, got %v", engine.cancelled)
+	}
+}
+
+func TestCancelForgetsUnknownTasks(t *testing.T) {
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if engine.Cancel("task-elsewhere") {
+		t.Fatal("unknown task must not be reported as running")
+	}
+	engine.cancelMu.Lock()
+	remembered := len(engine.cancelled)
+	engine.cancelMu.Unlock()
+	if remembered != 0 {
+		t.Fatalf("expected no cancellation kept for an unknown task, got %d", remembered)
+	}
+}
+
+func TestCancelDropsTaskWaitingForRetry(t *testing.T) {
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	engine.scheduleRetry(context.Background(), Task{ID: "task-1", WorkspaceID: "ws_1"}, 20*time.Millisecond)
+	engine.Cancel("task-1")
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		engine.cancelMu.Lock()
+		pending := len(engine.retrying) + len(engine.cancelled)
+		engine.cancelMu.Unlock()
+		if pending == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the retry timer to consume the cancellation")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if engine.Tracks("task-1") || len(engine.tasks) != 0 {
+		t.Fatal("expected the cancelled retry not to be queued")
+	}
+}
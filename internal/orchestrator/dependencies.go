@@ -0,0 +1,126 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDependencyFailed is reported for a task whose prerequisite failed or no
+// longer exists; the task never runs.
+var ErrDependencyFailed = errors.New("task dependency failed")
+
+// DependencyState is how far the prerequisites of a task have come.
+type DependencyState int
+
+const (
+	// DependenciesPending means a prerequisite is still queued or running.
+	DependenciesPending DependencyState = iota
+	// DependenciesMet means every prerequisite succeeded.
+	DependenciesMet
+	// DependenciesFailed means a prerequisite failed or was removed.
+	DependenciesFailed
+)
+
+// DependencyResolver reports the state of the tasks a task depends on. It
+// is consulted when a task with DependsOn is enqueued and again each time a
+// task finishes.
+type DependencyResolver interface {
+	DependencyState(task Task) (DependencyState, error)
+}
+
+// SetDependencyResolver enables task dependencies. Without a resolver
+// DependsOn is ignored and tasks are queued right away.
+func (e *Engine) SetDependencyResolver(resolver DependencyResolver) {
+	e.blockedMu.Lock()
+	e.dependencies = resolver
+	e.blockedMu.Unlock()
+}
+
+// Blocked is the number of tasks waiting for their prerequisites.
+func (e *Engine) Blocked() int {
+	e.blockedMu.Lock()
+	defer e.blockedMu.Unlock()
+	return len(e.blocked)
+}
+
+// waitForDependencies parks task when one of its prerequisites has not
+// succeeded yet. It reports whether the task was parked; a task whose
+// prerequisite failed is rejected with ErrDependencyFailed.
+func (e *Engine) waitForDependencies(task Task) (bool, error) {
+	if len(task.DependsOn) == 0 {
+		return false, nil
+	}
+	e.blockedMu.Lock()
+	defer e.blockedMu.Unlock()
+	if e.dependencies == nil {
+		return false, nil
+	}
+	state, err := e.dependencies.DependencyState(task)
+	if err != nil {
+		return false, err
+	}
+	switch state {
+	case DependenciesMet:
+		return false, nil
+	case DependenciesFailed:
+		return false, fmt.Errorf("%w: task %s", ErrDependencyFailed, task.ID)
+	}
+	// The check and the append share the lock with releaseBlocked, so a
+	// prerequisite finishing in between cannot strand the task.
+	e.blocked = append(e.blocked, task)
+	e.logger.Info("task blocked by dependencies", "task_id", task.ID, "workspace_id", task.WorkspaceID, "depends_on", task.DependsOn)
+	return true, nil
+}
+
+// releaseBlocked re-checks the parked tasks after a task finished. Tasks
+// whose prerequisites all succeeded are queued; tasks with a failed
+// prerequisite are reported as failed, which in turn fails their own
+// dependents on the next pass.
+func (e *Engine) releaseBlocked() {
+	for {
+		e.blockedMu.Lock()
+		if e.dependencies == nil || len(e.blocked) == 0 {
+			e.blockedMu.Unlock()
+			return
+		}
+		ready, failed, waiting := []Task{}, []Task{}, make([]Task, 0, len(e.blocked))
+		for _, task := range e.blocked {
+			state, err := e.dependencies.DependencyState(task)
+			switch {
+			case err != nil:
+				e.logger.Warn("task dependency check failed", "task_id", task.ID, "error", err)
+				waiting = append(waiting, task)
+			case state == DependenciesMet:
+				ready = append(ready, task)
+			case state == DependenciesFailed:
+				failed = append(failed, task)
+			default:
+				waiting = append(waiting, task)
+			}
+		}
+		e.blocked = waiting
+		e.blockedMu.Unlock()
+
+		for index, task := range ready {
+			if _, err := e.push(task); err != nil {
+				// The queue is full; keep the rest parked for the next release.
+				e.blockedMu.Lock()
+				e.blocked = append(append([]Task{}, ready[index:]...), e.blocked...)
+				e.blockedMu.Unlock()
+				break
+			}
+		}
+		if len(ready) > 0 {
+			e.updatePressure()
+		}
+		for _, task := range failed {
+			e.logger.Warn("task dependency failed", "task_id", task.ID, "depends_on", task.DependsOn)
+			if e.observer != nil {
+				e.observer.OnTaskFailed(task, 0, fmt.Errorf("%w: one of %v did not succeed", ErrDependencyFailed, task.DependsOn))
+			}
+		}
+		if len(failed) == 0 {
+			return
+		}
+	}
+}
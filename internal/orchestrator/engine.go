@@ -2,9 +2,11 @@ package orchestrator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +20,9 @@ const (
 	TaskKindGeneral   TaskKind = "general"
 	TaskKindReindex   TaskKind = "reindex_markdown"
 	TaskKindObjective TaskKind = "objective"
+	// TaskKindMemoryCompaction folds old chat log entries of a workspace
+	// into rolling summaries.
+	TaskKindMemoryCompaction TaskKind = "memory_compaction"
 )
 
 type Task struct {
@@ -27,18 +32,54 @@ type Task struct {
 	Kind        TaskKind
 	Title       string
 	Prompt      string
-	CreatedAt   time.Time
+	// DependsOn lists the IDs of tasks that must succeed before this one
+	// is queued for a worker.
+	DependsOn []string
+	// Attempts is the number of runs of the task that already failed.
+	Attempts int
+	// RunAt holds the task back until that time; zero runs it right away.
+	RunAt     time.Time
+	CreatedAt time.Time
 }
 
 type TaskResult struct {
 	Summary      string
 	ArtifactPath string
+	// Data is a JSON array of the structured tool results produced while
+	// running the task, each {"tool", "kind", "data"}; empty when none.
+	Data json.RawMessage
+	// Artifacts are the files the task produced, key ones first.
+	Artifacts []TaskArtifact
+	// Unchanged marks a run of a change-detecting objective that observed
+	// the same state as the run before; it sends no completion notice.
+	Unchanged bool
+}
+
+// TaskArtifact is a file a task produced under its workspace. Path is
+// relative to the workspace directory; URL is a download link, set by the
+// notifier when signed links are enabled.
+type TaskArtifact struct {
+	Path      string
+	Kind      string
+	Title     string
+	MediaType string
+	SizeBytes int64
+	URL       string
 }
 
 type TaskExecutor interface {
 	Execute(ctx context.Context, task Task) (TaskResult, error)
 }
 
+// Admission decides whether a task may be queued, e.g. to enforce workspace
+// quotas. A non-nil error rejects the task and is returned from Enqueue.
+type Admission interface {
+	Admit(task Task) error
+}
+
+// TaskObserver hears about task lifecycle changes. OnTaskFailed gets a zero
+// workerID for a task that failed before reaching a worker, such as one
+// whose dependency failed.
 type TaskObserver interface {
 	OnTaskQueued(task Task)
 	OnTaskStarted(task Task, workerID int)
@@ -53,6 +94,49 @@ type Engine struct {
 	startOnce      sync.Once
 	executor       TaskExecutor
 	observer       TaskObserver
+	admission      Admission
+
+	poolMu        sync.Mutex
+	runCtx        context.Context
+	workers       sync.WaitGroup
+	pool          []poolWorker
+	nextWorkerID  int
+	targetWorkers int
+	minWorkers    int
+	maxWorkers    int
+	busy          atomic.Int32
+	processed     int64
+	avgWait       time.Duration
+	avgRun        time.Duration
+
+	pressureMu        sync.Mutex
+	pressureThreshold int
+	pressureListener  BackpressureListener
+	pressured         bool
+	held              []Task
+
+	blockedMu    sync.Mutex
+	dependencies DependencyResolver
+	blocked      []Task
+
+	retryMu       sync.Mutex
+	retryFallback RetryPolicy
+	retryByKind   map[TaskKind]RetryPolicy
+
+	cancelMu  sync.Mutex
+	running   map[string]context.CancelCauseFunc
+	cancelled map[string]struct{}
+	retrying  map[string]struct{}
+
+	scheduleMu sync.Mutex
+	scheduled  map[string]*scheduledTask
+
+	leaseMu    sync.Mutex
+	leaser     TaskLeaser
+	leaseOwner string
+	leaseTTL   time.Duration
+	leases     map[string]*taskLease
+	queuedIDs  map[string]int
 }
 
 func New(maxConcurrency int, logger *slog.Logger) *Engine {
@@ -66,6 +150,9 @@ func New(maxConcurrency int, logger *slog.Logger) *Engine {
 		maxConcurrency: maxConcurrency,
 		tasks:          make(chan Task, maxConcurrency*50),
 		logger:         logger,
+		targetWorkers:  maxConcurrency,
+		minWorkers:     maxConcurrency,
+		maxWorkers:     maxConcurrency,
 	}
 }
 
@@ -77,34 +164,50 @@ func (e *Engine) SetObserver(observer TaskObserver) {
 	e.observer = observer
 }
 
+func (e *Engine) SetAdmission(admission Admission) {
+	e.admission = admission
+}
+
 func (e *Engine) Start(ctx context.Context) error {
-	var workers sync.WaitGroup
 	e.startOnce.Do(func() {
-		for index := 0; index < e.maxConcurrency; index++ {
-			workers.Add(1)
-			go func(workerID int) {
-				defer workers.Done()
-				e.worker(ctx, workerID)
-			}(index + 1)
-		}
+		e.poolMu.Lock()
+		e.runCtx = ctx
+		e.resizeLocked(e.targetWorkers)
+		e.poolMu.Unlock()
 	})
 
 	<-ctx.Done()
-	workers.Wait()
+	e.workers.Wait()
 	return nil
 }
 
 func (e *Engine) Enqueue(task Task) (Task, error) {
-	if task.ID == "" {
-		task.ID = uuid.NewString()
+	task = withTaskDefaults(task)
+	if e.admission != nil {
+		if err := e.admission.Admit(task); err != nil {
+			return Task{}, err
+		}
 	}
-	if task.Kind == "" {
-		task.Kind = TaskKindGeneral
+	if e.holdUntilDue(task) {
+		return task, nil
 	}
-	if task.CreatedAt.IsZero() {
-		task.CreatedAt = time.Now().UTC()
+	if waiting, err := e.waitForDependencies(task); waiting || err != nil {
+		if err != nil {
+			return Task{}, err
+		}
+		return task, nil
+	}
+	queued, err := e.push(task)
+	if err == nil {
+		e.updatePressure()
 	}
+	return queued, err
+}
 
+func (e *Engine) push(task Task) (Task, error) {
+	// Counted before the send so the worker that takes it never sees it
+	// uncounted.
+	e.markQueued(task.ID, 1)
 	select {
 	case e.tasks <- task:
 		e.logger.Info("task queued", "task_id", task.ID, "workspace_id", task.WorkspaceID, "context_id", task.ContextID, "kind", task.Kind)
@@ -113,19 +216,53 @@ func (e *Engine) Enqueue(task Task) (Task, error) {
 		}
 		return task, nil
 	default:
+		e.markQueued(task.ID, -1)
 		return Task{}, ErrQueueFull
 	}
 }
 
-func (e *Engine) worker(ctx context.Context, workerID int) {
+func withTaskDefaults(task Task) Task {
+	if task.ID == "" {
+		task.ID = uuid.NewString()
+	}
+	if task.Kind == "" {
+		task.Kind = TaskKindGeneral
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now().UTC()
+	}
+	return task
+}
+
+func (e *Engine) worker(ctx context.Context, workerID int, stop <-chan struct{}) {
 	e.logger.Info("worker started", "worker_id", workerID)
 	for {
 		select {
 		case <-ctx.Done():
 			e.logger.Info("worker stopped", "worker_id", workerID)
 			return
+		case <-stop:
+			e.logger.Info("worker stopped after scale down", "worker_id", workerID)
+			return
 		case task := <-e.tasks:
-			e.processTask(ctx, workerID, task)
+			taskCtx, ok := e.startTask(ctx, task.ID)
+			e.updatePressure()
+			if !ok {
+				e.logger.Info("skipping cancelled task", "worker_id", workerID, "task_id", task.ID)
+				continue
+			}
+			if !e.claimLease(workerID, task) {
+				e.finishTask(task.ID)
+				continue
+			}
+			e.busy.Add(1)
+			started := time.Now()
+			e.processTask(taskCtx, workerID, task)
+			e.endLease(task.ID)
+			e.finishTask(task.ID)
+			e.recordLatency(started.Sub(task.CreatedAt), time.Since(started))
+			e.busy.Add(-1)
+			e.releaseBlocked()
 		}
 	}
 }
@@ -140,17 +277,21 @@ func (e *Engine) processTask(ctx context.Context, workerID int, task Task) {
 		case <-ctx.Done():
 		case <-time.After(150 * time.Millisecond):
 		}
+		if e.lostLease(ctx, workerID, task) || e.wasCancelled(ctx, workerID, task) {
+			return
+		}
 		if e.observer != nil {
 			e.observer.OnTaskCompleted(task, workerID, TaskResult{Summary: "processed with default noop executor"})
 		}
 		return
 	}
-	result, err := e.executor.Execute(ctx, task)
+	result, err := e.executor.Execute(e.withProgress(ctx, workerID, task), task)
+	if e.lostLease(ctx, workerID, task) || e.wasCancelled(ctx, workerID, task) {
+		return
+	}
 	if err != nil {
 		e.logger.Error("task execution failed", "worker_id", workerID, "task_id", task.ID, "error", err)
-		if e.observer != nil {
-			e.observer.OnTaskFailed(task, workerID, err)
-		}
+		e.retryOrFail(ctx, workerID, task, err)
 		return
 	}
 	if e.observer != nil {
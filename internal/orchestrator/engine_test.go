@@ -2,6 +2,7 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"sync"
@@ -202,3 +203,214 @@ func TestEngineWorkerReportsFailure(t *testing.T) {
 		t.Fatalf("expected no completed callbacks, got %d", len(observer.completed))
 	}
 }
+
+type admissionFunc func(task Task) error
+
+func (f admissionFunc) Admit(task Task) error { return f(task) }
+
+func TestEnqueueRejectedByAdmission(t *testing.T) {
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	denied := errors.New("over quota")
+	engine.SetAdmission(admissionFunc(func(task Task) error {
+		if task.WorkspaceID == "ws_full" {
+			return denied
+		}
+		return nil
+	}))
+
+	if _, err := engine.Enqueue(Task{WorkspaceID: "ws_full", Title: "blocked"}); !errors.Is(err, denied) {
+		t.Fatalf("expected admission error, got %v", err)
+	}
+	if len(engine.tasks) != 0 {
+		t.Fatalf("expected rejected task not queued, got %d queued", len(engine.tasks))
+	}
+	if _, err := engine.Enqueue(Task{WorkspaceID: "ws_ok", Title: "allowed"}); err != nil {
+		t.Fatalf("expected task admitted, got %v", err)
+	}
+}
+
+type recordingBackpressure struct {
+	mu     sync.Mutex
+	states []BackpressureState
+}
+
+func (r *recordingBackpressure) OnBackpressure(state BackpressureState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states = append(r.states, state)
+}
+
+func TestBackpressureHoldsLowPriorityTasksUntilQueueDrains(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := New(1, logger)
+	listener := &recordingBackpressure{}
+	engine.SetBackpressure(4, listener)
+
+	held, err := engine.Hold(Task{WorkspaceID: "ws_1", Title: "calm"})
+	if err != nil || engine.QueueDepth() != 1 {
+		t.Fatalf("expected hold to queue directly while calm, got depth %d err %v", engine.QueueDepth(), err)
+	}
+	for index := 0; index < 3; index++ {
+		if _, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "busy"}); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	if !engine.UnderPressure() || len(listener.states) != 1 || !listener.states[0].Active || listener.states[0].Depth != 4 {
+		t.Fatalf("expected backpressure at depth 4, got %+v", listener.states)
+	}
+	held, err = engine.Hold(Task{WorkspaceID: "ws_1", Title: "later"})
+	if err != nil || held.ID == "" || engine.QueueDepth() != 4 {
+		t.Fatalf("expected p3 task held outside the queue, got %+v depth %d err %v", held, engine.QueueDepth(), err)
+	}
+
+	// Workers drain the queue; pressure ends at half the threshold and the
+	// held task is queued.
+	for index := 0; index < 2; index++ {
+		<-engine.tasks
+		engine.updatePressure()
+	}
+	if engine.UnderPressure() || len(listener.states) != 2 || listener.states[1].Active {
+		t.Fatalf("expected backpressure to end, got %+v", listener.states)
+	}
+	if engine.QueueDepth() != 3 {
+		t.Fatalf("expected the held task to be released, got depth %d", engine.QueueDepth())
+	}
+	var last Task
+	for engine.QueueDepth() > 0 {
+		last = <-engine.tasks
+	}
+	if last.ID != held.ID {
+		t.Fatalf("expected released task last in line, got %+v", last)
+	}
+}
+
+type blockingExecutor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingExecutor) Execute(ctx context.Context, task Task) (TaskResult, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return TaskResult{Summary: "done"}, nil
+}
+
+func TestAutoscaleGrowsWithQueueAndShrinksWhenIdle(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := New(1, logger)
+	engine.SetWorkerBounds(1, 4)
+	executor := &blockingExecutor{started: make(chan struct{}, 10), release: make(chan struct{})}
+	engine.SetExecutor(executor)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Start(ctx)
+
+	for index := 0; index < 3; index++ {
+		if _, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "Task"}); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	<-executor.started
+	stats := engine.Stats()
+	if stats.Workers != 1 || stats.BusyWorkers != 1 || stats.QueueDepth != 2 || stats.DesiredWorkers != 3 {
+		t.Fatalf("unexpected stats before scaling %+v", stats)
+	}
+	if workers := engine.autoscaleStep(); workers != 3 {
+		t.Fatalf("expected scale up to 3 workers, got %d", workers)
+	}
+	<-executor.started
+	<-executor.started
+	close(executor.release)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for engine.Stats().Processed < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("tasks did not finish: %+v", engine.Stats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if workers := engine.autoscaleStep(); workers != 2 {
+		t.Fatalf("expected scale down by one worker, got %d", workers)
+	}
+	if workers := engine.SetWorkers(10); workers != 4 {
+		t.Fatalf("expected the upper bound to apply, got %d", workers)
+	}
+	if stats := engine.Stats(); stats.AvgRun <= 0 || stats.Processed != 3 {
+		t.Fatalf("expected latency recorded, got %+v", stats)
+	}
+}
+
+type statusResolver struct {
+	mu       sync.Mutex
+	statuses map[string]string
+}
+
+func (r *statusResolver) set(taskID, status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses[taskID] = status
+}
+
+func (r *statusResolver) DependencyState(task Task) (DependencyState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := DependenciesMet
+	for _, id := range task.DependsOn {
+		switch r.statuses[id] {
+		case "succeeded":
+		case "failed":
+			return DependenciesFailed, nil
+		default:
+			state = DependenciesPending
+		}
+	}
+	return state, nil
+}
+
+func TestDependentTasksWaitForPrerequisites(t *testing.T) {
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	observer := newTestObserver()
+	engine.SetObserver(observer)
+	resolver := &statusResolver{statuses: map[string]string{"a": "queued", "x": "queued"}}
+	engine.SetDependencyResolver(resolver)
+
+	b, err := engine.Enqueue(Task{ID: "b", WorkspaceID: "ws_1", Title: "after a", DependsOn: []string{"a"}})
+	if err != nil || b.ID != "b" {
+		t.Fatalf("expected blocked task accepted, got %+v err %v", b, err)
+	}
+	if _, err := engine.Enqueue(Task{ID: "c", WorkspaceID: "ws_1", Title: "after b", DependsOn: []string{"b"}}); err != nil {
+		t.Fatalf("enqueue c: %v", err)
+	}
+	if _, err := engine.Hold(Task{ID: "y", WorkspaceID: "ws_1", Title: "after x", DependsOn: []string{"x"}}); err != nil {
+		t.Fatalf("hold y: %v", err)
+	}
+	if engine.QueueDepth() != 0 || engine.Blocked() != 3 {
+		t.Fatalf("expected three blocked tasks, got depth %d blocked %d", engine.QueueDepth(), engine.Blocked())
+	}
+
+	resolver.set("a", "succeeded")
+	engine.releaseBlocked()
+	if engine.QueueDepth() != 1 || engine.Blocked() != 2 {
+		t.Fatalf("expected b released, got depth %d blocked %d", engine.QueueDepth(), engine.Blocked())
+	}
+	if released := <-engine.tasks; released.ID != "b" {
+		t.Fatalf("expected b queued, got %+v", released)
+	}
+
+	// b fails, so c fails without running; y keeps waiting on x.
+	resolver.set("b", "failed")
+	engine.releaseBlocked()
+	observer.mu.Lock()
+	failed := append([]error{}, observer.failed...)
+	observer.mu.Unlock()
+	if len(failed) != 1 || !errors.Is(failed[0], ErrDependencyFailed) {
+		t.Fatalf("expected c failed by its dependency, got %v", failed)
+	}
+	if engine.QueueDepth() != 0 || engine.Blocked() != 1 {
+		t.Fatalf("expected only y blocked, got depth %d blocked %d", engine.QueueDepth(), engine.Blocked())
+	}
+
+	if _, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "after b", DependsOn: []string{"b"}}); !errors.Is(err, ErrDependencyFailed) {
+		t.Fatalf("expected enqueue after a failed prerequisite rejected, got %v", err)
+	}
+}
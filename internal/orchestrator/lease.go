@@ -0,0 +1,200 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrTaskLeaseLost is the cause of the context of a run whose lease was
+// taken over by another runtime instance or whose task finished elsewhere.
+var ErrTaskLeaseLost = errors.New("task lease lost")
+
+const defaultTaskLeaseTTL = time.Minute
+
+// TaskLeaser hands out leases on the tasks of a queue shared by several
+// runtime instances. A worker runs a task only while it holds the lease and
+// renews it while the task runs; a lease that is not renewed expires, so
+// another instance can take over the tasks of one that stopped.
+type TaskLeaser interface {
+	// ClaimTask takes the lease for owner unless another owner holds an
+	// unexpired one or the task is no longer queued or running.
+	ClaimTask(taskID, owner string, ttl time.Duration) (bool, error)
+	// RenewTask extends the lease of owner, returning ErrTaskLeaseLost once
+	// it no longer holds it.
+	RenewTask(taskID, owner string, ttl time.Duration) error
+	ReleaseTask(taskID, owner string) error
+}
+
+type taskLease struct {
+	stop chan struct{}
+	done chan struct{}
+	// keep is set for a task waiting for its retry; the lease is extended
+	// over the backoff instead of released.
+	keep time.Duration
+}
+
+// SetTaskLeaser makes workers lease each task from leaser under owner, the
+// name of this runtime instance, before running it. Without a leaser every
+// task that reaches a worker runs.
+func (e *Engine) SetTaskLeaser(leaser TaskLeaser, owner string, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = defaultTaskLeaseTTL
+	}
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+	e.leaser = leaser
+	e.leaseOwner = strings.TrimSpace(owner)
+	e.leaseTTL = ttl
+}
+
+// Tracks reports whether the task is queued, running or waiting in this
+// engine, so a poller of a shared queue does not add it twice.
+func (e *Engine) Tracks(taskID string) bool {
+	e.leaseMu.Lock()
+	queued := e.queuedIDs[taskID] > 0
+	e.leaseMu.Unlock()
+	if queued {
+		return true
+	}
+	e.cancelMu.Lock()
+	_, running := e.running[taskID]
+	e.cancelMu.Unlock()
+	if running {
+		return true
+	}
+	e.scheduleMu.Lock()
+	_, scheduled := e.scheduled[taskID]
+	e.scheduleMu.Unlock()
+	if scheduled {
+		return true
+	}
+	e.blockedMu.Lock()
+	blocked := containsTask(e.blocked, taskID)
+	e.blockedMu.Unlock()
+	if blocked {
+		return true
+	}
+	e.pressureMu.Lock()
+	defer e.pressureMu.Unlock()
+	return containsTask(e.held, taskID)
+}
+
+func containsTask(tasks []Task, taskID string) bool {
+	for _, task := range tasks {
+		if task.ID == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) markQueued(taskID string, delta int) {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+	if e.queuedIDs == nil {
+		e.queuedIDs = map[string]int{}
+	}
+	e.queuedIDs[taskID] += delta
+	if e.queuedIDs[taskID] <= 0 {
+		delete(e.queuedIDs, taskID)
+	}
+}
+
+// claimLease leases a task picked up by a worker and keeps renewing the
+// lease until endLease. It reports false when another instance holds it.
+func (e *Engine) claimLease(workerID int, task Task) bool {
+	e.leaseMu.Lock()
+	leaser, owner, ttl := e.leaser, e.leaseOwner, e.leaseTTL
+	e.leaseMu.Unlock()
+	if leaser == nil {
+		return true
+	}
+	claimed, err := leaser.ClaimTask(task.ID, owner, ttl)
+	if err != nil {
+		e.logger.Error("task lease claim failed", "worker_id", workerID, "task_id", task.ID, "error", err)
+		return false
+	}
+	if !claimed {
+		e.logger.Info("skipping task leased by another instance", "worker_id", workerID, "task_id", task.ID)
+		return false
+	}
+	lease := &taskLease{stop: make(chan struct{}), done: make(chan struct{})}
+	e.leaseMu.Lock()
+	if e.leases == nil {
+		e.leases = map[string]*taskLease{}
+	}
+	e.leases[task.ID] = lease
+	e.leaseMu.Unlock()
+	go e.renewLease(leaser, owner, ttl, task.ID, lease)
+	return true
+}
+
+func (e *Engine) renewLease(leaser TaskLeaser, owner string, ttl time.Duration, taskID string, lease *taskLease) {
+	defer close(lease.done)
+	ticker := time.NewTicker(max(ttl/3, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-lease.stop:
+			return
+		case <-ticker.C:
+			err := leaser.RenewTask(taskID, owner, ttl)
+			if errors.Is(err, ErrTaskLeaseLost) {
+				e.cancelMu.Lock()
+				if stop, ok := e.running[taskID]; ok {
+					stop(ErrTaskLeaseLost)
+				}
+				e.cancelMu.Unlock()
+				return
+			}
+			if err != nil {
+				e.logger.Warn("task lease renewal failed", "task_id", taskID, "error", err)
+			}
+		}
+	}
+}
+
+// keepLease holds on to the lease of a task waiting delay for its retry.
+func (e *Engine) keepLease(taskID string, delay time.Duration) {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+	if lease, ok := e.leases[taskID]; ok {
+		lease.keep = delay
+	}
+}
+
+// endLease stops renewing the lease of a finished run and releases it, or
+// extends it over the backoff of a retry.
+func (e *Engine) endLease(taskID string) {
+	e.leaseMu.Lock()
+	lease, ok := e.leases[taskID]
+	delete(e.leases, taskID)
+	leaser, owner, ttl := e.leaser, e.leaseOwner, e.leaseTTL
+	e.leaseMu.Unlock()
+	if !ok {
+		return
+	}
+	close(lease.stop)
+	<-lease.done
+	var err error
+	if lease.keep > 0 {
+		err = leaser.RenewTask(taskID, owner, lease.keep+ttl)
+	} else {
+		err = leaser.ReleaseTask(taskID, owner)
+	}
+	if err != nil && !errors.Is(err, ErrTaskLeaseLost) {
+		e.logger.Warn("task lease release failed", "task_id", taskID, "error", err)
+	}
+}
+
+// lostLease reports a run stopped because another instance took over its
+// task; that instance reports the outcome.
+func (e *Engine) lostLease(ctx context.Context, workerID int, task Task) bool {
+	if !errors.Is(context.Cause(ctx), ErrTaskLeaseLost) {
+		return false
+	}
+	e.logger.Warn("task stopped after losing its lease", "worker_id", workerID, "task_id", task.ID)
+	return true
+}
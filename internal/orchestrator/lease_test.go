@@ -0,0 +1,105 @@
+package orchestrator
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLeaser keeps leases in memory like a store shared by instances.
+type memoryLeaser struct {
+	mu       sync.Mutex
+	owners   map[string]string
+	released []string
+	lose     bool
+}
+
+func (l *memoryLeaser) ClaimTask(taskID, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if current, ok := l.owners[taskID]; ok && current != owner {
+		return false, nil
+	}
+	l.owners[taskID] = owner
+	return true, nil
+}
+
+func (l *memoryLeaser) RenewTask(taskID, owner string, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.lose || l.owners[taskID] != owner {
+		return ErrTaskLeaseLost
+	}
+	return nil
+}
+
+func (l *memoryLeaser) ReleaseTask(taskID, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.owners[taskID] == owner {
+		delete(l.owners, taskID)
+		l.released = append(l.released, taskID)
+	}
+	return nil
+}
+
+func TestLeasedTaskRunsOnceAndIsReleased(t *testing.T) {
+	leaser := &memoryLeaser{owners: map[string]string{"task_other": "replica-b"}}
+	engine, observer := startCancelEngine(t, nil)
+	engine.SetTaskLeaser(leaser, "replica-a", time.Minute)
+
+	if _, err := engine.Enqueue(Task{ID: "task_other", WorkspaceID: "ws_1", Title: "Leased elsewhere"}); err != nil {
+		t.Fatalf("enqueue leased task: %v", err)
+	}
+	if _, err := engine.Enqueue(Task{ID: "task_mine", WorkspaceID: "ws_1", Title: "Free"}); err != nil {
+		t.Fatalf("enqueue free task: %v", err)
+	}
+	select {
+	case <-observer.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("free task did not complete")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for engine.Tracks("task_mine") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	observer.mu.Lock()
+	started := append([]Task{}, observer.started...)
+	observer.mu.Unlock()
+	if len(started) != 1 || started[0].ID != "task_mine" {
+		t.Fatalf("expected only the free task to run, started %+v", started)
+	}
+	leaser.mu.Lock()
+	defer leaser.mu.Unlock()
+	if len(leaser.released) != 1 || leaser.released[0] != "task_mine" || leaser.owners["task_other"] != "replica-b" {
+		t.Fatalf("expected the finished task released and the other lease kept, got %v %v", leaser.released, leaser.owners)
+	}
+}
+
+func TestLostLeaseStopsRunWithoutReporting(t *testing.T) {
+	leaser := &memoryLeaser{owners: map[string]string{}, lose: true}
+	executor := &cancellableExecutor{started: make(chan string, 1)}
+	engine, observer := startCancelEngine(t, executor)
+	engine.SetTaskLeaser(leaser, "replica-a", 30*time.Millisecond)
+
+	if _, err := engine.Enqueue(Task{ID: "task_a", WorkspaceID: "ws_1", Title: "Long"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	select {
+	case <-executor.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("task did not start")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for engine.Tracks("task_a") {
+		if time.Now().After(deadline) {
+			t.Fatal("run was not stopped after losing its lease")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if len(observer.completed) != 0 || len(observer.failed) != 0 || len(observer.cancelled) != 0 {
+		t.Fatalf("a run that lost its lease must not be reported, got %d completed %v failed", len(observer.completed), observer.failed)
+	}
+}
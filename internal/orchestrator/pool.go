@@ -0,0 +1,180 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+)
+
+// latencySmoothing weighs each finished task in the moving latency averages.
+const latencySmoothing = 0.2
+
+type poolWorker struct {
+	id   int
+	stop chan struct{}
+}
+
+// PoolStats describes the worker pool and its queue for autoscalers.
+type PoolStats struct {
+	Workers     int
+	BusyWorkers int
+	MinWorkers  int
+	MaxWorkers  int
+	// DesiredWorkers is the count that would cover the running and waiting
+	// tasks, within the bounds.
+	DesiredWorkers int
+	QueueDepth     int
+	QueueCapacity  int
+	Held           int
+	// Scheduled is the number of tasks waiting for their RunAt time.
+	Scheduled    int
+	Backpressure bool
+	Processed    int64
+	// AvgWait is how long tasks waited between being queued and picked up,
+	// AvgRun how long they ran; both are moving averages.
+	AvgWait time.Duration
+	AvgRun  time.Duration
+}
+
+// SetWorkerBounds lets the worker count move between min and max, through
+// SetWorkers or Autoscale. Both default to the concurrency passed to New.
+func (e *Engine) SetWorkerBounds(min, max int) {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	e.poolMu.Lock()
+	e.minWorkers, e.maxWorkers = min, max
+	target := clampWorkers(e.targetWorkers, min, max)
+	e.poolMu.Unlock()
+	e.SetWorkers(target)
+}
+
+// SetWorkers resizes the pool to count workers, clamped to the bounds, and
+// returns the count it settled on. Before Start it sets how many workers
+// Start launches. Workers being removed finish their current task first.
+func (e *Engine) SetWorkers(count int) int {
+	e.poolMu.Lock()
+	defer e.poolMu.Unlock()
+	count = clampWorkers(count, e.minWorkers, e.maxWorkers)
+	if count != e.targetWorkers {
+		e.logger.Info("worker pool resized", "from", e.targetWorkers, "to", count)
+	}
+	e.targetWorkers = count
+	if e.runCtx != nil {
+		e.resizeLocked(count)
+	}
+	return count
+}
+
+// runContext is the context the engine was started with.
+func (e *Engine) runContext() context.Context {
+	e.poolMu.Lock()
+	defer e.poolMu.Unlock()
+	if e.runCtx == nil {
+		return context.Background()
+	}
+	return e.runCtx
+}
+
+func (e *Engine) resizeLocked(count int) {
+	for len(e.pool) < count {
+		e.nextWorkerID++
+		worker := poolWorker{id: e.nextWorkerID, stop: make(chan struct{})}
+		e.pool = append(e.pool, worker)
+		e.workers.Add(1)
+		go func(ctx context.Context) {
+			defer e.workers.Done()
+			e.worker(ctx, worker.id, worker.stop)
+		}(e.runCtx)
+	}
+	for len(e.pool) > count {
+		last := e.pool[len(e.pool)-1]
+		e.pool = e.pool[:len(e.pool)-1]
+		close(last.stop)
+	}
+}
+
+// Stats reports the pool size, queue depth and task latency.
+func (e *Engine) Stats() PoolStats {
+	e.pressureMu.Lock()
+	held, pressured := len(e.held), e.pressured
+	e.pressureMu.Unlock()
+	scheduled := e.Scheduled()
+
+	e.poolMu.Lock()
+	stats := PoolStats{
+		Workers:       e.targetWorkers,
+		BusyWorkers:   int(e.busy.Load()),
+		MinWorkers:    e.minWorkers,
+		MaxWorkers:    e.maxWorkers,
+		QueueDepth:    len(e.tasks),
+		QueueCapacity: cap(e.tasks),
+		Held:          held,
+		Scheduled:     scheduled,
+		Backpressure:  pressured,
+		Processed:     e.processed,
+		AvgWait:       e.avgWait,
+		AvgRun:        e.avgRun,
+	}
+	e.poolMu.Unlock()
+	stats.DesiredWorkers = clampWorkers(stats.BusyWorkers+stats.QueueDepth+stats.Held, stats.MinWorkers, stats.MaxWorkers)
+	return stats
+}
+
+// Autoscale resizes the pool every interval until ctx ends: up to
+// DesiredWorkers at once when tasks wait, down one worker per interval when
+// workers sit idle, so a short lull does not drain the pool.
+func (e *Engine) Autoscale(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			e.autoscaleStep()
+		}
+	}
+}
+
+func (e *Engine) autoscaleStep() int {
+	stats := e.Stats()
+	target := stats.DesiredWorkers
+	if target < stats.Workers {
+		target = stats.Workers - 1
+	}
+	if target == stats.Workers {
+		return target
+	}
+	return e.SetWorkers(target)
+}
+
+func (e *Engine) recordLatency(wait, run time.Duration) {
+	if wait < 0 {
+		wait = 0
+	}
+	e.poolMu.Lock()
+	defer e.poolMu.Unlock()
+	if e.processed == 0 {
+		e.avgWait, e.avgRun = wait, run
+	} else {
+		e.avgWait += time.Duration(latencySmoothing * float64(wait-e.avgWait))
+		e.avgRun += time.Duration(latencySmoothing * float64(run-e.avgRun))
+	}
+	e.processed++
+}
+
+func clampWorkers(count, min, max int) int {
+	if count < min {
+		return min
+	}
+	if count > max {
+		return max
+	}
+	return count
+}
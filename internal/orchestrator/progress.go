@@ -0,0 +1,54 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+)
+
+// TaskProgress is how far a running task has come.
+type TaskProgress struct {
+	// Percent is between 0 and 100, or negative when the executor cannot
+	// tell.
+	Percent int
+	// Step describes the current step, e.g. "step 3/5: fetch the data".
+	Step string
+}
+
+// ProgressObserver is implemented by task observers that want the progress
+// executors report while a task runs.
+type ProgressObserver interface {
+	OnTaskProgress(task Task, workerID int, progress TaskProgress)
+}
+
+type progressKey struct{}
+
+type progressReporter struct {
+	engine   *Engine
+	task     Task
+	workerID int
+}
+
+// ReportProgress tells the observer how far the task run by ctx has come.
+// Executors call it between steps; outside a task run it does nothing.
+func ReportProgress(ctx context.Context, progress TaskProgress) {
+	reporter, ok := ctx.Value(progressKey{}).(progressReporter)
+	if !ok || ctx.Err() != nil {
+		return
+	}
+	observer, ok := reporter.engine.observer.(ProgressObserver)
+	if !ok {
+		return
+	}
+	if progress.Percent > 100 {
+		progress.Percent = 100
+	}
+	if progress.Percent < 0 {
+		progress.Percent = -1
+	}
+	progress.Step = strings.TrimSpace(progress.Step)
+	observer.OnTaskProgress(reporter.task, reporter.workerID, progress)
+}
+
+func (e *Engine) withProgress(ctx context.Context, workerID int, task Task) context.Context {
+	return context.WithValue(ctx, progressKey{}, progressReporter{engine: e, task: task, workerID: workerID})
+}
@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type progressExecutor struct{}
+
+func (progressExecutor) Execute(ctx context.Context, task Task) (TaskResult, error) {
+	ReportProgress(ctx, TaskProgress{Percent: 40, Step: " step 2/5: fetch data "})
+	ReportProgress(ctx, TaskProgress{Percent: 140, Step: "step 5/5: write report"})
+	return TaskResult{Summary: "done"}, nil
+}
+
+type progressRecorder struct {
+	*testObserver
+	progress []TaskProgress
+}
+
+func (r *progressRecorder) OnTaskProgress(task Task, workerID int, progress TaskProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress = append(r.progress, progress)
+}
+
+func TestExecutorProgressReachesObserver(t *testing.T) {
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	observer := &progressRecorder{testObserver: newTestObserver()}
+	engine.SetExecutor(progressExecutor{})
+	engine.SetObserver(observer)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = engine.Start(ctx)
+	}()
+	if _, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "Report"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	select {
+	case <-observer.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("task did not complete")
+	}
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if len(observer.progress) != 2 || observer.progress[0].Step != "step 2/5: fetch data" || observer.progress[1].Percent != 100 {
+		t.Fatalf("unexpected progress %+v", observer.progress)
+	}
+
+	// Outside a task run reporting is a no-op.
+	ReportProgress(context.Background(), TaskProgress{Percent: 10})
+}
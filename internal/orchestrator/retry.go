@@ -0,0 +1,130 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrRetriesExhausted wraps the last error of a task that failed every
+// attempt its retry policy allowed; such a task is dead-lettered.
+var ErrRetriesExhausted = errors.New("task retries exhausted")
+
+// RetryPolicy says how often a failed task runs again and how long the
+// engine waits in between. MaxAttempts counts every run including the
+// first, so a value below two turns retries off.
+type RetryPolicy struct {
+	MaxAttempts int
+	// Backoff is the wait before the first retry; it doubles for each
+	// further retry up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Jitter is the share of each wait, between 0 and 1, taken off at
+	// random so failed tasks do not all come back at once.
+	Jitter float64
+}
+
+// RetryObserver is implemented by observers that want to hear about a
+// failed run that will be retried. Observers without it only see the
+// outcome of the last attempt.
+type RetryObserver interface {
+	OnTaskRetry(task Task, workerID int, err error, delay time.Duration)
+}
+
+// SetRetryPolicy retries failed tasks with fallback, or with the policy of
+// their kind when byKind has one.
+func (e *Engine) SetRetryPolicy(fallback RetryPolicy, byKind map[TaskKind]RetryPolicy) {
+	e.retryMu.Lock()
+	defer e.retryMu.Unlock()
+	e.retryFallback = fallback
+	e.retryByKind = make(map[TaskKind]RetryPolicy, len(byKind))
+	for kind, policy := range byKind {
+		e.retryByKind[kind] = policy
+	}
+}
+
+// RetryPolicyFor returns the policy that applies to tasks of kind.
+func (e *Engine) RetryPolicyFor(kind TaskKind) RetryPolicy {
+	e.retryMu.Lock()
+	defer e.retryMu.Unlock()
+	if policy, ok := e.retryByKind[kind]; ok {
+		return policy
+	}
+	return e.retryFallback
+}
+
+// Delay is the wait before the next run of a task that has failed attempts
+// times so far.
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	delay := p.Backoff
+	for index := 1; index < attempts && index < 32 && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); index++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 && delay > 0 {
+		delay -= time.Duration(float64(delay) * jitter * rand.Float64())
+	}
+	return delay
+}
+
+// retryOrFail handles a failed run: while the task's policy allows another
+// attempt it is requeued after the backoff, otherwise the failure is
+// reported, marked as dead-lettered when retries were allowed.
+func (e *Engine) retryOrFail(ctx context.Context, workerID int, task Task, err error) {
+	task.Attempts++
+	policy := e.RetryPolicyFor(task.Kind)
+	if policy.MaxAttempts > 1 && ctx.Err() == nil {
+		if task.Attempts < policy.MaxAttempts {
+			delay := policy.Delay(task.Attempts)
+			e.logger.Warn("task failed, retrying", "worker_id", workerID, "task_id", task.ID, "attempt", task.Attempts, "max_attempts", policy.MaxAttempts, "delay", delay, "error", err)
+			if observer, ok := e.observer.(RetryObserver); ok {
+				observer.OnTaskRetry(task, workerID, err, delay)
+			}
+			e.keepLease(task.ID, delay)
+			// The run context ends with the task; the retry waits on the
+			// engine's instead.
+			e.scheduleRetry(e.runContext(), task, delay)
+			return
+		}
+		err = fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, task.Attempts, err)
+	}
+	if e.observer != nil {
+		e.observer.OnTaskFailed(task, workerID, err)
+	}
+}
+
+func (e *Engine) scheduleRetry(ctx context.Context, task Task, delay time.Duration) {
+	e.cancelMu.Lock()
+	if e.retrying == nil {
+		e.retrying = map[string]struct{}{}
+	}
+	e.retrying[task.ID] = struct{}{}
+	e.cancelMu.Unlock()
+	time.AfterFunc(delay, func() {
+		e.cancelMu.Lock()
+		delete(e.retrying, task.ID)
+		_, cancelled := e.cancelled[task.ID]
+		delete(e.cancelled, task.ID)
+		e.cancelMu.Unlock()
+		if cancelled {
+			e.logger.Info("skipping retry of cancelled task", "task_id", task.ID)
+			return
+		}
+		if ctx.Err() != nil {
+			// Startup recovery requeues the task, which is stored as queued.
+			return
+		}
+		if _, err := e.push(task); err != nil {
+			e.logger.Error("task retry could not be queued", "task_id", task.ID, "error", err)
+			if e.observer != nil {
+				e.observer.OnTaskFailed(task, 0, fmt.Errorf("queue retry: %w", err))
+			}
+			return
+		}
+		e.updatePressure()
+	})
+}
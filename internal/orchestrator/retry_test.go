@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyExecutor fails the first failures runs and succeeds afterwards.
+type flakyExecutor struct {
+	mu       sync.Mutex
+	failures int
+	runs     int
+}
+
+func (e *flakyExecutor) Execute(ctx context.Context, task Task) (TaskResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.runs++
+	if e.runs <= e.failures {
+		return TaskResult{}, errors.New("upstream unavailable")
+	}
+	return TaskResult{Summary: "ok"}, nil
+}
+
+type retryRecorder struct {
+	*testObserver
+	retries []int
+}
+
+func (r *retryRecorder) OnTaskRetry(task Task, workerID int, err error, delay time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retries = append(r.retries, task.Attempts)
+}
+
+func runRetryEngine(t *testing.T, failures int, policy RetryPolicy) (*retryRecorder, *flakyExecutor) {
+	t.Helper()
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	executor := &flakyExecutor{failures: failures}
+	observer := &retryRecorder{testObserver: newTestObserver()}
+	engine.SetExecutor(executor)
+	engine.SetObserver(observer)
+	engine.SetRetryPolicy(RetryPolicy{}, map[TaskKind]RetryPolicy{TaskKindObjective: policy})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = engine.Start(ctx)
+	}()
+	if _, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Kind: TaskKindObjective, Title: "Digest"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	select {
+	case <-observer.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the final outcome")
+	}
+	return observer, executor
+}
+
+func TestFailedTaskIsRetriedWithBackoff(t *testing.T) {
+	observer, executor := runRetryEngine(t, 2, RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if executor.runs != 3 || len(observer.completed) != 1 || len(observer.failed) != 0 {
+		t.Fatalf("expected success on the third run, got runs=%d completed=%d failed=%v", executor.runs, len(observer.completed), observer.failed)
+	}
+	if len(observer.retries) != 2 || observer.retries[0] != 1 || observer.retries[1] != 2 {
+		t.Fatalf("expected retries after attempts 1 and 2, got %v", observer.retries)
+	}
+}
+
+func TestTaskIsDeadLetteredWhenRetriesRunOut(t *testing.T) {
+	observer, executor := runRetryEngine(t, 10, RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if executor.runs != 2 || len(observer.failed) != 1 || !errors.Is(observer.failed[0], ErrRetriesExhausted) {
+		t.Fatalf("expected one exhausted failure after two runs, got runs=%d failed=%v", executor.runs, observer.failed)
+	}
+}
+
+func TestRetryPolicyDelayDoublesUpToMax(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 40: 5 * time.Second} {
+		if got := policy.Delay(attempts); got != want {
+			t.Fatalf("attempt %d: expected %s, got %s", attempts, want, got)
+		}
+	}
+	policy.Jitter = 0.5
+	for range 20 {
+		if got := policy.Delay(3); got > 4*time.Second || got < 2*time.Second {
+			t.Fatalf("expected jittered delay within half of 4s, got %s", got)
+		}
+	}
+}
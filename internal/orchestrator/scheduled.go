@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+)
+
+type scheduledTask struct {
+	timer *time.Timer
+}
+
+// Scheduled is the number of tasks waiting for their RunAt time.
+func (e *Engine) Scheduled() int {
+	e.scheduleMu.Lock()
+	defer e.scheduleMu.Unlock()
+	return len(e.scheduled)
+}
+
+// holdUntilDue parks a task whose RunAt lies in the future and reports
+// whether it did. The task is queued when it falls due; its prerequisites
+// are checked only then.
+func (e *Engine) holdUntilDue(task Task) bool {
+	delay := time.Until(task.RunAt)
+	if task.RunAt.IsZero() || delay <= 0 {
+		return false
+	}
+	e.scheduleMu.Lock()
+	defer e.scheduleMu.Unlock()
+	if e.scheduled == nil {
+		e.scheduled = map[string]*scheduledTask{}
+	}
+	if previous, ok := e.scheduled[task.ID]; ok {
+		previous.timer.Stop()
+	}
+	entry := &scheduledTask{}
+	entry.timer = time.AfterFunc(delay, func() {
+		e.releaseDue(task, entry)
+	})
+	e.scheduled[task.ID] = entry
+	e.logger.Info("task scheduled", "task_id", task.ID, "workspace_id", task.WorkspaceID, "run_at", task.RunAt.UTC())
+	return true
+}
+
+// releaseDue queues a scheduled task once its time came, unless it was
+// cancelled or rescheduled in the meantime.
+func (e *Engine) releaseDue(task Task, entry *scheduledTask) {
+	e.scheduleMu.Lock()
+	current, ok := e.scheduled[task.ID]
+	if !ok || current != entry {
+		e.scheduleMu.Unlock()
+		return
+	}
+	delete(e.scheduled, task.ID)
+	e.scheduleMu.Unlock()
+
+	if waiting, err := e.waitForDependencies(task); waiting || err != nil {
+		if err != nil && e.observer != nil {
+			e.observer.OnTaskFailed(task, 0, err)
+		}
+		return
+	}
+	if _, err := e.push(task); err != nil {
+		e.logger.Error("scheduled task could not be queued", "task_id", task.ID, "error", err)
+		if e.observer != nil {
+			e.observer.OnTaskFailed(task, 0, fmt.Errorf("queue scheduled task: %w", err))
+		}
+		return
+	}
+	e.updatePressure()
+}
+
+// dropScheduled stops the timer of a scheduled task, reporting whether
+// there was one.
+func (e *Engine) dropScheduled(taskID string) bool {
+	e.scheduleMu.Lock()
+	defer e.scheduleMu.Unlock()
+	entry, ok := e.scheduled[taskID]
+	if ok {
+		entry.timer.Stop()
+		delete(e.scheduled, taskID)
+	}
+	return ok
+}
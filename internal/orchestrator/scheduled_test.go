@@ -0,0 +1,58 @@
+package orchestrator
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestScheduledTaskWaitsUntilDue(t *testing.T) {
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	observer := newTestObserver()
+	engine.SetExecutor(&testExecutor{result: TaskResult{Summary: "ok"}})
+	engine.SetObserver(observer)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = engine.Start(ctx)
+	}()
+
+	runAt := time.Now().Add(150 * time.Millisecond)
+	if _, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "Morning digest", RunAt: runAt}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if engine.Scheduled() != 1 || engine.QueueDepth() != 0 {
+		t.Fatalf("expected the task held back, scheduled=%d depth=%d", engine.Scheduled(), engine.QueueDepth())
+	}
+	select {
+	case <-observer.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("scheduled task did not run")
+	}
+	if time.Now().Before(runAt) {
+		t.Fatal("scheduled task ran before it was due")
+	}
+	if engine.Scheduled() != 0 {
+		t.Fatalf("expected no scheduled tasks left, got %d", engine.Scheduled())
+	}
+}
+
+func TestCancelDropsScheduledTask(t *testing.T) {
+	engine := New(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	observer := newTestObserver()
+	engine.SetObserver(observer)
+	task, err := engine.Enqueue(Task{WorkspaceID: "ws_1", Title: "Later", RunAt: time.Now().Add(50 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	engine.Cancel(task.ID)
+	if engine.Scheduled() != 0 || len(engine.cancelled) != 0 {
+		t.Fatalf("expected the scheduled task dropped without a tombstone, scheduled=%d cancelled=%v", engine.Scheduled(), engine.cancelled)
+	}
+	time.Sleep(120 * time.Millisecond)
+	if engine.QueueDepth() != 0 {
+		t.Fatalf("expected the cancelled task never queued, depth=%d", engine.QueueDepth())
+	}
+}
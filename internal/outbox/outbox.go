@@ -0,0 +1,317 @@
+// Package outbox queues outbound connector messages durably while a
+// connector is unreachable and forwards them once it accepts messages again.
+// Notifications and reports published through a wrapped publisher never get
+// lost to a connector outage, and a series of updates about the same thing
+// collapses to the latest one while it waits.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/reply"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const flushBatchSize = 100
+
+type Store interface {
+	QueueOutboundMessage(ctx context.Context, input store.QueueOutboundMessageInput) (store.OutboundMessage, bool, error)
+	ListOutboundMessages(ctx context.Context, connector string, limit int) ([]store.OutboundMessage, error)
+	CountOutboundMessages(ctx context.Context) (map[string]int, error)
+	MarkOutboundMessageFailed(ctx context.Context, id, lastError string) error
+	DeleteOutboundMessage(ctx context.Context, id string) error
+}
+
+type Config struct {
+	// RetryInterval is how often delivery of queued messages is retried.
+	RetryInterval time.Duration
+	// MaxAge drops queued messages that could not be delivered in time.
+	MaxAge time.Duration
+}
+
+type collapseKeyContextKey struct{}
+
+// WithCollapseKey marks messages published with ctx as updates of key: a
+// queued message with the same key for the same target is replaced instead
+// of sent as well.
+func WithCollapseKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, collapseKeyContextKey{}, strings.TrimSpace(key))
+}
+
+// CollapseKeyFrom returns the collapse key set with WithCollapseKey.
+func CollapseKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(collapseKeyContextKey{}).(string)
+	return key
+}
+
+type Queue struct {
+	cfg    Config
+	store  Store
+	logger *slog.Logger
+
+	mu         sync.Mutex
+	publishers map[string]connectors.Publisher
+	pending    map[string]bool
+	flushMu    sync.Mutex
+}
+
+func New(cfg Config, store Store, logger *slog.Logger) *Queue {
+	if cfg.RetryInterval < time.Second {
+		cfg.RetryInterval = 30 * time.Second
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = 24 * time.Hour
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Queue{
+		cfg:        cfg,
+		store:      store,
+		logger:     logger,
+		publishers: map[string]connectors.Publisher{},
+		pending:    map[string]bool{},
+	}
+}
+
+// WrapAll wraps every publisher of a connector-name keyed map.
+func (q *Queue) WrapAll(publishers map[string]connectors.Publisher) map[string]connectors.Publisher {
+	wrapped := make(map[string]connectors.Publisher, len(publishers))
+	for name, publisher := range publishers {
+		wrapped[name] = q.Wrap(name, publisher)
+	}
+	return wrapped
+}
+
+// Wrap returns a publisher that queues a message instead of failing when the
+// connector cannot deliver it. While a connector has queued messages new
+// ones are queued behind them, so targets receive messages in order.
+func (q *Queue) Wrap(connector string, publisher connectors.Publisher) connectors.Publisher {
+	connector = strings.ToLower(strings.TrimSpace(connector))
+	q.mu.Lock()
+	q.publishers[connector] = publisher
+	q.mu.Unlock()
+	return &queuedPublisher{queue: q, connector: connector, next: publisher}
+}
+
+type queuedPublisher struct {
+	queue     *Queue
+	connector string
+	next      connectors.Publisher
+}
+
+func (p *queuedPublisher) Publish(ctx context.Context, externalID, text string) error {
+	return p.deliver(ctx, externalID, text, func() error {
+		return p.next.Publish(ctx, externalID, text)
+	})
+}
+
+// PublishRich sends a rich reply natively when possible. A rich reply that
+// has to wait is queued as its plain text, which spells out the commands
+// behind its buttons.
+func (p *queuedPublisher) PublishRich(ctx context.Context, externalID string, message reply.Message) error {
+	return p.deliver(ctx, externalID, message.PlainText(), func() error {
+		return connectors.PublishRich(ctx, p.next, externalID, message)
+	})
+}
+
+// PublishRichTracked is PublishRich returning the id of the sent message. A
+// queued message has no id yet, so it comes back empty.
+func (p *queuedPublisher) PublishRichTracked(ctx context.Context, externalID string, message reply.Message) (string, error) {
+	messageID := ""
+	err := p.deliver(ctx, externalID, message.PlainText(), func() error {
+		id, err := connectors.PublishTracked(ctx, p.next, externalID, message)
+		messageID = id
+		return err
+	})
+	return messageID, err
+}
+
+// EditRich edits a sent message directly. Edits are not queued: an edit that
+// fails while the connector is down only leaves a stale message behind.
+func (p *queuedPublisher) EditRich(ctx context.Context, externalID, messageID string, message reply.Message) error {
+	return connectors.EditRich(ctx, p.next, externalID, messageID, message)
+}
+
+func (p *queuedPublisher) deliver(ctx context.Context, externalID, text string, send func() error) error {
+	if queued, err := p.queue.queueIfPending(ctx, p.connector, externalID, text); queued || err != nil {
+		return err
+	}
+	publishErr := send()
+	if publishErr == nil || errors.Is(publishErr, context.Canceled) {
+		return publishErr
+	}
+	if err := p.queue.enqueue(ctx, p.connector, externalID, text, publishErr); err != nil {
+		return errors.Join(publishErr, err)
+	}
+	return nil
+}
+
+func (q *Queue) queueIfPending(ctx context.Context, connector, externalID, text string) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.pending[connector] {
+		return false, nil
+	}
+	return true, q.queueLocked(ctx, connector, externalID, text, nil)
+}
+
+func (q *Queue) enqueue(ctx context.Context, connector, externalID, text string, cause error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queueLocked(ctx, connector, externalID, text, cause)
+}
+
+func (q *Queue) queueLocked(ctx context.Context, connector, externalID, text string, cause error) error {
+	lastError := ""
+	if cause != nil {
+		lastError = cause.Error()
+	}
+	// The caller's deadline may be what failed the delivery; queueing must
+	// not fail with it.
+	message, collapsed, err := q.store.QueueOutboundMessage(context.WithoutCancel(ctx), store.QueueOutboundMessageInput{
+		Connector:   connector,
+		ExternalID:  externalID,
+		CollapseKey: CollapseKeyFrom(ctx),
+		Text:        text,
+		LastError:   lastError,
+	})
+	if err != nil {
+		return err
+	}
+	q.pending[connector] = true
+	q.logger.Warn("outbound message queued",
+		"connector", connector,
+		"external_id", externalID,
+		"message_id", message.ID,
+		"collapsed", collapsed,
+		"error", lastError,
+	)
+	return nil
+}
+
+// Pending reports the number of queued messages per connector.
+func (q *Queue) Pending(ctx context.Context) (map[string]int, error) {
+	return q.store.CountOutboundMessages(ctx)
+}
+
+// Start forwards messages queued before a restart, then retries delivery
+// every RetryInterval for connectors with queued messages.
+func (q *Queue) Start(ctx context.Context) error {
+	counts, err := q.store.CountOutboundMessages(ctx)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	for connector, count := range counts {
+		if count > 0 {
+			q.pending[connector] = true
+		}
+	}
+	q.mu.Unlock()
+
+	ticker := time.NewTicker(q.cfg.RetryInterval)
+	defer ticker.Stop()
+	for {
+		q.flushPending(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Queue) flushPending(ctx context.Context) {
+	q.mu.Lock()
+	connectorNames := []string{}
+	for connector, pending := range q.pending {
+		if pending {
+			connectorNames = append(connectorNames, connector)
+		}
+	}
+	q.mu.Unlock()
+	for _, connector := range connectorNames {
+		if ctx.Err() != nil {
+			return
+		}
+		delivered, err := q.Flush(ctx, connector)
+		if err != nil {
+			q.logger.Info("outbound queue still waiting for connector", "connector", connector, "delivered", delivered, "error", err)
+			continue
+		}
+		if delivered > 0 {
+			q.logger.Info("outbound queue forwarded messages", "connector", connector, "delivered", delivered)
+		}
+	}
+}
+
+// Flush delivers the queued messages of a connector in order. It stops at
+// the first failed delivery, which means the connector is still down, and
+// returns the number of messages delivered before it.
+func (q *Queue) Flush(ctx context.Context, connector string) (int, error) {
+	connector = strings.ToLower(strings.TrimSpace(connector))
+	q.mu.Lock()
+	publisher := q.publishers[connector]
+	q.mu.Unlock()
+
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+	delivered := 0
+	for {
+		messages, err := q.store.ListOutboundMessages(ctx, connector, flushBatchSize)
+		if err != nil {
+			return delivered, err
+		}
+		if len(messages) == 0 {
+			break
+		}
+		if publisher == nil {
+			q.logger.Warn("outbound messages queued for unknown connector", "connector", connector, "count", len(messages))
+			return delivered, nil
+		}
+		for _, message := range messages {
+			if time.Since(message.CreatedAt) > q.cfg.MaxAge {
+				q.logger.Warn("dropping expired outbound message",
+					"connector", connector,
+					"external_id", message.ExternalID,
+					"message_id", message.ID,
+					"attempts", message.Attempts,
+				)
+				if err := q.store.DeleteOutboundMessage(ctx, message.ID); err != nil {
+					return delivered, err
+				}
+				continue
+			}
+			if err := publisher.Publish(ctx, message.ExternalID, message.Text); err != nil {
+				if markErr := q.store.MarkOutboundMessageFailed(ctx, message.ID, err.Error()); markErr != nil {
+					q.logger.Error("record outbound delivery attempt failed", "message_id", message.ID, "error", markErr)
+				}
+				return delivered, err
+			}
+			if err := q.store.DeleteOutboundMessage(ctx, message.ID); err != nil {
+				return delivered, err
+			}
+			delivered++
+		}
+	}
+
+	// A message queued after the last batch keeps the connector pending until
+	// the next retry, so the flag is only cleared under the queueing lock.
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	remaining, err := q.store.ListOutboundMessages(ctx, connector, 1)
+	if err != nil {
+		return delivered, err
+	}
+	if len(remaining) == 0 {
+		delete(q.pending, connector)
+	}
+	return delivered, nil
+}
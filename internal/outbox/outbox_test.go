@@ -0,0 +1,155 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/reply"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakePublisher struct {
+	down bool
+	sent []string
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, externalID, text string) error {
+	if f.down {
+		return errors.New("connector unreachable")
+	}
+	f.sent = append(f.sent, externalID+": "+text)
+	return nil
+}
+
+func newTestQueue(t *testing.T) (*Queue, *store.Store) {
+	t.Helper()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "outbox.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(context.Background()); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return New(Config{}, sqlStore, logger), sqlStore
+}
+
+func TestQueueForwardsMessagesInOrderAfterOutage(t *testing.T) {
+	queue, sqlStore := newTestQueue(t)
+	ctx := context.Background()
+	discord := &fakePublisher{}
+	publisher := queue.Wrap("Discord", discord)
+
+	if err := publisher.Publish(ctx, "chan-1", "first"); err != nil {
+		t.Fatalf("publish while up: %v", err)
+	}
+	discord.down = true
+	if err := publisher.Publish(ctx, "chan-1", "second"); err != nil {
+		t.Fatalf("expected failed delivery to be queued, got %v", err)
+	}
+	discord.down = false
+	if err := publisher.Publish(ctx, "chan-1", "third"); err != nil {
+		t.Fatalf("publish behind queued message: %v", err)
+	}
+	if len(discord.sent) != 1 {
+		t.Fatalf("expected later messages to wait behind the queued one, sent %v", discord.sent)
+	}
+	if counts, _ := queue.Pending(ctx); counts["discord"] != 2 {
+		t.Fatalf("expected two queued messages, got %v", counts)
+	}
+
+	delivered, err := queue.Flush(ctx, "discord")
+	if err != nil || delivered != 2 {
+		t.Fatalf("expected two forwarded messages, got %d (%v)", delivered, err)
+	}
+	if len(discord.sent) != 3 || discord.sent[1] != "chan-1: second" || discord.sent[2] != "chan-1: third" {
+		t.Fatalf("expected messages in order, got %v", discord.sent)
+	}
+	if remaining, _ := sqlStore.ListOutboundMessages(ctx, "discord", 10); len(remaining) != 0 {
+		t.Fatalf("expected empty queue, got %+v", remaining)
+	}
+	if err := publisher.Publish(ctx, "chan-1", "fourth"); err != nil || len(discord.sent) != 4 {
+		t.Fatalf("expected direct delivery once the queue drained, got %v (%v)", discord.sent, err)
+	}
+}
+
+func TestQueueCollapsesSupersededUpdates(t *testing.T) {
+	queue, _ := newTestQueue(t)
+	ctx := context.Background()
+	telegram := &fakePublisher{down: true}
+	publisher := queue.Wrap("telegram", telegram)
+
+	taskCtx := WithCollapseKey(ctx, "task:1")
+	_ = publisher.Publish(taskCtx, "42", "Task 1 started")
+	_ = publisher.Publish(ctx, "42", "Unrelated notice")
+	_ = publisher.Publish(taskCtx, "42", "Task 1 completed")
+	_ = publisher.Publish(taskCtx, "43", "Task 1 completed")
+
+	if delivered, err := queue.Flush(ctx, "telegram"); err == nil || delivered != 0 {
+		t.Fatalf("expected flush to stop while the connector is down, got %d (%v)", delivered, err)
+	}
+	telegram.down = false
+	if _, err := queue.Flush(ctx, "telegram"); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	want := []string{"42: Task 1 completed", "42: Unrelated notice", "43: Task 1 completed"}
+	if len(telegram.sent) != len(want) {
+		t.Fatalf("expected %v, got %v", want, telegram.sent)
+	}
+	for i := range want {
+		if telegram.sent[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, telegram.sent)
+		}
+	}
+}
+
+type fakeTrackedPublisher struct {
+	fakePublisher
+	edited []string
+}
+
+func (f *fakeTrackedPublisher) PublishRichTracked(ctx context.Context, externalID string, message reply.Message) (string, error) {
+	if err := f.Publish(ctx, externalID, message.PlainText()); err != nil {
+		return "", err
+	}
+	return "msg-1", nil
+}
+
+func (f *fakeTrackedPublisher) EditRich(ctx context.Context, externalID, messageID string, message reply.Message) error {
+	f.edited = append(f.edited, messageID+": "+message.PlainText())
+	return nil
+}
+
+func TestWrappedPublisherPassesMessageIDsAndEditsThrough(t *testing.T) {
+	queue, _ := newTestQueue(t)
+	ctx := context.Background()
+	telegram := &fakeTrackedPublisher{}
+	publisher := queue.Wrap("telegram", telegram)
+
+	messageID, err := connectors.PublishTracked(ctx, publisher, "42", reply.Message{Text: "Approval needed"})
+	if err != nil || messageID != "msg-1" {
+		t.Fatalf("expected message id through the queue, got %q (%v)", messageID, err)
+	}
+	if err := connectors.EditRich(ctx, publisher, "42", messageID, reply.Message{Text: "Approved"}); err != nil {
+		t.Fatalf("edit through the queue: %v", err)
+	}
+	if len(telegram.edited) != 1 || telegram.edited[0] != "msg-1: Approved" {
+		t.Fatalf("unexpected edits %v", telegram.edited)
+	}
+
+	telegram.down = true
+	if messageID, err := connectors.PublishTracked(ctx, publisher, "42", reply.Message{Text: "Second"}); err != nil || messageID != "" {
+		t.Fatalf("expected a queued message without id, got %q (%v)", messageID, err)
+	}
+
+	plain := queue.Wrap("discord", &fakePublisher{})
+	if err := connectors.EditRich(ctx, plain, "chan-1", "msg-1", reply.Message{Text: "Approved"}); !errors.Is(err, connectors.ErrEditUnsupported) {
+		t.Fatalf("expected edits to be unsupported for plain publishers, got %v", err)
+	}
+}
@@ -163,6 +163,17 @@ func (s *Service) Status(ctx context.Context, workspaceID string) (Status, error
 }
 
 func (s *Service) Search(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error) {
+	return s.search(ctx, workspaceID, "search", query, limit)
+}
+
+// VectorSearch ranks documents by embedding similarity using qmd's vsearch.
+// It only finds anything once the workspace has been embedded (see
+// AutoEmbed); until then it returns no results.
+func (s *Service) VectorSearch(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error) {
+	return s.search(ctx, workspaceID, "vsearch", query, limit)
+}
+
+func (s *Service) search(ctx context.Context, workspaceID, command, query string, limit int) ([]SearchResult, error) {
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return nil, nil
@@ -184,10 +195,10 @@ func (s *Service) Search(ctx context.Context, workspaceID, query string, limit i
 	searchCtx, cancel := context.WithTimeout(ctx, s.cfg.QueryTimeout)
 	defer cancel()
 
-	output, err := s.runQMD(searchCtx, workspaceDir, "search", query, "--json", "-n", strconv.Itoa(limit))
+	output, err := s.runQMD(searchCtx, workspaceDir, command, query, "--json", "-n", strconv.Itoa(limit))
 	if err != nil {
 		if err != nil && looksLikeIndexNotReady(err) {
-			s.logger.Debug("qmd index not ready during search; queueing async index", "workspace_id", workspaceID, "error", err)
+			s.logger.Debug("qmd index not ready during search; queueing async index", "workspace_id", workspaceID, "command", command, "error", err)
 			s.QueueWorkspaceIndex(workspaceID)
 			return nil, nil
 		}
@@ -237,7 +248,12 @@ func (s *Service) OpenMarkdown(ctx context.Context, workspaceID, target string)
 		return OpenResult{}, err
 	}
 	if strings.ToLower(filepath.Ext(relativePath)) != ".md" {
-		return OpenResult{}, ErrInvalidTarget
+		// Ingested attachments keep their extracted text in a markdown
+		// sidecar next to the original file.
+		if _, err := os.Stat(fullPath + ".md"); err != nil {
+			return OpenResult{}, ErrInvalidTarget
+		}
+		fullPath += ".md"
 	}
 
 	content, err := os.ReadFile(fullPath)
@@ -81,6 +81,36 @@ func TestSearchReturnsParsedResults(t *testing.T) {
 	}
 }
 
+func TestVectorSearchUsesVSearch(t *testing.T) {
+	root := t.TempDir()
+	workspaceID := "ws-vector"
+	if err := os.MkdirAll(filepath.Join(root, workspaceID), 0o755); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	runner := &fakeRunner{
+		resolver: func(cmd *exec.Cmd) ([]byte, error) {
+			if strings.Contains(strings.Join(cmd.Args, " "), " vsearch ") {
+				return []byte(`[{"path":"roadmap.md","docid":"#r1","score":0.72,"snippet":"quarterly goals"}]`), nil
+			}
+			return []byte("ok"), nil
+		},
+	}
+	service := newService(Config{WorkspaceRoot: root, AutoEmbed: false}, slog.Default(), runner)
+
+	results, err := service.VectorSearch(context.Background(), workspaceID, "what are we aiming for", 4)
+	if err != nil {
+		t.Fatalf("vector search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "roadmap.md" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	for _, call := range runner.callsSnapshot() {
+		if strings.Contains(call, " vsearch ") && !strings.Contains(call, "-n 4") {
+			t.Fatalf("expected limit to be passed, got %s", call)
+		}
+	}
+}
+
 func TestSearchUsesBM25AndSkipsQueryExpansion(t *testing.T) {
 	root := t.TempDir()
 	workspaceID := "ws-bm25-only"
@@ -284,6 +314,44 @@ func TestOpenMarkdownRejectsTraversal(t *testing.T) {
 	}
 }
 
+func TestOpenMarkdownFallsBackToAttachmentSidecar(t *testing.T) {
+	root := t.TempDir()
+	workspaceID := "ws-attachment"
+	attachmentDir := filepath.Join(root, workspaceID, "inbox", "telegram", "42")
+	if err := os.MkdirAll(attachmentDir, 0o755); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(attachmentDir, "7-plan.pdf"), []byte("%PDF"), 0o644); err != nil {
+		t.Fatalf("write pdf: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(attachmentDir, "7-plan.pdf.md"), []byte("# Attachment: plan.pdf\n\nFreeze on Friday"), 0o644); err != nil {
+		t.Fatalf("write sidecar: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(attachmentDir, "8-raw.bin"), []byte("raw"), 0o644); err != nil {
+		t.Fatalf("write binary: %v", err)
+	}
+
+	service := newService(
+		Config{
+			WorkspaceRoot: root,
+			OpenMaxBytes:  2048,
+		},
+		slog.Default(),
+		&fakeRunner{},
+	)
+
+	result, err := service.OpenMarkdown(context.Background(), workspaceID, "inbox/telegram/42/7-plan.pdf")
+	if err != nil {
+		t.Fatalf("open attachment failed: %v", err)
+	}
+	if result.Path != "inbox/telegram/42/7-plan.pdf" || !strings.Contains(result.Content, "Freeze on Friday") {
+		t.Fatalf("expected sidecar text for the attachment, got %+v", result)
+	}
+	if _, err := service.OpenMarkdown(context.Background(), workspaceID, "inbox/telegram/42/8-raw.bin"); !errors.Is(err, ErrInvalidTarget) {
+		t.Fatalf("expected invalid target without a sidecar, got %v", err)
+	}
+}
+
 func TestSearchReturnsUnavailableWhenBinaryMissing(t *testing.T) {
 	root := t.TempDir()
 	workspaceID := "ws-unavailable"
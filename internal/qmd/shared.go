@@ -0,0 +1,130 @@
+package qmd
+
+import (
+	"context"
+	"strings"
+)
+
+// SharedTargetPrefix labels search results and open targets that come from
+// the shared knowledge workspace rather than the caller's own workspace.
+const SharedTargetPrefix = "shared:"
+
+type sharedKnowledgeKey struct{}
+
+// WithSharedKnowledge marks ctx as belonging to a context that opted in to
+// the shared knowledge workspace.
+func WithSharedKnowledge(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sharedKnowledgeKey{}, true)
+}
+
+// SharedKnowledgeEnabled reports whether ctx opted in to shared knowledge.
+func SharedKnowledgeEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(sharedKnowledgeKey{}).(bool)
+	return enabled
+}
+
+// IsSharedTarget reports whether a search result path, doc id or open target
+// points into the shared knowledge workspace.
+func IsSharedTarget(target string) bool {
+	return strings.HasPrefix(strings.TrimSpace(target), SharedTargetPrefix)
+}
+
+// Backend is the search surface a Federated retriever wraps; *Service
+// implements it.
+type Backend interface {
+	Search(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error)
+	VectorSearch(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error)
+	OpenMarkdown(ctx context.Context, workspaceID, target string) (OpenResult, error)
+	Status(ctx context.Context, workspaceID string) (Status, error)
+}
+
+// Federated adds a shared knowledge workspace to searches made on behalf of
+// contexts that opted in with WithSharedKnowledge. Shared results are
+// interleaved with the workspace's own by rank and labeled with
+// SharedTargetPrefix so they can be told apart and opened again.
+type Federated struct {
+	backend         Backend
+	sharedWorkspace string
+}
+
+func NewFederated(backend Backend, sharedWorkspaceID string) *Federated {
+	return &Federated{
+		backend:         backend,
+		sharedWorkspace: strings.TrimSpace(sharedWorkspaceID),
+	}
+}
+
+// SharedWorkspace is the id of the shared knowledge workspace.
+func (f *Federated) SharedWorkspace() string {
+	return f.sharedWorkspace
+}
+
+func (f *Federated) Search(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error) {
+	return f.federate(ctx, workspaceID, query, limit, f.backend.Search)
+}
+
+func (f *Federated) VectorSearch(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error) {
+	return f.federate(ctx, workspaceID, query, limit, f.backend.VectorSearch)
+}
+
+func (f *Federated) OpenMarkdown(ctx context.Context, workspaceID, target string) (OpenResult, error) {
+	target = strings.TrimSpace(target)
+	if !IsSharedTarget(target) {
+		return f.backend.OpenMarkdown(ctx, workspaceID, target)
+	}
+	if !f.shares(ctx, workspaceID) {
+		return OpenResult{}, ErrNotFound
+	}
+	result, err := f.backend.OpenMarkdown(ctx, f.sharedWorkspace, strings.TrimPrefix(target, SharedTargetPrefix))
+	if err != nil {
+		return OpenResult{}, err
+	}
+	result.Path = SharedTargetPrefix + result.Path
+	return result, nil
+}
+
+func (f *Federated) Status(ctx context.Context, workspaceID string) (Status, error) {
+	return f.backend.Status(ctx, workspaceID)
+}
+
+func (f *Federated) shares(ctx context.Context, workspaceID string) bool {
+	return f.sharedWorkspace != "" &&
+		strings.TrimSpace(workspaceID) != f.sharedWorkspace &&
+		SharedKnowledgeEnabled(ctx)
+}
+
+func (f *Federated) federate(ctx context.Context, workspaceID, query string, limit int, search func(context.Context, string, string, int) ([]SearchResult, error)) ([]SearchResult, error) {
+	own, err := search(ctx, workspaceID, query, limit)
+	if err != nil || !f.shares(ctx, workspaceID) {
+		return own, err
+	}
+	// The shared workspace only adds to the results; when it cannot be
+	// searched the workspace's own results still stand.
+	shared, sharedErr := search(ctx, f.sharedWorkspace, query, limit)
+	if sharedErr != nil || len(shared) == 0 {
+		return own, nil
+	}
+	merged := make([]SearchResult, 0, len(own)+len(shared))
+	for rank := 0; rank < len(own) || rank < len(shared); rank++ {
+		if rank < len(own) {
+			merged = append(merged, own[rank])
+		}
+		if rank < len(shared) {
+			merged = append(merged, labelShared(shared[rank]))
+		}
+	}
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+func labelShared(result SearchResult) SearchResult {
+	if result.Path != "" {
+		result.Path = SharedTargetPrefix + result.Path
+	}
+	if result.DocID != "" {
+		result.DocID = SharedTargetPrefix + result.DocID
+	}
+	return result
+}
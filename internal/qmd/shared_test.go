@@ -0,0 +1,66 @@
+package qmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type backendStub struct {
+	results map[string][]SearchResult
+	opened  []string
+}
+
+func (b *backendStub) Search(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error) {
+	return b.results[workspaceID], nil
+}
+
+func (b *backendStub) VectorSearch(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error) {
+	return nil, errors.New("not embedded")
+}
+
+func (b *backendStub) OpenMarkdown(ctx context.Context, workspaceID, target string) (OpenResult, error) {
+	b.opened = append(b.opened, workspaceID+"/"+target)
+	return OpenResult{Path: target, Content: "content"}, nil
+}
+
+func (b *backendStub) Status(ctx context.Context, workspaceID string) (Status, error) {
+	return Status{WorkspaceID: workspaceID}, nil
+}
+
+func TestFederatedSearchAddsLabeledSharedResultsWhenOptedIn(t *testing.T) {
+	backend := &backendStub{results: map[string][]SearchResult{
+		"ws-1":   {{Path: "notes/a.md"}, {Path: "notes/b.md"}},
+		"shared": {{Path: "docs/runbook.md"}, {DocID: "#abc123"}},
+	}}
+	federated := NewFederated(backend, "shared")
+	ctx := context.Background()
+
+	results, err := federated.Search(ctx, "ws-1", "deploy", 10)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected only own results without opt-in, got %+v (%v)", results, err)
+	}
+
+	optedIn := WithSharedKnowledge(ctx)
+	results, err = federated.Search(optedIn, "ws-1", "deploy", 3)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 3 || results[0].Path != "notes/a.md" || results[1].Path != "shared:docs/runbook.md" || results[2].Path != "notes/b.md" {
+		t.Fatalf("expected interleaved, labeled results cut to the limit, got %+v", results)
+	}
+	if results, _ := federated.Search(optedIn, "shared", "deploy", 10); len(results) != 2 || IsSharedTarget(results[0].Path) {
+		t.Fatalf("expected the shared workspace to search itself unlabeled, got %+v", results)
+	}
+	if results, err := federated.VectorSearch(optedIn, "ws-1", "deploy", 10); err == nil || results != nil {
+		t.Fatalf("expected own vector errors to surface, got %+v (%v)", results, err)
+	}
+
+	opened, err := federated.OpenMarkdown(optedIn, "ws-1", "shared:docs/runbook.md")
+	if err != nil || opened.Path != "shared:docs/runbook.md" || backend.opened[0] != "shared/docs/runbook.md" {
+		t.Fatalf("expected shared target to open from the shared workspace, got %+v %v (%v)", opened, backend.opened, err)
+	}
+	if _, err := federated.OpenMarkdown(ctx, "ws-1", "shared:docs/runbook.md"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected shared target to be hidden without opt-in, got %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+// Package quota enforces per-workspace usage limits: tasks per day,
+// objectives, action approvals per day and model tokens per month. Limits
+// come from runtime defaults with optional per-workspace overrides stored in
+// SQLite; zero means unlimited.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// Limits are the quotas of one workspace. Zero means unlimited.
+type Limits struct {
+	TasksPerDay    int
+	Objectives     int
+	ActionsPerDay  int
+	TokensPerMonth int
+}
+
+// Usage is the current consumption of one quota resource.
+type Usage struct {
+	Resource store.QuotaResource
+	Period   string
+	Limit    int
+	Used     int
+}
+
+// ExceededError reports which quota a workspace ran into. It wraps
+// store.ErrQuotaExceeded.
+type ExceededError struct {
+	WorkspaceID string
+	Resource    store.QuotaResource
+	Limit       int
+	Used        int
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("workspace %s reached its quota of %d %s (used %d)", e.WorkspaceID, e.Limit, resourceLabel(e.Resource), e.Used)
+}
+
+func (e *ExceededError) Unwrap() error {
+	return store.ErrQuotaExceeded
+}
+
+// Notifier tells workspace admins that a quota was hit. It is called at most
+// once per workspace, resource and quota period.
+type Notifier interface {
+	NotifyQuotaExceeded(ctx context.Context, exceeded *ExceededError)
+}
+
+type Service struct {
+	store    *store.Store
+	defaults Limits
+	notifier Notifier
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu       sync.Mutex
+	notified map[string]struct{}
+}
+
+func New(storeRef *store.Store, defaults Limits, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		store:    storeRef,
+		defaults: defaults,
+		logger:   logger,
+		now:      func() time.Time { return time.Now().UTC() },
+		notified: map[string]struct{}{},
+	}
+}
+
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// Limits returns the effective limits of a workspace.
+func (s *Service) Limits(ctx context.Context, workspaceID string) (Limits, error) {
+	limits := s.defaults
+	overrides, err := s.store.LookupWorkspaceQuota(ctx, workspaceID)
+	if err != nil {
+		return Limits{}, err
+	}
+	if overrides.TasksPerDay != nil {
+		limits.TasksPerDay = *overrides.TasksPerDay
+	}
+	if overrides.Objectives != nil {
+		limits.Objectives = *overrides.Objectives
+	}
+	if overrides.ActionsPerDay != nil {
+		limits.ActionsPerDay = *overrides.ActionsPerDay
+	}
+	if overrides.TokensPerMonth != nil {
+		limits.TokensPerMonth = *overrides.TokensPerMonth
+	}
+	return limits, nil
+}
+
+// Report returns limit and usage for every quota resource of a workspace.
+func (s *Service) Report(ctx context.Context, workspaceID string) ([]Usage, error) {
+	limits, err := s.Limits(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	report := []Usage{}
+	for _, resource := range []store.QuotaResource{store.QuotaTasks, store.QuotaObjectives, store.QuotaActions, store.QuotaTokens} {
+		used, err := s.used(ctx, workspaceID, resource, "")
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, Usage{
+			Resource: resource,
+			Period:   s.period(resource),
+			Limit:    limitFor(limits, resource),
+			Used:     used,
+		})
+	}
+	return report, nil
+}
+
+// CheckQuota implements store.QuotaGuard.
+func (s *Service) CheckQuota(ctx context.Context, workspaceID string, resource store.QuotaResource) error {
+	return s.check(ctx, workspaceID, resource, "")
+}
+
+// Admit implements orchestrator.Admission. Reindex and memory compaction
+// tasks are housekeeping and always admitted; other tasks need both task and
+// token quota left.
+func (s *Service) Admit(task orchestrator.Task) error {
+	if task.Kind == orchestrator.TaskKindReindex || task.Kind == orchestrator.TaskKindMemoryCompaction {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.check(ctx, task.WorkspaceID, store.QuotaTasks, task.ID); err != nil {
+		return err
+	}
+	return s.check(ctx, task.WorkspaceID, store.QuotaTokens, "")
+}
+
+// RecordUsage implements llm.UsageRecorder.
+func (s *Service) RecordUsage(ctx context.Context, usage llm.Usage) {
+	if err := s.store.AddTokenUsage(ctx, usage.WorkspaceID, s.period(store.QuotaTokens), usage.InputTokens, usage.OutputTokens); err != nil {
+		s.logger.Warn("record token usage failed", "workspace_id", usage.WorkspaceID, "error", err)
+	}
+}
+
+// WrapResponder refuses model calls for workspaces over their token quota.
+func (s *Service) WrapResponder(next llm.Responder) llm.Responder {
+	return &quotaResponder{next: next, quota: s}
+}
+
+type quotaResponder struct {
+	next  llm.Responder
+	quota *Service
+}
+
+func (r *quotaResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	if err := r.quota.check(ctx, input.WorkspaceID, store.QuotaTokens, ""); err != nil {
+		return "", err
+	}
+	return r.next.Reply(ctx, input)
+}
+
+func (s *Service) check(ctx context.Context, workspaceID string, resource store.QuotaResource, excludeTaskID string) error {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if s == nil || s.store == nil || workspaceID == "" {
+		return nil
+	}
+	limits, err := s.Limits(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	limit := limitFor(limits, resource)
+	if limit <= 0 {
+		return nil
+	}
+	used, err := s.used(ctx, workspaceID, resource, excludeTaskID)
+	if err != nil {
+		return err
+	}
+	if used < limit {
+		return nil
+	}
+	exceeded := &ExceededError{WorkspaceID: workspaceID, Resource: resource, Limit: limit, Used: used}
+	s.notify(ctx, exceeded)
+	return exceeded
+}
+
+func (s *Service) used(ctx context.Context, workspaceID string, resource store.QuotaResource, excludeTaskID string) (int, error) {
+	now := s.now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch resource {
+	case store.QuotaTasks:
+		return s.store.CountTasksCreatedSince(ctx, workspaceID, dayStart, excludeTaskID)
+	case store.QuotaObjectives:
+		return s.store.CountObjectives(ctx, workspaceID)
+	case store.QuotaActions:
+		return s.store.CountActionApprovalsSince(ctx, workspaceID, dayStart)
+	case store.QuotaTokens:
+		inputTokens, outputTokens, err := s.store.TokenUsage(ctx, workspaceID, s.period(resource))
+		return inputTokens + outputTokens, err
+	}
+	return 0, fmt.Errorf("unknown quota resource %q", resource)
+}
+
+// period names the window a resource is counted in: the UTC day for daily
+// quotas, the UTC month for tokens and empty for the objective count.
+func (s *Service) period(resource store.QuotaResource) string {
+	now := s.now()
+	switch resource {
+	case store.QuotaTasks, store.QuotaActions:
+		return now.Format("2006-01-02")
+	case store.QuotaTokens:
+		return now.Format("2006-01")
+	}
+	return ""
+}
+
+func (s *Service) notify(ctx context.Context, exceeded *ExceededError) {
+	if s.notifier == nil {
+		return
+	}
+	key := exceeded.WorkspaceID + "|" + string(exceeded.Resource) + "|" + s.period(exceeded.Resource)
+	s.mu.Lock()
+	_, seen := s.notified[key]
+	s.notified[key] = struct{}{}
+	s.mu.Unlock()
+	if seen {
+		return
+	}
+	s.notifier.NotifyQuotaExceeded(ctx, exceeded)
+}
+
+func limitFor(limits Limits, resource store.QuotaResource) int {
+	switch resource {
+	case store.QuotaTasks:
+		return limits.TasksPerDay
+	case store.QuotaObjectives:
+		return limits.Objectives
+	case store.QuotaActions:
+		return limits.ActionsPerDay
+	case store.QuotaTokens:
+		return limits.TokensPerMonth
+	}
+	return 0
+}
+
+func resourceLabel(resource store.QuotaResource) string {
+	switch resource {
+	case store.QuotaTasks:
+		return "tasks per day"
+	case store.QuotaObjectives:
+		return "objectives"
+	case store.QuotaActions:
+		return "actions per day"
+	case store.QuotaTokens:
+		return "model tokens per month"
+	}
+	return string(resource)
+}
@@ -0,0 +1,105 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type recordingNotifier struct {
+	items []*ExceededError
+}
+
+func (n *recordingNotifier) NotifyQuotaExceeded(ctx context.Context, exceeded *ExceededError) {
+	n.items = append(n.items, exceeded)
+}
+
+type staticResponder struct{ calls int }
+
+func (r *staticResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	r.calls++
+	return "ok", nil
+}
+
+func newQuotaTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "quota.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(context.Background()); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	return sqlStore
+}
+
+func TestAdmitEnforcesDailyTaskQuotaWithOverrides(t *testing.T) {
+	sqlStore := newQuotaTestStore(t)
+	ctx := context.Background()
+	service := New(sqlStore, Limits{TasksPerDay: 1}, nil)
+	notifier := &recordingNotifier{}
+	service.SetNotifier(notifier)
+
+	if err := sqlStore.CreateTask(ctx, store.CreateTaskInput{ID: "task-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general", Title: "t", Prompt: "p", Status: "queued"}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := service.Admit(orchestrator.Task{ID: "task-1", WorkspaceID: "ws-1"}); err != nil {
+		t.Fatalf("expected re-queue of a counted task to be admitted, got %v", err)
+	}
+	err := service.Admit(orchestrator.Task{ID: "task-2", WorkspaceID: "ws-1"})
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) || !errors.Is(err, store.ErrQuotaExceeded) || exceeded.Limit != 1 {
+		t.Fatalf("expected task quota error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "quota of 1 tasks per day") {
+		t.Fatalf("unexpected error text %q", err.Error())
+	}
+	_ = service.Admit(orchestrator.Task{ID: "task-3", WorkspaceID: "ws-1"})
+	if len(notifier.items) != 1 {
+		t.Fatalf("expected one admin notification per period, got %d", len(notifier.items))
+	}
+	if err := service.Admit(orchestrator.Task{ID: "reindex", WorkspaceID: "ws-1", Kind: orchestrator.TaskKindReindex}); err != nil {
+		t.Fatalf("expected reindex tasks exempt, got %v", err)
+	}
+	if err := service.Admit(orchestrator.Task{ID: "compact", WorkspaceID: "ws-1", Kind: orchestrator.TaskKindMemoryCompaction}); err != nil {
+		t.Fatalf("expected memory compaction tasks exempt, got %v", err)
+	}
+
+	unlimited := 0
+	if _, err := sqlStore.SetWorkspaceQuota(ctx, store.WorkspaceQuota{WorkspaceID: "ws-1", TasksPerDay: &unlimited}); err != nil {
+		t.Fatalf("set override: %v", err)
+	}
+	if err := service.Admit(orchestrator.Task{ID: "task-4", WorkspaceID: "ws-1"}); err != nil {
+		t.Fatalf("expected override to lift the quota, got %v", err)
+	}
+}
+
+func TestTokenQuotaBlocksModelCalls(t *testing.T) {
+	sqlStore := newQuotaTestStore(t)
+	ctx := context.Background()
+	service := New(sqlStore, Limits{TokensPerMonth: 100}, nil)
+	next := &staticResponder{}
+	responder := service.WrapResponder(next)
+
+	if _, err := responder.Reply(ctx, llm.MessageInput{WorkspaceID: "ws-1", Text: "hi"}); err != nil {
+		t.Fatalf("expected reply under quota, got %v", err)
+	}
+	service.RecordUsage(ctx, llm.Usage{WorkspaceID: "ws-1", InputTokens: 80, OutputTokens: 30})
+	if _, err := responder.Reply(ctx, llm.MessageInput{WorkspaceID: "ws-1", Text: "hi"}); !errors.Is(err, store.ErrQuotaExceeded) {
+		t.Fatalf("expected token quota error, got %v", err)
+	}
+	if next.calls != 1 {
+		t.Fatalf("expected blocked call not forwarded, got %d calls", next.calls)
+	}
+	report, err := service.Report(ctx, "ws-1")
+	if err != nil || len(report) != 4 || report[3].Resource != store.QuotaTokens || report[3].Used != 110 {
+		t.Fatalf("unexpected report %+v (%v)", report, err)
+	}
+}
@@ -0,0 +1,150 @@
+// Package ratelimit throttles inbound chat messages with token buckets per
+// sender and per channel, so one noisy user or channel cannot burn the model
+// budget or flood the task queue.
+package ratelimit
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope names the bucket that refused a message.
+type Scope string
+
+const (
+	ScopeUser    Scope = "user"
+	ScopeContext Scope = "context"
+)
+
+type Config struct {
+	// UserPerMinute and UserBurst size each sender's bucket within one
+	// channel. Zero UserPerMinute turns the per-user limit off.
+	UserPerMinute float64
+	UserBurst     int
+	// ContextPerMinute and ContextBurst size each channel's bucket. Zero
+	// ContextPerMinute turns the per-channel limit off.
+	ContextPerMinute float64
+	ContextBurst     int
+	// NoticeInterval is how often a throttled bucket gets a throttle reply;
+	// messages in between are dropped silently.
+	NoticeInterval time.Duration
+}
+
+// Message identifies the sender and channel of one inbound message.
+type Message struct {
+	Connector  string
+	ExternalID string
+	UserID     string
+	// SkipContext leaves the channel bucket out, for commands admins must
+	// be able to send into a flooded channel.
+	SkipContext bool
+}
+
+// Decision is the outcome of checking one message.
+type Decision struct {
+	Allowed bool
+	// Scope is the bucket that ran out when Allowed is false.
+	Scope Scope
+	// RetryAfter is how long until that bucket holds a token again.
+	RetryAfter time.Duration
+	// Notify is true for the first refusal of a bucket within
+	// NoticeInterval, when the sender should be told they are throttled.
+	Notify bool
+}
+
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens     float64
+	updatedAt  time.Time
+	notifiedAt time.Time
+}
+
+func New(cfg Config) *Limiter {
+	if cfg.UserBurst < 1 {
+		cfg.UserBurst = 1
+	}
+	if cfg.ContextBurst < 1 {
+		cfg.ContextBurst = 1
+	}
+	if cfg.NoticeInterval <= 0 {
+		cfg.NoticeInterval = time.Minute
+	}
+	return &Limiter{cfg: cfg, now: time.Now, buckets: map[string]*bucket{}}
+}
+
+// Allow takes a token from the sender's and the channel's buckets. A refused
+// message takes no token from either bucket.
+func (l *Limiter) Allow(message Message) Decision {
+	channel := strings.ToLower(strings.TrimSpace(message.Connector)) + "|" + strings.TrimSpace(message.ExternalID)
+	userKey := string(ScopeUser) + "|" + channel + "|" + strings.TrimSpace(message.UserID)
+	contextKey := string(ScopeContext) + "|" + channel
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	type check struct {
+		scope  Scope
+		bucket *bucket
+		rate   float64
+	}
+	checks := []check{}
+	if l.cfg.UserPerMinute > 0 && strings.TrimSpace(message.UserID) != "" {
+		checks = append(checks, check{ScopeUser, l.refillLocked(userKey, now, l.cfg.UserPerMinute, l.cfg.UserBurst), l.cfg.UserPerMinute})
+	}
+	if l.cfg.ContextPerMinute > 0 && !message.SkipContext {
+		checks = append(checks, check{ScopeContext, l.refillLocked(contextKey, now, l.cfg.ContextPerMinute, l.cfg.ContextBurst), l.cfg.ContextPerMinute})
+	}
+	for _, c := range checks {
+		if c.bucket.tokens >= 1 {
+			continue
+		}
+		decision := Decision{
+			Scope:      c.scope,
+			RetryAfter: time.Duration((1 - c.bucket.tokens) / c.rate * float64(time.Minute)),
+		}
+		if now.Sub(c.bucket.notifiedAt) >= l.cfg.NoticeInterval {
+			c.bucket.notifiedAt = now
+			decision.Notify = true
+		}
+		return decision
+	}
+	for _, c := range checks {
+		c.bucket.tokens--
+	}
+	if len(l.buckets) > 10000 {
+		l.pruneLocked(now)
+	}
+	return Decision{Allowed: true}
+}
+
+func (l *Limiter) refillLocked(key string, now time.Time, perMinute float64, burst int) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), updatedAt: now}
+		l.buckets[key] = b
+		return b
+	}
+	b.tokens += now.Sub(b.updatedAt).Minutes() * perMinute
+	if b.tokens > float64(burst) {
+		b.tokens = float64(burst)
+	}
+	b.updatedAt = now
+	return b
+}
+
+// pruneLocked drops buckets idle long enough to have refilled completely.
+func (l *Limiter) pruneLocked(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.updatedAt) > time.Hour {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllowThrottlesSenderAndRefills(t *testing.T) {
+	now := time.Now()
+	limiter := New(Config{UserPerMinute: 6, UserBurst: 2, NoticeInterval: time.Minute})
+	limiter.now = func() time.Time { return now }
+	message := Message{Connector: "discord", ExternalID: "c1", UserID: "u1"}
+
+	for i := 0; i < 2; i++ {
+		if decision := limiter.Allow(message); !decision.Allowed {
+			t.Fatalf("expected message %d within the burst, got %+v", i+1, decision)
+		}
+	}
+	decision := limiter.Allow(message)
+	if decision.Allowed || decision.Scope != ScopeUser || !decision.Notify || decision.RetryAfter != 10*time.Second {
+		t.Fatalf("expected a notified user throttle, got %+v", decision)
+	}
+	if decision := limiter.Allow(message); decision.Allowed || decision.Notify {
+		t.Fatalf("expected a silent throttle within the notice interval, got %+v", decision)
+	}
+	if decision := limiter.Allow(Message{Connector: "discord", ExternalID: "c1", UserID: "u2"}); !decision.Allowed {
+		t.Fatalf("expected another sender to pass, got %+v", decision)
+	}
+
+	now = now.Add(10 * time.Second)
+	if decision := limiter.Allow(message); !decision.Allowed {
+		t.Fatalf("expected a refilled token, got %+v", decision)
+	}
+}
+
+func TestAllowThrottlesChannelUnlessSkipped(t *testing.T) {
+	now := time.Now()
+	limiter := New(Config{UserPerMinute: 60, UserBurst: 5, ContextPerMinute: 1, ContextBurst: 2})
+	limiter.now = func() time.Time { return now }
+
+	for _, user := range []string{"u1", "u2"} {
+		if decision := limiter.Allow(Message{Connector: "telegram", ExternalID: "42", UserID: user}); !decision.Allowed {
+			t.Fatalf("expected %s within the channel burst, got %+v", user, decision)
+		}
+	}
+	decision := limiter.Allow(Message{Connector: "telegram", ExternalID: "42", UserID: "u3"})
+	if decision.Allowed || decision.Scope != ScopeContext {
+		t.Fatalf("expected a channel throttle, got %+v", decision)
+	}
+	if decision := limiter.Allow(Message{Connector: "telegram", ExternalID: "42", UserID: "u3", SkipContext: true}); !decision.Allowed {
+		t.Fatalf("expected a command to skip the channel bucket, got %+v", decision)
+	}
+	if decision := limiter.Allow(Message{Connector: "telegram", ExternalID: "43", UserID: "u3"}); !decision.Allowed {
+		t.Fatalf("expected another channel to pass, got %+v", decision)
+	}
+}
@@ -0,0 +1,289 @@
+// Package redact replaces personal data and secrets (emails, phone numbers,
+// API keys, card numbers) with stable tokens before text leaves the runtime
+// for a model provider or lands in a chat log. The token map stays on local
+// disk so model replies can be restored to the real values.
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+)
+
+// Kind names a category of redacted value; it is the token prefix.
+type Kind string
+
+const (
+	KindEmail  Kind = "EMAIL"
+	KindPhone  Kind = "PHONE"
+	KindAPIKey Kind = "API_KEY"
+	KindCard   Kind = "CARD"
+)
+
+// globalScope keys the token map of text that belongs to no workspace.
+const globalScope = "_global"
+
+// detectors run in order, so keys and card numbers are tokenized before the
+// phone pattern can claim their digits.
+var detectors = []struct {
+	kind    Kind
+	pattern *regexp.Regexp
+	accept  func(string) bool
+}{
+	{KindAPIKey, regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_-]{20,}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|xox[abprs]-[A-Za-z0-9-]{10,}|AIza[0-9A-Za-z_-]{35})`), nil},
+	{KindEmail, regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`), nil},
+	{KindCard, regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), luhnValid},
+	{KindPhone, regexp.MustCompile(`(?:\+\d[\d\s().-]{6,}\d|\(?\b\d{3}\)?[\s.-]\d{3}[\s.-]\d{4}\b)`), phoneDigits},
+}
+
+var tokenPattern = regexp.MustCompile(`\[(?:EMAIL|PHONE|API_KEY|CARD)_\d+\]`)
+
+// Resolver reports a workspace's redaction setting; ok is false when the
+// workspace has none and the runtime default applies.
+type Resolver interface {
+	RedactionEnabled(workspaceID string) (enabled, ok bool)
+}
+
+type Config struct {
+	// Dir holds one token map file per workspace.
+	Dir string
+	// Default applies to workspaces the resolver has no setting for.
+	Default  bool
+	Resolver Resolver
+}
+
+type Redactor struct {
+	cfg Config
+
+	mu   sync.Mutex
+	maps map[string]*tokenMap
+}
+
+// tokenMap is the reversible mapping of one workspace. Values keep their
+// token for the life of the map so a conversation stays consistent.
+type tokenMap struct {
+	Tokens map[string]string `json:"tokens"`
+	Counts map[Kind]int      `json:"counts"`
+	values map[string]string
+}
+
+func New(cfg Config) *Redactor {
+	return &Redactor{cfg: cfg, maps: map[string]*tokenMap{}}
+}
+
+// Enabled reports whether text of a workspace is redacted.
+func (r *Redactor) Enabled(workspaceID string) bool {
+	if r == nil {
+		return false
+	}
+	if r.cfg.Resolver != nil {
+		if enabled, ok := r.cfg.Resolver.RedactionEnabled(strings.TrimSpace(workspaceID)); ok {
+			return enabled
+		}
+	}
+	return r.cfg.Default
+}
+
+// Redact replaces every detected value in text with its token when
+// redaction is enabled for the workspace.
+func (r *Redactor) Redact(workspaceID, text string) string {
+	if !r.Enabled(workspaceID) || strings.TrimSpace(text) == "" {
+		return text
+	}
+	scope := scopeFor(workspaceID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tokens := r.loadLocked(scope)
+	changed := false
+	for _, detector := range detectors {
+		text = detector.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if detector.accept != nil && !detector.accept(match) {
+				return match
+			}
+			token, added := tokens.tokenFor(detector.kind, match)
+			changed = changed || added
+			return token
+		})
+	}
+	if changed {
+		// A failed write only costs the mapping after a restart; the
+		// in-memory map still restores replies.
+		_ = r.saveLocked(scope, tokens)
+	}
+	return text
+}
+
+// Restore puts the original values back in place of known tokens. Unknown
+// tokens are left as they are.
+func (r *Redactor) Restore(workspaceID, text string) string {
+	if r == nil || !strings.Contains(text, "[") {
+		return text
+	}
+	scope := scopeFor(workspaceID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tokens := r.loadLocked(scope)
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if value, ok := tokens.Tokens[token]; ok {
+			return value
+		}
+		return token
+	})
+}
+
+// WrapResponder redacts the prompt of every model call for workspaces with
+// redaction on, and restores the tokens in the reply so tools and users see
+// the real values.
+func (r *Redactor) WrapResponder(next llm.Responder) llm.Responder {
+	return &redactingResponder{next: next, redactor: r}
+}
+
+type redactingResponder struct {
+	next     llm.Responder
+	redactor *Redactor
+}
+
+func (p *redactingResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	if !p.redactor.Enabled(input.WorkspaceID) {
+		return p.next.Reply(ctx, input)
+	}
+	input.Text = p.redactor.Redact(input.WorkspaceID, input.Text)
+	input.SystemPrompt = p.redactor.Redact(input.WorkspaceID, input.SystemPrompt)
+	reply, err := p.next.Reply(ctx, input)
+	return p.redactor.Restore(input.WorkspaceID, reply), err
+}
+
+// WrapEmbedder redacts the texts of every embedding call for the workspace
+// named by llm.WithWorkspace, so personal data never reaches the embedding
+// endpoint either. Vectors need no restoring.
+func (r *Redactor) WrapEmbedder(next llm.Embedder) llm.Embedder {
+	return &redactingEmbedder{next: next, redactor: r}
+}
+
+type redactingEmbedder struct {
+	next     llm.Embedder
+	redactor *Redactor
+}
+
+func (e *redactingEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	workspaceID := llm.WorkspaceFrom(ctx)
+	if !e.redactor.Enabled(workspaceID) {
+		return e.next.Embed(ctx, texts)
+	}
+	redacted := make([]string, len(texts))
+	for index, text := range texts {
+		redacted[index] = e.redactor.Redact(workspaceID, text)
+	}
+	return e.next.Embed(ctx, redacted)
+}
+
+func (m *tokenMap) tokenFor(kind Kind, value string) (string, bool) {
+	if token, ok := m.values[value]; ok {
+		return token, false
+	}
+	m.Counts[kind]++
+	token := fmt.Sprintf("[%s_%d]", kind, m.Counts[kind])
+	m.Tokens[token] = value
+	m.values[value] = token
+	return token, true
+}
+
+func (r *Redactor) loadLocked(scope string) *tokenMap {
+	if tokens, ok := r.maps[scope]; ok {
+		return tokens
+	}
+	tokens := &tokenMap{}
+	if path := r.mapPath(scope); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(data, tokens)
+		}
+	}
+	if tokens.Tokens == nil {
+		tokens.Tokens = map[string]string{}
+	}
+	if tokens.Counts == nil {
+		tokens.Counts = map[Kind]int{}
+	}
+	tokens.values = make(map[string]string, len(tokens.Tokens))
+	for token, value := range tokens.Tokens {
+		tokens.values[value] = token
+	}
+	r.maps[scope] = tokens
+	return tokens
+}
+
+func (r *Redactor) saveLocked(scope string, tokens *tokenMap) error {
+	path := r.mapPath(scope)
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (r *Redactor) mapPath(scope string) string {
+	dir := strings.TrimSpace(r.cfg.Dir)
+	if dir == "" || strings.ContainsAny(scope, `/\`) || scope == "." || scope == ".." {
+		return ""
+	}
+	return filepath.Join(dir, scope+".json")
+}
+
+func scopeFor(workspaceID string) string {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return globalScope
+	}
+	return workspaceID
+}
+
+// luhnValid accepts digit runs that pass the card number checksum.
+func luhnValid(match string) bool {
+	sum, double, digits := 0, false, 0
+	for index := len(match) - 1; index >= 0; index-- {
+		char := match[index]
+		if char < '0' || char > '9' {
+			continue
+		}
+		digit := int(char - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+		digits++
+	}
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
+
+// phoneDigits accepts matches with a plausible number of digits for a phone
+// number.
+func phoneDigits(match string) bool {
+	digits := 0
+	for _, char := range match {
+		if char >= '0' && char <= '9' {
+			digits++
+		}
+	}
+	return digits >= 8 && digits <= 15
+}
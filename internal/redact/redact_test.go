@@ -0,0 +1,125 @@
+package redact
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+)
+
+type fixedResolver map[string]bool
+
+func (r fixedResolver) RedactionEnabled(workspaceID string) (bool, bool) {
+	enabled, ok := r[workspaceID]
+	return enabled, ok
+}
+
+func TestRedactReplacesSensitiveValues(t *testing.T) {
+	redactor := New(Config{Default: true})
+	text := "Mail jane.doe@example.com or call +1 (555) 123-4567, card 4111 1111 1111 1111, key sk-abcdefghijklmnopqrstuvwx. Order 12345 ships 2026-10-17."
+	redacted := redactor.Redact("ws-1", text)
+	for _, secret := range []string{"jane.doe@example.com", "555", "4111", "sk-abcdef"} {
+		if strings.Contains(redacted, secret) {
+			t.Fatalf("expected %q redacted, got %q", secret, redacted)
+		}
+	}
+	for _, token := range []string{"[EMAIL_1]", "[PHONE_1]", "[CARD_1]", "[API_KEY_1]", "Order 12345 ships 2026-10-17."} {
+		if !strings.Contains(redacted, token) {
+			t.Fatalf("expected %q in %q", token, redacted)
+		}
+	}
+	if again := redactor.Redact("ws-1", "cc jane.doe@example.com"); again != "cc [EMAIL_1]" {
+		t.Fatalf("expected a stable token, got %q", again)
+	}
+	if restored := redactor.Restore("ws-1", redacted); restored != text {
+		t.Fatalf("expected restore to round-trip, got %q", restored)
+	}
+	if kept := redactor.Redact("ws-1", "card 4111 1111 1111 1112"); !strings.Contains(kept, "4111 1111 1111 1112") {
+		t.Fatalf("expected a failing checksum to stay, got %q", kept)
+	}
+}
+
+func TestRedactFollowsWorkspaceSettingAndPersists(t *testing.T) {
+	dir := t.TempDir()
+	redactor := New(Config{Dir: dir, Resolver: fixedResolver{"ws-on": true}})
+	if text := redactor.Redact("ws-off", "ops@example.com"); text != "ops@example.com" {
+		t.Fatalf("expected redaction off by default, got %q", text)
+	}
+	if text := redactor.Redact("ws-on", "ops@example.com"); text != "[EMAIL_1]" {
+		t.Fatalf("expected redaction for the opted-in workspace, got %q", text)
+	}
+	info, err := os.Stat(filepath.Join(dir, "ws-on.json"))
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a private token map, got %v (%v)", info, err)
+	}
+	reloaded := New(Config{Dir: dir, Resolver: fixedResolver{"ws-on": true}})
+	if restored := reloaded.Restore("ws-on", "send to [EMAIL_1]"); restored != "send to ops@example.com" {
+		t.Fatalf("expected the map to survive a restart, got %q", restored)
+	}
+	if text := reloaded.Redact("ws-on", "ops@example.com and dev@example.com"); text != "[EMAIL_1] and [EMAIL_2]" {
+		t.Fatalf("expected numbering to continue, got %q", text)
+	}
+}
+
+type recordingResponder struct {
+	input llm.MessageInput
+	reply string
+}
+
+func (r *recordingResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	r.input = input
+	return r.reply, nil
+}
+
+func TestWrapResponderRedactsPromptAndRestoresReply(t *testing.T) {
+	redactor := New(Config{Default: true})
+	base := &recordingResponder{reply: "I'll write to [EMAIL_1]."}
+	reply, err := redactor.WrapResponder(base).Reply(context.Background(), llm.MessageInput{
+		WorkspaceID:  "ws-1",
+		Text:         "email sam@example.com the report",
+		SystemPrompt: "Owner: sam@example.com",
+	})
+	if err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+	if base.input.Text != "email [EMAIL_1] the report" || base.input.SystemPrompt != "Owner: [EMAIL_1]" {
+		t.Fatalf("expected redacted prompt, got %+v", base.input)
+	}
+	if reply != "I'll write to sam@example.com." {
+		t.Fatalf("expected restored reply, got %q", reply)
+	}
+}
+
+type recordingEmbedder struct {
+	texts []string
+}
+
+func (e *recordingEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	e.texts = texts
+	return make([][]float64, len(texts)), nil
+}
+
+func TestWrapEmbedderRedactsQueryForWorkspace(t *testing.T) {
+	redactor := New(Config{Resolver: fixedResolver{"ws-on": true}})
+	base := &recordingEmbedder{}
+	embedder := redactor.WrapEmbedder(base)
+
+	ctx := llm.WithWorkspace(context.Background(), "ws-on")
+	if _, err := embedder.Embed(ctx, []string{"invoice for sam@example.com", "billing skill"}); err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if base.texts[0] != "invoice for [EMAIL_1]" || base.texts[1] != "billing skill" {
+		t.Fatalf("expected redacted texts, got %q", base.texts)
+	}
+
+	ctx = llm.WithWorkspace(context.Background(), "ws-off")
+	if _, err := embedder.Embed(ctx, []string{"invoice for sam@example.com"}); err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if base.texts[0] != "invoice for sam@example.com" {
+		t.Fatalf("expected workspaces without redaction to pass through, got %q", base.texts)
+	}
+}
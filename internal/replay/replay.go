@@ -0,0 +1,301 @@
+// Package replay runs recorded or scripted conversations through the
+// gateway against a scripted model, in a throwaway store, and checks the
+// routing decisions and tool calls of every turn. It lets prompt, policy and
+// tool changes be regression-tested without a provider or a live runtime.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/memorylog"
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/qmd"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	RouteAgent   = "agent"
+	RouteCommand = "command"
+)
+
+// errScriptExhausted is returned to the agent when it asks the model for
+// more responses than the turn scripts.
+var errScriptExhausted = errors.New("replay: the scenario scripts no further model response")
+
+// Report is the outcome of a scenario run.
+type Report struct {
+	Scenario string       `json:"scenario"`
+	Turns    []TurnResult `json:"turns"`
+	Failures int          `json:"failures"`
+}
+
+// TurnResult is what the runtime did with one turn and which expectations
+// it missed.
+type TurnResult struct {
+	Index       int        `json:"index"`
+	User        string     `json:"user"`
+	Reply       string     `json:"reply"`
+	Route       string     `json:"route"`
+	ModelCalls  int        `json:"model_calls"`
+	ToolCalls   []ToolCall `json:"tool_calls"`
+	Tasks       []TaskInfo `json:"tasks"`
+	Failures    []string   `json:"failures,omitempty"`
+	HandleError string     `json:"handle_error,omitempty"`
+}
+
+type ToolCall struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type TaskInfo struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	RouteClass string `json:"route_class"`
+	Priority   string `json:"priority"`
+	Lane       string `json:"lane"`
+}
+
+// Run replays scenario in a fresh store and workspace root under dir, which
+// the caller owns and removes. A nil logger discards runtime logs.
+func Run(ctx context.Context, scenario Scenario, dir string, logger *slog.Logger) (Report, error) {
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if err := scenario.validate(); err != nil {
+		return Report{}, err
+	}
+	sqlStore, err := store.New(filepath.Join(dir, "replay.db"))
+	if err != nil {
+		return Report{}, err
+	}
+	defer sqlStore.Close()
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		return Report{}, err
+	}
+	workspaceRoot := filepath.Join(dir, "workspaces")
+
+	connector, externalID, fromUserID, displayName := scenario.identity()
+	contextRecord, err := sqlStore.EnsureContextForExternalChannel(ctx, connector, externalID, displayName)
+	if err != nil {
+		return Report{}, err
+	}
+	if scenario.AdminChannel {
+		if _, err := sqlStore.SetContextAdminByExternal(ctx, connector, externalID, true); err != nil {
+			return Report{}, err
+		}
+	}
+
+	model := &scriptedModel{}
+	engine := &recordingEngine{}
+	service := gateway.New(sqlStore, engine, emptyRetriever{}, nil, workspaceRoot, logger)
+	service.SetTriageAcknowledger(model)
+
+	report := Report{Scenario: scenario.Name}
+	for index, turn := range scenario.Turns {
+		model.script(turn.Model)
+		queuedBefore := len(engine.tasks)
+		toolsBefore, err := toolCallLog(workspaceRoot, contextRecord.WorkspaceID, connector, externalID)
+		if err != nil {
+			return Report{}, err
+		}
+
+		output, handleErr := service.HandleMessage(ctx, gateway.MessageInput{
+			Connector:   connector,
+			ExternalID:  externalID,
+			DisplayName: displayName,
+			FromUserID:  fromUserID,
+			Text:        turn.User,
+		})
+
+		result := TurnResult{
+			Index:      index + 1,
+			User:       turn.User,
+			Reply:      strings.TrimSpace(output.Reply),
+			Route:      RouteCommand,
+			ModelCalls: model.asked,
+		}
+		if handleErr != nil {
+			result.HandleError = handleErr.Error()
+			result.Failures = append(result.Failures, "gateway error: "+handleErr.Error())
+		}
+		if model.asked > 0 {
+			result.Route = RouteAgent
+		}
+		toolsAfter, err := toolCallLog(workspaceRoot, contextRecord.WorkspaceID, connector, externalID)
+		if err != nil {
+			return Report{}, err
+		}
+		result.ToolCalls = toolsAfter[len(toolsBefore):]
+		for _, task := range engine.tasks[queuedBefore:] {
+			info := TaskInfo{ID: task.ID, Title: task.Title}
+			if record, err := sqlStore.LookupTask(ctx, task.ID); err == nil {
+				info.RouteClass = record.RouteClass
+				info.Priority = record.Priority
+				info.Lane = record.AssignedLane
+			}
+			result.Tasks = append(result.Tasks, info)
+		}
+		if model.exhausted {
+			result.Failures = append(result.Failures, fmt.Sprintf("the model was asked for response %d but the turn scripts %d", model.asked, len(turn.Model)))
+		} else if model.asked > 0 && model.asked < len(turn.Model) {
+			result.Failures = append(result.Failures, fmt.Sprintf("the turn ended after %d of %d scripted model responses", model.asked, len(turn.Model)))
+		}
+		result.Failures = append(result.Failures, turn.Expect.check(result)...)
+		report.Failures += len(result.Failures)
+		report.Turns = append(report.Turns, result)
+	}
+	return report, nil
+}
+
+func (e Expect) check(result TurnResult) []string {
+	failures := []string{}
+	if e.Route != "" && e.Route != result.Route {
+		failures = append(failures, fmt.Sprintf("route: want %s, got %s", e.Route, result.Route))
+	}
+	names := make([]string, 0, len(result.ToolCalls))
+	statuses := make([]string, 0, len(result.ToolCalls))
+	for _, call := range result.ToolCalls {
+		names = append(names, call.Name)
+		statuses = append(statuses, call.Status)
+	}
+	if e.Tools != nil && !equalFold(e.Tools, names) {
+		failures = append(failures, fmt.Sprintf("tools: want [%s], got [%s]", strings.Join(e.Tools, ", "), strings.Join(names, ", ")))
+	}
+	if e.ToolStatus != nil && !equalFold(e.ToolStatus, statuses) {
+		failures = append(failures, fmt.Sprintf("tool status: want [%s], got [%s]", strings.Join(e.ToolStatus, ", "), strings.Join(statuses, ", ")))
+	}
+	for _, fragment := range e.ReplyContains {
+		if !strings.Contains(strings.ToLower(result.Reply), strings.ToLower(fragment)) {
+			failures = append(failures, fmt.Sprintf("reply does not contain %q", fragment))
+		}
+	}
+	if e.Tasks != nil && *e.Tasks != len(result.Tasks) {
+		failures = append(failures, fmt.Sprintf("tasks: want %d, got %d", *e.Tasks, len(result.Tasks)))
+	}
+	if e.Task != nil {
+		if len(result.Tasks) == 0 {
+			return append(failures, "task: no task was queued")
+		}
+		task := result.Tasks[0]
+		for _, field := range []struct{ name, want, got string }{
+			{"route_class", e.Task.RouteClass, task.RouteClass},
+			{"priority", e.Task.Priority, task.Priority},
+			{"lane", e.Task.Lane, task.Lane},
+		} {
+			if field.want != "" && !strings.EqualFold(field.want, field.got) {
+				failures = append(failures, fmt.Sprintf("task %s: want %s, got %s", field.name, field.want, field.got))
+			}
+		}
+	}
+	return failures
+}
+
+func equalFold(want, got []string) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for index := range want {
+		if !strings.EqualFold(strings.TrimSpace(want[index]), got[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+// toolCallLog reads the tool calls the gateway logged for the chat so far.
+func toolCallLog(workspaceRoot, workspaceID, connector, externalID string) ([]ToolCall, error) {
+	records, err := memorylog.ReadEntries(workspaceRoot, workspaceID, connector, externalID, 0)
+	if err != nil {
+		return nil, err
+	}
+	calls := []ToolCall{}
+	for _, record := range records {
+		if record.Direction != "tool" {
+			continue
+		}
+		call := ToolCall{}
+		for _, line := range strings.Split(record.Text, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if value, ok := strings.CutPrefix(trimmed, "- tool:"); ok {
+				call.Name = strings.Trim(strings.TrimSpace(value), "`")
+			}
+			if value, ok := strings.CutPrefix(trimmed, "- status:"); ok {
+				call.Status = strings.Trim(strings.TrimSpace(value), "`")
+			}
+		}
+		if call.Name != "" {
+			calls = append(calls, call)
+		}
+	}
+	return calls, nil
+}
+
+// scriptedModel answers the agent from the current turn's script.
+type scriptedModel struct {
+	mu        sync.Mutex
+	responses []string
+	asked     int
+	exhausted bool
+}
+
+func (m *scriptedModel) script(responses []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses = responses
+	m.asked = 0
+	m.exhausted = false
+}
+
+func (m *scriptedModel) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.asked++
+	if m.asked > len(m.responses) {
+		m.exhausted = true
+		return "", errScriptExhausted
+	}
+	return m.responses[m.asked-1], nil
+}
+
+// recordingEngine keeps queued tasks instead of running them.
+type recordingEngine struct {
+	mu    sync.Mutex
+	tasks []orchestrator.Task
+}
+
+func (e *recordingEngine) Enqueue(task orchestrator.Task) (orchestrator.Task, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if task.ID == "" {
+		task.ID = uuid.NewString()
+	}
+	e.tasks = append(e.tasks, task)
+	return task, nil
+}
+
+// emptyRetriever stands in for qmd: replays have no indexed knowledge.
+type emptyRetriever struct{}
+
+func (emptyRetriever) Search(ctx context.Context, workspaceID, query string, limit int) ([]qmd.SearchResult, error) {
+	return nil, nil
+}
+
+func (emptyRetriever) OpenMarkdown(ctx context.Context, workspaceID, target string) (qmd.OpenResult, error) {
+	return qmd.OpenResult{}, fmt.Errorf("no knowledge documents during replay")
+}
+
+func (emptyRetriever) Status(ctx context.Context, workspaceID string) (qmd.Status, error) {
+	return qmd.Status{}, nil
+}
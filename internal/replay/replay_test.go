@@ -0,0 +1,75 @@
+package replay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunChecksToolCallsAndTaskRouting(t *testing.T) {
+	one := 1
+	scenario := Scenario{
+		Name: "create task",
+		Turns: []Turn{{
+			User: "the nightly report failed, please look into it",
+			Model: []string{
+				`{"tool":"create_task","args":{"title":"Investigate report","description":"follow up with logs","priority":"p2"}}`,
+				`{"final":"I created a follow-up task.","confidence":0.9}`,
+			},
+			Expect: Expect{
+				Route:         RouteAgent,
+				Tools:         []string{"create_task"},
+				ToolStatus:    []string{"succeeded"},
+				ReplyContains: []string{"follow-up task"},
+				Tasks:         &one,
+				Task:          &TaskExpect{RouteClass: "task", Priority: "p2", Lane: "operations"},
+			},
+		}},
+	}
+
+	report, err := Run(context.Background(), scenario, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Failures != 0 {
+		t.Fatalf("expected a passing replay, got %+v", report.Turns)
+	}
+	if len(report.Turns) != 1 || report.Turns[0].ModelCalls != 2 || report.Turns[0].Tasks[0].Title != "Investigate report" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestRunReportsMissedExpectations(t *testing.T) {
+	scenario := Scenario{
+		Turns: []Turn{{
+			User:  "what is going on?",
+			Model: []string{`{"final":"Nothing new.","confidence":0.9}`},
+			Expect: Expect{
+				Tools: []string{"create_task"},
+				Task:  &TaskExpect{Lane: "operations"},
+			},
+		}},
+	}
+
+	report, err := Run(context.Background(), scenario, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	failures := report.Turns[0].Failures
+	if report.Failures != 2 || !strings.Contains(failures[0], "tools: want [create_task], got []") || !strings.Contains(failures[1], "no task") {
+		t.Fatalf("unexpected failures %q", failures)
+	}
+}
+
+func TestLoadScenarioRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	content := "turns:\n  - user: hello\n    expect:\n      toolz: [create_task]\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadScenario(path); err == nil || !strings.Contains(err.Error(), "toolz") {
+		t.Fatalf("expected unknown key rejected, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a conversation to feed through the gateway. Each turn scripts
+// the model's responses, so a run exercises routing, policies and tools
+// without calling a provider.
+type Scenario struct {
+	Name        string `yaml:"name"`
+	Connector   string `yaml:"connector"`
+	ExternalID  string `yaml:"external_id"`
+	DisplayName string `yaml:"display_name"`
+	FromUserID  string `yaml:"from_user_id"`
+	// AdminChannel marks the channel as an admin channel before the first
+	// turn, for scenarios covering admin commands.
+	AdminChannel bool   `yaml:"admin_channel"`
+	Turns        []Turn `yaml:"turns"`
+}
+
+// Turn is one user message. Model lists the raw responses the model gives,
+// in order: tool calls such as {"tool":"create_task","args":{...}} and a
+// final {"final":"..."} or plain-text reply.
+type Turn struct {
+	User   string   `yaml:"user"`
+	Model  []string `yaml:"model"`
+	Expect Expect   `yaml:"expect"`
+}
+
+// Expect holds the assertions of a turn; unset fields are not checked.
+type Expect struct {
+	// Route is agent when the message reached the model and command when a
+	// command or guidance reply answered it first.
+	Route         string   `yaml:"route"`
+	Tools         []string `yaml:"tools"`
+	ToolStatus    []string `yaml:"tool_status"`
+	ReplyContains []string `yaml:"reply_contains"`
+	// Tasks is the number of tasks the turn queues.
+	Tasks *int        `yaml:"tasks"`
+	Task  *TaskExpect `yaml:"task"`
+}
+
+// TaskExpect checks the routing of the first task a turn queues.
+type TaskExpect struct {
+	RouteClass string `yaml:"route_class"`
+	Priority   string `yaml:"priority"`
+	Lane       string `yaml:"lane"`
+}
+
+// LoadScenario reads a YAML scenario file. Unknown keys are rejected so a
+// misspelled assertion does not silently pass.
+func LoadScenario(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var scenario Scenario
+	if err := decoder.Decode(&scenario); err != nil {
+		return Scenario{}, fmt.Errorf("parse scenario %s: %w", path, err)
+	}
+	if strings.TrimSpace(scenario.Name) == "" {
+		scenario.Name = path
+	}
+	if err := scenario.validate(); err != nil {
+		return Scenario{}, fmt.Errorf("scenario %s: %w", path, err)
+	}
+	return scenario, nil
+}
+
+func (s Scenario) validate() error {
+	if len(s.Turns) == 0 {
+		return fmt.Errorf("no turns")
+	}
+	for index, turn := range s.Turns {
+		if strings.TrimSpace(turn.User) == "" {
+			return fmt.Errorf("turn %d has no user message", index+1)
+		}
+		switch turn.Expect.Route {
+		case "", RouteAgent, RouteCommand:
+		default:
+			return fmt.Errorf("turn %d: route must be %s or %s", index+1, RouteAgent, RouteCommand)
+		}
+	}
+	return nil
+}
+
+// identity fills the defaults the chat CLI uses for unset identity fields.
+func (s Scenario) identity() (connector, externalID, fromUserID, displayName string) {
+	connector = strings.ToLower(strings.TrimSpace(s.Connector))
+	if connector == "" {
+		connector = "codex"
+	}
+	externalID = strings.TrimSpace(s.ExternalID)
+	if externalID == "" {
+		externalID = "replay"
+	}
+	fromUserID = strings.TrimSpace(s.FromUserID)
+	if fromUserID == "" {
+		fromUserID = externalID
+	}
+	displayName = strings.TrimSpace(s.DisplayName)
+	if displayName == "" {
+		displayName = "Replay"
+	}
+	return connector, externalID, fromUserID, displayName
+}
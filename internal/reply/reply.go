@@ -0,0 +1,128 @@
+// Package reply is the connector-neutral model of a rich outbound message.
+// The gateway and notifiers describe what to say — text, code, buttons and
+// files — and each connector renders it natively: Telegram as inline
+// keyboards and documents, Discord as embeds, components and uploads.
+// Connectors without rich rendering send PlainText.
+package reply
+
+import (
+	"strings"
+)
+
+// MaxCommandLength bounds a button's command so it fits the smallest
+// callback payload of the supported connectors (Telegram's 64 bytes).
+const MaxCommandLength = 64
+
+type ButtonStyle string
+
+const (
+	ButtonDefault ButtonStyle = ""
+	ButtonPrimary ButtonStyle = "primary"
+	ButtonDanger  ButtonStyle = "danger"
+)
+
+// Button runs Command as if the person who pressed it had sent it.
+type Button struct {
+	Label   string
+	Command string
+	Style   ButtonStyle
+}
+
+type CodeBlock struct {
+	Language string
+	Content  string
+}
+
+// Attachment is a file sent along with the message.
+type Attachment struct {
+	Name      string
+	MediaType string
+	Data      []byte
+}
+
+type Message struct {
+	Title       string
+	Text        string
+	Code        []CodeBlock
+	Buttons     []Button
+	Attachments []Attachment
+}
+
+// Text returns a message holding only text.
+func Text(text string) Message {
+	return Message{Text: text}
+}
+
+// IsZero reports whether the message has nothing to send.
+func (m Message) IsZero() bool {
+	return strings.TrimSpace(m.Title) == "" && strings.TrimSpace(m.Text) == "" &&
+		len(m.Code) == 0 && len(m.Buttons) == 0 && len(m.Attachments) == 0
+}
+
+// ValidButtons returns the buttons that have a label and a command short
+// enough for every connector.
+func (m Message) ValidButtons() []Button {
+	buttons := make([]Button, 0, len(m.Buttons))
+	for _, button := range m.Buttons {
+		label := strings.TrimSpace(button.Label)
+		command := strings.TrimSpace(button.Command)
+		if label == "" || command == "" || len(command) > MaxCommandLength {
+			continue
+		}
+		buttons = append(buttons, Button{Label: label, Command: command, Style: button.Style})
+	}
+	return buttons
+}
+
+// Body renders the title, text and code blocks as markdown, for connectors
+// that show buttons and attachments natively.
+func (m Message) Body() string {
+	parts := []string{}
+	if title := strings.TrimSpace(m.Title); title != "" {
+		parts = append(parts, "*"+title+"*")
+	}
+	if text := strings.TrimSpace(m.Text); text != "" {
+		parts = append(parts, text)
+	}
+	if code := m.CodeMarkdown(); code != "" {
+		parts = append(parts, code)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// CodeMarkdown renders the code blocks as fenced markdown.
+func (m Message) CodeMarkdown() string {
+	blocks := []string{}
+	for _, block := range m.Code {
+		content := strings.TrimRight(block.Content, "\n")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		blocks = append(blocks, "```"+strings.TrimSpace(block.Language)+"\n"+content+"\n```")
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// PlainText renders the whole message as text: buttons become the commands
+// to type and attachments are listed by name.
+func (m Message) PlainText() string {
+	parts := []string{}
+	if body := m.Body(); body != "" {
+		parts = append(parts, body)
+	}
+	if buttons := m.ValidButtons(); len(buttons) > 0 {
+		lines := make([]string, 0, len(buttons))
+		for _, button := range buttons {
+			lines = append(lines, "- "+button.Label+": `"+button.Command+"`")
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+	if len(m.Attachments) > 0 {
+		names := make([]string, 0, len(m.Attachments))
+		for _, attachment := range m.Attachments {
+			names = append(names, strings.TrimSpace(attachment.Name))
+		}
+		parts = append(parts, "Attached: "+strings.Join(names, ", "))
+	}
+	return strings.Join(parts, "\n\n")
+}
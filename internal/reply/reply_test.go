@@ -0,0 +1,36 @@
+package reply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainTextRendersEveryPart(t *testing.T) {
+	message := Message{
+		Title:   "Pending actions",
+		Text:    "1. `act_1` restart api",
+		Code:    []CodeBlock{{Language: "sh", Content: "systemctl restart api\n"}},
+		Buttons: []Button{{Label: "Approve 1", Command: "/approve-action act_1", Style: ButtonPrimary}, {Label: "", Command: "/x"}},
+		Attachments: []Attachment{
+			{Name: "plan.txt", MediaType: "text/plain", Data: []byte("plan")},
+		},
+	}
+	want := "*Pending actions*\n\n1. `act_1` restart api\n\n```sh\nsystemctl restart api\n```\n\n- Approve 1: `/approve-action act_1`\n\nAttached: plan.txt"
+	if got := message.PlainText(); got != want {
+		t.Fatalf("unexpected plain text:\n%s", got)
+	}
+}
+
+func TestValidButtonsDropsOversizedCommands(t *testing.T) {
+	message := Message{Buttons: []Button{
+		{Label: "Too long", Command: "/" + strings.Repeat("x", MaxCommandLength)},
+		{Label: " Deny ", Command: " /deny-action act_1 ", Style: ButtonDanger},
+	}}
+	buttons := message.ValidButtons()
+	if len(buttons) != 1 || buttons[0].Label != "Deny" || buttons[0].Command != "/deny-action act_1" {
+		t.Fatalf("unexpected buttons %+v", buttons)
+	}
+	if !Text("  ").IsZero() || message.IsZero() {
+		t.Fatal("unexpected IsZero result")
+	}
+}
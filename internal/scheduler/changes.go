@@ -0,0 +1,210 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	// observedValueMaxChars bounds one value kept in an objective's state.
+	observedValueMaxChars = 300
+	// observedValuesMax bounds the values compared per run.
+	observedValuesMax = 200
+	// changesSummaryValues bounds the added and removed values named in a
+	// change summary.
+	changesSummaryValues = 5
+)
+
+// objectiveChangesInstruction asks the tasks of change-detecting objectives
+// for output that can be compared line by line between runs.
+const objectiveChangesInstruction = "\n\nReply with what you observed as short facts, one per line, worded the same way on every run and without the time of this check. " +
+	"The runtime compares them with the previous run and reports only what changed."
+
+// ObjectiveChanges is what one run of a change-detecting objective found
+// compared with the run before it.
+type ObjectiveChanges struct {
+	ObjectiveID string
+	// Baseline marks the first observation, with nothing to compare to.
+	Baseline bool
+	Added    []string
+	Removed  []string
+	// Since is when the previous run observed its state.
+	Since time.Time
+}
+
+// Unchanged reports a run that observed the same values as the run before.
+func (c ObjectiveChanges) Unchanged() bool {
+	return !c.Baseline && len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// Summary describes the changes in one line for notices and run history.
+func (c ObjectiveChanges) Summary() string {
+	since := c.Since.UTC().Format("2006-01-02 15:04 UTC")
+	if c.Unchanged() {
+		return "No changes since " + since + "."
+	}
+	parts := []string{fmt.Sprintf("%d change(s) since %s.", len(c.Added)+len(c.Removed), since)}
+	if len(c.Added) > 0 {
+		parts = append(parts, "New: "+joinChangedValues(c.Added)+".")
+	}
+	if len(c.Removed) > 0 {
+		parts = append(parts, "Gone: "+joinChangedValues(c.Removed)+".")
+	}
+	return strings.Join(parts, " ")
+}
+
+// DetectObjectiveChanges compares the output of a finished objective task
+// with the values its objective's previous run observed and stores the new
+// values. It returns false for tasks of objectives that do not report
+// changes only.
+func (s *Service) DetectObjectiveChanges(ctx context.Context, taskID, output string) (ObjectiveChanges, bool, error) {
+	if s.store == nil {
+		return ObjectiveChanges{}, false, nil
+	}
+	run, err := s.store.LookupObjectiveRunByTask(ctx, taskID)
+	if errors.Is(err, store.ErrObjectiveRunNotFound) {
+		return ObjectiveChanges{}, false, nil
+	}
+	if err != nil {
+		return ObjectiveChanges{}, false, err
+	}
+	objective, err := s.store.LookupObjective(ctx, run.ObjectiveID)
+	if errors.Is(err, store.ErrObjectiveNotFound) {
+		return ObjectiveChanges{}, false, nil
+	}
+	if err != nil {
+		return ObjectiveChanges{}, false, err
+	}
+	if !objective.ReportChangesOnly {
+		return ObjectiveChanges{}, false, nil
+	}
+	values := extractObservedValues(output)
+	changes := ObjectiveChanges{ObjectiveID: objective.ID}
+	previous, err := s.store.LookupObjectiveState(ctx, objective.ID)
+	switch {
+	case errors.Is(err, store.ErrObjectiveStateNotFound):
+		changes.Baseline = true
+	case err != nil:
+		return ObjectiveChanges{}, false, err
+	default:
+		changes.Since = previous.ObservedAt
+		changes.Added, changes.Removed = diffObservedValues(previous.Values, values)
+	}
+	if _, err := s.store.SaveObjectiveState(ctx, store.SaveObjectiveStateInput{
+		ObjectiveID: objective.ID,
+		WorkspaceID: objective.WorkspaceID,
+		ContentHash: hashObservedValues(values),
+		Values:      values,
+		ObservedAt:  time.Now().UTC(),
+	}); err != nil {
+		return ObjectiveChanges{}, false, err
+	}
+	return changes, true, nil
+}
+
+// extractObservedValues splits task output into the values compared
+// between runs: its non-empty lines without list markers or code fences,
+// with whitespace collapsed, in order and without repeats.
+func extractObservedValues(output string) []string {
+	values := []string{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			continue
+		}
+		line = strings.Join(strings.Fields(trimListMarker(line)), " ")
+		if line == "" {
+			continue
+		}
+		if len(line) > observedValueMaxChars {
+			line = line[:observedValueMaxChars]
+		}
+		key := observedValueKey(line)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		values = append(values, line)
+		if len(values) >= observedValuesMax {
+			break
+		}
+	}
+	return values
+}
+
+// trimListMarker drops a leading bullet or number such as "- ", "* " or
+// "2. ", so reordered or renumbered lists compare equal.
+func trimListMarker(line string) string {
+	for _, marker := range []string{"- ", "* ", "+ ", "• "} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(line[len(marker):])
+		}
+	}
+	digits := 0
+	for digits < len(line) && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
+	if digits > 0 && digits+1 < len(line) && (line[digits] == '.' || line[digits] == ')') && line[digits+1] == ' ' {
+		return strings.TrimSpace(line[digits+2:])
+	}
+	return line
+}
+
+func observedValueKey(value string) string {
+	return strings.ToLower(value)
+}
+
+// hashObservedValues hashes values regardless of their order or case.
+func hashObservedValues(values []string) string {
+	keys := make([]string, 0, len(values))
+	for _, value := range values {
+		keys = append(keys, observedValueKey(value))
+	}
+	sort.Strings(keys)
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// diffObservedValues returns the values only in next and those only in
+// previous, each in its own order.
+func diffObservedValues(previous, next []string) (added, removed []string) {
+	previousKeys := map[string]bool{}
+	for _, value := range previous {
+		previousKeys[observedValueKey(value)] = true
+	}
+	nextKeys := map[string]bool{}
+	for _, value := range next {
+		key := observedValueKey(value)
+		nextKeys[key] = true
+		if !previousKeys[key] {
+			added = append(added, value)
+		}
+	}
+	for _, value := range previous {
+		if !nextKeys[observedValueKey(value)] {
+			removed = append(removed, value)
+		}
+	}
+	return added, removed
+}
+
+func joinChangedValues(values []string) string {
+	shown := values
+	if len(shown) > changesSummaryValues {
+		shown = shown[:changesSummaryValues]
+	}
+	joined := strings.Join(shown, "; ")
+	if extra := len(values) - len(shown); extra > 0 {
+		joined += fmt.Sprintf(" (+%d more)", extra)
+	}
+	return joined
+}
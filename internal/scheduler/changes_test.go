@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestDetectObjectiveChangesReportsOnlyDifferences(t *testing.T) {
+	storeMock := &fakeStore{objectives: map[string]store.Objective{
+		"obj-watch": {
+			ID: "obj-watch", WorkspaceID: "ws-1", ContextID: "ctx-1", Title: "Pricing watch", Prompt: "Check the pricing page",
+			TriggerType: store.ObjectiveTriggerSchedule, CronExpr: "0 * * * *", ReportChangesOnly: true,
+		},
+		"obj-plain": {
+			ID: "obj-plain", WorkspaceID: "ws-1", ContextID: "ctx-1", Title: "Digest", Prompt: "Write a digest",
+			TriggerType: store.ObjectiveTriggerSchedule, CronExpr: "0 * * * *",
+		},
+	}}
+	engineMock := &fakeEngine{}
+	service := New(storeMock, engineMock, 30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	runTask := func(objectiveID string) string {
+		task, err := service.RunNow(ctx, objectiveID)
+		if err != nil {
+			t.Fatalf("run %s: %v", objectiveID, err)
+		}
+		return task.ID
+	}
+
+	firstTask := runTask("obj-watch")
+	if !strings.HasSuffix(engineMock.lastTask.Prompt, objectiveChangesInstruction) {
+		t.Fatalf("expected the change instruction in the prompt, got %q", engineMock.lastTask.Prompt)
+	}
+	changes, tracked, err := service.DetectObjectiveChanges(ctx, firstTask, "- Starter: $10\n- Pro: $30\n")
+	if err != nil || !tracked || !changes.Baseline || changes.Unchanged() {
+		t.Fatalf("expected a baseline, got %+v tracked=%v (%v)", changes, tracked, err)
+	}
+
+	changes, _, err = service.DetectObjectiveChanges(ctx, runTask("obj-watch"), "1. pro: $30\n2. Starter:  $10")
+	if err != nil || !changes.Unchanged() || !strings.HasPrefix(changes.Summary(), "No changes since ") {
+		t.Fatalf("expected reordered, renumbered values unchanged, got %+v (%v)", changes, err)
+	}
+
+	changes, _, err = service.DetectObjectiveChanges(ctx, runTask("obj-watch"), "- Starter: $12\n- Pro: $30\n- Team: $99")
+	if err != nil || changes.Unchanged() {
+		t.Fatalf("expected changes, got %+v (%v)", changes, err)
+	}
+	if strings.Join(changes.Added, "|") != "Starter: $12|Team: $99" || strings.Join(changes.Removed, "|") != "Starter: $10" {
+		t.Fatalf("unexpected diff +%v -%v", changes.Added, changes.Removed)
+	}
+	if summary := changes.Summary(); !strings.Contains(summary, "3 change(s)") || !strings.Contains(summary, "Gone: Starter: $10.") {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+
+	plainTask := runTask("obj-plain")
+	if strings.Contains(engineMock.lastTask.Prompt, objectiveChangesInstruction) {
+		t.Fatalf("expected no change instruction for a plain objective, got %q", engineMock.lastTask.Prompt)
+	}
+	if _, tracked, err := service.DetectObjectiveChanges(ctx, plainTask, "anything"); err != nil || tracked {
+		t.Fatalf("expected a plain objective untracked, got tracked=%v (%v)", tracked, err)
+	}
+	if _, tracked, err := service.DetectObjectiveChanges(ctx, "task-unknown", "anything"); err != nil || tracked {
+		t.Fatalf("expected an unknown task untracked, got tracked=%v (%v)", tracked, err)
+	}
+}
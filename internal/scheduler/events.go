@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	// eventObjectivesLimit bounds the objectives one event runs.
+	eventObjectivesLimit = 50
+	// eventDataPromptMaxBytes bounds the event data added to the prompt.
+	eventDataPromptMaxBytes = 4000
+)
+
+// ErrEventKeyInvalid is returned by HandleEvent for keys other than
+// lowercase dotted names such as deploy.finished.
+var ErrEventKeyInvalid = errors.New("event key must be 1-100 characters of a-z, 0-9, '.', '_', ':' or '-'")
+
+var eventKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:-]{0,99}$`)
+
+// Event is a named occurrence, such as deploy.finished or alert.critical,
+// that runs the active objectives whose event key matches.
+type Event struct {
+	Key string
+	// WorkspaceID limits the event to one workspace; empty reaches the
+	// matching objectives of every workspace.
+	WorkspaceID string
+	// DedupeKey makes repeated deliveries of one event run each objective
+	// once. Without it, identical events within the dedupe window fold.
+	DedupeKey string
+	// Data is shown to the objective's task as JSON.
+	Data map[string]any
+}
+
+// FiredObjective reports what an event did to one matching objective.
+// AlreadyQueued marks a repeated delivery; Error a run that could not be
+// queued.
+type FiredObjective struct {
+	ObjectiveID   string
+	WorkspaceID   string
+	Title         string
+	TaskID        string
+	AlreadyQueued bool
+	Error         string
+}
+
+// HandleEvent queues a run of every active objective listening for the
+// event. Objectives whose run failed are reported, not returned as an
+// error.
+func (s *Service) HandleEvent(ctx context.Context, event Event) ([]FiredObjective, error) {
+	if s.store == nil || s.engine == nil {
+		return nil, fmt.Errorf("scheduler is not configured")
+	}
+	key := strings.ToLower(strings.TrimSpace(event.Key))
+	if !eventKeyPattern.MatchString(key) {
+		return nil, ErrEventKeyInvalid
+	}
+	objectives, err := s.store.ListEventObjectives(ctx, strings.TrimSpace(event.WorkspaceID), key, eventObjectivesLimit)
+	if err != nil {
+		return nil, err
+	}
+	suffix := fmt.Sprintf("\n\nEvent `%s` fired.", key)
+	dataJSON := ""
+	if len(event.Data) > 0 {
+		if encoded, err := json.Marshal(event.Data); err == nil {
+			dataJSON = string(encoded)
+			shown := dataJSON
+			if len(shown) > eventDataPromptMaxBytes {
+				shown = shown[:eventDataPromptMaxBytes] + "..."
+			}
+			suffix = fmt.Sprintf("\n\nEvent `%s` fired with data:\n```json\n%s\n```", key, shown)
+		}
+	}
+	now := time.Now().UTC()
+	dedupeKey := strings.TrimSpace(event.DedupeKey)
+	fired := make([]FiredObjective, 0, len(objectives))
+	for _, objective := range objectives {
+		runKey := objectiveEventRunKey(objective.ID, key+"\n"+dataJSON, now)
+		if dedupeKey != "" {
+			runKey = objectiveDedupedEventRunKey(objective.ID, key, dedupeKey)
+		}
+		fired = append(fired, s.fireEventObjective(ctx, objective, suffix, runKey))
+	}
+	return fired, nil
+}
+
+// fireEventObjective queues one event run of an objective, appending
+// suffix to its prompt, and records the run on the objective.
+func (s *Service) fireEventObjective(ctx context.Context, objective store.Objective, suffix, runKey string) FiredObjective {
+	result := FiredObjective{ObjectiveID: objective.ID, WorkspaceID: objective.WorkspaceID, Title: objective.Title}
+	trigger := string(store.ObjectiveTriggerEvent)
+	startedAt := time.Now().UTC()
+	if s.modelUnavailable() {
+		s.recordRun(ctx, objective, trigger, "", startedAt, store.ObjectiveRunSkipped, modelUnavailableRunError)
+		s.skipObjectiveRun(ctx, objective, time.Time{})
+		result.Error = modelUnavailableRunError
+		return result
+	}
+	prompt := strings.TrimSpace(objective.Prompt)
+	if prompt == "" {
+		s.recordRun(ctx, objective, trigger, "", startedAt, store.ObjectiveRunFailed, ErrObjectivePromptEmpty.Error())
+		s.persistRunResult(ctx, objective, startedAt, time.Time{}, ErrObjectivePromptEmpty.Error(), false)
+		result.Error = ErrObjectivePromptEmpty.Error()
+		return result
+	}
+	task, err := s.enqueueObjectiveTask(ctx, objective, prompt+suffix, runKey)
+	if errors.Is(err, ErrObjectiveRunAlreadyQueued) {
+		s.persistRunResult(ctx, objective, startedAt, time.Time{}, "", true)
+		s.logger.Info("event objective already queued", "objective_id", objective.ID, "workspace_id", objective.WorkspaceID)
+		result.AlreadyQueued = true
+		return result
+	}
+	if err != nil {
+		s.recordRun(ctx, objective, trigger, "", startedAt, store.ObjectiveRunFailed, err.Error())
+		s.persistRunResult(ctx, objective, startedAt, time.Time{}, err.Error(), false)
+		s.logger.Error("event objective enqueue failed", "objective_id", objective.ID, "workspace_id", objective.WorkspaceID, "error", err)
+		result.Error = err.Error()
+		return result
+	}
+	s.recordRun(ctx, objective, trigger, task.ID, startedAt, store.ObjectiveRunQueued, "")
+	s.persistRunResult(ctx, objective, startedAt, time.Time{}, "", true)
+	s.publishObjectiveFired(ctx, objective, trigger, task)
+	s.logger.Info("event objective queued", "objective_id", objective.ID, "task_id", task.ID, "workspace_id", objective.WorkspaceID)
+	result.TaskID = task.ID
+	return result
+}
+
+// objectiveDedupedEventRunKey keys an event run by the sender's dedupe key,
+// so redeliveries are recognised however late they arrive.
+func objectiveDedupedEventRunKey(objectiveID, eventKey, dedupeKey string) string {
+	sum := sha1.Sum([]byte(eventKey + "\n" + dedupeKey))
+	return fmt.Sprintf("objective:%s:event:%s", strings.TrimSpace(objectiveID), hex.EncodeToString(sum[:8]))
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestHandleEventQueuesMatchingObjectivesOncePerDedupeKey(t *testing.T) {
+	storeMock := &fakeStore{
+		eventObjectives: []store.Objective{
+			{ID: "obj-deploy", WorkspaceID: "ws-1", ContextID: "ctx-1", Title: "Post-deploy check", Prompt: "Check the error rate", TriggerType: store.ObjectiveTriggerEvent, EventKey: "deploy.finished"},
+			{ID: "obj-empty", WorkspaceID: "ws-2", Title: "Broken", TriggerType: store.ObjectiveTriggerEvent, EventKey: "deploy.finished"},
+		},
+		runKeys: map[string]bool{},
+	}
+	engineMock := &fakeEngine{}
+	service := New(storeMock, engineMock, 30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	event := Event{Key: " Deploy.Finished ", DedupeKey: "deploy-42", Data: map[string]any{"service": "api", "version": "1.4.2"}}
+
+	fired, err := service.HandleEvent(context.Background(), event)
+	if err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	if storeMock.eventQuery != [2]string{"", "deploy.finished"} {
+		t.Fatalf("unexpected objective query %v", storeMock.eventQuery)
+	}
+	if len(fired) != 2 || fired[0].TaskID == "" || fired[1].Error != ErrObjectivePromptEmpty.Error() {
+		t.Fatalf("unexpected result %+v", fired)
+	}
+	if !strings.Contains(engineMock.lastTask.Prompt, "Event `deploy.finished` fired with data:\n```json\n{\"service\":\"api\",\"version\":\"1.4.2\"}") {
+		t.Fatalf("expected event data in prompt, got %q", engineMock.lastTask.Prompt)
+	}
+
+	fired, err = service.HandleEvent(context.Background(), event)
+	if err != nil || !fired[0].AlreadyQueued || fired[0].TaskID != "" {
+		t.Fatalf("expected a redelivery recognised, got %+v (%v)", fired, err)
+	}
+	event.DedupeKey = "deploy-43"
+	if fired, _ := service.HandleEvent(context.Background(), event); fired[0].TaskID == "" {
+		t.Fatalf("expected a new deploy to run again, got %+v", fired)
+	}
+
+	for _, key := range []string{"", "Deploy finished", strings.Repeat("a", 101)} {
+		if _, err := service.HandleEvent(context.Background(), Event{Key: key}); !errors.Is(err, ErrEventKeyInvalid) {
+			t.Fatalf("expected %q refused, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// objectiveManualTrigger marks runs queued by RunNow.
+const objectiveManualTrigger = "manual"
+
+// PauseNotifier is told when repeated failures auto-pause an objective.
+type PauseNotifier interface {
+	NotifyObjectiveAutoPaused(ctx context.Context, objective store.Objective, reason, lastError string)
+}
+
+// SetPauseNotifier reports auto-paused objectives, e.g. to the admin
+// channels of their workspace.
+func (s *Service) SetPauseNotifier(notifier PauseNotifier) {
+	s.pauses = notifier
+}
+
+// SetAutoPauseAfter sets how many consecutive failed runs pause an
+// objective; zero or less never pauses one.
+func (s *Service) SetAutoPauseAfter(failures int) {
+	s.autoPauseAfter = failures
+}
+
+// recordRun adds a run to the objective's history. History is best effort:
+// a failed write is logged and the run goes ahead.
+func (s *Service) recordRun(ctx context.Context, objective store.Objective, trigger, taskID string, startedAt time.Time, status store.ObjectiveRunStatus, runError string) {
+	if _, err := s.store.CreateObjectiveRun(ctx, store.CreateObjectiveRunInput{
+		ObjectiveID: objective.ID,
+		WorkspaceID: objective.WorkspaceID,
+		TaskID:      taskID,
+		Trigger:     trigger,
+		Status:      status,
+		StartedAt:   startedAt,
+		Error:       runError,
+	}); err != nil {
+		s.logger.Error("record objective run failed", "objective_id", objective.ID, "error", err)
+	}
+}
+
+// FinishObjectiveTask records the outcome of an objective task once it
+// finished. Succeeded and failed schedule and event runs update the
+// objective's statistics, so failed tasks back the schedule off and
+// auto-pause it like runs that could not be queued. Manual and cancelled
+// runs only close their history entry.
+func (s *Service) FinishObjectiveTask(ctx context.Context, taskID string, status store.ObjectiveRunStatus, summary, failure string) {
+	if s.store == nil {
+		return
+	}
+	run, err := s.store.FinishObjectiveRun(ctx, store.FinishObjectiveRunInput{
+		TaskID:     taskID,
+		Status:     status,
+		FinishedAt: time.Now().UTC(),
+		Summary:    summary,
+		Error:      failure,
+	})
+	if errors.Is(err, store.ErrObjectiveRunNotFound) {
+		return
+	}
+	if err != nil {
+		s.logger.Error("finish objective run failed", "task_id", taskID, "error", err)
+		return
+	}
+	if run.Trigger == objectiveManualTrigger || status == store.ObjectiveRunCancelled {
+		return
+	}
+	objective, err := s.store.LookupObjective(ctx, run.ObjectiveID)
+	if err != nil {
+		if !errors.Is(err, store.ErrObjectiveNotFound) {
+			s.logger.Error("lookup objective failed", "objective_id", run.ObjectiveID, "error", err)
+		}
+		return
+	}
+	failure = strings.TrimSpace(failure)
+	if status == store.ObjectiveRunFailed && failure == "" {
+		failure = "objective task failed"
+	}
+	s.persistRunResult(ctx, objective, run.StartedAt, objective.NextRunAt, failure, false)
+}
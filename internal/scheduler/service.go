@@ -25,25 +25,55 @@ const (
 	objectiveAutoPauseAfter    = 5
 )
 
-var errObjectiveRunAlreadyQueued = errors.New("objective run already queued")
+var ErrObjectiveRunAlreadyQueued = errors.New("objective run already queued")
+
+// ErrObjectivePromptEmpty is returned by RunNow for objectives without a prompt.
+var ErrObjectivePromptEmpty = errors.New("objective prompt is empty")
+
+// ErrModelUnavailable is returned by RunNow while the model provider is down.
+var ErrModelUnavailable = errors.New("model provider is unavailable")
+
+// modelUnavailableRunError is recorded on objectives skipped in degraded mode.
+const modelUnavailableRunError = "skipped: model provider unavailable"
 
 type Store interface {
 	ListDueObjectives(ctx context.Context, now time.Time, limit int) ([]store.Objective, error)
 	ListEventObjectives(ctx context.Context, workspaceID, eventKey string, limit int) ([]store.Objective, error)
 	UpdateObjectiveRun(ctx context.Context, input store.UpdateObjectiveRunInput) (store.Objective, error)
 	CreateTask(ctx context.Context, input store.CreateTaskInput) error
+	MarkTaskFailed(ctx context.Context, id string, finishedAt time.Time, message string) error
+	LookupObjective(ctx context.Context, id string) (store.Objective, error)
+	CreateObjectiveRun(ctx context.Context, input store.CreateObjectiveRunInput) (store.ObjectiveRun, error)
+	FinishObjectiveRun(ctx context.Context, input store.FinishObjectiveRunInput) (store.ObjectiveRun, error)
+	LookupObjectiveRunByTask(ctx context.Context, taskID string) (store.ObjectiveRun, error)
+	LookupObjectiveState(ctx context.Context, objectiveID string) (store.ObjectiveState, error)
+	SaveObjectiveState(ctx context.Context, input store.SaveObjectiveStateInput) (store.ObjectiveState, error)
 }
 
 type Engine interface {
 	Enqueue(task orchestrator.Task) (orchestrator.Task, error)
 }
 
+// ModelAvailability reports whether model calls are expected to work.
+type ModelAvailability interface {
+	Available() bool
+}
+
+// EventPublisher is told about every objective run that queued a task.
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, eventType, workspaceID string, data map[string]any)
+}
+
 type Service struct {
-	store        Store
-	engine       Engine
-	logger       *slog.Logger
-	pollInterval time.Duration
-	reporter     heartbeat.Reporter
+	store          Store
+	engine         Engine
+	logger         *slog.Logger
+	pollInterval   time.Duration
+	reporter       heartbeat.Reporter
+	model          ModelAvailability
+	events         EventPublisher
+	pauses         PauseNotifier
+	autoPauseAfter int
 }
 
 func New(store Store, engine Engine, pollInterval time.Duration, logger *slog.Logger) *Service {
@@ -51,10 +81,11 @@ func New(store Store, engine Engine, pollInterval time.Duration, logger *slog.Lo
 		pollInterval = 15 * time.Second
 	}
 	return &Service{
-		store:        store,
-		engine:       engine,
-		logger:       logger,
-		pollInterval: pollInterval,
+		store:          store,
+		engine:         engine,
+		logger:         logger,
+		pollInterval:   pollInterval,
+		autoPauseAfter: objectiveAutoPauseAfter,
 	}
 }
 
@@ -62,6 +93,35 @@ func (s *Service) SetHeartbeatReporter(reporter heartbeat.Reporter) {
 	s.reporter = reporter
 }
 
+// SetModelAvailability makes the scheduler skip objective runs while the
+// model provider is down instead of queueing tasks that would fail.
+func (s *Service) SetModelAvailability(model ModelAvailability) {
+	s.model = model
+}
+
+// SetEventPublisher emits objective.fired whenever an objective queues a
+// run.
+func (s *Service) SetEventPublisher(publisher EventPublisher) {
+	s.events = publisher
+}
+
+func (s *Service) publishObjectiveFired(ctx context.Context, objective store.Objective, trigger string, task orchestrator.Task) {
+	if s.events == nil {
+		return
+	}
+	s.events.PublishEvent(ctx, "objective.fired", objective.WorkspaceID, map[string]any{
+		"objective_id": objective.ID,
+		"title":        objective.Title,
+		"trigger":      trigger,
+		"event_key":    objective.EventKey,
+		"task_id":      task.ID,
+	})
+}
+
+func (s *Service) modelUnavailable() bool {
+	return s.model != nil && !s.model.Available()
+}
+
 func (s *Service) Start(ctx context.Context) error {
 	if s.store == nil || s.engine == nil {
 		if s.reporter != nil {
@@ -119,30 +179,12 @@ func (s *Service) HandleMarkdownUpdate(ctx context.Context, workspaceID, changed
 		return
 	}
 	now := time.Now().UTC()
+	suffix := ""
+	if strings.TrimSpace(changedPath) != "" {
+		suffix = "\n\nChanged markdown file: `" + strings.TrimSpace(changedPath) + "`."
+	}
 	for _, objective := range objectives {
-		startedAt := time.Now().UTC()
-		prompt := strings.TrimSpace(objective.Prompt)
-		if prompt == "" {
-			s.persistRunResult(ctx, objective, startedAt, time.Time{}, "objective prompt is empty", false)
-			continue
-		}
-		if strings.TrimSpace(changedPath) != "" {
-			prompt += "\n\nChanged markdown file: `" + strings.TrimSpace(changedPath) + "`."
-		}
-		runKey := objectiveEventRunKey(objective.ID, changedPath, now)
-		task, taskErr := s.enqueueObjectiveTask(ctx, objective, prompt, runKey)
-		if errors.Is(taskErr, errObjectiveRunAlreadyQueued) {
-			s.persistRunResult(ctx, objective, startedAt, time.Time{}, "", true)
-			s.logger.Info("event objective already queued", "objective_id", objective.ID, "workspace_id", objective.WorkspaceID)
-			continue
-		}
-		if taskErr != nil {
-			s.persistRunResult(ctx, objective, startedAt, time.Time{}, taskErr.Error(), false)
-			s.logger.Error("event objective enqueue failed", "objective_id", objective.ID, "workspace_id", objective.WorkspaceID, "error", taskErr)
-			continue
-		}
-		s.persistRunResult(ctx, objective, startedAt, time.Time{}, "", false)
-		s.logger.Info("event objective queued", "objective_id", objective.ID, "task_id", task.ID, "workspace_id", objective.WorkspaceID)
+		s.fireEventObjective(ctx, objective, suffix, objectiveEventRunKey(objective.ID, changedPath, now))
 	}
 }
 
@@ -161,29 +203,88 @@ func (s *Service) processDue(ctx context.Context) error {
 func (s *Service) runScheduledObjective(ctx context.Context, objective store.Objective, now time.Time) {
 	startedAt := time.Now().UTC()
 	prompt := strings.TrimSpace(objective.Prompt)
+	trigger := string(store.ObjectiveTriggerSchedule)
 	nextRun, nextErr := store.ComputeScheduleNextRunForTimezone(objective.CronExpr, objective.Timezone, now)
 	if nextErr != nil {
+		s.recordRun(ctx, objective, trigger, "", startedAt, store.ObjectiveRunFailed, nextErr.Error())
 		s.persistRunResult(ctx, objective, startedAt, time.Time{}, nextErr.Error(), false)
 		return
 	}
 	if prompt == "" {
-		s.persistRunResult(ctx, objective, startedAt, nextRun, "objective prompt is empty", false)
+		s.recordRun(ctx, objective, trigger, "", startedAt, store.ObjectiveRunFailed, ErrObjectivePromptEmpty.Error())
+		s.persistRunResult(ctx, objective, startedAt, nextRun, ErrObjectivePromptEmpty.Error(), false)
+		return
+	}
+	if s.modelUnavailable() {
+		s.recordRun(ctx, objective, trigger, "", startedAt, store.ObjectiveRunSkipped, modelUnavailableRunError)
+		s.skipObjectiveRun(ctx, objective, nextRun)
 		return
 	}
 	task, err := s.enqueueObjectiveTask(ctx, objective, prompt, objectiveScheduleRunKey(objective.ID, objective.NextRunAt))
-	if errors.Is(err, errObjectiveRunAlreadyQueued) {
+	if errors.Is(err, ErrObjectiveRunAlreadyQueued) {
 		s.persistRunResult(ctx, objective, startedAt, nextRun, "", true)
 		s.logger.Info("scheduled objective already queued", "objective_id", objective.ID, "workspace_id", objective.WorkspaceID)
 		return
 	}
 	if err != nil {
+		s.recordRun(ctx, objective, trigger, "", startedAt, store.ObjectiveRunFailed, err.Error())
 		s.persistRunResult(ctx, objective, startedAt, nextRun, err.Error(), false)
 		return
 	}
-	s.persistRunResult(ctx, objective, startedAt, nextRun, "", false)
+	// The run counts once its task finishes; see FinishObjectiveTask.
+	s.recordRun(ctx, objective, trigger, task.ID, startedAt, store.ObjectiveRunQueued, "")
+	s.persistRunResult(ctx, objective, startedAt, nextRun, "", true)
+	s.publishObjectiveFired(ctx, objective, string(store.ObjectiveTriggerSchedule), task)
 	s.logger.Info("scheduled objective queued", "objective_id", objective.ID, "task_id", task.ID, "workspace_id", objective.WorkspaceID)
 }
 
+// RunNow queues one run of the objective immediately, regardless of its
+// trigger, schedule or active flag. Manual runs leave the schedule and run
+// statistics untouched so testing a monitor does not shift its next run; the
+// result is reported through the usual task completion notifications.
+func (s *Service) RunNow(ctx context.Context, objectiveID string) (orchestrator.Task, error) {
+	if s.store == nil || s.engine == nil {
+		return orchestrator.Task{}, fmt.Errorf("scheduler is not configured")
+	}
+	objective, err := s.store.LookupObjective(ctx, strings.TrimSpace(objectiveID))
+	if err != nil {
+		return orchestrator.Task{}, err
+	}
+	prompt := strings.TrimSpace(objective.Prompt)
+	if prompt == "" {
+		return orchestrator.Task{}, ErrObjectivePromptEmpty
+	}
+	if s.modelUnavailable() {
+		return orchestrator.Task{}, ErrModelUnavailable
+	}
+	startedAt := time.Now().UTC()
+	task, err := s.enqueueObjectiveTask(ctx, objective, prompt, objectiveManualRunKey(objective.ID, startedAt))
+	if err != nil {
+		return orchestrator.Task{}, err
+	}
+	s.recordRun(ctx, objective, objectiveManualTrigger, task.ID, startedAt, store.ObjectiveRunQueued, "")
+	s.publishObjectiveFired(ctx, objective, objectiveManualTrigger, task)
+	s.logger.Info("objective run queued manually", "objective_id", objective.ID, "task_id", task.ID, "workspace_id", objective.WorkspaceID)
+	return task, nil
+}
+
+// skipObjectiveRun moves an objective past a run it could not make because
+// the model was down. Skips are not failures, so they neither count towards
+// auto-pause nor delay the schedule with a backoff.
+func (s *Service) skipObjectiveRun(ctx context.Context, objective store.Objective, nextRunAt time.Time) {
+	now := time.Now().UTC()
+	if _, err := s.store.UpdateObjectiveRun(ctx, store.UpdateObjectiveRunInput{
+		ID:        objective.ID,
+		LastRunAt: now,
+		NextRunAt: nextRunAt,
+		LastError: modelUnavailableRunError,
+		SkipStats: true,
+	}); err != nil {
+		s.logger.Error("update objective run failed", "error", err, "objective_id", objective.ID)
+	}
+	s.logger.Warn("objective run skipped, model provider unavailable", "objective_id", objective.ID, "workspace_id", objective.WorkspaceID)
+}
+
 func (s *Service) persistRunResult(
 	ctx context.Context,
 	objective store.Objective,
@@ -193,7 +294,7 @@ func (s *Service) persistRunResult(
 	skipStats bool,
 ) {
 	lastError = strings.TrimSpace(lastError)
-	activeOverride, reasonOverride, adjustedNextRun := objectiveFailurePolicy(objective, startedAt, nextRunAt, lastError)
+	activeOverride, reasonOverride, adjustedNextRun := objectiveFailurePolicy(objective, startedAt, nextRunAt, lastError, s.autoPauseAfter)
 	_, err := s.store.UpdateObjectiveRun(ctx, store.UpdateObjectiveRunInput{
 		ID:               objective.ID,
 		LastRunAt:        startedAt,
@@ -206,6 +307,13 @@ func (s *Service) persistRunResult(
 	})
 	if err != nil {
 		s.logger.Error("update objective run failed", "error", err, "objective_id", objective.ID)
+		return
+	}
+	if activeOverride != nil && !*activeOverride && objective.Active {
+		s.logger.Warn("objective auto-paused", "objective_id", objective.ID, "workspace_id", objective.WorkspaceID, "reason", *reasonOverride)
+		if s.pauses != nil {
+			s.pauses.NotifyObjectiveAutoPaused(ctx, objective, *reasonOverride, lastError)
+		}
 	}
 }
 
@@ -217,6 +325,9 @@ func (s *Service) enqueueObjectiveTask(ctx context.Context, objective store.Obje
 	if len(title) > 72 {
 		title = title[:72]
 	}
+	if objective.ReportChangesOnly {
+		prompt += objectiveChangesInstruction
+	}
 	task := orchestrator.Task{
 		ID:          "task-" + uuid.NewString(),
 		WorkspaceID: objective.WorkspaceID,
@@ -236,12 +347,19 @@ func (s *Service) enqueueObjectiveTask(ctx context.Context, objective store.Obje
 		Status:      "queued",
 	}); err != nil {
 		if errors.Is(err, store.ErrTaskRunAlreadyExists) {
-			return orchestrator.Task{}, errObjectiveRunAlreadyQueued
+			return orchestrator.Task{}, ErrObjectiveRunAlreadyQueued
 		}
 		return orchestrator.Task{}, fmt.Errorf("persist objective task: %w", err)
 	}
 	queuedTask, err := s.engine.Enqueue(task)
 	if err != nil {
+		if errors.Is(err, store.ErrQuotaExceeded) {
+			// Over quota is not transient; recovery must not retry the run.
+			if markErr := s.store.MarkTaskFailed(ctx, task.ID, time.Now().UTC(), err.Error()); markErr != nil {
+				s.logger.Error("mark over-quota objective task failed", "task_id", task.ID, "error", markErr)
+			}
+			return orchestrator.Task{}, fmt.Errorf("enqueue objective task: %w", err)
+		}
 		// Keep the persisted queued task for startup recovery.
 		return orchestrator.Task{}, fmt.Errorf("enqueue objective task: %w", err)
 	}
@@ -259,6 +377,11 @@ func objectiveScheduleRunKey(objectiveID string, scheduledFor time.Time) string
 	return fmt.Sprintf("objective:%s:%d", id, scheduledFor.UTC().Unix())
 }
 
+// objectiveManualRunKey dedupes repeated run-now requests within one second.
+func objectiveManualRunKey(objectiveID string, requestedAt time.Time) string {
+	return fmt.Sprintf("objective:%s:manual:%d", strings.TrimSpace(objectiveID), requestedAt.UTC().Unix())
+}
+
 func objectiveEventRunKey(objectiveID, changedPath string, eventTime time.Time) string {
 	id := strings.TrimSpace(objectiveID)
 	if id == "" {
@@ -285,13 +408,14 @@ func objectiveFailurePolicy(
 	now time.Time,
 	nextRun time.Time,
 	lastError string,
+	autoPauseAfter int,
 ) (*bool, *string, time.Time) {
 	lastError = strings.TrimSpace(lastError)
 	if lastError == "" {
 		return nil, nil, nextRun
 	}
 	consecutive := objective.ConsecutiveFailures + 1
-	if consecutive >= objectiveAutoPauseAfter {
+	if autoPauseAfter > 0 && consecutive >= autoPauseAfter {
 		active := false
 		reason := fmt.Sprintf("auto-paused after %d consecutive failures", consecutive)
 		return &active, &reason, time.Time{}
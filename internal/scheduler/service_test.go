@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"strings"
@@ -19,6 +20,70 @@ type fakeStore struct {
 	lastTask        store.CreateTaskInput
 	lastRunUpdate   store.UpdateObjectiveRunInput
 	createTaskErr   error
+	objectives      map[string]store.Objective
+	failedTaskID    string
+	// eventQuery records the workspace and key of the last event listing.
+	eventQuery [2]string
+	runKeys    map[string]bool
+	runs       []store.CreateObjectiveRunInput
+	finished   []store.FinishObjectiveRunInput
+	states     map[string]store.ObjectiveState
+}
+
+func (f *fakeStore) CreateObjectiveRun(ctx context.Context, input store.CreateObjectiveRunInput) (store.ObjectiveRun, error) {
+	f.runs = append(f.runs, input)
+	return store.ObjectiveRun{ObjectiveID: input.ObjectiveID, TaskID: input.TaskID, Trigger: input.Trigger, Status: input.Status}, nil
+}
+
+// FinishObjectiveRun finishes the queued run recorded for the task.
+func (f *fakeStore) FinishObjectiveRun(ctx context.Context, input store.FinishObjectiveRunInput) (store.ObjectiveRun, error) {
+	for _, run := range f.runs {
+		if run.TaskID == input.TaskID && run.Status == store.ObjectiveRunQueued {
+			f.finished = append(f.finished, input)
+			return store.ObjectiveRun{ObjectiveID: run.ObjectiveID, TaskID: run.TaskID, Trigger: run.Trigger, Status: input.Status, StartedAt: run.StartedAt}, nil
+		}
+	}
+	return store.ObjectiveRun{}, store.ErrObjectiveRunNotFound
+}
+
+func (f *fakeStore) LookupObjectiveRunByTask(ctx context.Context, taskID string) (store.ObjectiveRun, error) {
+	for _, run := range f.runs {
+		if run.TaskID == taskID {
+			return store.ObjectiveRun{ObjectiveID: run.ObjectiveID, TaskID: run.TaskID, Trigger: run.Trigger, Status: run.Status}, nil
+		}
+	}
+	return store.ObjectiveRun{}, store.ErrObjectiveRunNotFound
+}
+
+func (f *fakeStore) LookupObjectiveState(ctx context.Context, objectiveID string) (store.ObjectiveState, error) {
+	state, ok := f.states[objectiveID]
+	if !ok {
+		return store.ObjectiveState{}, store.ErrObjectiveStateNotFound
+	}
+	return state, nil
+}
+
+func (f *fakeStore) SaveObjectiveState(ctx context.Context, input store.SaveObjectiveStateInput) (store.ObjectiveState, error) {
+	if f.states == nil {
+		f.states = map[string]store.ObjectiveState{}
+	}
+	state := store.ObjectiveState{
+		ObjectiveID: input.ObjectiveID,
+		WorkspaceID: input.WorkspaceID,
+		ContentHash: input.ContentHash,
+		Values:      input.Values,
+		ObservedAt:  input.ObservedAt,
+	}
+	f.states[input.ObjectiveID] = state
+	return state, nil
+}
+
+func (f *fakeStore) LookupObjective(ctx context.Context, id string) (store.Objective, error) {
+	objective, ok := f.objectives[id]
+	if !ok {
+		return store.Objective{}, store.ErrObjectiveNotFound
+	}
+	return objective, nil
 }
 
 func (f *fakeStore) ListDueObjectives(ctx context.Context, now time.Time, limit int) ([]store.Objective, error) {
@@ -26,6 +91,7 @@ func (f *fakeStore) ListDueObjectives(ctx context.Context, now time.Time, limit
 }
 
 func (f *fakeStore) ListEventObjectives(ctx context.Context, workspaceID, eventKey string, limit int) ([]store.Objective, error) {
+	f.eventQuery = [2]string{workspaceID, eventKey}
 	return f.eventObjectives, nil
 }
 
@@ -43,10 +109,21 @@ func (f *fakeStore) CreateTask(ctx context.Context, input store.CreateTaskInput)
 	if f.createTaskErr != nil {
 		return f.createTaskErr
 	}
+	if f.runKeys != nil {
+		if f.runKeys[input.RunKey] {
+			return store.ErrTaskRunAlreadyExists
+		}
+		f.runKeys[input.RunKey] = true
+	}
 	f.lastTask = input
 	return nil
 }
 
+func (f *fakeStore) MarkTaskFailed(ctx context.Context, id string, finishedAt time.Time, message string) error {
+	f.failedTaskID = id
+	return nil
+}
+
 type fakeEngine struct {
 	lastTask   orchestrator.Task
 	enqueueErr error
@@ -94,6 +171,43 @@ func TestProcessDueQueuesObjectiveTask(t *testing.T) {
 	if strings.TrimSpace(storeMock.lastRunUpdate.ID) != "obj-1" {
 		t.Fatalf("expected run update for obj-1, got %s", storeMock.lastRunUpdate.ID)
 	}
+	if !storeMock.lastRunUpdate.SkipStats {
+		t.Fatal("expected the run counted when its task finishes, not when queued")
+	}
+	if len(storeMock.runs) != 1 || storeMock.runs[0].Status != store.ObjectiveRunQueued || storeMock.runs[0].TaskID != storeMock.lastTask.ID || storeMock.runs[0].Trigger != "schedule" {
+		t.Fatalf("expected a queued run in the history, got %+v", storeMock.runs)
+	}
+}
+
+type fakeEventPublisher struct {
+	types []string
+	data  []map[string]any
+}
+
+func (f *fakeEventPublisher) PublishEvent(ctx context.Context, eventType, workspaceID string, data map[string]any) {
+	f.types = append(f.types, eventType)
+	f.data = append(f.data, data)
+}
+
+func TestProcessDuePublishesObjectiveFired(t *testing.T) {
+	storeMock := &fakeStore{
+		dueObjectives: []store.Objective{
+			{ID: "obj-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Title: "Daily Review", Prompt: "Review daily updates", TriggerType: store.ObjectiveTriggerSchedule, CronExpr: "* * * * *"},
+			{ID: "obj-2", WorkspaceID: "ws-1", ContextID: "ctx-1", Title: "Empty", TriggerType: store.ObjectiveTriggerSchedule, CronExpr: "* * * * *"},
+		},
+	}
+	publisher := &fakeEventPublisher{}
+	service := New(storeMock, &fakeEngine{}, 30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetEventPublisher(publisher)
+	if err := service.processDue(context.Background()); err != nil {
+		t.Fatalf("processDue failed: %v", err)
+	}
+	if len(publisher.types) != 1 || publisher.types[0] != "objective.fired" {
+		t.Fatalf("expected one objective.fired event, got %v", publisher.types)
+	}
+	if publisher.data[0]["objective_id"] != "obj-1" || publisher.data[0]["trigger"] != "schedule" || publisher.data[0]["task_id"] == "" {
+		t.Fatalf("unexpected event data %+v", publisher.data[0])
+	}
 }
 
 func TestProcessDueWritesLastErrorOnEnqueueFailure(t *testing.T) {
@@ -120,6 +234,47 @@ func TestProcessDueWritesLastErrorOnEnqueueFailure(t *testing.T) {
 	}
 }
 
+type fakeModelAvailability struct {
+	available bool
+}
+
+func (f fakeModelAvailability) Available() bool {
+	return f.available
+}
+
+func TestProcessDueSkipsObjectivesWhileModelIsUnavailable(t *testing.T) {
+	storeMock := &fakeStore{
+		dueObjectives: []store.Objective{
+			{
+				ID:                  "obj-3",
+				WorkspaceID:         "ws-1",
+				ContextID:           "ctx-1",
+				Prompt:              "Review daily updates",
+				TriggerType:         store.ObjectiveTriggerSchedule,
+				CronExpr:            "0 * * * *",
+				ConsecutiveFailures: objectiveAutoPauseAfter - 1,
+			},
+		},
+		objectives: map[string]store.Objective{"obj-3": {ID: "obj-3", Prompt: "Review daily updates"}},
+	}
+	engineMock := &fakeEngine{}
+	service := New(storeMock, engineMock, 30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetModelAvailability(fakeModelAvailability{available: false})
+	if err := service.processDue(context.Background()); err != nil {
+		t.Fatalf("processDue failed: %v", err)
+	}
+	if engineMock.lastTask.ID != "" || storeMock.lastTask.ID != "" {
+		t.Fatal("expected no task while the model is unavailable")
+	}
+	update := storeMock.lastRunUpdate
+	if update.LastError != modelUnavailableRunError || !update.SkipStats || update.Active != nil || update.NextRunAt.IsZero() {
+		t.Fatalf("expected a skipped run that keeps the schedule, got %+v", update)
+	}
+	if _, err := service.RunNow(context.Background(), "obj-3"); !errors.Is(err, ErrModelUnavailable) {
+		t.Fatalf("expected manual runs to be refused, got %v", err)
+	}
+}
+
 func TestHandleMarkdownUpdateQueuesEventObjectives(t *testing.T) {
 	storeMock := &fakeStore{
 		eventObjectives: []store.Objective{
@@ -230,3 +385,128 @@ func TestProcessDueBacksOffAfterFailure(t *testing.T) {
 		t.Fatalf("expected failure backoff to delay next run, got %s", storeMock.lastRunUpdate.NextRunAt)
 	}
 }
+
+func TestRunNowQueuesObjectiveWithoutTouchingSchedule(t *testing.T) {
+	nextRun := time.Now().UTC().Add(6 * time.Hour)
+	storeMock := &fakeStore{objectives: map[string]store.Objective{
+		"obj-9": {
+			ID:          "obj-9",
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Title:       "Status page monitor",
+			Prompt:      "Check the status page",
+			TriggerType: store.ObjectiveTriggerSchedule,
+			CronExpr:    "0 */6 * * *",
+			NextRunAt:   nextRun,
+			Active:      false,
+		},
+		"obj-empty": {ID: "obj-empty", WorkspaceID: "ws-1", TriggerType: store.ObjectiveTriggerEvent},
+	}}
+	engineMock := &fakeEngine{}
+	service := New(storeMock, engineMock, 30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	task, err := service.RunNow(context.Background(), "obj-9")
+	if err != nil {
+		t.Fatalf("run now: %v", err)
+	}
+	if task.Kind != orchestrator.TaskKindObjective || task.Prompt != "Check the status page" || task.ContextID != "ctx-1" {
+		t.Fatalf("unexpected queued task %+v", task)
+	}
+	if !strings.Contains(storeMock.lastTask.RunKey, "objective:obj-9:manual:") {
+		t.Fatalf("expected manual run key, got %s", storeMock.lastTask.RunKey)
+	}
+	if storeMock.lastRunUpdate.ID != "" {
+		t.Fatalf("expected schedule untouched, got run update %+v", storeMock.lastRunUpdate)
+	}
+
+	if _, err := service.RunNow(context.Background(), "obj-empty"); !errors.Is(err, ErrObjectivePromptEmpty) {
+		t.Fatalf("expected empty prompt error, got %v", err)
+	}
+	if _, err := service.RunNow(context.Background(), "missing"); !errors.Is(err, store.ErrObjectiveNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	storeMock.createTaskErr = store.ErrTaskRunAlreadyExists
+	if _, err := service.RunNow(context.Background(), "obj-9"); !errors.Is(err, ErrObjectiveRunAlreadyQueued) {
+		t.Fatalf("expected already queued, got %v", err)
+	}
+}
+
+func TestProcessDueFailsTaskOverQuota(t *testing.T) {
+	storeMock := &fakeStore{
+		dueObjectives: []store.Objective{
+			{
+				ID:          "obj-quota",
+				WorkspaceID: "ws-1",
+				ContextID:   "ctx-1",
+				TriggerType: store.ObjectiveTriggerSchedule,
+				CronExpr:    "* * * * *",
+				Prompt:      "run",
+				NextRunAt:   time.Now().UTC().Add(-time.Minute),
+			},
+		},
+	}
+	engineMock := &fakeEngine{enqueueErr: fmt.Errorf("over limit: %w", store.ErrQuotaExceeded)}
+	service := New(storeMock, engineMock, 30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := service.processDue(context.Background()); err != nil {
+		t.Fatalf("processDue failed: %v", err)
+	}
+	if storeMock.failedTaskID == "" || storeMock.failedTaskID != storeMock.lastTask.ID {
+		t.Fatalf("expected persisted task marked failed, got %q (task %q)", storeMock.failedTaskID, storeMock.lastTask.ID)
+	}
+	if !strings.Contains(storeMock.lastRunUpdate.LastError, "quota") {
+		t.Fatalf("expected quota error recorded on objective, got %q", storeMock.lastRunUpdate.LastError)
+	}
+}
+
+type fakePauseNotifier struct {
+	paused []string
+}
+
+func (f *fakePauseNotifier) NotifyObjectiveAutoPaused(ctx context.Context, objective store.Objective, reason, lastError string) {
+	f.paused = append(f.paused, objective.ID+": "+lastError)
+}
+
+func TestFinishObjectiveTaskCountsFailuresAndAutoPauses(t *testing.T) {
+	nextRun := time.Now().UTC().Add(time.Hour)
+	storeMock := &fakeStore{
+		objectives: map[string]store.Objective{
+			"obj-1": {ID: "obj-1", WorkspaceID: "ws-1", TriggerType: store.ObjectiveTriggerSchedule, CronExpr: "0 * * * *", NextRunAt: nextRun, Active: true, ConsecutiveFailures: 1},
+			"obj-2": {ID: "obj-2", WorkspaceID: "ws-1", TriggerType: store.ObjectiveTriggerEvent, Active: true, ConsecutiveFailures: 2},
+		},
+		runs: []store.CreateObjectiveRunInput{
+			{ObjectiveID: "obj-1", TaskID: "task-1", Trigger: "schedule", Status: store.ObjectiveRunQueued},
+			{ObjectiveID: "obj-2", TaskID: "task-2", Trigger: "event", Status: store.ObjectiveRunQueued},
+			{ObjectiveID: "obj-1", TaskID: "task-3", Trigger: "manual", Status: store.ObjectiveRunQueued},
+		},
+	}
+	notifier := &fakePauseNotifier{}
+	service := New(storeMock, &fakeEngine{}, 30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetPauseNotifier(notifier)
+	service.SetAutoPauseAfter(3)
+	ctx := context.Background()
+
+	service.FinishObjectiveTask(ctx, "task-1", store.ObjectiveRunFailed, "", "tool timed out")
+	update := storeMock.lastRunUpdate
+	if update.ID != "obj-1" || update.SkipStats || update.LastError != "tool timed out" || update.Active != nil || !update.NextRunAt.Equal(nextRun) {
+		t.Fatalf("expected a counted failure that keeps the later schedule, got %+v", update)
+	}
+
+	service.FinishObjectiveTask(ctx, "task-2", store.ObjectiveRunFailed, "", "")
+	update = storeMock.lastRunUpdate
+	if update.ID != "obj-2" || update.Active == nil || *update.Active || update.AutoPausedReason == nil {
+		t.Fatalf("expected the third failure to auto-pause, got %+v", update)
+	}
+	if len(notifier.paused) != 1 || notifier.paused[0] != "obj-2: objective task failed" {
+		t.Fatalf("expected one auto-pause notice, got %v", notifier.paused)
+	}
+
+	storeMock.lastRunUpdate = store.UpdateObjectiveRunInput{}
+	service.FinishObjectiveTask(ctx, "task-3", store.ObjectiveRunFailed, "", "boom")
+	service.FinishObjectiveTask(ctx, "task-unknown", store.ObjectiveRunSucceeded, "done", "")
+	if storeMock.lastRunUpdate.ID != "" {
+		t.Fatalf("expected manual and unknown runs to leave the objective alone, got %+v", storeMock.lastRunUpdate)
+	}
+	if len(storeMock.finished) != 3 || storeMock.finished[2].Error != "boom" {
+		t.Fatalf("expected three finished runs, got %+v", storeMock.finished)
+	}
+}
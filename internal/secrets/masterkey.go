@@ -0,0 +1,170 @@
+package secrets
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrWeakMasterKey is returned for key material that is neither 32 key
+// bytes nor an age identity, such as a passphrase.
+var ErrWeakMasterKey = errors.New("secrets master key must be 32 random bytes (raw, base64 or hex) or an age identity; generate one with `openssl rand -base64 32` or `age-keygen`")
+
+const (
+	ageIdentityPrefix = "AGE-SECRET-KEY-"
+	// masterKeyInfo binds keys derived from an age identity to this use, so
+	// the identity's own key never encrypts secrets directly.
+	masterKeyInfo = "agent-runtime secrets master key"
+)
+
+// LoadMasterKey resolves the master key from an inline value or a key file;
+// the inline value wins when both are set. The material must be 32 random
+// bytes, base64 or hex encoded (or raw in the key file), or an age X25519
+// identity such as one written by age-keygen, from which the key is derived
+// with HKDF-SHA256. Passphrases are refused rather than stretched.
+func LoadMasterKey(inline, keyFile string) ([]byte, error) {
+	var raw []byte
+	material := strings.TrimSpace(inline)
+	if material == "" && strings.TrimSpace(keyFile) != "" {
+		content, err := os.ReadFile(strings.TrimSpace(keyFile))
+		if err != nil {
+			return nil, fmt.Errorf("read secrets key file: %w", err)
+		}
+		raw = content
+		material = strings.TrimSpace(string(content))
+	}
+	if material == "" && len(raw) == 0 {
+		return nil, ErrNoMasterKey
+	}
+	if identity, ok := ageIdentity(material); ok {
+		scalar, err := decodeAgeIdentity(identity)
+		if err != nil {
+			return nil, err
+		}
+		return hkdf.Key(sha256.New, scalar, nil, masterKeyInfo, 32)
+	}
+	for _, decode := range []func(string) ([]byte, error){
+		base64.StdEncoding.DecodeString,
+		base64.RawStdEncoding.DecodeString,
+		base64.URLEncoding.DecodeString,
+		base64.RawURLEncoding.DecodeString,
+		hex.DecodeString,
+	} {
+		if key, err := decode(material); err == nil && len(key) == 32 {
+			return key, nil
+		}
+	}
+	// A raw key file holds the bytes themselves; 32 printable characters are
+	// a passphrase, not random bytes.
+	if len(raw) == 32 && !printable(raw) {
+		return raw, nil
+	}
+	return nil, ErrWeakMasterKey
+}
+
+// ageIdentity finds the identity line of an age identity file, skipping
+// the comments age-keygen writes around it.
+func ageIdentity(material string) (string, bool) {
+	for _, line := range strings.Split(material, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(strings.ToUpper(line), ageIdentityPrefix) {
+			return line, true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// decodeAgeIdentity returns the 32-byte X25519 scalar of an age identity,
+// a bech32 string with the AGE-SECRET-KEY- prefix.
+func decodeAgeIdentity(identity string) ([]byte, error) {
+	invalid := errors.New("invalid age identity in secrets master key")
+	lower := strings.ToLower(identity)
+	separator := strings.LastIndexByte(lower, '1')
+	if separator < 1 || len(lower)-separator < 7 || lower[:separator] != strings.ToLower(ageIdentityPrefix) {
+		return nil, invalid
+	}
+	hrp := lower[:separator]
+	values := make([]byte, 0, len(lower)-separator-1)
+	for _, char := range lower[separator+1:] {
+		index := strings.IndexRune(bech32Charset, char)
+		if index < 0 {
+			return nil, invalid
+		}
+		values = append(values, byte(index))
+	}
+	if bech32Polymod(append(bech32ExpandHRP(hrp), values...)) != 1 {
+		return nil, invalid
+	}
+	scalar, ok := convertBits(values[:len(values)-6])
+	if !ok || len(scalar) != 32 {
+		return nil, invalid
+	}
+	return scalar, nil
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for index := 0; index < len(hrp); index++ {
+		expanded = append(expanded, hrp[index]>>5)
+	}
+	expanded = append(expanded, 0)
+	for index := 0; index < len(hrp); index++ {
+		expanded = append(expanded, hrp[index]&31)
+	}
+	return expanded
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	checksum := uint32(1)
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+		for bit := 0; bit < 5; bit++ {
+			if (top>>bit)&1 == 1 {
+				checksum ^= generator[bit]
+			}
+		}
+	}
+	return checksum
+}
+
+// convertBits regroups 5-bit bech32 values into bytes, refusing non-zero
+// padding.
+func convertBits(values []byte) ([]byte, bool) {
+	result := make([]byte, 0, len(values)*5/8)
+	accumulator, bits := uint32(0), 0
+	for _, value := range values {
+		accumulator = accumulator<<5 | uint32(value)
+		bits += 5
+		for bits >= 8 {
+			bits -= 8
+			result = append(result, byte(accumulator>>bits))
+		}
+	}
+	if bits >= 5 || accumulator&(1<<bits-1) != 0 {
+		return nil, false
+	}
+	return result, true
+}
+
+// printable reports whether content is all printable ASCII.
+func printable(content []byte) bool {
+	for _, char := range content {
+		if (char < 0x20 || char > 0x7e) && char != '\t' && char != '\n' && char != '\r' {
+			return false
+		}
+	}
+	return true
+}
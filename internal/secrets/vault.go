@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const ciphertextPrefix = "v1:"
+
+var (
+	ErrNoMasterKey = errors.New("secrets master key is not configured")
+	ErrDecrypt     = errors.New("secret could not be decrypted")
+)
+
+// Store is the persistence surface the vault needs; values are stored encrypted.
+type Store interface {
+	PutSecret(ctx context.Context, name, ciphertext string) (store.SecretRecord, error)
+	LookupSecret(ctx context.Context, name string) (store.SecretRecord, error)
+	ListSecrets(ctx context.Context) ([]store.SecretRecord, error)
+	DeleteSecret(ctx context.Context, name string) error
+}
+
+// Vault encrypts secret values with AES-256-GCM before they reach the store.
+type Vault struct {
+	store Store
+	aead  cipher.AEAD
+}
+
+func New(secretStore Store, masterKey []byte) (*Vault, error) {
+	if secretStore == nil {
+		return nil, fmt.Errorf("secrets store is required")
+	}
+	if len(masterKey) != 32 {
+		return nil, fmt.Errorf("secrets master key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("init aes cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("init gcm: %w", err)
+	}
+	return &Vault{store: secretStore, aead: aead}, nil
+}
+
+func (v *Vault) Set(ctx context.Context, name, value string) error {
+	name = normalizeName(name)
+	if name == "" {
+		return store.ErrSecretInvalid
+	}
+	ciphertext, err := v.encrypt(name, value)
+	if err != nil {
+		return err
+	}
+	_, err = v.store.PutSecret(ctx, name, ciphertext)
+	return err
+}
+
+func (v *Vault) Get(ctx context.Context, name string) (string, error) {
+	record, err := v.store.LookupSecret(ctx, normalizeName(name))
+	if err != nil {
+		return "", err
+	}
+	return v.decrypt(record.Name, record.Ciphertext)
+}
+
+// Names lists stored secret names without decrypting any values.
+func (v *Vault) Names(ctx context.Context) ([]string, error) {
+	records, err := v.store.ListSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.Name)
+	}
+	return names, nil
+}
+
+func (v *Vault) Delete(ctx context.Context, name string) error {
+	return v.store.DeleteSecret(ctx, normalizeName(name))
+}
+
+func (v *Vault) encrypt(name, value string) (string, error) {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	// The secret name is bound as additional data so rows cannot be swapped.
+	sealed := v.aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return ciphertextPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (v *Vault) decrypt(name, ciphertext string) (string, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(ciphertext), ciphertextPrefix)
+	if !ok {
+		return "", fmt.Errorf("%w: unknown ciphertext version", ErrDecrypt)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	nonceSize := v.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("%w: ciphertext too short", ErrDecrypt)
+	}
+	plaintext, err := v.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("%w: wrong master key or corrupted value", ErrDecrypt)
+	}
+	return string(plaintext), nil
+}
+
+func normalizeName(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
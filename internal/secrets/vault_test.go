@@ -0,0 +1,155 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func testMasterKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, 32))
+}
+
+func newTestVault(t *testing.T, key string) (*Vault, *store.Store) {
+	t.Helper()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "secrets_test.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(context.Background()); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	masterKey, err := LoadMasterKey(key, "")
+	if err != nil {
+		t.Fatalf("load master key: %v", err)
+	}
+	vault, err := New(sqlStore, masterKey)
+	if err != nil {
+		t.Fatalf("new vault: %v", err)
+	}
+	return vault, sqlStore
+}
+
+func TestVaultRoundTripEncryptsAtRest(t *testing.T) {
+	vault, sqlStore := newTestVault(t, testMasterKey(0x01))
+	ctx := context.Background()
+
+	if err := vault.Set(ctx, "agent_runtime_llm_api_key", "sk-live-123"); err != nil {
+		t.Fatalf("set secret: %v", err)
+	}
+	record, err := sqlStore.LookupSecret(ctx, "AGENT_RUNTIME_LLM_API_KEY")
+	if err != nil {
+		t.Fatalf("lookup raw secret: %v", err)
+	}
+	if strings.Contains(record.Ciphertext, "sk-live-123") || !strings.HasPrefix(record.Ciphertext, "v1:") {
+		t.Fatalf("expected versioned ciphertext without plaintext, got %q", record.Ciphertext)
+	}
+	value, err := vault.Get(ctx, "AGENT_RUNTIME_LLM_API_KEY")
+	if err != nil {
+		t.Fatalf("get secret: %v", err)
+	}
+	if value != "sk-live-123" {
+		t.Fatalf("unexpected secret value %q", value)
+	}
+	names, err := vault.Names(ctx)
+	if err != nil {
+		t.Fatalf("list names: %v", err)
+	}
+	if len(names) != 1 || names[0] != "AGENT_RUNTIME_LLM_API_KEY" {
+		t.Fatalf("unexpected names %v", names)
+	}
+}
+
+func TestVaultRejectsWrongKeyAndSwappedRows(t *testing.T) {
+	vault, sqlStore := newTestVault(t, testMasterKey(0x01))
+	ctx := context.Background()
+	if err := vault.Set(ctx, "A", "alpha"); err != nil {
+		t.Fatalf("set secret: %v", err)
+	}
+
+	otherKey, _ := LoadMasterKey(testMasterKey(0x02), "")
+	other, err := New(sqlStore, otherKey)
+	if err != nil {
+		t.Fatalf("new vault: %v", err)
+	}
+	if _, err := other.Get(ctx, "A"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected ErrDecrypt with wrong key, got %v", err)
+	}
+
+	record, err := sqlStore.LookupSecret(ctx, "A")
+	if err != nil {
+		t.Fatalf("lookup raw secret: %v", err)
+	}
+	if _, err := sqlStore.PutSecret(ctx, "B", record.Ciphertext); err != nil {
+		t.Fatalf("copy ciphertext: %v", err)
+	}
+	if _, err := vault.Get(ctx, "B"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected ErrDecrypt for swapped row, got %v", err)
+	}
+}
+
+func TestLoadMasterKey(t *testing.T) {
+	if _, err := LoadMasterKey("", ""); !errors.Is(err, ErrNoMasterKey) {
+		t.Fatalf("expected ErrNoMasterKey, got %v", err)
+	}
+	key := bytes.Repeat([]byte{0x9c}, 32)
+	dir := t.TempDir()
+	encodedFile := filepath.Join(dir, "master.key")
+	if err := os.WriteFile(encodedFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+	rawFile := filepath.Join(dir, "master.bin")
+	if err := os.WriteFile(rawFile, key, 0o600); err != nil {
+		t.Fatalf("write raw key file: %v", err)
+	}
+	for name, load := range map[string]func() ([]byte, error){
+		"base64 file": func() ([]byte, error) { return LoadMasterKey("", encodedFile) },
+		"raw file":    func() ([]byte, error) { return LoadMasterKey("", rawFile) },
+		"inline hex":  func() ([]byte, error) { return LoadMasterKey(hex.EncodeToString(key), "") },
+	} {
+		loaded, err := load()
+		if err != nil || !bytes.Equal(loaded, key) {
+			t.Fatalf("expected %s to load the key, got %x, %v", name, loaded, err)
+		}
+	}
+
+	for _, weak := range []string{"correct horse battery staple", "file-key", base64.StdEncoding.EncodeToString(key[:16]), "0123456789abcdefghijklmnopqrstuv"} {
+		if _, err := LoadMasterKey(weak, ""); !errors.Is(err, ErrWeakMasterKey) {
+			t.Fatalf("expected %q to be refused, got %v", weak, err)
+		}
+	}
+	if _, err := LoadMasterKey("", filepath.Join(t.TempDir(), "missing.key")); err == nil {
+		t.Fatal("expected error for missing key file")
+	}
+}
+
+// testAgeIdentity encodes the scalar 0x01..0x20.
+const testAgeIdentity = "AGE-SECRET-KEY-1QYPQXPQ9QCRSSZG2PVXQ6RS0ZQG3YYC5Z5TPWXQERGD3C8G7RUSQGPQYEE"
+
+func TestLoadMasterKeyDerivesFromAgeIdentity(t *testing.T) {
+	identityFile := filepath.Join(t.TempDir(), "key.txt")
+	content := "# created: 2026-10-17T09:00:00Z\n# public key: age1example\n" + testAgeIdentity + "\n"
+	if err := os.WriteFile(identityFile, []byte(content), 0o600); err != nil {
+		t.Fatalf("write identity file: %v", err)
+	}
+	key, err := LoadMasterKey("", identityFile)
+	if err != nil {
+		t.Fatalf("load age identity: %v", err)
+	}
+	if hex.EncodeToString(key) != "efbc6bf73596c2badbbd3f31cb778fc0f934a2c7dd4e9b94f3b8c3d5ab9f4940" {
+		t.Fatalf("unexpected derived key %x", key)
+	}
+	corrupted := testAgeIdentity[:len(testAgeIdentity)-1] + "Q"
+	if _, err := LoadMasterKey(corrupted, ""); err == nil || errors.Is(err, ErrWeakMasterKey) {
+		t.Fatalf("expected a bad checksum to be reported, got %v", err)
+	}
+}
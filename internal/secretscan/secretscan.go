@@ -0,0 +1,122 @@
+// Package secretscan finds credentials (API keys, access tokens, private
+// keys, passwords in assignments) in text the agent is about to see or write
+// to the workspace, and masks them in place.
+package secretscan
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Mode decides what happens to text that contains credentials.
+type Mode string
+
+const (
+	// ModeMask replaces each credential with a marker and lets the text
+	// through.
+	ModeMask Mode = "mask"
+	// ModeBlock refuses file writes and withholds tool output that contains
+	// credentials.
+	ModeBlock Mode = "block"
+	// ModeOff disables scanning.
+	ModeOff Mode = "off"
+)
+
+// ParseMode reads a mode setting; an empty value is ModeMask.
+func ParseMode(value string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ModeMask:
+		return ModeMask, nil
+	case ModeBlock:
+		return ModeBlock, nil
+	case ModeOff:
+		return ModeOff, nil
+	}
+	return "", fmt.Errorf("unknown secret scan mode %q (use mask, block or off)", value)
+}
+
+// detectors name a credential kind each. A detector with a "value" group
+// masks only that group, so the key name of an assignment stays readable.
+var detectors = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"private_key", regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----[\s\S]*?(?:-----END (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----|$)`)},
+	{"aws_access_key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github_token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"gitlab_token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{"slack_token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"slack_webhook", regexp.MustCompile(`https://hooks\.slack\.com/services/[A-Za-z0-9/_-]{20,}`)},
+	{"stripe_key", regexp.MustCompile(`\b(?:sk|rk)_live_[A-Za-z0-9]{16,}`)},
+	{"model_api_key", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	{"url_credentials", regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+:(?P<value>[^\s@/]{3,})@`)},
+	{"password_assignment", regexp.MustCompile(`(?i)(?:password|passwd|pwd|secret|client_secret|api[_-]?key|access[_-]?token|auth[_-]?token|private[_-]?key)["']?\s*[:=]\s*["']?(?P<value>[^\s"'\[\],;]{8,})`)},
+}
+
+// Marker is the text that replaces a masked credential.
+func Marker(kind string) string {
+	return fmt.Sprintf("[secret removed: %s]", kind)
+}
+
+// Mask replaces every credential in text with its marker and returns the
+// sorted kinds it found.
+func Mask(text string) (string, []string) {
+	if strings.TrimSpace(text) == "" {
+		return text, nil
+	}
+	found := map[string]bool{}
+	for _, detector := range detectors {
+		text = maskMatches(text, detector.kind, detector.pattern, found)
+	}
+	if len(found) == 0 {
+		return text, nil
+	}
+	kinds := make([]string, 0, len(found))
+	for kind := range found {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return text, kinds
+}
+
+func maskMatches(text, kind string, pattern *regexp.Regexp, found map[string]bool) string {
+	matches := pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	valueGroup := pattern.SubexpIndex("value")
+	builder := strings.Builder{}
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if valueGroup > 0 && match[2*valueGroup] >= 0 {
+			start, end = match[2*valueGroup], match[2*valueGroup+1]
+			if placeholder(text[start:end]) {
+				continue
+			}
+		}
+		builder.WriteString(text[last:start])
+		builder.WriteString(Marker(kind))
+		last = end
+		found[kind] = true
+	}
+	builder.WriteString(text[last:])
+	return builder.String()
+}
+
+// placeholder accepts assignment values that only point at a secret, such
+// as environment references, templates and redaction stars.
+func placeholder(value string) bool {
+	switch {
+	case strings.HasPrefix(value, "$"), strings.HasPrefix(value, "<"), strings.HasPrefix(value, "{"), strings.HasPrefix(value, "%"):
+		return true
+	case strings.Trim(value, "*xX.") == "":
+		return true
+	}
+	lower := strings.ToLower(value)
+	return strings.Contains(lower, "changeme") || strings.Contains(lower, "example") || strings.Contains(lower, "placeholder")
+}
@@ -0,0 +1,368 @@
+// Package skillreview periodically flags workspace skill templates that
+// are no longer retrieved or that newer knowledge may have superseded, and
+// opens a review task for each so long-term memory stays current.
+package skillreview
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	reviewContextID      = "system:skill-review"
+	maxTasksPerWorkspace = 5
+	maxKnowledgeDocs     = 500
+	maxDocBytes          = 16 * 1024
+	minSharedTerms       = 8
+	minOverlap           = 0.3
+)
+
+// Directories that hold generated output or scratch files rather than
+// curated knowledge.
+var skipKnowledgeDirs = map[string]struct{}{
+	"skills":  {},
+	"scratch": {},
+	"tasks":   {},
+	"logs":    {},
+	".git":    {},
+}
+
+type Store interface {
+	EnsureSkillsTracked(ctx context.Context, workspaceID string, paths []string) error
+	ListSkillUsage(ctx context.Context, workspaceID string) ([]store.SkillUsage, error)
+	MarkSkillReviewed(ctx context.Context, workspaceID, path, taskID string) error
+	ListWorkspaceAdminDeliveries(ctx context.Context, workspaceID string, limit int) ([]store.ContextDelivery, error)
+	CreateTask(ctx context.Context, input store.CreateTaskInput) error
+}
+
+type Engine interface {
+	Enqueue(task orchestrator.Task) (orchestrator.Task, error)
+}
+
+type Config struct {
+	WorkspaceRoot string
+	StaleAfter    time.Duration
+	Interval      time.Duration
+}
+
+type Reviewer struct {
+	root       string
+	staleAfter time.Duration
+	interval   time.Duration
+	store      Store
+	engine     Engine
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// Finding explains why a skill needs review.
+type Finding struct {
+	WorkspaceID string
+	Path        string
+	Reasons     []string
+	Related     []string
+}
+
+func New(cfg Config, storeRef Store, engine Engine, logger *slog.Logger) *Reviewer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	staleAfter := cfg.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = 30 * 24 * time.Hour
+	}
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	return &Reviewer{
+		root:       filepath.Clean(strings.TrimSpace(cfg.WorkspaceRoot)),
+		staleAfter: staleAfter,
+		interval:   interval,
+		store:      storeRef,
+		engine:     engine,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (r *Reviewer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			opened, err := r.RunOnce(ctx)
+			if err != nil {
+				r.logger.Error("skill review failed", "error", err)
+				continue
+			}
+			if opened > 0 {
+				r.logger.Info("skill review opened tasks", "count", opened)
+			}
+		}
+	}
+}
+
+// RunOnce reviews every workspace and returns the number of tasks opened.
+func (r *Reviewer) RunOnce(ctx context.Context) (int, error) {
+	entries, err := os.ReadDir(r.root)
+	if err != nil {
+		return 0, fmt.Errorf("list workspaces: %w", err)
+	}
+	opened := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		findings, err := r.Review(ctx, entry.Name())
+		if err != nil {
+			r.logger.Error("skill review failed for workspace", "workspace_id", entry.Name(), "error", err)
+			continue
+		}
+		for _, finding := range findings {
+			if err := r.openTask(ctx, finding); err != nil {
+				r.logger.Error("failed to open skill review task", "workspace_id", finding.WorkspaceID, "skill", finding.Path, "error", err)
+				continue
+			}
+			opened++
+		}
+	}
+	return opened, nil
+}
+
+// Review returns the skills in one workspace that are due for review.
+func (r *Reviewer) Review(ctx context.Context, workspaceID string) ([]Finding, error) {
+	workspaceDir := filepath.Join(r.root, workspaceID)
+	skills, err := listMarkdown(filepath.Join(workspaceDir, "skills"), workspaceDir, nil)
+	if err != nil || len(skills) == 0 {
+		return nil, err
+	}
+	paths := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		paths = append(paths, skill.rel)
+	}
+	if err := r.store.EnsureSkillsTracked(ctx, workspaceID, paths); err != nil {
+		return nil, err
+	}
+	usageList, err := r.store.ListSkillUsage(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	usage := map[string]store.SkillUsage{}
+	for _, item := range usageList {
+		usage[item.Path] = item
+	}
+
+	var knowledge []markdownFile
+	knowledgeLoaded := false
+	now := r.now().UTC()
+	findings := []Finding{}
+	for _, skill := range skills {
+		if len(findings) >= maxTasksPerWorkspace {
+			break
+		}
+		record := usage[skill.rel]
+		if !record.LastReviewAt.IsZero() && now.Sub(record.LastReviewAt) < r.staleAfter {
+			continue
+		}
+		finding := Finding{WorkspaceID: workspaceID, Path: skill.rel}
+
+		lastActive := latest(record.LastUsedAt, record.FirstSeenAt, skill.modTime)
+		if now.Sub(lastActive) >= r.staleAfter {
+			days := int(now.Sub(lastActive).Hours() / 24)
+			if record.LastUsedAt.IsZero() {
+				finding.Reasons = append(finding.Reasons, fmt.Sprintf("never retrieved into a prompt in the %d days it has been tracked", days))
+			} else {
+				finding.Reasons = append(finding.Reasons, fmt.Sprintf("not retrieved into a prompt for %d days", days))
+			}
+		}
+
+		if !knowledgeLoaded {
+			knowledge, err = listMarkdown(workspaceDir, workspaceDir, skipKnowledgeDirs)
+			if err != nil {
+				return nil, err
+			}
+			knowledgeLoaded = true
+		}
+		for _, doc := range newerOverlapping(skill, knowledge) {
+			finding.Related = append(finding.Related, doc.rel)
+			finding.Reasons = append(finding.Reasons, fmt.Sprintf("may conflict with `%s`, which covers the same topic and was updated %s", doc.rel, doc.modTime.Format("2006-01-02")))
+		}
+		if len(finding.Reasons) > 0 {
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+func (r *Reviewer) openTask(ctx context.Context, finding Finding) error {
+	contextID := reviewContextID
+	if admins, err := r.store.ListWorkspaceAdminDeliveries(ctx, finding.WorkspaceID, 1); err == nil && len(admins) > 0 {
+		contextID = admins[0].ContextID
+	}
+	task, err := r.engine.Enqueue(orchestrator.Task{
+		WorkspaceID: finding.WorkspaceID,
+		ContextID:   contextID,
+		Kind:        orchestrator.TaskKindGeneral,
+		Title:       "Review skill " + finding.Path,
+		Prompt:      buildPrompt(finding),
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.store.CreateTask(ctx, store.CreateTaskInput{
+		ID:           task.ID,
+		WorkspaceID:  task.WorkspaceID,
+		ContextID:    task.ContextID,
+		Kind:         string(task.Kind),
+		Title:        task.Title,
+		Prompt:       task.Prompt,
+		Status:       "queued",
+		RouteClass:   "task",
+		Priority:     "p3",
+		AssignedLane: "knowledge",
+	}); err != nil {
+		return err
+	}
+	return r.store.MarkSkillReviewed(ctx, finding.WorkspaceID, finding.Path, task.ID)
+}
+
+func buildPrompt(finding Finding) string {
+	lines := []string{
+		fmt.Sprintf("The skill template `%s` was flagged by the periodic skill review:", finding.Path),
+	}
+	for _, reason := range finding.Reasons {
+		lines = append(lines, "- "+reason)
+	}
+	lines = append(lines,
+		"",
+		"Read the skill and any documents listed above. Decide whether it is still accurate:",
+		"- if it is outdated or contradicts newer knowledge, propose the corrected text or recommend removing it;",
+		"- if it is still correct, say so.",
+		"Summarize the decision for the workspace admins. Do not delete the skill yourself.",
+	)
+	return strings.Join(lines, "\n")
+}
+
+type markdownFile struct {
+	rel     string
+	path    string
+	modTime time.Time
+	terms   map[string]struct{}
+}
+
+func listMarkdown(dir, workspaceDir string, skipDirs map[string]struct{}) ([]markdownFile, error) {
+	files := []markdownFile{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if _, skip := skipDirs[entry.Name()]; skip && path != dir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if strings.ToLower(filepath.Ext(path)) != ".md" || len(files) >= maxKnowledgeDocs {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(workspaceDir, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, markdownFile{rel: filepath.ToSlash(rel), path: path, modTime: info.ModTime().UTC()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].rel < files[j].rel })
+	return files, nil
+}
+
+// newerOverlapping finds knowledge documents updated after the skill that
+// share enough vocabulary to be about the same procedure.
+func newerOverlapping(skill markdownFile, knowledge []markdownFile) []markdownFile {
+	skillTerms := loadTerms(&skill)
+	if len(skillTerms) < minSharedTerms {
+		return nil
+	}
+	matches := []markdownFile{}
+	for index := range knowledge {
+		doc := &knowledge[index]
+		if !doc.modTime.After(skill.modTime) {
+			continue
+		}
+		docTerms := loadTerms(doc)
+		shared := 0
+		for term := range skillTerms {
+			if _, ok := docTerms[term]; ok {
+				shared++
+			}
+		}
+		if shared >= minSharedTerms && float64(shared)/float64(len(skillTerms)) >= minOverlap {
+			matches = append(matches, *doc)
+		}
+		if len(matches) >= 3 {
+			break
+		}
+	}
+	return matches
+}
+
+func loadTerms(file *markdownFile) map[string]struct{} {
+	if file.terms != nil {
+		return file.terms
+	}
+	file.terms = map[string]struct{}{}
+	handle, err := os.Open(file.path)
+	if err != nil {
+		return file.terms
+	}
+	defer handle.Close()
+	content, err := io.ReadAll(io.LimitReader(handle, maxDocBytes))
+	if err != nil {
+		return file.terms
+	}
+	fields := strings.FieldsFunc(strings.ToLower(string(content)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, field := range fields {
+		if len(field) >= 4 {
+			file.terms[field] = struct{}{}
+		}
+	}
+	return file.terms
+}
+
+func latest(values ...time.Time) time.Time {
+	var result time.Time
+	for _, value := range values {
+		if value.After(result) {
+			result = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package skillreview
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/orchestrator"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+type fakeEngine struct {
+	tasks []orchestrator.Task
+}
+
+func (f *fakeEngine) Enqueue(task orchestrator.Task) (orchestrator.Task, error) {
+	task.ID = fmt.Sprintf("task-%d", len(f.tasks)+1)
+	f.tasks = append(f.tasks, task)
+	return task, nil
+}
+
+type fakeStore struct {
+	now     func() time.Time
+	usage   map[string]store.SkillUsage
+	admins  []store.ContextDelivery
+	created []store.CreateTaskInput
+}
+
+func (f *fakeStore) EnsureSkillsTracked(ctx context.Context, workspaceID string, paths []string) error {
+	for _, path := range paths {
+		if _, ok := f.usage[path]; !ok {
+			f.usage[path] = store.SkillUsage{WorkspaceID: workspaceID, Path: path, FirstSeenAt: f.now()}
+		}
+	}
+	return nil
+}
+
+func (f *fakeStore) ListSkillUsage(ctx context.Context, workspaceID string) ([]store.SkillUsage, error) {
+	items := []store.SkillUsage{}
+	for _, item := range f.usage {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (f *fakeStore) MarkSkillReviewed(ctx context.Context, workspaceID, path, taskID string) error {
+	item := f.usage[path]
+	item.LastReviewAt = f.now()
+	item.ReviewTaskID = taskID
+	f.usage[path] = item
+	return nil
+}
+
+func (f *fakeStore) ListWorkspaceAdminDeliveries(ctx context.Context, workspaceID string, limit int) ([]store.ContextDelivery, error) {
+	return f.admins, nil
+}
+
+func (f *fakeStore) CreateTask(ctx context.Context, input store.CreateTaskInput) error {
+	f.created = append(f.created, input)
+	return nil
+}
+
+func writeFile(t *testing.T, path, body string, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("set mod time: %v", err)
+	}
+}
+
+func TestRunOnceFlagsConflictingSkills(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	procedure := "Rotate database credentials through the vault, restart the billing workers, verify connection pools, notify finance operations afterwards."
+	writeFile(t, filepath.Join(root, "ws-1", "skills", "common", "rotate.md"), procedure, now.Add(-48*time.Hour))
+	writeFile(t, filepath.Join(root, "ws-1", "skills", "common", "greet.md"), "Greet new members warmly.", now.Add(-48*time.Hour))
+	writeFile(t, filepath.Join(root, "ws-1", "runbooks", "credentials.md"), procedure+" Vault paths changed in May.", now.Add(-24*time.Hour))
+	writeFile(t, filepath.Join(root, "ws-1", "tasks", "2026", "copy.md"), procedure, now)
+
+	clock := func() time.Time { return now }
+	sqlStore := &fakeStore{now: clock, usage: map[string]store.SkillUsage{}, admins: []store.ContextDelivery{{ContextID: "ctx-admin"}}}
+	engine := &fakeEngine{}
+	reviewer := New(Config{WorkspaceRoot: root, StaleAfter: 30 * 24 * time.Hour}, sqlStore, engine, nil)
+	reviewer.now = clock
+
+	opened, err := reviewer.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run once: %v", err)
+	}
+	if opened != 1 || len(engine.tasks) != 1 {
+		t.Fatalf("expected one review task, got %+v", engine.tasks)
+	}
+	task := engine.tasks[0]
+	if task.Title != "Review skill skills/common/rotate.md" || task.ContextID != "ctx-admin" {
+		t.Fatalf("unexpected review task: %+v", task)
+	}
+	if !strings.Contains(task.Prompt, "runbooks/credentials.md") || strings.Contains(task.Prompt, "tasks/2026") {
+		t.Fatalf("expected conflict with the runbook only, got %s", task.Prompt)
+	}
+	if len(sqlStore.created) != 1 || sqlStore.created[0].AssignedLane != "knowledge" {
+		t.Fatalf("expected persisted knowledge task, got %+v", sqlStore.created)
+	}
+	if sqlStore.usage["skills/common/rotate.md"].ReviewTaskID != task.ID {
+		t.Fatalf("expected skill to be marked reviewed, got %+v", sqlStore.usage)
+	}
+}
+
+func TestRunOnceFlagsUnusedSkillsAfterCooldown(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	writeFile(t, filepath.Join(root, "ws-1", "skills", "common", "unused.md"), "Archive quarterly reports.", now.Add(-90*24*time.Hour))
+	writeFile(t, filepath.Join(root, "ws-1", "skills", "common", "used.md"), "Summarize standups.", now.Add(-90*24*time.Hour))
+
+	current := now
+	clock := func() time.Time { return current }
+	sqlStore := &fakeStore{now: clock, usage: map[string]store.SkillUsage{}}
+	engine := &fakeEngine{}
+	reviewer := New(Config{WorkspaceRoot: root, StaleAfter: 30 * 24 * time.Hour}, sqlStore, engine, nil)
+	reviewer.now = clock
+
+	// Tracking starts now, so nothing is stale yet.
+	if opened, err := reviewer.RunOnce(context.Background()); err != nil || opened != 0 {
+		t.Fatalf("expected no tasks on first sight, got %d (%v)", opened, err)
+	}
+
+	current = now.Add(31 * 24 * time.Hour)
+	used := sqlStore.usage["skills/common/used.md"]
+	used.LastUsedAt = current.Add(-time.Hour)
+	sqlStore.usage["skills/common/used.md"] = used
+
+	opened, err := reviewer.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run once: %v", err)
+	}
+	if opened != 1 || engine.tasks[0].Title != "Review skill skills/common/unused.md" {
+		t.Fatalf("expected only the unused skill to be flagged, got %+v", engine.tasks)
+	}
+	if engine.tasks[0].ContextID != reviewContextID || !strings.Contains(engine.tasks[0].Prompt, "never retrieved") {
+		t.Fatalf("unexpected review task: %+v", engine.tasks[0])
+	}
+
+	// A second pass inside the cooldown does not reopen the same review.
+	if opened, err := reviewer.RunOnce(context.Background()); err != nil || opened != 0 {
+		t.Fatalf("expected no duplicate review, got %d (%v)", opened, err)
+	}
+}
@@ -0,0 +1,247 @@
+// Package spam scores chat messages for spam and bot patterns before they
+// reach triage, so junk is held for moderation instead of consuming model
+// turns.
+package spam
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>]+|\bwww\.[^\s<>]+`)
+
+// repeatedRunLength is the length of a single repeated character that counts
+// as filler, like "!!!!!!!!!!".
+const repeatedRunLength = 10
+
+// suspiciousDomains are invite links and URL shorteners that show up in
+// most chat spam.
+var suspiciousDomains = []string{
+	"discord.gg/", "discord.com/invite/", "t.me/", "bit.ly/", "tinyurl.com/",
+	"goo.gl/", "cutt.ly/", "is.gd/", "shorturl.at/",
+}
+
+var scamPhrases = []string{
+	"free nitro", "airdrop", "giveaway", "claim your", "double your",
+	"crypto investment", "guaranteed profit", "dm me for", "click here",
+	"limited offer", "earn $", "work from home",
+}
+
+type Config struct {
+	// Threshold is the score at which a message counts as spam.
+	Threshold float64
+	// RepeatWindow is how long identical messages from one sender count as
+	// repetition.
+	RepeatWindow time.Duration
+	// BurstLimit is the number of messages one sender may post within
+	// BurstWindow before the burst counts as bot-like.
+	BurstLimit  int
+	BurstWindow time.Duration
+	// NewAccountAge is the account age below which a sender is treated as new.
+	NewAccountAge time.Duration
+}
+
+// Message is what the filter knows about an inbound message.
+type Message struct {
+	Connector  string
+	ExternalID string
+	UserID     string
+	Text       string
+	// IsBot is set when the connector reports an automated account.
+	IsBot bool
+	// AccountCreatedAt is the sender's account creation time when the
+	// connector exposes it.
+	AccountCreatedAt time.Time
+}
+
+// Verdict is the outcome of scoring one message.
+type Verdict struct {
+	Spam    bool
+	Score   float64
+	Reasons []string
+}
+
+// Reason joins the verdict's reasons for logs and notices.
+func (v Verdict) Reason() string {
+	return strings.Join(v.Reasons, ", ")
+}
+
+type Filter struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	senders map[string][]sentMessage
+}
+
+type sentMessage struct {
+	at   time.Time
+	text string
+}
+
+func New(cfg Config) *Filter {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 0.7
+	}
+	if cfg.RepeatWindow <= 0 {
+		cfg.RepeatWindow = 10 * time.Minute
+	}
+	if cfg.BurstLimit < 1 {
+		cfg.BurstLimit = 6
+	}
+	if cfg.BurstWindow <= 0 {
+		cfg.BurstWindow = 20 * time.Second
+	}
+	if cfg.NewAccountAge <= 0 {
+		cfg.NewAccountAge = 7 * 24 * time.Hour
+	}
+	return &Filter{cfg: cfg, now: time.Now, senders: map[string][]sentMessage{}}
+}
+
+// Check scores a message and remembers it for repetition and burst checks.
+func (f *Filter) Check(message Message) Verdict {
+	text := strings.TrimSpace(message.Text)
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	score := 0.0
+	reasons := []string{}
+	add := func(weight float64, reason string) {
+		score += weight
+		reasons = append(reasons, reason)
+	}
+
+	if message.IsBot {
+		add(1, "sent by a bot account")
+	}
+	links := linkPattern.FindAllString(normalized, -1)
+	switch {
+	case len(links) >= 3:
+		add(0.4, fmt.Sprintf("%d links", len(links)))
+	case len(links) > 0:
+		add(0.15, "contains a link")
+	}
+	if containsAny(normalized, suspiciousDomains) {
+		add(0.3, "invite or shortened link")
+	}
+	if strings.Contains(normalized, "@everyone") || strings.Contains(normalized, "@here") {
+		add(0.3, "mass mention")
+	}
+	if containsAny(normalized, scamPhrases) {
+		add(0.35, "scam phrasing")
+	}
+	if hasRepeatedRun(normalized, repeatedRunLength) {
+		add(0.2, "repeated characters")
+	}
+	if !message.AccountCreatedAt.IsZero() && f.now().Sub(message.AccountCreatedAt) < f.cfg.NewAccountAge {
+		if len(links) > 0 {
+			add(0.3, "new account posting links")
+		} else {
+			add(0.1, "new account")
+		}
+	}
+
+	repeats, burst := f.remember(message, normalized)
+	switch {
+	case repeats >= 2:
+		add(0.7, fmt.Sprintf("same message %d times", repeats+1))
+	case repeats == 1:
+		add(0.3, "repeated message")
+	}
+	if burst {
+		add(0.4, "message burst")
+	}
+
+	if score > 1 {
+		score = 1
+	}
+	return Verdict{Spam: score >= f.cfg.Threshold, Score: score, Reasons: reasons}
+}
+
+// remember records a message and returns how often the sender posted the
+// same text within the repeat window and whether they exceeded the burst
+// limit.
+func (f *Filter) remember(message Message, normalized string) (int, bool) {
+	sender := strings.Join([]string{
+		strings.ToLower(strings.TrimSpace(message.Connector)),
+		strings.TrimSpace(message.ExternalID),
+		strings.TrimSpace(message.UserID),
+	}, "|")
+	now := f.now()
+	keep := f.cfg.RepeatWindow
+	if f.cfg.BurstWindow > keep {
+		keep = f.cfg.BurstWindow
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recent := f.senders[sender][:0]
+	repeats := 0
+	inBurst := 0
+	for _, sent := range f.senders[sender] {
+		age := now.Sub(sent.at)
+		if age > keep {
+			continue
+		}
+		recent = append(recent, sent)
+		if normalized != "" && sent.text == normalized && age <= f.cfg.RepeatWindow {
+			repeats++
+		}
+		if age <= f.cfg.BurstWindow {
+			inBurst++
+		}
+	}
+	f.senders[sender] = append(recent, sentMessage{at: now, text: normalized})
+	if len(f.senders) > 10000 {
+		f.pruneLocked(now, keep)
+	}
+	return repeats, inBurst+1 > f.cfg.BurstLimit
+}
+
+func (f *Filter) pruneLocked(now time.Time, keep time.Duration) {
+	senders := make([]string, 0, len(f.senders))
+	for sender, history := range f.senders {
+		if len(history) == 0 || now.Sub(history[len(history)-1].at) > keep {
+			delete(f.senders, sender)
+			continue
+		}
+		senders = append(senders, sender)
+	}
+	if len(senders) <= 10000 {
+		return
+	}
+	sort.Slice(senders, func(i, j int) bool {
+		a, b := f.senders[senders[i]], f.senders[senders[j]]
+		return a[len(a)-1].at.Before(b[len(b)-1].at)
+	})
+	for _, sender := range senders[:len(senders)-10000] {
+		delete(f.senders, sender)
+	}
+}
+
+func hasRepeatedRun(text string, length int) bool {
+	run := 0
+	var previous rune
+	for _, r := range text {
+		if r == previous {
+			run++
+		} else {
+			previous, run = r, 1
+		}
+		if run >= length {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(text string, needles []string) bool {
+	for _, needle := range needles {
+		if strings.Contains(text, needle) {
+			return true
+		}
+	}
+	return false
+}
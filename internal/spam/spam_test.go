@@ -0,0 +1,56 @@
+package spam
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestFilter(now time.Time) *Filter {
+	filter := New(Config{})
+	filter.now = func() time.Time { return now }
+	return filter
+}
+
+func TestCheckPassesOrdinaryMessages(t *testing.T) {
+	filter := newTestFilter(time.Now())
+	for _, text := range []string{
+		"Can someone look at the deploy failure from this morning?",
+		"The docs are at https://example.com/docs if that helps",
+	} {
+		verdict := filter.Check(Message{Connector: "discord", ExternalID: "c1", UserID: "u1", Text: text})
+		if verdict.Spam {
+			t.Fatalf("expected %q to pass, got %+v", text, verdict)
+		}
+	}
+}
+
+func TestCheckFlagsLinkSpamFromNewAccounts(t *testing.T) {
+	now := time.Now()
+	filter := newTestFilter(now)
+	verdict := filter.Check(Message{
+		Connector:        "discord",
+		ExternalID:       "c1",
+		UserID:           "u2",
+		Text:             "@everyone free nitro giveaway https://discord.gg/abc",
+		AccountCreatedAt: now.Add(-2 * time.Hour),
+	})
+	if !verdict.Spam || verdict.Score != 1 || len(verdict.Reasons) < 3 {
+		t.Fatalf("expected spam verdict with reasons, got %+v", verdict)
+	}
+}
+
+func TestCheckFlagsRepetitionAndBots(t *testing.T) {
+	filter := newTestFilter(time.Now())
+	message := Message{Connector: "telegram", ExternalID: "42", UserID: "7", Text: "Join my channel now"}
+	for i := 0; i < 2; i++ {
+		if verdict := filter.Check(message); verdict.Spam {
+			t.Fatalf("expected message %d to pass, got %+v", i+1, verdict)
+		}
+	}
+	if verdict := filter.Check(message); !verdict.Spam {
+		t.Fatalf("expected third identical message to be spam, got %+v", verdict)
+	}
+	if verdict := filter.Check(Message{Connector: "telegram", ExternalID: "42", UserID: "8", Text: "hello", IsBot: true}); !verdict.Spam {
+		t.Fatalf("expected bot account to be flagged, got %+v", verdict)
+	}
+}
@@ -0,0 +1,132 @@
+package statuspage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// State is the health shown next to each item and for the page overall.
+type State string
+
+const (
+	StateOperational State = "operational"
+	StateDegraded    State = "degraded"
+	StateOutage      State = "outage"
+	StateUnknown     State = "unknown"
+)
+
+var stateRank = map[State]int{StateOperational: 0, StateUnknown: 1, StateDegraded: 2, StateOutage: 3}
+
+func worse(a, b State) State {
+	if stateRank[b] > stateRank[a] {
+		return b
+	}
+	return a
+}
+
+// Page is everything rendered for one workspace.
+type Page struct {
+	Title       string            `json:"title"`
+	WorkspaceID string            `json:"workspace_id"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	WindowDays  int               `json:"window_days"`
+	Overall     State             `json:"overall"`
+	Objectives  []ObjectiveStatus `json:"objectives"`
+	Incidents   []Incident        `json:"incidents"`
+	Endpoints   []EndpointStatus  `json:"endpoints"`
+}
+
+type ObjectiveStatus struct {
+	Title     string    `json:"title"`
+	State     State     `json:"state"`
+	LastRunAt time.Time `json:"last_run_at,omitzero"`
+	NextRunAt time.Time `json:"next_run_at,omitzero"`
+}
+
+type Incident struct {
+	Title      string    `json:"title"`
+	Open       bool      `json:"open"`
+	OpenedAt   time.Time `json:"opened_at"`
+	ResolvedAt time.Time `json:"resolved_at,omitzero"`
+}
+
+type EndpointStatus struct {
+	Name  string `json:"name"`
+	State State  `json:"state"`
+	// UptimePercent is -1 when the endpoint has not been checked yet.
+	UptimePercent float64   `json:"uptime_percent"`
+	LastCheckedAt time.Time `json:"last_checked_at,omitzero"`
+}
+
+func RenderJSON(page Page) ([]byte, error) {
+	data, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode status json: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func RenderHTML(page Page) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := pageTemplate.Execute(&buffer, page); err != nil {
+		return nil, fmt.Errorf("render status page: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+var pageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"when": func(value time.Time) string {
+		if value.IsZero() {
+			return "—"
+		}
+		return value.UTC().Format("2006-01-02 15:04 UTC")
+	},
+	"uptime": func(percent float64) string {
+		if percent < 0 {
+			return "no data"
+		}
+		return fmt.Sprintf("%.2f%%", percent)
+	},
+}).Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · {{.WorkspaceID}}</title>
+<style>
+body{font-family:system-ui,sans-serif;max-width:48rem;margin:2rem auto;padding:0 1rem;color:#222}
+table{width:100%;border-collapse:collapse;margin-bottom:2rem}
+th,td{text-align:left;padding:.4rem;border-bottom:1px solid #ddd}
+.operational{color:#1a7f37}.degraded{color:#9a6700}.outage{color:#cf222e}.unknown{color:#666}
+.banner{padding:1rem;border-radius:.4rem;background:#f6f8fa;font-weight:600}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="banner {{.Overall}}">Overall: {{.Overall}}</p>
+
+<h2>Endpoints</h2>
+{{if .Endpoints}}<table>
+<tr><th>Endpoint</th><th>State</th><th>Uptime ({{.WindowDays}}d)</th><th>Last checked</th></tr>
+{{range .Endpoints}}<tr><td>{{.Name}}</td><td class="{{.State}}">{{.State}}</td><td>{{uptime .UptimePercent}}</td><td>{{when .LastCheckedAt}}</td></tr>
+{{end}}</table>{{else}}<p>No monitored endpoints.</p>{{end}}
+
+<h2>Incidents</h2>
+{{if .Incidents}}<table>
+<tr><th>Incident</th><th>Status</th><th>Opened</th><th>Resolved</th></tr>
+{{range .Incidents}}<tr><td>{{.Title}}</td><td class="{{if .Open}}degraded{{else}}operational{{end}}">{{if .Open}}open{{else}}resolved{{end}}</td><td>{{when .OpenedAt}}</td><td>{{when .ResolvedAt}}</td></tr>
+{{end}}</table>{{else}}<p>No incidents in the last {{.WindowDays}} days.</p>{{end}}
+
+<h2>Active objectives</h2>
+{{if .Objectives}}<table>
+<tr><th>Objective</th><th>State</th><th>Last run</th><th>Next run</th></tr>
+{{range .Objectives}}<tr><td>{{.Title}}</td><td class="{{.State}}">{{.State}}</td><td>{{when .LastRunAt}}</td><td>{{when .NextRunAt}}</td></tr>
+{{end}}</table>{{else}}<p>No active objectives.</p>{{end}}
+
+<p><small>Generated {{when .GeneratedAt}}.</small></p>
+</body>
+</html>
+`))
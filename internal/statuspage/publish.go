@@ -0,0 +1,232 @@
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Publisher stores one rendered file under a slash-separated name such as
+// "ws-1/index.html".
+type Publisher interface {
+	Publish(ctx context.Context, name, contentType string, body []byte) error
+}
+
+// DirPublisher writes pages below a local directory, e.g. one served by a
+// web server. Files are replaced atomically so readers never see half a page.
+type DirPublisher struct {
+	Root string
+}
+
+func (p DirPublisher) Publish(ctx context.Context, name, contentType string, body []byte) error {
+	cleaned, err := cleanObjectName(name)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(p.Root, filepath.FromSlash(cleaned))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("create status page dir: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(target), ".status-*")
+	if err != nil {
+		return fmt.Errorf("create status page temp file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(body); err != nil {
+		temp.Close()
+		return fmt.Errorf("write status page: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("write status page: %w", err)
+	}
+	if err := os.Chmod(temp.Name(), 0o644); err != nil {
+		return fmt.Errorf("write status page: %w", err)
+	}
+	if err := os.Rename(temp.Name(), target); err != nil {
+		return fmt.Errorf("replace status page: %w", err)
+	}
+	return nil
+}
+
+// S3Config points at a bucket on AWS S3 or any S3-compatible store. With an
+// Endpoint, requests use path-style URLs (endpoint/bucket/key); without one
+// they go to the AWS virtual-hosted endpoint of the region.
+type S3Config struct {
+	Bucket          string
+	Region          string
+	Prefix          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3Publisher uploads pages with SigV4-signed PUT requests.
+type S3Publisher struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Publisher(cfg S3Config, client *http.Client) (*S3Publisher, error) {
+	cfg.Bucket = strings.TrimSpace(cfg.Bucket)
+	cfg.Region = strings.TrimSpace(cfg.Region)
+	cfg.Prefix = strings.Trim(strings.TrimSpace(cfg.Prefix), "/")
+	cfg.Endpoint = strings.TrimRight(strings.TrimSpace(cfg.Endpoint), "/")
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if strings.TrimSpace(cfg.AccessKeyID) == "" || strings.TrimSpace(cfg.SecretAccessKey) == "" {
+		return nil, fmt.Errorf("s3 access key id and secret access key are required")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &S3Publisher{cfg: cfg, client: client, now: time.Now}, nil
+}
+
+func (p *S3Publisher) Publish(ctx context.Context, name, contentType string, body []byte) error {
+	cleaned, err := cleanObjectName(name)
+	if err != nil {
+		return err
+	}
+	key := cleaned
+	if p.cfg.Prefix != "" {
+		key = p.cfg.Prefix + "/" + cleaned
+	}
+	objectURL, err := p.objectURL(key)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build s3 request: %w", err)
+	}
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("Cache-Control", "max-age=60")
+	p.sign(request, body, p.now().UTC())
+	response, err := p.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("s3 put %s: %w", key, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("s3 put %s: %s: %s", key, response.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+func (p *S3Publisher) objectURL(key string) (*url.URL, error) {
+	escaped := escapeS3Path(key)
+	if p.cfg.Endpoint == "" {
+		return url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", p.cfg.Bucket, p.cfg.Region, escaped))
+	}
+	base, err := url.Parse(p.cfg.Endpoint)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", p.cfg.Endpoint)
+	}
+	return url.Parse(strings.TrimRight(base.String(), "/") + "/" + escapeS3Path(p.cfg.Bucket) + "/" + escaped)
+}
+
+// sign adds AWS Signature Version 4 headers for the s3 service.
+func (p *S3Publisher) sign(request *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if token := strings.TrimSpace(p.cfg.SessionToken); token != "" {
+		request.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	signedNames := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if request.Header.Get("X-Amz-Security-Token") != "" {
+		signedNames = append(signedNames, "x-amz-security-token")
+	}
+	canonicalHeaders := strings.Builder{}
+	for _, name := range signedNames {
+		value := request.Header.Get(name)
+		if name == "host" {
+			value = request.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(signedNames, ";")
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + p.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(signingKey(p.cfg.SecretAccessKey, day, p.cfg.Region, "s3"), stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		strings.TrimSpace(p.cfg.AccessKeyID),
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func signingKey(secret, day, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escapeS3Path percent-encodes each segment of an object key, keeping the
+// slashes. "+" is escaped too, since S3 would read it as a space.
+func escapeS3Path(key string) string {
+	segments := strings.Split(key, "/")
+	for index, segment := range segments {
+		segments[index] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+// cleanObjectName rejects names that are empty or climb out of the
+// publishing root; workspace ids end up in them.
+func cleanObjectName(name string) (string, error) {
+	trimmed := strings.TrimSpace(name)
+	for _, segment := range strings.Split(trimmed, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("invalid status page name %q", name)
+		}
+	}
+	cleaned := strings.TrimPrefix(path.Clean("/"+trimmed), "/")
+	if cleaned == "" || cleaned == "." {
+		return "", fmt.Errorf("invalid status page name %q", name)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,284 @@
+// Package statuspage periodically renders a public status page for each
+// opted-in workspace — active objectives, recent incidents and the uptime of
+// monitored endpoints — and publishes it to a directory or an S3 bucket so
+// community members can see how the runtime is doing.
+package statuspage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+const (
+	probeTimeout      = 10 * time.Second
+	maxIncidents      = 20
+	maxObjectives     = 50
+	checkRetentionPad = 24 * time.Hour
+)
+
+type Store interface {
+	ListObjectives(ctx context.Context, input store.ListObjectivesInput) ([]store.Objective, error)
+	ListCases(ctx context.Context, input store.ListCasesInput) ([]store.Case, error)
+	RecordEndpointCheck(ctx context.Context, check store.EndpointCheck) (store.EndpointCheck, error)
+	LookupEndpointUptime(ctx context.Context, endpoint string, since time.Time) (store.EndpointUptime, error)
+	PruneEndpointChecks(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Endpoint is a URL probed with GET on every cycle. Any 2xx or 3xx answer
+// counts as up.
+type Endpoint struct {
+	Name string
+	URL  string
+}
+
+type Config struct {
+	Workspaces []string
+	Endpoints  []Endpoint
+	Interval   time.Duration
+	// Window is how far back uptime and resolved incidents reach.
+	Window time.Duration
+	Title  string
+}
+
+type Generator struct {
+	workspaces []string
+	endpoints  []Endpoint
+	interval   time.Duration
+	window     time.Duration
+	title      string
+	store      Store
+	publisher  Publisher
+	client     *http.Client
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+func New(cfg Config, storeRef Store, publisher Publisher, logger *slog.Logger) *Generator {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	window := cfg.Window
+	if window <= 0 {
+		window = 7 * 24 * time.Hour
+	}
+	title := strings.TrimSpace(cfg.Title)
+	if title == "" {
+		title = "Status"
+	}
+	workspaces := []string{}
+	for _, workspaceID := range cfg.Workspaces {
+		if trimmed := strings.TrimSpace(workspaceID); trimmed != "" {
+			workspaces = append(workspaces, trimmed)
+		}
+	}
+	endpoints := []Endpoint{}
+	for _, endpoint := range cfg.Endpoints {
+		name := strings.TrimSpace(endpoint.Name)
+		url := strings.TrimSpace(endpoint.URL)
+		if name == "" || url == "" {
+			continue
+		}
+		endpoints = append(endpoints, Endpoint{Name: name, URL: url})
+	}
+	return &Generator{
+		workspaces: workspaces,
+		endpoints:  endpoints,
+		interval:   interval,
+		window:     window,
+		title:      title,
+		store:      storeRef,
+		publisher:  publisher,
+		client:     &http.Client{Timeout: probeTimeout},
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (g *Generator) Start(ctx context.Context) error {
+	g.logger.Info("status page generator started", "workspaces", len(g.workspaces), "endpoints", len(g.endpoints), "interval", g.interval.String())
+	g.runCycle(ctx)
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			g.runCycle(ctx)
+		}
+	}
+}
+
+func (g *Generator) runCycle(ctx context.Context) {
+	now := g.now().UTC()
+	g.ProbeEndpoints(ctx, now)
+	published, err := g.PublishAll(ctx, now)
+	if err != nil {
+		g.logger.Error("status page publish failed", "error", err)
+	}
+	if published > 0 {
+		g.logger.Debug("status pages published", "count", published)
+	}
+	if _, err := g.store.PruneEndpointChecks(ctx, now.Add(-g.window-checkRetentionPad)); err != nil {
+		g.logger.Warn("prune endpoint checks failed", "error", err)
+	}
+}
+
+// ProbeEndpoints checks every monitored endpoint once and records the result.
+func (g *Generator) ProbeEndpoints(ctx context.Context, now time.Time) {
+	for _, endpoint := range g.endpoints {
+		check := g.probe(ctx, endpoint)
+		check.CheckedAt = now
+		if _, err := g.store.RecordEndpointCheck(ctx, check); err != nil {
+			g.logger.Warn("record endpoint check failed", "endpoint", endpoint.Name, "error", err)
+		}
+	}
+}
+
+func (g *Generator) probe(ctx context.Context, endpoint Endpoint) store.EndpointCheck {
+	check := store.EndpointCheck{Endpoint: endpoint.Name, URL: endpoint.URL}
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(probeCtx, http.MethodGet, endpoint.URL, nil)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	request.Header.Set("User-Agent", "agent-runtime-status/1")
+	started := time.Now()
+	response, err := g.client.Do(request)
+	check.LatencyMs = time.Since(started).Milliseconds()
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	_ = response.Body.Close()
+	check.StatusCode = response.StatusCode
+	check.Up = response.StatusCode >= 200 && response.StatusCode < 400
+	if !check.Up {
+		check.Error = response.Status
+	}
+	return check
+}
+
+// PublishAll renders and publishes the page of every configured workspace.
+// A workspace that fails does not stop the others; the first error is
+// returned.
+func (g *Generator) PublishAll(ctx context.Context, now time.Time) (int, error) {
+	if g.publisher == nil {
+		return 0, nil
+	}
+	published := 0
+	var firstErr error
+	for _, workspaceID := range g.workspaces {
+		if err := g.Publish(ctx, workspaceID, now); err != nil {
+			g.logger.Warn("status page publish failed", "workspace_id", workspaceID, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		published++
+	}
+	return published, firstErr
+}
+
+// Publish writes index.html and status.json for one workspace.
+func (g *Generator) Publish(ctx context.Context, workspaceID string, now time.Time) error {
+	page, err := g.Build(ctx, workspaceID, now)
+	if err != nil {
+		return err
+	}
+	html, err := RenderHTML(page)
+	if err != nil {
+		return err
+	}
+	data, err := RenderJSON(page)
+	if err != nil {
+		return err
+	}
+	if err := g.publisher.Publish(ctx, workspaceID+"/index.html", "text/html; charset=utf-8", html); err != nil {
+		return fmt.Errorf("publish %s status page: %w", workspaceID, err)
+	}
+	if err := g.publisher.Publish(ctx, workspaceID+"/status.json", "application/json", data); err != nil {
+		return fmt.Errorf("publish %s status json: %w", workspaceID, err)
+	}
+	return nil
+}
+
+// Build collects what goes on a workspace's page. Only titles, states and
+// times are included: prompts, summaries and endpoint URLs stay private.
+func (g *Generator) Build(ctx context.Context, workspaceID string, now time.Time) (Page, error) {
+	since := now.Add(-g.window)
+	page := Page{
+		Title:       g.title,
+		WorkspaceID: workspaceID,
+		GeneratedAt: now,
+		WindowDays:  int(g.window.Hours() / 24),
+		Overall:     StateOperational,
+	}
+
+	objectives, err := g.store.ListObjectives(ctx, store.ListObjectivesInput{WorkspaceID: workspaceID, ActiveOnly: true, Limit: maxObjectives})
+	if err != nil {
+		return Page{}, fmt.Errorf("list objectives: %w", err)
+	}
+	for _, objective := range objectives {
+		item := ObjectiveStatus{Title: objective.Title, LastRunAt: objective.LastRunAt, NextRunAt: objective.NextRunAt, State: StateOperational}
+		if objective.ConsecutiveFailures > 0 {
+			item.State = StateDegraded
+		}
+		page.Objectives = append(page.Objectives, item)
+	}
+
+	cases, err := g.store.ListCases(ctx, store.ListCasesInput{WorkspaceID: workspaceID, Kind: store.CaseKindIncident, Limit: maxIncidents * 2})
+	if err != nil {
+		return Page{}, fmt.Errorf("list incidents: %w", err)
+	}
+	for _, record := range cases {
+		open := record.Status == store.CaseStatusOpen
+		if !open && record.ResolvedAt.Before(since) {
+			continue
+		}
+		page.Incidents = append(page.Incidents, Incident{
+			Title:      strings.TrimSpace(strings.TrimPrefix(record.Title, "[INCIDENT]")),
+			Open:       open,
+			OpenedAt:   record.CreatedAt,
+			ResolvedAt: record.ResolvedAt,
+		})
+		if open {
+			page.Overall = worse(page.Overall, StateDegraded)
+		}
+		if len(page.Incidents) == maxIncidents {
+			break
+		}
+	}
+
+	for _, endpoint := range g.endpoints {
+		uptime, err := g.store.LookupEndpointUptime(ctx, endpoint.Name, since)
+		if err != nil {
+			return Page{}, fmt.Errorf("endpoint uptime: %w", err)
+		}
+		item := EndpointStatus{Name: endpoint.Name, UptimePercent: uptime.Percent(), LastCheckedAt: uptime.LastCheck.CheckedAt, State: StateUnknown}
+		if !uptime.LastCheck.CheckedAt.IsZero() {
+			item.State = StateOperational
+			if !uptime.LastCheck.Up {
+				item.State = StateOutage
+			}
+		}
+		page.Overall = worse(page.Overall, item.State)
+		page.Endpoints = append(page.Endpoints, item)
+	}
+	sort.SliceStable(page.Endpoints, func(i, j int) bool { return page.Endpoints[i].Name < page.Endpoints[j].Name })
+	return page, nil
+}
@@ -0,0 +1,151 @@
+package statuspage
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	sqlStore, err := store.New(filepath.Join(t.TempDir(), "statuspage_test.sqlite"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlStore.Close() })
+	if err := sqlStore.AutoMigrate(context.Background()); err != nil {
+		t.Fatalf("migrate store: %v", err)
+	}
+	return sqlStore
+}
+
+func TestPublishWritesWorkspacePageWithIncidentsAndUptime(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := newTestStore(t)
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+	defer healthy.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) }))
+	defer broken.Close()
+
+	if _, err := sqlStore.CreateObjective(ctx, store.CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Nightly digest",
+		Prompt:      "secret prompt text",
+		TriggerType: store.ObjectiveTriggerSchedule,
+		CronExpr:    "0 3 * * *",
+	}); err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	if _, err := sqlStore.CreateCase(ctx, store.CreateCaseInput{WorkspaceID: "ws-1", Kind: store.CaseKindIncident, Title: "[INCIDENT] Login broken", Summary: "private details"}); err != nil {
+		t.Fatalf("create incident: %v", err)
+	}
+	if _, err := sqlStore.CreateCase(ctx, store.CreateCaseInput{WorkspaceID: "ws-1", Kind: store.CaseKindModeration, Title: "[MODERATION] spam"}); err != nil {
+		t.Fatalf("create moderation case: %v", err)
+	}
+
+	root := t.TempDir()
+	generator := New(Config{
+		Workspaces: []string{"ws-1"},
+		Endpoints:  []Endpoint{{Name: "api", URL: healthy.URL}, {Name: "web", URL: broken.URL}},
+		Title:      "Community status",
+	}, sqlStore, DirPublisher{Root: root}, nil)
+	now := time.Now().UTC()
+	generator.ProbeEndpoints(ctx, now)
+	published, err := generator.PublishAll(ctx, now)
+	if err != nil || published != 1 {
+		t.Fatalf("expected one published page, got %d (%v)", published, err)
+	}
+
+	html, err := os.ReadFile(filepath.Join(root, "ws-1", "index.html"))
+	if err != nil {
+		t.Fatalf("read page: %v", err)
+	}
+	page := string(html)
+	for _, want := range []string{"Community status", "Login broken", "Nightly digest", "100.00%", "0.00%", "Overall: outage"} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("expected page to contain %q:\n%s", want, page)
+		}
+	}
+	for _, private := range []string{"secret prompt text", "private details", "spam", healthy.URL} {
+		if strings.Contains(page, private) {
+			t.Fatalf("expected page not to leak %q", private)
+		}
+	}
+
+	raw, err := os.ReadFile(filepath.Join(root, "ws-1", "status.json"))
+	if err != nil {
+		t.Fatalf("read json: %v", err)
+	}
+	var decoded Page
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if decoded.Overall != StateOutage || len(decoded.Endpoints) != 2 || decoded.Endpoints[1].State != StateOutage || len(decoded.Incidents) != 1 || !decoded.Incidents[0].Open {
+		t.Fatalf("unexpected status json: %+v", decoded)
+	}
+}
+
+func TestSigningKeyMatchesAWSExample(t *testing.T) {
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	if got := hex.EncodeToString(key); got != "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d" {
+		t.Fatalf("unexpected signing key %s", got)
+	}
+}
+
+func TestS3PublisherPutsSignedObject(t *testing.T) {
+	var gotPath, gotAuth, gotHash, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		gotHash = r.Header.Get("X-Amz-Content-Sha256")
+		gotBody = string(body)
+	}))
+	defer server.Close()
+
+	publisher, err := NewS3Publisher(S3Config{
+		Bucket:          "status-bucket",
+		Region:          "eu-west-1",
+		Prefix:          "/pages/",
+		Endpoint:        server.URL,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("new s3 publisher: %v", err)
+	}
+	publisher.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+	if err := publisher.Publish(context.Background(), "ws-1/index.html", "text/html", []byte("<html></html>")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if gotPath != "/status-bucket/pages/ws-1/index.html" || gotBody != "<html></html>" {
+		t.Fatalf("unexpected upload %s %q", gotPath, gotBody)
+	}
+	if gotHash != sha256Hex([]byte("<html></html>")) {
+		t.Fatalf("unexpected payload hash %s", gotHash)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260301/eu-west-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=") {
+		t.Fatalf("unexpected authorization header %s", gotAuth)
+	}
+
+	if _, err := NewS3Publisher(S3Config{Bucket: "b"}, nil); err == nil {
+		t.Fatal("expected credentials to be required")
+	}
+	if err := publisher.Publish(context.Background(), "../escape", "text/plain", nil); err == nil {
+		t.Fatal("expected names outside the prefix to be rejected")
+	}
+}
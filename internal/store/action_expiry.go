@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ExpiredApprovalUserID is recorded as the approver of approvals denied
+// because nobody decided in time.
+const ExpiredApprovalUserID = "system:expiry"
+
+// ActionApprovalTTLPolicy decides how long a new approval of an action type
+// stays pending. A zero duration means it never expires.
+type ActionApprovalTTLPolicy interface {
+	ActionApprovalTTL(actionType string) time.Duration
+}
+
+func (s *Store) SetActionApprovalTTLPolicy(policy ActionApprovalTTLPolicy) {
+	s.approvalTTL = policy
+}
+
+// ExpireActionApprovals denies pending approvals whose expiry has passed and
+// returns them. An approval decided concurrently is left alone.
+func (s *Store) ExpireActionApprovals(ctx context.Context, now time.Time, limit int) ([]ActionApproval, error) {
+	if limit < 1 {
+		limit = 100
+	}
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, workspace_id, context_id, connector, external_id, requester_user_id, action_type, action_target, action_summary, payload_json, status, approver_user_id, denied_reason
+		 , execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix, expires_at_unix, required_approvals, risk_level, risk_reason
+		 FROM action_approvals
+		 WHERE status = 'pending' AND expires_at_unix IS NOT NULL AND expires_at_unix > 0 AND expires_at_unix <= ?
+		 ORDER BY expires_at_unix ASC
+		 LIMIT ?`,
+		now.UTC().Unix(),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query expired action approvals: %w", err)
+	}
+	candidates := []ActionApproval{}
+	for rows.Next() {
+		record, scanErr := scanActionApproval(rows)
+		if scanErr != nil {
+			rows.Close()
+			return nil, scanErr
+		}
+		candidates = append(candidates, record)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate expired action approvals: %w", err)
+	}
+	rows.Close()
+
+	expired := make([]ActionApproval, 0, len(candidates))
+	updatedAt := now.UTC()
+	for _, record := range candidates {
+		reason := fmt.Sprintf("expired: no decision within %s", record.ExpiresAt.Sub(record.CreatedAt).Round(time.Minute))
+		result, err := s.db.ExecContext(
+			ctx,
+			`UPDATE action_approvals SET status = 'denied', approver_user_id = ?, denied_reason = ?, updated_at_unix = ? WHERE id = ? AND status = 'pending'`,
+			ExpiredApprovalUserID,
+			reason,
+			updatedAt.Unix(),
+			record.ID,
+		)
+		if err != nil {
+			return expired, fmt.Errorf("expire action approval: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			continue
+		}
+		record.Status = "denied"
+		record.ApproverUserID = ExpiredApprovalUserID
+		record.DeniedReason = reason
+		record.UpdatedAt = updatedAt
+		s.notifyActionApprovalDecided(ctx, record)
+		expired = append(expired, record)
+	}
+	return expired, nil
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fixedApprovalTTL map[string]time.Duration
+
+func (f fixedApprovalTTL) ActionApprovalTTL(actionType string) time.Duration {
+	return f[actionType]
+}
+
+func TestExpireActionApprovalsDeniesOnlyExpiredPending(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	sqlStore.SetActionApprovalTTLPolicy(fixedApprovalTTL{"run_command": time.Hour})
+
+	create := func(actionType string) ActionApproval {
+		t.Helper()
+		approval, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+			WorkspaceID:     "ws-1",
+			ContextID:       "ctx-1",
+			Connector:       "telegram",
+			ExternalID:      "42",
+			RequesterUserID: "user-1",
+			ActionType:      actionType,
+		})
+		if err != nil {
+			t.Fatalf("create %s approval: %v", actionType, err)
+		}
+		return approval
+	}
+	expiring := create("run_command")
+	forever := create("send_email")
+	if expiring.ExpiresAt.IsZero() || !forever.ExpiresAt.IsZero() {
+		t.Fatalf("expected expiry only for run_command, got %v / %v", expiring.ExpiresAt, forever.ExpiresAt)
+	}
+	if stored, err := sqlStore.LookupActionApproval(ctx, expiring.ID); err != nil || stored.ExpiresAt.Unix() != expiring.ExpiresAt.Unix() {
+		t.Fatalf("expected expiry to be stored, got %+v (%v)", stored, err)
+	}
+
+	if expired, err := sqlStore.ExpireActionApprovals(ctx, time.Now().Add(30*time.Minute), 10); err != nil || len(expired) != 0 {
+		t.Fatalf("expected nothing to expire yet, got %+v (%v)", expired, err)
+	}
+	later := time.Now().Add(2 * time.Hour)
+	expired, err := sqlStore.ExpireActionApprovals(ctx, later, 10)
+	if err != nil || len(expired) != 1 || expired[0].ID != expiring.ID {
+		t.Fatalf("expected the run_command approval to expire, got %+v (%v)", expired, err)
+	}
+	if expired[0].Status != "denied" || expired[0].ApproverUserID != ExpiredApprovalUserID || expired[0].DeniedReason != "expired: no decision within 1h0m0s" {
+		t.Fatalf("unexpected expired approval %+v", expired[0])
+	}
+	if again, err := sqlStore.ExpireActionApprovals(ctx, later, 10); err != nil || len(again) != 0 {
+		t.Fatalf("expected expiry to run once, got %+v (%v)", again, err)
+	}
+	if _, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: expiring.ID, ApproverUserID: "admin"}); !errors.Is(err, ErrActionApprovalNotReady) {
+		t.Fatalf("expected expired approval to be closed, got %v", err)
+	}
+}
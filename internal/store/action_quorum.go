@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrActionApprovalQuorumPending is returned with the updated record when
+	// an approval was recorded but more admins still have to sign off.
+	ErrActionApprovalQuorumPending = errors.New("action approval needs more admin approvals")
+	ErrActionApprovalAlreadySigned = errors.New("action approval already signed by this admin")
+)
+
+// ApprovalSignoff is one admin's approval of an action that needs several.
+type ApprovalSignoff struct {
+	UserID     string
+	ApprovedAt time.Time
+}
+
+// ActionApprovalQuorumPolicy decides how many distinct admins must approve a
+// new action before it runs. Anything below two means a single approval.
+type ActionApprovalQuorumPolicy interface {
+	RequiredApprovals(record ActionApproval) int
+}
+
+func (s *Store) SetActionApprovalQuorumPolicy(policy ActionApprovalQuorumPolicy) {
+	s.approvalQuorum = policy
+}
+
+// approvalSignoffQuerier is the database or the transaction that records a
+// signoff, so the quorum is counted in the same transaction.
+type approvalSignoffQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func addApprovalSignoff(ctx context.Context, querier approvalSignoffQuerier, approvalID, approverUserID string, now time.Time) error {
+	result, err := querier.ExecContext(
+		ctx,
+		`INSERT OR IGNORE INTO action_approval_signoffs (approval_id, approver_user_id, approved_at_unix) VALUES (?, ?, ?)`,
+		approvalID,
+		approverUserID,
+		now.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("record approval signoff: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrActionApprovalAlreadySigned
+	}
+	return nil
+}
+
+func listApprovalSignoffs(ctx context.Context, querier approvalSignoffQuerier, approvalID string) ([]ApprovalSignoff, error) {
+	rows, err := querier.QueryContext(
+		ctx,
+		`SELECT approver_user_id, approved_at_unix
+		 FROM action_approval_signoffs
+		 WHERE approval_id = ?
+		 ORDER BY approved_at_unix ASC, approver_user_id ASC`,
+		strings.TrimSpace(approvalID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list approval signoffs: %w", err)
+	}
+	defer rows.Close()
+
+	signoffs := []ApprovalSignoff{}
+	for rows.Next() {
+		var signoff ApprovalSignoff
+		var approvedAtUnix int64
+		if err := rows.Scan(&signoff.UserID, &approvedAtUnix); err != nil {
+			return nil, fmt.Errorf("scan approval signoff: %w", err)
+		}
+		signoff.ApprovedAt = time.Unix(approvedAtUnix, 0).UTC()
+		signoffs = append(signoffs, signoff)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate approval signoffs: %w", err)
+	}
+	return signoffs, nil
+}
+
+// attachApprovalSignoffs loads signoffs for records that need more than one
+// approval. Callers must have closed their row cursor first.
+func (s *Store) attachApprovalSignoffs(ctx context.Context, records []ActionApproval) ([]ActionApproval, error) {
+	for index := range records {
+		if records[index].RequiredApprovals < 2 {
+			continue
+		}
+		signoffs, err := listApprovalSignoffs(ctx, s.db, records[index].ID)
+		if err != nil {
+			return nil, err
+		}
+		records[index].Signoffs = signoffs
+	}
+	return records, nil
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sync"
+
+	"path/filepath"
+)
+
+type fixedApprovalQuorum map[string]int
+
+func (f fixedApprovalQuorum) RequiredApprovals(record ActionApproval) int {
+	return f[record.ActionType]
+}
+
+func TestApproveActionApprovalRequiresDistinctAdmins(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	sqlStore.SetActionApprovalQuorumPolicy(fixedApprovalQuorum{"send_email": 2})
+
+	create := func(actionType string) ActionApproval {
+		t.Helper()
+		approval, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+			WorkspaceID:     "ws-1",
+			ContextID:       "ctx-1",
+			Connector:       "telegram",
+			ExternalID:      "42",
+			RequesterUserID: "user-1",
+			ActionType:      actionType,
+		})
+		if err != nil {
+			t.Fatalf("create %s approval: %v", actionType, err)
+		}
+		return approval
+	}
+	guarded := create("send_email")
+	plain := create("run_command")
+	if guarded.RequiredApprovals != 2 || plain.RequiredApprovals != 1 {
+		t.Fatalf("unexpected required approvals %d / %d", guarded.RequiredApprovals, plain.RequiredApprovals)
+	}
+
+	if _, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: guarded.ID, ApproverUserID: "system:agent"}); !errors.Is(err, ErrActionApprovalQuorumPending) {
+		t.Fatalf("expected system approver not to count, got %v", err)
+	}
+	first, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: guarded.ID, ApproverUserID: "admin-a"})
+	if !errors.Is(err, ErrActionApprovalQuorumPending) {
+		t.Fatalf("expected quorum pending after first approval, got %v", err)
+	}
+	if first.Status != "pending" || len(first.Signoffs) != 1 || first.Signoffs[0].UserID != "admin-a" {
+		t.Fatalf("unexpected record after first approval: %+v", first)
+	}
+	if _, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: guarded.ID, ApproverUserID: "admin-a"}); !errors.Is(err, ErrActionApprovalAlreadySigned) {
+		t.Fatalf("expected duplicate approver to be rejected, got %v", err)
+	}
+
+	pending, err := sqlStore.ListPendingActionApprovals(ctx, "telegram", "42", 10)
+	if err != nil {
+		t.Fatalf("list pending: %v", err)
+	}
+	if len(pending) != 2 || len(pending[0].Signoffs) != 1 {
+		t.Fatalf("expected pending listing to carry signoffs, got %+v", pending)
+	}
+
+	approved, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: guarded.ID, ApproverUserID: "admin-b"})
+	if err != nil {
+		t.Fatalf("second approval: %v", err)
+	}
+	if approved.Status != "approved" || approved.ApproverUserID != "admin-b" || len(approved.Signoffs) != 2 {
+		t.Fatalf("unexpected record after quorum: %+v", approved)
+	}
+	if approved.Signoffs[0].ApprovedAt.IsZero() || approved.Signoffs[1].ApprovedAt.IsZero() {
+		t.Fatalf("expected per-approver timestamps, got %+v", approved.Signoffs)
+	}
+
+	if _, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: plain.ID, ApproverUserID: "system:agent"}); err != nil {
+		t.Fatalf("single approval should still accept system approvers: %v", err)
+	}
+}
+
+func TestConcurrentSignoffsReachQuorum(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "quorum.sqlite")
+	// Two stores on one file stand in for two admins' requests served over
+	// separate connections.
+	stores := make([]*Store, 2)
+	for index := range stores {
+		opened, err := New(dbPath)
+		if err != nil {
+			t.Fatalf("open store %d: %v", index, err)
+		}
+		t.Cleanup(func() { _ = opened.Close() })
+		if err := opened.AutoMigrate(ctx); err != nil {
+			t.Fatalf("migrate store %d: %v", index, err)
+		}
+		opened.SetActionApprovalQuorumPolicy(fixedApprovalQuorum{"send_email": 2})
+		stores[index] = opened
+	}
+	sqlStore := stores[0]
+
+	for round := range 50 {
+		approval, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+			WorkspaceID: "ws-1", ContextID: "ctx-1", Connector: "telegram", ExternalID: "42",
+			RequesterUserID: "user-1", ActionType: "send_email",
+		})
+		if err != nil {
+			t.Fatalf("round %d: create approval: %v", round, err)
+		}
+		var wg sync.WaitGroup
+		for index, admin := range []string{"admin-a", "admin-b"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = stores[index].ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: approval.ID, ApproverUserID: admin})
+			}()
+		}
+		wg.Wait()
+		record, err := sqlStore.LookupActionApproval(ctx, approval.ID)
+		if err != nil || record.Status != "approved" || len(record.Signoffs) != 2 {
+			t.Fatalf("round %d: expected two concurrent signoffs to approve, got %+v (%v)", round, record, err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"strings"
+)
+
+// Risk levels stored on action approvals, lowest first.
+const (
+	ActionRiskLow    = "low"
+	ActionRiskMedium = "medium"
+	ActionRiskHigh   = "high"
+)
+
+// ActionApprovalRiskClassifier rates a new action before it is stored, so
+// admins can handle the riskiest pending actions first. It returns one of
+// the ActionRisk levels and a short reason.
+type ActionApprovalRiskClassifier interface {
+	ClassifyActionRisk(ctx context.Context, record ActionApproval) (level, reason string)
+}
+
+func (s *Store) SetActionApprovalRiskClassifier(classifier ActionApprovalRiskClassifier) {
+	s.approvalRisk = classifier
+}
+
+// NormalizeActionRisk returns the risk level named by value, or "" when it is
+// not one.
+func NormalizeActionRisk(value string) string {
+	switch level := strings.ToLower(strings.TrimSpace(value)); level {
+	case ActionRiskLow, ActionRiskMedium, ActionRiskHigh:
+		return level
+	default:
+		return ""
+	}
+}
+
+// ActionRiskRank orders risk levels for comparisons; unknown levels rank
+// below low.
+func ActionRiskRank(level string) int {
+	switch NormalizeActionRisk(level) {
+	case ActionRiskLow:
+		return 1
+	case ActionRiskMedium:
+		return 2
+	case ActionRiskHigh:
+		return 3
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+type fixedRiskClassifier struct {
+	level, reason string
+}
+
+func (f fixedRiskClassifier) ClassifyActionRisk(ctx context.Context, record ActionApproval) (string, string) {
+	return f.level, f.reason
+}
+
+func TestCreateActionApprovalStoresRisk(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	create := func() ActionApproval {
+		t.Helper()
+		approval, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+			WorkspaceID:     "ws-1",
+			ContextID:       "ctx-1",
+			Connector:       "telegram",
+			ExternalID:      "42",
+			RequesterUserID: "user-1",
+			ActionType:      "run_command",
+			ActionTarget:    "rm",
+			ActionSummary:   "clean old logs",
+		})
+		if err != nil {
+			t.Fatalf("create approval: %v", err)
+		}
+		return approval
+	}
+
+	unrated := create()
+	if unrated.RiskLevel != "" {
+		t.Fatalf("expected no risk without a classifier, got %q", unrated.RiskLevel)
+	}
+
+	sqlStore.SetActionApprovalRiskClassifier(fixedRiskClassifier{level: " HIGH ", reason: "destructive command rm"})
+	rated := create()
+	loaded, err := sqlStore.LookupActionApproval(ctx, rated.ID)
+	if err != nil {
+		t.Fatalf("lookup approval: %v", err)
+	}
+	if loaded.RiskLevel != ActionRiskHigh || loaded.RiskReason != "destructive command rm" {
+		t.Fatalf("expected stored high risk, got %q (%q)", loaded.RiskLevel, loaded.RiskReason)
+	}
+	pending, err := sqlStore.ListPendingActionApprovals(ctx, "telegram", "42", 10)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("list pending: %v (%d)", err, len(pending))
+	}
+	results, err := sqlStore.Search(ctx, SearchInput{Query: "old logs", Kinds: []SearchKind{SearchKindApproval}})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	risks := map[string]string{}
+	for _, result := range results {
+		risks[result.ID] = result.Risk
+	}
+	if risks[rated.ID] != ActionRiskHigh || risks[unrated.ID] != "" {
+		t.Fatalf("expected search results to carry risk, got %+v", risks)
+	}
+
+	sqlStore.SetActionApprovalRiskClassifier(fixedRiskClassifier{level: "catastrophic", reason: "bogus"})
+	if bogus := create(); bogus.RiskLevel != "" || bogus.RiskReason != "" {
+		t.Fatalf("expected unknown levels to be dropped, got %q (%q)", bogus.RiskLevel, bogus.RiskReason)
+	}
+}
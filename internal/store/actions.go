@@ -17,6 +17,29 @@ var (
 	ErrActionApprovalNotReady = errors.New("action approval is not pending")
 )
 
+// ActionApprovalNotifier is told about every new action approval, whichever
+// connector or tool created it.
+type ActionApprovalNotifier interface {
+	NotifyActionApprovalCreated(ctx context.Context, approval ActionApproval)
+}
+
+// ActionApprovalDecisionNotifier is implemented by notifiers that also want
+// to hear when a pending approval is approved, denied or expires, e.g. to
+// update the alerts they sent about it.
+type ActionApprovalDecisionNotifier interface {
+	NotifyActionApprovalDecided(ctx context.Context, approval ActionApproval)
+}
+
+func (s *Store) SetActionApprovalNotifier(notifier ActionApprovalNotifier) {
+	s.approvals = notifier
+}
+
+func (s *Store) notifyActionApprovalDecided(ctx context.Context, record ActionApproval) {
+	if notifier, ok := s.approvals.(ActionApprovalDecisionNotifier); ok {
+		notifier.NotifyActionApprovalDecided(ctx, record)
+	}
+}
+
 type CreateActionApprovalInput struct {
 	WorkspaceID     string
 	ContextID       string
@@ -49,6 +72,18 @@ type ActionApproval struct {
 	ExecutedAt       time.Time
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
+	// ExpiresAt is when a pending approval is denied automatically; zero
+	// means it never expires.
+	ExpiresAt time.Time
+	// RequiredApprovals is how many distinct admins must approve before the
+	// action runs; Signoffs lists those who have, oldest first.
+	RequiredApprovals int
+	Signoffs          []ApprovalSignoff
+	// RiskLevel is ActionRiskLow, ActionRiskMedium or ActionRiskHigh as
+	// judged when the approval was created, with RiskReason saying why.
+	// Both are empty when no classifier is configured.
+	RiskLevel  string
+	RiskReason string
 }
 
 type ApproveActionApprovalInput struct {
@@ -82,30 +117,56 @@ func (s *Store) CreateActionApproval(ctx context.Context, input CreateActionAppr
 	}
 
 	record := ActionApproval{
-		ID:              "act_" + uuid.NewString(),
-		WorkspaceID:     strings.TrimSpace(input.WorkspaceID),
-		ContextID:       strings.TrimSpace(input.ContextID),
-		Connector:       strings.ToLower(strings.TrimSpace(input.Connector)),
-		ExternalID:      strings.TrimSpace(input.ExternalID),
-		RequesterUserID: strings.TrimSpace(input.RequesterUserID),
-		ActionType:      strings.TrimSpace(input.ActionType),
-		ActionTarget:    strings.TrimSpace(input.ActionTarget),
-		ActionSummary:   strings.TrimSpace(input.ActionSummary),
-		Payload:         payload,
-		Status:          "pending",
-		ExecutionStatus: "not_executed",
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:                "act_" + uuid.NewString(),
+		WorkspaceID:       strings.TrimSpace(input.WorkspaceID),
+		ContextID:         strings.TrimSpace(input.ContextID),
+		Connector:         strings.ToLower(strings.TrimSpace(input.Connector)),
+		ExternalID:        strings.TrimSpace(input.ExternalID),
+		RequesterUserID:   strings.TrimSpace(input.RequesterUserID),
+		ActionType:        strings.TrimSpace(input.ActionType),
+		ActionTarget:      strings.TrimSpace(input.ActionTarget),
+		ActionSummary:     strings.TrimSpace(input.ActionSummary),
+		Payload:           payload,
+		Status:            "pending",
+		ExecutionStatus:   "not_executed",
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		RequiredApprovals: 1,
 	}
 	if record.WorkspaceID == "" || record.ContextID == "" || record.Connector == "" || record.ExternalID == "" || record.RequesterUserID == "" || record.ActionType == "" {
 		return ActionApproval{}, fmt.Errorf("missing required action approval fields")
 	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, nil); err != nil {
+		return ActionApproval{}, err
+	}
+	if err := s.checkQuota(ctx, record.WorkspaceID, QuotaActions); err != nil {
+		return ActionApproval{}, err
+	}
+	expiresAtUnix := int64(0)
+	if s.approvalTTL != nil {
+		if ttl := s.approvalTTL.ActionApprovalTTL(record.ActionType); ttl > 0 {
+			record.ExpiresAt = now.Add(ttl)
+			expiresAtUnix = record.ExpiresAt.Unix()
+		}
+	}
+	if s.approvalQuorum != nil {
+		if required := s.approvalQuorum.RequiredApprovals(record); required > 1 {
+			record.RequiredApprovals = required
+		}
+	}
+	if s.approvalRisk != nil {
+		record.RiskLevel, record.RiskReason = s.approvalRisk.ClassifyActionRisk(ctx, record)
+		record.RiskLevel = NormalizeActionRisk(record.RiskLevel)
+		if record.RiskLevel == "" {
+			record.RiskReason = ""
+		}
+	}
 
 	if _, err := s.db.ExecContext(
 		ctx,
 		`INSERT INTO action_approvals (
-			id, workspace_id, context_id, connector, external_id, requester_user_id, action_type, action_target, action_summary, payload_json, status, execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, workspace_id, context_id, connector, external_id, requester_user_id, action_type, action_target, action_summary, payload_json, status, execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix, expires_at_unix, required_approvals, risk_level, risk_reason
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		record.ID,
 		record.WorkspaceID,
 		record.ContextID,
@@ -123,9 +184,29 @@ func (s *Store) CreateActionApproval(ctx context.Context, input CreateActionAppr
 		nil,
 		record.CreatedAt.Unix(),
 		record.UpdatedAt.Unix(),
+		nullIfZeroInt64(expiresAtUnix),
+		record.RequiredApprovals,
+		record.RiskLevel,
+		record.RiskReason,
 	); err != nil {
 		return ActionApproval{}, fmt.Errorf("insert action approval: %w", err)
 	}
+	if s.approvals != nil {
+		s.approvals.NotifyActionApprovalCreated(ctx, record)
+	}
+	s.publishEvent(ctx, "approval.pending", record.WorkspaceID, map[string]any{
+		"approval_id":        record.ID,
+		"context_id":         record.ContextID,
+		"connector":          record.Connector,
+		"external_id":        record.ExternalID,
+		"requester_user_id":  record.RequesterUserID,
+		"action_type":        record.ActionType,
+		"action_target":      record.ActionTarget,
+		"action_summary":     record.ActionSummary,
+		"required_approvals": record.RequiredApprovals,
+		"risk_level":         record.RiskLevel,
+		"expires_at_unix":    expiresAtUnix,
+	})
 	return record, nil
 }
 
@@ -136,7 +217,7 @@ func (s *Store) ListPendingActionApprovals(ctx context.Context, connector, exter
 	rows, err := s.db.QueryContext(
 		ctx,
 		`SELECT id, workspace_id, context_id, connector, external_id, requester_user_id, action_type, action_target, action_summary, payload_json, status, approver_user_id, denied_reason
-		 , execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix
+		 , execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix, expires_at_unix, required_approvals, risk_level, risk_reason
 		 FROM action_approvals
 		 WHERE connector = ? AND external_id = ? AND status = 'pending'
 		 ORDER BY created_at_unix ASC
@@ -158,22 +239,32 @@ func (s *Store) ListPendingActionApprovals(ctx context.Context, connector, exter
 		}
 		results = append(results, record)
 	}
-	return results, nil
+	rows.Close()
+	return s.attachApprovalSignoffs(ctx, results)
 }
 
+// ListPendingActionApprovalsGlobal lists pending approvals of every
+// workspace, or only of the scoped workspace for scoped callers.
 func (s *Store) ListPendingActionApprovalsGlobal(ctx context.Context, limit int) ([]ActionApproval, error) {
 	if limit < 1 {
 		limit = 10
 	}
+	workspaceFilter := ""
+	args := []any{}
+	if scope, ok := WorkspaceScopeFromContext(ctx); ok {
+		workspaceFilter = " AND workspace_id = ?"
+		args = append(args, scope)
+	}
+	args = append(args, limit)
 	rows, err := s.db.QueryContext(
 		ctx,
 		`SELECT id, workspace_id, context_id, connector, external_id, requester_user_id, action_type, action_target, action_summary, payload_json, status, approver_user_id, denied_reason
-		 , execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix
+		 , execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix, expires_at_unix, required_approvals, risk_level, risk_reason
 		 FROM action_approvals
-		 WHERE status = 'pending'
+		 WHERE status = 'pending'`+workspaceFilter+`
 		 ORDER BY created_at_unix ASC
 		 LIMIT ?`,
-		limit,
+		args...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("query global pending action approvals: %w", err)
@@ -188,14 +279,90 @@ func (s *Store) ListPendingActionApprovalsGlobal(ctx context.Context, limit int)
 		}
 		results = append(results, record)
 	}
-	return results, nil
+	rows.Close()
+	return s.attachApprovalSignoffs(ctx, results)
+}
+
+// ListActionApprovalsInput filters ListActionApprovals. Status defaults to
+// pending; "all" lists every status. An empty WorkspaceID lists every
+// workspace the caller may see.
+type ListActionApprovalsInput struct {
+	WorkspaceID string
+	Status      string
+	Limit       int
+}
+
+// ListActionApprovals lists approvals by status: pending ones oldest first
+// so they read as a queue, decided ones most recently updated first.
+func (s *Store) ListActionApprovals(ctx context.Context, input ListActionApprovalsInput) ([]ActionApproval, error) {
+	limit := input.Limit
+	if limit < 1 {
+		limit = 50
+	}
+	workspaceID, err := scopedWorkspaceFilter(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	status := strings.ToLower(strings.TrimSpace(input.Status))
+	if status == "" {
+		status = "pending"
+	}
+	where := []string{}
+	args := []any{}
+	if workspaceID != "" {
+		where = append(where, "workspace_id = ?")
+		args = append(args, workspaceID)
+	}
+	order := "created_at_unix ASC"
+	switch status {
+	case "all":
+		order = "updated_at_unix DESC"
+	case "pending", "approved", "denied":
+		where = append(where, "status = ?")
+		args = append(args, status)
+		if status != "pending" {
+			order = "updated_at_unix DESC"
+		}
+	default:
+		return nil, fmt.Errorf("unknown approval status %q (use pending, approved, denied or all)", status)
+	}
+	filter := ""
+	if len(where) > 0 {
+		filter = "WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, limit)
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, workspace_id, context_id, connector, external_id, requester_user_id, action_type, action_target, action_summary, payload_json, status, approver_user_id, denied_reason
+		 , execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix, expires_at_unix, required_approvals, risk_level, risk_reason
+		 FROM action_approvals
+		 `+filter+`
+		 ORDER BY `+order+`, id ASC
+		 LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query action approvals: %w", err)
+	}
+	defer rows.Close()
+
+	results := []ActionApproval{}
+	for rows.Next() {
+		record, scanErr := scanActionApproval(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		results = append(results, record)
+	}
+	rows.Close()
+	return s.attachApprovalSignoffs(ctx, results)
 }
 
 func (s *Store) LookupActionApproval(ctx context.Context, id string) (ActionApproval, error) {
 	row := s.db.QueryRowContext(
 		ctx,
 		`SELECT id, workspace_id, context_id, connector, external_id, requester_user_id, action_type, action_target, action_summary, payload_json, status, approver_user_id, denied_reason
-		 , execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix
+		 , execution_status, execution_message, executor_plugin, executed_at_unix, created_at_unix, updated_at_unix, expires_at_unix, required_approvals, risk_level, risk_reason
 		 FROM action_approvals
 		 WHERE id = ?`,
 		strings.TrimSpace(id),
@@ -207,6 +374,13 @@ func (s *Store) LookupActionApproval(ctx context.Context, id string) (ActionAppr
 		}
 		return ActionApproval{}, err
 	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, ErrActionApprovalNotFound); err != nil {
+		return ActionApproval{}, err
+	}
+	record.Signoffs, err = listApprovalSignoffs(ctx, s.db, record.ID)
+	if err != nil {
+		return ActionApproval{}, err
+	}
 	return record, nil
 }
 
@@ -219,18 +393,69 @@ func (s *Store) ApproveActionApproval(ctx context.Context, input ApproveActionAp
 		return ActionApproval{}, ErrActionApprovalNotReady
 	}
 	now := time.Now().UTC()
-	if _, err := s.db.ExecContext(
+	if !record.ExpiresAt.IsZero() && !now.Before(record.ExpiresAt) {
+		return ActionApproval{}, fmt.Errorf("%w: expired at %s", ErrActionApprovalNotReady, record.ExpiresAt.Format(time.RFC3339))
+	}
+	approverUserID := strings.TrimSpace(input.ApproverUserID)
+	if record.RequiredApprovals > 1 && strings.HasPrefix(approverUserID, "system:") {
+		return record, fmt.Errorf("%w: %d of %d admin approvals", ErrActionApprovalQuorumPending, len(record.Signoffs), record.RequiredApprovals)
+	}
+	// The signoff, the recount and the status change share one transaction,
+	// so two admins approving at once cannot both count themselves short of
+	// the quorum.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ActionApproval{}, fmt.Errorf("begin action approval: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var status string
+	if err := tx.QueryRowContext(ctx, `SELECT status FROM action_approvals WHERE id = ?`, record.ID).Scan(&status); err != nil {
+		return ActionApproval{}, fmt.Errorf("recheck action approval: %w", err)
+	}
+	if status != "pending" {
+		return ActionApproval{}, ErrActionApprovalNotReady
+	}
+	signErr := addApprovalSignoff(ctx, tx, record.ID, approverUserID, now)
+	if signErr != nil && !errors.Is(signErr, ErrActionApprovalAlreadySigned) {
+		return ActionApproval{}, signErr
+	}
+	record.Signoffs, err = listApprovalSignoffs(ctx, tx, record.ID)
+	if err != nil {
+		return ActionApproval{}, err
+	}
+	if signErr != nil {
+		return record, fmt.Errorf("%w: %d of %d admin approvals", signErr, len(record.Signoffs), record.RequiredApprovals)
+	}
+	if len(record.Signoffs) < record.RequiredApprovals {
+		if _, err := tx.ExecContext(ctx, `UPDATE action_approvals SET updated_at_unix = ? WHERE id = ?`, now.Unix(), record.ID); err != nil {
+			return ActionApproval{}, fmt.Errorf("touch action approval: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return ActionApproval{}, fmt.Errorf("commit action approval: %w", err)
+		}
+		record.UpdatedAt = now
+		return record, fmt.Errorf("%w: %d of %d admin approvals", ErrActionApprovalQuorumPending, len(record.Signoffs), record.RequiredApprovals)
+	}
+	result, err := tx.ExecContext(
 		ctx,
-		`UPDATE action_approvals SET status = 'approved', approver_user_id = ?, updated_at_unix = ? WHERE id = ?`,
-		strings.TrimSpace(input.ApproverUserID),
+		`UPDATE action_approvals SET status = 'approved', approver_user_id = ?, updated_at_unix = ? WHERE id = ? AND status = 'pending'`,
+		approverUserID,
 		now.Unix(),
 		record.ID,
-	); err != nil {
+	)
+	if err != nil {
 		return ActionApproval{}, fmt.Errorf("approve action approval: %w", err)
 	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ActionApproval{}, ErrActionApprovalNotReady
+	}
+	if err := tx.Commit(); err != nil {
+		return ActionApproval{}, fmt.Errorf("commit action approval: %w", err)
+	}
 	record.Status = "approved"
-	record.ApproverUserID = strings.TrimSpace(input.ApproverUserID)
+	record.ApproverUserID = approverUserID
 	record.UpdatedAt = now
+	s.notifyActionApprovalDecided(ctx, record)
 	return record, nil
 }
 
@@ -247,20 +472,25 @@ func (s *Store) DenyActionApproval(ctx context.Context, input DenyActionApproval
 	if reason == "" {
 		reason = "denied by admin"
 	}
-	if _, err := s.db.ExecContext(
+	result, err := s.db.ExecContext(
 		ctx,
-		`UPDATE action_approvals SET status = 'denied', approver_user_id = ?, denied_reason = ?, updated_at_unix = ? WHERE id = ?`,
+		`UPDATE action_approvals SET status = 'denied', approver_user_id = ?, denied_reason = ?, updated_at_unix = ? WHERE id = ? AND status = 'pending'`,
 		strings.TrimSpace(input.ApproverUserID),
 		reason,
 		now.Unix(),
 		record.ID,
-	); err != nil {
+	)
+	if err != nil {
 		return ActionApproval{}, fmt.Errorf("deny action approval: %w", err)
 	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ActionApproval{}, ErrActionApprovalNotReady
+	}
 	record.Status = "denied"
 	record.ApproverUserID = strings.TrimSpace(input.ApproverUserID)
 	record.DeniedReason = reason
 	record.UpdatedAt = now
+	s.notifyActionApprovalDecided(ctx, record)
 	return record, nil
 }
 
@@ -297,6 +527,16 @@ func (s *Store) UpdateActionExecution(ctx context.Context, input UpdateActionExe
 	record.ExecutorPlugin = strings.TrimSpace(input.ExecutorPlugin)
 	record.ExecutedAt = executedAt
 	record.UpdatedAt = now
+	s.publishEvent(ctx, "approval.executed", record.WorkspaceID, map[string]any{
+		"approval_id":       record.ID,
+		"context_id":        record.ContextID,
+		"action_type":       record.ActionType,
+		"action_target":     record.ActionTarget,
+		"approver_user_id":  record.ApproverUserID,
+		"execution_status":  record.ExecutionStatus,
+		"execution_message": record.ExecutionMessage,
+		"executor_plugin":   record.ExecutorPlugin,
+	})
 	return record, nil
 }
 
@@ -314,6 +554,8 @@ func scanActionApproval(scanner actionApprovalScanner) (ActionApproval, error) {
 	var executedAtUnix sql.NullInt64
 	var createdAtUnix int64
 	var updatedAtUnix int64
+	var expiresAtUnix sql.NullInt64
+	var requiredApprovals sql.NullInt64
 	err := scanner.Scan(
 		&record.ID,
 		&record.WorkspaceID,
@@ -334,10 +576,21 @@ func scanActionApproval(scanner actionApprovalScanner) (ActionApproval, error) {
 		&executedAtUnix,
 		&createdAtUnix,
 		&updatedAtUnix,
+		&expiresAtUnix,
+		&requiredApprovals,
+		&record.RiskLevel,
+		&record.RiskReason,
 	)
 	if err != nil {
 		return ActionApproval{}, err
 	}
+	record.RequiredApprovals = 1
+	if requiredApprovals.Valid && requiredApprovals.Int64 > 1 {
+		record.RequiredApprovals = int(requiredApprovals.Int64)
+	}
+	if expiresAtUnix.Valid && expiresAtUnix.Int64 > 0 {
+		record.ExpiresAt = time.Unix(expiresAtUnix.Int64, 0).UTC()
+	}
 	record.ApproverUserID = approver.String
 	record.DeniedReason = deniedReason.String
 	record.ExecutionMessage = executionMessage.String
@@ -185,3 +185,43 @@ func TestListPendingActionApprovalsGlobal(t *testing.T) {
 		t.Fatalf("unexpected pending action source: %s/%s", pending[0].Connector, pending[0].ExternalID)
 	}
 }
+
+func TestListActionApprovalsByStatus(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	ids := []string{}
+	for _, workspaceID := range []string{"ws-1", "ws-1", "ws-2"} {
+		record, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+			WorkspaceID:     workspaceID,
+			ContextID:       "ctx-1",
+			Connector:       "telegram",
+			ExternalID:      "42",
+			RequesterUserID: "user-1",
+			ActionType:      "send_email",
+		})
+		if err != nil {
+			t.Fatalf("create action approval: %v", err)
+		}
+		ids = append(ids, record.ID)
+	}
+	if _, err := sqlStore.DenyActionApproval(ctx, DenyActionApprovalInput{ID: ids[1], ApproverUserID: "admin-1"}); err != nil {
+		t.Fatalf("deny action approval: %v", err)
+	}
+
+	pending, err := sqlStore.ListActionApprovals(ctx, ListActionApprovalsInput{WorkspaceID: "ws-1"})
+	if err != nil || len(pending) != 1 || pending[0].ID != ids[0] {
+		t.Fatalf("expected the one pending ws-1 approval, got %+v (%v)", pending, err)
+	}
+	denied, err := sqlStore.ListActionApprovals(ctx, ListActionApprovalsInput{Status: "denied"})
+	if err != nil || len(denied) != 1 || denied[0].ID != ids[1] || denied[0].DeniedReason != "denied by admin" {
+		t.Fatalf("expected the denied approval, got %+v (%v)", denied, err)
+	}
+	all, err := sqlStore.ListActionApprovals(ctx, ListActionApprovalsInput{Status: "all"})
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected every approval, got %d (%v)", len(all), err)
+	}
+	if _, err := sqlStore.ListActionApprovals(ctx, ListActionApprovalsInput{Status: "stuck"}); err == nil {
+		t.Fatal("expected unknown status to be rejected")
+	}
+}
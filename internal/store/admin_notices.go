@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AdminNotice is one copy of an alert sent to an admin channel. The same
+// alert is mirrored to every admin channel under one alert key, so handling
+// it in one place can mark and edit all the other copies.
+type AdminNotice struct {
+	AlertKey   string
+	Connector  string
+	ExternalID string
+	MessageID  string
+	CreatedAt  time.Time
+	HandledAt  time.Time
+	HandledBy  string
+}
+
+type RecordAdminNoticeInput struct {
+	AlertKey   string
+	Connector  string
+	ExternalID string
+	MessageID  string
+}
+
+// RecordAdminNotice remembers a sent alert copy. Sending the same alert to
+// the same channel again replaces the remembered message.
+func (s *Store) RecordAdminNotice(ctx context.Context, input RecordAdminNoticeInput) error {
+	alertKey := strings.TrimSpace(input.AlertKey)
+	connector := strings.ToLower(strings.TrimSpace(input.Connector))
+	externalID := strings.TrimSpace(input.ExternalID)
+	messageID := strings.TrimSpace(input.MessageID)
+	if alertKey == "" || connector == "" || externalID == "" || messageID == "" {
+		return fmt.Errorf("alert key, connector, external id and message id are required")
+	}
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO admin_notices (alert_key, connector, external_id, message_id, created_at_unix)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(alert_key, connector, external_id) DO UPDATE SET message_id = excluded.message_id`,
+		alertKey,
+		connector,
+		externalID,
+		messageID,
+		time.Now().UTC().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("record admin notice: %w", err)
+	}
+	return nil
+}
+
+// ListAdminNotices returns every copy of an alert, handled or not.
+func (s *Store) ListAdminNotices(ctx context.Context, alertKey string) ([]AdminNotice, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT alert_key, connector, external_id, message_id, created_at_unix, handled_at_unix, handled_by
+		 FROM admin_notices
+		 WHERE alert_key = ?
+		 ORDER BY created_at_unix ASC, connector ASC, external_id ASC`,
+		strings.TrimSpace(alertKey),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list admin notices: %w", err)
+	}
+	defer rows.Close()
+
+	notices := []AdminNotice{}
+	for rows.Next() {
+		var (
+			notice        AdminNotice
+			createdAtUnix int64
+			handledAtUnix sql.NullInt64
+		)
+		if err := rows.Scan(&notice.AlertKey, &notice.Connector, &notice.ExternalID, &notice.MessageID, &createdAtUnix, &handledAtUnix, &notice.HandledBy); err != nil {
+			return nil, fmt.Errorf("scan admin notice: %w", err)
+		}
+		notice.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+		if handledAtUnix.Valid {
+			notice.HandledAt = time.Unix(handledAtUnix.Int64, 0).UTC()
+		}
+		notices = append(notices, notice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate admin notices: %w", err)
+	}
+	return notices, nil
+}
+
+// MarkAdminNoticesHandled acknowledges every copy of an alert and returns the
+// copies this call marked. Copies already handled are left alone, so a second
+// acknowledgement of the same alert returns nothing.
+func (s *Store) MarkAdminNoticesHandled(ctx context.Context, alertKey, handledBy string) ([]AdminNotice, error) {
+	alertKey = strings.TrimSpace(alertKey)
+	handledBy = strings.TrimSpace(handledBy)
+	notices, err := s.ListAdminNotices(ctx, alertKey)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	marked := make([]AdminNotice, 0, len(notices))
+	for _, notice := range notices {
+		if !notice.HandledAt.IsZero() {
+			continue
+		}
+		result, err := s.db.ExecContext(
+			ctx,
+			`UPDATE admin_notices SET handled_at_unix = ?, handled_by = ?
+			 WHERE alert_key = ? AND connector = ? AND external_id = ? AND handled_at_unix IS NULL`,
+			now.Unix(),
+			handledBy,
+			notice.AlertKey,
+			notice.Connector,
+			notice.ExternalID,
+		)
+		if err != nil {
+			return marked, fmt.Errorf("mark admin notice handled: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			continue
+		}
+		notice.HandledAt = now
+		notice.HandledBy = handledBy
+		marked = append(marked, notice)
+	}
+	return marked, nil
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type recordingDecisionNotifier struct {
+	created []ActionApproval
+	decided []ActionApproval
+}
+
+func (r *recordingDecisionNotifier) NotifyActionApprovalCreated(ctx context.Context, approval ActionApproval) {
+	r.created = append(r.created, approval)
+}
+
+func (r *recordingDecisionNotifier) NotifyActionApprovalDecided(ctx context.Context, approval ActionApproval) {
+	r.decided = append(r.decided, approval)
+}
+
+func TestMarkAdminNoticesHandledMarksEveryCopyOnce(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	for _, input := range []RecordAdminNoticeInput{
+		{AlertKey: "approval:act_1", Connector: "telegram", ExternalID: "42", MessageID: "7"},
+		{AlertKey: "approval:act_1", Connector: "Discord", ExternalID: "chan-1", MessageID: "msg-1"},
+		{AlertKey: "approval:act_2", Connector: "telegram", ExternalID: "42", MessageID: "8"},
+	} {
+		if err := sqlStore.RecordAdminNotice(ctx, input); err != nil {
+			t.Fatalf("record admin notice: %v", err)
+		}
+	}
+	if err := sqlStore.RecordAdminNotice(ctx, RecordAdminNoticeInput{AlertKey: "approval:act_1", Connector: "telegram", ExternalID: "42"}); err == nil {
+		t.Fatal("expected a notice without message id to be rejected")
+	}
+
+	marked, err := sqlStore.MarkAdminNoticesHandled(ctx, "approval:act_1", "admin-1")
+	if err != nil {
+		t.Fatalf("mark handled: %v", err)
+	}
+	if len(marked) != 2 || marked[0].HandledBy != "admin-1" || marked[0].HandledAt.IsZero() {
+		t.Fatalf("expected both copies marked, got %+v", marked)
+	}
+	again, err := sqlStore.MarkAdminNoticesHandled(ctx, "approval:act_1", "admin-2")
+	if err != nil || len(again) != 0 {
+		t.Fatalf("expected a second acknowledgement to mark nothing, got %+v (%v)", again, err)
+	}
+	other, err := sqlStore.ListAdminNotices(ctx, "approval:act_2")
+	if err != nil || len(other) != 1 || !other[0].HandledAt.IsZero() {
+		t.Fatalf("expected other alerts untouched, got %+v (%v)", other, err)
+	}
+}
+
+func TestActionApprovalDecisionsNotifyOnceAndRefuseSecondDecision(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	notifier := &recordingDecisionNotifier{}
+	sqlStore.SetActionApprovalNotifier(notifier)
+
+	approval, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+		WorkspaceID:     "ws-1",
+		ContextID:       "ctx-1",
+		Connector:       "telegram",
+		ExternalID:      "42",
+		RequesterUserID: "user-1",
+		ActionType:      "run_command",
+	})
+	if err != nil {
+		t.Fatalf("create approval: %v", err)
+	}
+	if _, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: approval.ID, ApproverUserID: "admin-1"}); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := sqlStore.DenyActionApproval(ctx, DenyActionApprovalInput{ID: approval.ID, ApproverUserID: "admin-2"}); !errors.Is(err, ErrActionApprovalNotReady) {
+		t.Fatalf("expected deny after approve to be refused, got %v", err)
+	}
+	if _, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: approval.ID, ApproverUserID: "admin-2"}); !errors.Is(err, ErrActionApprovalNotReady) {
+		t.Fatalf("expected a second approval to be refused, got %v", err)
+	}
+	if len(notifier.created) != 1 || len(notifier.decided) != 1 {
+		t.Fatalf("expected one created and one decided notification, got %d/%d", len(notifier.created), len(notifier.decided))
+	}
+	if decided := notifier.decided[0]; decided.Status != "approved" || decided.ApproverUserID != "admin-1" {
+		t.Fatalf("unexpected decided approval %+v", decided)
+	}
+}
@@ -2,13 +2,17 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var ErrAgentAuditCursorInvalid = errors.New("audit cursor is invalid")
+
 type AgentAuditEvent struct {
 	ID           string
 	WorkspaceID  string
@@ -24,8 +28,14 @@ type AgentAuditEvent struct {
 	BlockReason  string
 	Message      string
 	CreatedAt    time.Time
+	// Seq, PrevHash and Hash place the event in the audit hash chain.
+	Seq      int64
+	PrevHash string
+	Hash     string
 }
 
+const agentAuditEventColumns = `id, workspace_id, context_id, connector, external_id, COALESCE(source_user_id, ''), event_type, stage, COALESCE(tool_name, ''), COALESCE(tool_class, ''), blocked, COALESCE(block_reason, ''), COALESCE(message, ''), created_at_unix, COALESCE(seq, 0), COALESCE(prev_hash, ''), COALESCE(hash, '')`
+
 type CreateAgentAuditEventInput struct {
 	WorkspaceID  string
 	ContextID    string
@@ -41,14 +51,36 @@ type CreateAgentAuditEventInput struct {
 	Message      string
 }
 
+// ListAgentAuditEventsInput filters audit events, newest first. Since and
+// Until bound created_at (Until is exclusive); Cursor continues after the
+// last event of a previous page.
 type ListAgentAuditEventsInput struct {
-	WorkspaceID string
-	ContextID   string
-	Connector   string
-	ExternalID  string
-	EventType   string
-	BlockedOnly bool
-	Limit       int
+	WorkspaceID  string
+	ContextID    string
+	Connector    string
+	ExternalID   string
+	SourceUserID string
+	EventType    string
+	ToolName     string
+	BlockedOnly  bool
+	Since        time.Time
+	Until        time.Time
+	Cursor       string
+	Limit        int
+}
+
+// AgentAuditCursor is the cursor that lists the events after event.
+func AgentAuditCursor(event AgentAuditEvent) string {
+	return fmt.Sprintf("%d.%s", event.CreatedAt.Unix(), event.ID)
+}
+
+func parseAgentAuditCursor(cursor string) (int64, string, error) {
+	unixText, id, ok := strings.Cut(strings.TrimSpace(cursor), ".")
+	createdUnix, err := strconv.ParseInt(unixText, 10, 64)
+	if !ok || err != nil || strings.TrimSpace(id) == "" {
+		return 0, "", ErrAgentAuditCursorInvalid
+	}
+	return createdUnix, id, nil
 }
 
 func (s *Store) CreateAgentAuditEvent(ctx context.Context, input CreateAgentAuditEventInput) (AgentAuditEvent, error) {
@@ -73,11 +105,24 @@ func (s *Store) CreateAgentAuditEvent(ctx context.Context, input CreateAgentAudi
 		return AgentAuditEvent{}, fmt.Errorf("missing required agent audit event fields")
 	}
 
-	if _, err := s.db.ExecContext(
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return AgentAuditEvent{}, fmt.Errorf("begin agent audit event: %w", err)
+	}
+	defer tx.Rollback()
+	headSeq, headHash, err := agentAuditChainHead(ctx, tx)
+	if err != nil {
+		return AgentAuditEvent{}, err
+	}
+	record.Seq = headSeq + 1
+	record.PrevHash = headHash
+	record.Hash = agentAuditHash(record)
+
+	if _, err := tx.ExecContext(
 		ctx,
 		`INSERT INTO agent_audit_events (
-			id, workspace_id, context_id, connector, external_id, source_user_id, event_type, stage, tool_name, tool_class, blocked, block_reason, message, created_at_unix
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, workspace_id, context_id, connector, external_id, source_user_id, event_type, stage, tool_name, tool_class, blocked, block_reason, message, created_at_unix, seq, prev_hash, hash
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		record.ID,
 		record.WorkspaceID,
 		record.ContextID,
@@ -92,9 +137,28 @@ func (s *Store) CreateAgentAuditEvent(ctx context.Context, input CreateAgentAudi
 		nullIfEmpty(record.BlockReason),
 		nullIfEmpty(record.Message),
 		record.CreatedAt.Unix(),
+		record.Seq,
+		nullIfEmpty(record.PrevHash),
+		record.Hash,
 	); err != nil {
 		return AgentAuditEvent{}, fmt.Errorf("insert agent audit event: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return AgentAuditEvent{}, fmt.Errorf("commit agent audit event: %w", err)
+	}
+	if record.Blocked {
+		s.publishEvent(ctx, "agent.blocked", record.WorkspaceID, map[string]any{
+			"audit_id":       record.ID,
+			"context_id":     record.ContextID,
+			"connector":      record.Connector,
+			"external_id":    record.ExternalID,
+			"source_user_id": record.SourceUserID,
+			"event_type":     record.EventType,
+			"tool_name":      record.ToolName,
+			"tool_class":     record.ToolClass,
+			"block_reason":   record.BlockReason,
+		})
+	}
 	return record, nil
 }
 
@@ -106,10 +170,14 @@ func (s *Store) ListAgentAuditEvents(ctx context.Context, input ListAgentAuditEv
 	if limit > 1000 {
 		limit = 1000
 	}
+	workspaceID, err := scopedWorkspaceFilter(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
 	whereParts := []string{"1=1"}
-	args := make([]any, 0, 8)
+	args := make([]any, 0, 14)
 
-	if workspaceID := strings.TrimSpace(input.WorkspaceID); workspaceID != "" {
+	if workspaceID != "" {
 		whereParts = append(whereParts, "workspace_id = ?")
 		args = append(args, workspaceID)
 	}
@@ -129,17 +197,41 @@ func (s *Store) ListAgentAuditEvents(ctx context.Context, input ListAgentAuditEv
 		whereParts = append(whereParts, "event_type = ?")
 		args = append(args, eventType)
 	}
+	if sourceUserID := strings.TrimSpace(input.SourceUserID); sourceUserID != "" {
+		whereParts = append(whereParts, "source_user_id = ?")
+		args = append(args, sourceUserID)
+	}
+	if toolName := strings.TrimSpace(input.ToolName); toolName != "" {
+		whereParts = append(whereParts, "tool_name = ?")
+		args = append(args, toolName)
+	}
 	if input.BlockedOnly {
 		whereParts = append(whereParts, "blocked = 1")
 	}
+	if !input.Since.IsZero() {
+		whereParts = append(whereParts, "created_at_unix >= ?")
+		args = append(args, input.Since.Unix())
+	}
+	if !input.Until.IsZero() {
+		whereParts = append(whereParts, "created_at_unix < ?")
+		args = append(args, input.Until.Unix())
+	}
+	if strings.TrimSpace(input.Cursor) != "" {
+		createdUnix, id, err := parseAgentAuditCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		whereParts = append(whereParts, "(created_at_unix < ? OR (created_at_unix = ? AND id < ?))")
+		args = append(args, createdUnix, createdUnix, id)
+	}
 	args = append(args, limit)
 
 	rows, err := s.db.QueryContext(
 		ctx,
-		`SELECT id, workspace_id, context_id, connector, external_id, COALESCE(source_user_id, ''), event_type, stage, COALESCE(tool_name, ''), COALESCE(tool_class, ''), blocked, COALESCE(block_reason, ''), COALESCE(message, ''), created_at_unix
+		`SELECT `+agentAuditEventColumns+`
 		 FROM agent_audit_events
 		 WHERE `+strings.Join(whereParts, " AND ")+`
-		 ORDER BY created_at_unix DESC
+		 ORDER BY created_at_unix DESC, id DESC
 		 LIMIT ?`,
 		args...,
 	)
@@ -150,32 +242,47 @@ func (s *Store) ListAgentAuditEvents(ctx context.Context, input ListAgentAuditEv
 
 	events := make([]AgentAuditEvent, 0, limit)
 	for rows.Next() {
-		var event AgentAuditEvent
-		var blocked int
-		var createdAtUnix int64
-		if err := rows.Scan(
-			&event.ID,
-			&event.WorkspaceID,
-			&event.ContextID,
-			&event.Connector,
-			&event.ExternalID,
-			&event.SourceUserID,
-			&event.EventType,
-			&event.Stage,
-			&event.ToolName,
-			&event.ToolClass,
-			&blocked,
-			&event.BlockReason,
-			&event.Message,
-			&createdAtUnix,
-		); err != nil {
+		event, err := scanAgentAuditEvent(rows)
+		if err != nil {
 			return nil, err
 		}
-		event.Blocked = blocked == 1
-		if createdAtUnix > 0 {
-			event.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
-		}
 		events = append(events, event)
 	}
-	return events, nil
+	return events, rows.Err()
+}
+
+type agentAuditEventScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAgentAuditEvent(scanner agentAuditEventScanner) (AgentAuditEvent, error) {
+	var event AgentAuditEvent
+	var blocked int
+	var createdAtUnix int64
+	if err := scanner.Scan(
+		&event.ID,
+		&event.WorkspaceID,
+		&event.ContextID,
+		&event.Connector,
+		&event.ExternalID,
+		&event.SourceUserID,
+		&event.EventType,
+		&event.Stage,
+		&event.ToolName,
+		&event.ToolClass,
+		&blocked,
+		&event.BlockReason,
+		&event.Message,
+		&createdAtUnix,
+		&event.Seq,
+		&event.PrevHash,
+		&event.Hash,
+	); err != nil {
+		return AgentAuditEvent{}, err
+	}
+	event.Blocked = blocked == 1
+	if createdAtUnix > 0 {
+		event.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+	}
+	return event, nil
 }
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Audit events form one hash chain in insertion order: each event stores a
+// sequence number, the hash of the event before it and its own hash over
+// both plus its fields. Editing, deleting or reordering a row breaks the
+// chain from that row on.
+
+// agentAuditChainProblemLimit caps the problems one verification reports.
+const agentAuditChainProblemLimit = 100
+
+// AgentAuditChainProblem is one place where the chain does not hold.
+type AgentAuditChainProblem struct {
+	Seq     int64
+	EventID string
+	Reason  string
+}
+
+// AgentAuditChainReport is the result of VerifyAgentAuditChain. HeadSeq and
+// HeadHash identify the newest event; record them elsewhere and pass the
+// hash back as an anchor to prove later that nothing up to it was cut.
+type AgentAuditChainReport struct {
+	Checked   int
+	HeadSeq   int64
+	HeadHash  string
+	AnchorSeq int64
+	Problems  []AgentAuditChainProblem
+}
+
+// OK reports whether the chain verified without problems.
+func (r AgentAuditChainReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// agentAuditHash hashes an event with the hash before it. Fields are JSON
+// encoded so no value can shift into its neighbour.
+func agentAuditHash(event AgentAuditEvent) string {
+	fields, _ := json.Marshal([]any{
+		event.Seq,
+		event.ID,
+		event.WorkspaceID,
+		event.ContextID,
+		event.Connector,
+		event.ExternalID,
+		event.SourceUserID,
+		event.EventType,
+		event.Stage,
+		event.ToolName,
+		event.ToolClass,
+		event.Blocked,
+		event.BlockReason,
+		event.Message,
+		event.CreatedAt.Unix(),
+	})
+	sum := sha256.Sum256(append([]byte(event.PrevHash+"\n"), fields...))
+	return hex.EncodeToString(sum[:])
+}
+
+// agentAuditChainHead returns the sequence number and hash of the newest
+// chained event, or zero values for an empty chain.
+func agentAuditChainHead(ctx context.Context, tx *sql.Tx) (int64, string, error) {
+	var seq int64
+	var hash string
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT seq, COALESCE(hash, '') FROM agent_audit_events WHERE seq IS NOT NULL ORDER BY seq DESC LIMIT 1`,
+	).Scan(&seq, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("read audit chain head: %w", err)
+	}
+	return seq, hash, nil
+}
+
+// chainAgentAuditEvents appends events written before hash chaining to the
+// chain, oldest first.
+func (s *Store) chainAgentAuditEvents(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin audit chain migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT `+agentAuditEventColumns+`
+		 FROM agent_audit_events
+		 WHERE seq IS NULL
+		 ORDER BY created_at_unix ASC, rowid ASC`,
+	)
+	if err != nil {
+		return fmt.Errorf("query unchained audit events: %w", err)
+	}
+	pending := []AgentAuditEvent{}
+	for rows.Next() {
+		event, err := scanAgentAuditEvent(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		pending = append(pending, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	seq, prevHash, err := agentAuditChainHead(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, event := range pending {
+		seq++
+		event.Seq = seq
+		event.PrevHash = prevHash
+		event.Hash = agentAuditHash(event)
+		if _, err := tx.ExecContext(
+			ctx,
+			`UPDATE agent_audit_events SET seq = ?, prev_hash = ?, hash = ? WHERE id = ?`,
+			event.Seq, nullIfEmpty(event.PrevHash), event.Hash, event.ID,
+		); err != nil {
+			return fmt.Errorf("chain audit event: %w", err)
+		}
+		prevHash = event.Hash
+	}
+	return tx.Commit()
+}
+
+// VerifyAgentAuditChain walks every audit event in sequence order and
+// checks that sequence numbers are contiguous from 1, that each event points
+// at the hash of the one before it and that its hash still matches its
+// contents. A non-empty anchor must be the hash of some event in the chain.
+func (s *Store) VerifyAgentAuditChain(ctx context.Context, anchor string) (AgentAuditChainReport, error) {
+	anchor = strings.ToLower(strings.TrimSpace(anchor))
+	report := AgentAuditChainReport{}
+	addProblem := func(event AgentAuditEvent, reason string) {
+		if len(report.Problems) < agentAuditChainProblemLimit {
+			report.Problems = append(report.Problems, AgentAuditChainProblem{Seq: event.Seq, EventID: event.ID, Reason: reason})
+		}
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT `+agentAuditEventColumns+`
+		 FROM agent_audit_events
+		 ORDER BY seq IS NULL, seq ASC, created_at_unix ASC`,
+	)
+	if err != nil {
+		return AgentAuditChainReport{}, fmt.Errorf("query audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	var lastSeq int64
+	lastHash := ""
+	for rows.Next() {
+		event, err := scanAgentAuditEvent(rows)
+		if err != nil {
+			return AgentAuditChainReport{}, err
+		}
+		report.Checked++
+		if event.Seq == 0 {
+			addProblem(event, "event is not in the chain")
+			continue
+		}
+		switch {
+		case event.Seq == lastSeq+1:
+		case event.Seq == lastSeq+2:
+			addProblem(event, fmt.Sprintf("event %d is missing", lastSeq+1))
+		default:
+			addProblem(event, fmt.Sprintf("events %d to %d are missing", lastSeq+1, event.Seq-1))
+		}
+		if event.PrevHash != lastHash {
+			addProblem(event, "previous hash does not match the event before it")
+		}
+		if agentAuditHash(event) != event.Hash {
+			addProblem(event, "contents changed after the event was written")
+		}
+		if anchor != "" && event.Hash == anchor {
+			report.AnchorSeq = event.Seq
+		}
+		lastSeq = event.Seq
+		lastHash = event.Hash
+	}
+	if err := rows.Err(); err != nil {
+		return AgentAuditChainReport{}, err
+	}
+	report.HeadSeq = lastSeq
+	report.HeadHash = lastHash
+	if anchor != "" && report.AnchorSeq == 0 {
+		report.Problems = append(report.Problems, AgentAuditChainProblem{Reason: "anchor hash is not in the chain; events up to it were removed or changed"})
+	}
+	return report, nil
+}
@@ -2,7 +2,9 @@ package store
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestCreateAndListAgentAuditEvents(t *testing.T) {
@@ -49,3 +51,136 @@ func TestCreateAndListAgentAuditEvents(t *testing.T) {
 		t.Fatal("expected blocked audit event")
 	}
 }
+
+func TestListAgentAuditEventsPagesAndFilters(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	for index, tool := range []string{"curl", "fetch_url", "curl", "write_file", "curl"} {
+		created, err := sqlStore.CreateAgentAuditEvent(ctx, CreateAgentAuditEventInput{
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Connector:   "discord",
+			ExternalID:  "chan-1",
+			EventType:   "egress_blocked",
+			Stage:       "audit.egress_blocked",
+			ToolName:    tool,
+			Blocked:     true,
+		})
+		if err != nil {
+			t.Fatalf("create audit event: %v", err)
+		}
+		// Spread the events over distinct seconds so the order is known.
+		if _, err := sqlStore.db.ExecContext(ctx, `UPDATE agent_audit_events SET created_at_unix = ? WHERE id = ?`, int64(1000+index), created.ID); err != nil {
+			t.Fatalf("backdate audit event: %v", err)
+		}
+	}
+
+	first, err := sqlStore.ListAgentAuditEvents(ctx, ListAgentAuditEventsInput{WorkspaceID: "ws-1", ToolName: "curl", Limit: 2})
+	if err != nil || len(first) != 2 || first[0].CreatedAt.Unix() != 1004 || first[1].CreatedAt.Unix() != 1002 {
+		t.Fatalf("expected the two newest curl events, got %+v (%v)", first, err)
+	}
+	rest, err := sqlStore.ListAgentAuditEvents(ctx, ListAgentAuditEventsInput{WorkspaceID: "ws-1", ToolName: "curl", Limit: 2, Cursor: AgentAuditCursor(first[1])})
+	if err != nil || len(rest) != 1 || rest[0].CreatedAt.Unix() != 1000 {
+		t.Fatalf("expected the oldest curl event on the next page, got %+v (%v)", rest, err)
+	}
+
+	window, err := sqlStore.ListAgentAuditEvents(ctx, ListAgentAuditEventsInput{
+		WorkspaceID: "ws-1",
+		Since:       time.Unix(1001, 0),
+		Until:       time.Unix(1003, 0),
+	})
+	if err != nil || len(window) != 2 || window[0].ToolName != "curl" || window[1].ToolName != "fetch_url" {
+		t.Fatalf("expected the events inside the window, got %+v (%v)", window, err)
+	}
+
+	if _, err := sqlStore.ListAgentAuditEvents(ctx, ListAgentAuditEventsInput{Cursor: "nonsense"}); !errors.Is(err, ErrAgentAuditCursorInvalid) {
+		t.Fatalf("expected an invalid cursor error, got %v", err)
+	}
+}
+
+func TestVerifyAgentAuditChainDetectsTampering(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	created := make([]AgentAuditEvent, 0, 4)
+	for _, tool := range []string{"curl", "write_file", "fetch_url", "curl"} {
+		event, err := sqlStore.CreateAgentAuditEvent(ctx, CreateAgentAuditEventInput{
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Connector:   "discord",
+			ExternalID:  "chan-1",
+			EventType:   "egress_blocked",
+			Stage:       "audit.egress_blocked",
+			ToolName:    tool,
+			Blocked:     true,
+		})
+		if err != nil {
+			t.Fatalf("create audit event: %v", err)
+		}
+		created = append(created, event)
+	}
+	if created[1].Seq != 2 || created[1].PrevHash != created[0].Hash {
+		t.Fatalf("expected the second event chained to the first, got %+v", created[1])
+	}
+
+	report, err := sqlStore.VerifyAgentAuditChain(ctx, created[1].Hash)
+	if err != nil || !report.OK() || report.Checked != 4 || report.HeadSeq != 4 || report.HeadHash != created[3].Hash || report.AnchorSeq != 2 {
+		t.Fatalf("expected an intact chain, got %+v (%v)", report, err)
+	}
+
+	if _, err := sqlStore.db.ExecContext(ctx, `UPDATE agent_audit_events SET blocked = 0 WHERE id = ?`, created[2].ID); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+	if _, err := sqlStore.db.ExecContext(ctx, `DELETE FROM agent_audit_events WHERE id = ?`, created[1].ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	report, err = sqlStore.VerifyAgentAuditChain(ctx, created[1].Hash)
+	if err != nil || report.OK() {
+		t.Fatalf("expected problems, got %+v (%v)", report, err)
+	}
+	reasons := map[string]bool{}
+	for _, problem := range report.Problems {
+		reasons[problem.Reason] = true
+	}
+	for _, reason := range []string{
+		"event 2 is missing",
+		"previous hash does not match the event before it",
+		"contents changed after the event was written",
+		"anchor hash is not in the chain; events up to it were removed or changed",
+	} {
+		if !reasons[reason] {
+			t.Fatalf("expected problem %q, got %+v", reason, report.Problems)
+		}
+	}
+}
+
+func TestAutoMigrateChainsExistingAuditEvents(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	for index, id := range []string{"audit_old_b", "audit_old_a"} {
+		if _, err := sqlStore.db.ExecContext(
+			ctx,
+			`INSERT INTO agent_audit_events (id, workspace_id, context_id, connector, external_id, event_type, stage, created_at_unix)
+			 VALUES (?, 'ws-1', 'ctx-1', 'discord', 'chan-1', 'approval_required', 'audit.approval_required', ?)`,
+			id, int64(1000+index),
+		); err != nil {
+			t.Fatalf("insert legacy event: %v", err)
+		}
+	}
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err := sqlStore.CreateAgentAuditEvent(ctx, CreateAgentAuditEventInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Connector:   "discord",
+		ExternalID:  "chan-1",
+		EventType:   "approval_required",
+		Stage:       "audit.approval_required",
+	}); err != nil {
+		t.Fatalf("create audit event: %v", err)
+	}
+	report, err := sqlStore.VerifyAgentAuditChain(ctx, "")
+	if err != nil || !report.OK() || report.Checked != 3 || report.HeadSeq != 3 {
+		t.Fatalf("expected legacy events chained in order, got %+v (%v)", report, err)
+	}
+}
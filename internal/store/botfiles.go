@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrBotfileNotFound = errors.New("botfile not found")
+
+type BotfileStatus string
+
+const (
+	BotfileApplied BotfileStatus = "applied"
+	// BotfileInvalid means the latest file failed validation; Content and
+	// Checksum still describe the last version that was applied.
+	BotfileInvalid BotfileStatus = "invalid"
+	BotfileRemoved BotfileStatus = "removed"
+)
+
+// WorkspaceBotfile records the last botfile applied to a workspace and the
+// outcome of the most recent attempt.
+type WorkspaceBotfile struct {
+	WorkspaceID string
+	Version     int
+	Checksum    string
+	Content     string
+	Status      BotfileStatus
+	Error       string
+	Changes     []string
+	AppliedAt   time.Time
+	UpdatedAt   time.Time
+	// Drift lists the differences between the applied botfile and the live
+	// workspace found by the last reconcile check that were left unfixed.
+	Drift          []string
+	DriftCheckedAt time.Time
+}
+
+func (s *Store) SaveWorkspaceBotfile(ctx context.Context, record WorkspaceBotfile) (WorkspaceBotfile, error) {
+	record.WorkspaceID = strings.TrimSpace(record.WorkspaceID)
+	if record.WorkspaceID == "" {
+		return WorkspaceBotfile{}, fmt.Errorf("workspace id is required")
+	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, nil); err != nil {
+		return WorkspaceBotfile{}, err
+	}
+	if record.Changes == nil {
+		record.Changes = []string{}
+	}
+	changesJSON, err := json.Marshal(record.Changes)
+	if err != nil {
+		return WorkspaceBotfile{}, fmt.Errorf("encode botfile changes: %w", err)
+	}
+	if record.Drift == nil {
+		record.Drift = []string{}
+	}
+	driftJSON, err := json.Marshal(record.Drift)
+	if err != nil {
+		return WorkspaceBotfile{}, fmt.Errorf("encode botfile drift: %w", err)
+	}
+	driftCheckedAtUnix := int64(0)
+	if !record.DriftCheckedAt.IsZero() {
+		record.DriftCheckedAt = record.DriftCheckedAt.UTC()
+		driftCheckedAtUnix = record.DriftCheckedAt.Unix()
+	}
+	record.UpdatedAt = time.Now().UTC()
+	appliedAtUnix := int64(0)
+	if !record.AppliedAt.IsZero() {
+		record.AppliedAt = record.AppliedAt.UTC()
+		appliedAtUnix = record.AppliedAt.Unix()
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO workspace_botfiles (workspace_id, version, checksum, content, status, error, changes_json, applied_at_unix, updated_at_unix, drift_json, drift_checked_at_unix)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(workspace_id) DO UPDATE SET
+		     version = excluded.version,
+		     checksum = excluded.checksum,
+		     content = excluded.content,
+		     status = excluded.status,
+		     error = excluded.error,
+		     changes_json = excluded.changes_json,
+		     applied_at_unix = excluded.applied_at_unix,
+		     updated_at_unix = excluded.updated_at_unix,
+		     drift_json = excluded.drift_json,
+		     drift_checked_at_unix = excluded.drift_checked_at_unix`,
+		record.WorkspaceID,
+		record.Version,
+		record.Checksum,
+		record.Content,
+		string(record.Status),
+		record.Error,
+		string(changesJSON),
+		appliedAtUnix,
+		record.UpdatedAt.Unix(),
+		string(driftJSON),
+		driftCheckedAtUnix,
+	); err != nil {
+		return WorkspaceBotfile{}, fmt.Errorf("save workspace botfile: %w", err)
+	}
+	return record, nil
+}
+
+func (s *Store) LookupWorkspaceBotfile(ctx context.Context, workspaceID string) (WorkspaceBotfile, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if err := checkWorkspaceScope(ctx, workspaceID, ErrBotfileNotFound); err != nil {
+		return WorkspaceBotfile{}, err
+	}
+	record := WorkspaceBotfile{WorkspaceID: workspaceID}
+	var status, changesJSON, driftJSON string
+	var appliedAtUnix, updatedAtUnix, driftCheckedAtUnix int64
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT version, checksum, content, status, error, changes_json, applied_at_unix, updated_at_unix, drift_json, drift_checked_at_unix FROM workspace_botfiles WHERE workspace_id = ?`,
+		workspaceID,
+	).Scan(&record.Version, &record.Checksum, &record.Content, &status, &record.Error, &changesJSON, &appliedAtUnix, &updatedAtUnix, &driftJSON, &driftCheckedAtUnix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WorkspaceBotfile{}, ErrBotfileNotFound
+		}
+		return WorkspaceBotfile{}, fmt.Errorf("lookup workspace botfile: %w", err)
+	}
+	record.Status = BotfileStatus(status)
+	if err := json.Unmarshal([]byte(changesJSON), &record.Changes); err != nil {
+		return WorkspaceBotfile{}, fmt.Errorf("decode botfile changes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(driftJSON), &record.Drift); err != nil {
+		return WorkspaceBotfile{}, fmt.Errorf("decode botfile drift: %w", err)
+	}
+	if appliedAtUnix > 0 {
+		record.AppliedAt = time.Unix(appliedAtUnix, 0).UTC()
+	}
+	if driftCheckedAtUnix > 0 {
+		record.DriftCheckedAt = time.Unix(driftCheckedAtUnix, 0).UTC()
+	}
+	record.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+	return record, nil
+}
+
+// RecordBotfileDrift stores the outcome of a reconcile check without
+// touching the applied botfile.
+func (s *Store) RecordBotfileDrift(ctx context.Context, workspaceID string, drift []string, checkedAt time.Time) error {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if err := checkWorkspaceScope(ctx, workspaceID, ErrBotfileNotFound); err != nil {
+		return err
+	}
+	if drift == nil {
+		drift = []string{}
+	}
+	driftJSON, err := json.Marshal(drift)
+	if err != nil {
+		return fmt.Errorf("encode botfile drift: %w", err)
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE workspace_botfiles SET drift_json = ?, drift_checked_at_unix = ? WHERE workspace_id = ?`,
+		string(driftJSON),
+		checkedAt.UTC().Unix(),
+		workspaceID,
+	)
+	if err != nil {
+		return fmt.Errorf("record botfile drift: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrBotfileNotFound
+	}
+	return nil
+}
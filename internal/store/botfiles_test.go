@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceBotfileSaveAndLookup(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := sqlStore.LookupWorkspaceBotfile(ctx, "ws-1"); !errors.Is(err, ErrBotfileNotFound) {
+		t.Fatalf("expected not found before first apply, got %v", err)
+	}
+	appliedAt := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	if _, err := sqlStore.SaveWorkspaceBotfile(ctx, WorkspaceBotfile{
+		WorkspaceID: "ws-1",
+		Version:     1,
+		Checksum:    "abc",
+		Content:     "version: 1\n",
+		Status:      BotfileApplied,
+		Changes:     []string{"persona: added"},
+		AppliedAt:   appliedAt,
+	}); err != nil {
+		t.Fatalf("save botfile: %v", err)
+	}
+	if _, err := sqlStore.SaveWorkspaceBotfile(ctx, WorkspaceBotfile{
+		WorkspaceID: "ws-1",
+		Version:     1,
+		Checksum:    "abc",
+		Content:     "version: 1\n",
+		Status:      BotfileInvalid,
+		Error:       "invalid botfile: version is required",
+		Changes:     []string{"persona: added"},
+		AppliedAt:   appliedAt,
+	}); err != nil {
+		t.Fatalf("save rejected botfile: %v", err)
+	}
+
+	record, err := sqlStore.LookupWorkspaceBotfile(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("lookup botfile: %v", err)
+	}
+	if record.Status != BotfileInvalid || record.Error == "" || record.Checksum != "abc" {
+		t.Fatalf("unexpected record %+v", record)
+	}
+	if !record.AppliedAt.Equal(appliedAt) || len(record.Changes) != 1 {
+		t.Fatalf("expected the last applied version to be kept, got %+v", record)
+	}
+
+	checkedAt := appliedAt.Add(time.Hour)
+	if err := sqlStore.RecordBotfileDrift(ctx, "ws-1", []string{"objectives: missing nightly"}, checkedAt); err != nil {
+		t.Fatalf("record drift: %v", err)
+	}
+	record, err = sqlStore.LookupWorkspaceBotfile(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("lookup after drift: %v", err)
+	}
+	if len(record.Drift) != 1 || !record.DriftCheckedAt.Equal(checkedAt) || record.Checksum != "abc" {
+		t.Fatalf("expected drift to be recorded alongside the applied botfile, got %+v", record)
+	}
+	if err := sqlStore.RecordBotfileDrift(ctx, "ws-3", nil, checkedAt); !errors.Is(err, ErrBotfileNotFound) {
+		t.Fatalf("expected drift for an unknown workspace to be rejected, got %v", err)
+	}
+
+	scoped := WithWorkspaceScope(ctx, "ws-2")
+	if _, err := sqlStore.LookupWorkspaceBotfile(scoped, "ws-1"); !errors.Is(err, ErrBotfileNotFound) {
+		t.Fatalf("expected another workspace's botfile to be hidden, got %v", err)
+	}
+}
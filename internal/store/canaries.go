@@ -0,0 +1,367 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCanaryNotFound  = errors.New("canary rollout not found")
+	ErrCanaryConflict  = errors.New("an active canary rollout already covers this change")
+	ErrCanaryNotActive = errors.New("canary rollout is no longer active")
+)
+
+// CanaryKind is the kind of change a canary rollout tries on part of the
+// traffic: an alternate system prompt, model or newly enabled tool.
+type CanaryKind string
+
+const (
+	CanaryPrompt CanaryKind = "prompt"
+	CanaryModel  CanaryKind = "model"
+	CanaryTool   CanaryKind = "tool"
+)
+
+type CanaryStatus string
+
+const (
+	CanaryActive     CanaryStatus = "active"
+	CanaryRolledBack CanaryStatus = "rolled_back"
+	CanaryPromoted   CanaryStatus = "promoted"
+	CanaryStopped    CanaryStatus = "stopped"
+)
+
+// Rollback thresholds applied when a rollout is created without its own.
+const (
+	DefaultCanaryMinSamples  = 20
+	DefaultCanaryErrorMargin = 0.10
+	DefaultCanaryBlockMargin = 0.10
+)
+
+// CanaryArm counts the agent turns of one side of a rollout and how many of
+// them failed or were blocked by policy.
+type CanaryArm struct {
+	Turns  int
+	Errors int
+	Blocks int
+}
+
+// CanaryRollout sends Percent of the contexts of a workspace (every
+// workspace when WorkspaceID is empty) to Value. For prompt rollouts Value
+// is the system prompt directive text, for model rollouts the model name and
+// for tool rollouts the tool that only the canary contexts may use.
+type CanaryRollout struct {
+	ID          string
+	WorkspaceID string
+	Name        string
+	Kind        CanaryKind
+	Value       string
+	Percent     int
+	Status      CanaryStatus
+	MinSamples  int
+	ErrorMargin float64
+	BlockMargin float64
+	Canary      CanaryArm
+	Control     CanaryArm
+	Reason      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type CreateCanaryRolloutInput struct {
+	WorkspaceID string
+	Name        string
+	Kind        CanaryKind
+	Value       string
+	Percent     int
+	MinSamples  int
+	ErrorMargin float64
+	BlockMargin float64
+}
+
+type ListCanaryRolloutsInput struct {
+	WorkspaceID string
+	Status      CanaryStatus
+	Limit       int
+}
+
+const canaryRolloutColumns = `id, workspace_id, name, kind, value, percent, status, min_samples, error_margin, block_margin,
+	canary_turns, canary_errors, canary_blocks, control_turns, control_errors, control_blocks, reason, created_at_unix, updated_at_unix`
+
+func (s *Store) CreateCanaryRollout(ctx context.Context, input CreateCanaryRolloutInput) (CanaryRollout, error) {
+	now := time.Now().UTC()
+	record := CanaryRollout{
+		ID:          "canary_" + uuid.NewString(),
+		WorkspaceID: strings.TrimSpace(input.WorkspaceID),
+		Name:        strings.TrimSpace(input.Name),
+		Kind:        CanaryKind(strings.ToLower(strings.TrimSpace(string(input.Kind)))),
+		Value:       strings.TrimSpace(input.Value),
+		Percent:     input.Percent,
+		Status:      CanaryActive,
+		MinSamples:  input.MinSamples,
+		ErrorMargin: input.ErrorMargin,
+		BlockMargin: input.BlockMargin,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	switch record.Kind {
+	case CanaryPrompt, CanaryModel, CanaryTool:
+	default:
+		return CanaryRollout{}, fmt.Errorf("canary kind must be prompt, model or tool")
+	}
+	if record.Value == "" {
+		return CanaryRollout{}, fmt.Errorf("canary value is required")
+	}
+	if record.Percent < 1 || record.Percent > 100 {
+		return CanaryRollout{}, fmt.Errorf("canary percent must be between 1 and 100")
+	}
+	if record.MinSamples < 0 || record.ErrorMargin < 0 || record.BlockMargin < 0 {
+		return CanaryRollout{}, fmt.Errorf("canary thresholds must not be negative")
+	}
+	if record.MinSamples == 0 {
+		record.MinSamples = DefaultCanaryMinSamples
+	}
+	if record.ErrorMargin == 0 {
+		record.ErrorMargin = DefaultCanaryErrorMargin
+	}
+	if record.BlockMargin == 0 {
+		record.BlockMargin = DefaultCanaryBlockMargin
+	}
+	if record.Name == "" {
+		record.Name = string(record.Kind) + " canary"
+	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, nil); err != nil {
+		return CanaryRollout{}, err
+	}
+
+	// Two active prompt or model rollouts for the same workspace would fight
+	// over the same turns; tools only clash when they gate the same tool.
+	conflictQuery := `SELECT COUNT(1) FROM canary_rollouts WHERE status = ? AND workspace_id = ? AND kind = ?`
+	conflictArgs := []any{string(CanaryActive), record.WorkspaceID, string(record.Kind)}
+	if record.Kind == CanaryTool {
+		conflictQuery += ` AND value = ?`
+		conflictArgs = append(conflictArgs, record.Value)
+	}
+	var active int
+	if err := s.db.QueryRowContext(ctx, conflictQuery, conflictArgs...).Scan(&active); err != nil {
+		return CanaryRollout{}, fmt.Errorf("check canary conflicts: %w", err)
+	}
+	if active > 0 {
+		return CanaryRollout{}, ErrCanaryConflict
+	}
+
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO canary_rollouts (`+canaryRolloutColumns+`)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, 0, 0, '', ?, ?)`,
+		record.ID,
+		record.WorkspaceID,
+		record.Name,
+		string(record.Kind),
+		record.Value,
+		record.Percent,
+		string(record.Status),
+		record.MinSamples,
+		record.ErrorMargin,
+		record.BlockMargin,
+		record.CreatedAt.Unix(),
+		record.UpdatedAt.Unix(),
+	); err != nil {
+		return CanaryRollout{}, fmt.Errorf("insert canary rollout: %w", err)
+	}
+	return record, nil
+}
+
+func (s *Store) LookupCanaryRollout(ctx context.Context, id string) (CanaryRollout, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+canaryRolloutColumns+` FROM canary_rollouts WHERE id = ?`, strings.TrimSpace(id))
+	record, err := scanCanaryRollout(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CanaryRollout{}, ErrCanaryNotFound
+	}
+	if err != nil {
+		return CanaryRollout{}, fmt.Errorf("lookup canary rollout: %w", err)
+	}
+	// Runtime-wide rollouts are visible from every workspace scope.
+	if record.WorkspaceID != "" {
+		if err := checkWorkspaceScope(ctx, record.WorkspaceID, ErrCanaryNotFound); err != nil {
+			return CanaryRollout{}, err
+		}
+	}
+	return record, nil
+}
+
+func (s *Store) ListCanaryRollouts(ctx context.Context, input ListCanaryRolloutsInput) ([]CanaryRollout, error) {
+	limit := input.Limit
+	if limit < 1 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	workspaceID, err := scopedWorkspaceFilter(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	whereParts := []string{"1=1"}
+	args := []any{}
+	if workspaceID != "" {
+		whereParts = append(whereParts, "workspace_id = ?")
+		args = append(args, workspaceID)
+	}
+	if status := strings.TrimSpace(string(input.Status)); status != "" {
+		whereParts = append(whereParts, "status = ?")
+		args = append(args, status)
+	}
+	args = append(args, limit)
+	return s.queryCanaryRollouts(
+		ctx,
+		`SELECT `+canaryRolloutColumns+` FROM canary_rollouts
+		 WHERE `+strings.Join(whereParts, " AND ")+`
+		 ORDER BY created_at_unix DESC, id DESC
+		 LIMIT ?`,
+		args...,
+	)
+}
+
+// ListActiveCanaryRollouts returns the active rollouts that apply to a
+// workspace: its own and the runtime-wide ones.
+func (s *Store) ListActiveCanaryRollouts(ctx context.Context, workspaceID string) ([]CanaryRollout, error) {
+	return s.queryCanaryRollouts(
+		ctx,
+		`SELECT `+canaryRolloutColumns+` FROM canary_rollouts
+		 WHERE status = ? AND (workspace_id = '' OR workspace_id = ?)
+		 ORDER BY created_at_unix ASC, id ASC`,
+		string(CanaryActive),
+		strings.TrimSpace(workspaceID),
+	)
+}
+
+// RecordCanaryOutcome counts one agent turn against the canary or control
+// side of an active rollout and returns the updated counters.
+func (s *Store) RecordCanaryOutcome(ctx context.Context, id string, canary, errored, blocked bool) (CanaryRollout, error) {
+	prefix := "control"
+	if canary {
+		prefix = "canary"
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE canary_rollouts SET
+		     `+prefix+`_turns = `+prefix+`_turns + 1,
+		     `+prefix+`_errors = `+prefix+`_errors + ?,
+		     `+prefix+`_blocks = `+prefix+`_blocks + ?,
+		     updated_at_unix = ?
+		 WHERE id = ? AND status = ?`,
+		boolToInt(errored),
+		boolToInt(blocked),
+		time.Now().UTC().Unix(),
+		strings.TrimSpace(id),
+		string(CanaryActive),
+	)
+	if err != nil {
+		return CanaryRollout{}, fmt.Errorf("record canary outcome: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		if _, err := s.LookupCanaryRollout(ctx, id); err != nil {
+			return CanaryRollout{}, err
+		}
+		return CanaryRollout{}, ErrCanaryNotActive
+	}
+	return s.LookupCanaryRollout(ctx, id)
+}
+
+// FinishCanaryRollout ends an active rollout as promoted, stopped or rolled
+// back. Finished rollouts keep their counters for review.
+func (s *Store) FinishCanaryRollout(ctx context.Context, id string, status CanaryStatus, reason string) (CanaryRollout, error) {
+	switch status {
+	case CanaryRolledBack, CanaryPromoted, CanaryStopped:
+	default:
+		return CanaryRollout{}, fmt.Errorf("canary status must be rolled_back, promoted or stopped")
+	}
+	record, err := s.LookupCanaryRollout(ctx, id)
+	if err != nil {
+		return CanaryRollout{}, err
+	}
+	now := time.Now().UTC()
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE canary_rollouts SET status = ?, reason = ?, updated_at_unix = ? WHERE id = ? AND status = ?`,
+		string(status),
+		strings.TrimSpace(reason),
+		now.Unix(),
+		record.ID,
+		string(CanaryActive),
+	)
+	if err != nil {
+		return CanaryRollout{}, fmt.Errorf("finish canary rollout: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return CanaryRollout{}, ErrCanaryNotActive
+	}
+	record.Status = status
+	record.Reason = strings.TrimSpace(reason)
+	record.UpdatedAt = now
+	return record, nil
+}
+
+func (s *Store) queryCanaryRollouts(ctx context.Context, query string, args ...any) ([]CanaryRollout, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list canary rollouts: %w", err)
+	}
+	defer rows.Close()
+	records := []CanaryRollout{}
+	for rows.Next() {
+		record, err := scanCanaryRollout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan canary rollout: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate canary rollouts: %w", err)
+	}
+	return records, nil
+}
+
+type canaryScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCanaryRollout(row canaryScanner) (CanaryRollout, error) {
+	var record CanaryRollout
+	var kind, status string
+	var createdAtUnix, updatedAtUnix int64
+	if err := row.Scan(
+		&record.ID,
+		&record.WorkspaceID,
+		&record.Name,
+		&kind,
+		&record.Value,
+		&record.Percent,
+		&status,
+		&record.MinSamples,
+		&record.ErrorMargin,
+		&record.BlockMargin,
+		&record.Canary.Turns,
+		&record.Canary.Errors,
+		&record.Canary.Blocks,
+		&record.Control.Turns,
+		&record.Control.Errors,
+		&record.Control.Blocks,
+		&record.Reason,
+		&createdAtUnix,
+		&updatedAtUnix,
+	); err != nil {
+		return CanaryRollout{}, err
+	}
+	record.Kind = CanaryKind(kind)
+	record.Status = CanaryStatus(status)
+	record.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+	record.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+	return record, nil
+}
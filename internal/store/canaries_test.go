@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCanaryRolloutLifecycle(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := sqlStore.CreateCanaryRollout(ctx, CreateCanaryRolloutInput{Kind: "banner", Value: "x", Percent: 10}); err == nil {
+		t.Fatal("expected unknown kind to be rejected")
+	}
+	if _, err := sqlStore.CreateCanaryRollout(ctx, CreateCanaryRolloutInput{Kind: CanaryModel, Value: "gpt-4.1", Percent: 0}); err == nil {
+		t.Fatal("expected zero percent to be rejected")
+	}
+	rollout, err := sqlStore.CreateCanaryRollout(ctx, CreateCanaryRolloutInput{WorkspaceID: "ws-1", Kind: CanaryModel, Value: "gpt-4.1", Percent: 25})
+	if err != nil {
+		t.Fatalf("create rollout: %v", err)
+	}
+	if rollout.Status != CanaryActive || rollout.MinSamples != DefaultCanaryMinSamples || rollout.ErrorMargin != DefaultCanaryErrorMargin {
+		t.Fatalf("expected active rollout with default thresholds, got %+v", rollout)
+	}
+	if _, err := sqlStore.CreateCanaryRollout(ctx, CreateCanaryRolloutInput{WorkspaceID: "ws-1", Kind: CanaryModel, Value: "other", Percent: 5}); !errors.Is(err, ErrCanaryConflict) {
+		t.Fatalf("expected a second model rollout to conflict, got %v", err)
+	}
+	if _, err := sqlStore.CreateCanaryRollout(ctx, CreateCanaryRolloutInput{Kind: CanaryTool, Value: "fetch_url", Percent: 50}); err != nil {
+		t.Fatalf("create global tool rollout: %v", err)
+	}
+
+	active, err := sqlStore.ListActiveCanaryRollouts(ctx, "ws-1")
+	if err != nil || len(active) != 2 {
+		t.Fatalf("expected own and global rollouts, got %+v (%v)", active, err)
+	}
+	if other, _ := sqlStore.ListActiveCanaryRollouts(ctx, "ws-2"); len(other) != 1 || other[0].Kind != CanaryTool {
+		t.Fatalf("expected only the global rollout for another workspace, got %+v", other)
+	}
+
+	if _, err := sqlStore.RecordCanaryOutcome(ctx, rollout.ID, true, true, false); err != nil {
+		t.Fatalf("record canary outcome: %v", err)
+	}
+	updated, err := sqlStore.RecordCanaryOutcome(ctx, rollout.ID, false, false, true)
+	if err != nil {
+		t.Fatalf("record control outcome: %v", err)
+	}
+	if updated.Canary != (CanaryArm{Turns: 1, Errors: 1}) || updated.Control != (CanaryArm{Turns: 1, Blocks: 1}) {
+		t.Fatalf("unexpected counters %+v / %+v", updated.Canary, updated.Control)
+	}
+
+	finished, err := sqlStore.FinishCanaryRollout(ctx, rollout.ID, CanaryRolledBack, "error rate spiked")
+	if err != nil || finished.Status != CanaryRolledBack || finished.Reason != "error rate spiked" {
+		t.Fatalf("expected rollout to be rolled back, got %+v (%v)", finished, err)
+	}
+	if _, err := sqlStore.RecordCanaryOutcome(ctx, rollout.ID, true, false, false); !errors.Is(err, ErrCanaryNotActive) {
+		t.Fatalf("expected outcomes on a finished rollout to be refused, got %v", err)
+	}
+	if _, err := sqlStore.FinishCanaryRollout(ctx, rollout.ID, CanaryPromoted, ""); !errors.Is(err, ErrCanaryNotActive) {
+		t.Fatalf("expected finished rollout to stay finished, got %v", err)
+	}
+	if _, err := sqlStore.FinishCanaryRollout(ctx, "canary_missing", CanaryStopped, ""); !errors.Is(err, ErrCanaryNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	listed, err := sqlStore.ListCanaryRollouts(ctx, ListCanaryRolloutsInput{Status: CanaryRolledBack})
+	if err != nil || len(listed) != 1 || listed[0].ID != rollout.ID {
+		t.Fatalf("expected the rolled back rollout, got %+v (%v)", listed, err)
+	}
+}
@@ -0,0 +1,393 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCaseNotFound      = errors.New("case not found")
+	ErrCaseStatusInvalid = errors.New("case status must be open or resolved")
+	ErrCaseItemInvalid   = errors.New("case item kind must be task, action, audit, moderation or chat")
+)
+
+const (
+	CaseKindIncident   = "incident"
+	CaseKindModeration = "moderation"
+	CaseKindManual     = "manual"
+
+	CaseStatusOpen     = "open"
+	CaseStatusResolved = "resolved"
+
+	CaseItemTask       = "task"
+	CaseItemAction     = "action"
+	CaseItemAudit      = "audit"
+	CaseItemModeration = "moderation"
+	CaseItemChat       = "chat"
+)
+
+// maxCaseExcerpt bounds stored chat excerpts and summaries.
+const maxCaseExcerpt = 2000
+
+// Case groups the tasks, action approvals, audit events, moderation cases
+// and chat excerpts that belong to one incident or moderation matter, so
+// operators follow it under a single id.
+type Case struct {
+	ID          string
+	WorkspaceID string
+	ContextID   string
+	Kind        string
+	Title       string
+	Summary     string
+	Status      string
+	OwnerUserID string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ResolvedAt  time.Time
+}
+
+// CaseItem links a record to a case. ItemID is the linked record's id; chat
+// items carry the message text in Excerpt instead.
+type CaseItem struct {
+	CaseID    string
+	Kind      string
+	ItemID    string
+	Excerpt   string
+	CreatedAt time.Time
+}
+
+type CreateCaseInput struct {
+	WorkspaceID string
+	ContextID   string
+	Kind        string
+	Title       string
+	Summary     string
+	OwnerUserID string
+	Items       []AddCaseItemInput
+}
+
+type ListCasesInput struct {
+	WorkspaceID string
+	Status      string
+	Kind        string
+	Limit       int
+}
+
+// UpdateCaseInput changes a case's status and owner. Empty fields are left
+// unchanged.
+type UpdateCaseInput struct {
+	ID          string
+	Status      string
+	OwnerUserID string
+}
+
+type AddCaseItemInput struct {
+	CaseID  string
+	Kind    string
+	ItemID  string
+	Excerpt string
+}
+
+// CreateCase opens a case along with its initial items.
+func (s *Store) CreateCase(ctx context.Context, input CreateCaseInput) (Case, error) {
+	now := time.Now().UTC()
+	record := Case{
+		ID:          "case_" + uuid.NewString(),
+		WorkspaceID: strings.TrimSpace(input.WorkspaceID),
+		ContextID:   strings.TrimSpace(input.ContextID),
+		Kind:        strings.ToLower(strings.TrimSpace(input.Kind)),
+		Title:       strings.TrimSpace(input.Title),
+		Summary:     clipCaseText(input.Summary),
+		Status:      CaseStatusOpen,
+		OwnerUserID: strings.TrimSpace(input.OwnerUserID),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if record.Kind == "" {
+		record.Kind = CaseKindManual
+	}
+	if record.WorkspaceID == "" || record.Title == "" {
+		return Case{}, fmt.Errorf("missing required case fields")
+	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, nil); err != nil {
+		return Case{}, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Case{}, fmt.Errorf("begin case: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO cases (id, workspace_id, context_id, kind, title, summary, status, owner_user_id, created_at_unix, updated_at_unix)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.ID,
+		record.WorkspaceID,
+		record.ContextID,
+		record.Kind,
+		record.Title,
+		record.Summary,
+		record.Status,
+		record.OwnerUserID,
+		record.CreatedAt.Unix(),
+		record.UpdatedAt.Unix(),
+	); err != nil {
+		return Case{}, fmt.Errorf("insert case: %w", err)
+	}
+	for _, item := range input.Items {
+		item.CaseID = record.ID
+		if err := insertCaseItem(ctx, tx, item, now); err != nil {
+			return Case{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return Case{}, fmt.Errorf("commit case: %w", err)
+	}
+	return record, nil
+}
+
+func (s *Store) LookupCase(ctx context.Context, id string) (Case, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+caseColumns+` FROM cases WHERE id = ?`, strings.TrimSpace(id))
+	record, err := scanCase(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Case{}, ErrCaseNotFound
+	}
+	if err != nil {
+		return Case{}, err
+	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, ErrCaseNotFound); err != nil {
+		return Case{}, err
+	}
+	return record, nil
+}
+
+// ListCases returns a workspace's cases, open ones first and then by most
+// recent activity.
+func (s *Store) ListCases(ctx context.Context, input ListCasesInput) ([]Case, error) {
+	workspaceID, err := scopedWorkspaceFilter(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID == "" {
+		return nil, fmt.Errorf("workspace id is required")
+	}
+	limit := input.Limit
+	if limit < 1 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	query := `SELECT ` + caseColumns + ` FROM cases WHERE workspace_id = ?`
+	args := []any{workspaceID}
+	if status := strings.ToLower(strings.TrimSpace(input.Status)); status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	if kind := strings.ToLower(strings.TrimSpace(input.Kind)); kind != "" {
+		query += ` AND kind = ?`
+		args = append(args, kind)
+	}
+	query += ` ORDER BY CASE status WHEN 'open' THEN 0 ELSE 1 END, updated_at_unix DESC, rowid DESC LIMIT ?`
+	args = append(args, limit)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list cases: %w", err)
+	}
+	defer rows.Close()
+	cases := []Case{}
+	for rows.Next() {
+		record, err := scanCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate cases: %w", err)
+	}
+	return cases, nil
+}
+
+// UpdateCase sets a case's status or owner. Resolving stamps ResolvedAt;
+// reopening clears it.
+func (s *Store) UpdateCase(ctx context.Context, input UpdateCaseInput) (Case, error) {
+	record, err := s.LookupCase(ctx, input.ID)
+	if err != nil {
+		return Case{}, err
+	}
+	now := time.Now().UTC()
+	if status := strings.ToLower(strings.TrimSpace(input.Status)); status != "" && status != record.Status {
+		switch status {
+		case CaseStatusOpen:
+			record.ResolvedAt = time.Time{}
+		case CaseStatusResolved:
+			record.ResolvedAt = now
+		default:
+			return Case{}, ErrCaseStatusInvalid
+		}
+		record.Status = status
+	}
+	if owner := strings.TrimSpace(input.OwnerUserID); owner != "" {
+		record.OwnerUserID = owner
+	}
+	record.UpdatedAt = now
+	resolvedAtUnix := int64(0)
+	if !record.ResolvedAt.IsZero() {
+		resolvedAtUnix = record.ResolvedAt.Unix()
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`UPDATE cases SET status = ?, owner_user_id = ?, resolved_at_unix = ?, updated_at_unix = ? WHERE id = ?`,
+		record.Status,
+		record.OwnerUserID,
+		nullIfZeroInt64(resolvedAtUnix),
+		record.UpdatedAt.Unix(),
+		record.ID,
+	); err != nil {
+		return Case{}, fmt.Errorf("update case: %w", err)
+	}
+	return record, nil
+}
+
+// AddCaseItem links a record to a case. Linking the same record twice is a
+// no-op.
+func (s *Store) AddCaseItem(ctx context.Context, input AddCaseItemInput) (CaseItem, error) {
+	record, err := s.LookupCase(ctx, input.CaseID)
+	if err != nil {
+		return CaseItem{}, err
+	}
+	now := time.Now().UTC()
+	input.CaseID = record.ID
+	if err := insertCaseItem(ctx, s.db, input, now); err != nil {
+		return CaseItem{}, err
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE cases SET updated_at_unix = ? WHERE id = ?`, now.Unix(), record.ID); err != nil {
+		return CaseItem{}, fmt.Errorf("touch case: %w", err)
+	}
+	return CaseItem{
+		CaseID:    record.ID,
+		Kind:      strings.ToLower(strings.TrimSpace(input.Kind)),
+		ItemID:    strings.TrimSpace(input.ItemID),
+		Excerpt:   clipCaseText(input.Excerpt),
+		CreatedAt: now,
+	}, nil
+}
+
+// ListCaseItems returns a case's items in the order they were linked.
+func (s *Store) ListCaseItems(ctx context.Context, caseID string) ([]CaseItem, error) {
+	record, err := s.LookupCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT case_id, kind, item_id, excerpt, created_at_unix FROM case_items WHERE case_id = ? ORDER BY created_at_unix ASC, rowid ASC`,
+		record.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list case items: %w", err)
+	}
+	defer rows.Close()
+	items := []CaseItem{}
+	for rows.Next() {
+		var item CaseItem
+		var createdAtUnix int64
+		if err := rows.Scan(&item.CaseID, &item.Kind, &item.ItemID, &item.Excerpt, &createdAtUnix); err != nil {
+			return nil, fmt.Errorf("scan case item: %w", err)
+		}
+		item.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate case items: %w", err)
+	}
+	return items, nil
+}
+
+type caseItemExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertCaseItem(ctx context.Context, execer caseItemExecer, input AddCaseItemInput, now time.Time) error {
+	kind := strings.ToLower(strings.TrimSpace(input.Kind))
+	itemID := strings.TrimSpace(input.ItemID)
+	excerpt := clipCaseText(input.Excerpt)
+	switch kind {
+	case CaseItemTask, CaseItemAction, CaseItemAudit, CaseItemModeration:
+		if itemID == "" {
+			return fmt.Errorf("%w: %s item needs an id", ErrCaseItemInvalid, kind)
+		}
+	case CaseItemChat:
+		if excerpt == "" {
+			return fmt.Errorf("%w: chat item needs an excerpt", ErrCaseItemInvalid)
+		}
+		if itemID == "" {
+			itemID = "chat_" + uuid.NewString()
+		}
+	default:
+		return ErrCaseItemInvalid
+	}
+	if _, err := execer.ExecContext(
+		ctx,
+		`INSERT OR IGNORE INTO case_items (case_id, kind, item_id, excerpt, created_at_unix) VALUES (?, ?, ?, ?, ?)`,
+		strings.TrimSpace(input.CaseID),
+		kind,
+		itemID,
+		excerpt,
+		now.Unix(),
+	); err != nil {
+		return fmt.Errorf("insert case item: %w", err)
+	}
+	return nil
+}
+
+func clipCaseText(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if len(trimmed) <= maxCaseExcerpt {
+		return trimmed
+	}
+	return strings.TrimSpace(trimmed[:maxCaseExcerpt]) + "..."
+}
+
+const caseColumns = `id, workspace_id, context_id, kind, title, summary, status, owner_user_id, created_at_unix, updated_at_unix, resolved_at_unix`
+
+type caseScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCase(scanner caseScanner) (Case, error) {
+	var record Case
+	var createdAtUnix, updatedAtUnix int64
+	var resolvedAtUnix sql.NullInt64
+	if err := scanner.Scan(
+		&record.ID,
+		&record.WorkspaceID,
+		&record.ContextID,
+		&record.Kind,
+		&record.Title,
+		&record.Summary,
+		&record.Status,
+		&record.OwnerUserID,
+		&createdAtUnix,
+		&updatedAtUnix,
+		&resolvedAtUnix,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Case{}, err
+		}
+		return Case{}, fmt.Errorf("scan case: %w", err)
+	}
+	record.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+	record.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+	if resolvedAtUnix.Valid && resolvedAtUnix.Int64 > 0 {
+		record.ResolvedAt = time.Unix(resolvedAtUnix.Int64, 0).UTC()
+	}
+	return record, nil
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCaseGroupsItemsAndTracksStatus(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	record, err := sqlStore.CreateCase(ctx, CreateCaseInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        CaseKindIncident,
+		Title:       "Checkout is down",
+		Items: []AddCaseItemInput{
+			{Kind: CaseItemTask, ItemID: "task-1"},
+			{Kind: CaseItemChat, Excerpt: "checkout returns 500 for everyone"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create case: %v", err)
+	}
+	if record.Status != CaseStatusOpen || record.Kind != CaseKindIncident {
+		t.Fatalf("unexpected case %+v", record)
+	}
+	if _, err := sqlStore.AddCaseItem(ctx, AddCaseItemInput{CaseID: record.ID, Kind: CaseItemAction, ItemID: "act_1"}); err != nil {
+		t.Fatalf("link action: %v", err)
+	}
+	if _, err := sqlStore.AddCaseItem(ctx, AddCaseItemInput{CaseID: record.ID, Kind: CaseItemAction, ItemID: "act_1"}); err != nil {
+		t.Fatalf("relink action: %v", err)
+	}
+	if _, err := sqlStore.AddCaseItem(ctx, AddCaseItemInput{CaseID: record.ID, Kind: "note", ItemID: "x"}); !errors.Is(err, ErrCaseItemInvalid) {
+		t.Fatalf("expected unknown item kind to be rejected, got %v", err)
+	}
+	items, err := sqlStore.ListCaseItems(ctx, record.ID)
+	if err != nil || len(items) != 3 {
+		t.Fatalf("expected task, chat and action items, got %+v (%v)", items, err)
+	}
+	if items[0].Kind != CaseItemTask || items[1].Excerpt == "" || items[2].ItemID != "act_1" {
+		t.Fatalf("unexpected items %+v", items)
+	}
+
+	updated, err := sqlStore.UpdateCase(ctx, UpdateCaseInput{ID: record.ID, Status: CaseStatusResolved, OwnerUserID: "admin-1"})
+	if err != nil || updated.Status != CaseStatusResolved || updated.OwnerUserID != "admin-1" || updated.ResolvedAt.IsZero() {
+		t.Fatalf("expected resolved case owned by admin-1, got %+v (%v)", updated, err)
+	}
+	if _, err := sqlStore.UpdateCase(ctx, UpdateCaseInput{ID: record.ID, Status: "closed"}); !errors.Is(err, ErrCaseStatusInvalid) {
+		t.Fatalf("expected invalid status error, got %v", err)
+	}
+	if _, err := sqlStore.CreateCase(ctx, CreateCaseInput{WorkspaceID: "ws-1", Title: "Spam wave"}); err != nil {
+		t.Fatalf("create second case: %v", err)
+	}
+	cases, err := sqlStore.ListCases(ctx, ListCasesInput{WorkspaceID: "ws-1"})
+	if err != nil || len(cases) != 2 || cases[0].Title != "Spam wave" || cases[0].Kind != CaseKindManual {
+		t.Fatalf("expected open cases first, got %+v (%v)", cases, err)
+	}
+	resolved, err := sqlStore.ListCases(ctx, ListCasesInput{WorkspaceID: "ws-1", Status: CaseStatusResolved})
+	if err != nil || len(resolved) != 1 || resolved[0].ID != record.ID {
+		t.Fatalf("expected status filter, got %+v (%v)", resolved, err)
+	}
+	if _, err := sqlStore.LookupCase(ctx, "case_missing"); !errors.Is(err, ErrCaseNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
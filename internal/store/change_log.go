@@ -0,0 +1,259 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ChangeLogTables are the tables whose row changes the change log captures.
+var ChangeLogTables = []string{"tasks", "action_approvals", "objectives"}
+
+// changeLogQuietColumns are bookkeeping columns whose updates alone are not
+// captured or counted as a change version, such as the task leases and
+// progress updated while a task runs.
+var changeLogQuietColumns = map[string][]string{
+	"tasks": {"lease_owner", "lease_expires_at_unix", "progress_percent", "progress_step", "progress_updated_at_unix"},
+}
+
+var changeLogOperations = []struct {
+	suffix string
+	event  string
+	row    string
+}{
+	{suffix: "ai", event: "INSERT", row: "NEW"},
+	{suffix: "au", event: "UPDATE", row: "NEW"},
+	{suffix: "ad", event: "DELETE", row: "OLD"},
+}
+
+// ErrChangeLogTableUnknown is returned for tables the change log does not
+// capture.
+var ErrChangeLogTableUnknown = errors.New("change log does not capture this table")
+
+// ChangeRecord is one captured row change. Row holds every column after the
+// change, or before it for deletes.
+type ChangeRecord struct {
+	ID          int64
+	Table       string
+	RowID       string
+	WorkspaceID string
+	Operation   string
+	Row         map[string]any
+	ChangedAt   time.Time
+}
+
+type ListChangesInput struct {
+	Table       string
+	RowID       string
+	WorkspaceID string
+	// Until keeps changes made at or before it; zero means now.
+	Until time.Time
+	Limit int
+}
+
+// EnableChangeLog installs triggers that copy every insert, update and
+// delete on ChangeLogTables into change_log. The triggers are rebuilt each
+// time so columns added by later migrations are captured too.
+func (s *Store) EnableChangeLog(ctx context.Context) error {
+	for _, table := range ChangeLogTables {
+		columns, err := s.tableColumns(ctx, table)
+		if err != nil {
+			return err
+		}
+		for _, operation := range changeLogOperations {
+			pairs := make([]string, 0, len(columns))
+			for _, column := range columns {
+				pairs = append(pairs, fmt.Sprintf("'%s', %s.%s", column, operation.row, column))
+			}
+			event := changeTriggerEvent(table, columns, operation.event)
+			name := fmt.Sprintf("change_log_%s_%s", table, operation.suffix)
+			queries := []string{
+				`DROP TRIGGER IF EXISTS ` + name,
+				fmt.Sprintf(
+					`CREATE TRIGGER %s AFTER %s ON %s BEGIN
+						INSERT INTO change_log (table_name, row_id, workspace_id, operation, row_json, changed_at_unix)
+						VALUES ('%s', %s.id, %s.workspace_id, '%s', json_object(%s), CAST(strftime('%%s', 'now') AS INTEGER));
+					END;`,
+					name, event, table, table, operation.row, operation.row, strings.ToLower(operation.event), strings.Join(pairs, ", "),
+				),
+			}
+			for _, query := range queries {
+				if _, err := s.db.ExecContext(ctx, query); err != nil {
+					return fmt.Errorf("install change log trigger %s: %w", name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// changeTriggerEvent is the trigger event for operation on table; updates
+// only fire for columns outside changeLogQuietColumns.
+func changeTriggerEvent(table string, columns []string, event string) string {
+	quiet := changeLogQuietColumns[table]
+	if event != "UPDATE" || len(quiet) == 0 {
+		return event
+	}
+	watched := []string{}
+	for _, column := range columns {
+		if !slices.Contains(quiet, column) {
+			watched = append(watched, column)
+		}
+	}
+	return "UPDATE OF " + strings.Join(watched, ", ")
+}
+
+// DisableChangeLog removes the capture triggers. Entries already logged are
+// kept until PruneChangeLog removes them.
+func (s *Store) DisableChangeLog(ctx context.Context) error {
+	for _, table := range ChangeLogTables {
+		for _, operation := range changeLogOperations {
+			name := fmt.Sprintf("change_log_%s_%s", table, operation.suffix)
+			if _, err := s.db.ExecContext(ctx, `DROP TRIGGER IF EXISTS `+name); err != nil {
+				return fmt.Errorf("drop change log trigger %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// PruneChangeLog deletes entries older than before.
+func (s *Store) PruneChangeLog(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM change_log WHERE changed_at_unix < ?`, before.UTC().Unix())
+	if err != nil {
+		return 0, fmt.Errorf("prune change log: %w", err)
+	}
+	pruned, _ := result.RowsAffected()
+	return int(pruned), nil
+}
+
+// ListChanges returns captured changes oldest first, for tracing how a row
+// got into its state.
+func (s *Store) ListChanges(ctx context.Context, input ListChangesInput) ([]ChangeRecord, error) {
+	table := strings.TrimSpace(input.Table)
+	if table != "" && !isChangeLogTable(table) {
+		return nil, fmt.Errorf("%w: %s", ErrChangeLogTableUnknown, table)
+	}
+	until := input.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	limit := input.Limit
+	if limit < 1 || limit > 1000 {
+		limit = 200
+	}
+	query := `SELECT id, table_name, row_id, workspace_id, operation, row_json, changed_at_unix
+		FROM change_log WHERE changed_at_unix <= ?`
+	args := []any{until.UTC().Unix()}
+	for column, value := range map[string]string{
+		"table_name":   table,
+		"row_id":       strings.TrimSpace(input.RowID),
+		"workspace_id": strings.TrimSpace(input.WorkspaceID),
+	} {
+		if value != "" {
+			query += " AND " + column + " = ?"
+			args = append(args, value)
+		}
+	}
+	// The newest entries within the limit, returned oldest first.
+	query = `SELECT * FROM (` + query + ` ORDER BY id DESC LIMIT ?) ORDER BY id ASC`
+	args = append(args, limit)
+	return s.queryChanges(ctx, query, args...)
+}
+
+// StateAsOf reconstructs the rows of table as they were at the given time:
+// the last captured version of each row, leaving out rows deleted by then.
+// rowID and workspaceID narrow the result when set. Rows that did not
+// change while the change log was enabled are not known to it.
+func (s *Store) StateAsOf(ctx context.Context, table, workspaceID, rowID string, at time.Time) ([]ChangeRecord, error) {
+	table = strings.TrimSpace(table)
+	if !isChangeLogTable(table) {
+		return nil, fmt.Errorf("%w: %s", ErrChangeLogTableUnknown, table)
+	}
+	query := `SELECT c.id, c.table_name, c.row_id, c.workspace_id, c.operation, c.row_json, c.changed_at_unix
+		FROM change_log c
+		JOIN (
+			SELECT row_id, MAX(id) AS last_id FROM change_log
+			WHERE table_name = ? AND changed_at_unix <= ?
+			GROUP BY row_id
+		) latest ON latest.last_id = c.id
+		WHERE c.operation != 'delete'`
+	args := []any{table, at.UTC().Unix()}
+	if rowID = strings.TrimSpace(rowID); rowID != "" {
+		query += ` AND c.row_id = ?`
+		args = append(args, rowID)
+	}
+	if workspaceID = strings.TrimSpace(workspaceID); workspaceID != "" {
+		query += ` AND c.workspace_id = ?`
+		args = append(args, workspaceID)
+	}
+	query += ` ORDER BY c.row_id ASC`
+	return s.queryChanges(ctx, query, args...)
+}
+
+func (s *Store) queryChanges(ctx context.Context, query string, args ...any) ([]ChangeRecord, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list changes: %w", err)
+	}
+	defer rows.Close()
+	records := []ChangeRecord{}
+	for rows.Next() {
+		var (
+			record      ChangeRecord
+			workspaceID sql.NullString
+			rowJSON     string
+			changedAt   int64
+		)
+		if err := rows.Scan(&record.ID, &record.Table, &record.RowID, &workspaceID, &record.Operation, &rowJSON, &changedAt); err != nil {
+			return nil, fmt.Errorf("scan change: %w", err)
+		}
+		record.WorkspaceID = workspaceID.String
+		record.ChangedAt = time.Unix(changedAt, 0).UTC()
+		if err := json.Unmarshal([]byte(rowJSON), &record.Row); err != nil {
+			return nil, fmt.Errorf("decode change %d: %w", record.ID, err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate changes: %w", err)
+	}
+	return records, nil
+}
+
+func (s *Store) tableColumns(ctx context.Context, table string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?) ORDER BY cid`, table)
+	if err != nil {
+		return nil, fmt.Errorf("read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+	columns := []string{}
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("scan column of %s: %w", table, err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate columns of %s: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("read columns of %s: table not found", table)
+	}
+	return columns, nil
+}
+
+func isChangeLogTable(table string) bool {
+	for _, candidate := range ChangeLogTables {
+		if candidate == table {
+			return true
+		}
+	}
+	return false
+}
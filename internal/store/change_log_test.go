@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChangeLogReconstructsRowsAsOfATime(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.EnableChangeLog(ctx); err != nil {
+		t.Fatalf("enable change log: %v", err)
+	}
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID: "task-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general",
+		Title: "Triage", Prompt: "triage", Status: "queued", RouteClass: "question", AssignedLane: "support",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	// Backdate the insert so the reroute lands at a later second.
+	created := time.Now().UTC().Add(-time.Hour)
+	if _, err := sqlStore.db.ExecContext(ctx, `UPDATE change_log SET changed_at_unix = ?`, created.Unix()); err != nil {
+		t.Fatalf("backdate change: %v", err)
+	}
+	if _, err := sqlStore.UpdateTaskRouting(ctx, UpdateTaskRoutingInput{ID: "task-1", RouteClass: "incident", Priority: "p1", AssignedLane: "operations"}); err != nil {
+		t.Fatalf("reroute task: %v", err)
+	}
+
+	before, err := sqlStore.StateAsOf(ctx, "tasks", "", "task-1", created.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("state as of: %v", err)
+	}
+	if len(before) != 1 || before[0].Row["assigned_lane"] != "support" || before[0].Operation != "insert" {
+		t.Fatalf("expected the original routing, got %+v", before)
+	}
+	now, err := sqlStore.StateAsOf(ctx, "tasks", "ws-1", "", time.Now().UTC())
+	if err != nil {
+		t.Fatalf("state now: %v", err)
+	}
+	if len(now) != 1 || now[0].Row["assigned_lane"] != "operations" || now[0].Row["route_class"] != "incident" {
+		t.Fatalf("expected the rerouted task, got %+v", now)
+	}
+	if earlier, _ := sqlStore.StateAsOf(ctx, "tasks", "", "", created.Add(-time.Minute)); len(earlier) != 0 {
+		t.Fatalf("expected nothing before the task existed, got %+v", earlier)
+	}
+
+	history, err := sqlStore.ListChanges(ctx, ListChangesInput{Table: "tasks", RowID: "task-1"})
+	if err != nil {
+		t.Fatalf("list changes: %v", err)
+	}
+	if len(history) != 2 || history[0].Operation != "insert" || history[1].Operation != "update" || history[1].WorkspaceID != "ws-1" {
+		t.Fatalf("unexpected history %+v", history)
+	}
+
+	if _, err := sqlStore.db.ExecContext(ctx, `DELETE FROM tasks WHERE id = 'task-1'`); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	if gone, _ := sqlStore.StateAsOf(ctx, "tasks", "", "task-1", time.Now().UTC()); len(gone) != 0 {
+		t.Fatalf("expected deleted task to drop out, got %+v", gone)
+	}
+	if _, err := sqlStore.StateAsOf(ctx, "users", "", "", time.Now()); !errors.Is(err, ErrChangeLogTableUnknown) {
+		t.Fatalf("expected unknown table error, got %v", err)
+	}
+
+	pruned, err := sqlStore.PruneChangeLog(ctx, created.Add(time.Second))
+	if err != nil || pruned != 1 {
+		t.Fatalf("expected the backdated entry pruned, got %d %v", pruned, err)
+	}
+}
+
+func TestDisableChangeLogStopsCapture(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.EnableChangeLog(ctx); err != nil {
+		t.Fatalf("enable change log: %v", err)
+	}
+	if err := sqlStore.DisableChangeLog(ctx); err != nil {
+		t.Fatalf("disable change log: %v", err)
+	}
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{ID: "task-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general", Title: "t", Prompt: "p", Status: "queued"}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	changes, err := sqlStore.ListChanges(ctx, ListChangesInput{})
+	if err != nil {
+		t.Fatalf("list changes: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("expected no captured changes, got %+v", changes)
+	}
+}
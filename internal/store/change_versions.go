@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// changeVersionSources are the tables whose writes bump a change version,
+// keyed by the kind clients see.
+var changeVersionSources = []struct {
+	kind  string
+	table string
+}{
+	{kind: "task", table: "tasks"},
+	{kind: "approval", table: "action_approvals"},
+	{kind: "objective", table: "objectives"},
+}
+
+// ChangeVersion counts the writes to one kind of record in one workspace.
+// Clients compare versions to learn that something changed without reading
+// the records themselves.
+type ChangeVersion struct {
+	Kind        string
+	WorkspaceID string
+	Version     int64
+}
+
+// migrateChangeVersions creates change_versions and the triggers that bump
+// it on every insert, update and delete, leaving out updates of bookkeeping
+// columns alone. Unlike the change log these are always on: each write
+// costs one upsert of a single small row. The triggers are rebuilt each
+// time so columns added by later migrations are watched too.
+func (s *Store) migrateChangeVersions(ctx context.Context) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS change_versions (
+			kind TEXT NOT NULL,
+			workspace_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			PRIMARY KEY (kind, workspace_id)
+		);`,
+	}
+	for _, source := range changeVersionSources {
+		columns, err := s.tableColumns(ctx, source.table)
+		if err != nil {
+			return err
+		}
+		for _, operation := range changeLogOperations {
+			name := fmt.Sprintf("change_versions_%s_%s", source.table, operation.suffix)
+			queries = append(queries,
+				`DROP TRIGGER IF EXISTS `+name,
+				fmt.Sprintf(
+					`CREATE TRIGGER %s AFTER %s ON %s BEGIN
+						INSERT INTO change_versions (kind, workspace_id, version) VALUES ('%s', COALESCE(%s.workspace_id, ''), 1)
+						ON CONFLICT(kind, workspace_id) DO UPDATE SET version = version + 1;
+					END;`,
+					name, changeTriggerEvent(source.table, columns, operation.event), source.table, source.kind, operation.row,
+				),
+			)
+		}
+	}
+	for _, query := range queries {
+		if _, err := s.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("run change versions migration: %w", err)
+		}
+	}
+	return nil
+}
+
+// ChangeVersions returns the current version of every kind and workspace
+// that has been written to. A scoped caller only sees its own workspace.
+func (s *Store) ChangeVersions(ctx context.Context) ([]ChangeVersion, error) {
+	query := `SELECT kind, workspace_id, version FROM change_versions`
+	args := []any{}
+	if scope, ok := WorkspaceScopeFromContext(ctx); ok {
+		query += ` WHERE workspace_id = ?`
+		args = append(args, scope)
+	}
+	query += ` ORDER BY kind, workspace_id`
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list change versions: %w", err)
+	}
+	defer rows.Close()
+	versions := []ChangeVersion{}
+	for rows.Next() {
+		var record ChangeVersion
+		if err := rows.Scan(&record.Kind, &record.WorkspaceID, &record.Version); err != nil {
+			return nil, fmt.Errorf("scan change version: %w", err)
+		}
+		versions = append(versions, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate change versions: %w", err)
+	}
+	return versions, nil
+}
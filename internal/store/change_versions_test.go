@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChangeVersionsBumpOnTaskWrites(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	for _, input := range []CreateTaskInput{
+		{ID: "task-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general", Title: "One", Prompt: "do it", Status: "queued"},
+		{ID: "task-2", WorkspaceID: "ws-2", ContextID: "ctx-2", Kind: "general", Title: "Two", Prompt: "do it", Status: "queued"},
+	} {
+		if err := sqlStore.CreateTask(ctx, input); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-1", 1, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+
+	versions, err := sqlStore.ChangeVersions(ctx)
+	if err != nil {
+		t.Fatalf("change versions: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected one version per workspace, got %+v", versions)
+	}
+	if versions[0] != (ChangeVersion{Kind: "task", WorkspaceID: "ws-1", Version: 2}) {
+		t.Fatalf("expected insert and update to bump ws-1 twice, got %+v", versions[0])
+	}
+	if versions[1] != (ChangeVersion{Kind: "task", WorkspaceID: "ws-2", Version: 1}) {
+		t.Fatalf("unexpected ws-2 version %+v", versions[1])
+	}
+
+	scoped, err := sqlStore.ChangeVersions(WithWorkspaceScope(ctx, "ws-2"))
+	if err != nil {
+		t.Fatalf("scoped change versions: %v", err)
+	}
+	if len(scoped) != 1 || scoped[0].WorkspaceID != "ws-2" {
+		t.Fatalf("expected only the scoped workspace, got %+v", scoped)
+	}
+}
+
+func TestChangeVersionsIgnoreLeaseBookkeeping(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{ID: "task-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general", Title: "One", Prompt: "do it", Status: "queued"}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	now := time.Now().UTC()
+	if claimed, err := sqlStore.ClaimTaskLease(ctx, "task-1", "runtime-a", time.Minute, now); err != nil || !claimed {
+		t.Fatalf("claim lease: %v, %v", claimed, err)
+	}
+	if err := sqlStore.RenewTaskLease(ctx, "task-1", "runtime-a", time.Minute, now.Add(time.Second)); err != nil {
+		t.Fatalf("renew lease: %v", err)
+	}
+	if err := sqlStore.ReleaseTaskLease(ctx, "task-1", "runtime-a"); err != nil {
+		t.Fatalf("release lease: %v", err)
+	}
+
+	versions, err := sqlStore.ChangeVersions(ctx)
+	if err != nil {
+		t.Fatalf("change versions: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != 1 {
+		t.Fatalf("expected leases not to bump the version, got %+v", versions)
+	}
+}
@@ -33,6 +33,17 @@ type ContextPolicy struct {
 	WorkspaceID  string
 	IsAdmin      bool
 	SystemPrompt string
+	VoiceReplies bool
+	// SharedKnowledge adds the shared knowledge workspace to the context's
+	// knowledge searches.
+	SharedKnowledge bool
+	// Silenced stops the agent from answering ordinary messages in the
+	// context; commands still work.
+	Silenced bool
+	// Language is the code of the language gateway replies use in the
+	// context; empty means English.
+	Language string
+	Revision int
 }
 
 type ContextDelivery struct {
@@ -110,7 +121,7 @@ func (s *Store) SetContextAdminByExternal(ctx context.Context, connector, extern
 	}
 	if _, err := s.db.ExecContext(
 		ctx,
-		`UPDATE contexts SET is_admin = ? WHERE id = ?`,
+		`UPDATE contexts SET is_admin = ?, revision = revision + 1 WHERE id = ?`,
 		flag,
 		contextRecord.ID,
 	); err != nil {
@@ -123,28 +134,31 @@ func (s *Store) SetContextAdminByExternal(ctx context.Context, connector, extern
 func (s *Store) LookupContextPolicy(ctx context.Context, contextID string) (ContextPolicy, error) {
 	row := s.db.QueryRowContext(
 		ctx,
-		`SELECT id, workspace_id, is_admin, system_prompt
+		`SELECT id, workspace_id, is_admin, system_prompt, voice_replies, shared_knowledge, silenced, language, revision
 		 FROM contexts
 		 WHERE id = ?`,
 		strings.TrimSpace(contextID),
 	)
 
 	var record ContextPolicy
-	var isAdminInt int
-	if err := row.Scan(&record.ContextID, &record.WorkspaceID, &isAdminInt, &record.SystemPrompt); err != nil {
+	var isAdminInt, voiceRepliesInt, sharedKnowledgeInt, silencedInt int
+	if err := row.Scan(&record.ContextID, &record.WorkspaceID, &isAdminInt, &record.SystemPrompt, &voiceRepliesInt, &sharedKnowledgeInt, &silencedInt, &record.Language, &record.Revision); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ContextPolicy{}, ErrContextNotFound
 		}
 		return ContextPolicy{}, fmt.Errorf("lookup context policy: %w", err)
 	}
 	record.IsAdmin = isAdminInt == 1
+	record.VoiceReplies = voiceRepliesInt == 1
+	record.SharedKnowledge = sharedKnowledgeInt == 1
+	record.Silenced = silencedInt == 1
 	return record, nil
 }
 
 func (s *Store) LookupContextPolicyByExternal(ctx context.Context, connector, externalID string) (ContextPolicy, error) {
 	row := s.db.QueryRowContext(
 		ctx,
-		`SELECT id, workspace_id, is_admin, system_prompt
+		`SELECT id, workspace_id, is_admin, system_prompt, voice_replies, shared_knowledge, silenced, language, revision
 		 FROM contexts
 		 WHERE connector = ? AND external_id = ?`,
 		strings.ToLower(strings.TrimSpace(connector)),
@@ -152,30 +166,122 @@ func (s *Store) LookupContextPolicyByExternal(ctx context.Context, connector, ex
 	)
 
 	var record ContextPolicy
-	var isAdminInt int
-	if err := row.Scan(&record.ContextID, &record.WorkspaceID, &isAdminInt, &record.SystemPrompt); err != nil {
+	var isAdminInt, voiceRepliesInt, sharedKnowledgeInt, silencedInt int
+	if err := row.Scan(&record.ContextID, &record.WorkspaceID, &isAdminInt, &record.SystemPrompt, &voiceRepliesInt, &sharedKnowledgeInt, &silencedInt, &record.Language, &record.Revision); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ContextPolicy{}, ErrContextNotFound
 		}
 		return ContextPolicy{}, fmt.Errorf("lookup context policy by external: %w", err)
 	}
 	record.IsAdmin = isAdminInt == 1
+	record.VoiceReplies = voiceRepliesInt == 1
+	record.SharedKnowledge = sharedKnowledgeInt == 1
+	record.Silenced = silencedInt == 1
 	return record, nil
 }
 
-func (s *Store) SetContextSystemPromptByExternal(ctx context.Context, connector, externalID, prompt string) (ContextPolicy, error) {
+// SetContextSystemPromptByExternal replaces a context's system prompt. A
+// non-zero expectedRevision refuses the write with ErrRevisionConflict if the
+// policy changed after the caller read it.
+func (s *Store) SetContextSystemPromptByExternal(ctx context.Context, connector, externalID, prompt string, expectedRevision int) (ContextPolicy, error) {
 	contextRecord, err := s.EnsureContextForExternalChannel(ctx, connector, externalID, externalID)
 	if err != nil {
 		return ContextPolicy{}, err
 	}
 	prompt = strings.TrimSpace(prompt)
+	query := `UPDATE contexts SET system_prompt = ?, revision = revision + 1 WHERE id = ?`
+	args := []any{prompt, contextRecord.ID}
+	if expectedRevision > 0 {
+		query += ` AND revision = ?`
+		args = append(args, expectedRevision)
+	}
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return ContextPolicy{}, fmt.Errorf("update context system prompt: %w", err)
+	}
+	if err := requireRevisionWrite(result); err != nil {
+		return ContextPolicy{}, err
+	}
+	return s.LookupContextPolicy(ctx, contextRecord.ID)
+}
+
+func (s *Store) SetContextVoiceRepliesByExternal(ctx context.Context, connector, externalID string, enabled bool) (ContextPolicy, error) {
+	contextRecord, err := s.EnsureContextForExternalChannel(ctx, connector, externalID, externalID)
+	if err != nil {
+		return ContextPolicy{}, err
+	}
+	flag := 0
+	if enabled {
+		flag = 1
+	}
 	if _, err := s.db.ExecContext(
 		ctx,
-		`UPDATE contexts SET system_prompt = ? WHERE id = ?`,
-		prompt,
+		`UPDATE contexts SET voice_replies = ?, revision = revision + 1 WHERE id = ?`,
+		flag,
 		contextRecord.ID,
 	); err != nil {
-		return ContextPolicy{}, fmt.Errorf("update context system prompt: %w", err)
+		return ContextPolicy{}, fmt.Errorf("update context voice replies: %w", err)
+	}
+	return s.LookupContextPolicy(ctx, contextRecord.ID)
+}
+
+func (s *Store) SetContextSharedKnowledgeByExternal(ctx context.Context, connector, externalID string, enabled bool) (ContextPolicy, error) {
+	contextRecord, err := s.EnsureContextForExternalChannel(ctx, connector, externalID, externalID)
+	if err != nil {
+		return ContextPolicy{}, err
+	}
+	flag := 0
+	if enabled {
+		flag = 1
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`UPDATE contexts SET shared_knowledge = ?, revision = revision + 1 WHERE id = ?`,
+		flag,
+		contextRecord.ID,
+	); err != nil {
+		return ContextPolicy{}, fmt.Errorf("update context shared knowledge: %w", err)
+	}
+	return s.LookupContextPolicy(ctx, contextRecord.ID)
+}
+
+// SetContextSilencedByExternal turns the agent's replies to ordinary
+// messages in a context off or back on without removing the context.
+func (s *Store) SetContextSilencedByExternal(ctx context.Context, connector, externalID string, silenced bool) (ContextPolicy, error) {
+	contextRecord, err := s.EnsureContextForExternalChannel(ctx, connector, externalID, externalID)
+	if err != nil {
+		return ContextPolicy{}, err
+	}
+	flag := 0
+	if silenced {
+		flag = 1
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`UPDATE contexts SET silenced = ?, revision = revision + 1 WHERE id = ?`,
+		flag,
+		contextRecord.ID,
+	); err != nil {
+		return ContextPolicy{}, fmt.Errorf("update context silenced: %w", err)
+	}
+	return s.LookupContextPolicy(ctx, contextRecord.ID)
+}
+
+// SetContextLanguageByExternal sets the language of gateway replies in a
+// context. The caller checks that the language is supported; "" resets
+// replies to English.
+func (s *Store) SetContextLanguageByExternal(ctx context.Context, connector, externalID, language string) (ContextPolicy, error) {
+	contextRecord, err := s.EnsureContextForExternalChannel(ctx, connector, externalID, externalID)
+	if err != nil {
+		return ContextPolicy{}, err
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`UPDATE contexts SET language = ?, revision = revision + 1 WHERE id = ?`,
+		strings.ToLower(strings.TrimSpace(language)),
+		contextRecord.ID,
+	); err != nil {
+		return ContextPolicy{}, fmt.Errorf("update context language: %w", err)
 	}
 	return s.LookupContextPolicy(ctx, contextRecord.ID)
 }
@@ -9,7 +9,7 @@ func TestSetAndLookupContextSystemPrompt(t *testing.T) {
 	sqlStore := newTestStore(t)
 	ctx := context.Background()
 
-	policy, err := sqlStore.SetContextSystemPromptByExternal(ctx, "telegram", "42", "You are an ops assistant")
+	policy, err := sqlStore.SetContextSystemPromptByExternal(ctx, "telegram", "42", "You are an ops assistant", 0)
 	if err != nil {
 		t.Fatalf("set context system prompt: %v", err)
 	}
@@ -32,6 +32,72 @@ func TestSetAndLookupContextSystemPrompt(t *testing.T) {
 	}
 }
 
+func TestSetContextVoiceReplies(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	policy, err := sqlStore.SetContextVoiceRepliesByExternal(ctx, "telegram", "42", true)
+	if err != nil {
+		t.Fatalf("enable voice replies: %v", err)
+	}
+	if !policy.VoiceReplies {
+		t.Fatal("expected voice replies enabled")
+	}
+	loaded, err := sqlStore.LookupContextPolicyByExternal(ctx, "telegram", "42")
+	if err != nil {
+		t.Fatalf("lookup context policy: %v", err)
+	}
+	if !loaded.VoiceReplies {
+		t.Fatal("expected persisted voice replies flag")
+	}
+	policy, err = sqlStore.SetContextVoiceRepliesByExternal(ctx, "telegram", "42", false)
+	if err != nil || policy.VoiceReplies {
+		t.Fatalf("expected voice replies disabled, got %+v %v", policy, err)
+	}
+}
+
+func TestSetContextSharedKnowledge(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	policy, err := sqlStore.SetContextSharedKnowledgeByExternal(ctx, "discord", "chan-1", true)
+	if err != nil {
+		t.Fatalf("enable shared knowledge: %v", err)
+	}
+	if !policy.SharedKnowledge || policy.VoiceReplies {
+		t.Fatalf("expected only shared knowledge enabled, got %+v", policy)
+	}
+	loaded, err := sqlStore.LookupContextPolicy(ctx, policy.ContextID)
+	if err != nil || !loaded.SharedKnowledge {
+		t.Fatalf("expected persisted shared knowledge flag, got %+v %v", loaded, err)
+	}
+	policy, err = sqlStore.SetContextSharedKnowledgeByExternal(ctx, "discord", "chan-1", false)
+	if err != nil || policy.SharedKnowledge {
+		t.Fatalf("expected shared knowledge disabled, got %+v %v", policy, err)
+	}
+}
+
+func TestSetContextLanguage(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	policy, err := sqlStore.SetContextLanguageByExternal(ctx, "telegram", "100", " ES ")
+	if err != nil {
+		t.Fatalf("set language: %v", err)
+	}
+	if policy.Language != "es" {
+		t.Fatalf("expected normalized language es, got %+v", policy)
+	}
+	loaded, err := sqlStore.LookupContextPolicyByExternal(ctx, "telegram", "100")
+	if err != nil || loaded.Language != "es" {
+		t.Fatalf("expected persisted language, got %+v %v", loaded, err)
+	}
+	policy, err = sqlStore.SetContextLanguageByExternal(ctx, "telegram", "100", "")
+	if err != nil || policy.Language != "" {
+		t.Fatalf("expected language reset, got %+v %v", policy, err)
+	}
+}
+
 func TestLookupContextPolicyByExternal(t *testing.T) {
 	sqlStore := newTestStore(t)
 	ctx := context.Background()
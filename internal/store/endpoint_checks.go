@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EndpointCheck is one probe of a monitored endpoint.
+type EndpointCheck struct {
+	ID         string
+	Endpoint   string
+	URL        string
+	Up         bool
+	StatusCode int
+	LatencyMs  int64
+	Error      string
+	CheckedAt  time.Time
+}
+
+// EndpointUptime summarises an endpoint's checks over a window.
+type EndpointUptime struct {
+	Endpoint  string
+	Checks    int
+	UpChecks  int
+	LastCheck EndpointCheck
+}
+
+// Percent is the share of checks that found the endpoint up, or -1 when
+// there were no checks in the window.
+func (u EndpointUptime) Percent() float64 {
+	if u.Checks == 0 {
+		return -1
+	}
+	return float64(u.UpChecks) * 100 / float64(u.Checks)
+}
+
+func (s *Store) RecordEndpointCheck(ctx context.Context, check EndpointCheck) (EndpointCheck, error) {
+	check.Endpoint = strings.TrimSpace(check.Endpoint)
+	check.URL = strings.TrimSpace(check.URL)
+	if check.Endpoint == "" || check.URL == "" {
+		return EndpointCheck{}, fmt.Errorf("endpoint name and url are required")
+	}
+	if check.CheckedAt.IsZero() {
+		check.CheckedAt = time.Now().UTC()
+	}
+	check.ID = "chk_" + uuid.NewString()
+	up := 0
+	if check.Up {
+		up = 1
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO endpoint_checks (id, endpoint, url, up, status_code, latency_ms, error, checked_at_unix)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		check.ID,
+		check.Endpoint,
+		check.URL,
+		up,
+		check.StatusCode,
+		check.LatencyMs,
+		strings.TrimSpace(check.Error),
+		check.CheckedAt.Unix(),
+	); err != nil {
+		return EndpointCheck{}, fmt.Errorf("insert endpoint check: %w", err)
+	}
+	return check, nil
+}
+
+// LookupEndpointUptime counts an endpoint's checks since a point in time and
+// returns the latest one.
+func (s *Store) LookupEndpointUptime(ctx context.Context, endpoint string, since time.Time) (EndpointUptime, error) {
+	uptime := EndpointUptime{Endpoint: strings.TrimSpace(endpoint)}
+	var upChecks sql.NullInt64
+	if err := s.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*), SUM(up) FROM endpoint_checks WHERE endpoint = ? AND checked_at_unix >= ?`,
+		uptime.Endpoint,
+		since.Unix(),
+	).Scan(&uptime.Checks, &upChecks); err != nil {
+		return EndpointUptime{}, fmt.Errorf("count endpoint checks: %w", err)
+	}
+	uptime.UpChecks = int(upChecks.Int64)
+
+	var last EndpointCheck
+	var up int
+	var errorText sql.NullString
+	var checkedAtUnix int64
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, endpoint, url, up, status_code, latency_ms, error, checked_at_unix
+		 FROM endpoint_checks
+		 WHERE endpoint = ?
+		 ORDER BY checked_at_unix DESC, rowid DESC
+		 LIMIT 1`,
+		uptime.Endpoint,
+	).Scan(&last.ID, &last.Endpoint, &last.URL, &up, &last.StatusCode, &last.LatencyMs, &errorText, &checkedAtUnix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uptime, nil
+	}
+	if err != nil {
+		return EndpointUptime{}, fmt.Errorf("lookup last endpoint check: %w", err)
+	}
+	last.Up = up == 1
+	last.Error = errorText.String
+	last.CheckedAt = time.Unix(checkedAtUnix, 0).UTC()
+	uptime.LastCheck = last
+	return uptime, nil
+}
+
+// PruneEndpointChecks deletes checks older than the cutoff and returns how
+// many were removed.
+func (s *Store) PruneEndpointChecks(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM endpoint_checks WHERE checked_at_unix < ?`, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("prune endpoint checks: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("prune endpoint checks: %w", err)
+	}
+	return removed, nil
+}
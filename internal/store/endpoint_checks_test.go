@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEndpointUptimeCountsChecksInWindow(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	record := func(up bool, at time.Time) {
+		t.Helper()
+		if _, err := sqlStore.RecordEndpointCheck(ctx, EndpointCheck{Endpoint: "api", URL: "https://api.example.com/health", Up: up, StatusCode: 200, CheckedAt: at}); err != nil {
+			t.Fatalf("record check: %v", err)
+		}
+	}
+	record(false, now.Add(-48*time.Hour))
+	record(true, now.Add(-3*time.Hour))
+	record(true, now.Add(-2*time.Hour))
+	record(true, now.Add(-time.Hour))
+	record(false, now)
+
+	uptime, err := sqlStore.LookupEndpointUptime(ctx, "api", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("lookup uptime: %v", err)
+	}
+	if uptime.Checks != 4 || uptime.UpChecks != 3 || uptime.Percent() != 75 {
+		t.Fatalf("unexpected uptime %+v (%.1f%%)", uptime, uptime.Percent())
+	}
+	if uptime.LastCheck.Up || uptime.LastCheck.CheckedAt.Unix() != now.Unix() {
+		t.Fatalf("expected latest check to be the failing one, got %+v", uptime.LastCheck)
+	}
+
+	removed, err := sqlStore.PruneEndpointChecks(ctx, now.Add(-24*time.Hour))
+	if err != nil || removed != 1 {
+		t.Fatalf("expected one pruned check, got %d (%v)", removed, err)
+	}
+
+	empty, err := sqlStore.LookupEndpointUptime(ctx, "unknown", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("lookup unknown uptime: %v", err)
+	}
+	if empty.Checks != 0 || empty.Percent() != -1 || !empty.LastCheck.CheckedAt.IsZero() {
+		t.Fatalf("expected empty uptime, got %+v", empty)
+	}
+}
@@ -0,0 +1,21 @@
+package store
+
+import "context"
+
+// EventPublisher hears about changes other systems may want to react to:
+// task.created, approval.pending, approval.executed and agent.blocked. It
+// must not block; data holds ids and short fields, never prompts or message
+// text.
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, eventType, workspaceID string, data map[string]any)
+}
+
+func (s *Store) SetEventPublisher(publisher EventPublisher) {
+	s.events = publisher
+}
+
+func (s *Store) publishEvent(ctx context.Context, eventType, workspaceID string, data map[string]any) {
+	if s.events != nil {
+		s.events.PublishEvent(ctx, eventType, workspaceID, data)
+	}
+}
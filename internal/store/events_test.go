@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type recordedEvent struct {
+	eventType   string
+	workspaceID string
+	data        map[string]any
+}
+
+type recordingEventPublisher struct {
+	events []recordedEvent
+}
+
+func (p *recordingEventPublisher) PublishEvent(ctx context.Context, eventType, workspaceID string, data map[string]any) {
+	p.events = append(p.events, recordedEvent{eventType: eventType, workspaceID: workspaceID, data: data})
+}
+
+func TestStorePublishesEvents(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	publisher := &recordingEventPublisher{}
+	sqlStore.SetEventPublisher(publisher)
+
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-1",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Review docs",
+		Prompt:      "Review inbox markdown and summarize actions",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	approval, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+		WorkspaceID:     "ws-1",
+		ContextID:       "ctx-1",
+		Connector:       "telegram",
+		ExternalID:      "42",
+		RequesterUserID: "user-1",
+		ActionType:      "send_email",
+		ActionTarget:    "ops@example.com",
+	})
+	if err != nil {
+		t.Fatalf("create action approval: %v", err)
+	}
+	if _, err := sqlStore.UpdateActionExecution(ctx, UpdateActionExecutionInput{ID: approval.ID, ExecutionStatus: "succeeded"}); err != nil {
+		t.Fatalf("update action execution: %v", err)
+	}
+	for _, blocked := range []bool{false, true} {
+		if _, err := sqlStore.CreateAgentAuditEvent(ctx, CreateAgentAuditEventInput{
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Connector:   "telegram",
+			ExternalID:  "42",
+			EventType:   "egress_blocked",
+			Stage:       "audit.egress_blocked",
+			ToolName:    "curl",
+			Blocked:     blocked,
+		}); err != nil {
+			t.Fatalf("create audit event: %v", err)
+		}
+	}
+
+	types := []string{}
+	for _, event := range publisher.events {
+		if event.workspaceID != "ws-1" {
+			t.Fatalf("expected workspace ws-1, got %+v", event)
+		}
+		types = append(types, event.eventType)
+	}
+	expected := []string{"task.created", "approval.pending", "approval.executed", "agent.blocked"}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("expected events %v, got %v", expected, types)
+	}
+	if _, ok := publisher.events[0].data["prompt"]; ok {
+		t.Fatal("expected task events to leave the prompt out")
+	}
+	if publisher.events[2].data["execution_status"] != "succeeded" || publisher.events[3].data["tool_name"] != "curl" {
+		t.Fatalf("unexpected event data %+v", publisher.events)
+	}
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContextMemberPolicy is what one connector user may do in one context.
+// Muted users never trigger auto-triage there. Once any member of a context
+// is a task creator, only task creators may create tasks in it.
+type ContextMemberPolicy struct {
+	ContextID   string
+	UserID      string
+	Muted       bool
+	TaskCreator bool
+	UpdatedAt   time.Time
+}
+
+// ContextMemberAccess is the effective policy for a user in a context.
+type ContextMemberAccess struct {
+	Muted bool
+	// TasksRestricted reports that the context has a task-creator allowlist.
+	TasksRestricted bool
+	CanCreateTasks  bool
+}
+
+// SetContextMemberMuted mutes or unmutes a user in a context.
+func (s *Store) SetContextMemberMuted(ctx context.Context, contextID, userID string, muted bool) (ContextMemberPolicy, error) {
+	return s.updateContextMemberPolicy(ctx, contextID, userID, func(policy *ContextMemberPolicy) {
+		policy.Muted = muted
+	})
+}
+
+// SetContextTaskCreator adds a user to, or removes them from, a context's
+// task-creator allowlist.
+func (s *Store) SetContextTaskCreator(ctx context.Context, contextID, userID string, allowed bool) (ContextMemberPolicy, error) {
+	return s.updateContextMemberPolicy(ctx, contextID, userID, func(policy *ContextMemberPolicy) {
+		policy.TaskCreator = allowed
+	})
+}
+
+// ListContextMemberPolicies returns every member policy of a context,
+// ordered by user id.
+func (s *Store) ListContextMemberPolicies(ctx context.Context, contextID string) ([]ContextMemberPolicy, error) {
+	contextID = strings.TrimSpace(contextID)
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT context_id, user_id, muted, task_creator, updated_at_unix
+		 FROM context_member_policies
+		 WHERE context_id = ?
+		 ORDER BY user_id`,
+		contextID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list context member policies: %w", err)
+	}
+	defer rows.Close()
+	policies := []ContextMemberPolicy{}
+	for rows.Next() {
+		policy, err := scanContextMemberPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate context member policies: %w", err)
+	}
+	return policies, nil
+}
+
+// LookupContextMemberAccess returns whether a user is muted in a context and
+// whether they may create tasks there.
+func (s *Store) LookupContextMemberAccess(ctx context.Context, contextID, userID string) (ContextMemberAccess, error) {
+	var muted, taskCreator, creators int
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT
+		     COALESCE(MAX(CASE WHEN user_id = ? THEN muted END), 0),
+		     COALESCE(MAX(CASE WHEN user_id = ? THEN task_creator END), 0),
+		     COALESCE(SUM(task_creator), 0)
+		 FROM context_member_policies
+		 WHERE context_id = ?`,
+		strings.TrimSpace(userID),
+		strings.TrimSpace(userID),
+		strings.TrimSpace(contextID),
+	).Scan(&muted, &taskCreator, &creators)
+	if err != nil {
+		return ContextMemberAccess{}, fmt.Errorf("lookup context member access: %w", err)
+	}
+	return ContextMemberAccess{
+		Muted:           muted == 1,
+		TasksRestricted: creators > 0,
+		CanCreateTasks:  creators == 0 || taskCreator == 1,
+	}, nil
+}
+
+// updateContextMemberPolicy applies change to a member's stored policy and
+// drops the record once it no longer restricts or allows anything.
+func (s *Store) updateContextMemberPolicy(ctx context.Context, contextID, userID string, change func(*ContextMemberPolicy)) (ContextMemberPolicy, error) {
+	contextID = strings.TrimSpace(contextID)
+	userID = strings.TrimSpace(userID)
+	if contextID == "" || userID == "" {
+		return ContextMemberPolicy{}, fmt.Errorf("context id and user id are required")
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ContextMemberPolicy{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	policy, err := scanContextMemberPolicy(tx.QueryRowContext(
+		ctx,
+		`SELECT context_id, user_id, muted, task_creator, updated_at_unix
+		 FROM context_member_policies
+		 WHERE context_id = ? AND user_id = ?`,
+		contextID,
+		userID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		policy, err = ContextMemberPolicy{ContextID: contextID, UserID: userID}, nil
+	}
+	if err != nil {
+		return ContextMemberPolicy{}, err
+	}
+	change(&policy)
+	policy.UpdatedAt = time.Now().UTC()
+	if !policy.Muted && !policy.TaskCreator {
+		if _, err := tx.ExecContext(
+			ctx,
+			`DELETE FROM context_member_policies WHERE context_id = ? AND user_id = ?`,
+			contextID,
+			userID,
+		); err != nil {
+			return ContextMemberPolicy{}, fmt.Errorf("delete context member policy: %w", err)
+		}
+	} else if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO context_member_policies (context_id, user_id, muted, task_creator, updated_at_unix)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(context_id, user_id) DO UPDATE SET
+		     muted = excluded.muted,
+		     task_creator = excluded.task_creator,
+		     updated_at_unix = excluded.updated_at_unix`,
+		contextID,
+		userID,
+		boolToInt(policy.Muted),
+		boolToInt(policy.TaskCreator),
+		policy.UpdatedAt.Unix(),
+	); err != nil {
+		return ContextMemberPolicy{}, fmt.Errorf("set context member policy: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return ContextMemberPolicy{}, fmt.Errorf("commit context member policy: %w", err)
+	}
+	return policy, nil
+}
+
+type memberPolicyScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanContextMemberPolicy(row memberPolicyScanner) (ContextMemberPolicy, error) {
+	var policy ContextMemberPolicy
+	var muted, taskCreator int
+	var updatedAtUnix int64
+	if err := row.Scan(&policy.ContextID, &policy.UserID, &muted, &taskCreator, &updatedAtUnix); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ContextMemberPolicy{}, err
+		}
+		return ContextMemberPolicy{}, fmt.Errorf("scan context member policy: %w", err)
+	}
+	policy.Muted = muted == 1
+	policy.TaskCreator = taskCreator == 1
+	policy.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+	return policy, nil
+}
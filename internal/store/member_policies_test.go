@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextMemberPolicies(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	access, err := sqlStore.LookupContextMemberAccess(ctx, "ctx-1", "user-1")
+	if err != nil {
+		t.Fatalf("lookup access: %v", err)
+	}
+	if access.Muted || access.TasksRestricted || !access.CanCreateTasks {
+		t.Fatalf("expected unrestricted access by default, got %+v", access)
+	}
+
+	if _, err := sqlStore.SetContextMemberMuted(ctx, "ctx-1", "user-1", true); err != nil {
+		t.Fatalf("mute: %v", err)
+	}
+	if _, err := sqlStore.SetContextTaskCreator(ctx, "ctx-1", "user-2", true); err != nil {
+		t.Fatalf("allow tasks: %v", err)
+	}
+	access, err = sqlStore.LookupContextMemberAccess(ctx, "ctx-1", "user-1")
+	if err != nil {
+		t.Fatalf("lookup access: %v", err)
+	}
+	if !access.Muted || !access.TasksRestricted || access.CanCreateTasks {
+		t.Fatalf("expected muted user without task rights, got %+v", access)
+	}
+	if access, _ := sqlStore.LookupContextMemberAccess(ctx, "ctx-1", "user-2"); access.Muted || !access.CanCreateTasks {
+		t.Fatalf("expected allowlisted user to create tasks, got %+v", access)
+	}
+	if access, _ := sqlStore.LookupContextMemberAccess(ctx, "ctx-2", "user-1"); access.Muted || !access.CanCreateTasks {
+		t.Fatalf("expected policies scoped to their context, got %+v", access)
+	}
+
+	if _, err := sqlStore.SetContextMemberMuted(ctx, "ctx-1", "user-1", false); err != nil {
+		t.Fatalf("unmute: %v", err)
+	}
+	policies, err := sqlStore.ListContextMemberPolicies(ctx, "ctx-1")
+	if err != nil {
+		t.Fatalf("list policies: %v", err)
+	}
+	if len(policies) != 1 || policies[0].UserID != "user-2" || !policies[0].TaskCreator {
+		t.Fatalf("expected only the task creator to remain, got %+v", policies)
+	}
+}
+
+func TestSetContextSilencedByExternal(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	policy, err := sqlStore.SetContextSilencedByExternal(ctx, "discord", "chan-1", true)
+	if err != nil {
+		t.Fatalf("silence: %v", err)
+	}
+	if !policy.Silenced {
+		t.Fatalf("expected silenced policy, got %+v", policy)
+	}
+	policy, err = sqlStore.SetContextSilencedByExternal(ctx, "discord", "chan-1", false)
+	if err != nil || policy.Silenced {
+		t.Fatalf("expected silence lifted, got %+v (%v)", policy, err)
+	}
+}
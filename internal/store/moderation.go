@@ -0,0 +1,373 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrModerationCaseNotFound = errors.New("moderation case not found")
+	// ErrModerationAppealNotAllowed is returned when someone other than the
+	// moderated user appeals a decision.
+	ErrModerationAppealNotAllowed = errors.New("only the moderated user can appeal")
+	// ErrModerationCaseNotOpen is returned when appealing a decision that was
+	// already appealed, or resolving an appeal that was already resolved.
+	ErrModerationCaseNotOpen = errors.New("moderation case is not open")
+)
+
+const (
+	ModerationKindDecision = "decision"
+	ModerationKindAppeal   = "appeal"
+
+	ModerationStatusOpen       = "open"
+	ModerationStatusAppealed   = "appealed"
+	ModerationStatusUpheld     = "upheld"
+	ModerationStatusOverturned = "overturned"
+	ModerationStatusResolved   = "resolved"
+)
+
+// maxModerationEvidence bounds the stored message excerpt.
+const maxModerationEvidence = 2000
+
+// ModerationCase records a moderation decision about a user, or an appeal
+// against one. An appeal links to its decision through LinkedCaseID; the
+// decision's status moves from open to appealed, then to upheld or
+// overturned when an admin resolves the appeal.
+type ModerationCase struct {
+	ID            string
+	WorkspaceID   string
+	ContextID     string
+	Connector     string
+	ExternalID    string
+	SubjectUserID string
+	Kind          string
+	LinkedCaseID  string
+	// Action is what the runtime did: "held" for a message kept from the
+	// agent, "flagged" for a message routed to moderators.
+	Action     string
+	Reason     string
+	Evidence   string
+	TaskID     string
+	Status     string
+	ResolvedBy string
+	Resolution string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type CreateModerationCaseInput struct {
+	WorkspaceID   string
+	ContextID     string
+	Connector     string
+	ExternalID    string
+	SubjectUserID string
+	Action        string
+	Reason        string
+	Evidence      string
+	TaskID        string
+}
+
+type AppealModerationCaseInput struct {
+	CaseID string
+	UserID string
+	Text   string
+}
+
+type ResolveModerationAppealInput struct {
+	AppealID       string
+	ResolverUserID string
+	Uphold         bool
+	Note           string
+}
+
+// CreateModerationCase records a moderation decision.
+func (s *Store) CreateModerationCase(ctx context.Context, input CreateModerationCaseInput) (ModerationCase, error) {
+	now := time.Now().UTC()
+	record := ModerationCase{
+		ID:            "mod_" + uuid.NewString(),
+		WorkspaceID:   strings.TrimSpace(input.WorkspaceID),
+		ContextID:     strings.TrimSpace(input.ContextID),
+		Connector:     strings.ToLower(strings.TrimSpace(input.Connector)),
+		ExternalID:    strings.TrimSpace(input.ExternalID),
+		SubjectUserID: strings.TrimSpace(input.SubjectUserID),
+		Kind:          ModerationKindDecision,
+		Action:        strings.TrimSpace(input.Action),
+		Reason:        strings.TrimSpace(input.Reason),
+		Evidence:      clipModerationEvidence(input.Evidence),
+		TaskID:        strings.TrimSpace(input.TaskID),
+		Status:        ModerationStatusOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if record.WorkspaceID == "" || record.Connector == "" || record.ExternalID == "" || record.SubjectUserID == "" || record.Action == "" {
+		return ModerationCase{}, fmt.Errorf("missing required moderation case fields")
+	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, nil); err != nil {
+		return ModerationCase{}, err
+	}
+	if err := insertModerationCase(ctx, s.db, record); err != nil {
+		return ModerationCase{}, err
+	}
+	return record, nil
+}
+
+func (s *Store) LookupModerationCase(ctx context.Context, id string) (ModerationCase, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+moderationCaseColumns+` FROM moderation_cases WHERE id = ?`, strings.TrimSpace(id))
+	record, err := scanModerationCase(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ModerationCase{}, ErrModerationCaseNotFound
+	}
+	if err != nil {
+		return ModerationCase{}, err
+	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, ErrModerationCaseNotFound); err != nil {
+		return ModerationCase{}, err
+	}
+	return record, nil
+}
+
+// ListModerationDecisionsForUser returns the decisions about a user on a
+// connector, newest first.
+func (s *Store) ListModerationDecisionsForUser(ctx context.Context, connector, userID string, limit int) ([]ModerationCase, error) {
+	if limit < 1 {
+		limit = 10
+	}
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT `+moderationCaseColumns+` FROM moderation_cases
+		 WHERE connector = ? AND subject_user_id = ? AND kind = ?
+		 ORDER BY created_at_unix DESC, rowid DESC
+		 LIMIT ?`,
+		strings.ToLower(strings.TrimSpace(connector)),
+		strings.TrimSpace(userID),
+		ModerationKindDecision,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list moderation decisions: %w", err)
+	}
+	defer rows.Close()
+	cases := []ModerationCase{}
+	for rows.Next() {
+		record, err := scanModerationCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate moderation decisions: %w", err)
+	}
+	return cases, nil
+}
+
+// AppealModerationCase records the moderated user's appeal as a case linked
+// to the decision and marks the decision appealed. A decision can be
+// appealed once.
+func (s *Store) AppealModerationCase(ctx context.Context, input AppealModerationCaseInput) (decision, appeal ModerationCase, err error) {
+	decision, err = s.LookupModerationCase(ctx, input.CaseID)
+	if err != nil {
+		return ModerationCase{}, ModerationCase{}, err
+	}
+	if decision.Kind != ModerationKindDecision {
+		return ModerationCase{}, ModerationCase{}, ErrModerationCaseNotFound
+	}
+	if decision.SubjectUserID != strings.TrimSpace(input.UserID) {
+		return ModerationCase{}, ModerationCase{}, ErrModerationAppealNotAllowed
+	}
+	now := time.Now().UTC()
+	appeal = ModerationCase{
+		ID:            "mod_" + uuid.NewString(),
+		WorkspaceID:   decision.WorkspaceID,
+		ContextID:     decision.ContextID,
+		Connector:     decision.Connector,
+		ExternalID:    decision.ExternalID,
+		SubjectUserID: decision.SubjectUserID,
+		Kind:          ModerationKindAppeal,
+		LinkedCaseID:  decision.ID,
+		Action:        decision.Action,
+		Reason:        clipModerationEvidence(input.Text),
+		Status:        ModerationStatusOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ModerationCase{}, ModerationCase{}, fmt.Errorf("begin appeal: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE moderation_cases SET status = ?, updated_at_unix = ? WHERE id = ? AND status = ?`,
+		ModerationStatusAppealed,
+		now.Unix(),
+		decision.ID,
+		ModerationStatusOpen,
+	)
+	if err != nil {
+		return ModerationCase{}, ModerationCase{}, fmt.Errorf("mark moderation case appealed: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ModerationCase{}, ModerationCase{}, ErrModerationCaseNotOpen
+	}
+	if err := insertModerationCase(ctx, tx, appeal); err != nil {
+		return ModerationCase{}, ModerationCase{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return ModerationCase{}, ModerationCase{}, fmt.Errorf("commit appeal: %w", err)
+	}
+	decision.Status = ModerationStatusAppealed
+	decision.UpdatedAt = now
+	return decision, appeal, nil
+}
+
+// ResolveModerationAppeal closes an open appeal and sets its decision to
+// upheld or overturned.
+func (s *Store) ResolveModerationAppeal(ctx context.Context, input ResolveModerationAppealInput) (decision, appeal ModerationCase, err error) {
+	appeal, err = s.LookupModerationCase(ctx, input.AppealID)
+	if err != nil {
+		return ModerationCase{}, ModerationCase{}, err
+	}
+	if appeal.Kind != ModerationKindAppeal {
+		return ModerationCase{}, ModerationCase{}, ErrModerationCaseNotFound
+	}
+	resolverUserID := strings.TrimSpace(input.ResolverUserID)
+	if resolverUserID == "" {
+		return ModerationCase{}, ModerationCase{}, fmt.Errorf("resolver user id is required")
+	}
+	outcome := ModerationStatusOverturned
+	if input.Uphold {
+		outcome = ModerationStatusUpheld
+	}
+	note := strings.TrimSpace(input.Note)
+	now := time.Now().UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ModerationCase{}, ModerationCase{}, fmt.Errorf("begin appeal resolution: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE moderation_cases SET status = ?, resolved_by = ?, resolution = ?, updated_at_unix = ? WHERE id = ? AND status = ?`,
+		ModerationStatusResolved,
+		resolverUserID,
+		outcome,
+		now.Unix(),
+		appeal.ID,
+		ModerationStatusOpen,
+	)
+	if err != nil {
+		return ModerationCase{}, ModerationCase{}, fmt.Errorf("resolve moderation appeal: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ModerationCase{}, ModerationCase{}, ErrModerationCaseNotOpen
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE moderation_cases SET status = ?, resolved_by = ?, resolution = ?, updated_at_unix = ? WHERE id = ?`,
+		outcome,
+		resolverUserID,
+		note,
+		now.Unix(),
+		appeal.LinkedCaseID,
+	); err != nil {
+		return ModerationCase{}, ModerationCase{}, fmt.Errorf("update appealed moderation case: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return ModerationCase{}, ModerationCase{}, fmt.Errorf("commit appeal resolution: %w", err)
+	}
+	appeal.Status = ModerationStatusResolved
+	appeal.ResolvedBy = resolverUserID
+	appeal.Resolution = outcome
+	appeal.UpdatedAt = now
+	decision, err = s.LookupModerationCase(ctx, appeal.LinkedCaseID)
+	if err != nil {
+		return ModerationCase{}, ModerationCase{}, err
+	}
+	return decision, appeal, nil
+}
+
+type moderationCaseExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertModerationCase(ctx context.Context, execer moderationCaseExecer, record ModerationCase) error {
+	if _, err := execer.ExecContext(
+		ctx,
+		`INSERT INTO moderation_cases (
+			id, workspace_id, context_id, connector, external_id, subject_user_id, kind, linked_case_id, action, reason, evidence, task_id, status, resolved_by, resolution, created_at_unix, updated_at_unix
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '', '', ?, ?)`,
+		record.ID,
+		record.WorkspaceID,
+		record.ContextID,
+		record.Connector,
+		record.ExternalID,
+		record.SubjectUserID,
+		record.Kind,
+		record.LinkedCaseID,
+		record.Action,
+		record.Reason,
+		record.Evidence,
+		record.TaskID,
+		record.Status,
+		record.CreatedAt.Unix(),
+		record.UpdatedAt.Unix(),
+	); err != nil {
+		return fmt.Errorf("insert moderation case: %w", err)
+	}
+	return nil
+}
+
+func clipModerationEvidence(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if len(trimmed) <= maxModerationEvidence {
+		return trimmed
+	}
+	return strings.TrimSpace(trimmed[:maxModerationEvidence]) + "..."
+}
+
+const moderationCaseColumns = `id, workspace_id, context_id, connector, external_id, subject_user_id, kind, linked_case_id, action, reason, evidence, task_id, status, resolved_by, resolution, created_at_unix, updated_at_unix`
+
+type moderationCaseScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanModerationCase(scanner moderationCaseScanner) (ModerationCase, error) {
+	var record ModerationCase
+	var createdAtUnix, updatedAtUnix int64
+	if err := scanner.Scan(
+		&record.ID,
+		&record.WorkspaceID,
+		&record.ContextID,
+		&record.Connector,
+		&record.ExternalID,
+		&record.SubjectUserID,
+		&record.Kind,
+		&record.LinkedCaseID,
+		&record.Action,
+		&record.Reason,
+		&record.Evidence,
+		&record.TaskID,
+		&record.Status,
+		&record.ResolvedBy,
+		&record.Resolution,
+		&createdAtUnix,
+		&updatedAtUnix,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ModerationCase{}, err
+		}
+		return ModerationCase{}, fmt.Errorf("scan moderation case: %w", err)
+	}
+	record.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+	record.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+	return record, nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestModerationAppealLifecycle(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	decision, err := sqlStore.CreateModerationCase(ctx, CreateModerationCaseInput{
+		WorkspaceID:   "ws-1",
+		ContextID:     "ctx-1",
+		Connector:     "Discord",
+		ExternalID:    "chan-1",
+		SubjectUserID: "user-1",
+		Action:        "held",
+		Reason:        "spam filter: 3 links",
+		Evidence:      "buy now https://a https://b https://c",
+	})
+	if err != nil {
+		t.Fatalf("create moderation case: %v", err)
+	}
+	if decision.Kind != ModerationKindDecision || decision.Status != ModerationStatusOpen || decision.Connector != "discord" {
+		t.Fatalf("unexpected decision %+v", decision)
+	}
+	decisions, err := sqlStore.ListModerationDecisionsForUser(ctx, "discord", "user-1", 5)
+	if err != nil || len(decisions) != 1 || decisions[0].ID != decision.ID {
+		t.Fatalf("expected the decision for user-1, got %+v (%v)", decisions, err)
+	}
+
+	if _, _, err := sqlStore.AppealModerationCase(ctx, AppealModerationCaseInput{CaseID: decision.ID, UserID: "user-2", Text: "not me"}); !errors.Is(err, ErrModerationAppealNotAllowed) {
+		t.Fatalf("expected another user's appeal to be refused, got %v", err)
+	}
+	appealed, appeal, err := sqlStore.AppealModerationCase(ctx, AppealModerationCaseInput{CaseID: decision.ID, UserID: "user-1", Text: "those were docs links"})
+	if err != nil {
+		t.Fatalf("appeal: %v", err)
+	}
+	if appealed.Status != ModerationStatusAppealed || appeal.Kind != ModerationKindAppeal || appeal.LinkedCaseID != decision.ID || appeal.Reason != "those were docs links" {
+		t.Fatalf("unexpected appeal %+v / %+v", appealed, appeal)
+	}
+	if _, _, err := sqlStore.AppealModerationCase(ctx, AppealModerationCaseInput{CaseID: decision.ID, UserID: "user-1"}); !errors.Is(err, ErrModerationCaseNotOpen) {
+		t.Fatalf("expected a second appeal to be refused, got %v", err)
+	}
+	if _, _, err := sqlStore.ResolveModerationAppeal(ctx, ResolveModerationAppealInput{AppealID: decision.ID, ResolverUserID: "admin-1"}); !errors.Is(err, ErrModerationCaseNotFound) {
+		t.Fatalf("expected resolving a decision id to fail, got %v", err)
+	}
+
+	resolved, closed, err := sqlStore.ResolveModerationAppeal(ctx, ResolveModerationAppealInput{AppealID: appeal.ID, ResolverUserID: "admin-1", Note: "legit links"})
+	if err != nil {
+		t.Fatalf("resolve appeal: %v", err)
+	}
+	if resolved.Status != ModerationStatusOverturned || resolved.ResolvedBy != "admin-1" || resolved.Resolution != "legit links" {
+		t.Fatalf("expected overturned decision, got %+v", resolved)
+	}
+	if closed.Status != ModerationStatusResolved || closed.Resolution != ModerationStatusOverturned {
+		t.Fatalf("expected resolved appeal, got %+v", closed)
+	}
+	if _, _, err := sqlStore.ResolveModerationAppeal(ctx, ResolveModerationAppealInput{AppealID: appeal.ID, ResolverUserID: "admin-1", Uphold: true}); !errors.Is(err, ErrModerationCaseNotOpen) {
+		t.Fatalf("expected a second resolution to be refused, got %v", err)
+	}
+}
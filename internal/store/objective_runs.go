@@ -0,0 +1,268 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrObjectiveRunNotFound = errors.New("objective run not found")
+
+// ObjectiveRunStatus is where one objective run stands.
+type ObjectiveRunStatus string
+
+const (
+	// ObjectiveRunQueued runs have a task that has not finished yet.
+	ObjectiveRunQueued    ObjectiveRunStatus = "queued"
+	ObjectiveRunSucceeded ObjectiveRunStatus = "succeeded"
+	ObjectiveRunFailed    ObjectiveRunStatus = "failed"
+	// ObjectiveRunSkipped runs were not made, e.g. while the model was down.
+	ObjectiveRunSkipped   ObjectiveRunStatus = "skipped"
+	ObjectiveRunCancelled ObjectiveRunStatus = "cancelled"
+)
+
+const (
+	// objectiveRunsKept bounds the history kept per objective.
+	objectiveRunsKept       = 200
+	objectiveRunSummaryMax  = 500
+	objectiveRunErrorMax    = 1000
+	defaultObjectiveRunList = 20
+)
+
+// ObjectiveRun is one execution of an objective: the task it queued, the
+// trigger that fired it and, once the task finished, its outcome.
+// Runs that failed before queueing a task have no TaskID.
+type ObjectiveRun struct {
+	ID          string
+	ObjectiveID string
+	WorkspaceID string
+	TaskID      string
+	Trigger     string
+	Status      ObjectiveRunStatus
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	DurationMs  int64
+	Summary     string
+	Error       string
+}
+
+type CreateObjectiveRunInput struct {
+	ObjectiveID string
+	WorkspaceID string
+	TaskID      string
+	Trigger     string
+	Status      ObjectiveRunStatus
+	StartedAt   time.Time
+	Error       string
+}
+
+type FinishObjectiveRunInput struct {
+	TaskID     string
+	Status     ObjectiveRunStatus
+	FinishedAt time.Time
+	Summary    string
+	Error      string
+}
+
+// CreateObjectiveRun records a run. Runs created in a final status are
+// finished at once; the oldest runs beyond the kept history are dropped.
+func (s *Store) CreateObjectiveRun(ctx context.Context, input CreateObjectiveRunInput) (ObjectiveRun, error) {
+	run := ObjectiveRun{
+		ID:          "objrun_" + uuid.NewString(),
+		ObjectiveID: strings.TrimSpace(input.ObjectiveID),
+		WorkspaceID: strings.TrimSpace(input.WorkspaceID),
+		TaskID:      strings.TrimSpace(input.TaskID),
+		Trigger:     strings.ToLower(strings.TrimSpace(input.Trigger)),
+		Status:      input.Status,
+		StartedAt:   input.StartedAt.UTC(),
+		Error:       truncateRunText(input.Error, objectiveRunErrorMax),
+	}
+	if run.ObjectiveID == "" {
+		return ObjectiveRun{}, ErrObjectiveInvalid
+	}
+	if run.Status == "" {
+		run.Status = ObjectiveRunQueued
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now().UTC()
+	}
+	if run.Status != ObjectiveRunQueued {
+		run.FinishedAt = time.Now().UTC()
+		run.DurationMs = max(run.FinishedAt.Sub(run.StartedAt).Milliseconds(), 0)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ObjectiveRun{}, fmt.Errorf("begin objective run: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO objective_runs (id, objective_id, workspace_id, task_id, trigger, status, started_at_unix, finished_at_unix, duration_ms, error)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID,
+		run.ObjectiveID,
+		run.WorkspaceID,
+		nullIfEmpty(run.TaskID),
+		run.Trigger,
+		string(run.Status),
+		run.StartedAt.Unix(),
+		nullTimeUnix(run.FinishedAt),
+		run.DurationMs,
+		run.Error,
+	); err != nil {
+		return ObjectiveRun{}, fmt.Errorf("insert objective run: %w", err)
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM objective_runs
+		 WHERE objective_id = ? AND id NOT IN (
+			SELECT id FROM objective_runs WHERE objective_id = ?
+			ORDER BY started_at_unix DESC, rowid DESC LIMIT ?
+		 )`,
+		run.ObjectiveID,
+		run.ObjectiveID,
+		objectiveRunsKept,
+	); err != nil {
+		return ObjectiveRun{}, fmt.Errorf("trim objective runs: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return ObjectiveRun{}, fmt.Errorf("commit objective run: %w", err)
+	}
+	return run, nil
+}
+
+// FinishObjectiveRun records the outcome of the queued run that owns a
+// task. It returns ErrObjectiveRunNotFound for tasks of no queued run, such
+// as runs already finished.
+func (s *Store) FinishObjectiveRun(ctx context.Context, input FinishObjectiveRunInput) (ObjectiveRun, error) {
+	taskID := strings.TrimSpace(input.TaskID)
+	if taskID == "" {
+		return ObjectiveRun{}, ErrObjectiveRunNotFound
+	}
+	finishedAt := input.FinishedAt.UTC()
+	if finishedAt.IsZero() {
+		finishedAt = time.Now().UTC()
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE objective_runs
+		 SET status = ?, finished_at_unix = ?, duration_ms = MAX((? - started_at_unix) * 1000, 0), summary = ?, error = ?
+		 WHERE task_id = ? AND status = ?`,
+		string(input.Status),
+		finishedAt.Unix(),
+		finishedAt.Unix(),
+		truncateRunText(input.Summary, objectiveRunSummaryMax),
+		truncateRunText(input.Error, objectiveRunErrorMax),
+		taskID,
+		string(ObjectiveRunQueued),
+	)
+	if err != nil {
+		return ObjectiveRun{}, fmt.Errorf("finish objective run: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return ObjectiveRun{}, fmt.Errorf("finish objective run rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ObjectiveRun{}, ErrObjectiveRunNotFound
+	}
+	return s.LookupObjectiveRunByTask(ctx, taskID)
+}
+
+// LookupObjectiveRunByTask returns the latest run that queued a task.
+func (s *Store) LookupObjectiveRunByTask(ctx context.Context, taskID string) (ObjectiveRun, error) {
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, objective_id, workspace_id, task_id, trigger, status, started_at_unix, finished_at_unix, duration_ms, summary, error
+		 FROM objective_runs
+		 WHERE task_id = ?
+		 ORDER BY started_at_unix DESC, rowid DESC
+		 LIMIT 1`,
+		strings.TrimSpace(taskID),
+	)
+	return scanObjectiveRun(row)
+}
+
+// ListObjectiveRuns returns the latest runs of an objective, newest first.
+func (s *Store) ListObjectiveRuns(ctx context.Context, objectiveID string, limit int) ([]ObjectiveRun, error) {
+	if limit <= 0 {
+		limit = defaultObjectiveRunList
+	}
+	if limit > objectiveRunsKept {
+		limit = objectiveRunsKept
+	}
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, objective_id, workspace_id, task_id, trigger, status, started_at_unix, finished_at_unix, duration_ms, summary, error
+		 FROM objective_runs
+		 WHERE objective_id = ?
+		 ORDER BY started_at_unix DESC, rowid DESC
+		 LIMIT ?`,
+		strings.TrimSpace(objectiveID),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list objective runs: %w", err)
+	}
+	defer rows.Close()
+	runs := []ObjectiveRun{}
+	for rows.Next() {
+		run, err := scanObjectiveRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate objective runs: %w", err)
+	}
+	return runs, nil
+}
+
+func scanObjectiveRun(row interface{ Scan(dest ...any) error }) (ObjectiveRun, error) {
+	var (
+		run            ObjectiveRun
+		taskID         sql.NullString
+		status         string
+		startedAtUnix  int64
+		finishedAtUnix sql.NullInt64
+	)
+	if err := row.Scan(
+		&run.ID,
+		&run.ObjectiveID,
+		&run.WorkspaceID,
+		&taskID,
+		&run.Trigger,
+		&status,
+		&startedAtUnix,
+		&finishedAtUnix,
+		&run.DurationMs,
+		&run.Summary,
+		&run.Error,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ObjectiveRun{}, ErrObjectiveRunNotFound
+		}
+		return ObjectiveRun{}, fmt.Errorf("scan objective run: %w", err)
+	}
+	run.TaskID = taskID.String
+	run.Status = ObjectiveRunStatus(status)
+	run.StartedAt = time.Unix(startedAtUnix, 0).UTC()
+	if finishedAtUnix.Valid {
+		run.FinishedAt = time.Unix(finishedAtUnix.Int64, 0).UTC()
+	}
+	return run, nil
+}
+
+func truncateRunText(value string, maxLen int) string {
+	value = strings.TrimSpace(value)
+	if len(value) <= maxLen {
+		return value
+	}
+	return strings.TrimSpace(value[:maxLen]) + "..."
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestObjectiveRunsRecordAndFinish(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	startedAt := time.Now().UTC().Add(-90 * time.Second)
+
+	queued, err := sqlStore.CreateObjectiveRun(ctx, CreateObjectiveRunInput{
+		ObjectiveID: "obj-1", WorkspaceID: "ws-1", TaskID: "task-1", Trigger: "Schedule", StartedAt: startedAt,
+	})
+	if err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	if queued.Status != ObjectiveRunQueued || queued.Trigger != "schedule" || !queued.FinishedAt.IsZero() {
+		t.Fatalf("unexpected queued run %+v", queued)
+	}
+	if _, err := sqlStore.CreateObjectiveRun(ctx, CreateObjectiveRunInput{
+		ObjectiveID: "obj-1", WorkspaceID: "ws-1", Trigger: "schedule", Status: ObjectiveRunFailed,
+		StartedAt: startedAt.Add(time.Minute), Error: "queue full",
+	}); err != nil {
+		t.Fatalf("create failed run: %v", err)
+	}
+
+	finished, err := sqlStore.FinishObjectiveRun(ctx, FinishObjectiveRunInput{
+		TaskID: "task-1", Status: ObjectiveRunSucceeded, FinishedAt: startedAt.Add(90 * time.Second), Summary: "All checks green",
+	})
+	if err != nil {
+		t.Fatalf("finish run: %v", err)
+	}
+	if finished.ID != queued.ID || finished.Status != ObjectiveRunSucceeded || finished.DurationMs != 90000 || finished.Summary != "All checks green" {
+		t.Fatalf("unexpected finished run %+v", finished)
+	}
+	if _, err := sqlStore.FinishObjectiveRun(ctx, FinishObjectiveRunInput{TaskID: "task-1", Status: ObjectiveRunFailed}); !errors.Is(err, ErrObjectiveRunNotFound) {
+		t.Fatalf("expected a finished run left alone, got %v", err)
+	}
+
+	runs, err := sqlStore.ListObjectiveRuns(ctx, "obj-1", 10)
+	if err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Status != ObjectiveRunFailed || runs[0].Error != "queue full" || runs[0].TaskID != "" || runs[1].ID != queued.ID {
+		t.Fatalf("unexpected history %+v", runs)
+	}
+	if other, err := sqlStore.ListObjectiveRuns(ctx, "obj-2", 10); err != nil || len(other) != 0 {
+		t.Fatalf("expected no runs for another objective, got %+v (%v)", other, err)
+	}
+}
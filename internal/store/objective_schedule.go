@@ -53,6 +53,17 @@ func ComputeScheduleNextRunForTimezone(cronExpr, timezone string, from time.Time
 	return spec.Next(base.In(location)).UTC(), nil
 }
 
+// normalizeObjectiveDigestPeriod accepts an empty period or a known one,
+// in any case.
+func normalizeObjectiveDigestPeriod(raw ObjectiveDigestPeriod) (ObjectiveDigestPeriod, error) {
+	period := ObjectiveDigestPeriod(strings.ToLower(strings.TrimSpace(string(raw))))
+	switch period {
+	case ObjectiveDigestNone, ObjectiveDigestDaily, ObjectiveDigestWeekly:
+		return period, nil
+	}
+	return "", ErrObjectiveInvalid
+}
+
 func normalizeObjectiveTimezone(raw string) (string, error) {
 	timezone := strings.TrimSpace(raw)
 	if timezone == "" {
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrObjectiveStateNotFound = errors.New("objective state not found")
+
+// objectiveStateValuesMax bounds the values kept per objective.
+const objectiveStateValuesMax = 200
+
+// ObjectiveState is what the latest run of a change-detecting objective
+// observed: the values extracted from its output and their hash. ChangedAt
+// is when the hash last differed from the run before.
+type ObjectiveState struct {
+	ObjectiveID string
+	WorkspaceID string
+	ContentHash string
+	Values      []string
+	ObservedAt  time.Time
+	ChangedAt   time.Time
+}
+
+type SaveObjectiveStateInput struct {
+	ObjectiveID string
+	WorkspaceID string
+	ContentHash string
+	Values      []string
+	ObservedAt  time.Time
+}
+
+// SaveObjectiveState replaces the state of an objective with what its
+// latest run observed.
+func (s *Store) SaveObjectiveState(ctx context.Context, input SaveObjectiveStateInput) (ObjectiveState, error) {
+	objectiveID := strings.TrimSpace(input.ObjectiveID)
+	contentHash := strings.TrimSpace(input.ContentHash)
+	if objectiveID == "" || contentHash == "" {
+		return ObjectiveState{}, ErrObjectiveInvalid
+	}
+	observedAt := input.ObservedAt.UTC()
+	if observedAt.IsZero() {
+		observedAt = time.Now().UTC()
+	}
+	values := input.Values
+	if len(values) > objectiveStateValuesMax {
+		values = values[:objectiveStateValuesMax]
+	}
+	if values == nil {
+		values = []string{}
+	}
+	valuesJSON, err := json.Marshal(values)
+	if err != nil {
+		return ObjectiveState{}, fmt.Errorf("encode objective state values: %w", err)
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO objective_states (objective_id, workspace_id, content_hash, values_json, observed_at_unix, changed_at_unix)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(objective_id) DO UPDATE SET
+			workspace_id = excluded.workspace_id,
+			changed_at_unix = CASE WHEN objective_states.content_hash = excluded.content_hash
+				THEN objective_states.changed_at_unix ELSE excluded.changed_at_unix END,
+			content_hash = excluded.content_hash,
+			values_json = excluded.values_json,
+			observed_at_unix = excluded.observed_at_unix`,
+		objectiveID,
+		strings.TrimSpace(input.WorkspaceID),
+		contentHash,
+		string(valuesJSON),
+		observedAt.Unix(),
+		observedAt.Unix(),
+	); err != nil {
+		return ObjectiveState{}, fmt.Errorf("save objective state: %w", err)
+	}
+	return s.LookupObjectiveState(ctx, objectiveID)
+}
+
+// LookupObjectiveState returns the state the latest run of an objective
+// observed, or ErrObjectiveStateNotFound before its first run.
+func (s *Store) LookupObjectiveState(ctx context.Context, objectiveID string) (ObjectiveState, error) {
+	var (
+		state          ObjectiveState
+		valuesJSON     string
+		observedAtUnix int64
+		changedAtUnix  int64
+	)
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT objective_id, workspace_id, content_hash, values_json, observed_at_unix, changed_at_unix
+		 FROM objective_states
+		 WHERE objective_id = ?`,
+		strings.TrimSpace(objectiveID),
+	).Scan(&state.ObjectiveID, &state.WorkspaceID, &state.ContentHash, &valuesJSON, &observedAtUnix, &changedAtUnix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ObjectiveState{}, ErrObjectiveStateNotFound
+	}
+	if err != nil {
+		return ObjectiveState{}, fmt.Errorf("lookup objective state: %w", err)
+	}
+	state.Values = []string{}
+	if err := json.Unmarshal([]byte(valuesJSON), &state.Values); err != nil {
+		state.Values = []string{}
+	}
+	state.ObservedAt = time.Unix(observedAtUnix, 0).UTC()
+	state.ChangedAt = time.Unix(changedAtUnix, 0).UTC()
+	return state, nil
+}
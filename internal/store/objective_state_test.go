@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestObjectiveStateKeepsChangedAtUntilTheHashChanges(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if _, err := sqlStore.LookupObjectiveState(ctx, "obj-1"); !errors.Is(err, ErrObjectiveStateNotFound) {
+		t.Fatalf("expected no state before the first run, got %v", err)
+	}
+	first := time.Unix(1767225600, 0).UTC()
+
+	saved, err := sqlStore.SaveObjectiveState(ctx, SaveObjectiveStateInput{
+		ObjectiveID: "obj-1", WorkspaceID: "ws-1", ContentHash: "aaa", Values: []string{"v1.2.0 released"}, ObservedAt: first,
+	})
+	if err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+	if saved.ContentHash != "aaa" || len(saved.Values) != 1 || !saved.ChangedAt.Equal(first) {
+		t.Fatalf("unexpected first state %+v", saved)
+	}
+
+	same, err := sqlStore.SaveObjectiveState(ctx, SaveObjectiveStateInput{
+		ObjectiveID: "obj-1", WorkspaceID: "ws-1", ContentHash: "aaa", Values: []string{"v1.2.0 released"}, ObservedAt: first.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("save unchanged state: %v", err)
+	}
+	if !same.ObservedAt.Equal(first.Add(time.Hour)) || !same.ChangedAt.Equal(first) {
+		t.Fatalf("expected an unchanged hash to keep changed_at, got %+v", same)
+	}
+
+	changed, err := sqlStore.SaveObjectiveState(ctx, SaveObjectiveStateInput{
+		ObjectiveID: "obj-1", WorkspaceID: "ws-1", ContentHash: "bbb", Values: []string{"v1.3.0 released"}, ObservedAt: first.Add(2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("save changed state: %v", err)
+	}
+	if !changed.ChangedAt.Equal(first.Add(2*time.Hour)) || changed.Values[0] != "v1.3.0 released" {
+		t.Fatalf("unexpected changed state %+v", changed)
+	}
+	if _, err := sqlStore.SaveObjectiveState(ctx, SaveObjectiveStateInput{ObjectiveID: "obj-1"}); !errors.Is(err, ErrObjectiveInvalid) {
+		t.Fatalf("expected a state without hash rejected, got %v", err)
+	}
+}
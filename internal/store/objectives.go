@@ -19,7 +19,7 @@ var (
 
 const maxRecentObjectiveErrors = 5
 
-const objectiveSelectColumns = `id, workspace_id, context_id, title, prompt, trigger_type, event_key, cron_expr, timezone, active, next_run_unix, last_run_unix, last_error, run_count, success_count, failure_count, consecutive_failures, consecutive_successes, total_run_duration_ms, last_success_unix, last_failure_unix, auto_paused_reason, recent_errors_json, created_at_unix, updated_at_unix`
+const objectiveSelectColumns = `id, workspace_id, context_id, title, prompt, trigger_type, event_key, cron_expr, timezone, active, next_run_unix, last_run_unix, last_error, run_count, success_count, failure_count, consecutive_failures, consecutive_successes, total_run_duration_ms, last_success_unix, last_failure_unix, auto_paused_reason, recent_errors_json, created_at_unix, updated_at_unix, revision, report_changes_only, digest_period`
 
 type ObjectiveTriggerType string
 
@@ -28,6 +28,16 @@ const (
 	ObjectiveTriggerEvent    ObjectiveTriggerType = "event"
 )
 
+// ObjectiveDigestPeriod is the output mode of an objective: empty reports
+// every run, a period collects the runs into one digest per context.
+type ObjectiveDigestPeriod string
+
+const (
+	ObjectiveDigestNone   ObjectiveDigestPeriod = ""
+	ObjectiveDigestDaily  ObjectiveDigestPeriod = "daily"
+	ObjectiveDigestWeekly ObjectiveDigestPeriod = "weekly"
+)
+
 type ObjectiveRunError struct {
 	OccurredAt time.Time `json:"occurred_at"`
 	Message    string    `json:"message"`
@@ -57,8 +67,15 @@ type Objective struct {
 	LastFailureAt        time.Time
 	AutoPausedReason     string
 	RecentErrors         []ObjectiveRunError
-	CreatedAt            time.Time
-	UpdatedAt            time.Time
+	// ReportChangesOnly makes the runtime compare each run's output with
+	// the state observed by the previous run and report only differences.
+	ReportChangesOnly bool
+	// DigestPeriod collects the results of successful runs into a digest
+	// instead of reporting each one.
+	DigestPeriod ObjectiveDigestPeriod
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Revision     int
 }
 
 type CreateObjectiveInput struct {
@@ -72,6 +89,9 @@ type CreateObjectiveInput struct {
 	Timezone    string
 	NextRunAt   time.Time
 	Active      *bool
+	// ReportChangesOnly turns on change detection, see Objective.
+	ReportChangesOnly bool
+	DigestPeriod      ObjectiveDigestPeriod
 }
 
 type ListObjectivesInput struct {
@@ -101,6 +121,12 @@ type UpdateObjectiveInput struct {
 	Timezone    *string
 	NextRunAt   *time.Time
 	Active      *bool
+	// ReportChangesOnly turns change detection on or off.
+	ReportChangesOnly *bool
+	DigestPeriod      *ObjectiveDigestPeriod
+	// ExpectedRevision, when set, makes the update fail with
+	// ErrRevisionConflict if the objective changed since it was read.
+	ExpectedRevision int
 }
 
 func (s *Store) CreateObjective(ctx context.Context, input CreateObjectiveInput) (Objective, error) {
@@ -113,6 +139,10 @@ func (s *Store) CreateObjective(ctx context.Context, input CreateObjectiveInput)
 	if err != nil {
 		return Objective{}, ErrObjectiveInvalid
 	}
+	digestPeriod, err := normalizeObjectiveDigestPeriod(input.DigestPeriod)
+	if err != nil {
+		return Objective{}, err
+	}
 	record := Objective{
 		ID:                   "obj_" + uuid.NewString(),
 		WorkspaceID:          strings.TrimSpace(input.WorkspaceID),
@@ -131,8 +161,11 @@ func (s *Store) CreateObjective(ctx context.Context, input CreateObjectiveInput)
 		ConsecutiveFailures:  0,
 		ConsecutiveSuccesses: 0,
 		TotalRunDurationMs:   0,
+		ReportChangesOnly:    input.ReportChangesOnly,
+		DigestPeriod:         digestPeriod,
 		CreatedAt:            now,
 		UpdatedAt:            now,
+		Revision:             1,
 	}
 	if record.WorkspaceID == "" || record.ContextID == "" || record.Title == "" || record.Prompt == "" {
 		return Objective{}, ErrObjectiveInvalid
@@ -161,6 +194,12 @@ func (s *Store) CreateObjective(ctx context.Context, input CreateObjectiveInput)
 	default:
 		return Objective{}, ErrObjectiveInvalid
 	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, nil); err != nil {
+		return Objective{}, err
+	}
+	if err := s.checkQuota(ctx, record.WorkspaceID, QuotaObjectives); err != nil {
+		return Objective{}, err
+	}
 
 	if _, err := s.db.ExecContext(
 		ctx,
@@ -169,8 +208,8 @@ func (s *Store) CreateObjective(ctx context.Context, input CreateObjectiveInput)
 			next_run_unix, last_run_unix, last_error,
 			run_count, success_count, failure_count, consecutive_failures, consecutive_successes, total_run_duration_ms,
 			last_success_unix, last_failure_unix, auto_paused_reason, recent_errors_json,
-			created_at_unix, updated_at_unix
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			report_changes_only, digest_period, created_at_unix, updated_at_unix
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		record.ID,
 		record.WorkspaceID,
 		record.ContextID,
@@ -194,6 +233,8 @@ func (s *Store) CreateObjective(ctx context.Context, input CreateObjectiveInput)
 		nil,
 		nil,
 		nil,
+		boolToInt(record.ReportChangesOnly),
+		string(record.DigestPeriod),
 		record.CreatedAt.Unix(),
 		record.UpdatedAt.Unix(),
 	); err != nil {
@@ -207,11 +248,14 @@ func (s *Store) ListObjectives(ctx context.Context, input ListObjectivesInput) (
 	if workspaceID == "" {
 		return nil, ErrObjectiveInvalid
 	}
+	if err := checkWorkspaceScope(ctx, workspaceID, nil); err != nil {
+		return nil, err
+	}
 	limit := input.Limit
 	if limit < 1 {
 		limit = 50
 	}
-	whereParts := []string{"workspace_id = ?"}
+	whereParts := []string{"workspace_id = ?", "deleted_at_unix IS NULL"}
 	args := []any{workspaceID}
 	if input.ActiveOnly {
 		whereParts = append(whereParts, "active = 1")
@@ -250,6 +294,7 @@ func (s *Store) ListDueObjectives(ctx context.Context, now time.Time, limit int)
 		`SELECT `+objectiveSelectColumns+`
 		 FROM objectives
 		 WHERE active = 1
+		   AND deleted_at_unix IS NULL
 		   AND trigger_type = ?
 		   AND next_run_unix IS NOT NULL
 		   AND next_run_unix <= ?
@@ -274,10 +319,12 @@ func (s *Store) ListDueObjectives(ctx context.Context, now time.Time, limit int)
 	return results, nil
 }
 
+// ListEventObjectives returns the active objectives run by an event key,
+// oldest first. An empty workspace id lists those of every workspace.
 func (s *Store) ListEventObjectives(ctx context.Context, workspaceID, eventKey string, limit int) ([]Objective, error) {
 	workspaceID = strings.TrimSpace(workspaceID)
 	eventKey = strings.TrimSpace(strings.ToLower(eventKey))
-	if workspaceID == "" || eventKey == "" {
+	if eventKey == "" {
 		return nil, ErrObjectiveInvalid
 	}
 	if limit < 1 {
@@ -288,12 +335,14 @@ func (s *Store) ListEventObjectives(ctx context.Context, workspaceID, eventKey s
 		`SELECT `+objectiveSelectColumns+`
 		 FROM objectives
 		 WHERE active = 1
-		   AND workspace_id = ?
+		   AND deleted_at_unix IS NULL
+		   AND (? = '' OR workspace_id = ?)
 		   AND trigger_type = ?
 		   AND event_key = ?
 		 ORDER BY created_at_unix ASC
 		 LIMIT ?`,
 		workspaceID,
+		workspaceID,
 		string(ObjectiveTriggerEvent),
 		eventKey,
 		limit,
@@ -313,15 +362,30 @@ func (s *Store) ListEventObjectives(ctx context.Context, workspaceID, eventKey s
 	return results, nil
 }
 
+// UpdateObjectiveRun records the outcome of a run. It re-reads the objective
+// and retries when an admin edit lands in between, so run bookkeeping never
+// overwrites a concurrent pause or schedule change.
 func (s *Store) UpdateObjectiveRun(ctx context.Context, input UpdateObjectiveRunInput) (Objective, error) {
 	id := strings.TrimSpace(input.ID)
 	if id == "" {
 		return Objective{}, ErrObjectiveInvalid
 	}
+	for attempt := 0; ; attempt++ {
+		record, err := s.updateObjectiveRunOnce(ctx, id, input)
+		if errors.Is(err, ErrRevisionConflict) && attempt < maxRevisionRetries {
+			continue
+		}
+		return record, err
+	}
+}
+
+func (s *Store) updateObjectiveRunOnce(ctx context.Context, id string, input UpdateObjectiveRunInput) (Objective, error) {
 	record, err := s.LookupObjective(ctx, id)
 	if err != nil {
 		return Objective{}, err
 	}
+	readRevision := record.Revision
+	wasActive := record.Active
 	now := time.Now().UTC()
 	lastRun := input.LastRunAt.UTC()
 	if lastRun.IsZero() {
@@ -368,14 +432,20 @@ func (s *Store) UpdateObjectiveRun(ctx context.Context, input UpdateObjectiveRun
 		return Objective{}, err
 	}
 
-	_, err = s.db.ExecContext(
+	// Run statistics alone do not bump the revision; only a change to the
+	// active flag is an edit that an admin holding the old copy must see.
+	revisionBump := 0
+	if record.Active != wasActive {
+		revisionBump = 1
+	}
+	result, err := s.db.ExecContext(
 		ctx,
 		`UPDATE objectives
 		 SET active = ?, next_run_unix = ?, last_run_unix = ?, last_error = ?,
 		     run_count = ?, success_count = ?, failure_count = ?, consecutive_failures = ?, consecutive_successes = ?,
 		     total_run_duration_ms = ?, last_success_unix = ?, last_failure_unix = ?,
-		     auto_paused_reason = ?, recent_errors_json = ?, updated_at_unix = ?
-		 WHERE id = ?`,
+		     auto_paused_reason = ?, recent_errors_json = ?, updated_at_unix = ?, revision = revision + ?
+		 WHERE id = ? AND revision = ? AND deleted_at_unix IS NULL`,
 		boolToInt(record.Active),
 		nullTimeUnix(record.NextRunAt),
 		nullTimeUnix(record.LastRunAt),
@@ -391,11 +461,16 @@ func (s *Store) UpdateObjectiveRun(ctx context.Context, input UpdateObjectiveRun
 		nullIfEmpty(record.AutoPausedReason),
 		nullIfEmpty(recentErrorsJSON),
 		record.UpdatedAt.Unix(),
+		revisionBump,
 		id,
+		readRevision,
 	)
 	if err != nil {
 		return Objective{}, fmt.Errorf("update objective run: %w", err)
 	}
+	if err := requireRevisionWrite(result); err != nil {
+		return Objective{}, err
+	}
 	return s.LookupObjective(ctx, id)
 }
 
@@ -404,7 +479,7 @@ func (s *Store) LookupObjective(ctx context.Context, id string) (Objective, erro
 		ctx,
 		`SELECT `+objectiveSelectColumns+`
 		 FROM objectives
-		 WHERE id = ?`,
+		 WHERE id = ? AND deleted_at_unix IS NULL`,
 		strings.TrimSpace(id),
 	)
 	record, err := scanObjective(row)
@@ -414,14 +489,23 @@ func (s *Store) LookupObjective(ctx context.Context, id string) (Objective, erro
 		}
 		return Objective{}, err
 	}
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, ErrObjectiveNotFound); err != nil {
+		return Objective{}, err
+	}
 	return record, nil
 }
 
+// UpdateObjective applies a partial edit. The write only lands if the row is
+// still at the revision that was read, so two editors cannot silently
+// overwrite each other; the loser gets ErrRevisionConflict.
 func (s *Store) UpdateObjective(ctx context.Context, input UpdateObjectiveInput) (Objective, error) {
 	record, err := s.LookupObjective(ctx, input.ID)
 	if err != nil {
 		return Objective{}, err
 	}
+	if !revisionMatches(input.ExpectedRevision, record.Revision) {
+		return Objective{}, ErrRevisionConflict
+	}
 	if input.Title != nil {
 		record.Title = strings.TrimSpace(*input.Title)
 	}
@@ -453,6 +537,16 @@ func (s *Store) UpdateObjective(ctx context.Context, input UpdateObjectiveInput)
 			record.AutoPausedReason = ""
 		}
 	}
+	if input.ReportChangesOnly != nil {
+		record.ReportChangesOnly = *input.ReportChangesOnly
+	}
+	if input.DigestPeriod != nil {
+		digestPeriod, err := normalizeObjectiveDigestPeriod(*input.DigestPeriod)
+		if err != nil {
+			return Objective{}, err
+		}
+		record.DigestPeriod = digestPeriod
+	}
 
 	now := time.Now().UTC()
 	if strings.TrimSpace(record.Title) == "" || strings.TrimSpace(record.Prompt) == "" {
@@ -485,11 +579,11 @@ func (s *Store) UpdateObjective(ctx context.Context, input UpdateObjectiveInput)
 	}
 	record.UpdatedAt = now
 
-	if _, err := s.db.ExecContext(
+	result, err := s.db.ExecContext(
 		ctx,
 		`UPDATE objectives
-		 SET title = ?, prompt = ?, trigger_type = ?, event_key = ?, cron_expr = ?, timezone = ?, active = ?, next_run_unix = ?, auto_paused_reason = ?, updated_at_unix = ?
-		 WHERE id = ?`,
+		 SET title = ?, prompt = ?, trigger_type = ?, event_key = ?, cron_expr = ?, timezone = ?, active = ?, next_run_unix = ?, auto_paused_reason = ?, report_changes_only = ?, digest_period = ?, updated_at_unix = ?, revision = revision + 1
+		 WHERE id = ? AND revision = ? AND deleted_at_unix IS NULL`,
 		record.Title,
 		record.Prompt,
 		string(record.TriggerType),
@@ -499,11 +593,18 @@ func (s *Store) UpdateObjective(ctx context.Context, input UpdateObjectiveInput)
 		boolToInt(record.Active),
 		nullTimeUnix(record.NextRunAt),
 		nullIfEmpty(record.AutoPausedReason),
+		boolToInt(record.ReportChangesOnly),
+		string(record.DigestPeriod),
 		record.UpdatedAt.Unix(),
 		record.ID,
-	); err != nil {
+		record.Revision,
+	)
+	if err != nil {
 		return Objective{}, fmt.Errorf("update objective: %w", err)
 	}
+	if err := requireRevisionWrite(result); err != nil {
+		return Objective{}, err
+	}
 	return s.LookupObjective(ctx, record.ID)
 }
 
@@ -514,23 +615,35 @@ func (s *Store) SetObjectiveActive(ctx context.Context, id string, active bool)
 	})
 }
 
-func (s *Store) DeleteObjective(ctx context.Context, id string) error {
+// DeleteObjective moves an objective to the trash. It stops running and
+// disappears from listings but can be restored until the trash is purged.
+// A non-zero expectedRevision refuses the delete if the objective was edited
+// after the caller read it.
+func (s *Store) DeleteObjective(ctx context.Context, id string, expectedRevision int) error {
 	id = strings.TrimSpace(id)
 	if id == "" {
 		return ErrObjectiveInvalid
 	}
-	result, err := s.db.ExecContext(ctx, `DELETE FROM objectives WHERE id = ?`, id)
+	record, err := s.LookupObjective(ctx, id)
 	if err != nil {
-		return fmt.Errorf("delete objective: %w", err)
+		return err
 	}
-	affected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("delete objective rows affected: %w", err)
+	if !revisionMatches(expectedRevision, record.Revision) {
+		return ErrRevisionConflict
 	}
-	if affected < 1 {
-		return ErrObjectiveNotFound
+	now := time.Now().UTC().Unix()
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE objectives SET deleted_at_unix = ?, updated_at_unix = ?, revision = revision + 1 WHERE id = ? AND revision = ? AND deleted_at_unix IS NULL`,
+		now,
+		now,
+		id,
+		record.Revision,
+	)
+	if err != nil {
+		return fmt.Errorf("delete objective: %w", err)
 	}
-	return nil
+	return requireRevisionWrite(result)
 }
 
 type objectiveScanner interface {
@@ -559,6 +672,8 @@ func scanObjective(scanner objectiveScanner) (Objective, error) {
 	var recentErrorsJSON sql.NullString
 	var createdAtUnix int64
 	var updatedAtUnix int64
+	var reportChangesOnly int
+	var digestPeriod string
 	if err := scanner.Scan(
 		&record.ID,
 		&record.WorkspaceID,
@@ -585,6 +700,9 @@ func scanObjective(scanner objectiveScanner) (Objective, error) {
 		&recentErrorsJSON,
 		&createdAtUnix,
 		&updatedAtUnix,
+		&record.Revision,
+		&reportChangesOnly,
+		&digestPeriod,
 	); err != nil {
 		return Objective{}, err
 	}
@@ -618,6 +736,8 @@ func scanObjective(scanner objectiveScanner) (Objective, error) {
 	}
 	record.AutoPausedReason = strings.TrimSpace(autoPausedReason.String)
 	record.RecentErrors = decodeObjectiveRecentErrors(recentErrorsJSON.String)
+	record.ReportChangesOnly = reportChangesOnly == 1
+	record.DigestPeriod = ObjectiveDigestPeriod(digestPeriod)
 	record.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
 	record.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
 	return record, nil
@@ -120,6 +120,12 @@ func TestListEventObjectives(t *testing.T) {
 	if items[0].TriggerType != ObjectiveTriggerEvent {
 		t.Fatalf("unexpected trigger type: %s", items[0].TriggerType)
 	}
+	if items, err := sqlStore.ListEventObjectives(ctx, "ws-other", "markdown.updated", 10); err != nil || len(items) != 0 {
+		t.Fatalf("expected other workspaces to list none, got %d (%v)", len(items), err)
+	}
+	if items, err := sqlStore.ListEventObjectives(ctx, "", "markdown.updated", 10); err != nil || len(items) != 1 {
+		t.Fatalf("expected every workspace listed without a workspace id, got %d (%v)", len(items), err)
+	}
 }
 
 func TestUpdatePauseAndDeleteObjective(t *testing.T) {
@@ -142,22 +148,31 @@ func TestUpdatePauseAndDeleteObjective(t *testing.T) {
 	newTitle := "Draft weekly summary"
 	newPrompt := "Draft a weekly summary from latest markdown notes"
 	inactive := false
+	changesOnly := true
+	digestPeriod := ObjectiveDigestPeriod(" Weekly ")
 	updated, err := sqlStore.UpdateObjective(ctx, UpdateObjectiveInput{
-		ID:     created.ID,
-		Title:  &newTitle,
-		Prompt: &newPrompt,
-		Active: &inactive,
+		ID:                created.ID,
+		Title:             &newTitle,
+		Prompt:            &newPrompt,
+		Active:            &inactive,
+		ReportChangesOnly: &changesOnly,
+		DigestPeriod:      &digestPeriod,
 	})
 	if err != nil {
 		t.Fatalf("update objective: %v", err)
 	}
-	if updated.Title != newTitle || updated.Prompt != newPrompt {
+	if updated.Title != newTitle || updated.Prompt != newPrompt || !updated.ReportChangesOnly || updated.DigestPeriod != ObjectiveDigestWeekly {
 		t.Fatalf("objective update not persisted: %+v", updated)
 	}
 	if updated.Active {
 		t.Fatal("expected objective to be inactive after update")
 	}
 
+	hourly := ObjectiveDigestPeriod("hourly")
+	if _, err := sqlStore.UpdateObjective(ctx, UpdateObjectiveInput{ID: created.ID, DigestPeriod: &hourly}); !errors.Is(err, ErrObjectiveInvalid) {
+		t.Fatalf("expected an unknown digest period rejected, got %v", err)
+	}
+
 	resumed, err := sqlStore.SetObjectiveActive(ctx, created.ID, true)
 	if err != nil {
 		t.Fatalf("set objective active: %v", err)
@@ -166,7 +181,7 @@ func TestUpdatePauseAndDeleteObjective(t *testing.T) {
 		t.Fatal("expected objective to be active")
 	}
 
-	if err := sqlStore.DeleteObjective(ctx, created.ID); err != nil {
+	if err := sqlStore.DeleteObjective(ctx, created.ID, 0); err != nil {
 		t.Fatalf("delete objective: %v", err)
 	}
 	if _, err := sqlStore.LookupObjective(ctx, created.ID); err == nil {
@@ -0,0 +1,235 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrOutboundMessageNotFound = errors.New("outbound message not found")
+
+// OutboundMessage is a message that could not be delivered because its
+// connector was unreachable. It waits in the outbound queue until the
+// connector accepts it. Messages sharing a CollapseKey for the same target
+// replace each other, so only the latest of a series of updates is sent.
+type OutboundMessage struct {
+	ID          string
+	Connector   string
+	ExternalID  string
+	CollapseKey string
+	Text        string
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type QueueOutboundMessageInput struct {
+	Connector   string
+	ExternalID  string
+	CollapseKey string
+	Text        string
+	LastError   string
+}
+
+// QueueOutboundMessage stores a message for later delivery. When a queued
+// message with the same collapse key exists for the target its text is
+// replaced in place and collapsed is true.
+func (s *Store) QueueOutboundMessage(ctx context.Context, input QueueOutboundMessageInput) (message OutboundMessage, collapsed bool, err error) {
+	connector := strings.ToLower(strings.TrimSpace(input.Connector))
+	externalID := strings.TrimSpace(input.ExternalID)
+	text := strings.TrimSpace(input.Text)
+	if connector == "" || externalID == "" || text == "" {
+		return OutboundMessage{}, false, fmt.Errorf("connector, external id and text are required")
+	}
+	collapseKey := strings.TrimSpace(input.CollapseKey)
+	lastError := strings.TrimSpace(input.LastError)
+	now := time.Now().UTC()
+
+	if collapseKey != "" {
+		result, err := s.db.ExecContext(
+			ctx,
+			`UPDATE outbound_messages SET text = ?, last_error = ?, updated_at_unix = ?
+			 WHERE connector = ? AND external_id = ? AND collapse_key = ?`,
+			text,
+			lastError,
+			now.Unix(),
+			connector,
+			externalID,
+			collapseKey,
+		)
+		if err != nil {
+			return OutboundMessage{}, false, fmt.Errorf("collapse outbound message: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			existing, err := s.lookupCollapsedOutboundMessage(ctx, connector, externalID, collapseKey)
+			if err != nil {
+				return OutboundMessage{}, false, err
+			}
+			return existing, true, nil
+		}
+	}
+
+	message = OutboundMessage{
+		ID:          "out_" + uuid.NewString(),
+		Connector:   connector,
+		ExternalID:  externalID,
+		CollapseKey: collapseKey,
+		Text:        text,
+		LastError:   lastError,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO outbound_messages (
+			id, connector, external_id, collapse_key, text, attempts, last_error, created_at_unix, updated_at_unix
+		) VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?)`,
+		message.ID,
+		message.Connector,
+		message.ExternalID,
+		message.CollapseKey,
+		message.Text,
+		message.LastError,
+		now.Unix(),
+		now.Unix(),
+	); err != nil {
+		return OutboundMessage{}, false, fmt.Errorf("queue outbound message: %w", err)
+	}
+	return message, false, nil
+}
+
+// ListOutboundMessages returns queued messages oldest first, optionally for
+// a single connector.
+func (s *Store) ListOutboundMessages(ctx context.Context, connector string, limit int) ([]OutboundMessage, error) {
+	if limit < 1 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	query := `SELECT id, connector, external_id, collapse_key, text, attempts, last_error, created_at_unix, updated_at_unix
+		FROM outbound_messages`
+	args := []any{}
+	if connector = strings.ToLower(strings.TrimSpace(connector)); connector != "" {
+		query += ` WHERE connector = ?`
+		args = append(args, connector)
+	}
+	query += ` ORDER BY created_at_unix ASC, rowid ASC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list outbound messages: %w", err)
+	}
+	defer rows.Close()
+	messages := []OutboundMessage{}
+	for rows.Next() {
+		message, err := scanOutboundMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate outbound messages: %w", err)
+	}
+	return messages, nil
+}
+
+// CountOutboundMessages returns the number of queued messages per connector.
+func (s *Store) CountOutboundMessages(ctx context.Context) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT connector, COUNT(*) FROM outbound_messages GROUP BY connector`)
+	if err != nil {
+		return nil, fmt.Errorf("count outbound messages: %w", err)
+	}
+	defer rows.Close()
+	counts := map[string]int{}
+	for rows.Next() {
+		var connector string
+		var count int
+		if err := rows.Scan(&connector, &count); err != nil {
+			return nil, fmt.Errorf("scan outbound message count: %w", err)
+		}
+		counts[connector] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate outbound message counts: %w", err)
+	}
+	return counts, nil
+}
+
+// MarkOutboundMessageFailed records a failed delivery attempt.
+func (s *Store) MarkOutboundMessageFailed(ctx context.Context, id, lastError string) error {
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE outbound_messages SET attempts = attempts + 1, last_error = ?, updated_at_unix = ? WHERE id = ?`,
+		strings.TrimSpace(lastError),
+		time.Now().UTC().Unix(),
+		strings.TrimSpace(id),
+	)
+	if err != nil {
+		return fmt.Errorf("mark outbound message failed: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrOutboundMessageNotFound
+	}
+	return nil
+}
+
+// DeleteOutboundMessage removes a message once it has been delivered.
+func (s *Store) DeleteOutboundMessage(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM outbound_messages WHERE id = ?`, strings.TrimSpace(id)); err != nil {
+		return fmt.Errorf("delete outbound message: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) lookupCollapsedOutboundMessage(ctx context.Context, connector, externalID, collapseKey string) (OutboundMessage, error) {
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, connector, external_id, collapse_key, text, attempts, last_error, created_at_unix, updated_at_unix
+		 FROM outbound_messages WHERE connector = ? AND external_id = ? AND collapse_key = ?`,
+		connector,
+		externalID,
+		collapseKey,
+	)
+	message, err := scanOutboundMessage(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return OutboundMessage{}, ErrOutboundMessageNotFound
+	}
+	return message, err
+}
+
+type outboundMessageScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOutboundMessage(scanner outboundMessageScanner) (OutboundMessage, error) {
+	var message OutboundMessage
+	var createdAtUnix, updatedAtUnix int64
+	if err := scanner.Scan(
+		&message.ID,
+		&message.Connector,
+		&message.ExternalID,
+		&message.CollapseKey,
+		&message.Text,
+		&message.Attempts,
+		&message.LastError,
+		&createdAtUnix,
+		&updatedAtUnix,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OutboundMessage{}, err
+		}
+		return OutboundMessage{}, fmt.Errorf("scan outbound message: %w", err)
+	}
+	message.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+	message.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+	return message, nil
+}
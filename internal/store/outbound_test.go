@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOutboundMessageQueue(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	if _, _, err := sqlStore.QueueOutboundMessage(ctx, QueueOutboundMessageInput{Connector: "discord", ExternalID: "c1"}); err == nil {
+		t.Fatal("expected empty text to be rejected")
+	}
+	first, collapsed, err := sqlStore.QueueOutboundMessage(ctx, QueueOutboundMessageInput{
+		Connector: "Discord", ExternalID: "c1", CollapseKey: "task:1", Text: "started", LastError: "timeout",
+	})
+	if err != nil || collapsed {
+		t.Fatalf("queue first message: collapsed=%v err=%v", collapsed, err)
+	}
+	if _, _, err := sqlStore.QueueOutboundMessage(ctx, QueueOutboundMessageInput{Connector: "discord", ExternalID: "c1", Text: "other"}); err != nil {
+		t.Fatalf("queue second message: %v", err)
+	}
+	updated, collapsed, err := sqlStore.QueueOutboundMessage(ctx, QueueOutboundMessageInput{
+		Connector: "discord", ExternalID: "c1", CollapseKey: "task:1", Text: "completed",
+	})
+	if err != nil || !collapsed || updated.ID != first.ID || updated.Text != "completed" {
+		t.Fatalf("expected update to collapse into %s, got %+v collapsed=%v err=%v", first.ID, updated, collapsed, err)
+	}
+
+	messages, err := sqlStore.ListOutboundMessages(ctx, "discord", 10)
+	if err != nil || len(messages) != 2 || messages[0].ID != first.ID || messages[1].Text != "other" {
+		t.Fatalf("expected two messages oldest first, got %+v (%v)", messages, err)
+	}
+	if err := sqlStore.MarkOutboundMessageFailed(ctx, first.ID, "still down"); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	if err := sqlStore.MarkOutboundMessageFailed(ctx, "missing", "x"); !errors.Is(err, ErrOutboundMessageNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	messages, _ = sqlStore.ListOutboundMessages(ctx, "", 10)
+	if messages[0].Attempts != 1 || messages[0].LastError != "still down" {
+		t.Fatalf("expected recorded attempt, got %+v", messages[0])
+	}
+	if err := sqlStore.DeleteOutboundMessage(ctx, first.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	counts, err := sqlStore.CountOutboundMessages(ctx)
+	if err != nil || counts["discord"] != 1 {
+		t.Fatalf("expected one queued message, got %v (%v)", counts, err)
+	}
+}
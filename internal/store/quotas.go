@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrQuotaExceeded is wrapped by errors returned when a workspace is over one
+// of its usage quotas.
+var ErrQuotaExceeded = errors.New("workspace quota exceeded")
+
+type QuotaResource string
+
+const (
+	QuotaTasks      QuotaResource = "tasks"
+	QuotaObjectives QuotaResource = "objectives"
+	QuotaActions    QuotaResource = "actions"
+	QuotaTokens     QuotaResource = "tokens"
+)
+
+// QuotaGuard is consulted before objectives and action approvals are
+// created, so quotas hold no matter which connector or tool creates them.
+type QuotaGuard interface {
+	CheckQuota(ctx context.Context, workspaceID string, resource QuotaResource) error
+}
+
+func (s *Store) SetQuotaGuard(guard QuotaGuard) {
+	s.quotaGuard = guard
+}
+
+func (s *Store) checkQuota(ctx context.Context, workspaceID string, resource QuotaResource) error {
+	if s.quotaGuard == nil {
+		return nil
+	}
+	return s.quotaGuard.CheckQuota(ctx, workspaceID, resource)
+}
+
+// WorkspaceQuota holds per-workspace quota overrides. Nil fields fall back to
+// the runtime defaults; zero means unlimited.
+type WorkspaceQuota struct {
+	WorkspaceID    string
+	TasksPerDay    *int
+	Objectives     *int
+	ActionsPerDay  *int
+	TokensPerMonth *int
+	UpdatedAt      time.Time
+}
+
+func (s *Store) SetWorkspaceQuota(ctx context.Context, quota WorkspaceQuota) (WorkspaceQuota, error) {
+	quota.WorkspaceID = strings.TrimSpace(quota.WorkspaceID)
+	if quota.WorkspaceID == "" {
+		return WorkspaceQuota{}, fmt.Errorf("workspace id is required")
+	}
+	for _, value := range []*int{quota.TasksPerDay, quota.Objectives, quota.ActionsPerDay, quota.TokensPerMonth} {
+		if value != nil && *value < 0 {
+			return WorkspaceQuota{}, fmt.Errorf("quota limits must not be negative")
+		}
+	}
+	quota.UpdatedAt = time.Now().UTC()
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO workspace_quotas (workspace_id, tasks_per_day, objectives, actions_per_day, tokens_per_month, updated_at_unix)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(workspace_id) DO UPDATE SET
+		     tasks_per_day = excluded.tasks_per_day,
+		     objectives = excluded.objectives,
+		     actions_per_day = excluded.actions_per_day,
+		     tokens_per_month = excluded.tokens_per_month,
+		     updated_at_unix = excluded.updated_at_unix`,
+		quota.WorkspaceID,
+		nullableInt(quota.TasksPerDay),
+		nullableInt(quota.Objectives),
+		nullableInt(quota.ActionsPerDay),
+		nullableInt(quota.TokensPerMonth),
+		quota.UpdatedAt.Unix(),
+	); err != nil {
+		return WorkspaceQuota{}, fmt.Errorf("set workspace quota: %w", err)
+	}
+	return quota, nil
+}
+
+// LookupWorkspaceQuota returns the overrides of a workspace; workspaces
+// without overrides get an empty record.
+func (s *Store) LookupWorkspaceQuota(ctx context.Context, workspaceID string) (WorkspaceQuota, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	var tasks, objectives, actions, tokens sql.NullInt64
+	var updatedAtUnix int64
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT tasks_per_day, objectives, actions_per_day, tokens_per_month, updated_at_unix FROM workspace_quotas WHERE workspace_id = ?`,
+		workspaceID,
+	).Scan(&tasks, &objectives, &actions, &tokens, &updatedAtUnix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WorkspaceQuota{WorkspaceID: workspaceID}, nil
+		}
+		return WorkspaceQuota{}, fmt.Errorf("lookup workspace quota: %w", err)
+	}
+	return WorkspaceQuota{
+		WorkspaceID:    workspaceID,
+		TasksPerDay:    intFromNull(tasks),
+		Objectives:     intFromNull(objectives),
+		ActionsPerDay:  intFromNull(actions),
+		TokensPerMonth: intFromNull(tokens),
+		UpdatedAt:      time.Unix(updatedAtUnix, 0).UTC(),
+	}, nil
+}
+
+// CountTasksCreatedSince counts workspace tasks created at or after since,
+// leaving out excludeID so a task being re-queued does not count twice.
+// Markdown reindex and memory compaction tasks are housekeeping and do not
+// count.
+func (s *Store) CountTasksCreatedSince(ctx context.Context, workspaceID string, since time.Time, excludeID string) (int, error) {
+	var count int
+	if err := s.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM tasks WHERE workspace_id = ? AND created_at >= ? AND id <> ? AND kind NOT IN ('reindex_markdown', 'memory_compaction')`,
+		strings.TrimSpace(workspaceID),
+		since.UTC().Format("2006-01-02 15:04:05"),
+		strings.TrimSpace(excludeID),
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count workspace tasks: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Store) CountObjectives(ctx context.Context, workspaceID string) (int, error) {
+	var count int
+	if err := s.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM objectives WHERE workspace_id = ? AND deleted_at_unix IS NULL`,
+		strings.TrimSpace(workspaceID),
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count workspace objectives: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Store) CountActionApprovalsSince(ctx context.Context, workspaceID string, since time.Time) (int, error) {
+	var count int
+	if err := s.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM action_approvals WHERE workspace_id = ? AND created_at_unix >= ?`,
+		strings.TrimSpace(workspaceID),
+		since.UTC().Unix(),
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count workspace actions: %w", err)
+	}
+	return count, nil
+}
+
+// AddTokenUsage adds model token usage to the workspace total for period,
+// e.g. "2026-10" for monthly accounting.
+func (s *Store) AddTokenUsage(ctx context.Context, workspaceID, period string, inputTokens, outputTokens int) error {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" || (inputTokens <= 0 && outputTokens <= 0) {
+		return nil
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO token_usage (workspace_id, period, input_tokens, output_tokens, updated_at_unix)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(workspace_id, period) DO UPDATE SET
+		     input_tokens = input_tokens + excluded.input_tokens,
+		     output_tokens = output_tokens + excluded.output_tokens,
+		     updated_at_unix = excluded.updated_at_unix`,
+		workspaceID,
+		strings.TrimSpace(period),
+		max(inputTokens, 0),
+		max(outputTokens, 0),
+		time.Now().UTC().Unix(),
+	); err != nil {
+		return fmt.Errorf("add token usage: %w", err)
+	}
+	return nil
+}
+
+// TokenUsage returns the input and output tokens recorded for period.
+func (s *Store) TokenUsage(ctx context.Context, workspaceID, period string) (int, int, error) {
+	var inputTokens, outputTokens int
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT input_tokens, output_tokens FROM token_usage WHERE workspace_id = ? AND period = ?`,
+		strings.TrimSpace(workspaceID),
+		strings.TrimSpace(period),
+	).Scan(&inputTokens, &outputTokens)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("lookup token usage: %w", err)
+	}
+	return inputTokens, outputTokens, nil
+}
+
+func nullableInt(value *int) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func intFromNull(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	converted := int(value.Int64)
+	return &converted
+}
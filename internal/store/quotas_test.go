@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type limitGuard struct {
+	blocked QuotaResource
+}
+
+func (g limitGuard) CheckQuota(ctx context.Context, workspaceID string, resource QuotaResource) error {
+	if resource == g.blocked {
+		return fmt.Errorf("%w: %s", ErrQuotaExceeded, resource)
+	}
+	return nil
+}
+
+func TestWorkspaceQuotaOverridesAndUsage(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	empty, err := sqlStore.LookupWorkspaceQuota(ctx, "ws-1")
+	if err != nil || empty.TasksPerDay != nil {
+		t.Fatalf("expected no overrides, got %+v (%v)", empty, err)
+	}
+	tasks := 10
+	if _, err := sqlStore.SetWorkspaceQuota(ctx, WorkspaceQuota{WorkspaceID: "ws-1", TasksPerDay: &tasks}); err != nil {
+		t.Fatalf("set quota: %v", err)
+	}
+	loaded, err := sqlStore.LookupWorkspaceQuota(ctx, "ws-1")
+	if err != nil || loaded.TasksPerDay == nil || *loaded.TasksPerDay != 10 || loaded.Objectives != nil {
+		t.Fatalf("unexpected quota %+v (%v)", loaded, err)
+	}
+	negative := -1
+	if _, err := sqlStore.SetWorkspaceQuota(ctx, WorkspaceQuota{WorkspaceID: "ws-1", Objectives: &negative}); err == nil {
+		t.Fatal("expected negative limit to be rejected")
+	}
+
+	for _, id := range []string{"task-a", "task-b"} {
+		if err := sqlStore.CreateTask(ctx, CreateTaskInput{ID: id, WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general", Title: id, Prompt: id, Status: "queued"}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{ID: "reindex", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "reindex_markdown", Title: "r", Prompt: "r", Status: "queued"}); err != nil {
+		t.Fatalf("create reindex task: %v", err)
+	}
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{ID: "compact", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "memory_compaction", Title: "c", Prompt: "c", Status: "queued"}); err != nil {
+		t.Fatalf("create compaction task: %v", err)
+	}
+	count, err := sqlStore.CountTasksCreatedSince(ctx, "ws-1", time.Now().UTC().Add(-time.Hour), "task-b")
+	if err != nil || count != 1 {
+		t.Fatalf("expected one counted task, got %d (%v)", count, err)
+	}
+
+	if err := sqlStore.AddTokenUsage(ctx, "ws-1", "2026-10", 100, 20); err != nil {
+		t.Fatalf("add usage: %v", err)
+	}
+	if err := sqlStore.AddTokenUsage(ctx, "ws-1", "2026-10", 50, 5); err != nil {
+		t.Fatalf("add usage: %v", err)
+	}
+	in, out, err := sqlStore.TokenUsage(ctx, "ws-1", "2026-10")
+	if err != nil || in != 150 || out != 25 {
+		t.Fatalf("expected accumulated usage, got %d/%d (%v)", in, out, err)
+	}
+}
+
+func TestQuotaGuardBlocksObjectiveCreation(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	sqlStore.SetQuotaGuard(limitGuard{blocked: QuotaObjectives})
+	_, err := sqlStore.CreateObjective(ctx, CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Watch",
+		Prompt:      "Watch things",
+		TriggerType: ObjectiveTriggerSchedule,
+		CronExpr:    "0 * * * *",
+	})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	count, err := sqlStore.CountObjectives(ctx, "ws-1")
+	if err != nil || count != 0 {
+		t.Fatalf("expected no objective stored, got %d (%v)", count, err)
+	}
+}
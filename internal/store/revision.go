@@ -0,0 +1,35 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrRevisionConflict reports that a row changed between the caller reading
+// it and writing it back. Callers should reload and retry rather than
+// overwrite the newer edit.
+var ErrRevisionConflict = errors.New("revision conflict: record changed since it was read")
+
+// maxRevisionRetries bounds how often internal read-modify-write loops
+// reload a row after losing a race with another writer.
+const maxRevisionRetries = 3
+
+// revisionMatches reports whether an expected revision permits a write. Zero
+// means the caller did not read a revision and writes unconditionally.
+func revisionMatches(expected, current int) bool {
+	return expected <= 0 || expected == current
+}
+
+// requireRevisionWrite turns an UPDATE guarded by "revision = ?" that matched
+// no rows into ErrRevisionConflict.
+func requireRevisionWrite(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("revision write rows affected: %w", err)
+	}
+	if affected < 1 {
+		return ErrRevisionConflict
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestObjectiveRevisionGuardsConcurrentEdits(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	created, err := sqlStore.CreateObjective(ctx, CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Nightly report",
+		Prompt:      "Summarize the day",
+		TriggerType: ObjectiveTriggerSchedule,
+		CronExpr:    "0 2 * * *",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	if created.Revision != 1 {
+		t.Fatalf("expected new objective at revision 1, got %d", created.Revision)
+	}
+
+	title := "Morning report"
+	updated, err := sqlStore.UpdateObjective(ctx, UpdateObjectiveInput{ID: created.ID, Title: &title, ExpectedRevision: 1})
+	if err != nil {
+		t.Fatalf("update objective: %v", err)
+	}
+	if updated.Revision != 2 {
+		t.Fatalf("expected revision 2 after update, got %d", updated.Revision)
+	}
+
+	stale := "Evening report"
+	if _, err := sqlStore.UpdateObjective(ctx, UpdateObjectiveInput{ID: created.ID, Title: &stale, ExpectedRevision: 1}); !errors.Is(err, ErrRevisionConflict) {
+		t.Fatalf("expected stale update to conflict, got %v", err)
+	}
+	if err := sqlStore.DeleteObjective(ctx, created.ID, 1); !errors.Is(err, ErrRevisionConflict) {
+		t.Fatalf("expected stale delete to conflict, got %v", err)
+	}
+
+	// Run bookkeeping does not invalidate an admin's copy unless it pauses
+	// the objective.
+	ran, err := sqlStore.UpdateObjectiveRun(ctx, UpdateObjectiveRunInput{ID: created.ID, NextRunAt: time.Now().UTC().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("update objective run: %v", err)
+	}
+	if ran.Revision != 2 || ran.Title != "Morning report" {
+		t.Fatalf("expected run update to keep revision and edits, got %+v", ran)
+	}
+	paused := false
+	reason := "too many failures"
+	ran, err = sqlStore.UpdateObjectiveRun(ctx, UpdateObjectiveRunInput{ID: created.ID, LastError: "boom", Active: &paused, AutoPausedReason: &reason})
+	if err != nil {
+		t.Fatalf("auto-pause objective: %v", err)
+	}
+	if ran.Revision != 3 {
+		t.Fatalf("expected auto-pause to bump revision to 3, got %d", ran.Revision)
+	}
+
+	if err := sqlStore.DeleteObjective(ctx, created.ID, 3); err != nil {
+		t.Fatalf("delete objective at current revision: %v", err)
+	}
+}
+
+func TestTaskRevisionGuardsRoutingAndDelete(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-1",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Report",
+		Prompt:      "do work",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	routed, err := sqlStore.UpdateTaskRouting(ctx, UpdateTaskRoutingInput{ID: "task-1", Priority: "p1", ExpectedRevision: 1})
+	if err != nil {
+		t.Fatalf("update task routing: %v", err)
+	}
+	if routed.Revision != 2 {
+		t.Fatalf("expected revision 2 after routing, got %d", routed.Revision)
+	}
+	if _, err := sqlStore.UpdateTaskRouting(ctx, UpdateTaskRoutingInput{ID: "task-1", Priority: "p3", ExpectedRevision: 1}); !errors.Is(err, ErrRevisionConflict) {
+		t.Fatalf("expected stale routing update to conflict, got %v", err)
+	}
+
+	if err := sqlStore.MarkTaskCompleted(ctx, "task-1", time.Now().UTC(), "done", ""); err != nil {
+		t.Fatalf("mark task completed: %v", err)
+	}
+	if err := sqlStore.DeleteTask(ctx, "task-1", 2); !errors.Is(err, ErrRevisionConflict) {
+		t.Fatalf("expected delete with pre-completion revision to conflict, got %v", err)
+	}
+	record, err := sqlStore.LookupTask(ctx, "task-1")
+	if err != nil {
+		t.Fatalf("lookup task: %v", err)
+	}
+	if record.Priority != "p1" || record.Revision != 3 {
+		t.Fatalf("unexpected task after stale writes: %+v", record)
+	}
+	if err := sqlStore.DeleteTask(ctx, "task-1", record.Revision); err != nil {
+		t.Fatalf("delete task at current revision: %v", err)
+	}
+}
+
+func TestContextPromptRevisionGuard(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	policy, err := sqlStore.SetContextSystemPromptByExternal(ctx, "telegram", "42", "Be brief", 0)
+	if err != nil {
+		t.Fatalf("set prompt: %v", err)
+	}
+	if _, err := sqlStore.SetContextVoiceRepliesByExternal(ctx, "telegram", "42", true); err != nil {
+		t.Fatalf("enable voice replies: %v", err)
+	}
+	if _, err := sqlStore.SetContextSystemPromptByExternal(ctx, "telegram", "42", "Be verbose", policy.Revision); !errors.Is(err, ErrRevisionConflict) {
+		t.Fatalf("expected stale prompt write to conflict, got %v", err)
+	}
+	current, err := sqlStore.LookupContextPolicyByExternal(ctx, "telegram", "42")
+	if err != nil {
+		t.Fatalf("lookup policy: %v", err)
+	}
+	if current.SystemPrompt != "Be brief" || current.Revision != policy.Revision+1 {
+		t.Fatalf("unexpected policy after stale write: %+v", current)
+	}
+	if _, err := sqlStore.SetContextSystemPromptByExternal(ctx, "telegram", "42", "Be verbose", current.Revision); err != nil {
+		t.Fatalf("set prompt at current revision: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Permission is one privileged operation a role may be allowed to perform.
+type Permission string
+
+const (
+	PermissionApproveActions   Permission = "approve_actions"
+	PermissionManageObjectives Permission = "manage_objectives"
+	PermissionSetPrompt        Permission = "set_prompt"
+	PermissionRouteTasks       Permission = "route_tasks"
+	PermissionReadAudit        Permission = "read_audit"
+	PermissionManageMembers    Permission = "manage_members"
+)
+
+// AllPermissions lists every permission in display order.
+var AllPermissions = []Permission{
+	PermissionApproveActions,
+	PermissionManageObjectives,
+	PermissionSetPrompt,
+	PermissionRouteTasks,
+	PermissionReadAudit,
+	PermissionManageMembers,
+}
+
+// ErrUnknownPermission is returned when a role is given a permission that
+// does not exist.
+var ErrUnknownPermission = errors.New("unknown permission")
+
+// ErrUserNotFound is returned when a user id has no user record.
+var ErrUserNotFound = errors.New("user not found")
+
+// WorkspaceRole is the permission set of one role inside one workspace.
+// Stored roles replace the defaults for that role; roles without a stored
+// record use DefaultRolePermissions.
+type WorkspaceRole struct {
+	WorkspaceID string
+	Role        string
+	Permissions []Permission
+	// Stored is false for roles reported from the defaults.
+	Stored    bool
+	UpdatedAt time.Time
+}
+
+// DefaultRolePermissions is what a role may do in a workspace that has not
+// configured it: admin and overlord may do everything, other roles nothing.
+func DefaultRolePermissions(role string) []Permission {
+	switch normalizeRoleName(role) {
+	case "admin", "overlord":
+		return append([]Permission(nil), AllPermissions...)
+	default:
+		return nil
+	}
+}
+
+// ParsePermissions parses a comma or space separated permission list; "all"
+// expands to every permission.
+func ParsePermissions(value string) ([]Permission, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	seen := map[Permission]bool{}
+	for _, field := range fields {
+		name := Permission(strings.ToLower(strings.TrimSpace(field)))
+		if name == "all" {
+			for _, permission := range AllPermissions {
+				seen[permission] = true
+			}
+			continue
+		}
+		if !isKnownPermission(name) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, name)
+		}
+		seen[name] = true
+	}
+	permissions := []Permission{}
+	for _, permission := range AllPermissions {
+		if seen[permission] {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, nil
+}
+
+// SetWorkspaceRole stores the permissions of a role in a workspace,
+// replacing any earlier set.
+func (s *Store) SetWorkspaceRole(ctx context.Context, role WorkspaceRole) (WorkspaceRole, error) {
+	role.WorkspaceID = strings.TrimSpace(role.WorkspaceID)
+	role.Role = normalizeRoleName(role.Role)
+	if role.WorkspaceID == "" || role.Role == "" {
+		return WorkspaceRole{}, fmt.Errorf("workspace id and role are required")
+	}
+	names := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		if !isKnownPermission(permission) {
+			return WorkspaceRole{}, fmt.Errorf("%w: %s", ErrUnknownPermission, permission)
+		}
+		names = append(names, string(permission))
+	}
+	parsed, _ := ParsePermissions(strings.Join(names, ","))
+	role.Permissions = parsed
+	role.Stored = true
+	role.UpdatedAt = time.Now().UTC()
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO workspace_roles (workspace_id, role, permissions, updated_at_unix)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(workspace_id, role) DO UPDATE SET
+		     permissions = excluded.permissions,
+		     updated_at_unix = excluded.updated_at_unix`,
+		role.WorkspaceID,
+		role.Role,
+		joinPermissions(role.Permissions),
+		role.UpdatedAt.Unix(),
+	); err != nil {
+		return WorkspaceRole{}, fmt.Errorf("set workspace role: %w", err)
+	}
+	return role, nil
+}
+
+// DeleteWorkspaceRole drops a stored role so it falls back to the defaults.
+// It reports whether a record was removed.
+func (s *Store) DeleteWorkspaceRole(ctx context.Context, workspaceID, role string) (bool, error) {
+	result, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM workspace_roles WHERE workspace_id = ? AND role = ?`,
+		strings.TrimSpace(workspaceID),
+		normalizeRoleName(role),
+	)
+	if err != nil {
+		return false, fmt.Errorf("delete workspace role: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete workspace role: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ListWorkspaceRoles returns the effective roles of a workspace: its stored
+// roles plus the default admin and overlord roles it has not overridden.
+func (s *Store) ListWorkspaceRoles(ctx context.Context, workspaceID string) ([]WorkspaceRole, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT role, permissions, updated_at_unix FROM workspace_roles WHERE workspace_id = ? ORDER BY role`,
+		workspaceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list workspace roles: %w", err)
+	}
+	defer rows.Close()
+	roles := []WorkspaceRole{}
+	stored := map[string]bool{}
+	for rows.Next() {
+		var name, permissions string
+		var updatedAtUnix int64
+		if err := rows.Scan(&name, &permissions, &updatedAtUnix); err != nil {
+			return nil, fmt.Errorf("scan workspace role: %w", err)
+		}
+		parsed, _ := ParsePermissions(permissions)
+		roles = append(roles, WorkspaceRole{
+			WorkspaceID: workspaceID,
+			Role:        name,
+			Permissions: parsed,
+			Stored:      true,
+			UpdatedAt:   time.Unix(updatedAtUnix, 0).UTC(),
+		})
+		stored[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate workspace roles: %w", err)
+	}
+	for _, name := range []string{"admin", "overlord"} {
+		if !stored[name] {
+			roles = append(roles, WorkspaceRole{WorkspaceID: workspaceID, Role: name, Permissions: DefaultRolePermissions(name)})
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Role < roles[j].Role })
+	return roles, nil
+}
+
+// RolePermissions returns what role may do in a workspace: the stored set
+// when the workspace configured the role, the defaults otherwise.
+func (s *Store) RolePermissions(ctx context.Context, workspaceID, role string) ([]Permission, error) {
+	var permissions string
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT permissions FROM workspace_roles WHERE workspace_id = ? AND role = ?`,
+		strings.TrimSpace(workspaceID),
+		normalizeRoleName(role),
+	).Scan(&permissions)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DefaultRolePermissions(role), nil
+		}
+		return nil, fmt.Errorf("lookup workspace role: %w", err)
+	}
+	parsed, _ := ParsePermissions(permissions)
+	return parsed, nil
+}
+
+// LookupUser returns a user by id; DisplayName falls back to the id.
+func (s *Store) LookupUser(ctx context.Context, userID string) (UserIdentity, error) {
+	var identity UserIdentity
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, display_name, role FROM users WHERE id = ?`,
+		strings.TrimSpace(userID),
+	).Scan(&identity.UserID, &identity.DisplayName, &identity.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return UserIdentity{}, ErrUserNotFound
+		}
+		return UserIdentity{}, fmt.Errorf("lookup user: %w", err)
+	}
+	if strings.TrimSpace(identity.DisplayName) == "" {
+		identity.DisplayName = identity.UserID
+	}
+	return identity, nil
+}
+
+// HasPermission reports whether permissions contains permission.
+func HasPermission(permissions []Permission, permission Permission) bool {
+	for _, candidate := range permissions {
+		if candidate == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownPermission(permission Permission) bool {
+	for _, known := range AllPermissions {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPermissions(permissions []Permission) string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, string(permission))
+	}
+	return strings.Join(names, ",")
+}
+
+func normalizeRoleName(role string) string {
+	return strings.ToLower(strings.TrimSpace(role))
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWorkspaceRolesOverrideDefaults(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	permissions, err := sqlStore.RolePermissions(ctx, "ws-1", "Admin")
+	if err != nil {
+		t.Fatalf("role permissions: %v", err)
+	}
+	if !reflect.DeepEqual(permissions, AllPermissions) {
+		t.Fatalf("expected admin default to hold every permission, got %v", permissions)
+	}
+	if permissions, _ := sqlStore.RolePermissions(ctx, "ws-1", "member"); len(permissions) != 0 {
+		t.Fatalf("expected member default to hold nothing, got %v", permissions)
+	}
+
+	parsed, err := ParsePermissions("read_audit, approve_actions")
+	if err != nil {
+		t.Fatalf("parse permissions: %v", err)
+	}
+	if _, err := sqlStore.SetWorkspaceRole(ctx, WorkspaceRole{WorkspaceID: "ws-1", Role: "Auditor", Permissions: parsed}); err != nil {
+		t.Fatalf("set role: %v", err)
+	}
+	if _, err := sqlStore.SetWorkspaceRole(ctx, WorkspaceRole{WorkspaceID: "ws-1", Role: "admin", Permissions: []Permission{PermissionSetPrompt}}); err != nil {
+		t.Fatalf("set admin role: %v", err)
+	}
+
+	permissions, _ = sqlStore.RolePermissions(ctx, "ws-1", "auditor")
+	if !reflect.DeepEqual(permissions, []Permission{PermissionApproveActions, PermissionReadAudit}) {
+		t.Fatalf("unexpected auditor permissions: %v", permissions)
+	}
+	if permissions, _ = sqlStore.RolePermissions(ctx, "ws-1", "admin"); !reflect.DeepEqual(permissions, []Permission{PermissionSetPrompt}) {
+		t.Fatalf("expected narrowed admin, got %v", permissions)
+	}
+	if permissions, _ = sqlStore.RolePermissions(ctx, "ws-2", "admin"); len(permissions) != len(AllPermissions) {
+		t.Fatalf("expected other workspaces to keep the default, got %v", permissions)
+	}
+
+	roles, err := sqlStore.ListWorkspaceRoles(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("list roles: %v", err)
+	}
+	names := []string{}
+	for _, role := range roles {
+		names = append(names, role.Role)
+	}
+	if !reflect.DeepEqual(names, []string{"admin", "auditor", "overlord"}) {
+		t.Fatalf("unexpected roles: %v", names)
+	}
+
+	removed, err := sqlStore.DeleteWorkspaceRole(ctx, "ws-1", "admin")
+	if err != nil || !removed {
+		t.Fatalf("delete role: removed=%t err=%v", removed, err)
+	}
+	if permissions, _ = sqlStore.RolePermissions(ctx, "ws-1", "admin"); len(permissions) != len(AllPermissions) {
+		t.Fatalf("expected admin back on defaults, got %v", permissions)
+	}
+
+	if _, err := ParsePermissions("approve_actions,launch_missiles"); !errors.Is(err, ErrUnknownPermission) {
+		t.Fatalf("expected unknown permission error, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrWorkspaceScope is wrapped by errors returned when a scoped caller
+// touches a task, objective or action approval of another workspace. Access
+// by ID also wraps the resource's not-found error, so callers cannot tell a
+// foreign record from a missing one.
+var ErrWorkspaceScope = errors.New("resource belongs to a different workspace")
+
+type workspaceScopeKey struct{}
+
+// WithWorkspaceScope restricts store access through ctx to one workspace.
+// Chat turns and worker tasks run scoped; admin API calls and runtime
+// housekeeping do not.
+func WithWorkspaceScope(ctx context.Context, workspaceID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, workspaceScopeKey{}, strings.TrimSpace(workspaceID))
+}
+
+// WorkspaceScopeFromContext returns the workspace ctx is scoped to.
+func WorkspaceScopeFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	workspaceID, ok := ctx.Value(workspaceScopeKey{}).(string)
+	if !ok || workspaceID == "" {
+		return "", false
+	}
+	return workspaceID, true
+}
+
+// checkWorkspaceScope validates a record's workspace against the caller's
+// scope. notFound is the resource's not-found error; nil for creates and
+// listings, which name the workspace explicitly.
+func checkWorkspaceScope(ctx context.Context, workspaceID string, notFound error) error {
+	scope, ok := WorkspaceScopeFromContext(ctx)
+	if !ok || strings.TrimSpace(workspaceID) == scope {
+		return nil
+	}
+	if notFound != nil {
+		return fmt.Errorf("%w: %w", notFound, ErrWorkspaceScope)
+	}
+	return fmt.Errorf("workspace %s: %w", strings.TrimSpace(workspaceID), ErrWorkspaceScope)
+}
+
+// checkRowScope validates the workspace of a row before it is updated by ID.
+// Missing rows pass so the caller reports its usual not-found error.
+func (s *Store) checkRowScope(ctx context.Context, table, id string, notFound error) error {
+	if _, ok := WorkspaceScopeFromContext(ctx); !ok {
+		return nil
+	}
+	var workspaceID string
+	err := s.db.QueryRowContext(ctx, `SELECT workspace_id FROM `+table+` WHERE id = ?`, strings.TrimSpace(id)).Scan(&workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("check workspace scope: %w", err)
+	}
+	return checkWorkspaceScope(ctx, workspaceID, notFound)
+}
+
+// scopedWorkspaceFilter narrows an optional workspace filter to the caller's
+// scope: an empty filter becomes the scoped workspace, a foreign one fails.
+func scopedWorkspaceFilter(ctx context.Context, workspaceID string) (string, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	scope, ok := WorkspaceScopeFromContext(ctx)
+	if !ok {
+		return workspaceID, nil
+	}
+	if workspaceID == "" {
+		return scope, nil
+	}
+	return workspaceID, checkWorkspaceScope(ctx, workspaceID, nil)
+}
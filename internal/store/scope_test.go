@@ -0,0 +1,218 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type isolationFixture struct {
+	taskID      string
+	objectiveID string
+	actionID    string
+}
+
+func seedIsolationWorkspace(t *testing.T, sqlStore *Store, connector, externalID string) (ContextRecord, isolationFixture) {
+	t.Helper()
+	ctx := context.Background()
+	record, err := sqlStore.EnsureContextForExternalChannel(ctx, connector, externalID, externalID)
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	fixture := isolationFixture{taskID: "task-" + externalID}
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          fixture.taskID,
+		WorkspaceID: record.WorkspaceID,
+		ContextID:   record.ID,
+		Kind:        "general",
+		Title:       "Task",
+		Prompt:      "Do work",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	objective, err := sqlStore.CreateObjective(ctx, CreateObjectiveInput{
+		WorkspaceID: record.WorkspaceID,
+		ContextID:   record.ID,
+		Title:       "Digest",
+		Prompt:      "Summarize",
+		TriggerType: ObjectiveTriggerEvent,
+		EventKey:    "markdown.updated",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	fixture.objectiveID = objective.ID
+	action, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+		WorkspaceID:     record.WorkspaceID,
+		ContextID:       record.ID,
+		Connector:       connector,
+		ExternalID:      externalID,
+		RequesterUserID: "user-1",
+		ActionType:      "run_command",
+		ActionTarget:    "echo",
+	})
+	if err != nil {
+		t.Fatalf("create action approval: %v", err)
+	}
+	fixture.actionID = action.ID
+	return record, fixture
+}
+
+// TestWorkspaceScopeIsolation runs every ID-based store access of a scoped
+// caller against another workspace's records. New by-ID accessors belong in
+// this table.
+func TestWorkspaceScopeIsolation(t *testing.T) {
+	sqlStore := newTestStore(t)
+	own, _ := seedIsolationWorkspace(t, sqlStore, "discord", "chan-a")
+	foreign, other := seedIsolationWorkspace(t, sqlStore, "discord", "chan-b")
+	scoped := WithWorkspaceScope(context.Background(), own.WorkspaceID)
+
+	cases := []struct {
+		name     string
+		notFound error
+		access   func(ctx context.Context) error
+	}{
+		{"LookupTask", ErrTaskNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.LookupTask(ctx, other.taskID)
+			return err
+		}},
+		{"UpdateTaskRouting", ErrTaskNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.UpdateTaskRouting(ctx, UpdateTaskRoutingInput{ID: other.taskID, RouteClass: "issue"})
+			return err
+		}},
+		{"SetTaskExternalRef", ErrTaskNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.SetTaskExternalRef(ctx, SetTaskExternalRefInput{ID: other.taskID, System: "linear", Key: "OPS-1"})
+			return err
+		}},
+		{"SetTaskPlan", ErrTaskNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.SetTaskPlan(ctx, other.taskID, []string{"step"})
+			return err
+		}},
+		{"ListTaskPlan", ErrTaskNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.ListTaskPlan(ctx, other.taskID)
+			return err
+		}},
+		{"UpdateTaskPlanStep", ErrTaskNotFound, func(ctx context.Context) error {
+			return sqlStore.UpdateTaskPlanStep(ctx, other.taskID, 1, TaskPlanStepDone, "")
+		}},
+		{"LookupObjective", ErrObjectiveNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.LookupObjective(ctx, other.objectiveID)
+			return err
+		}},
+		{"SetObjectiveActive", ErrObjectiveNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.SetObjectiveActive(ctx, other.objectiveID, false)
+			return err
+		}},
+		{"UpdateObjectiveRun", ErrObjectiveNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.UpdateObjectiveRun(ctx, UpdateObjectiveRunInput{ID: other.objectiveID, LastRunAt: time.Now().UTC()})
+			return err
+		}},
+		{"DeleteObjective", ErrObjectiveNotFound, func(ctx context.Context) error {
+			return sqlStore.DeleteObjective(ctx, other.objectiveID, 0)
+		}},
+		{"RestoreObjective", ErrObjectiveNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.RestoreObjective(ctx, other.objectiveID)
+			return err
+		}},
+		{"DeleteTask", ErrTaskNotFound, func(ctx context.Context) error {
+			return sqlStore.DeleteTask(ctx, other.taskID, 0)
+		}},
+		{"RestoreTask", ErrTaskNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.RestoreTask(ctx, other.taskID)
+			return err
+		}},
+		{"LookupActionApproval", ErrActionApprovalNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.LookupActionApproval(ctx, other.actionID)
+			return err
+		}},
+		{"ApproveActionApproval", ErrActionApprovalNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.ApproveActionApproval(ctx, ApproveActionApprovalInput{ID: other.actionID, ApproverUserID: "admin"})
+			return err
+		}},
+		{"DenyActionApproval", ErrActionApprovalNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.DenyActionApproval(ctx, DenyActionApprovalInput{ID: other.actionID, ApproverUserID: "admin"})
+			return err
+		}},
+		{"UpdateActionExecution", ErrActionApprovalNotFound, func(ctx context.Context) error {
+			_, err := sqlStore.UpdateActionExecution(ctx, UpdateActionExecutionInput{ID: other.actionID, ExecutionStatus: "succeeded"})
+			return err
+		}},
+		{"CreateTask", nil, func(ctx context.Context) error {
+			return sqlStore.CreateTask(ctx, CreateTaskInput{ID: "task-smuggled", WorkspaceID: foreign.WorkspaceID, ContextID: foreign.ID, Kind: "general", Title: "x", Prompt: "x", Status: "queued"})
+		}},
+		{"CreateObjective", nil, func(ctx context.Context) error {
+			_, err := sqlStore.CreateObjective(ctx, CreateObjectiveInput{WorkspaceID: foreign.WorkspaceID, ContextID: foreign.ID, Title: "x", Prompt: "x", TriggerType: ObjectiveTriggerEvent, EventKey: "markdown.updated"})
+			return err
+		}},
+		{"CreateActionApproval", nil, func(ctx context.Context) error {
+			_, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{WorkspaceID: foreign.WorkspaceID, ContextID: foreign.ID, Connector: "discord", ExternalID: "chan-b", RequesterUserID: "user-1", ActionType: "run_command"})
+			return err
+		}},
+		{"ListTasks", nil, func(ctx context.Context) error {
+			_, err := sqlStore.ListTasks(ctx, ListTasksInput{WorkspaceID: foreign.WorkspaceID})
+			return err
+		}},
+		{"ListObjectives", nil, func(ctx context.Context) error {
+			_, err := sqlStore.ListObjectives(ctx, ListObjectivesInput{WorkspaceID: foreign.WorkspaceID})
+			return err
+		}},
+		{"ListTrash", nil, func(ctx context.Context) error {
+			_, err := sqlStore.ListTrash(ctx, ListTrashInput{WorkspaceID: foreign.WorkspaceID})
+			return err
+		}},
+		{"ListAgentAuditEvents", nil, func(ctx context.Context) error {
+			_, err := sqlStore.ListAgentAuditEvents(ctx, ListAgentAuditEventsInput{WorkspaceID: foreign.WorkspaceID})
+			return err
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.access(scoped)
+			if !errors.Is(err, ErrWorkspaceScope) {
+				t.Fatalf("expected workspace scope error, got %v", err)
+			}
+			if tc.notFound != nil && !errors.Is(err, tc.notFound) {
+				t.Fatalf("expected foreign record to read as not found, got %v", err)
+			}
+		})
+	}
+
+	// Nothing above may have changed the foreign workspace.
+	task, err := sqlStore.LookupTask(context.Background(), other.taskID)
+	if err != nil || task.RouteClass != "" || task.ExternalKey != "" {
+		t.Fatalf("expected foreign task untouched, got %+v (%v)", task, err)
+	}
+	action, err := sqlStore.LookupActionApproval(context.Background(), other.actionID)
+	if err != nil || action.Status != "pending" {
+		t.Fatalf("expected foreign action still pending, got %+v (%v)", action, err)
+	}
+	if _, err := sqlStore.LookupObjective(context.Background(), other.objectiveID); err != nil {
+		t.Fatalf("expected foreign objective kept, got %v", err)
+	}
+}
+
+func TestWorkspaceScopeFiltersListings(t *testing.T) {
+	sqlStore := newTestStore(t)
+	own, fixture := seedIsolationWorkspace(t, sqlStore, "telegram", "100")
+	seedIsolationWorkspace(t, sqlStore, "telegram", "200")
+	scoped := WithWorkspaceScope(context.Background(), own.WorkspaceID)
+
+	pending, err := sqlStore.ListPendingActionApprovalsGlobal(scoped, 10)
+	if err != nil || len(pending) != 1 || pending[0].ID != fixture.actionID {
+		t.Fatalf("expected only own pending action, got %+v (%v)", pending, err)
+	}
+	tasks, err := sqlStore.ListTasks(scoped, ListTasksInput{})
+	if err != nil || len(tasks) != 1 || tasks[0].ID != fixture.taskID {
+		t.Fatalf("expected only own tasks, got %+v (%v)", tasks, err)
+	}
+	if _, err := sqlStore.LookupTask(scoped, fixture.taskID); err != nil {
+		t.Fatalf("expected own task visible, got %v", err)
+	}
+
+	all, err := sqlStore.ListPendingActionApprovalsGlobal(context.Background(), 10)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("expected unscoped callers to see every workspace, got %d (%v)", len(all), err)
+	}
+}
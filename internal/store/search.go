@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type SearchKind string
+
+const (
+	SearchKindTask      SearchKind = "task"
+	SearchKindObjective SearchKind = "objective"
+	SearchKindApproval  SearchKind = "approval"
+	SearchKindAudit     SearchKind = "audit"
+)
+
+// SearchKinds lists every record kind covered by the search index.
+func SearchKinds() []SearchKind {
+	return []SearchKind{SearchKindTask, SearchKindObjective, SearchKindApproval, SearchKindAudit}
+}
+
+type SearchResult struct {
+	Kind        SearchKind
+	ID          string
+	WorkspaceID string
+	Title       string
+	Snippet     string
+	Status      string
+	// Risk is the risk level of an approval; empty for other kinds.
+	Risk      string
+	UpdatedAt time.Time
+}
+
+type SearchInput struct {
+	WorkspaceID string
+	Query       string
+	Kinds       []SearchKind
+	Limit       int
+}
+
+// searchSource describes how one table feeds search_documents. Expressions
+// use {row} for the row alias so the same text serves the triggers (NEW) and
+// the initial backfill (the table itself).
+type searchSource struct {
+	kind      SearchKind
+	table     string
+	title     string
+	body      string
+	live      string
+	updatedOn string
+}
+
+var searchSources = []searchSource{
+	{
+		kind:      SearchKindTask,
+		table:     "tasks",
+		title:     "{row}.title",
+		body:      "{row}.prompt || ' ' || COALESCE({row}.result_summary, '') || ' ' || COALESCE({row}.error_message, '')",
+		live:      "{row}.deleted_at_unix IS NULL",
+		updatedOn: "title, prompt, result_summary, error_message, deleted_at_unix",
+	},
+	{
+		kind:      SearchKindObjective,
+		table:     "objectives",
+		title:     "{row}.title",
+		body:      "{row}.prompt || ' ' || COALESCE({row}.event_key, '')",
+		live:      "{row}.deleted_at_unix IS NULL",
+		updatedOn: "title, prompt, event_key, deleted_at_unix",
+	},
+	{
+		kind:      SearchKindApproval,
+		table:     "action_approvals",
+		title:     "{row}.action_type || ' ' || COALESCE({row}.action_target, '')",
+		body:      "COALESCE({row}.action_summary, '') || ' ' || COALESCE({row}.denied_reason, '') || ' ' || COALESCE({row}.execution_message, '')",
+		live:      "1",
+		updatedOn: "action_summary, denied_reason, execution_message",
+	},
+	{
+		kind:      SearchKindAudit,
+		table:     "agent_audit_events",
+		title:     "{row}.event_type || ' ' || COALESCE({row}.tool_name, '')",
+		body:      "COALESCE({row}.message, '') || ' ' || COALESCE({row}.block_reason, '')",
+		live:      "1",
+		updatedOn: "message, block_reason",
+	},
+}
+
+func (source searchSource) expr(value, row string) string {
+	return strings.ReplaceAll(value, "{row}", row)
+}
+
+// migrateSearchIndex creates the full-text index and the triggers that keep
+// it in step with its source tables, then backfills it on first run.
+// search_documents holds one row per record; search_index is an FTS5 table
+// over its title and body.
+func (s *Store) migrateSearchIndex(ctx context.Context) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS search_documents (
+			id INTEGER PRIMARY KEY,
+			kind TEXT NOT NULL,
+			record_id TEXT NOT NULL,
+			workspace_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			body TEXT NOT NULL,
+			UNIQUE(kind, record_id)
+		);`,
+		`CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+			title, body,
+			content='search_documents', content_rowid='id',
+			tokenize='porter unicode61'
+		);`,
+		`CREATE TRIGGER IF NOT EXISTS search_documents_ai AFTER INSERT ON search_documents BEGIN
+			INSERT INTO search_index(rowid, title, body) VALUES (NEW.id, NEW.title, NEW.body);
+		END;`,
+		`CREATE TRIGGER IF NOT EXISTS search_documents_ad AFTER DELETE ON search_documents BEGIN
+			INSERT INTO search_index(search_index, rowid, title, body) VALUES ('delete', OLD.id, OLD.title, OLD.body);
+		END;`,
+	}
+	for _, source := range searchSources {
+		insert := fmt.Sprintf(
+			`INSERT INTO search_documents (kind, record_id, workspace_id, title, body)
+				SELECT '%s', NEW.id, NEW.workspace_id, %s, %s WHERE %s;`,
+			source.kind, source.expr(source.title, "NEW"), source.expr(source.body, "NEW"), source.expr(source.live, "NEW"),
+		)
+		remove := fmt.Sprintf(`DELETE FROM search_documents WHERE kind = '%s' AND record_id = OLD.id;`, source.kind)
+		queries = append(queries,
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%s_ai AFTER INSERT ON %s BEGIN %s END;`, source.table, source.table, insert),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%s_au AFTER UPDATE OF %s ON %s BEGIN %s %s END;`, source.table, source.updatedOn, source.table, remove, insert),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%s_ad AFTER DELETE ON %s BEGIN %s END;`, source.table, source.table, remove),
+		)
+	}
+	for _, query := range queries {
+		if _, err := s.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("run search migration: %w", err)
+		}
+	}
+
+	var indexed int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM search_documents`).Scan(&indexed); err != nil {
+		return fmt.Errorf("count search documents: %w", err)
+	}
+	if indexed > 0 {
+		return nil
+	}
+	for _, source := range searchSources {
+		row := source.table
+		_, err := s.db.ExecContext(ctx, fmt.Sprintf(
+			`INSERT OR IGNORE INTO search_documents (kind, record_id, workspace_id, title, body)
+				SELECT '%s', id, workspace_id, %s, %s FROM %s WHERE %s`,
+			source.kind, source.expr(source.title, row), source.expr(source.body, row), source.table, source.expr(source.live, row),
+		))
+		if err != nil {
+			return fmt.Errorf("backfill search index from %s: %w", source.table, err)
+		}
+	}
+	return nil
+}
+
+// Search finds tasks, objectives, action approvals and audit events whose
+// text matches every word of the query, best matches first. Words match as
+// prefixes, so "webhook" finds "webhooks". Trashed tasks and objectives are
+// not searchable until restored.
+func (s *Store) Search(ctx context.Context, input SearchInput) ([]SearchResult, error) {
+	match := searchMatchExpression(input.Query)
+	if match == "" {
+		return []SearchResult{}, nil
+	}
+	limit := input.Limit
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	workspaceID, err := scopedWorkspaceFilter(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	whereParts := []string{"search_index MATCH ?"}
+	args := []any{match}
+	if workspaceID != "" {
+		whereParts = append(whereParts, "d.workspace_id = ?")
+		args = append(args, workspaceID)
+	}
+	if len(input.Kinds) > 0 {
+		placeholders := make([]string, 0, len(input.Kinds))
+		for _, kind := range input.Kinds {
+			placeholders = append(placeholders, "?")
+			args = append(args, string(kind))
+		}
+		whereParts = append(whereParts, "d.kind IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT d.kind, d.record_id, d.workspace_id, d.title,
+			snippet(search_index, -1, '[', ']', '...', 12),
+			CASE d.kind
+				WHEN 'task' THEN t.status
+				WHEN 'objective' THEN CASE WHEN o.active = 1 THEN 'active' ELSE 'paused' END
+				WHEN 'approval' THEN a.status
+				ELSE CASE WHEN e.blocked = 1 THEN 'blocked' ELSE e.stage END
+			END,
+			COALESCE(a.risk_level, ''),
+			COALESCE(t.updated_at_unix, o.updated_at_unix, a.updated_at_unix, e.created_at_unix, 0)
+		 FROM search_index
+		 JOIN search_documents d ON d.id = search_index.rowid
+		 LEFT JOIN tasks t ON d.kind = 'task' AND t.id = d.record_id
+		 LEFT JOIN objectives o ON d.kind = 'objective' AND o.id = d.record_id
+		 LEFT JOIN action_approvals a ON d.kind = 'approval' AND a.id = d.record_id
+		 LEFT JOIN agent_audit_events e ON d.kind = 'audit' AND e.id = d.record_id
+		 WHERE `+strings.Join(whereParts, " AND ")+`
+		 ORDER BY search_index.rank
+		 LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]SearchResult, 0, limit)
+	for rows.Next() {
+		var result SearchResult
+		var kind string
+		var updatedAtUnix int64
+		if err := rows.Scan(&kind, &result.ID, &result.WorkspaceID, &result.Title, &result.Snippet, &result.Status, &result.Risk, &updatedAtUnix); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		result.Kind = SearchKind(kind)
+		result.Title = strings.TrimSpace(result.Title)
+		result.Snippet = strings.TrimSpace(result.Snippet)
+		if updatedAtUnix > 0 {
+			result.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search results: %w", err)
+	}
+	return results, nil
+}
+
+// searchMatchExpression turns free text into an FTS5 query that ANDs each
+// word as a quoted prefix, so operators and punctuation typed by the user
+// cannot produce a syntax error.
+func searchMatchExpression(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+word+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,187 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSearchFindsRecordsAcrossKinds(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-webhooks",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Wire up GitHub webhooks",
+		Prompt:      "Register the delivery endpoint",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-other",
+		WorkspaceID: "ws-2",
+		ContextID:   "ctx-2",
+		Kind:        "general",
+		Title:       "Webhook audit for billing",
+		Prompt:      "Check signatures",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := sqlStore.CreateObjective(ctx, CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Nightly digest",
+		Prompt:      "Summarize failed webhook deliveries",
+		TriggerType: ObjectiveTriggerEvent,
+		EventKey:    "markdown.updated",
+	}); err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	if _, err := sqlStore.CreateActionApproval(ctx, CreateActionApprovalInput{
+		WorkspaceID:     "ws-1",
+		ContextID:       "ctx-1",
+		Connector:       "discord",
+		ExternalID:      "chan-1",
+		RequesterUserID: "user-1",
+		ActionType:      "http_request",
+		ActionTarget:    "https://hooks.example.com",
+		ActionSummary:   "POST test payload to the webhook receiver",
+	}); err != nil {
+		t.Fatalf("create approval: %v", err)
+	}
+	if _, err := sqlStore.CreateAgentAuditEvent(ctx, CreateAgentAuditEventInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Connector:   "discord",
+		ExternalID:  "chan-1",
+		EventType:   "tool_call",
+		Stage:       "policy",
+		ToolName:    "fetch_url",
+		Blocked:     true,
+		BlockReason: "webhook host not on allowlist",
+	}); err != nil {
+		t.Fatalf("create audit event: %v", err)
+	}
+
+	results, err := sqlStore.Search(ctx, SearchInput{WorkspaceID: "ws-1", Query: "webhook"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	kinds := map[SearchKind]SearchResult{}
+	for _, result := range results {
+		if result.WorkspaceID != "ws-1" {
+			t.Fatalf("expected results from ws-1 only, got %+v", result)
+		}
+		kinds[result.Kind] = result
+	}
+	if len(kinds) != 4 {
+		t.Fatalf("expected a hit for every kind, got %+v", results)
+	}
+	if kinds[SearchKindTask].ID != "task-webhooks" || kinds[SearchKindTask].Status != "queued" {
+		t.Fatalf("unexpected task hit %+v", kinds[SearchKindTask])
+	}
+	if kinds[SearchKindAudit].Status != "blocked" {
+		t.Fatalf("expected blocked audit status, got %+v", kinds[SearchKindAudit])
+	}
+
+	results, err = sqlStore.Search(ctx, SearchInput{Query: "webhook signatures", Kinds: []SearchKind{SearchKindTask}})
+	if err != nil {
+		t.Fatalf("search all workspaces: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "task-other" {
+		t.Fatalf("expected every word to match, got %+v", results)
+	}
+
+	if _, err := sqlStore.Search(WithWorkspaceScope(ctx, "ws-1"), SearchInput{WorkspaceID: "ws-2", Query: "webhook"}); !errors.Is(err, ErrWorkspaceScope) {
+		t.Fatalf("expected scoped search of another workspace to fail, got %v", err)
+	}
+	if results, err := sqlStore.Search(ctx, SearchInput{Query: `"(*`}); err != nil || len(results) != 0 {
+		t.Fatalf("expected punctuation-only query to return nothing, got %+v, %v", results, err)
+	}
+}
+
+func TestSearchIndexFollowsEditsAndTrash(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-1",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Rotate keys",
+		Prompt:      "rotate the signing keys",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := sqlStore.MarkTaskFailed(ctx, "task-1", time.Now().UTC(), "vault timeout"); err != nil {
+		t.Fatalf("mark task failed: %v", err)
+	}
+	search := func(query string) []SearchResult {
+		t.Helper()
+		results, err := sqlStore.Search(ctx, SearchInput{WorkspaceID: "ws-1", Query: query})
+		if err != nil {
+			t.Fatalf("search %q: %v", query, err)
+		}
+		return results
+	}
+	if results := search("vault"); len(results) != 1 || results[0].Status != "failed" {
+		t.Fatalf("expected the error message to be searchable, got %+v", results)
+	}
+	if err := sqlStore.DeleteTask(ctx, "task-1", 0); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	if results := search("rotate"); len(results) != 0 {
+		t.Fatalf("expected trashed task to drop out of search, got %+v", results)
+	}
+	if _, err := sqlStore.RestoreTask(ctx, "task-1"); err != nil {
+		t.Fatalf("restore task: %v", err)
+	}
+	if results := search("rotate"); len(results) != 1 {
+		t.Fatalf("expected restored task to be searchable again, got %+v", results)
+	}
+}
+
+func TestSearchMatchExpressionQuotesWords(t *testing.T) {
+	if got := searchMatchExpression(`Webhook OR "retry"-loop`); got != `"webhook"* "or"* "retry"* "loop"*` {
+		t.Fatalf("unexpected match expression %q", got)
+	}
+	if got := searchMatchExpression("  "); got != "" {
+		t.Fatalf("expected empty expression, got %q", got)
+	}
+}
+
+func TestAutoMigrateBackfillsSearchIndex(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-1",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Quarterly report",
+		Prompt:      "draft it",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	// Simulate a database created before the index existed.
+	if _, err := sqlStore.db.ExecContext(ctx, `DELETE FROM search_documents`); err != nil {
+		t.Fatalf("clear search documents: %v", err)
+	}
+	if err := sqlStore.AutoMigrate(ctx); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	results, err := sqlStore.Search(ctx, SearchInput{Query: "quarterly"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "task-1" {
+		t.Fatalf("expected backfilled task, got %+v", results)
+	}
+}
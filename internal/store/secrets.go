@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSecretNotFound = errors.New("secret not found")
+	ErrSecretInvalid  = errors.New("secret input is invalid")
+)
+
+// SecretRecord holds an encrypted secret value. The store never sees plaintext.
+type SecretRecord struct {
+	Name       string
+	Ciphertext string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (s *Store) PutSecret(ctx context.Context, name, ciphertext string) (SecretRecord, error) {
+	name = strings.TrimSpace(name)
+	ciphertext = strings.TrimSpace(ciphertext)
+	if name == "" || ciphertext == "" {
+		return SecretRecord{}, ErrSecretInvalid
+	}
+	nowUnix := time.Now().UTC().Unix()
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO secrets (name, ciphertext, created_at_unix, updated_at_unix)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(name) DO UPDATE SET ciphertext = excluded.ciphertext, updated_at_unix = excluded.updated_at_unix`,
+		name,
+		ciphertext,
+		nowUnix,
+		nowUnix,
+	)
+	if err != nil {
+		return SecretRecord{}, fmt.Errorf("upsert secret: %w", err)
+	}
+	return s.LookupSecret(ctx, name)
+}
+
+func (s *Store) LookupSecret(ctx context.Context, name string) (SecretRecord, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return SecretRecord{}, ErrSecretNotFound
+	}
+	var (
+		record        SecretRecord
+		createdAtUnix int64
+		updatedAtUnix int64
+	)
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT name, ciphertext, created_at_unix, updated_at_unix FROM secrets WHERE name = ?`,
+		name,
+	).Scan(&record.Name, &record.Ciphertext, &createdAtUnix, &updatedAtUnix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SecretRecord{}, ErrSecretNotFound
+		}
+		return SecretRecord{}, fmt.Errorf("lookup secret: %w", err)
+	}
+	record.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+	record.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+	return record, nil
+}
+
+func (s *Store) ListSecrets(ctx context.Context) ([]SecretRecord, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT name, ciphertext, created_at_unix, updated_at_unix FROM secrets ORDER BY name ASC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list secrets: %w", err)
+	}
+	defer rows.Close()
+	records := []SecretRecord{}
+	for rows.Next() {
+		var (
+			record        SecretRecord
+			createdAtUnix int64
+			updatedAtUnix int64
+		)
+		if err := rows.Scan(&record.Name, &record.Ciphertext, &createdAtUnix, &updatedAtUnix); err != nil {
+			return nil, fmt.Errorf("scan secret: %w", err)
+		}
+		record.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+		record.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate secrets: %w", err)
+	}
+	return records, nil
+}
+
+func (s *Store) DeleteSecret(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM secrets WHERE name = ?`, strings.TrimSpace(name))
+	if err != nil {
+		return fmt.Errorf("delete secret: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete secret rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrSecretNotFound
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSecretLifecycle(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	created, err := sqlStore.PutSecret(ctx, "AGENT_RUNTIME_LLM_API_KEY", "v1:first")
+	if err != nil {
+		t.Fatalf("put secret: %v", err)
+	}
+	if created.CreatedAt.IsZero() || created.UpdatedAt.IsZero() {
+		t.Fatalf("expected timestamps, got %+v", created)
+	}
+	if _, err := sqlStore.PutSecret(ctx, "AGENT_RUNTIME_LLM_API_KEY", "v1:second"); err != nil {
+		t.Fatalf("update secret: %v", err)
+	}
+	record, err := sqlStore.LookupSecret(ctx, "AGENT_RUNTIME_LLM_API_KEY")
+	if err != nil {
+		t.Fatalf("lookup secret: %v", err)
+	}
+	if record.Ciphertext != "v1:second" {
+		t.Fatalf("expected updated ciphertext, got %q", record.Ciphertext)
+	}
+
+	if _, err := sqlStore.PutSecret(ctx, "AGENT_RUNTIME_DISCORD_TOKEN", "v1:token"); err != nil {
+		t.Fatalf("put second secret: %v", err)
+	}
+	records, err := sqlStore.ListSecrets(ctx)
+	if err != nil {
+		t.Fatalf("list secrets: %v", err)
+	}
+	if len(records) != 2 || records[0].Name != "AGENT_RUNTIME_DISCORD_TOKEN" {
+		t.Fatalf("expected two secrets sorted by name, got %+v", records)
+	}
+
+	if err := sqlStore.DeleteSecret(ctx, "AGENT_RUNTIME_LLM_API_KEY"); err != nil {
+		t.Fatalf("delete secret: %v", err)
+	}
+	if _, err := sqlStore.LookupSecret(ctx, "AGENT_RUNTIME_LLM_API_KEY"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound after delete, got %v", err)
+	}
+	if err := sqlStore.DeleteSecret(ctx, "AGENT_RUNTIME_LLM_API_KEY"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound on second delete, got %v", err)
+	}
+	if _, err := sqlStore.PutSecret(ctx, " ", "v1:x"); !errors.Is(err, ErrSecretInvalid) {
+		t.Fatalf("expected ErrSecretInvalid for blank name, got %v", err)
+	}
+}
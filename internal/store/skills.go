@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SkillUsage tracks when a workspace skill template was last put into a
+// prompt and when it was last sent for review. Paths are workspace-relative.
+type SkillUsage struct {
+	WorkspaceID  string
+	Path         string
+	FirstSeenAt  time.Time
+	LastUsedAt   time.Time
+	UseCount     int
+	LastReviewAt time.Time
+	ReviewTaskID string
+}
+
+// RecordSkillUsage marks skills as retrieved for a prompt.
+func (s *Store) RecordSkillUsage(ctx context.Context, workspaceID string, paths []string) error {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil
+	}
+	nowUnix := time.Now().UTC().Unix()
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		_, err := s.db.ExecContext(
+			ctx,
+			`INSERT INTO skill_usage (workspace_id, skill_path, first_seen_unix, last_used_unix, use_count)
+			 VALUES (?, ?, ?, ?, 1)
+			 ON CONFLICT(workspace_id, skill_path) DO UPDATE SET
+			   last_used_unix = excluded.last_used_unix,
+			   use_count = skill_usage.use_count + 1`,
+			workspaceID,
+			path,
+			nowUnix,
+			nowUnix,
+		)
+		if err != nil {
+			return fmt.Errorf("record skill usage: %w", err)
+		}
+	}
+	return nil
+}
+
+// EnsureSkillsTracked starts the staleness clock for skills seen for the
+// first time, so existing skills are not flagged the moment tracking begins.
+func (s *Store) EnsureSkillsTracked(ctx context.Context, workspaceID string, paths []string) error {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil
+	}
+	nowUnix := time.Now().UTC().Unix()
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		_, err := s.db.ExecContext(
+			ctx,
+			`INSERT INTO skill_usage (workspace_id, skill_path, first_seen_unix) VALUES (?, ?, ?)
+			 ON CONFLICT(workspace_id, skill_path) DO NOTHING`,
+			workspaceID,
+			path,
+			nowUnix,
+		)
+		if err != nil {
+			return fmt.Errorf("track skill: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) MarkSkillReviewed(ctx context.Context, workspaceID, path, taskID string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`UPDATE skill_usage SET last_review_unix = ?, review_task_id = ? WHERE workspace_id = ? AND skill_path = ?`,
+		time.Now().UTC().Unix(),
+		strings.TrimSpace(taskID),
+		strings.TrimSpace(workspaceID),
+		strings.TrimSpace(path),
+	)
+	if err != nil {
+		return fmt.Errorf("mark skill reviewed: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListSkillUsage(ctx context.Context, workspaceID string) ([]SkillUsage, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT workspace_id, skill_path, first_seen_unix, COALESCE(last_used_unix, 0), use_count,
+		        COALESCE(last_review_unix, 0), COALESCE(review_task_id, '')
+		 FROM skill_usage WHERE workspace_id = ? ORDER BY skill_path ASC`,
+		strings.TrimSpace(workspaceID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list skill usage: %w", err)
+	}
+	defer rows.Close()
+	usage := []SkillUsage{}
+	for rows.Next() {
+		var (
+			item           SkillUsage
+			firstSeenUnix  int64
+			lastUsedUnix   int64
+			lastReviewUnix int64
+		)
+		if err := rows.Scan(&item.WorkspaceID, &item.Path, &firstSeenUnix, &lastUsedUnix, &item.UseCount, &lastReviewUnix, &item.ReviewTaskID); err != nil {
+			return nil, fmt.Errorf("scan skill usage: %w", err)
+		}
+		item.FirstSeenAt = time.Unix(firstSeenUnix, 0).UTC()
+		if lastUsedUnix > 0 {
+			item.LastUsedAt = time.Unix(lastUsedUnix, 0).UTC()
+		}
+		if lastReviewUnix > 0 {
+			item.LastReviewAt = time.Unix(lastReviewUnix, 0).UTC()
+		}
+		usage = append(usage, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate skill usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSkillUsageTracking(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	if err := sqlStore.EnsureSkillsTracked(ctx, "ws-1", []string{"skills/common/deploy.md", "skills/common/tone.md"}); err != nil {
+		t.Fatalf("ensure tracked: %v", err)
+	}
+	if err := sqlStore.RecordSkillUsage(ctx, "ws-1", []string{"skills/common/tone.md"}); err != nil {
+		t.Fatalf("record usage: %v", err)
+	}
+	if err := sqlStore.RecordSkillUsage(ctx, "ws-1", []string{"skills/common/tone.md"}); err != nil {
+		t.Fatalf("record usage again: %v", err)
+	}
+	if err := sqlStore.MarkSkillReviewed(ctx, "ws-1", "skills/common/deploy.md", "task-1"); err != nil {
+		t.Fatalf("mark reviewed: %v", err)
+	}
+	// Re-tracking must not reset the first-seen clock or usage.
+	if err := sqlStore.EnsureSkillsTracked(ctx, "ws-1", []string{"skills/common/tone.md"}); err != nil {
+		t.Fatalf("ensure tracked again: %v", err)
+	}
+
+	usage, err := sqlStore.ListSkillUsage(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("list usage: %v", err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("expected two tracked skills, got %+v", usage)
+	}
+	deploy, tone := usage[0], usage[1]
+	if deploy.Path != "skills/common/deploy.md" || !deploy.LastUsedAt.IsZero() || deploy.ReviewTaskID != "task-1" || deploy.LastReviewAt.IsZero() {
+		t.Fatalf("unexpected deploy usage %+v", deploy)
+	}
+	if tone.UseCount != 2 || tone.LastUsedAt.IsZero() || tone.FirstSeenAt.IsZero() {
+		t.Fatalf("unexpected tone usage %+v", tone)
+	}
+}
@@ -11,7 +11,16 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db          *sql.DB
+	quotaGuard  QuotaGuard
+	approvals   ActionApprovalNotifier
+	approvalTTL ActionApprovalTTLPolicy
+	// approvalQuorum decides how many admins must sign off on an action.
+	approvalQuorum ActionApprovalQuorumPolicy
+	// approvalRisk rates new actions low, medium or high risk.
+	approvalRisk ActionApprovalRiskClassifier
+	// events hears about new tasks, approvals and blocked agent actions.
+	events EventPublisher
 }
 
 type CreateTaskInput struct {
@@ -31,16 +40,35 @@ type CreateTaskInput struct {
 	SourceExternalID string
 	SourceUserID     string
 	SourceText       string
+	// DependsOn lists tasks of the same workspace that must succeed before
+	// this one runs.
+	DependsOn []string
+	// RunAt keeps a queued task from starting before that time.
+	RunAt time.Time
 }
 
+// sqliteBusyTimeout is how long a write waits for another process holding
+// the database, such as a second runtime sharing the file, before failing
+// with SQLITE_BUSY.
+const sqliteBusyTimeout = 10 * time.Second
+
+// New opens the database at path. Every connection waits out other writers
+// for sqliteBusyTimeout and begins transactions with BEGIN IMMEDIATE, so a
+// transaction that reads before it writes takes the write lock up front
+// instead of failing when another process wrote in between.
 func New(path string) (*Store, error) {
-	db, err := sql.Open("sqlite", path)
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	dsn := path + separator + fmt.Sprintf("_pragma=busy_timeout(%d)&_pragma=foreign_keys(1)&_txlock=immediate", sqliteBusyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open sqlite: %w", err)
 	}
 	db.SetMaxOpenConns(1)
 
-	if _, err := db.Exec(`PRAGMA journal_mode=WAL; PRAGMA foreign_keys=ON;`); err != nil {
+	if _, err := db.Exec(`PRAGMA journal_mode=WAL;`); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("apply sqlite pragmas: %w", err)
 	}
@@ -146,7 +174,27 @@ func (s *Store) AutoMigrate(ctx context.Context) error {
 			executor_plugin TEXT,
 			executed_at_unix INTEGER,
 			created_at_unix INTEGER NOT NULL,
-			updated_at_unix INTEGER NOT NULL
+			updated_at_unix INTEGER NOT NULL,
+			expires_at_unix INTEGER,
+			required_approvals INTEGER NOT NULL DEFAULT 1,
+			risk_level TEXT NOT NULL DEFAULT '',
+			risk_reason TEXT NOT NULL DEFAULT ''
+		);`,
+		`CREATE TABLE IF NOT EXISTS endpoint_checks (
+			id TEXT PRIMARY KEY,
+			endpoint TEXT NOT NULL,
+			url TEXT NOT NULL,
+			up INTEGER NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			latency_ms INTEGER NOT NULL DEFAULT 0,
+			error TEXT,
+			checked_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS action_approval_signoffs (
+			approval_id TEXT NOT NULL,
+			approver_user_id TEXT NOT NULL,
+			approved_at_unix INTEGER NOT NULL,
+			PRIMARY KEY (approval_id, approver_user_id)
 		);`,
 		`CREATE TABLE IF NOT EXISTS objectives (
 			id TEXT PRIMARY KEY,
@@ -201,7 +249,235 @@ func (s *Store) AutoMigrate(ctx context.Context) error {
 			blocked INTEGER NOT NULL DEFAULT 0,
 			block_reason TEXT,
 			message TEXT,
-			created_at_unix INTEGER NOT NULL
+			created_at_unix INTEGER NOT NULL,
+			seq INTEGER,
+			prev_hash TEXT,
+			hash TEXT
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_agent_audit_events_created ON agent_audit_events(workspace_id, created_at_unix, id);`,
+		`CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			event_types TEXT NOT NULL DEFAULT '',
+			enabled INTEGER NOT NULL DEFAULT 1,
+			last_delivery_at_unix INTEGER NOT NULL DEFAULT 0,
+			last_status TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT '',
+			consecutive_failures INTEGER NOT NULL DEFAULT 0,
+			created_at_unix INTEGER NOT NULL,
+			updated_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_workspace ON webhook_subscriptions(workspace_id);`,
+		`CREATE TABLE IF NOT EXISTS secrets (
+			name TEXT PRIMARY KEY,
+			ciphertext TEXT NOT NULL,
+			created_at_unix INTEGER NOT NULL,
+			updated_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS skill_usage (
+			workspace_id TEXT NOT NULL,
+			skill_path TEXT NOT NULL,
+			first_seen_unix INTEGER NOT NULL,
+			last_used_unix INTEGER,
+			use_count INTEGER NOT NULL DEFAULT 0,
+			last_review_unix INTEGER,
+			review_task_id TEXT,
+			PRIMARY KEY(workspace_id, skill_path)
+		);`,
+		`CREATE TABLE IF NOT EXISTS task_plan_steps (
+			task_id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			description TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			result TEXT,
+			updated_at_unix INTEGER NOT NULL,
+			PRIMARY KEY(task_id, position)
+		);`,
+		`CREATE TABLE IF NOT EXISTS task_dependencies (
+			task_id TEXT NOT NULL,
+			depends_on_task_id TEXT NOT NULL,
+			PRIMARY KEY(task_id, depends_on_task_id)
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on ON task_dependencies(depends_on_task_id);`,
+		`CREATE TABLE IF NOT EXISTS task_checkpoints (
+			task_id TEXT NOT NULL,
+			scope TEXT NOT NULL DEFAULT '',
+			step INTEGER NOT NULL,
+			state TEXT NOT NULL,
+			updated_at_unix INTEGER NOT NULL,
+			PRIMARY KEY(task_id, scope)
+		);`,
+		`CREATE TABLE IF NOT EXISTS task_artifacts (
+			id TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL,
+			workspace_id TEXT NOT NULL,
+			path TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			title TEXT NOT NULL DEFAULT '',
+			media_type TEXT NOT NULL DEFAULT '',
+			size_bytes INTEGER NOT NULL DEFAULT 0,
+			position INTEGER NOT NULL DEFAULT 0,
+			created_at_unix INTEGER NOT NULL,
+			UNIQUE(task_id, path)
+		);`,
+		`CREATE TABLE IF NOT EXISTS objective_runs (
+			id TEXT PRIMARY KEY,
+			objective_id TEXT NOT NULL,
+			workspace_id TEXT NOT NULL,
+			task_id TEXT,
+			trigger TEXT NOT NULL,
+			status TEXT NOT NULL,
+			started_at_unix INTEGER NOT NULL,
+			finished_at_unix INTEGER,
+			duration_ms INTEGER NOT NULL DEFAULT 0,
+			summary TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT ''
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_objective_runs_objective ON objective_runs(objective_id, started_at_unix);`,
+		`CREATE INDEX IF NOT EXISTS idx_objective_runs_task ON objective_runs(task_id);`,
+		`CREATE TABLE IF NOT EXISTS objective_states (
+			objective_id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			content_hash TEXT NOT NULL,
+			values_json TEXT NOT NULL DEFAULT '[]',
+			observed_at_unix INTEGER NOT NULL,
+			changed_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS workspace_quotas (
+			workspace_id TEXT PRIMARY KEY,
+			tasks_per_day INTEGER,
+			objectives INTEGER,
+			actions_per_day INTEGER,
+			tokens_per_month INTEGER,
+			updated_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS workspace_botfiles (
+			workspace_id TEXT PRIMARY KEY,
+			version INTEGER NOT NULL DEFAULT 0,
+			checksum TEXT NOT NULL DEFAULT '',
+			content TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			changes_json TEXT NOT NULL DEFAULT '[]',
+			applied_at_unix INTEGER NOT NULL DEFAULT 0,
+			updated_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS canary_rollouts (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL DEFAULT '',
+			kind TEXT NOT NULL,
+			value TEXT NOT NULL,
+			percent INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			min_samples INTEGER NOT NULL,
+			error_margin REAL NOT NULL,
+			block_margin REAL NOT NULL,
+			canary_turns INTEGER NOT NULL DEFAULT 0,
+			canary_errors INTEGER NOT NULL DEFAULT 0,
+			canary_blocks INTEGER NOT NULL DEFAULT 0,
+			control_turns INTEGER NOT NULL DEFAULT 0,
+			control_errors INTEGER NOT NULL DEFAULT 0,
+			control_blocks INTEGER NOT NULL DEFAULT 0,
+			reason TEXT NOT NULL DEFAULT '',
+			created_at_unix INTEGER NOT NULL,
+			updated_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS outbound_messages (
+			id TEXT PRIMARY KEY,
+			connector TEXT NOT NULL,
+			external_id TEXT NOT NULL,
+			collapse_key TEXT NOT NULL DEFAULT '',
+			text TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at_unix INTEGER NOT NULL,
+			updated_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS moderation_cases (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			context_id TEXT NOT NULL DEFAULT '',
+			connector TEXT NOT NULL,
+			external_id TEXT NOT NULL,
+			subject_user_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			linked_case_id TEXT NOT NULL DEFAULT '',
+			action TEXT NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			evidence TEXT NOT NULL DEFAULT '',
+			task_id TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			resolved_by TEXT NOT NULL DEFAULT '',
+			resolution TEXT NOT NULL DEFAULT '',
+			created_at_unix INTEGER NOT NULL,
+			updated_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS cases (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			context_id TEXT NOT NULL DEFAULT '',
+			kind TEXT NOT NULL,
+			title TEXT NOT NULL,
+			summary TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			owner_user_id TEXT NOT NULL DEFAULT '',
+			created_at_unix INTEGER NOT NULL,
+			updated_at_unix INTEGER NOT NULL,
+			resolved_at_unix INTEGER
+		);`,
+		`CREATE TABLE IF NOT EXISTS case_items (
+			case_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			item_id TEXT NOT NULL,
+			excerpt TEXT NOT NULL DEFAULT '',
+			created_at_unix INTEGER NOT NULL,
+			PRIMARY KEY(case_id, kind, item_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS token_usage (
+			workspace_id TEXT NOT NULL,
+			period TEXT NOT NULL,
+			input_tokens INTEGER NOT NULL DEFAULT 0,
+			output_tokens INTEGER NOT NULL DEFAULT 0,
+			updated_at_unix INTEGER NOT NULL,
+			PRIMARY KEY(workspace_id, period)
+		);`,
+		`CREATE TABLE IF NOT EXISTS admin_notices (
+			alert_key TEXT NOT NULL,
+			connector TEXT NOT NULL,
+			external_id TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			created_at_unix INTEGER NOT NULL,
+			handled_at_unix INTEGER,
+			handled_by TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY(alert_key, connector, external_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS change_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			table_name TEXT NOT NULL,
+			row_id TEXT NOT NULL,
+			workspace_id TEXT,
+			operation TEXT NOT NULL,
+			row_json TEXT NOT NULL,
+			changed_at_unix INTEGER NOT NULL
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_change_log_row ON change_log(table_name, row_id, id);`,
+		`CREATE TABLE IF NOT EXISTS workspace_roles (
+			workspace_id TEXT NOT NULL,
+			role TEXT NOT NULL,
+			permissions TEXT NOT NULL DEFAULT '',
+			updated_at_unix INTEGER NOT NULL,
+			PRIMARY KEY(workspace_id, role)
+		);`,
+		`CREATE TABLE IF NOT EXISTS context_member_policies (
+			context_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			muted INTEGER NOT NULL DEFAULT 0,
+			task_creator INTEGER NOT NULL DEFAULT 0,
+			updated_at_unix INTEGER NOT NULL,
+			PRIMARY KEY(context_id, user_id)
 		);`,
 	}
 
@@ -215,6 +491,7 @@ func (s *Store) AutoMigrate(ctx context.Context) error {
 		`ALTER TABLE action_approvals ADD COLUMN execution_message TEXT;`,
 		`ALTER TABLE action_approvals ADD COLUMN executor_plugin TEXT;`,
 		`ALTER TABLE action_approvals ADD COLUMN executed_at_unix INTEGER;`,
+		`ALTER TABLE contexts ADD COLUMN voice_replies INTEGER NOT NULL DEFAULT 0;`,
 		`ALTER TABLE tasks ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;`,
 		`ALTER TABLE tasks ADD COLUMN worker_id INTEGER;`,
 		`ALTER TABLE tasks ADD COLUMN started_at_unix INTEGER;`,
@@ -232,6 +509,10 @@ func (s *Store) AutoMigrate(ctx context.Context) error {
 		`ALTER TABLE tasks ADD COLUMN source_external_id TEXT;`,
 		`ALTER TABLE tasks ADD COLUMN source_user_id TEXT;`,
 		`ALTER TABLE tasks ADD COLUMN source_text TEXT;`,
+		`ALTER TABLE tasks ADD COLUMN external_system TEXT;`,
+		`ALTER TABLE tasks ADD COLUMN external_key TEXT;`,
+		`ALTER TABLE tasks ADD COLUMN external_url TEXT;`,
+		`ALTER TABLE tasks ADD COLUMN result_data TEXT;`,
 		`ALTER TABLE objectives ADD COLUMN cron_expr TEXT;`,
 		`ALTER TABLE objectives ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';`,
 		`ALTER TABLE objectives ADD COLUMN run_count INTEGER NOT NULL DEFAULT 0;`,
@@ -244,6 +525,32 @@ func (s *Store) AutoMigrate(ctx context.Context) error {
 		`ALTER TABLE objectives ADD COLUMN last_failure_unix INTEGER;`,
 		`ALTER TABLE objectives ADD COLUMN auto_paused_reason TEXT;`,
 		`ALTER TABLE objectives ADD COLUMN recent_errors_json TEXT;`,
+		`ALTER TABLE objectives ADD COLUMN deleted_at_unix INTEGER;`,
+		`ALTER TABLE tasks ADD COLUMN deleted_at_unix INTEGER;`,
+		`ALTER TABLE objectives ADD COLUMN revision INTEGER NOT NULL DEFAULT 1;`,
+		`ALTER TABLE tasks ADD COLUMN revision INTEGER NOT NULL DEFAULT 1;`,
+		`ALTER TABLE contexts ADD COLUMN revision INTEGER NOT NULL DEFAULT 1;`,
+		`ALTER TABLE workspace_botfiles ADD COLUMN drift_json TEXT NOT NULL DEFAULT '[]';`,
+		`ALTER TABLE workspace_botfiles ADD COLUMN drift_checked_at_unix INTEGER NOT NULL DEFAULT 0;`,
+		`ALTER TABLE contexts ADD COLUMN shared_knowledge INTEGER NOT NULL DEFAULT 0;`,
+		`ALTER TABLE action_approvals ADD COLUMN expires_at_unix INTEGER;`,
+		`ALTER TABLE action_approvals ADD COLUMN required_approvals INTEGER NOT NULL DEFAULT 1;`,
+		`ALTER TABLE action_approvals ADD COLUMN risk_level TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE action_approvals ADD COLUMN risk_reason TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE contexts ADD COLUMN silenced INTEGER NOT NULL DEFAULT 0;`,
+		`ALTER TABLE contexts ADD COLUMN language TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE agent_audit_events ADD COLUMN seq INTEGER;`,
+		`ALTER TABLE agent_audit_events ADD COLUMN prev_hash TEXT;`,
+		`ALTER TABLE agent_audit_events ADD COLUMN hash TEXT;`,
+		`ALTER TABLE tasks ADD COLUMN dead_lettered_at_unix INTEGER;`,
+		`ALTER TABLE tasks ADD COLUMN run_at_unix INTEGER;`,
+		`ALTER TABLE tasks ADD COLUMN lease_owner TEXT;`,
+		`ALTER TABLE tasks ADD COLUMN lease_expires_at_unix INTEGER;`,
+		`ALTER TABLE tasks ADD COLUMN progress_percent INTEGER;`,
+		`ALTER TABLE tasks ADD COLUMN progress_step TEXT;`,
+		`ALTER TABLE tasks ADD COLUMN progress_updated_at_unix INTEGER;`,
+		`ALTER TABLE objectives ADD COLUMN report_changes_only INTEGER NOT NULL DEFAULT 0;`,
+		`ALTER TABLE objectives ADD COLUMN digest_period TEXT NOT NULL DEFAULT '';`,
 	}
 	for _, query := range alterQueries {
 		if _, err := s.db.ExecContext(ctx, query); err != nil {
@@ -257,23 +564,43 @@ func (s *Store) AutoMigrate(ctx context.Context) error {
 	if _, err := s.db.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_run_key ON tasks(run_key) WHERE run_key IS NOT NULL`); err != nil {
 		return fmt.Errorf("run migration index: %w", err)
 	}
-	return nil
+	if _, err := s.db.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS idx_agent_audit_events_seq ON agent_audit_events(seq) WHERE seq IS NOT NULL`); err != nil {
+		return fmt.Errorf("run migration index: %w", err)
+	}
+	if err := s.chainAgentAuditEvents(ctx); err != nil {
+		return err
+	}
+	if err := s.migrateChangeVersions(ctx); err != nil {
+		return err
+	}
+	return s.migrateSearchIndex(ctx)
 }
 
 func (s *Store) CreateTask(ctx context.Context, input CreateTaskInput) error {
+	if err := checkWorkspaceScope(ctx, input.WorkspaceID, nil); err != nil {
+		return err
+	}
+	dependsOn, err := s.normalizeTaskDependencies(ctx, input)
+	if err != nil {
+		return err
+	}
 	nowUnix := time.Now().UTC().Unix()
 	dueAtUnix := int64(0)
 	if !input.DueAt.IsZero() {
 		dueAtUnix = input.DueAt.UTC().Unix()
 	}
-	_, err := s.db.ExecContext(
+	runAtUnix := int64(0)
+	if !input.RunAt.IsZero() {
+		runAtUnix = input.RunAt.UTC().Unix()
+	}
+	_, err = s.db.ExecContext(
 		ctx,
 		`INSERT INTO tasks (
 			id, workspace_id, context_id, kind, title, prompt, run_key, status,
 			route_class, priority, due_at_unix, assigned_lane,
 			source_connector, source_external_id, source_user_id, source_text,
-			updated_at_unix
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			run_at_unix, updated_at_unix
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		input.ID,
 		input.WorkspaceID,
 		input.ContextID,
@@ -290,6 +617,7 @@ func (s *Store) CreateTask(ctx context.Context, input CreateTaskInput) error {
 		nullIfEmpty(strings.TrimSpace(input.SourceExternalID)),
 		nullIfEmpty(strings.TrimSpace(input.SourceUserID)),
 		nullIfEmpty(strings.TrimSpace(input.SourceText)),
+		nullIfZeroInt64(runAtUnix),
 		nowUnix,
 	)
 	if err != nil {
@@ -299,6 +627,24 @@ func (s *Store) CreateTask(ctx context.Context, input CreateTaskInput) error {
 		}
 		return fmt.Errorf("insert task: %w", err)
 	}
+	if err := s.insertTaskDependencies(ctx, input.ID, dependsOn); err != nil {
+		return err
+	}
+	s.publishEvent(ctx, "task.created", input.WorkspaceID, map[string]any{
+		"task_id":            input.ID,
+		"context_id":         input.ContextID,
+		"kind":               input.Kind,
+		"title":              input.Title,
+		"status":             input.Status,
+		"route_class":        strings.TrimSpace(input.RouteClass),
+		"priority":           strings.TrimSpace(input.Priority),
+		"assigned_lane":      strings.TrimSpace(input.AssignedLane),
+		"source_connector":   strings.TrimSpace(input.SourceConnector),
+		"source_external_id": strings.TrimSpace(input.SourceExternalID),
+		"source_user_id":     strings.TrimSpace(input.SourceUserID),
+		"depends_on":         dependsOn,
+		"run_at_unix":        runAtUnix,
+	})
 	return nil
 }
 
@@ -316,3 +662,14 @@ func nullIfZeroInt64(value int64) any {
 	}
 	return value
 }
+
+// SnapshotTo writes a consistent copy of the database to destPath.
+func (s *Store) SnapshotTo(ctx context.Context, destPath string) error {
+	if strings.TrimSpace(destPath) == "" {
+		return fmt.Errorf("snapshot path is required")
+	}
+	if _, err := s.db.ExecContext(ctx, `VACUUM INTO ?`, destPath); err != nil {
+		return fmt.Errorf("snapshot sqlite: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrTaskArtifactNotFound = errors.New("task artifact not found")
+
+// TaskArtifact is a file a task produced under its workspace: the markdown
+// result every finished task writes, and the files its tools saved. Path is
+// relative to the workspace directory and slash separated.
+type TaskArtifact struct {
+	ID          string
+	TaskID      string
+	WorkspaceID string
+	Path        string
+	Kind        string
+	Title       string
+	MediaType   string
+	SizeBytes   int64
+	CreatedAt   time.Time
+}
+
+type TaskArtifactInput struct {
+	Path      string
+	Kind      string
+	Title     string
+	MediaType string
+	SizeBytes int64
+}
+
+// ReplaceTaskArtifacts records the artifacts of a task run, in order,
+// dropping the records of earlier runs of the task. Artifacts without a
+// path, with an absolute one or one leaving the workspace are skipped.
+func (s *Store) ReplaceTaskArtifacts(ctx context.Context, taskID, workspaceID string, artifacts []TaskArtifactInput) ([]TaskArtifact, error) {
+	taskID = strings.TrimSpace(taskID)
+	workspaceID = strings.TrimSpace(workspaceID)
+	if taskID == "" {
+		return nil, ErrTaskNotFound
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin task artifacts: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_artifacts WHERE task_id = ?`, taskID); err != nil {
+		return nil, fmt.Errorf("clear task artifacts: %w", err)
+	}
+	now := time.Now().UTC()
+	records := []TaskArtifact{}
+	seen := map[string]bool{}
+	for _, input := range artifacts {
+		artifactPath := cleanArtifactPath(input.Path)
+		if artifactPath == "" || seen[artifactPath] {
+			continue
+		}
+		seen[artifactPath] = true
+		record := TaskArtifact{
+			ID:          "artifact_" + uuid.NewString(),
+			TaskID:      taskID,
+			WorkspaceID: workspaceID,
+			Path:        artifactPath,
+			Kind:        strings.ToLower(strings.TrimSpace(input.Kind)),
+			Title:       strings.TrimSpace(input.Title),
+			MediaType:   strings.TrimSpace(input.MediaType),
+			SizeBytes:   input.SizeBytes,
+			CreatedAt:   now,
+		}
+		if record.Kind == "" {
+			record.Kind = "file"
+		}
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO task_artifacts (id, task_id, workspace_id, path, kind, title, media_type, size_bytes, position, created_at_unix)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			record.ID,
+			record.TaskID,
+			record.WorkspaceID,
+			record.Path,
+			record.Kind,
+			record.Title,
+			record.MediaType,
+			record.SizeBytes,
+			len(records),
+			now.Unix(),
+		); err != nil {
+			return nil, fmt.Errorf("insert task artifact: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit task artifacts: %w", err)
+	}
+	return records, nil
+}
+
+// ListTaskArtifacts returns the artifacts of a task in the order the task
+// recorded them.
+func (s *Store) ListTaskArtifacts(ctx context.Context, taskID string) ([]TaskArtifact, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, task_id, workspace_id, path, kind, title, media_type, size_bytes, created_at_unix
+		 FROM task_artifacts
+		 WHERE task_id = ?
+		 ORDER BY position ASC`,
+		strings.TrimSpace(taskID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list task artifacts: %w", err)
+	}
+	defer rows.Close()
+	artifacts := []TaskArtifact{}
+	for rows.Next() {
+		artifact, err := scanTaskArtifact(rows)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate task artifacts: %w", err)
+	}
+	return artifacts, nil
+}
+
+func (s *Store) LookupTaskArtifact(ctx context.Context, id string) (TaskArtifact, error) {
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, task_id, workspace_id, path, kind, title, media_type, size_bytes, created_at_unix
+		 FROM task_artifacts
+		 WHERE id = ?`,
+		strings.TrimSpace(id),
+	)
+	artifact, err := scanTaskArtifact(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TaskArtifact{}, ErrTaskArtifactNotFound
+	}
+	return artifact, err
+}
+
+func scanTaskArtifact(row interface{ Scan(dest ...any) error }) (TaskArtifact, error) {
+	var artifact TaskArtifact
+	var createdAtUnix int64
+	if err := row.Scan(
+		&artifact.ID,
+		&artifact.TaskID,
+		&artifact.WorkspaceID,
+		&artifact.Path,
+		&artifact.Kind,
+		&artifact.Title,
+		&artifact.MediaType,
+		&artifact.SizeBytes,
+		&createdAtUnix,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TaskArtifact{}, err
+		}
+		return TaskArtifact{}, fmt.Errorf("scan task artifact: %w", err)
+	}
+	artifact.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
+	return artifact, nil
+}
+
+func cleanArtifactPath(value string) string {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "\\", "/")
+	if value == "" || strings.HasPrefix(value, "/") {
+		return ""
+	}
+	cleaned := path.Clean(value)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return ""
+	}
+	return cleaned
+}
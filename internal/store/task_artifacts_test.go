@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReplaceTaskArtifacts(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	records, err := sqlStore.ReplaceTaskArtifacts(ctx, "task-1", "ws-1", []TaskArtifactInput{
+		{Path: "tasks/2026/10/17/task-1.md", Kind: "result", Title: "Vendor research", MediaType: "text/markdown", SizeBytes: 120},
+		{Path: "scratch/./report.csv", Title: "report.csv", MediaType: "text/csv", SizeBytes: 40},
+		{Path: "scratch/report.csv"},
+		{Path: "../other/secret.txt"},
+		{Path: "/etc/passwd"},
+	})
+	if err != nil {
+		t.Fatalf("replace artifacts: %v", err)
+	}
+	if len(records) != 2 || records[1].Path != "scratch/report.csv" || records[1].Kind != "file" {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	listed, err := sqlStore.ListTaskArtifacts(ctx, "task-1")
+	if err != nil {
+		t.Fatalf("list artifacts: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Kind != "result" || listed[0].SizeBytes != 120 || listed[1].MediaType != "text/csv" {
+		t.Fatalf("unexpected listing %+v", listed)
+	}
+	found, err := sqlStore.LookupTaskArtifact(ctx, records[1].ID)
+	if err != nil || found.TaskID != "task-1" || found.WorkspaceID != "ws-1" {
+		t.Fatalf("unexpected lookup %+v (%v)", found, err)
+	}
+
+	// A rerun replaces the records of the earlier run.
+	if _, err := sqlStore.ReplaceTaskArtifacts(ctx, "task-1", "ws-1", []TaskArtifactInput{{Path: "tasks/2026/10/18/task-1.md", Kind: "result"}}); err != nil {
+		t.Fatalf("replace again: %v", err)
+	}
+	if listed, _ := sqlStore.ListTaskArtifacts(ctx, "task-1"); len(listed) != 1 || listed[0].Path != "tasks/2026/10/18/task-1.md" {
+		t.Fatalf("expected only the latest run, got %+v", listed)
+	}
+	if _, err := sqlStore.LookupTaskArtifact(ctx, records[1].ID); !errors.Is(err, ErrTaskArtifactNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
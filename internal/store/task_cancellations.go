@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrTaskNotActive = errors.New("task is not queued or running")
+
+// CancelTask moves a queued or running task to cancelled. The worker of a
+// running task is stopped separately; its completion or failure no longer
+// applies once the status changed. Finished tasks return ErrTaskNotActive.
+func (s *Store) CancelTask(ctx context.Context, id string) (TaskRecord, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return TaskRecord{}, ErrTaskNotFound
+	}
+	now := time.Now().UTC().Unix()
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET status = 'cancelled',
+		     finished_at_unix = ?,
+		     updated_at_unix = ?,
+		     revision = revision + 1
+		 WHERE id = ? AND status IN ('queued', 'running') AND deleted_at_unix IS NULL`,
+		now,
+		now,
+		id,
+	)
+	if err != nil {
+		return TaskRecord{}, fmt.Errorf("cancel task: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return TaskRecord{}, fmt.Errorf("cancel task: %w", err)
+	}
+	record, lookupErr := s.LookupTask(ctx, id)
+	if lookupErr != nil {
+		return TaskRecord{}, lookupErr
+	}
+	if rowsAffected == 0 {
+		return record, ErrTaskNotActive
+	}
+	return record, nil
+}
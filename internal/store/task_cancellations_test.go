@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCancelTask(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	for _, id := range []string{"task-queued", "task-running"} {
+		if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+			ID:          id,
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Kind:        "general",
+			Title:       "Research vendors",
+			Prompt:      "Compare vendors",
+			Status:      "queued",
+		}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-running", 1, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+
+	for _, id := range []string{"task-queued", "task-running"} {
+		record, err := sqlStore.CancelTask(ctx, id)
+		if err != nil || record.Status != "cancelled" || record.FinishedAt.IsZero() {
+			t.Fatalf("expected %s cancelled, got %+v (%v)", id, record, err)
+		}
+	}
+	if _, err := sqlStore.CancelTask(ctx, "task-queued"); !errors.Is(err, ErrTaskNotActive) {
+		t.Fatalf("expected a second cancel refused, got %v", err)
+	}
+	if _, err := sqlStore.CancelTask(ctx, "task-missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected missing task, got %v", err)
+	}
+
+	// The worker finishing late must not overwrite the cancellation.
+	if err := sqlStore.MarkTaskCompletedByWorker(ctx, "task-running", 1, time.Now().UTC(), "done", ""); !errors.Is(err, ErrTaskNotRunningForWorker) {
+		t.Fatalf("expected late completion refused, got %v", err)
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-queued", 1, time.Now().UTC()); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected cancelled task not to start, got %v", err)
+	}
+	cancelled, err := sqlStore.ListTasks(ctx, ListTasksInput{WorkspaceID: "ws-1", Status: "cancelled"})
+	if err != nil || len(cancelled) != 2 {
+		t.Fatalf("expected two cancelled tasks, got %+v (%v)", cancelled, err)
+	}
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrTaskCheckpointNotFound = errors.New("task checkpoint not found")
+
+// TaskCheckpoint holds the serialized intermediate state of an agent turn
+// that runs a task. Scope separates turns within one task, such as the steps
+// of a planned task; single-turn tasks use an empty scope.
+type TaskCheckpoint struct {
+	TaskID    string
+	Scope     string
+	Step      int
+	State     string
+	UpdatedAt time.Time
+}
+
+func (s *Store) SaveTaskCheckpoint(ctx context.Context, taskID, scope string, step int, state string) error {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return ErrTaskNotFound
+	}
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO task_checkpoints (task_id, scope, step, state, updated_at_unix)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(task_id, scope) DO UPDATE SET
+		     step = excluded.step,
+		     state = excluded.state,
+		     updated_at_unix = excluded.updated_at_unix`,
+		taskID,
+		strings.TrimSpace(scope),
+		step,
+		state,
+		time.Now().UTC().Unix(),
+	); err != nil {
+		return fmt.Errorf("save task checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) LookupTaskCheckpoint(ctx context.Context, taskID, scope string) (TaskCheckpoint, error) {
+	var checkpoint TaskCheckpoint
+	var updatedAtUnix int64
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT task_id, scope, step, state, updated_at_unix FROM task_checkpoints WHERE task_id = ? AND scope = ?`,
+		strings.TrimSpace(taskID),
+		strings.TrimSpace(scope),
+	).Scan(&checkpoint.TaskID, &checkpoint.Scope, &checkpoint.Step, &checkpoint.State, &updatedAtUnix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TaskCheckpoint{}, ErrTaskCheckpointNotFound
+		}
+		return TaskCheckpoint{}, fmt.Errorf("lookup task checkpoint: %w", err)
+	}
+	checkpoint.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+	return checkpoint, nil
+}
+
+// DeleteTaskCheckpoints drops every checkpoint of a task once its turns have
+// finished.
+func (s *Store) DeleteTaskCheckpoints(ctx context.Context, taskID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM task_checkpoints WHERE task_id = ?`, strings.TrimSpace(taskID)); err != nil {
+		return fmt.Errorf("delete task checkpoints: %w", err)
+	}
+	return nil
+}
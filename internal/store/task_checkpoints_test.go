@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTaskCheckpointSaveOverwriteAndDelete(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := sqlStore.LookupTaskCheckpoint(ctx, "task-1", ""); !errors.Is(err, ErrTaskCheckpointNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := sqlStore.SaveTaskCheckpoint(ctx, "task-1", "", 1, `{"step":1}`); err != nil {
+		t.Fatalf("save checkpoint: %v", err)
+	}
+	if err := sqlStore.SaveTaskCheckpoint(ctx, "task-1", "", 2, `{"step":2}`); err != nil {
+		t.Fatalf("overwrite checkpoint: %v", err)
+	}
+	if err := sqlStore.SaveTaskCheckpoint(ctx, "task-1", "plan:1", 4, `{"step":4}`); err != nil {
+		t.Fatalf("save scoped checkpoint: %v", err)
+	}
+	checkpoint, err := sqlStore.LookupTaskCheckpoint(ctx, "task-1", "")
+	if err != nil || checkpoint.Step != 2 || checkpoint.State != `{"step":2}` {
+		t.Fatalf("expected latest checkpoint, got %+v (%v)", checkpoint, err)
+	}
+	if err := sqlStore.DeleteTaskCheckpoints(ctx, "task-1"); err != nil {
+		t.Fatalf("delete checkpoints: %v", err)
+	}
+	if _, err := sqlStore.LookupTaskCheckpoint(ctx, "task-1", "plan:1"); !errors.Is(err, ErrTaskCheckpointNotFound) {
+		t.Fatalf("expected scoped checkpoint deleted, got %v", err)
+	}
+}
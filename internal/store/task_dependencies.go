@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrTaskDependencyNotFound = errors.New("task dependency not found in workspace")
+
+// maxTaskDependencies bounds the prerequisites of one task.
+const maxTaskDependencies = 20
+
+// normalizeTaskDependencies trims and de-duplicates the prerequisite IDs of
+// a new task and checks that each is a live task of the same workspace.
+// Prerequisites must exist before their dependents, so the graph cannot
+// contain a cycle.
+func (s *Store) normalizeTaskDependencies(ctx context.Context, input CreateTaskInput) ([]string, error) {
+	ids := make([]string, 0, len(input.DependsOn))
+	seen := map[string]struct{}{}
+	for _, id := range input.DependsOn {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) > maxTaskDependencies {
+		return nil, fmt.Errorf("task depends on %d tasks, at most %d are allowed", len(ids), maxTaskDependencies)
+	}
+	for _, id := range ids {
+		if id == strings.TrimSpace(input.ID) {
+			return nil, fmt.Errorf("task %s cannot depend on itself", id)
+		}
+		var count int
+		if err := s.db.QueryRowContext(
+			ctx,
+			`SELECT COUNT(1) FROM tasks WHERE id = ? AND workspace_id = ? AND deleted_at_unix IS NULL`,
+			id,
+			strings.TrimSpace(input.WorkspaceID),
+		).Scan(&count); err != nil {
+			return nil, fmt.Errorf("check task dependency: %w", err)
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTaskDependencyNotFound, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *Store) insertTaskDependencies(ctx context.Context, taskID string, dependsOn []string) error {
+	for _, id := range dependsOn {
+		if _, err := s.db.ExecContext(
+			ctx,
+			`INSERT OR IGNORE INTO task_dependencies (task_id, depends_on_task_id) VALUES (?, ?)`,
+			taskID,
+			id,
+		); err != nil {
+			return fmt.Errorf("insert task dependency: %w", err)
+		}
+	}
+	return nil
+}
+
+// attachTaskDependencies fills DependsOn and Blocks of records. Blocks
+// leaves out dependents that were moved to the trash.
+func (s *Store) attachTaskDependencies(ctx context.Context, records []TaskRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	index := make(map[string]int, len(records))
+	placeholders := make([]string, 0, len(records))
+	args := make([]any, 0, len(records)*2)
+	for position, record := range records {
+		index[record.ID] = position
+		placeholders = append(placeholders, "?")
+		args = append(args, record.ID)
+	}
+	in := strings.Join(placeholders, ", ")
+	args = append(args, args...)
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT d.task_id, d.depends_on_task_id
+		 FROM task_dependencies d
+		 JOIN tasks t ON t.id = d.task_id AND t.deleted_at_unix IS NULL
+		 WHERE d.task_id IN (`+in+`) OR d.depends_on_task_id IN (`+in+`)
+		 ORDER BY d.rowid`,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("list task dependencies: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var taskID, dependsOn string
+		if err := rows.Scan(&taskID, &dependsOn); err != nil {
+			return fmt.Errorf("scan task dependency: %w", err)
+		}
+		if position, ok := index[taskID]; ok {
+			records[position].DependsOn = append(records[position].DependsOn, dependsOn)
+		}
+		if position, ok := index[dependsOn]; ok {
+			records[position].Blocks = append(records[position].Blocks, taskID)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCreateTaskRecordsDependencies(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	create := func(id, workspaceID string, dependsOn ...string) error {
+		return sqlStore.CreateTask(ctx, CreateTaskInput{
+			ID:          id,
+			WorkspaceID: workspaceID,
+			ContextID:   "ctx-1",
+			Kind:        "general",
+			Title:       "Step " + id,
+			Prompt:      "Do " + id,
+			Status:      "queued",
+			DependsOn:   dependsOn,
+		})
+	}
+	for _, id := range []string{"fetch", "clean"} {
+		if err := create(id, "ws-1"); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	if err := create("report", "ws-1", "fetch", " clean ", "fetch"); err != nil {
+		t.Fatalf("create dependent task: %v", err)
+	}
+
+	report, err := sqlStore.LookupTask(ctx, "report")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if len(report.DependsOn) != 2 || report.DependsOn[0] != "fetch" || report.DependsOn[1] != "clean" {
+		t.Fatalf("expected deduplicated prerequisites, got %v", report.DependsOn)
+	}
+	tasks, err := sqlStore.ListTasks(ctx, ListTasksInput{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, task := range tasks {
+		if task.ID == "fetch" && (len(task.Blocks) != 1 || task.Blocks[0] != "report") {
+			t.Fatalf("expected fetch to block report, got %v", task.Blocks)
+		}
+	}
+
+	if err := create("other", "ws-2", "fetch"); !errors.Is(err, ErrTaskDependencyNotFound) {
+		t.Fatalf("expected prerequisite from another workspace rejected, got %v", err)
+	}
+	if err := create("loop", "ws-1", "loop"); err == nil {
+		t.Fatal("expected self dependency rejected")
+	}
+	if _, err := sqlStore.LookupTask(ctx, "other"); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected rejected task not created, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTaskLeaseLost is returned when renewing a lease that expired and was
+// taken by another owner, or whose task is no longer queued or running.
+var ErrTaskLeaseLost = errors.New("task lease is no longer held")
+
+// ClaimTaskLease leases a queued or running task to owner until now+ttl. It
+// reports false while another owner holds an unexpired lease. Leases are
+// runtime bookkeeping and leave the task revision alone.
+func (s *Store) ClaimTaskLease(ctx context.Context, id, owner string, ttl time.Duration, now time.Time) (bool, error) {
+	id, owner = strings.TrimSpace(id), strings.TrimSpace(owner)
+	if id == "" || owner == "" {
+		return false, ErrTaskNotFound
+	}
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET lease_owner = ?, lease_expires_at_unix = ?
+		 WHERE id = ? AND status IN ('queued', 'running') AND deleted_at_unix IS NULL
+		   AND (lease_owner IS NULL OR lease_owner = ? OR COALESCE(lease_expires_at_unix, 0) <= ?)`,
+		owner,
+		now.Add(ttl).Unix(),
+		id,
+		owner,
+		now.Unix(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim task lease: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim task lease: %w", err)
+	}
+	return rowsAffected == 1, nil
+}
+
+// RenewTaskLease extends the lease owner holds on a queued or running task.
+func (s *Store) RenewTaskLease(ctx context.Context, id, owner string, ttl time.Duration, now time.Time) error {
+	id, owner = strings.TrimSpace(id), strings.TrimSpace(owner)
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET lease_expires_at_unix = ?
+		 WHERE id = ? AND lease_owner = ? AND status IN ('queued', 'running') AND deleted_at_unix IS NULL`,
+		now.Add(ttl).Unix(),
+		id,
+		owner,
+	)
+	if err != nil {
+		return fmt.Errorf("renew task lease: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return ErrTaskLeaseLost
+	}
+	return nil
+}
+
+// ReleaseTaskLease drops the lease owner holds, if any.
+func (s *Store) ReleaseTaskLease(ctx context.Context, id, owner string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET lease_owner = NULL, lease_expires_at_unix = NULL WHERE id = ? AND lease_owner = ?`,
+		strings.TrimSpace(id),
+		strings.TrimSpace(owner),
+	)
+	if err != nil {
+		return fmt.Errorf("release task lease: %w", err)
+	}
+	return nil
+}
+
+// ListClaimableTasks lists the tasks a runtime instance sharing the queue
+// may take: queued tasks nobody leased that have not changed for idleFor,
+// so the instance that created them gets them first, and queued or running
+// tasks whose lease expired, typically those of an instance that stopped.
+// Running tasks without a lease count too once idle, as they were started
+// before leases were used. Oldest tasks come first.
+func (s *Store) ListClaimableTasks(ctx context.Context, now time.Time, idleFor time.Duration, limit int) ([]TaskRecord, error) {
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	idleBefore := now.Add(-idleFor).Unix()
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id FROM tasks
+		 WHERE deleted_at_unix IS NULL AND status IN ('queued', 'running')
+		   AND (
+		     (lease_owner IS NULL AND COALESCE(updated_at_unix, 0) <= ?)
+		     OR (lease_owner IS NOT NULL AND COALESCE(lease_expires_at_unix, 0) <= ?)
+		   )
+		 ORDER BY created_at ASC, id ASC
+		 LIMIT ?`,
+		idleBefore,
+		now.Unix(),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list claimable tasks: %w", err)
+	}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan claimable task: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate claimable tasks: %w", err)
+	}
+	rows.Close()
+	tasks := make([]TaskRecord, 0, len(ids))
+	for _, id := range ids {
+		record, err := s.LookupTask(ctx, id)
+		if errors.Is(err, ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, record)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fmt"
+
+	"path/filepath"
+
+	"sync"
+)
+
+func TestTaskLeaseClaimRenewAndExpiry(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.EnableChangeLog(ctx); err != nil {
+		t.Fatalf("enable change log: %v", err)
+	}
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID: "task-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general",
+		Title: "Shared", Prompt: "run once", Status: "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	now := time.Now().UTC()
+
+	claimed, err := sqlStore.ClaimTaskLease(ctx, "task-1", "replica-a", time.Minute, now)
+	if err != nil || !claimed {
+		t.Fatalf("expected replica-a to claim the task, got %t %v", claimed, err)
+	}
+	if claimed, _ := sqlStore.ClaimTaskLease(ctx, "task-1", "replica-b", time.Minute, now); claimed {
+		t.Fatal("expected replica-b to be refused while the lease is live")
+	}
+	if err := sqlStore.RenewTaskLease(ctx, "task-1", "replica-a", time.Minute, now.Add(30*time.Second)); err != nil {
+		t.Fatalf("renew lease: %v", err)
+	}
+	record, err := sqlStore.LookupTask(ctx, "task-1")
+	if err != nil || record.LeaseOwner != "replica-a" || record.Revision != 1 {
+		t.Fatalf("expected the lease on the record without a new revision, got %+v (%v)", record, err)
+	}
+	if history, _ := sqlStore.ListChanges(ctx, ListChangesInput{Table: "tasks", RowID: "task-1"}); len(history) != 1 {
+		t.Fatalf("lease updates must not reach the change log, got %d changes", len(history))
+	}
+	if claimable, err := sqlStore.ListClaimableTasks(ctx, now.Add(time.Minute), 0, 10); err != nil || len(claimable) != 0 {
+		t.Fatalf("expected no claimable task while leased, got %d (%v)", len(claimable), err)
+	}
+
+	expired := now.Add(2 * time.Minute)
+	claimable, err := sqlStore.ListClaimableTasks(ctx, expired, 0, 10)
+	if err != nil || len(claimable) != 1 || claimable[0].ID != "task-1" {
+		t.Fatalf("expected the expired lease to make the task claimable, got %+v (%v)", claimable, err)
+	}
+	if claimed, _ := sqlStore.ClaimTaskLease(ctx, "task-1", "replica-b", time.Minute, expired); !claimed {
+		t.Fatal("expected replica-b to take over the expired lease")
+	}
+	if err := sqlStore.RenewTaskLease(ctx, "task-1", "replica-a", time.Minute, expired); !errors.Is(err, ErrTaskLeaseLost) {
+		t.Fatalf("expected replica-a to have lost the lease, got %v", err)
+	}
+	if err := sqlStore.ReleaseTaskLease(ctx, "task-1", "replica-b"); err != nil {
+		t.Fatalf("release lease: %v", err)
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-1", 1, expired); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	if err := sqlStore.MarkTaskCompleted(ctx, "task-1", expired, "done", ""); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+	if claimed, _ := sqlStore.ClaimTaskLease(ctx, "task-1", "replica-a", time.Minute, expired); claimed {
+		t.Fatal("expected a finished task to be unclaimable")
+	}
+}
+
+func TestListClaimableTasksWaitsForIdleQueuedTasks(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID: "task-new", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general",
+		Title: "Fresh", Prompt: "fresh", Status: "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	now := time.Now().UTC()
+	if claimable, _ := sqlStore.ListClaimableTasks(ctx, now, time.Minute, 10); len(claimable) != 0 {
+		t.Fatalf("expected a fresh task to stay with its creator, got %d", len(claimable))
+	}
+	if claimable, _ := sqlStore.ListClaimableTasks(ctx, now.Add(2*time.Minute), time.Minute, 10); len(claimable) != 1 {
+		t.Fatalf("expected an idle queued task to be claimable, got %d", len(claimable))
+	}
+}
+
+func TestStoresSharingAFileWaitForEachOther(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "shared.sqlite")
+	ctx := context.Background()
+	stores := make([]*Store, 2)
+	for index := range stores {
+		sqlStore, err := New(dbPath)
+		if err != nil {
+			t.Fatalf("open store %d: %v", index, err)
+		}
+		t.Cleanup(func() { _ = sqlStore.Close() })
+		if err := sqlStore.AutoMigrate(ctx); err != nil {
+			t.Fatalf("migrate store %d: %v", index, err)
+		}
+		stores[index] = sqlStore
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for index := range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sqlStore := stores[index%2]
+			id := fmt.Sprintf("task-%d", index)
+			if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+				ID: id, WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general",
+				Title: "Shared", Prompt: "run once", Status: "queued",
+			}); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := sqlStore.ClaimTaskLease(ctx, id, fmt.Sprintf("replica-%d", index%2), time.Minute, time.Now().UTC()); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("expected concurrent writes from both stores to succeed, got %v", err)
+	}
+}
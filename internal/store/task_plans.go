@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTaskPlanClosed       = errors.New("task plan cannot change after the task finished")
+	ErrTaskPlanStepNotFound = errors.New("task plan step not found")
+)
+
+const (
+	TaskPlanStepPending = "pending"
+	TaskPlanStepRunning = "running"
+	TaskPlanStepDone    = "done"
+	TaskPlanStepFailed  = "failed"
+)
+
+// TaskPlanStep is one step of the plan a worker follows for a planned task.
+// Steps are checkpointed as they finish so a requeued task resumes after the
+// last completed step.
+type TaskPlanStep struct {
+	TaskID      string
+	Position    int
+	Description string
+	Status      string
+	Result      string
+	UpdatedAt   time.Time
+}
+
+// SetTaskPlan replaces the steps that have not started yet with steps, in
+// order. Completed and running steps are kept, so the planner and admins
+// editing a plan mid-flight only ever change the remaining work.
+func (s *Store) SetTaskPlan(ctx context.Context, taskID string, steps []string) ([]TaskPlanStep, error) {
+	taskID = strings.TrimSpace(taskID)
+	record, err := s.LookupTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(strings.TrimSpace(record.Status)) {
+	case "succeeded", "failed", "cancelled":
+		return nil, ErrTaskPlanClosed
+	}
+	now := time.Now().UTC().Unix()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM task_plan_steps WHERE task_id = ? AND status IN (?, ?)`,
+		taskID,
+		TaskPlanStepPending,
+		TaskPlanStepFailed,
+	); err != nil {
+		return nil, fmt.Errorf("clear pending task plan steps: %w", err)
+	}
+	var last int
+	if err := tx.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(MAX(position), 0) FROM task_plan_steps WHERE task_id = ?`,
+		taskID,
+	).Scan(&last); err != nil {
+		return nil, fmt.Errorf("read task plan position: %w", err)
+	}
+	for _, step := range steps {
+		step = strings.TrimSpace(step)
+		if step == "" {
+			continue
+		}
+		last++
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO task_plan_steps (task_id, position, description, status, updated_at_unix) VALUES (?, ?, ?, ?, ?)`,
+			taskID,
+			last,
+			step,
+			TaskPlanStepPending,
+			now,
+		); err != nil {
+			return nil, fmt.Errorf("insert task plan step: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit task plan: %w", err)
+	}
+	return s.ListTaskPlan(ctx, taskID)
+}
+
+// ListTaskPlan returns the plan steps of a task in execution order. Tasks
+// without a plan return an empty list.
+func (s *Store) ListTaskPlan(ctx context.Context, taskID string) ([]TaskPlanStep, error) {
+	if err := s.checkRowScope(ctx, "tasks", taskID, ErrTaskNotFound); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT task_id, position, description, status, COALESCE(result, ''), updated_at_unix
+		 FROM task_plan_steps
+		 WHERE task_id = ?
+		 ORDER BY position ASC`,
+		strings.TrimSpace(taskID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list task plan: %w", err)
+	}
+	defer rows.Close()
+
+	steps := []TaskPlanStep{}
+	for rows.Next() {
+		var step TaskPlanStep
+		var updatedAtUnix int64
+		if err := rows.Scan(&step.TaskID, &step.Position, &step.Description, &step.Status, &step.Result, &updatedAtUnix); err != nil {
+			return nil, fmt.Errorf("scan task plan step: %w", err)
+		}
+		step.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+		steps = append(steps, step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate task plan: %w", err)
+	}
+	return steps, nil
+}
+
+// UpdateTaskPlanStep checkpoints the status and result of one plan step.
+func (s *Store) UpdateTaskPlanStep(ctx context.Context, taskID string, position int, status, result string) error {
+	result = strings.TrimSpace(result)
+	if err := s.checkRowScope(ctx, "tasks", taskID, ErrTaskNotFound); err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE task_plan_steps SET status = ?, result = ?, updated_at_unix = ? WHERE task_id = ? AND position = ?`,
+		strings.TrimSpace(status),
+		nullIfEmpty(result),
+		time.Now().UTC().Unix(),
+		strings.TrimSpace(taskID),
+		position,
+	)
+	if err != nil {
+		return fmt.Errorf("update task plan step: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return ErrTaskPlanStepNotFound
+	}
+	return nil
+}
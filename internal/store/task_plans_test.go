@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetTaskPlanKeepsCompletedSteps(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-plan",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Audit dependencies",
+		Prompt:      "Audit dependencies and report outdated ones",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	steps, err := sqlStore.SetTaskPlan(ctx, "task-plan", []string{"List modules", " ", "Check versions", "Write report"})
+	if err != nil || len(steps) != 3 {
+		t.Fatalf("expected three plan steps, got %+v (%v)", steps, err)
+	}
+	if err := sqlStore.UpdateTaskPlanStep(ctx, "task-plan", 1, TaskPlanStepDone, "12 modules"); err != nil {
+		t.Fatalf("checkpoint step: %v", err)
+	}
+	if err := sqlStore.UpdateTaskPlanStep(ctx, "task-plan", 2, TaskPlanStepRunning, ""); err != nil {
+		t.Fatalf("mark step running: %v", err)
+	}
+
+	steps, err = sqlStore.SetTaskPlan(ctx, "task-plan", []string{"Open upgrade issues"})
+	if err != nil {
+		t.Fatalf("edit plan: %v", err)
+	}
+	if len(steps) != 3 {
+		t.Fatalf("expected done and running steps kept plus one new step, got %+v", steps)
+	}
+	if steps[0].Status != TaskPlanStepDone || steps[0].Result != "12 modules" {
+		t.Fatalf("expected completed step preserved, got %+v", steps[0])
+	}
+	if steps[1].Description != "Check versions" || steps[2].Description != "Open upgrade issues" || steps[2].Position != 3 {
+		t.Fatalf("unexpected edited plan %+v", steps)
+	}
+
+	if err := sqlStore.UpdateTaskPlanStep(ctx, "task-plan", 9, TaskPlanStepDone, ""); !errors.Is(err, ErrTaskPlanStepNotFound) {
+		t.Fatalf("expected missing step error, got %v", err)
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-plan", 1, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	if err := sqlStore.MarkTaskCompleted(ctx, "task-plan", time.Now().UTC(), "done", ""); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+	if _, err := sqlStore.SetTaskPlan(ctx, "task-plan", []string{"Too late"}); !errors.Is(err, ErrTaskPlanClosed) {
+		t.Fatalf("expected closed plan error, got %v", err)
+	}
+	if _, err := sqlStore.SetTaskPlan(ctx, "missing", []string{"x"}); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected task not found, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const taskProgressStepMaxChars = 300
+
+// SetTaskProgress records how far a running task has come. A negative
+// percent means unknown. Progress is cleared when the task starts again and
+// leaves the task revision alone, so it never conflicts with admin edits.
+func (s *Store) SetTaskProgress(ctx context.Context, id string, percent int, step string, at time.Time) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrTaskNotFound
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	step = strings.TrimSpace(step)
+	if len(step) > taskProgressStepMaxChars {
+		step = step[:taskProgressStepMaxChars]
+	}
+	var percentValue any
+	if percent >= 0 {
+		percentValue = min(percent, 100)
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET progress_percent = ?, progress_step = ?, progress_updated_at_unix = ?
+		 WHERE id = ? AND status = 'running'`,
+		percentValue,
+		nullIfEmpty(step),
+		at.Unix(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("set task progress: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return ErrTaskNotActive
+	}
+	return nil
+}
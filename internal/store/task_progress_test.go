@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetTaskProgress(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID: "task-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Kind: "general",
+		Title: "Report", Prompt: "report", Status: "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := sqlStore.SetTaskProgress(ctx, "task-1", 10, "step 1/5", time.Now()); !errors.Is(err, ErrTaskNotActive) {
+		t.Fatalf("expected progress refused before the task runs, got %v", err)
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-1", 1, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	at := time.Now().UTC().Truncate(time.Second)
+	if err := sqlStore.SetTaskProgress(ctx, "task-1", 60, "step 3/5: fetch data", at); err != nil {
+		t.Fatalf("set progress: %v", err)
+	}
+	task, err := sqlStore.LookupTask(ctx, "task-1")
+	if err != nil {
+		t.Fatalf("lookup task: %v", err)
+	}
+	if task.ProgressPercent != 60 || task.ProgressStep != "step 3/5: fetch data" || !task.ProgressUpdatedAt.Equal(at) || task.Revision != 2 {
+		t.Fatalf("unexpected progress %+v", task)
+	}
+	if err := sqlStore.SetTaskProgress(ctx, "task-1", -1, "agent step 4", at); err != nil {
+		t.Fatalf("set unknown progress: %v", err)
+	}
+	if task, _ := sqlStore.LookupTask(ctx, "task-1"); task.ProgressPercent != -1 {
+		t.Fatalf("expected unknown percent, got %d", task.ProgressPercent)
+	}
+
+	if err := sqlStore.MarkTaskRunning(ctx, "task-1", 2, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running again: %v", err)
+	}
+	if task, _ := sqlStore.LookupTask(ctx, "task-1"); task.ProgressStep != "" || !task.ProgressUpdatedAt.IsZero() {
+		t.Fatalf("expected progress cleared on a new run, got %+v", task)
+	}
+}
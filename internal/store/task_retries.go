@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleTaskRetry puts a task that failed on workerID back to queued for
+// its next attempt. The error of the failed run is kept so operators can
+// see why the task is being retried; attempts keeps counting.
+func (s *Store) ScheduleTaskRetry(ctx context.Context, id string, workerID int, message string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrTaskNotFound
+	}
+	if workerID < 1 {
+		return ErrTaskNotRunningForWorker
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET status = 'queued',
+		     worker_id = NULL,
+		     started_at_unix = NULL,
+		     finished_at_unix = NULL,
+		     error_message = ?,
+		     updated_at_unix = ?,
+		     revision = revision + 1
+		 WHERE id = ? AND status = 'running' AND worker_id = ?`,
+		nullIfEmpty(strings.TrimSpace(message)),
+		time.Now().UTC().Unix(),
+		id,
+		workerID,
+	)
+	if err != nil {
+		return fmt.Errorf("schedule task retry: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return ErrTaskNotRunningForWorker
+	}
+	return nil
+}
+
+// MarkTaskDeadLettered flags a failed task whose retries ran out, so it
+// shows up in dead-letter listings until someone retries or trashes it.
+func (s *Store) MarkTaskDeadLettered(ctx context.Context, id string, at time.Time) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrTaskNotFound
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET dead_lettered_at_unix = ?,
+		     updated_at_unix = ?,
+		     revision = revision + 1
+		 WHERE id = ? AND status = 'failed'`,
+		at.Unix(),
+		time.Now().UTC().Unix(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("mark task dead-lettered: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
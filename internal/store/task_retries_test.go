@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskRetryAndDeadLetter(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-flaky",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "objective",
+		Title:       "Nightly digest",
+		Prompt:      "Summarize the day",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-flaky", 2, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	if err := sqlStore.ScheduleTaskRetry(ctx, "task-flaky", 3, "timeout"); !errors.Is(err, ErrTaskNotRunningForWorker) {
+		t.Fatalf("expected retry from another worker refused, got %v", err)
+	}
+	if err := sqlStore.ScheduleTaskRetry(ctx, "task-flaky", 2, "timeout"); err != nil {
+		t.Fatalf("schedule retry: %v", err)
+	}
+	record, err := sqlStore.LookupTask(ctx, "task-flaky")
+	if err != nil || record.Status != "queued" || record.Attempts != 1 || record.ErrorMessage != "timeout" || record.WorkerID != 0 {
+		t.Fatalf("expected queued task keeping its error, got %+v (%v)", record, err)
+	}
+
+	if err := sqlStore.MarkTaskDeadLettered(ctx, "task-flaky", time.Now().UTC()); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected only failed tasks dead-lettered, got %v", err)
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-flaky", 2, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	if err := sqlStore.MarkTaskFailedByWorker(ctx, "task-flaky", 2, time.Now().UTC(), "timeout again"); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	if err := sqlStore.MarkTaskDeadLettered(ctx, "task-flaky", time.Now().UTC()); err != nil {
+		t.Fatalf("mark dead-lettered: %v", err)
+	}
+	dead, err := sqlStore.ListTasks(ctx, ListTasksInput{WorkspaceID: "ws-1", DeadLettered: true})
+	if err != nil || len(dead) != 1 || dead[0].Attempts != 2 || dead[0].DeadLetteredAt.IsZero() {
+		t.Fatalf("expected the dead-lettered task listed, got %+v (%v)", dead, err)
+	}
+}
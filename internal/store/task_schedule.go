@@ -0,0 +1,64 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTaskScheduleInPast is returned for a task schedule that already passed.
+var ErrTaskScheduleInPast = errors.New("task schedule is in the past")
+
+var taskScheduleLocalLayouts = []string{
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// ResolveTaskSchedule turns the schedule of a one-off task into the time it
+// runs. The schedule is an RFC 3339 timestamp, a local date and time read in
+// timezone (UTC when empty), or a cron expression whose next slot after now
+// is taken. An empty schedule resolves to the zero time.
+func ResolveTaskSchedule(schedule, timezone string, now time.Time) (time.Time, error) {
+	schedule = strings.TrimSpace(schedule)
+	if schedule == "" {
+		return time.Time{}, nil
+	}
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	normalizedTimezone, err := normalizeObjectiveTimezone(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	location, err := time.LoadLocation(normalizedTimezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("load timezone: %w", err)
+	}
+	runAt, ok := parseTaskScheduleTime(schedule, location)
+	if !ok {
+		next, err := ComputeScheduleNextRunForTimezone(schedule, normalizedTimezone, now)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("schedule must be a timestamp or a cron expression: %w", err)
+		}
+		return next, nil
+	}
+	if !runAt.After(now) {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrTaskScheduleInPast, runAt.UTC().Format(time.RFC3339))
+	}
+	return runAt.UTC(), nil
+}
+
+func parseTaskScheduleTime(schedule string, location *time.Location) (time.Time, bool) {
+	if parsed, err := time.Parse(time.RFC3339, schedule); err == nil {
+		return parsed, true
+	}
+	for _, layout := range taskScheduleLocalLayouts {
+		if parsed, err := time.ParseInLocation(layout, schedule, location); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResolveTaskSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 30, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		schedule string
+		timezone string
+		expected time.Time
+	}{
+		{"empty", "", "", time.Time{}},
+		{"rfc3339", "2026-03-03T09:00:00Z", "", time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{"local in timezone", "2026-03-03 09:00", "Europe/Berlin", time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)},
+		{"cron next slot", "0 9 * * *", "", time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		got, err := ResolveTaskSchedule(tc.schedule, tc.timezone, now)
+		if err != nil {
+			t.Fatalf("%s: resolve: %v", tc.name, err)
+		}
+		if !got.Equal(tc.expected) {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestResolveTaskScheduleRejectsPastAndInvalid(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 30, 0, 0, time.UTC)
+	if _, err := ResolveTaskSchedule("2026-03-01T09:00:00Z", "", now); !errors.Is(err, ErrTaskScheduleInPast) {
+		t.Fatalf("expected ErrTaskScheduleInPast, got %v", err)
+	}
+	if _, err := ResolveTaskSchedule("tomorrow-ish", "", now); err == nil {
+		t.Fatal("expected invalid schedule error")
+	}
+	if _, err := ResolveTaskSchedule("0 9 * * *", "Mars/Base", now); err == nil {
+		t.Fatal("expected invalid timezone error")
+	}
+}
+
+func TestCreateTaskStoresRunAt(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	runAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID: "task_scheduled", WorkspaceID: "ws_1", ContextID: "ctx_1", Kind: "general",
+		Title: "Later", Prompt: "Do it later", Status: "queued", RunAt: runAt,
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	task, err := sqlStore.LookupTask(ctx, "task_scheduled")
+	if err != nil {
+		t.Fatalf("lookup task: %v", err)
+	}
+	if !task.RunAt.Equal(runAt) {
+		t.Fatalf("expected run_at %s, got %s", runAt, task.RunAt)
+	}
+	tasks, err := sqlStore.ListTasks(ctx, ListTasksInput{WorkspaceID: "ws_1"})
+	if err != nil {
+		t.Fatalf("list tasks: %v", err)
+	}
+	if len(tasks) != 1 || !tasks[0].RunAt.Equal(runAt) {
+		t.Fatalf("expected listed task to carry run_at, got %+v", tasks)
+	}
+}
@@ -12,6 +12,7 @@ import (
 var ErrTaskNotFound = errors.New("task not found")
 var ErrTaskRunAlreadyExists = errors.New("task run already exists")
 var ErrTaskNotRunningForWorker = errors.New("task not running for worker")
+var ErrTaskActive = errors.New("task is still queued or running")
 
 type TaskRecord struct {
 	ID               string
@@ -35,9 +36,32 @@ type TaskRecord struct {
 	FinishedAt       time.Time
 	ResultSummary    string
 	ResultPath       string
+	ResultData       string
 	ErrorMessage     string
+	ExternalSystem   string
+	ExternalKey      string
+	ExternalURL      string
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
+	Revision         int
+	// DependsOn lists the tasks that must succeed before this one runs and
+	// Blocks the tasks waiting on this one.
+	DependsOn []string
+	Blocks    []string
+	// DeadLetteredAt is set when the task failed every attempt its retry
+	// policy allowed; such a task stays failed.
+	DeadLetteredAt time.Time
+	// RunAt is when a scheduled task may start; zero for tasks that run as
+	// soon as a worker is free.
+	RunAt time.Time
+	// LeaseOwner names the runtime instance holding the task's lease when
+	// instances share the queue.
+	LeaseOwner string
+	// ProgressStep and ProgressPercent are the last progress the worker
+	// reported for the current run; the percent is -1 when unknown.
+	ProgressStep      string
+	ProgressPercent   int
+	ProgressUpdatedAt time.Time
 }
 
 type ListTasksInput struct {
@@ -45,7 +69,9 @@ type ListTasksInput struct {
 	ContextID   string
 	Kind        string
 	Status      string
-	Limit       int
+	// DeadLettered limits the list to dead-lettered tasks.
+	DeadLettered bool
+	Limit        int
 }
 
 func (s *Store) MarkTaskRunning(ctx context.Context, id string, workerID int, startedAt time.Time) error {
@@ -67,8 +93,13 @@ func (s *Store) MarkTaskRunning(ctx context.Context, id string, workerID int, st
 		     error_message = NULL,
 		     result_summary = NULL,
 		     result_path = NULL,
-		     updated_at_unix = ?
-		 WHERE id = ?`,
+		     result_data = NULL,
+		     progress_percent = NULL,
+		     progress_step = NULL,
+		     progress_updated_at_unix = NULL,
+		     updated_at_unix = ?,
+		     revision = revision + 1
+		 WHERE id = ? AND status != 'cancelled'`,
 		workerID,
 		startedAt.Unix(),
 		time.Now().UTC().Unix(),
@@ -98,8 +129,10 @@ func (s *Store) RequeueTask(ctx context.Context, id string) error {
 		     finished_at_unix = NULL,
 		     result_summary = NULL,
 		     result_path = NULL,
+		     result_data = NULL,
 		     error_message = NULL,
-		     updated_at_unix = ?
+		     updated_at_unix = ?,
+		     revision = revision + 1
 		 WHERE id = ?`,
 		time.Now().UTC().Unix(),
 		id,
@@ -130,7 +163,8 @@ func (s *Store) MarkTaskCompleted(ctx context.Context, id string, finishedAt tim
 		     result_summary = ?,
 		     result_path = ?,
 		     error_message = NULL,
-		     updated_at_unix = ?
+		     updated_at_unix = ?,
+		     revision = revision + 1
 		 WHERE id = ?`,
 		finishedAt.Unix(),
 		nullIfEmpty(strings.TrimSpace(summary)),
@@ -167,7 +201,8 @@ func (s *Store) MarkTaskCompletedByWorker(ctx context.Context, id string, worker
 		     result_summary = ?,
 		     result_path = ?,
 		     error_message = NULL,
-		     updated_at_unix = ?
+		     updated_at_unix = ?,
+		     revision = revision + 1
 		 WHERE id = ? AND status = 'running' AND worker_id = ?`,
 		finishedAt.Unix(),
 		nullIfEmpty(strings.TrimSpace(summary)),
@@ -186,6 +221,30 @@ func (s *Store) MarkTaskCompletedByWorker(ctx context.Context, id string, worker
 	return nil
 }
 
+// SetTaskResultData stores the structured tool results of a finished task
+// as a JSON document.
+func (s *Store) SetTaskResultData(ctx context.Context, id, data string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrTaskNotFound
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET result_data = ?, updated_at_unix = ?, revision = revision + 1 WHERE id = ?`,
+		nullIfEmpty(strings.TrimSpace(data)),
+		time.Now().UTC().Unix(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("set task result data: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
 func (s *Store) MarkTaskFailed(ctx context.Context, id string, finishedAt time.Time, message string) error {
 	id = strings.TrimSpace(id)
 	if id == "" {
@@ -200,7 +259,8 @@ func (s *Store) MarkTaskFailed(ctx context.Context, id string, finishedAt time.T
 		 SET status = 'failed',
 		     finished_at_unix = ?,
 		     error_message = ?,
-		     updated_at_unix = ?
+		     updated_at_unix = ?,
+		     revision = revision + 1
 		 WHERE id = ?`,
 		finishedAt.Unix(),
 		nullIfEmpty(strings.TrimSpace(message)),
@@ -234,7 +294,8 @@ func (s *Store) MarkTaskFailedByWorker(ctx context.Context, id string, workerID
 		 SET status = 'failed',
 		     finished_at_unix = ?,
 		     error_message = ?,
-		     updated_at_unix = ?
+		     updated_at_unix = ?,
+		     revision = revision + 1
 		 WHERE id = ? AND status = 'running' AND worker_id = ?`,
 		finishedAt.Unix(),
 		nullIfEmpty(strings.TrimSpace(message)),
@@ -259,10 +320,13 @@ func (s *Store) LookupTask(ctx context.Context, id string) (TaskRecord, error) {
 		        COALESCE(route_class, ''), COALESCE(priority, ''), COALESCE(due_at_unix, 0),
 		        COALESCE(assigned_lane, ''), COALESCE(source_connector, ''), COALESCE(source_external_id, ''), COALESCE(source_user_id, ''), COALESCE(source_text, ''),
 		        attempts, COALESCE(worker_id, 0), COALESCE(started_at_unix, 0), COALESCE(finished_at_unix, 0),
-		        COALESCE(result_summary, ''), COALESCE(result_path, ''), COALESCE(error_message, ''),
-		        created_at, COALESCE(updated_at_unix, 0)
+		        COALESCE(result_summary, ''), COALESCE(result_path, ''), COALESCE(result_data, ''), COALESCE(error_message, ''),
+		        COALESCE(external_system, ''), COALESCE(external_key, ''), COALESCE(external_url, ''),
+		        created_at, COALESCE(updated_at_unix, 0), revision, COALESCE(dead_lettered_at_unix, 0),
+		        COALESCE(run_at_unix, 0), COALESCE(lease_owner, ''),
+		        COALESCE(progress_step, ''), COALESCE(progress_percent, -1), COALESCE(progress_updated_at_unix, 0)
 		 FROM tasks
-		 WHERE id = ?`,
+		 WHERE id = ? AND deleted_at_unix IS NULL`,
 		strings.TrimSpace(id),
 	)
 	var record TaskRecord
@@ -270,6 +334,9 @@ func (s *Store) LookupTask(ctx context.Context, id string) (TaskRecord, error) {
 	var startedUnix int64
 	var finishedUnix int64
 	var updatedUnix int64
+	var deadLetteredUnix int64
+	var runAtUnix int64
+	var progressUnix int64
 	var createdAtText string
 	if err := row.Scan(
 		&record.ID,
@@ -293,9 +360,20 @@ func (s *Store) LookupTask(ctx context.Context, id string) (TaskRecord, error) {
 		&finishedUnix,
 		&record.ResultSummary,
 		&record.ResultPath,
+		&record.ResultData,
 		&record.ErrorMessage,
+		&record.ExternalSystem,
+		&record.ExternalKey,
+		&record.ExternalURL,
 		&createdAtText,
 		&updatedUnix,
+		&record.Revision,
+		&deadLetteredUnix,
+		&runAtUnix,
+		&record.LeaseOwner,
+		&record.ProgressStep,
+		&record.ProgressPercent,
+		&progressUnix,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return TaskRecord{}, ErrTaskNotFound
@@ -314,8 +392,24 @@ func (s *Store) LookupTask(ctx context.Context, id string) (TaskRecord, error) {
 	if updatedUnix > 0 {
 		record.UpdatedAt = time.Unix(updatedUnix, 0).UTC()
 	}
+	if deadLetteredUnix > 0 {
+		record.DeadLetteredAt = time.Unix(deadLetteredUnix, 0).UTC()
+	}
+	if runAtUnix > 0 {
+		record.RunAt = time.Unix(runAtUnix, 0).UTC()
+	}
+	if progressUnix > 0 {
+		record.ProgressUpdatedAt = time.Unix(progressUnix, 0).UTC()
+	}
 	record.CreatedAt = parseSQLiteDateTime(createdAtText)
-	return record, nil
+	if err := checkWorkspaceScope(ctx, record.WorkspaceID, ErrTaskNotFound); err != nil {
+		return TaskRecord{}, err
+	}
+	records := []TaskRecord{record}
+	if err := s.attachTaskDependencies(ctx, records); err != nil {
+		return TaskRecord{}, err
+	}
+	return records[0], nil
 }
 
 func (s *Store) ListTasks(ctx context.Context, input ListTasksInput) ([]TaskRecord, error) {
@@ -327,9 +421,13 @@ func (s *Store) ListTasks(ctx context.Context, input ListTasksInput) ([]TaskReco
 		limit = 500
 	}
 
-	whereParts := []string{"1=1"}
+	workspaceID, err := scopedWorkspaceFilter(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	whereParts := []string{"deleted_at_unix IS NULL"}
 	args := make([]any, 0, 6)
-	if workspaceID := strings.TrimSpace(input.WorkspaceID); workspaceID != "" {
+	if workspaceID != "" {
 		whereParts = append(whereParts, "workspace_id = ?")
 		args = append(args, workspaceID)
 	}
@@ -345,6 +443,9 @@ func (s *Store) ListTasks(ctx context.Context, input ListTasksInput) ([]TaskReco
 		whereParts = append(whereParts, "status = ?")
 		args = append(args, status)
 	}
+	if input.DeadLettered {
+		whereParts = append(whereParts, "dead_lettered_at_unix IS NOT NULL")
+	}
 	args = append(args, limit)
 
 	rows, err := s.db.QueryContext(
@@ -353,7 +454,10 @@ func (s *Store) ListTasks(ctx context.Context, input ListTasksInput) ([]TaskReco
 		        COALESCE(route_class, ''), COALESCE(priority, ''), COALESCE(due_at_unix, 0),
 		        COALESCE(assigned_lane, ''), COALESCE(source_connector, ''), COALESCE(source_external_id, ''), COALESCE(source_user_id, ''), COALESCE(source_text, ''),
 		        attempts, COALESCE(worker_id, 0), COALESCE(started_at_unix, 0), COALESCE(finished_at_unix, 0),
-		        COALESCE(result_summary, ''), COALESCE(result_path, ''), COALESCE(error_message, ''), created_at, COALESCE(updated_at_unix, 0)
+		        COALESCE(result_summary, ''), COALESCE(result_path, ''), COALESCE(result_data, ''), COALESCE(error_message, ''),
+		        COALESCE(external_system, ''), COALESCE(external_key, ''), COALESCE(external_url, ''), created_at, COALESCE(updated_at_unix, 0), revision,
+		        COALESCE(dead_lettered_at_unix, 0), COALESCE(run_at_unix, 0), COALESCE(lease_owner, ''),
+		        COALESCE(progress_step, ''), COALESCE(progress_percent, -1), COALESCE(progress_updated_at_unix, 0)
 		 FROM tasks
 		 WHERE `+strings.Join(whereParts, " AND ")+`
 		 ORDER BY COALESCE(updated_at_unix, 0) DESC, created_at DESC
@@ -372,6 +476,9 @@ func (s *Store) ListTasks(ctx context.Context, input ListTasksInput) ([]TaskReco
 		var startedUnix int64
 		var finishedUnix int64
 		var updatedUnix int64
+		var deadLetteredUnix int64
+		var runAtUnix int64
+		var progressUnix int64
 		var createdAtText string
 		if err := rows.Scan(
 			&record.ID,
@@ -395,9 +502,20 @@ func (s *Store) ListTasks(ctx context.Context, input ListTasksInput) ([]TaskReco
 			&finishedUnix,
 			&record.ResultSummary,
 			&record.ResultPath,
+			&record.ResultData,
 			&record.ErrorMessage,
+			&record.ExternalSystem,
+			&record.ExternalKey,
+			&record.ExternalURL,
 			&createdAtText,
 			&updatedUnix,
+			&record.Revision,
+			&deadLetteredUnix,
+			&runAtUnix,
+			&record.LeaseOwner,
+			&record.ProgressStep,
+			&record.ProgressPercent,
+			&progressUnix,
 		); err != nil {
 			return nil, fmt.Errorf("scan task row: %w", err)
 		}
@@ -413,18 +531,67 @@ func (s *Store) ListTasks(ctx context.Context, input ListTasksInput) ([]TaskReco
 		if updatedUnix > 0 {
 			record.UpdatedAt = time.Unix(updatedUnix, 0).UTC()
 		}
+		if deadLetteredUnix > 0 {
+			record.DeadLetteredAt = time.Unix(deadLetteredUnix, 0).UTC()
+		}
+		if runAtUnix > 0 {
+			record.RunAt = time.Unix(runAtUnix, 0).UTC()
+		}
+		if progressUnix > 0 {
+			record.ProgressUpdatedAt = time.Unix(progressUnix, 0).UTC()
+		}
 		record.CreatedAt = parseSQLiteDateTime(createdAtText)
 		results = append(results, record)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	rows.Close()
+	if err := s.attachTaskDependencies(ctx, results); err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
+// DeleteTask moves a finished task to the trash. Queued and running tasks
+// are refused so a worker never loses the row it is executing. A non-zero
+// expectedRevision refuses the delete if the task changed after it was read.
+func (s *Store) DeleteTask(ctx context.Context, id string, expectedRevision int) error {
+	record, err := s.LookupTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !revisionMatches(expectedRevision, record.Revision) {
+		return ErrRevisionConflict
+	}
+	switch strings.ToLower(strings.TrimSpace(record.Status)) {
+	case "queued", "running":
+		return ErrTaskActive
+	}
+	now := time.Now().UTC().Unix()
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET deleted_at_unix = ?, updated_at_unix = ?, revision = revision + 1 WHERE id = ? AND revision = ? AND deleted_at_unix IS NULL`,
+		now,
+		now,
+		record.ID,
+		record.Revision,
+	)
+	if err != nil {
+		return fmt.Errorf("delete task: %w", err)
+	}
+	return requireRevisionWrite(result)
+}
+
 type UpdateTaskRoutingInput struct {
 	ID           string
 	RouteClass   string
 	Priority     string
 	DueAt        time.Time
 	AssignedLane string
+	// ExpectedRevision, when set, makes the update fail with
+	// ErrRevisionConflict if the task changed since it was read.
+	ExpectedRevision int
 }
 
 func (s *Store) UpdateTaskRouting(ctx context.Context, input UpdateTaskRoutingInput) (TaskRecord, error) {
@@ -432,6 +599,13 @@ func (s *Store) UpdateTaskRouting(ctx context.Context, input UpdateTaskRoutingIn
 	if taskID == "" {
 		return TaskRecord{}, ErrTaskNotFound
 	}
+	current, err := s.LookupTask(ctx, taskID)
+	if err != nil {
+		return TaskRecord{}, err
+	}
+	if !revisionMatches(input.ExpectedRevision, current.Revision) {
+		return TaskRecord{}, ErrRevisionConflict
+	}
 	routeClass := strings.ToLower(strings.TrimSpace(input.RouteClass))
 	priority := strings.ToLower(strings.TrimSpace(input.Priority))
 	assignedLane := strings.ToLower(strings.TrimSpace(input.AssignedLane))
@@ -447,18 +621,60 @@ func (s *Store) UpdateTaskRouting(ctx context.Context, input UpdateTaskRoutingIn
 		     priority = ?,
 		     due_at_unix = ?,
 		     assigned_lane = ?,
-		     updated_at_unix = ?
-		 WHERE id = ?`,
+		     updated_at_unix = ?,
+		     revision = revision + 1
+		 WHERE id = ? AND revision = ? AND deleted_at_unix IS NULL`,
 		nullIfEmpty(routeClass),
 		nullIfEmpty(priority),
 		nullIfZeroInt64(dueAtUnix),
 		nullIfEmpty(assignedLane),
 		time.Now().UTC().Unix(),
 		taskID,
+		current.Revision,
 	)
 	if err != nil {
 		return TaskRecord{}, fmt.Errorf("update task routing: %w", err)
 	}
+	if err := requireRevisionWrite(result); err != nil {
+		return TaskRecord{}, err
+	}
+	return s.LookupTask(ctx, taskID)
+}
+
+type SetTaskExternalRefInput struct {
+	ID     string
+	System string
+	Key    string
+	URL    string
+}
+
+// SetTaskExternalRef records the issue tracker item a task is mirrored to.
+func (s *Store) SetTaskExternalRef(ctx context.Context, input SetTaskExternalRefInput) (TaskRecord, error) {
+	taskID := strings.TrimSpace(input.ID)
+	if taskID == "" {
+		return TaskRecord{}, ErrTaskNotFound
+	}
+	if err := s.checkRowScope(ctx, "tasks", taskID, ErrTaskNotFound); err != nil {
+		return TaskRecord{}, err
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET external_system = ?,
+		     external_key = ?,
+		     external_url = ?,
+		     updated_at_unix = ?,
+		     revision = revision + 1
+		 WHERE id = ?`,
+		nullIfEmpty(strings.ToLower(strings.TrimSpace(input.System))),
+		nullIfEmpty(strings.TrimSpace(input.Key)),
+		nullIfEmpty(strings.TrimSpace(input.URL)),
+		time.Now().UTC().Unix(),
+		taskID,
+	)
+	if err != nil {
+		return TaskRecord{}, fmt.Errorf("set task external ref: %w", err)
+	}
 	rowsAffected, err := result.RowsAffected()
 	if err == nil && rowsAffected == 0 {
 		return TaskRecord{}, ErrTaskNotFound
@@ -126,6 +126,40 @@ func TestTaskWorkerScopedCompletionPreventsStaleOverwrite(t *testing.T) {
 	}
 }
 
+func TestSetTaskResultDataRoundTripsAndClearsOnRetry(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-data",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Structured",
+		Prompt:      "run",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	data := `[{"tool":"list_issues","kind":"table","data":{"columns":["Issue"],"rows":[["#1"]]}}]`
+	if err := sqlStore.SetTaskResultData(ctx, "task-data", data); err != nil {
+		t.Fatalf("set result data: %v", err)
+	}
+	record, err := sqlStore.LookupTask(ctx, "task-data")
+	if err != nil || record.ResultData != data {
+		t.Fatalf("expected stored result data, got %q (%v)", record.ResultData, err)
+	}
+	if err := sqlStore.MarkTaskRunning(ctx, "task-data", 1, time.Now().UTC()); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	record, _ = sqlStore.LookupTask(ctx, "task-data")
+	if record.ResultData != "" {
+		t.Fatalf("expected result data cleared on retry, got %q", record.ResultData)
+	}
+	if err := sqlStore.SetTaskResultData(ctx, "missing", data); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
 func TestTaskWorkerScopedFailurePreventsStaleOverwrite(t *testing.T) {
 	sqlStore := newTestStore(t)
 	ctx := context.Background()
@@ -273,6 +307,46 @@ func TestTaskRoutingMetadataPersistAndUpdate(t *testing.T) {
 	}
 }
 
+func TestSetTaskExternalRef(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-sync",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Mirror me",
+		Prompt:      "broken login",
+		Status:      "queued",
+		RouteClass:  "issue",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	updated, err := sqlStore.SetTaskExternalRef(ctx, SetTaskExternalRefInput{
+		ID:     "task-sync",
+		System: "Jira",
+		Key:    "OPS-12",
+		URL:    "https://example.atlassian.net/browse/OPS-12",
+	})
+	if err != nil {
+		t.Fatalf("set external ref: %v", err)
+	}
+	if updated.ExternalSystem != "jira" || updated.ExternalKey != "OPS-12" || updated.ExternalURL == "" {
+		t.Fatalf("unexpected external ref: %+v", updated)
+	}
+	listed, err := sqlStore.ListTasks(ctx, ListTasksInput{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatalf("list tasks: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ExternalKey != "OPS-12" {
+		t.Fatalf("expected listed task to carry external key, got %+v", listed)
+	}
+	if _, err := sqlStore.SetTaskExternalRef(ctx, SetTaskExternalRefInput{ID: "missing", Key: "X-1"}); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
 func TestCreateTaskRejectsDuplicateRunKey(t *testing.T) {
 	sqlStore := newTestStore(t)
 	ctx := context.Background()
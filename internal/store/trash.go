@@ -0,0 +1,245 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrashRetention is how long deleted tasks and objectives stay restorable
+// before PurgeTrash removes them for good.
+const TrashRetention = 30 * 24 * time.Hour
+
+type TrashKind string
+
+const (
+	TrashKindTask      TrashKind = "task"
+	TrashKindObjective TrashKind = "objective"
+)
+
+type TrashEntry struct {
+	Kind        TrashKind
+	ID          string
+	WorkspaceID string
+	Title       string
+	Status      string
+	DeletedAt   time.Time
+	PurgeAt     time.Time
+}
+
+type ListTrashInput struct {
+	WorkspaceID string
+	Limit       int
+}
+
+// ListTrash returns deleted tasks and objectives that are still inside the
+// retention window, most recently deleted first.
+func (s *Store) ListTrash(ctx context.Context, input ListTrashInput) ([]TrashEntry, error) {
+	limit := input.Limit
+	if limit < 1 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	workspaceID, err := scopedWorkspaceFilter(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().UTC().Add(-TrashRetention).Unix()
+
+	results := make([]TrashEntry, 0, limit)
+	for _, source := range []struct {
+		kind   TrashKind
+		table  string
+		status string
+	}{
+		{kind: TrashKindTask, table: "tasks", status: "status"},
+		{kind: TrashKindObjective, table: "objectives", status: "CASE WHEN active = 1 THEN 'active' ELSE 'paused' END"},
+	} {
+		whereParts := []string{"deleted_at_unix IS NOT NULL", "deleted_at_unix >= ?"}
+		args := []any{cutoff}
+		if workspaceID != "" {
+			whereParts = append(whereParts, "workspace_id = ?")
+			args = append(args, workspaceID)
+		}
+		args = append(args, limit)
+		rows, err := s.db.QueryContext(
+			ctx,
+			`SELECT id, workspace_id, title, `+source.status+`, deleted_at_unix
+			 FROM `+source.table+`
+			 WHERE `+strings.Join(whereParts, " AND ")+`
+			 ORDER BY deleted_at_unix DESC
+			 LIMIT ?`,
+			args...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("list trashed %ss: %w", source.kind, err)
+		}
+		for rows.Next() {
+			entry := TrashEntry{Kind: source.kind}
+			var deletedAtUnix int64
+			if err := rows.Scan(&entry.ID, &entry.WorkspaceID, &entry.Title, &entry.Status, &deletedAtUnix); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan trashed %s: %w", source.kind, err)
+			}
+			entry.DeletedAt = time.Unix(deletedAtUnix, 0).UTC()
+			entry.PurgeAt = entry.DeletedAt.Add(TrashRetention)
+			results = append(results, entry)
+		}
+		if err := rows.Close(); err != nil {
+			return nil, fmt.Errorf("list trashed %ss: %w", source.kind, err)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].DeletedAt.After(results[j].DeletedAt)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// TrashedWorkspace returns the workspace of a trashed task or objective, so
+// callers can check permissions before restoring it.
+func (s *Store) TrashedWorkspace(ctx context.Context, kind TrashKind, id string) (string, error) {
+	table, notFound := "tasks", ErrTaskNotFound
+	if kind == TrashKindObjective {
+		table, notFound = "objectives", ErrObjectiveNotFound
+	}
+	var workspaceID string
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT workspace_id FROM `+table+` WHERE id = ? AND deleted_at_unix IS NOT NULL`,
+		strings.TrimSpace(id),
+	).Scan(&workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", notFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("lookup trashed %s: %w", kind, err)
+	}
+	if err := checkWorkspaceScope(ctx, workspaceID, notFound); err != nil {
+		return "", err
+	}
+	return workspaceID, nil
+}
+
+// RestoreTask brings a trashed task back into listings unchanged.
+func (s *Store) RestoreTask(ctx context.Context, id string) (TaskRecord, error) {
+	id = strings.TrimSpace(id)
+	if err := s.restoreTrashed(ctx, "tasks", id, ErrTaskNotFound); err != nil {
+		return TaskRecord{}, err
+	}
+	return s.LookupTask(ctx, id)
+}
+
+// RestoreObjective brings a trashed objective back. Schedules resume from the
+// next cron slot rather than firing for the time spent in the trash, and the
+// workspace objective quota applies as it does to a new objective.
+func (s *Store) RestoreObjective(ctx context.Context, id string) (Objective, error) {
+	id = strings.TrimSpace(id)
+	if err := s.checkRowScope(ctx, "objectives", id, ErrObjectiveNotFound); err != nil {
+		return Objective{}, err
+	}
+	var workspaceID string
+	var triggerType string
+	var cronExpr sql.NullString
+	var timezone sql.NullString
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT workspace_id, trigger_type, cron_expr, timezone FROM objectives WHERE id = ? AND deleted_at_unix IS NOT NULL`,
+		id,
+	).Scan(&workspaceID, &triggerType, &cronExpr, &timezone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Objective{}, ErrObjectiveNotFound
+		}
+		return Objective{}, fmt.Errorf("lookup trashed objective: %w", err)
+	}
+	if err := s.checkQuota(ctx, workspaceID, QuotaObjectives); err != nil {
+		return Objective{}, err
+	}
+	if err := s.restoreTrashed(ctx, "objectives", id, ErrObjectiveNotFound); err != nil {
+		return Objective{}, err
+	}
+	if ObjectiveTriggerType(triggerType) == ObjectiveTriggerSchedule {
+		nextRun, err := ComputeScheduleNextRunForTimezone(normalizeCronExpr(cronExpr.String), timezone.String, time.Now().UTC())
+		if err == nil {
+			if _, err := s.db.ExecContext(ctx, `UPDATE objectives SET next_run_unix = ? WHERE id = ?`, nextRun.Unix(), id); err != nil {
+				return Objective{}, fmt.Errorf("reschedule restored objective: %w", err)
+			}
+		}
+	}
+	return s.LookupObjective(ctx, id)
+}
+
+func (s *Store) restoreTrashed(ctx context.Context, table, id string, notFound error) error {
+	if id == "" {
+		return notFound
+	}
+	if err := s.checkRowScope(ctx, table, id, notFound); err != nil {
+		return err
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE `+table+` SET deleted_at_unix = NULL, updated_at_unix = ?, revision = revision + 1 WHERE id = ? AND deleted_at_unix IS NOT NULL`,
+		time.Now().UTC().Unix(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("restore %s: %w", table, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("restore %s rows affected: %w", table, err)
+	}
+	if affected < 1 {
+		return notFound
+	}
+	return nil
+}
+
+// PurgeTrash permanently deletes tasks and objectives that have been in the
+// trash longer than TrashRetention, along with task plans, checkpoints and
+// artifact records.
+func (s *Store) PurgeTrash(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.UTC().Add(-TrashRetention).Unix()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin trash purge: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		`DELETE FROM task_plan_steps WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at_unix < ?)`,
+		`DELETE FROM task_checkpoints WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at_unix < ?)`,
+		`DELETE FROM task_artifacts WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at_unix < ?)`,
+		`DELETE FROM objective_runs WHERE objective_id IN (SELECT id FROM objectives WHERE deleted_at_unix < ?)`,
+		`DELETE FROM objective_states WHERE objective_id IN (SELECT id FROM objectives WHERE deleted_at_unix < ?)`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, cutoff); err != nil {
+			return 0, fmt.Errorf("purge trashed task and objective data: %w", err)
+		}
+	}
+	purged := 0
+	for _, table := range []string{"tasks", "objectives"} {
+		result, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE deleted_at_unix < ?`, cutoff)
+		if err != nil {
+			return 0, fmt.Errorf("purge trashed %s: %w", table, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("purge trashed %s rows affected: %w", table, err)
+		}
+		purged += int(affected)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit trash purge: %w", err)
+	}
+	return purged, nil
+}
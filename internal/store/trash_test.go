@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeletedObjectiveMovesToTrashAndRestores(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	created, err := sqlStore.CreateObjective(ctx, CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Nightly report",
+		Prompt:      "Summarize the day",
+		TriggerType: ObjectiveTriggerSchedule,
+		CronExpr:    "0 2 * * *",
+		NextRunAt:   time.Now().UTC().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	if err := sqlStore.DeleteObjective(ctx, created.ID, 0); err != nil {
+		t.Fatalf("delete objective: %v", err)
+	}
+	if err := sqlStore.DeleteObjective(ctx, created.ID, 0); !errors.Is(err, ErrObjectiveNotFound) {
+		t.Fatalf("expected second delete to report not found, got %v", err)
+	}
+	due, err := sqlStore.ListDueObjectives(ctx, time.Now().UTC(), 10)
+	if err != nil {
+		t.Fatalf("list due objectives: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("expected trashed objective to stop running, got %d due", len(due))
+	}
+	listed, err := sqlStore.ListObjectives(ctx, ListObjectivesInput{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatalf("list objectives: %v", err)
+	}
+	if len(listed) != 0 {
+		t.Fatalf("expected trashed objective to be hidden, got %d", len(listed))
+	}
+
+	trash, err := sqlStore.ListTrash(ctx, ListTrashInput{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatalf("list trash: %v", err)
+	}
+	if len(trash) != 1 || trash[0].Kind != TrashKindObjective || trash[0].ID != created.ID {
+		t.Fatalf("unexpected trash entries: %+v", trash)
+	}
+	if got := trash[0].PurgeAt.Sub(trash[0].DeletedAt); got != TrashRetention {
+		t.Fatalf("expected purge after %s, got %s", TrashRetention, got)
+	}
+
+	if workspaceID, err := sqlStore.TrashedWorkspace(ctx, TrashKindObjective, created.ID); err != nil || workspaceID != "ws-1" {
+		t.Fatalf("expected the trashed objective's workspace, got %q (%v)", workspaceID, err)
+	}
+	if _, err := sqlStore.TrashedWorkspace(WithWorkspaceScope(ctx, "ws-2"), TrashKindObjective, created.ID); !errors.Is(err, ErrObjectiveNotFound) {
+		t.Fatalf("expected another workspace not to see the trashed objective, got %v", err)
+	}
+
+	restored, err := sqlStore.RestoreObjective(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("restore objective: %v", err)
+	}
+	if _, err := sqlStore.TrashedWorkspace(ctx, TrashKindObjective, created.ID); !errors.Is(err, ErrObjectiveNotFound) {
+		t.Fatalf("expected a restored objective to leave the trash, got %v", err)
+	}
+	if !restored.NextRunAt.After(time.Now().UTC()) {
+		t.Fatalf("expected restored schedule to resume in the future, got %s", restored.NextRunAt)
+	}
+	if _, err := sqlStore.RestoreObjective(ctx, created.ID); !errors.Is(err, ErrObjectiveNotFound) {
+		t.Fatalf("expected restoring a live objective to fail, got %v", err)
+	}
+}
+
+func TestDeleteTaskRefusesActiveTasks(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	for _, id := range []string{"task-queued", "task-done"} {
+		if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+			ID:          id,
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Kind:        "general",
+			Title:       id,
+			Prompt:      "do work",
+			Status:      "queued",
+		}); err != nil {
+			t.Fatalf("create task %s: %v", id, err)
+		}
+	}
+	if err := sqlStore.MarkTaskFailed(ctx, "task-done", time.Now().UTC(), "boom"); err != nil {
+		t.Fatalf("mark task failed: %v", err)
+	}
+
+	if err := sqlStore.DeleteTask(ctx, "task-queued", 0); !errors.Is(err, ErrTaskActive) {
+		t.Fatalf("expected queued task delete to be refused, got %v", err)
+	}
+	if err := sqlStore.DeleteTask(ctx, "task-done", 0); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	if _, err := sqlStore.LookupTask(ctx, "task-done"); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected trashed task to be hidden, got %v", err)
+	}
+	tasks, err := sqlStore.ListTasks(ctx, ListTasksInput{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatalf("list tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "task-queued" {
+		t.Fatalf("unexpected tasks after delete: %+v", tasks)
+	}
+
+	restored, err := sqlStore.RestoreTask(ctx, "task-done")
+	if err != nil {
+		t.Fatalf("restore task: %v", err)
+	}
+	if restored.Status != "failed" || restored.ErrorMessage != "boom" {
+		t.Fatalf("expected task to restore unchanged, got %+v", restored)
+	}
+}
+
+func TestPurgeTrashRemovesExpiredEntries(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+	if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+		ID:          "task-old",
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Kind:        "general",
+		Title:       "Old task",
+		Prompt:      "do work",
+		Status:      "queued",
+	}); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := sqlStore.SetTaskPlan(ctx, "task-old", []string{"step one"}); err != nil {
+		t.Fatalf("set task plan: %v", err)
+	}
+	if err := sqlStore.MarkTaskCompleted(ctx, "task-old", time.Now().UTC(), "done", ""); err != nil {
+		t.Fatalf("mark task completed: %v", err)
+	}
+	if err := sqlStore.DeleteTask(ctx, "task-old", 0); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	created, err := sqlStore.CreateObjective(ctx, CreateObjectiveInput{
+		WorkspaceID: "ws-1",
+		ContextID:   "ctx-1",
+		Title:       "Recent",
+		Prompt:      "watch",
+		TriggerType: ObjectiveTriggerEvent,
+		EventKey:    "markdown.updated",
+	})
+	if err != nil {
+		t.Fatalf("create objective: %v", err)
+	}
+	if err := sqlStore.DeleteObjective(ctx, created.ID, 0); err != nil {
+		t.Fatalf("delete objective: %v", err)
+	}
+	expired := time.Now().UTC().Add(-TrashRetention - time.Hour).Unix()
+	if _, err := sqlStore.db.ExecContext(ctx, `UPDATE tasks SET deleted_at_unix = ? WHERE id = ?`, expired, "task-old"); err != nil {
+		t.Fatalf("backdate task deletion: %v", err)
+	}
+
+	trash, err := sqlStore.ListTrash(ctx, ListTrashInput{})
+	if err != nil {
+		t.Fatalf("list trash: %v", err)
+	}
+	if len(trash) != 1 || trash[0].ID != created.ID {
+		t.Fatalf("expected only the recent objective in trash, got %+v", trash)
+	}
+
+	purged, err := sqlStore.PurgeTrash(ctx, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("purge trash: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("expected one purged row, got %d", purged)
+	}
+	if _, err := sqlStore.RestoreTask(ctx, "task-old"); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected purged task to be gone, got %v", err)
+	}
+	steps, err := sqlStore.ListTaskPlan(ctx, "task-old")
+	if err != nil {
+		t.Fatalf("list task plan: %v", err)
+	}
+	if len(steps) != 0 {
+		t.Fatalf("expected purged task plan to be removed, got %+v", steps)
+	}
+	if _, err := sqlStore.RestoreObjective(ctx, created.ID); err != nil {
+		t.Fatalf("expected recent objective to stay restorable: %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+
+// WebhookSubscription sends a workspace's events to one URL. An empty
+// EventTypes list subscribes to every type. The Last* fields describe the
+// most recent delivery.
+type WebhookSubscription struct {
+	ID                  string
+	WorkspaceID         string
+	URL                 string
+	Secret              string
+	EventTypes          []string
+	Enabled             bool
+	LastDeliveryAt      time.Time
+	LastStatus          string
+	LastError           string
+	ConsecutiveFailures int
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Accepts reports whether the subscription wants events of eventType.
+func (w WebhookSubscription) Accepts(eventType string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, candidate := range w.EventTypes {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveWebhookSubscriptionInput creates a subscription when ID is empty and
+// replaces one otherwise. An empty Secret generates one on create and keeps
+// the stored one on update.
+type SaveWebhookSubscriptionInput struct {
+	ID          string
+	WorkspaceID string
+	URL         string
+	Secret      string
+	EventTypes  []string
+	Enabled     bool
+}
+
+const webhookSubscriptionColumns = `id, workspace_id, url, secret, event_types, enabled, last_delivery_at_unix, last_status, last_error, consecutive_failures, created_at_unix, updated_at_unix`
+
+func (s *Store) SaveWebhookSubscription(ctx context.Context, input SaveWebhookSubscriptionInput) (WebhookSubscription, error) {
+	workspaceID := strings.TrimSpace(input.WorkspaceID)
+	target := strings.TrimSpace(input.URL)
+	if workspaceID == "" {
+		return WebhookSubscription{}, fmt.Errorf("workspace id is required")
+	}
+	parsed, err := url.Parse(target)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return WebhookSubscription{}, fmt.Errorf("webhook url must be an http(s) URL")
+	}
+	if err := checkWorkspaceScope(ctx, workspaceID, nil); err != nil {
+		return WebhookSubscription{}, err
+	}
+	eventTypes := normalizeWebhookEventTypes(input.EventTypes)
+	secret := strings.TrimSpace(input.Secret)
+	now := time.Now().UTC().Unix()
+
+	id := strings.TrimSpace(input.ID)
+	if id == "" {
+		if secret == "" {
+			if secret, err = newWebhookSecret(); err != nil {
+				return WebhookSubscription{}, err
+			}
+		}
+		id = "whk_" + uuid.NewString()
+		if _, err := s.db.ExecContext(
+			ctx,
+			`INSERT INTO webhook_subscriptions (id, workspace_id, url, secret, event_types, enabled, created_at_unix, updated_at_unix)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, workspaceID, target, secret, strings.Join(eventTypes, ","), boolToInt(input.Enabled), now, now,
+		); err != nil {
+			return WebhookSubscription{}, fmt.Errorf("insert webhook subscription: %w", err)
+		}
+		return s.LookupWebhookSubscription(ctx, id)
+	}
+
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE webhook_subscriptions
+		 SET url = ?, secret = COALESCE(NULLIF(?, ''), secret), event_types = ?, enabled = ?, updated_at_unix = ?
+		 WHERE id = ? AND workspace_id = ?`,
+		target, secret, strings.Join(eventTypes, ","), boolToInt(input.Enabled), now, id, workspaceID,
+	)
+	if err != nil {
+		return WebhookSubscription{}, fmt.Errorf("update webhook subscription: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return WebhookSubscription{}, ErrWebhookSubscriptionNotFound
+	}
+	return s.LookupWebhookSubscription(ctx, id)
+}
+
+func (s *Store) LookupWebhookSubscription(ctx context.Context, id string) (WebhookSubscription, error) {
+	subscription, err := scanWebhookSubscription(s.db.QueryRowContext(
+		ctx,
+		`SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE id = ?`,
+		strings.TrimSpace(id),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return WebhookSubscription{}, ErrWebhookSubscriptionNotFound
+	}
+	if err != nil {
+		return WebhookSubscription{}, fmt.Errorf("lookup webhook subscription: %w", err)
+	}
+	if err := checkWorkspaceScope(ctx, subscription.WorkspaceID, ErrWebhookSubscriptionNotFound); err != nil {
+		return WebhookSubscription{}, err
+	}
+	return subscription, nil
+}
+
+// ListWebhookSubscriptions lists a workspace's subscriptions, oldest first.
+func (s *Store) ListWebhookSubscriptions(ctx context.Context, workspaceID string) ([]WebhookSubscription, error) {
+	workspaceID, err := scopedWorkspaceFilter(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID == "" {
+		return nil, fmt.Errorf("workspace id is required")
+	}
+	return s.queryWebhookSubscriptions(ctx, `WHERE workspace_id = ? ORDER BY created_at_unix ASC, id ASC`, workspaceID)
+}
+
+// ListWebhookSubscriptionsForEvent lists the enabled subscriptions of a
+// workspace that want eventType.
+func (s *Store) ListWebhookSubscriptionsForEvent(ctx context.Context, workspaceID, eventType string) ([]WebhookSubscription, error) {
+	subscriptions, err := s.queryWebhookSubscriptions(ctx, `WHERE workspace_id = ? AND enabled = 1 ORDER BY created_at_unix ASC, id ASC`, strings.TrimSpace(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+	matching := subscriptions[:0]
+	for _, subscription := range subscriptions {
+		if subscription.Accepts(eventType) {
+			matching = append(matching, subscription)
+		}
+	}
+	return matching, nil
+}
+
+func (s *Store) DeleteWebhookSubscription(ctx context.Context, id string) (WebhookSubscription, error) {
+	subscription, err := s.LookupWebhookSubscription(ctx, id)
+	if err != nil {
+		return WebhookSubscription{}, err
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ?`, subscription.ID); err != nil {
+		return WebhookSubscription{}, fmt.Errorf("delete webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// RecordWebhookDelivery notes the outcome of a delivery; an empty
+// deliveryErr means it succeeded.
+func (s *Store) RecordWebhookDelivery(ctx context.Context, id string, deliveredAt time.Time, deliveryErr string) error {
+	deliveryErr = strings.TrimSpace(deliveryErr)
+	query := `UPDATE webhook_subscriptions SET last_delivery_at_unix = ?, last_status = 'delivered', last_error = '', consecutive_failures = 0 WHERE id = ?`
+	args := []any{deliveredAt.UTC().Unix(), strings.TrimSpace(id)}
+	if deliveryErr != "" {
+		query = `UPDATE webhook_subscriptions SET last_delivery_at_unix = ?, last_status = 'failed', last_error = ?, consecutive_failures = consecutive_failures + 1 WHERE id = ?`
+		args = []any{deliveredAt.UTC().Unix(), deliveryErr, strings.TrimSpace(id)}
+	}
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("record webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) queryWebhookSubscriptions(ctx context.Context, where string, args ...any) ([]WebhookSubscription, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+	subscriptions := []WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+type webhookSubscriptionScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhookSubscription(scanner webhookSubscriptionScanner) (WebhookSubscription, error) {
+	var subscription WebhookSubscription
+	var eventTypes string
+	var enabled int
+	var lastDeliveryUnix, createdUnix, updatedUnix int64
+	if err := scanner.Scan(
+		&subscription.ID,
+		&subscription.WorkspaceID,
+		&subscription.URL,
+		&subscription.Secret,
+		&eventTypes,
+		&enabled,
+		&lastDeliveryUnix,
+		&subscription.LastStatus,
+		&subscription.LastError,
+		&subscription.ConsecutiveFailures,
+		&createdUnix,
+		&updatedUnix,
+	); err != nil {
+		return WebhookSubscription{}, err
+	}
+	subscription.EventTypes = normalizeWebhookEventTypes(strings.Split(eventTypes, ","))
+	subscription.Enabled = enabled == 1
+	if lastDeliveryUnix > 0 {
+		subscription.LastDeliveryAt = time.Unix(lastDeliveryUnix, 0).UTC()
+	}
+	subscription.CreatedAt = time.Unix(createdUnix, 0).UTC()
+	subscription.UpdatedAt = time.Unix(updatedUnix, 0).UTC()
+	return subscription, nil
+}
+
+func normalizeWebhookEventTypes(values []string) []string {
+	seen := map[string]bool{}
+	types := []string{}
+	for _, value := range values {
+		eventType := strings.ToLower(strings.TrimSpace(value))
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
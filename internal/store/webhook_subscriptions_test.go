@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookSubscriptionLifecycle(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := sqlStore.SaveWebhookSubscription(ctx, SaveWebhookSubscriptionInput{WorkspaceID: "ws-1", URL: "ftp://example.com"}); err == nil {
+		t.Fatal("expected non-http url to be rejected")
+	}
+	created, err := sqlStore.SaveWebhookSubscription(ctx, SaveWebhookSubscriptionInput{
+		WorkspaceID: "ws-1",
+		URL:         "https://hooks.example.com/a",
+		EventTypes:  []string{"task.created", " Approval.Pending ", "task.created"},
+		Enabled:     true,
+	})
+	if err != nil {
+		t.Fatalf("create subscription: %v", err)
+	}
+	if !strings.HasPrefix(created.ID, "whk_") || !strings.HasPrefix(created.Secret, "whsec_") {
+		t.Fatalf("expected generated id and secret, got %+v", created)
+	}
+	if strings.Join(created.EventTypes, ",") != "approval.pending,task.created" {
+		t.Fatalf("unexpected event types %v", created.EventTypes)
+	}
+	if _, err := sqlStore.SaveWebhookSubscription(ctx, SaveWebhookSubscriptionInput{WorkspaceID: "ws-1", URL: "http://hooks.example.com/all", Enabled: true}); err != nil {
+		t.Fatalf("create catch-all subscription: %v", err)
+	}
+	if _, err := sqlStore.SaveWebhookSubscription(ctx, SaveWebhookSubscriptionInput{WorkspaceID: "ws-2", URL: "https://other.example.com", Enabled: true}); err != nil {
+		t.Fatalf("create other workspace subscription: %v", err)
+	}
+
+	matching, err := sqlStore.ListWebhookSubscriptionsForEvent(ctx, "ws-1", "objective.fired")
+	if err != nil || len(matching) != 1 || matching[0].URL != "http://hooks.example.com/all" {
+		t.Fatalf("expected only the catch-all subscription, got %+v (%v)", matching, err)
+	}
+	matching, err = sqlStore.ListWebhookSubscriptionsForEvent(ctx, "ws-1", "task.created")
+	if err != nil || len(matching) != 2 {
+		t.Fatalf("expected both subscriptions, got %+v (%v)", matching, err)
+	}
+
+	updated, err := sqlStore.SaveWebhookSubscription(ctx, SaveWebhookSubscriptionInput{
+		ID:          created.ID,
+		WorkspaceID: "ws-1",
+		URL:         "https://hooks.example.com/b",
+		EventTypes:  created.EventTypes,
+		Enabled:     false,
+	})
+	if err != nil {
+		t.Fatalf("update subscription: %v", err)
+	}
+	if updated.Secret != created.Secret || updated.URL != "https://hooks.example.com/b" || updated.Enabled {
+		t.Fatalf("expected secret kept and fields updated, got %+v", updated)
+	}
+	if _, err := sqlStore.SaveWebhookSubscription(ctx, SaveWebhookSubscriptionInput{ID: created.ID, WorkspaceID: "ws-2", URL: "https://x.example.com"}); !errors.Is(err, ErrWebhookSubscriptionNotFound) {
+		t.Fatalf("expected update from another workspace to miss, got %v", err)
+	}
+	matching, err = sqlStore.ListWebhookSubscriptionsForEvent(ctx, "ws-1", "task.created")
+	if err != nil || len(matching) != 1 {
+		t.Fatalf("expected disabled subscription skipped, got %+v (%v)", matching, err)
+	}
+
+	at := time.Unix(1_700_000_000, 0).UTC()
+	for i := 0; i < 2; i++ {
+		if err := sqlStore.RecordWebhookDelivery(ctx, created.ID, at, "webhook returned status 500"); err != nil {
+			t.Fatalf("record failure: %v", err)
+		}
+	}
+	failed, err := sqlStore.LookupWebhookSubscription(ctx, created.ID)
+	if err != nil || failed.LastStatus != "failed" || failed.ConsecutiveFailures != 2 || !failed.LastDeliveryAt.Equal(at) {
+		t.Fatalf("unexpected failure state %+v (%v)", failed, err)
+	}
+	if err := sqlStore.RecordWebhookDelivery(ctx, created.ID, at, ""); err != nil {
+		t.Fatalf("record success: %v", err)
+	}
+	delivered, err := sqlStore.LookupWebhookSubscription(ctx, created.ID)
+	if err != nil || delivered.LastStatus != "delivered" || delivered.ConsecutiveFailures != 0 || delivered.LastError != "" {
+		t.Fatalf("unexpected success state %+v (%v)", delivered, err)
+	}
+
+	if _, err := sqlStore.DeleteWebhookSubscription(ctx, created.ID); err != nil {
+		t.Fatalf("delete subscription: %v", err)
+	}
+	if _, err := sqlStore.LookupWebhookSubscription(ctx, created.ID); !errors.Is(err, ErrWebhookSubscriptionNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	listed, err := sqlStore.ListWebhookSubscriptions(ctx, "ws-1")
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("expected one subscription left, got %+v (%v)", listed, err)
+	}
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// WorkspaceSummary is a workspace with counts of its tasks and objectives.
+// Workspaces only known from their tasks or objectives have no slug or name.
+type WorkspaceSummary struct {
+	ID                   string
+	Slug                 string
+	Name                 string
+	Kind                 string
+	TaskCount            int
+	OpenTaskCount        int
+	FailedTaskCount      int
+	ObjectiveCount       int
+	ActiveObjectiveCount int
+}
+
+// ListWorkspaces returns every workspace the store knows, by name, with task
+// and objective counts. Trashed tasks and objectives are not counted. A
+// scoped caller only sees its own workspace.
+func (s *Store) ListWorkspaces(ctx context.Context) ([]WorkspaceSummary, error) {
+	query := `WITH ids AS (
+			SELECT id AS workspace_id FROM workspaces
+			UNION SELECT workspace_id FROM tasks WHERE deleted_at_unix IS NULL
+			UNION SELECT workspace_id FROM objectives WHERE deleted_at_unix IS NULL
+		),
+		task_counts AS (
+			SELECT workspace_id,
+				COUNT(*) AS total,
+				SUM(CASE WHEN status IN ('queued', 'running') THEN 1 ELSE 0 END) AS open,
+				SUM(CASE WHEN status = 'failed' THEN 1 ELSE 0 END) AS failed
+			FROM tasks WHERE deleted_at_unix IS NULL GROUP BY workspace_id
+		),
+		objective_counts AS (
+			SELECT workspace_id, COUNT(*) AS total, SUM(active) AS active
+			FROM objectives WHERE deleted_at_unix IS NULL GROUP BY workspace_id
+		)
+		SELECT ids.workspace_id, COALESCE(w.slug, ''), COALESCE(w.name, ''), COALESCE(w.kind, ''),
+			COALESCE(t.total, 0), COALESCE(t.open, 0), COALESCE(t.failed, 0),
+			COALESCE(o.total, 0), COALESCE(o.active, 0)
+		FROM ids
+		LEFT JOIN workspaces w ON w.id = ids.workspace_id
+		LEFT JOIN task_counts t ON t.workspace_id = ids.workspace_id
+		LEFT JOIN objective_counts o ON o.workspace_id = ids.workspace_id
+		WHERE ids.workspace_id <> ''`
+	args := []any{}
+	if scope, ok := WorkspaceScopeFromContext(ctx); ok {
+		query += ` AND ids.workspace_id = ?`
+		args = append(args, scope)
+	}
+	query += ` ORDER BY LOWER(COALESCE(NULLIF(w.name, ''), ids.workspace_id)), ids.workspace_id`
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	defer rows.Close()
+	workspaces := []WorkspaceSummary{}
+	for rows.Next() {
+		var record WorkspaceSummary
+		if err := rows.Scan(
+			&record.ID,
+			&record.Slug,
+			&record.Name,
+			&record.Kind,
+			&record.TaskCount,
+			&record.OpenTaskCount,
+			&record.FailedTaskCount,
+			&record.ObjectiveCount,
+			&record.ActiveObjectiveCount,
+		); err != nil {
+			return nil, fmt.Errorf("scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate workspaces: %w", err)
+	}
+	return workspaces, nil
+}
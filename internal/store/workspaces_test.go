@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestListWorkspacesCountsTasksAndObjectives(t *testing.T) {
+	sqlStore := newTestStore(t)
+	ctx := context.Background()
+
+	channel, err := sqlStore.EnsureContextForExternalChannel(ctx, "discord", "chan-1", "ops")
+	if err != nil {
+		t.Fatalf("ensure context: %v", err)
+	}
+	for index, status := range []string{"queued", "failed", "succeeded"} {
+		if err := sqlStore.CreateTask(ctx, CreateTaskInput{
+			ID:          "task-" + status,
+			WorkspaceID: "ws-1",
+			ContextID:   "ctx-1",
+			Kind:        "general",
+			Title:       "Task " + string(rune('A'+index)),
+			Prompt:      "do it",
+			Status:      status,
+		}); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+	paused := false
+	for _, active := range []*bool{nil, &paused} {
+		if _, err := sqlStore.CreateObjective(ctx, CreateObjectiveInput{
+			WorkspaceID: channel.WorkspaceID,
+			ContextID:   channel.ID,
+			Title:       "Nightly report",
+			Prompt:      "Summarize the day",
+			TriggerType: ObjectiveTriggerSchedule,
+			CronExpr:    "0 2 * * *",
+			NextRunAt:   time.Now().UTC().Add(time.Hour),
+			Active:      active,
+		}); err != nil {
+			t.Fatalf("create objective: %v", err)
+		}
+	}
+
+	workspaces, err := sqlStore.ListWorkspaces(ctx)
+	if err != nil {
+		t.Fatalf("list workspaces: %v", err)
+	}
+	if len(workspaces) != 2 {
+		t.Fatalf("expected the named and the task-only workspace, got %+v", workspaces)
+	}
+	named, taskOnly := workspaces[0], workspaces[1]
+	if named.ID != channel.WorkspaceID || named.Name != "Discord: ops" || named.ObjectiveCount != 2 || named.ActiveObjectiveCount != 1 || named.TaskCount != 0 {
+		t.Fatalf("unexpected named workspace %+v", named)
+	}
+	if taskOnly.ID != "ws-1" || taskOnly.Name != "" || taskOnly.TaskCount != 3 || taskOnly.OpenTaskCount != 1 || taskOnly.FailedTaskCount != 1 {
+		t.Fatalf("unexpected task-only workspace %+v", taskOnly)
+	}
+
+	scoped, err := sqlStore.ListWorkspaces(WithWorkspaceScope(ctx, "ws-1"))
+	if err != nil || len(scoped) != 1 || scoped[0].ID != "ws-1" {
+		t.Fatalf("expected a scoped caller to see only its workspace, got %+v (%v)", scoped, err)
+	}
+}
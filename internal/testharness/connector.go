@@ -0,0 +1,100 @@
+package testharness
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// ConnectorName is the connector name harness messages arrive on. It
+// impersonates Telegram, a connector pairing accepts; the real Telegram
+// connector stays off because the harness clears its token.
+const ConnectorName = "telegram"
+
+// FakeConnector stands in for Telegram/Discord: Send hands a message to the
+// gateway like a chat connector would, and Publish records what the runtime
+// posts back on its own (task results, notices, digests).
+type FakeConnector struct {
+	gateway   *gateway.Service
+	store     *store.Store
+	responder llm.Responder
+
+	mu        sync.Mutex
+	published map[string][]string
+}
+
+func newFakeConnector(gatewayService *gateway.Service, sqlStore *store.Store, responder llm.Responder) *FakeConnector {
+	return &FakeConnector{
+		gateway:   gatewayService,
+		store:     sqlStore,
+		responder: responder,
+		published: map[string][]string{},
+	}
+}
+
+func (c *FakeConnector) Name() string {
+	return ConnectorName
+}
+
+func (c *FakeConnector) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (c *FakeConnector) Publish(ctx context.Context, externalID, text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.published[externalID] = append(c.published[externalID], text)
+	return nil
+}
+
+// Published returns what the runtime posted to externalID so far.
+func (c *FakeConnector) Published(externalID string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.published[externalID]...)
+}
+
+// Send delivers one inbound message. When the gateway leaves it unhandled
+// the grounded responder answers, as the chat connectors do for DMs.
+func (c *FakeConnector) Send(ctx context.Context, input gateway.MessageInput) (gateway.MessageOutput, error) {
+	if c.gateway == nil {
+		return gateway.MessageOutput{}, errors.New("harness connector is not wired to a gateway")
+	}
+	input.Connector = ConnectorName
+	contextRecord, err := c.store.EnsureContextForExternalChannel(ctx, ConnectorName, input.ExternalID, input.DisplayName)
+	if err != nil {
+		return gateway.MessageOutput{}, err
+	}
+	output, err := c.gateway.HandleMessage(ctx, input)
+	if err != nil || output.Suppressed {
+		return output, err
+	}
+	if output.Handled && strings.TrimSpace(output.Reply) != "" {
+		return output, nil
+	}
+	if c.responder == nil {
+		return output, nil
+	}
+	reply, err := c.responder.Reply(ctx, llm.MessageInput{
+		Connector:   ConnectorName,
+		WorkspaceID: contextRecord.WorkspaceID,
+		ContextID:   contextRecord.ID,
+		ExternalID:  input.ExternalID,
+		DisplayName: input.DisplayName,
+		FromUserID:  input.FromUserID,
+		Text:        input.Text,
+		IsDM:        true,
+		Images:      input.Images,
+	})
+	if err != nil {
+		return output, err
+	}
+	output.Reply = strings.TrimSpace(reply)
+	return output, nil
+}
@@ -0,0 +1,263 @@
+// Package testharness runs the full runtime in-process for feature tests:
+// real store, gateway, task engine, executor and sandbox, with a scripted
+// model (FakeLLM) and a fake chat connector in place of Telegram/Discord.
+//
+//	h := testharness.New(t)
+//	h.MakeAdmin("alice")
+//	chat := h.Conversation("ops-room").As("alice")
+//	reply := chat.Send("/task write the weekly report")
+//
+// Nothing outside the temp directory of the test is touched and no network
+// is needed.
+package testharness
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/app"
+	"github.com/dwizi/agent-runtime/internal/config"
+	"github.com/dwizi/agent-runtime/internal/connectors"
+	"github.com/dwizi/agent-runtime/internal/gateway"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+// DefaultSandboxCommands are the commands the harness sandbox allows unless
+// WithConfig changes SandboxAllowedCommandsCSV.
+const DefaultSandboxCommands = "echo,cat,ls,head,tail,wc,grep"
+
+// Option adjusts the harness before the runtime is built.
+type Option func(*settings)
+
+type settings struct {
+	llm       *FakeLLM
+	configure []func(*config.Config)
+	logger    *slog.Logger
+}
+
+// WithLLM uses llm instead of a fresh FakeLLM, e.g. one already scripted.
+func WithLLM(llm *FakeLLM) Option {
+	return func(s *settings) {
+		s.llm = llm
+	}
+}
+
+// WithConfig changes the runtime config after the harness defaults apply.
+func WithConfig(configure func(cfg *config.Config)) Option {
+	return func(s *settings) {
+		s.configure = append(s.configure, configure)
+	}
+}
+
+// WithLogger sends runtime logs to logger instead of discarding them.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *settings) {
+		s.logger = logger
+	}
+}
+
+// Harness is a running runtime plus handles to its fakes.
+type Harness struct {
+	t         testing.TB
+	Config    config.Config
+	Runtime   *app.Runtime
+	LLM       *FakeLLM
+	Connector *FakeConnector
+}
+
+// New builds and starts a runtime rooted in t.TempDir. It is stopped and
+// closed when the test ends.
+func New(t testing.TB, opts ...Option) *Harness {
+	t.Helper()
+	s := settings{}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if s.llm == nil {
+		s.llm = NewFakeLLM()
+	}
+	if s.logger == nil {
+		s.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	cfg := harnessConfig(t.TempDir())
+	for _, configure := range s.configure {
+		configure(&cfg)
+	}
+
+	h := &Harness{t: t, Config: cfg, LLM: s.llm}
+	runtime, err := app.New(cfg, s.logger,
+		app.WithResponder(s.llm),
+		app.WithConnector(func(deps app.ConnectorDeps) connectors.Connector {
+			h.Connector = newFakeConnector(deps.Gateway, deps.Store, deps.Responder)
+			return h.Connector
+		}),
+	)
+	if err != nil {
+		t.Fatalf("testharness: build runtime: %v", err)
+	}
+	h.Runtime = runtime
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := runtime.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			t.Logf("testharness: runtime stopped: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+		_ = runtime.Close()
+	})
+	return h
+}
+
+func harnessConfig(root string) config.Config {
+	cfg := config.FromEnv()
+	cfg.HTTPAddr = "127.0.0.1:0"
+	cfg.DataDir = root
+	cfg.DBPath = filepath.Join(root, "meta.sqlite")
+	cfg.WorkspaceRoot = filepath.Join(root, "workspaces")
+	cfg.StatusPageDir = filepath.Join(root, "status")
+	cfg.QMDSharedModelsDir = filepath.Join(root, "qmd-models")
+	cfg.QMDSidecarURL = ""
+	cfg.ExtPluginsConfigPath = filepath.Join(root, "plugins.json")
+	cfg.ExtPluginCacheDir = filepath.Join(root, "ext-plugin-cache")
+	cfg.MCPConfigPath = filepath.Join(root, "mcp.json")
+	cfg.ToolPluginsDir = filepath.Join(root, "tool-plugins")
+	cfg.SkillsGlobalRoot = filepath.Join(root, "skills")
+	cfg.SoulGlobalFile = filepath.Join(root, "context", "SOUL.md")
+	cfg.SystemPromptGlobalFile = filepath.Join(root, "context", "SYSTEM_PROMPT.md")
+	cfg.ReasoningPromptFile = filepath.Join(root, "context", "REASONING.md")
+	cfg.TelegramToken = ""
+	cfg.DiscordToken = ""
+	cfg.IMAPHost = ""
+	cfg.CodexPublishURL = ""
+	cfg.LLMEnabled = true
+	cfg.SandboxEnabled = true
+	cfg.SandboxRunnerCommand = ""
+	cfg.SandboxAllowedCommandsCSV = DefaultSandboxCommands
+	return cfg
+}
+
+// Store is the runtime's database, for seeding and asserting state.
+func (h *Harness) Store() *store.Store {
+	return h.Runtime.Store()
+}
+
+// MakeAdmin pairs connectorUserID on the harness connector as an admin.
+func (h *Harness) MakeAdmin(connectorUserID string) {
+	h.t.Helper()
+	h.pair(connectorUserID, "admin")
+}
+
+// MakeMember pairs connectorUserID on the harness connector as a member.
+func (h *Harness) MakeMember(connectorUserID string) {
+	h.t.Helper()
+	h.pair(connectorUserID, "member")
+}
+
+func (h *Harness) pair(connectorUserID, role string) {
+	h.t.Helper()
+	ctx := context.Background()
+	pairing, err := h.Store().CreatePairingRequest(ctx, store.CreatePairingRequestInput{
+		Connector:       ConnectorName,
+		ConnectorUserID: connectorUserID,
+		DisplayName:     connectorUserID,
+		ExpiresAt:       time.Now().UTC().Add(time.Hour),
+	})
+	if err != nil {
+		h.t.Fatalf("testharness: pairing request for %s: %v", connectorUserID, err)
+	}
+	if _, err := h.Store().ApprovePairing(ctx, store.ApprovePairingInput{
+		Token:          pairing.Token,
+		ApproverUserID: "testharness",
+		Role:           role,
+	}); err != nil {
+		h.t.Fatalf("testharness: approve pairing for %s: %v", connectorUserID, err)
+	}
+}
+
+// Conversation is a chat with externalID as the channel id.
+func (h *Harness) Conversation(externalID string) *Conversation {
+	return &Conversation{h: h, externalID: externalID, userID: "user"}
+}
+
+// Published returns what the runtime posted to externalID on its own.
+func (h *Harness) Published(externalID string) []string {
+	return h.Connector.Published(externalID)
+}
+
+// Eventually fails the test when condition is not true within timeout.
+func (h *Harness) Eventually(timeout time.Duration, condition func() bool) {
+	h.t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		if condition() {
+			return
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("testharness: condition not met within %s", timeout)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// Do sends req to the HTTP API in-process and returns the recorded response.
+func (h *Harness) Do(req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	h.Runtime.Handler().ServeHTTP(recorder, req)
+	return recorder
+}
+
+// Conversation scripts one channel. As switches the sender; Send delivers a
+// message and returns the reply.
+type Conversation struct {
+	h          *Harness
+	externalID string
+	userID     string
+}
+
+// As returns the conversation with userID as the sender.
+func (c *Conversation) As(userID string) *Conversation {
+	return &Conversation{h: c.h, externalID: c.externalID, userID: userID}
+}
+
+// Send delivers text and returns the reply, failing the test on error.
+func (c *Conversation) Send(text string) string {
+	c.h.t.Helper()
+	output := c.SendMessage(text)
+	return strings.TrimSpace(output.Reply)
+}
+
+// SendMessage is Send returning the full gateway output.
+func (c *Conversation) SendMessage(text string) gateway.MessageOutput {
+	c.h.t.Helper()
+	output, err := c.h.Connector.Send(context.Background(), gateway.MessageInput{
+		ExternalID:  c.externalID,
+		DisplayName: c.externalID,
+		FromUserID:  c.userID,
+		Text:        text,
+	})
+	if err != nil {
+		c.h.t.Fatalf("testharness: send %q: %v", text, err)
+	}
+	return output
+}
+
+// Published returns what the runtime posted to this channel on its own.
+func (c *Conversation) Published() []string {
+	return c.h.Published(c.externalID)
+}
@@ -0,0 +1,61 @@
+package testharness
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+	"github.com/dwizi/agent-runtime/internal/store"
+)
+
+func TestAdminTaskRunsThroughRuntime(t *testing.T) {
+	h := New(t)
+	h.LLM.Handle(func(_ llm.MessageInput) (string, error) {
+		return "Weekly report drafted.", nil
+	})
+	h.MakeAdmin("alice")
+	chat := h.Conversation("ops-room").As("alice")
+
+	reply := chat.Send("/task write the weekly report")
+	if !strings.Contains(reply, "Task queued") {
+		t.Fatalf("expected task queued reply, got %q", reply)
+	}
+
+	var task store.TaskRecord
+	h.Eventually(10*time.Second, func() bool {
+		tasks, err := h.Store().ListTasks(context.Background(), store.ListTasksInput{})
+		if err != nil || len(tasks) == 0 {
+			return false
+		}
+		task = tasks[0]
+		return task.Status == "succeeded" || task.Status == "failed"
+	})
+	if task.Status != "succeeded" {
+		t.Fatalf("expected task to succeed, got %s: %s", task.Status, task.ErrorMessage)
+	}
+	if len(h.LLM.Calls()) == 0 {
+		t.Fatal("expected the task to call the model")
+	}
+}
+
+func TestUnhandledMessageFallsBackToModel(t *testing.T) {
+	h := New(t, WithLLM(NewFakeLLM("Hello from the model.")))
+	h.MakeMember("bob")
+
+	reply := h.Conversation("dm-bob").As("bob").Send("hi there")
+	if reply != "Hello from the model." {
+		t.Fatalf("expected scripted model reply, got %q", reply)
+	}
+}
+
+func TestHTTPAPIIsServedInProcess(t *testing.T) {
+	h := New(t)
+	recorder := h.Do(httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /healthz, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,68 @@
+package testharness
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+)
+
+// DefaultReply is what FakeLLM answers once its script runs out and no
+// handler is set.
+const DefaultReply = "ok"
+
+// FakeLLM is a scripted llm.Responder. Replies queued with Script are
+// returned in order; after that Handler, when set, answers, and otherwise
+// DefaultReply. Every call is recorded.
+type FakeLLM struct {
+	mu      sync.Mutex
+	script  []string
+	handler func(input llm.MessageInput) (string, error)
+	calls   []llm.MessageInput
+}
+
+// NewFakeLLM returns a FakeLLM with replies already queued.
+func NewFakeLLM(replies ...string) *FakeLLM {
+	return &FakeLLM{script: append([]string(nil), replies...)}
+}
+
+// Script queues replies behind the ones not yet used.
+func (f *FakeLLM) Script(replies ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.script = append(f.script, replies...)
+}
+
+// Handle answers calls the script does not cover.
+func (f *FakeLLM) Handle(handler func(input llm.MessageInput) (string, error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handler = handler
+}
+
+// Calls returns the inputs the runtime sent so far.
+func (f *FakeLLM) Calls() []llm.MessageInput {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]llm.MessageInput(nil), f.calls...)
+}
+
+func (f *FakeLLM) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, input)
+	if len(f.script) > 0 {
+		reply := f.script[0]
+		f.script = f.script[1:]
+		f.mu.Unlock()
+		return reply, nil
+	}
+	handler := f.handler
+	f.mu.Unlock()
+	if handler != nil {
+		return handler(input)
+	}
+	return DefaultReply, nil
+}
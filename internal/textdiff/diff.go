@@ -0,0 +1,240 @@
+// Package textdiff produces line-based unified diffs and applies unified
+// patches with strict context matching.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DefaultContextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type edit struct {
+	kind opKind
+	line string
+}
+
+// SplitLines splits text into lines, keeping line terminators so that a
+// missing final newline round-trips.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Unified returns a unified diff from oldText to newText, or "" when equal.
+func Unified(oldName, newName, oldText, newText string, contextLines int) string {
+	if contextLines < 0 {
+		contextLines = DefaultContextLines
+	}
+	oldLines, newLines := SplitLines(oldText), SplitLines(newText)
+	edits := diffLines(oldLines, newLines)
+	hunks := groupHunks(edits, contextLines)
+	if len(hunks) == 0 {
+		return ""
+	}
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", oldName, newName)
+	for _, hunk := range hunks {
+		builder.WriteString(hunk)
+	}
+	return builder.String()
+}
+
+// diffLines computes a shortest edit script with the Myers algorithm after
+// trimming the common prefix and suffix.
+func diffLines(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{kind: opEqual, line: line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{kind: opEqual, line: line})
+	}
+	return edits
+}
+
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	maxD := n + m
+	offset := maxD
+	v := make([]int, 2*maxD+2)
+	trace := make([][]int, 0, 16)
+	for d := 0; d <= maxD; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset, d)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(trace [][]int, a, b []string, offset, d int) []edit {
+	x, y := len(a), len(b)
+	reversed := []edit{}
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, edit{kind: opEqual, line: a[x]})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, edit{kind: opInsert, line: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, edit{kind: opDelete, line: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, edit{kind: opEqual, line: a[x]})
+	}
+	edits := make([]edit, len(reversed))
+	for index, item := range reversed {
+		edits[len(reversed)-1-index] = item
+	}
+	return edits
+}
+
+func groupHunks(edits []edit, contextLines int) []string {
+	hunks := []string{}
+	index := 0
+	oldLine, newLine := 1, 1
+	for index < len(edits) {
+		if edits[index].kind == opEqual {
+			index++
+			oldLine++
+			newLine++
+			continue
+		}
+		// Walk back to include leading context.
+		start := index
+		for back := 0; back < contextLines && start > 0 && edits[start-1].kind == opEqual; back++ {
+			start--
+		}
+		oldStart := oldLine - (index - start)
+		newStart := newLine - (index - start)
+		end := index
+		equalRun := 0
+		for end < len(edits) {
+			if edits[end].kind == opEqual {
+				equalRun++
+				if equalRun > 2*contextLines {
+					break
+				}
+			} else {
+				equalRun = 0
+			}
+			end++
+		}
+		// Trim trailing context beyond contextLines.
+		trailing := 0
+		for trailing < equalRun && end-1-trailing >= 0 && edits[end-1-trailing].kind == opEqual {
+			trailing++
+		}
+		if trailing > contextLines {
+			end -= trailing - contextLines
+		}
+
+		body := strings.Builder{}
+		oldCount, newCount := 0, 0
+		for _, item := range edits[start:end] {
+			prefix := " "
+			switch item.kind {
+			case opEqual:
+				oldCount++
+				newCount++
+			case opDelete:
+				prefix = "-"
+				oldCount++
+			case opInsert:
+				prefix = "+"
+				newCount++
+			}
+			body.WriteString(prefix)
+			body.WriteString(item.line)
+			if !strings.HasSuffix(item.line, "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		hunks = append(hunks, fmt.Sprintf("@@ -%s +%s @@\n%s", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount), body.String()))
+
+		for _, item := range edits[index:end] {
+			switch item.kind {
+			case opEqual:
+				oldLine++
+				newLine++
+			case opDelete:
+				oldLine++
+			case opInsert:
+				newLine++
+			}
+		}
+		index = end
+	}
+	return hunks
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range points at the line before the change.
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,119 @@
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	newText := "one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+	got := Unified("a/notes.md", "b/notes.md", oldText, newText, 1)
+	want := strings.Join([]string{
+		"--- a/notes.md",
+		"+++ b/notes.md",
+		"@@ -1,3 +1,3 @@",
+		" one",
+		"-two",
+		"+2",
+		" three",
+		"@@ -10 +10,2 @@",
+		" ten",
+		"+eleven",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if Unified("a", "b", oldText, oldText, 3) != "" {
+		t.Fatal("expected empty diff for equal input")
+	}
+}
+
+func TestDiffRoundTripsThroughApply(t *testing.T) {
+	cases := []struct{ oldText, newText string }{
+		{"", "hello\n"},
+		{"hello\n", ""},
+		{"a\nb\nc\n", "a\nc\nd\n"},
+		{"no newline", "no newline\nmore"},
+		{strings.Repeat("x\n", 30) + "mid\n" + strings.Repeat("y\n", 30), "top\n" + strings.Repeat("x\n", 30) + "middle\n" + strings.Repeat("y\n", 29)},
+	}
+	for index, tc := range cases {
+		diff := Unified("a/f", "b/f", tc.oldText, tc.newText, 3)
+		patches, err := Parse(diff)
+		if err != nil {
+			t.Fatalf("case %d: parse: %v\n%s", index, err, diff)
+		}
+		got, err := Apply(tc.oldText, patches[0])
+		if err != nil {
+			t.Fatalf("case %d: apply: %v\n%s", index, err, diff)
+		}
+		if got != tc.newText {
+			t.Fatalf("case %d: got %q want %q\n%s", index, got, tc.newText, diff)
+		}
+	}
+}
+
+func TestParseMultiFileAndApplyWithOffset(t *testing.T) {
+	patch := strings.Join([]string{
+		"diff --git a/docs/a.md b/docs/a.md",
+		"index 123..456 100644",
+		"--- a/docs/a.md",
+		"+++ b/docs/a.md",
+		"@@ -2,2 +2,2 @@",
+		" beta",
+		"-gamma",
+		"+GAMMA",
+		"--- /dev/null",
+		"+++ b/docs/new.md",
+		"@@ -0,0 +1 @@",
+		"+created",
+		"",
+	}, "\n")
+	patches, err := Parse(patch)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(patches) != 2 || patches[0].Path() != "docs/a.md" || !patches[1].IsCreate() || patches[1].Path() != "docs/new.md" {
+		t.Fatalf("unexpected patches: %+v", patches)
+	}
+	// Two lines were inserted above the hunk since the patch was written.
+	got, err := Apply("intro\nextra\nalpha\nbeta\ngamma\n", patches[0])
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got != "intro\nextra\nalpha\nbeta\nGAMMA\n" {
+		t.Fatalf("unexpected result %q", got)
+	}
+	if _, err := Apply("alpha\nbeta\ndelta\n", patches[0]); !errors.Is(err, ErrHunkMismatch) {
+		t.Fatalf("expected mismatch, got %v", err)
+	}
+}
+
+func TestParseRejectsMalformedPatches(t *testing.T) {
+	for index, patch := range []string{
+		"just text",
+		"--- a/x\n@@ -1 +1 @@\n-a\n+b\n",
+		"--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n-a\n+b\n",
+		"--- a/x\n+++ b/x\n@@ -1 +1 @@\n*a\n",
+	} {
+		if _, err := Parse(patch); !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("case %d: expected invalid patch, got %v", index, err)
+		}
+	}
+}
+
+func TestUnifiedLargeFileSingleChange(t *testing.T) {
+	builder := strings.Builder{}
+	for index := range 5000 {
+		fmt.Fprintf(&builder, "line %d\n", index)
+	}
+	oldText := builder.String()
+	newText := strings.Replace(oldText, "line 2500\n", "changed\n", 1)
+	diff := Unified("a", "b", oldText, newText, 3)
+	if !strings.Contains(diff, "@@ -2498,7 +2498,7 @@\n") || strings.Count(diff, "\n") != 11 {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+}
@@ -0,0 +1,239 @@
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrHunkMismatch = errors.New("patch does not apply")
+)
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// FilePatch holds the hunks for one file. OldPath is empty when the patch
+// creates the file and NewPath is empty when it deletes it.
+type FilePatch struct {
+	OldPath string
+	NewPath string
+	Hunks   []Hunk
+}
+
+type Hunk struct {
+	OldStart int
+	OldCount int
+	NewStart int
+	NewCount int
+	// Lines keep their ' ', '-', or '+' prefix and line terminator.
+	Lines []string
+}
+
+// Path returns the path the patch targets.
+func (p FilePatch) Path() string {
+	if p.NewPath != "" {
+		return p.NewPath
+	}
+	return p.OldPath
+}
+
+func (p FilePatch) IsCreate() bool { return p.OldPath == "" }
+
+func (p FilePatch) IsDelete() bool { return p.NewPath == "" }
+
+// Parse reads a unified diff that may touch several files. git headers
+// (diff --git, index, mode lines) are tolerated and ignored.
+func Parse(patch string) ([]FilePatch, error) {
+	lines := SplitLines(strings.ReplaceAll(patch, "\r\n", "\n"))
+	patches := []FilePatch{}
+	for index := 0; index < len(lines); {
+		line := lines[index]
+		if !strings.HasPrefix(line, "--- ") {
+			index++
+			continue
+		}
+		if index+1 >= len(lines) || !strings.HasPrefix(lines[index+1], "+++ ") {
+			return nil, fmt.Errorf("%w: missing +++ header after %q", ErrInvalidPatch, strings.TrimSpace(line))
+		}
+		current := FilePatch{
+			OldPath: patchPath(line[4:], "a/"),
+			NewPath: patchPath(lines[index+1][4:], "b/"),
+		}
+		if current.OldPath == "" && current.NewPath == "" {
+			return nil, fmt.Errorf("%w: both paths are /dev/null", ErrInvalidPatch)
+		}
+		index += 2
+		for index < len(lines) && strings.HasPrefix(lines[index], "@@") {
+			hunk, next, err := parseHunk(lines, index)
+			if err != nil {
+				return nil, err
+			}
+			current.Hunks = append(current.Hunks, hunk)
+			index = next
+		}
+		if len(current.Hunks) == 0 {
+			return nil, fmt.Errorf("%w: no hunks for %s", ErrInvalidPatch, current.Path())
+		}
+		patches = append(patches, current)
+	}
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("%w: no file headers found", ErrInvalidPatch)
+	}
+	return patches, nil
+}
+
+func patchPath(raw, prefix string) string {
+	value := strings.TrimRight(raw, "\n")
+	// Drop trailing timestamps ("path\t2024-01-01 ...").
+	if tab := strings.Index(value, "\t"); tab >= 0 {
+		value = value[:tab]
+	}
+	value = strings.TrimSpace(value)
+	if value == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(value, prefix)
+}
+
+func parseHunk(lines []string, index int) (Hunk, int, error) {
+	header := strings.TrimRight(lines[index], "\n")
+	match := hunkHeaderPattern.FindStringSubmatch(header)
+	if match == nil {
+		return Hunk{}, 0, fmt.Errorf("%w: bad hunk header %q", ErrInvalidPatch, header)
+	}
+	hunk := Hunk{
+		OldStart: atoiDefault(match[1], 0),
+		OldCount: atoiDefault(match[2], 1),
+		NewStart: atoiDefault(match[3], 0),
+		NewCount: atoiDefault(match[4], 1),
+	}
+	oldSeen, newSeen := 0, 0
+	index++
+	for index < len(lines) && (oldSeen < hunk.OldCount || newSeen < hunk.NewCount) {
+		line := lines[index]
+		if line == "\n" {
+			// Editors and models often strip the space from blank context lines.
+			line = " \n"
+		}
+		switch line[0] {
+		case ' ':
+			oldSeen++
+			newSeen++
+		case '-':
+			oldSeen++
+		case '+':
+			newSeen++
+		case '\\':
+			trimLastNewline(&hunk)
+			index++
+			continue
+		default:
+			return Hunk{}, 0, fmt.Errorf("%w: unexpected line %q in hunk %q", ErrInvalidPatch, strings.TrimRight(line, "\n"), header)
+		}
+		hunk.Lines = append(hunk.Lines, line)
+		index++
+	}
+	if oldSeen != hunk.OldCount || newSeen != hunk.NewCount {
+		return Hunk{}, 0, fmt.Errorf("%w: hunk %q is truncated", ErrInvalidPatch, header)
+	}
+	if index < len(lines) && strings.HasPrefix(lines[index], "\\") {
+		trimLastNewline(&hunk)
+		index++
+	}
+	return hunk, index, nil
+}
+
+func trimLastNewline(hunk *Hunk) {
+	if len(hunk.Lines) == 0 {
+		return
+	}
+	last := len(hunk.Lines) - 1
+	hunk.Lines[last] = strings.TrimSuffix(hunk.Lines[last], "\n")
+}
+
+func atoiDefault(raw string, fallback int) int {
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Apply applies a file patch to original. Every hunk must match its context
+// exactly; a hunk may be found away from its stated line but never before
+// the previous hunk.
+func Apply(original string, patch FilePatch) (string, error) {
+	lines := SplitLines(original)
+	result := make([]string, 0, len(lines))
+	cursor := 0
+	for number, hunk := range patch.Hunks {
+		oldLines, newLines := hunkSides(hunk)
+		expected := hunk.OldStart - 1
+		if hunk.OldCount == 0 {
+			expected = hunk.OldStart
+		}
+		position := findHunk(lines, oldLines, expected, cursor)
+		if position < 0 {
+			return "", fmt.Errorf("%w: hunk %d (line %d) of %s does not match the file", ErrHunkMismatch, number+1, hunk.OldStart, patch.Path())
+		}
+		result = append(result, lines[cursor:position]...)
+		result = append(result, newLines...)
+		cursor = position + len(oldLines)
+	}
+	result = append(result, lines[cursor:]...)
+	return strings.Join(result, ""), nil
+}
+
+func hunkSides(hunk Hunk) ([]string, []string) {
+	oldLines, newLines := []string{}, []string{}
+	for _, line := range hunk.Lines {
+		text := line[1:]
+		switch line[0] {
+		case ' ':
+			oldLines = append(oldLines, text)
+			newLines = append(newLines, text)
+		case '-':
+			oldLines = append(oldLines, text)
+		case '+':
+			newLines = append(newLines, text)
+		}
+	}
+	return oldLines, newLines
+}
+
+// findHunk searches outward from expected for an exact match of want.
+func findHunk(lines, want []string, expected, minimum int) int {
+	if expected < minimum {
+		expected = minimum
+	}
+	limit := len(lines) - len(want)
+	for distance := 0; ; distance++ {
+		forward, backward := expected+distance, expected-distance
+		if forward > limit && backward < minimum {
+			return -1
+		}
+		if forward <= limit && linesMatch(lines[forward:forward+len(want)], want) {
+			return forward
+		}
+		if distance > 0 && backward >= minimum && backward <= limit && linesMatch(lines[backward:backward+len(want)], want) {
+			return backward
+		}
+	}
+}
+
+func linesMatch(have, want []string) bool {
+	for index := range want {
+		// Line terminators are compared loosely so a missing final newline
+		// or CRLF endings do not reject an otherwise exact match.
+		if strings.TrimRight(have[index], "\r\n") != strings.TrimRight(want[index], "\r\n") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,323 @@
+package toolplugins
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultDir            = "ext/tool-plugins"
+	DefaultTimeoutSeconds = 30
+	maxResponseBytes      = 1 << 20
+	jsonRPCVersion        = "2.0"
+)
+
+var ErrPluginResponse = errors.New("tool plugin returned an invalid response")
+
+// Definition describes one tool exposed by a plugin executable.
+type Definition struct {
+	Plugin           string
+	Executable       string
+	ToolName         string
+	RegisteredName   string
+	Description      string
+	ParametersSchema string
+	ToolClass        string
+	RequiresApproval bool
+}
+
+// CallContext is forwarded to plugins with every execute request.
+type CallContext struct {
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	ContextID   string `json:"context_id,omitempty"`
+	Connector   string `json:"connector,omitempty"`
+	ExternalID  string `json:"external_id,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+}
+
+type Runner struct {
+	timeout time.Duration
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type describeResult struct {
+	Tools []struct {
+		Name             string          `json:"name"`
+		Description      string          `json:"description"`
+		ParametersSchema json.RawMessage `json:"parameters_schema"`
+		ToolClass        string          `json:"tool_class"`
+		RequiresApproval *bool           `json:"requires_approval"`
+	} `json:"tools"`
+}
+
+type executeParams struct {
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments"`
+	Context   CallContext     `json:"context"`
+}
+
+type executeResult struct {
+	Output string `json:"output"`
+}
+
+func NewRunner(timeout time.Duration) *Runner {
+	if timeout <= 0 {
+		timeout = DefaultTimeoutSeconds * time.Second
+	}
+	return &Runner{timeout: timeout}
+}
+
+// DiscoverExecutables lists executable regular files directly under dir as
+// absolute paths. A missing directory is not an error.
+func DiscoverExecutables(dir string) ([]string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve tool plugins dir: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read tool plugins dir: %w", err)
+	}
+	paths := []string{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Describe asks an executable for its tools. Plugin tools require approval
+// unless the plugin explicitly opts out.
+func (r *Runner) Describe(ctx context.Context, executable string) ([]Definition, error) {
+	raw, err := r.call(ctx, executable, "describe", nil)
+	if err != nil {
+		return nil, err
+	}
+	var result describeResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("%w: decode describe result: %v", ErrPluginResponse, err)
+	}
+	plugin := PluginName(executable)
+	definitions := make([]Definition, 0, len(result.Tools))
+	seen := map[string]struct{}{}
+	for _, item := range result.Tools {
+		toolName := strings.TrimSpace(item.Name)
+		if toolName == "" {
+			return nil, fmt.Errorf("%w: tool without name", ErrPluginResponse)
+		}
+		registered := BuildRegisteredToolName(plugin, toolName)
+		if _, exists := seen[registered]; exists {
+			return nil, fmt.Errorf("%w: duplicate tool %s", ErrPluginResponse, toolName)
+		}
+		seen[registered] = struct{}{}
+		requiresApproval := true
+		if item.RequiresApproval != nil {
+			requiresApproval = *item.RequiresApproval
+		}
+		definitions = append(definitions, Definition{
+			Plugin:           plugin,
+			Executable:       executable,
+			ToolName:         toolName,
+			RegisteredName:   registered,
+			Description:      strings.TrimSpace(item.Description),
+			ParametersSchema: schemaString(item.ParametersSchema),
+			ToolClass:        strings.ToLower(strings.TrimSpace(item.ToolClass)),
+			RequiresApproval: requiresApproval,
+		})
+	}
+	return definitions, nil
+}
+
+// Execute runs one tool call in a fresh plugin process.
+func (r *Runner) Execute(ctx context.Context, definition Definition, args json.RawMessage, callContext CallContext) (string, error) {
+	if len(bytes.TrimSpace(args)) == 0 {
+		args = json.RawMessage(`{}`)
+	}
+	raw, err := r.call(ctx, definition.Executable, "execute", executeParams{
+		Tool:      definition.ToolName,
+		Arguments: args,
+		Context:   callContext,
+	})
+	if err != nil {
+		return "", err
+	}
+	var result executeResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return "", fmt.Errorf("%w: decode execute result: %v", ErrPluginResponse, err)
+	}
+	return strings.TrimSpace(result.Output), nil
+}
+
+func (r *Runner) call(ctx context.Context, executable, method string, params any) (json.RawMessage, error) {
+	callCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	payload, err := json.Marshal(rpcRequest{JSONRPC: jsonRPCVersion, ID: 1, Method: method, Params: params})
+	if err != nil {
+		return nil, fmt.Errorf("encode plugin request: %w", err)
+	}
+	// The plugin runs in its own directory, so a relative path would be
+	// resolved against that directory instead of ours.
+	executable, err = filepath.Abs(executable)
+	if err != nil {
+		return nil, fmt.Errorf("resolve tool plugin path: %w", err)
+	}
+	cmd := exec.CommandContext(callCtx, executable)
+	cmd.Dir = filepath.Dir(executable)
+	// Child processes may keep stdout open after the plugin is killed.
+	cmd.WaitDelay = time.Second
+	// Plugins do not inherit the runtime environment, which carries credentials.
+	cmd.Env = pluginEnv()
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	var stdout, stderr limitedBuffer
+	stdout.limit = maxResponseBytes
+	stderr.limit = 4096
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	if callCtx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("tool plugin %s timed out after %s", PluginName(executable), r.timeout)
+	}
+	if stdout.overflow {
+		return nil, fmt.Errorf("%w: response exceeds %d bytes", ErrPluginResponse, maxResponseBytes)
+	}
+	line, _ := bufio.NewReader(bytes.NewReader(stdout.Bytes())).ReadBytes('\n')
+	if len(bytes.TrimSpace(line)) == 0 {
+		if runErr != nil {
+			return nil, fmt.Errorf("tool plugin %s failed: %v: %s", PluginName(executable), runErr, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("%w: empty response", ErrPluginResponse)
+	}
+	var response rpcResponse
+	if err := json.Unmarshal(line, &response); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPluginResponse, err)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("tool plugin %s error %d: %s", PluginName(executable), response.Error.Code, strings.TrimSpace(response.Error.Message))
+	}
+	if response.JSONRPC != jsonRPCVersion || response.ID != 1 {
+		return nil, fmt.Errorf("%w: unexpected jsonrpc envelope", ErrPluginResponse)
+	}
+	return response.Result, nil
+}
+
+func pluginEnv() []string {
+	env := []string{}
+	for _, key := range []string{"PATH", "HOME", "LANG", "TMPDIR"} {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+func schemaString(raw json.RawMessage) string {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return `{}`
+	}
+	// Plugins may send the schema as a JSON object or as an already encoded string.
+	var text string
+	if err := json.Unmarshal(trimmed, &text); err == nil {
+		return strings.TrimSpace(text)
+	}
+	return string(trimmed)
+}
+
+// PluginName derives the plugin name from its executable file name.
+func PluginName(executable string) string {
+	base := filepath.Base(executable)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func BuildRegisteredToolName(plugin, toolName string) string {
+	pluginPart := sanitizeName(plugin)
+	toolPart := sanitizeName(toolName)
+	if pluginPart == "" {
+		pluginPart = "plugin"
+	}
+	if toolPart == "" {
+		toolPart = "tool"
+	}
+	base := "plugin_" + pluginPart + "__" + toolPart
+	if len(base) <= 128 {
+		return base
+	}
+	hash := sha1.Sum([]byte(plugin + "::" + toolName))
+	return strings.TrimRight(base[:118], "_") + "_" + hex.EncodeToString(hash[:])[:8]
+}
+
+func sanitizeName(raw string) string {
+	builder := strings.Builder{}
+	for _, r := range strings.ToLower(strings.TrimSpace(raw)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			builder.WriteRune(r)
+			continue
+		}
+		builder.WriteByte('_')
+	}
+	return strings.Trim(builder.String(), "_")
+}
+
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.Len()
+	if remaining <= 0 {
+		b.overflow = b.overflow || len(p) > 0
+		return len(p), nil
+	}
+	if len(p) > remaining {
+		b.overflow = true
+		b.Buffer.Write(p[:remaining])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
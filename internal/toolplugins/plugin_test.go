@@ -0,0 +1,122 @@
+package toolplugins
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPluginScript = `#!/bin/sh
+read -r line
+case "$line" in
+  *'"describe"'*)
+    echo '{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"echo","description":"Echo text","parameters_schema":{"type":"object"}},{"name":"lookup","tool_class":"knowledge","requires_approval":false}]}}'
+    ;;
+  *'"text":"boom"'*)
+    echo '{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"boom requested"}}'
+    ;;
+  *'"execute"'*)
+    if [ -n "$AGENT_RUNTIME_LLM_API_KEY" ]; then
+      echo '{"jsonrpc":"2.0","id":1,"result":{"output":"leaked env"}}'
+    else
+      echo '{"jsonrpc":"2.0","id":1,"result":{"output":"echo ok ws-1"}}'
+    fi
+    ;;
+esac
+`
+
+func writeTestPlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	target := filepath.Join(dir, name)
+	if err := os.WriteFile(target, []byte(script), 0o755); err != nil {
+		t.Fatalf("write plugin: %v", err)
+	}
+	return target
+}
+
+func TestDescribeAndExecutePlugin(t *testing.T) {
+	dir := t.TempDir()
+	executable := writeTestPlugin(t, dir, "github-tools.sh", testPluginScript)
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a plugin"), 0o644); err != nil {
+		t.Fatalf("write readme: %v", err)
+	}
+	t.Setenv("AGENT_RUNTIME_LLM_API_KEY", "secret")
+
+	found, err := DiscoverExecutables(dir)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	if len(found) != 1 || found[0] != executable {
+		t.Fatalf("expected only the executable plugin, got %v", found)
+	}
+
+	runner := NewRunner(5 * time.Second)
+	definitions, err := runner.Describe(context.Background(), executable)
+	if err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	if len(definitions) != 2 {
+		t.Fatalf("expected 2 tools, got %+v", definitions)
+	}
+	echo := definitions[0]
+	if echo.RegisteredName != "plugin_github_tools__echo" || !echo.RequiresApproval || echo.ParametersSchema != `{"type":"object"}` {
+		t.Fatalf("unexpected echo definition %+v", echo)
+	}
+	if definitions[1].RequiresApproval || definitions[1].ToolClass != "knowledge" || definitions[1].ParametersSchema != "{}" {
+		t.Fatalf("expected lookup to opt out of approval, got %+v", definitions[1])
+	}
+
+	output, err := runner.Execute(context.Background(), echo, json.RawMessage(`{"text":"hi"}`), CallContext{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if output != "echo ok ws-1" {
+		t.Fatalf("unexpected output %q", output)
+	}
+	if _, err := runner.Execute(context.Background(), echo, json.RawMessage(`{"text":"boom"}`), CallContext{}); err == nil || !strings.Contains(err.Error(), "boom requested") {
+		t.Fatalf("expected plugin error to surface, got %v", err)
+	}
+}
+
+func TestPluginTimeoutAndInvalidResponse(t *testing.T) {
+	dir := t.TempDir()
+	slow := writeTestPlugin(t, dir, "slow", "#!/bin/sh\nsleep 5\n")
+	garbage := writeTestPlugin(t, dir, "garbage", "#!/bin/sh\necho not-json\n")
+
+	runner := NewRunner(200 * time.Millisecond)
+	if _, err := runner.Describe(context.Background(), slow); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if _, err := runner.Describe(context.Background(), garbage); err == nil {
+		t.Fatal("expected invalid response error")
+	}
+}
+
+func TestRelativePluginDirRuns(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "ext", "tool-plugins")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeTestPlugin(t, dir, "echo", testPluginScript)
+	t.Chdir(root)
+
+	found, err := DiscoverExecutables(DefaultDir)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	if len(found) != 1 || !filepath.IsAbs(found[0]) {
+		t.Fatalf("expected one absolute plugin path, got %v", found)
+	}
+	runner := NewRunner(5 * time.Second)
+	if _, err := runner.Describe(context.Background(), found[0]); err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	if _, err := runner.Describe(context.Background(), filepath.Join(DefaultDir, "echo")); err != nil {
+		t.Fatalf("describe with a relative path: %v", err)
+	}
+}
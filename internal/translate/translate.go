@@ -0,0 +1,255 @@
+// Package translate translates chat text with an LLM while enforcing
+// per-workspace terminology glossaries, and describes the channels whose
+// messages are mirrored into other languages.
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+)
+
+// SettingsRelPath is the workspace-relative location of translation settings.
+const SettingsRelPath = "context/translation.json"
+
+const maxTextBytes = 16000
+
+var (
+	ErrNoResponder   = errors.New("translation requires an LLM responder")
+	ErrEmptyText     = errors.New("text is required")
+	ErrNoLanguage    = errors.New("target language is required")
+	ErrTextTooLong   = fmt.Errorf("text exceeds %d bytes", maxTextBytes)
+	ErrEmptyResponse = errors.New("translation returned no text")
+)
+
+// Entry is one glossary term. Keep marks names and product terms that must
+// never be translated; otherwise Translations maps a language to the
+// required rendering.
+type Entry struct {
+	Term         string            `json:"term"`
+	Translations map[string]string `json:"translations,omitempty"`
+	Keep         bool              `json:"keep,omitempty"`
+	Note         string            `json:"note,omitempty"`
+}
+
+// Mirror republishes messages from a source channel into a target channel
+// in another language.
+type Mirror struct {
+	SourceConnector  string `json:"source_connector"`
+	SourceExternalID string `json:"source_external_id"`
+	TargetConnector  string `json:"target_connector"`
+	TargetExternalID string `json:"target_external_id"`
+	Language         string `json:"language"`
+}
+
+// Settings is the parsed context/translation.json of a workspace.
+type Settings struct {
+	Glossary []Entry  `json:"glossary"`
+	Mirrors  []Mirror `json:"mirrors"`
+}
+
+// LoadSettings reads a workspace's translation settings. A missing file
+// yields empty settings.
+func LoadSettings(workspaceRoot, workspaceID string) (Settings, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if strings.TrimSpace(workspaceRoot) == "" || workspaceID == "" {
+		return Settings{}, nil
+	}
+	path := filepath.Join(workspaceRoot, workspaceID, filepath.FromSlash(SettingsRelPath))
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Settings{}, nil
+	}
+	if err != nil {
+		return Settings{}, err
+	}
+	var settings Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("parse %s: %w", SettingsRelPath, err)
+	}
+	glossary := settings.Glossary[:0]
+	for _, entry := range settings.Glossary {
+		entry.Term = strings.TrimSpace(entry.Term)
+		if entry.Term == "" {
+			continue
+		}
+		glossary = append(glossary, entry)
+	}
+	settings.Glossary = glossary
+	mirrors := settings.Mirrors[:0]
+	for _, mirror := range settings.Mirrors {
+		mirror.SourceConnector = strings.ToLower(strings.TrimSpace(mirror.SourceConnector))
+		mirror.SourceExternalID = strings.TrimSpace(mirror.SourceExternalID)
+		mirror.TargetConnector = strings.ToLower(strings.TrimSpace(mirror.TargetConnector))
+		mirror.TargetExternalID = strings.TrimSpace(mirror.TargetExternalID)
+		mirror.Language = strings.TrimSpace(mirror.Language)
+		if mirror.SourceConnector == "" || mirror.SourceExternalID == "" || mirror.TargetConnector == "" || mirror.TargetExternalID == "" || mirror.Language == "" {
+			continue
+		}
+		mirrors = append(mirrors, mirror)
+	}
+	settings.Mirrors = mirrors
+	return settings, nil
+}
+
+// MirrorsFor returns the mirrors whose source is the given channel.
+func (s Settings) MirrorsFor(connector, externalID string) []Mirror {
+	connector = strings.ToLower(strings.TrimSpace(connector))
+	externalID = strings.TrimSpace(externalID)
+	var matches []Mirror
+	for _, mirror := range s.Mirrors {
+		if mirror.SourceConnector == connector && mirror.SourceExternalID == externalID {
+			matches = append(matches, mirror)
+		}
+	}
+	return matches
+}
+
+// Relevant returns the glossary entries whose term appears in text,
+// matched case-insensitively and sorted by term.
+func Relevant(glossary []Entry, text string) []Entry {
+	lower := strings.ToLower(text)
+	var matches []Entry
+	for _, entry := range glossary {
+		if strings.Contains(lower, strings.ToLower(entry.Term)) {
+			matches = append(matches, entry)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Term < matches[j].Term })
+	return matches
+}
+
+// Rendering returns the required rendering of an entry in language and
+// whether the glossary constrains it at all.
+func (e Entry) Rendering(language string) (string, bool) {
+	if e.Keep {
+		return e.Term, true
+	}
+	language = strings.TrimSpace(language)
+	for key, value := range e.Translations {
+		if strings.EqualFold(strings.TrimSpace(key), language) && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+// MissingTerms lists the required renderings that do not appear in a
+// translation.
+func MissingTerms(entries []Entry, language, translation string) []string {
+	lower := strings.ToLower(translation)
+	var missing []string
+	for _, entry := range entries {
+		rendering, ok := entry.Rendering(language)
+		if !ok {
+			continue
+		}
+		if !strings.Contains(lower, strings.ToLower(rendering)) {
+			missing = append(missing, rendering)
+		}
+	}
+	return missing
+}
+
+type Request struct {
+	WorkspaceID    string
+	Text           string
+	TargetLanguage string
+	SourceLanguage string
+	Glossary       []Entry
+}
+
+// Translator translates text through an LLM responder.
+type Translator struct {
+	responder llm.Responder
+}
+
+func New(responder llm.Responder) *Translator {
+	return &Translator{responder: responder}
+}
+
+// Translate returns the translation of req.Text into req.TargetLanguage,
+// instructing the model to use the glossary entries relevant to the text.
+func (t *Translator) Translate(ctx context.Context, req Request) (string, error) {
+	if t == nil || t.responder == nil {
+		return "", ErrNoResponder
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return "", ErrEmptyText
+	}
+	if len(text) > maxTextBytes {
+		return "", ErrTextTooLong
+	}
+	target := strings.TrimSpace(req.TargetLanguage)
+	if target == "" {
+		return "", ErrNoLanguage
+	}
+	reply, err := t.responder.Reply(ctx, llm.MessageInput{
+		WorkspaceID:   strings.TrimSpace(req.WorkspaceID),
+		Text:          buildPrompt(text, target, strings.TrimSpace(req.SourceLanguage), Relevant(req.Glossary, text)),
+		SkipGrounding: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	translation := cleanReply(reply)
+	if translation == "" {
+		return "", ErrEmptyResponse
+	}
+	return translation, nil
+}
+
+func buildPrompt(text, target, source string, entries []Entry) string {
+	lines := []string{
+		fmt.Sprintf("Translate the text below into %s.", target),
+	}
+	if source != "" {
+		lines = append(lines, fmt.Sprintf("The source language is %s.", source))
+	}
+	lines = append(lines,
+		"Rules:",
+		"- reply with the translation only, without quotes or commentary",
+		"- keep markdown, links, mentions, emoji, code, and line breaks unchanged",
+		"- keep the tone and register of the original",
+	)
+	var glossary []string
+	for _, entry := range entries {
+		rendering, ok := entry.Rendering(target)
+		if !ok {
+			continue
+		}
+		line := fmt.Sprintf("- %q -> %q", entry.Term, rendering)
+		if entry.Keep {
+			line = fmt.Sprintf("- %q: do not translate", entry.Term)
+		}
+		if note := strings.TrimSpace(entry.Note); note != "" {
+			line += " (" + note + ")"
+		}
+		glossary = append(glossary, line)
+	}
+	if len(glossary) > 0 {
+		lines = append(lines, "Glossary (always use these renderings):")
+		lines = append(lines, glossary...)
+	}
+	lines = append(lines, "Text:", text)
+	return strings.Join(lines, "\n")
+}
+
+func cleanReply(reply string) string {
+	reply = strings.TrimSpace(reply)
+	if strings.HasPrefix(reply, "```") && strings.HasSuffix(reply, "```") && len(reply) >= 6 {
+		reply = strings.TrimSuffix(strings.TrimPrefix(reply, "```"), "```")
+		if newline := strings.IndexByte(reply, '\n'); newline >= 0 && !strings.Contains(reply[:newline], " ") {
+			reply = reply[newline+1:]
+		}
+	}
+	return strings.TrimSpace(reply)
+}
@@ -0,0 +1,105 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dwizi/agent-runtime/internal/llm"
+)
+
+type fakeResponder struct {
+	input llm.MessageInput
+	reply string
+}
+
+func (f *fakeResponder) Reply(ctx context.Context, input llm.MessageInput) (string, error) {
+	f.input = input
+	return f.reply, nil
+}
+
+func TestLoadSettingsAndMirrors(t *testing.T) {
+	root := t.TempDir()
+	if settings, err := LoadSettings(root, "ws-1"); err != nil || len(settings.Glossary) != 0 {
+		t.Fatalf("expected empty settings without file, got %+v %v", settings, err)
+	}
+	dir := filepath.Join(root, "ws-1", "context")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{
+  "glossary": [{"term": "Workspace", "translations": {"es": "espacio de trabajo"}}, {"term": " "}],
+  "mirrors": [
+    {"source_connector": "Discord", "source_external_id": "announce", "target_connector": "discord", "target_external_id": "anuncios", "language": "es"},
+    {"source_connector": "discord", "source_external_id": "announce", "target_connector": "telegram", "target_external_id": "", "language": "fr"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "translation.json"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := LoadSettings(root, "ws-1")
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	if len(settings.Glossary) != 1 || len(settings.Mirrors) != 1 {
+		t.Fatalf("expected invalid entries dropped, got %+v", settings)
+	}
+	if mirrors := settings.MirrorsFor("DISCORD", "announce"); len(mirrors) != 1 || mirrors[0].TargetExternalID != "anuncios" {
+		t.Fatalf("unexpected mirrors %+v", mirrors)
+	}
+	if mirrors := settings.MirrorsFor("discord", "general"); len(mirrors) != 0 {
+		t.Fatalf("expected no mirrors for other channel, got %+v", mirrors)
+	}
+}
+
+func TestTranslateUsesRelevantGlossary(t *testing.T) {
+	responder := &fakeResponder{reply: "```\nNuevo espacio de trabajo en Dwizi\n```"}
+	glossary := []Entry{
+		{Term: "workspace", Translations: map[string]string{"ES": "espacio de trabajo"}},
+		{Term: "Dwizi", Keep: true, Note: "brand"},
+		{Term: "objective", Translations: map[string]string{"es": "objetivo"}},
+		{Term: "release", Translations: map[string]string{"fr": "version"}},
+	}
+	got, err := New(responder).Translate(context.Background(), Request{
+		WorkspaceID:    "ws-1",
+		Text:           "New Workspace release on Dwizi",
+		TargetLanguage: "es",
+		Glossary:       glossary,
+	})
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	if got != "Nuevo espacio de trabajo en Dwizi" {
+		t.Fatalf("unexpected translation %q", got)
+	}
+	prompt := responder.input.Text
+	for _, want := range []string{"into es.", `- "Dwizi": do not translate (brand)`, `- "workspace" -> "espacio de trabajo"`, "Text:\nNew Workspace release on Dwizi"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("expected %q in prompt:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "objetivo") || strings.Contains(prompt, "version") {
+		t.Fatalf("expected irrelevant entries omitted:\n%s", prompt)
+	}
+	if !responder.input.SkipGrounding {
+		t.Fatal("expected grounding to be skipped")
+	}
+	if _, err := New(responder).Translate(context.Background(), Request{Text: "hi"}); !errors.Is(err, ErrNoLanguage) {
+		t.Fatalf("expected missing language error, got %v", err)
+	}
+}
+
+func TestMissingTerms(t *testing.T) {
+	entries := []Entry{
+		{Term: "workspace", Translations: map[string]string{"es": "espacio de trabajo"}},
+		{Term: "Dwizi", Keep: true},
+	}
+	got := MissingTerms(entries, "es", "El área de trabajo de dwizi")
+	if !reflect.DeepEqual(got, []string{"espacio de trabajo"}) {
+		t.Fatalf("unexpected missing terms %v", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Package tts turns reply text into speech for connectors that can deliver
+// audio messages.
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxInputChars bounds the text sent to a provider; longer replies are cut
+// at a sentence boundary.
+const MaxInputChars = 4000
+
+const maxAudioBytes = 20 << 20
+
+var (
+	ErrUnknownProvider = errors.New("unknown tts provider")
+	ErrNotConfigured   = errors.New("tts provider is not configured")
+	ErrEmptyText       = errors.New("nothing to speak")
+)
+
+type Audio struct {
+	Data     []byte
+	MIMEType string
+	FileName string
+}
+
+// Synthesizer converts text into an audio clip.
+type Synthesizer interface {
+	Synthesize(ctx context.Context, text string) (Audio, error)
+}
+
+type Config struct {
+	Provider string
+	APIKey   string
+	BaseURL  string
+	Model    string
+	Voice    string
+	Timeout  time.Duration
+}
+
+// New returns the configured synthesizer, or nil when no provider is set.
+func New(cfg Config) (Synthesizer, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "":
+		return nil, nil
+	case "openai":
+		return NewOpenAI(cfg)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, cfg.Provider)
+	}
+}
+
+// OpenAI speaks through an OpenAI-compatible /audio/speech endpoint and
+// returns Ogg/Opus, which messaging apps play as voice notes.
+type OpenAI struct {
+	apiKey  string
+	baseURL string
+	model   string
+	voice   string
+	client  *http.Client
+}
+
+func NewOpenAI(cfg Config) (*OpenAI, error) {
+	if strings.TrimSpace(cfg.APIKey) == "" {
+		return nil, fmt.Errorf("%w: api key is required", ErrNotConfigured)
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		model = "tts-1"
+	}
+	voice := strings.TrimSpace(cfg.Voice)
+	if voice == "" {
+		voice = "alloy"
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &OpenAI{
+		apiKey:  strings.TrimSpace(cfg.APIKey),
+		baseURL: baseURL,
+		model:   model,
+		voice:   voice,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (o *OpenAI) Synthesize(ctx context.Context, text string) (Audio, error) {
+	text = SpeechText(text)
+	if text == "" {
+		return Audio{}, ErrEmptyText
+	}
+	payload, err := json.Marshal(map[string]any{
+		"model":           o.model,
+		"voice":           o.voice,
+		"input":           text,
+		"response_format": "opus",
+	})
+	if err != nil {
+		return Audio{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/audio/speech", bytes.NewReader(payload))
+	if err != nil {
+		return Audio{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := o.client.Do(req)
+	if err != nil {
+		return Audio{}, fmt.Errorf("tts request: %w", err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxAudioBytes+1))
+	if err != nil {
+		return Audio{}, fmt.Errorf("read tts response: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return Audio{}, fmt.Errorf("tts request failed: status=%d body=%q", res.StatusCode, truncate(strings.TrimSpace(string(data)), 300))
+	}
+	if len(data) > maxAudioBytes {
+		return Audio{}, fmt.Errorf("tts audio exceeds %d bytes", maxAudioBytes)
+	}
+	return Audio{Data: data, MIMEType: "audio/ogg", FileName: "reply.ogg"}, nil
+}
+
+var (
+	markdownLink   = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`)
+	markdownSyntax = regexp.MustCompile("(?m)(```[a-zA-Z]*|[*_`~]+|^#+\\s*|^>\\s*)")
+	bareURL        = regexp.MustCompile(`https?://\S+`)
+	extraSpace     = regexp.MustCompile(`[ \t]+`)
+)
+
+// SpeechText strips markdown and URLs that read badly aloud and caps the
+// result at MaxInputChars.
+func SpeechText(text string) string {
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = bareURL.ReplaceAllString(text, "")
+	text = markdownSyntax.ReplaceAllString(text, "")
+	text = extraSpace.ReplaceAllString(text, " ")
+	text = strings.TrimSpace(text)
+	if len(text) <= MaxInputChars {
+		return text
+	}
+	cut := text[:MaxInputChars]
+	if index := strings.LastIndexAny(cut, ".!?\n"); index > MaxInputChars/2 {
+		cut = cut[:index+1]
+	}
+	return strings.TrimSpace(cut)
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit]
+}
@@ -0,0 +1,58 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAISynthesize(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/speech" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte("OggS-audio"))
+	}))
+	defer server.Close()
+
+	synth, err := New(Config{Provider: "OpenAI", APIKey: "key", BaseURL: server.URL + "/v1/", Voice: "nova"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	audio, err := synth.Synthesize(context.Background(), "**Done!** See [the report](https://example.com/r) at https://example.com/x")
+	if err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+	if string(audio.Data) != "OggS-audio" || audio.MIMEType != "audio/ogg" {
+		t.Fatalf("unexpected audio %+v", audio)
+	}
+	if body["input"] != "Done! See the report at" || body["voice"] != "nova" || body["model"] != "tts-1" || body["response_format"] != "opus" {
+		t.Fatalf("unexpected request body %v", body)
+	}
+}
+
+func TestNewAndSpeechText(t *testing.T) {
+	if synth, err := New(Config{}); synth != nil || err != nil {
+		t.Fatalf("expected nil synthesizer without provider, got %v %v", synth, err)
+	}
+	if _, err := New(Config{Provider: "polly"}); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("expected unknown provider, got %v", err)
+	}
+	if _, err := New(Config{Provider: "openai"}); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected not configured, got %v", err)
+	}
+	long := strings.Repeat("This is a sentence. ", 400)
+	spoken := SpeechText(long)
+	if len(spoken) > MaxInputChars || !strings.HasSuffix(spoken, ".") {
+		t.Fatalf("expected sentence-bounded cut, got len %d ending %q", len(spoken), spoken[len(spoken)-5:])
+	}
+	if got := SpeechText("# Status\n> `ok`"); got != "Status\nok" {
+		t.Fatalf("unexpected speech text %q", got)
+	}
+}
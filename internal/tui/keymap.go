@@ -19,6 +19,10 @@ type keyMap struct {
 	View3 key.Binding
 	View4 key.Binding
 	View5 key.Binding
+	View6 key.Binding
+	View7 key.Binding
+	View8 key.Binding
+	View9 key.Binding
 
 	PairApprove  key.Binding
 	PairDeny     key.Binding
@@ -26,12 +30,39 @@ type keyMap struct {
 	PairRolePrev key.Binding
 	PairRoleNext key.Binding
 
-	ObjectiveToggle key.Binding
-	ObjectiveDelete key.Binding
+	ObjectiveToggle  key.Binding
+	ObjectiveDelete  key.Binding
+	ObjectiveRun     key.Binding
+	ObjectiveNew     key.Binding
+	ObjectiveEdit    key.Binding
+	ObjectiveHistory key.Binding
 
+	TaskResult     key.Binding
 	TaskRetry      key.Binding
+	TaskCancel     key.Binding
+	TaskDelete     key.Binding
 	TaskFilterPrev key.Binding
 	TaskFilterNext key.Binding
+
+	TrashRestore key.Binding
+
+	CaseDetail key.Binding
+	CaseToggle key.Binding
+
+	ApprovalApprove key.Binding
+	ApprovalDeny    key.Binding
+
+	Search      key.Binding
+	SearchClose key.Binding
+	Workspaces  key.Binding
+	Profile     key.Binding
+	SearchUp    key.Binding
+	SearchDown  key.Binding
+
+	FormNext   key.Binding
+	FormPrev   key.Binding
+	FormSubmit key.Binding
+	FormCancel key.Binding
 }
 
 func newKeyMap() keyMap {
@@ -88,6 +119,22 @@ func newKeyMap() keyMap {
 			key.WithKeys("5"),
 			key.WithHelp("5", "activity"),
 		),
+		View6: key.NewBinding(
+			key.WithKeys("6"),
+			key.WithHelp("6", "trash"),
+		),
+		View7: key.NewBinding(
+			key.WithKeys("7"),
+			key.WithHelp("7", "cases"),
+		),
+		View8: key.NewBinding(
+			key.WithKeys("8"),
+			key.WithHelp("8", "approvals"),
+		),
+		View9: key.NewBinding(
+			key.WithKeys("9"),
+			key.WithHelp("9", "trace"),
+		),
 		PairApprove: key.NewBinding(
 			key.WithKeys("a"),
 			key.WithHelp("a", "approve pairing"),
@@ -114,12 +161,40 @@ func newKeyMap() keyMap {
 		),
 		ObjectiveDelete: key.NewBinding(
 			key.WithKeys("x"),
-			key.WithHelp("x", "delete objective"),
+			key.WithHelp("x", "trash objective"),
+		),
+		ObjectiveRun: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "run objective now"),
+		),
+		ObjectiveNew: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new objective"),
+		),
+		ObjectiveEdit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit objective"),
+		),
+		ObjectiveHistory: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "show/hide objective runs"),
+		),
+		TaskResult: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open/close task result"),
 		),
 		TaskRetry: key.NewBinding(
 			key.WithKeys("y"),
 			key.WithHelp("y", "retry task"),
 		),
+		TaskCancel: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "cancel task"),
+		),
+		TaskDelete: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "trash task"),
+		),
 		TaskFilterPrev: key.NewBinding(
 			key.WithKeys("["),
 			key.WithHelp("[", "prev filter"),
@@ -128,6 +203,70 @@ func newKeyMap() keyMap {
 			key.WithKeys("]"),
 			key.WithHelp("]", "next filter"),
 		),
+		TrashRestore: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "restore from trash"),
+		),
+		CaseDetail: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open case items"),
+		),
+		CaseToggle: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "resolve/reopen case"),
+		),
+		ApprovalApprove: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "approve action"),
+		),
+		ApprovalDeny: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "deny action"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("ctrl+k"),
+			key.WithHelp("ctrl+k", "search"),
+		),
+		// ctrl+w is textinput's delete-word, so the picker uses ctrl+o.
+		Workspaces: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "switch workspace"),
+		),
+		Profile: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "next runtime profile"),
+		),
+		SearchClose: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close search"),
+		),
+		// j/k are typed into the query while searching, so only arrows move.
+		SearchUp: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("up", "prev result"),
+		),
+		SearchDown: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("down", "next result"),
+		),
+		// The objective form takes typed text, so only arrows, tab and
+		// control keys act on it.
+		FormNext: key.NewBinding(
+			key.WithKeys("tab", "down"),
+			key.WithHelp("tab", "next field"),
+		),
+		FormPrev: key.NewBinding(
+			key.WithKeys("shift+tab", "up"),
+			key.WithHelp("shift+tab", "prev field"),
+		),
+		FormSubmit: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "save form"),
+		),
+		FormCancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel form"),
+		),
 	}
 }
 
@@ -138,6 +277,8 @@ func (k keyMap) ShortHelp() []key.Binding {
 		k.Up,
 		k.Down,
 		k.Refresh,
+		k.Search,
+		k.Workspaces,
 		k.ToggleHelp,
 		k.Quit,
 	}
@@ -145,9 +286,10 @@ func (k keyMap) ShortHelp() []key.Binding {
 
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.FocusNext, k.FocusPrev, k.Activate, k.Refresh, k.ToggleHelp, k.Quit},
-		{k.View1, k.View2, k.View3, k.View4, k.View5},
+		{k.FocusNext, k.FocusPrev, k.Activate, k.Refresh, k.Search, k.SearchClose, k.Workspaces, k.Profile, k.ToggleHelp, k.Quit},
+		{k.View1, k.View2, k.View3, k.View4, k.View5, k.View6, k.View7, k.View8, k.View9},
 		{k.PairApprove, k.PairDeny, k.PairingNew, k.PairRolePrev, k.PairRoleNext},
-		{k.ObjectiveToggle, k.ObjectiveDelete, k.TaskRetry, k.TaskFilterPrev, k.TaskFilterNext},
+		{k.ObjectiveNew, k.ObjectiveEdit, k.FormSubmit, k.FormCancel},
+		{k.ObjectiveToggle, k.ObjectiveDelete, k.ObjectiveRun, k.ObjectiveHistory, k.TaskResult, k.TaskRetry, k.TaskCancel, k.TaskDelete, k.TaskFilterPrev, k.TaskFilterNext, k.TrashRestore, k.CaseDetail, k.CaseToggle, k.ApprovalApprove, k.ApprovalDeny},
 	}
 }
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+)
+
+const (
+	liveRetryMin = time.Second
+	liveRetryMax = 30 * time.Second
+	// liveDebounce coalesces a burst of changes, such as a task moving
+	// through queued, running and succeeded, into one refresh.
+	liveDebounce = 500 * time.Millisecond
+)
+
+// liveStateMsg reports that the change stream connected or dropped. gen ties
+// every live message to the stream that sent it, so messages of a stream
+// replaced by a runtime switch are ignored.
+type liveStateMsg struct {
+	gen       int
+	connected bool
+	err       error
+}
+
+type liveChangeMsg struct {
+	gen    int
+	change adminclient.Change
+}
+
+type liveDebounceMsg struct {
+	seq int
+}
+
+func liveDebounceCmd(seq int) tea.Cmd {
+	return tea.Tick(liveDebounce, func(time.Time) tea.Msg {
+		return liveDebounceMsg{seq: seq}
+	})
+}
+
+// waitLiveCmd delivers the next message of a change stream. Update re-issues
+// it after each one, so exactly one read is outstanding per stream.
+func waitLiveCmd(events <-chan tea.Msg) tea.Cmd {
+	if events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg, ok := <-events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// startLiveCmd replaces the change stream with one on the current client.
+func (m *model) startLiveCmd() tea.Cmd {
+	if m.liveCancel != nil {
+		m.liveCancel()
+		m.liveCancel = nil
+	}
+	m.liveGeneration++
+	m.liveConnected = false
+	m.liveEvents = nil
+	if m.client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan tea.Msg, 16)
+	m.liveCancel = cancel
+	m.liveEvents = events
+	go runChangeStream(ctx, m.client, m.liveGeneration, events)
+	return waitLiveCmd(events)
+}
+
+// runChangeStream keeps a change stream open, reconnecting with backoff,
+// until ctx is done. Servers without the stream fail every attempt and the
+// TUI keeps polling.
+func runChangeStream(ctx context.Context, client *adminclient.Client, gen int, events chan<- tea.Msg) {
+	defer close(events)
+	send := func(msg tea.Msg) bool {
+		select {
+		case events <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	delay := liveRetryMin
+	for {
+		stream, err := client.OpenChangeStream(ctx, "")
+		if err == nil {
+			delay = liveRetryMin
+			if !send(liveStateMsg{gen: gen, connected: true}) {
+				_ = stream.Close()
+				return
+			}
+			for {
+				change, nextErr := stream.Next()
+				if nextErr != nil {
+					err = nextErr
+					break
+				}
+				if !send(liveChangeMsg{gen: gen, change: change}) {
+					_ = stream.Close()
+					return
+				}
+			}
+			_ = stream.Close()
+		}
+		if ctx.Err() != nil || !send(liveStateMsg{gen: gen, err: err}) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, liveRetryMax)
+	}
+}
+
+// changeRelevant reports whether a change touches what the TUI shows:
+// approvals are listed across workspaces, everything else only for the
+// workspaces the views filter on.
+func (m model) changeRelevant(change adminclient.Change) bool {
+	if change.Kind == "approval" {
+		return true
+	}
+	for _, input := range []string{
+		m.objectiveWorkspaceInput.Value(),
+		m.taskWorkspaceInput.Value(),
+		m.trashWorkspaceInput.Value(),
+		m.caseWorkspaceInput.Value(),
+	} {
+		if strings.TrimSpace(input) == change.WorkspaceID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m model) liveLabel() string {
+	if m.liveConnected {
+		return "live"
+	}
+	return "polling"
+}
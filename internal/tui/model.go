@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -33,6 +34,10 @@ const (
 	viewObjectives viewID = "objectives"
 	viewTasks      viewID = "tasks"
 	viewActivity   viewID = "activity"
+	viewTrash      viewID = "trash"
+	viewCases      viewID = "cases"
+	viewApprovals  viewID = "approvals"
+	viewTrace      viewID = "trace"
 )
 
 type focusZone int
@@ -67,6 +72,12 @@ type model struct {
 	logger *slog.Logger
 	client *adminclient.Client
 
+	// profiles are the admin endpoints the TUI can switch between; the
+	// first is the AGENT_RUNTIME_ADMIN_* one. cfg and client follow the
+	// profile at profileIndex.
+	profiles     []config.TUIProfile
+	profileIndex int
+
 	width  int
 	height int
 
@@ -100,12 +111,61 @@ type model struct {
 	objectiveWorkspaceInput textinput.Model
 	objectives              []adminclient.Objective
 	objectivesTable         table.Model
+	objectiveForm           objectiveForm
+	// objectiveRuns is the run history last opened with the history key;
+	// the inspector lists it while that objective stays selected.
+	objectiveRuns *objectiveRunHistory
 
 	taskWorkspaceInput textinput.Model
 	taskStatusFilter   string
 	tasks              []adminclient.Task
 	tasksTable         table.Model
 	taskRetryMsg       *adminclient.RetryTaskResponse
+	// taskResult is the result file last opened with the result key; the
+	// inspector shows it instead of the task detail while that task stays
+	// selected.
+	taskResult *adminclient.TaskResult
+
+	trashWorkspaceInput textinput.Model
+	trash               []adminclient.TrashItem
+	trashTable          table.Model
+
+	caseWorkspaceInput textinput.Model
+	cases              []adminclient.Case
+	casesTable         table.Model
+	// caseDetail holds the items of the case last opened with the detail
+	// key; it is shown while that case stays selected.
+	caseDetail *adminclient.Case
+
+	// approvals is the pending action queue across every workspace.
+	approvals      []adminclient.Approval
+	approvalsTable table.Model
+
+	// The trace view follows one chat log. traceEntries holds the tail of
+	// the chat named by traceChat and is refetched on every traceTickMsg
+	// while the view is open.
+	traceWorkspaceInput textinput.Model
+	chatLogs            []adminclient.ChatLog
+	chatLogsTable       table.Model
+	traceEntries        []adminclient.ChatLogEntry
+	traceChat           string
+	traceInFlight       bool
+
+	// The workspace picker lists the known workspaces; picking one sets the
+	// workspace of every view.
+	workspacePickerOpen bool
+	workspaces          []adminclient.Workspace
+	workspaceCursor     int
+
+	searchOpen    bool
+	searchInput   textinput.Model
+	searchResults []adminclient.SearchResult
+	searchCursor  int
+
+	// pendingTaskID and pendingObjectiveID select a row once the table a
+	// search result jumped to has loaded.
+	pendingTaskID      string
+	pendingObjectiveID string
 
 	inspectorViewport viewport.Model
 	activityViewport  viewport.Model
@@ -116,6 +176,15 @@ type model struct {
 	activity  []activityEvent
 
 	debounceSequence int
+
+	// The change stream pushes task, objective and approval changes so
+	// views refresh as they happen; the poll only refreshes while it is
+	// down. liveGeneration grows with every stream started.
+	liveEvents     <-chan tea.Msg
+	liveCancel     context.CancelFunc
+	liveGeneration int
+	liveConnected  bool
+	liveSequence   int
 }
 
 type tickMsg struct {
@@ -128,12 +197,19 @@ type pollMsg struct {
 
 type bootstrapMsg struct{}
 
+type traceTickMsg struct{}
+
 type workspaceDebounceMsg struct {
 	seq    int
 	target viewID
 	value  string
 }
 
+type searchDebounceMsg struct {
+	seq   int
+	value string
+}
+
 func tickCmd() tea.Cmd {
 	return tea.Tick(250*time.Millisecond, func(at time.Time) tea.Msg {
 		return tickMsg{at: at}
@@ -146,6 +222,14 @@ func pollCmd() tea.Cmd {
 	})
 }
 
+// traceTickCmd paces the trace view's tail of the selected chat log, much
+// faster than the regular poll so tool calls show up as they happen.
+func traceTickCmd() tea.Cmd {
+	return tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+		return traceTickMsg{}
+	})
+}
+
 func bootstrapCmd() tea.Cmd {
 	return func() tea.Msg {
 		return bootstrapMsg{}
@@ -158,6 +242,12 @@ func workspaceDebounceCmd(seq int, target viewID, value string) tea.Cmd {
 	})
 }
 
+func searchDebounceCmd(seq int, value string) tea.Cmd {
+	return tea.Tick(300*time.Millisecond, func(time.Time) tea.Msg {
+		return searchDebounceMsg{seq: seq, value: value}
+	})
+}
+
 func Run(cfg config.Config, logger *slog.Logger) error {
 	updatedCfg, startupInfo := syncEnvAtStartup(cfg, logger)
 
@@ -188,16 +278,34 @@ func newModel(cfg config.Config, startupInfo string, client *adminclient.Client,
 
 	objectiveWorkspaceInput := textinput.New()
 	objectiveWorkspaceInput.Prompt = "workspace> "
-	objectiveWorkspaceInput.Placeholder = "ws-1"
+	objectiveWorkspaceInput.Placeholder = "ws-1 (ctrl+o to pick)"
 	objectiveWorkspaceInput.CharLimit = 128
 	objectiveWorkspaceInput.SetValue("ws-1")
 
 	taskWorkspaceInput := textinput.New()
 	taskWorkspaceInput.Prompt = "workspace> "
-	taskWorkspaceInput.Placeholder = "ws-1"
+	taskWorkspaceInput.Placeholder = "ws-1 (ctrl+o to pick)"
 	taskWorkspaceInput.CharLimit = 128
 	taskWorkspaceInput.SetValue("ws-1")
 
+	trashWorkspaceInput := textinput.New()
+	trashWorkspaceInput.Prompt = "workspace> "
+	trashWorkspaceInput.Placeholder = "ws-1 (ctrl+o to pick)"
+	trashWorkspaceInput.CharLimit = 128
+	trashWorkspaceInput.SetValue("ws-1")
+
+	caseWorkspaceInput := textinput.New()
+	caseWorkspaceInput.Prompt = "workspace> "
+	caseWorkspaceInput.Placeholder = "ws-1 (ctrl+o to pick)"
+	caseWorkspaceInput.CharLimit = 128
+	caseWorkspaceInput.SetValue("ws-1")
+
+	traceWorkspaceInput := textinput.New()
+	traceWorkspaceInput.Prompt = "workspace> "
+	traceWorkspaceInput.Placeholder = "ws-1 (ctrl+o to pick)"
+	traceWorkspaceInput.CharLimit = 128
+	traceWorkspaceInput.SetValue("ws-1")
+
 	objectivesTable := table.New()
 	objectivesTable.Focus()
 	objectivesTable.SetColumns([]table.Column{{Title: "Title", Width: 32}, {Title: "State", Width: 10}, {Title: "Trigger", Width: 12}, {Title: "Next Run", Width: 22}})
@@ -206,6 +314,27 @@ func newModel(cfg config.Config, startupInfo string, client *adminclient.Client,
 	tasksTable.Focus()
 	tasksTable.SetColumns([]table.Column{{Title: "Title", Width: 36}, {Title: "Status", Width: 10}, {Title: "Kind", Width: 12}, {Title: "Attempts", Width: 10}, {Title: "Updated", Width: 22}})
 
+	searchInput := textinput.New()
+	searchInput.Prompt = "search> "
+	searchInput.Placeholder = "tasks, objectives, approvals, audit"
+	searchInput.CharLimit = 256
+
+	trashTable := table.New()
+	trashTable.Focus()
+	trashTable.SetColumns([]table.Column{{Title: "Title", Width: 32}, {Title: "Kind", Width: 10}, {Title: "Deleted", Width: 22}, {Title: "Purge", Width: 22}})
+
+	casesTable := table.New()
+	casesTable.Focus()
+	casesTable.SetColumns([]table.Column{{Title: "Title", Width: 32}, {Title: "Kind", Width: 10}, {Title: "Status", Width: 9}, {Title: "Owner", Width: 12}, {Title: "Updated", Width: 22}})
+
+	approvalsTable := table.New()
+	approvalsTable.Focus()
+	approvalsTable.SetColumns([]table.Column{{Title: "Summary", Width: 32}, {Title: "Risk", Width: 7}, {Title: "Age", Width: 6}, {Title: "Context", Width: 14}, {Title: "ID", Width: 14}})
+
+	chatLogsTable := table.New()
+	chatLogsTable.Focus()
+	chatLogsTable.SetColumns([]table.Column{{Title: "Connector", Width: 10}, {Title: "Chat", Width: 24}, {Title: "Updated", Width: 22}})
+
 	inspectorVP := viewport.New(viewport.WithWidth(40), viewport.WithHeight(20))
 	activityVP := viewport.New(viewport.WithWidth(80), viewport.WithHeight(20))
 
@@ -213,6 +342,7 @@ func newModel(cfg config.Config, startupInfo string, client *adminclient.Client,
 		cfg:                     cfg,
 		logger:                  logger,
 		client:                  client,
+		profiles:                append([]config.TUIProfile{cfg.DefaultTUIProfile()}, cfg.TUIProfiles...),
 		clock:                   time.Now().UTC(),
 		activeView:              viewOverview,
 		sidebarIndex:            0,
@@ -225,8 +355,16 @@ func newModel(cfg config.Config, startupInfo string, client *adminclient.Client,
 		tokenInput:              tokenInput,
 		objectiveWorkspaceInput: objectiveWorkspaceInput,
 		taskWorkspaceInput:      taskWorkspaceInput,
+		trashWorkspaceInput:     trashWorkspaceInput,
+		caseWorkspaceInput:      caseWorkspaceInput,
+		traceWorkspaceInput:     traceWorkspaceInput,
 		objectivesTable:         objectivesTable,
 		tasksTable:              tasksTable,
+		trashTable:              trashTable,
+		casesTable:              casesTable,
+		approvalsTable:          approvalsTable,
+		chatLogsTable:           chatLogsTable,
+		searchInput:             searchInput,
 		inspectorViewport:       inspectorVP,
 		activityViewport:        activityVP,
 		activity:                make([]activityEvent, 0, 256),
@@ -240,7 +378,7 @@ func newModel(cfg config.Config, startupInfo string, client *adminclient.Client,
 }
 
 func (m model) Init() tea.Cmd {
-	return batchCmds(tickCmd(), pollCmd(), bootstrapCmd())
+	return batchCmds(tickCmd(), pollCmd(), traceTickCmd(), bootstrapCmd())
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -261,7 +399,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m.finalize(tickCmd())
 	case pollMsg:
-		if !m.busy() {
+		if !m.busy() && !m.liveConnected {
 			cmd := m.refreshForPollCmd()
 			if cmd != nil {
 				cmds = append(cmds, cmd)
@@ -269,13 +407,57 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		cmds = append(cmds, pollCmd())
 		return m.finalize(batchCmds(cmds...))
+	case traceTickMsg:
+		if m.activeView == viewTrace && m.pendingMutations == 0 && !m.traceInFlight {
+			cmds = append(cmds, m.traceTailCmd())
+		}
+		cmds = append(cmds, traceTickCmd())
+		return m.finalize(batchCmds(cmds...))
 	case bootstrapMsg:
 		cmd := m.refreshViewAndOverviewCmd("initial load", true)
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
 		m.statusText = "initial load queued"
+		cmds = append(cmds, m.startLiveCmd())
+		return m.finalize(batchCmds(cmds...))
+	case liveStateMsg:
+		if typed.gen != m.liveGeneration {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, waitLiveCmd(m.liveEvents))
+		if typed.connected == m.liveConnected {
+			return m.finalize(batchCmds(cmds...))
+		}
+		m.liveConnected = typed.connected
+		if typed.connected {
+			m.addActivity("info", "live updates connected")
+			// Changes made while the stream was down were never announced.
+			m.liveSequence++
+			cmds = append(cmds, liveDebounceCmd(m.liveSequence))
+		} else {
+			m.addActivity("warn", "live updates lost, polling: "+mutationErrorText(typed.err))
+		}
+		return m.finalize(batchCmds(cmds...))
+	case liveChangeMsg:
+		if typed.gen != m.liveGeneration {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, waitLiveCmd(m.liveEvents))
+		if m.changeRelevant(typed.change) {
+			m.liveSequence++
+			cmds = append(cmds, liveDebounceCmd(m.liveSequence))
+		}
 		return m.finalize(batchCmds(cmds...))
+	case liveDebounceMsg:
+		if typed.seq != m.liveSequence {
+			return m.finalize(nil)
+		}
+		if m.busy() {
+			// Retry once the pending requests finish rather than drop it.
+			return m.finalize(liveDebounceCmd(typed.seq))
+		}
+		return m.finalize(m.refreshForPollCmd())
 	case workspaceDebounceMsg:
 		if typed.seq != m.debounceSequence {
 			return m.finalize(nil)
@@ -299,6 +481,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmd := m.beginLoad(1, "loading tasks...")
 			cmds = append(cmds, cmd, m.listTasksCmd(trimmed, m.taskStatusFilter, "workspace-change"))
 			m.addActivity("info", "workspace changed for tasks: "+trimmed)
+		case viewTrash:
+			if trimmed != strings.TrimSpace(m.trashWorkspaceInput.Value()) {
+				return m.finalize(nil)
+			}
+			cmd := m.beginLoad(1, "loading trash...")
+			cmds = append(cmds, cmd, m.listTrashCmd(trimmed, "workspace-change"))
+			m.addActivity("info", "workspace changed for trash: "+trimmed)
+		case viewCases:
+			if trimmed != strings.TrimSpace(m.caseWorkspaceInput.Value()) {
+				return m.finalize(nil)
+			}
+			cmd := m.beginLoad(1, "loading cases...")
+			cmds = append(cmds, cmd, m.listCasesCmd(trimmed, "workspace-change"))
+			m.addActivity("info", "workspace changed for cases: "+trimmed)
+		case viewTrace:
+			if trimmed != strings.TrimSpace(m.traceWorkspaceInput.Value()) {
+				return m.finalize(nil)
+			}
+			cmd := m.beginLoad(1, "loading chats...")
+			cmds = append(cmds, cmd, m.listChatLogsCmd(trimmed, "workspace-change"))
+			m.addActivity("info", "workspace changed for trace: "+trimmed)
 		}
 		return m.finalize(batchCmds(cmds...))
 	case spinner.TickMsg:
@@ -362,6 +565,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if typed.workspaceID == strings.TrimSpace(m.objectiveWorkspaceInput.Value()) {
 			m.objectives = typed.items
 			m.rebuildObjectiveRows()
+			m.selectPendingObjective()
 		}
 		m.recomputeDashboardStats()
 		m.dashboard.LastRefresh = m.clock
@@ -372,7 +576,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case objectiveActiveDoneMsg:
 		m.endMutation()
 		if typed.err != nil {
-			m.errorText = typed.err.Error()
+			m.errorText = mutationErrorText(typed.err)
 			m.statusText = ""
 			m.addActivity("error", "objective state update failed: "+typed.err.Error())
 			return m.finalize(nil)
@@ -394,11 +598,59 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.rebuildObjectiveRows()
 		m.recomputeDashboardStats()
 		return m.finalize(nil)
-	case objectiveDeleteDoneMsg:
+	case objectiveSavedMsg:
+		m.endMutation()
+		if typed.err != nil {
+			// The form stays open so the input is not lost.
+			m.errorText = mutationErrorText(typed.err)
+			m.statusText = ""
+			m.addActivity("error", "objective save failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		m.objectiveForm = objectiveForm{}
+		if typed.created {
+			m.statusText = "objective created"
+			m.addActivity("info", "objective created: "+typed.item.ID)
+		} else {
+			m.statusText = "objective updated"
+			m.addActivity("info", "objective updated: "+typed.item.ID)
+		}
+		m.errorText = ""
+		// Reload so the saved objective shows in server order, then select it.
+		m.objectiveWorkspaceInput.SetValue(typed.item.WorkspaceID)
+		m.pendingObjectiveID = typed.item.ID
+		cmds = append(cmds, m.applyFocusCmd(), m.beginLoad(1, "loading objectives..."), m.listObjectivesCmd(typed.item.WorkspaceID, "post-save"))
+		return m.finalize(batchCmds(cmds...))
+	case objectiveRunDoneMsg:
 		m.endMutation()
 		if typed.err != nil {
 			m.errorText = typed.err.Error()
 			m.statusText = ""
+			m.addActivity("error", "objective run failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		m.statusText = "objective run queued: " + typed.response.TaskID
+		m.errorText = ""
+		m.addActivity("info", fmt.Sprintf("objective %s queued as task %s", typed.response.ObjectiveID, typed.response.TaskID))
+		return m.finalize(nil)
+	case objectiveRunsLoadedMsg:
+		m.endLoad()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "objective runs load failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		m.objectiveRuns = &objectiveRunHistory{objectiveID: typed.objectiveID, items: typed.items}
+		m.inspectorViewport.GotoTop()
+		m.statusText = fmt.Sprintf("loaded %d objective run(s); h hides them", len(typed.items))
+		m.errorText = ""
+		return m.finalize(nil)
+	case objectiveDeleteDoneMsg:
+		m.endMutation()
+		if typed.err != nil {
+			m.errorText = mutationErrorText(typed.err)
+			m.statusText = ""
 			m.addActivity("error", "objective delete failed: "+typed.err.Error())
 			return m.finalize(nil)
 		}
@@ -412,9 +664,238 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.objectives = filtered
 		m.rebuildObjectiveRows()
 		m.recomputeDashboardStats()
-		m.statusText = "objective deleted"
+		m.statusText = "objective moved to trash"
+		m.errorText = ""
+		m.addActivity("warn", "objective moved to trash: "+typed.id+" (restore from view 6)")
+		return m.finalize(nil)
+	case taskDeleteDoneMsg:
+		m.endMutation()
+		if typed.err != nil {
+			m.errorText = mutationErrorText(typed.err)
+			m.statusText = ""
+			m.addActivity("error", "task delete failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		filtered := make([]adminclient.Task, 0, len(m.tasks))
+		for _, item := range m.tasks {
+			if item.ID == typed.id {
+				continue
+			}
+			filtered = append(filtered, item)
+		}
+		m.tasks = filtered
+		m.rebuildTaskRows()
+		m.recomputeDashboardStats()
+		m.statusText = "task moved to trash"
+		m.errorText = ""
+		m.addActivity("warn", "task moved to trash: "+typed.id+" (restore from view 6)")
+		return m.finalize(nil)
+	case workspacesLoadedMsg:
+		m.endLoad()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "workspace list failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		m.workspaces = typed.items
+		m.workspaceCursor = 0
+		current := m.currentWorkspaceID()
+		for index, item := range m.workspaces {
+			if item.ID == current {
+				m.workspaceCursor = index
+				break
+			}
+		}
+		m.statusText = fmt.Sprintf("loaded %d workspace(s)", len(typed.items))
+		m.errorText = ""
+		return m.finalize(nil)
+	case searchDebounceMsg:
+		if typed.seq != m.debounceSequence || !m.searchOpen {
+			return m.finalize(nil)
+		}
+		query := strings.TrimSpace(typed.value)
+		if query == "" || query != strings.TrimSpace(m.searchInput.Value()) {
+			return m.finalize(nil)
+		}
+		m.statusText = "searching..."
+		return m.finalize(m.searchCmd(query))
+	case searchLoadedMsg:
+		if typed.query != strings.TrimSpace(m.searchInput.Value()) {
+			return m.finalize(nil)
+		}
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "search failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		m.searchResults = typed.items
+		m.searchCursor = 0
+		m.statusText = fmt.Sprintf("%d match(es) for %q", len(typed.items), typed.query)
+		m.errorText = ""
+		return m.finalize(nil)
+	case trashLoadedMsg:
+		m.endLoad()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "trash load failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		if typed.workspaceID == strings.TrimSpace(m.trashWorkspaceInput.Value()) {
+			m.trash = typed.items
+			m.rebuildTrashRows()
+		}
+		m.statusText = fmt.Sprintf("loaded %d trashed item(s)", len(typed.items))
+		m.errorText = ""
+		m.addActivity("info", fmt.Sprintf("loaded %d trashed items (%s)", len(typed.items), typed.workspaceID))
+		return m.finalize(nil)
+	case trashRestoreDoneMsg:
+		m.endMutation()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "restore failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		filtered := make([]adminclient.TrashItem, 0, len(m.trash))
+		for _, item := range m.trash {
+			if item.Kind == typed.item.Kind && item.ID == typed.item.ID {
+				continue
+			}
+			filtered = append(filtered, item)
+		}
+		m.trash = filtered
+		m.rebuildTrashRows()
+		if cmd := m.refreshViewAndOverviewCmd("post-restore", false); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		m.statusText = typed.item.Kind + " restored"
+		m.errorText = ""
+		m.addActivity("info", typed.item.Kind+" restored: "+typed.item.ID)
+		return m.finalize(batchCmds(cmds...))
+	case casesLoadedMsg:
+		m.endLoad()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "cases load failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		if typed.workspaceID == strings.TrimSpace(m.caseWorkspaceInput.Value()) {
+			m.cases = typed.items
+			m.rebuildCaseRows()
+		}
+		m.statusText = fmt.Sprintf("loaded %d case(s)", len(typed.items))
+		m.errorText = ""
+		m.addActivity("info", fmt.Sprintf("loaded %d cases (%s)", len(typed.items), typed.workspaceID))
+		return m.finalize(nil)
+	case caseDetailLoadedMsg:
+		m.endLoad()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "case load failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		detail := typed.item
+		m.caseDetail = &detail
+		m.statusText = fmt.Sprintf("case has %d item(s)", len(detail.Items))
+		m.errorText = ""
+		return m.finalize(nil)
+	case caseUpdateDoneMsg:
+		m.endMutation()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "case update failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		for index := range m.cases {
+			if m.cases[index].ID == typed.item.ID {
+				m.cases[index] = typed.item
+			}
+		}
+		m.rebuildCaseRows()
+		m.statusText = "case " + typed.item.Status
+		m.errorText = ""
+		m.addActivity("info", "case "+typed.item.Status+": "+typed.item.ID)
+		return m.finalize(nil)
+	case approvalsLoadedMsg:
+		m.endLoad()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "approvals load failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		m.approvals = typed.items
+		m.rebuildApprovalRows()
+		m.statusText = fmt.Sprintf("loaded %d pending approval(s)", len(typed.items))
+		m.errorText = ""
+		m.addActivity("info", fmt.Sprintf("loaded %d pending approvals", len(typed.items)))
+		return m.finalize(nil)
+	case chatLogsLoadedMsg:
+		m.endLoad()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "chat load failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		if typed.workspaceID == strings.TrimSpace(m.traceWorkspaceInput.Value()) {
+			m.chatLogs = typed.items
+			m.rebuildChatLogRows()
+			if selected, ok := m.selectedChatLog(); ok && chatLogKey(selected) != m.traceChat {
+				m.traceInFlight = false
+				cmds = append(cmds, m.traceTailCmd())
+			}
+		}
+		m.statusText = fmt.Sprintf("loaded %d chat(s)", len(typed.items))
+		m.errorText = ""
+		m.addActivity("info", fmt.Sprintf("loaded %d chats (%s)", len(typed.items), typed.workspaceID))
+		return m.finalize(batchCmds(cmds...))
+	case chatLogTailLoadedMsg:
+		m.traceInFlight = false
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			return m.finalize(nil)
+		}
+		selected, ok := m.selectedChatLog()
+		if !ok || chatLogKey(selected) != typed.chat || typed.workspaceID != strings.TrimSpace(m.traceWorkspaceInput.Value()) {
+			return m.finalize(nil)
+		}
+		m.traceChat = typed.chat
+		m.traceEntries = typed.items
+		return m.finalize(nil)
+	case approvalDecisionDoneMsg:
+		m.endMutation()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "approval decision failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		if typed.item.Status != "pending" {
+			filtered := make([]adminclient.Approval, 0, len(m.approvals))
+			for _, item := range m.approvals {
+				if item.ID != typed.item.ID {
+					filtered = append(filtered, item)
+				}
+			}
+			m.approvals = filtered
+		} else {
+			for index := range m.approvals {
+				if m.approvals[index].ID == typed.item.ID {
+					m.approvals[index] = typed.item
+				}
+			}
+		}
+		m.rebuildApprovalRows()
+		m.statusText = approvalDecisionText(typed.item)
 		m.errorText = ""
-		m.addActivity("warn", "objective deleted: "+typed.id)
+		m.addActivity("info", m.statusText+": "+typed.item.ID)
 		return m.finalize(nil)
 	case tasksLoadedMsg:
 		m.endLoad()
@@ -427,6 +908,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if typed.workspaceID == strings.TrimSpace(m.taskWorkspaceInput.Value()) && typed.status == m.taskStatusFilter {
 			m.tasks = typed.items
 			m.rebuildTaskRows()
+			m.selectPendingTask()
 		}
 		m.recomputeDashboardStats()
 		m.dashboard.LastRefresh = m.clock
@@ -434,6 +916,39 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.errorText = ""
 		m.addActivity("info", fmt.Sprintf("loaded %d tasks (%s)", len(typed.items), typed.workspaceID))
 		return m.finalize(nil)
+	case taskResultLoadedMsg:
+		m.endLoad()
+		if typed.err != nil {
+			m.errorText = typed.err.Error()
+			m.statusText = ""
+			m.addActivity("error", "task result load failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		result := typed.item
+		m.taskResult = &result
+		m.inspectorViewport.GotoTop()
+		m.focus = focusInspector
+		cmds = append(cmds, m.applyFocusCmd())
+		m.statusText = "task result opened; o closes it"
+		m.errorText = ""
+		return m.finalize(batchCmds(cmds...))
+	case taskCancelDoneMsg:
+		m.endMutation()
+		if typed.err != nil {
+			m.errorText = mutationErrorText(typed.err)
+			m.statusText = ""
+			m.addActivity("error", "task cancel failed: "+typed.err.Error())
+			return m.finalize(nil)
+		}
+		m.statusText = "task cancelled"
+		m.errorText = ""
+		m.addActivity("warn", "task cancelled: "+typed.response.TaskID)
+		workspaceID := strings.TrimSpace(m.taskWorkspaceInput.Value())
+		if workspaceID != "" {
+			cmd := m.beginLoad(1, "loading tasks...")
+			cmds = append(cmds, cmd, m.listTasksCmd(workspaceID, m.taskStatusFilter, "post-cancel"))
+		}
+		return m.finalize(batchCmds(cmds...))
 	case taskRetryDoneMsg:
 		m.endMutation()
 		if typed.err != nil {
@@ -458,8 +973,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if !isKey {
 		return m.finalize(nil)
 	}
+	if m.searchOpen {
+		return m.updateSearchKey(keyMsg)
+	}
+	if m.workspacePickerOpen {
+		return m.updateWorkspacePickerKey(keyMsg)
+	}
+	if m.objectiveForm.open && m.activeView == viewObjectives && m.focus == focusWorkbench {
+		return m.updateObjectiveFormKey(keyMsg)
+	}
 
 	switch {
+	case key.Matches(keyMsg, m.keys.Search):
+		m.searchOpen = true
+		m.statusText = "search: type to query, enter to open, esc to close"
+		m.errorText = ""
+		return m.finalize(m.searchInput.Focus())
+	case key.Matches(keyMsg, m.keys.Profile):
+		return m.switchProfile()
+	case key.Matches(keyMsg, m.keys.Workspaces):
+		m.workspacePickerOpen = true
+		m.statusText = "pick a workspace: enter to switch, esc to close"
+		m.errorText = ""
+		return m.finalize(batchCmds(m.beginLoad(1, "loading workspaces..."), m.listWorkspacesCmd()))
 	case key.Matches(keyMsg, m.keys.Quit):
 		m.quitting = true
 		return m.finalize(tea.Quit)
@@ -496,6 +1032,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case key.Matches(keyMsg, m.keys.View5):
 		cmds = append(cmds, m.activateView(viewActivity))
 		return m.finalize(batchCmds(cmds...))
+	case key.Matches(keyMsg, m.keys.View6) && !m.editingPairingToken():
+		// Pairing tokens are base32 and may contain a 6, so the shortcut
+		// yields to the token input.
+		cmds = append(cmds, m.activateView(viewTrash))
+		return m.finalize(batchCmds(cmds...))
+	case key.Matches(keyMsg, m.keys.View7) && !m.editingPairingToken():
+		cmds = append(cmds, m.activateView(viewCases))
+		return m.finalize(batchCmds(cmds...))
+	case key.Matches(keyMsg, m.keys.View8):
+		cmds = append(cmds, m.activateView(viewApprovals))
+		return m.finalize(batchCmds(cmds...))
+	case key.Matches(keyMsg, m.keys.View9):
+		cmds = append(cmds, m.activateView(viewTrace))
+		return m.finalize(batchCmds(cmds...))
 	case key.Matches(keyMsg, m.keys.Refresh):
 		if !m.busy() {
 			cmd := m.refreshViewAndOverviewCmd("manual refresh", true)
@@ -583,6 +1133,14 @@ func (m model) updateWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.updateObjectivesWorkbenchKey(keyMsg)
 	case viewTasks:
 		return m.updateTasksWorkbenchKey(keyMsg)
+	case viewTrash:
+		return m.updateTrashWorkbenchKey(keyMsg)
+	case viewCases:
+		return m.updateCasesWorkbenchKey(keyMsg)
+	case viewApprovals:
+		return m.updateApprovalsWorkbenchKey(keyMsg)
+	case viewTrace:
+		return m.updateTraceWorkbenchKey(keyMsg)
 	case viewActivity:
 		var cmd tea.Cmd
 		m.activityViewport, cmd = m.activityViewport.Update(keyMsg)
@@ -596,43 +1154,255 @@ func (m model) updateWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
-func (m model) updatePairingsWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
+// updateSearchKey handles keys while the search palette is open; it owns
+// the keyboard until closed so typed letters never trigger view shortcuts.
+func (m model) updateSearchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case key.Matches(keyMsg, m.keys.PairRolePrev):
-		m.pairingRole = previousPairingRole(m.currentPairingRole())
-		m.statusText = "approval role: " + m.pairingRole
-		m.errorText = ""
-		m.addActivity("info", "pairing role set to "+m.pairingRole)
-		return m.finalize(nil)
-	case key.Matches(keyMsg, m.keys.PairRoleNext):
-		m.pairingRole = nextPairingRole(m.currentPairingRole())
-		m.statusText = "approval role: " + m.pairingRole
-		m.errorText = ""
-		m.addActivity("info", "pairing role set to "+m.pairingRole)
+	case key.Matches(keyMsg, m.keys.SearchClose):
+		m.searchOpen = false
+		m.searchInput.Blur()
+		m.statusText = strings.ToLower(string(m.activeView)) + " view"
+		return m.finalize(m.applyFocusCmd())
+	case key.Matches(keyMsg, m.keys.SearchUp):
+		if m.searchCursor > 0 {
+			m.searchCursor--
+		}
 		return m.finalize(nil)
-	case key.Matches(keyMsg, m.keys.PairingNew):
-		m.activePair = nil
-		m.approvedMsg = nil
-		m.tokenInput.SetValue("")
-		m.statusText = "new token"
-		m.errorText = ""
-		m.addActivity("info", "ready for new pairing token")
+	case key.Matches(keyMsg, m.keys.SearchDown):
+		if m.searchCursor < len(m.searchResults)-1 {
+			m.searchCursor++
+		}
 		return m.finalize(nil)
 	case key.Matches(keyMsg, m.keys.Activate):
-		if m.activePair != nil {
-			return m.finalize(nil)
-		}
-		token := normalizePairingToken(m.tokenInput.Value())
-		if token == "" || m.busy() {
+		selected, ok := m.selectedSearchResult()
+		if !ok {
 			return m.finalize(nil)
 		}
-		m.tokenInput.SetValue(token)
-		cmds = append(cmds, m.beginLoad(1, "loading token..."), m.lookupPairingCmd(token))
-		return m.finalize(batchCmds(cmds...))
-	case key.Matches(keyMsg, m.keys.PairApprove):
-		if m.activePair == nil || m.busy() {
-			return m.finalize(nil)
+		return m.openSearchResult(selected)
+	}
+
+	before := m.searchInput.Value()
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(keyMsg)
+	if m.searchInput.Value() == before {
+		return m.finalize(cmd)
+	}
+	m.debounceSequence++
+	if strings.TrimSpace(m.searchInput.Value()) == "" {
+		m.searchResults = nil
+		m.searchCursor = 0
+		return m.finalize(cmd)
+	}
+	return m.finalize(batchCmds(cmd, searchDebounceCmd(m.debounceSequence, m.searchInput.Value())))
+}
+
+func (m model) updateWorkspacePickerKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMsg, m.keys.SearchClose), key.Matches(keyMsg, m.keys.Workspaces):
+		m.workspacePickerOpen = false
+		m.statusText = strings.ToLower(string(m.activeView)) + " view"
+		return m.finalize(nil)
+	case key.Matches(keyMsg, m.keys.Up):
+		if m.workspaceCursor > 0 {
+			m.workspaceCursor--
+		}
+		return m.finalize(nil)
+	case key.Matches(keyMsg, m.keys.Down):
+		if m.workspaceCursor < len(m.workspaces)-1 {
+			m.workspaceCursor++
+		}
+		return m.finalize(nil)
+	case key.Matches(keyMsg, m.keys.Activate):
+		selected, ok := m.selectedWorkspace()
+		if !ok {
+			return m.finalize(nil)
+		}
+		return m.switchWorkspace(selected.ID)
+	}
+	return m.finalize(nil)
+}
+
+// switchWorkspace points every view at workspaceID. Rows of the previous
+// workspace are dropped so they never show under the new filter, even when
+// the reload is skipped because other loads are in flight.
+func (m model) switchWorkspace(workspaceID string) (tea.Model, tea.Cmd) {
+	m.workspacePickerOpen = false
+	for _, input := range []*textinput.Model{
+		&m.objectiveWorkspaceInput,
+		&m.taskWorkspaceInput,
+		&m.trashWorkspaceInput,
+		&m.caseWorkspaceInput,
+		&m.traceWorkspaceInput,
+	} {
+		input.SetValue(workspaceID)
+	}
+	m.clearWorkspaceRows()
+	m.statusText = "workspace: " + workspaceID
+	m.errorText = ""
+	m.addActivity("info", "switched workspace to "+workspaceID)
+	refresh := m.refreshViewAndOverviewCmd("workspace switch", true)
+	if refresh == nil {
+		m.statusText = "workspace: " + workspaceID + " (press r to reload)"
+	}
+	return m.finalize(refresh)
+}
+
+func (m *model) clearWorkspaceRows() {
+	m.objectives = nil
+	m.tasks = nil
+	m.taskResult = nil
+	m.trash = nil
+	m.cases = nil
+	m.caseDetail = nil
+	m.chatLogs = nil
+	m.traceEntries = nil
+	m.traceChat = ""
+	m.rebuildObjectiveRows()
+	m.rebuildTaskRows()
+	m.rebuildTrashRows()
+	m.rebuildCaseRows()
+	m.rebuildChatLogRows()
+}
+
+// switchProfile points the TUI at the next runtime profile with a fresh
+// admin client. It waits for pending requests so no response of the old
+// runtime lands in the new one's views.
+func (m model) switchProfile() (tea.Model, tea.Cmd) {
+	if len(m.profiles) < 2 {
+		m.statusText = "no other runtime profiles; set AGENT_RUNTIME_TUI_PROFILES"
+		return m.finalize(nil)
+	}
+	if m.busy() {
+		m.errorText = "wait for pending requests before switching runtime"
+		return m.finalize(nil)
+	}
+	next := (m.profileIndex + 1) % len(m.profiles)
+	profile := m.profiles[next]
+	cfg := profile.Apply(m.cfg)
+	client, err := adminclient.New(cfg)
+	if err != nil {
+		m.errorText = "runtime " + profile.Name + ": " + err.Error()
+		m.addActivity("error", "runtime switch to "+profile.Name+" failed: "+err.Error())
+		return m.finalize(nil)
+	}
+	m.cfg = cfg
+	m.client = client
+	m.profileIndex = next
+	m.clearWorkspaceRows()
+	m.approvals = nil
+	m.rebuildApprovalRows()
+	m.activePair = nil
+	m.approvedMsg = nil
+	m.taskRetryMsg = nil
+	m.objectiveForm = objectiveForm{}
+	m.workspaces = nil
+	m.searchResults = nil
+	m.dashboard = dashboardStats{}
+	m.statusText = "runtime: " + profile.Name + " (" + profile.AdminAPIURL + ")"
+	m.errorText = ""
+	m.addActivity("warn", "switched runtime to "+profile.Name+" at "+profile.AdminAPIURL)
+	return m.finalize(batchCmds(m.applyFocusCmd(), m.refreshViewAndOverviewCmd("runtime switch", true), m.startLiveCmd()))
+}
+
+func (m model) profileName() string {
+	if m.profileIndex < 0 || m.profileIndex >= len(m.profiles) {
+		return config.DefaultTUIProfileName
+	}
+	return m.profiles[m.profileIndex].Name
+}
+
+// currentWorkspaceID is the workspace filter of the active view, or the
+// objectives one for views without a filter.
+func (m model) currentWorkspaceID() string {
+	switch m.activeView {
+	case viewTasks:
+		return strings.TrimSpace(m.taskWorkspaceInput.Value())
+	case viewTrash:
+		return strings.TrimSpace(m.trashWorkspaceInput.Value())
+	case viewCases:
+		return strings.TrimSpace(m.caseWorkspaceInput.Value())
+	case viewTrace:
+		return strings.TrimSpace(m.traceWorkspaceInput.Value())
+	default:
+		return strings.TrimSpace(m.objectiveWorkspaceInput.Value())
+	}
+}
+
+// openSearchResult jumps to the view that lists a result. Approvals and
+// audit events have no view of their own, so they stay in the palette and
+// show in the inspector.
+func (m model) openSearchResult(result adminclient.SearchResult) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	var target viewID
+	switch result.Kind {
+	case "task":
+		target = viewTasks
+		m.taskWorkspaceInput.SetValue(result.WorkspaceID)
+		m.taskStatusFilter = ""
+		m.pendingTaskID = result.ID
+	case "objective":
+		target = viewObjectives
+		m.objectiveWorkspaceInput.SetValue(result.WorkspaceID)
+		m.pendingObjectiveID = result.ID
+	default:
+		m.statusText = result.Kind + " details are shown in the inspector"
+		return m.finalize(nil)
+	}
+	m.searchOpen = false
+	m.searchInput.Blur()
+	m.focus = focusWorkbench
+	// activateView skips its refresh while other loads are in flight, and
+	// those loads are for the previous workspace, so queue the list here.
+	if m.busy() {
+		switch target {
+		case viewTasks:
+			cmds = append(cmds, m.beginLoad(1, "loading tasks..."), m.listTasksCmd(result.WorkspaceID, "", "search"))
+		case viewObjectives:
+			cmds = append(cmds, m.beginLoad(1, "loading objectives..."), m.listObjectivesCmd(result.WorkspaceID, "search"))
+		}
+	}
+	cmds = append(cmds, m.activateView(target))
+	m.addActivity("info", "opened "+result.Kind+" from search: "+result.ID)
+	return m.finalize(batchCmds(cmds...))
+}
+
+func (m model) updatePairingsWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	switch {
+	case key.Matches(keyMsg, m.keys.PairRolePrev):
+		m.pairingRole = previousPairingRole(m.currentPairingRole())
+		m.statusText = "approval role: " + m.pairingRole
+		m.errorText = ""
+		m.addActivity("info", "pairing role set to "+m.pairingRole)
+		return m.finalize(nil)
+	case key.Matches(keyMsg, m.keys.PairRoleNext):
+		m.pairingRole = nextPairingRole(m.currentPairingRole())
+		m.statusText = "approval role: " + m.pairingRole
+		m.errorText = ""
+		m.addActivity("info", "pairing role set to "+m.pairingRole)
+		return m.finalize(nil)
+	case key.Matches(keyMsg, m.keys.PairingNew):
+		m.activePair = nil
+		m.approvedMsg = nil
+		m.tokenInput.SetValue("")
+		m.statusText = "new token"
+		m.errorText = ""
+		m.addActivity("info", "ready for new pairing token")
+		return m.finalize(nil)
+	case key.Matches(keyMsg, m.keys.Activate):
+		if m.activePair != nil {
+			return m.finalize(nil)
+		}
+		token := normalizePairingToken(m.tokenInput.Value())
+		if token == "" || m.busy() {
+			return m.finalize(nil)
+		}
+		m.tokenInput.SetValue(token)
+		cmds = append(cmds, m.beginLoad(1, "loading token..."), m.lookupPairingCmd(token))
+		return m.finalize(batchCmds(cmds...))
+	case key.Matches(keyMsg, m.keys.PairApprove):
+		if m.activePair == nil || m.busy() {
+			return m.finalize(nil)
 		}
 		token := normalizePairingToken(m.tokenInput.Value())
 		if token == "" {
@@ -681,15 +1451,56 @@ func (m model) updateObjectivesWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.C
 		if !ok || m.busy() {
 			return m.finalize(nil)
 		}
-		cmds = append(cmds, m.beginMutation(1, "updating objective state..."), m.setObjectiveActiveCmd(selected.ID, !selected.Active))
+		cmds = append(cmds, m.beginMutation(1, "updating objective state..."), m.setObjectiveActiveCmd(selected.ID, !selected.Active, selected.Revision))
+		return m.finalize(batchCmds(cmds...))
+	}
+	if key.Matches(keyMsg, m.keys.ObjectiveRun) {
+		selected, ok := m.selectedObjective()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginMutation(1, "queueing objective run..."), m.runObjectiveCmd(selected.ID))
 		return m.finalize(batchCmds(cmds...))
 	}
+	if key.Matches(keyMsg, m.keys.ObjectiveHistory) {
+		selected, ok := m.selectedObjective()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		if m.objectiveRuns != nil && m.objectiveRuns.objectiveID == selected.ID {
+			m.objectiveRuns = nil
+			m.statusText = "objective detail"
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginLoad(1, "loading objective runs..."), m.listObjectiveRunsCmd(selected.ID))
+		return m.finalize(batchCmds(cmds...))
+	}
+	if key.Matches(keyMsg, m.keys.ObjectiveNew) {
+		contextID := ""
+		if selected, ok := m.selectedObjective(); ok {
+			contextID = selected.ContextID
+		}
+		m.objectiveForm = newObjectiveForm(strings.TrimSpace(m.objectiveWorkspaceInput.Value()), contextID)
+		m.statusText = "new objective: fill the fields, ctrl+s to save"
+		m.errorText = ""
+		return m.finalize(m.applyFocusCmd())
+	}
+	if key.Matches(keyMsg, m.keys.ObjectiveEdit) {
+		selected, ok := m.selectedObjective()
+		if !ok {
+			return m.finalize(nil)
+		}
+		m.objectiveForm = editObjectiveForm(selected)
+		m.statusText = "editing objective: ctrl+s to save"
+		m.errorText = ""
+		return m.finalize(m.applyFocusCmd())
+	}
 	if key.Matches(keyMsg, m.keys.ObjectiveDelete) {
 		selected, ok := m.selectedObjective()
 		if !ok || m.busy() {
 			return m.finalize(nil)
 		}
-		cmds = append(cmds, m.beginMutation(1, "deleting objective..."), m.deleteObjectiveCmd(selected.ID))
+		cmds = append(cmds, m.beginMutation(1, "moving objective to trash..."), m.deleteObjectiveCmd(selected.ID, selected.Revision))
 		return m.finalize(batchCmds(cmds...))
 	}
 
@@ -705,6 +1516,46 @@ func (m model) updateObjectivesWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.C
 	return m.finalize(cmd)
 }
 
+// updateObjectiveFormKey drives the open objective form. Enter moves to the
+// next field and saves from the last one.
+func (m model) updateObjectiveFormKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(keyMsg, m.keys.FormCancel):
+		m.objectiveForm = objectiveForm{}
+		m.statusText = "objectives view"
+		m.errorText = ""
+		return m.finalize(m.applyFocusCmd())
+	case key.Matches(keyMsg, m.keys.FormNext):
+		m.objectiveForm.move(1)
+		return m.finalize(m.objectiveForm.focusCmd())
+	case key.Matches(keyMsg, m.keys.FormPrev):
+		m.objectiveForm.move(-1)
+		return m.finalize(m.objectiveForm.focusCmd())
+	case key.Matches(keyMsg, m.keys.Activate) && m.objectiveForm.focus < formFieldCount-1:
+		m.objectiveForm.move(1)
+		return m.finalize(m.objectiveForm.focusCmd())
+	case key.Matches(keyMsg, m.keys.FormSubmit), key.Matches(keyMsg, m.keys.Activate):
+		if m.busy() {
+			return m.finalize(nil)
+		}
+		if m.objectiveForm.editing() {
+			input, err := m.objectiveForm.updateRequest()
+			if err != nil {
+				m.errorText = err.Error()
+				return m.finalize(nil)
+			}
+			return m.finalize(batchCmds(m.beginMutation(1, "saving objective..."), m.updateObjectiveCmd(input)))
+		}
+		input, err := m.objectiveForm.createRequest()
+		if err != nil {
+			m.errorText = err.Error()
+			return m.finalize(nil)
+		}
+		return m.finalize(batchCmds(m.beginMutation(1, "creating objective..."), m.createObjectiveCmd(input)))
+	}
+	return m.finalize(m.objectiveForm.update(keyMsg))
+}
+
 func (m model) updateTasksWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	if key.Matches(keyMsg, m.keys.Up) {
@@ -741,6 +1592,23 @@ func (m model) updateTasksWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.addActivity("info", "task filter set to "+taskFilterLabel(m.taskStatusFilter))
 		return m.finalize(batchCmds(cmds...))
 	}
+	if key.Matches(keyMsg, m.keys.TaskResult) {
+		selected, ok := m.selectedTask()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		if m.taskResult != nil && m.taskResult.TaskID == selected.ID {
+			m.taskResult = nil
+			m.statusText = "task detail"
+			return m.finalize(nil)
+		}
+		if strings.TrimSpace(selected.ResultPath) == "" {
+			m.errorText = "task has no result file yet"
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginLoad(1, "loading task result..."), m.getTaskResultCmd(selected.ID))
+		return m.finalize(batchCmds(cmds...))
+	}
 	if key.Matches(keyMsg, m.keys.TaskRetry) {
 		selected, ok := m.selectedTask()
 		if !ok || m.busy() {
@@ -753,6 +1621,33 @@ func (m model) updateTasksWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.beginMutation(1, "retrying task..."), m.retryTaskCmd(selected.ID))
 		return m.finalize(batchCmds(cmds...))
 	}
+	if key.Matches(keyMsg, m.keys.TaskCancel) {
+		selected, ok := m.selectedTask()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		switch strings.ToLower(strings.TrimSpace(selected.Status)) {
+		case "queued", "running":
+		default:
+			m.errorText = "only queued or running tasks can be cancelled"
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginMutation(1, "cancelling task..."), m.cancelTaskCmd(selected.ID))
+		return m.finalize(batchCmds(cmds...))
+	}
+	if key.Matches(keyMsg, m.keys.TaskDelete) {
+		selected, ok := m.selectedTask()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		switch strings.ToLower(strings.TrimSpace(selected.Status)) {
+		case "queued", "running":
+			m.errorText = "queued or running tasks cannot be trashed"
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginMutation(1, "moving task to trash..."), m.deleteTaskCmd(selected.ID, selected.Revision))
+		return m.finalize(batchCmds(cmds...))
+	}
 	if key.Matches(keyMsg, m.keys.Activate) {
 		workspaceID := strings.TrimSpace(m.taskWorkspaceInput.Value())
 		if workspaceID == "" || m.busy() {
@@ -774,6 +1669,183 @@ func (m model) updateTasksWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m.finalize(cmd)
 }
 
+func (m model) updateTrashWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	if key.Matches(keyMsg, m.keys.Up) {
+		m.trashTable.MoveUp(1)
+		return m.finalize(nil)
+	}
+	if key.Matches(keyMsg, m.keys.Down) {
+		m.trashTable.MoveDown(1)
+		return m.finalize(nil)
+	}
+	if key.Matches(keyMsg, m.keys.TrashRestore) {
+		selected, ok := m.selectedTrashItem()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginMutation(1, "restoring "+selected.Kind+"..."), m.restoreTrashItemCmd(selected))
+		return m.finalize(batchCmds(cmds...))
+	}
+	if key.Matches(keyMsg, m.keys.Activate) {
+		workspaceID := strings.TrimSpace(m.trashWorkspaceInput.Value())
+		if workspaceID == "" || m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginLoad(1, "loading trash..."), m.listTrashCmd(workspaceID, "manual"))
+		return m.finalize(batchCmds(cmds...))
+	}
+
+	before := m.trashWorkspaceInput.Value()
+	var cmd tea.Cmd
+	m.trashWorkspaceInput, cmd = m.trashWorkspaceInput.Update(keyMsg)
+	m.trashWorkspaceInput.SetValue(sanitizeWorkspaceID(m.trashWorkspaceInput.Value()))
+	if m.trashWorkspaceInput.Value() != before {
+		m.debounceSequence++
+		cmds = append(cmds, cmd, workspaceDebounceCmd(m.debounceSequence, viewTrash, m.trashWorkspaceInput.Value()))
+		return m.finalize(batchCmds(cmds...))
+	}
+	return m.finalize(cmd)
+}
+
+func (m model) updateCasesWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	if key.Matches(keyMsg, m.keys.Up) {
+		m.casesTable.MoveUp(1)
+		return m.finalize(nil)
+	}
+	if key.Matches(keyMsg, m.keys.Down) {
+		m.casesTable.MoveDown(1)
+		return m.finalize(nil)
+	}
+	if key.Matches(keyMsg, m.keys.CaseDetail) {
+		selected, ok := m.selectedCase()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginLoad(1, "loading case items..."), m.getCaseCmd(selected.ID))
+		return m.finalize(batchCmds(cmds...))
+	}
+	if key.Matches(keyMsg, m.keys.CaseToggle) {
+		selected, ok := m.selectedCase()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		status := "resolved"
+		if selected.Status == "resolved" {
+			status = "open"
+		}
+		cmds = append(cmds, m.beginMutation(1, "updating case..."), m.updateCaseCmd(selected.ID, status))
+		return m.finalize(batchCmds(cmds...))
+	}
+	if key.Matches(keyMsg, m.keys.Activate) {
+		workspaceID := strings.TrimSpace(m.caseWorkspaceInput.Value())
+		if workspaceID == "" || m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginLoad(1, "loading cases..."), m.listCasesCmd(workspaceID, "manual"))
+		return m.finalize(batchCmds(cmds...))
+	}
+
+	before := m.caseWorkspaceInput.Value()
+	var cmd tea.Cmd
+	m.caseWorkspaceInput, cmd = m.caseWorkspaceInput.Update(keyMsg)
+	m.caseWorkspaceInput.SetValue(sanitizeWorkspaceID(m.caseWorkspaceInput.Value()))
+	if m.caseWorkspaceInput.Value() != before {
+		m.debounceSequence++
+		cmds = append(cmds, cmd, workspaceDebounceCmd(m.debounceSequence, viewCases, m.caseWorkspaceInput.Value()))
+		return m.finalize(batchCmds(cmds...))
+	}
+	return m.finalize(cmd)
+}
+
+func (m model) updateApprovalsWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	switch {
+	case key.Matches(keyMsg, m.keys.Up):
+		m.approvalsTable.MoveUp(1)
+		return m.finalize(nil)
+	case key.Matches(keyMsg, m.keys.Down):
+		m.approvalsTable.MoveDown(1)
+		return m.finalize(nil)
+	case key.Matches(keyMsg, m.keys.ApprovalApprove):
+		selected, ok := m.selectedApproval()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginMutation(1, "approving action..."), m.approveActionCmd(selected.ID))
+		return m.finalize(batchCmds(cmds...))
+	case key.Matches(keyMsg, m.keys.ApprovalDeny):
+		selected, ok := m.selectedApproval()
+		if !ok || m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginMutation(1, "denying action..."), m.denyActionCmd(selected.ID))
+		return m.finalize(batchCmds(cmds...))
+	case key.Matches(keyMsg, m.keys.Activate):
+		if m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginLoad(1, "loading approvals..."), m.listApprovalsCmd("manual"))
+		return m.finalize(batchCmds(cmds...))
+	}
+	return m.finalize(nil)
+}
+
+func (m model) updateTraceWorkbenchKey(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	if key.Matches(keyMsg, m.keys.Up) || key.Matches(keyMsg, m.keys.Down) {
+		if key.Matches(keyMsg, m.keys.Up) {
+			m.chatLogsTable.MoveUp(1)
+		} else {
+			m.chatLogsTable.MoveDown(1)
+		}
+		if selected, ok := m.selectedChatLog(); ok && chatLogKey(selected) != m.traceChat {
+			m.traceChat = ""
+			m.traceEntries = nil
+			m.traceInFlight = false
+			cmds = append(cmds, m.traceTailCmd())
+		}
+		return m.finalize(batchCmds(cmds...))
+	}
+	if key.Matches(keyMsg, m.keys.Activate) {
+		workspaceID := strings.TrimSpace(m.traceWorkspaceInput.Value())
+		if workspaceID == "" || m.busy() {
+			return m.finalize(nil)
+		}
+		cmds = append(cmds, m.beginLoad(1, "loading chats..."), m.listChatLogsCmd(workspaceID, "manual"))
+		return m.finalize(batchCmds(cmds...))
+	}
+
+	before := m.traceWorkspaceInput.Value()
+	var cmd tea.Cmd
+	m.traceWorkspaceInput, cmd = m.traceWorkspaceInput.Update(keyMsg)
+	m.traceWorkspaceInput.SetValue(sanitizeWorkspaceID(m.traceWorkspaceInput.Value()))
+	if m.traceWorkspaceInput.Value() != before {
+		m.chatLogs = nil
+		m.traceChat = ""
+		m.traceEntries = nil
+		m.rebuildChatLogRows()
+		m.debounceSequence++
+		cmds = append(cmds, cmd, workspaceDebounceCmd(m.debounceSequence, viewTrace, m.traceWorkspaceInput.Value()))
+		return m.finalize(batchCmds(cmds...))
+	}
+	return m.finalize(cmd)
+}
+
+// traceTailCmd fetches the newest entries of the selected chat log unless a
+// fetch is already out. The tail is not counted as a load so following a
+// chat neither spins the spinner nor holds back the regular refresh.
+func (m *model) traceTailCmd() tea.Cmd {
+	workspaceID := strings.TrimSpace(m.traceWorkspaceInput.Value())
+	selected, ok := m.selectedChatLog()
+	if !ok || workspaceID == "" || m.traceInFlight {
+		return nil
+	}
+	m.traceInFlight = true
+	return m.tailChatLogCmd(workspaceID, selected)
+}
+
 func (m *model) refreshForPollCmd() tea.Cmd {
 	if m.pendingMutations > 0 {
 		return nil
@@ -825,6 +1897,20 @@ func (m *model) refreshViewAndOverviewCmd(reason string, trackActivity bool) tea
 		if taskWS != "" {
 			addLoad("load", "tasks:"+taskWS+":"+m.taskStatusFilter+":active", m.listTasksCmd(taskWS, m.taskStatusFilter, "active-view"))
 		}
+	case viewTrash:
+		if trashWS := strings.TrimSpace(m.trashWorkspaceInput.Value()); trashWS != "" {
+			addLoad("load", "trash:"+trashWS, m.listTrashCmd(trashWS, "active-view"))
+		}
+	case viewCases:
+		if caseWS := strings.TrimSpace(m.caseWorkspaceInput.Value()); caseWS != "" {
+			addLoad("load", "cases:"+caseWS, m.listCasesCmd(caseWS, "active-view"))
+		}
+	case viewApprovals:
+		addLoad("load", "approvals", m.listApprovalsCmd("active-view"))
+	case viewTrace:
+		if traceWS := strings.TrimSpace(m.traceWorkspaceInput.Value()); traceWS != "" {
+			addLoad("load", "chatlogs:"+traceWS, m.listChatLogsCmd(traceWS, "active-view"))
+		}
 	}
 
 	if len(requests) == 0 {
@@ -889,9 +1975,19 @@ func (m model) busy() bool {
 func (m *model) applyFocusCmd() tea.Cmd {
 	m.objectivesTable.Blur()
 	m.tasksTable.Blur()
+	m.trashTable.Blur()
+	m.casesTable.Blur()
+	m.approvalsTable.Blur()
+	m.chatLogsTable.Blur()
 	m.tokenInput.Blur()
 	m.objectiveWorkspaceInput.Blur()
 	m.taskWorkspaceInput.Blur()
+	m.trashWorkspaceInput.Blur()
+	m.caseWorkspaceInput.Blur()
+	m.traceWorkspaceInput.Blur()
+	for index := range m.objectiveForm.inputs {
+		m.objectiveForm.inputs[index].Blur()
+	}
 
 	cmds := make([]tea.Cmd, 0, 3)
 
@@ -900,11 +1996,26 @@ func (m *model) applyFocusCmd() tea.Cmd {
 		case viewPairings:
 			cmds = append(cmds, m.tokenInput.Focus())
 		case viewObjectives:
+			if m.objectiveForm.open {
+				cmds = append(cmds, m.objectiveForm.focusCmd())
+				break
+			}
 			m.objectivesTable.Focus()
 			cmds = append(cmds, m.objectiveWorkspaceInput.Focus())
 		case viewTasks:
 			m.tasksTable.Focus()
 			cmds = append(cmds, m.taskWorkspaceInput.Focus())
+		case viewTrash:
+			m.trashTable.Focus()
+			cmds = append(cmds, m.trashWorkspaceInput.Focus())
+		case viewCases:
+			m.casesTable.Focus()
+			cmds = append(cmds, m.caseWorkspaceInput.Focus())
+		case viewApprovals:
+			m.approvalsTable.Focus()
+		case viewTrace:
+			m.chatLogsTable.Focus()
+			cmds = append(cmds, m.traceWorkspaceInput.Focus())
 		}
 	}
 	return batchCmds(cmds...)
@@ -915,16 +2026,14 @@ func (m *model) applyVisualStyles() {
 
 	m.spinner.Style = t.spinner
 
-	inputStyles := textinput.DefaultDarkStyles()
-	inputStyles.Focused.Prompt = t.inputPrompt
-	inputStyles.Focused.Text = t.inputText
-	inputStyles.Focused.Placeholder = t.inputPlaceholder
-	inputStyles.Blurred.Prompt = t.inputPrompt
-	inputStyles.Blurred.Text = t.inputText
-	inputStyles.Blurred.Placeholder = t.inputPlaceholder
+	inputStyles := newInputStyles(t)
 	m.tokenInput.SetStyles(inputStyles)
 	m.objectiveWorkspaceInput.SetStyles(inputStyles)
 	m.taskWorkspaceInput.SetStyles(inputStyles)
+	m.trashWorkspaceInput.SetStyles(inputStyles)
+	m.caseWorkspaceInput.SetStyles(inputStyles)
+	m.traceWorkspaceInput.SetStyles(inputStyles)
+	m.searchInput.SetStyles(inputStyles)
 
 	tableStyles := table.DefaultStyles()
 	tableStyles.Header = t.tableHeader
@@ -932,6 +2041,10 @@ func (m *model) applyVisualStyles() {
 	tableStyles.Selected = t.tableSelected
 	m.objectivesTable.SetStyles(tableStyles)
 	m.tasksTable.SetStyles(tableStyles)
+	m.trashTable.SetStyles(tableStyles)
+	m.casesTable.SetStyles(tableStyles)
+	m.approvalsTable.SetStyles(tableStyles)
+	m.chatLogsTable.SetStyles(tableStyles)
 
 	m.help.Styles.Ellipsis = t.footerInfo
 	m.help.Styles.ShortKey = t.footerKey
@@ -942,6 +2055,17 @@ func (m *model) applyVisualStyles() {
 	m.help.Styles.FullSeparator = t.panelSubtle
 }
 
+func newInputStyles(t theme) textinput.Styles {
+	inputStyles := textinput.DefaultDarkStyles()
+	inputStyles.Focused.Prompt = t.inputPrompt
+	inputStyles.Focused.Text = t.inputText
+	inputStyles.Focused.Placeholder = t.inputPlaceholder
+	inputStyles.Blurred.Prompt = t.inputPrompt
+	inputStyles.Blurred.Text = t.inputText
+	inputStyles.Blurred.Placeholder = t.inputPlaceholder
+	return inputStyles
+}
+
 func (m *model) resizeWidgets() {
 	layout := computeLayout(m.width, m.height)
 
@@ -960,13 +2084,30 @@ func (m *model) resizeWidgets() {
 	m.tokenInput.SetWidth(maxInt(12, mainWidth-10))
 	m.objectiveWorkspaceInput.SetWidth(maxInt(8, mainWidth-14))
 	m.taskWorkspaceInput.SetWidth(maxInt(8, mainWidth-14))
+	m.trashWorkspaceInput.SetWidth(maxInt(8, mainWidth-14))
+	m.caseWorkspaceInput.SetWidth(maxInt(8, mainWidth-14))
+	m.traceWorkspaceInput.SetWidth(maxInt(8, mainWidth-14))
+	m.searchInput.SetWidth(maxInt(8, mainWidth-14))
+	m.objectiveForm.setWidth(maxInt(8, mainWidth-16))
 
 	m.setObjectiveColumns(mainWidth)
 	m.setTaskColumns(mainWidth)
+	m.setTrashColumns(mainWidth)
+	m.setCaseColumns(mainWidth)
+	m.setApprovalColumns(mainWidth)
+	m.setChatLogColumns(mainWidth)
 	m.objectivesTable.SetWidth(mainWidth)
 	m.tasksTable.SetWidth(mainWidth)
+	m.trashTable.SetWidth(mainWidth)
+	m.casesTable.SetWidth(mainWidth)
+	m.approvalsTable.SetWidth(mainWidth)
+	m.chatLogsTable.SetWidth(mainWidth)
 	m.objectivesTable.SetHeight(mainHeight)
 	m.tasksTable.SetHeight(mainHeight)
+	m.trashTable.SetHeight(mainHeight)
+	m.casesTable.SetHeight(mainHeight)
+	m.approvalsTable.SetHeight(mainHeight)
+	m.chatLogsTable.SetHeight(mainHeight)
 
 	m.inspectorViewport.SetWidth(maxInt(16, inspectorWidth))
 	m.inspectorViewport.SetHeight(maxInt(4, inspectorHeight))
@@ -1029,6 +2170,108 @@ func (m *model) setTaskColumns(mainWidth int) {
 	m.tasksTable.SetColumns(columns)
 }
 
+func (m *model) setTrashColumns(mainWidth int) {
+	usable := maxInt(24, mainWidth-8) // 4 columns * 2 padding
+	kindWidth := 9
+	deletedWidth := 18
+	purgeWidth := 18
+	titleWidth := usable - kindWidth - deletedWidth - purgeWidth
+
+	if titleWidth < 12 {
+		purgeWidth = maxInt(10, usable-kindWidth-deletedWidth-12)
+		titleWidth = usable - kindWidth - deletedWidth - purgeWidth
+	}
+	if titleWidth < 8 {
+		titleWidth = 8
+	}
+	purgeWidth = maxInt(10, usable-titleWidth-kindWidth-deletedWidth)
+
+	columns := []table.Column{
+		{Title: "Title", Width: titleWidth},
+		{Title: "Kind", Width: kindWidth},
+		{Title: "Deleted", Width: deletedWidth},
+		{Title: "Purge", Width: purgeWidth},
+	}
+	m.trashTable.SetColumns(columns)
+}
+
+func (m *model) setCaseColumns(mainWidth int) {
+	usable := maxInt(24, mainWidth-10) // 5 columns * 2 padding
+	kindWidth := 10
+	statusWidth := 9
+	ownerWidth := 12
+	updatedWidth := 18
+	titleWidth := usable - kindWidth - statusWidth - ownerWidth - updatedWidth
+
+	if titleWidth < 12 {
+		ownerWidth = maxInt(6, usable-kindWidth-statusWidth-updatedWidth-12)
+		titleWidth = usable - kindWidth - statusWidth - ownerWidth - updatedWidth
+	}
+	if titleWidth < 8 {
+		titleWidth = 8
+	}
+	updatedWidth = maxInt(10, usable-titleWidth-kindWidth-statusWidth-ownerWidth)
+
+	columns := []table.Column{
+		{Title: "Title", Width: titleWidth},
+		{Title: "Kind", Width: kindWidth},
+		{Title: "Status", Width: statusWidth},
+		{Title: "Owner", Width: ownerWidth},
+		{Title: "Updated", Width: updatedWidth},
+	}
+	m.casesTable.SetColumns(columns)
+}
+
+func (m *model) setApprovalColumns(mainWidth int) {
+	usable := maxInt(24, mainWidth-10) // 5 columns * 2 padding
+	riskWidth := 7
+	ageWidth := 6
+	contextWidth := 14
+	idWidth := 14
+	summaryWidth := usable - riskWidth - ageWidth - contextWidth - idWidth
+
+	if summaryWidth < 12 {
+		contextWidth = maxInt(6, usable-riskWidth-ageWidth-idWidth-12)
+		summaryWidth = usable - riskWidth - ageWidth - contextWidth - idWidth
+	}
+	if summaryWidth < 8 {
+		summaryWidth = 8
+	}
+	idWidth = maxInt(8, usable-summaryWidth-riskWidth-ageWidth-contextWidth)
+
+	columns := []table.Column{
+		{Title: "Summary", Width: summaryWidth},
+		{Title: "Risk", Width: riskWidth},
+		{Title: "Age", Width: ageWidth},
+		{Title: "Context", Width: contextWidth},
+		{Title: "ID", Width: idWidth},
+	}
+	m.approvalsTable.SetColumns(columns)
+}
+
+func (m *model) setChatLogColumns(mainWidth int) {
+	usable := maxInt(24, mainWidth-6) // 3 columns * 2 padding
+	connectorWidth := 10
+	updatedWidth := 18
+	chatWidth := usable - connectorWidth - updatedWidth
+
+	if chatWidth < 12 {
+		updatedWidth = maxInt(10, usable-connectorWidth-12)
+		chatWidth = usable - connectorWidth - updatedWidth
+	}
+	if chatWidth < 8 {
+		chatWidth = 8
+	}
+	updatedWidth = maxInt(10, usable-chatWidth-connectorWidth)
+
+	columns := []table.Column{
+		{Title: "Connector", Width: connectorWidth},
+		{Title: "Chat", Width: chatWidth},
+		{Title: "Updated", Width: updatedWidth},
+	}
+	m.chatLogsTable.SetColumns(columns)
+}
+
 func (m *model) rebuildObjectiveRows() {
 	rows := make([]table.Row, 0, len(m.objectives))
 	for _, item := range m.objectives {
@@ -1039,10 +2282,62 @@ func (m *model) rebuildObjectiveRows() {
 		nextRun := formatUnixPtr(item.NextRunUnix)
 		rows = append(rows, table.Row{item.Title, state, item.TriggerType, nextRun})
 	}
-	cursor := m.objectivesTable.Cursor()
-	m.objectivesTable.SetRows(rows)
+	cursor := m.objectivesTable.Cursor()
+	m.objectivesTable.SetRows(rows)
+	if len(rows) == 0 {
+		m.objectivesTable.SetCursor(0)
+		return
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	m.objectivesTable.SetCursor(cursor)
+}
+
+func (m *model) rebuildTaskRows() {
+	rows := make([]table.Row, 0, len(m.tasks))
+	byID := tasksByID(m.tasks)
+	for _, item := range m.tasks {
+		rows = append(rows, table.Row{
+			item.Title,
+			taskDisplayStatus(item, byID),
+			item.Kind,
+			strconv.Itoa(item.Attempts),
+			formatUnix(item.UpdatedAtUnix),
+		})
+	}
+	cursor := m.tasksTable.Cursor()
+	m.tasksTable.SetRows(rows)
+	if len(rows) == 0 {
+		m.tasksTable.SetCursor(0)
+		return
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	m.tasksTable.SetCursor(cursor)
+}
+
+func (m *model) rebuildTrashRows() {
+	rows := make([]table.Row, 0, len(m.trash))
+	for _, item := range m.trash {
+		rows = append(rows, table.Row{
+			item.Title,
+			item.Kind,
+			formatUnix(item.DeletedAtUnix),
+			formatUnix(item.PurgeAtUnix),
+		})
+	}
+	cursor := m.trashTable.Cursor()
+	m.trashTable.SetRows(rows)
 	if len(rows) == 0 {
-		m.objectivesTable.SetCursor(0)
+		m.trashTable.SetCursor(0)
 		return
 	}
 	if cursor < 0 {
@@ -1051,24 +2346,24 @@ func (m *model) rebuildObjectiveRows() {
 	if cursor >= len(rows) {
 		cursor = len(rows) - 1
 	}
-	m.objectivesTable.SetCursor(cursor)
+	m.trashTable.SetCursor(cursor)
 }
 
-func (m *model) rebuildTaskRows() {
-	rows := make([]table.Row, 0, len(m.tasks))
-	for _, item := range m.tasks {
+func (m *model) rebuildCaseRows() {
+	rows := make([]table.Row, 0, len(m.cases))
+	for _, item := range m.cases {
 		rows = append(rows, table.Row{
 			item.Title,
-			strings.ToLower(strings.TrimSpace(item.Status)),
 			item.Kind,
-			strconv.Itoa(item.Attempts),
+			item.Status,
+			fallbackText(item.OwnerUserID, "-"),
 			formatUnix(item.UpdatedAtUnix),
 		})
 	}
-	cursor := m.tasksTable.Cursor()
-	m.tasksTable.SetRows(rows)
+	cursor := m.casesTable.Cursor()
+	m.casesTable.SetRows(rows)
 	if len(rows) == 0 {
-		m.tasksTable.SetCursor(0)
+		m.casesTable.SetCursor(0)
 		return
 	}
 	if cursor < 0 {
@@ -1077,7 +2372,53 @@ func (m *model) rebuildTaskRows() {
 	if cursor >= len(rows) {
 		cursor = len(rows) - 1
 	}
-	m.tasksTable.SetCursor(cursor)
+	m.casesTable.SetCursor(cursor)
+}
+
+func (m *model) rebuildApprovalRows() {
+	rows := make([]table.Row, 0, len(m.approvals))
+	for _, item := range m.approvals {
+		rows = append(rows, table.Row{
+			approvalSummary(item),
+			fallbackText(item.RiskLevel, "-"),
+			approvalAge(item.CreatedAtUnix, m.clock),
+			fallbackText(item.ContextID, "-"),
+			item.ID,
+		})
+	}
+	cursor := m.approvalsTable.Cursor()
+	m.approvalsTable.SetRows(rows)
+	if len(rows) == 0 {
+		m.approvalsTable.SetCursor(0)
+		return
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	m.approvalsTable.SetCursor(cursor)
+}
+
+func (m *model) rebuildChatLogRows() {
+	rows := make([]table.Row, 0, len(m.chatLogs))
+	for _, item := range m.chatLogs {
+		rows = append(rows, table.Row{item.Connector, item.ExternalID, formatUnix(item.UpdatedAtUnix)})
+	}
+	cursor := m.chatLogsTable.Cursor()
+	m.chatLogsTable.SetRows(rows)
+	if len(rows) == 0 {
+		m.chatLogsTable.SetCursor(0)
+		return
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	m.chatLogsTable.SetCursor(cursor)
 }
 
 func (m *model) recomputeDashboardStats() {
@@ -1113,6 +2454,14 @@ func (m *model) recomputeDashboardStats() {
 }
 
 func (m *model) syncInspectorContent() {
+	if m.workspacePickerOpen {
+		m.inspectorViewport.SetContent(m.renderWorkspaceInspectorText())
+		return
+	}
+	if m.searchOpen {
+		m.inspectorViewport.SetContent(m.renderSearchInspectorText())
+		return
+	}
 	content := ""
 	switch m.activeView {
 	case viewPairings:
@@ -1121,6 +2470,21 @@ func (m *model) syncInspectorContent() {
 		content = m.renderObjectivesInspectorText()
 	case viewTasks:
 		content = m.renderTasksInspectorText()
+	case viewTrash:
+		content = m.renderTrashInspectorText()
+	case viewCases:
+		content = m.renderCasesInspectorText()
+	case viewApprovals:
+		content = m.renderApprovalsInspectorText()
+	case viewTrace:
+		// Stay pinned to the newest entry unless the operator has
+		// scrolled up to read older ones.
+		following := m.inspectorViewport.AtBottom()
+		m.inspectorViewport.SetContent(m.renderTraceInspectorText(following))
+		if following {
+			m.inspectorViewport.GotoBottom()
+		}
+		return
 	case viewActivity:
 		content = m.renderActivityInspectorText()
 	default:
@@ -1183,6 +2547,82 @@ func (m model) selectedTask() (adminclient.Task, bool) {
 	return m.tasks[cursor], true
 }
 
+func (m model) selectedTrashItem() (adminclient.TrashItem, bool) {
+	cursor := m.trashTable.Cursor()
+	if cursor < 0 || cursor >= len(m.trash) {
+		return adminclient.TrashItem{}, false
+	}
+	return m.trash[cursor], true
+}
+
+func (m model) selectedCase() (adminclient.Case, bool) {
+	cursor := m.casesTable.Cursor()
+	if cursor < 0 || cursor >= len(m.cases) {
+		return adminclient.Case{}, false
+	}
+	return m.cases[cursor], true
+}
+
+func (m model) selectedApproval() (adminclient.Approval, bool) {
+	cursor := m.approvalsTable.Cursor()
+	if cursor < 0 || cursor >= len(m.approvals) {
+		return adminclient.Approval{}, false
+	}
+	return m.approvals[cursor], true
+}
+
+func (m model) selectedChatLog() (adminclient.ChatLog, bool) {
+	cursor := m.chatLogsTable.Cursor()
+	if cursor < 0 || cursor >= len(m.chatLogs) {
+		return adminclient.ChatLog{}, false
+	}
+	return m.chatLogs[cursor], true
+}
+
+func (m model) selectedSearchResult() (adminclient.SearchResult, bool) {
+	if m.searchCursor < 0 || m.searchCursor >= len(m.searchResults) {
+		return adminclient.SearchResult{}, false
+	}
+	return m.searchResults[m.searchCursor], true
+}
+
+func (m model) selectedWorkspace() (adminclient.Workspace, bool) {
+	if m.workspaceCursor < 0 || m.workspaceCursor >= len(m.workspaces) {
+		return adminclient.Workspace{}, false
+	}
+	return m.workspaces[m.workspaceCursor], true
+}
+
+func (m *model) selectPendingTask() {
+	if m.pendingTaskID == "" {
+		return
+	}
+	for index, item := range m.tasks {
+		if item.ID == m.pendingTaskID {
+			m.tasksTable.SetCursor(index)
+			break
+		}
+	}
+	m.pendingTaskID = ""
+}
+
+func (m *model) selectPendingObjective() {
+	if m.pendingObjectiveID == "" {
+		return
+	}
+	for index, item := range m.objectives {
+		if item.ID == m.pendingObjectiveID {
+			m.objectivesTable.SetCursor(index)
+			break
+		}
+	}
+	m.pendingObjectiveID = ""
+}
+
+func (m model) editingPairingToken() bool {
+	return m.focus == focusWorkbench && m.activeView == viewPairings && m.activePair == nil
+}
+
 func (m model) currentPairingRole() string {
 	return normalizePairingRole(m.pairingRole)
 }
@@ -1214,11 +2654,34 @@ type objectiveActiveDoneMsg struct {
 	err  error
 }
 
+type objectiveSavedMsg struct {
+	item    adminclient.Objective
+	created bool
+	err     error
+}
+
 type objectiveDeleteDoneMsg struct {
 	id  string
 	err error
 }
 
+type objectiveRunDoneMsg struct {
+	response adminclient.RunObjectiveResponse
+	err      error
+}
+
+// objectiveRunHistory is the loaded run history of one objective.
+type objectiveRunHistory struct {
+	objectiveID string
+	items       []adminclient.ObjectiveRun
+}
+
+type objectiveRunsLoadedMsg struct {
+	objectiveID string
+	items       []adminclient.ObjectiveRun
+	err         error
+}
+
 type tasksLoadedMsg struct {
 	items       []adminclient.Task
 	workspaceID string
@@ -1232,6 +2695,86 @@ type taskRetryDoneMsg struct {
 	err      error
 }
 
+type taskCancelDoneMsg struct {
+	response adminclient.CancelTaskResponse
+	err      error
+}
+
+type taskDeleteDoneMsg struct {
+	id  string
+	err error
+}
+
+type searchLoadedMsg struct {
+	query string
+	items []adminclient.SearchResult
+	err   error
+}
+
+type workspacesLoadedMsg struct {
+	items []adminclient.Workspace
+	err   error
+}
+
+type trashLoadedMsg struct {
+	items       []adminclient.TrashItem
+	workspaceID string
+	source      string
+	err         error
+}
+
+type trashRestoreDoneMsg struct {
+	item adminclient.TrashItem
+	err  error
+}
+
+type casesLoadedMsg struct {
+	items       []adminclient.Case
+	workspaceID string
+	source      string
+	err         error
+}
+
+type caseDetailLoadedMsg struct {
+	item adminclient.Case
+	err  error
+}
+
+type caseUpdateDoneMsg struct {
+	item adminclient.Case
+	err  error
+}
+
+type taskResultLoadedMsg struct {
+	item adminclient.TaskResult
+	err  error
+}
+
+type approvalsLoadedMsg struct {
+	items  []adminclient.Approval
+	source string
+	err    error
+}
+
+type approvalDecisionDoneMsg struct {
+	item adminclient.Approval
+	err  error
+}
+
+type chatLogsLoadedMsg struct {
+	items       []adminclient.ChatLog
+	workspaceID string
+	source      string
+	err         error
+}
+
+type chatLogTailLoadedMsg struct {
+	workspaceID string
+	chat        string
+	items       []adminclient.ChatLogEntry
+	err         error
+}
+
 func (m model) lookupPairingCmd(token string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
@@ -1269,20 +2812,56 @@ func (m model) listObjectivesCmd(workspaceID, source string) tea.Cmd {
 	}
 }
 
-func (m model) setObjectiveActiveCmd(objectiveID string, active bool) tea.Cmd {
+func (m model) setObjectiveActiveCmd(objectiveID string, active bool, revision int) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
 		defer cancel()
-		item, err := m.client.SetObjectiveActive(ctx, objectiveID, active)
+		item, err := m.client.SetObjectiveActive(ctx, objectiveID, active, revision)
 		return objectiveActiveDoneMsg{item: item, err: err}
 	}
 }
 
-func (m model) deleteObjectiveCmd(objectiveID string) tea.Cmd {
+func (m model) runObjectiveCmd(objectiveID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		response, err := m.client.RunObjective(ctx, objectiveID)
+		return objectiveRunDoneMsg{response: response, err: err}
+	}
+}
+
+func (m model) listObjectiveRunsCmd(objectiveID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		items, err := m.client.ListObjectiveRuns(ctx, objectiveID, 20)
+		return objectiveRunsLoadedMsg{objectiveID: objectiveID, items: items, err: err}
+	}
+}
+
+func (m model) createObjectiveCmd(input adminclient.CreateObjectiveRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		item, err := m.client.CreateObjective(ctx, input)
+		return objectiveSavedMsg{item: item, created: true, err: err}
+	}
+}
+
+func (m model) updateObjectiveCmd(input adminclient.UpdateObjectiveRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		item, err := m.client.UpdateObjective(ctx, input)
+		return objectiveSavedMsg{item: item, err: err}
+	}
+}
+
+func (m model) deleteObjectiveCmd(objectiveID string, revision int) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
 		defer cancel()
-		err := m.client.DeleteObjective(ctx, objectiveID)
+		err := m.client.DeleteObjective(ctx, objectiveID, revision)
 		return objectiveDeleteDoneMsg{id: objectiveID, err: err}
 	}
 }
@@ -1305,7 +2884,154 @@ func (m model) retryTaskCmd(taskID string) tea.Cmd {
 	}
 }
 
-var taskFilterCycle = []string{"", "failed", "queued", "running", "succeeded"}
+func (m model) cancelTaskCmd(taskID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		response, err := m.client.CancelTask(ctx, taskID)
+		return taskCancelDoneMsg{response: response, err: err}
+	}
+}
+
+func (m model) deleteTaskCmd(taskID string, revision int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		err := m.client.DeleteTask(ctx, taskID, revision)
+		return taskDeleteDoneMsg{id: taskID, err: err}
+	}
+}
+
+func (m model) listTrashCmd(workspaceID, source string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		items, err := m.client.ListTrash(ctx, workspaceID, 200)
+		return trashLoadedMsg{items: items, workspaceID: workspaceID, source: source, err: err}
+	}
+}
+
+func (m model) searchCmd(query string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		items, err := m.client.Search(ctx, query, "", nil, 30)
+		return searchLoadedMsg{query: query, items: items, err: err}
+	}
+}
+
+func (m model) listWorkspacesCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		items, err := m.client.ListWorkspaces(ctx)
+		return workspacesLoadedMsg{items: items, err: err}
+	}
+}
+
+func (m model) restoreTrashItemCmd(item adminclient.TrashItem) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		err := m.client.RestoreTrashItem(ctx, item.Kind, item.ID)
+		return trashRestoreDoneMsg{item: item, err: err}
+	}
+}
+
+func (m model) getTaskResultCmd(taskID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		item, err := m.client.GetTaskResult(ctx, taskID)
+		return taskResultLoadedMsg{item: item, err: err}
+	}
+}
+
+func (m model) listCasesCmd(workspaceID, source string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		items, err := m.client.ListCases(ctx, workspaceID, "", 200)
+		return casesLoadedMsg{items: items, workspaceID: workspaceID, source: source, err: err}
+	}
+}
+
+func (m model) getCaseCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		item, err := m.client.GetCase(ctx, id)
+		return caseDetailLoadedMsg{item: item, err: err}
+	}
+}
+
+func (m model) updateCaseCmd(id, status string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		item, err := m.client.UpdateCase(ctx, id, status, "")
+		return caseUpdateDoneMsg{item: item, err: err}
+	}
+}
+
+func (m model) listApprovalsCmd(source string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		items, err := m.client.ListApprovals(ctx, "", "pending", 200)
+		return approvalsLoadedMsg{items: items, source: source, err: err}
+	}
+}
+
+// approveActionCmd allows longer than other mutations because the runtime
+// runs the action before it answers.
+func (m model) approveActionCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+		defer cancel()
+		item, err := m.client.ApproveAction(ctx, id, m.cfg.TUIApproverUserID)
+		return approvalDecisionDoneMsg{item: item, err: err}
+	}
+}
+
+func (m model) denyActionCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		item, err := m.client.DenyAction(ctx, id, m.cfg.TUIApproverUserID, "denied by admin")
+		return approvalDecisionDoneMsg{item: item, err: err}
+	}
+}
+
+func (m model) listChatLogsCmd(workspaceID, source string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		items, err := m.client.ListChatLogs(ctx, workspaceID)
+		return chatLogsLoadedMsg{items: items, workspaceID: workspaceID, source: source, err: err}
+	}
+}
+
+func (m model) tailChatLogCmd(workspaceID string, chat adminclient.ChatLog) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		items, err := m.client.TailChatLog(ctx, workspaceID, chat.Connector, chat.ExternalID, 200)
+		return chatLogTailLoadedMsg{workspaceID: workspaceID, chat: chatLogKey(chat), items: items, err: err}
+	}
+}
+
+// mutationErrorText explains a refused write. A revision conflict means the
+// row was edited elsewhere after the table loaded, so the operator should
+// reload before deciding again.
+func mutationErrorText(err error) string {
+	if errors.Is(err, adminclient.ErrRevisionConflict) {
+		return "changed since last refresh; press enter to reload and try again"
+	}
+	return err.Error()
+}
+
+var taskFilterCycle = []string{"", "failed", "queued", "running", "succeeded", "cancelled"}
 
 func nextTaskFilter(current string) string {
 	value := strings.ToLower(strings.TrimSpace(current))
@@ -1340,6 +3066,8 @@ func taskFilterLabel(value string) string {
 		return "running"
 	case "succeeded":
 		return "succeeded"
+	case "cancelled":
+		return "cancelled"
 	default:
 		return "all"
 	}
@@ -1428,7 +3156,7 @@ func batchCmds(cmds ...tea.Cmd) tea.Cmd {
 }
 
 func allViews() []viewID {
-	return []viewID{viewOverview, viewPairings, viewObjectives, viewTasks, viewActivity}
+	return []viewID{viewOverview, viewPairings, viewObjectives, viewTasks, viewActivity, viewTrash, viewCases, viewApprovals, viewTrace}
 }
 
 func viewLabel(view viewID) string {
@@ -1443,6 +3171,14 @@ func viewLabel(view viewID) string {
 		return "Tasks"
 	case viewActivity:
 		return "Activity"
+	case viewTrash:
+		return "Trash"
+	case viewCases:
+		return "Cases"
+	case viewApprovals:
+		return "Approvals"
+	case viewTrace:
+		return "Trace"
 	default:
 		return strings.Title(string(view))
 	}
@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 
@@ -220,9 +224,611 @@ func TestRetryOnlyFailedTask(t *testing.T) {
 	}
 }
 
+func TestCancelOnlyActiveTask(t *testing.T) {
+	m := newTestModel()
+	m.activeView = viewTasks
+	m.focus = focusWorkbench
+	m.tasks = []adminclient.Task{{ID: "task-1", Title: "Task", Status: "succeeded"}}
+	m.rebuildTaskRows()
+	_ = m.applyFocusCmd()
+
+	updated, _ := m.Update(keyRune('c'))
+	typed := updated.(model)
+	if typed.errorText != "only queued or running tasks can be cancelled" {
+		t.Fatalf("expected active-only cancel error, got %s", typed.errorText)
+	}
+
+	typed.tasks[0].Status = "running"
+	typed.rebuildTaskRows()
+	typed.errorText = ""
+	updated, cmd := typed.Update(keyRune('c'))
+	typed = updated.(model)
+	if typed.errorText != "" || typed.pendingMutations != 1 || cmd == nil {
+		t.Fatalf("expected a cancel request, got error %q and %d pending", typed.errorText, typed.pendingMutations)
+	}
+}
+
+func TestTrashRefusesActiveTask(t *testing.T) {
+	m := newTestModel()
+	m.activeView = viewTasks
+	m.focus = focusWorkbench
+	m.tasks = []adminclient.Task{{ID: "task-1", Title: "Task", Status: "running"}}
+	m.rebuildTaskRows()
+	_ = m.applyFocusCmd()
+
+	updated, _ := m.Update(keyRune('x'))
+	typed := updated.(model)
+	if typed.errorText != "queued or running tasks cannot be trashed" {
+		t.Fatalf("expected active task trash error, got %s", typed.errorText)
+	}
+	if typed.pendingMutations != 0 {
+		t.Fatalf("expected no mutation, got %d pending", typed.pendingMutations)
+	}
+}
+
+func TestTrashRestoreRemovesRow(t *testing.T) {
+	m := newTestModel()
+	m.trashWorkspaceInput.SetValue("ws-1")
+	updated, _ := m.Update(trashLoadedMsg{
+		items: []adminclient.TrashItem{
+			{Kind: "objective", ID: "obj-1", Title: "Digest"},
+			{Kind: "task", ID: "task-1", Title: "Report"},
+		},
+		workspaceID: "ws-1",
+	})
+	typed := updated.(model)
+	if len(typed.trash) != 2 {
+		t.Fatalf("expected two trashed items, got %d", len(typed.trash))
+	}
+
+	typed.pendingMutations = 1
+	updated, _ = typed.Update(trashRestoreDoneMsg{item: adminclient.TrashItem{Kind: "objective", ID: "obj-1"}})
+	typed = updated.(model)
+	if len(typed.trash) != 1 || typed.trash[0].ID != "task-1" {
+		t.Fatalf("expected restored objective to leave the trash, got %+v", typed.trash)
+	}
+	if typed.statusText != "objective restored" {
+		t.Fatalf("unexpected status %q", typed.statusText)
+	}
+}
+
+func TestObjectiveDeleteConflictAsksForReload(t *testing.T) {
+	m := newTestModel()
+	m.objectives = []adminclient.Objective{{ID: "obj-1", Title: "Digest", Revision: 2}}
+	m.rebuildObjectiveRows()
+	m.pendingMutations = 1
+
+	updated, _ := m.Update(objectiveDeleteDoneMsg{
+		id:  "obj-1",
+		err: fmt.Errorf("%w: record changed since it was read", adminclient.ErrRevisionConflict),
+	})
+	typed := updated.(model)
+	if !strings.Contains(typed.errorText, "changed since last refresh") {
+		t.Fatalf("expected reload hint, got %q", typed.errorText)
+	}
+	if len(typed.objectives) != 1 {
+		t.Fatalf("expected conflicting objective to stay listed, got %+v", typed.objectives)
+	}
+}
+
+func TestSearchPaletteOpensTaskResult(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(keyPress('k', "", tea.ModCtrl))
+	typed := updated.(model)
+	if !typed.searchOpen {
+		t.Fatal("expected ctrl+k to open the search palette")
+	}
+	for _, r := range "q6" {
+		updated, _ = typed.Update(keyRune(r))
+		typed = updated.(model)
+	}
+	if typed.quitting || typed.activeView != viewOverview || typed.searchInput.Value() != "q6" {
+		t.Fatalf("expected typed keys to go to the query, got quitting=%v view=%s value=%q", typed.quitting, typed.activeView, typed.searchInput.Value())
+	}
+
+	updated, _ = typed.Update(searchLoadedMsg{query: "q6", items: []adminclient.SearchResult{
+		{Kind: "approval", ID: "act-1", WorkspaceID: "ws-1", Title: "http_request"},
+		{Kind: "task", ID: "task-2", WorkspaceID: "ws-9", Title: "Q6 webhooks"},
+	}})
+	typed = updated.(model)
+	updated, _ = typed.Update(keyPress(tea.KeyDown, ""))
+	typed = updated.(model)
+	updated, _ = typed.Update(keyPress(tea.KeyEnter, ""))
+	typed = updated.(model)
+	if typed.searchOpen || typed.activeView != viewTasks {
+		t.Fatalf("expected enter to jump to the tasks view, got open=%v view=%s", typed.searchOpen, typed.activeView)
+	}
+	if typed.taskWorkspaceInput.Value() != "ws-9" || typed.pendingTaskID != "task-2" {
+		t.Fatalf("expected task workspace and pending selection, got %q %q", typed.taskWorkspaceInput.Value(), typed.pendingTaskID)
+	}
+
+	updated, _ = typed.Update(tasksLoadedMsg{workspaceID: "ws-9", items: []adminclient.Task{
+		{ID: "task-1", WorkspaceID: "ws-9", Title: "Other"},
+		{ID: "task-2", WorkspaceID: "ws-9", Title: "Q6 webhooks"},
+	}})
+	typed = updated.(model)
+	if selected, ok := typed.selectedTask(); !ok || selected.ID != "task-2" {
+		t.Fatalf("expected the search result to be selected, got %+v", selected)
+	}
+}
+
 func TestNormalizePairingRoleFallback(t *testing.T) {
 	role := normalizePairingRole("unknown")
 	if role != "admin" {
 		t.Fatalf("expected fallback admin role, got %s", role)
 	}
 }
+
+func TestCasesViewShowsLoadedItemsAndStatusChange(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(keyRune('7'))
+	typed := updated.(model)
+	if typed.activeView != viewCases {
+		t.Fatalf("expected cases view, got %s", typed.activeView)
+	}
+	updated, _ = typed.Update(casesLoadedMsg{
+		items: []adminclient.Case{
+			{ID: "case_1", Title: "[INCIDENT] checkout down", Kind: "incident", Status: "open"},
+		},
+		workspaceID: "ws-1",
+	})
+	typed = updated.(model)
+	if !strings.Contains(typed.renderCasesInspectorText(), "press o to load linked items") {
+		t.Fatalf("expected items hint before loading detail, got %q", typed.renderCasesInspectorText())
+	}
+
+	typed.pendingLoads = 1
+	updated, _ = typed.Update(caseDetailLoadedMsg{item: adminclient.Case{
+		ID:    "case_1",
+		Items: []adminclient.CaseItem{{Kind: "task", ItemID: "task-9"}, {Kind: "chat", Excerpt: "checkout returns 500"}},
+	}})
+	typed = updated.(model)
+	inspector := typed.renderCasesInspectorText()
+	if !strings.Contains(inspector, "- task task-9") || !strings.Contains(inspector, "- chat: checkout returns 500") {
+		t.Fatalf("expected linked items in inspector, got %q", inspector)
+	}
+
+	typed.pendingMutations = 1
+	updated, _ = typed.Update(caseUpdateDoneMsg{item: adminclient.Case{ID: "case_1", Title: "[INCIDENT] checkout down", Kind: "incident", Status: "resolved"}})
+	typed = updated.(model)
+	if typed.cases[0].Status != "resolved" || typed.statusText != "case resolved" {
+		t.Fatalf("expected resolved case row, got %+v (%q)", typed.cases[0], typed.statusText)
+	}
+}
+
+func TestApprovalsViewShowsPayloadAndDropsDecidedRows(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(keyRune('8'))
+	typed := updated.(model)
+	if typed.activeView != viewApprovals {
+		t.Fatalf("expected approvals view, got %s", typed.activeView)
+	}
+	typed.pendingLoads = 1
+	updated, _ = typed.Update(approvalsLoadedMsg{items: []adminclient.Approval{
+		{
+			ID:            "act_1",
+			ContextID:     "ctx-1",
+			ActionType:    "webhook",
+			ActionTarget:  "https://hooks.example.com/deploy",
+			ActionSummary: "Trigger deploy",
+			RiskLevel:     "high",
+			RiskReason:    "production deploy",
+			Payload:       map[string]any{"url": "https://hooks.example.com/deploy", "method": "POST"},
+		},
+		{ID: "act_2", ActionType: "send_email", ActionTarget: "ops@example.com"},
+	}})
+	typed = updated.(model)
+	inspector := typed.renderApprovalsInspectorText()
+	if !strings.Contains(inspector, `"method": "POST"`) || !strings.Contains(inspector, "risk       high production deploy") {
+		t.Fatalf("expected payload and risk in inspector, got %q", inspector)
+	}
+
+	typed.focus = focusWorkbench
+	updated, cmd := typed.Update(keyRune('a'))
+	typed = updated.(model)
+	if cmd == nil || typed.pendingMutations != 1 {
+		t.Fatalf("expected approve to start a mutation, got %d", typed.pendingMutations)
+	}
+	updated, _ = typed.Update(approvalDecisionDoneMsg{item: adminclient.Approval{ID: "act_1", Status: "approved", ExecutionStatus: "succeeded"}})
+	typed = updated.(model)
+	if len(typed.approvals) != 1 || typed.approvals[0].ID != "act_2" || typed.statusText != "action approved, execution succeeded" {
+		t.Fatalf("expected approved row removed, got %+v (%q)", typed.approvals, typed.statusText)
+	}
+	if !strings.Contains(typed.renderApprovalsInspectorText(), "target     ops@example.com") {
+		t.Fatalf("expected remaining approval selected, got %q", typed.renderApprovalsInspectorText())
+	}
+}
+
+func TestTraceViewTailsSelectedChatAndIgnoresStaleTails(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(keyRune('9'))
+	typed := updated.(model)
+	if typed.activeView != viewTrace {
+		t.Fatalf("expected trace view, got %s", typed.activeView)
+	}
+	typed.pendingLoads = 1
+	updated, cmd := typed.Update(chatLogsLoadedMsg{workspaceID: "ws-1", items: []adminclient.ChatLog{
+		{Connector: "discord", ExternalID: "chan-1"},
+		{Connector: "telegram", ExternalID: "42"},
+	}})
+	typed = updated.(model)
+	if cmd == nil || !typed.traceInFlight {
+		t.Fatal("expected loading chats to start tailing the selected chat")
+	}
+	updated, _ = typed.Update(chatLogTailLoadedMsg{workspaceID: "ws-1", chat: "discord/chan-1", items: []adminclient.ChatLogEntry{
+		{TimestampUnix: 1700000000, Direction: "inbound", Actor: "user-1", Text: "deploy please"},
+		{TimestampUnix: 1700000001, Direction: "tool", Actor: "agent-runtime", Text: "Tool call\n- tool: `run_action`\n- status: `blocked`\n- error: needs approval"},
+	}})
+	typed = updated.(model)
+	inspector := typed.renderTraceInspectorText(true)
+	if !strings.Contains(inspector, "22:13:21  tool run_action blocked") || !strings.Contains(inspector, "  error: needs approval") {
+		t.Fatalf("expected tool call in trace, got %q", inspector)
+	}
+
+	typed.focus = focusWorkbench
+	updated, _ = typed.Update(keyRune('j'))
+	typed = updated.(model)
+	if len(typed.traceEntries) != 0 || !typed.traceInFlight {
+		t.Fatalf("expected moving to another chat to clear and refetch, got %+v", typed.traceEntries)
+	}
+	updated, _ = typed.Update(chatLogTailLoadedMsg{workspaceID: "ws-1", chat: "discord/chan-1", items: []adminclient.ChatLogEntry{{Direction: "outbound", Text: "stale"}}})
+	typed = updated.(model)
+	if len(typed.traceEntries) != 0 || !strings.Contains(typed.renderTraceInspectorText(true), "loading...") {
+		t.Fatalf("expected tail of the previous chat ignored, got %+v", typed.traceEntries)
+	}
+}
+
+func TestTasksInspectorShowsDependencyGraph(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(keyRune('4'))
+	typed := updated.(model)
+	typed.pendingLoads = 1
+	updated, _ = typed.Update(tasksLoadedMsg{workspaceID: "ws-1", items: []adminclient.Task{
+		{ID: "task-clean", WorkspaceID: "ws-1", Title: "Clean data", Status: "queued", DependsOn: []string{"task-fetch"}, Blocks: []string{"task-report"}},
+		{ID: "task-fetch", WorkspaceID: "ws-1", Title: "Fetch data", Status: "running", Blocks: []string{"task-clean"}},
+		{ID: "task-report", WorkspaceID: "ws-1", Title: "Write report", Status: "queued", DependsOn: []string{"task-clean", "task-gone"}},
+	}})
+	typed = updated.(model)
+	inspector := typed.renderTasksInspectorText()
+	for _, want := range []string{
+		"Dependencies",
+		"waits on\n  - Fetch data  [running]  task-fetch",
+		"blocks\n  - Write report  [blocked]  task-report",
+	} {
+		if !strings.Contains(inspector, want) {
+			t.Fatalf("expected %q in task detail, got %q", want, inspector)
+		}
+	}
+	if rows := typed.tasksTable.Rows(); rows[0][1] != "blocked" || rows[1][1] != "running" {
+		t.Fatalf("expected blocked status in the table, got %v", rows)
+	}
+
+	typed.focus = focusWorkbench
+	updated, _ = typed.Update(keyRune('j'))
+	typed = updated.(model)
+	updated, _ = typed.Update(keyRune('j'))
+	typed = updated.(model)
+	inspector = typed.renderTasksInspectorText()
+	for _, want := range []string{"  - Clean data  [blocked]  task-clean", "    - Fetch data  [running]  task-fetch", "  - task-gone  (not loaded)"} {
+		if !strings.Contains(inspector, want) {
+			t.Fatalf("expected %q in transitive graph, got %q", want, inspector)
+		}
+	}
+}
+
+func TestTasksTableShowsScheduledTask(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(keyRune('4'))
+	typed := updated.(model)
+	typed.pendingLoads = 1
+	runAt := time.Now().Add(time.Hour).Unix()
+	updated, _ = typed.Update(tasksLoadedMsg{workspaceID: "ws-1", items: []adminclient.Task{
+		{ID: "task-later", WorkspaceID: "ws-1", Title: "Standup notes", Status: "queued", RunAtUnix: runAt},
+		{ID: "task-due", WorkspaceID: "ws-1", Title: "Due now", Status: "queued", RunAtUnix: time.Now().Add(-time.Minute).Unix()},
+	}})
+	typed = updated.(model)
+	if rows := typed.tasksTable.Rows(); rows[0][1] != "scheduled" || rows[1][1] != "queued" {
+		t.Fatalf("expected scheduled status in the table, got %v", rows)
+	}
+	if inspector := typed.renderTasksInspectorText(); !strings.Contains(inspector, "runs at    "+formatUnix(runAt)) {
+		t.Fatalf("expected run time in task detail, got %q", inspector)
+	}
+}
+
+func TestTasksInspectorShowsDetailAndOpensResult(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(keyRune('4'))
+	typed := updated.(model)
+	typed.pendingLoads = 1
+	updated, _ = typed.Update(tasksLoadedMsg{workspaceID: "ws-1", items: []adminclient.Task{
+		{
+			ID:              "task-1",
+			WorkspaceID:     "ws-1",
+			Title:           "Weekly report",
+			Prompt:          "Summarize the week's incidents",
+			Status:          "succeeded",
+			RouteClass:      "report",
+			Priority:        "p2",
+			SourceConnector: "discord",
+			Attempts:        2,
+			ResultSummary:   "Three incidents, all resolved.",
+			ResultPath:      "tasks/2026/10/17/task-1.md",
+
+			ProgressStep:          "step 5/5: write summary",
+			ProgressPercent:       80,
+			ProgressUpdatedAtUnix: 1760000000,
+		},
+		{ID: "task-2", WorkspaceID: "ws-1", Title: "Queued", Status: "queued"},
+	}})
+	typed = updated.(model)
+	inspector := typed.renderTasksInspectorText()
+	for _, want := range []string{"class      report", "count      2", "Three incidents, all resolved.", "- tasks/2026/10/17/task-1.md", "Summarize the week's incidents", "progress   step 5/5: write summary (80%)"} {
+		if !strings.Contains(inspector, want) {
+			t.Fatalf("expected %q in task detail, got %q", want, inspector)
+		}
+	}
+
+	typed.focus = focusWorkbench
+	updated, cmd := typed.Update(keyRune('o'))
+	typed = updated.(model)
+	if cmd == nil || typed.pendingLoads != 1 {
+		t.Fatalf("expected result load to start, got %d", typed.pendingLoads)
+	}
+	updated, _ = typed.Update(taskResultLoadedMsg{item: adminclient.TaskResult{TaskID: "task-1", ResultPath: "tasks/2026/10/17/task-1.md", Content: "# Task Result\n\n## Final Output\n\nAll clear."}})
+	typed = updated.(model)
+	if typed.focus != focusInspector || !strings.Contains(typed.renderTasksInspectorText(), "## Final Output") {
+		t.Fatalf("expected result markdown in focused inspector, got %q", typed.renderTasksInspectorText())
+	}
+
+	typed.focus = focusWorkbench
+	updated, _ = typed.Update(keyRune('j'))
+	typed = updated.(model)
+	updated, _ = typed.Update(keyRune('o'))
+	typed = updated.(model)
+	if typed.errorText != "task has no result file yet" || typed.pendingLoads != 0 {
+		t.Fatalf("expected unfinished task to report no result, got %q", typed.errorText)
+	}
+}
+
+func TestObjectiveFormCreatesAndEditsObjectives(t *testing.T) {
+	m := newTestModel()
+	m.activeView = viewObjectives
+	m.focus = focusWorkbench
+	m.objectiveWorkspaceInput.SetValue("ws-1")
+	m.objectives = []adminclient.Objective{{
+		ID: "obj-1", WorkspaceID: "ws-1", ContextID: "ctx-1", Title: "Watch deploys",
+		Prompt: "summarize the deploy", TriggerType: "event", EventKey: "deploy.finished", Revision: 3,
+	}}
+	m.rebuildObjectiveRows()
+
+	updated, _ := m.Update(keyRune('n'))
+	typed := updated.(model)
+	if !typed.objectiveForm.open || typed.objectiveForm.editing() {
+		t.Fatal("expected n to open an empty objective form")
+	}
+	if typed.objectiveForm.value(formFieldContext) != "ctx-1" {
+		t.Fatalf("expected context prefilled from the selected objective, got %q", typed.objectiveForm.value(formFieldContext))
+	}
+	typeText := func(text string) {
+		for _, r := range text {
+			updated, _ = typed.Update(keyRune(r))
+			typed = updated.(model)
+		}
+	}
+	typeText("Digest")
+	updated, _ = typed.Update(keyPress(tea.KeyEnter, ""))
+	typed = updated.(model)
+	typeText("write it")
+	updated, _ = typed.Update(keyPress(tea.KeyTab, ""))
+	typed = updated.(model)
+	updated, _ = typed.Update(keyPress(tea.KeyTab, ""))
+	typed = updated.(model)
+	typeText("30m")
+	input, err := typed.objectiveForm.createRequest()
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	if input.WorkspaceID != "ws-1" || input.Title != "Digest" || input.Prompt != "write it" || input.TriggerType != "schedule" || input.CronExpr != "@every 30m" {
+		t.Fatalf("unexpected create request %+v", input)
+	}
+
+	updated, _ = typed.Update(keyPress(tea.KeyEscape, ""))
+	typed = updated.(model)
+	if typed.objectiveForm.open {
+		t.Fatal("expected esc to close the form")
+	}
+
+	updated, _ = typed.Update(keyRune('e'))
+	typed = updated.(model)
+	if !typed.objectiveForm.editing() || typed.objectiveForm.value(formFieldSchedule) != "deploy.finished" {
+		t.Fatalf("expected edit form filled from the objective, got %+v", typed.objectiveForm.editID)
+	}
+	update, err := typed.objectiveForm.updateRequest()
+	if err != nil {
+		t.Fatalf("update request: %v", err)
+	}
+	if update.ID != "obj-1" || update.Revision != 3 || *update.TriggerType != "event" || *update.EventKey != "deploy.finished" || *update.CronExpr != "" {
+		t.Fatalf("unexpected update request %+v", update)
+	}
+
+	typed.pendingMutations = 1
+	updated, _ = typed.Update(objectiveSavedMsg{err: fmt.Errorf("%w: record changed since it was read", adminclient.ErrRevisionConflict)})
+	typed = updated.(model)
+	if !typed.objectiveForm.open || !strings.Contains(typed.errorText, "changed since last refresh") {
+		t.Fatalf("expected the form kept open with a reload hint, got open=%v error=%q", typed.objectiveForm.open, typed.errorText)
+	}
+}
+
+func TestWorkspacePickerSwitchesEveryView(t *testing.T) {
+	m := newTestModel()
+	m.activeView = viewTasks
+	m.taskWorkspaceInput.SetValue("ws-2")
+	m.tasks = []adminclient.Task{{ID: "task-1", WorkspaceID: "ws-2"}}
+	m.rebuildTaskRows()
+
+	updated, _ := m.Update(keyPress('o', "", tea.ModCtrl))
+	typed := updated.(model)
+	if !typed.workspacePickerOpen {
+		t.Fatal("expected ctrl+o to open the workspace picker")
+	}
+	updated, _ = typed.Update(workspacesLoadedMsg{items: []adminclient.Workspace{
+		{ID: "ws-1", Name: "Discord: ops", OpenTaskCount: 2},
+		{ID: "ws-2"},
+		{ID: "ws-3", ActiveObjectiveCount: 1},
+	}})
+	typed = updated.(model)
+	if typed.workspaceCursor != 1 {
+		t.Fatalf("expected the cursor on the current workspace, got %d", typed.workspaceCursor)
+	}
+	if !strings.Contains(typed.renderWorkspacePickerText(newTheme(), computeLayout(120, 40)), "Discord: ops (ws-1)") {
+		t.Fatal("expected the picker to list workspace names")
+	}
+
+	updated, _ = typed.Update(keyRune('j'))
+	typed = updated.(model)
+	updated, _ = typed.Update(keyPress(tea.KeyEnter, ""))
+	typed = updated.(model)
+	if typed.workspacePickerOpen {
+		t.Fatal("expected enter to close the picker")
+	}
+	for name, value := range map[string]string{
+		"objectives": typed.objectiveWorkspaceInput.Value(),
+		"tasks":      typed.taskWorkspaceInput.Value(),
+		"trash":      typed.trashWorkspaceInput.Value(),
+		"cases":      typed.caseWorkspaceInput.Value(),
+		"trace":      typed.traceWorkspaceInput.Value(),
+	} {
+		if value != "ws-3" {
+			t.Fatalf("expected %s workspace ws-3, got %q", name, value)
+		}
+	}
+	if len(typed.tasks) != 0 {
+		t.Fatalf("expected rows of the previous workspace dropped, got %+v", typed.tasks)
+	}
+}
+
+func TestRuntimeProfileSwitchRebuildsClient(t *testing.T) {
+	m := newTestModel()
+	m.profiles = append(m.profiles, config.TUIProfile{Name: "staging", AdminAPIURL: "https://admin.staging.test", AdminHTTPTimeoutSec: 30})
+	m.objectives = []adminclient.Objective{{ID: "obj-1", Title: "Default runtime objective"}}
+	m.rebuildObjectiveRows()
+
+	m.pendingLoads = 1
+	updated, _ := m.Update(keyPress('r', "", tea.ModCtrl))
+	typed := updated.(model)
+	if typed.profileIndex != 0 || !strings.Contains(typed.errorText, "wait for pending requests") {
+		t.Fatalf("expected the switch refused while loading, got profile %d error %q", typed.profileIndex, typed.errorText)
+	}
+
+	typed.pendingLoads = 0
+	updated, _ = typed.Update(keyPress('r', "", tea.ModCtrl))
+	typed = updated.(model)
+	if typed.profileName() != "staging" || typed.cfg.AdminAPIURL != "https://admin.staging.test" || typed.client == nil {
+		t.Fatalf("expected the staging runtime with a new client, got %q %q", typed.profileName(), typed.cfg.AdminAPIURL)
+	}
+	if len(typed.objectives) != 0 {
+		t.Fatalf("expected rows of the previous runtime dropped, got %+v", typed.objectives)
+	}
+	if !strings.Contains(typed.renderHeader(newTheme(), computeLayout(140, 48)), "runtime: staging") {
+		t.Fatal("expected the header to name the runtime")
+	}
+
+	typed.pendingLoads = 0
+	typed.pendingMutations = 0
+	updated, _ = typed.Update(keyPress('r', "", tea.ModCtrl))
+	typed = updated.(model)
+	if typed.profileName() != config.DefaultTUIProfileName || typed.cfg.AdminAPIURL != "https://admin.test" {
+		t.Fatalf("expected the switch to wrap back to the default runtime, got %q %q", typed.profileName(), typed.cfg.AdminAPIURL)
+	}
+}
+
+func TestLiveChangesReplacePolling(t *testing.T) {
+	m := newTestModel()
+	m.liveGeneration = 2
+
+	updated, _ := m.Update(liveStateMsg{gen: 2, connected: true})
+	typed := updated.(model)
+	if !typed.liveConnected || typed.liveSequence != 1 {
+		t.Fatalf("expected the stream marked live with a catch-up refresh queued, got %+v", typed.liveConnected)
+	}
+	if !strings.Contains(typed.renderHeader(newTheme(), computeLayout(140, 48)), "(live)") {
+		t.Fatal("expected the header to show live updates")
+	}
+
+	updated, _ = typed.Update(pollMsg{at: time.Now()})
+	typed = updated.(model)
+	if typed.pendingLoads != 0 {
+		t.Fatalf("expected no polling while live, got %d loads", typed.pendingLoads)
+	}
+
+	updated, _ = typed.Update(liveChangeMsg{gen: 2, change: adminclient.Change{Kind: "task", WorkspaceID: "ws-other", Version: 3}})
+	typed = updated.(model)
+	if typed.liveSequence != 1 {
+		t.Fatal("expected a change in an unwatched workspace ignored")
+	}
+	updated, _ = typed.Update(liveChangeMsg{gen: 1, change: adminclient.Change{Kind: "approval", WorkspaceID: "ws-1", Version: 3}})
+	typed = updated.(model)
+	if typed.liveSequence != 1 {
+		t.Fatal("expected a change from a replaced stream ignored")
+	}
+	updated, _ = typed.Update(liveChangeMsg{gen: 2, change: adminclient.Change{Kind: "task", WorkspaceID: "ws-1", Version: 4}})
+	typed = updated.(model)
+	if typed.liveSequence != 2 {
+		t.Fatal("expected a change in the task workspace to queue a refresh")
+	}
+
+	updated, _ = typed.Update(liveDebounceMsg{seq: 1})
+	typed = updated.(model)
+	if typed.pendingLoads != 0 {
+		t.Fatal("expected a superseded debounce to do nothing")
+	}
+	updated, _ = typed.Update(liveDebounceMsg{seq: 2})
+	typed = updated.(model)
+	if typed.pendingLoads == 0 {
+		t.Fatal("expected the debounced change to refresh the views")
+	}
+
+	typed.pendingLoads = 0
+	updated, _ = typed.Update(liveStateMsg{gen: 2, err: errors.New("stream closed")})
+	typed = updated.(model)
+	if typed.liveConnected {
+		t.Fatal("expected the stream marked down")
+	}
+	updated, _ = typed.Update(pollMsg{at: time.Now()})
+	typed = updated.(model)
+	if typed.pendingLoads == 0 {
+		t.Fatal("expected polling to resume while the stream is down")
+	}
+}
+
+func TestObjectivesInspectorTogglesRunHistory(t *testing.T) {
+	m := newTestModel()
+	m.activeView = viewObjectives
+	m.focus = focusWorkbench
+	m.objectives = []adminclient.Objective{{ID: "obj-1", Title: "Nightly report", Active: false, AutoPausedReason: "auto-paused after 5 consecutive failures"}}
+	m.rebuildObjectiveRows()
+
+	updated, cmd := m.Update(keyRune('h'))
+	typed := updated.(model)
+	if cmd == nil || typed.pendingLoads != 1 {
+		t.Fatalf("expected the run history load to start, got %d", typed.pendingLoads)
+	}
+	finished := int64(1760000060)
+	updated, _ = typed.Update(objectiveRunsLoadedMsg{objectiveID: "obj-1", items: []adminclient.ObjectiveRun{
+		{ID: "run-2", Status: "failed", Trigger: "schedule", StartedAtUnix: 1760000000, FinishedAtUnix: &finished, DurationMs: 60000, Error: "tool timed out"},
+		{ID: "run-1", Status: "queued", Trigger: "event", StartedAtUnix: 1759990000},
+	}})
+	typed = updated.(model)
+	inspector := typed.renderObjectivesInspectorText()
+	for _, want := range []string{"auto-paused after 5 consecutive failures", "Recent Runs", "failed", "1m0s", "tool timed out", "running"} {
+		if !strings.Contains(inspector, want) {
+			t.Fatalf("expected %q in objective detail, got %q", want, inspector)
+		}
+	}
+
+	updated, _ = typed.Update(keyRune('h'))
+	typed = updated.(model)
+	if typed.objectiveRuns != nil || strings.Contains(typed.renderObjectivesInspectorText(), "Recent Runs") {
+		t.Fatal("expected the history key to hide the runs again")
+	}
+}
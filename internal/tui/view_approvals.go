@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+)
+
+func (m model) renderApprovalsWorkbenchText(t theme, layout uiLayout) string {
+	width := layout.MainWidth - 6
+	if layout.Compact {
+		width = layout.Width - 6
+	}
+	high := 0
+	for _, item := range m.approvals {
+		if item.RiskLevel == "high" {
+			high++
+		}
+	}
+	intro := []string{
+		t.panelSubtle.Render("Actions the agent proposed that wait for an admin"),
+		t.panelSubtle.Render("pending queue, every workspace, oldest first"),
+	}
+	primary := []string{
+		fillLine(
+			fmt.Sprintf("pending %d", len(m.approvals)),
+			fmt.Sprintf("high risk %d", high),
+			width,
+		),
+		"",
+		m.approvalsTable.View(),
+	}
+	tail := []string{t.panelSubtle.Render("actions: enter refresh | a approve and run | d deny | inspector shows the payload")}
+	if strings.TrimSpace(m.errorText) != "" {
+		tail = append(tail, t.panelError.Render("error: "+m.errorText))
+	}
+	return renderWorkbenchRhythm(intro, primary, tail)
+}
+
+func (m model) renderApprovalsInspectorText() string {
+	selected, ok := m.selectedApproval()
+	if !ok {
+		return strings.Join([]string{
+			"Approval Detail",
+			"",
+			"no pending approvals",
+		}, "\n")
+	}
+	lines := []string{
+		"Approval Detail",
+		"",
+		"id         " + selected.ID,
+		"action     " + fallbackText(selected.ActionType, "n/a"),
+		"target     " + fallbackText(selected.ActionTarget, "n/a"),
+		"requester  " + fallbackText(selected.RequesterUserID, "n/a"),
+		"workspace  " + fallbackText(selected.WorkspaceID, "n/a"),
+		"context    " + fallbackText(selected.ContextID, "n/a"),
+		"channel    " + fallbackText(strings.Trim(selected.Connector+"/"+selected.ExternalID, "/"), "n/a"),
+		"created    " + formatUnix(selected.CreatedAtUnix),
+	}
+	if selected.ExpiresAtUnix > 0 {
+		lines = append(lines, "expires    "+formatUnix(selected.ExpiresAtUnix))
+	}
+	if selected.RiskLevel != "" {
+		lines = append(lines, "risk       "+selected.RiskLevel+" "+selected.RiskReason)
+	}
+	if selected.RequiredApprovals > 1 {
+		signers := make([]string, 0, len(selected.Signoffs))
+		for _, signoff := range selected.Signoffs {
+			signers = append(signers, signoff.UserID)
+		}
+		lines = append(lines, fmt.Sprintf("approvals  %d of %d %s", len(selected.Signoffs), selected.RequiredApprovals, strings.Join(signers, ", ")))
+	}
+	if summary := strings.TrimSpace(selected.ActionSummary); summary != "" {
+		lines = append(lines, "", "summary", summary)
+	}
+	lines = append(lines, "", "payload", approvalPayloadText(selected.Payload))
+	return strings.Join(lines, "\n")
+}
+
+// approvalPayloadText renders the payload exactly as the executor will see
+// it, keys sorted, so it can be checked before approving.
+func approvalPayloadText(payload map[string]any) string {
+	if len(payload) == 0 {
+		return "{}"
+	}
+	encoded, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", payload)
+	}
+	return string(encoded)
+}
+
+func approvalSummary(item adminclient.Approval) string {
+	if summary := strings.TrimSpace(item.ActionSummary); summary != "" {
+		return summary
+	}
+	return strings.TrimSpace(item.ActionType + " " + item.ActionTarget)
+}
+
+// approvalAge is a compact age for the queue table: 45s, 12m, 3h, 2d.
+func approvalAge(createdAtUnix int64, now time.Time) string {
+	if createdAtUnix <= 0 {
+		return "-"
+	}
+	age := now.Sub(time.Unix(createdAtUnix, 0))
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", maxInt(0, int(age.Seconds())))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+func approvalDecisionText(item adminclient.Approval) string {
+	switch {
+	case item.Status == "denied":
+		return "action denied"
+	case item.Status == "pending":
+		return fallbackText(item.Message, "approval recorded, waiting for another admin")
+	case item.ExecutionStatus != "":
+		return "action approved, execution " + item.ExecutionStatus
+	default:
+		return "action approved"
+	}
+}
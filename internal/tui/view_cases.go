@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (m model) renderCasesWorkbenchText(t theme, layout uiLayout) string {
+	width := layout.MainWidth - 6
+	if layout.Compact {
+		width = layout.Width - 6
+	}
+	open := 0
+	for _, item := range m.cases {
+		if item.Status == "open" {
+			open++
+		}
+	}
+	intro := []string{
+		t.panelSubtle.Render("Incidents and moderation matters with their tasks, actions and messages"),
+		t.panelSubtle.Render("workspace filter + case table"),
+	}
+	primary := []string{
+		t.panelSubtle.Render("workspace"),
+		m.caseWorkspaceInput.View(),
+		"",
+		fillLine(
+			fmt.Sprintf("cases %d", len(m.cases)),
+			fmt.Sprintf("open %d", open),
+			width,
+		),
+		"",
+		m.casesTable.View(),
+	}
+	tail := []string{t.panelSubtle.Render("actions: enter refresh | o items | c resolve/reopen")}
+	if strings.TrimSpace(m.errorText) != "" {
+		tail = append(tail, t.panelError.Render("error: "+m.errorText))
+	}
+	return renderWorkbenchRhythm(intro, primary, tail)
+}
+
+func (m model) renderCasesInspectorText() string {
+	selected, ok := m.selectedCase()
+	if !ok {
+		return strings.Join([]string{
+			"Case Detail",
+			"",
+			"load a workspace and select a case",
+		}, "\n")
+	}
+	lines := []string{
+		"Case Detail",
+		"",
+		"title      " + fallbackText(selected.Title, "untitled"),
+		"id         " + fallbackText(selected.ID, "n/a"),
+		"kind       " + fallbackText(selected.Kind, "n/a"),
+		"status     " + fallbackText(selected.Status, "n/a"),
+		"owner      " + fallbackText(selected.OwnerUserID, "unassigned"),
+		"workspace  " + fallbackText(selected.WorkspaceID, "n/a"),
+		"opened     " + formatUnix(selected.CreatedAtUnix),
+		"updated    " + formatUnix(selected.UpdatedAtUnix),
+	}
+	if selected.ResolvedAtUnix > 0 {
+		lines = append(lines, "resolved   "+formatUnix(selected.ResolvedAtUnix))
+	}
+	if summary := strings.TrimSpace(selected.Summary); summary != "" {
+		lines = append(lines, "", "summary", summary)
+	}
+	lines = append(lines, "", "items")
+	if m.caseDetail == nil || m.caseDetail.ID != selected.ID {
+		return strings.Join(append(lines, "press o to load linked items"), "\n")
+	}
+	if len(m.caseDetail.Items) == 0 {
+		return strings.Join(append(lines, "no linked items"), "\n")
+	}
+	for _, item := range m.caseDetail.Items {
+		if item.Kind == "chat" {
+			lines = append(lines, fmt.Sprintf("- chat: %s", item.Excerpt))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s %s", item.Kind, item.ItemID))
+	}
+	return strings.Join(lines, "\n")
+}
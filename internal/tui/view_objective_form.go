@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+)
+
+const (
+	formFieldWorkspace = iota
+	formFieldContext
+	formFieldTitle
+	formFieldPrompt
+	formFieldTrigger
+	formFieldSchedule
+	formFieldCount
+)
+
+var objectiveFormLabels = [formFieldCount]string{"workspace", "context", "title", "prompt", "trigger", "schedule"}
+
+// objectiveForm creates an objective, or edits one when editID is set. An
+// edit cannot move the objective to another workspace or context, so those
+// fields are shown but skipped.
+type objectiveForm struct {
+	open     bool
+	editID   string
+	revision int
+	inputs   [formFieldCount]textinput.Model
+	focus    int
+}
+
+func newObjectiveForm(workspaceID, contextID string) objectiveForm {
+	form := objectiveForm{open: true, focus: formFieldTitle}
+	placeholders := [formFieldCount]string{
+		"ws-1",
+		"context id the runs report to",
+		"Weekly digest",
+		"what the agent should do on each run",
+		"schedule | interval | event",
+		"0 8 * * 1, 30m or an event key",
+	}
+	for index := range form.inputs {
+		input := textinput.New()
+		input.Prompt = fmt.Sprintf("%-10s> ", objectiveFormLabels[index])
+		input.Placeholder = placeholders[index]
+		input.CharLimit = 512
+		input.SetStyles(newInputStyles(newTheme()))
+		form.inputs[index] = input
+	}
+	form.inputs[formFieldPrompt].CharLimit = 4000
+	form.inputs[formFieldWorkspace].SetValue(workspaceID)
+	form.inputs[formFieldContext].SetValue(contextID)
+	form.inputs[formFieldTrigger].SetValue("schedule")
+	return form
+}
+
+func editObjectiveForm(item adminclient.Objective) objectiveForm {
+	form := newObjectiveForm(item.WorkspaceID, item.ContextID)
+	form.editID = item.ID
+	form.revision = item.Revision
+	form.inputs[formFieldTitle].SetValue(item.Title)
+	form.inputs[formFieldPrompt].SetValue(item.Prompt)
+	form.inputs[formFieldTrigger].SetValue(item.TriggerType)
+	schedule := item.CronExpr
+	if item.TriggerType == "event" {
+		schedule = item.EventKey
+	}
+	if interval, ok := strings.CutPrefix(schedule, "@every "); ok {
+		form.inputs[formFieldTrigger].SetValue("interval")
+		schedule = interval
+	}
+	form.inputs[formFieldSchedule].SetValue(schedule)
+	return form
+}
+
+func (f objectiveForm) editing() bool {
+	return f.editID != ""
+}
+
+func (f objectiveForm) locked(field int) bool {
+	return f.editing() && (field == formFieldWorkspace || field == formFieldContext)
+}
+
+// move steps the focus by delta, wrapping and skipping locked fields.
+func (f *objectiveForm) move(delta int) {
+	for range formFieldCount {
+		f.focus = (f.focus + delta + formFieldCount) % formFieldCount
+		if !f.locked(f.focus) {
+			return
+		}
+	}
+}
+
+func (f *objectiveForm) focusCmd() tea.Cmd {
+	for index := range f.inputs {
+		f.inputs[index].Blur()
+	}
+	return f.inputs[f.focus].Focus()
+}
+
+func (f *objectiveForm) update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	if f.focus == formFieldWorkspace {
+		f.inputs[f.focus].SetValue(sanitizeWorkspaceID(f.inputs[f.focus].Value()))
+	}
+	return cmd
+}
+
+func (f *objectiveForm) setWidth(width int) {
+	for index := range f.inputs {
+		f.inputs[index].SetWidth(width)
+	}
+}
+
+func (f objectiveForm) value(field int) string {
+	return strings.TrimSpace(f.inputs[field].Value())
+}
+
+// trigger resolves the trigger and schedule fields. Cron expressions and
+// descriptors are sent as is; an interval such as 30m becomes "@every 30m".
+func (f objectiveForm) trigger() (triggerType, cronExpr, eventKey string, err error) {
+	schedule := f.value(formFieldSchedule)
+	switch strings.ToLower(f.value(formFieldTrigger)) {
+	case "schedule", "cron", "":
+		if schedule == "" {
+			return "", "", "", errors.New("schedule needs a cron expression or interval")
+		}
+		if _, parseErr := time.ParseDuration(schedule); parseErr == nil {
+			return "schedule", "@every " + schedule, "", nil
+		}
+		return "schedule", schedule, "", nil
+	case "interval":
+		interval, parseErr := time.ParseDuration(schedule)
+		if parseErr != nil || interval <= 0 {
+			return "", "", "", errors.New("interval must be a duration such as 30m or 6h")
+		}
+		return "schedule", "@every " + schedule, "", nil
+	case "event":
+		if schedule == "" {
+			return "", "", "", errors.New("event trigger needs an event key")
+		}
+		return "event", "", schedule, nil
+	default:
+		return "", "", "", errors.New("trigger must be schedule, interval or event")
+	}
+}
+
+func (f objectiveForm) createRequest() (adminclient.CreateObjectiveRequest, error) {
+	for _, field := range []int{formFieldWorkspace, formFieldContext, formFieldTitle, formFieldPrompt} {
+		if f.value(field) == "" {
+			return adminclient.CreateObjectiveRequest{}, fmt.Errorf("%s is required", objectiveFormLabels[field])
+		}
+	}
+	triggerType, cronExpr, eventKey, err := f.trigger()
+	if err != nil {
+		return adminclient.CreateObjectiveRequest{}, err
+	}
+	return adminclient.CreateObjectiveRequest{
+		WorkspaceID: f.value(formFieldWorkspace),
+		ContextID:   f.value(formFieldContext),
+		Title:       f.value(formFieldTitle),
+		Prompt:      f.value(formFieldPrompt),
+		TriggerType: triggerType,
+		CronExpr:    cronExpr,
+		EventKey:    eventKey,
+	}, nil
+}
+
+// updateRequest sends every editable field so switching between schedule
+// and event clears the field the old trigger used.
+func (f objectiveForm) updateRequest() (adminclient.UpdateObjectiveRequest, error) {
+	for _, field := range []int{formFieldTitle, formFieldPrompt} {
+		if f.value(field) == "" {
+			return adminclient.UpdateObjectiveRequest{}, fmt.Errorf("%s is required", objectiveFormLabels[field])
+		}
+	}
+	triggerType, cronExpr, eventKey, err := f.trigger()
+	if err != nil {
+		return adminclient.UpdateObjectiveRequest{}, err
+	}
+	title, prompt := f.value(formFieldTitle), f.value(formFieldPrompt)
+	return adminclient.UpdateObjectiveRequest{
+		ID:          f.editID,
+		Title:       &title,
+		Prompt:      &prompt,
+		TriggerType: &triggerType,
+		CronExpr:    &cronExpr,
+		EventKey:    &eventKey,
+		Revision:    f.revision,
+	}, nil
+}
+
+func (m model) renderObjectiveFormText(t theme) string {
+	form := m.objectiveForm
+	intro := []string{
+		t.panelSubtle.Render("Create an objective the scheduler runs on a cron, interval or event"),
+		t.panelSubtle.Render("trigger schedule takes cron (0 8 * * 1) or a duration (30m); event takes an event key"),
+	}
+	if form.editing() {
+		intro = []string{
+			t.panelSubtle.Render("Edit objective " + form.editID),
+			t.panelSubtle.Render("workspace and context cannot change; trigger schedule, interval or event"),
+		}
+	}
+	primary := make([]string, 0, formFieldCount)
+	for index, input := range form.inputs {
+		if form.locked(index) {
+			primary = append(primary, t.panelSubtle.Render(fmt.Sprintf("%-10s  %s", objectiveFormLabels[index], input.Value())))
+			continue
+		}
+		primary = append(primary, input.View())
+	}
+	tail := []string{t.panelSubtle.Render("actions: tab/down next | shift+tab/up prev | enter next/save | ctrl+s save | esc cancel")}
+	if strings.TrimSpace(m.errorText) != "" {
+		tail = append(tail, t.panelError.Render("error: "+m.errorText))
+	}
+	return renderWorkbenchRhythm(intro, primary, tail)
+}
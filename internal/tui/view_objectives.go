@@ -26,7 +26,7 @@ func (m model) renderObjectivesWorkbenchText(t theme, layout uiLayout) string {
 		"",
 		m.objectivesTable.View(),
 	}
-	tail := []string{t.panelSubtle.Render("actions: enter refresh | p pause/resume | x delete")}
+	tail := []string{t.panelSubtle.Render("actions: enter refresh | n new | e edit | p pause/resume | g run now | h runs | x trash")}
 	if strings.TrimSpace(m.errorText) != "" {
 		tail = append(tail, t.panelError.Render("error: "+m.errorText))
 	}
@@ -57,6 +57,9 @@ func (m model) renderObjectivesInspectorText() string {
 		"trigger    " + fallbackText(selected.TriggerType, "n/a"),
 		"timezone   " + fallbackText(selected.Timezone, "UTC"),
 		"state      " + map[bool]string{true: "active", false: "paused"}[selected.Active],
+		"reports    " + map[bool]string{true: "changes only", false: "every run"}[selected.ReportChangesOnly],
+		"digest     " + fallbackText(selected.DigestPeriod, "off"),
+		fmt.Sprintf("revision   %d", selected.Revision),
 		"",
 		fmt.Sprintf("runs       %d", selected.RunCount),
 		fmt.Sprintf("success    %d", selected.SuccessCount),
@@ -66,8 +69,30 @@ func (m model) renderObjectivesInspectorText() string {
 		"next run   " + formatUnixPtr(selected.NextRunUnix),
 		"last run   " + formatUnixPtr(selected.LastRunUnix),
 	}
+	if strings.TrimSpace(selected.AutoPausedReason) != "" {
+		lines = append(lines, "", "Paused", selected.AutoPausedReason)
+	}
 	if strings.TrimSpace(selected.LastError) != "" {
 		lines = append(lines, "", "Last Error", selected.LastError)
 	}
+	if m.objectiveRuns != nil && m.objectiveRuns.objectiveID == selected.ID {
+		lines = append(lines, "", "Recent Runs")
+		if len(m.objectiveRuns.items) == 0 {
+			lines = append(lines, "no runs yet")
+		}
+		for _, run := range m.objectiveRuns.items {
+			duration := "running"
+			if run.FinishedAtUnix != nil {
+				duration = humanDurationMs(run.DurationMs)
+			}
+			lines = append(lines, fmt.Sprintf("%s  %-9s %-8s %s", formatUnixPtr(&run.StartedAtUnix), run.Status, run.Trigger, duration))
+			if detail := fallbackText(run.Error, run.Summary); strings.TrimSpace(detail) != "" {
+				if len(detail) > 100 {
+					detail = detail[:97] + "..."
+				}
+				lines = append(lines, "  "+detail)
+			}
+		}
+	}
 	return strings.Join(lines, "\n")
 }
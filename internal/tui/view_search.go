@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (m model) renderSearchWorkbenchText(t theme, layout uiLayout) string {
+	width := layout.MainWidth - 6
+	if layout.Compact {
+		width = layout.Width - 6
+	}
+	intro := []string{
+		t.panelSubtle.Render("Find tasks, objectives, approvals and audit events"),
+		t.panelSubtle.Render("every word must match; words match as prefixes"),
+	}
+	primary := []string{
+		m.searchInput.View(),
+		"",
+		fillLine(
+			fmt.Sprintf("matches %d", len(m.searchResults)),
+			"best first",
+			width,
+		),
+		"",
+	}
+	if len(m.searchResults) == 0 {
+		hint := "type at least one word"
+		if strings.TrimSpace(m.searchInput.Value()) != "" {
+			hint = "no matches"
+		}
+		primary = append(primary, t.panelSubtle.Render(hint))
+	}
+	for index, result := range m.searchResults {
+		cursor := "  "
+		style := t.tableCell
+		if index == m.searchCursor {
+			cursor = "> "
+			style = t.tableSelected
+		}
+		label := fmt.Sprintf("%s%-9s %s", cursor, result.Kind, fallbackText(result.Title, result.ID))
+		status := result.Status
+		if result.Risk != "" {
+			status += " · " + result.Risk + " risk"
+		}
+		primary = append(primary, style.Render(trimToWidth(fillLine(label, status+"  "+result.WorkspaceID, width), width)))
+	}
+	tail := []string{t.panelSubtle.Render("actions: up/down select | enter open | esc close")}
+	if strings.TrimSpace(m.errorText) != "" {
+		tail = append(tail, t.panelError.Render("error: "+m.errorText))
+	}
+	return renderWorkbenchRhythm(intro, primary, tail)
+}
+
+func (m model) renderSearchInspectorText() string {
+	selected, ok := m.selectedSearchResult()
+	if !ok {
+		return strings.Join([]string{
+			"Search Result",
+			"",
+			"type a query and select a match",
+		}, "\n")
+	}
+	lines := []string{
+		"Search Result",
+		"",
+		"title      " + fallbackText(selected.Title, "untitled"),
+		"id         " + fallbackText(selected.ID, "n/a"),
+		"kind       " + fallbackText(selected.Kind, "n/a"),
+		"workspace  " + fallbackText(selected.WorkspaceID, "n/a"),
+		"status     " + fallbackText(selected.Status, "n/a"),
+	}
+	if selected.Risk != "" {
+		lines = append(lines, "risk       "+selected.Risk)
+	}
+	lines = append(lines, "updated    "+formatUnix(selected.UpdatedAtUnix))
+	if strings.TrimSpace(selected.Snippet) != "" {
+		lines = append(lines, "", "match      "+selected.Snippet)
+	}
+	switch selected.Kind {
+	case "task", "objective":
+		lines = append(lines, "", "enter opens it in the "+selected.Kind+"s view")
+	}
+	return strings.Join(lines, "\n")
+}
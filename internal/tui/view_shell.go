@@ -54,7 +54,7 @@ func (m model) renderHeader(t theme, layout uiLayout) string {
 
 	line1 := fillLine(t.brand.Render("Agent Runtime Control Plane"), statusChip, contentWidth)
 	line2 := fillLine(
-		t.headerSub.Render(trimToWidth("env: "+fallbackText(m.cfg.Environment, "unset")+" | focus: "+focusLabel(m.focus), maxInt(20, contentWidth/2))),
+		t.headerSub.Render(trimToWidth("env: "+fallbackText(m.cfg.Environment, "unset")+" | runtime: "+m.profileName()+" ("+m.liveLabel()+") | focus: "+focusLabel(m.focus), maxInt(20, contentWidth/2))),
 		t.headerSub.Render(trimToWidth("approver: "+fallbackText(m.cfg.TUIApproverUserID, "unset")+" | utc "+m.clock.UTC().Format("15:04:05"), maxInt(20, contentWidth/2))),
 		contentWidth,
 	)
@@ -113,16 +113,46 @@ func (m model) renderWorkbench(t theme, layout uiLayout) string {
 	case viewObjectives:
 		title = "Objectives"
 		content = m.renderObjectivesWorkbenchText(t, layout)
+		if m.objectiveForm.open {
+			title = "New Objective"
+			if m.objectiveForm.editing() {
+				title = "Edit Objective"
+			}
+			content = m.renderObjectiveFormText(t)
+		}
 	case viewTasks:
 		title = "Tasks"
 		content = m.renderTasksWorkbenchText(t, layout)
 	case viewActivity:
 		title = "Activity"
 		content = m.renderActivityWorkbenchText(t, layout)
+	case viewTrash:
+		title = "Trash"
+		content = m.renderTrashWorkbenchText(t, layout)
+	case viewCases:
+		title = "Cases"
+		content = m.renderCasesWorkbenchText(t, layout)
+	case viewApprovals:
+		title = "Approvals"
+		content = m.renderApprovalsWorkbenchText(t, layout)
+	case viewTrace:
+		title = "Trace"
+		content = m.renderTraceWorkbenchText(t, layout)
 	default:
 		title = "Overview"
 		content = m.renderOverviewWorkbenchText(t, layout)
 	}
+	subtitle := viewSubtitle(m.activeView)
+	if m.workspacePickerOpen {
+		title = "Workspaces"
+		content = m.renderWorkspacePickerText(t, layout)
+		subtitle = "current " + fallbackText(m.currentWorkspaceID(), "none")
+	}
+	if m.searchOpen {
+		title = "Search"
+		content = m.renderSearchWorkbenchText(t, layout)
+		subtitle = "all workspaces"
+	}
 
 	bodyWidth := layout.MainWidth
 	bodyHeight := layout.BodyHeight
@@ -136,7 +166,7 @@ func (m model) renderWorkbench(t theme, layout uiLayout) string {
 	if m.focus == focusWorkbench {
 		titleStyle = t.panelAccent.Copy().Bold(true)
 	}
-	header := fillLine(titleStyle.Render(paneLabel(title, m.focus == focusWorkbench)), t.panelSubtle.Render(viewSubtitle(m.activeView)), innerWidth(style, bodyWidth))
+	header := fillLine(titleStyle.Render(paneLabel(title, m.focus == focusWorkbench)), t.panelSubtle.Render(subtitle), innerWidth(style, bodyWidth))
 	return sizedStyle(style, bodyWidth, bodyHeight).Render(header + "\n" + content)
 }
 
@@ -234,6 +264,14 @@ func viewSubtitle(view viewID) string {
 		return "task operations"
 	case viewActivity:
 		return "session event feed"
+	case viewTrash:
+		return "deleted items"
+	case viewCases:
+		return "incidents and moderation"
+	case viewApprovals:
+		return "pending actions"
+	case viewTrace:
+		return "live chat and tool calls"
 	default:
 		return "runtime health"
 	}
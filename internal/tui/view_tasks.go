@@ -3,6 +3,9 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
 )
 
 func (m model) renderTasksWorkbenchText(t theme, layout uiLayout) string {
@@ -26,7 +29,7 @@ func (m model) renderTasksWorkbenchText(t theme, layout uiLayout) string {
 		"",
 		m.tasksTable.View(),
 	}
-	tail := []string{t.panelSubtle.Render("actions: enter refresh | [ ] filter | y retry failed")}
+	tail := []string{t.panelSubtle.Render("actions: enter refresh | [ ] filter | o result | y retry failed | c cancel | x trash")}
 	if strings.TrimSpace(m.errorText) != "" {
 		tail = append(tail, t.panelError.Render("error: "+m.errorText))
 	}
@@ -42,6 +45,9 @@ func (m model) renderTasksInspectorText() string {
 			"load a workspace and select a task",
 		}, "\n")
 	}
+	if m.taskResult != nil && m.taskResult.TaskID == selected.ID {
+		return m.renderTaskResultText()
+	}
 
 	lines := []string{
 		"Task Detail",
@@ -49,21 +55,51 @@ func (m model) renderTasksInspectorText() string {
 		"title      " + fallbackText(selected.Title, "untitled"),
 		"id         " + fallbackText(selected.ID, "n/a"),
 		"workspace  " + fallbackText(selected.WorkspaceID, "n/a"),
+		"context    " + fallbackText(selected.ContextID, "n/a"),
 		"kind       " + fallbackText(selected.Kind, "n/a"),
 		"status     " + fallbackText(selected.Status, "unknown"),
-		fmt.Sprintf("attempts   %d", selected.Attempts),
 		"created    " + formatUnix(selected.CreatedAtUnix),
 		"updated    " + formatUnix(selected.UpdatedAtUnix),
+		fmt.Sprintf("revision   %d", selected.Revision),
+		"",
+		"Routing",
+		"class      " + fallbackText(selected.RouteClass, "none"),
+		"priority   " + fallbackText(selected.Priority, "n/a"),
+		"lane       " + fallbackText(selected.AssignedLane, "n/a"),
+		"due        " + formatUnix(selected.DueAtUnix),
+		"runs at    " + formatUnix(selected.RunAtUnix),
+		"source     " + fallbackText(strings.Trim(selected.SourceConnector+"/"+selected.SourceExternalID, "/"), "n/a"),
+		"requester  " + fallbackText(selected.SourceUserID, "n/a"),
+		"",
+		"Attempts",
+		fmt.Sprintf("count      %d", selected.Attempts),
 	}
-	if strings.TrimSpace(selected.ResultPath) != "" {
-		lines = append(lines, "output     "+selected.ResultPath)
+	if selected.WorkerID > 0 {
+		lines = append(lines, fmt.Sprintf("worker     %d", selected.WorkerID))
 	}
-	if strings.TrimSpace(selected.ResultSummary) != "" {
-		lines = append(lines, "summary    "+selected.ResultSummary)
+	lines = append(lines,
+		"started    "+formatUnix(selected.StartedAtUnix),
+		"finished   "+formatUnix(selected.FinishedAtUnix),
+	)
+	if progress := taskProgressText(selected); progress != "" {
+		lines = append(lines, "progress   "+progress)
 	}
 	if strings.TrimSpace(selected.ErrorMessage) != "" {
 		lines = append(lines, "error      "+selected.ErrorMessage)
 	}
+	if len(selected.DependsOn) > 0 || len(selected.Blocks) > 0 {
+		lines = append(lines, "", "Dependencies")
+		lines = append(lines, m.renderTaskDependencies(selected)...)
+	}
+	lines = append(lines, "", "Result")
+	if summary := strings.TrimSpace(selected.ResultSummary); summary != "" {
+		lines = append(lines, summary)
+	} else {
+		lines = append(lines, "no result yet")
+	}
+	if resultPath := strings.TrimSpace(selected.ResultPath); resultPath != "" {
+		lines = append(lines, "", "Files", "- "+resultPath+"  (o to open)")
+	}
 	if m.taskRetryMsg != nil {
 		lines = append(lines,
 			"",
@@ -72,5 +108,106 @@ func (m model) renderTasksInspectorText() string {
 			"retry of   "+fallbackText(m.taskRetryMsg.RetryOfTask, "n/a"),
 		)
 	}
+	lines = append(lines, "", "Prompt", fallbackText(strings.TrimSpace(selected.Prompt), "n/a"))
+	if source := strings.TrimSpace(selected.SourceText); source != "" && source != strings.TrimSpace(selected.Prompt) {
+		lines = append(lines, "", "Source Message", source)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderTaskResultText shows the opened result markdown as written, so the
+// inspector viewport scrolls through the whole file.
+func (m model) renderTaskResultText() string {
+	lines := []string{
+		"Task Result",
+		"",
+		"file       " + m.taskResult.ResultPath,
+		"",
+		strings.TrimRight(m.taskResult.Content, "\n"),
+	}
+	if m.taskResult.Truncated {
+		lines = append(lines, "", "(truncated; open the file in the workspace for the rest)")
+	}
 	return strings.Join(lines, "\n")
 }
+
+// taskDependencyDepth bounds how far the inspector follows a dependency
+// chain in each direction.
+const taskDependencyDepth = 6
+
+// renderTaskDependencies draws the part of the task graph around task: the
+// tasks it waits on and, below them, the tasks waiting on it, each followed
+// transitively through the loaded tasks.
+func (m model) renderTaskDependencies(task adminclient.Task) []string {
+	byID := tasksByID(m.tasks)
+	lines := []string{}
+	if len(task.DependsOn) > 0 {
+		lines = append(lines, "waits on")
+		lines = appendTaskTree(lines, byID, task.DependsOn, func(item adminclient.Task) []string { return item.DependsOn }, 1, map[string]bool{task.ID: true})
+	}
+	if len(task.Blocks) > 0 {
+		lines = append(lines, "blocks")
+		lines = appendTaskTree(lines, byID, task.Blocks, func(item adminclient.Task) []string { return item.Blocks }, 1, map[string]bool{task.ID: true})
+	}
+	return lines
+}
+
+func appendTaskTree(lines []string, byID map[string]adminclient.Task, ids []string, next func(adminclient.Task) []string, depth int, seen map[string]bool) []string {
+	indent := strings.Repeat("  ", depth)
+	for _, id := range ids {
+		item, loaded := byID[id]
+		if !loaded {
+			lines = append(lines, indent+"- "+id+"  (not loaded)")
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s- %s  [%s]  %s", indent, fallbackText(item.Title, "untitled"), taskDisplayStatus(item, byID), id))
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if depth < taskDependencyDepth {
+			lines = appendTaskTree(lines, byID, next(item), next, depth+1, seen)
+		}
+	}
+	return lines
+}
+
+func tasksByID(tasks []adminclient.Task) map[string]adminclient.Task {
+	byID := make(map[string]adminclient.Task, len(tasks))
+	for _, item := range tasks {
+		byID[item.ID] = item
+	}
+	return byID
+}
+
+// taskProgressText is the last progress a task reported, e.g.
+// "step 3/5: fetch data (40%)", or empty when it reported none.
+func taskProgressText(task adminclient.Task) string {
+	if task.ProgressUpdatedAtUnix <= 0 {
+		return ""
+	}
+	text := strings.TrimSpace(task.ProgressStep)
+	if task.ProgressPercent >= 0 {
+		text = strings.TrimSpace(fmt.Sprintf("%s (%d%%)", text, task.ProgressPercent))
+	}
+	return text
+}
+
+// taskDisplayStatus shows a queued task as scheduled until its run time and
+// as blocked while one of its prerequisites has not succeeded, or is not
+// among the loaded tasks.
+func taskDisplayStatus(task adminclient.Task, byID map[string]adminclient.Task) string {
+	status := strings.ToLower(strings.TrimSpace(task.Status))
+	if status != "queued" {
+		return status
+	}
+	if task.RunAtUnix > time.Now().Unix() {
+		return "scheduled"
+	}
+	for _, id := range task.DependsOn {
+		if prerequisite, ok := byID[id]; !ok || !strings.EqualFold(prerequisite.Status, "succeeded") {
+			return "blocked"
+		}
+	}
+	return status
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dwizi/agent-runtime/internal/adminclient"
+)
+
+func (m model) renderTraceWorkbenchText(t theme, layout uiLayout) string {
+	width := layout.MainWidth - 6
+	if layout.Compact {
+		width = layout.Width - 6
+	}
+	toolCalls := 0
+	for _, entry := range m.traceEntries {
+		if entry.Direction == "tool" {
+			toolCalls++
+		}
+	}
+	intro := []string{
+		t.panelSubtle.Render("Follow a chat live: messages and the agent's tool calls as they are logged"),
+		t.panelSubtle.Render("workspace filter + chat table, newest activity first"),
+	}
+	primary := []string{
+		t.panelSubtle.Render("workspace"),
+		m.traceWorkspaceInput.View(),
+		"",
+		fillLine(
+			fmt.Sprintf("chats %d", len(m.chatLogs)),
+			fmt.Sprintf("entries %d  tool calls %d", len(m.traceEntries), toolCalls),
+			width,
+		),
+		"",
+		m.chatLogsTable.View(),
+	}
+	tail := []string{t.panelSubtle.Render("actions: enter refresh chats | j/k pick chat | inspector tails it every 2s")}
+	if strings.TrimSpace(m.errorText) != "" {
+		tail = append(tail, t.panelError.Render("error: "+m.errorText))
+	}
+	return renderWorkbenchRhythm(intro, primary, tail)
+}
+
+// renderTraceInspectorText is the tail of the selected chat, oldest entry
+// first so new ones appear at the bottom like a terminal tail.
+func (m model) renderTraceInspectorText(following bool) string {
+	selected, ok := m.selectedChatLog()
+	if !ok {
+		return strings.Join([]string{
+			"Chat Trace",
+			"",
+			"load a workspace and select a chat",
+		}, "\n")
+	}
+	state := "following new entries"
+	if !following {
+		state = "scrolled back, scroll to the end to follow"
+	}
+	lines := []string{
+		"Chat Trace",
+		"",
+		"chat       " + chatLogKey(selected),
+		"state      " + state,
+		"",
+	}
+	if m.traceChat != chatLogKey(selected) {
+		return strings.Join(append(lines, "loading..."), "\n")
+	}
+	if len(m.traceEntries) == 0 {
+		return strings.Join(append(lines, "no entries in the live log"), "\n")
+	}
+	for index, entry := range m.traceEntries {
+		if index > 0 {
+			lines = append(lines, "")
+		}
+		heading, body := traceEntryText(entry)
+		lines = append(lines, heading)
+		for _, line := range body {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// traceEntryText turns a chat log entry into a heading and indented body.
+// Tool call entries put the tool and its status in the heading and keep
+// their args, error and output lines as the body.
+func traceEntryText(entry adminclient.ChatLogEntry) (string, []string) {
+	at := "--:--:--"
+	if entry.TimestampUnix > 0 {
+		at = time.Unix(entry.TimestampUnix, 0).UTC().Format("15:04:05")
+	}
+	body := strings.Split(strings.TrimSpace(entry.Text), "\n")
+	switch entry.Direction {
+	case "inbound":
+		return at + "  user " + fallbackText(entry.Actor, "unknown"), body
+	case "outbound":
+		return at + "  agent", body
+	case "tool":
+		tool, status := "", ""
+		rest := make([]string, 0, len(body))
+		for _, line := range body {
+			trimmed := strings.TrimSpace(line)
+			if value, ok := strings.CutPrefix(trimmed, "- tool:"); ok {
+				tool = strings.Trim(strings.TrimSpace(value), "`")
+				continue
+			}
+			if value, ok := strings.CutPrefix(trimmed, "- status:"); ok {
+				status = strings.Trim(strings.TrimSpace(value), "`")
+				continue
+			}
+			if trimmed == "Tool call" {
+				continue
+			}
+			rest = append(rest, strings.TrimPrefix(trimmed, "- "))
+		}
+		return strings.TrimSpace(at + "  tool " + fallbackText(tool, "unknown") + " " + status), rest
+	default:
+		return at + "  " + fallbackText(entry.Direction, "entry"), body
+	}
+}
+
+func chatLogKey(chat adminclient.ChatLog) string {
+	return chat.Connector + "/" + chat.ExternalID
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (m model) renderTrashWorkbenchText(t theme, layout uiLayout) string {
+	width := layout.MainWidth - 6
+	if layout.Compact {
+		width = layout.Width - 6
+	}
+	intro := []string{
+		t.panelSubtle.Render("Deleted tasks and objectives, kept for 30 days"),
+		t.panelSubtle.Render("workspace filter + trash table"),
+	}
+	primary := []string{
+		t.panelSubtle.Render("workspace"),
+		m.trashWorkspaceInput.View(),
+		"",
+		fillLine(
+			fmt.Sprintf("items %d", len(m.trash)),
+			"restorable until purge",
+			width,
+		),
+		"",
+		m.trashTable.View(),
+	}
+	tail := []string{t.panelSubtle.Render("actions: enter refresh | u restore")}
+	if strings.TrimSpace(m.errorText) != "" {
+		tail = append(tail, t.panelError.Render("error: "+m.errorText))
+	}
+	return renderWorkbenchRhythm(intro, primary, tail)
+}
+
+func (m model) renderTrashInspectorText() string {
+	selected, ok := m.selectedTrashItem()
+	if !ok {
+		return strings.Join([]string{
+			"Trash Detail",
+			"",
+			"load a workspace and select a deleted item",
+		}, "\n")
+	}
+	return strings.Join([]string{
+		"Trash Detail",
+		"",
+		"title      " + fallbackText(selected.Title, "untitled"),
+		"id         " + fallbackText(selected.ID, "n/a"),
+		"kind       " + fallbackText(selected.Kind, "n/a"),
+		"workspace  " + fallbackText(selected.WorkspaceID, "n/a"),
+		"state      " + fallbackText(selected.Status, "n/a"),
+		"deleted    " + formatUnix(selected.DeletedAtUnix),
+		"purge      " + formatUnix(selected.PurgeAtUnix),
+	}, "\n")
+}
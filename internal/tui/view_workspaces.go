@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (m model) renderWorkspacePickerText(t theme, layout uiLayout) string {
+	width := layout.MainWidth - 6
+	if layout.Compact {
+		width = layout.Width - 6
+	}
+	intro := []string{
+		t.panelSubtle.Render("Pick the workspace every view filters on"),
+		t.panelSubtle.Render("workspaces with tasks or objectives, by name"),
+	}
+	primary := []string{
+		fillLine(
+			fmt.Sprintf("workspaces %d", len(m.workspaces)),
+			"open tasks · active objectives",
+			width,
+		),
+		"",
+	}
+	if len(m.workspaces) == 0 {
+		primary = append(primary, t.panelSubtle.Render("no workspaces yet"))
+	}
+	current := m.currentWorkspaceID()
+	for index, item := range m.workspaces {
+		cursor := "  "
+		style := t.tableCell
+		if index == m.workspaceCursor {
+			cursor = "> "
+			style = t.tableSelected
+		}
+		marker := " "
+		if item.ID == current {
+			marker = "*"
+		}
+		label := cursor + marker + " " + workspaceLabel(item.Name, item.ID)
+		counts := fmt.Sprintf("%d tasks · %d objectives", item.OpenTaskCount, item.ActiveObjectiveCount)
+		primary = append(primary, style.Render(trimToWidth(fillLine(label, counts, width), width)))
+	}
+	tail := []string{t.panelSubtle.Render("actions: up/down select | enter switch | esc close")}
+	if strings.TrimSpace(m.errorText) != "" {
+		tail = append(tail, t.panelError.Render("error: "+m.errorText))
+	}
+	return renderWorkbenchRhythm(intro, primary, tail)
+}
+
+func (m model) renderWorkspaceInspectorText() string {
+	selected, ok := m.selectedWorkspace()
+	if !ok {
+		return strings.Join([]string{
+			"Workspace",
+			"",
+			"no workspace selected",
+		}, "\n")
+	}
+	return strings.Join([]string{
+		"Workspace",
+		"",
+		"name       " + fallbackText(selected.Name, "unnamed"),
+		"id         " + selected.ID,
+		"slug       " + fallbackText(selected.Slug, "n/a"),
+		"kind       " + fallbackText(selected.Kind, "n/a"),
+		"",
+		fmt.Sprintf("tasks      %d", selected.TaskCount),
+		fmt.Sprintf("open       %d", selected.OpenTaskCount),
+		fmt.Sprintf("failed     %d", selected.FailedTaskCount),
+		"",
+		fmt.Sprintf("objectives %d", selected.ObjectiveCount),
+		fmt.Sprintf("active     %d", selected.ActiveObjectiveCount),
+	}, "\n")
+}
+
+// workspaceLabel names a workspace by its name and id, or just the id for
+// workspaces only known from their records.
+func workspaceLabel(name, id string) string {
+	if strings.TrimSpace(name) == "" {
+		return id
+	}
+	return name + " (" + id + ")"
+}
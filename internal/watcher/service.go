@@ -19,6 +19,9 @@ type Service struct {
 	onChange func(context.Context, string)
 	watcher  *fsnotify.Watcher
 	reporter heartbeat.Reporter
+	// fileNames are non-markdown files that are reported too, including
+	// their removal.
+	fileNames map[string]bool
 }
 
 func New(roots []string, logger *slog.Logger, onChange func(context.Context, string)) (*Service, error) {
@@ -38,6 +41,17 @@ func (s *Service) SetHeartbeatReporter(reporter heartbeat.Reporter) {
 	s.reporter = reporter
 }
 
+// WatchFileNames reports changes to files with these base names in addition
+// to markdown files.
+func (s *Service) WatchFileNames(names ...string) {
+	if s.fileNames == nil {
+		s.fileNames = map[string]bool{}
+	}
+	for _, name := range names {
+		s.fileNames[name] = true
+	}
+}
+
 func (s *Service) Start(ctx context.Context) error {
 	defer s.watcher.Close()
 
@@ -103,6 +117,14 @@ func (s *Service) handleEvent(ctx context.Context, event fsnotify.Event) {
 			return
 		}
 	}
+	if s.fileNames[filepath.Base(event.Name)] {
+		if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+			return
+		}
+		s.logger.Info("watched file changed", "path", event.Name, "op", event.Op.String())
+		s.onChange(ctx, event.Name)
+		return
+	}
 	if filepath.Ext(event.Name) != ".md" {
 		return
 	}